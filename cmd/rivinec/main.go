@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/modules"
@@ -22,8 +21,17 @@ func main() {
 	// define preRunE, as to ensure we go to a default config should it be required
 	cliClient.PreRunE = func(cfg *client.Config) (*client.Config, error) {
 		if cfg == nil {
-			chainConstants := types.StandardnetChainConstants()
-			daemonConstants := modules.NewDaemonConstants(bchainInfo, chainConstants)
+			chainInfo, chainConstants := bchainInfo, types.StandardnetChainConstants()
+			if cliClient.Profile != nil {
+				// default to the network of the profile in use
+				switch cliClient.Profile.Network {
+				case "testnet":
+					chainInfo.NetworkName, chainConstants = "testnet", types.TestnetChainConstants()
+				case "devnet":
+					chainInfo.NetworkName, chainConstants = "devnet", types.DevnetChainConstants()
+				}
+			}
+			daemonConstants := modules.NewDaemonConstants(chainInfo, chainConstants)
 			newCfg := client.ConfigFromDaemonConstants(daemonConstants)
 			cfg = &newCfg
 		}
@@ -36,11 +44,10 @@ func main() {
 	}
 	// start cli
 	if err := cliClient.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, "client exited with an error: ", err)
 		// Since no commands return errors (all commands set Command.Run instead of
 		// Command.RunE), Command.Execute() should only return an error on an
 		// invalid command or flag. Therefore Command.Usage() was called (assuming
 		// Command.SilenceUsage is false) and we should exit with exitCodeUsage.
-		os.Exit(cli.ExitCodeUsage)
+		cli.DieWithExitCode(cli.ExitCodeUsage, "client exited with an error:", err)
 	}
 }
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/cmd/rivinecg/pkg/config"
+)
+
+// root convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert content (e.g. a config) from one format to another",
+}
+
+var convertConfigCmd = &cobra.Command{
+	Use:   "config <source> <destination>",
+	Short: "Convert a blockchain config file to another format",
+	Long: `Convert a blockchain config file to another format, the formats are based on the file extensions,
+and can be yaml, toml or json. Both files can be the same, in which case the config file is reformatted.
+
+Comment lines are preserved when converting between yaml and toml, comments preceding a property
+remain with that property. Comments at the end of a line are not preserved, and json files have no comments.`,
+	Args: cobra.ExactArgs(2),
+	RunE: convertConfig,
+}
+
+func convertConfig(cmd *cobra.Command, args []string) error {
+	result, err := config.ConvertConfigFile(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Converted config file %s to %s\n", args[0], args[1])
+	if result.DroppedComments > 0 {
+		fmt.Printf("%d of %d comment line(s) couldn't be preserved\n", result.DroppedComments, result.Comments)
+	}
+	return nil
+}
+
+func init() {
+	convertCmd.AddCommand(
+		convertConfigCmd,
+	)
+}
@@ -8,4 +8,9 @@ var (
 	pluginAuthcoinEnabled bool
 	frontendExplorerType  string
 	frontendFaucet        bool
+	generateDocker        bool
+	templateCacheDir      string
+	refreshTemplateCache  bool
+	diffBlockchain        bool
+	interactiveConfig     bool
 )
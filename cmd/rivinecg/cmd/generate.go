@@ -3,6 +3,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/threefoldtech/rivine/modules"
@@ -31,8 +33,12 @@ var generateSeedCmd = &cobra.Command{
 var generateConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Generate blockchain config file",
-	Args:  cobra.ExactArgs(0),
-	RunE:  generateConfigFile,
+	Long: `Generate a blockchain config file, filled with default values.
+
+Using --interactive you are walked through the network parameters, genesis allocations,
+binary names and template selection instead, with each answer validated before moving on.`,
+	Args: cobra.ExactArgs(0),
+	RunE: generateConfigFile,
 }
 
 var generateBlockchainCmd = &cobra.Command{
@@ -65,12 +71,13 @@ func init() {
 	generateSeedCmd.Flags().Uint64VarP(&numberOfAddresses, "address-amount", "n", 1, "amount of generated addresses")
 
 	generateConfigCmd.Flags().BoolVar(&pluginMintingEnabled, "minting", true, "enable minting plugin")
-	generateConfigCmd.Flags().BoolVar(&pluginAuthcoinEnabled, "authcoin", false, "enable minting plugin")
+	generateConfigCmd.Flags().BoolVar(&pluginAuthcoinEnabled, "authcoin", false, "enable authcoin plugin")
+	generateConfigCmd.Flags().BoolVarP(&interactiveConfig, "interactive", "i", false, "configure the blockchain step by step, instead of using default values")
 
 	for _, cmd := range []*cobra.Command{generateConfigCmd, generateBlockchainCmd} {
 		cmd.Flags().StringVarP(
 			&filePath, "config", "c", "blockchaincfg.yaml",
-			"file path of the config, ecoding is based on the file extension, can be yaml or json")
+			"file path of the config, ecoding is based on the file extension, can be yaml, toml or json")
 	}
 	generateBlockchainCmd.Flags().StringVarP(
 		&outputDir, "output", "o", "",
@@ -81,6 +88,18 @@ func init() {
 	generateBlockchainCmd.Flags().BoolVar(
 		&frontendFaucet, "faucet", true,
 		"Generate the frontend faucet, opt-out.")
+	generateBlockchainCmd.Flags().BoolVar(
+		&generateDocker, "docker", false,
+		"Generate a Dockerfile, entrypoint script and docker-compose devnet, opt-in.")
+	generateBlockchainCmd.Flags().StringVar(
+		&templateCacheDir, "cache-dir", "",
+		"directory Github template tarballs are cached in, defaults to rivinecg/templates within the user cache directory")
+	generateBlockchainCmd.Flags().BoolVar(
+		&refreshTemplateCache, "refresh", false,
+		"download Github template tarballs, even if they are cached already")
+	generateBlockchainCmd.Flags().BoolVar(
+		&diffBlockchain, "diff", false,
+		"print the difference between what would be generated and the output directory, without writing anything")
 
 	// adds generateSeedCmd to rootCmd
 	generateCmd.AddCommand(
@@ -134,10 +153,26 @@ func generateAddressesFromMnemonic(mnemonic string, n uint64) ([]types.UnlockHas
 }
 
 func generateConfigFile(cmd *cobra.Command, args []string) error {
-	err := config.GenerateConfigFile(filePath, &config.ConfigGenerationOpts{
+	opts := &config.ConfigGenerationOpts{
 		PluginMintingEnabled:  pluginMintingEnabled,
 		PluginAuthcoinEnabled: pluginAuthcoinEnabled,
-	})
+	}
+	if interactiveConfig {
+		// fail early, prior to asking all questions
+		if !config.IsSupportedConfigFileType(path.Ext(filePath)) {
+			return config.ErrUnsupportedFileType
+		}
+		cfg, err := config.RunConfigWizard(os.Stdin, os.Stdout, filePath, opts)
+		if err != nil {
+			return err
+		}
+		if err = config.WriteConfigFile(filePath, cfg); err != nil {
+			return err
+		}
+		fmt.Printf("\nConfig written in: %s\n", filePath)
+		return nil
+	}
+	err := config.GenerateConfigFile(filePath, opts)
 	if err != nil {
 		return err
 	}
@@ -155,12 +190,54 @@ func generateBlockchain(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid explorer flag: %v", err)
 	}
-	err = config.GenerateBlockchain(filePath, dir, &config.BlockchainGenerationOpts{
+	opts := &config.BlockchainGenerationOpts{
 		FrontendExplorerType: fExplorerType,
 		FrontendFaucet:       frontendFaucet,
-	})
+		Docker:               generateDocker,
+		TemplateCacheDir:     templateCacheDir,
+		RefreshTemplateCache: refreshTemplateCache,
+	}
+	if diffBlockchain {
+		diff, err := config.DiffBlockchain(filePath, dir, opts)
+		if err != nil {
+			return err
+		}
+		printBlockchainDiff(dir, diff)
+		return nil
+	}
+	err = config.GenerateBlockchain(filePath, dir, opts)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// printBlockchainDiff prints the files that would be added or modified,
+// followed by the content diff of all modified files
+func printBlockchainDiff(dir string, diff *config.BlockchainDiff) {
+	fmt.Printf("\nComparing the blockchain that would be generated against %s:\n\n", dir)
+	var added, modified int
+	for _, file := range diff.Files {
+		if file.Status == config.FileDiffStatusAdded {
+			added++
+		} else {
+			modified++
+		}
+		line := fmt.Sprintf("%-9s %s", file.Status.String()+":", file.Path)
+		if file.Status == config.FileDiffStatusModified && file.OldMode != file.NewMode {
+			line += fmt.Sprintf(" (mode %04o -> %04o)", file.OldMode, file.NewMode)
+		}
+		fmt.Println(line)
+	}
+	for _, file := range diff.Files {
+		if file.Status != config.FileDiffStatusModified {
+			continue
+		}
+		if file.Binary {
+			fmt.Printf("\nBinary files a/%s and b/%s differ\n", file.Path, file.Path)
+		} else if file.Diff != "" {
+			fmt.Printf("\n%s", file.Diff)
+		}
+	}
+	fmt.Printf("\n%d file(s) would be added, %d modified and %d are unchanged\n", added, modified, diff.Unchanged)
+}
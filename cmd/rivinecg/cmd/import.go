@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/cmd/rivinecg/pkg/config"
+)
+
+// root import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import content (e.g. genesis allocations) into a config",
+}
+
+var importAllocationsCmd = &cobra.Command{
+	Use:   "allocations <file.csv|file.json>",
+	Short: "Import genesis allocations into a blockchain config file",
+	Long: `Import the genesis allocations of a CSV or JSON file into the genesis of a network of a blockchain config file.
+
+A CSV file contains address,value rows, optionally preceded by an address,value header row,
+and can contain # comments. A JSON file contains a list of {"address": ..., "value": ...} objects.
+Coin values are expressed in the coin unit (e.g. 1.5) and can be suffixed with the (SI-prefixed) coin unit,
+block stake values are natural numbers.
+
+All addresses and values are validated, and all invalid rows are reported, prior to updating the config.
+By default the existing allocations of the network are replaced.`,
+	Args: cobra.ExactArgs(1),
+	RunE: importAllocations,
+}
+
+var (
+	importAllocationsOpts config.AllocationImportOpts
+	importAllocationsType string
+)
+
+func importAllocations(cmd *cobra.Command, args []string) error {
+	err := importAllocationsOpts.Type.FromString(importAllocationsType)
+	if err != nil {
+		return fmt.Errorf("invalid type flag: %v", err)
+	}
+	result, err := config.ImportAllocations(filePath, args[0], &importAllocationsOpts)
+	if err != nil {
+		return err
+	}
+	kind := "coin"
+	if importAllocationsOpts.Type == config.AllocationTypeBlockStakes {
+		kind = "block stake"
+	}
+	fmt.Printf("Imported %d %s allocation(s) with a total value of %s into the genesis of network %s of %s\n",
+		result.Count, kind, result.Total, importAllocationsOpts.Network, filePath)
+	return nil
+}
+
+func init() {
+	importAllocationsCmd.Flags().StringVarP(
+		&filePath, "config", "c", "blockchaincfg.yaml",
+		"file path of the config, ecoding is based on the file extension, can be yaml, toml or json")
+	importAllocationsCmd.Flags().StringVarP(
+		&importAllocationsOpts.Network, "network", "n", "",
+		"name of the network whose genesis the allocations are imported into")
+	importAllocationsCmd.MarkFlagRequired("network")
+	importAllocationsCmd.Flags().StringVarP(
+		&importAllocationsType, "type", "t", config.AllocationTypeCoins.String(),
+		"type of the allocations, options: coins,blockstakes")
+	importAllocationsCmd.Flags().BoolVar(
+		&importAllocationsOpts.Append, "append", false,
+		"append the allocations to the existing genesis allocations, rather than replacing them")
+	importAllocationsCmd.Flags().StringVar(
+		&importAllocationsOpts.ExpectedTotal, "total", "",
+		"total value the allocations are expected to have, the import fails if it differs")
+	importCmd.AddCommand(
+		importAllocationsCmd,
+	)
+}
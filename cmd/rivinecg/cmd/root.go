@@ -28,5 +28,7 @@ func init() {
 		versionCmd,
 		generateCmd,
 		validateCmd,
+		importCmd,
+		convertCmd,
 	)
 }
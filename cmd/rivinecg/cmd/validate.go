@@ -18,24 +18,48 @@ var validateCmd = &cobra.Command{
 var validateConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "validate blockchain config file",
-	Args:  cobra.ExactArgs(0),
-	Run:   validateConfigFile,
+	Long: `Validate a blockchain config file against all rules used by the generator,
+reporting every problem found together with the line on which it occurs.
+
+Unless the --offline flag is given, the template repository (version)
+is checked to be reachable as well.`,
+	Args: cobra.ExactArgs(0),
+	Run:  validateConfigFile,
 }
 
+var validateConfigOffline bool
+
 func validateConfigFile(cmd *cobra.Command, args []string) {
-	_, err := config.ImportAndValidateConfig(filePath)
+	problems, err := config.ValidateConfigFile(filePath, &config.ValidationOpts{
+		Offline: validateConfigOffline,
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid config file: %v", err)
+		fmt.Fprintf(os.Stderr, "invalid config file: %v\n", err)
 		os.Exit(1)
 		return
 	}
-	fmt.Println("Ok")
+	if len(problems) == 0 {
+		fmt.Println("Ok")
+		return
+	}
+	for _, problem := range problems {
+		if problem.Line > 0 {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", filePath, problem.Line, problem)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filePath, problem)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "invalid config file: %d problem(s) found\n", len(problems))
+	os.Exit(1)
 }
 
 func init() {
 	validateConfigCmd.Flags().StringVarP(
 		&filePath, "config", "c", "blockchaincfg.yaml",
-		"file path of the config, ecoding is based on the file extension, can be yaml or json")
+		"file path of the config, ecoding is based on the file extension, can be yaml, toml or json")
+	validateConfigCmd.Flags().BoolVar(
+		&validateConfigOffline, "offline", false,
+		"skip the validation which requires network access, such as the template repository reachability")
 	validateCmd.AddCommand(
 		validateConfigCmd,
 	)
@@ -0,0 +1,350 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// AllocationType is the type of the genesis allocations to import
+type AllocationType uint8
+
+const (
+	// AllocationTypeCoins defines genesis coin outputs
+	AllocationTypeCoins AllocationType = iota
+	// AllocationTypeBlockStakes defines genesis block stake outputs
+	AllocationTypeBlockStakes
+)
+
+func (at AllocationType) String() string {
+	switch at {
+	case AllocationTypeBlockStakes:
+		return "blockstakes"
+	default:
+		return "coins"
+	}
+}
+
+func (at *AllocationType) FromString(str string) error {
+	str = strings.ToLower(str)
+	switch str {
+	case "coins", "coin":
+		*at = AllocationTypeCoins
+		return nil
+	case "blockstakes", "blockstake", "bs":
+		*at = AllocationTypeBlockStakes
+		return nil
+	default:
+		return fmt.Errorf("%s is an invalid AllocationType in string format", str)
+	}
+}
+
+// Allocation is a single genesis allocation,
+// the value of coin allocations is expressed in the coin unit (e.g. "1.5").
+type Allocation struct {
+	Address types.UnlockHash
+	Value   string
+}
+
+// AllocationImportOpts are the options used to import genesis allocations.
+type AllocationImportOpts struct {
+	// Network whose genesis the allocations are imported into
+	Network string
+	Type    AllocationType
+	// Append the allocations to the existing genesis allocations,
+	// rather than replacing them
+	Append bool
+	// ExpectedTotal, if defined, is the total value the imported allocations are expected to have,
+	// expressed in the coin unit for coin allocations
+	ExpectedTotal string
+}
+
+// AllocationImportResult summarizes the imported genesis allocations.
+type AllocationImportResult struct {
+	// Count is the amount of imported allocations
+	Count int
+	// Total value of the imported allocations,
+	// formatted using the coin unit for coin allocations
+	Total string
+}
+
+// allocationRowError is the error of a single row of an allocations file
+type allocationRowError struct {
+	row int
+	err error
+}
+
+// AllocationsError is returned in case one or multiple allocations of a file are invalid.
+type AllocationsError struct {
+	rows []allocationRowError
+}
+
+// Error implements error.Error
+func (err *AllocationsError) Error() string {
+	lines := make([]string, 0, len(err.rows)+1)
+	lines = append(lines, fmt.Sprintf("%d invalid allocation(s):", len(err.rows)))
+	for _, row := range err.rows {
+		lines = append(lines, fmt.Sprintf("  row %d: %v", row.row, row.err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rawAllocation is an allocation as read from an allocations file, prior to being validated
+type rawAllocation struct {
+	row            int
+	address, value string
+}
+
+// allocationJSONValue is the value of a JSON allocation, which can be a string or number
+type allocationJSONValue string
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (v *allocationJSONValue) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*v = allocationJSONValue(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return fmt.Errorf("invalid value %s: has to be a string or number", b)
+	}
+	*v = allocationJSONValue(str)
+	return nil
+}
+
+// readRawAllocations reads the allocations of a CSV or JSON file (based on the given file extension).
+// CSV files contain address,value rows, optionally preceded by a header row, and can contain # comments.
+// JSON files contain a list of objects with an address and value property.
+func readRawAllocations(typ string, r io.Reader) ([]rawAllocation, error) {
+	switch typ {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.Comment = '#'
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = 2
+		var allocations []rawAllocation
+		for row := 1; ; row++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return allocations, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+				continue // header
+			}
+			allocations = append(allocations, rawAllocation{
+				row:     row,
+				address: strings.TrimSpace(record[0]),
+				value:   strings.TrimSpace(record[1]),
+			})
+		}
+	case ".json":
+		var entries []struct {
+			Address string              `json:"address"`
+			Value   allocationJSONValue `json:"value"`
+		}
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&entries); err != nil {
+			return nil, err
+		}
+		allocations := make([]rawAllocation, 0, len(entries))
+		for idx, entry := range entries {
+			allocations = append(allocations, rawAllocation{
+				row:     idx + 1,
+				address: strings.TrimSpace(entry.Address),
+				value:   strings.TrimSpace(string(entry.Value)),
+			})
+		}
+		return allocations, nil
+	default:
+		return nil, fmt.Errorf("unsupported allocations file type %q, has to be .csv or .json", typ)
+	}
+}
+
+// ReadAllocations reads and validates the genesis allocations of a CSV or JSON file.
+// All addresses have to be valid wallet addresses, defined only once,
+// and all values have to be greater than zero. Coin values are parsed using the given currency format,
+// and are normalized to be expressed in the coin unit. Block stake values have to be natural numbers.
+// The total value of all allocations is returned as well.
+func ReadAllocations(filePath string, typ AllocationType, format types.CurrencyFormat) ([]Allocation, types.Currency, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, types.Currency{}, err
+	}
+	defer file.Close()
+	rawAllocations, err := readRawAllocations(path.Ext(filePath), file)
+	if err != nil {
+		return nil, types.Currency{}, fmt.Errorf("failed to read allocations file %s: %v", filePath, err)
+	}
+	if len(rawAllocations) == 0 {
+		return nil, types.Currency{}, fmt.Errorf("allocations file %s doesn't define any allocations", filePath)
+	}
+
+	var (
+		total       types.Currency
+		allocations = make([]Allocation, 0, len(rawAllocations))
+		rowErrors   []allocationRowError
+		rows        = make(map[types.UnlockHash]int, len(rawAllocations))
+	)
+	for _, raw := range rawAllocations {
+		var uh types.UnlockHash
+		if err := uh.LoadString(raw.address); err != nil {
+			rowErrors = append(rowErrors, allocationRowError{raw.row, fmt.Errorf("invalid address %q: %v", raw.address, err)})
+			continue
+		}
+		if uh.Type != types.UnlockTypePubKey {
+			// the condition of other addresses (e.g. multisig addresses) cannot be derived from their address
+			rowErrors = append(rowErrors, allocationRowError{raw.row, fmt.Errorf("address %s is not a wallet address", uh)})
+			continue
+		}
+		if row, ok := rows[uh]; ok {
+			rowErrors = append(rowErrors, allocationRowError{raw.row, fmt.Errorf("address %s is already allocated on row %d", uh, row)})
+			continue
+		}
+		rows[uh] = raw.row
+
+		var value types.Currency
+		if typ == AllocationTypeBlockStakes {
+			x, err := strconv.ParseUint(raw.value, 10, 64)
+			if err != nil {
+				rowErrors = append(rowErrors, allocationRowError{raw.row, fmt.Errorf("invalid block stake value %q: has to be a natural number", raw.value)})
+				continue
+			}
+			value = types.NewCurrency64(x)
+		} else {
+			value, err = format.ParseCoins(raw.value)
+			if err != nil {
+				rowErrors = append(rowErrors, allocationRowError{raw.row, fmt.Errorf("invalid coin value %q: %v", raw.value, err)})
+				continue
+			}
+		}
+		if value.IsZero() {
+			rowErrors = append(rowErrors, allocationRowError{raw.row, errors.New("value has to be greater than zero")})
+			continue
+		}
+		total = total.Add(value)
+
+		allocation := Allocation{Address: uh, Value: value.String()}
+		if typ == AllocationTypeCoins {
+			allocation.Value = format.FormatCoins(value)
+		}
+		allocations = append(allocations, allocation)
+	}
+	if len(rowErrors) > 0 {
+		return nil, types.Currency{}, &AllocationsError{rows: rowErrors}
+	}
+	return allocations, total, nil
+}
+
+// ImportAllocations imports the genesis allocations of a CSV or JSON file
+// into the genesis of a network of a config file, see ReadAllocations for the supported formats.
+// The config file is only updated if the result is a valid config.
+func ImportAllocations(configFilePath, allocationsFilePath string, opts *AllocationImportOpts) (*AllocationImportResult, error) {
+	if opts == nil || opts.Network == "" {
+		return nil, errors.New("the network to import the allocations into has to be defined")
+	}
+	typ := path.Ext(configFilePath)
+	b, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	config, err := decodeConfig(typ, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if config.Blockchain == nil || config.Blockchain.Currency == nil {
+		return nil, errors.New("config file doesn't define a blockchain currency")
+	}
+	network, ok := config.Blockchain.Networks[opts.Network]
+	if !ok || network == nil {
+		return nil, fmt.Errorf("config file doesn't define a network %s", opts.Network)
+	}
+	if config.Blockchain.Currency.Precision > maxCurrencyPrecision {
+		return nil, fmt.Errorf("currency precision %d is too large, the maximum supported precision is %d", config.Blockchain.Currency.Precision, maxCurrencyPrecision)
+	}
+	format := types.NewCurrencyFormat(config.Blockchain.Currency.Units(), config.Blockchain.Currency.Unit)
+
+	allocations, total, err := ReadAllocations(allocationsFilePath, opts.Type, format)
+	if err != nil {
+		return nil, err
+	}
+	result := &AllocationImportResult{Count: len(allocations)}
+	if opts.Type == AllocationTypeBlockStakes {
+		result.Total = total.String()
+	} else {
+		result.Total = format.Format(total)
+	}
+	if opts.ExpectedTotal != "" {
+		var expected types.Currency
+		if opts.Type == AllocationTypeBlockStakes {
+			x, err := strconv.ParseUint(opts.ExpectedTotal, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expected block stake total %q: has to be a natural number", opts.ExpectedTotal)
+			}
+			expected = types.NewCurrency64(x)
+		} else if expected, err = format.ParseCoins(opts.ExpectedTotal); err != nil {
+			return nil, fmt.Errorf("invalid expected coin total %q: %v", opts.ExpectedTotal, err)
+		}
+		if !total.Equals(expected) {
+			return nil, fmt.Errorf("the total value of the allocations is %s, while %s is expected", result.Total, opts.ExpectedTotal)
+		}
+	}
+
+	outputs := make([]Output, 0, len(allocations))
+	for _, allocation := range allocations {
+		outputs = append(outputs, Output{
+			Value:     allocation.Value,
+			Condition: Condition{types.NewCondition(types.NewUnlockHashCondition(allocation.Address))},
+		})
+	}
+	if network.Genesis == nil {
+		network.Genesis = new(Genesis)
+	}
+	if opts.Type == AllocationTypeBlockStakes {
+		if opts.Append {
+			outputs = append(network.Genesis.BlockStakeOutputs, outputs...)
+		}
+		network.Genesis.BlockStakeOutputs = outputs
+	} else {
+		if opts.Append {
+			outputs = append(network.Genesis.CoinOutputs, outputs...)
+		}
+		network.Genesis.CoinOutputs = outputs
+	}
+
+	// encode the updated config, and ensure it is still valid prior to writing it
+	var buf bytes.Buffer
+	err = encodeConfigStruct(typ, &buf, config)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := decodeConfig(typ, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	if errs := resolveNetworkExtensions(updated.Blockchain.Networks); len(errs) > 0 {
+		return nil, fmt.Errorf("updated config is invalid: %v", errs[0])
+	}
+	if err = validateConfig(updated); err != nil {
+		return nil, fmt.Errorf("updated config is invalid: %v", err)
+	}
+	err = ioutil.WriteFile(configFilePath, buf.Bytes(), 0644)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	testAllocationAddressA = "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+	testAllocationAddressB = "01434535fd01243c02c277cd58d71423163767a575a8ae44e15807bf545e4a8456a5c4afabad51"
+	testAllocationAddressC = "01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e"
+)
+
+func TestImportAllocations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-allocations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, content string) string {
+		fPath := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(fPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return fPath
+	}
+	coinsPath := writeFile("coins.csv", `address,value
+# founders
+`+testAllocationAddressA+`,1000.5
+`+testAllocationAddressB+`, 250 ROC
+`+testAllocationAddressC+`,500m
+`)
+	blockStakesPath := writeFile("blockstakes.json", `[
+  {"address": "`+testAllocationAddressA+`", "value": 100},
+  {"address": "`+testAllocationAddressB+`", "value": "20"}
+]`)
+
+	for _, typ := range []string{".yaml", ".json"} {
+		configPath := filepath.Join(dir, "blockchaincfg"+typ)
+		err = GenerateConfigFile(configPath, &ConfigGenerationOpts{PluginMintingEnabled: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := ImportAllocations(configPath, coinsPath, &AllocationImportOpts{
+			Network:       "devnet",
+			ExpectedTotal: "1251",
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to import coin allocations: %v", typ, err)
+		}
+		if result.Count != 3 || result.Total != "1251 ROC" {
+			t.Errorf("%s: unexpected result: %v", typ, result)
+		}
+		result, err = ImportAllocations(configPath, blockStakesPath, &AllocationImportOpts{
+			Network: "devnet",
+			Type:    AllocationTypeBlockStakes,
+			Append:  true,
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to import block stake allocations: %v", typ, err)
+		}
+		if result.Count != 2 || result.Total != "120" {
+			t.Errorf("%s: unexpected result: %v", typ, result)
+		}
+
+		config, err := ImportAndValidateConfig(configPath)
+		if err != nil {
+			t.Fatalf("%s: imported config is invalid: %v", typ, err)
+		}
+		genesis := config.Blockchain.Networks["devnet"].Genesis
+		var values []string
+		for _, output := range genesis.CoinOutputs {
+			values = append(values, output.Value+"@"+output.Condition.UnlockHash().String()[:4])
+		}
+		if str := strings.Join(values, ","); str != "1000.5@015a,250@0143,0.5@01b5" {
+			t.Errorf("%s: unexpected genesis coin outputs: %s", typ, str)
+		}
+		values = values[:0]
+		for _, output := range genesis.BlockStakeOutputs {
+			values = append(values, output.Value)
+		}
+		// appended to the default block stake output
+		if str := strings.Join(values, ","); str != "3000,100,20" {
+			t.Errorf("%s: unexpected genesis block stake outputs: %s", typ, str)
+		}
+		// other networks are untouched
+		if outputs := config.Blockchain.Networks["standard"].Genesis.CoinOutputs; len(outputs) != 1 || outputs[0].Value != "500000" {
+			t.Errorf("%s: genesis of the standard network is updated: %v", typ, outputs)
+		}
+
+		// a config isn't updated in case of an unexpected total
+		b, err := ioutil.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ImportAllocations(configPath, coinsPath, &AllocationImportOpts{
+			Network:       "devnet",
+			ExpectedTotal: "1251.5 ROC",
+		})
+		if err == nil {
+			t.Errorf("%s: expected an import with an unexpected total to fail", typ)
+		}
+		if _, err = ImportAllocations(configPath, coinsPath, &AllocationImportOpts{Network: "unknown"}); err == nil {
+			t.Errorf("%s: expected an import into an unknown network to fail", typ)
+		}
+		if after, err := ioutil.ReadFile(configPath); err != nil || string(after) != string(b) {
+			t.Errorf("%s: config is updated by a failed import: %v", typ, err)
+		}
+	}
+}
+
+func TestReadInvalidAllocations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-allocations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fPath := filepath.Join(dir, "allocations.csv")
+	err = ioutil.WriteFile(fPath, []byte(testAllocationAddressA+`,1
+`+testAllocationAddressA+`,2
+abc,3
+`+testAllocationAddressB+`,0
+`+testAllocationAddressC+`,1.5
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := BuildConfigStruct("", nil)
+	_, _, err = ReadAllocations(fPath, AllocationTypeBlockStakes, types.NewCurrencyFormat(config.Blockchain.Currency.Units(), ""))
+	allocationsErr, ok := err.(*AllocationsError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rows []int
+	for _, row := range allocationsErr.rows {
+		rows = append(rows, row.row)
+	}
+	if len(rows) != 4 || rows[0] != 2 || rows[1] != 3 || rows[2] != 4 || rows[3] != 5 {
+		t.Errorf("unexpected invalid rows %v: %v", rows, err)
+	}
+}
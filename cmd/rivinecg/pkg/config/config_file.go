@@ -148,6 +148,7 @@ type (
 	}
 
 	Network struct {
+		Extends                string           `json:"extends,omitempty" yaml:"extends,omitempty"`
 		NetworkType            NetworkType      `json:"networkType" yaml:"networkType" validate:"required"`
 		Genesis                *Genesis         `json:"genesis" yaml:"genesis" validate:"required"`
 		TransactionFeePool     string           `json:"transactionFeePool,omitempty" yaml:"transactionFeePool,omitempty"`
@@ -409,6 +410,13 @@ func ImportAndValidateConfig(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	// Networks can extend other networks, which has to be resolved prior to validation
+	if config.Blockchain != nil {
+		if errs := resolveNetworkExtensions(config.Blockchain.Networks); len(errs) > 0 {
+			return nil, errs[0]
+		}
+	}
+
 	// Validate if config provided is formatted correctly
 	err = validateConfig(config)
 	if err != nil {
@@ -421,32 +429,73 @@ func ImportAndValidateConfig(configFilePath string) (*Config, error) {
 type BlockchainGenerationOpts struct {
 	FrontendExplorerType FrontendExplorerType
 	FrontendFaucet       bool
+	// Docker enables the generation of a Dockerfile, daemon entrypoint script
+	// and docker-compose file running a network of nodes
+	Docker bool
+	// TemplateCacheDir is the directory Github template tarballs are cached in,
+	// the rivinecg directory within the user cache directory is used if not defined
+	TemplateCacheDir string
+	// RefreshTemplateCache enforces Github template tarballs to be downloaded,
+	// even if they are cached already
+	RefreshTemplateCache bool
 }
 
 // GenerateBlockchain imports a config file and uses it to generate a blockchain
 func GenerateBlockchain(configFilePath, outputDir string, opts *BlockchainGenerationOpts) error {
-	config, err := ImportAndValidateConfig(configFilePath)
+	config, err := generateBlockchain(configFilePath, outputDir, opts)
 	if err != nil {
 		return err
 	}
 
+	printSteps(outputDir, config.Blockchain.Binaries.Daemon)
+	if opts != nil && opts.Docker {
+		fmt.Println("9. Or launch a local network of nodes using docker: docker-compose up")
+	}
+	if isExplorerDeploymentEnabled(config, opts) {
+		fmt.Printf("Serve the explorer at %s using deploy/explorer/Caddyfile or deploy/explorer/nginx.conf \n", config.Frontend.Explorer.DNS)
+	}
+	return nil
+}
+
+// generateBlockchain imports a config file and uses it to generate a blockchain,
+// returning the imported config with all default values assigned
+func generateBlockchain(configFilePath, outputDir string, opts *BlockchainGenerationOpts) (*Config, error) {
+	config, err := ImportAndValidateConfig(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
 	config, err = assignDefaultValues(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	commitHash, err := getTemplateRepo(config.Template.Repository, config.Template.Version, outputDir)
+	// local template repositories are resolved relative to the directory of the config file
+	baseDir := filepath.Dir(configFilePath)
+	templateDirPath, err := fetchTemplate(config.Template.Repository, config.Template.Version, baseDir, outputDir, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = generateBlockchainTemplate(outputDir, commitHash, config, opts)
+	err = generateBlockchainTemplate(outputDir, templateDirPath, baseDir, config, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	printSteps(outputDir, config.Blockchain.Binaries.Daemon)
-	return nil
+	if opts != nil && opts.Docker {
+		err = generateDockerArtifacts(outputDir, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate docker artifacts: %v", err)
+		}
+	}
+
+	if isExplorerDeploymentEnabled(config, opts) {
+		err = generateExplorerDeployment(outputDir, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate explorer deployment: %v", err)
+		}
+	}
+	return config, nil
 }
 
 // assignDefaultValues assign sane default values to missing parameters in config
@@ -522,6 +571,11 @@ func validateConfig(conf *Config) error {
 		}
 	}
 
+	// validates that all currency values can be expressed using the configured precision
+	if err := validateCurrencyValues(conf.Blockchain); err != nil {
+		return err
+	}
+
 	// validate that if minting plugin props are defined, that all minting props are defined
 	var (
 		pluginMintingValidated  = false
@@ -595,6 +649,45 @@ func validateConfig(conf *Config) error {
 	return err
 }
 
+// maxCurrencyPrecision is the largest supported currency precision,
+// such that the one coin multipliers of generated code fit in an uint64.
+const maxCurrencyPrecision = 18
+
+// Units returns the currency units defined by the precision of the currency.
+func (c Currency) Units() types.CurrencyUnits {
+	return types.NewCurrencyUnits(uint(c.Precision))
+}
+
+// validateCurrencyValues validates that all currency values of the networks
+// are valid and do not define more decimals than the currency precision allows.
+func validateCurrencyValues(blockc *Blockchain) error {
+	if blockc.Currency.Precision > maxCurrencyPrecision {
+		return fmt.Errorf("currency precision %d is too large, the maximum supported precision is %d", blockc.Currency.Precision, maxCurrencyPrecision)
+	}
+	// no unit name is given, as values are required to be plain decimal numbers
+	format := types.NewCurrencyFormat(blockc.Currency.Units(), "")
+	for networkName, network := range blockc.Networks {
+		if network == nil {
+			continue
+		}
+		values := []string{network.BlockCreatorFee, network.MinimumTransactionFee}
+		if network.Genesis != nil {
+			for _, co := range network.Genesis.CoinOutputs {
+				values = append(values, co.Value)
+			}
+		}
+		for _, value := range values {
+			if value == "" {
+				continue // optional value
+			}
+			if _, err := format.ParseCoins(value); err != nil {
+				return fmt.Errorf("invalid currency value %q for network %s: %v", value, networkName, err)
+			}
+		}
+	}
+	return nil
+}
+
 type ConfigGenerationOpts struct {
 	PluginMintingEnabled  bool
 	PluginAuthcoinEnabled bool
@@ -613,16 +706,51 @@ func GenerateConfigFile(filepath string, opts *ConfigGenerationOpts) error {
 	return encodeConfig(typ, file, filepath, opts)
 }
 
+// WriteConfigFile writes the given config to a blockchain config file,
+// encoded according to the extension of the file path
+func WriteConfigFile(filepath string, config *Config) error {
+	typ := path.Ext(filepath)
+	if !IsSupportedConfigFileType(typ) {
+		return ErrUnsupportedFileType
+	}
+	filepath = path.Clean(filepath)
+	file, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return encodeConfigStruct(typ, file, config)
+}
+
+// IsSupportedConfigFileType returns true if configs can be encoded
+// in the format of the given file extension (.yaml, .toml or .json)
+func IsSupportedConfigFileType(typ string) bool {
+	switch typ {
+	case ".yaml", ".toml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
 // encodeConfig generates a default config, encodes it according
 // to the given type, and writes it to the provided writer
 func encodeConfig(typ string, w io.Writer, filePath string, opts *ConfigGenerationOpts) error {
-	config := BuildConfigStruct(filePath, opts)
+	return encodeConfigStruct(typ, w, BuildConfigStruct(filePath, opts))
+}
+
+// encodeConfigStruct encodes the given config according
+// to the given type, and writes it to the provided writer
+func encodeConfigStruct(typ string, w io.Writer, config *Config) error {
 	var enc interface {
 		Encode(interface{}) error
 	}
 	switch typ {
 	case ".yaml":
 		enc = yaml.NewEncoder(w)
+	case ".toml":
+		return encodeTOMLConfig(w, config)
 	case ".json":
 		// properly format json file
 		t := json.NewEncoder(w)
@@ -643,6 +771,8 @@ func decodeConfig(typ string, r io.Reader) (*Config, error) {
 	switch typ {
 	case ".yaml":
 		dec = yaml.NewDecoder(r)
+	case ".toml":
+		return decodeTOMLConfig(r)
 	case ".json":
 		dec = json.NewDecoder(r)
 	default:
@@ -795,7 +925,7 @@ func BuildConfigStruct(filePath string, opts *ConfigGenerationOpts) *Config {
 			Repository: repository,
 			Currency: &Currency{
 				Unit:      "ROC",
-				Precision: 9,
+				Precision: types.DefaultCurrencyPrecision,
 			},
 			Ports: &Ports{
 				API: 23111,
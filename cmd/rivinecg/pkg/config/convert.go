@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ConfigConversionResult summarizes the conversion of a config file
+type ConfigConversionResult struct {
+	// Comments is the amount of comment lines of the source file
+	Comments int
+	// DroppedComments is the amount of comment lines which couldn't be preserved,
+	// which are all comment lines when converting to JSON
+	DroppedComments int
+}
+
+// ConvertConfigFile converts a config file to another format (.yaml, .toml or .json), based on the file extensions.
+// The config is only decoded (and not validated), such that configs which are being edited can be converted as well.
+// Comment lines are preserved when converting between YAML and TOML, comments preceding a property are
+// kept with that property, all other comments at the start or end of the file are kept there,
+// comments defined at the end of a line are not preserved.
+// Both paths can be the same, in which case the config file is reformatted.
+func ConvertConfigFile(srcFilePath, dstFilePath string) (*ConfigConversionResult, error) {
+	srcTyp, dstTyp := path.Ext(srcFilePath), path.Ext(dstFilePath)
+	if !IsSupportedConfigFileType(srcTyp) || !IsSupportedConfigFileType(dstTyp) {
+		return nil, ErrUnsupportedFileType
+	}
+	b, err := ioutil.ReadFile(srcFilePath)
+	if err != nil {
+		return nil, err
+	}
+	config, err := decodeConfig(srcTyp, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config file %s: %v", srcFilePath, err)
+	}
+	var buf bytes.Buffer
+	err = encodeConfigStruct(dstTyp, &buf, config)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := extractConfigComments(srcTyp, b)
+	result := &ConfigConversionResult{Comments: comments.count()}
+	out := buf.Bytes()
+	if dstTyp == ".json" {
+		result.DroppedComments = result.Comments
+	} else {
+		out, result.DroppedComments = comments.insert(dstTyp, out)
+	}
+	err = ioutil.WriteFile(dstFilePath, out, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// configComments are the comment lines of a (YAML or TOML) config file
+type configComments struct {
+	// header and footer comments are defined at the start and end of the file
+	header, footer []string
+	// properties maps the path of a property to the comments preceding it
+	properties map[string][]string
+}
+
+// extractConfigComments extracts the comment lines of a (YAML or TOML) config file,
+// comments are attached to the property defined on the first line following them
+func extractConfigComments(typ string, b []byte) *configComments {
+	comments := &configComments{properties: make(map[string][]string)}
+	if typ == ".json" {
+		return comments
+	}
+	// map each line to the most specific path defined on it (e.g. the table of a TOML header,
+	// rather than its parent tables), unless it is the first property of a list item,
+	// such that a comment preceding a list item is attached to the item
+	paths := make(map[int]string)
+	lines := newLineLocator(typ, b)
+	for _, p := range lines.sortedPaths() {
+		if existing, ok := paths[lines[p]]; !ok || len(p) > len(existing) {
+			paths[lines[p]] = p
+		}
+	}
+	for line, p := range paths {
+		if parent := parentPath(p); strings.HasSuffix(parent, "]") && lines[parent] == line {
+			paths[line] = parent
+		}
+	}
+
+	var (
+		pending    []string
+		properties bool
+	)
+	for n, line := range strings.Split(string(b), "\n") {
+		content := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(content, "#"):
+			pending = append(pending, content)
+		case content == "" || content == "---":
+			// comments separated from the first property are kept at the start of the file
+			if !properties && len(pending) > 0 {
+				comments.header = append(comments.header, pending...)
+				pending = nil
+			}
+		default:
+			p, ok := paths[n+1]
+			if !ok {
+				// lines such as the continuation of a value
+				continue
+			}
+			properties = true
+			if len(pending) > 0 {
+				comments.properties[p] = append(comments.properties[p], pending...)
+				pending = nil
+			}
+		}
+	}
+	comments.footer = pending
+	return comments
+}
+
+// count returns the amount of comment lines
+func (cc *configComments) count() int {
+	n := len(cc.header) + len(cc.footer)
+	for _, lines := range cc.properties {
+		n += len(lines)
+	}
+	return n
+}
+
+// insert inserts the comments into a (YAML or TOML) encoded config,
+// returning the amount of comment lines which couldn't be inserted.
+// The comments of a property are inserted prior to the line on which it is defined,
+// or prior to its first defined child property in case it isn't defined itself
+// (e.g. a TOML table only defining sub tables).
+func (cc *configComments) insert(typ string, b []byte) ([]byte, int) {
+	lines := newLineLocator(typ, b)
+	comments := make(map[int][]string)
+	var dropped int
+	properties := make([]string, 0, len(cc.properties))
+	for p := range cc.properties {
+		properties = append(properties, p)
+	}
+	sort.Strings(properties)
+	for _, p := range properties {
+		line, ok := lines[p]
+		if !ok {
+			for child, childLine := range lines {
+				if strings.HasPrefix(child, p+".") || strings.HasPrefix(child, p+"[") {
+					if !ok || childLine < line {
+						line, ok = childLine, true
+					}
+				}
+			}
+		}
+		if !ok {
+			dropped += len(cc.properties[p])
+			continue
+		}
+		comments[line] = append(comments[line], cc.properties[p]...)
+	}
+
+	var buf bytes.Buffer
+	for _, comment := range cc.header {
+		buf.WriteString(comment + "\n")
+	}
+	if len(cc.header) > 0 {
+		buf.WriteByte('\n')
+	}
+	content := strings.TrimSuffix(string(b), "\n")
+	for n, line := range strings.Split(content, "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		for _, comment := range comments[n+1] {
+			buf.WriteString(indent + comment + "\n")
+		}
+		buf.WriteString(line + "\n")
+	}
+	if len(cc.footer) > 0 {
+		buf.WriteByte('\n')
+	}
+	for _, comment := range cc.footer {
+		buf.WriteString(comment + "\n")
+	}
+	return buf.Bytes(), dropped
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertConfigFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-convert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "blockchaincfg.yaml")
+	err = GenerateConfigFile(filePath, &ConfigGenerationOpts{PluginMintingEnabled: true, PluginAuthcoinEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// convert the config through all formats, back to the original format
+	src := filePath
+	for _, name := range []string{"blockchaincfg.toml", "blockchaincfg.json", "converted.toml", "converted.yaml"} {
+		dst := filepath.Join(dir, name)
+		if _, err := ConvertConfigFile(src, dst); err != nil {
+			t.Fatalf("failed to convert %s to %s: %v", src, dst, err)
+		}
+		if _, err := ImportAndValidateConfig(dst); err != nil {
+			t.Errorf("converted config %s is invalid: %v", dst, err)
+		}
+		src = dst
+	}
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, expected) {
+		t.Errorf("config differs after round-trip conversion:\n%s\nexpected:\n%s", b, expected)
+	}
+
+	if _, err := ConvertConfigFile(filePath, filepath.Join(dir, "blockchaincfg.xml")); err != ErrUnsupportedFileType {
+		t.Errorf("unexpected error for an unsupported file type: %v", err)
+	}
+}
+
+func TestConvertConfigFileComments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-convert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const config = `# mychain config
+
+template:
+  # pinned template
+  repository: github.com/threefoldtech/rivine-chain-template
+  version: master
+blockchain:
+  name: mychain
+  repository: github.com/somebody/mychain
+  currency:
+    unit: MYC
+    precision: 9
+  ports:
+    api: 23110
+    rpc: 23112
+  networks:
+    # the main network
+    standard:
+      networkType: 1
+      genesis:
+        coinOutputs:
+        # founder allocation
+        - value: "100"
+          condition: 01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e
+        blockStakeOutputs:
+        - value: "3000"
+          # the block creator
+          condition: 01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e
+        genesisBlockTimestamp: 1524168391
+      bootstrapPeers:
+      - bootstrap1.example.com:23112
+`
+	yamlPath, tomlPath := filepath.Join(dir, "config.yaml"), filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(yamlPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ConvertConfigFile(yamlPath, tomlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Comments != 5 || result.DroppedComments != 0 {
+		t.Errorf("unexpected result: %v", result)
+	}
+	b, err := ioutil.ReadFile(tomlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"# mychain config\n\n[template]\n# pinned template\nrepository = ",
+		"# the main network\n[blockchain.networks.standard]\n",
+		"# founder allocation\n[[blockchain.networks.standard.genesis.coinOutputs]]\n",
+		"value = \"3000\"\n# the block creator\ncondition = ",
+	} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("converted TOML config doesn't contain %q:\n%s", expected, b)
+		}
+	}
+
+	// converting back results in the original config
+	if _, err = ConvertConfigFile(tomlPath, yamlPath); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = ioutil.ReadFile(yamlPath); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != config {
+		t.Errorf("unexpected YAML config:\n%s\nexpected:\n%s", b, config)
+	}
+
+	// JSON has no comments
+	result, err = ConvertConfigFile(yamlPath, filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Comments != 5 || result.DroppedComments != 5 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestLocateTOMLLines(t *testing.T) {
+	lines := locateTOMLLines([]byte(`# comment
+[blockchain]
+name = "my#chain" # comment
+"quoted.key" = 1
+bootstrapPeers = [
+  "a:1",
+  "b:2",
+]
+
+[[blockchain.networks.standard.genesis.coinOutputs]]
+value = "1"
+
+[[blockchain.networks.standard.genesis.coinOutputs]]
+value = "2"
+
+[blockchain.networks.standard.genesis.coinOutputs.condition]
+signaturesRequired = 2
+`))
+	for p, line := range map[string]int{
+		"blockchain":                2,
+		"blockchain.name":           3,
+		"blockchain.quoted.key":     4,
+		"blockchain.bootstrapPeers": 5,
+		"blockchain.networks":       10,
+		"blockchain.networks.standard.genesis.coinOutputs":                                 10,
+		"blockchain.networks.standard.genesis.coinOutputs[0]":                              10,
+		"blockchain.networks.standard.genesis.coinOutputs[0].value":                        11,
+		"blockchain.networks.standard.genesis.coinOutputs[1]":                              13,
+		"blockchain.networks.standard.genesis.coinOutputs[1].value":                        14,
+		"blockchain.networks.standard.genesis.coinOutputs[1].condition":                    16,
+		"blockchain.networks.standard.genesis.coinOutputs[1].condition.signaturesRequired": 17,
+	} {
+		if l := lines.line(p); l != line {
+			t.Errorf("%s: expected line %d, not %d", p, line, l)
+		}
+	}
+}
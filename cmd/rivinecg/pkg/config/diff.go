@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileDiffStatus defines how a generated file differs from the existing file
+type FileDiffStatus uint8
+
+const (
+	// FileDiffStatusAdded defines a file which doesn't exist yet
+	FileDiffStatusAdded FileDiffStatus = iota
+	// FileDiffStatusModified defines a file whose content or mode differs from the existing file
+	FileDiffStatusModified
+)
+
+func (s FileDiffStatus) String() string {
+	switch s {
+	case FileDiffStatusModified:
+		return "modified"
+	default:
+		return "added"
+	}
+}
+
+// FileDiff is the difference between a generated file and the existing file
+type FileDiff struct {
+	// Path of the file, relative to the output directory
+	Path   string
+	Status FileDiffStatus
+	// Diff is the unified diff of the content of a modified text file,
+	// empty if only its mode differs
+	Diff string
+	// Binary is true for (non-text) files whose content differs
+	Binary bool
+	// OldMode and NewMode are the modes of a modified file
+	OldMode, NewMode os.FileMode
+}
+
+// BlockchainDiff is the difference between what would be generated and an existing output directory
+type BlockchainDiff struct {
+	// Files that would be added or modified, sorted by path
+	Files []FileDiff
+	// Unchanged is the amount of generated files identical to the existing files
+	Unchanged int
+}
+
+// DiffBlockchain imports a config file and compares the blockchain it would generate
+// against the given (existing) output directory, without writing to that directory.
+// As generation never removes files, files which only exist in the output directory are not reported.
+func DiffBlockchain(configFilePath, outputDir string, opts *BlockchainGenerationOpts) (*BlockchainDiff, error) {
+	tmpDir, err := ioutil.TempDir("", "rivinecg-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	_, err = generateBlockchain(configFilePath, tmpDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return diffDirectories(tmpDir, outputDir)
+}
+
+// diffDirectories compares all files of the generated directory against those of the existing directory
+func diffDirectories(generatedDir, existingDir string) (*BlockchainDiff, error) {
+	diff := new(BlockchainDiff)
+	err := filepath.Walk(generatedDir, func(fPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(generatedDir, fPath)
+		if err != nil {
+			return err
+		}
+		existingInfo, err := os.Stat(filepath.Join(existingDir, relPath))
+		if err != nil {
+			if os.IsNotExist(err) {
+				diff.Files = append(diff.Files, FileDiff{
+					Path:    filepath.ToSlash(relPath),
+					Status:  FileDiffStatusAdded,
+					NewMode: info.Mode().Perm(),
+				})
+				return nil
+			}
+			return err
+		}
+		if existingInfo.IsDir() {
+			return fmt.Errorf("cannot compare generated file %s, as it is a directory in %s", relPath, existingDir)
+		}
+		generated, err := ioutil.ReadFile(fPath)
+		if err != nil {
+			return err
+		}
+		existing, err := ioutil.ReadFile(filepath.Join(existingDir, relPath))
+		if err != nil {
+			return err
+		}
+		fileDiff := FileDiff{
+			Path:    filepath.ToSlash(relPath),
+			Status:  FileDiffStatusModified,
+			OldMode: existingInfo.Mode().Perm(),
+			NewMode: info.Mode().Perm(),
+		}
+		if !bytes.Equal(generated, existing) {
+			if isBinaryContent(generated) || isBinaryContent(existing) {
+				fileDiff.Binary = true
+			} else {
+				fileDiff.Diff = unifiedDiff("a/"+fileDiff.Path, "b/"+fileDiff.Path, string(existing), string(generated))
+			}
+		} else if fileDiff.OldMode == fileDiff.NewMode {
+			diff.Unchanged++
+			return nil
+		}
+		diff.Files = append(diff.Files, fileDiff)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(diff.Files, func(i, j int) bool {
+		return diff.Files[i].Path < diff.Files[j].Path
+	})
+	return diff, nil
+}
+
+// isBinaryContent returns true if the content contains a NUL byte, as (non-text) binary files do
+func isBinaryContent(b []byte) bool {
+	return bytes.IndexByte(b, 0) >= 0
+}
+
+const (
+	// diffContextLines is the amount of unchanged lines shown around changes
+	diffContextLines = 3
+	// maxDiffCells limits the size of the table used to compute a minimal diff,
+	// larger changes are shown as replacing all changed lines
+	maxDiffCells = 1 << 22
+)
+
+// diffLine is a line of a diff: unchanged (' '), removed ('-') or added ('+')
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// splitLines splits text into lines, each line keeping its newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the line diff of a and b, using the longest common subsequence
+// of the lines in between their common prefix and suffix
+func diffLines(a, b []string) []diffLine {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(am)*len(bm) > maxDiffCells {
+		for _, line := range am {
+			lines = append(lines, diffLine{'-', line})
+		}
+		for _, line := range bm {
+			lines = append(lines, diffLine{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of am[i:] and bm[j:]
+		lcs := make([][]int32, len(am)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(bm)+1)
+		}
+		for i := len(am) - 1; i >= 0; i-- {
+			for j := len(bm) - 1; j >= 0; j-- {
+				if am[i] == bm[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(am) && j < len(bm) {
+			switch {
+			case am[i] == bm[j]:
+				lines = append(lines, diffLine{' ', am[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lines = append(lines, diffLine{'-', am[i]})
+				i++
+			default:
+				lines = append(lines, diffLine{'+', bm[j]})
+				j++
+			}
+		}
+		for ; i < len(am); i++ {
+			lines = append(lines, diffLine{'-', am[i]})
+		}
+		for ; j < len(bm); j++ {
+			lines = append(lines, diffLine{'+', bm[j]})
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return lines
+}
+
+// unifiedDiff returns the unified diff of two texts, empty if they are equal
+func unifiedDiff(fromName, toName, from, to string) string {
+	lines := diffLines(splitLines(from), splitLines(to))
+
+	var buf strings.Builder
+	// aBefore and bBefore are the amount of lines of a and b preceding line idx
+	aBefore, bBefore := 0, 0
+	advance := func(start, end int) {
+		for _, line := range lines[start:end] {
+			if line.kind != '+' {
+				aBefore++
+			}
+			if line.kind != '-' {
+				bBefore++
+			}
+		}
+	}
+	idx := 0
+	for idx < len(lines) {
+		// find the next change
+		next := idx
+		for next < len(lines) && lines[next].kind == ' ' {
+			next++
+		}
+		if next == len(lines) {
+			break
+		}
+		start := next - diffContextLines
+		if start < idx {
+			start = idx
+		}
+		// extend the hunk with all changes separated by no more than twice the context
+		end := next
+		for {
+			for end < len(lines) && lines[end].kind != ' ' {
+				end++
+			}
+			equal := end
+			for equal < len(lines) && lines[equal].kind == ' ' {
+				equal++
+			}
+			if equal < len(lines) && equal-end <= 2*diffContextLines {
+				end = equal
+				continue
+			}
+			if end+diffContextLines < equal {
+				end += diffContextLines
+			} else {
+				end = equal
+			}
+			break
+		}
+
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		advance(idx, start)
+		var aLen, bLen int
+		for _, line := range lines[start:end] {
+			if line.kind != '+' {
+				aLen++
+			}
+			if line.kind != '-' {
+				bLen++
+			}
+		}
+		aStart, bStart := aBefore+1, bBefore+1
+		if aLen == 0 {
+			aStart = aBefore
+		}
+		if bLen == 0 {
+			bStart = bBefore
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, line := range lines[start:end] {
+			buf.WriteByte(line.kind)
+			buf.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		advance(start, end)
+		idx = end
+	}
+	return buf.String()
+}
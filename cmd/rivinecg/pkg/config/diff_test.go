@@ -0,0 +1,97 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		from, to, diff string
+	}{
+		{"a\nb\n", "a\nb\n", ""},
+		{"", "a\n", "--- a/f\n+++ b/f\n@@ -0,0 +1,1 @@\n+a\n"},
+		{"a\n", "", "--- a/f\n+++ b/f\n@@ -1,1 +0,0 @@\n-a\n"},
+		{"a\nb\nc\n", "a\nx\nc\n", "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"a\n", "a", "--- a/f\n+++ b/f\n@@ -1,1 +1,1 @@\n-a\n+a\n\\ No newline at end of file\n"},
+		// changes far apart result in separate hunks, showing only 3 lines of context
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n",
+			"--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n",
+		},
+		// changes close to each other are merged into a single hunk
+		{
+			"1\n2\n3\n4\n5\n6\n7\n",
+			"1\n2\nx\n4\n5\n6\ny\n",
+			"--- a/f\n+++ b/f\n@@ -1,7 +1,7 @@\n 1\n 2\n-3\n+x\n 4\n 5\n 6\n-7\n+y\n",
+		},
+	}
+	for idx, testCase := range testCases {
+		if diff := unifiedDiff("a/f", "b/f", testCase.from, testCase.to); diff != testCase.diff {
+			t.Errorf("#%d: unexpected diff:\n%s\nexpected:\n%s", idx, diff, testCase.diff)
+		}
+	}
+}
+
+func TestDiffDirectories(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles := func(root string, files map[string]string) {
+		for name, content := range files {
+			fPath := filepath.Join(root, name)
+			if err := os.MkdirAll(filepath.Dir(fPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(fPath, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	generated, existing := filepath.Join(dir, "generated"), filepath.Join(dir, "existing")
+	writeFiles(generated, map[string]string{
+		"README.md":            "# mychain\n",
+		"cmd/mychaind/main.go": "package main\n\nfunc main() {}\n",
+		"pkg/config/config.go": "package config\n",
+		"docker/entrypoint.sh": "#!/bin/sh\n",
+	})
+	writeFiles(existing, map[string]string{
+		"README.md":            "# mychain\n",
+		"cmd/mychaind/main.go": "package main\n",
+		"docker/entrypoint.sh": "#!/bin/sh\n",
+		// files which aren't generated are never reported
+		"notes.txt": "todo\n",
+	})
+	if err := os.Chmod(filepath.Join(generated, "docker", "entrypoint.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := diffDirectories(generated, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Unchanged != 1 || len(diff.Files) != 3 {
+		t.Fatalf("unexpected diff: %v", diff)
+	}
+	main, entrypoint, config := diff.Files[0], diff.Files[1], diff.Files[2]
+	if main.Path != "cmd/mychaind/main.go" || main.Status != FileDiffStatusModified ||
+		main.Diff != "--- a/cmd/mychaind/main.go\n+++ b/cmd/mychaind/main.go\n@@ -1,1 +1,3 @@\n package main\n+\n+func main() {}\n" {
+		t.Errorf("unexpected diff of main.go: %v", main)
+	}
+	if entrypoint.Path != "docker/entrypoint.sh" || entrypoint.Status != FileDiffStatusModified || entrypoint.Diff != "" || entrypoint.OldMode != 0644 || entrypoint.NewMode != 0755 {
+		t.Errorf("unexpected diff of entrypoint.sh: %v", entrypoint)
+	}
+	if config.Path != "pkg/config/config.go" || config.Status != FileDiffStatusAdded {
+		t.Errorf("unexpected diff of config.go: %v", config)
+	}
+	// the existing directory is left untouched
+	if _, err := os.Stat(filepath.Join(existing, "pkg")); !os.IsNotExist(err) {
+		t.Errorf("existing directory is modified: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"text/template"
+)
+
+// devnetGenesisMnemonic is the mnemonic of the well-known devnet genesis address,
+// used by the default devnet network of generated configs
+const (
+	devnetGenesisMnemonic = "carbon boss inject cover mountain fetch fiber fit tornado cloth wing dinosaur proof joy intact fabric thumb rebel borrow poet chair network expire else"
+	devnetGenesisAddress  = "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+)
+
+// dockerNodeCount is the amount of nodes of the generated docker-compose network
+const dockerNodeCount = 3
+
+// dockerTemplateValues are the values used to generate the docker artifacts
+type dockerTemplateValues struct {
+	Config *Config
+	// Network run by the docker-compose nodes
+	Network string
+	// WalletMnemonic, if known, is the mnemonic of the genesis block stake address of the network
+	WalletMnemonic string
+	// Nodes are the names of the docker-compose nodes, the first of which is the bootstrap node
+	Nodes []string
+}
+
+// dockerArtifacts are the (file path, file mode and content template of the) generated docker artifacts
+var dockerArtifacts = []struct {
+	path     string
+	mode     os.FileMode
+	template string
+}{
+	{"Dockerfile", 0644, `# builds the {{.Config.Blockchain.Binaries.Daemon}} daemon and {{.Config.Blockchain.Binaries.Client}} client
+FROM golang:1.13 AS builder
+
+WORKDIR /go/src/{{.Config.Blockchain.Repository}}
+COPY . .
+RUN go build -o /out/{{.Config.Blockchain.Binaries.Daemon}} ./cmd/{{.Config.Blockchain.Binaries.Daemon}} \
+	&& go build -o /out/{{.Config.Blockchain.Binaries.Client}} ./cmd/{{.Config.Blockchain.Binaries.Client}}
+
+FROM debian:buster-slim
+
+COPY --from=builder /out/{{.Config.Blockchain.Binaries.Daemon}} /out/{{.Config.Blockchain.Binaries.Client}} /usr/local/bin/
+COPY docker/entrypoint.sh /usr/local/bin/entrypoint.sh
+
+ENV NETWORK={{.Network}}
+VOLUME /data
+EXPOSE {{.Config.Blockchain.Ports.RPC}}
+
+ENTRYPOINT ["entrypoint.sh"]
+`},
+	{"docker/entrypoint.sh", 0755, `#!/bin/sh
+# Starts the {{.Config.Blockchain.Binaries.Daemon}} daemon, configured using the following environment variables:
+#
+#   NETWORK          name of the network to connect to (default: {{.Network}})
+#   BOOTSTRAP_PEERS  space-separated peers to bootstrap from, instead of the default bootstrap peers of the network
+#   NO_BOOTSTRAP     disables bootstrapping when defined (e.g. for the first node of a network)
+#   WALLET_MNEMONIC  mnemonic of a seed to recover a plain wallet from, such that the node creates blocks
+#                    with the block stakes of that seed
+#
+# The API is only served within the container, use it with: docker exec <container> {{.Config.Blockchain.Binaries.Client}} <command>
+# Extra arguments are passed to the daemon.
+set -e
+
+NETWORK="${NETWORK:-{{.Network}}}"
+
+set -- --network "$NETWORK" --persistent-directory /data --rpc-addr ":{{.Config.Blockchain.Ports.RPC}}" --api-addr "localhost:{{.Config.Blockchain.Ports.API}}" "$@"
+for peer in $BOOTSTRAP_PEERS; do
+	set -- "$@" --bootstrap-peers "$peer"
+done
+if [ -n "$NO_BOOTSTRAP" ]; then
+	set -- "$@" --no-bootstrap
+fi
+
+{{.Config.Blockchain.Binaries.Daemon}} "$@" &
+pid=$!
+trap 'kill -TERM $pid' INT TERM
+
+if [ -n "$WALLET_MNEMONIC" ]; then
+	# wait until the API is served
+	until {{.Config.Blockchain.Binaries.Client}} --addr "localhost:{{.Config.Blockchain.Ports.API}}" consensus >/dev/null 2>&1; do
+		if ! kill -0 $pid 2>/dev/null; then
+			break
+		fi
+		sleep 1
+	done
+	# recovering fails if the wallet already exists, which is fine
+	{{.Config.Blockchain.Binaries.Client}} --addr "localhost:{{.Config.Blockchain.Ports.API}}" wallet recover --plain --seed "$WALLET_MNEMONIC" >/dev/null 2>&1 || true
+fi
+
+wait $pid
+`},
+	{"docker-compose.yml", 0644, `# runs a {{.Network}} network of {{len .Nodes}} {{.Config.Blockchain.Name}} nodes,
+# bootstrapped from {{index .Nodes 0}}, which creates the blocks
+version: "3"
+
+services:
+{{- range $idx, $node := .Nodes}}
+  {{$node}}:
+    build: .
+    image: {{$.Config.Blockchain.Name}}:latest
+    restart: unless-stopped
+    environment:
+      NETWORK: {{$.Network}}
+{{- if eq $idx 0}}
+      NO_BOOTSTRAP: "true"
+{{- if $.WalletMnemonic}}
+      WALLET_MNEMONIC: {{$.WalletMnemonic}}
+{{- else}}
+      # WALLET_MNEMONIC: <mnemonic of the genesis block stake address of the {{$.Network}} network>
+{{- end}}
+{{- else}}
+      BOOTSTRAP_PEERS: {{index $.Nodes 0}}:{{$.Config.Blockchain.Ports.RPC}}
+    depends_on:
+      - {{index $.Nodes 0}}
+{{- end}}
+    volumes:
+      - {{$node}}-data:/data
+{{- end}}
+
+volumes:
+{{- range .Nodes}}
+  {{.}}-data:
+{{- end}}
+`},
+	{".dockerignore", 0644, `.git
+frontend
+`},
+}
+
+// preferredNetwork returns the name of the first network (sorted by name) of the given type,
+// the first network if none is of that type
+func preferredNetwork(config *Config, typ NetworkType) string {
+	names := make([]string, 0, len(config.Blockchain.Networks))
+	for name := range config.Blockchain.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if network := config.Blockchain.Networks[name]; network != nil && network.NetworkType == typ {
+			return name
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// generateDockerArtifacts generates a Dockerfile building the daemon and client,
+// an entrypoint script for the daemon and a docker-compose file running a network of nodes.
+// Artifacts matching the ignore patterns of the config are not generated.
+func generateDockerArtifacts(destinationDirPath string, config *Config) error {
+	values := dockerTemplateValues{
+		Config: config,
+		// a devnet network is run if defined
+		Network: preferredNetwork(config, NetworkTypeDevnet),
+	}
+	if values.Network == "" {
+		return fmt.Errorf("config doesn't define any network to run using docker")
+	}
+	// the mnemonic of the well-known devnet genesis address can be configured,
+	// the mnemonics of other genesis addresses are unknown
+	if genesis := config.Blockchain.Networks[values.Network].Genesis; genesis != nil {
+		for _, output := range genesis.BlockStakeOutputs {
+			if output.Condition.Condition != nil && output.Condition.UnlockHash().String() == devnetGenesisAddress {
+				values.WalletMnemonic = devnetGenesisMnemonic
+				break
+			}
+		}
+	}
+	for idx := 1; idx <= dockerNodeCount; idx++ {
+		values.Nodes = append(values.Nodes, fmt.Sprintf("%s-node%d", config.Blockchain.Name, idx))
+	}
+
+	for _, artifact := range dockerArtifacts {
+		if isIgnoredArtifact(config, artifact.path) {
+			continue
+		}
+		err := generateArtifact(destinationDirPath, artifact.path, artifact.mode, artifact.template, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateArtifact generates the file at the given path, relative to the destination directory,
+// by executing the given template using the given values
+func generateArtifact(destinationDirPath, relPath string, mode os.FileMode, templ string, values interface{}) error {
+	t, err := template.New(relPath).Parse(templ)
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %v", relPath, err)
+	}
+	fPath := path.Join(destinationDirPath, relPath)
+	err = os.MkdirAll(path.Dir(fPath), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(fPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(file, values)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %v", relPath, err)
+	}
+	// ensure the mode of an existing file is updated as well
+	return os.Chmod(fPath, mode)
+}
+
+// isIgnoredArtifact returns true if the given (generated) artifact path matches an ignore pattern of the config
+func isIgnoredArtifact(config *Config, fPath string) bool {
+	if config.Generation == nil {
+		return false
+	}
+	for _, p := range config.Generation.Ignore {
+		if p.Match(fPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDockerArtifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := BuildConfigStruct("", &ConfigGenerationOpts{PluginMintingEnabled: true})
+	config.Blockchain.Name = "mychain"
+	config.Blockchain.Binaries = nil
+	config, err = assignDefaultValues(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = generateDockerArtifacts(dir, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readFile := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	dockerfile := readFile("Dockerfile")
+	if !strings.Contains(dockerfile, "go build -o /out/mychaind ./cmd/mychaind") || !strings.Contains(dockerfile, "ENV NETWORK=devnet") {
+		t.Errorf("unexpected Dockerfile:\n%s", dockerfile)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "docker", "entrypoint.sh")); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("entrypoint script isn't generated as an executable: %v", err)
+	}
+	compose := readFile("docker-compose.yml")
+	for _, expected := range []string{
+		"mychain-node1:",
+		"NO_BOOTSTRAP",
+		"WALLET_MNEMONIC: " + devnetGenesisMnemonic,
+		"mychain-node3:",
+		"BOOTSTRAP_PEERS: mychain-node1:23112",
+	} {
+		if !strings.Contains(compose, expected) {
+			t.Errorf("docker-compose.yml doesn't contain %q:\n%s", expected, compose)
+		}
+	}
+
+	// ignored artifacts aren't generated, and the mnemonic of unknown genesis addresses isn't configured
+	dir2 := filepath.Join(dir, "ignored")
+	var pattern GlobPattern
+	if err = pattern.UnmarshalText([]byte("Dockerfile")); err != nil {
+		t.Fatal(err)
+	}
+	config.Generation = &Generation{Ignore: []GlobPattern{pattern}}
+	config.Blockchain.Networks["devnet"].Genesis.BlockStakeOutputs = config.Blockchain.Networks["standard"].Genesis.BlockStakeOutputs
+	err = generateDockerArtifacts(dir2, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir2, "Dockerfile")); !os.IsNotExist(err) {
+		t.Errorf("ignored Dockerfile is generated: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir2, "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "WALLET_MNEMONIC: ") && !strings.Contains(string(b), "# WALLET_MNEMONIC: ") {
+		t.Errorf("unexpected wallet mnemonic:\n%s", b)
+	}
+}
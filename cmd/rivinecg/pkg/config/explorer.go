@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/daemon"
+)
+
+// explorerSettingsPath is the path of the generated settings of the explorer frontend
+const explorerSettingsPath = "frontend/explorer/settings.json"
+
+// ExplorerSettings are the settings of the explorer frontend, generated as JSON
+type ExplorerSettings struct {
+	// Name of the blockchain
+	Name string `json:"name"`
+	// Network served by the explorer
+	Network  string                   `json:"network"`
+	Currency ExplorerCurrencySettings `json:"currency"`
+	// APIURL is the URL the (public) daemon API routes are served at
+	APIURL string `json:"apiUrl"`
+}
+
+// ExplorerCurrencySettings define how the explorer frontend formats currency values
+type ExplorerCurrencySettings struct {
+	Unit      string `json:"unit"`
+	Precision uint64 `json:"precision"`
+}
+
+// explorerAPIRoute is a public daemon API route proxied by the explorer web server
+type explorerAPIRoute struct {
+	Path string
+	// Prefix indicates all paths starting with Path are matched, rather than only Path itself
+	Prefix bool
+}
+
+// explorerTemplateValues are the values used to generate the explorer web server configs
+type explorerTemplateValues struct {
+	Config  *Config
+	Network string
+	// Root is the directory the built explorer frontend is served from
+	Root      string
+	Routes    []explorerAPIRoute
+	UserAgent string
+}
+
+// explorerArtifacts are the (file path and content template of the) generated explorer web server configs
+var explorerArtifacts = []struct {
+	path     string
+	template string
+}{
+	{"deploy/explorer/Caddyfile", `# Serves the {{.Config.Blockchain.Name}} explorer (built into {{.Root}})
+# and the public routes of a {{.Config.Blockchain.Binaries.Daemon}} daemon running on the same host:
+#
+#   {{.Config.Blockchain.Binaries.Daemon}} --network {{.Network}} --api-addr localhost:{{.Config.Blockchain.Ports.API}}
+{{.Config.Frontend.Explorer.DNS}} {
+	tls {{.Config.Frontend.Explorer.TLS}}
+	encode gzip
+
+	@api {
+		method GET HEAD
+		path{{range .Routes}} {{.Path}}{{if .Prefix}}*{{end}}{{end}}
+	}
+	handle @api {
+		reverse_proxy localhost:{{.Config.Blockchain.Ports.API}} {
+			header_up User-Agent {{.UserAgent}}
+		}
+	}
+
+	handle {
+		root * {{.Root}}
+		try_files {path} /index.html
+		file_server
+	}
+}
+`},
+	{"deploy/explorer/nginx.conf", `# Serves the {{.Config.Blockchain.Name}} explorer (built into {{.Root}})
+# and the public routes of a {{.Config.Blockchain.Binaries.Daemon}} daemon running on the same host:
+#
+#   {{.Config.Blockchain.Binaries.Daemon}} --network {{.Network}} --api-addr localhost:{{.Config.Blockchain.Ports.API}}
+#
+# Serve it over https by requesting a certificate, e.g. using:
+#
+#   certbot --nginx -d {{.Config.Frontend.Explorer.DNS}} -m {{.Config.Frontend.Explorer.TLS}}
+server {
+	listen 80;
+	server_name {{.Config.Frontend.Explorer.DNS}};
+
+	gzip on;
+	gzip_types application/json application/javascript text/css;
+
+	root {{.Root}};
+	index index.html;
+{{range .Routes}}
+	location {{if not .Prefix}}= {{end}}{{.Path}} {
+		limit_except GET {
+			deny all;
+		}
+		proxy_pass http://localhost:{{$.Config.Blockchain.Ports.API}};
+		proxy_set_header User-Agent {{$.UserAgent}};
+		proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+	}
+{{end}}
+	location / {
+		try_files $uri $uri/ /index.html;
+	}
+}
+`},
+}
+
+// explorerAPIRoutes returns the public daemon API routes proxied by the explorer web server,
+// based on the default public routes of the daemon
+func explorerAPIRoutes() []explorerAPIRoute {
+	var routes []explorerAPIRoute
+	seen := make(map[explorerAPIRoute]bool)
+	for _, pattern := range api.DefaultAPIPublicRoutes {
+		route := explorerAPIRoute{Path: pattern}
+		// web server configs only match exact paths and path prefixes
+		if idx := strings.IndexAny(pattern, ":*"); idx >= 0 {
+			route = explorerAPIRoute{Path: pattern[:idx], Prefix: true}
+		}
+		if !seen[route] {
+			seen[route] = true
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// isExplorerDeploymentEnabled returns true if the explorer deployment is generated,
+// which is the case when the explorer frontend is generated and the explorer is configured
+func isExplorerDeploymentEnabled(config *Config, opts *BlockchainGenerationOpts) bool {
+	return opts != nil && opts.FrontendExplorerType != FrontendExplorerTypeNone &&
+		config.Frontend != nil && config.Frontend.Explorer != nil
+}
+
+// generateExplorerDeployment generates the settings of the explorer frontend,
+// as well as Caddy and nginx configs serving the explorer and the public routes of the daemon API
+// at the configured explorer domain. Files matching the ignore patterns of the config are not generated.
+func generateExplorerDeployment(destinationDirPath string, config *Config) error {
+	// an explorer serves the standard network if defined
+	network := preferredNetwork(config, NetworkTypeStandard)
+	if network == "" {
+		return fmt.Errorf("config doesn't define any network to explore")
+	}
+
+	if !isIgnoredArtifact(config, explorerSettingsPath) {
+		settings := ExplorerSettings{
+			Name:    config.Blockchain.Name,
+			Network: network,
+			Currency: ExplorerCurrencySettings{
+				Unit:      config.Blockchain.Currency.Unit,
+				Precision: config.Blockchain.Currency.Precision,
+			},
+			APIURL: "https://" + config.Frontend.Explorer.DNS,
+		}
+		b, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fPath := path.Join(destinationDirPath, explorerSettingsPath)
+		err = os.MkdirAll(path.Dir(fPath), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(fPath, append(b, '\n'), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	values := explorerTemplateValues{
+		Config:    config,
+		Network:   network,
+		Root:      fmt.Sprintf("/var/www/%s-explorer", config.Blockchain.Name),
+		Routes:    explorerAPIRoutes(),
+		UserAgent: daemon.RivineUserAgent,
+	}
+	for _, artifact := range explorerArtifacts {
+		if isIgnoredArtifact(config, artifact.path) {
+			continue
+		}
+		err := generateArtifact(destinationDirPath, artifact.path, 0644, artifact.template, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateExplorerDeployment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-explorer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := BuildConfigStruct("", nil)
+	config.Blockchain.Name = "mychain"
+	config.Blockchain.Binaries = nil
+	config, err = assignDefaultValues(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isExplorerDeploymentEnabled(config, &BlockchainGenerationOpts{FrontendExplorerType: FrontendExplorerTypeVueTypescript}) {
+		t.Error("explorer deployment isn't enabled for a configured explorer")
+	}
+	if isExplorerDeploymentEnabled(config, &BlockchainGenerationOpts{FrontendExplorerType: FrontendExplorerTypeNone}) {
+		t.Error("explorer deployment is enabled while no explorer frontend is generated")
+	}
+	err = generateExplorerDeployment(dir, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readFile := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	var settings ExplorerSettings
+	if err = json.Unmarshal([]byte(readFile(explorerSettingsPath)), &settings); err != nil {
+		t.Fatal(err)
+	}
+	expectedSettings := ExplorerSettings{
+		Name:     "mychain",
+		Network:  "standard",
+		Currency: ExplorerCurrencySettings{Unit: "ROC", Precision: config.Blockchain.Currency.Precision},
+		APIURL:   "https://explorer.example.com",
+	}
+	if settings != expectedSettings {
+		t.Errorf("unexpected explorer settings: %+v", settings)
+	}
+
+	caddyfile := readFile("deploy/explorer/Caddyfile")
+	for _, expected := range []string{
+		"explorer.example.com {",
+		"tls support@example.com",
+		"path /consensus /consensus/* /explorer /explorer/*",
+		"reverse_proxy localhost:23111",
+		"header_up User-Agent Rivine-Agent",
+		"root * /var/www/mychain-explorer",
+		"mychaind --network standard --api-addr localhost:23111",
+	} {
+		if !strings.Contains(caddyfile, expected) {
+			t.Errorf("Caddyfile doesn't contain %q:\n%s", expected, caddyfile)
+		}
+	}
+	nginx := readFile("deploy/explorer/nginx.conf")
+	for _, expected := range []string{
+		"server_name explorer.example.com;",
+		"location = /explorer {",
+		"location /explorer/ {",
+		"proxy_pass http://localhost:23111;",
+		"proxy_set_header User-Agent Rivine-Agent;",
+	} {
+		if !strings.Contains(nginx, expected) {
+			t.Errorf("nginx.conf doesn't contain %q:\n%s", expected, nginx)
+		}
+	}
+
+	// ignored files aren't generated
+	dir2 := filepath.Join(dir, "ignored")
+	var pattern GlobPattern
+	if err = pattern.UnmarshalText([]byte("deploy/explorer/*")); err != nil {
+		t.Fatal(err)
+	}
+	config.Generation = &Generation{Ignore: []GlobPattern{pattern}}
+	err = generateExplorerDeployment(dir2, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir2, "deploy")); !os.IsNotExist(err) {
+		t.Errorf("ignored web server configs are generated: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir2, explorerSettingsPath)); err != nil {
+		t.Errorf("explorer settings aren't generated: %v", err)
+	}
+}
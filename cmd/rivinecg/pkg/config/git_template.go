@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// isGitTemplateRepository returns true if the given template repository is to be cloned using git,
+// rather than downloaded as a tarball using the Github API. This is the case for all remote repositories
+// not hosted on Github (e.g. GitLab or Gitea instances), as well as for explicit git URLs
+// (ssh URLs, scp-like URLs such as git@example.com:owner/repo and URLs ending in .git).
+func isGitTemplateRepository(repository string) bool {
+	if isLocalTemplateRepository(repository) {
+		return false
+	}
+	if strings.HasPrefix(repository, "ssh://") || strings.HasPrefix(repository, "git://") ||
+		strings.HasPrefix(repository, "git@") || strings.HasSuffix(repository, ".git") {
+		return true
+	}
+	_, _, err := githubOwnerAndRepoFromString(repository)
+	return err != nil
+}
+
+// _scpLikeGitURLRgxp matches scp-like git URLs, such as git@gitlab.com:owner/repo.git
+var _scpLikeGitURLRgxp = regexp.MustCompile(`^[^/:@\s]+@[^/:\s]+:[^\s]+$`)
+
+// gitCloneURL returns the URL used to clone the given template repository,
+// repositories without a scheme (e.g. gitlab.com/owner/repo) are cloned using https.
+func gitCloneURL(repository string) (string, error) {
+	if repository == "" || strings.ContainsAny(repository, " \t\r\n") {
+		return "", fmt.Errorf("invalid git repository: %q", repository)
+	}
+	if _scpLikeGitURLRgxp.MatchString(repository) {
+		return repository, nil
+	}
+	if idx := strings.Index(repository, "://"); idx >= 0 {
+		if idx == 0 || len(repository) == idx+3 {
+			return "", fmt.Errorf("invalid git repository: %q", repository)
+		}
+		return repository, nil
+	}
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) != 2 || !strings.Contains(parts[0], ".") || strings.Trim(parts[1], "/") == "" {
+		return "", fmt.Errorf("invalid git repository (expected a host and path, e.g. gitlab.com/owner/repo): %s", repository)
+	}
+	return "https://" + repository, nil
+}
+
+// runGit runs a git command, returning the trimmed output,
+// or an error containing the reported problem if it failed.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// never prompt for credentials, as rivinecg isn't interactive
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// getGitTemplateRepo clones the given version (a branch, tag or commit) of the template repository
+// into a new directory within the destination directory, returning the path of that directory.
+func getGitTemplateRepo(repository, version, destination string) (string, error) {
+	url, err := gitCloneURL(repository)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(destination, 0755)
+	if err != nil {
+		return "", err
+	}
+	dir, err := ioutil.TempDir(destination, ".template-")
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Cloning repository: %s (%s) ...\n", url, version)
+	// a shallow clone suffices for branches and tags
+	_, err = runGit("", "clone", "--quiet", "--depth", "1", "--branch", version, url, dir)
+	if err != nil {
+		// commits can only be checked out from a full clone
+		os.RemoveAll(dir)
+		_, err = runGit("", "clone", "--quiet", "--no-checkout", url, dir)
+		if err == nil {
+			_, err = runGit(dir, "checkout", "--quiet", version)
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	commitHash, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	fmt.Printf("Checked out commit: %s\n", commitHash)
+
+	// the git metadata isn't part of the template
+	err = os.RemoveAll(filepath.Join(dir, ".git"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// _gitCommitRgxp matches (abbreviated) commit hashes
+var _gitCommitRgxp = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// checkGitTemplateRepository checks whether or not the template repository is reachable using git,
+// and whether or not it defines the given version, should it be a branch or tag.
+func checkGitTemplateRepository(repository, version string) error {
+	url, err := gitCloneURL(repository)
+	if err != nil {
+		return err
+	}
+	refs, err := runGit("", "ls-remote", "--heads", "--tags", url)
+	if err != nil {
+		return fmt.Errorf("repository is unreachable: %v", err)
+	}
+	if _gitCommitRgxp.MatchString(version) {
+		return nil // commits can't be checked without cloning
+	}
+	for _, line := range strings.Split(refs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		ref := strings.TrimSuffix(fields[1], "^{}")
+		if ref == "refs/heads/"+version || ref == "refs/tags/"+version {
+			return nil
+		}
+	}
+	return fmt.Errorf("version %s of repository %s does not exist", version, repository)
+}
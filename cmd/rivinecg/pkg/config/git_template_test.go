@@ -0,0 +1,141 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsGitTemplateRepository(t *testing.T) {
+	testCases := []struct {
+		repository string
+		git        bool
+	}{
+		{"github.com/threefoldtech/rivine-chain-template", false},
+		{"https://github.com/threefoldtech/rivine-chain-template", false},
+		{"https://github.com/threefoldtech/rivine-chain-template.git", true},
+		{"git@github.com:threefoldtech/rivine-chain-template.git", true},
+		{"gitlab.com/threefoldtech/rivine-chain-template", true},
+		{"https://gitea.example.com/owner/template", true},
+		{"ssh://git@gitlab.example.com:2222/group/subgroup/template", true},
+		{"./rivine-chain-template", false},
+		{"rivine-chain-template.tar.gz", false},
+	}
+	for _, testCase := range testCases {
+		if git := isGitTemplateRepository(testCase.repository); git != testCase.git {
+			t.Errorf("%s: expected git to be %v, not %v", testCase.repository, testCase.git, git)
+		}
+	}
+}
+
+func TestGitCloneURL(t *testing.T) {
+	testCases := []struct {
+		repository, url string
+	}{
+		{"gitlab.com/owner/repo", "https://gitlab.com/owner/repo"},
+		{"gitlab.example.com/group/subgroup/repo.git", "https://gitlab.example.com/group/subgroup/repo.git"},
+		{"git@gitlab.com:owner/repo.git", "git@gitlab.com:owner/repo.git"},
+		{"ssh://git@gitlab.com/owner/repo.git", "ssh://git@gitlab.com/owner/repo.git"},
+		{"http://gitea.local:3000/owner/repo", "http://gitea.local:3000/owner/repo"},
+		{"", ""},
+		{"repo", ""},
+		{"gitlab.com/", ""},
+		{"https://", ""},
+		{"gitlab.com/owner/my repo", ""},
+	}
+	for _, testCase := range testCases {
+		url, err := gitCloneURL(testCase.repository)
+		if testCase.url == "" {
+			if err == nil {
+				t.Errorf("%q: expected an error, got URL %s", testCase.repository, url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", testCase.repository, err)
+		} else if url != testCase.url {
+			t.Errorf("%q: expected URL %s, not %s", testCase.repository, testCase.url, url)
+		}
+	}
+}
+
+func TestGetGitTemplateRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir, err := ioutil.TempDir("", "rivinecg-git-template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// create a repository with two commits, the first of which is tagged
+	repoDir := filepath.Join(dir, "repo")
+	git := func(args ...string) string {
+		out, err := runGit(repoDir, append([]string{"-c", "user.name=rivinecg", "-c", "user.email=rivinecg@example.com"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "--quiet")
+	git("checkout", "--quiet", "-b", "master")
+	writeVersion := func(version string) {
+		if err := ioutil.WriteFile(filepath.Join(repoDir, "version.txt"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "version.txt")
+		git("commit", "--quiet", "-m", version)
+	}
+	writeVersion("v1")
+	git("tag", "v1.0.0")
+	firstCommit := git("rev-parse", "HEAD")
+	writeVersion("v2")
+
+	url := "file://" + repoDir
+	for version, expected := range map[string]string{
+		"master":         "v2",
+		"v1.0.0":         "v1",
+		firstCommit:      "v1",
+		firstCommit[:10]: "v1",
+	} {
+		templDir, err := getGitTemplateRepo(url, version, filepath.Join(dir, "out"))
+		if err != nil {
+			t.Errorf("%s: failed to clone: %v", version, err)
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(templDir, "version.txt"))
+		if err != nil {
+			t.Errorf("%s: %v", version, err)
+		} else if string(b) != expected {
+			t.Errorf("%s: expected %s to be checked out, not %s", version, expected, b)
+		}
+		if _, err := os.Stat(filepath.Join(templDir, ".git")); !os.IsNotExist(err) {
+			t.Errorf("%s: git metadata isn't removed: %v", version, err)
+		}
+		if err := os.RemoveAll(templDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkGitTemplateRepository(url, version); err != nil {
+			t.Errorf("%s: unexpected repository check error: %v", version, err)
+		}
+	}
+
+	if _, err := getGitTemplateRepo(url, "unknown", filepath.Join(dir, "out")); err == nil {
+		t.Error("expected cloning an unknown version to fail")
+	}
+	if infos, err := ioutil.ReadDir(filepath.Join(dir, "out")); err != nil || len(infos) != 0 {
+		t.Errorf("expected failed clones to be cleaned up: %v (%d entries)", err, len(infos))
+	}
+	if err := checkGitTemplateRepository(url, "unknown"); err == nil {
+		t.Error("expected checking an unknown version to fail")
+	}
+	if err := checkGitTemplateRepository("file://"+filepath.Join(dir, "missing"), "master"); err == nil {
+		t.Error("expected checking a missing repository to fail")
+	}
+}
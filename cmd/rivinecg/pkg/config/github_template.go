@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"os/exec"
 	"path"
@@ -23,40 +22,55 @@ import (
 	"github.com/threefoldtech/rivine/types"
 )
 
-const rootGithubAPIurl = "https://api.github.com"
+var rootGithubAPIurl = "https://api.github.com"
 
 var _githubRgxp = regexp.MustCompile(`github.com/([^/]+)/([^/]+)`)
 
 func githubOwnerAndRepoFromString(s string) (string, string, error) {
 	repoGithubMatches := _githubRgxp.FindStringSubmatch(s)
 	if len(repoGithubMatches) != 3 {
-		return "", "", fmt.Errorf("invalid repository (only Github is supported at the moment): %s", s)
+		return "", "", fmt.Errorf("invalid Github repository: %s", s)
 	}
 	return repoGithubMatches[1], repoGithubMatches[2], nil
 }
 
-// getTemplateRepo fetches the template repository from github and extracts this tar file.
-// At the end of this function we extract the commithash from the headers in order to rename this extracted directory later.
-func getTemplateRepo(repository, version, destination string) (string, error) {
+// getTemplateRepo fetches the template repository from github, using the template cache,
+// and extracts its tarball into a new directory within the destination directory, returning the path of that directory.
+func getTemplateRepo(repository, version, destination string, opts *BlockchainGenerationOpts) (string, error) {
 	templOwner, templRepo, err := githubOwnerAndRepoFromString(repository)
 	if err != nil {
 		return "", err
 	}
+	var (
+		cacheDir string
+		refresh  bool
+	)
+	if opts != nil {
+		cacheDir, refresh = opts.TemplateCacheDir, opts.RefreshTemplateCache
+	}
+	cache, err := newTemplateCache(cacheDir, templOwner, templRepo)
+	if err != nil {
+		return "", err
+	}
+	tarball, err := cache.tarball(version, refresh)
+	if err != nil {
+		return "", err
+	}
 
-	endPoint := rootGithubAPIurl + path.Join("/repos", templOwner, templRepo, "tarball", version)
-	fmt.Printf("Fetching repository: %s ...\n", endPoint)
-	resp, err := http.Get(endPoint)
+	err = os.MkdirAll(destination, 0755)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	err = untar(destination, resp.Body)
+	dst, err := ioutil.TempDir(destination, ".template-")
 	if err != nil {
 		return "", err
 	}
-	// Extract the commitHash from the headers, need this to rename directory later
-	commitHash := strings.Split(strings.Split(strings.Split(resp.Header["Content-Disposition"][0], "=")[1], ".")[0], "-")[4]
-	return commitHash, nil
+	err = untarLocalTemplate(tarball, dst)
+	if err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+	return dst, nil
 }
 
 type TemplateConfig struct {
@@ -73,7 +87,7 @@ type TemplateFrontendTypeConfig struct {
 	Branch     string `json:"branch" validate:"required"`
 }
 
-func generateBlockchainTemplate(destinationDirPath, commitHash string, config *Config, opts *BlockchainGenerationOpts) error {
+func generateBlockchainTemplate(destinationDirPath, dirPath, baseDir string, config *Config, opts *BlockchainGenerationOpts) error {
 	var templateConfig *TemplateConfig
 
 	var fPathAction func(fPath, dirPath, destPath string) error
@@ -95,15 +109,8 @@ func generateBlockchainTemplate(destinationDirPath, commitHash string, config *C
 		}
 	}
 
-	templOwner, templRepo, err := githubOwnerAndRepoFromString(config.Template.Repository)
-	if err != nil {
-		return err
-	}
-	// Directory where the contents of template repo is unpackged
-	dirPath := path.Join(destinationDirPath, templOwner+"-"+templRepo+"-"+commitHash)
-
-	// walk over the files, and copy only those not ignored
-	err = filepath.Walk(dirPath, func(fPath string, info os.FileInfo, err error) error {
+	// walk over the files of the template repo (unpacked in dirPath), and copy only those not ignored
+	err := filepath.Walk(dirPath, func(fPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err // return an error immediately
 		}
@@ -150,18 +157,12 @@ func generateBlockchainTemplate(destinationDirPath, commitHash string, config *C
 			return fmt.Errorf("used template repo doesn't link to an explorer frontend template of type %s (%d)", frontendExplorerTypeStr, opts.FrontendExplorerType)
 		}
 
-		explorerTemplOwner, explorerTemplRepo, err := githubOwnerAndRepoFromString(explorerFrontendConfig.Repository)
-		if err != nil {
-			return fmt.Errorf("invalid frontend explorer (type: %s) template repo %s: %v", frontendExplorerTypeStr, explorerFrontendConfig.Repository, err)
-		}
-		commitHash, err := getTemplateRepo(explorerFrontendConfig.Repository, explorerFrontendConfig.Branch, destinationDirPath)
+		// Directory where the contents of template repo is unpacked
+		frontendExplorerDirPath, err := fetchTemplate(explorerFrontendConfig.Repository, explorerFrontendConfig.Branch, baseDir, destinationDirPath, opts)
 		if err != nil {
-			return fmt.Errorf("failed to download frontend explorer (type: %s) template repo %s: %v", frontendExplorerTypeStr, explorerFrontendConfig.Repository, err)
+			return fmt.Errorf("failed to fetch frontend explorer (type: %s) template repo %s: %v", frontendExplorerTypeStr, explorerFrontendConfig.Repository, err)
 		}
 
-		// Directory where the contents of template repo is unpacked
-		frontendExplorerDirPath := path.Join(destinationDirPath, explorerTemplOwner+"-"+explorerTemplRepo+"-"+commitHash)
-
 		// Directory where the frontend explorer needs to be generated to
 		frontendExplorerDestinationPath := path.Join(destinationDirPath, "frontend", "explorer")
 
@@ -215,18 +216,12 @@ func generateBlockchainTemplate(destinationDirPath, commitHash string, config *C
 			return errors.New("used template repo doesn't link to an faucet frontend template of type go")
 		}
 
-		faucetTemplOwner, faucetTempRepo, err := githubOwnerAndRepoFromString(explorerFaucetConfig.Repository)
-		if err != nil {
-			return fmt.Errorf("invalid faucet explorer (type: go) template repo %s: %v", explorerFaucetConfig.Repository, err)
-		}
-		commitHash, err := getTemplateRepo(explorerFaucetConfig.Repository, explorerFaucetConfig.Branch, destinationDirPath)
+		// Directory where the contents of template repo is unpacked
+		frontendFaucetDirPath, err := fetchTemplate(explorerFaucetConfig.Repository, explorerFaucetConfig.Branch, baseDir, destinationDirPath, opts)
 		if err != nil {
-			return fmt.Errorf("failed to download faucet explorer (type: go) template repo %s: %v", explorerFaucetConfig.Repository, err)
+			return fmt.Errorf("failed to fetch faucet explorer (type: go) template repo %s: %v", explorerFaucetConfig.Repository, err)
 		}
 
-		// Directory where the contents of template repo is unpacked
-		frontendFaucetDirPath := path.Join(destinationDirPath, faucetTemplOwner+"-"+faucetTempRepo+"-"+commitHash)
-
 		// Directory where the frontend faucet needs to be generated to
 		frontendFaucetDestinationPath := path.Join(destinationDirPath, "frontend", "faucet")
 
@@ -309,6 +304,7 @@ func writeTemplateValues(destinationDirPath string, config *Config) error {
 		"formatConditionAsUnlockhashString":            formatConditionAsUnlockhashString,
 		"formatConditionAsGoString":                    formatConditionAsGoString,
 		"formatValueStringAsOneCoinCurrencyMultiplier": formatValueStringAsOneCoinCurrencyMultiplier,
+		"formatPrecisionAsCurrencyUnitsGoString":       formatPrecisionAsCurrencyUnitsGoString,
 	}
 	for n, f := range sprig.FuncMap() {
 		fmap[n] = f
@@ -436,6 +432,13 @@ func formatValueStringAsOneCoinCurrencyMultiplier(v string) (string, error) {
 	return fmt.Sprintf(".Mul64(%s%s).Div64(1%s)", parts[0], parts[1], strings.Repeat("0", len(parts[1]))), nil
 }
 
+func formatPrecisionAsCurrencyUnitsGoString(precision uint64) string {
+	if precision == types.DefaultCurrencyPrecision {
+		return "types.DefaultCurrencyUnits()"
+	}
+	return fmt.Sprintf("types.NewCurrencyUnits(%d)", precision)
+}
+
 func readTemplateFileAsString(filepath string) (string, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
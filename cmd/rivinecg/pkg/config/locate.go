@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// lineLocator maps the paths of the properties defined in a config file
+// (e.g. blockchain.networks.devnet.genesis.coinOutputs[0].value)
+// to the (1-indexed) line on which they are defined.
+type lineLocator map[string]int
+
+// pathAliases are the property names which differ between the
+// YAML and JSON encoding of a config, paths use the YAML names
+var pathAliases = map[string]string{
+	"coinOutputs": "coinOuputs",
+}
+
+// newLineLocator creates a line locator for the content of a config file of the given type.
+func newLineLocator(typ string, b []byte) lineLocator {
+	switch typ {
+	case ".json":
+		return locateJSONLines(b)
+	case ".yaml":
+		return locateYAMLLines(b)
+	case ".toml":
+		return locateTOMLLines(b)
+	default:
+		return lineLocator{}
+	}
+}
+
+// line returns the line on which the property of the given path is defined.
+// If it isn't defined, the line of its closest defined parent is returned,
+// and 0 if none of its parents are defined either.
+func (l lineLocator) line(path string) int {
+	for p := path; p != ""; p = parentPath(p) {
+		if line, ok := l[p]; ok {
+			return line
+		}
+		for name, alias := range pathAliases {
+			if line, ok := l[strings.Replace(p, name, alias, -1)]; ok {
+				return line
+			}
+		}
+	}
+	return 0
+}
+
+// sortedPaths returns the paths of a line locator, sorted by line and path
+func (l lineLocator) sortedPaths() []string {
+	paths := make([]string, 0, len(l))
+	for p := range l {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if l[paths[i]] != l[paths[j]] {
+			return l[paths[i]] < l[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+// parentPath returns the path of the parent of the property of the given path
+func parentPath(path string) string {
+	idx := strings.LastIndexAny(path, ".[")
+	if idx <= 0 {
+		return ""
+	}
+	return path[:idx]
+}
+
+// childPath returns the path of a property of the given parent path
+func childPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// itemPath returns the path of an item of the list of the given path
+func itemPath(list string, index int) string {
+	return fmt.Sprintf("%s[%d]", list, index)
+}
+
+// lineAt returns the (1-indexed) line of the given offset
+func lineAt(b []byte, offset int64) int {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	return bytes.Count(b[:offset], []byte{'\n'}) + 1
+}
+
+// locateJSONLines locates the lines of all properties of a JSON-encoded config
+func locateJSONLines(b []byte) lineLocator {
+	lines := lineLocator{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// errors are ignored, as they are reported when decoding the config,
+	// all lines located prior to an error remain usable
+	walkJSON(dec, b, "", lines)
+	return lines
+}
+
+// nextTokenOffset returns the offset of the next token of the decoder,
+// skipping the whitespace and separators preceding it
+func nextTokenOffset(dec *json.Decoder, b []byte) int64 {
+	offset := dec.InputOffset()
+	for offset < int64(len(b)) && strings.IndexByte(" \t\r\n,:", b[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// walkJSON walks a single JSON value, locating the lines of all its properties
+func walkJSON(dec *json.Decoder, b []byte, path string, lines lineLocator) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil // scalar value
+	}
+	switch delim {
+	case '{':
+		for dec.More() {
+			offset := nextTokenOffset(dec, b)
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			p := childPath(path, fmt.Sprint(key))
+			lines[p] = lineAt(b, offset)
+			if err = walkJSON(dec, b, p, lines); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for index := 0; dec.More(); index++ {
+			p := itemPath(path, index)
+			lines[p] = lineAt(b, nextTokenOffset(dec, b))
+			if err = walkJSON(dec, b, p, lines); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token() // closing delimiter
+	return err
+}
+
+// yamlEntry is a (mapping or sequence) entry on the stack of locateYAMLLines
+type yamlEntry struct {
+	indent int
+	path   string
+	item   bool
+	// items is the amount of sequence items of the entry seen so far
+	items int
+}
+
+// locateYAMLLines locates the lines of all properties of a YAML-encoded config.
+// Only the block style is supported, which is the style used by the generated configs,
+// properties defined using the flow style are located at the line of their parent.
+func locateYAMLLines(b []byte) lineLocator {
+	lines := lineLocator{}
+	var stack []*yamlEntry
+	for n, line := range strings.Split(string(b), "\n") {
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+		indent := len(line) - len(content)
+		isItem := content == "-" || strings.HasPrefix(content, "- ")
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.indent < indent || (top.indent == indent && isItem && !top.item) {
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+		if isItem {
+			if len(stack) == 0 {
+				continue // top-level sequences aren't used by configs
+			}
+			parent := stack[len(stack)-1]
+			p := itemPath(parent.path, parent.items)
+			parent.items++
+			lines[p] = n + 1
+			stack = append(stack, &yamlEntry{indent: indent, path: p, item: true})
+			// the item can define the first property of a mapping on the same line
+			content = strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			indent = len(line) - len(content)
+		}
+		key, ok := yamlKey(content)
+		if !ok {
+			continue
+		}
+		parent := ""
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1].path
+		}
+		p := childPath(parent, key)
+		lines[p] = n + 1
+		stack = append(stack, &yamlEntry{indent: indent, path: p})
+	}
+	return lines
+}
+
+// yamlKey returns the key of a YAML mapping line, if the line defines one
+func yamlKey(content string) (string, bool) {
+	var idx int
+	switch {
+	case strings.HasSuffix(content, ":"):
+		idx = len(content) - 1
+	default:
+		idx = strings.Index(content, ": ")
+		if idx < 0 {
+			return "", false
+		}
+	}
+	key := strings.TrimSpace(content[:idx])
+	if key == "" || key[0] == '{' || key[0] == '[' {
+		return "", false // flow style
+	}
+	return strings.Trim(key, `"'`), true
+}
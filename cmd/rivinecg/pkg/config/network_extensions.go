@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NetworkExtensionError is returned in case a network extends an unknown network,
+// or in case networks extend each other
+type NetworkExtensionError struct {
+	// Network is the name of the network which cannot be extended
+	Network string
+	Err     error
+}
+
+// Error implements error.Error
+func (err *NetworkExtensionError) Error() string {
+	return fmt.Sprintf("network %s: %v", err.Network, err.Err)
+}
+
+// resolveNetworkExtensions assigns the properties of extended networks
+// to the properties left undefined by the networks extending them,
+// such that the shared parameters of multiple networks only have to be defined once.
+// Networks are extended in order, such that a network can extend a network which extends another network.
+// An error is returned for each network that cannot be extended, sorted by network name.
+func resolveNetworkExtensions(networks map[string]*Network) []*NetworkExtensionError {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []*NetworkExtensionError
+	resolved := make(map[string]bool, len(networks))
+	var resolve func(name string, chain []string) error
+	resolve = func(name string, chain []string) error {
+		if resolved[name] {
+			return nil
+		}
+		network := networks[name]
+		if network == nil || network.Extends == "" {
+			resolved[name] = true
+			return nil
+		}
+		for _, other := range chain {
+			if other == name {
+				return fmt.Errorf("networks cannot extend each other: %s", strings.Join(append(chain, name), " extends "))
+			}
+		}
+		parent, ok := networks[network.Extends]
+		if !ok || parent == nil {
+			return fmt.Errorf("extends unknown network %s", network.Extends)
+		}
+		err := resolve(network.Extends, append(chain, name))
+		if err != nil {
+			return err
+		}
+		extendNetwork(network, parent)
+		resolved[name] = true
+		return nil
+	}
+	for _, name := range names {
+		if err := resolve(name, nil); err != nil {
+			errs = append(errs, &NetworkExtensionError{Network: name, Err: err})
+		}
+	}
+	return errs
+}
+
+// extendNetwork assigns the properties of the parent network
+// to the properties left undefined by the given network,
+// including the individual properties of its genesis and transaction pool.
+func extendNetwork(network, parent *Network) {
+	if parent.Genesis != nil {
+		if network.Genesis == nil {
+			genesis := *parent.Genesis
+			network.Genesis = &genesis
+		} else {
+			assignUndefinedFields(reflect.ValueOf(network.Genesis).Elem(), reflect.ValueOf(parent.Genesis).Elem())
+		}
+	}
+	assignUndefinedFields(reflect.ValueOf(&network.TransactionPool).Elem(), reflect.ValueOf(&parent.TransactionPool).Elem())
+	assignUndefinedFields(reflect.ValueOf(network).Elem(), reflect.ValueOf(parent).Elem())
+}
+
+// assignUndefinedFields assigns the fields of the src struct value
+// to the undefined (zero) fields of the dst struct value
+func assignUndefinedFields(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(src.Field(i))
+			}
+			continue
+		}
+		if isZero(field) {
+			field.Set(src.Field(i))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveNetworkExtensions(t *testing.T) {
+	config := BuildConfigStruct("", &ConfigGenerationOpts{PluginMintingEnabled: true})
+	networks := config.Blockchain.Networks
+	standard := networks["standard"]
+
+	// testnet only overrides the network type, genesis timestamp and block frequency of the standard network
+	networks["testnet"] = &Network{
+		Extends:        "standard",
+		NetworkType:    NetworkTypeTestnet,
+		BlockFrequency: 60,
+		Genesis: &Genesis{
+			GenesisBlockTimestamp: standard.Genesis.GenesisBlockTimestamp + 1,
+		},
+		TransactionPool: TransactionPool{
+			PoolSizeLimit: 42,
+		},
+	}
+	// devnet extends testnet, which extends standard
+	networks["devnet"] = &Network{
+		Extends:     "testnet",
+		NetworkType: NetworkTypeDevnet,
+	}
+
+	errs := resolveNetworkExtensions(networks)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for _, name := range []string{"testnet", "devnet"} {
+		network := networks[name]
+		if network.Genesis == standard.Genesis {
+			t.Errorf("%s: genesis is shared with the standard network", name)
+		}
+		if network.Genesis.GenesisBlockTimestamp != standard.Genesis.GenesisBlockTimestamp+1 {
+			t.Errorf("%s: unexpected genesis timestamp %d", name, network.Genesis.GenesisBlockTimestamp)
+		}
+		if len(network.Genesis.CoinOutputs) != len(standard.Genesis.CoinOutputs) || network.Genesis.Minting == nil {
+			t.Errorf("%s: genesis allocations aren't inherited: %v", name, network.Genesis)
+		}
+		if network.BlockFrequency != 60 {
+			t.Errorf("%s: unexpected block frequency %d", name, network.BlockFrequency)
+		}
+		if network.TransactionFeePool != standard.TransactionFeePool || len(network.BootstrapPeers) != len(standard.BootstrapPeers) {
+			t.Errorf("%s: network properties aren't inherited", name)
+		}
+		if network.TransactionPool.PoolSizeLimit != 42 || network.TransactionPool.TransactionSizeLimit != standard.TransactionPool.TransactionSizeLimit {
+			t.Errorf("%s: unexpected transaction pool: %v", name, network.TransactionPool)
+		}
+	}
+	if networks["devnet"].NetworkType != NetworkTypeDevnet {
+		t.Errorf("network type of devnet is overwritten: %d", networks["devnet"].NetworkType)
+	}
+	if err := validateConfig(config); err != nil {
+		t.Errorf("extended config is invalid: %v", err)
+	}
+
+	// unknown and cyclic extensions are reported for each network
+	networks = map[string]*Network{
+		"a": {Extends: "b"},
+		"b": {Extends: "a"},
+		"c": {Extends: "unknown"},
+		"d": {Extends: "c"},
+		"e": {},
+	}
+	errs = resolveNetworkExtensions(networks)
+	var names []string
+	for _, err := range errs {
+		names = append(names, err.Network)
+	}
+	if len(names) != 4 || names[0] != "a" || names[1] != "b" || names[2] != "c" || names[3] != "d" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestImportConfigWithNetworkExtensions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-extends")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "blockchaincfg.yaml")
+	err = ioutil.WriteFile(filePath, []byte(`blockchain:
+  name: mychain
+  repository: github.com/somebody/mychain
+  currency:
+    unit: MYC
+    precision: 9
+  ports:
+    api: 23110
+    rpc: 23112
+  networks:
+    standard:
+      networkType: 1
+      genesis:
+        coinOutputs:
+        - value: "100"
+          condition: 01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e
+        blockStakeOutputs:
+        - value: "3000"
+          condition: 01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e
+        genesisBlockTimestamp: 1524168391
+      bootstrapPeers:
+      - bootstrap1.example.com:23112
+    testnet:
+      extends: standard
+      networkType: 2
+      blockFrequency: 60
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := ImportAndValidateConfig(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testnet := config.Blockchain.Networks["testnet"]
+	if testnet.Genesis == nil || testnet.Genesis.GenesisBlockTimestamp != 1524168391 || len(testnet.BootstrapPeers) != 1 {
+		t.Errorf("testnet doesn't extend the standard network: %v", testnet)
+	}
+	if testnet.BlockFrequency != 60 {
+		t.Errorf("unexpected testnet block frequency: %d", testnet.BlockFrequency)
+	}
+
+	problems, err := ValidateConfigFile(filePath, &ValidationOpts{Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
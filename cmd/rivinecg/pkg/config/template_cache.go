@@ -0,0 +1,274 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var _commitHashRgxp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// templateCache caches the tarballs of a Github template repository by commit hash,
+// such that they can be reused across runs, even when offline.
+// Next to each tarball its SHA-256 checksum is stored, which is verified prior to reusing it.
+// The commit the branches and tags of the repository last resolved to is stored in the refs file,
+// used as a fallback when the Github API cannot be reached.
+type templateCache struct {
+	dir         string
+	owner, repo string
+}
+
+// defaultTemplateCacheDir returns the directory used to cache template tarballs if none is defined
+func defaultTemplateCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rivinecg", "templates"), nil
+}
+
+// newTemplateCache creates the cache of a Github template repository within the given cache directory,
+// the default user cache directory is used if none is given
+func newTemplateCache(cacheDir, owner, repo string) (*templateCache, error) {
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = defaultTemplateCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to define template cache directory: %v", err)
+		}
+	}
+	dir := filepath.Join(cacheDir, "github.com", owner, repo)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template cache directory: %v", err)
+	}
+	return &templateCache{dir: dir, owner: owner, repo: repo}, nil
+}
+
+func (tc *templateCache) tarballPath(commit string) string {
+	return filepath.Join(tc.dir, commit+".tar.gz")
+}
+
+func (tc *templateCache) checksumPath(commit string) string {
+	return tc.tarballPath(commit) + ".sha256"
+}
+
+func (tc *templateCache) refsPath() string {
+	return filepath.Join(tc.dir, "refs.json")
+}
+
+// verify verifies the integrity of the cached tarball of the given commit,
+// returning an error satisfying os.IsNotExist if the commit isn't cached
+func (tc *templateCache) verify(commit string) error {
+	b, err := ioutil.ReadFile(tc.checksumPath(commit))
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return errors.New("empty checksum file")
+	}
+	checksum, err := fileChecksum(tc.tarballPath(commit))
+	if err != nil {
+		return err
+	}
+	if checksum != fields[0] {
+		return fmt.Errorf("checksum mismatch: expected %s, while tarball has checksum %s", fields[0], checksum)
+	}
+	return nil
+}
+
+// remove removes the cached tarball of the given commit
+func (tc *templateCache) remove(commit string) {
+	os.Remove(tc.tarballPath(commit))
+	os.Remove(tc.checksumPath(commit))
+}
+
+// store stores the tarball read from the given reader as the tarball of the given commit,
+// the tarball is only stored if it can be unpacked
+func (tc *templateCache) store(commit string, r io.Reader) error {
+	file, err := ioutil.TempFile(tc.dir, ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), r)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	err = checkTarball(file.Name())
+	if err != nil {
+		return fmt.Errorf("invalid tarball: %v", err)
+	}
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	err = os.Rename(file.Name(), tc.tarballPath(commit))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(tc.checksumPath(commit), []byte(checksum+"  "+commit+".tar.gz\n"), 0644)
+}
+
+// readRefs reads the commits the branches and tags of the repository last resolved to
+func (tc *templateCache) readRefs() map[string]string {
+	refs := make(map[string]string)
+	b, err := ioutil.ReadFile(tc.refsPath())
+	if err == nil {
+		json.Unmarshal(b, &refs)
+	}
+	return refs
+}
+
+// storeRef stores the commit the given branch or tag resolved to
+func (tc *templateCache) storeRef(version, commit string) error {
+	if version == commit {
+		return nil
+	}
+	refs := tc.readRefs()
+	if refs[version] == commit {
+		return nil
+	}
+	refs[version] = commit
+	b, err := json.MarshalIndent(refs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(tc.refsPath(), b, 0644)
+}
+
+// fileChecksum returns the hex-encoded SHA-256 checksum of a file
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// checkTarball checks whether or not a file is a complete gzipped tarball
+func checkTarball(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+	tr := tar.NewReader(gzr)
+	for {
+		_, err = tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(ioutil.Discard, tr)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// resolveGithubCommit resolves a branch, tag or commit of a Github repository to its full commit hash
+func resolveGithubCommit(owner, repo, version string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, rootGithubAPIurl+path.Join("/repos", owner, repo, "commits", version), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3.sha")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected Github API response (%s): %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	commit := strings.TrimSpace(string(b))
+	if !_commitHashRgxp.MatchString(commit) {
+		return "", fmt.Errorf("unexpected Github API response: %q is not a commit hash", commit)
+	}
+	return commit, nil
+}
+
+// download downloads the tarball of a commit of a Github repository into the cache
+func (tc *templateCache) download(commit string) error {
+	endPoint := rootGithubAPIurl + path.Join("/repos", tc.owner, tc.repo, "tarball", commit)
+	fmt.Printf("Fetching repository: %s ...\n", endPoint)
+	resp, err := http.Get(endPoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch repository: unexpected Github API response (%s)", resp.Status)
+	}
+	return tc.store(commit, resp.Body)
+}
+
+// tarball returns the path of the cached tarball of the given version of the template repository,
+// downloading it if it isn't cached yet, isn't intact or if a refresh is requested.
+// Versions other than commit hashes are resolved using the Github API,
+// if that fails, the commit the version last resolved to is used, unless a refresh is requested.
+func (tc *templateCache) tarball(version string, refresh bool) (string, error) {
+	commit := strings.ToLower(version)
+	if !_commitHashRgxp.MatchString(commit) {
+		var err error
+		commit, err = resolveGithubCommit(tc.owner, tc.repo, version)
+		if err != nil {
+			cached, ok := tc.readRefs()[version]
+			if refresh || !ok {
+				return "", fmt.Errorf("failed to resolve version %s of template repository github.com/%s/%s: %v", version, tc.owner, tc.repo, err)
+			}
+			fmt.Printf("Failed to resolve version %s of template repository github.com/%s/%s (%v), using cached commit %s\n",
+				version, tc.owner, tc.repo, err, cached)
+			commit = cached
+		}
+	}
+
+	if !refresh {
+		err := tc.verify(commit)
+		if err == nil {
+			fmt.Printf("Using cached template: %s\n", tc.tarballPath(commit))
+			return tc.tarballPath(commit), tc.storeRef(version, commit)
+		}
+		if !os.IsNotExist(err) {
+			fmt.Printf("Discarding corrupted cached template %s: %v\n", tc.tarballPath(commit), err)
+		}
+	}
+	tc.remove(commit)
+	err := tc.download(commit)
+	if err != nil {
+		return "", err
+	}
+	return tc.tarballPath(commit), tc.storeRef(version, commit)
+}
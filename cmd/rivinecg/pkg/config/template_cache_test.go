@@ -0,0 +1,115 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFetchCachedGithubTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-template-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	content := "{}"
+	if err := tw.WriteHeader(&tar.Header{Name: "owner-repo-0123456/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "owner-repo-0123456/template.json", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarball := buf.Bytes()
+
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/master":
+			w.Write([]byte(commit))
+		case "/repos/owner/repo/tarball/" + commit:
+			downloads++
+			w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func(url string) { rootGithubAPIurl = url }(rootGithubAPIurl)
+	rootGithubAPIurl = server.URL
+
+	opts := &BlockchainGenerationOpts{TemplateCacheDir: filepath.Join(dir, "cache")}
+	fetch := func(expectedDownloads int) error {
+		destination := filepath.Join(dir, "out")
+		defer os.RemoveAll(destination)
+		templDir, err := fetchTemplate("github.com/owner/repo", "master", dir, destination, opts)
+		if err != nil {
+			return err
+		}
+		if b, err := ioutil.ReadFile(filepath.Join(templDir, "template.json")); err != nil || string(b) != content {
+			t.Errorf("unexpected fetched template: %q (%v)", b, err)
+		}
+		if downloads != expectedDownloads {
+			t.Errorf("expected %d download(s), not %d", expectedDownloads, downloads)
+		}
+		return nil
+	}
+
+	// the tarball is only downloaded once
+	for i := 0; i < 2; i++ {
+		if err := fetch(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// unless a refresh is requested
+	opts.RefreshTemplateCache = true
+	if err := fetch(2); err != nil {
+		t.Fatal(err)
+	}
+	opts.RefreshTemplateCache = false
+
+	// a corrupted tarball is downloaded again
+	tarballPath := filepath.Join(dir, "cache", "github.com", "owner", "repo", commit+".tar.gz")
+	if err := ioutil.WriteFile(tarballPath, []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fetch(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// when offline, the commit the version last resolved to is used
+	rootGithubAPIurl = "http://127.0.0.1:1"
+	if err := fetch(3); err != nil {
+		t.Fatalf("failed to fetch cached template while offline: %v", err)
+	}
+	// which fails if a refresh is requested or if the version was never resolved
+	opts.RefreshTemplateCache = true
+	if err := fetch(3); err == nil {
+		t.Error("expected refreshing the template to fail while offline")
+	}
+	opts.RefreshTemplateCache = false
+	_, err = fetchTemplate("github.com/owner/repo", "develop", dir, filepath.Join(dir, "out"), opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to resolve version develop") {
+		t.Errorf("unexpected error for an unresolved version while offline: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/otiai10/copy"
+)
+
+// localTemplatePrefix can be used to explicitly mark a template repository as a local source
+const localTemplatePrefix = "file://"
+
+// isLocalTemplateRepository returns true if the given template repository
+// refers to a local directory or (gzipped) tarball rather than a Github repository.
+// Local sources are either prefixed with file://, are (absolute or explicitly relative) paths,
+// or are tarballs (.tar.gz or .tgz files).
+func isLocalTemplateRepository(repository string) bool {
+	return strings.HasPrefix(repository, localTemplatePrefix) ||
+		strings.HasPrefix(repository, "/") ||
+		strings.HasPrefix(repository, "~/") ||
+		repository == "." || repository == ".." ||
+		strings.HasPrefix(repository, "./") || strings.HasPrefix(repository, "../") ||
+		isTarballTemplateRepository(repository)
+}
+
+// isTarballTemplateRepository returns true if the given template repository refers to a gzipped tarball
+func isTarballTemplateRepository(repository string) bool {
+	return strings.HasSuffix(repository, ".tar.gz") || strings.HasSuffix(repository, ".tgz")
+}
+
+// localTemplatePath returns the file path of a local template repository,
+// relative paths are resolved against the given base directory.
+func localTemplatePath(repository, baseDir string) (string, error) {
+	p := strings.TrimPrefix(repository, localTemplatePrefix)
+	if strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory of local template repository %s: %v", repository, err)
+		}
+		p = filepath.Join(home, p[2:])
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(baseDir, p)
+	}
+	return filepath.Clean(p), nil
+}
+
+// checkLocalTemplateRepository checks whether or not a local template repository exists
+func checkLocalTemplateRepository(repository, baseDir string) error {
+	p, err := localTemplatePath(repository, baseDir)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return fmt.Errorf("local template repository is not available: %v", err)
+	}
+	if isTarballTemplateRepository(p) {
+		if info.IsDir() {
+			return fmt.Errorf("local template repository %s is a directory and not a tarball", p)
+		}
+	} else if !info.IsDir() {
+		return fmt.Errorf("local template repository %s is not a directory", p)
+	}
+	return nil
+}
+
+// fetchTemplate fetches the template repository into a new directory within the destination directory,
+// returning the path of that directory. The version is ignored for local template repositories,
+// relative paths of which are resolved against the given base directory.
+// Github template repositories are cached, as configured by the given options.
+func fetchTemplate(repository, version, baseDir, destination string, opts *BlockchainGenerationOpts) (string, error) {
+	if isGitTemplateRepository(repository) {
+		return getGitTemplateRepo(repository, version, destination)
+	}
+	if !isLocalTemplateRepository(repository) {
+		return getTemplateRepo(repository, version, destination, opts)
+	}
+
+	err := checkLocalTemplateRepository(repository, baseDir)
+	if err != nil {
+		return "", err
+	}
+	src, err := localTemplatePath(repository, baseDir)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(destination, 0755)
+	if err != nil {
+		return "", err
+	}
+	dst, err := ioutil.TempDir(destination, ".template-")
+	if err != nil {
+		return "", err
+	}
+	if isTarballTemplateRepository(src) {
+		fmt.Printf("Unpacking local template: %s ...\n", src)
+		err = untarLocalTemplate(src, dst)
+	} else {
+		fmt.Printf("Copying local template: %s ...\n", src)
+		err = copyLocalTemplate(src, dst)
+	}
+	if err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+	return dst, nil
+}
+
+// untarLocalTemplate unpacks a gzipped tarball into the given directory,
+// the content of a single top-level directory (as found in tarballs downloaded from Github)
+// is unpacked directly into the given directory.
+func untarLocalTemplate(src, dst string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	staging := dst + "-tarball"
+	err = os.MkdirAll(staging, 0755)
+	if err != nil {
+		return err
+	}
+	err = untar(staging, file)
+	if err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	defer os.RemoveAll(staging)
+	root := staging
+	if infos, err := ioutil.ReadDir(staging); err == nil && len(infos) == 1 && infos[0].IsDir() {
+		root = filepath.Join(staging, infos[0].Name())
+	}
+	err = os.Remove(dst)
+	if err != nil {
+		return err
+	}
+	return os.Rename(root, dst)
+}
+
+// copyLocalTemplate copies a local template directory into the given directory,
+// skipping VCS metadata as well as the destination itself,
+// should it be located within the template directory.
+func copyLocalTemplate(src, dst string) error {
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	absDestination := filepath.Dir(absDst)
+	return filepath.Walk(src, func(fPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if absPath, err := filepath.Abs(fPath); err == nil && fPath != src && (absPath == absDst || absPath == absDestination) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relFilePath := strings.TrimLeft(strings.TrimPrefix(fPath, src), `\/`)
+		return copy.Copy(fPath, filepath.Join(dst, relFilePath))
+	})
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsLocalTemplateRepository(t *testing.T) {
+	testCases := []struct {
+		repository string
+		local      bool
+	}{
+		{"github.com/threefoldtech/rivine-chain-template", false},
+		{"https://github.com/threefoldtech/rivine-chain-template", false},
+		{"/home/user/rivine-chain-template", true},
+		{"./rivine-chain-template", true},
+		{"../rivine-chain-template", true},
+		{"~/rivine-chain-template", true},
+		{"file://rivine-chain-template", true},
+		{"rivine-chain-template.tar.gz", true},
+		{"templates/rivine-chain-template.tgz", true},
+	}
+	for _, testCase := range testCases {
+		if local := isLocalTemplateRepository(testCase.repository); local != testCase.local {
+			t.Errorf("%s: expected local to be %v, not %v", testCase.repository, testCase.local, local)
+		}
+	}
+}
+
+func TestFetchLocalTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"template.json":                 "{}",
+		"cmd/main.go.template":          "package main",
+		"pkg/config/config.go.template": "package config",
+	}
+	templateDir := filepath.Join(dir, "template")
+	for name, content := range files {
+		fPath := filepath.Join(templateDir, name)
+		if err := os.MkdirAll(filepath.Dir(fPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// VCS metadata is never copied
+	if err := os.MkdirAll(filepath.Join(templateDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(templateDir, ".git", "HEAD"), []byte("master"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// create a Github-like tarball of the template, which has a single top-level directory
+	tarballPath := filepath.Join(dir, "template.tar.gz")
+	tarball, err := os.Create(tarballPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(tarball)
+	tw := tar.NewWriter(gzw)
+	for _, name := range []string{"owner-repo-abcdef/", "owner-repo-abcdef/cmd/", "owner-repo-abcdef/pkg/", "owner-repo-abcdef/pkg/config/"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: "owner-repo-abcdef/" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tarball.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, repository := range []string{"./template", templateDir, "file://template", "template.tar.gz"} {
+		// generate into the template directory itself, as is common while developing templates
+		destination := filepath.Join(templateDir, "out")
+		templDir, err := fetchTemplate(repository, "master", dir, destination, nil)
+		if err != nil {
+			t.Errorf("%s: failed to fetch template: %v", repository, err)
+			continue
+		}
+		for name, content := range files {
+			b, err := ioutil.ReadFile(filepath.Join(templDir, name))
+			if err != nil {
+				t.Errorf("%s: failed to read fetched file %s: %v", repository, name, err)
+			} else if string(b) != content {
+				t.Errorf("%s: unexpected content of fetched file %s: %q", repository, name, b)
+			}
+		}
+		infos, err := ioutil.ReadDir(templDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 3 {
+			t.Errorf("%s: expected 3 top-level entries to be fetched, not %d", repository, len(infos))
+		}
+		// the destination only contains the fetched template
+		infos, err = ioutil.ReadDir(destination)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 1 {
+			t.Errorf("%s: expected the destination to only contain the template, not %d entries", repository, len(infos))
+		}
+		if err := os.RemoveAll(destination); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, repository := range []string{"./missing", "missing.tar.gz", "./template/template.json"} {
+		if _, err := fetchTemplate(repository, "master", dir, filepath.Join(dir, "out"), nil); err == nil {
+			t.Errorf("%s: expected fetching to fail", repository)
+		}
+	}
+}
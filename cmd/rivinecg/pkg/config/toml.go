@@ -0,0 +1,404 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// TOML-encoded configs are converted from and to their YAML encoding,
+// such that the (custom) YAML encoding of all config types is used for TOML as well.
+
+// encodeTOMLConfig encodes the given config as TOML,
+// properties are encoded in the same order as they are encoded in YAML
+func encodeTOMLConfig(w io.Writer, config *Config) error {
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	var tree yaml.MapSlice
+	err = yaml.Unmarshal(b, &tree)
+	if err != nil {
+		return err
+	}
+	enc := &tomlEncoder{}
+	enc.table(nil, tree, false)
+	if enc.err != nil {
+		return enc.err
+	}
+	_, err = w.Write(enc.buf.Bytes())
+	return err
+}
+
+// yamlErrorLinePrefixRegexp matches the line prefix of YAML decoding errors
+var yamlErrorLinePrefixRegexp = regexp.MustCompile(`line \d+: `)
+
+// decodeTOMLConfig decodes a TOML-encoded config
+func decodeTOMLConfig(r io.Reader) (*Config, error) {
+	var tree map[string]interface{}
+	_, err := toml.DecodeReader(r, &tree)
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	err = yaml.Unmarshal(b, &config)
+	if err != nil {
+		if typeErr, ok := err.(*yaml.TypeError); ok {
+			// the lines of the intermediate YAML encoding are meaningless to the user
+			msgs := make([]string, 0, len(typeErr.Errors))
+			for _, msg := range typeErr.Errors {
+				msgs = append(msgs, yamlErrorLinePrefixRegexp.ReplaceAllString(msg, ""))
+			}
+			return &config, errors.New("toml: " + strings.Join(msgs, "; "))
+		}
+		return &config, err
+	}
+	return &config, nil
+}
+
+// tomlEncoder encodes a (YAML-decoded) tree as TOML
+type tomlEncoder struct {
+	buf bytes.Buffer
+	err error
+}
+
+// tomlBareKeyRegexp matches the keys which do not have to be quoted
+var tomlBareKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if tomlBareKeyRegexp.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString quotes a string as a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func tomlTablePath(path []string) string {
+	keys := make([]string, 0, len(path))
+	for _, key := range path {
+		keys = append(keys, tomlKey(key))
+	}
+	return strings.Join(keys, ".")
+}
+
+// isTOMLTableArray returns true if the value is a non-empty list of tables
+func isTOMLTableArray(v interface{}) bool {
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if _, ok := item.(yaml.MapSlice); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// table encodes a table (or an item of an array of tables), defining all its
+// key-value pairs prior to its sub-tables, as TOML requires.
+// The header of a table which only defines sub-tables is omitted.
+func (enc *tomlEncoder) table(path []string, m yaml.MapSlice, arrayItem bool) {
+	var simple, tables []yaml.MapItem
+	for _, item := range m {
+		if item.Value == nil {
+			continue // TOML has no null values
+		}
+		switch item.Value.(type) {
+		case yaml.MapSlice:
+			tables = append(tables, item)
+		default:
+			if isTOMLTableArray(item.Value) {
+				tables = append(tables, item)
+			} else {
+				simple = append(simple, item)
+			}
+		}
+	}
+	if len(path) > 0 && (arrayItem || len(simple) > 0 || len(tables) == 0) {
+		if enc.buf.Len() > 0 {
+			enc.buf.WriteByte('\n')
+		}
+		if arrayItem {
+			fmt.Fprintf(&enc.buf, "[[%s]]\n", tomlTablePath(path))
+		} else {
+			fmt.Fprintf(&enc.buf, "[%s]\n", tomlTablePath(path))
+		}
+	}
+	for _, item := range simple {
+		key := fmt.Sprint(item.Key)
+		fmt.Fprintf(&enc.buf, "%s = %s\n", tomlKey(key), enc.value(item.Value))
+	}
+	for _, item := range tables {
+		childPath := append(append([]string(nil), path...), fmt.Sprint(item.Key))
+		if sub, ok := item.Value.(yaml.MapSlice); ok {
+			enc.table(childPath, sub, false)
+			continue
+		}
+		for _, sub := range item.Value.([]interface{}) {
+			enc.table(childPath, sub.(yaml.MapSlice), true)
+		}
+	}
+}
+
+// value encodes a single (inline) value
+func (enc *tomlEncoder) value(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return tomlString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEIN") {
+			s += ".0"
+		}
+		return s
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if item == nil {
+				continue
+			}
+			items = append(items, enc.value(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case yaml.MapSlice:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if item.Value == nil {
+				continue
+			}
+			items = append(items, tomlKey(fmt.Sprint(item.Key))+" = "+enc.value(item.Value))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		if enc.err == nil {
+			enc.err = fmt.Errorf("cannot encode value %v of type %T as TOML", v, v)
+		}
+		return ""
+	}
+}
+
+// splitTOMLKey splits a (dotted) TOML key into its (unquoted) parts
+func splitTOMLKey(key string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+		quote   rune
+	)
+	for _, r := range key {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		case r == ' ' || r == '\t':
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(parts, strings.TrimSpace(current.String()))
+}
+
+// scanTOML calls fn for all runes of a line which are not part of a string or comment,
+// until fn returns false
+func scanTOML(line string, fn func(idx int, r rune) bool) {
+	var (
+		quote   rune
+		escaped bool
+	)
+	for idx, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return
+		default:
+			if !fn(idx, r) {
+				return
+			}
+		}
+	}
+}
+
+// tomlKeyValueSplit returns the index of the = separating the key and value of a line,
+// -1 if the line doesn't define a key-value pair
+func tomlKeyValueSplit(line string) int {
+	split := -1
+	scanTOML(line, func(idx int, r rune) bool {
+		if r == '=' {
+			split = idx
+			return false
+		}
+		return true
+	})
+	return split
+}
+
+// tomlBracketDepth returns the change in (array) bracket depth of a value
+func tomlBracketDepth(value string) int {
+	var depth int
+	scanTOML(value, func(_ int, r rune) bool {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		return true
+	})
+	return depth
+}
+
+// locateTOMLLines locates the lines of all properties of a TOML-encoded config
+func locateTOMLLines(b []byte) lineLocator {
+	lines := lineLocator{}
+	// tableArrays counts the items of the arrays of tables defined so far
+	tableArrays := map[string]int{}
+	resolve := func(keys []string) string {
+		var p string
+		for _, key := range keys {
+			p = childPath(p, key)
+			if n, ok := tableArrays[p]; ok {
+				p = itemPath(p, n-1)
+			}
+		}
+		return p
+	}
+	// the parent tables of a table are located at the first table defining them
+	definePrefixes := func(keys []string, line int) {
+		for idx := range keys[:len(keys)-1] {
+			p := resolve(keys[:idx+1])
+			if _, ok := lines[p]; !ok {
+				lines[p] = line
+			}
+		}
+	}
+	var (
+		table     string
+		depth     int
+		multiline string
+	)
+	for n, line := range strings.Split(string(b), "\n") {
+		content := strings.TrimSpace(line)
+		if multiline != "" {
+			if strings.Contains(content, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+		if depth > 0 {
+			depth += tomlBracketDepth(content)
+			continue
+		}
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		if strings.HasPrefix(content, "[[") {
+			end := strings.Index(content, "]]")
+			if end < 0 {
+				continue
+			}
+			keys := splitTOMLKey(content[2:end])
+			definePrefixes(keys, n+1)
+			p := childPath(resolve(keys[:len(keys)-1]), keys[len(keys)-1])
+			if _, ok := lines[p]; !ok {
+				lines[p] = n + 1
+			}
+			table = itemPath(p, tableArrays[p])
+			tableArrays[p]++
+			lines[table] = n + 1
+			continue
+		}
+		if strings.HasPrefix(content, "[") {
+			end := strings.Index(content, "]")
+			if end < 0 {
+				continue
+			}
+			keys := splitTOMLKey(content[1:end])
+			definePrefixes(keys, n+1)
+			table = resolve(keys)
+			lines[table] = n + 1
+			continue
+		}
+		idx := tomlKeyValueSplit(content)
+		if idx < 0 {
+			continue
+		}
+		p := table
+		for _, key := range splitTOMLKey(content[:idx]) {
+			p = childPath(p, key)
+		}
+		lines[p] = n + 1
+		value := strings.TrimSpace(content[idx+1:])
+		for _, delim := range []string{`"""`, `'''`} {
+			if strings.HasPrefix(value, delim) && !strings.Contains(value[len(delim):], delim) {
+				multiline = delim
+			}
+		}
+		if multiline == "" {
+			depth = tomlBracketDepth(value)
+		}
+	}
+	return lines
+}
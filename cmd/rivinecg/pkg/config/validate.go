@@ -0,0 +1,481 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// minGenesisTimestamp is the smallest genesis timestamp accepted by types.ChainConstants.Validate,
+// the timestamp of the bitcoin genesis block
+const minGenesisTimestamp = 1231006505
+
+// Problem is a single problem found while validating a config file.
+type Problem struct {
+	// Path of the property the problem applies to,
+	// e.g. blockchain.networks.devnet.genesis.coinOutputs[0].value
+	Path string
+	// Line on which the property is defined, 0 if unknown
+	Line    int
+	Message string
+}
+
+// String implements fmt.Stringer.String
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// ValidationOpts are the options of ValidateConfigFile.
+type ValidationOpts struct {
+	// Offline disables the validation which requires network access,
+	// such as checking whether or not the template repository is reachable
+	Offline bool
+}
+
+// problems collects the problems found while validating a config
+type problems []Problem
+
+// add adds a problem for the property of the given path
+func (ps *problems) add(path, format string, args ...interface{}) {
+	*ps = append(*ps, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// CheckTemplateRepository checks whether or not the given version of the given remote template repository exists,
+// it is a variable such that it can be overwritten in tests.
+var CheckTemplateRepository = func(repository, version string) error {
+	if isGitTemplateRepository(repository) {
+		return checkGitTemplateRepository(repository, version)
+	}
+	owner, repo, err := githubOwnerAndRepoFromString(repository)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(rootGithubAPIurl + path.Join("/repos", owner, repo, "commits", version))
+	if err != nil {
+		return fmt.Errorf("repository is unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		return fmt.Errorf("version %s of repository %s does not exist", version, repository)
+	default:
+		return fmt.Errorf("repository is unreachable: %s", resp.Status)
+	}
+}
+
+// ValidateConfigFile validates a config file, returning every problem found,
+// sorted by the line on which it occurs. Contrary to ImportAndValidateConfig,
+// which fails on the first problem found, all rules are checked:
+// those of the config file format, those of the generated code
+// (e.g. names and transaction versions) and those of types.ChainConstants.Validate.
+// An error is only returned in case the file can't be read.
+func ValidateConfigFile(configFilePath string, opts *ValidationOpts) ([]Problem, error) {
+	if opts == nil {
+		opts = &ValidationOpts{}
+	}
+	b, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	typ := path.Ext(configFilePath)
+	lines := newLineLocator(typ, b)
+
+	var ps problems
+	config, err := decodeConfig(typ, bytes.NewReader(b))
+	if err != nil {
+		ps = append(ps, decodeProblem(err, b))
+	} else {
+		ps = validateConfigDeep(config, path.Dir(configFilePath), opts)
+	}
+	for idx := range ps {
+		if ps[idx].Line == 0 {
+			ps[idx].Line = lines.line(ps[idx].Path)
+		}
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		return ps[i].Line < ps[j].Line
+	})
+	return ps, nil
+}
+
+// decodeErrorLineRegexp matches the line number of a YAML or TOML decoding error
+var decodeErrorLineRegexp = regexp.MustCompile(`line (\d+)[: ]`)
+
+// decodeProblem converts a decoding error to a problem, locating it if possible
+func decodeProblem(err error, b []byte) Problem {
+	if err == ErrUnsupportedFileType {
+		return Problem{Message: "unsupported file type, has to be .yaml, .toml or .json"}
+	}
+	problem := Problem{Message: err.Error()}
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		problem.Line = lineAt(b, e.Offset)
+	case *json.UnmarshalTypeError:
+		problem.Line = lineAt(b, e.Offset)
+		problem.Path = e.Field
+	default:
+		if m := decodeErrorLineRegexp.FindStringSubmatch(err.Error()); m != nil {
+			problem.Line, _ = strconv.Atoi(m[1])
+		}
+	}
+	return problem
+}
+
+// deepValidate validates the format of a config,
+// reporting the properties using the names used in config files
+var deepValidate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}()
+
+// validateConfigDeep validates a decoded config, returning all problems found,
+// local template repositories are resolved relative to the given base directory
+func validateConfigDeep(config *Config, baseDir string, opts *ValidationOpts) problems {
+	var ps problems
+	if config.Blockchain != nil {
+		for _, err := range resolveNetworkExtensions(config.Blockchain.Networks) {
+			ps.add(childPath(childPath("blockchain.networks", err.Network), "extends"), "%v", err.Err)
+		}
+	}
+	if err := deepValidate.Struct(config); err != nil {
+		if fieldErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fe := range fieldErrors {
+				ps.add(namespaceToPath(fe.Namespace()), "%s", fieldErrorMessage(fe))
+			}
+		} else {
+			ps.add("", "%v", err)
+		}
+	}
+	bc := config.Blockchain
+	if bc == nil {
+		return ps // reported by the format validation
+	}
+
+	// names of the blockchain and its binaries, used for the generated code
+	if bc.Name != "" && !blockchainNameRegexp.MatchString(bc.Name) {
+		ps.add("blockchain.name", "%q has to start with a letter, and can only contain lowercase letters and digits", bc.Name)
+	}
+	if bc.Binaries != nil {
+		for _, binary := range []struct{ path, name string }{
+			{"blockchain.binaries.client", bc.Binaries.Client},
+			{"blockchain.binaries.daemon", bc.Binaries.Daemon},
+		} {
+			if binary.name != "" && !binaryNameRegexp.MatchString(binary.name) {
+				ps.add(binary.path, "%q can only contain letters, digits, underscores and dashes", binary.name)
+			}
+		}
+		client, daemon := bc.Binaries.Client, bc.Binaries.Daemon
+		if client == "" {
+			client = bc.Name + "c"
+		}
+		if daemon == "" {
+			daemon = bc.Name + "d"
+		}
+		if client == daemon {
+			ps.add("blockchain.binaries", "the client and daemon binaries cannot have the same name %q", client)
+		}
+	}
+	if bc.Currency != nil && bc.Currency.Precision > maxCurrencyPrecision {
+		ps.add("blockchain.currency.precision", "%d is too large, the maximum supported precision is %d", bc.Currency.Precision, maxCurrencyPrecision)
+	}
+	validateTransactionVersionsDeep(bc.Transactions, &ps)
+
+	// networks, validated in a stable order
+	names := make([]string, 0, len(bc.Networks))
+	for name := range bc.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if network := bc.Networks[name]; network != nil {
+			validateNetworkDeep(childPath("blockchain.networks", name), network, bc, &ps)
+		}
+	}
+	validatePluginsDeep(bc, names, &ps)
+
+	templ := assignDefaultTemplateValues(config.Template)
+	switch {
+	case isLocalTemplateRepository(templ.Repository):
+		if err := checkLocalTemplateRepository(templ.Repository, baseDir); err != nil {
+			ps.add("template.repository", "%v", err)
+		}
+	case opts.Offline:
+		var err error
+		if isGitTemplateRepository(templ.Repository) {
+			_, err = gitCloneURL(templ.Repository)
+		} else {
+			_, _, err = githubOwnerAndRepoFromString(templ.Repository)
+		}
+		if err != nil {
+			ps.add("template.repository", "%v", err)
+		}
+	default:
+		if err := CheckTemplateRepository(templ.Repository, templ.Version); err != nil {
+			ps.add("template.repository", "%v", err)
+		}
+	}
+	return ps
+}
+
+// namespaceToPath converts the namespace of a validation error
+// (e.g. Config.blockchain.networks[devnet].genesis) to a property path
+func namespaceToPath(namespace string) string {
+	p := strings.TrimPrefix(namespace, "Config.")
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(p, '[')
+		if start < 0 {
+			sb.WriteString(p)
+			return sb.String()
+		}
+		end := strings.IndexByte(p[start:], ']')
+		if end < 0 {
+			sb.WriteString(p)
+			return sb.String()
+		}
+		key := p[start+1 : start+end]
+		sb.WriteString(p[:start])
+		if _, err := strconv.Atoi(key); err == nil {
+			sb.WriteString("[" + key + "]")
+		} else {
+			sb.WriteString("." + key)
+		}
+		p = p[start+end+1:]
+	}
+}
+
+// fieldErrorMessage returns a human-readable message for a validation error
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return fmt.Sprintf("has to define more than %s element(s)", fe.Param())
+	case "nefield":
+		return fmt.Sprintf("has to differ from %s", strings.ToLower(fe.Param()))
+	default:
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}
+
+// validateTransactionVersionsDeep validates that all configured transaction versions are unique,
+// and that the plugin transactions do not use the versions of the standard transactions
+func validateTransactionVersionsDeep(txs *Transactions, ps *problems) {
+	if txs == nil {
+		return
+	}
+	type version struct {
+		path    string
+		version uint64
+	}
+	var versions []version
+	if txs.Minting != nil {
+		versions = append(versions,
+			version{"blockchain.transactions.minting.conditionUpdate", txs.Minting.ConditionUpdate.Version},
+			version{"blockchain.transactions.minting.coinCreation", txs.Minting.CoinCreation.Version})
+		if txs.Minting.CoinDestruction != nil {
+			versions = append(versions, version{"blockchain.transactions.minting.coinDestruction", txs.Minting.CoinDestruction.Version})
+		}
+	}
+	if txs.Authcoin != nil {
+		versions = append(versions,
+			version{"blockchain.transactions.authcoin.addressUpdate", txs.Authcoin.AddressUpdate.Version},
+			version{"blockchain.transactions.authcoin.conditionUpdate", txs.Authcoin.ConditionUpdate.Version})
+	}
+	if txs.Default != nil && txs.Default.Version > uint64(types.TransactionVersionTwo) {
+		ps.add("blockchain.transactions.default.version", "%d is not a standard transaction version", txs.Default.Version)
+	}
+	seen := make(map[uint64]string)
+	for _, v := range versions {
+		if v.version == 0 {
+			continue // reported by the format validation
+		}
+		if v.version > 255 {
+			ps.add(v.path, "transaction version %d does not fit in a single byte", v.version)
+		} else if v.version <= uint64(types.TransactionVersionTwo) {
+			ps.add(v.path, "transaction version %d is reserved for standard transactions", v.version)
+		}
+		if other, ok := seen[v.version]; ok {
+			ps.add(v.path, "transaction version %d is already used by %s", v.version, other)
+		}
+		seen[v.version] = v.path
+	}
+}
+
+// validateNetworkDeep validates a single network
+func validateNetworkDeep(p string, network *Network, bc *Blockchain, ps *problems) {
+	var format *types.CurrencyFormat
+	if bc.Currency != nil && bc.Currency.Precision <= maxCurrencyPrecision {
+		f := types.NewCurrencyFormat(bc.Currency.Units(), "")
+		format = &f
+	}
+	validateCoins := func(p, value string, required bool) {
+		if value == "" {
+			if required {
+				ps.add(p, "is required")
+			}
+			return
+		}
+		if format == nil {
+			return // invalid precision is reported already
+		}
+		c, err := format.ParseCoins(value)
+		if err != nil {
+			ps.add(p, "invalid currency value %q: %v", value, err)
+		} else if required && c.IsZero() {
+			ps.add(p, "has to be greater than zero")
+		}
+	}
+	validateCoins(childPath(p, "blockCreatorFee"), network.BlockCreatorFee, false)
+	validateCoins(childPath(p, "minimumTransactionFee"), network.MinimumTransactionFee, false)
+	if network.TransactionFeePool != "" {
+		var uh types.UnlockHash
+		if err := uh.LoadString(network.TransactionFeePool); err != nil {
+			ps.add(childPath(p, "transactionFeePool"), "invalid address: %v", err)
+		}
+	}
+
+	if genesis := network.Genesis; genesis != nil {
+		gp := childPath(p, "genesis")
+		if genesis.GenesisBlockTimestamp != 0 && genesis.GenesisBlockTimestamp < minGenesisTimestamp {
+			ps.add(childPath(gp, "genesisBlockTimestamp"), "%d is too far in the past, has to be at least %d",
+				genesis.GenesisBlockTimestamp, minGenesisTimestamp)
+		}
+		if genesis.CoinOutputs != nil && len(genesis.CoinOutputs) == 0 {
+			ps.add(childPath(gp, "coinOutputs"), "at least one genesis coin output is required")
+		}
+		for idx, output := range genesis.CoinOutputs {
+			op := itemPath(childPath(gp, "coinOutputs"), idx)
+			validateCoins(childPath(op, "value"), output.Value, true)
+			validateConditionDeep(childPath(op, "condition"), output.Condition, ps)
+		}
+		if genesis.BlockStakeOutputs != nil && len(genesis.BlockStakeOutputs) == 0 {
+			ps.add(childPath(gp, "blockStakeOutputs"), "at least one genesis block stake output is required")
+		}
+		for idx, output := range genesis.BlockStakeOutputs {
+			op := itemPath(childPath(gp, "blockStakeOutputs"), idx)
+			if output.Value != "" {
+				if x, err := strconv.ParseUint(output.Value, 10, 64); err != nil || x == 0 {
+					ps.add(childPath(op, "value"), "%q has to be an unsigned integer greater than zero", output.Value)
+				}
+			}
+			validateConditionDeep(childPath(op, "condition"), output.Condition, ps)
+		}
+		if genesis.Minting != nil {
+			validateConditionDeep(childPath(gp, "minting"), *genesis.Minting, ps)
+		}
+		if genesis.Authcoin != nil {
+			validateConditionDeep(childPath(gp, "authcoin"), *genesis.Authcoin, ps)
+		}
+	}
+
+	if network.NetworkType != 0 && (network.NetworkType < NetworkTypeStandard || network.NetworkType > NetworkTypeDevnet) {
+		ps.add(childPath(p, "networkType"), "%d is not a known network type (1=standard, 2=testnet, 3=devnet)", network.NetworkType)
+	}
+	if network.MaxAdjustmentUp.Denominator < 0 || network.MaxAdjustmentUp.Numerator < 0 {
+		ps.add(childPath(p, "maxAdjustmentUp"), "cannot be negative")
+	}
+	if network.MaxAdjustmentDown.Denominator < 0 || network.MaxAdjustmentDown.Numerator < 0 {
+		ps.add(childPath(p, "maxAdjustmentDown"), "cannot be negative")
+	}
+	for idx, peer := range network.BootstrapPeers {
+		if peer == nil {
+			continue
+		}
+		var err error
+		// allow loopback addresses in devnet network
+		if peer.IsLoopback() && network.NetworkType == NetworkTypeDevnet {
+			err = peer.IsStdValid()
+		} else {
+			err = peer.IsValid()
+		}
+		if err != nil {
+			ps.add(itemPath(childPath(p, "bootstrapPeers"), idx), "invalid peer %s: %v", peer.NetAddress, err)
+		}
+	}
+}
+
+// validateConditionDeep validates the sanity of a condition of the genesis block
+func validateConditionDeep(p string, condition Condition, ps *problems) {
+	switch c := condition.Condition.(type) {
+	case nil, *types.NilCondition:
+		ps.add(p, "is required")
+	case *types.UnlockHashCondition:
+		if c.TargetUnlockHash.Type != types.UnlockTypePubKey {
+			ps.add(p, "address %s is not a wallet address", c.TargetUnlockHash.String())
+		}
+	case *types.MultiSignatureCondition:
+		for _, uh := range c.UnlockHashes {
+			if uh.Type != types.UnlockTypePubKey {
+				ps.add(p, "address %s is not a wallet address", uh.String())
+			}
+		}
+		if c.MinimumSignatureCount == 0 || c.MinimumSignatureCount > uint64(len(c.UnlockHashes)) {
+			ps.add(p, "%d signatures required can never be fulfilled by %d addresses",
+				c.MinimumSignatureCount, len(c.UnlockHashes))
+		}
+	}
+}
+
+// validatePluginsDeep validates that the plugin conditions of all networks
+// are consistent with the configured plugin transactions
+func validatePluginsDeep(bc *Blockchain, names []string, ps *problems) {
+	for _, plugin := range []struct {
+		name, property string
+		configured     bool
+		condition      func(*Genesis) *Condition
+	}{
+		{"minting", "minting", bc.Transactions != nil && bc.Transactions.Minting != nil, func(g *Genesis) *Condition { return g.Minting }},
+		{"authcoin", "authcoin", bc.Transactions != nil && bc.Transactions.Authcoin != nil, func(g *Genesis) *Condition { return g.Authcoin }},
+	} {
+		var defined, missing []string
+		for _, name := range names {
+			network := bc.Networks[name]
+			if network == nil || network.Genesis == nil {
+				continue // reported by the format validation
+			}
+			if plugin.condition(network.Genesis) == nil {
+				missing = append(missing, name)
+			} else {
+				defined = append(defined, name)
+			}
+		}
+		switch {
+		case plugin.configured:
+			for _, name := range missing {
+				ps.add(childPath(childPath("blockchain.networks", name), "genesis"),
+					"the %s transactions are configured, but the genesis %s condition is missing", plugin.name, plugin.property)
+			}
+		case len(defined) > 0:
+			ps.add(childPath("blockchain.transactions", plugin.property),
+				"networks %s define the genesis %s condition, but the %s transactions aren't configured",
+				strings.Join(defined, ", "), plugin.property, plugin.name)
+		}
+	}
+}
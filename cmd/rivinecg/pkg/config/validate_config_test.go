@@ -316,3 +316,26 @@ func TestValidateConfigWithFaultyNetworkBootstrapPeersShouldThrowError(t *testin
 		t.Errorf("%s", err.Error())
 	}
 }
+
+func TestValidateConfigWithCurrencyPrecision(t *testing.T) {
+	conf := BuildConfigStruct("", nil)
+	conf.Blockchain.Currency.Precision = 18
+	conf.Blockchain.Networks["testnet"].Genesis.CoinOutputs[0].Value = "0.000000000000000001"
+	if err := validateConfig(conf); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+
+	conf.Blockchain.Currency.Precision = 6
+	if err := validateConfig(conf); err == nil {
+		t.Error("expected a too precise genesis coin output value to be invalid")
+	}
+	conf.Blockchain.Networks["testnet"].Genesis.CoinOutputs[0].Value = "500000.5"
+	if err := validateConfig(conf); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+
+	conf.Blockchain.Currency.Precision = maxCurrencyPrecision + 1
+	if err := validateConfig(conf); err == nil {
+		t.Error("expected a too large currency precision to be invalid")
+	}
+}
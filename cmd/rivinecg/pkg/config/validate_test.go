@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the template repository is never reached from within tests
+	defer func(check func(string, string) error) { CheckTemplateRepository = check }(CheckTemplateRepository)
+	CheckTemplateRepository = func(repository, version string) error {
+		return errors.New("unreachable")
+	}
+
+	for _, typ := range []string{".yaml", ".json"} {
+		// a valid config has no problems
+		filePath := path.Join(dir, "valid"+typ)
+		err = WriteConfigFile(filePath, BuildConfigStruct(filePath, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems, err := ValidateConfigFile(filePath, &ValidationOpts{Offline: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(problems) != 0 {
+			t.Errorf("%s: unexpected problems: %v", typ, problems)
+		}
+		// unless the template repository is checked
+		problems, err = ValidateConfigFile(filePath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(problems) != 1 || problems[0].Path != "template.repository" || problems[0].Line == 0 {
+			t.Errorf("%s: unexpected problems: %v", typ, problems)
+		}
+
+		// all problems of an invalid config are reported
+		filePath = path.Join(dir, "invalid"+typ)
+		config := BuildConfigStruct(filePath, &ConfigGenerationOpts{PluginMintingEnabled: true})
+		config.Blockchain.Name = "My-Chain"
+		config.Blockchain.Currency.Precision = 30
+		config.Blockchain.Transactions.Minting.CoinCreation.Version = 128
+		devnet := config.Blockchain.Networks["devnet"]
+		devnet.Genesis.GenesisBlockTimestamp = 42
+		devnet.Genesis.BlockStakeOutputs[0].Value = "0"
+		devnet.Genesis.Minting = nil
+		err = WriteConfigFile(filePath, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems, err = ValidateConfigFile(filePath, &ValidationOpts{Offline: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(string(b), "\n")
+		expected := map[string]string{
+			"blockchain.name":                                               "name",
+			"blockchain.currency.precision":                                 "precision",
+			"blockchain.transactions.minting.coinCreation":                  "coinCreation",
+			"blockchain.networks.devnet.genesis.genesisBlockTimestamp":      "genesisBlockTimestamp",
+			"blockchain.networks.devnet.genesis.blockStakeOutputs[0].value": "value",
+			"blockchain.networks.devnet.genesis":                            "genesis",
+		}
+		if len(problems) != len(expected) {
+			t.Errorf("%s: expected %d problems, found: %v", typ, len(expected), problems)
+		}
+		for _, problem := range problems {
+			key, ok := expected[problem.Path]
+			if !ok {
+				t.Errorf("%s: unexpected problem: %v", typ, problem)
+				continue
+			}
+			if problem.Line < 1 || problem.Line > len(lines) {
+				t.Errorf("%s: problem %v has invalid line %d", typ, problem, problem.Line)
+				continue
+			}
+			if !strings.Contains(lines[problem.Line-1], key) {
+				t.Errorf("%s: problem %v is located at line %d: %q", typ, problem, problem.Line, lines[problem.Line-1])
+			}
+		}
+		for idx := 1; idx < len(problems); idx++ {
+			if problems[idx].Line < problems[idx-1].Line {
+				t.Errorf("%s: problems aren't sorted by line: %v", typ, problems)
+			}
+		}
+	}
+}
+
+func TestValidateConfigFileDecodeError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinecg-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		name, content string
+		line          int
+	}{
+		{"a.yaml", "blockchain:\n  name: foo\n  currency: [\n", 3},
+		{"b.yaml", "blockchain:\n  name: foo\n  ports: abc\n", 3},
+		{"c.json", "{\n  \"blockchain\": {\n    \"name\": 42\n  }\n}\n", 3},
+		{"d.json", "{\n  \"blockchain\": {\n    \"name\": \"foo\",,\n  }\n}\n", 3},
+	}
+	for _, testCase := range testCases {
+		filePath := path.Join(dir, testCase.name)
+		err = ioutil.WriteFile(filePath, []byte(testCase.content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems, err := ValidateConfigFile(filePath, &ValidationOpts{Offline: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(problems) != 1 || problems[0].Line != testCase.line {
+			t.Errorf("%s: unexpected problems: %v", testCase.name, problems)
+		}
+	}
+
+	if _, err := ValidateConfigFile(path.Join(dir, "missing.yaml"), nil); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+}
+
+func TestLocateYAMLLines(t *testing.T) {
+	lines := locateYAMLLines([]byte(`# comment
+blockchain:
+  name: foo
+  networks:
+    devnet:
+      genesis:
+        coinOutputs:
+        - value: "1"
+          condition: abc
+        - value: "2"
+          condition:
+            addresses:
+            - abc
+`))
+	for p, line := range map[string]int{
+		"blockchain":      2,
+		"blockchain.name": 3,
+		"blockchain.networks.devnet.genesis.coinOutputs[0]":                        8,
+		"blockchain.networks.devnet.genesis.coinOutputs[0].value":                  8,
+		"blockchain.networks.devnet.genesis.coinOutputs[1].condition":              11,
+		"blockchain.networks.devnet.genesis.coinOutputs[1].condition.addresses[0]": 13,
+		// undefined properties are located at their closest defined parent
+		"blockchain.networks.devnet.genesis.minting": 6,
+		"template": 0,
+	} {
+		if actual := lines.line(p); actual != line {
+			t.Errorf("%s: expected line %d, not %d", p, line, actual)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// wizardNetworkTypes are the network types which can be configured using the wizard, indexed by name
+var wizardNetworkTypes = map[string]NetworkType{
+	"standard": NetworkTypeStandard,
+	"testnet":  NetworkTypeTestnet,
+	"devnet":   NetworkTypeDevnet,
+}
+
+var (
+	// blockchainNameRegexp matches the names allowed for a blockchain,
+	// as the name is used for packages and binaries of the generated code
+	blockchainNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	// binaryNameRegexp matches the names allowed for the generated binaries
+	binaryNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// errWizardAborted is returned in case the input ends before the wizard is completed
+var errWizardAborted = errors.New("configuration wizard aborted: no more input")
+
+// wizard prompts the user for config values, one question at a time,
+// asking a question again until a valid answer is given.
+type wizard struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// section prints the title of a new section of questions
+func (wz *wizard) section(title string) {
+	fmt.Fprintf(wz.w, "\n== %s ==\n", title)
+}
+
+// ask prints the question with its default value, and returns the (trimmed) answer,
+// or the default value in case no answer is given. The answer is validated
+// using the (optional) validate function, asking the question again if it is invalid.
+func (wz *wizard) ask(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(wz.w, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(wz.w, "%s: ", question)
+		}
+		line, err := wz.r.ReadString('\n')
+		if err != nil {
+			if err != io.EOF {
+				return "", err
+			}
+			if line == "" {
+				fmt.Fprintln(wz.w)
+				return "", errWizardAborted
+			}
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(wz.w, "  invalid answer: %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// askUint asks a question for an unsigned integer within the given (inclusive) range
+func (wz *wizard) askUint(question string, def, min, max uint64) (uint64, error) {
+	answer, err := wz.ask(question, strconv.FormatUint(def, 10), func(str string) error {
+		x, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return errors.New("not an unsigned integer")
+		}
+		if x < min || x > max {
+			return fmt.Errorf("has to be in the range [%d, %d]", min, max)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(answer, 10, 64)
+}
+
+// askBool asks a yes/no question
+func (wz *wizard) askBool(question string, def bool) (bool, error) {
+	defStr := "no"
+	if def {
+		defStr = "yes"
+	}
+	answer, err := wz.ask(question+" (yes/no)", defStr, func(str string) error {
+		switch strings.ToLower(str) {
+		case "y", "yes", "n", "no":
+			return nil
+		default:
+			return errors.New("answer yes or no")
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// RunConfigWizard walks the user through the configuration of a blockchain,
+// reading the answers from the given reader and writing the questions to the given writer.
+// The values of the default config (see BuildConfigStruct) are proposed where sensible,
+// and each answer is validated before the next question is asked.
+// The returned config is validated the same way as a config file is validated.
+func RunConfigWizard(r io.Reader, w io.Writer, filePath string, opts *ConfigGenerationOpts) (*Config, error) {
+	if opts == nil {
+		opts = &ConfigGenerationOpts{}
+	}
+	wz := &wizard{r: bufio.NewReader(r), w: w}
+	cfg := BuildConfigStruct(filePath, opts)
+	bc := cfg.Blockchain
+	var err error
+
+	wz.section("Blockchain")
+	if bc.Name, err = wz.ask("Name of the blockchain", bc.Name, func(str string) error {
+		if !blockchainNameRegexp.MatchString(str) {
+			return errors.New("has to start with a letter, and can only contain lowercase letters and digits")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if bc.LongName, err = wz.ask("Long (display) name of the blockchain, optional", bc.LongName, nil); err != nil {
+		return nil, err
+	}
+	if bc.Repository, err = wz.ask("Go repository of the generated code", bc.Repository, validateNoWhitespace); err != nil {
+		return nil, err
+	}
+	if bc.Currency.Unit, err = wz.ask("Currency unit", bc.Currency.Unit, validateNoWhitespace); err != nil {
+		return nil, err
+	}
+	if bc.Currency.Precision, err = wz.askUint("Currency precision (amount of decimals)", bc.Currency.Precision, 1, maxCurrencyPrecision); err != nil {
+		return nil, err
+	}
+	port, err := wz.askUint("API port", uint64(bc.Ports.API), 1, 65535)
+	if err != nil {
+		return nil, err
+	}
+	bc.Ports.API = uint16(port)
+	for {
+		if port, err = wz.askUint("RPC port", uint64(bc.Ports.RPC), 1, 65535); err != nil {
+			return nil, err
+		}
+		if uint16(port) != bc.Ports.API {
+			break
+		}
+		fmt.Fprintln(wz.w, "  invalid answer: has to differ from the API port")
+	}
+	bc.Ports.RPC = uint16(port)
+
+	wz.section("Binaries")
+	validateBinaryName := func(str string) error {
+		if !binaryNameRegexp.MatchString(str) {
+			return errors.New("can only contain letters, digits, underscores and dashes")
+		}
+		return nil
+	}
+	if bc.Binaries.Client, err = wz.ask("Name of the client binary", bc.Name+"c", validateBinaryName); err != nil {
+		return nil, err
+	}
+	if bc.Binaries.Daemon, err = wz.ask("Name of the daemon binary", bc.Name+"d", func(str string) error {
+		if str == bc.Binaries.Client {
+			return errors.New("has to differ from the name of the client binary")
+		}
+		return validateBinaryName(str)
+	}); err != nil {
+		return nil, err
+	}
+
+	wz.section("Plugins")
+	if opts.PluginMintingEnabled, err = wz.askBool("Enable the minting plugin", opts.PluginMintingEnabled); err != nil {
+		return nil, err
+	}
+	if opts.PluginAuthcoinEnabled, err = wz.askBool("Enable the authcoin plugin", opts.PluginAuthcoinEnabled); err != nil {
+		return nil, err
+	}
+	// take the transaction versions of the enabled plugins from the default config
+	bc.Transactions = BuildConfigStruct(filePath, opts).Blockchain.Transactions
+
+	wz.section("Networks")
+	answer, err := wz.ask("Networks to configure, comma-separated (standard, testnet, devnet)", "standard,testnet,devnet", func(str string) error {
+		_, err := parseWizardNetworkNames(str)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	names, _ := parseWizardNetworkNames(answer)
+	format := types.NewCurrencyFormat(bc.Currency.Units(), "")
+	networks := make(map[string]*Network, len(names))
+	for _, name := range names {
+		network, err := wz.askNetwork(name, bc, opts, format)
+		if err != nil {
+			return nil, err
+		}
+		networks[name] = network
+	}
+	bc.Networks = networks
+
+	wz.section("Template")
+	if cfg.Template.Repository, err = wz.ask("Repository of the chain template", cfg.Template.Repository, validateNoWhitespace); err != nil {
+		return nil, err
+	}
+	if cfg.Template.Version, err = wz.ask("Version (branch, tag or commit) of the chain template", cfg.Template.Version, validateNoWhitespace); err != nil {
+		return nil, err
+	}
+
+	wz.section("Frontend")
+	explorerDomain, err := wz.ask("Domain of the explorer, empty to not serve the explorer", "", validateOptionalNoWhitespace)
+	if err != nil {
+		return nil, err
+	}
+	faucetDomain, err := wz.ask("Domain of the faucet, empty to not serve the faucet", "", validateOptionalNoWhitespace)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Frontend = nil
+	if explorerDomain != "" || faucetDomain != "" {
+		email, err := wz.ask("Email address used to request TLS certificates", "", func(str string) error {
+			if !strings.Contains(str, "@") {
+				return errors.New("not an email address")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		cfg.Frontend = &Frontend{}
+		if explorerDomain != "" {
+			cfg.Frontend.Explorer = &Explorer{Caddy: Caddy{DNS: explorerDomain, TLS: email}}
+		}
+		if faucetDomain != "" {
+			cfg.Frontend.Faucet = &Faucet{Caddy: Caddy{DNS: faucetDomain, TLS: email}}
+		}
+	}
+
+	if err = validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %v", err)
+	}
+	return cfg, nil
+}
+
+// askNetwork asks the genesis allocations and bootstrap peers of the network with the given name,
+// taking the other network properties from the default network config
+func (wz *wizard) askNetwork(name string, bc *Blockchain, opts *ConfigGenerationOpts, format types.CurrencyFormat) (*Network, error) {
+	networkType := wizardNetworkTypes[name]
+	network := bc.Networks[name]
+	devnet := networkType == NetworkTypeDevnet
+	// the example addresses of the default config are only proposed for the devnet network,
+	// as its (well-known) mnemonic is shared by all rivine devnet networks
+	var devnetAddress string
+	if devnet {
+		devnetAddress = network.Genesis.CoinOutputs[0].Condition.UnlockHash().String()
+	}
+	network.Genesis.Minting, network.Genesis.Authcoin = nil, nil
+
+	wz.section(fmt.Sprintf("Network %s", name))
+	var err error
+	network.Genesis.CoinOutputs, err = wz.askOutputs("genesis coin allocation", "Amount of coins ("+bc.Currency.Unit+")", devnetAddress, func(str string) error {
+		if str == "" {
+			return errors.New("a value is required")
+		}
+		c, err := format.ParseCoins(str)
+		if err != nil {
+			return err
+		}
+		if c.IsZero() {
+			return errors.New("has to be greater than zero")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	network.Genesis.BlockStakeOutputs, err = wz.askOutputs("genesis block stake allocation", "Amount of block stakes", devnetAddress, func(str string) error {
+		x, err := strconv.ParseUint(str, 10, 64)
+		if err != nil || x == 0 {
+			return errors.New("has to be an unsigned integer greater than zero")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	firstAddress := network.Genesis.CoinOutputs[0].Condition.UnlockHash().String()
+	if network.TransactionFeePool, err = wz.ask("Address of the transaction fee pool, optional", devnetAddress, validateOptionalAddress); err != nil {
+		return nil, err
+	}
+	if opts.PluginMintingEnabled {
+		c, err := wz.askCondition("Address allowed to mint coins", firstAddress)
+		if err != nil {
+			return nil, err
+		}
+		network.Genesis.Minting = &c
+	}
+	if opts.PluginAuthcoinEnabled {
+		c, err := wz.askCondition("Address allowed to authorize addresses", firstAddress)
+		if err != nil {
+			return nil, err
+		}
+		network.Genesis.Authcoin = &c
+	}
+
+	var defaultPeers string
+	if devnet {
+		defaultPeers = fmt.Sprintf("localhost:%d", bc.Ports.RPC)
+	}
+	answer, err := wz.ask("Bootstrap peers, comma-separated host:port addresses", defaultPeers, func(str string) error {
+		_, err := parseWizardBootstrapPeers(str, networkType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	network.BootstrapPeers, _ = parseWizardBootstrapPeers(answer, networkType)
+	return network, nil
+}
+
+// askOutputs asks the outputs of a genesis allocation, until no address is given.
+// At least one output is required.
+func (wz *wizard) askOutputs(name, valueQuestion, defaultAddress string, validateValue func(string) error) ([]Output, error) {
+	var outputs []Output
+	for {
+		def := ""
+		if len(outputs) == 0 {
+			def = defaultAddress
+		}
+		address, err := wz.ask(fmt.Sprintf("Address of %s #%d, empty to finish", name, len(outputs)+1), def, validateOptionalAddress)
+		if err != nil {
+			return nil, err
+		}
+		if address == "" {
+			if len(outputs) == 0 {
+				fmt.Fprintf(wz.w, "  invalid answer: at least one %s is required\n", name)
+				continue
+			}
+			return outputs, nil
+		}
+		value, err := wz.ask(valueQuestion, "", validateValue)
+		if err != nil {
+			return nil, err
+		}
+		var uh types.UnlockHash
+		uh.LoadString(address) // validated by validateOptionalAddress
+		outputs = append(outputs, Output{
+			Value:     value,
+			Condition: NewCondition(types.NewUnlockHashCondition(uh)),
+		})
+	}
+}
+
+// askCondition asks an address, returning it as an unlock hash condition
+func (wz *wizard) askCondition(question, def string) (Condition, error) {
+	address, err := wz.ask(question, def, func(str string) error {
+		if str == "" {
+			return errors.New("an address is required")
+		}
+		return validateOptionalAddress(str)
+	})
+	if err != nil {
+		return Condition{}, err
+	}
+	var uh types.UnlockHash
+	uh.LoadString(address) // validated above
+	return NewCondition(types.NewUnlockHashCondition(uh)), nil
+}
+
+// parseWizardNetworkNames parses a comma-separated list of network names,
+// in which each name has to be one of the network types
+func parseWizardNetworkNames(str string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(str, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := wizardNetworkTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown network %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("network %q is defined multiple times", name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("at least one network is required")
+	}
+	return names, nil
+}
+
+// parseWizardBootstrapPeers parses a comma-separated list of bootstrap peers,
+// validated the same way as the bootstrap peers of a config file.
+// At least one peer is required.
+func parseWizardBootstrapPeers(str string, networkType NetworkType) ([]*BootstrapPeer, error) {
+	var peers []*BootstrapPeer
+	for _, addr := range strings.Split(str, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		peer := &BootstrapPeer{NetAddress: modules.NetAddress(addr)}
+		var err error
+		// allow loopback addresses in devnet network
+		if peer.IsLoopback() && networkType == NetworkTypeDevnet {
+			err = peer.IsStdValid()
+		} else {
+			err = peer.IsValid()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer %s: %v", addr, err)
+		}
+		peers = append(peers, peer)
+	}
+	if len(peers) == 0 {
+		return nil, errors.New("at least one bootstrap peer is required")
+	}
+	return peers, nil
+}
+
+// validateOptionalAddress validates an address, if given
+func validateOptionalAddress(str string) error {
+	if str == "" {
+		return nil
+	}
+	var uh types.UnlockHash
+	if err := uh.LoadString(str); err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+	return nil
+}
+
+// validateNoWhitespace validates that a value is given, which contains no whitespace
+func validateNoWhitespace(str string) error {
+	if str == "" {
+		return errors.New("a value is required")
+	}
+	return validateOptionalNoWhitespace(str)
+}
+
+// validateOptionalNoWhitespace validates that a value contains no whitespace
+func validateOptionalNoWhitespace(str string) error {
+	if strings.ContainsAny(str, " \t") {
+		return errors.New("cannot contain whitespace")
+	}
+	return nil
+}
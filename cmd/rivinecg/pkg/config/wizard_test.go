@@ -0,0 +1,62 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunConfigWizard(t *testing.T) {
+	const address = "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+	answers := []string{
+		"My Chain", "mychain", // invalid name is asked again
+		"", "github.com/me/mychain", "MYC", "19", "9", // precision out of range is asked again
+		"", "23111", "23112", // RPC port equal to the API port is asked again
+		"", "", // binary names
+		"yes", "no", // plugins
+		"devnet,foo", "devnet", // unknown network is asked again
+		"", "", "1.5", "", // coin allocation using the default address, a missing amount is asked again
+		"", "abc", "10", "", // block stake allocation, an invalid amount is asked again
+		"",           // transaction fee pool
+		"",           // minting address
+		"",           // bootstrap peers
+		"", "v1.0.0", // template
+		"", "", // frontend
+	}
+	var output bytes.Buffer
+	cfg, err := RunConfigWizard(strings.NewReader(strings.Join(answers, "\n")+"\n"), &output, "blockchaincfg.yaml", nil)
+	if err != nil {
+		t.Fatalf("wizard failed: %v\n%s", err, output.String())
+	}
+	bc := cfg.Blockchain
+	if bc.Name != "mychain" || bc.Binaries.Client != "mychainc" || bc.Binaries.Daemon != "mychaind" ||
+		bc.Currency.Unit != "MYC" || bc.Currency.Precision != 9 || bc.Ports.RPC != 23112 {
+		t.Errorf("unexpected blockchain config: %+v", bc)
+	}
+	if len(bc.Networks) != 1 || bc.Networks["devnet"] == nil {
+		t.Fatalf("unexpected networks: %v", bc.Networks)
+	}
+	devnet := bc.Networks["devnet"]
+	if len(devnet.Genesis.CoinOutputs) != 1 || devnet.Genesis.CoinOutputs[0].Value != "1.5" ||
+		devnet.Genesis.CoinOutputs[0].Condition.UnlockHash().String() != address ||
+		len(devnet.Genesis.BlockStakeOutputs) != 1 || devnet.Genesis.BlockStakeOutputs[0].Value != "10" {
+		t.Errorf("unexpected genesis: %+v", devnet.Genesis)
+	}
+	if devnet.Genesis.Minting == nil || devnet.Genesis.Authcoin != nil || bc.Transactions.Minting == nil || bc.Transactions.Authcoin != nil {
+		t.Error("only the minting plugin should be configured")
+	}
+	if len(devnet.BootstrapPeers) != 1 || devnet.BootstrapPeers[0].NetAddress != "localhost:23112" {
+		t.Errorf("unexpected bootstrap peers: %v", devnet.BootstrapPeers)
+	}
+	if cfg.Template.Version != "v1.0.0" || cfg.Frontend != nil {
+		t.Errorf("unexpected template or frontend: %+v %+v", cfg.Template, cfg.Frontend)
+	}
+	if n := strings.Count(output.String(), "invalid answer"); n != 6 {
+		t.Errorf("expected 6 invalid answers, not %d:\n%s", n, output.String())
+	}
+
+	// the wizard is aborted when the input ends prematurely
+	if _, err = RunConfigWizard(strings.NewReader("mychain\n"), &output, "blockchaincfg.yaml", nil); err != errWizardAborted {
+		t.Errorf("expected the wizard to be aborted, not: %v", err)
+	}
+}
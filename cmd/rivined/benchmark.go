@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/daemon"
+)
+
+// benchmarkCmd measures the performance of the local machine,
+// such that operators can size their hardware and compare hosting providers.
+type benchmarkCmd struct {
+	duration     time.Duration
+	dir          string
+	network      string
+	encodingType cli.EncodingType
+}
+
+func createBenchmarkCmd(cmds *commands) *cobra.Command {
+	benchmarkCmd := &benchmarkCmd{}
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure the performance of this machine",
+		Long: `Measure the signature verification throughput, the database write, commit and read rates
+and the transaction validation speed of this machine, and estimate the time it takes
+to validate the transactions of a full block. The database benchmarks use a temporary database,
+created in the given directory, which should be on the disk the daemon stores its data on.`,
+		Run: benchmarkCmd.run,
+	}
+	cmd.Flags().DurationVar(&benchmarkCmd.duration, "duration", 2*time.Second, "duration of each benchmark")
+	cmd.Flags().StringVarP(&benchmarkCmd.dir, "persistent-directory", "d", ".",
+		"directory to create the temporary database of the database benchmarks in")
+	cmd.Flags().StringVarP(&benchmarkCmd.network, "network", "n", cmds.cfg.BlockchainInfo.NetworkName,
+		"network of which the constants are used to validate the transactions with")
+	cmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &benchmarkCmd.encodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+	return cmd
+}
+
+func (cmd *benchmarkCmd) run(*cobra.Command, []string) {
+	if cmd.duration <= 0 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "the duration of a benchmark has to be positive")
+	}
+	networkCfg, err := daemon.DefaultNetworkConfig(cmd.network)
+	if err != nil {
+		cli.DieWithError("failed to create network config", err)
+	}
+	if cmd.encodingType == cli.EncodingTypeHuman {
+		fmt.Fprintf(os.Stderr, "Running 6 benchmarks of %v each...\n", cmd.duration)
+	}
+	report, err := daemon.RunBenchmarks(daemon.BenchmarkConfig{
+		Duration:  cmd.duration,
+		Dir:       cmd.dir,
+		Constants: networkCfg.Constants,
+	})
+	if err != nil {
+		cli.DieWithError("benchmark failed", err)
+	}
+	switch cmd.encodingType {
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(report)
+	default:
+		err = daemon.WriteBenchmarkReport(os.Stdout, report)
+	}
+	if err != nil {
+		cli.DieWithError("failed to write benchmark report", err)
+	}
+}
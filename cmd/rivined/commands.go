@@ -31,6 +31,11 @@ func (cmds *commands) rootCommand(*cobra.Command, []string) {
 		cli.DieWithError("failed to validate network config", err)
 	}
 
+	err = daemon.VerifyStakingNetworks(daemon.ProcessConfig(cmds.cfg))
+	if err != nil {
+		cli.DieWithError("failed to validate staking networks", err)
+	}
+
 	// Silently append a subdirectory for storage with the name of the network so we don't create conflicts
 	cmds.cfg.RootPersistentDir = filepath.Join(cmds.cfg.RootPersistentDir, cmds.cfg.BlockchainInfo.NetworkName)
 	// Check if we require an api password
@@ -20,11 +20,15 @@ import (
 	"github.com/threefoldtech/rivine/modules/wallet"
 	"github.com/threefoldtech/rivine/pkg/api"
 	"github.com/threefoldtech/rivine/pkg/daemon"
+	"github.com/threefoldtech/rivine/types"
 )
 
 const (
 	// maxConcurrentRPC is the maximum number of concurrent RPC calls allowed for a single daemon
 	maxConcurrentRPC = 1
+
+	// apiTokensFile is the name of the file that contains the (hashed) API tokens
+	apiTokensFile = "apitokens.json"
 )
 
 func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentifiers daemon.ModuleIdentifierSet) error {
@@ -46,18 +50,92 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 	}
 
 	// create our server already, this way we can fail early if the API addr is already bound
-	fmt.Println("Binding API Address and serving the API...")
-	srv, err := daemon.NewHTTPServer(cfg.APIaddr)
+	tlsConfig, err := daemon.APITLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		fmt.Println("Binding API Address and serving the API over TLS...")
+	} else {
+		fmt.Println("Binding API Address and serving the API...")
+	}
+	srv, err := daemon.NewHTTPServer(cfg.APIaddr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	socketPath, socketPerm, err := daemon.APISocket(cfg)
 	if err != nil {
 		return err
 	}
+	if socketPath != "" {
+		fmt.Println("Serving the API on unix socket", socketPath)
+		if err = os.MkdirAll(filepath.Dir(socketPath), 0755); err != nil {
+			return err
+		}
+		if err = srv.ListenUnix(socketPath, socketPerm); err != nil {
+			return err
+		}
+	}
 	servErrs := make(chan error)
 	go func() {
 		servErrs <- srv.Serve()
 	}()
 
-	// router to register all endpoints to
-	router := httprouter.New()
+	// health endpoints are served while the modules are loading,
+	// and do not require a user agent, such that they can be used as liveness and readiness probes
+	healthChecker := api.NewHealthChecker(api.HealthConfig{
+		Modules:               modulesToLoad,
+		PersistDir:            cfg.RootPersistentDir,
+		BlockFrequency:        networkCfg.Constants.BlockFrequency,
+		MaxBlocksBehind:       types.BlockHeight(cfg.HealthMaxBlocksBehind),
+		RequireUnlockedWallet: cfg.HealthRequireUnlockedWallet,
+	})
+	healthRouter := httprouter.New()
+	api.RegisterHealthHTTPHandlers(healthRouter, healthChecker)
+	srv.Handle("/health/", api.RequestIDHandler(healthRouter))
+
+	// authenticator used to authenticate API requests, using the API password or an API token
+	auth, err := api.NewAPIAuthenticator(cfg.APIPassword, filepath.Join(cfg.RootPersistentDir, apiTokensFile))
+	if err != nil {
+		return err
+	}
+
+	// router to register all endpoints to,
+	// keeping track of them such that they can be served by the versioned API as well,
+	// requiring authentication for all but the public routes should public routes be configured
+	mux := httprouter.New()
+	router := api.NewRouteCatalog(mux)
+	// route groups (e.g. the wallet routes) can be disabled at runtime
+	routeGroups := api.NewRouteGroups(router)
+	// router to register the module endpoints to, accepting the bech32 addresses of the network next to hex addresses
+	chainRouter := api.NewBech32Router(router, networkCfg.Constants.Bech32AddressPrefix)
+	// status of the daemon, reporting the modules loaded by the health checker
+	daemonStatus := api.NewDaemonStatus(cfg.BlockchainInfo, networkCfg.Constants.BlockFrequency, healthChecker, routeGroups)
+	publicRoutes := cfg.APIPublicRoutes
+	if len(publicRoutes) == 1 && publicRoutes[0] == daemon.APIPublicRoutesDefault {
+		publicRoutes = api.DefaultAPIPublicRoutes
+	}
+	// immutable responses (e.g. confirmed blocks) are cached, should a cache size be configured,
+	// only once authenticated as the cached responses are served without going through the routes
+	cachedMux := api.ResponseCacheHandler(mux, api.NewResponseCache(cfg.APICacheSize<<20))
+	muxHandler, err := api.PublicRoutesHandler(api.RouteGroupsHandler(cachedMux, routeGroups), auth, publicRoutes)
+	if err != nil {
+		return err
+	}
+	// transaction submissions can be retried using an idempotency key, should a window be configured
+	muxHandler = api.IdempotencyHandler(muxHandler, api.NewIdempotencyStore(cfg.APIIdempotencyWindow), api.IdempotentRoutes)
+	versionedHandler := api.NewVersionedHandler(muxHandler, router)
+
+	// access log of all API requests (excluding the health endpoints), should one be configured
+	var accessLogger *api.AccessLogger
+	accessLog, err := daemon.APIAccessLogWriter(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open API access log: %v", err)
+	}
+	if accessLog != nil {
+		defer accessLog.Close()
+		accessLogger = api.NewAccessLogger(accessLog, auth)
+	}
 
 	// Initialize the Rivine modules
 	var g modules.Gateway
@@ -69,7 +147,8 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err != nil {
 			return err
 		}
-		api.RegisterGatewayHTTPHandlers(router, g, cfg.APIPassword)
+		api.RegisterGatewayHTTPHandlers(chainRouter, g, auth)
+		healthChecker.ModuleLoaded("gateway")
 		defer func() {
 			fmt.Println("Closing gateway...")
 			err := g.Close()
@@ -88,7 +167,8 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err != nil {
 			return err
 		}
-		api.RegisterConsensusHTTPHandlers(router, cs)
+		api.RegisterConsensusHTTPHandlers(chainRouter, cs)
+		healthChecker.SetConsensusSet(cs)
 		defer func() {
 			fmt.Println("Closing consensus set...")
 			err := cs.Close()
@@ -107,7 +187,8 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err != nil {
 			return err
 		}
-		api.RegisterTransactionPoolHTTPHandlers(router, cs, tpool, cfg.APIPassword)
+		api.RegisterTransactionPoolHTTPHandlers(chainRouter, cs, tpool, networkCfg.Constants, auth)
+		healthChecker.ModuleLoaded("transaction pool")
 		defer func() {
 			fmt.Println("Closing transaction pool...")
 			err := tpool.Close()
@@ -125,7 +206,8 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err != nil {
 			return err
 		}
-		api.RegisterWalletHTTPHandlers(router, w, cfg.APIPassword)
+		api.RegisterWalletHTTPHandlers(chainRouter, w, auth)
+		healthChecker.SetWallet(w)
 		defer func() {
 			fmt.Println("Closing wallet...")
 			err := w.Close()
@@ -138,13 +220,14 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 	var b modules.BlockCreator
 	if moduleIdentifiers.Contains(daemon.BlockCreatorModule.Identifier()) {
 		printModuleIsLoading("block creator")
-		b, err = blockcreator.New(cs, tpool, w,
+		b, err = blockcreator.New(cs, tpool, w, g,
 			filepath.Join(cfg.RootPersistentDir, modules.BlockCreatorDir),
-			cfg.BlockchainInfo, networkCfg.Constants, cfg.VerboseLogging)
+			cfg.BlockchainInfo, networkCfg.Constants, cfg.VerboseLogging, cfg.BlockCreatorDryRun)
 		if err != nil {
 			return err
 		}
-		// block creator has no API endpoints to register
+		api.RegisterBlockCreatorHTTPHandlers(chainRouter, b)
+		healthChecker.ModuleLoaded("block creator")
 		defer func() {
 			fmt.Println("Closing block creator...")
 			err := b.Close()
@@ -162,7 +245,12 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err != nil {
 			return err
 		}
-		api.RegisterExplorerHTTPHandlers(router, cs, e, tpool)
+		api.RegisterExplorerHTTPHandlers(chainRouter, cs, e, tpool)
+		api.RegisterAddressHTTPHandlers(chainRouter, e)
+		if cfg.APIGraphQL {
+			api.RegisterGraphQLHTTPHandlers(chainRouter, cs, e)
+		}
+		healthChecker.ModuleLoaded("explorer")
 		defer func() {
 			fmt.Println("Closing explorer...")
 			err := e.Close()
@@ -172,9 +260,44 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		}()
 	}
 
+	if cs != nil {
+		api.RegisterEventsHTTPHandlers(chainRouter, cs, tpool, w, auth)
+		if cfg.APIJSONRPC {
+			api.RegisterJSONRPCHTTPHandlers(chainRouter, cs, tpool, w, e, networkCfg.Constants, auth)
+		}
+	}
+
+	stakingNetworks := make([]*stakingNetwork, 0, len(cfg.StakingNetworks))
+	for _, stakingCfg := range cfg.StakingNetworks {
+		fmt.Printf("Loading staking network %s...\r\n", stakingCfg.NetworkName)
+		sn, err := loadStakingNetwork(cfg, stakingCfg, router, auth)
+		if err != nil {
+			return fmt.Errorf("failed to load staking network %s: %v", stakingCfg.NetworkName, err)
+		}
+		stakingNetworks = append(stakingNetworks, sn)
+		defer sn.Close()
+	}
+
 	fmt.Println("Setting up root HTTP API handler...")
 
+	// limit the rate of API requests, should rate limits be configured
+	rateLimiter, err := api.NewRateLimiter(api.RateLimiterConfig{
+		IP:           api.RateLimit{Rate: cfg.APIRateLimit, Burst: cfg.APIRateLimitBurst},
+		Token:        api.RateLimit{Rate: cfg.APITokenRateLimit, Burst: cfg.APITokenRateLimitBurst},
+		ExemptRoutes: cfg.APIRateLimitExemptRoutes,
+	}, auth)
+	if err != nil {
+		return err
+	}
+
 	// register our special daemon HTTP handlers
+	api.RegisterAuthHTTPHandlers(router, auth)
+	api.RegisterRouteGroupsHTTPHandlers(router, routeGroups, auth)
+	if rateLimiter != nil {
+		api.RegisterRateLimitHTTPHandlers(router, rateLimiter, auth)
+	}
+	// batched calls are rate limited individually
+	api.RegisterBatchHTTPHandlers(router, api.RateLimitHandler(versionedHandler, rateLimiter))
 	router.GET("/daemon/constants", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 		constants := modules.NewDaemonConstants(cfg.BlockchainInfo, networkCfg.Constants)
 		api.WriteJSON(w, constants)
@@ -185,7 +308,8 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 			ProtocolVersion: cfg.BlockchainInfo.ProtocolVersion,
 		})
 	})
-	router.POST("/daemon/stop", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	router.GET("/daemon/status", api.RequireScopeHandler(api.NewDaemonStatusHandler(daemonStatus), auth, api.APIScopeReadOnly))
+	router.POST("/daemon/stop", api.RequireScopeHandler(func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 		// can't write after we stop the server, so lie a bit.
 		api.WriteSuccess(w)
 
@@ -200,17 +324,33 @@ func runDaemon(cfg daemon.Config, networkCfg daemon.NetworkConfig, moduleIdentif
 		if err := srv.Close(); err != nil {
 			servErrs <- err
 		}
-	})
+	}, auth, api.APIScopeAdmin))
 
-	// handle all our endpoints over a router,
-	// which requires a user agent should one be configured
-	srv.Handle("/", api.RequireUserAgentHandler(router, cfg.RequiredUserAgent))
+	// handle all our endpoints over a router, both unversioned and versioned,
+	// which requires a user agent should one be configured,
+	// limits the rate of requests should rate limits be configured,
+	// allows cross-origin requests should a CORS policy be configured,
+	// compresses large responses for clients accepting compressed responses,
+	// and logs all requests should an access log be configured,
+	// identifying every request by a request ID
+	handler := api.RateLimitHandler(api.RequireUserAgentHandler(versionedHandler, cfg.RequiredUserAgent), rateLimiter)
+	handler = api.CORSHandler(handler, api.CORSPolicy{
+		AllowedOrigins: cfg.APICORSAllowedOrigins,
+		AllowedMethods: cfg.APICORSAllowedMethods,
+		AllowedHeaders: cfg.APICORSAllowedHeaders,
+	})
+	handler = api.CompressionHandler(handler, cfg.APICompressionMinSize)
+	srv.Handle("/", api.RequestIDHandler(api.AccessLogHandler(handler, accessLogger)))
 
 	// If there are any long running operations that need to happen first (e.g. for some extension code)
 	// You can do that first before starting the cs syncing
 	if cs != nil {
 		cs.Start()
 	}
+	for _, sn := range stakingNetworks {
+		sn.Start()
+	}
+	healthChecker.SetStarted()
 
 	// stop the server if a kill signal is caught
 	sigChan := make(chan os.Signal, 1)
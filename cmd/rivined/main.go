@@ -46,6 +46,8 @@ func main() {
 		Run:   cmds.modulesCommand,
 	})
 
+	root.AddCommand(createBenchmarkCmd(&cmds))
+
 	// Parse cmdline flags, overwriting both the default values and the config
 	// file values.
 	if err := root.Execute(); err != nil {
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/modules/blockcreator"
+	"github.com/threefoldtech/rivine/modules/consensus"
+	"github.com/threefoldtech/rivine/modules/gateway"
+	"github.com/threefoldtech/rivine/modules/transactionpool"
+	"github.com/threefoldtech/rivine/modules/wallet"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/daemon"
+)
+
+// stakingNetwork groups all modules required to create blocks on an additional network.
+type stakingNetwork struct {
+	name string
+
+	g     modules.Gateway
+	cs    modules.ConsensusSet
+	tpool modules.TransactionPool
+	w     modules.Wallet
+	b     modules.BlockCreator
+}
+
+// loadStakingNetwork loads all modules required to stake on the given network,
+// using its own persistent directory, living next to the one of the main network.
+// All API endpoints of the modules are registered on the given router,
+// prefixed with "/networks/<network>".
+func loadStakingNetwork(cfg daemon.Config, stakingCfg daemon.StakingNetworkConfig, router api.Router, auth *api.APIAuthenticator) (*stakingNetwork, error) {
+	networkCfg, err := daemon.DefaultNetworkConfig(stakingCfg.NetworkName)
+	if err != nil {
+		return nil, err
+	}
+	err = networkCfg.Constants.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid network config for %s: %v", stakingCfg.NetworkName, err)
+	}
+
+	bcInfo := cfg.BlockchainInfo
+	bcInfo.NetworkName = stakingCfg.NetworkName
+	// the root persistent dir already has the name of the main network appended to it
+	rootPersistentDir := filepath.Join(filepath.Dir(cfg.RootPersistentDir), stakingCfg.NetworkName)
+
+	// close all modules that were already loaded, should we fail to load any module
+	sn := &stakingNetwork{name: stakingCfg.NetworkName}
+	loaded := false
+	defer func() {
+		if !loaded {
+			sn.Close()
+		}
+	}()
+
+	g, err := gateway.New(stakingCfg.RPCaddr, !cfg.NoBootstrap, maxConcurrentRPC,
+		filepath.Join(rootPersistentDir, modules.GatewayDir),
+		bcInfo, networkCfg.Constants, networkCfg.BootstrapPeers, cfg.VerboseLogging)
+	if err != nil {
+		return nil, err
+	}
+	sn.g = g
+	cs, err := consensus.New(g, !cfg.NoBootstrap,
+		filepath.Join(rootPersistentDir, modules.ConsensusDir),
+		bcInfo, networkCfg.Constants, cfg.VerboseLogging, "")
+	if err != nil {
+		return nil, err
+	}
+	sn.cs = cs
+	tpool, err := transactionpool.New(cs, g,
+		filepath.Join(rootPersistentDir, modules.TransactionPoolDir),
+		bcInfo, networkCfg.Constants, cfg.VerboseLogging)
+	if err != nil {
+		return nil, err
+	}
+	sn.tpool = tpool
+	w, err := wallet.New(cs, tpool,
+		filepath.Join(rootPersistentDir, modules.WalletDir),
+		bcInfo, networkCfg.Constants, cfg.VerboseLogging)
+	if err != nil {
+		return nil, err
+	}
+	sn.w = w
+	b, err := blockcreator.New(cs, tpool, w, g,
+		filepath.Join(rootPersistentDir, modules.BlockCreatorDir),
+		bcInfo, networkCfg.Constants, cfg.VerboseLogging, cfg.BlockCreatorDryRun)
+	if err != nil {
+		return nil, err
+	}
+	sn.b = b
+
+	networkRouter := api.NewBech32Router(
+		api.NewPrefixedRouter(router, "/networks/"+stakingCfg.NetworkName),
+		networkCfg.Constants.Bech32AddressPrefix)
+	api.RegisterGatewayHTTPHandlers(networkRouter, sn.g, auth)
+	api.RegisterConsensusHTTPHandlers(networkRouter, sn.cs)
+	api.RegisterTransactionPoolHTTPHandlers(networkRouter, sn.cs, sn.tpool, networkCfg.Constants, auth)
+	api.RegisterWalletHTTPHandlers(networkRouter, sn.w, auth)
+	api.RegisterBlockCreatorHTTPHandlers(networkRouter, sn.b)
+
+	loaded = true
+	return sn, nil
+}
+
+// Start starts syncing the consensus set of the staking network.
+func (sn *stakingNetwork) Start() {
+	sn.cs.Start()
+}
+
+// Close closes all loaded modules of the staking network, in reverse order of loading.
+func (sn *stakingNetwork) Close() {
+	closers := []struct {
+		name   string
+		module interface{ Close() error }
+	}{
+		{"block creator", sn.b},
+		{"wallet", sn.w},
+		{"transaction pool", sn.tpool},
+		{"consensus set", sn.cs},
+		{"gateway", sn.g},
+	}
+	for _, closer := range closers {
+		if closer.module == nil {
+			continue
+		}
+		fmt.Printf("Closing %s of network %s...\n", closer.name, sn.name)
+		if err := closer.module.Close(); err != nil {
+			fmt.Printf("Error during %s shutdown of network %s: %v\n", closer.name, sn.name, err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package crypto
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/NebulousLabs/fastrand"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+const (
+	// BLSPublicKeySize defines the size of (compressed G1) BLS12-381 public keys in bytes.
+	BLSPublicKeySize = 48
+
+	// BLSSecretKeySize defines the size of BLS12-381 secret keys in bytes.
+	BLSSecretKeySize = 32
+
+	// BLSSignatureSize defines the size of (compressed G2) BLS12-381 signatures in bytes.
+	BLSSignatureSize = 96
+)
+
+var (
+	// ErrInvalidBLSPublicKey is returned in case a BLS public key
+	// is not a valid compressed G1 point in the correct subgroup.
+	ErrInvalidBLSPublicKey = errors.New("invalid BLS public key")
+
+	// ErrInvalidBLSSecretKey is returned in case a BLS secret key
+	// is not a valid (non-zero) scalar of the curve.
+	ErrInvalidBLSSecretKey = errors.New("invalid BLS secret key")
+
+	// ErrBLSDuplicateMessage is returned in case an aggregated BLS signature
+	// is verified against a message set which contains a message more than once.
+	ErrBLSDuplicateMessage = errors.New("aggregated BLS signature covers a duplicate message")
+)
+
+type (
+	// BLSPublicKey is a BLS12-381 public key,
+	// encoded as a compressed point of the G1 group.
+	BLSPublicKey [BLSPublicKeySize]byte
+
+	// BLSSecretKey is a BLS12-381 secret key (scalar),
+	// which can be used to sign data for the corresponding public key.
+	BLSSecretKey [BLSSecretKeySize]byte
+
+	// BLSSignature is a BLS12-381 signature (or an aggregation of signatures),
+	// encoded as a compressed point of the G2 group.
+	BLSSignature [BLSSignatureSize]byte
+)
+
+var (
+	nilBLSSecretKey = BLSSecretKey{}
+	nilBLSPublicKey = BLSPublicKey{}
+
+	// blsGroupOrder is the order of the G1, G2 and GT groups
+	blsGroupOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+	// blsDomain is the domain separation tag used to hash messages onto G2,
+	// following the basic scheme of the IETF BLS signature draft,
+	// which requires all messages of an aggregated signature to be distinct.
+	blsDomain = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+)
+
+// PublicKey returns the compressed public key that corresponds to a secret key.
+func (sk BLSSecretKey) PublicKey() (pk BLSPublicKey) {
+	g1 := bls12381.NewG1()
+	p := g1.MulScalarBig(g1.New(), g1.One(), new(big.Int).SetBytes(sk[:]))
+	copy(pk[:], g1.ToCompressed(p))
+	return
+}
+
+// IsNil returns true if this secret key equals to a pure zero array.
+func (sk BLSSecretKey) IsNil() bool {
+	return sk == nilBLSSecretKey
+}
+
+// IsNil returns true if this public key equals to a pure zero array.
+func (pk BLSPublicKey) IsNil() bool {
+	return pk == nilBLSPublicKey
+}
+
+// GenerateBLSKeyPair creates a BLS12-381 public-secret keypair
+// that can be used to sign and verify messages.
+func GenerateBLSKeyPair() (BLSSecretKey, BLSPublicKey) {
+	var entropy [EntropySize]byte
+	fastrand.Read(entropy[:])
+	return GenerateBLSKeyPairDeterministic(entropy)
+}
+
+// GenerateBLSKeyPairDeterministic generates BLS12-381 keys deterministically using the input
+// entropy. The entropy is mapped onto the valid (non-zero) scalar range of the curve.
+func GenerateBLSKeyPairDeterministic(entropy [EntropySize]byte) (sk BLSSecretKey, pk BLSPublicKey) {
+	// d = (entropy mod (r-1)) + 1, ensuring 0 < d < r
+	n := new(big.Int).Sub(blsGroupOrder, big.NewInt(1))
+	d := new(big.Int).SetBytes(entropy[:])
+	d.Mod(d, n).Add(d, big.NewInt(1))
+	b := d.Bytes()
+	copy(sk[BLSSecretKeySize-len(b):], b)
+	pk = sk.PublicKey()
+	return
+}
+
+// SignHashBLS signs a message using a BLS12-381 secret key.
+// The signature is deterministic, and can be aggregated with other BLS signatures.
+func SignHashBLS(data Hash, sk BLSSecretKey) (sig BLSSignature, err error) {
+	d := new(big.Int).SetBytes(sk[:])
+	if d.Sign() == 0 || d.Cmp(blsGroupOrder) >= 0 {
+		return sig, ErrInvalidBLSSecretKey
+	}
+	g2 := bls12381.NewG2()
+	h, err := g2.HashToCurve(data[:], blsDomain)
+	if err != nil {
+		return sig, err
+	}
+	copy(sig[:], g2.ToCompressed(g2.MulScalarBig(g2.New(), h, d)))
+	return sig, nil
+}
+
+// VerifyHashBLS uses a BLS12-381 public key and input data to verify a signature.
+func VerifyHashBLS(data Hash, pk BLSPublicKey, sig BLSSignature) error {
+	return VerifyAggregatedHashesBLS([]Hash{data}, []BLSPublicKey{pk}, sig)
+}
+
+// AggregateBLSSignatures aggregates one or multiple BLS12-381 signatures
+// into a single signature of the same size.
+func AggregateBLSSignatures(sigs ...BLSSignature) (agg BLSSignature, err error) {
+	if len(sigs) == 0 {
+		return agg, errors.New("no BLS signatures given to aggregate")
+	}
+	g2 := bls12381.NewG2()
+	sum := g2.Zero()
+	for _, sig := range sigs {
+		p, err := g2.FromCompressed(sig[:])
+		if err != nil {
+			return agg, ErrInvalidSignature
+		}
+		g2.Add(sum, sum, p)
+	}
+	copy(agg[:], g2.ToCompressed(sum))
+	return agg, nil
+}
+
+// VerifyAggregatedHashesBLS verifies an (aggregated) BLS12-381 signature,
+// against a set of distinct messages, each signed by the public key at the same index.
+// All pairings are checked in a single batch.
+func VerifyAggregatedHashesBLS(data []Hash, pks []BLSPublicKey, sig BLSSignature) error {
+	if len(data) == 0 || len(data) != len(pks) {
+		return errors.New("invalid BLS message set: each message requires exactly one public key")
+	}
+	seen := make(map[Hash]struct{}, len(data))
+	for _, h := range data {
+		if _, ok := seen[h]; ok {
+			return ErrBLSDuplicateMessage
+		}
+		seen[h] = struct{}{}
+	}
+
+	engine := bls12381.NewEngine()
+	s, err := engine.G2.FromCompressed(sig[:])
+	if err != nil || engine.G2.IsZero(s) {
+		return ErrInvalidSignature
+	}
+	// e(-g1, sig) * Π e(pk_i, H(m_i)) == 1
+	engine.AddPairInv(engine.G1.One(), s)
+	for i, pk := range pks {
+		p, err := engine.G1.FromCompressed(pk[:])
+		if err != nil || engine.G1.IsZero(p) {
+			return ErrInvalidBLSPublicKey
+		}
+		h, err := engine.G2.HashToCurve(data[i][:], blsDomain)
+		if err != nil {
+			return err
+		}
+		engine.AddPair(p, h)
+	}
+	if !engine.Check() {
+		return ErrInvalidSignature
+	}
+	return nil
+}
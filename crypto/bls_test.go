@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/fastrand"
+)
+
+// TestBLSSigning creates a couple of BLS keypairs and signs random data with each of
+// them, verifying the signatures and checking that modified data, keys or signatures are rejected.
+func TestBLSSigning(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		sk, pk := GenerateBLSKeyPair()
+		if sk.IsNil() || pk.IsNil() {
+			t.Fatal("generated nil BLS key")
+		}
+		if sk.PublicKey() != pk {
+			t.Fatal("public key does not match secret key")
+		}
+
+		var data Hash
+		fastrand.Read(data[:])
+		sig, err := SignHashBLS(data, sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = VerifyHashBLS(data, pk, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		// modified data should not verify
+		data[0]++
+		if err = VerifyHashBLS(data, pk, sig); err != ErrInvalidSignature {
+			t.Error("expected invalid signature for modified data, got:", err)
+		}
+		data[0]--
+
+		// another key should not verify
+		_, otherPK := GenerateBLSKeyPair()
+		if err = VerifyHashBLS(data, otherPK, sig); err != ErrInvalidSignature {
+			t.Error("expected invalid signature for other key, got:", err)
+		}
+
+		// an invalid key should be rejected
+		var invalidPK BLSPublicKey
+		if err = VerifyHashBLS(data, invalidPK, sig); err != ErrInvalidBLSPublicKey {
+			t.Error("expected invalid public key, got:", err)
+		}
+	}
+
+	if _, err := SignHashBLS(Hash{}, BLSSecretKey{}); err != ErrInvalidBLSSecretKey {
+		t.Error("expected nil secret key to be rejected, got:", err)
+	}
+}
+
+// TestBLSAggregation aggregates the signatures of multiple signers over distinct messages,
+// and verifies the aggregated signature in a single batch.
+func TestBLSAggregation(t *testing.T) {
+	const n = 4
+	var (
+		hashes = make([]Hash, n)
+		pks    = make([]BLSPublicKey, n)
+		sigs   = make([]BLSSignature, n)
+	)
+	for i := range hashes {
+		var sk BLSSecretKey
+		sk, pks[i] = GenerateBLSKeyPair()
+		fastrand.Read(hashes[i][:])
+		var err error
+		sigs[i], err = SignHashBLS(hashes[i], sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	agg, err := AggregateBLSSignatures(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyAggregatedHashesBLS(hashes, pks, agg); err != nil {
+		t.Fatal(err)
+	}
+
+	// a missing signature should invalidate the aggregation
+	partialAgg, err := AggregateBLSSignatures(sigs[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyAggregatedHashesBLS(hashes, pks, partialAgg); err != ErrInvalidSignature {
+		t.Error("expected invalid signature for partial aggregation, got:", err)
+	}
+
+	// swapped messages should not verify
+	hashes[0], hashes[1] = hashes[1], hashes[0]
+	if err = VerifyAggregatedHashesBLS(hashes, pks, agg); err != ErrInvalidSignature {
+		t.Error("expected invalid signature for swapped messages, got:", err)
+	}
+
+	// duplicate messages are not allowed
+	hashes[1] = hashes[0]
+	if err = VerifyAggregatedHashesBLS(hashes, pks, agg); err != ErrBLSDuplicateMessage {
+		t.Error("expected duplicate message error, got:", err)
+	}
+}
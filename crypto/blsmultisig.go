@@ -0,0 +1,212 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/NebulousLabs/fastrand"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// BLS multisig errors
+var (
+	// ErrBLSDuplicatePublicKey is returned in case a set of BLS public keys,
+	// which is to be aggregated, contains a public key more than once.
+	ErrBLSDuplicatePublicKey = errors.New("duplicate BLS public key in key set")
+	// ErrBLSPublicKeyNotInSet is returned in case a BLS secret key is used
+	// to sign for an aggregated key, which it isn't part of.
+	ErrBLSPublicKeyNotInSet = errors.New("BLS public key of secret key is not part of the aggregated key set")
+	// ErrBLSInvalidShareIndex is returned in case a BLS key or signature share
+	// has an index of zero, or an index which is used by another share.
+	ErrBLSInvalidShareIndex = errors.New("invalid or duplicate BLS share index")
+)
+
+type (
+	// BLSKeyShare is a share of a BLS secret key, created by splitting
+	// a BLS secret key into n shares, of which any m shares can sign on behalf of that key.
+	BLSKeyShare struct {
+		Index     uint64
+		SecretKey BLSSecretKey
+	}
+
+	// BLSSignatureShare is a signature created using a BLS key share,
+	// m signature shares, with distinct indices, can be combined into a signature
+	// of the original (split) BLS secret key.
+	BLSSignatureShare struct {
+		Index     uint64
+		Signature BLSSignature
+	}
+)
+
+// AggregateBLSPublicKeys aggregates an n-of-n group of BLS public keys into a single public key,
+// for which all members of the group need to sign (see SignHashBLSMultiSig) in order to produce
+// a valid signature. The order of the public keys does not matter.
+//
+// Each public key is weighted by a coefficient derived from the full key set,
+// MuSig-style, protecting the aggregated key against rogue key attacks.
+func AggregateBLSPublicKeys(pks ...BLSPublicKey) (agg BLSPublicKey, err error) {
+	coefficients, err := blsKeyAggregationCoefficients(pks)
+	if err != nil {
+		return agg, err
+	}
+	g1 := bls12381.NewG1()
+	sum := g1.Zero()
+	for i, pk := range pks {
+		p, err := g1.FromCompressed(pk[:])
+		if err != nil || g1.IsZero(p) {
+			return agg, ErrInvalidBLSPublicKey
+		}
+		g1.Add(sum, sum, g1.MulScalarBig(g1.New(), p, coefficients[i]))
+	}
+	copy(agg[:], g1.ToCompressed(sum))
+	return agg, nil
+}
+
+// SignHashBLSMultiSig signs a message using a BLS12-381 secret key,
+// as one of the members of the given (n-of-n) public key set. Once all members signed,
+// their signatures can be aggregated using AggregateBLSSignatures, producing a signature
+// which can be verified using the public key returned by AggregateBLSPublicKeys.
+func SignHashBLSMultiSig(data Hash, sk BLSSecretKey, pks []BLSPublicKey) (sig BLSSignature, err error) {
+	coefficients, err := blsKeyAggregationCoefficients(pks)
+	if err != nil {
+		return sig, err
+	}
+	pk := sk.PublicKey()
+	for i := range pks {
+		if pks[i] != pk {
+			continue
+		}
+		d := new(big.Int).SetBytes(sk[:])
+		d.Mul(d, coefficients[i]).Mod(d, blsGroupOrder)
+		return SignHashBLS(data, blsSecretKeyFromBig(d))
+	}
+	return sig, ErrBLSPublicKeyNotInSet
+}
+
+// blsKeyAggregationCoefficients computes the coefficient of each public key,
+// as the hash of the (sorted) key set and the public key itself.
+func blsKeyAggregationCoefficients(pks []BLSPublicKey) ([]*big.Int, error) {
+	if len(pks) == 0 {
+		return nil, errors.New("no BLS public keys given to aggregate")
+	}
+	sorted := make([]BLSPublicKey, len(pks))
+	copy(sorted, pks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	set := make([]byte, 0, len(sorted)*BLSPublicKeySize)
+	for i, pk := range sorted {
+		if i > 0 && sorted[i-1] == pk {
+			return nil, ErrBLSDuplicatePublicKey
+		}
+		set = append(set, pk[:]...)
+	}
+	setHash := HashBytes(set)
+	coefficients := make([]*big.Int, len(pks))
+	for i, pk := range pks {
+		h := HashBytes(append(append([]byte("bls-key-aggregation"), setHash[:]...), pk[:]...))
+		coefficients[i] = new(big.Int).SetBytes(h[:])
+		coefficients[i].Mod(coefficients[i], blsGroupOrder)
+	}
+	return coefficients, nil
+}
+
+// SplitBLSSecretKey splits a BLS secret key into count shares,
+// of which any threshold shares can sign on behalf of the split secret key,
+// using Shamir's secret sharing scheme. The public key of the split secret key
+// is used on-chain, as a regular BLS public key. The secret key itself
+// should be discarded once the shares are distributed.
+func SplitBLSSecretKey(sk BLSSecretKey, threshold, count int) ([]BLSKeyShare, error) {
+	if threshold < 1 || count < threshold {
+		return nil, errors.New("invalid BLS key split: threshold has to be in the range [1, count]")
+	}
+	d := new(big.Int).SetBytes(sk[:])
+	if d.Sign() == 0 || d.Cmp(blsGroupOrder) >= 0 {
+		return nil, ErrInvalidBLSSecretKey
+	}
+	// f(x) = d + c_1*x + ... + c_(threshold-1)*x^(threshold-1)
+	coefficients := make([]*big.Int, threshold)
+	coefficients[0] = d
+	for i := 1; i < threshold; i++ {
+		coefficients[i] = new(big.Int).SetBytes(fastrand.Bytes(2 * BLSSecretKeySize))
+		coefficients[i].Mod(coefficients[i], blsGroupOrder)
+	}
+	shares := make([]BLSKeyShare, count)
+	for i := range shares {
+		x := big.NewInt(int64(i + 1))
+		y := new(big.Int)
+		for j := threshold - 1; j >= 0; j-- {
+			y.Mul(y, x).Add(y, coefficients[j]).Mod(y, blsGroupOrder)
+		}
+		shares[i] = BLSKeyShare{
+			Index:     uint64(i + 1),
+			SecretKey: blsSecretKeyFromBig(y),
+		}
+	}
+	return shares, nil
+}
+
+// SignHashBLSShare signs a message using a BLS key share.
+func SignHashBLSShare(data Hash, share BLSKeyShare) (BLSSignatureShare, error) {
+	if share.Index == 0 {
+		return BLSSignatureShare{}, ErrBLSInvalidShareIndex
+	}
+	sig, err := SignHashBLS(data, share.SecretKey)
+	if err != nil {
+		return BLSSignatureShare{}, err
+	}
+	return BLSSignatureShare{
+		Index:     share.Index,
+		Signature: sig,
+	}, nil
+}
+
+// CombineBLSSignatureShares combines signature shares into a signature
+// of the split secret key. Exactly threshold shares are expected,
+// combining less shares results in an invalid signature.
+func CombineBLSSignatureShares(shares ...BLSSignatureShare) (sig BLSSignature, err error) {
+	if len(shares) == 0 {
+		return sig, errors.New("no BLS signature shares given to combine")
+	}
+	indices := make([]*big.Int, len(shares))
+	seen := make(map[uint64]struct{}, len(shares))
+	for i, share := range shares {
+		if _, ok := seen[share.Index]; ok || share.Index == 0 {
+			return sig, ErrBLSInvalidShareIndex
+		}
+		seen[share.Index] = struct{}{}
+		indices[i] = new(big.Int).SetUint64(share.Index)
+	}
+	g2 := bls12381.NewG2()
+	sum := g2.Zero()
+	for i, share := range shares {
+		p, err := g2.FromCompressed(share.Signature[:])
+		if err != nil {
+			return sig, ErrInvalidSignature
+		}
+		// lagrange coefficient at x=0: Π x_j / (x_j - x_i), for j != i
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range indices {
+			if j == i {
+				continue
+			}
+			num.Mul(num, xj).Mod(num, blsGroupOrder)
+			diff := new(big.Int).Sub(xj, indices[i])
+			den.Mul(den, diff).Mod(den, blsGroupOrder)
+		}
+		lambda := num.Mul(num, den.ModInverse(den, blsGroupOrder))
+		lambda.Mod(lambda, blsGroupOrder)
+		g2.Add(sum, sum, g2.MulScalarBig(g2.New(), p, lambda))
+	}
+	copy(sig[:], g2.ToCompressed(sum))
+	return sig, nil
+}
+
+// blsSecretKeyFromBig encodes a scalar as a (big-endian) BLS secret key
+func blsSecretKeyFromBig(d *big.Int) (sk BLSSecretKey) {
+	b := d.Bytes()
+	copy(sk[BLSSecretKeySize-len(b):], b)
+	return
+}
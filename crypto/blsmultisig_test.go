@@ -0,0 +1,133 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/fastrand"
+)
+
+// TestBLSKeyAggregation aggregates an n-of-n group of BLS public keys,
+// and verifies that only the aggregated signature of all members is valid.
+func TestBLSKeyAggregation(t *testing.T) {
+	const n = 3
+	var (
+		sks = make([]BLSSecretKey, n)
+		pks = make([]BLSPublicKey, n)
+	)
+	for i := range sks {
+		sks[i], pks[i] = GenerateBLSKeyPair()
+	}
+	aggPK, err := AggregateBLSPublicKeys(pks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the order of the keys should not matter
+	reversedAggPK, err := AggregateBLSPublicKeys(pks[2], pks[1], pks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aggPK != reversedAggPK {
+		t.Fatal("aggregated public key depends on the order of the key set")
+	}
+
+	var data Hash
+	fastrand.Read(data[:])
+	sigs := make([]BLSSignature, n)
+	for i, sk := range sks {
+		sigs[i], err = SignHashBLSMultiSig(data, sk, pks)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sig, err := AggregateBLSSignatures(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyHashBLS(data, aggPK, sig); err != nil {
+		t.Fatal("failed to verify aggregated multisig signature:", err)
+	}
+
+	// a signature of only some members should not verify
+	partialSig, err := AggregateBLSSignatures(sigs[:n-1]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyHashBLS(data, aggPK, partialSig); err != ErrInvalidSignature {
+		t.Error("expected invalid signature for partial multisig signature, got:", err)
+	}
+
+	// a naive aggregation of the keys should not match the aggregated key
+	if naiveSig, _ := AggregateBLSSignatures(func() []BLSSignature {
+		naive := make([]BLSSignature, n)
+		for i, sk := range sks {
+			naive[i], _ = SignHashBLS(data, sk)
+		}
+		return naive
+	}()...); VerifyHashBLS(data, aggPK, naiveSig) == nil {
+		t.Error("unweighted signatures should not verify for the aggregated key")
+	}
+
+	// invalid key sets
+	otherSK, _ := GenerateBLSKeyPair()
+	if _, err = SignHashBLSMultiSig(data, otherSK, pks); err != ErrBLSPublicKeyNotInSet {
+		t.Error("expected key not in set error, got:", err)
+	}
+	if _, err = AggregateBLSPublicKeys(pks[0], pks[1], pks[0]); err != ErrBLSDuplicatePublicKey {
+		t.Error("expected duplicate key error, got:", err)
+	}
+}
+
+// TestBLSThresholdSignatures splits a BLS secret key in m-of-n shares,
+// and verifies that any m signature shares combine into a valid signature.
+func TestBLSThresholdSignatures(t *testing.T) {
+	const m, n = 2, 3
+	sk, pk := GenerateBLSKeyPair()
+	shares, err := SplitBLSSecretKey(sk, m, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != n {
+		t.Fatal("unexpected amount of shares:", len(shares))
+	}
+
+	var data Hash
+	fastrand.Read(data[:])
+	sigShares := make([]BLSSignatureShare, n)
+	for i, share := range shares {
+		sigShares[i], err = SignHashBLSShare(data, share)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, subset := range [][]BLSSignatureShare{
+		{sigShares[0], sigShares[1]},
+		{sigShares[2], sigShares[0]},
+		{sigShares[1], sigShares[2]},
+	} {
+		sig, err := CombineBLSSignatureShares(subset...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = VerifyHashBLS(data, pk, sig); err != nil {
+			t.Error("failed to verify combined threshold signature:", err)
+		}
+	}
+
+	// less than threshold shares should not result in a valid signature
+	sig, err := CombineBLSSignatureShares(sigShares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyHashBLS(data, pk, sig); err != ErrInvalidSignature {
+		t.Error("expected invalid signature for a single share, got:", err)
+	}
+
+	// invalid share sets
+	if _, err = CombineBLSSignatureShares(sigShares[0], sigShares[0]); err != ErrBLSInvalidShareIndex {
+		t.Error("expected invalid share index error, got:", err)
+	}
+	if _, err = SplitBLSSecretKey(sk, n+1, n); err == nil {
+		t.Error("expected error for threshold greater than share count")
+	}
+}
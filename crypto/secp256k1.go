@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/NebulousLabs/fastrand"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	// Secp256k1PublicKeySize defines the size of (compressed) secp256k1 public keys in bytes.
+	Secp256k1PublicKeySize = btcec.PubKeyBytesLenCompressed
+
+	// Secp256k1SecretKeySize defines the size of secp256k1 secret keys in bytes.
+	Secp256k1SecretKeySize = 32
+
+	// Secp256k1SignatureSize defines the size of (compact R || S) secp256k1 signatures in bytes.
+	Secp256k1SignatureSize = 64
+)
+
+var (
+	// ErrInvalidSecp256k1PublicKey is returned in case a secp256k1 public key
+	// is not a valid compressed point on the curve.
+	ErrInvalidSecp256k1PublicKey = errors.New("invalid secp256k1 public key")
+
+	// ErrInvalidSecp256k1SecretKey is returned in case a secp256k1 secret key
+	// is not a valid (non-zero) scalar of the curve.
+	ErrInvalidSecp256k1SecretKey = errors.New("invalid secp256k1 secret key")
+)
+
+type (
+	// Secp256k1PublicKey is a compressed secp256k1 public key,
+	// as used by Bitcoin and Ethereum tooling.
+	Secp256k1PublicKey [Secp256k1PublicKeySize]byte
+
+	// Secp256k1SecretKey is a secp256k1 secret key (scalar),
+	// which can be used to sign data for the corresponding public key.
+	Secp256k1SecretKey [Secp256k1SecretKeySize]byte
+
+	// Secp256k1Signature is a secp256k1 ECDSA signature,
+	// encoded as the 32-byte R value followed by the 32-byte (low) S value.
+	Secp256k1Signature [Secp256k1SignatureSize]byte
+)
+
+var (
+	nilSecp256k1SecretKey = Secp256k1SecretKey{}
+	nilSecp256k1PublicKey = Secp256k1PublicKey{}
+
+	secp256k1HalfOrder = new(big.Int).Rsh(btcec.S256().N, 1)
+)
+
+// PublicKey returns the compressed public key that corresponds to a secret key.
+func (sk Secp256k1SecretKey) PublicKey() (pk Secp256k1PublicKey) {
+	_, btcPK := btcec.PrivKeyFromBytes(btcec.S256(), sk[:])
+	copy(pk[:], btcPK.SerializeCompressed())
+	return
+}
+
+// IsNil returns true if this secret key equals to a pure zero array.
+func (sk Secp256k1SecretKey) IsNil() bool {
+	return sk == nilSecp256k1SecretKey
+}
+
+// IsNil returns true if this public key equals to a pure zero array.
+func (pk Secp256k1PublicKey) IsNil() bool {
+	return pk == nilSecp256k1PublicKey
+}
+
+// GenerateSecp256k1KeyPair creates a secp256k1 public-secret keypair
+// that can be used to sign and verify messages.
+func GenerateSecp256k1KeyPair() (Secp256k1SecretKey, Secp256k1PublicKey) {
+	var entropy [EntropySize]byte
+	fastrand.Read(entropy[:])
+	return GenerateSecp256k1KeyPairDeterministic(entropy)
+}
+
+// GenerateSecp256k1KeyPairDeterministic generates secp256k1 keys deterministically using the input
+// entropy. The entropy is mapped onto the valid (non-zero) scalar range of the curve.
+func GenerateSecp256k1KeyPairDeterministic(entropy [EntropySize]byte) (sk Secp256k1SecretKey, pk Secp256k1PublicKey) {
+	// d = (entropy mod (N-1)) + 1, ensuring 0 < d < N
+	n := new(big.Int).Sub(btcec.S256().N, big.NewInt(1))
+	d := new(big.Int).SetBytes(entropy[:])
+	d.Mod(d, n).Add(d, big.NewInt(1))
+	b := d.Bytes()
+	copy(sk[Secp256k1SecretKeySize-len(b):], b)
+	pk = sk.PublicKey()
+	return
+}
+
+// SignHashSecp256k1 signs a message using a secp256k1 secret key.
+// The signature is deterministic (RFC6979) and uses a canonical (low) S value.
+func SignHashSecp256k1(data Hash, sk Secp256k1SecretKey) (sig Secp256k1Signature, err error) {
+	d := new(big.Int).SetBytes(sk[:])
+	if d.Sign() == 0 || d.Cmp(btcec.S256().N) >= 0 {
+		return sig, ErrInvalidSecp256k1SecretKey
+	}
+	btcSK, _ := btcec.PrivKeyFromBytes(btcec.S256(), sk[:])
+	btcSig, err := btcSK.Sign(data[:])
+	if err != nil {
+		return sig, err
+	}
+	r, s := btcSig.R.Bytes(), btcSig.S.Bytes()
+	copy(sig[32-len(r):32], r)
+	copy(sig[64-len(s):], s)
+	return sig, nil
+}
+
+// VerifyHashSecp256k1 uses a secp256k1 public key and input data to verify a signature.
+// Signatures with a high S value are rejected, such that signatures are not malleable.
+func VerifyHashSecp256k1(data Hash, pk Secp256k1PublicKey, sig Secp256k1Signature) error {
+	btcPK, err := btcec.ParsePubKey(pk[:], btcec.S256())
+	if err != nil {
+		return ErrInvalidSecp256k1PublicKey
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(btcec.S256().N) >= 0 || s.Cmp(secp256k1HalfOrder) > 0 {
+		return ErrInvalidSignature
+	}
+	btcSig := btcec.Signature{R: r, S: s}
+	if !btcSig.Verify(data[:], btcPK) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NebulousLabs/fastrand"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// TestSecp256k1Signing creates a bunch of secp256k1 keypairs and signs random data with each of
+// them, verifying the signatures and checking that modified data, keys or signatures are rejected.
+func TestSecp256k1Signing(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		sk, pk := GenerateSecp256k1KeyPair()
+		if sk.IsNil() || pk.IsNil() {
+			t.Fatal("generated nil secp256k1 key")
+		}
+		if sk.PublicKey() != pk {
+			t.Fatal("public key does not match secret key")
+		}
+
+		var data Hash
+		fastrand.Read(data[:])
+		sig, err := SignHashSecp256k1(data, sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = VerifyHashSecp256k1(data, pk, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		// signing is deterministic
+		sig2, err := SignHashSecp256k1(data, sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig != sig2 {
+			t.Error("signing the same data twice produced different signatures")
+		}
+
+		// modified data should not verify
+		data[0]++
+		if err = VerifyHashSecp256k1(data, pk, sig); err != ErrInvalidSignature {
+			t.Error("expected invalid signature for modified data, got:", err)
+		}
+		data[0]--
+
+		// another key should not verify
+		_, otherPK := GenerateSecp256k1KeyPair()
+		if err = VerifyHashSecp256k1(data, otherPK, sig); err != ErrInvalidSignature {
+			t.Error("expected invalid signature for other key, got:", err)
+		}
+
+		// an invalid key should be rejected
+		var invalidPK Secp256k1PublicKey
+		if err = VerifyHashSecp256k1(data, invalidPK, sig); err != ErrInvalidSecp256k1PublicKey {
+			t.Error("expected invalid public key, got:", err)
+		}
+	}
+}
+
+// TestSecp256k1HighSRejected ensures that the malleable (high S) form
+// of a valid signature is rejected.
+func TestSecp256k1HighSRejected(t *testing.T) {
+	sk, pk := GenerateSecp256k1KeyPair()
+	var data Hash
+	fastrand.Read(data[:])
+	sig, err := SignHashSecp256k1(data, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := new(big.Int).SetBytes(sig[32:])
+	s.Sub(btcec.S256().N, s)
+	var malleatedSig Secp256k1Signature
+	copy(malleatedSig[:32], sig[:32])
+	b := s.Bytes()
+	copy(malleatedSig[64-len(b):], b)
+	if err = VerifyHashSecp256k1(data, pk, malleatedSig); err != ErrInvalidSignature {
+		t.Error("expected high S signature to be rejected, got:", err)
+	}
+}
+
+// TestGenerateSecp256k1KeyPairDeterministic checks that the same entropy
+// always results in the same valid key pair, including edge-case entropy.
+func TestGenerateSecp256k1KeyPairDeterministic(t *testing.T) {
+	var entropy [EntropySize]byte
+	fastrand.Read(entropy[:])
+	sk1, pk1 := GenerateSecp256k1KeyPairDeterministic(entropy)
+	sk2, pk2 := GenerateSecp256k1KeyPairDeterministic(entropy)
+	if sk1 != sk2 || pk1 != pk2 {
+		t.Fatal("deterministic key generation is not deterministic")
+	}
+
+	for _, b := range []byte{0x00, 0xff} {
+		for i := range entropy {
+			entropy[i] = b
+		}
+		sk, pk := GenerateSecp256k1KeyPairDeterministic(entropy)
+		sig, err := SignHashSecp256k1(Hash{}, sk)
+		if err != nil {
+			t.Fatalf("invalid secret key generated for entropy byte %x: %v", b, err)
+		}
+		if err = VerifyHashSecp256k1(Hash{}, pk, sig); err != nil {
+			t.Fatalf("invalid key pair generated for entropy byte %x: %v", b, err)
+		}
+	}
+
+	if _, err := SignHashSecp256k1(Hash{}, Secp256k1SecretKey{}); err != ErrInvalidSecp256k1SecretKey {
+		t.Error("expected nil secret key to be rejected, got:", err)
+	}
+}
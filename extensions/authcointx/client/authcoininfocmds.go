@@ -70,7 +70,7 @@ func (ac *authCoinCmd) getAuthInfo(cmd *cobra.Command, args []string) {
 		ac.getAuthCondition(cmd, args[1:])
 	} else {
 		var uh types.UnlockHash
-		err := uh.LoadString(args[0])
+		err := uh.LoadAddressString(args[0], ac.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cmd.UsageFunc()(cmd)
 			cli.DieWithError("invalid address cannot be authorized", err)
@@ -90,7 +90,7 @@ func (walletCmd *walletCmd) authConditionUpdateTxCreateCmd(cmd *cobra.Command, a
 	if len(args) == 1 {
 		// create a single sig condition
 		var uh types.UnlockHash
-		err = uh.LoadString(args[0])
+		err = uh.LoadAddressString(args[0], walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cmd.UsageFunc()(cmd)
 			cli.DieWithError("invalid address cannot be turned into an UnlockHashCondition", err)
@@ -110,7 +110,7 @@ func (walletCmd *walletCmd) authConditionUpdateTxCreateCmd(cmd *cobra.Command, a
 		}
 		addresses := make([]types.UnlockHash, len(args))
 		for index, arg := range args {
-			err = addresses[index].LoadString(arg)
+			err = addresses[index].LoadAddressString(arg, walletCmd.cli.Config.Bech32AddressPrefix)
 			if err != nil {
 				cli.DieWithError(fmt.Sprintf("invalid address %q cannot be used as part of a MultiSigCondition", arg), err)
 			}
@@ -149,7 +149,7 @@ func (walletCmd *walletCmd) authAddressUpdateTxCreateCmd(cmd *cobra.Command, _ [
 	// add authorized addresses
 	tx.AuthAddresses = make([]types.UnlockHash, len(walletCmd.authAddressUpdateTxCfg.AuthAddresses))
 	for index, address := range walletCmd.authAddressUpdateTxCfg.AuthAddresses {
-		err = tx.AuthAddresses[index].LoadString(address)
+		err = tx.AuthAddresses[index].LoadAddressString(address, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.DieWithError(fmt.Sprintf("invalid address %q cannot be authorized", address), err)
 		}
@@ -157,7 +157,7 @@ func (walletCmd *walletCmd) authAddressUpdateTxCreateCmd(cmd *cobra.Command, _ [
 	// add deauthorized addresses
 	tx.DeauthAddresses = make([]types.UnlockHash, len(walletCmd.authAddressUpdateTxCfg.DeauthAddresses))
 	for index, address := range walletCmd.authAddressUpdateTxCfg.DeauthAddresses {
-		err = tx.DeauthAddresses[index].LoadString(address)
+		err = tx.DeauthAddresses[index].LoadAddressString(address, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.DieWithError(fmt.Sprintf("invalid address %q cannot be deauthorized", address), err)
 		}
@@ -9,4 +9,5 @@ import (
 func RegisterConsensusMintingHTTPHandlers(router rapi.Router, plugin *minting.Plugin) {
 	router.GET("/consensus/mintcondition", NewTransactionDBGetActiveMintConditionHandler(plugin))
 	router.GET("/consensus/mintcondition/:height", NewTransactionDBGetMintConditionAtHandler(plugin))
+	router.GET("/consensus/mintedsupply", NewTransactionDBGetMintedSupplyHandler(plugin))
 }
@@ -9,4 +9,5 @@ import (
 func RegisterExplorerMintingHTTPHandlers(router rapi.Router, plugin *minting.Plugin) {
 	router.GET("/explorer/mintcondition", NewTransactionDBGetActiveMintConditionHandler(plugin))
 	router.GET("/explorer/mintcondition/:height", NewTransactionDBGetMintConditionAtHandler(plugin))
+	router.GET("/explorer/mintedsupply", NewTransactionDBGetMintedSupplyHandler(plugin))
 }
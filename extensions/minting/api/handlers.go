@@ -17,6 +17,14 @@ type TransactionDBGetMintCondition struct {
 	MintCondition types.UnlockConditionProxy `json:"mintcondition"`
 }
 
+// TransactionDBGetMintedSupply contains the total amount of coins
+// created and destroyed using the minting transactions.
+type TransactionDBGetMintedSupply struct {
+	minting.MintedSupply
+	// Net is the amount of coins added to the coin supply by the minting transactions.
+	Net types.Currency `json:"net"`
+}
+
 // NewTransactionDBGetActiveMintConditionHandler creates a handler to handle the API calls to /transactiondb/mintcondition.
 func NewTransactionDBGetActiveMintConditionHandler(plugin *minting.Plugin) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
@@ -31,6 +39,21 @@ func NewTransactionDBGetActiveMintConditionHandler(plugin *minting.Plugin) httpr
 	}
 }
 
+// NewTransactionDBGetMintedSupplyHandler creates a handler to handle the API calls to /transactiondb/mintedsupply.
+func NewTransactionDBGetMintedSupplyHandler(plugin *minting.Plugin) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		supply, err := plugin.GetMintedSupply()
+		if err != nil {
+			rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		rapi.WriteJSON(w, TransactionDBGetMintedSupply{
+			MintedSupply: supply,
+			Net:          supply.Net(),
+		})
+	}
+}
+
 // NewTransactionDBGetMintConditionAtHandler creates a handler to handle the API calls to /transactiondb/mintcondition/:height.
 func NewTransactionDBGetMintConditionAtHandler(plugin *minting.Plugin) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
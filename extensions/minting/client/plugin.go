@@ -59,6 +59,18 @@ func (cli *PluginClient) GetActiveMintCondition() (types.UnlockConditionProxy, e
 	return result.MintCondition, nil
 }
 
+// GetMintedSupply returns the total amount of coins created and destroyed
+// using the minting transactions, as tracked by the daemon.
+func (cli *PluginClient) GetMintedSupply() (minting.MintedSupply, error) {
+	var result api.TransactionDBGetMintedSupply
+	err := cli.client.GetAPI(cli.rootEndpoint+"/mintedsupply", &result)
+	if err != nil {
+		return minting.MintedSupply{}, fmt.Errorf(
+			"failed to get minted supply from daemon: %v", err)
+	}
+	return result.MintedSupply, nil
+}
+
 // GetMintConditionAt implements minting.MintConditionGetter.GetMintConditionAt
 func (cli *PluginClient) GetMintConditionAt(height types.BlockHeight) (types.UnlockConditionProxy, error) {
 	var result api.TransactionDBGetMintCondition
@@ -2,6 +2,7 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
 
@@ -39,14 +40,27 @@ or the one for the given block height.
 `,
 			Run: subCmds.getMintCondition,
 		}
+		getMintedSupplyCmd = &cobra.Command{
+			Use:   "mintedsupply",
+			Short: "Get the minted supply",
+			Long: `Get the total amount of coins created and destroyed
+using the minting transactions, as tracked by the consensus.
+`,
+			Args: cobra.NoArgs,
+			Run:  subCmds.getMintedSupply,
+		}
 	)
 
 	getMintConditionCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &subCmds.getMintConditionCfg.EncodingType, cli.EncodingTypeJSON|cli.EncodingTypeHuman), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeJSON|cli.EncodingTypeHuman))
 
-	// Add getMintConditionCmd to the ExploreCmd
-	rootCmd.AddCommand(getMintConditionCmd)
+	getMintedSupplyCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &subCmds.getMintedSupplyCfg.EncodingType, cli.EncodingTypeJSON|cli.EncodingTypeHuman), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeJSON|cli.EncodingTypeHuman))
+
+	// Add getMintConditionCmd and getMintedSupplyCmd to the root command
+	rootCmd.AddCommand(getMintConditionCmd, getMintedSupplyCmd)
 }
 
 type subCmd struct {
@@ -55,6 +69,9 @@ type subCmd struct {
 	getMintConditionCfg struct {
 		EncodingType cli.EncodingType
 	}
+	getMintedSupplyCfg struct {
+		EncodingType cli.EncodingType
+	}
 }
 
 func (subCmds *subCmd) getMintCondition(cmd *cobra.Command, args []string) {
@@ -105,3 +122,23 @@ func (subCmds *subCmd) getMintCondition(cmd *cobra.Command, args []string) {
 		cli.DieWithError("failed to encode mint condition", err)
 	}
 }
+
+func (subCmds *subCmd) getMintedSupply(cmd *cobra.Command, args []string) {
+	supply, err := subCmds.pluginClient.GetMintedSupply()
+	if err != nil {
+		cli.DieWithError("failed to get the minted supply", err)
+	}
+
+	switch subCmds.getMintedSupplyCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		currencyConvertor := subCmds.cli.CreateCurrencyConvertor()
+		fmt.Printf("Created:   %s\n", currencyConvertor.ToCoinStringWithUnit(supply.Created))
+		fmt.Printf("Destroyed: %s\n", currencyConvertor.ToCoinStringWithUnit(supply.Destroyed))
+		fmt.Printf("Net:       %s\n", currencyConvertor.ToCoinStringWithUnit(supply.Net()))
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(supply)
+		if err != nil {
+			cli.DieWithError("failed to encode minted supply", err)
+		}
+	}
+}
@@ -52,7 +52,7 @@ The returned (raw) MinterDefinitionTransaction still has to be signed, prior to
 The outputs can be given as a pair of value and a raw output condition (or
 address, which resolves to a singlesignature condition).
 
-Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency.
 Decimals are possible and have to be defined using the decimal point.
 
 The Minimum Miner Fee will be added on top of the total given amount automatically.
@@ -158,7 +158,7 @@ func (walletCmd *walletCmd) createMinterDefinitionTxCmd(cmd *cobra.Command, args
 
 	// parse the given mint condition
 	var err error
-	tx.MintCondition, err = parseConditionString(args[0])
+	tx.MintCondition, err = parseConditionString(args[0], walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
@@ -178,7 +178,7 @@ func (walletCmd *walletCmd) createCoinCreationTxCmd(cmd *cobra.Command, args []s
 	}
 
 	// parse the remainder as output coditions and values
-	pairs, err := parsePairedOutputs(args, currencyConvertor.ParseCoinString)
+	pairs, err := parsePairedOutputs(args, currencyConvertor.ParseCoinString, walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
@@ -225,7 +225,7 @@ func (walletCmd *walletCmd) burnCoinsCmd(cmd *cobra.Command, args []string) {
 	var refundAddress *types.UnlockHash
 	if walletCmd.coinDestructionTxCfg.RefundAddress != "" {
 		refundAddress = new(types.UnlockHash)
-		err = refundAddress.LoadString(walletCmd.coinDestructionTxCfg.RefundAddress)
+		err = refundAddress.LoadAddressString(walletCmd.coinDestructionTxCfg.RefundAddress, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cmd.UsageFunc()(cmd)
 			cli.Die(err)
@@ -268,10 +268,10 @@ func (walletCmd *walletCmd) burnCoinsCmd(cmd *cobra.Command, args []string) {
 
 // try to parse the string first as an unlock hash,
 // if that fails parse it as a
-func parseConditionString(str string) (condition types.UnlockConditionProxy, err error) {
+func parseConditionString(str, bech32Prefix string) (condition types.UnlockConditionProxy, err error) {
 	// try to parse it as an unlock hash
 	var uh types.UnlockHash
-	err = uh.LoadString(str)
+	err = uh.LoadAddressString(str, bech32Prefix)
 	if err == nil {
 		// parsing as an unlock hash was succesfull, store the pair and continue to the next pair
 		condition = types.NewCondition(types.NewUnlockHashCondition(uh))
@@ -297,7 +297,7 @@ type (
 	}
 )
 
-func parsePairedOutputs(args []string, parseCurrency parseCurrencyString) (pairs []outputPair, err error) {
+func parsePairedOutputs(args []string, parseCurrency parseCurrencyString, bech32Prefix string) (pairs []outputPair, err error) {
 	argn := len(args)
 	if argn < 2 {
 		err = errors.New("not enough arguments, at least 2 required")
@@ -318,7 +318,7 @@ func parsePairedOutputs(args []string, parseCurrency parseCurrencyString) (pairs
 		}
 
 		// parse condition second
-		pair.Condition, err = parseConditionString(args[i])
+		pair.Condition, err = parseConditionString(args[i], bech32Prefix)
 		if err != nil {
 			err = fmt.Errorf("failed to parse condition for output #%d: %v", i/2, err)
 			return
@@ -16,14 +16,51 @@ import (
 )
 
 const (
-	pluginDBVersion = "1.0.0.0"
+	pluginDBVersion = "1.1.0.0"
 	pluginDBHeader  = "mintingPlugin"
+
+	// legacyPluginDBVersion is the version of the plugin DB,
+	// prior to tracking the minted supply
+	legacyPluginDBVersion = "1.0.0.0"
 )
 
 var (
 	bucketMintConditions = []byte("mintconditions")
+	bucketMintedSupply   = []byte("mintedsupply")
+
+	keyCreatedCoins   = []byte("created")
+	keyDestroyedCoins = []byte("destroyed")
+	// keyMintedSupplyIncomplete is set for a plugin DB upgraded from the legacy version,
+	// as the coins created and destroyed prior to the upgrade are not known
+	keyMintedSupplyIncomplete = []byte("incomplete")
+)
+
+var (
+	// ErrMintedSupplyUnknown is returned in case the minted supply is requested
+	// from a plugin DB which was upgraded from a version that did not track the minted supply yet.
+	// Resyncing the consensus database resolves this.
+	ErrMintedSupplyUnknown = errors.New("minted supply is unknown: plugin DB was created prior to minted supply tracking, resync the consensus to track it")
 )
 
+type (
+	// MintedSupply defines the total amount of coins created and destroyed
+	// using the minting transactions, in the current path of the consensus.
+	MintedSupply struct {
+		Created   types.Currency `json:"created"`
+		Destroyed types.Currency `json:"destroyed"`
+	}
+)
+
+// Net returns the coins added to the coin supply by the minting transactions,
+// which is the amount of coins created minus the amount of coins destroyed.
+// Zero is returned in case more coins were destroyed than created.
+func (ms MintedSupply) Net() types.Currency {
+	if ms.Destroyed.Cmp(ms.Created) >= 0 {
+		return types.ZeroCurrency
+	}
+	return ms.Created.Sub(ms.Destroyed)
+}
+
 type (
 	// Plugin is a struct defines the minting plugin
 	Plugin struct {
@@ -72,8 +109,8 @@ func NewMintingPlugin(genesisMintCondition types.UnlockConditionProxy, minterDef
 			types.RegisterTransactionVersion(opts.CoinDestructionTransactionVersion, CoinDestructionTransactionController{
 				TransactionVersion: opts.CoinDestructionTransactionVersion,
 			})
+			p.coinDestructionTransactionVersion = &opts.CoinDestructionTransactionVersion
 		}
-		p.coinDestructionTransactionVersion = &opts.CoinDestructionTransactionVersion
 		legacyEncoding = opts.UseLegacySiaEncoding
 	}
 	if legacyEncoding {
@@ -108,6 +145,27 @@ func (p *Plugin) InitPlugin(metadata *persist.Metadata, bucket *bolt.Bucket, sto
 		if err != nil {
 			return persist.Metadata{}, fmt.Errorf("failed to store genesis mint condition: %v", err)
 		}
+		if bucket.Bucket(bucketMintedSupply) == nil {
+			_, err = bucket.CreateBucket(bucketMintedSupply)
+			if err != nil {
+				return persist.Metadata{}, fmt.Errorf("failed to create mintedsupply bucket: %v", err)
+			}
+		}
+		metadata = &persist.Metadata{
+			Version: pluginDBVersion,
+			Header:  pluginDBHeader,
+		}
+	} else if metadata.Version == legacyPluginDBVersion {
+		// track the minted supply from now on,
+		// marking it as incomplete as the past minting transactions are not known
+		supplyBucket, err := bucket.CreateBucketIfNotExists(bucketMintedSupply)
+		if err != nil {
+			return persist.Metadata{}, fmt.Errorf("failed to create mintedsupply bucket: %v", err)
+		}
+		err = supplyBucket.Put(keyMintedSupplyIncomplete, []byte{1})
+		if err != nil {
+			return persist.Metadata{}, fmt.Errorf("failed to mark minted supply as incomplete: %v", err)
+		}
 		metadata = &persist.Metadata{
 			Version: pluginDBVersion,
 			Header:  pluginDBHeader,
@@ -172,6 +230,18 @@ func (p *Plugin) ApplyTransaction(txn modules.ConsensusTransaction, bucket *pers
 				"failed to put mint condition for block height %d: %v",
 				txn.BlockHeight, err)
 		}
+
+	case p.coinCreationTransactionVersion:
+		return p.updateMintedCoins(bucket, keyCreatedCoins, txn.CoinOutputSum(), modules.DiffApply)
+
+	default:
+		if p.isCoinDestructionTransaction(txn) {
+			destroyed, err := coinDestructionValue(txn)
+			if err != nil {
+				return err
+			}
+			return p.updateMintedCoins(bucket, keyDestroyedCoins, destroyed, modules.DiffApply)
+		}
 	}
 	return nil
 }
@@ -224,10 +294,124 @@ func (p *Plugin) RevertTransaction(txn modules.ConsensusTransaction, bucket *per
 				"failed to delete mint condition for block height %d: %v",
 				txn.BlockHeight, err)
 		}
+
+	case p.coinCreationTransactionVersion:
+		return p.updateMintedCoins(bucket, keyCreatedCoins, txn.CoinOutputSum(), modules.DiffRevert)
+
+	default:
+		if p.isCoinDestructionTransaction(txn) {
+			destroyed, err := coinDestructionValue(txn)
+			if err != nil {
+				return err
+			}
+			return p.updateMintedCoins(bucket, keyDestroyedCoins, destroyed, modules.DiffRevert)
+		}
 	}
 	return nil
 }
 
+func (p *Plugin) isCoinDestructionTransaction(txn modules.ConsensusTransaction) bool {
+	return p.coinDestructionTransactionVersion != nil && txn.Version == *p.coinDestructionTransactionVersion
+}
+
+// coinDestructionValue returns the amount of coins destroyed by a coin destruction transaction,
+// being the sum of its coin inputs minus the sum of its coin outputs and miner fees.
+func coinDestructionValue(txn modules.ConsensusTransaction) (types.Currency, error) {
+	var coinInputSum types.Currency
+	for _, ci := range txn.CoinInputs {
+		co, ok := txn.SpentCoinOutputs[ci.ParentID]
+		if !ok {
+			return types.Currency{}, fmt.Errorf(
+				"unable to find parent ID %s as a spent coin output of coin destruction tx %s",
+				ci.ParentID.String(), txn.ID().String())
+		}
+		coinInputSum = coinInputSum.Add(co.Value)
+	}
+	coinOutputSum := txn.CoinOutputSum()
+	if coinOutputSum.Cmp(coinInputSum) > 0 {
+		return types.Currency{}, fmt.Errorf(
+			"coin destruction tx %s has more coin outputs (%s) than coin inputs (%s)",
+			txn.ID().String(), coinOutputSum.String(), coinInputSum.String())
+	}
+	return coinInputSum.Sub(coinOutputSum), nil
+}
+
+// updateMintedCoins applies or reverts the given amount of coins
+// to/from the minted supply total stored under the given key.
+func (p *Plugin) updateMintedCoins(bucket *persist.LazyBoltBucket, key []byte, value types.Currency, dir modules.DiffDirection) error {
+	if value.IsZero() {
+		return nil
+	}
+	supplyBucket, err := bucket.Bucket(bucketMintedSupply)
+	if err != nil {
+		return errors.New("mintedsupply bucket does not exist")
+	}
+	total, err := p.getMintedCoins(supplyBucket, key)
+	if err != nil {
+		return err
+	}
+	switch {
+	case dir == modules.DiffApply:
+		total = total.Add(value)
+	case total.Cmp(value) >= 0:
+		total = total.Sub(value)
+	case supplyBucket.Get(keyMintedSupplyIncomplete) != nil:
+		// coins minted prior to the plugin DB upgrade are not tracked
+		total = types.ZeroCurrency
+	default:
+		return fmt.Errorf("corrupt plugin DB: cannot revert %s %s coins from a total of %s", value.String(), string(key), total.String())
+	}
+	b, err := p.binMarshal(total)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s coins total: %v", string(key), err)
+	}
+	err = supplyBucket.Put(key, b)
+	if err != nil {
+		return fmt.Errorf("failed to put %s coins total: %v", string(key), err)
+	}
+	return nil
+}
+
+func (p *Plugin) getMintedCoins(supplyBucket *bolt.Bucket, key []byte) (types.Currency, error) {
+	b := supplyBucket.Get(key)
+	if len(b) == 0 {
+		return types.ZeroCurrency, nil
+	}
+	var total types.Currency
+	err := p.binUnmarshal(b, &total)
+	if err != nil {
+		return types.Currency{}, fmt.Errorf("corrupt plugin DB: failed to decode %s coins total: %v", string(key), err)
+	}
+	return total, nil
+}
+
+// GetMintedSupply returns the total amount of coins created and destroyed
+// using the minting transactions, in the current path of the consensus.
+// ErrMintedSupplyUnknown is returned in case the plugin DB was upgraded
+// from a version that did not track the minted supply yet.
+func (p *Plugin) GetMintedSupply() (MintedSupply, error) {
+	var supply MintedSupply
+	err := p.storage.View(func(bucket *bolt.Bucket) (err error) {
+		supplyBucket := bucket.Bucket(bucketMintedSupply)
+		if supplyBucket == nil {
+			return errors.New("no minted supply bucket found")
+		}
+		if supplyBucket.Get(keyMintedSupplyIncomplete) != nil {
+			return ErrMintedSupplyUnknown
+		}
+		supply.Created, err = p.getMintedCoins(supplyBucket, keyCreatedCoins)
+		if err != nil {
+			return err
+		}
+		supply.Destroyed, err = p.getMintedCoins(supplyBucket, keyDestroyedCoins)
+		return err
+	})
+	if err != nil {
+		return MintedSupply{}, err
+	}
+	return supply, nil
+}
+
 // GetActiveMintCondition implements types.MintConditionGetter.GetActiveMintCondition
 func (p *Plugin) GetActiveMintCondition() (types.UnlockConditionProxy, error) {
 	var mintCondition types.UnlockConditionProxy
@@ -0,0 +1,176 @@
+package minting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	testMinterDefinitionTxVersion types.TransactionVersion = 0xa1
+	testCoinCreationTxVersion     types.TransactionVersion = 0xa2
+	testCoinDestructionTxVersion  types.TransactionVersion = 0xa3
+)
+
+// testPluginStorage gives view access to the bucket of a plugin.
+type testPluginStorage struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+func (s testPluginStorage) View(callback func(bucket *bolt.Bucket) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return callback(tx.Bucket(s.bucket))
+	})
+}
+
+func (s testPluginStorage) Close() error { return nil }
+
+// newTestPlugin creates a minting plugin, initialized using the given metadata in a new bucket of the given DB.
+func newTestPlugin(t *testing.T, db *bolt.DB, name string, metadata *persist.Metadata) *Plugin {
+	p := NewMintingPlugin(types.NewCondition(types.NewUnlockHashCondition(types.UnlockHash{})),
+		testMinterDefinitionTxVersion, testCoinCreationTxVersion, &PluginOptions{
+			CoinDestructionTransactionVersion: testCoinDestructionTxVersion,
+		})
+	err := db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte(name))
+		if err != nil {
+			return err
+		}
+		_, err = p.InitPlugin(metadata, bucket, testPluginStorage{db: db, bucket: []byte(name)}, nil)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// updateTestPlugin calls the given function with the bucket of the plugin, within an update transaction.
+func updateTestPlugin(db *bolt.DB, name string, fn func(bucket *persist.LazyBoltBucket) error) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		return fn(persist.NewLazyBoltBucket(func() (*bolt.Bucket, error) {
+			return tx.Bucket([]byte(name)), nil
+		}))
+	})
+}
+
+func openTestDB(t *testing.T) *bolt.DB {
+	testDir := build.TempDir("minting", t.Name())
+	if err := os.MkdirAll(testDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(testDir, "consensus.db"), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMintedSupply(t *testing.T) {
+	defer func() {
+		for _, version := range []types.TransactionVersion{testMinterDefinitionTxVersion, testCoinCreationTxVersion, testCoinDestructionTxVersion} {
+			types.RegisterTransactionVersion(version, nil)
+		}
+	}()
+	db := openTestDB(t)
+	defer db.Close()
+	p := newTestPlugin(t, db, "minting", nil)
+
+	creation := modules.ConsensusTransaction{Transaction: types.Transaction{
+		Version:     testCoinCreationTxVersion,
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(100)}, {Value: types.NewCurrency64(50)}},
+	}}
+	destruction := modules.ConsensusTransaction{
+		Transaction: types.Transaction{
+			Version:     testCoinDestructionTxVersion,
+			CoinInputs:  []types.CoinInput{{ParentID: types.CoinOutputID{1}}},
+			CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(10)}},
+		},
+		SpentCoinOutputs: map[types.CoinOutputID]types.CoinOutput{
+			{1}: {Value: types.NewCurrency64(40)},
+		},
+	}
+	expectSupply := func(created, destroyed, net uint64) {
+		t.Helper()
+		supply, err := p.GetMintedSupply()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !supply.Created.Equals64(created) || !supply.Destroyed.Equals64(destroyed) || !supply.Net().Equals64(net) {
+			t.Errorf("unexpected minted supply: %v created, %v destroyed, %v net", supply.Created, supply.Destroyed, supply.Net())
+		}
+	}
+
+	expectSupply(0, 0, 0)
+	for _, txn := range []modules.ConsensusTransaction{creation, destruction} {
+		if err := updateTestPlugin(db, "minting", func(bucket *persist.LazyBoltBucket) error {
+			return p.ApplyTransaction(txn, bucket)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectSupply(150, 30, 120)
+
+	if err := updateTestPlugin(db, "minting", func(bucket *persist.LazyBoltBucket) error {
+		return p.RevertTransaction(destruction, bucket)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expectSupply(150, 0, 150)
+
+	// coins which weren't destroyed can't be reverted
+	if err := updateTestPlugin(db, "minting", func(bucket *persist.LazyBoltBucket) error {
+		return p.RevertTransaction(destruction, bucket)
+	}); err == nil {
+		t.Error("reverted more destroyed coins than tracked")
+	}
+	// the spent coin outputs are required to know the amount of coins destroyed
+	invalid := destruction
+	invalid.SpentCoinOutputs = nil
+	if err := updateTestPlugin(db, "minting", func(bucket *persist.LazyBoltBucket) error {
+		return p.ApplyTransaction(invalid, bucket)
+	}); err == nil {
+		t.Error("applied coin destruction without its spent coin outputs")
+	}
+	expectSupply(150, 0, 150)
+
+	if net := (MintedSupply{Created: types.NewCurrency64(10), Destroyed: types.NewCurrency64(20)}).Net(); !net.IsZero() {
+		t.Errorf("expected zero net supply when more coins are destroyed than created, not %v", net)
+	}
+}
+
+func TestMintedSupplyLegacyPluginDB(t *testing.T) {
+	defer func() {
+		for _, version := range []types.TransactionVersion{testMinterDefinitionTxVersion, testCoinCreationTxVersion, testCoinDestructionTxVersion} {
+			types.RegisterTransactionVersion(version, nil)
+		}
+	}()
+	db := openTestDB(t)
+	defer db.Close()
+	p := newTestPlugin(t, db, "minting", &persist.Metadata{Version: legacyPluginDBVersion, Header: pluginDBHeader})
+
+	// the minted supply is unknown for an upgraded plugin DB
+	if _, err := p.GetMintedSupply(); err != ErrMintedSupplyUnknown {
+		t.Errorf("expected %v, not %v", ErrMintedSupplyUnknown, err)
+	}
+
+	// coins created prior to the upgrade can be reverted
+	creation := modules.ConsensusTransaction{Transaction: types.Transaction{
+		Version:     testCoinCreationTxVersion,
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(100)}},
+	}}
+	if err := updateTestPlugin(db, "minting", func(bucket *persist.LazyBoltBucket) error {
+		return p.RevertTransaction(creation, bucket)
+	}); err != nil {
+		t.Error("failed to revert coins created prior to the upgrade:", err)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/threefoldtech/rivine/extensions/names"
+	rapi "github.com/threefoldtech/rivine/pkg/api"
+)
+
+// GetNameResponse contains the info of a requested (registered) name.
+type GetNameResponse struct {
+	Name string         `json:"name"`
+	Info names.NameInfo `json:"info"`
+}
+
+// RegisterConsensusNamesHTTPHandlers registers the name handlers for the Rivine Consensus HTTP endpoints.
+func RegisterConsensusNamesHTTPHandlers(router rapi.Router, plugin *names.Plugin) {
+	registerNamesHTTPHandlers(router, "/consensus", plugin)
+}
+
+// RegisterExplorerNamesHTTPHandlers registers the name handlers for the Rivine Explorer HTTP endpoints.
+func RegisterExplorerNamesHTTPHandlers(router rapi.Router, plugin *names.Plugin) {
+	registerNamesHTTPHandlers(router, "/explorer", plugin)
+}
+
+func registerNamesHTTPHandlers(router rapi.Router, root string, plugin *names.Plugin) {
+	router.GET(root+"/names/:name", NewGetNameHandler(plugin))
+}
+
+// NewGetNameHandler creates a handler to handle the API calls to /<root>/names/:name.
+func NewGetNameHandler(plugin *names.Plugin) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		name := ps.ByName("name")
+		err := names.ValidateName(name)
+		if err != nil {
+			rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		info, err := plugin.GetNameInfo(name)
+		if err != nil {
+			if err == names.ErrNameNotFound {
+				rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusNoContent)
+				return
+			}
+			rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		rapi.WriteJSON(w, GetNameResponse{
+			Name: name,
+			Info: info,
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package names
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	pluginDBVersion = "1.0.0.0"
+	pluginDBHeader  = "NamesPlugin"
+)
+
+var (
+	bucketNames = []byte("names")
+	// bucketNameHistory stores the previous info of a name,
+	// keyed by the ID of the transaction that updated or transferred it,
+	// such that it can be restored when that transaction gets reverted
+	bucketNameHistory = []byte("namehistory")
+)
+
+type (
+	// Plugin is a struct defines the name registry plugin
+	Plugin struct {
+		nameRegistrationTransactionVersion types.TransactionVersion
+		nameUpdateTransactionVersion       types.TransactionVersion
+		nameTransferTransactionVersion     types.TransactionVersion
+		storage                            modules.PluginViewStorage
+		unregisterCallback                 modules.PluginUnregisterCallback
+	}
+)
+
+// NewPlugin creates a new Plugin using the given transaction versions,
+// registering the transaction controllers of all three name transaction types.
+func NewPlugin(nameRegistrationTransactionVersion, nameUpdateTransactionVersion, nameTransferTransactionVersion types.TransactionVersion) *Plugin {
+	p := &Plugin{
+		nameRegistrationTransactionVersion: nameRegistrationTransactionVersion,
+		nameUpdateTransactionVersion:       nameUpdateTransactionVersion,
+		nameTransferTransactionVersion:     nameTransferTransactionVersion,
+	}
+	types.RegisterTransactionVersion(nameRegistrationTransactionVersion, NameRegistrationTransactionController{
+		TransactionVersion: nameRegistrationTransactionVersion,
+	})
+	types.RegisterTransactionVersion(nameUpdateTransactionVersion, NameUpdateTransactionController{
+		NameInfoGetter:     p,
+		TransactionVersion: nameUpdateTransactionVersion,
+	})
+	types.RegisterTransactionVersion(nameTransferTransactionVersion, NameTransferTransactionController{
+		NameInfoGetter:     p,
+		TransactionVersion: nameTransferTransactionVersion,
+	})
+	return p
+}
+
+// InitPlugin initializes the Bucket for the first time
+func (p *Plugin) InitPlugin(metadata *persist.Metadata, bucket *bolt.Bucket, storage modules.PluginViewStorage, unregisterCallback modules.PluginUnregisterCallback) (persist.Metadata, error) {
+	p.storage = storage
+	p.unregisterCallback = unregisterCallback
+	if metadata == nil {
+		for _, name := range [][]byte{bucketNames, bucketNameHistory} {
+			if bucket.Bucket(name) != nil {
+				continue
+			}
+			_, err := bucket.CreateBucket(name)
+			if err != nil {
+				return persist.Metadata{}, fmt.Errorf("failed to create %s bucket: %v", string(name), err)
+			}
+		}
+		metadata = &persist.Metadata{
+			Version: pluginDBVersion,
+			Header:  pluginDBHeader,
+		}
+	} else if metadata.Version != pluginDBVersion {
+		return persist.Metadata{}, errors.New("There is only 1 version of this plugin, version mismatch")
+	} else if metadata.Header != pluginDBHeader {
+		return persist.Metadata{}, errors.New("There is only 1 header of this plugin, header mismatch")
+	}
+	return *metadata, nil
+}
+
+// ApplyBlock applies a block's name transactions to the name buckets.
+func (p *Plugin) ApplyBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	for idx, txn := range block.Transactions {
+		cTxn := modules.ConsensusTransaction{
+			Transaction:            txn,
+			BlockHeight:            block.Height,
+			BlockTime:              block.Timestamp,
+			SequenceID:             uint16(idx),
+			SpentCoinOutputs:       block.SpentCoinOutputs,
+			SpentBlockStakeOutputs: block.SpentBlockStakeOutputs,
+		}
+		err = p.ApplyTransaction(cTxn, bucket)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyTransaction applies a name transaction to the name buckets.
+func (p *Plugin) ApplyTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	// check the version and handle the ones we care about
+	switch txn.Version {
+	case p.nameRegistrationTransactionVersion:
+		nrtx, err := NameRegistrationTransactionFromTransaction(txn.Transaction, p.nameRegistrationTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name registration tx type: %v", err)
+		}
+		namesBucket, err := bucket.Bucket(bucketNames)
+		if err != nil {
+			return errors.New("names bucket does not exist")
+		}
+		return putNameInfo(namesBucket, nrtx.Name, NameInfo{
+			Owner:              nrtx.Owner,
+			Data:               nrtx.Data,
+			RegistrationHeight: txn.BlockHeight,
+			UpdateHeight:       txn.BlockHeight,
+		})
+
+	case p.nameUpdateTransactionVersion:
+		nutx, err := NameUpdateTransactionFromTransaction(txn.Transaction, p.nameUpdateTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name update tx type: %v", err)
+		}
+		return p.applyNameChange(bucket, txn, nutx.Name, func(info *NameInfo) {
+			info.Data = nutx.Data
+		})
+
+	case p.nameTransferTransactionVersion:
+		nttx, err := NameTransferTransactionFromTransaction(txn.Transaction, p.nameTransferTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name transfer tx type: %v", err)
+		}
+		return p.applyNameChange(bucket, txn, nttx.Name, func(info *NameInfo) {
+			info.Owner = nttx.NewOwner
+		})
+	}
+	return nil
+}
+
+// applyNameChange changes the info of a registered name,
+// storing its previous info in the history bucket.
+func (p *Plugin) applyNameChange(bucket *persist.LazyBoltBucket, txn modules.ConsensusTransaction, name string, change func(*NameInfo)) error {
+	namesBucket, err := bucket.Bucket(bucketNames)
+	if err != nil {
+		return errors.New("names bucket does not exist")
+	}
+	historyBucket, err := bucket.Bucket(bucketNameHistory)
+	if err != nil {
+		return errors.New("name history bucket does not exist")
+	}
+	info, err := getNameInfo(namesBucket, name)
+	if err != nil {
+		return err
+	}
+	b, err := rivbin.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to (rivbin) marshal previous info of name %q: %v", name, err)
+	}
+	txID := txn.ID()
+	err = historyBucket.Put(txID[:], b)
+	if err != nil {
+		return fmt.Errorf("failed to put previous info of name %q: %v", name, err)
+	}
+	change(&info)
+	info.UpdateHeight = txn.BlockHeight
+	return putNameInfo(namesBucket, name, info)
+}
+
+// RevertBlock reverts a block's name transactions from the name buckets.
+func (p *Plugin) RevertBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	// revert in reverse order, as a name can be changed multiple times within the same block
+	for idx := len(block.Transactions) - 1; idx >= 0; idx-- {
+		cTxn := modules.ConsensusTransaction{
+			Transaction:            block.Transactions[idx],
+			BlockHeight:            block.Height,
+			BlockTime:              block.Timestamp,
+			SequenceID:             uint16(idx),
+			SpentCoinOutputs:       block.SpentCoinOutputs,
+			SpentBlockStakeOutputs: block.SpentBlockStakeOutputs,
+		}
+		err = p.RevertTransaction(cTxn, bucket)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevertTransaction reverts a name transaction from the name buckets.
+func (p *Plugin) RevertTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	// check the version and handle the ones we care about
+	switch txn.Version {
+	case p.nameRegistrationTransactionVersion:
+		nrtx, err := NameRegistrationTransactionFromTransaction(txn.Transaction, p.nameRegistrationTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name registration tx type: %v", err)
+		}
+		namesBucket, err := bucket.Bucket(bucketNames)
+		if err != nil {
+			return errors.New("names bucket does not exist")
+		}
+		err = namesBucket.Delete([]byte(nrtx.Name))
+		if err != nil {
+			return fmt.Errorf("failed to delete name %q: %v", nrtx.Name, err)
+		}
+
+	case p.nameUpdateTransactionVersion:
+		nutx, err := NameUpdateTransactionFromTransaction(txn.Transaction, p.nameUpdateTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name update tx type: %v", err)
+		}
+		return p.revertNameChange(bucket, txn.ID(), nutx.Name)
+
+	case p.nameTransferTransactionVersion:
+		nttx, err := NameTransferTransactionFromTransaction(txn.Transaction, p.nameTransferTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the name transfer tx type: %v", err)
+		}
+		return p.revertNameChange(bucket, txn.ID(), nttx.Name)
+	}
+	return nil
+}
+
+// revertNameChange restores the info of a name, as it was prior to the given transaction.
+func (p *Plugin) revertNameChange(bucket *persist.LazyBoltBucket, txID types.TransactionID, name string) error {
+	namesBucket, err := bucket.Bucket(bucketNames)
+	if err != nil {
+		return errors.New("names bucket does not exist")
+	}
+	historyBucket, err := bucket.Bucket(bucketNameHistory)
+	if err != nil {
+		return errors.New("name history bucket does not exist")
+	}
+	b := historyBucket.Get(txID[:])
+	if len(b) == 0 {
+		return fmt.Errorf("corrupt plugin DB: previous info of name %q could not be found for tx %s", name, txID.String())
+	}
+	// copy the value, as it is only valid for the lifetime of the transaction
+	// and the deletion below invalidates it
+	value := make([]byte, len(b))
+	copy(value, b)
+	err = historyBucket.Delete(txID[:])
+	if err != nil {
+		return fmt.Errorf("failed to delete previous info of name %q: %v", name, err)
+	}
+	err = namesBucket.Put([]byte(name), value)
+	if err != nil {
+		return fmt.Errorf("failed to restore info of name %q: %v", name, err)
+	}
+	return nil
+}
+
+// GetNameInfo implements NameInfoGetter.GetNameInfo
+func (p *Plugin) GetNameInfo(name string) (NameInfo, error) {
+	var info NameInfo
+	err := p.storage.View(func(bucket *bolt.Bucket) (err error) {
+		namesBucket := bucket.Bucket(bucketNames)
+		if namesBucket == nil {
+			return errors.New("names bucket could not be found")
+		}
+		info, err = getNameInfo(namesBucket, name)
+		return err
+	})
+	if err != nil {
+		return NameInfo{}, err
+	}
+	return info, nil
+}
+
+// TransactionValidatorVersionFunctionMapping returns all tx validators for specific tx versions linked to this plugin
+func (p *Plugin) TransactionValidatorVersionFunctionMapping() map[types.TransactionVersion][]modules.PluginTransactionValidationFunction {
+	return map[types.TransactionVersion][]modules.PluginTransactionValidationFunction{
+		p.nameRegistrationTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateNameRegistrationTx,
+			validateNameTransactionCoinFlowIsBalanced,
+		},
+		p.nameUpdateTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateNameUpdateTx,
+			validateNameTransactionCoinFlowIsBalanced,
+		},
+		p.nameTransferTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateNameTransferTx,
+			validateNameTransactionCoinFlowIsBalanced,
+		},
+	}
+}
+
+// TransactionValidators returns all tx validators linked to this plugin
+func (p *Plugin) TransactionValidators() []modules.PluginTransactionValidationFunction {
+	return nil
+}
+
+func (p *Plugin) validateNameRegistrationTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	nrtx, err := NameRegistrationTransactionFromTransaction(tx.Transaction, p.nameRegistrationTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a name registration tx: %v", err)
+	}
+
+	err = ValidateName(nrtx.Name)
+	if err != nil {
+		return err
+	}
+	err = validateNameData(nrtx.Data)
+	if err != nil {
+		return err
+	}
+	err = validateOwnerCondition(nrtx.Owner, ctx)
+	if err != nil {
+		return err
+	}
+
+	namesBucket, err := bucket.Bucket(bucketNames)
+	if err != nil {
+		return err
+	}
+	_, err = getNameInfo(namesBucket, nrtx.Name)
+	if err == nil {
+		return types.NewClientError(fmt.Errorf("cannot register name %q: %v", nrtx.Name, ErrNameAlreadyRegistered), types.ClientErrorForbidden)
+	}
+	if err != ErrNameNotFound {
+		return err
+	}
+	return nil
+}
+
+func (p *Plugin) validateNameUpdateTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	nutx, err := NameUpdateTransactionFromTransaction(tx.Transaction, p.nameUpdateTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a name update tx: %v", err)
+	}
+	err = validateNameData(nutx.Data)
+	if err != nil {
+		return err
+	}
+	return validateOwnerFulfillment(tx, ctx, bucket, nutx.Name, nutx.OwnerFulfillment)
+}
+
+func (p *Plugin) validateNameTransferTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	nttx, err := NameTransferTransactionFromTransaction(tx.Transaction, p.nameTransferTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a name transfer tx: %v", err)
+	}
+	err = validateOwnerCondition(nttx.NewOwner, ctx)
+	if err != nil {
+		return err
+	}
+	return validateOwnerFulfillment(tx, ctx, bucket, nttx.Name, nttx.OwnerFulfillment)
+}
+
+// validateOwnerFulfillment ensures the given fulfillment fulfills
+// the owner condition of the given (registered) name.
+func validateOwnerFulfillment(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket, name string, fulfillment types.UnlockFulfillmentProxy) error {
+	namesBucket, err := bucket.Bucket(bucketNames)
+	if err != nil {
+		return err
+	}
+	info, err := getNameInfo(namesBucket, name)
+	if err != nil {
+		return fmt.Errorf("cannot change name %q: %v", name, err)
+	}
+	err = fulfillment.IsStandardFulfillment(ctx.ValidationContext)
+	if err != nil {
+		return err
+	}
+	err = info.Owner.Fulfill(fulfillment, types.FulfillContext{
+		BlockHeight: ctx.BlockHeight,
+		BlockTime:   ctx.BlockTime,
+		Transaction: tx.Transaction,
+	})
+	if err != nil {
+		return types.NewClientError(fmt.Errorf("failed to fulfill owner condition of name %q: %v", name, err), types.ClientErrorUnauthorized)
+	}
+	return nil
+}
+
+// validateOwnerCondition ensures the owner condition of a name is a standard, non-nil, condition,
+// as a name owned by a nil condition could be claimed by anyone.
+func validateOwnerCondition(condition types.UnlockConditionProxy, ctx types.TransactionValidationContext) error {
+	if condition.ConditionType() == types.ConditionTypeNil {
+		return errors.New("the owner condition of a name cannot be a nil condition")
+	}
+	err := condition.IsStandardCondition(ctx.ValidationContext)
+	if err != nil {
+		return fmt.Errorf("invalid owner condition: %v", err)
+	}
+	return nil
+}
+
+// validateNameTransactionCoinFlowIsBalanced ensures that the coin inputs of a name transaction
+// equal the sum of its optional refund coin output and miner fees.
+func validateNameTransactionCoinFlowIsBalanced(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	var coinInputSum types.Currency
+	for _, ci := range tx.CoinInputs {
+		co, ok := tx.SpentCoinOutputs[ci.ParentID]
+		if !ok {
+			return fmt.Errorf(
+				"unable to find parent ID %s as an unspent coin output in the current consensus transaction at block height %d",
+				ci.ParentID.String(), ctx.BlockHeight)
+		}
+		coinInputSum = coinInputSum.Add(co.Value)
+	}
+	if coinOutputSum := tx.CoinOutputSum(); !coinInputSum.Equals(coinOutputSum) {
+		return fmt.Errorf(
+			"unbalanced coin outputs: the sum of coin inputs (%s) for tx %s does not equal its sum of coin outputs (%s)",
+			coinInputSum.String(), tx.ID().String(), coinOutputSum.String())
+	}
+	return nil
+}
+
+// Close unregisters the plugin from the consensus
+func (p *Plugin) Close() error {
+	return p.storage.Close()
+}
+
+func getNameInfo(namesBucket *bolt.Bucket, name string) (NameInfo, error) {
+	b := namesBucket.Get([]byte(name))
+	if len(b) == 0 {
+		return NameInfo{}, ErrNameNotFound
+	}
+	var info NameInfo
+	err := rivbin.Unmarshal(b, &info)
+	if err != nil {
+		return NameInfo{}, fmt.Errorf("corrupt plugin DB: failed to decode info of name %q: %v", name, err)
+	}
+	return info, nil
+}
+
+func putNameInfo(namesBucket *bolt.Bucket, name string, info NameInfo) error {
+	b, err := rivbin.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to (rivbin) marshal info of name %q: %v", name, err)
+	}
+	err = namesBucket.Put([]byte(name), b)
+	if err != nil {
+		return fmt.Errorf("failed to put info of name %q: %v", name, err)
+	}
+	return nil
+}
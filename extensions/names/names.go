@@ -0,0 +1,84 @@
+package names
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierNameRegistrationTransaction = types.Specifier{'n', 'a', 'm', 'e', ' ', 'r', 'e', 'g', 'i', 's', 't', 'e', 'r'}
+	SpecifierNameUpdateTransaction       = types.Specifier{'n', 'a', 'm', 'e', ' ', 'u', 'p', 'd', 'a', 't', 'e'}
+	SpecifierNameTransferTransaction     = types.Specifier{'n', 'a', 'm', 'e', ' ', 't', 'r', 'a', 'n', 's', 'f', 'e', 'r'}
+)
+
+const (
+	// MaxNameLength defines the maximum length (in bytes) of a name.
+	MaxNameLength = 64
+	// MaxNameDataLength defines the maximum length (in bytes)
+	// of the payload that can be bound to a name.
+	MaxNameDataLength = 256
+)
+
+var (
+	// ErrNameNotFound is returned in case a name is looked up,
+	// which was never registered (or is reverted since).
+	ErrNameNotFound = errors.New("name not found")
+	// ErrNameAlreadyRegistered is returned in case a name is registered,
+	// which is already registered.
+	ErrNameAlreadyRegistered = errors.New("name is already registered")
+)
+
+type (
+	// NameInfo defines a registered name, the condition of its owner,
+	// as well as the (small) payload bound to it.
+	NameInfo struct {
+		// Owner defines the condition that has to be fulfilled
+		// in order to update or transfer the name.
+		Owner types.UnlockConditionProxy `json:"owner"`
+		// Data defines the optional payload bound to the name.
+		Data []byte `json:"data,omitempty"`
+		// RegistrationHeight defines the block height at which the name was registered.
+		RegistrationHeight types.BlockHeight `json:"registrationheight"`
+		// UpdateHeight defines the block height at which the name was last updated or transferred.
+		UpdateHeight types.BlockHeight `json:"updateheight"`
+	}
+
+	// NameInfoGetter allows you to look up the registered names,
+	// as currently tracked by the consensus.
+	NameInfoGetter interface {
+		// GetNameInfo returns the info of a registered name.
+		GetNameInfo(name string) (NameInfo, error)
+	}
+)
+
+// ValidateName validates a name, which has to be between 1 and MaxNameLength bytes long,
+// and can only contain lowercase ASCII letters, digits, '-', '_' and '.' characters.
+// The first and last character have to be a letter or digit.
+func ValidateName(name string) error {
+	if l := len(name); l == 0 || l > MaxNameLength {
+		return fmt.Errorf("name has to be between 1 and %d bytes long, %d bytes is invalid", MaxNameLength, l)
+	}
+	for idx := 0; idx < len(name); idx++ {
+		c := name[idx]
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			continue
+		}
+		if (c == '-' || c == '_' || c == '.') && idx != 0 && idx != len(name)-1 {
+			continue
+		}
+		return fmt.Errorf("name %q contains invalid character %q at position %d", name, c, idx)
+	}
+	return nil
+}
+
+// validateNameData ensures the payload bound to a name does not exceed MaxNameDataLength.
+func validateNameData(data []byte) error {
+	if l := len(data); l > MaxNameDataLength {
+		return fmt.Errorf("name data can be maximum %d bytes long, %d bytes is invalid", MaxNameDataLength, l)
+	}
+	return nil
+}
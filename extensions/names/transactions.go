@@ -0,0 +1,792 @@
+package names
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Name Registration									///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// NameRegistrationTransaction is to be used by anyone in order to register a name
+	// which isn't registered yet, binding it to an owner and an optional (small) payload.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	NameRegistrationTransaction struct {
+		// Name defines the name to register.
+		Name string `json:"name"`
+		// Owner defines the condition that has to be fulfilled
+		// in order to update or transfer the name.
+		Owner types.UnlockConditionProxy `json:"owner"`
+		// Data defines the optional payload bound to the name.
+		Data []byte `json:"data,omitempty"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this name registration transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// NameRegistrationTransactionExtension defines the NameRegistrationTransaction Extension Data
+	NameRegistrationTransactionExtension struct {
+		Name  string
+		Owner types.UnlockConditionProxy
+		Data  []byte
+	}
+)
+
+// NameRegistrationTransactionFromTransaction creates a NameRegistrationTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `NameRegistrationTransactionFromTransactionData` constructor.
+func NameRegistrationTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (NameRegistrationTransaction, error) {
+	if tx.Version != expectedVersion {
+		return NameRegistrationTransaction{}, fmt.Errorf(
+			"a name registration transaction requires tx version %d",
+			expectedVersion)
+	}
+	return NameRegistrationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// NameRegistrationTransactionFromTransactionData creates a NameRegistrationTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func NameRegistrationTransactionFromTransactionData(txData types.TransactionData) (NameRegistrationTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid NameRegistrationTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*NameRegistrationTransactionExtension)
+	if !ok {
+		return NameRegistrationTransaction{}, errors.New("invalid extension data for a NameRegistrationTransaction")
+	}
+	err := validateNameTransactionCoinFlow(txData, "NameRegistrationTransaction")
+	if err != nil {
+		return NameRegistrationTransaction{}, err
+	}
+	// return the NameRegistrationTransaction, with the data extracted from the TransactionData
+	return NameRegistrationTransaction{
+		Name:             extensionData.Name,
+		Owner:            extensionData.Owner,
+		Data:             extensionData.Data,
+		CoinInputs:       txData.CoinInputs,
+		RefundCoinOutput: refundCoinOutputFromTransactionData(txData),
+		MinerFees:        txData.MinerFees,
+		ArbitraryData:    txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this NameRegistrationTransaction
+// as regular rivine transaction data.
+func (nrtx *NameRegistrationTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    nrtx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(nrtx.RefundCoinOutput),
+		MinerFees:     nrtx.MinerFees,
+		ArbitraryData: nrtx.ArbitraryData,
+		Extension: &NameRegistrationTransactionExtension{
+			Name:  nrtx.Name,
+			Owner: nrtx.Owner,
+			Data:  nrtx.Data,
+		},
+	}
+}
+
+// Transaction returns this NameRegistrationTransaction
+// as regular rivine transaction, using the given version as the type.
+func (nrtx *NameRegistrationTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := nrtx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Name Registration							///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Name Registration Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = NameRegistrationTransactionController{}
+	_ types.TransactionSignatureHasher           = NameRegistrationTransactionController{}
+	_ types.TransactionIDEncoder                 = NameRegistrationTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = NameRegistrationTransactionController{}
+)
+
+type (
+	// NameRegistrationTransactionController defines a custom transaction controller,
+	// for a Name Registration Transaction. It allows the registration of a new name.
+	NameRegistrationTransactionController struct {
+		// TransactionVersion is used to validate/set the transaction version
+		// of a name registration transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (nrtc NameRegistrationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	nrtx, err := NameRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(nrtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (nrtc NameRegistrationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var nrtx NameRegistrationTransaction
+	err := rivbin.NewDecoder(r).Decode(&nrtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a NameRegistrationTx: %v", err)
+	}
+	// return name registration tx as regular rivine tx data
+	return nrtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (nrtc NameRegistrationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	nrtx, err := NameRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a NameRegistrationTx: %v", err)
+	}
+	return json.Marshal(nrtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (nrtc NameRegistrationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var nrtx NameRegistrationTransaction
+	err := json.Unmarshal(data, &nrtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a NameRegistrationTx: %v", err)
+	}
+	// return name registration tx as regular rivine tx data
+	return nrtx.TransactionData(), nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (nrtc NameRegistrationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	nrtx, err := NameRegistrationTransactionFromTransaction(t, nrtc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a name registration tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierNameRegistrationTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(nrtx.CoinInputs),
+		nrtx.Name,
+		nrtx.Owner,
+		nrtx.Data,
+		nrtx.RefundCoinOutput,
+		nrtx.MinerFees,
+		nrtx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (nrtc NameRegistrationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	nrtx, err := NameRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierNameRegistrationTransaction, nrtx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (nrtc NameRegistrationTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	nrTxExtension, ok := extension.(*NameRegistrationTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a NameRegistrationTransaction")
+	}
+	return types.CommonTransactionExtensionData{
+		UnlockConditions: []types.UnlockConditionProxy{nrTxExtension.Owner},
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Name Update											///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// NameUpdateTransaction is to be used by the owner of a name,
+	// as a medium in order to replace the payload bound to that name.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	NameUpdateTransaction struct {
+		// Name defines the (registered) name to update.
+		Name string `json:"name"`
+		// Data defines the new (optional) payload bound to the name,
+		// replacing the current payload.
+		Data []byte `json:"data,omitempty"`
+		// OwnerFulfillment defines the fulfillment which is used in order to
+		// fulfill the owner condition of the name.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this name update transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// NameUpdateTransactionExtension defines the NameUpdateTransaction Extension Data
+	NameUpdateTransactionExtension struct {
+		Name             string
+		Data             []byte
+		OwnerFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// NameUpdateTransactionFromTransaction creates a NameUpdateTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `NameUpdateTransactionFromTransactionData` constructor.
+func NameUpdateTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (NameUpdateTransaction, error) {
+	if tx.Version != expectedVersion {
+		return NameUpdateTransaction{}, fmt.Errorf(
+			"a name update transaction requires tx version %d",
+			expectedVersion)
+	}
+	return NameUpdateTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// NameUpdateTransactionFromTransactionData creates a NameUpdateTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func NameUpdateTransactionFromTransactionData(txData types.TransactionData) (NameUpdateTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid NameUpdateTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*NameUpdateTransactionExtension)
+	if !ok {
+		return NameUpdateTransaction{}, errors.New("invalid extension data for a NameUpdateTransaction")
+	}
+	err := validateNameTransactionCoinFlow(txData, "NameUpdateTransaction")
+	if err != nil {
+		return NameUpdateTransaction{}, err
+	}
+	// return the NameUpdateTransaction, with the data extracted from the TransactionData
+	return NameUpdateTransaction{
+		Name:             extensionData.Name,
+		Data:             extensionData.Data,
+		OwnerFulfillment: extensionData.OwnerFulfillment,
+		CoinInputs:       txData.CoinInputs,
+		RefundCoinOutput: refundCoinOutputFromTransactionData(txData),
+		MinerFees:        txData.MinerFees,
+		ArbitraryData:    txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this NameUpdateTransaction
+// as regular rivine transaction data.
+func (nutx *NameUpdateTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    nutx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(nutx.RefundCoinOutput),
+		MinerFees:     nutx.MinerFees,
+		ArbitraryData: nutx.ArbitraryData,
+		Extension: &NameUpdateTransactionExtension{
+			Name:             nutx.Name,
+			Data:             nutx.Data,
+			OwnerFulfillment: nutx.OwnerFulfillment,
+		},
+	}
+}
+
+// Transaction returns this NameUpdateTransaction
+// as regular rivine transaction, using the given version as the type.
+func (nutx *NameUpdateTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := nutx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Name Update									///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Name Update Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = NameUpdateTransactionController{}
+	_ types.TransactionExtensionSigner           = NameUpdateTransactionController{}
+	_ types.TransactionSignatureHasher           = NameUpdateTransactionController{}
+	_ types.TransactionIDEncoder                 = NameUpdateTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = NameUpdateTransactionController{}
+)
+
+type (
+	// NameUpdateTransactionController defines a custom transaction controller,
+	// for a Name Update Transaction. It allows the owner of a name to update its payload.
+	NameUpdateTransactionController struct {
+		// NameInfoGetter is used to get the owner condition of a name.
+		NameInfoGetter NameInfoGetter
+
+		// TransactionVersion is used to validate/set the transaction version
+		// of a name update transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (nutc NameUpdateTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	nutx, err := NameUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(nutx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (nutc NameUpdateTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var nutx NameUpdateTransaction
+	err := rivbin.NewDecoder(r).Decode(&nutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a NameUpdateTx: %v", err)
+	}
+	// return name update tx as regular rivine tx data
+	return nutx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (nutc NameUpdateTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	nutx, err := NameUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a NameUpdateTx: %v", err)
+	}
+	return json.Marshal(nutx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (nutc NameUpdateTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var nutx NameUpdateTransaction
+	err := json.Unmarshal(data, &nutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a NameUpdateTx: %v", err)
+	}
+	// return name update tx as regular rivine tx data
+	return nutx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (nutc NameUpdateTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid NameUpdateTransactionExtension,
+	// which contains the owner fulfillment that can be used to fulfill the owner condition of the name
+	nuTxExtension, ok := extension.(*NameUpdateTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a NameUpdateTransaction")
+	}
+
+	nameInfo, err := nutc.NameInfoGetter.GetNameInfo(nuTxExtension.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the info of name %q: %v", nuTxExtension.Name, err)
+	}
+	err = sign(&nuTxExtension.OwnerFulfillment, nameInfo.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign owner fulfillment of name update tx: %v", err)
+	}
+	return nuTxExtension, nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (nutc NameUpdateTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	nutx, err := NameUpdateTransactionFromTransaction(t, nutc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a name update tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierNameUpdateTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(nutx.CoinInputs),
+		nutx.Name,
+		nutx.Data,
+		nutx.RefundCoinOutput,
+		nutx.MinerFees,
+		nutx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (nutc NameUpdateTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	nutx, err := NameUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierNameUpdateTransaction, nutx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (nutc NameUpdateTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	_, ok := extension.(*NameUpdateTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a NameUpdateTransaction")
+	}
+	return types.CommonTransactionExtensionData{}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Name Transfer										///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// NameTransferTransaction is to be used by the owner of a name,
+	// as a medium in order to transfer the ownership of that name to a new owner.
+	// The payload bound to the name is kept as is.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	NameTransferTransaction struct {
+		// Name defines the (registered) name to transfer.
+		Name string `json:"name"`
+		// NewOwner defines the condition of the new owner of the name.
+		NewOwner types.UnlockConditionProxy `json:"newowner"`
+		// OwnerFulfillment defines the fulfillment which is used in order to
+		// fulfill the (current) owner condition of the name.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this name transfer transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// NameTransferTransactionExtension defines the NameTransferTransaction Extension Data
+	NameTransferTransactionExtension struct {
+		Name             string
+		NewOwner         types.UnlockConditionProxy
+		OwnerFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// NameTransferTransactionFromTransaction creates a NameTransferTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `NameTransferTransactionFromTransactionData` constructor.
+func NameTransferTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (NameTransferTransaction, error) {
+	if tx.Version != expectedVersion {
+		return NameTransferTransaction{}, fmt.Errorf(
+			"a name transfer transaction requires tx version %d",
+			expectedVersion)
+	}
+	return NameTransferTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// NameTransferTransactionFromTransactionData creates a NameTransferTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func NameTransferTransactionFromTransactionData(txData types.TransactionData) (NameTransferTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid NameTransferTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*NameTransferTransactionExtension)
+	if !ok {
+		return NameTransferTransaction{}, errors.New("invalid extension data for a NameTransferTransaction")
+	}
+	err := validateNameTransactionCoinFlow(txData, "NameTransferTransaction")
+	if err != nil {
+		return NameTransferTransaction{}, err
+	}
+	// return the NameTransferTransaction, with the data extracted from the TransactionData
+	return NameTransferTransaction{
+		Name:             extensionData.Name,
+		NewOwner:         extensionData.NewOwner,
+		OwnerFulfillment: extensionData.OwnerFulfillment,
+		CoinInputs:       txData.CoinInputs,
+		RefundCoinOutput: refundCoinOutputFromTransactionData(txData),
+		MinerFees:        txData.MinerFees,
+		ArbitraryData:    txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this NameTransferTransaction
+// as regular rivine transaction data.
+func (nttx *NameTransferTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    nttx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(nttx.RefundCoinOutput),
+		MinerFees:     nttx.MinerFees,
+		ArbitraryData: nttx.ArbitraryData,
+		Extension: &NameTransferTransactionExtension{
+			Name:             nttx.Name,
+			NewOwner:         nttx.NewOwner,
+			OwnerFulfillment: nttx.OwnerFulfillment,
+		},
+	}
+}
+
+// Transaction returns this NameTransferTransaction
+// as regular rivine transaction, using the given version as the type.
+func (nttx *NameTransferTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := nttx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Name Transfer								///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Name Transfer Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = NameTransferTransactionController{}
+	_ types.TransactionExtensionSigner           = NameTransferTransactionController{}
+	_ types.TransactionSignatureHasher           = NameTransferTransactionController{}
+	_ types.TransactionIDEncoder                 = NameTransferTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = NameTransferTransactionController{}
+)
+
+type (
+	// NameTransferTransactionController defines a custom transaction controller,
+	// for a Name Transfer Transaction. It allows the owner of a name to transfer it.
+	NameTransferTransactionController struct {
+		// NameInfoGetter is used to get the owner condition of a name.
+		NameInfoGetter NameInfoGetter
+
+		// TransactionVersion is used to validate/set the transaction version
+		// of a name transfer transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (nttc NameTransferTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	nttx, err := NameTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameTransferTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(nttx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (nttc NameTransferTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var nttx NameTransferTransaction
+	err := rivbin.NewDecoder(r).Decode(&nttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a NameTransferTx: %v", err)
+	}
+	// return name transfer tx as regular rivine tx data
+	return nttx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (nttc NameTransferTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	nttx, err := NameTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a NameTransferTx: %v", err)
+	}
+	return json.Marshal(nttx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (nttc NameTransferTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var nttx NameTransferTransaction
+	err := json.Unmarshal(data, &nttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a NameTransferTx: %v", err)
+	}
+	// return name transfer tx as regular rivine tx data
+	return nttx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (nttc NameTransferTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid NameTransferTransactionExtension,
+	// which contains the owner fulfillment that can be used to fulfill the owner condition of the name
+	ntTxExtension, ok := extension.(*NameTransferTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a NameTransferTransaction")
+	}
+
+	nameInfo, err := nttc.NameInfoGetter.GetNameInfo(ntTxExtension.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the info of name %q: %v", ntTxExtension.Name, err)
+	}
+	err = sign(&ntTxExtension.OwnerFulfillment, nameInfo.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign owner fulfillment of name transfer tx: %v", err)
+	}
+	return ntTxExtension, nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (nttc NameTransferTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	nttx, err := NameTransferTransactionFromTransaction(t, nttc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a name transfer tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierNameTransferTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(nttx.CoinInputs),
+		nttx.Name,
+		nttx.NewOwner,
+		nttx.RefundCoinOutput,
+		nttx.MinerFees,
+		nttx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (nttc NameTransferTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	nttx, err := NameTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a NameTransferTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierNameTransferTransaction, nttx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (nttc NameTransferTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	ntTxExtension, ok := extension.(*NameTransferTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a NameTransferTransaction")
+	}
+	return types.CommonTransactionExtensionData{
+		UnlockConditions: []types.UnlockConditionProxy{ntTxExtension.NewOwner},
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// UTILITIES																	///
+///////////////////////////////////////////////////////////////////////////////////
+
+// validateNameTransactionCoinFlow validates the base coin flow of a name transaction,
+// which only exists in order to pay the miner fees.
+func validateNameTransactionCoinFlow(txData types.TransactionData, txName string) error {
+	if len(txData.CoinOutputs) > 1 {
+		return fmt.Errorf("maximum one coin output is allowed for a %s", txName)
+	}
+	if len(txData.MinerFees) == 0 {
+		return fmt.Errorf("at least one miner fee is required for a %s", txName)
+	}
+	if len(txData.CoinInputs) == 0 {
+		return fmt.Errorf("at least one coin input is required for a %s", txName)
+	}
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return fmt.Errorf("no block stake inputs/outputs are allowed in a %s", txName)
+	}
+	return nil
+}
+
+func refundCoinOutputFromTransactionData(txData types.TransactionData) *types.CoinOutput {
+	if len(txData.CoinOutputs) == 0 {
+		return nil
+	}
+	return &txData.CoinOutputs[0]
+}
+
+func refundCoinOutputAsSlice(co *types.CoinOutput) []types.CoinOutput {
+	if co == nil {
+		return nil
+	}
+	return []types.CoinOutput{*co}
+}
+
+func coinInputParentIDs(inputs []types.CoinInput) []types.CoinOutputID {
+	parentIDSlice := make([]types.CoinOutputID, 0, len(inputs))
+	for _, ci := range inputs {
+		parentIDSlice = append(parentIDSlice, ci.ParentID)
+	}
+	return parentIDSlice
+}
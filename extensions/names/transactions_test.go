@@ -0,0 +1,200 @@
+package names
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	testNameRegistrationTxVersion types.TransactionVersion = 0xd0
+	testNameUpdateTxVersion       types.TransactionVersion = 0xd1
+	testNameTransferTxVersion     types.TransactionVersion = 0xd2
+)
+
+func testUnlockHash(t *testing.T, str string) types.UnlockHash {
+	var uh types.UnlockHash
+	err := uh.LoadString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uh
+}
+
+func testTransactions(t *testing.T) []types.Transaction {
+	uhA := testUnlockHash(t, "0112210f9efa5441ab705226b0628679ed190eb4588b662991747ea3809d93932c7b41cbe4b732")
+	uhB := testUnlockHash(t, "01450aeb140c58012cb4afb48e068f976272fefa44ffe0991a8a4350a3687558d66c8fc753c37e")
+
+	coinInputs := []types.CoinInput{
+		{
+			ParentID: types.CoinOutputID{1, 2, 3},
+			Fulfillment: types.NewFulfillment(&types.SingleSignatureFulfillment{
+				PublicKey: types.PublicKey{Algorithm: types.SignatureAlgoEd25519, Key: bytes.Repeat([]byte{4}, 32)},
+				Signature: bytes.Repeat([]byte{5}, 64),
+			}),
+		},
+	}
+	refund := &types.CoinOutput{
+		Value:     types.NewCurrency64(42),
+		Condition: types.NewCondition(types.NewUnlockHashCondition(uhA)),
+	}
+	minerFees := []types.Currency{types.NewCurrency64(100)}
+	ownerFulfillment := types.NewFulfillment(&types.SingleSignatureFulfillment{
+		PublicKey: types.PublicKey{Algorithm: types.SignatureAlgoEd25519, Key: bytes.Repeat([]byte{7}, 32)},
+		Signature: bytes.Repeat([]byte{8}, 64),
+	})
+
+	registration := NameRegistrationTransaction{
+		Name:          "alice",
+		Owner:         types.NewCondition(types.NewUnlockHashCondition(uhA)),
+		Data:          []byte("https://example.com"),
+		CoinInputs:    coinInputs,
+		MinerFees:     minerFees,
+		ArbitraryData: []byte("name registration"),
+	}
+	update := NameUpdateTransaction{
+		Name:             "alice",
+		Data:             []byte("https://example.org"),
+		OwnerFulfillment: ownerFulfillment,
+		CoinInputs:       coinInputs,
+		RefundCoinOutput: refund,
+		MinerFees:        minerFees,
+	}
+	transfer := NameTransferTransaction{
+		Name:             "alice",
+		NewOwner:         types.NewCondition(types.NewUnlockHashCondition(uhB)),
+		OwnerFulfillment: ownerFulfillment,
+		CoinInputs:       coinInputs,
+		RefundCoinOutput: refund,
+		MinerFees:        minerFees,
+		ArbitraryData:    []byte("name transfer"),
+	}
+
+	return []types.Transaction{
+		registration.Transaction(testNameRegistrationTxVersion),
+		update.Transaction(testNameUpdateTxVersion),
+		transfer.Transaction(testNameTransferTxVersion),
+	}
+}
+
+func registerTestTransactionVersions() func() {
+	types.RegisterTransactionVersion(testNameRegistrationTxVersion, NameRegistrationTransactionController{TransactionVersion: testNameRegistrationTxVersion})
+	types.RegisterTransactionVersion(testNameUpdateTxVersion, NameUpdateTransactionController{TransactionVersion: testNameUpdateTxVersion})
+	types.RegisterTransactionVersion(testNameTransferTxVersion, NameTransferTransactionController{TransactionVersion: testNameTransferTxVersion})
+	return func() {
+		types.RegisterTransactionVersion(testNameRegistrationTxVersion, nil)
+		types.RegisterTransactionVersion(testNameUpdateTxVersion, nil)
+		types.RegisterTransactionVersion(testNameTransferTxVersion, nil)
+	}
+}
+
+func TestNameTransactionsJSONEncoding(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		b, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		var decodedTx types.Transaction
+		err = json.Unmarshal(b, &decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if tx.ID() != decodedTx.ID() {
+			t.Error(idx, "unexpected ID after JSON round trip:", tx.ID(), "!=", decodedTx.ID())
+		}
+		b2, err := json.Marshal(decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !bytes.Equal(b, b2) {
+			t.Error(idx, "unexpected JSON after round trip:", string(b), "!=", string(b2))
+		}
+	}
+}
+
+func TestNameTransactionsBinaryEncoding(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		b, err := siabin.Marshal(tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		var decodedTx types.Transaction
+		err = siabin.Unmarshal(b, &decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if tx.ID() != decodedTx.ID() {
+			t.Error(idx, "unexpected ID after binary round trip:", tx.ID(), "!=", decodedTx.ID())
+		}
+		b2, err := siabin.Marshal(decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !bytes.Equal(b, b2) {
+			t.Error(idx, "unexpected binary encoding after round trip")
+		}
+	}
+}
+
+func TestNameTransactionsSignatureHashIgnoresFulfillments(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		hash, err := tx.SignatureHash()
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		// wipe all fulfillments, the signature hash should remain the same
+		tx.CoinInputs = []types.CoinInput{{ParentID: tx.CoinInputs[0].ParentID}}
+		switch ext := tx.Extension.(type) {
+		case *NameUpdateTransactionExtension:
+			ext.OwnerFulfillment = types.UnlockFulfillmentProxy{}
+		case *NameTransferTransactionExtension:
+			ext.OwnerFulfillment = types.UnlockFulfillmentProxy{}
+		}
+		wipedHash, err := tx.SignatureHash()
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if hash != wipedHash {
+			t.Error(idx, "signature hash changed after wiping fulfillments")
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	validNames := []string{
+		"a",
+		"alice",
+		"alice.bob",
+		"alice-bob_42",
+		"0",
+		string(bytes.Repeat([]byte{'a'}, MaxNameLength)),
+	}
+	for idx, name := range validNames {
+		if err := ValidateName(name); err != nil {
+			t.Error(idx, name, "unexpected error:", err)
+		}
+	}
+	invalidNames := []string{
+		"",
+		"Alice",
+		"alice bob",
+		"-alice",
+		"alice.",
+		"álice",
+		string(bytes.Repeat([]byte{'a'}, MaxNameLength+1)),
+	}
+	for idx, name := range invalidNames {
+		if err := ValidateName(name); err == nil {
+			t.Error(idx, name, "expected error, but none was returned")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/threefoldtech/rivine/extensions/tokens"
+	rapi "github.com/threefoldtech/rivine/pkg/api"
+)
+
+// GetTokenResponse contains the info of a requested token.
+type GetTokenResponse struct {
+	ID    tokens.TokenID   `json:"id"`
+	Token tokens.TokenInfo `json:"token"`
+}
+
+// GetTokenOutputResponse contains a requested unspent token output.
+type GetTokenOutputResponse struct {
+	ID     tokens.TokenOutputID   `json:"id"`
+	Output tokens.TokenOutputInfo `json:"output"`
+}
+
+// RegisterConsensusTokensHTTPHandlers registers the token handlers for the Rivine Consensus HTTP endpoints.
+func RegisterConsensusTokensHTTPHandlers(router rapi.Router, plugin *tokens.Plugin) {
+	registerTokensHTTPHandlers(router, "/consensus", plugin)
+}
+
+// RegisterExplorerTokensHTTPHandlers registers the token handlers for the Rivine Explorer HTTP endpoints.
+func RegisterExplorerTokensHTTPHandlers(router rapi.Router, plugin *tokens.Plugin) {
+	registerTokensHTTPHandlers(router, "/explorer", plugin)
+}
+
+func registerTokensHTTPHandlers(router rapi.Router, root string, plugin *tokens.Plugin) {
+	router.GET(root+"/tokens/:id", NewGetTokenHandler(plugin))
+	router.GET(root+"/tokenoutputs/:id", NewGetTokenOutputHandler(plugin))
+}
+
+// NewGetTokenHandler creates a handler to handle the API calls to /<root>/tokens/:id.
+func NewGetTokenHandler(plugin *tokens.Plugin) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var id tokens.TokenID
+		err := id.LoadString(ps.ByName("id"))
+		if err != nil {
+			rapi.WriteError(w, rapi.Error{Message: fmt.Sprintf("invalid token ID given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		info, err := plugin.GetTokenInfo(id)
+		if err != nil {
+			if err == tokens.ErrTokenNotFound {
+				rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusNoContent)
+				return
+			}
+			rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		rapi.WriteJSON(w, GetTokenResponse{
+			ID:    id,
+			Token: info,
+		})
+	}
+}
+
+// NewGetTokenOutputHandler creates a handler to handle the API calls to /<root>/tokenoutputs/:id.
+func NewGetTokenOutputHandler(plugin *tokens.Plugin) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var id tokens.TokenOutputID
+		err := id.LoadString(ps.ByName("id"))
+		if err != nil {
+			rapi.WriteError(w, rapi.Error{Message: fmt.Sprintf("invalid token output ID given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		output, err := plugin.GetTokenOutput(id)
+		if err != nil {
+			if err == tokens.ErrTokenOutputNotFound {
+				rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusNoContent)
+				return
+			}
+			rapi.WriteError(w, rapi.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		rapi.WriteJSON(w, GetTokenOutputResponse{
+			ID:     id,
+			Output: output,
+		})
+	}
+}
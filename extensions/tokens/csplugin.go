@@ -0,0 +1,596 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	pluginDBVersion = "1.0.0.0"
+	pluginDBHeader  = "TokensPlugin"
+)
+
+var (
+	bucketTokens            = []byte("tokens")
+	bucketTokenOutputs      = []byte("tokenoutputs")
+	bucketSpentTokenOutputs = []byte("spenttokenoutputs")
+)
+
+type (
+	// Plugin is a struct defines the custom tokens plugin
+	Plugin struct {
+		tokenCreationTransactionVersion types.TransactionVersion
+		tokenIssuanceTransactionVersion types.TransactionVersion
+		tokenTransferTransactionVersion types.TransactionVersion
+		storage                         modules.PluginViewStorage
+		unregisterCallback              modules.PluginUnregisterCallback
+	}
+)
+
+// NewPlugin creates a new Plugin using the given transaction versions,
+// registering the transaction controllers of all three token transaction types.
+func NewPlugin(tokenCreationTransactionVersion, tokenIssuanceTransactionVersion, tokenTransferTransactionVersion types.TransactionVersion) *Plugin {
+	p := &Plugin{
+		tokenCreationTransactionVersion: tokenCreationTransactionVersion,
+		tokenIssuanceTransactionVersion: tokenIssuanceTransactionVersion,
+		tokenTransferTransactionVersion: tokenTransferTransactionVersion,
+	}
+	types.RegisterTransactionVersion(tokenCreationTransactionVersion, TokenCreationTransactionController{
+		TransactionVersion: tokenCreationTransactionVersion,
+	})
+	types.RegisterTransactionVersion(tokenIssuanceTransactionVersion, TokenIssuanceTransactionController{
+		TokenInfoGetter:    p,
+		TransactionVersion: tokenIssuanceTransactionVersion,
+	})
+	types.RegisterTransactionVersion(tokenTransferTransactionVersion, TokenTransferTransactionController{
+		TokenInfoGetter:    p,
+		TransactionVersion: tokenTransferTransactionVersion,
+	})
+	return p
+}
+
+// InitPlugin initializes the Bucket for the first time
+func (p *Plugin) InitPlugin(metadata *persist.Metadata, bucket *bolt.Bucket, storage modules.PluginViewStorage, unregisterCallback modules.PluginUnregisterCallback) (persist.Metadata, error) {
+	p.storage = storage
+	p.unregisterCallback = unregisterCallback
+	if metadata == nil {
+		for _, name := range [][]byte{bucketTokens, bucketTokenOutputs, bucketSpentTokenOutputs} {
+			if bucket.Bucket(name) != nil {
+				continue
+			}
+			_, err := bucket.CreateBucket(name)
+			if err != nil {
+				return persist.Metadata{}, fmt.Errorf("failed to create %s bucket: %v", string(name), err)
+			}
+		}
+		metadata = &persist.Metadata{
+			Version: pluginDBVersion,
+			Header:  pluginDBHeader,
+		}
+	} else if metadata.Version != pluginDBVersion {
+		return persist.Metadata{}, errors.New("There is only 1 version of this plugin, version mismatch")
+	} else if metadata.Header != pluginDBHeader {
+		return persist.Metadata{}, errors.New("There is only 1 header of this plugin, header mismatch")
+	}
+	return *metadata, nil
+}
+
+// ApplyBlock applies a block's token transactions to the token buckets.
+func (p *Plugin) ApplyBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	for idx, txn := range block.Transactions {
+		cTxn := modules.ConsensusTransaction{
+			Transaction:            txn,
+			BlockHeight:            block.Height,
+			BlockTime:              block.Timestamp,
+			SequenceID:             uint16(idx),
+			SpentCoinOutputs:       block.SpentCoinOutputs,
+			SpentBlockStakeOutputs: block.SpentBlockStakeOutputs,
+		}
+		err = p.ApplyTransaction(cTxn, bucket)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyTransaction applies a token transaction to the token buckets.
+func (p *Plugin) ApplyTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	// check the version and handle the ones we care about
+	switch txn.Version {
+	case p.tokenCreationTransactionVersion:
+		tctx, err := TokenCreationTransactionFromTransaction(txn.Transaction, p.tokenCreationTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token creation tx type: %v", err)
+		}
+		tokensBucket, err := bucket.Bucket(bucketTokens)
+		if err != nil {
+			return errors.New("tokens bucket does not exist")
+		}
+		txID := txn.ID()
+		tokenID := TokenID(txID)
+		err = putTokenInfo(tokensBucket, tokenID, TokenInfo{
+			Symbol:          tctx.Symbol,
+			MaxSupply:       tctx.MaxSupply,
+			IssuerCondition: tctx.IssuerCondition,
+			Supply:          tokenOutputSum(tctx.TokenOutputs),
+			CreationHeight:  txn.BlockHeight,
+		})
+		if err != nil {
+			return err
+		}
+		return p.applyTokenOutputs(bucket, txID, tokenID, tctx.TokenOutputs)
+
+	case p.tokenIssuanceTransactionVersion:
+		titx, err := TokenIssuanceTransactionFromTransaction(txn.Transaction, p.tokenIssuanceTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token issuance tx type: %v", err)
+		}
+		tokensBucket, err := bucket.Bucket(bucketTokens)
+		if err != nil {
+			return errors.New("tokens bucket does not exist")
+		}
+		info, err := getTokenInfo(tokensBucket, titx.TokenID)
+		if err != nil {
+			return err
+		}
+		info.Supply = info.Supply.Add(tokenOutputSum(titx.TokenOutputs))
+		err = putTokenInfo(tokensBucket, titx.TokenID, info)
+		if err != nil {
+			return err
+		}
+		return p.applyTokenOutputs(bucket, txn.ID(), titx.TokenID, titx.TokenOutputs)
+
+	case p.tokenTransferTransactionVersion:
+		tttx, err := TokenTransferTransactionFromTransaction(txn.Transaction, p.tokenTransferTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token transfer tx type: %v", err)
+		}
+		outputsBucket, err := bucket.Bucket(bucketTokenOutputs)
+		if err != nil {
+			return errors.New("token outputs bucket does not exist")
+		}
+		spentOutputsBucket, err := bucket.Bucket(bucketSpentTokenOutputs)
+		if err != nil {
+			return errors.New("spent token outputs bucket does not exist")
+		}
+		// move all spent token outputs to the spent bucket,
+		// such that they can be restored when this transaction gets reverted
+		for _, ti := range tttx.TokenInputs {
+			err = moveTokenOutput(outputsBucket, spentOutputsBucket, ti.ParentID)
+			if err != nil {
+				return fmt.Errorf("failed to spend token output %s: %v", ti.ParentID.String(), err)
+			}
+		}
+		return p.applyTokenOutputs(bucket, txn.ID(), tttx.TokenID, tttx.TokenOutputs)
+	}
+	return nil
+}
+
+// RevertBlock reverts a block's token transactions from the token buckets.
+func (p *Plugin) RevertBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	// revert in reverse order, as token outputs can be spent within the same block
+	for idx := len(block.Transactions) - 1; idx >= 0; idx-- {
+		cTxn := modules.ConsensusTransaction{
+			Transaction:            block.Transactions[idx],
+			BlockHeight:            block.Height,
+			BlockTime:              block.Timestamp,
+			SequenceID:             uint16(idx),
+			SpentCoinOutputs:       block.SpentCoinOutputs,
+			SpentBlockStakeOutputs: block.SpentBlockStakeOutputs,
+		}
+		err = p.RevertTransaction(cTxn, bucket)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevertTransaction reverts a token transaction from the token buckets.
+func (p *Plugin) RevertTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	// check the version and handle the ones we care about
+	switch txn.Version {
+	case p.tokenCreationTransactionVersion:
+		tctx, err := TokenCreationTransactionFromTransaction(txn.Transaction, p.tokenCreationTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token creation tx type: %v", err)
+		}
+		txID := txn.ID()
+		err = p.revertTokenOutputs(bucket, txID, len(tctx.TokenOutputs))
+		if err != nil {
+			return err
+		}
+		tokensBucket, err := bucket.Bucket(bucketTokens)
+		if err != nil {
+			return errors.New("tokens bucket does not exist")
+		}
+		tokenID := TokenID(txID)
+		err = tokensBucket.Delete(tokenID[:])
+		if err != nil {
+			return fmt.Errorf("failed to delete token %s: %v", tokenID.String(), err)
+		}
+
+	case p.tokenIssuanceTransactionVersion:
+		titx, err := TokenIssuanceTransactionFromTransaction(txn.Transaction, p.tokenIssuanceTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token issuance tx type: %v", err)
+		}
+		err = p.revertTokenOutputs(bucket, txn.ID(), len(titx.TokenOutputs))
+		if err != nil {
+			return err
+		}
+		tokensBucket, err := bucket.Bucket(bucketTokens)
+		if err != nil {
+			return errors.New("tokens bucket does not exist")
+		}
+		info, err := getTokenInfo(tokensBucket, titx.TokenID)
+		if err != nil {
+			return err
+		}
+		info.Supply = info.Supply.Sub(tokenOutputSum(titx.TokenOutputs))
+		return putTokenInfo(tokensBucket, titx.TokenID, info)
+
+	case p.tokenTransferTransactionVersion:
+		tttx, err := TokenTransferTransactionFromTransaction(txn.Transaction, p.tokenTransferTransactionVersion)
+		if err != nil {
+			return fmt.Errorf("unexpected error while unpacking the token transfer tx type: %v", err)
+		}
+		err = p.revertTokenOutputs(bucket, txn.ID(), len(tttx.TokenOutputs))
+		if err != nil {
+			return err
+		}
+		outputsBucket, err := bucket.Bucket(bucketTokenOutputs)
+		if err != nil {
+			return errors.New("token outputs bucket does not exist")
+		}
+		spentOutputsBucket, err := bucket.Bucket(bucketSpentTokenOutputs)
+		if err != nil {
+			return errors.New("spent token outputs bucket does not exist")
+		}
+		// restore all token outputs spent by this transaction
+		for _, ti := range tttx.TokenInputs {
+			err = moveTokenOutput(spentOutputsBucket, outputsBucket, ti.ParentID)
+			if err != nil {
+				return fmt.Errorf("failed to restore spent token output %s: %v", ti.ParentID.String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) applyTokenOutputs(bucket *persist.LazyBoltBucket, txID types.TransactionID, tokenID TokenID, outputs []TokenOutput) error {
+	outputsBucket, err := bucket.Bucket(bucketTokenOutputs)
+	if err != nil {
+		return errors.New("token outputs bucket does not exist")
+	}
+	for index, to := range outputs {
+		id := TokenOutputIDFromTransactionID(txID, uint64(index))
+		b, err := rivbin.Marshal(TokenOutputInfo{
+			TokenID:     tokenID,
+			TokenOutput: to,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to (rivbin) marshal token output %s: %v", id.String(), err)
+		}
+		err = outputsBucket.Put(id[:], b)
+		if err != nil {
+			return fmt.Errorf("failed to put token output %s: %v", id.String(), err)
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) revertTokenOutputs(bucket *persist.LazyBoltBucket, txID types.TransactionID, n int) error {
+	outputsBucket, err := bucket.Bucket(bucketTokenOutputs)
+	if err != nil {
+		return errors.New("token outputs bucket does not exist")
+	}
+	for index := 0; index < n; index++ {
+		id := TokenOutputIDFromTransactionID(txID, uint64(index))
+		err = outputsBucket.Delete(id[:])
+		if err != nil {
+			return fmt.Errorf("failed to delete token output %s: %v", id.String(), err)
+		}
+	}
+	return nil
+}
+
+// GetTokenInfo implements TokenInfoGetter.GetTokenInfo
+func (p *Plugin) GetTokenInfo(id TokenID) (TokenInfo, error) {
+	var info TokenInfo
+	err := p.storage.View(func(bucket *bolt.Bucket) (err error) {
+		tokensBucket := bucket.Bucket(bucketTokens)
+		if tokensBucket == nil {
+			return errors.New("tokens bucket could not be found")
+		}
+		info, err = getTokenInfo(tokensBucket, id)
+		return err
+	})
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	return info, nil
+}
+
+// GetTokenOutput implements TokenInfoGetter.GetTokenOutput
+func (p *Plugin) GetTokenOutput(id TokenOutputID) (TokenOutputInfo, error) {
+	var output TokenOutputInfo
+	err := p.storage.View(func(bucket *bolt.Bucket) (err error) {
+		outputsBucket := bucket.Bucket(bucketTokenOutputs)
+		if outputsBucket == nil {
+			return errors.New("token outputs bucket could not be found")
+		}
+		output, err = getTokenOutput(outputsBucket, id)
+		return err
+	})
+	if err != nil {
+		return TokenOutputInfo{}, err
+	}
+	return output, nil
+}
+
+// TransactionValidatorVersionFunctionMapping returns all tx validators for specific tx versions linked to this plugin
+func (p *Plugin) TransactionValidatorVersionFunctionMapping() map[types.TransactionVersion][]modules.PluginTransactionValidationFunction {
+	return map[types.TransactionVersion][]modules.PluginTransactionValidationFunction{
+		p.tokenCreationTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateTokenCreationTx,
+			validateTokenTransactionCoinFlowIsBalanced,
+		},
+		p.tokenIssuanceTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateTokenIssuanceTx,
+			validateTokenTransactionCoinFlowIsBalanced,
+		},
+		p.tokenTransferTransactionVersion: []modules.PluginTransactionValidationFunction{
+			p.validateTokenTransferTx,
+			validateTokenTransactionCoinFlowIsBalanced,
+		},
+	}
+}
+
+// TransactionValidators returns all tx validators linked to this plugin
+func (p *Plugin) TransactionValidators() []modules.PluginTransactionValidationFunction {
+	return nil
+}
+
+func (p *Plugin) validateTokenCreationTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	tctx, err := TokenCreationTransactionFromTransaction(tx.Transaction, p.tokenCreationTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a token creation tx: %v", err)
+	}
+
+	// ensure the symbol is defined and not too long
+	if l := len(tctx.Symbol); l == 0 || l > MaxTokenSymbolLength {
+		return fmt.Errorf("token symbol has to be between 1 and %d bytes long, %d bytes is invalid", MaxTokenSymbolLength, l)
+	}
+	// ensure the token can have a supply at all
+	if len(tctx.TokenOutputs) == 0 && !(&TokenInfo{IssuerCondition: tctx.IssuerCondition}).IsIssuable() {
+		return errors.New("a token without initial supply requires an issuer condition")
+	}
+	if tctx.IssuerCondition.ConditionType() != types.ConditionTypeNil {
+		err = tctx.IssuerCondition.IsStandardCondition(ctx.ValidationContext)
+		if err != nil {
+			return fmt.Errorf("invalid issuer condition: %v", err)
+		}
+	}
+	err = validateTokenOutputs(tctx.TokenOutputs, ctx)
+	if err != nil {
+		return err
+	}
+	// ensure the initial supply does not exceed the max supply
+	return (&TokenInfo{Symbol: tctx.Symbol, MaxSupply: tctx.MaxSupply}).CanIssue(tokenOutputSum(tctx.TokenOutputs))
+}
+
+func (p *Plugin) validateTokenIssuanceTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	titx, err := TokenIssuanceTransactionFromTransaction(tx.Transaction, p.tokenIssuanceTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a token issuance tx: %v", err)
+	}
+
+	tokensBucket, err := bucket.Bucket(bucketTokens)
+	if err != nil {
+		return err
+	}
+	info, err := getTokenInfo(tokensBucket, titx.TokenID)
+	if err != nil {
+		return fmt.Errorf("cannot issue tokens for token %s: %v", titx.TokenID.String(), err)
+	}
+	if !info.IsIssuable() {
+		return types.NewClientError(fmt.Errorf("token %s has a fixed supply", titx.TokenID.String()), types.ClientErrorForbidden)
+	}
+
+	// check if IssuerFulfillment fulfills the IssuerCondition of the token
+	err = info.IssuerCondition.Fulfill(titx.IssuerFulfillment, types.FulfillContext{
+		BlockHeight: ctx.BlockHeight,
+		BlockTime:   ctx.BlockTime,
+		Transaction: tx.Transaction,
+	})
+	if err != nil {
+		return types.NewClientError(fmt.Errorf("failed to fulfill issuer condition of token %s: %v", titx.TokenID.String(), err), types.ClientErrorUnauthorized)
+	}
+
+	err = validateTokenOutputs(titx.TokenOutputs, ctx)
+	if err != nil {
+		return err
+	}
+	return info.CanIssue(tokenOutputSum(titx.TokenOutputs))
+}
+
+func (p *Plugin) validateTokenTransferTx(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	tttx, err := TokenTransferTransactionFromTransaction(tx.Transaction, p.tokenTransferTransactionVersion)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a token transfer tx: %v", err)
+	}
+
+	tokensBucket, err := bucket.Bucket(bucketTokens)
+	if err != nil {
+		return err
+	}
+	_, err = getTokenInfo(tokensBucket, tttx.TokenID)
+	if err != nil {
+		return fmt.Errorf("cannot transfer token %s: %v", tttx.TokenID.String(), err)
+	}
+
+	outputsBucket, err := bucket.Bucket(bucketTokenOutputs)
+	if err != nil {
+		return err
+	}
+	var inputSum types.Currency
+	spent := make(map[TokenOutputID]struct{}, len(tttx.TokenInputs))
+	for index, ti := range tttx.TokenInputs {
+		if _, ok := spent[ti.ParentID]; ok {
+			return fmt.Errorf("token output %s is spent twice within the same transaction", ti.ParentID.String())
+		}
+		spent[ti.ParentID] = struct{}{}
+		err = ti.Fulfillment.IsStandardFulfillment(ctx.ValidationContext)
+		if err != nil {
+			return err
+		}
+		parent, err := getTokenOutput(outputsBucket, ti.ParentID)
+		if err != nil {
+			return fmt.Errorf("cannot spend token output %s: %v", ti.ParentID.String(), err)
+		}
+		if parent.TokenID != tttx.TokenID {
+			return fmt.Errorf(
+				"token output %s contains token %s while token %s is transferred",
+				ti.ParentID.String(), parent.TokenID.String(), tttx.TokenID.String())
+		}
+		err = parent.Condition.Fulfill(ti.Fulfillment, types.FulfillContext{
+			ExtraObjects: []interface{}{SpecifierTokenInput, uint64(index)},
+			BlockHeight:  ctx.BlockHeight,
+			BlockTime:    ctx.BlockTime,
+			Transaction:  tx.Transaction,
+		})
+		if err != nil {
+			return err
+		}
+		inputSum = inputSum.Add(parent.Value)
+	}
+
+	err = validateTokenOutputs(tttx.TokenOutputs, ctx)
+	if err != nil {
+		return err
+	}
+	// ensure the tx is balanced within the context of token outputs
+	if outputSum := tokenOutputSum(tttx.TokenOutputs); !inputSum.Equals(outputSum) {
+		return fmt.Errorf(
+			"unbalanced token outputs: the sum of token inputs (%s) for tx %s does not equal its sum of token outputs (%s)",
+			inputSum.String(), tx.ID().String(), outputSum.String())
+	}
+	return nil
+}
+
+// validateTokenTransactionCoinFlowIsBalanced ensures that the coin inputs of a token transaction
+// equal the sum of its optional refund coin output and miner fees.
+func validateTokenTransactionCoinFlowIsBalanced(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+	var coinInputSum types.Currency
+	for _, ci := range tx.CoinInputs {
+		co, ok := tx.SpentCoinOutputs[ci.ParentID]
+		if !ok {
+			return fmt.Errorf(
+				"unable to find parent ID %s as an unspent coin output in the current consensus transaction at block height %d",
+				ci.ParentID.String(), ctx.BlockHeight)
+		}
+		coinInputSum = coinInputSum.Add(co.Value)
+	}
+	if coinOutputSum := tx.CoinOutputSum(); !coinInputSum.Equals(coinOutputSum) {
+		return fmt.Errorf(
+			"unbalanced coin outputs: the sum of coin inputs (%s) for tx %s does not equal its sum of coin outputs (%s)",
+			coinInputSum.String(), tx.ID().String(), coinOutputSum.String())
+	}
+	return nil
+}
+
+func validateTokenOutputs(outputs []TokenOutput, ctx types.TransactionValidationContext) error {
+	for _, to := range outputs {
+		if to.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+		err := to.Condition.IsStandardCondition(ctx.ValidationContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close unregisters the plugin from the consensus
+func (p *Plugin) Close() error {
+	return p.storage.Close()
+}
+
+func getTokenInfo(tokensBucket *bolt.Bucket, id TokenID) (TokenInfo, error) {
+	b := tokensBucket.Get(id[:])
+	if len(b) == 0 {
+		return TokenInfo{}, ErrTokenNotFound
+	}
+	var info TokenInfo
+	err := rivbin.Unmarshal(b, &info)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("corrupt plugin DB: failed to decode info of token %s: %v", id.String(), err)
+	}
+	return info, nil
+}
+
+func putTokenInfo(tokensBucket *bolt.Bucket, id TokenID, info TokenInfo) error {
+	b, err := rivbin.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to (rivbin) marshal info of token %s: %v", id.String(), err)
+	}
+	err = tokensBucket.Put(id[:], b)
+	if err != nil {
+		return fmt.Errorf("failed to put info of token %s: %v", id.String(), err)
+	}
+	return nil
+}
+
+func getTokenOutput(outputsBucket *bolt.Bucket, id TokenOutputID) (TokenOutputInfo, error) {
+	b := outputsBucket.Get(id[:])
+	if len(b) == 0 {
+		return TokenOutputInfo{}, ErrTokenOutputNotFound
+	}
+	var output TokenOutputInfo
+	err := rivbin.Unmarshal(b, &output)
+	if err != nil {
+		return TokenOutputInfo{}, fmt.Errorf("corrupt plugin DB: failed to decode token output %s: %v", id.String(), err)
+	}
+	return output, nil
+}
+
+// moveTokenOutput moves a (rivbin-encoded) token output from one bucket to another
+func moveTokenOutput(from, to *bolt.Bucket, id TokenOutputID) error {
+	b := from.Get(id[:])
+	if len(b) == 0 {
+		return ErrTokenOutputNotFound
+	}
+	// copy the value, as it is only valid for the lifetime of the transaction
+	// and the deletion below invalidates it
+	value := make([]byte, len(b))
+	copy(value, b)
+	err := from.Delete(id[:])
+	if err != nil {
+		return err
+	}
+	return to.Put(id[:], value)
+}
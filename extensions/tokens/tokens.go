@@ -0,0 +1,169 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID,
+// as well as the IDs of the token outputs created by those transactions.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierTokenCreationTransaction = types.Specifier{'t', 'o', 'k', 'e', 'n', ' ', 'c', 'r', 'e', 'a', 't', 'e'}
+	SpecifierTokenIssuanceTransaction = types.Specifier{'t', 'o', 'k', 'e', 'n', ' ', 'i', 's', 's', 'u', 'e'}
+	SpecifierTokenTransferTransaction = types.Specifier{'t', 'o', 'k', 'e', 'n', ' ', 't', 'r', 'a', 'n', 's', 'f', 'e', 'r'}
+
+	SpecifierTokenOutput = types.Specifier{'t', 'o', 'k', 'e', 'n', ' ', 'o', 'u', 't', 'p', 'u', 't'}
+	SpecifierTokenInput  = types.Specifier{'t', 'o', 'k', 'e', 'n', ' ', 'i', 'n', 'p', 'u', 't'}
+)
+
+const (
+	// MaxTokenSymbolLength defines the maximum length (in bytes)
+	// of the symbol of a token.
+	MaxTokenSymbolLength = 16
+)
+
+var (
+	// ErrTokenNotFound is returned in case a token is looked up,
+	// which was never created (or is reverted since).
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrTokenOutputNotFound is returned in case a token output is looked up,
+	// which does not exist or is already spent.
+	ErrTokenOutputNotFound = errors.New("token output not found")
+)
+
+type (
+	// TokenID uniquely identifies a custom token.
+	// It is equal to the ID of the TokenCreationTransaction that created the token.
+	TokenID crypto.Hash
+
+	// TokenOutputID uniquely identifies a token output.
+	TokenOutputID crypto.Hash
+
+	// TokenOutput defines an amount of tokens, locked by a condition.
+	// The token it applies to is defined by the transaction that creates the output.
+	TokenOutput struct {
+		Value     types.Currency             `json:"value"`
+		Condition types.UnlockConditionProxy `json:"condition"`
+	}
+
+	// TokenInput spends a TokenOutput, fulfilling the condition of that output.
+	TokenInput struct {
+		ParentID    TokenOutputID                `json:"parentid"`
+		Fulfillment types.UnlockFulfillmentProxy `json:"fulfillment"`
+	}
+
+	// TokenOutputInfo is a TokenOutput as tracked by the consensus plugin,
+	// combined with the ID of the token it contains.
+	TokenOutputInfo struct {
+		TokenID TokenID `json:"tokenid"`
+		TokenOutput
+	}
+
+	// TokenInfo defines a custom token and its supply rules,
+	// as well as the amount of tokens currently issued.
+	TokenInfo struct {
+		// Symbol is a short human-readable name of the token,
+		// it is not required to be unique.
+		Symbol string `json:"symbol"`
+		// MaxSupply defines the maximum amount of tokens that can ever be issued,
+		// a zero value means that the supply is unlimited.
+		MaxSupply types.Currency `json:"maxsupply"`
+		// IssuerCondition defines the condition that has to be fulfilled in order to issue new tokens,
+		// a nil condition means that no tokens can be issued past the ones created as part of the token creation.
+		IssuerCondition types.UnlockConditionProxy `json:"issuercondition"`
+		// Supply defines the amount of tokens currently issued.
+		Supply types.Currency `json:"supply"`
+		// CreationHeight defines the block height at which the token was created.
+		CreationHeight types.BlockHeight `json:"creationheight"`
+	}
+
+	// TokenInfoGetter allows you to look up the (custom) tokens and
+	// their unspent outputs, as currently tracked by the consensus.
+	TokenInfoGetter interface {
+		// GetTokenInfo returns the info of a token, identified by its ID.
+		GetTokenInfo(id TokenID) (TokenInfo, error)
+		// GetTokenOutput returns an unspent token output, identified by its ID.
+		GetTokenOutput(id TokenOutputID) (TokenOutputInfo, error)
+	}
+)
+
+// TokenOutputIDFromTransactionID returns the ID of a token output at the given index,
+// for a transaction with the given ID.
+func TokenOutputIDFromTransactionID(txID types.TransactionID, index uint64) (id TokenOutputID) {
+	h := crypto.NewHash()
+	rivbin.NewEncoder(h).EncodeAll(SpecifierTokenOutput, txID, index)
+	h.Sum(id[:0])
+	return
+}
+
+// IsIssuable returns true if new tokens can still be issued for this token.
+func (ti *TokenInfo) IsIssuable() bool {
+	return ti.IssuerCondition.ConditionType() != types.ConditionTypeNil
+}
+
+// CanIssue returns an error in case the given amount of tokens
+// cannot be added to the current supply, as it would exceed the max supply.
+func (ti *TokenInfo) CanIssue(amount types.Currency) error {
+	if ti.MaxSupply.IsZero() {
+		return nil // unlimited supply
+	}
+	if ti.Supply.Add(amount).Cmp(ti.MaxSupply) > 0 {
+		return fmt.Errorf(
+			"issuing %s tokens would exceed the max supply (%s) of token %s, current supply is %s",
+			amount.String(), ti.MaxSupply.String(), ti.Symbol, ti.Supply.String())
+	}
+	return nil
+}
+
+// String prints the token id in hex.
+func (tid TokenID) String() string {
+	return crypto.Hash(tid).String()
+}
+
+// LoadString loads the given token id from a hex string
+func (tid *TokenID) LoadString(str string) error {
+	return (*crypto.Hash)(tid).LoadString(str)
+}
+
+// MarshalJSON marshals a token id as a hex string.
+func (tid TokenID) MarshalJSON() ([]byte, error) {
+	return crypto.Hash(tid).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the json hex string of the token id.
+func (tid *TokenID) UnmarshalJSON(b []byte) error {
+	return (*crypto.Hash)(tid).UnmarshalJSON(b)
+}
+
+// String prints the token output id in hex.
+func (toid TokenOutputID) String() string {
+	return crypto.Hash(toid).String()
+}
+
+// LoadString loads the given token output id from a hex string
+func (toid *TokenOutputID) LoadString(str string) error {
+	return (*crypto.Hash)(toid).LoadString(str)
+}
+
+// MarshalJSON marshals a token output id as a hex string.
+func (toid TokenOutputID) MarshalJSON() ([]byte, error) {
+	return crypto.Hash(toid).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the json hex string of the token output id.
+func (toid *TokenOutputID) UnmarshalJSON(b []byte) error {
+	return (*crypto.Hash)(toid).UnmarshalJSON(b)
+}
+
+// tokenOutputSum returns the sum of the values of the given token outputs.
+func tokenOutputSum(outputs []TokenOutput) (sum types.Currency) {
+	for _, to := range outputs {
+		sum = sum.Add(to.Value)
+	}
+	return
+}
@@ -0,0 +1,828 @@
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Token Creation										///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// TokenCreationTransaction is to be used by anyone in order to define a new custom token,
+	// optionally creating an initial supply of that token. The ID of the created token
+	// is equal to the ID of this transaction.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	TokenCreationTransaction struct {
+		// Symbol is a short human-readable name of the token.
+		Symbol string `json:"symbol"`
+		// MaxSupply defines the maximum amount of tokens that can ever be issued,
+		// a zero value means that the supply is unlimited.
+		MaxSupply types.Currency `json:"maxsupply"`
+		// IssuerCondition defines the condition that has to be fulfilled in order to issue new tokens,
+		// a nil condition means that the token supply is fixed to the initial token outputs.
+		IssuerCondition types.UnlockConditionProxy `json:"issuercondition"`
+		// TokenOutputs defines the initial supply of the token.
+		TokenOutputs []TokenOutput `json:"tokenoutputs,omitempty"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this token creation transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// TokenCreationTransactionExtension defines the TokenCreationTransaction Extension Data
+	TokenCreationTransactionExtension struct {
+		Symbol          string
+		MaxSupply       types.Currency
+		IssuerCondition types.UnlockConditionProxy
+		TokenOutputs    []TokenOutput
+	}
+)
+
+// TokenCreationTransactionFromTransaction creates a TokenCreationTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `TokenCreationTransactionFromTransactionData` constructor.
+func TokenCreationTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (TokenCreationTransaction, error) {
+	if tx.Version != expectedVersion {
+		return TokenCreationTransaction{}, fmt.Errorf(
+			"a token creation transaction requires tx version %d",
+			expectedVersion)
+	}
+	return TokenCreationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// TokenCreationTransactionFromTransactionData creates a TokenCreationTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func TokenCreationTransactionFromTransactionData(txData types.TransactionData) (TokenCreationTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid TokenCreationTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*TokenCreationTransactionExtension)
+	if !ok {
+		return TokenCreationTransaction{}, errors.New("invalid extension data for a TokenCreationTransaction")
+	}
+	err := validateTokenTransactionCoinFlow(txData, "TokenCreationTransaction")
+	if err != nil {
+		return TokenCreationTransaction{}, err
+	}
+	// return the TokenCreationTransaction, with the data extracted from the TransactionData
+	return TokenCreationTransaction{
+		Symbol:           extensionData.Symbol,
+		MaxSupply:        extensionData.MaxSupply,
+		IssuerCondition:  extensionData.IssuerCondition,
+		TokenOutputs:     extensionData.TokenOutputs,
+		CoinInputs:       txData.CoinInputs,
+		RefundCoinOutput: refundCoinOutputFromTransactionData(txData),
+		MinerFees:        txData.MinerFees,
+		ArbitraryData:    txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this TokenCreationTransaction
+// as regular rivine transaction data.
+func (tctx *TokenCreationTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    tctx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(tctx.RefundCoinOutput),
+		MinerFees:     tctx.MinerFees,
+		ArbitraryData: tctx.ArbitraryData,
+		Extension: &TokenCreationTransactionExtension{
+			Symbol:          tctx.Symbol,
+			MaxSupply:       tctx.MaxSupply,
+			IssuerCondition: tctx.IssuerCondition,
+			TokenOutputs:    tctx.TokenOutputs,
+		},
+	}
+}
+
+// Transaction returns this TokenCreationTransaction
+// as regular rivine transaction, using the given version as the type.
+func (tctx *TokenCreationTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := tctx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Token Creation								///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Token Creation Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = TokenCreationTransactionController{}
+	_ types.TransactionSignatureHasher           = TokenCreationTransactionController{}
+	_ types.TransactionIDEncoder                 = TokenCreationTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = TokenCreationTransactionController{}
+)
+
+type (
+	// TokenCreationTransactionController defines a custom transaction controller,
+	// for a Token Creation Transaction. It allows the definition of a new custom token.
+	TokenCreationTransactionController struct {
+		// TransactionVersion is used to validate/set the transaction version
+		// of a token creation transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (tctc TokenCreationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	tctx, err := TokenCreationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenCreationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(tctx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (tctc TokenCreationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var tctx TokenCreationTransaction
+	err := rivbin.NewDecoder(r).Decode(&tctx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a TokenCreationTx: %v", err)
+	}
+	// return token creation tx as regular rivine tx data
+	return tctx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (tctc TokenCreationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	tctx, err := TokenCreationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a TokenCreationTx: %v", err)
+	}
+	return json.Marshal(tctx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (tctc TokenCreationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var tctx TokenCreationTransaction
+	err := json.Unmarshal(data, &tctx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a TokenCreationTx: %v", err)
+	}
+	// return token creation tx as regular rivine tx data
+	return tctx.TransactionData(), nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (tctc TokenCreationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	tctx, err := TokenCreationTransactionFromTransaction(t, tctc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a token creation tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierTokenCreationTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(tctx.CoinInputs),
+		tctx.Symbol,
+		tctx.MaxSupply,
+		tctx.IssuerCondition,
+		tctx.TokenOutputs,
+		tctx.RefundCoinOutput,
+		tctx.MinerFees,
+		tctx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (tctc TokenCreationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	tctx, err := TokenCreationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenCreationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierTokenCreationTransaction, tctx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (tctc TokenCreationTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	tcTxExtension, ok := extension.(*TokenCreationTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a TokenCreationTransaction")
+	}
+	data := types.CommonTransactionExtensionData{
+		UnlockConditions: tokenOutputConditions(tcTxExtension.TokenOutputs),
+	}
+	if tcTxExtension.IssuerCondition.ConditionType() != types.ConditionTypeNil {
+		data.UnlockConditions = append(data.UnlockConditions, tcTxExtension.IssuerCondition)
+	}
+	return data, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Token Issuance										///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// TokenIssuanceTransaction is to be used by the issuer of a custom token,
+	// as a medium in order to issue new tokens, adding to the supply of that token.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	TokenIssuanceTransaction struct {
+		// TokenID defines the token to issue new tokens for.
+		TokenID TokenID `json:"tokenid"`
+		// TokenOutputs defines the token outputs,
+		// which contain the freshly issued tokens.
+		TokenOutputs []TokenOutput `json:"tokenoutputs"`
+		// IssuerFulfillment defines the fulfillment which is used in order to
+		// fulfill the issuer condition of the token.
+		IssuerFulfillment types.UnlockFulfillmentProxy `json:"issuerfulfillment"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this token issuance transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// TokenIssuanceTransactionExtension defines the TokenIssuanceTransaction Extension Data
+	TokenIssuanceTransactionExtension struct {
+		TokenID           TokenID
+		TokenOutputs      []TokenOutput
+		IssuerFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// TokenIssuanceTransactionFromTransaction creates a TokenIssuanceTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `TokenIssuanceTransactionFromTransactionData` constructor.
+func TokenIssuanceTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (TokenIssuanceTransaction, error) {
+	if tx.Version != expectedVersion {
+		return TokenIssuanceTransaction{}, fmt.Errorf(
+			"a token issuance transaction requires tx version %d",
+			expectedVersion)
+	}
+	return TokenIssuanceTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// TokenIssuanceTransactionFromTransactionData creates a TokenIssuanceTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func TokenIssuanceTransactionFromTransactionData(txData types.TransactionData) (TokenIssuanceTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid TokenIssuanceTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*TokenIssuanceTransactionExtension)
+	if !ok {
+		return TokenIssuanceTransaction{}, errors.New("invalid extension data for a TokenIssuanceTransaction")
+	}
+	if len(extensionData.TokenOutputs) == 0 {
+		return TokenIssuanceTransaction{}, errors.New("at least one token output is required for a TokenIssuanceTransaction")
+	}
+	err := validateTokenTransactionCoinFlow(txData, "TokenIssuanceTransaction")
+	if err != nil {
+		return TokenIssuanceTransaction{}, err
+	}
+	// return the TokenIssuanceTransaction, with the data extracted from the TransactionData
+	return TokenIssuanceTransaction{
+		TokenID:           extensionData.TokenID,
+		TokenOutputs:      extensionData.TokenOutputs,
+		IssuerFulfillment: extensionData.IssuerFulfillment,
+		CoinInputs:        txData.CoinInputs,
+		RefundCoinOutput:  refundCoinOutputFromTransactionData(txData),
+		MinerFees:         txData.MinerFees,
+		ArbitraryData:     txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this TokenIssuanceTransaction
+// as regular rivine transaction data.
+func (titx *TokenIssuanceTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    titx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(titx.RefundCoinOutput),
+		MinerFees:     titx.MinerFees,
+		ArbitraryData: titx.ArbitraryData,
+		Extension: &TokenIssuanceTransactionExtension{
+			TokenID:           titx.TokenID,
+			TokenOutputs:      titx.TokenOutputs,
+			IssuerFulfillment: titx.IssuerFulfillment,
+		},
+	}
+}
+
+// Transaction returns this TokenIssuanceTransaction
+// as regular rivine transaction, using the given version as the type.
+func (titx *TokenIssuanceTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := titx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Token Issuance								///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Token Issuance Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = TokenIssuanceTransactionController{}
+	_ types.TransactionExtensionSigner           = TokenIssuanceTransactionController{}
+	_ types.TransactionSignatureHasher           = TokenIssuanceTransactionController{}
+	_ types.TransactionIDEncoder                 = TokenIssuanceTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = TokenIssuanceTransactionController{}
+)
+
+type (
+	// TokenIssuanceTransactionController defines a custom transaction controller,
+	// for a Token Issuance Transaction. It allows the issuance of new tokens.
+	TokenIssuanceTransactionController struct {
+		// TokenInfoGetter is used to get the issuer condition of a token.
+		TokenInfoGetter TokenInfoGetter
+
+		// TransactionVersion is used to validate/set the transaction version
+		// of a token issuance transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (titc TokenIssuanceTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	titx, err := TokenIssuanceTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenIssuanceTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(titx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (titc TokenIssuanceTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var titx TokenIssuanceTransaction
+	err := rivbin.NewDecoder(r).Decode(&titx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a TokenIssuanceTx: %v", err)
+	}
+	// return token issuance tx as regular rivine tx data
+	return titx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (titc TokenIssuanceTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	titx, err := TokenIssuanceTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a TokenIssuanceTx: %v", err)
+	}
+	return json.Marshal(titx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (titc TokenIssuanceTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var titx TokenIssuanceTransaction
+	err := json.Unmarshal(data, &titx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a TokenIssuanceTx: %v", err)
+	}
+	// return token issuance tx as regular rivine tx data
+	return titx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (titc TokenIssuanceTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid TokenIssuanceTransactionExtension,
+	// which contains the issuer fulfillment that can be used to fulfill the issuer condition of the token
+	tiTxExtension, ok := extension.(*TokenIssuanceTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a TokenIssuanceTransaction")
+	}
+
+	tokenInfo, err := titc.TokenInfoGetter.GetTokenInfo(tiTxExtension.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the info of token %s: %v", tiTxExtension.TokenID.String(), err)
+	}
+	err = sign(&tiTxExtension.IssuerFulfillment, tokenInfo.IssuerCondition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign issuer fulfillment of token issuance tx: %v", err)
+	}
+	return tiTxExtension, nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (titc TokenIssuanceTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	titx, err := TokenIssuanceTransactionFromTransaction(t, titc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a token issuance tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierTokenIssuanceTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(titx.CoinInputs),
+		titx.TokenID,
+		titx.TokenOutputs,
+		titx.RefundCoinOutput,
+		titx.MinerFees,
+		titx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (titc TokenIssuanceTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	titx, err := TokenIssuanceTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenIssuanceTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierTokenIssuanceTransaction, titx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (titc TokenIssuanceTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	tiTxExtension, ok := extension.(*TokenIssuanceTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a TokenIssuanceTransaction")
+	}
+	return types.CommonTransactionExtensionData{
+		UnlockConditions: tokenOutputConditions(tiTxExtension.TokenOutputs),
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION		///		Token Transfer										///
+///////////////////////////////////////////////////////////////////////////////////
+
+type (
+	// TokenTransferTransaction is to be used by anyone owning tokens,
+	// as a medium in order to transfer those tokens to other condition(s).
+	// The sum of the token inputs has to equal the sum of the token outputs.
+	//
+	// The miner fees of this transaction are paid using the base coin of the chain.
+	TokenTransferTransaction struct {
+		// TokenID defines the token which is transferred.
+		TokenID TokenID `json:"tokenid"`
+		// TokenInputs defines the token outputs that are spent.
+		TokenInputs []TokenInput `json:"tokeninputs"`
+		// TokenOutputs defines the newly created token outputs.
+		TokenOutputs []TokenOutput `json:"tokenoutputs"`
+		// CoinInputs are used to fund the miner fees of this transaction.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput defines an optional coin output,
+		// that can be used to refund in case it is needed.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+		// Minerfees, a fee paid for this token transfer transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// TokenTransferTransactionExtension defines the TokenTransferTransaction Extension Data
+	TokenTransferTransactionExtension struct {
+		TokenID      TokenID
+		TokenInputs  []TokenInput
+		TokenOutputs []TokenOutput
+	}
+)
+
+// TokenTransferTransactionFromTransaction creates a TokenTransferTransaction,
+// using a regular in-memory rivine transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `TokenTransferTransactionFromTransactionData` constructor.
+func TokenTransferTransactionFromTransaction(tx types.Transaction, expectedVersion types.TransactionVersion) (TokenTransferTransaction, error) {
+	if tx.Version != expectedVersion {
+		return TokenTransferTransaction{}, fmt.Errorf(
+			"a token transfer transaction requires tx version %d",
+			expectedVersion)
+	}
+	return TokenTransferTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// TokenTransferTransactionFromTransactionData creates a TokenTransferTransaction,
+// using the TransactionData from a regular in-memory rivine transaction.
+func TokenTransferTransactionFromTransactionData(txData types.TransactionData) (TokenTransferTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid TokenTransferTransactionExtension,
+	// which contains all the non-standard information for this transaction type.
+	extensionData, ok := txData.Extension.(*TokenTransferTransactionExtension)
+	if !ok {
+		return TokenTransferTransaction{}, errors.New("invalid extension data for a TokenTransferTransaction")
+	}
+	if len(extensionData.TokenInputs) == 0 || len(extensionData.TokenOutputs) == 0 {
+		return TokenTransferTransaction{}, errors.New("at least one token input and output is required for a TokenTransferTransaction")
+	}
+	err := validateTokenTransactionCoinFlow(txData, "TokenTransferTransaction")
+	if err != nil {
+		return TokenTransferTransaction{}, err
+	}
+	// return the TokenTransferTransaction, with the data extracted from the TransactionData
+	return TokenTransferTransaction{
+		TokenID:          extensionData.TokenID,
+		TokenInputs:      extensionData.TokenInputs,
+		TokenOutputs:     extensionData.TokenOutputs,
+		CoinInputs:       txData.CoinInputs,
+		RefundCoinOutput: refundCoinOutputFromTransactionData(txData),
+		MinerFees:        txData.MinerFees,
+		ArbitraryData:    txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this TokenTransferTransaction
+// as regular rivine transaction data.
+func (tttx *TokenTransferTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		CoinInputs:    tttx.CoinInputs,
+		CoinOutputs:   refundCoinOutputAsSlice(tttx.RefundCoinOutput),
+		MinerFees:     tttx.MinerFees,
+		ArbitraryData: tttx.ArbitraryData,
+		Extension: &TokenTransferTransactionExtension{
+			TokenID:      tttx.TokenID,
+			TokenInputs:  tttx.TokenInputs,
+			TokenOutputs: tttx.TokenOutputs,
+		},
+	}
+}
+
+// Transaction returns this TokenTransferTransaction
+// as regular rivine transaction, using the given version as the type.
+func (tttx *TokenTransferTransaction) Transaction(version types.TransactionVersion) types.Transaction {
+	txData := tttx.TransactionData()
+	return types.Transaction{
+		Version:       version,
+		CoinInputs:    txData.CoinInputs,
+		CoinOutputs:   txData.CoinOutputs,
+		MinerFees:     txData.MinerFees,
+		ArbitraryData: txData.ArbitraryData,
+		Extension:     txData.Extension,
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// TRANSACTION CONTROLLER	///		Token Transfer								///
+///////////////////////////////////////////////////////////////////////////////////
+
+// ensures at compile time that the Token Transfer Transaction Controller implement all desired interfaces
+var (
+	_ types.TransactionController                = TokenTransferTransactionController{}
+	_ types.TransactionExtensionSigner           = TokenTransferTransactionController{}
+	_ types.TransactionSignatureHasher           = TokenTransferTransactionController{}
+	_ types.TransactionIDEncoder                 = TokenTransferTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = TokenTransferTransactionController{}
+)
+
+type (
+	// TokenTransferTransactionController defines a custom transaction controller,
+	// for a Token Transfer Transaction. It allows the transfer of tokens.
+	TokenTransferTransactionController struct {
+		// TokenInfoGetter is used to get the token outputs spent by a transfer.
+		TokenInfoGetter TokenInfoGetter
+
+		// TransactionVersion is used to validate/set the transaction version
+		// of a token transfer transaction.
+		TransactionVersion types.TransactionVersion
+	}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (tttc TokenTransferTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	tttx, err := TokenTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenTransferTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(tttx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (tttc TokenTransferTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var tttx TokenTransferTransaction
+	err := rivbin.NewDecoder(r).Decode(&tttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a TokenTransferTx: %v", err)
+	}
+	// return token transfer tx as regular rivine tx data
+	return tttx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (tttc TokenTransferTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	tttx, err := TokenTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a TokenTransferTx: %v", err)
+	}
+	return json.Marshal(tttx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (tttc TokenTransferTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var tttx TokenTransferTransaction
+	err := json.Unmarshal(data, &tttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a TokenTransferTx: %v", err)
+	}
+	// return token transfer tx as regular rivine tx data
+	return tttx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (tttc TokenTransferTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid TokenTransferTransactionExtension,
+	// which contains the token inputs that have to be signed
+	ttTxExtension, ok := extension.(*TokenTransferTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a TokenTransferTransaction")
+	}
+
+	for index := range ttTxExtension.TokenInputs {
+		ti := &ttTxExtension.TokenInputs[index]
+		parent, err := tttc.TokenInfoGetter.GetTokenOutput(ti.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent token output %s: %v", ti.ParentID.String(), err)
+		}
+		err = sign(&ti.Fulfillment, parent.Condition, SpecifierTokenInput, uint64(index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign token input #%d of token transfer tx: %v", index, err)
+		}
+	}
+	return ttTxExtension, nil
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (tttc TokenTransferTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	tttx, err := TokenTransferTransactionFromTransaction(t, tttc.TransactionVersion)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a token transfer tx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierTokenTransferTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	tokenParentIDSlice := make([]TokenOutputID, 0, len(tttx.TokenInputs))
+	for _, ti := range tttx.TokenInputs {
+		tokenParentIDSlice = append(tokenParentIDSlice, ti.ParentID)
+	}
+
+	enc.EncodeAll(
+		coinInputParentIDs(tttx.CoinInputs),
+		tttx.TokenID,
+		tokenParentIDSlice,
+		tttx.TokenOutputs,
+		tttx.RefundCoinOutput,
+		tttx.MinerFees,
+		tttx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (tttc TokenTransferTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	tttx, err := TokenTransferTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a TokenTransferTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierTokenTransferTransaction, tttx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (tttc TokenTransferTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	ttTxExtension, ok := extension.(*TokenTransferTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a TokenTransferTransaction")
+	}
+	return types.CommonTransactionExtensionData{
+		UnlockConditions: tokenOutputConditions(ttTxExtension.TokenOutputs),
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////////
+// UTILITIES																	///
+///////////////////////////////////////////////////////////////////////////////////
+
+// validateTokenTransactionCoinFlow validates the base coin flow of a token transaction,
+// which only exists in order to pay the miner fees.
+func validateTokenTransactionCoinFlow(txData types.TransactionData, txName string) error {
+	if len(txData.CoinOutputs) > 1 {
+		return fmt.Errorf("maximum one coin output is allowed for a %s", txName)
+	}
+	if len(txData.MinerFees) == 0 {
+		return fmt.Errorf("at least one miner fee is required for a %s", txName)
+	}
+	if len(txData.CoinInputs) == 0 {
+		return fmt.Errorf("at least one coin input is required for a %s", txName)
+	}
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return fmt.Errorf("no block stake inputs/outputs are allowed in a %s", txName)
+	}
+	return nil
+}
+
+func refundCoinOutputFromTransactionData(txData types.TransactionData) *types.CoinOutput {
+	if len(txData.CoinOutputs) == 0 {
+		return nil
+	}
+	return &txData.CoinOutputs[0]
+}
+
+func refundCoinOutputAsSlice(co *types.CoinOutput) []types.CoinOutput {
+	if co == nil {
+		return nil
+	}
+	return []types.CoinOutput{*co}
+}
+
+func coinInputParentIDs(inputs []types.CoinInput) []types.CoinOutputID {
+	parentIDSlice := make([]types.CoinOutputID, 0, len(inputs))
+	for _, ci := range inputs {
+		parentIDSlice = append(parentIDSlice, ci.ParentID)
+	}
+	return parentIDSlice
+}
+
+func tokenOutputConditions(outputs []TokenOutput) []types.UnlockConditionProxy {
+	conditions := make([]types.UnlockConditionProxy, 0, len(outputs))
+	for _, to := range outputs {
+		conditions = append(conditions, to.Condition)
+	}
+	return conditions
+}
@@ -0,0 +1,253 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	testTokenCreationTxVersion types.TransactionVersion = 0xc0
+	testTokenIssuanceTxVersion types.TransactionVersion = 0xc1
+	testTokenTransferTxVersion types.TransactionVersion = 0xc2
+)
+
+func testUnlockHash(t *testing.T, str string) types.UnlockHash {
+	var uh types.UnlockHash
+	err := uh.LoadString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uh
+}
+
+func testTransactions(t *testing.T) []types.Transaction {
+	uhA := testUnlockHash(t, "0112210f9efa5441ab705226b0628679ed190eb4588b662991747ea3809d93932c7b41cbe4b732")
+	uhB := testUnlockHash(t, "01450aeb140c58012cb4afb48e068f976272fefa44ffe0991a8a4350a3687558d66c8fc753c37e")
+
+	coinInputs := []types.CoinInput{
+		{
+			ParentID: types.CoinOutputID{1, 2, 3},
+			Fulfillment: types.NewFulfillment(&types.SingleSignatureFulfillment{
+				PublicKey: types.PublicKey{Algorithm: types.SignatureAlgoEd25519, Key: bytes.Repeat([]byte{4}, 32)},
+				Signature: bytes.Repeat([]byte{5}, 64),
+			}),
+		},
+	}
+	refund := &types.CoinOutput{
+		Value:     types.NewCurrency64(42),
+		Condition: types.NewCondition(types.NewUnlockHashCondition(uhA)),
+	}
+	minerFees := []types.Currency{types.NewCurrency64(100)}
+	tokenOutputs := []TokenOutput{
+		{Value: types.NewCurrency64(1000), Condition: types.NewCondition(types.NewUnlockHashCondition(uhA))},
+		{Value: types.NewCurrency64(500), Condition: types.NewCondition(types.NewUnlockHashCondition(uhB))},
+	}
+
+	creation := TokenCreationTransaction{
+		Symbol:          "TKN",
+		MaxSupply:       types.NewCurrency64(1000000),
+		IssuerCondition: types.NewCondition(types.NewUnlockHashCondition(uhA)),
+		TokenOutputs:    tokenOutputs,
+		CoinInputs:      coinInputs,
+		MinerFees:       minerFees,
+		ArbitraryData:   []byte("token creation"),
+	}
+	issuance := TokenIssuanceTransaction{
+		TokenID:      TokenID{6},
+		TokenOutputs: tokenOutputs[:1],
+		IssuerFulfillment: types.NewFulfillment(&types.SingleSignatureFulfillment{
+			PublicKey: types.PublicKey{Algorithm: types.SignatureAlgoEd25519, Key: bytes.Repeat([]byte{7}, 32)},
+			Signature: bytes.Repeat([]byte{8}, 64),
+		}),
+		CoinInputs:       coinInputs,
+		RefundCoinOutput: refund,
+		MinerFees:        minerFees,
+	}
+	transfer := TokenTransferTransaction{
+		TokenID: TokenID{6},
+		TokenInputs: []TokenInput{
+			{
+				ParentID: TokenOutputID{9},
+				Fulfillment: types.NewFulfillment(&types.SingleSignatureFulfillment{
+					PublicKey: types.PublicKey{Algorithm: types.SignatureAlgoEd25519, Key: bytes.Repeat([]byte{10}, 32)},
+					Signature: bytes.Repeat([]byte{11}, 64),
+				}),
+			},
+		},
+		TokenOutputs:     tokenOutputs,
+		CoinInputs:       coinInputs,
+		RefundCoinOutput: refund,
+		MinerFees:        minerFees,
+		ArbitraryData:    []byte("token transfer"),
+	}
+
+	return []types.Transaction{
+		creation.Transaction(testTokenCreationTxVersion),
+		issuance.Transaction(testTokenIssuanceTxVersion),
+		transfer.Transaction(testTokenTransferTxVersion),
+	}
+}
+
+func registerTestTransactionVersions() func() {
+	types.RegisterTransactionVersion(testTokenCreationTxVersion, TokenCreationTransactionController{TransactionVersion: testTokenCreationTxVersion})
+	types.RegisterTransactionVersion(testTokenIssuanceTxVersion, TokenIssuanceTransactionController{TransactionVersion: testTokenIssuanceTxVersion})
+	types.RegisterTransactionVersion(testTokenTransferTxVersion, TokenTransferTransactionController{TransactionVersion: testTokenTransferTxVersion})
+	return func() {
+		types.RegisterTransactionVersion(testTokenCreationTxVersion, nil)
+		types.RegisterTransactionVersion(testTokenIssuanceTxVersion, nil)
+		types.RegisterTransactionVersion(testTokenTransferTxVersion, nil)
+	}
+}
+
+func TestTokenTransactionsJSONEncoding(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		b, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		var decodedTx types.Transaction
+		err = json.Unmarshal(b, &decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if tx.ID() != decodedTx.ID() {
+			t.Error(idx, "unexpected ID after JSON round trip:", tx.ID(), "!=", decodedTx.ID())
+		}
+		b2, err := json.Marshal(decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !bytes.Equal(b, b2) {
+			t.Error(idx, "unexpected JSON after round trip:", string(b), "!=", string(b2))
+		}
+	}
+}
+
+func TestTokenTransactionsBinaryEncoding(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		b, err := siabin.Marshal(tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		var decodedTx types.Transaction
+		err = siabin.Unmarshal(b, &decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if tx.ID() != decodedTx.ID() {
+			t.Error(idx, "unexpected ID after binary round trip:", tx.ID(), "!=", decodedTx.ID())
+		}
+		b2, err := siabin.Marshal(decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !bytes.Equal(b, b2) {
+			t.Error(idx, "unexpected binary encoding after round trip")
+		}
+	}
+}
+
+func TestTokenTransactionsSignatureHashIgnoresFulfillments(t *testing.T) {
+	defer registerTestTransactionVersions()()
+
+	for idx, tx := range testTransactions(t) {
+		hash, err := tx.SignatureHash()
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		// wipe all fulfillments, the signature hash should remain the same
+		tx.CoinInputs = []types.CoinInput{{ParentID: tx.CoinInputs[0].ParentID}}
+		switch ext := tx.Extension.(type) {
+		case *TokenIssuanceTransactionExtension:
+			ext.IssuerFulfillment = types.UnlockFulfillmentProxy{}
+		case *TokenTransferTransactionExtension:
+			ext.TokenInputs = []TokenInput{{ParentID: ext.TokenInputs[0].ParentID}}
+		}
+		wipedHash, err := tx.SignatureHash()
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if hash != wipedHash {
+			t.Error(idx, "signature hash changed after wiping fulfillments")
+		}
+	}
+}
+
+func TestTokenTransactionsInvalidCoinFlow(t *testing.T) {
+	for idx, tx := range testTransactions(t) {
+		txData := types.TransactionData{
+			CoinInputs:    tx.CoinInputs,
+			CoinOutputs:   tx.CoinOutputs,
+			MinerFees:     tx.MinerFees,
+			ArbitraryData: tx.ArbitraryData,
+			Extension:     tx.Extension,
+		}
+		if err := validateTokenTransactionCoinFlow(txData, "test"); err != nil {
+			t.Fatal(idx, "unexpected error for valid coin flow:", err)
+		}
+		invalidTxData := txData
+		invalidTxData.MinerFees = nil
+		if err := validateTokenTransactionCoinFlow(invalidTxData, "test"); err == nil {
+			t.Error(idx, "expected error for missing miner fees")
+		}
+		invalidTxData = txData
+		invalidTxData.CoinInputs = nil
+		if err := validateTokenTransactionCoinFlow(invalidTxData, "test"); err == nil {
+			t.Error(idx, "expected error for missing coin inputs")
+		}
+		invalidTxData = txData
+		invalidTxData.CoinOutputs = make([]types.CoinOutput, 2)
+		if err := validateTokenTransactionCoinFlow(invalidTxData, "test"); err == nil {
+			t.Error(idx, "expected error for multiple coin outputs")
+		}
+		invalidTxData = txData
+		invalidTxData.BlockStakeOutputs = make([]types.BlockStakeOutput, 1)
+		if err := validateTokenTransactionCoinFlow(invalidTxData, "test"); err == nil {
+			t.Error(idx, "expected error for block stake outputs")
+		}
+	}
+}
+
+func TestTokenInfoSupplyRules(t *testing.T) {
+	info := TokenInfo{
+		Symbol:    "TKN",
+		MaxSupply: types.NewCurrency64(100),
+		Supply:    types.NewCurrency64(60),
+	}
+	if info.IsIssuable() {
+		t.Error("token without issuer condition should not be issuable")
+	}
+	if err := info.CanIssue(types.NewCurrency64(40)); err != nil {
+		t.Error("issuing up to the max supply should be allowed:", err)
+	}
+	if err := info.CanIssue(types.NewCurrency64(41)); err == nil {
+		t.Error("issuing past the max supply should not be allowed")
+	}
+	info.MaxSupply = types.ZeroCurrency
+	if err := info.CanIssue(types.NewCurrency64(1000000)); err != nil {
+		t.Error("a zero max supply should allow an unlimited supply:", err)
+	}
+}
+
+func TestTokenOutputIDFromTransactionID(t *testing.T) {
+	txID := types.TransactionID{1}
+	ids := map[TokenOutputID]struct{}{}
+	for index := uint64(0); index < 8; index++ {
+		id := TokenOutputIDFromTransactionID(txID, index)
+		if _, ok := ids[id]; ok {
+			t.Fatal("duplicate token output ID for index", index)
+		}
+		ids[id] = struct{}{}
+	}
+	if TokenOutputIDFromTransactionID(txID, 0) == TokenOutputIDFromTransactionID(types.TransactionID{2}, 0) {
+		t.Error("token output IDs of different transactions should differ")
+	}
+}
@@ -0,0 +1,303 @@
+package txversions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	pluginDBVersion = "1.0.0.0"
+	pluginDBHeader  = "TransactionVersionsPlugin"
+)
+
+// registry errors
+var (
+	// ErrReservedTransactionVersion is returned in case an extension is registered
+	// for one of the transaction versions reserved by Rivine itself.
+	ErrReservedTransactionVersion = errors.New("transaction version is reserved by rivine")
+	// ErrTransactionVersionExists is returned in case an extension is registered
+	// for a transaction version which is already registered by another extension.
+	ErrTransactionVersionExists = errors.New("an extension is already registered for that transaction version")
+	// ErrExtensionNameExists is returned in case an extension is registered
+	// using a name which is already used by another extension.
+	ErrExtensionNameExists = errors.New("an extension with that name is already registered")
+	// ErrRegistryInitialized is returned in case an extension is registered,
+	// after the registry was already registered as a plugin to the consensus set.
+	ErrRegistryInitialized = errors.New("extensions cannot be registered once the registry is initialized")
+	// ErrUnknownExtension is returned in case an extension is not registered.
+	ErrUnknownExtension = errors.New("unknown transaction version extension")
+)
+
+type (
+	// Extension defines a custom transaction version, registered from a package outside of Rivine.
+	// It bundles the transaction controller (defining the encoding and signing of the transaction version),
+	// with the validation rules and state hooks used by the consensus set for that transaction version.
+	//
+	// Each extension gets its own (extension-data) bucket in the consensus database,
+	// which is passed (as a bucket scoped to that extension) to all its validators and hooks.
+	Extension struct {
+		// Name of the extension, used as the name of its bucket, required.
+		Name string
+		// Version is the transaction version defined by this extension, required.
+		Version types.TransactionVersion
+		// Controller of the transaction version, required.
+		Controller types.TransactionController
+
+		// Buckets are the names of the (sub) buckets to create within the bucket of the extension, optional.
+		Buckets [][]byte
+		// Validators are the validation rules applied to all transactions of the extension's version, optional.
+		Validators []modules.PluginTransactionValidationFunction
+		// ApplyTransaction is called for each applied transaction of the extension's version, optional.
+		ApplyTransaction func(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error
+		// RevertTransaction is called for each reverted transaction of the extension's version, optional.
+		RevertTransaction func(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error
+	}
+
+	// Registry is a registry of custom transaction versions (extensions),
+	// allowing chains to define their own transaction versions without modifying Rivine.
+	// It implements modules.ConsensusSetPlugin, and is to be registered to the consensus set,
+	// once all extensions are registered to it.
+	Registry struct {
+		mu                 sync.RWMutex
+		extensions         map[types.TransactionVersion]Extension
+		initialized        bool
+		storage            modules.PluginViewStorage
+		unregisterCallback modules.PluginUnregisterCallback
+	}
+)
+
+// NewRegistry creates a new (empty) transaction version registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		extensions: make(map[types.TransactionVersion]Extension),
+	}
+}
+
+// Register registers an extension, registering its transaction controller
+// as the controller for its transaction version as well (see types.RegisterTransactionVersion).
+// Extensions have to be registered prior to registering the registry to the consensus set.
+func (r *Registry) Register(ext Extension) error {
+	if ext.Name == "" {
+		return errors.New("extension name cannot be empty")
+	}
+	if ext.Controller == nil {
+		return fmt.Errorf("extension %s has no transaction controller", ext.Name)
+	}
+	if ext.Version == types.TransactionVersionZero || ext.Version == types.TransactionVersionOne || ext.Version == types.TransactionVersionTwo || ext.Version == types.TransactionVersionThree {
+		return ErrReservedTransactionVersion
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.initialized {
+		return ErrRegistryInitialized
+	}
+	if _, ok := r.extensions[ext.Version]; ok {
+		return ErrTransactionVersionExists
+	}
+	for _, other := range r.extensions {
+		if other.Name == ext.Name {
+			return ErrExtensionNameExists
+		}
+	}
+	r.extensions[ext.Version] = ext
+	types.RegisterTransactionVersion(ext.Version, ext.Controller)
+	return nil
+}
+
+// Extension returns the extension registered for the given transaction version.
+func (r *Registry) Extension(version types.TransactionVersion) (Extension, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ext, ok := r.extensions[version]
+	return ext, ok
+}
+
+// Extensions returns all registered extensions, sorted by transaction version.
+func (r *Registry) Extensions() []Extension {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	exts := make([]Extension, 0, len(r.extensions))
+	for _, ext := range r.extensions {
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		return exts[i].Version < exts[j].Version
+	})
+	return exts
+}
+
+// View gives read-only access to the bucket of the extension registered for the given transaction version.
+func (r *Registry) View(version types.TransactionVersion, callback func(bucket *bolt.Bucket) error) error {
+	ext, ok := r.Extension(version)
+	if !ok {
+		return ErrUnknownExtension
+	}
+	r.mu.RLock()
+	storage := r.storage
+	r.mu.RUnlock()
+	if storage == nil {
+		return errors.New("registry is not yet registered to the consensus set")
+	}
+	return storage.View(func(bucket *bolt.Bucket) error {
+		extBucket := bucket.Bucket([]byte(ext.Name))
+		if extBucket == nil {
+			return fmt.Errorf("bucket of extension %s does not exist", ext.Name)
+		}
+		return callback(extBucket)
+	})
+}
+
+// InitPlugin initializes the bucket of each registered extension,
+// creating the buckets of extensions which didn't exist yet.
+func (r *Registry) InitPlugin(metadata *persist.Metadata, bucket *bolt.Bucket, storage modules.PluginViewStorage, unregisterCallback modules.PluginUnregisterCallback) (persist.Metadata, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if metadata == nil {
+		metadata = &persist.Metadata{
+			Version: pluginDBVersion,
+			Header:  pluginDBHeader,
+		}
+	} else if metadata.Version != pluginDBVersion {
+		return persist.Metadata{}, errors.New("There is only 1 version of this plugin, version mismatch")
+	} else if metadata.Header != pluginDBHeader {
+		return persist.Metadata{}, errors.New("There is only 1 header of this plugin, header mismatch")
+	}
+	for _, ext := range r.extensions {
+		extBucket, err := bucket.CreateBucketIfNotExists([]byte(ext.Name))
+		if err != nil {
+			return persist.Metadata{}, fmt.Errorf("failed to create bucket of extension %s: %v", ext.Name, err)
+		}
+		for _, name := range ext.Buckets {
+			_, err = extBucket.CreateBucketIfNotExists(name)
+			if err != nil {
+				return persist.Metadata{}, fmt.Errorf("failed to create %s bucket of extension %s: %v", string(name), ext.Name, err)
+			}
+		}
+	}
+	r.storage = storage
+	r.unregisterCallback = unregisterCallback
+	r.initialized = true
+	return *metadata, nil
+}
+
+// ApplyBlock applies all extension transactions of a block.
+func (r *Registry) ApplyBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	for idx, txn := range block.Transactions {
+		err = r.ApplyTransaction(consensusTransaction(block, idx), bucket)
+		if err != nil {
+			return fmt.Errorf("failed to apply tx %s: %v", txn.ID().String(), err)
+		}
+	}
+	return nil
+}
+
+// ApplyTransaction applies a transaction, if it is of a version registered by an extension.
+func (r *Registry) ApplyTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	ext, ok := r.Extension(txn.Version)
+	if !ok || ext.ApplyTransaction == nil {
+		return nil
+	}
+	return ext.ApplyTransaction(txn, extensionBucket(bucket, ext))
+}
+
+// RevertBlock reverts all extension transactions of a block, in reverse order.
+func (r *Registry) RevertBlock(block modules.ConsensusBlock, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	var err error
+	for idx := len(block.Transactions) - 1; idx >= 0; idx-- {
+		err = r.RevertTransaction(consensusTransaction(block, idx), bucket)
+		if err != nil {
+			return fmt.Errorf("failed to revert tx %s: %v", block.Transactions[idx].ID().String(), err)
+		}
+	}
+	return nil
+}
+
+// RevertTransaction reverts a transaction, if it is of a version registered by an extension.
+func (r *Registry) RevertTransaction(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+	if bucket == nil {
+		return errors.New("plugin bucket does not exist")
+	}
+	ext, ok := r.Extension(txn.Version)
+	if !ok || ext.RevertTransaction == nil {
+		return nil
+	}
+	return ext.RevertTransaction(txn, extensionBucket(bucket, ext))
+}
+
+// TransactionValidatorVersionFunctionMapping returns the validators of all registered extensions,
+// each validator receiving the bucket of its extension rather than the bucket of the registry.
+func (r *Registry) TransactionValidatorVersionFunctionMapping() map[types.TransactionVersion][]modules.PluginTransactionValidationFunction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	mapping := make(map[types.TransactionVersion][]modules.PluginTransactionValidationFunction, len(r.extensions))
+	for version, ext := range r.extensions {
+		validators := make([]modules.PluginTransactionValidationFunction, 0, len(ext.Validators))
+		for _, validator := range ext.Validators {
+			validators = append(validators, scopedValidator(ext, validator))
+		}
+		mapping[version] = validators
+	}
+	return mapping
+}
+
+// TransactionValidators returns no validators, as all validators of extensions are version-specific.
+func (r *Registry) TransactionValidators() []modules.PluginTransactionValidationFunction {
+	return nil
+}
+
+// Close releases the storage of the registry.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.storage == nil {
+		return nil
+	}
+	return r.storage.Close()
+}
+
+func scopedValidator(ext Extension, validator modules.PluginTransactionValidationFunction) modules.PluginTransactionValidationFunction {
+	return func(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+		return validator(tx, ctx, extensionBucket(bucket, ext))
+	}
+}
+
+// extensionBucket returns the bucket of an extension, nested within the bucket of the registry.
+func extensionBucket(bucket *persist.LazyBoltBucket, ext Extension) *persist.LazyBoltBucket {
+	return persist.NewLazyBoltBucket(func() (*bolt.Bucket, error) {
+		b, err := bucket.Bucket([]byte(ext.Name))
+		if err != nil {
+			return nil, fmt.Errorf("bucket of extension %s does not exist: %v", ext.Name, err)
+		}
+		return b, nil
+	})
+}
+
+func consensusTransaction(block modules.ConsensusBlock, idx int) modules.ConsensusTransaction {
+	return modules.ConsensusTransaction{
+		Transaction:            block.Transactions[idx],
+		BlockHeight:            block.Height,
+		BlockTime:              block.Timestamp,
+		SequenceID:             uint16(idx),
+		SpentCoinOutputs:       block.SpentCoinOutputs,
+		SpentBlockStakeOutputs: block.SpentBlockStakeOutputs,
+	}
+}
@@ -0,0 +1,167 @@
+package txversions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	const version = types.TransactionVersion(200)
+	defer types.RegisterTransactionVersion(version, nil)
+
+	r := NewRegistry()
+	ext := Extension{
+		Name:       "foo",
+		Version:    version,
+		Controller: types.DefaultTransactionController{},
+	}
+	if err := r.Register(ext); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ext); err != ErrTransactionVersionExists {
+		t.Error("expected version exists error, got:", err)
+	}
+	if err := r.Register(Extension{Name: "foo", Version: version + 1, Controller: ext.Controller}); err != ErrExtensionNameExists {
+		t.Error("expected name exists error, got:", err)
+	}
+	if err := r.Register(Extension{Name: "bar", Version: types.TransactionVersionOne, Controller: ext.Controller}); err != ErrReservedTransactionVersion {
+		t.Error("expected reserved version error, got:", err)
+	}
+	if err := r.Register(Extension{Name: "bar", Version: version + 1}); err == nil {
+		t.Error("registered an extension without a transaction controller")
+	}
+	if exts := r.Extensions(); len(exts) != 1 || exts[0].Name != "foo" {
+		t.Errorf("unexpected extensions: %v", exts)
+	}
+}
+
+func TestRegistryPlugin(t *testing.T) {
+	const version = types.TransactionVersion(201)
+	defer types.RegisterTransactionVersion(version, nil)
+
+	var (
+		bucketName = []byte("applied")
+		errInvalid = errors.New("invalid")
+	)
+	r := NewRegistry()
+	err := r.Register(Extension{
+		Name:       "counter",
+		Version:    version,
+		Controller: types.DefaultTransactionController{},
+		Buckets:    [][]byte{bucketName},
+		Validators: []modules.PluginTransactionValidationFunction{
+			func(tx modules.ConsensusTransaction, _ types.TransactionValidationContext, bucket *persist.LazyBoltBucket) error {
+				// validators receive the bucket of their extension
+				if _, err := bucket.Bucket(bucketName); err != nil {
+					return err
+				}
+				if len(tx.ArbitraryData) > 0 {
+					return errInvalid
+				}
+				return nil
+			},
+		},
+		ApplyTransaction: func(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+			b, err := bucket.Bucket(bucketName)
+			if err != nil {
+				return err
+			}
+			id := txn.ID()
+			return b.Put(id[:], []byte{1})
+		},
+		RevertTransaction: func(txn modules.ConsensusTransaction, bucket *persist.LazyBoltBucket) error {
+			b, err := bucket.Bucket(bucketName)
+			if err != nil {
+				return err
+			}
+			id := txn.ID()
+			return b.Delete(id[:])
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testDir := build.TempDir("txversions", t.Name())
+	if err = os.MkdirAll(testDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(testDir, "consensus.db"), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	block := modules.ConsensusBlock{
+		Block: types.Block{Transactions: []types.Transaction{
+			{Version: types.TransactionVersionOne},
+			{Version: version},
+		}},
+	}
+	extTxID := block.Transactions[1].ID()
+	countApplied := func(bucket *bolt.Bucket) int {
+		n := 0
+		bucket.Bucket([]byte("counter")).Bucket(bucketName).ForEach(func(k, _ []byte) error {
+			if string(k) == string(extTxID[:]) {
+				n++
+			}
+			return nil
+		})
+		return n
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte("plugin"))
+		if err != nil {
+			return err
+		}
+		if _, err = r.InitPlugin(nil, bucket, nil, nil); err != nil {
+			return err
+		}
+		lazyBucket := persist.NewLazyBoltBucket(func() (*bolt.Bucket, error) { return bucket, nil })
+
+		validators := r.TransactionValidatorVersionFunctionMapping()[version]
+		if len(validators) != 1 {
+			t.Fatalf("unexpected amount of validators: %d", len(validators))
+		}
+		cTxn := modules.ConsensusTransaction{Transaction: block.Transactions[1]}
+		if err = validators[0](cTxn, types.TransactionValidationContext{}, lazyBucket); err != nil {
+			t.Error("unexpected validation error:", err)
+		}
+		cTxn.ArbitraryData = []byte("data")
+		if err = validators[0](cTxn, types.TransactionValidationContext{}, lazyBucket); err != errInvalid {
+			t.Error("expected invalid error, got:", err)
+		}
+
+		if err = r.ApplyBlock(block, lazyBucket); err != nil {
+			return err
+		}
+		if n := countApplied(bucket); n != 1 {
+			t.Errorf("expected extension tx to be applied once, got: %d", n)
+		}
+		if err = r.RevertBlock(block, lazyBucket); err != nil {
+			return err
+		}
+		if n := countApplied(bucket); n != 0 {
+			t.Errorf("expected extension tx to be reverted, got: %d", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = r.Register(Extension{Name: "late", Version: version + 1, Controller: types.DefaultTransactionController{}}); err != ErrRegistryInitialized {
+		t.Error("expected registry initialized error, got:", err)
+	}
+}
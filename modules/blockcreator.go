@@ -1,6 +1,11 @@
 package modules
 
-import "io"
+import (
+	"errors"
+	"io"
+
+	"github.com/threefoldtech/rivine/types"
+)
 
 const (
 	// BlockCreatorDir is the name of the directory that is used to store the BlockCreator's
@@ -8,7 +13,57 @@ const (
 	BlockCreatorDir = "blockcreator"
 )
 
-// The BlockCreator interface provides access to BlockCreator features.
-type BlockCreator interface {
-	io.Closer
-}
+var (
+	// ErrClockSkewExceeded is returned (as a warning) in case the local clock differs too much
+	// from the network-adjusted time, in which case the block creator refuses to create blocks.
+	ErrClockSkewExceeded = errors.New("local clock skew exceeds the maximum allowed skew, block creation is paused")
+)
+
+type (
+	// The BlockCreator interface provides access to BlockCreator features.
+	BlockCreator interface {
+		io.Closer
+
+		// RegisterPreAssemblyHook registers a hook which is consulted
+		// for every unconfirmed transaction, prior to assembling a block.
+		RegisterPreAssemblyHook(BlockCreatorPreAssemblyHook)
+		// RegisterPreSubmitHook registers a hook which is consulted
+		// for every created block, prior to submitting it.
+		RegisterPreSubmitHook(BlockCreatorPreSubmitHook)
+
+		// Status returns the current status of the block creator.
+		Status() BlockCreatorStatus
+	}
+
+	// BlockCreatorStatus contains the current status of a block creator.
+	BlockCreatorStatus struct {
+		// DryRun is true in case the block creator never submits the blocks it creates.
+		DryRun bool `json:"dryrun"`
+		// ClockOffset is the median offset, in seconds, between the clocks of
+		// the connected peers and the local clock, as used by the block creator.
+		ClockOffset int64 `json:"clockoffset"`
+		// ClockSamples is the amount of peers the clock offset is based on.
+		ClockSamples int `json:"clocksamples"`
+		// ClockSkewExceeded is true in case the block creator refuses to create blocks,
+		// because the local clock differs too much from the network-adjusted time.
+		ClockSkewExceeded bool `json:"clockskewexceeded"`
+		// Warnings lists any issues an operator should be aware of.
+		Warnings []string `json:"warnings,omitempty"`
+	}
+
+	// BlockCreatorPreAssemblyHook allows a chain to enforce custom inclusion policies,
+	// deciding which unconfirmed transactions can be included in blocks created by this node.
+	BlockCreatorPreAssemblyHook interface {
+		// FilterTransaction returns an error in case the given unconfirmed transaction
+		// should not be included in a block. All transactions that are interdependent
+		// with a rejected transaction are excluded as well.
+		FilterTransaction(txn types.Transaction) error
+	}
+
+	// BlockCreatorPreSubmitHook allows a chain to validate a created block,
+	// prior to it being submitted to the consensus set.
+	BlockCreatorPreSubmitHook interface {
+		// ValidateBlock returns an error in case the created block should not be submitted.
+		ValidateBlock(block types.Block) error
+	}
+)
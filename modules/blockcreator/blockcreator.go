@@ -15,9 +15,10 @@ import (
 // BlockCreator participates in the Proof Of Block Stake protocol for creating new blocks
 type BlockCreator struct {
 	// Module dependencies
-	cs     modules.ConsensusSet
-	tpool  modules.TransactionPool
-	wallet modules.Wallet
+	cs      modules.ConsensusSet
+	tpool   modules.TransactionPool
+	wallet  modules.Wallet
+	gateway modules.Gateway
 
 	bcInfo    types.BlockchainInfo
 	chainCts  types.ChainConstants
@@ -26,8 +27,21 @@ type BlockCreator struct {
 	// Cache the synced state of the consensus set to avoid unnecessarily locking it
 	csSynced bool
 
+	// dryRun makes the block creator go through the entire block creation process,
+	// except that it never respends block stakes nor submits any created block.
+	dryRun bool
+
+	// last known clock status, as computed using the network-adjusted time
+	clockOffset       int64
+	clockSamples      int
+	clockSkewExceeded bool
+
 	unsolvedBlock *types.Block
 
+	// hooks registered by the chain, as to enforce custom block creation policies
+	preAssemblyHooks []modules.BlockCreatorPreAssemblyHook
+	preSubmitHooks   []modules.BlockCreatorPreSubmitHook
+
 	log        *persist.Logger
 	mu         sync.RWMutex
 	persist    persistence
@@ -72,7 +86,14 @@ func (b *BlockCreator) startupRescan() error {
 }
 
 // New returns a block creator that is collaborating in the pobs protocol.
-func New(cs modules.ConsensusSet, tpool modules.TransactionPool, w modules.Wallet, persistDir string, bcInfo types.BlockchainInfo, chainCts types.ChainConstants, verboseLogging bool) (*BlockCreator, error) {
+//
+// If dryRun is true, the block creator only logs the blocks it would have created,
+// without ever submitting them, which allows operators to validate their configuration.
+//
+// The gateway is optional. If given, the block creator uses the network-adjusted time
+// rather than the local system clock, and refuses to create blocks in case the
+// local clock skew exceeds MaxClockSkew.
+func New(cs modules.ConsensusSet, tpool modules.TransactionPool, w modules.Wallet, g modules.Gateway, persistDir string, bcInfo types.BlockchainInfo, chainCts types.ChainConstants, verboseLogging bool, dryRun bool) (*BlockCreator, error) {
 	// Create the block creator and its dependencies.
 	if cs == nil {
 		return nil, errors.New("A consensset is required to create a block creator")
@@ -86,9 +107,10 @@ func New(cs modules.ConsensusSet, tpool modules.TransactionPool, w modules.Walle
 
 	// Assemble the block creator.
 	b := &BlockCreator{
-		cs:     cs,
-		tpool:  tpool,
-		wallet: w,
+		cs:      cs,
+		tpool:   tpool,
+		wallet:  w,
+		gateway: g,
 
 		bcInfo:    bcInfo,
 		chainCts:  chainCts,
@@ -96,6 +118,8 @@ func New(cs modules.ConsensusSet, tpool modules.TransactionPool, w modules.Walle
 
 		unsolvedBlock: &types.Block{},
 
+		dryRun: dryRun,
+
 		persistDir: persistDir,
 	}
 
@@ -138,6 +162,10 @@ func New(cs modules.ConsensusSet, tpool modules.TransactionPool, w modules.Walle
 		return nil, errors.New("block creator could not save during startup: " + err.Error())
 	}
 
+	if dryRun {
+		b.log.Println("Block creator is running in dry-run mode, created blocks will not be submitted")
+	}
+
 	// Start the proof of block stake protocol
 	go b.SolveBlocks()
 
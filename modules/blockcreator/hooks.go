@@ -0,0 +1,75 @@
+package blockcreator
+
+import (
+	"fmt"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// RegisterPreAssemblyHook implements modules.BlockCreator.RegisterPreAssemblyHook
+//
+// The hook is consulted, together with all other registered pre-assembly hooks,
+// in the order they were registered, starting from the next transaction pool update.
+func (bc *BlockCreator) RegisterPreAssemblyHook(hook modules.BlockCreatorPreAssemblyHook) {
+	bc.mu.Lock()
+	bc.preAssemblyHooks = append(bc.preAssemblyHooks, hook)
+	bc.mu.Unlock()
+}
+
+// RegisterPreSubmitHook implements modules.BlockCreator.RegisterPreSubmitHook
+//
+// The hook is consulted, together with all other registered pre-submit hooks,
+// in the order they were registered.
+func (bc *BlockCreator) RegisterPreSubmitHook(hook modules.BlockCreatorPreSubmitHook) {
+	bc.mu.Lock()
+	bc.preSubmitHooks = append(bc.preSubmitHooks, hook)
+	bc.mu.Unlock()
+}
+
+// filterTransactionPackage returns an error in case any of the transactions
+// of the given package is rejected by a registered pre-assembly hook.
+//
+// The lock of the block creator is expected to be held by the caller.
+func (bc *BlockCreator) filterTransactionPackage(pkg modules.TransactionPackage) error {
+	for _, hook := range bc.preAssemblyHooks {
+		for _, txn := range pkg.Transactions {
+			if err := hook.FilterTransaction(txn); err != nil {
+				return fmt.Errorf("transaction %s rejected: %v", txn.ID().String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBlock returns an error in case the given block
+// is rejected by a registered pre-submit hook.
+func (bc *BlockCreator) validateBlock(block types.Block) error {
+	bc.mu.RLock()
+	hooks := bc.preSubmitHooks
+	bc.mu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook.ValidateBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArbitraryDataSizePolicy is a pre-assembly hook which rejects
+// all transactions that have more arbitrary data attached than the defined limit.
+type ArbitraryDataSizePolicy struct {
+	Limit uint64
+}
+
+// FilterTransaction implements modules.BlockCreatorPreAssemblyHook.FilterTransaction
+func (p ArbitraryDataSizePolicy) FilterTransaction(txn types.Transaction) error {
+	if size := uint64(len(txn.ArbitraryData)); size > p.Limit {
+		return fmt.Errorf("arbitrary data size of %d bytes exceeds the policy limit of %d bytes", size, p.Limit)
+	}
+	return nil
+}
+
+var (
+	_ modules.BlockCreatorPreAssemblyHook = ArbitraryDataSizePolicy{}
+)
@@ -0,0 +1,29 @@
+package blockcreator
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestArbitraryDataSizePolicy(t *testing.T) {
+	bc := &BlockCreator{}
+	bc.RegisterPreAssemblyHook(ArbitraryDataSizePolicy{Limit: 4})
+
+	small := types.Transaction{Version: types.TransactionVersionOne, ArbitraryData: []byte("data")}
+	large := types.Transaction{Version: types.TransactionVersionOne, ArbitraryData: []byte("too much data")}
+
+	err := bc.filterTransactionPackage(modules.TransactionPackage{
+		Transactions: []types.Transaction{small},
+	})
+	if err != nil {
+		t.Error("package with small arbitrary data should be accepted:", err)
+	}
+	err = bc.filterTransactionPackage(modules.TransactionPackage{
+		Transactions: []types.Transaction{small, large},
+	})
+	if err == nil {
+		t.Error("package with large arbitrary data should be rejected")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -34,21 +35,39 @@ func (bc *BlockCreator) SolveBlocks() {
 			bc.csSynced = true
 		}
 
+		// Refuse to create blocks in case our clock is too far off,
+		// as such blocks would either be rejected or would push the chain time off
+		now, skewExceeded := bc.networkAdjustedTime()
+		if skewExceeded {
+			bc.log.Println("WARNING:", modules.ErrClockSkewExceeded)
+			time.Sleep(8 * time.Second)
+			continue
+		}
+
 		// Try to solve a block for blocktimes of the next 10 seconds
-		now := time.Now().Unix()
 		bc.log.Debugln("[BC] Attempting to solve blocks")
 		b := bc.solveBlock(uint64(now), 10)
+		if b != nil {
+			if err := bc.validateBlock(*b); err != nil {
+				bc.log.Println("Solved block was rejected by a registered pre-submit hook:", err)
+				b = nil
+			}
+		}
 		if b != nil {
 			bjson, err := json.Marshal(b)
 			if err != nil {
 				bc.log.Println("Solved block but failed to JSON-marshal it for logging purposes:", err)
+			} else if bc.dryRun {
+				bc.log.Println("[DRY-RUN] Would have created block:", string(bjson))
 			} else {
 				bc.log.Println("Solved block:", string(bjson))
 			}
 
-			err = bc.submitBlock(*b)
-			if err != nil {
-				bc.log.Println("ERROR: An error occurred while submitting a solved block:", err)
+			if !bc.dryRun {
+				err = bc.submitBlock(*b)
+				if err != nil {
+					bc.log.Println("ERROR: An error occurred while submitting a solved block:", err)
+				}
 			}
 		}
 		//sleep a while before recalculating
@@ -97,10 +116,14 @@ func (bc *BlockCreator) solveBlock(startTime uint64, secondsInTheFuture uint64)
 			pobshashvalue.Div(pobshashvalue, ubso.Value.Big()) //TODO rivine : this div can be mul on the other side of the compare
 
 			if pobshashvalue.Cmp(target.Int()) == -1 {
-				err := bc.RespentBlockStake(ubso)
-				if err != nil {
-					bc.log.Printf("failed to respond block stake %q: %v", ubso.BlockStakeOutputID.String(), err)
-					return nil
+				// in dry-run mode the block stake is not respent,
+				// as to not touch the wallet nor the unsolved block
+				if !bc.dryRun {
+					err := bc.RespentBlockStake(ubso)
+					if err != nil {
+						bc.log.Printf("failed to respond block stake %q: %v", ubso.BlockStakeOutputID.String(), err)
+						return nil
+					}
 				}
 
 				bc.log.Debugln("\nSolved block with target", target)
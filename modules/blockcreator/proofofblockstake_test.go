@@ -1 +1,90 @@
 package blockcreator
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// solveTestConsensusSet is a consensus set with a target which is met by any block stake.
+type solveTestConsensusSet struct {
+	modules.ConsensusSet
+}
+
+func (solveTestConsensusSet) CurrentBlock() types.Block { return types.Block{} }
+func (solveTestConsensusSet) CalculateStakeModifier(types.BlockHeight, types.Block, types.BlockHeight) *big.Int {
+	return big.NewInt(0)
+}
+func (solveTestConsensusSet) ChildTarget(types.BlockID) (types.Target, bool) {
+	var target types.Target
+	for i := range target {
+		target[i] = 0xff
+	}
+	return target, true
+}
+
+// solveTestWallet is a wallet with a single block stake output,
+// failing to respend it.
+type solveTestWallet struct {
+	modules.Wallet
+	respent bool
+}
+
+func (w *solveTestWallet) GetUnspentBlockStakeOutputs() ([]types.UnspentBlockStakeOutput, error) {
+	return []types.UnspentBlockStakeOutput{{Value: types.NewCurrency64(1000)}}, nil
+}
+
+func (w *solveTestWallet) StartTransaction() modules.TransactionBuilder {
+	w.respent = true
+	return solveTestTransactionBuilder{}
+}
+
+type solveTestTransactionBuilder struct {
+	modules.TransactionBuilder
+}
+
+func (solveTestTransactionBuilder) SpendBlockStake(types.BlockStakeOutputID) error {
+	return errors.New("wallet is locked")
+}
+
+func TestSolveBlockDryRun(t *testing.T) {
+	txn := types.Transaction{Version: types.TransactionVersionOne, ArbitraryData: []byte("data")}
+	newBlockCreator := func(w modules.Wallet, dryRun bool) *BlockCreator {
+		return &BlockCreator{
+			cs:            solveTestConsensusSet{},
+			wallet:        w,
+			chainCts:      types.TestnetChainConstants(),
+			dryRun:        dryRun,
+			unsolvedBlock: &types.Block{Transactions: []types.Transaction{txn}},
+			log:           persist.NewLogger(types.DefaultBlockchainInfo(), ioutil.Discard, false),
+		}
+	}
+
+	// the block stake is respent when creating a block
+	w := new(solveTestWallet)
+	if b := newBlockCreator(w, false).solveBlock(1000, 10); b != nil || !w.respent {
+		t.Errorf("block stake isn't respent: %v", b)
+	}
+
+	// in dry-run mode a block is created without respending the block stake
+	w = new(solveTestWallet)
+	bc := newBlockCreator(w, true)
+	b := bc.solveBlock(1000, 10)
+	if b == nil {
+		t.Fatal("no block is created in dry-run mode")
+	}
+	if w.respent {
+		t.Error("block stake is respent in dry-run mode")
+	}
+	if len(b.Transactions) != 1 || b.Transactions[0].ID() != txn.ID() || len(bc.unsolvedBlock.Transactions) != 1 {
+		t.Errorf("unexpected transactions of the block created in dry-run mode: %v", b.Transactions)
+	}
+	if b.Timestamp < 1000 || b.Timestamp >= 1010 {
+		t.Errorf("unexpected timestamp of the block created in dry-run mode: %d", b.Timestamp)
+	}
+}
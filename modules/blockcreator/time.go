@@ -0,0 +1,58 @@
+package blockcreator
+
+import (
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+)
+
+const (
+	// MaxClockSkew is the maximum amount of seconds the local clock
+	// is allowed to differ from the network-adjusted time,
+	// prior to the block creator refusing to create blocks.
+	MaxClockSkew = 60
+
+	// minClockSamples is the minimum amount of peers required
+	// before the network-adjusted time is taken into account.
+	minClockSamples = 3
+)
+
+// networkAdjustedTime returns the current time, adjusted with the median clock offset
+// of the connected peers, and updates the clock status of the block creator.
+// The local system clock is used as-is in case no gateway is available,
+// or in case not enough peers are connected.
+func (bc *BlockCreator) networkAdjustedTime() (now int64, skewExceeded bool) {
+	now = time.Now().Unix()
+	if bc.gateway == nil {
+		return now, false
+	}
+	offset, samples := bc.gateway.ClockOffset()
+	if samples < minClockSamples {
+		offset = 0
+	}
+	skewExceeded = offset > MaxClockSkew || offset < -MaxClockSkew
+
+	bc.mu.Lock()
+	bc.clockOffset = offset
+	bc.clockSamples = samples
+	bc.clockSkewExceeded = skewExceeded
+	bc.mu.Unlock()
+
+	return now + offset, skewExceeded
+}
+
+// Status implements modules.BlockCreator.Status
+func (bc *BlockCreator) Status() modules.BlockCreatorStatus {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	status := modules.BlockCreatorStatus{
+		DryRun:            bc.dryRun,
+		ClockOffset:       bc.clockOffset,
+		ClockSamples:      bc.clockSamples,
+		ClockSkewExceeded: bc.clockSkewExceeded,
+	}
+	if bc.clockSkewExceeded {
+		status.Warnings = append(status.Warnings, modules.ErrClockSkewExceeded.Error())
+	}
+	return status
+}
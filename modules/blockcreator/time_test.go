@@ -0,0 +1,54 @@
+package blockcreator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+)
+
+// timeTestGateway is a gateway only reporting its clock offset.
+type timeTestGateway struct {
+	modules.Gateway
+	offset  int64
+	samples int
+}
+
+func (g timeTestGateway) ClockOffset() (int64, int) {
+	return g.offset, g.samples
+}
+
+func TestNetworkAdjustedTime(t *testing.T) {
+	testCases := []struct {
+		name           string
+		gateway        modules.Gateway
+		expectedOffset int64
+		skewExceeded   bool
+	}{
+		{"no gateway", nil, 0, false},
+		{"too few peers", timeTestGateway{offset: 3600, samples: minClockSamples - 1}, 0, false},
+		{"small offset", timeTestGateway{offset: -30, samples: minClockSamples}, -30, false},
+		{"clock ahead", timeTestGateway{offset: -MaxClockSkew - 1, samples: 8}, -MaxClockSkew - 1, true},
+		{"clock behind", timeTestGateway{offset: MaxClockSkew + 1, samples: 8}, MaxClockSkew + 1, true},
+	}
+	for _, testCase := range testCases {
+		bc := &BlockCreator{gateway: testCase.gateway}
+		before := time.Now().Unix()
+		now, skewExceeded := bc.networkAdjustedTime()
+		after := time.Now().Unix()
+		if now < before+testCase.expectedOffset || now > after+testCase.expectedOffset {
+			t.Errorf("%s: unexpected network-adjusted time %d, expected an offset of %d", testCase.name, now, testCase.expectedOffset)
+		}
+		if skewExceeded != testCase.skewExceeded {
+			t.Errorf("%s: expected clock skew exceeded to be %t", testCase.name, testCase.skewExceeded)
+		}
+
+		status := bc.Status()
+		if status.ClockSkewExceeded != testCase.skewExceeded || (len(status.Warnings) != 0) != testCase.skewExceeded {
+			t.Errorf("%s: unexpected status: %+v", testCase.name, status)
+		}
+		if testCase.gateway != nil && status.ClockOffset != testCase.expectedOffset {
+			t.Errorf("%s: expected clock offset %d, not %d", testCase.name, testCase.expectedOffset, status.ClockOffset)
+		}
+	}
+}
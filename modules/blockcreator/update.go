@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/threefoldtech/rivine/modules"
-	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -63,24 +62,29 @@ func (bc *BlockCreator) ReceiveUpdatedUnconfirmedTransactions(unconfirmedTransac
 		return nil
 	}
 
-	// Add transactions to the block until the block size limit is reached.
-	// Transactions are assumed to be in a sensible order.
-	var i int
-	remainingSize := int(bc.chainCts.BlockSizeLimit - 5e3) //check this 5k for the first extra
-	var (
-		err     error
-		txBytes []byte
-	)
-	for i = range unconfirmedTransactions {
-		txBytes, err = siabin.Marshal(unconfirmedTransactions[i])
-		if err != nil {
-			return fmt.Errorf("failed to (siabin) marshal tx %d: %v", i, err)
+	// Group the transactions in packages of interdependent transactions,
+	// such that dependent transaction chains are included atomically,
+	// prioritizing the packages which pay the highest fee rate.
+	packages, err := modules.NewTransactionPackages(unconfirmedTransactions)
+	if err != nil {
+		return fmt.Errorf("failed to compute transaction packages: %v", err)
+	}
+
+	// Add packages to the block until the block size limit is reached,
+	// skipping those packages that no longer fit or are rejected by a registered hook.
+	remainingSize := bc.chainCts.BlockSizeLimit - 5e3 //check this 5k for the first extra
+	var txns []types.Transaction
+	for _, pkg := range packages {
+		if pkg.Size > remainingSize {
+			continue
 		}
-		remainingSize -= len(txBytes)
-		if remainingSize < 0 {
-			break
+		if err = bc.filterTransactionPackage(pkg); err != nil {
+			bc.log.Debugf("Excluding package of %d transaction(s) from block: %v", len(pkg.Transactions), err)
+			continue
 		}
+		remainingSize -= pkg.Size
+		txns = append(txns, pkg.Transactions...)
 	}
-	bc.unsolvedBlock.Transactions = unconfirmedTransactions[:i+1]
+	bc.unsolvedBlock.Transactions = txns
 	return nil
 }
@@ -151,6 +151,10 @@ type (
 
 		SpentCoinOutputs       map[types.CoinOutputID]types.CoinOutput
 		SpentBlockStakeOutputs map[types.BlockStakeOutputID]types.BlockStakeOutput
+
+		// SpentOutputOrigins contains the origins of the spent coin and block stake outputs,
+		// mapped by output ID, and only defined for outputs locked by a relative time lock condition.
+		SpentOutputOrigins map[crypto.Hash]types.OutputOrigin
 	}
 
 	// A ConsensusChange enumerates a set of changes that occurred to the consensus set.
@@ -325,6 +329,14 @@ type (
 		// GetBlockStakeOutput takes a blockstake output ID and returns the appropriate blockstake output
 		GetBlockStakeOutput(types.BlockStakeOutputID) (types.BlockStakeOutput, error)
 
+		// BurnedTotals returns the total amount of coins and block stakes burned in the current path,
+		// meaning sent to a BurnCondition, and thus no longer part of the circulating supply.
+		BurnedTotals() (coins, blockStakes types.Currency)
+
+		// SupplyStatistics returns the supply statistics of the current path,
+		// computed from the supply totals maintained by the consensus set.
+		SupplyStatistics() types.SupplyStatistics
+
 		// RegisterPlugin takes in a name and plugin and registers this plugin on the consensus
 		// When the plugin is registered, all unprocessed changes are synchronously sent to the plugin
 		// unless the passed context is cancelled
@@ -0,0 +1,108 @@
+package consensus
+
+import (
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	// BurnedTotals is a database bucket that contains the cumulative amount
+	// of coins and block stakes burned in the current path, meaning sent to a BurnCondition.
+	BurnedTotals = []byte("BurnedTotals")
+
+	keyBurnedCoins       = []byte("coins")
+	keyBurnedBlockStakes = []byte("blockstakes")
+)
+
+// BurnedTotals returns the total amount of coins and block stakes
+// burned in the current path, these are no longer part of the circulating supply.
+func (cs *ConsensusSet) BurnedTotals() (coins, blockStakes types.Currency) {
+	dbErr := cs.db.View(func(tx *bolt.Tx) error {
+		coins, blockStakes = getBurnedTotals(tx)
+		return nil
+	})
+	if dbErr != nil {
+		build.Critical(dbErr)
+	}
+	return
+}
+
+// burnedOutputsOfBlock returns the amount of coins and block stakes burned by a block.
+func burnedOutputsOfBlock(block types.Block) (coins, blockStakes types.Currency) {
+	for _, txn := range block.Transactions {
+		for _, co := range txn.CoinOutputs {
+			if types.IsBurnCondition(co.Condition) {
+				coins = coins.Add(co.Value)
+			}
+		}
+		for _, bso := range txn.BlockStakeOutputs {
+			if types.IsBurnCondition(bso.Condition) {
+				blockStakes = blockStakes.Add(bso.Value)
+			}
+		}
+	}
+	return
+}
+
+// commitBurnedTotals applies or reverts the coins and block stakes burned by a block
+// to/from the cumulative burned totals.
+func commitBurnedTotals(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
+	coins, blockStakes := burnedOutputsOfBlock(pb.Block)
+	if coins.IsZero() && blockStakes.IsZero() {
+		return
+	}
+	totalCoins, totalBlockStakes := getBurnedTotals(tx)
+	if dir == modules.DiffApply {
+		totalCoins = totalCoins.Add(coins)
+		totalBlockStakes = totalBlockStakes.Add(blockStakes)
+	} else {
+		totalCoins = totalCoins.Sub(coins)
+		totalBlockStakes = totalBlockStakes.Sub(blockStakes)
+	}
+	putBurnedTotal(tx, keyBurnedCoins, totalCoins)
+	putBurnedTotal(tx, keyBurnedBlockStakes, totalBlockStakes)
+}
+
+// getBurnedTotals returns the cumulative burned totals of the current path.
+func getBurnedTotals(tx *bolt.Tx) (coins, blockStakes types.Currency) {
+	bucket := tx.Bucket(BurnedTotals)
+	if bucket == nil {
+		return // no outputs burned yet
+	}
+	for _, pair := range []struct {
+		key   []byte
+		value *types.Currency
+	}{
+		{keyBurnedCoins, &coins},
+		{keyBurnedBlockStakes, &blockStakes},
+	} {
+		b := bucket.Get(pair.key)
+		if len(b) == 0 {
+			continue
+		}
+		err := siabin.Unmarshal(b, pair.value)
+		if err != nil {
+			build.Severe(err)
+		}
+	}
+	return
+}
+
+// putBurnedTotal stores a cumulative burned total.
+func putBurnedTotal(tx *bolt.Tx, key []byte, total types.Currency) {
+	bucket, err := tx.CreateBucketIfNotExists(BurnedTotals)
+	if err != nil {
+		build.Severe(err)
+	}
+	b, err := siabin.Marshal(total)
+	if err != nil {
+		build.Severe(err)
+	}
+	err = bucket.Put(key, b)
+	if err != nil {
+		build.Severe(err)
+	}
+}
@@ -102,6 +102,9 @@ func (cs *ConsensusSet) createConsensusDB(tx *bolt.Tx) error {
 	for _, sfod := range cs.blockRoot.BlockStakeOutputDiffs {
 		commitBlockStakeOutputDiff(tx, sfod, modules.DiffApply)
 	}
+	commitBurnedTotals(tx, &cs.blockRoot, modules.DiffApply)
+	commitSupplyTotals(tx, &cs.blockRoot, modules.DiffApply)
+	commitOutputOrigins(tx, &cs.blockRoot, modules.DiffApply)
 
 	// Add the genesis block to the block structures - checksum must be taken
 	// after pushing the genesis block into the path.
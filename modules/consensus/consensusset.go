@@ -115,6 +115,7 @@ type ConsensusSet struct {
 
 	bcInfo                 types.BlockchainInfo
 	chainCts               types.ChainConstants
+	chainID                types.ChainID
 	genesisBlockStakeCount types.Currency
 
 	dbDebugFile *os.File
@@ -156,6 +157,7 @@ func New(gateway modules.Gateway, bootstrap bool, persistDir string, bcInfo type
 
 		bcInfo:                 bcInfo,
 		chainCts:               chainCts,
+		chainID:                chainCts.ChainID(),
 		genesisBlockStakeCount: chainCts.GenesisBlockStakeCount(),
 	}
 
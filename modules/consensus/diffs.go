@@ -133,6 +133,9 @@ func commitDiffSet(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
 	}
 
 	commitNodeDiffs(tx, pb, dir)
+	commitBurnedTotals(tx, pb, dir)
+	commitSupplyTotals(tx, pb, dir)
+	commitOutputOrigins(tx, pb, dir)
 	updateCurrentPath(tx, pb, dir)
 }
 
@@ -178,11 +181,15 @@ func (cs *ConsensusSet) generateAndApplyDiff(tx *bolt.Tx, pb *processedBlock) er
 				return fmt.Errorf("failed to find block stake input %s as unspent block stake output in current consensus state: %v", bsi.ParentID.String(), err)
 			}
 		}
+		addSpentOutputOrigins(tx, &cTxn, pb.Height, pb.Block.Timestamp)
 
 		err = cs.validTransaction(tx, cTxn, types.TransactionValidationConstants{
-			BlockSizeLimit:         cs.chainCts.BlockSizeLimit,
-			ArbitraryDataSizeLimit: cs.chainCts.ArbitraryDataSizeLimit,
-			MinimumMinerFee:        cs.chainCts.MinimumTransactionFee,
+			BlockSizeLimit:            cs.chainCts.BlockSizeLimit,
+			ArbitraryDataSizeLimit:    cs.chainCts.ArbitraryDataSizeLimit,
+			AllowedArbitraryDataTypes: cs.chainCts.AllowedArbitraryDataTypes,
+			MinimumMinerFee:           cs.chainCts.MinimumTransactionFee,
+			ExtensionDataSizeLimit:    cs.chainCts.ExtensionDataSizeLimit,
+			OutputMetadataSizeLimit:   cs.chainCts.OutputMetadataSizeLimit,
 		}, pb.Height, pb.Block.Timestamp, cs.isBlockCreatingTx(idx, pb.Block))
 		if err != nil {
 			cs.log.Printf("WARN: block %v cannot be applied: tx %v is invalid: %v",
@@ -219,6 +226,9 @@ func (cs *ConsensusSet) generateAndApplyDiff(tx *bolt.Tx, pb *processedBlock) er
 	// Add the block to the current path and block map.
 	bid := pb.Block.ID()
 	blockMap := tx.Bucket(BlockMap)
+	commitBurnedTotals(tx, pb, modules.DiffApply)
+	commitSupplyTotals(tx, pb, modules.DiffApply)
+	commitOutputOrigins(tx, pb, modules.DiffApply)
 	updateCurrentPath(tx, pb, modules.DiffApply)
 
 	// Sanity check preparation - set the consensus hash at this height so that
@@ -0,0 +1,141 @@
+package consensus
+
+import (
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	// OutputOrigins is a database bucket that contains the origin (block height and time)
+	// of all coin and block stake outputs locked by a RelativeTimeLockCondition
+	// (either directly or nested by a composite condition), mapped by output ID.
+	// Origins are only removed when the block which created the output is reverted.
+	OutputOrigins = []byte("OutputOrigins")
+)
+
+// isRelativeTimeLocked returns true if the given condition is a relative time lock condition,
+// or a composite condition nesting (at any depth) a relative time lock condition.
+func isRelativeTimeLocked(condition types.UnlockCondition) bool {
+	switch c := condition.(type) {
+	case types.UnlockConditionProxy:
+		return c.Condition != nil && isRelativeTimeLocked(c.Condition)
+	case *types.RelativeTimeLockCondition:
+		return true
+	case *types.AnyOfCondition:
+		return anyRelativeTimeLocked(c.Conditions)
+	case *types.AllOfCondition:
+		return anyRelativeTimeLocked(c.Conditions)
+	case types.MarshalableUnlockConditionGetter:
+		inner := c.GetMarshalableUnlockCondition()
+		return inner != nil && isRelativeTimeLocked(inner)
+	default:
+		return false
+	}
+}
+
+func anyRelativeTimeLocked(conditions []types.UnlockConditionProxy) bool {
+	for _, condition := range conditions {
+		if isRelativeTimeLocked(condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// commitOutputOrigins stores (apply) or removes (revert) the origin
+// of all relative time locked outputs created by a block.
+func commitOutputOrigins(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
+	var ids []crypto.Hash
+	for _, txn := range pb.Block.Transactions {
+		for idx, co := range txn.CoinOutputs {
+			if isRelativeTimeLocked(co.Condition) {
+				ids = append(ids, crypto.Hash(txn.CoinOutputID(uint64(idx))))
+			}
+		}
+		for idx, bso := range txn.BlockStakeOutputs {
+			if isRelativeTimeLocked(bso.Condition) {
+				ids = append(ids, crypto.Hash(txn.BlockStakeOutputID(uint64(idx))))
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	bucket, err := tx.CreateBucketIfNotExists(OutputOrigins)
+	if err != nil {
+		build.Severe(err)
+	}
+	if dir == modules.DiffRevert {
+		for _, id := range ids {
+			err = bucket.Delete(id[:])
+			if err != nil {
+				build.Severe(err)
+			}
+		}
+		return
+	}
+	b, err := siabin.Marshal(types.OutputOrigin{
+		BlockHeight: pb.Height,
+		BlockTime:   pb.Block.Timestamp,
+	})
+	if err != nil {
+		build.Severe(err)
+	}
+	for _, id := range ids {
+		err = bucket.Put(id[:], b)
+		if err != nil {
+			build.Severe(err)
+		}
+	}
+}
+
+// getOutputOrigin returns the origin of a relative time locked output,
+// false is returned in case the origin is not known.
+func getOutputOrigin(tx *bolt.Tx, id crypto.Hash) (origin types.OutputOrigin, ok bool) {
+	bucket := tx.Bucket(OutputOrigins)
+	if bucket == nil {
+		return
+	}
+	b := bucket.Get(id[:])
+	if len(b) == 0 {
+		return
+	}
+	err := siabin.Unmarshal(b, &origin)
+	if err != nil {
+		build.Severe(err)
+	}
+	return origin, true
+}
+
+// addSpentOutputOrigins adds the origins of all relative time locked outputs spent by the given transaction.
+// Outputs of which the origin isn't stored yet are created within the block (or pool) being validated,
+// and are thus given an origin equal to the given block height and time.
+func addSpentOutputOrigins(tx *bolt.Tx, cTxn *modules.ConsensusTransaction, height types.BlockHeight, timestamp types.Timestamp) {
+	addOrigin := func(id crypto.Hash) {
+		origin, ok := getOutputOrigin(tx, id)
+		if !ok {
+			origin = types.OutputOrigin{
+				BlockHeight: height,
+				BlockTime:   timestamp,
+			}
+		}
+		if cTxn.SpentOutputOrigins == nil {
+			cTxn.SpentOutputOrigins = make(map[crypto.Hash]types.OutputOrigin)
+		}
+		cTxn.SpentOutputOrigins[id] = origin
+	}
+	for id, co := range cTxn.SpentCoinOutputs {
+		if isRelativeTimeLocked(co.Condition) {
+			addOrigin(crypto.Hash(id))
+		}
+	}
+	for id, bso := range cTxn.SpentBlockStakeOutputs {
+		if isRelativeTimeLocked(bso.Condition) {
+			addOrigin(crypto.Hash(id))
+		}
+	}
+}
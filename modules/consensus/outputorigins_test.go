@@ -0,0 +1,163 @@
+package consensus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCommitOutputOrigins(t *testing.T) {
+	testDir := build.TempDir(modules.ConsensusDir, t.Name())
+	if err := os.MkdirAll(testDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(testDir, "consensus.db"), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	txn := types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinOutputs: []types.CoinOutput{
+			{Value: types.NewCurrency64(1), Condition: types.NewCondition(nil)},
+			{Value: types.NewCurrency64(2), Condition: types.NewCondition(
+				types.NewRelativeTimeLockCondition(10, types.RelativeLockTimeUnitBlocks, nil))},
+		},
+	}
+	pb := &processedBlock{
+		Block:  types.Block{Timestamp: 1600000000, Transactions: []types.Transaction{txn}},
+		Height: 42,
+	}
+	unlockedID := crypto.Hash(txn.CoinOutputID(0))
+	lockedID := crypto.Hash(txn.CoinOutputID(1))
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		commitOutputOrigins(tx, pb, modules.DiffApply)
+		if _, ok := getOutputOrigin(tx, unlockedID); ok {
+			t.Error("origin of unlocked output should not be stored")
+		}
+		origin, ok := getOutputOrigin(tx, lockedID)
+		if !ok {
+			t.Fatal("origin of relative time locked output should be stored")
+		}
+		if origin.BlockHeight != pb.Height || origin.BlockTime != pb.Block.Timestamp {
+			t.Errorf("unexpected origin: %v", origin)
+		}
+
+		cTxn := modules.ConsensusTransaction{
+			SpentCoinOutputs: map[types.CoinOutputID]types.CoinOutput{
+				txn.CoinOutputID(0): txn.CoinOutputs[0],
+				txn.CoinOutputID(1): txn.CoinOutputs[1],
+			},
+		}
+		addSpentOutputOrigins(tx, &cTxn, 50, 1600005000)
+		if len(cTxn.SpentOutputOrigins) != 1 || cTxn.SpentOutputOrigins[lockedID] != origin {
+			t.Errorf("unexpected spent output origins: %v", cTxn.SpentOutputOrigins)
+		}
+
+		commitOutputOrigins(tx, pb, modules.DiffRevert)
+		if _, ok := getOutputOrigin(tx, lockedID); ok {
+			t.Error("origin of relative time locked output should be removed when reverted")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpendNestedRelativeTimeLock(t *testing.T) {
+	testDir := build.TempDir(modules.ConsensusDir, t.Name())
+	if err := os.MkdirAll(testDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(testDir, "consensus.db"), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	sk, pk := crypto.GenerateKeyPair()
+	uh, err := types.NewEd25519PubKeyUnlockHash(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPK := crypto.GenerateKeyPair()
+	otherUH, err := types.NewEd25519PubKeyUnlockHash(otherPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (other key) OR (key, 10 blocks after the output was created)
+	condition := types.NewCondition(types.NewAnyOfCondition(
+		types.NewUnlockHashCondition(otherUH),
+		types.NewRelativeTimeLockCondition(10, types.RelativeLockTimeUnitBlocks, types.NewUnlockHashCondition(uh))))
+	if err = condition.IsStandardCondition(types.ValidationContext{}); err != nil {
+		t.Fatal(err)
+	}
+	parent := types.Transaction{
+		Version:     types.TransactionVersionOne,
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(2), Condition: condition}},
+	}
+	pb := &processedBlock{
+		Block:  types.Block{Timestamp: 1600000000, Transactions: []types.Transaction{parent}},
+		Height: 42,
+	}
+
+	txn := types.Transaction{
+		Version:     types.TransactionVersionOne,
+		CoinInputs:  []types.CoinInput{{ParentID: parent.CoinOutputID(0)}},
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(2), Condition: types.NewCondition(types.NewUnlockHashCondition(uh))}},
+	}
+	ff := types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(pk))
+	err = ff.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+		Key:          types.ByteSlice(sk[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn.CoinInputs[0].Fulfillment = types.NewFulfillment(types.NewAnyOfFulfillment(1, ff))
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		commitOutputOrigins(tx, pb, modules.DiffApply)
+		if _, ok := getOutputOrigin(tx, crypto.Hash(parent.CoinOutputID(0))); !ok {
+			t.Fatal("origin of output with a nested relative time lock should be stored")
+		}
+		for _, testCase := range []struct {
+			Height types.BlockHeight
+			Valid  bool
+		}{
+			{51, false},
+			{52, true},
+		} {
+			cTxn := modules.ConsensusTransaction{
+				Transaction: txn,
+				SpentCoinOutputs: map[types.CoinOutputID]types.CoinOutput{
+					parent.CoinOutputID(0): parent.CoinOutputs[0],
+				},
+			}
+			addSpentOutputOrigins(tx, &cTxn, testCase.Height, 1600005000)
+			err := ValidateCoinInputsAreFulfilled(cTxn, types.TransactionValidationContext{
+				ValidationContext: types.ValidationContext{BlockHeight: testCase.Height, BlockTime: 1600005000},
+			})
+			if testCase.Valid && err != nil {
+				t.Errorf("nested relative time lock should be spendable at height %d: %v", testCase.Height, err)
+			} else if !testCase.Valid && err == nil {
+				t.Errorf("nested relative time lock should not be spendable at height %d", testCase.Height)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
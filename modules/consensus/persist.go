@@ -49,6 +49,12 @@ func (cs *ConsensusSet) loadDB() error {
 		if genesisID != cs.blockRoot.Block.ID() {
 			return errors.New("blockchain has wrong genesis block, exiting")
 		}
+
+		// Compute the supply totals of databases created before they were maintained.
+		if tx.Bucket(SupplyTotals) == nil {
+			cs.log.Println("Computing the supply totals of the existing consensus database...")
+			return initSupplyTotals(tx)
+		}
 		return nil
 	})
 }
@@ -0,0 +1,203 @@
+package consensus
+
+import (
+	"bytes"
+
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	// SupplyTotals is a database bucket that contains the total amount of coins and block stakes
+	// in existence in the current path, as well as the total amount of coins created as block creator fees.
+	SupplyTotals = []byte("SupplyTotals")
+
+	keySupplyCoins            = []byte("coins")
+	keySupplyBlockStakes      = []byte("blockstakes")
+	keySupplyBlockCreatorFees = []byte("blockcreatorfees")
+)
+
+// SupplyStatistics implements modules.ConsensusSet.SupplyStatistics
+func (cs *ConsensusSet) SupplyStatistics() types.SupplyStatistics {
+	return cs.chainCts.SupplyStatistics(cs.supplyTotals())
+}
+
+// supplyTotals returns the supply totals of the current path.
+func (cs *ConsensusSet) supplyTotals() (totals types.SupplyTotals) {
+	dbErr := cs.db.View(func(tx *bolt.Tx) error {
+		totals.Coins = getSupplyTotal(tx, keySupplyCoins)
+		totals.BlockStakes = getSupplyTotal(tx, keySupplyBlockStakes)
+		totals.BlockCreatorFees = getSupplyTotal(tx, keySupplyBlockCreatorFees)
+		totals.BurnedCoins, totals.BurnedBlockStakes = getBurnedTotals(tx)
+		return nil
+	})
+	if dbErr != nil {
+		build.Critical(dbErr)
+	}
+	return
+}
+
+// supplyDiffsOfBlock returns the amount of coins and block stakes created and destroyed by a block,
+// derived from its (delayed) output diffs, such that outputs which reach maturity are not counted twice.
+func supplyDiffsOfBlock(pb *processedBlock) (coinsCreated, coinsDestroyed, blockStakesCreated, blockStakesDestroyed types.Currency) {
+	for _, cod := range pb.CoinOutputDiffs {
+		if cod.Direction == modules.DiffApply {
+			coinsCreated = coinsCreated.Add(cod.CoinOutput.Value)
+		} else {
+			coinsDestroyed = coinsDestroyed.Add(cod.CoinOutput.Value)
+		}
+	}
+	for _, dcod := range pb.DelayedCoinOutputDiffs {
+		if dcod.Direction == modules.DiffApply {
+			coinsCreated = coinsCreated.Add(dcod.CoinOutput.Value)
+		} else {
+			coinsDestroyed = coinsDestroyed.Add(dcod.CoinOutput.Value)
+		}
+	}
+	for _, bsod := range pb.BlockStakeOutputDiffs {
+		if bsod.Direction == modules.DiffApply {
+			blockStakesCreated = blockStakesCreated.Add(bsod.BlockStakeOutput.Value)
+		} else {
+			blockStakesDestroyed = blockStakesDestroyed.Add(bsod.BlockStakeOutput.Value)
+		}
+	}
+	return
+}
+
+// blockCreatorFeesOfBlock returns the amount of coins created as block creator fees by a block,
+// being its miner payouts minus the transaction fees they include.
+func blockCreatorFeesOfBlock(block types.Block) types.Currency {
+	var payouts, txFees types.Currency
+	for _, mp := range block.MinerPayouts {
+		payouts = payouts.Add(mp.Value)
+	}
+	for _, txn := range block.Transactions {
+		for _, fee := range txn.MinerFees {
+			txFees = txFees.Add(fee)
+		}
+	}
+	if payouts.Cmp(txFees) <= 0 {
+		return types.Currency{}
+	}
+	return payouts.Sub(txFees)
+}
+
+// commitSupplyTotals applies or reverts the coins and block stakes created and destroyed by a block
+// to/from the supply totals.
+func commitSupplyTotals(tx *bolt.Tx, pb *processedBlock, dir modules.DiffDirection) {
+	coinsCreated, coinsDestroyed, blockStakesCreated, blockStakesDestroyed := supplyDiffsOfBlock(pb)
+	fees := blockCreatorFeesOfBlock(pb.Block)
+	coins := getSupplyTotal(tx, keySupplyCoins)
+	blockStakes := getSupplyTotal(tx, keySupplyBlockStakes)
+	totalFees := getSupplyTotal(tx, keySupplyBlockCreatorFees)
+	if dir == modules.DiffApply {
+		coins = coins.Add(coinsCreated).Sub(coinsDestroyed)
+		blockStakes = blockStakes.Add(blockStakesCreated).Sub(blockStakesDestroyed)
+		totalFees = totalFees.Add(fees)
+	} else {
+		coins = coins.Add(coinsDestroyed).Sub(coinsCreated)
+		blockStakes = blockStakes.Add(blockStakesDestroyed).Sub(blockStakesCreated)
+		totalFees = totalFees.Sub(fees)
+	}
+	putSupplyTotal(tx, keySupplyCoins, coins)
+	putSupplyTotal(tx, keySupplyBlockStakes, blockStakes)
+	putSupplyTotal(tx, keySupplyBlockCreatorFees, totalFees)
+}
+
+// initSupplyTotals computes the supply totals of a database created
+// before the supply totals were maintained. The coin and block stake totals
+// are computed from the (delayed) unspent outputs, while the block creator fees
+// are computed from all blocks in the current path.
+func initSupplyTotals(tx *bolt.Tx) error {
+	var coins, blockStakes, fees types.Currency
+	err := tx.Bucket(CoinOutputs).ForEach(func(_, v []byte) error {
+		var co types.CoinOutput
+		if err := siabin.Unmarshal(v, &co); err != nil {
+			return err
+		}
+		coins = coins.Add(co.Value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		if !bytes.HasPrefix(name, prefixDCO) {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var co types.CoinOutput
+			if err := siabin.Unmarshal(v, &co); err != nil {
+				return err
+			}
+			coins = coins.Add(co.Value)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(BlockStakeOutputs).ForEach(func(_, v []byte) error {
+		var bso types.BlockStakeOutput
+		if err := siabin.Unmarshal(v, &bso); err != nil {
+			return err
+		}
+		blockStakes = blockStakes.Add(bso.Value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	height := blockHeight(tx)
+	for h := types.BlockHeight(1); h <= height; h++ {
+		id, err := getPath(tx, h)
+		if err != nil {
+			return err
+		}
+		pb, err := getBlockMap(tx, id)
+		if err != nil {
+			return err
+		}
+		fees = fees.Add(blockCreatorFeesOfBlock(pb.Block))
+	}
+	putSupplyTotal(tx, keySupplyCoins, coins)
+	putSupplyTotal(tx, keySupplyBlockStakes, blockStakes)
+	putSupplyTotal(tx, keySupplyBlockCreatorFees, fees)
+	return nil
+}
+
+// getSupplyTotal returns a supply total of the current path.
+func getSupplyTotal(tx *bolt.Tx, key []byte) (total types.Currency) {
+	bucket := tx.Bucket(SupplyTotals)
+	if bucket == nil {
+		return
+	}
+	b := bucket.Get(key)
+	if len(b) == 0 {
+		return
+	}
+	err := siabin.Unmarshal(b, &total)
+	if err != nil {
+		build.Severe(err)
+	}
+	return
+}
+
+// putSupplyTotal stores a supply total.
+func putSupplyTotal(tx *bolt.Tx, key []byte, total types.Currency) {
+	bucket, err := tx.CreateBucketIfNotExists(SupplyTotals)
+	if err != nil {
+		build.Severe(err)
+	}
+	b, err := siabin.Marshal(total)
+	if err != nil {
+		build.Severe(err)
+	}
+	err = bucket.Put(key, b)
+	if err != nil {
+		build.Severe(err)
+	}
+}
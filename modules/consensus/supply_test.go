@@ -0,0 +1,78 @@
+package consensus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCommitSupplyTotals(t *testing.T) {
+	testDir := build.TempDir(modules.ConsensusDir, t.Name())
+	if err := os.MkdirAll(testDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(testDir, "consensus.db"), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	genesis := &processedBlock{
+		CoinOutputDiffs: []modules.CoinOutputDiff{
+			{Direction: modules.DiffApply, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(100)}},
+		},
+		BlockStakeOutputDiffs: []modules.BlockStakeOutputDiff{
+			{Direction: modules.DiffApply, BlockStakeOutput: types.BlockStakeOutput{Value: types.NewCurrency64(10)}},
+		},
+	}
+	// a block spending 60 coins into 50 coins (paying a fee of 10),
+	// paying out the fee and a block creator fee of 5 as a delayed output,
+	// with 20 coins of an earlier block creator payout reaching maturity
+	block := &processedBlock{
+		Block: types.Block{
+			MinerPayouts: []types.MinerPayout{{Value: types.NewCurrency64(15)}},
+			Transactions: []types.Transaction{{MinerFees: []types.Currency{types.NewCurrency64(10)}}},
+		},
+		CoinOutputDiffs: []modules.CoinOutputDiff{
+			{Direction: modules.DiffRevert, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(60)}},
+			{Direction: modules.DiffApply, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(50)}},
+			{Direction: modules.DiffApply, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(20)}},
+		},
+		DelayedCoinOutputDiffs: []modules.DelayedCoinOutputDiff{
+			{Direction: modules.DiffApply, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(15)}},
+			{Direction: modules.DiffRevert, CoinOutput: types.CoinOutput{Value: types.NewCurrency64(20)}},
+		},
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		commitSupplyTotals(tx, genesis, modules.DiffApply)
+		commitSupplyTotals(tx, block, modules.DiffApply)
+		if coins := getSupplyTotal(tx, keySupplyCoins); !coins.Equals64(105) {
+			t.Errorf("unexpected coin supply: %v", coins)
+		}
+		if blockStakes := getSupplyTotal(tx, keySupplyBlockStakes); !blockStakes.Equals64(10) {
+			t.Errorf("unexpected block stake supply: %v", blockStakes)
+		}
+		if fees := getSupplyTotal(tx, keySupplyBlockCreatorFees); !fees.Equals64(5) {
+			t.Errorf("unexpected block creator fees: %v", fees)
+		}
+
+		commitSupplyTotals(tx, block, modules.DiffRevert)
+		if coins := getSupplyTotal(tx, keySupplyCoins); !coins.Equals64(100) {
+			t.Errorf("unexpected coin supply after revert: %v", coins)
+		}
+		if fees := getSupplyTotal(tx, keySupplyBlockCreatorFees); !fees.IsZero() {
+			t.Errorf("unexpected block creator fees after revert: %v", fees)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
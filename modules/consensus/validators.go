@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/threefoldtech/rivine/crypto"
 	"github.com/threefoldtech/rivine/modules"
 	"github.com/threefoldtech/rivine/types"
 )
@@ -45,6 +46,10 @@ func StandardTransactionVersionMappedValidators() map[types.TransactionVersion][
 			ValidateCoinOutputsAreBalanced,
 			ValidateBlockStakeOutputsAreBalanced,
 		},
+		types.TransactionVersionTwo: []modules.TransactionValidationFunction{
+			ValidateCoinOutputsAreBalanced,
+			ValidateBlockStakeOutputsAreBalanced,
+		},
 	}
 }
 
@@ -55,6 +60,8 @@ func StandardTransactionValidators() []modules.TransactionValidationFunction {
 	return []modules.TransactionValidationFunction{
 		ValidateTransactionFitsInABlock,
 		ValidateTransactionArbitraryData,
+		ValidateTransactionExtensionData,
+		ValidateTransactionOutputMetadata,
 		ValidateCoinInputsAreValid,
 		ValidateCoinOutputsAreValid,
 		ValidateBlockStakeInputsAreValid,
@@ -78,9 +85,31 @@ func ValidateTransactionFitsInABlock(tx modules.ConsensusTransaction, ctx types.
 }
 
 // ValidateTransactionArbitraryData is a validator function that checks
-// if a transaction's arbitrary data is valid
+// if a transaction's arbitrary data is valid, in size as well as in type,
+// the latter only once the arbitrary data envelope rules are active
 func ValidateTransactionArbitraryData(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
-	return types.ArbitraryDataFits(tx.ArbitraryData, ctx.ArbitraryDataSizeLimit)
+	if ctx.LegacyArbitraryData {
+		return types.ArbitraryDataFits(tx.ArbitraryData, ctx.ArbitraryDataSizeLimit)
+	}
+	return types.ValidateArbitraryData(tx.ArbitraryData, ctx.ArbitraryDataSizeLimit, ctx.AllowedArbitraryDataTypes)
+}
+
+// ValidateTransactionExtensionData is a validator function that checks if
+// the extension data of a transaction is supported by its version, fits, and is valid
+func ValidateTransactionExtensionData(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
+	if len(tx.ExtensionData) == 0 {
+		return nil
+	}
+	if tx.Version != types.TransactionVersionOne && tx.Version != types.TransactionVersionTwo {
+		return types.ErrTransactionExtensionDataNotSupported
+	}
+	return types.ValidateTransactionExtensionData(tx.ExtensionData, ctx.ExtensionDataSizeLimit)
+}
+
+// ValidateTransactionOutputMetadata is a validator function that checks if
+// the output metadata of a transaction is supported by its version and fits
+func ValidateTransactionOutputMetadata(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
+	return types.ValidateOutputMetadata(tx.Transaction, ctx.OutputMetadataSizeLimit)
 }
 
 // ValidateCoinOutputsAreValid is a validator function that checks if all coin outputs are standard,
@@ -103,13 +132,13 @@ func ValidateCoinOutputsAreValid(tx modules.ConsensusTransaction, ctx types.Tran
 // meaning their fulfillment is considered standard (== known) and their parent ID is defined.
 func ValidateCoinInputsAreValid(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
 	var err error
-	for _, ci := range tx.CoinInputs {
+	for index, ci := range tx.CoinInputs {
 		if ci.ParentID == (types.CoinOutputID{}) {
-			return errors.New("no parent ID defined for coin input")
+			return types.NewCoinInputError(types.ErrMissingParentID, index, ci, nil)
 		}
 		err = ci.Fulfillment.IsStandardFulfillment(ctx.ValidationContext)
 		if err != nil {
-			return err
+			return types.NewCoinInputError(err, index, ci, nil)
 		}
 	}
 	return nil
@@ -135,13 +164,13 @@ func ValidateBlockStakeOutputsAreValid(tx modules.ConsensusTransaction, ctx type
 // meaning their fulfillment is considered standard (== known) and their parent ID is defined.
 func ValidateBlockStakeInputsAreValid(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
 	var err error
-	for _, bsi := range tx.BlockStakeInputs {
+	for index, bsi := range tx.BlockStakeInputs {
 		if bsi.ParentID == (types.BlockStakeOutputID{}) {
-			return errors.New("no parent ID defined for block stake input")
+			return types.NewBlockStakeInputError(types.ErrMissingParentID, index, bsi, nil)
 		}
 		err = bsi.Fulfillment.IsStandardFulfillment(ctx.ValidationContext)
 		if err != nil {
-			return err
+			return types.NewBlockStakeInputError(err, index, bsi, nil)
 		}
 	}
 	return nil
@@ -199,33 +228,55 @@ func ValidateInvalidByDefault(_ modules.ConsensusTransaction, _ types.Transactio
 	return errors.New("transaction is invalid as it has been disabled for validation using the ValidateInvalidByDefault function")
 }
 
-// ValidateCoinInputsAreFulfilled validates that all coin outputs are validated
+// ValidateCoinInputsAreFulfilled validates that all coin outputs are validated.
+// BLS signatures of all coin inputs are verified in a single batch,
+// such that they can be aggregated into a single signature.
+// The signature hash properties shared by all inputs are encoded only once.
 func ValidateCoinInputsAreFulfilled(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
 	var (
-		ok bool
-		co types.CoinOutput
+		ok    bool
+		co    types.CoinOutput
+		batch types.BLSSignatureBatch
+		cache = types.NewSignatureHashCache(tx.Transaction, ctx.ChainID)
 	)
 	for index, ci := range tx.CoinInputs {
 		co, ok = tx.SpentCoinOutputs[ci.ParentID]
 		if !ok {
-			return fmt.Errorf(
-				"unable to find parent ID %s as an unspent coin output in the current consensus transaction at block height %d",
-				ci.ParentID.String(), ctx.BlockHeight)
+			return types.NewCoinInputError(
+				fmt.Errorf("%w at block height %d", types.ErrUnknownParentOutput, ctx.BlockHeight),
+				index, ci, nil)
 		}
 		// check if the referenced output's condition has been fulfilled
 		err := co.Condition.Fulfill(ci.Fulfillment, types.FulfillContext{
-			ExtraObjects: []interface{}{uint64(index)},
-			BlockHeight:  ctx.BlockHeight,
-			BlockTime:    ctx.BlockTime,
-			Transaction:  tx.Transaction,
+			ExtraObjects:       []interface{}{uint64(index)},
+			BlockHeight:        ctx.BlockHeight,
+			BlockTime:          ctx.BlockTime,
+			Transaction:        tx.Transaction,
+			SignatureHashCache: cache,
+			ChainID:            ctx.ChainID,
+			OutputOrigin:       spentOutputOrigin(tx, crypto.Hash(ci.ParentID)),
+			SpentCoinOutputs:   tx.SpentCoinOutputs,
+			BLSSignatureBatch:  &batch,
 		})
 		if err != nil {
-			return err
+			return types.NewCoinInputError(err, index, ci, &co.Condition)
 		}
 	}
+	if err := batch.Verify(); err != nil {
+		return fmt.Errorf("invalid BLS signature(s) for the coin inputs of tx %s: %w", tx.ID().String(), err)
+	}
 	return nil
 }
 
+// spentOutputOrigin returns the origin of a spent output, if known.
+func spentOutputOrigin(tx modules.ConsensusTransaction, id crypto.Hash) *types.OutputOrigin {
+	origin, ok := tx.SpentOutputOrigins[id]
+	if !ok {
+		return nil
+	}
+	return &origin
+}
+
 // ValidateCoinOutputsAreBalanced is a validator function that checks if the sum of
 // all types of coin outputs equals the sum of coin inputs.
 func ValidateCoinOutputsAreBalanced(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
@@ -259,31 +310,42 @@ func ValidateCoinOutputsAreBalanced(tx modules.ConsensusTransaction, ctx types.T
 	return nil
 }
 
-// ValidateBlockStakeInputsAreFulfilled validates that all block stake inputs are fulfilled
+// ValidateBlockStakeInputsAreFulfilled validates that all block stake inputs are fulfilled.
+// BLS signatures of all block stake inputs are verified in a single batch,
+// such that they can be aggregated into a single signature.
 func ValidateBlockStakeInputsAreFulfilled(tx modules.ConsensusTransaction, ctx types.TransactionValidationContext) error {
 	var (
-		ok  bool
-		err error
-		bso types.BlockStakeOutput
+		ok    bool
+		err   error
+		bso   types.BlockStakeOutput
+		batch types.BLSSignatureBatch
+		cache = types.NewSignatureHashCache(tx.Transaction, ctx.ChainID)
 	)
 	for index, bsi := range tx.BlockStakeInputs {
 		bso, ok = tx.SpentBlockStakeOutputs[bsi.ParentID]
 		if !ok {
-			return fmt.Errorf(
-				"unable to find parent ID %s as an unspent blockstake output in the current consensus transaction at block height %d",
-				bsi.ParentID.String(), ctx.BlockHeight)
+			return types.NewBlockStakeInputError(
+				fmt.Errorf("%w at block height %d", types.ErrUnknownParentOutput, ctx.BlockHeight),
+				index, bsi, nil)
 		}
 		// check if the referenced output's condition has been fulfilled
 		err = bso.Condition.Fulfill(bsi.Fulfillment, types.FulfillContext{
-			ExtraObjects: []interface{}{uint64(index)},
-			BlockHeight:  ctx.BlockHeight,
-			BlockTime:    ctx.BlockTime,
-			Transaction:  tx.Transaction,
+			ExtraObjects:       []interface{}{uint64(index)},
+			BlockHeight:        ctx.BlockHeight,
+			BlockTime:          ctx.BlockTime,
+			Transaction:        tx.Transaction,
+			SignatureHashCache: cache,
+			ChainID:            ctx.ChainID,
+			OutputOrigin:       spentOutputOrigin(tx, crypto.Hash(bsi.ParentID)),
+			BLSSignatureBatch:  &batch,
 		})
 		if err != nil {
-			return err
+			return types.NewBlockStakeInputError(err, index, bsi, &bso.Condition)
 		}
 	}
+	if err = batch.Verify(); err != nil {
+		return fmt.Errorf("invalid BLS signature(s) for the block stake inputs of tx %s: %w", tx.ID().String(), err)
+	}
 	return nil
 }
 
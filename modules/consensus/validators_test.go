@@ -0,0 +1,31 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// TestValidateTransactionArbitraryData ensures that raw arbitrary data which looks like
+// an invalid envelope is only rejected once the arbitrary data envelope rules are active.
+func TestValidateTransactionArbitraryData(t *testing.T) {
+	tx := modules.ConsensusTransaction{Transaction: types.Transaction{
+		Version:       types.TransactionVersionOne,
+		ArbitraryData: []byte{0xAD, 0xE7},
+	}}
+	ctx := types.TransactionValidationContext{ArbitraryDataSizeLimit: 83}
+
+	if err := ValidateTransactionArbitraryData(tx, ctx); err == nil {
+		t.Error("expected the invalid envelope to be rejected")
+	}
+	ctx.LegacyArbitraryData = true
+	if err := ValidateTransactionArbitraryData(tx, ctx); err != nil {
+		t.Error("expected legacy arbitrary data to be accepted:", err)
+	}
+	// the size limit applies to legacy arbitrary data as well
+	tx.ArbitraryData = make([]byte, 84)
+	if err := ValidateTransactionArbitraryData(tx, ctx); err == nil {
+		t.Error("expected legacy arbitrary data exceeding the size limit to be rejected")
+	}
+}
@@ -9,9 +9,22 @@ import (
 	"github.com/threefoldtech/rivine/types"
 )
 
+var (
+	errReplayProtectionNotActive = errors.New("replay-protected transactions are not yet accepted at this block height")
+)
+
 // validTransaction checks that all fields are valid within the current
 // consensus state. If not an error is returned.
 func (cs *ConsensusSet) validTransaction(tx *bolt.Tx, t modules.ConsensusTransaction, constants types.TransactionValidationConstants, blockHeight types.BlockHeight, blockTimestamp types.Timestamp, isBlockCreatingTx bool) error {
+	// replay-protected transactions are only valid once the hard fork activating them is reached
+	if t.Version == types.TransactionVersionTwo && blockHeight < cs.chainCts.ReplayProtectionActivationHeight {
+		return errReplayProtectionNotActive
+	}
+	// condition types and signature algorithms are only valid once the hard fork activating them is reached
+	if err := cs.chainCts.ValidateUnlockTypesActivated(t.Transaction, blockHeight); err != nil {
+		return err
+	}
+
 	ctx := types.TransactionValidationContext{
 		ValidationContext: types.ValidationContext{
 			Confirmed:         true,
@@ -19,9 +32,14 @@ func (cs *ConsensusSet) validTransaction(tx *bolt.Tx, t modules.ConsensusTransac
 			BlockTime:         blockTimestamp,
 			IsBlockCreatingTx: isBlockCreatingTx,
 		},
-		BlockSizeLimit:         constants.BlockSizeLimit,
-		ArbitraryDataSizeLimit: constants.ArbitraryDataSizeLimit,
-		MinimumMinerFee:        constants.MinimumMinerFee,
+		BlockSizeLimit:            constants.BlockSizeLimit,
+		ArbitraryDataSizeLimit:    constants.ArbitraryDataSizeLimit,
+		AllowedArbitraryDataTypes: constants.AllowedArbitraryDataTypes,
+		MinimumMinerFee:           constants.MinimumMinerFee,
+		ExtensionDataSizeLimit:    constants.ExtensionDataSizeLimit,
+		OutputMetadataSizeLimit:   constants.OutputMetadataSizeLimit,
+		LegacyArbitraryData:       blockHeight < cs.chainCts.ArbitraryDataEnvelopeActivationHeight,
+		ChainID:                   cs.chainID,
 	}
 
 	// return the first error reported by a validator
@@ -91,27 +109,35 @@ func (cs *ConsensusSet) TryTransactionSet(txns []types.Transaction) (modules.Con
 				SpentCoinOutputs:       make(map[types.CoinOutputID]types.CoinOutput),
 				SpentBlockStakeOutputs: make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
 			}
-			for _, ci := range txn.CoinInputs {
+			for index, ci := range txn.CoinInputs {
 				cTxn.SpentCoinOutputs[ci.ParentID], err = getCoinOutput(tx, ci.ParentID)
 				if err != nil {
-					return fmt.Errorf("failed to find coin input %s from txn %s as unspent coin output in the consensus state: %v", ci.ParentID.String(), txn.ID().String(), err)
+					return types.NewCoinInputError(
+						fmt.Errorf("%w in the consensus state (txn %s): %v", types.ErrUnknownParentOutput, txn.ID().String(), err),
+						index, ci, nil)
 				}
 			}
-			for _, bsi := range txn.BlockStakeInputs {
+			for index, bsi := range txn.BlockStakeInputs {
 				cTxn.SpentBlockStakeOutputs[bsi.ParentID], err = getBlockStakeOutput(tx, bsi.ParentID)
 				if err != nil {
-					return fmt.Errorf("failed to find block stake input %s from txn %s as unspent block stake output in the consensus state: %v", bsi.ParentID.String(), txn.ID().String(), err)
+					return types.NewBlockStakeInputError(
+						fmt.Errorf("%w in the consensus state (txn %s): %v", types.ErrUnknownParentOutput, txn.ID().String(), err),
+						index, bsi, nil)
 				}
 			}
+			addSpentOutputOrigins(tx, &cTxn, diffHolder.Height, blockTime)
 
 			// a transaction can only be "block creating" in the context of a block,
 			// which we don't have here, so just pass in false for the "isBlockCreatingTx"
 			// argument. In other words, a block creating transaction can never be part
 			// of a transaction pool and must be inserted when the block is actually created
 			err := cs.validTransaction(tx, cTxn, types.TransactionValidationConstants{
-				BlockSizeLimit:         cs.chainCts.BlockSizeLimit,
-				ArbitraryDataSizeLimit: cs.chainCts.ArbitraryDataSizeLimit,
-				MinimumMinerFee:        cs.chainCts.MinimumTransactionFee,
+				BlockSizeLimit:            cs.chainCts.BlockSizeLimit,
+				ArbitraryDataSizeLimit:    cs.chainCts.ArbitraryDataSizeLimit,
+				AllowedArbitraryDataTypes: cs.chainCts.AllowedArbitraryDataTypes,
+				MinimumMinerFee:           cs.chainCts.MinimumTransactionFee,
+				ExtensionDataSizeLimit:    cs.chainCts.ExtensionDataSizeLimit,
+				OutputMetadataSizeLimit:   cs.chainCts.OutputMetadataSizeLimit,
 			}, diffHolder.Height, blockTime, false)
 			if err != nil {
 				cs.log.Printf("WARN: try-out tx %v is invalid: %v", txn.ID(), err)
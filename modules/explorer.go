@@ -3,6 +3,7 @@ package modules
 import (
 	"math/big"
 
+	"github.com/threefoldtech/rivine/crypto"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -12,6 +13,14 @@ const (
 	ExplorerDir = "explorer"
 )
 
+// types of the objects identified by a hash, as indexed by the explorer
+const (
+	ExplorerHashTypeBlockID            = "blockid"
+	ExplorerHashTypeTransactionID      = "transactionid"
+	ExplorerHashTypeCoinOutputID       = "coinoutputid"
+	ExplorerHashTypeBlockStakeOutputID = "blockstakeoutputid"
+)
+
 type (
 	// BlockFacts returns a bunch of statistics about the consensus set as they
 	// were at a specific block.
@@ -72,6 +81,11 @@ type (
 		ExtremeFutureThreshold types.Timestamp   `json:"extremefuturethreshold"`
 		BlockStakeCount        types.Currency    `json:"blockstakecount"`
 
+		// ArbitraryDataSizeLimit and AllowedArbitraryDataTypes limit the arbitrary data of transactions,
+		// allowing clients to validate arbitrary data prior to submitting a transaction
+		ArbitraryDataSizeLimit    uint64                    `json:"arbitrarydatasizelimit"`
+		AllowedArbitraryDataTypes []types.ArbitraryDataType `json:"allowedarbitrarydatatypes,omitempty"`
+
 		BlockStakeAging        uint64                     `json:"blockstakeaging"`
 		BlockCreatorFee        types.Currency             `json:"blockcreatorfee"`
 		MinimumTransactionFee  types.Currency             `json:"minimumtransactionfee"`
@@ -87,9 +101,54 @@ type (
 		MaxAdjustmentUp   *big.Rat          `json:"maxadjustmentup"`
 		MaxAdjustmentDown *big.Rat          `json:"maxadjustmentdown"`
 
-		OneCoin types.Currency `json:"onecoin"`
+		OneCoin           types.Currency `json:"onecoin"`
+		CurrencyPrecision uint           `json:"currencyprecision"`
 
 		DefaultTransactionVersion types.TransactionVersion `json:"deftransactionversion"`
+
+		Bech32AddressPrefix string `json:"bech32addressprefix,omitempty"`
+
+		ChainID                          types.ChainID     `json:"chainid"`
+		ReplayProtectionActivationHeight types.BlockHeight `json:"replayprotectionactivationheight"`
+	}
+
+	// UnlockHashBalance is the balance of an unlock hash,
+	// being the sum of the (coin and block stake) outputs it can spend.
+	UnlockHashBalance struct {
+		UnlockHash  types.UnlockHash `json:"unlockhash"`
+		Coins       types.Currency   `json:"coins"`
+		BlockStakes types.Currency   `json:"blockstakes"`
+	}
+
+	// BalanceDistribution describes how a currency (coins or block stakes)
+	// is distributed over all unlock hashes with a non-zero balance of that currency.
+	BalanceDistribution struct {
+		// Addresses is the amount of unlock hashes with a non-zero balance
+		Addresses uint64 `json:"addresses"`
+		// Total is the sum of all balances
+		Total types.Currency `json:"total"`
+		// Buckets groups the unlock hashes by the order of magnitude of their balance
+		Buckets []BalanceDistributionBucket `json:"buckets"`
+	}
+
+	// BalanceDistributionBucket groups all unlock hashes
+	// with a balance within the range of the bucket.
+	BalanceDistributionBucket struct {
+		// MinBalance is the (inclusive) minimum balance of the bucket
+		MinBalance types.Currency `json:"minbalance"`
+		// MaxBalance is the (exclusive) maximum balance of the bucket,
+		// nil for the last bucket, which is unbounded
+		MaxBalance *types.Currency `json:"maxbalance,omitempty"`
+		Addresses  uint64          `json:"addresses"`
+		Total      types.Currency  `json:"total"`
+	}
+
+	// HashPrefixMatch is an object indexed by the explorer,
+	// identified by a hash starting with a searched prefix.
+	HashPrefixMatch struct {
+		// Type is one of the ExplorerHashType* constants
+		Type string      `json:"type"`
+		Hash crypto.Hash `json:"hash"`
 	}
 
 	// Explorer tracks the blockchain and provides tools for gathering
@@ -146,6 +205,27 @@ type (
 		// Constants returns the constants in use by the chain
 		Constants() DaemonConstants
 
+		// Balance returns the confirmed coin and block stake balance of the given unlock hash.
+		Balance(types.UnlockHash) UnlockHashBalance
+
+		// CoinRichList returns the (at most) n unlock hashes with the highest coin balance,
+		// sorted by coin balance in descending order.
+		CoinRichList(n int) []UnlockHashBalance
+
+		// BlockStakeRichList returns the (at most) n unlock hashes with the highest block stake balance,
+		// sorted by block stake balance in descending order.
+		BlockStakeRichList(n int) []UnlockHashBalance
+
+		// CoinDistribution returns the distribution of all coins over the unlock hashes.
+		CoinDistribution() BalanceDistribution
+
+		// BlockStakeDistribution returns the distribution of all block stakes over the unlock hashes.
+		BlockStakeDistribution() BalanceDistribution
+
+		// HashesWithPrefix returns the (at most) n block, transaction, coin output and
+		// block stake output IDs starting with the given hex-encoded prefix.
+		HashesWithPrefix(prefix string, n int) ([]HashPrefixMatch, error)
+
 		Close() error
 	}
 )
@@ -187,6 +267,9 @@ func NewDaemonConstants(info types.BlockchainInfo, constants types.ChainConstant
 		ExtremeFutureThreshold: constants.ExtremeFutureThreshold,
 		BlockStakeCount:        constants.GenesisBlockStakeCount(),
 
+		ArbitraryDataSizeLimit:    constants.ArbitraryDataSizeLimit,
+		AllowedArbitraryDataTypes: constants.AllowedArbitraryDataTypes,
+
 		BlockStakeAging:        constants.BlockStakeAging,
 		BlockCreatorFee:        constants.BlockCreatorFee,
 		MinimumTransactionFee:  constants.MinimumTransactionFee,
@@ -202,8 +285,14 @@ func NewDaemonConstants(info types.BlockchainInfo, constants types.ChainConstant
 		MaxAdjustmentUp:   constants.MaxAdjustmentUp,
 		MaxAdjustmentDown: constants.MaxAdjustmentDown,
 
-		OneCoin: constants.CurrencyUnits.OneCoin,
+		OneCoin:           constants.CurrencyUnits.OneCoin,
+		CurrencyPrecision: constants.CurrencyUnits.Precision(),
 
 		DefaultTransactionVersion: constants.DefaultTransactionVersion,
+
+		Bech32AddressPrefix: constants.Bech32AddressPrefix,
+
+		ChainID:                          constants.ChainID(),
+		ReplayProtectionActivationHeight: constants.ReplayProtectionActivationHeight,
 	}
 }
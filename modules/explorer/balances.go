@@ -0,0 +1,253 @@
+package explorer
+
+import (
+	"math/big"
+
+	bolt "github.com/rivine/bbolt"
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// balanceDistributionBuckets is the amount of buckets of a balance distribution,
+	// the first bucket containing all balances smaller than a single unit,
+	// each next bucket containing the balances of the next order of magnitude
+	// and the last bucket containing all balances of at least 10^(balanceDistributionBuckets-2) units.
+	balanceDistributionBuckets = 12
+
+	// balanceIndexValueSize is the size of the (big-endian) balance prefix of a balance index key
+	balanceIndexValueSize = 32
+)
+
+type (
+	// unlockHashBalance is the balance of an unlock hash as stored in bucketUnlockHashBalances
+	unlockHashBalance struct {
+		Coins       types.Currency
+		BlockStakes types.Currency
+	}
+
+	// balanceDistribution is a balance distribution as stored in bucketInternal
+	balanceDistribution struct {
+		Addresses uint64
+		Total     types.Currency
+		Buckets   []balanceDistributionBucket
+	}
+	balanceDistributionBucket struct {
+		Addresses uint64
+		Total     types.Currency
+	}
+)
+
+// dbApplyCoinOutputDiff updates the coin balance of the unlock hash of the diff's output.
+func (e *Explorer) dbApplyCoinOutputDiff(tx *bolt.Tx, diff modules.CoinOutputDiff) {
+	uh := diff.CoinOutput.Condition.UnlockHash()
+	balance := dbGetUnlockHashBalance(tx, uh)
+	oldValue := balance.Coins
+	if diff.Direction == modules.DiffApply {
+		balance.Coins = balance.Coins.Add(diff.CoinOutput.Value)
+	} else {
+		balance.Coins = balance.Coins.Sub(diff.CoinOutput.Value)
+	}
+	dbUpdateBalanceIndex(tx, bucketCoinBalanceIndex, uh, oldValue, balance.Coins)
+	dbUpdateBalanceDistribution(tx, internalCoinDistribution, e.chainCts.CurrencyUnits.OneCoin, oldValue, balance.Coins)
+	dbSetUnlockHashBalance(tx, uh, balance)
+}
+
+// dbApplyBlockStakeOutputDiff updates the block stake balance of the unlock hash of the diff's output.
+func (e *Explorer) dbApplyBlockStakeOutputDiff(tx *bolt.Tx, diff modules.BlockStakeOutputDiff) {
+	uh := diff.BlockStakeOutput.Condition.UnlockHash()
+	balance := dbGetUnlockHashBalance(tx, uh)
+	oldValue := balance.BlockStakes
+	if diff.Direction == modules.DiffApply {
+		balance.BlockStakes = balance.BlockStakes.Add(diff.BlockStakeOutput.Value)
+	} else {
+		balance.BlockStakes = balance.BlockStakes.Sub(diff.BlockStakeOutput.Value)
+	}
+	dbUpdateBalanceIndex(tx, bucketBlockStakeBalanceIndex, uh, oldValue, balance.BlockStakes)
+	dbUpdateBalanceDistribution(tx, internalBlockStakeDistribution, types.NewCurrency64(1), oldValue, balance.BlockStakes)
+	dbSetUnlockHashBalance(tx, uh, balance)
+}
+
+// Get/Set unlock hash balance, an unlock hash without any balance is removed
+func dbGetUnlockHashBalance(tx *bolt.Tx, uh types.UnlockHash) (balance unlockHashBalance) {
+	b := tx.Bucket(bucketUnlockHashBalances).Get(assertSiaMarshal(uh))
+	if b != nil {
+		assertNil(siabin.Unmarshal(b, &balance))
+	}
+	return
+}
+func dbSetUnlockHashBalance(tx *bolt.Tx, uh types.UnlockHash, balance unlockHashBalance) {
+	if balance.Coins.Equals64(0) && balance.BlockStakes.Equals64(0) {
+		mustDelete(tx.Bucket(bucketUnlockHashBalances), uh)
+		return
+	}
+	mustPut(tx.Bucket(bucketUnlockHashBalances), uh, balance)
+}
+
+// dbUpdateBalanceIndex moves the unlock hash within the given (sorted) balance index,
+// from its old balance to its new balance, only indexing non-zero balances.
+func dbUpdateBalanceIndex(tx *bolt.Tx, bucket []byte, uh types.UnlockHash, oldValue, newValue types.Currency) {
+	b := tx.Bucket(bucket)
+	if !oldValue.Equals64(0) {
+		assertNil(b.Delete(balanceIndexKey(oldValue, uh)))
+	}
+	if !newValue.Equals64(0) {
+		assertNil(b.Put(balanceIndexKey(newValue, uh), nil))
+	}
+}
+
+// balanceIndexKey creates the key of an unlock hash in a balance index,
+// the big-endian fixed-size balance followed by the unlock hash,
+// such that the keys are sorted by balance.
+func balanceIndexKey(value types.Currency, uh types.UnlockHash) []byte {
+	valueBytes := value.Big().Bytes()
+	if len(valueBytes) > balanceIndexValueSize {
+		build.Critical("balance is too large to be indexed:", value.String())
+	}
+	key := make([]byte, balanceIndexValueSize, balanceIndexValueSize+len(uh.Hash)+1)
+	copy(key[balanceIndexValueSize-len(valueBytes):], valueBytes)
+	return append(key, assertSiaMarshal(uh)...)
+}
+
+// dbUpdateBalanceDistribution moves a balance within the given distribution, from its old value to its new value.
+func dbUpdateBalanceDistribution(tx *bolt.Tx, key []byte, unit, oldValue, newValue types.Currency) {
+	distribution := dbGetBalanceDistribution(tx, key)
+	if !oldValue.Equals64(0) {
+		index := balanceDistributionBucketIndex(oldValue, unit)
+		distribution.Addresses--
+		distribution.Total = distribution.Total.Sub(oldValue)
+		distribution.Buckets[index].Addresses--
+		distribution.Buckets[index].Total = distribution.Buckets[index].Total.Sub(oldValue)
+	}
+	if !newValue.Equals64(0) {
+		index := balanceDistributionBucketIndex(newValue, unit)
+		distribution.Addresses++
+		distribution.Total = distribution.Total.Add(newValue)
+		distribution.Buckets[index].Addresses++
+		distribution.Buckets[index].Total = distribution.Buckets[index].Total.Add(newValue)
+	}
+	assertNil(tx.Bucket(bucketInternal).Put(key, assertSiaMarshal(distribution)))
+}
+
+func dbGetBalanceDistribution(tx *bolt.Tx, key []byte) (distribution balanceDistribution) {
+	b := tx.Bucket(bucketInternal).Get(key)
+	if b != nil {
+		assertNil(siabin.Unmarshal(b, &distribution))
+	}
+	if len(distribution.Buckets) != balanceDistributionBuckets {
+		distribution.Buckets = make([]balanceDistributionBucket, balanceDistributionBuckets)
+	}
+	return
+}
+
+// balanceDistributionBucketIndex returns the index of the distribution bucket the given balance belongs to,
+// being the amount of digits of the balance expressed in whole units.
+func balanceDistributionBucketIndex(value, unit types.Currency) int {
+	units := new(big.Int).Div(value.Big(), unit.Big())
+	if units.Sign() == 0 {
+		return 0
+	}
+	index := len(units.String())
+	if index >= balanceDistributionBuckets {
+		return balanceDistributionBuckets - 1
+	}
+	return index
+}
+
+// Balance returns the confirmed coin and block stake balance of the given unlock hash.
+func (e *Explorer) Balance(uh types.UnlockHash) modules.UnlockHashBalance {
+	var balance unlockHashBalance
+	err := e.db.View(func(tx *bolt.Tx) error {
+		balance = dbGetUnlockHashBalance(tx, uh)
+		return nil
+	})
+	if err != nil {
+		build.Critical("failed to get unlock hash balance:", err)
+	}
+	return modules.UnlockHashBalance{
+		UnlockHash:  uh,
+		Coins:       balance.Coins,
+		BlockStakes: balance.BlockStakes,
+	}
+}
+
+// CoinRichList returns the (at most) n unlock hashes with the highest coin balance,
+// sorted by coin balance in descending order.
+func (e *Explorer) CoinRichList(n int) []modules.UnlockHashBalance {
+	return e.richList(bucketCoinBalanceIndex, n)
+}
+
+// BlockStakeRichList returns the (at most) n unlock hashes with the highest block stake balance,
+// sorted by block stake balance in descending order.
+func (e *Explorer) BlockStakeRichList(n int) []modules.UnlockHashBalance {
+	return e.richList(bucketBlockStakeBalanceIndex, n)
+}
+
+func (e *Explorer) richList(index []byte, n int) []modules.UnlockHashBalance {
+	var balances []modules.UnlockHashBalance
+	err := e.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(index).Cursor()
+		for k, _ := cursor.Last(); k != nil && len(balances) < n; k, _ = cursor.Prev() {
+			var uh types.UnlockHash
+			err := siabin.Unmarshal(k[balanceIndexValueSize:], &uh)
+			if err != nil {
+				return err
+			}
+			balance := dbGetUnlockHashBalance(tx, uh)
+			balances = append(balances, modules.UnlockHashBalance{
+				UnlockHash:  uh,
+				Coins:       balance.Coins,
+				BlockStakes: balance.BlockStakes,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		build.Critical("failed to get rich list:", err)
+	}
+	return balances
+}
+
+// CoinDistribution returns the distribution of all coins over the unlock hashes.
+func (e *Explorer) CoinDistribution() modules.BalanceDistribution {
+	return e.distribution(internalCoinDistribution, e.chainCts.CurrencyUnits.OneCoin)
+}
+
+// BlockStakeDistribution returns the distribution of all block stakes over the unlock hashes.
+func (e *Explorer) BlockStakeDistribution() modules.BalanceDistribution {
+	return e.distribution(internalBlockStakeDistribution, types.NewCurrency64(1))
+}
+
+func (e *Explorer) distribution(key []byte, unit types.Currency) modules.BalanceDistribution {
+	var distribution balanceDistribution
+	err := e.db.View(func(tx *bolt.Tx) error {
+		distribution = dbGetBalanceDistribution(tx, key)
+		return nil
+	})
+	if err != nil {
+		build.Critical("failed to get balance distribution:", err)
+	}
+	result := modules.BalanceDistribution{
+		Addresses: distribution.Addresses,
+		Total:     distribution.Total,
+		Buckets:   make([]modules.BalanceDistributionBucket, 0, len(distribution.Buckets)),
+	}
+	minBalance := types.ZeroCurrency
+	maxBalance := unit
+	for index, bucket := range distribution.Buckets {
+		resultBucket := modules.BalanceDistributionBucket{
+			MinBalance: minBalance,
+			Addresses:  bucket.Addresses,
+			Total:      bucket.Total,
+		}
+		if index < len(distribution.Buckets)-1 {
+			max := maxBalance
+			resultBucket.MaxBalance = &max
+		}
+		result.Buckets = append(result.Buckets, resultBucket)
+		minBalance, maxBalance = maxBalance, maxBalance.Mul64(10)
+	}
+	return result
+}
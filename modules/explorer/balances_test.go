@@ -0,0 +1,116 @@
+package explorer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+func TestUnlockHashBalances(t *testing.T) {
+	db, err := persist.OpenDatabase(explorerMetadata, filepath.Join(t.TempDir(), "explorer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{bucketInternal, bucketUnlockHashBalances, bucketCoinBalanceIndex, bucketBlockStakeBalanceIndex} {
+			if _, err := tx.CreateBucket(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Explorer{db: db, chainCts: types.TestnetChainConstants()}
+	oneCoin := e.chainCts.CurrencyUnits.OneCoin
+
+	var uhs [3]types.UnlockHash
+	for i := range uhs {
+		uhs[i] = types.UnlockHash{Type: types.UnlockTypePubKey}
+		uhs[i].Hash[0] = byte(i + 1)
+	}
+	coinDiff := func(dir modules.DiffDirection, uh types.UnlockHash, value types.Currency) modules.CoinOutputDiff {
+		return modules.CoinOutputDiff{
+			Direction: dir,
+			CoinOutput: types.CoinOutput{
+				Value:     value,
+				Condition: types.NewCondition(types.NewUnlockHashCondition(uh)),
+			},
+		}
+	}
+	applyCoinDiffs := func(diffs ...modules.CoinOutputDiff) {
+		err := db.Update(func(tx *bolt.Tx) error {
+			for _, diff := range diffs {
+				e.dbApplyCoinOutputDiff(tx, diff)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	applyCoinDiffs(
+		coinDiff(modules.DiffApply, uhs[0], oneCoin.Mul64(5)),
+		coinDiff(modules.DiffApply, uhs[1], oneCoin.Mul64(500)),
+		coinDiff(modules.DiffApply, uhs[2], oneCoin.Div64(2)),
+		coinDiff(modules.DiffApply, uhs[0], oneCoin.Mul64(10)),
+	)
+	richList := e.CoinRichList(2)
+	if len(richList) != 2 {
+		t.Fatalf("expected 2 unlock hashes, got %d", len(richList))
+	}
+	if richList[0].UnlockHash != uhs[1] || !richList[0].Coins.Equals(oneCoin.Mul64(500)) {
+		t.Errorf("unexpected richest unlock hash: %v", richList[0])
+	}
+	if richList[1].UnlockHash != uhs[0] || !richList[1].Coins.Equals(oneCoin.Mul64(15)) {
+		t.Errorf("unexpected second richest unlock hash: %v", richList[1])
+	}
+
+	if balance := e.Balance(uhs[0]); !balance.Coins.Equals(oneCoin.Mul64(15)) || !balance.BlockStakes.Equals64(0) {
+		t.Errorf("unexpected balance: %v", balance)
+	}
+
+	distribution := e.CoinDistribution()
+	if distribution.Addresses != 3 || !distribution.Total.Equals(oneCoin.Mul64(515).Add(oneCoin.Div64(2))) {
+		t.Errorf("unexpected distribution: %d addresses, total %v", distribution.Addresses, distribution.Total)
+	}
+	for index, expected := range map[int]uint64{0: 1, 1: 0, 2: 1, 3: 1} {
+		if distribution.Buckets[index].Addresses != expected {
+			t.Errorf("expected %d addresses in bucket %d, got %d", expected, index, distribution.Buckets[index].Addresses)
+		}
+	}
+	if last := distribution.Buckets[len(distribution.Buckets)-1]; last.MaxBalance != nil {
+		t.Errorf("expected the last bucket to be unbounded, got %v", last.MaxBalance)
+	}
+
+	// reverting all diffs brings us back to an empty state
+	applyCoinDiffs(
+		coinDiff(modules.DiffRevert, uhs[0], oneCoin.Mul64(10)),
+		coinDiff(modules.DiffRevert, uhs[2], oneCoin.Div64(2)),
+		coinDiff(modules.DiffRevert, uhs[1], oneCoin.Mul64(500)),
+		coinDiff(modules.DiffRevert, uhs[0], oneCoin.Mul64(5)),
+	)
+	if richList := e.CoinRichList(10); len(richList) != 0 {
+		t.Errorf("expected an empty rich list, got %v", richList)
+	}
+	if distribution := e.CoinDistribution(); distribution.Addresses != 0 || !distribution.Total.Equals64(0) {
+		t.Errorf("expected an empty distribution, got %d addresses, total %v", distribution.Addresses, distribution.Total)
+	}
+	err = db.View(func(tx *bolt.Tx) error {
+		if !bucketIsEmpty(tx.Bucket(bucketUnlockHashBalances)) {
+			t.Error("expected no balances to be tracked")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -27,12 +27,19 @@ var (
 	// used to map (single-signature) wallet addresses to all the
 	// multisig addresses they are part of
 	bucketWalletAddressToMultiSigAddressMapping = []byte("WalletAddressToMultiSigAddressMapping")
+	// used to track the balance of each unlock hash,
+	// as well as to index these balances sorted by value
+	bucketUnlockHashBalances     = []byte("UnlockHashBalances")
+	bucketCoinBalanceIndex       = []byte("CoinBalanceIndex")
+	bucketBlockStakeBalanceIndex = []byte("BlockStakeBalanceIndex")
 
 	errNotExist = errors.New("entry does not exist")
 
 	// keys for bucketInternal
-	internalBlockHeight  = []byte("BlockHeight")
-	internalRecentChange = []byte("RecentChange")
+	internalBlockHeight            = []byte("BlockHeight")
+	internalRecentChange           = []byte("RecentChange")
+	internalCoinDistribution       = []byte("CoinDistribution")
+	internalBlockStakeDistribution = []byte("BlockStakeDistribution")
 )
 
 // These functions all return a 'func(*bolt.Tx) error', which, allows them to
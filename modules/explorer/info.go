@@ -1,11 +1,16 @@
 package explorer
 
 import (
+	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	bolt "github.com/rivine/bbolt"
 	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/crypto"
 	"github.com/threefoldtech/rivine/modules"
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	"github.com/threefoldtech/rivine/types"
@@ -278,3 +283,58 @@ func (e *Explorer) getStats(start types.BlockHeight, end types.BlockHeight) (*mo
 func (e *Explorer) Constants() modules.DaemonConstants {
 	return modules.NewDaemonConstants(e.bcInfo, e.chainCts)
 }
+
+// hashPrefixIndices are the indices searched for hashes starting with a given prefix,
+// all of them being keyed by the (siabin-encoded) hash. A block ID is indexed as transaction ID as well
+// (for its miner payouts), such that the order of the indices defines the type a hash resolves to.
+var hashPrefixIndices = []struct {
+	bucket   []byte
+	hashType string
+}{
+	{bucketBlockIDs, modules.ExplorerHashTypeBlockID},
+	{bucketTransactionIDs, modules.ExplorerHashTypeTransactionID},
+	{bucketCoinOutputIDs, modules.ExplorerHashTypeCoinOutputID},
+	{bucketBlockStakeOutputIDs, modules.ExplorerHashTypeBlockStakeOutputID},
+}
+
+// HashesWithPrefix returns the (at most) n block, transaction, coin output and
+// block stake output IDs starting with the given hex-encoded prefix.
+func (e *Explorer) HashesWithPrefix(prefix string, n int) ([]modules.HashPrefixMatch, error) {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) > crypto.HashSize*2 {
+		return nil, fmt.Errorf("invalid hash prefix %q: longer than a hash", prefix)
+	}
+	// the index is searched using the whole bytes of the prefix,
+	// filtering on the final half byte of an odd-length prefix afterwards
+	seek, err := hex.DecodeString(prefix[:len(prefix)/2*2])
+	if err == nil && len(prefix)%2 == 1 {
+		_, err = strconv.ParseUint(prefix[len(prefix)-1:], 16, 8)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash prefix %q: not hex-encoded", prefix)
+	}
+	var matches []modules.HashPrefixMatch
+	err = e.db.View(func(tx *bolt.Tx) error {
+		found := make(map[crypto.Hash]struct{})
+		for _, index := range hashPrefixIndices {
+			c := tx.Bucket(index.bucket).Cursor()
+			for k, _ := c.Seek(seek); k != nil && bytes.HasPrefix(k, seek) && len(matches) < n; k, _ = c.Next() {
+				if len(k) != crypto.HashSize || !strings.HasPrefix(hex.EncodeToString(k), prefix) {
+					continue
+				}
+				var hash crypto.Hash
+				copy(hash[:], k)
+				if _, ok := found[hash]; ok {
+					continue
+				}
+				found[hash] = struct{}{}
+				matches = append(matches, modules.HashPrefixMatch{
+					Type: index.hashType,
+					Hash: hash,
+				})
+			}
+		}
+		return nil
+	})
+	return matches, err
+}
@@ -1,5 +1,17 @@
 package explorer
 
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
 /* TODO: enable and fix
 
 // TestImmediateBlockFacts grabs the block facts object from the block explorer
@@ -70,3 +82,78 @@ func TestBlockFacts(t *testing.T) {
 	}
 }
 */
+
+func TestHashesWithPrefix(t *testing.T) {
+	db, err := persist.OpenDatabase(explorerMetadata, filepath.Join(t.TempDir(), "explorer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	e := &Explorer{db: db}
+
+	hash := func(s string) (h crypto.Hash) {
+		if err := h.LoadString(s + "00000000000000000000000000000000000000000000000000000000"); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	blockID := types.BlockID(hash("abcd1234"))
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range hashPrefixIndices {
+			if _, err := tx.CreateBucket(b.bucket); err != nil {
+				return err
+			}
+		}
+		dbAddBlockID(tx, blockID, 1)
+		dbAddTransactionID(tx, types.TransactionID(blockID), 1)
+		dbAddTransactionID(tx, types.TransactionID(hash("abce0000")), 1)
+		dbAddCoinOutputID(tx, types.CoinOutputID(hash("abd00000")), types.TransactionID(blockID))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		prefix  string
+		n       int
+		matches []modules.HashPrefixMatch
+	}{
+		{"abcd", 10, []modules.HashPrefixMatch{{Type: modules.ExplorerHashTypeBlockID, Hash: hash("abcd1234")}}},
+		{"ABCD1", 10, []modules.HashPrefixMatch{{Type: modules.ExplorerHashTypeBlockID, Hash: hash("abcd1234")}}},
+		{"abc", 10, []modules.HashPrefixMatch{
+			{Type: modules.ExplorerHashTypeBlockID, Hash: hash("abcd1234")},
+			{Type: modules.ExplorerHashTypeTransactionID, Hash: hash("abce0000")},
+		}},
+		{"ab", 2, []modules.HashPrefixMatch{
+			{Type: modules.ExplorerHashTypeBlockID, Hash: hash("abcd1234")},
+			{Type: modules.ExplorerHashTypeTransactionID, Hash: hash("abce0000")},
+		}},
+		{"abd", 10, []modules.HashPrefixMatch{{Type: modules.ExplorerHashTypeCoinOutputID, Hash: hash("abd00000")}}},
+		{"abcd2", 10, nil},
+		{"ff", 10, nil},
+	}
+	for _, tc := range testCases {
+		matches, err := e.HashesWithPrefix(tc.prefix, tc.n)
+		if err != nil {
+			t.Errorf("prefix %q: unexpected error: %v", tc.prefix, err)
+			continue
+		}
+		if len(matches) != len(tc.matches) {
+			t.Errorf("prefix %q: expected %v, got %v", tc.prefix, tc.matches, matches)
+			continue
+		}
+		for i := range matches {
+			if matches[i] != tc.matches[i] {
+				t.Errorf("prefix %q: expected %v, got %v", tc.prefix, tc.matches, matches)
+				break
+			}
+		}
+	}
+
+	for _, prefix := range []string{"abcg", "abg", "ab" + crypto.Hash{}.String()} {
+		if _, err := e.HashesWithPrefix(prefix, 10); err == nil {
+			t.Errorf("prefix %q: expected an error", prefix)
+		}
+	}
+}
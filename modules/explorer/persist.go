@@ -48,6 +48,28 @@ func (e *Explorer) initPersist(verbose bool) error {
 
 	// Initialize the database
 	err = e.db.Update(func(tx *bolt.Tx) error {
+		// a database created by a version which did not track the balances of unlock hashes yet
+		// is reset, such that it is rebuilt from scratch, the balances being computed as the blocks are applied
+		if tx.Bucket(bucketBlockIDs) != nil && tx.Bucket(bucketUnlockHashBalances) == nil {
+			e.log.Println("[INFO] explorer database does not track balances yet, resetting it so it is rebuilt")
+			var names [][]byte
+			err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+				if string(name) != "Metadata" {
+					names = append(names, append([]byte(nil), name...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				err = tx.DeleteBucket(name)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
 		buckets := [][]byte{
 			bucketBlockFacts,
 			bucketBlockIDs,
@@ -61,6 +83,9 @@ func (e *Explorer) initPersist(verbose bool) error {
 			bucketTransactionIDs,
 			bucketUnlockHashes,
 			bucketWalletAddressToMultiSigAddressMapping,
+			bucketUnlockHashBalances,
+			bucketCoinBalanceIndex,
+			bucketBlockStakeBalanceIndex,
 		}
 		for _, b := range buckets {
 			_, err := tx.CreateBucketIfNotExists(b)
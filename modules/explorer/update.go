@@ -208,6 +208,14 @@ func (e *Explorer) ProcessConsensusChange(cc modules.ConsensusChange) {
 			}
 		}
 
+		// update the balances of all unlock hashes affected by the change
+		for _, diff := range cc.CoinOutputDiffs {
+			e.dbApplyCoinOutputDiff(tx, diff)
+		}
+		for _, diff := range cc.BlockStakeOutputDiffs {
+			e.dbApplyBlockStakeOutputDiff(tx, diff)
+		}
+
 		// set final blockheight
 		err = dbSetInternal(internalBlockHeight, blockheight)(tx)
 		if err != nil {
@@ -517,7 +525,8 @@ func mapUnlockConditionMultiSigAddress(tx *bolt.Tx, muh types.UnlockHash, cond t
 		}
 		mapUnlockConditionMultiSigAddress(tx, muh, cond, txid)
 
-	case types.ConditionTypeMultiSignature:
+	case types.ConditionTypeMultiSignature, types.ConditionTypeWeightedMultiSignature,
+		types.ConditionTypeAnyOf, types.ConditionTypeAllOf:
 		mcond, ok := cond.(types.UnlockHashSliceGetter)
 		if !ok {
 			build.Severe(fmt.Errorf("unexpected Go-type for MultiSignatureCondition: %T", cond))
@@ -544,7 +553,8 @@ func unmapUnlockConditionMultiSigAddress(tx *bolt.Tx, muh types.UnlockHash, cond
 		}
 		unmapUnlockConditionMultiSigAddress(tx, muh, cond, txid)
 
-	case types.ConditionTypeMultiSignature:
+	case types.ConditionTypeMultiSignature, types.ConditionTypeWeightedMultiSignature,
+		types.ConditionTypeAnyOf, types.ConditionTypeAllOf:
 		mcond, ok := cond.(types.UnlockHashSliceGetter)
 		if !ok {
 			build.Severe(fmt.Errorf("unexpected Go-type for MultiSignatureCondition: %T", cond))
@@ -2,6 +2,7 @@ package modules
 
 import (
 	"net"
+	"time"
 
 	"github.com/threefoldtech/rivine/build"
 )
@@ -22,6 +23,24 @@ type (
 		Version build.ProtocolVersion `json:"version"`
 	}
 
+	// PeerStats contains the statistics of a connected peer.
+	PeerStats struct {
+		Peer
+		// ConnectedSince is the unix timestamp at which the connection was established
+		ConnectedSince int64 `json:"connectedsince"`
+		// BytesSent and BytesReceived count the bytes exchanged over the connection
+		BytesSent     uint64 `json:"bytessent"`
+		BytesReceived uint64 `json:"bytesreceived"`
+	}
+
+	// PeerBan describes a banned host, of which all connections are refused.
+	PeerBan struct {
+		Host string `json:"host"`
+		// Expires is the unix timestamp at which the ban is lifted, 0 for a permanent ban
+		Expires int64  `json:"expires"`
+		Reason  string `json:"reason,omitempty"`
+	}
+
 	// A PeerConn is the connection type used when communicating with peers during
 	// an RPC. It is identical to a net.Conn with the additional RPCAddr method.
 	// This method acts as an identifier for peers and is the address that the
@@ -56,6 +75,20 @@ type (
 		// Peers returns the addresses that the Gateway is currently connected to.
 		Peers() []Peer
 
+		// PeerStats returns the statistics of the peers that the Gateway is currently connected to.
+		PeerStats() []PeerStats
+
+		// BanPeer disconnects from all peers of the host of the given address,
+		// and refuses any connection with that host for the given duration.
+		// A duration of 0 bans the host permanently.
+		BanPeer(addr NetAddress, duration time.Duration, reason string) error
+
+		// UnbanPeer lifts the ban of the host of the given address.
+		UnbanPeer(NetAddress) error
+
+		// PeerBans returns all active bans.
+		PeerBans() []PeerBan
+
 		// RegisterRPC registers a function to handle incoming connections that
 		// supply the given RPC ID.
 		RegisterRPC(string, RPCFunc)
@@ -87,6 +120,12 @@ type (
 		// Online returns true if the gateway is connected to remote hosts
 		Online() bool
 
+		// ClockOffset returns the median offset, in seconds, between the clocks
+		// of the connected peers and our local clock, as well as the amount of peers
+		// that were used to compute it. The network-adjusted time is the
+		// local time with this offset added to it.
+		ClockOffset() (offset int64, samples int)
+
 		// Close safely stops the Gateway's listener process.
 		Close() error
 	}
@@ -0,0 +1,204 @@
+package gateway
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/persist"
+)
+
+const (
+	// bansFile is the name of the file that contains all banned hosts.
+	bansFile = "bans.json"
+)
+
+var (
+	errHostBanned = errors.New("host is banned")
+	errNotBanned  = errors.New("host is not banned")
+)
+
+// bansMetadata contains the header and version strings that identify the
+// gateway bans file.
+var bansMetadata = persist.Metadata{
+	Header:  "Rivine Gateway Bans",
+	Version: "1.0.0",
+}
+
+// countingConn wraps a net.Conn, counting the bytes read from and written to it.
+type countingConn struct {
+	net.Conn
+	sent, received uint64
+}
+
+// Read implements io.Reader.Read
+func (cc *countingConn) Read(b []byte) (int, error) {
+	n, err := cc.Conn.Read(b)
+	atomic.AddUint64(&cc.received, uint64(n))
+	return n, err
+}
+
+// Write implements io.Writer.Write
+func (cc *countingConn) Write(b []byte) (int, error) {
+	n, err := cc.Conn.Write(b)
+	atomic.AddUint64(&cc.sent, uint64(n))
+	return n, err
+}
+
+// banHost returns the host to ban for the given address,
+// which can be either an IP address or an IP address with a port.
+func banHost(addr modules.NetAddress) (string, error) {
+	host := string(addr)
+	if h := addr.Host(); h != "" {
+		host = h
+	}
+	if net.ParseIP(host) == nil {
+		return "", errors.New("address must be an IP address")
+	}
+	return host, nil
+}
+
+// isBanned returns true if the host of the given address is currently banned.
+// Expired bans are removed as a side effect, therefore the lock has to be held.
+func (g *Gateway) isBanned(addr modules.NetAddress) bool {
+	host := addr.Host()
+	if host == "" {
+		host = string(addr)
+	}
+	ban, ok := g.bans[host]
+	if !ok {
+		return false
+	}
+	if ban.Expires != 0 && ban.Expires <= time.Now().Unix() {
+		delete(g.bans, host)
+		return false
+	}
+	return true
+}
+
+// BanPeer implements modules.Gateway.BanPeer
+func (g *Gateway) BanPeer(addr modules.NetAddress, duration time.Duration, reason string) error {
+	if err := g.threads.Add(); err != nil {
+		return err
+	}
+	defer g.threads.Done()
+	host, err := banHost(addr)
+	if err != nil {
+		return err
+	}
+	if duration < 0 {
+		return errors.New("ban duration cannot be negative")
+	}
+	ban := modules.PeerBan{Host: host, Reason: reason}
+	if duration > 0 {
+		ban.Expires = time.Now().Add(duration).Unix()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if host == g.myAddr.Host() {
+		return errors.New("can't ban our own address")
+	}
+	g.bans[host] = ban
+	// disconnect from all peers of the banned host,
+	// and forget about its nodes such that they aren't connected to again
+	for addr, p := range g.peers {
+		if addr.Host() == host {
+			p.sess.Close()
+			delete(g.peers, addr)
+			g.log.Println("INFO: disconnected from banned peer", addr)
+		}
+	}
+	for addr := range g.nodes {
+		if addr.Host() == host {
+			delete(g.nodes, addr)
+		}
+	}
+	g.log.Printf("INFO: banned host %s (expires: %d): %s", host, ban.Expires, reason)
+	return g.saveSync()
+}
+
+// UnbanPeer implements modules.Gateway.UnbanPeer
+func (g *Gateway) UnbanPeer(addr modules.NetAddress) error {
+	if err := g.threads.Add(); err != nil {
+		return err
+	}
+	defer g.threads.Done()
+	host, err := banHost(addr)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.isBanned(modules.NetAddress(host)) {
+		return errNotBanned
+	}
+	delete(g.bans, host)
+	g.log.Println("INFO: unbanned host", host)
+	return g.saveSync()
+}
+
+// PeerBans implements modules.Gateway.PeerBans
+func (g *Gateway) PeerBans() []modules.PeerBan {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var bans []modules.PeerBan
+	for host, ban := range g.bans {
+		if g.isBanned(modules.NetAddress(host)) {
+			bans = append(bans, ban)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Host < bans[j].Host
+	})
+	return bans
+}
+
+// PeerStats implements modules.Gateway.PeerStats
+func (g *Gateway) PeerStats() []modules.PeerStats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var stats []modules.PeerStats
+	for _, p := range g.peers {
+		s := modules.PeerStats{
+			Peer:           p.Peer,
+			ConnectedSince: p.connectedSince.Unix(),
+		}
+		if p.conn != nil {
+			s.BytesSent = atomic.LoadUint64(&p.conn.sent)
+			s.BytesReceived = atomic.LoadUint64(&p.conn.received)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].NetAddress < stats[j].NetAddress
+	})
+	return stats
+}
+
+// loadBans loads the banned hosts from disk, if any were persisted.
+func (g *Gateway) loadBans() error {
+	var bans []modules.PeerBan
+	err := persist.LoadJSON(bansMetadata, &bans, filepath.Join(g.persistDir, bansFile))
+	if err != nil {
+		return err
+	}
+	for _, ban := range bans {
+		g.bans[ban.Host] = ban
+	}
+	return nil
+}
+
+// saveBans stores the banned hosts on disk.
+func (g *Gateway) saveBans() error {
+	bans := make([]modules.PeerBan, 0, len(g.bans))
+	for _, ban := range g.bans {
+		bans = append(bans, ban)
+	}
+	return persist.SaveJSON(bansMetadata, bans, filepath.Join(g.persistDir, bansFile))
+}
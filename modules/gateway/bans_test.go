@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// TestBanPeer tests that banned hosts are disconnected, refused as nodes,
+// persisted and can be unbanned again.
+func TestBanPeer(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	persistDir := build.TempDir("gateway", t.Name())
+	g, err := New("localhost:0", false, 1, persistDir,
+		types.DefaultBlockchainInfo(), types.TestnetChainConstants(), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add a peer and node of the host to ban
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal("couldn't start listener:", err)
+	}
+	defer l.Close()
+	go l.Accept()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal("dial failed:", err)
+	}
+	g.mu.Lock()
+	g.addPeer(&peer{
+		Peer: modules.Peer{NetAddress: "1.2.3.4:23112"},
+		sess: newSmuxClient(conn),
+	})
+	g.addNode("1.2.3.4:23112")
+	g.mu.Unlock()
+
+	if err = g.BanPeer("foo.com:123", 0, ""); err == nil {
+		t.Error("only IP addresses should be banned")
+	}
+	if err = g.BanPeer("1.2.3.4", 0, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.BanPeer("5.6.7.8:23112", time.Hour, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Peers()) != 0 {
+		t.Error("peer of banned host should be disconnected:", g.Peers())
+	}
+	g.mu.Lock()
+	_, exists := g.nodes["1.2.3.4:23112"]
+	err = g.addNode("1.2.3.4:23113")
+	g.mu.Unlock()
+	if exists || err != errHostBanned {
+		t.Errorf("banned host shouldn't be a node: %v (%v)", exists, err)
+	}
+	if err = g.Connect("1.2.3.4:23112"); err != errHostBanned {
+		t.Errorf("expected banned host to be refused, not: %v", err)
+	}
+
+	// bans are persisted
+	if err = g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err = New("localhost:0", false, 1, persistDir,
+		types.DefaultBlockchainInfo(), types.TestnetChainConstants(), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	bans := g.PeerBans()
+	if len(bans) != 2 || bans[0].Host != "1.2.3.4" || bans[0].Expires != 0 || bans[0].Reason != "spam" ||
+		bans[1].Host != "5.6.7.8" || bans[1].Expires == 0 {
+		t.Fatalf("unexpected bans: %+v", bans)
+	}
+
+	if err = g.UnbanPeer("1.2.3.4:23112"); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.UnbanPeer("1.2.3.4"); err != errNotBanned {
+		t.Errorf("expected host to no longer be banned, not: %v", err)
+	}
+
+	// expired bans are lifted
+	g.mu.Lock()
+	g.bans["5.6.7.8"] = modules.PeerBan{Host: "5.6.7.8", Expires: time.Now().Add(-time.Second).Unix()}
+	g.mu.Unlock()
+	if bans = g.PeerBans(); len(bans) != 0 {
+		t.Errorf("unexpected bans: %+v", bans)
+	}
+}
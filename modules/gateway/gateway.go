@@ -146,6 +146,14 @@ type Gateway struct {
 	peers  map[modules.NetAddress]*peer
 	peerTG siasync.ThreadGroup
 
+	// peerClockOffsets stores for each (outbound) peer the offset,
+	// in seconds, between the clock of that peer and our local clock.
+	peerClockOffsets map[modules.NetAddress]int64
+
+	// bans are the banned hosts, indexed by host,
+	// of which all connections are refused
+	bans map[string]modules.PeerBan
+
 	// Utilities.
 	log        *persist.Logger
 	mu         sync.RWMutex
@@ -208,6 +216,10 @@ func New(addr string, bootstrap bool, concurrentRPCPerPeer uint64, persistDir st
 		nodes: make(map[modules.NetAddress]*node),
 		peers: make(map[modules.NetAddress]*peer),
 
+		peerClockOffsets: make(map[modules.NetAddress]int64),
+
+		bans: make(map[string]modules.PeerBan),
+
 		persistDir: persistDir,
 
 		bcInfo:         bcInfo,
@@ -246,12 +258,16 @@ func New(addr string, bootstrap bool, concurrentRPCPerPeer uint64, persistDir st
 	// Register RPCs.
 	g.RegisterRPC("ShareNodes", g.shareNodes)
 	g.RegisterRPC("DiscoverIP", g.discoverPeerIP)
+	g.RegisterRPC("ShareTime", g.shareTime)
 	g.RegisterConnectCall("ShareNodes", g.requestNodes)
+	g.RegisterConnectCall("ShareTime", g.requestTime)
 	// Establish the de-registration of the RPCs.
 	g.threads.OnStop(func() {
 		g.UnregisterRPC("ShareNodes")
 		g.UnregisterRPC("DiscoverIP")
+		g.UnregisterRPC("ShareTime")
 		g.UnregisterConnectCall("ShareNodes")
+		g.UnregisterConnectCall("ShareTime")
 	})
 
 	// Load the old node list. If it doesn't exist, no problem, but if it does,
@@ -259,6 +275,9 @@ func New(addr string, bootstrap bool, concurrentRPCPerPeer uint64, persistDir st
 	if loadErr := g.load(); loadErr != nil && !os.IsNotExist(loadErr) {
 		return nil, loadErr
 	}
+	if loadErr := g.loadBans(); loadErr != nil && !os.IsNotExist(loadErr) {
+		return nil, loadErr
+	}
 	// Spawn the thread to periodically save the gateway.
 	go g.threadedSaveLoop()
 	// Make sure that the gateway saves after shutdown.
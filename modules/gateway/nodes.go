@@ -29,6 +29,8 @@ func (g *Gateway) addNode(addr modules.NetAddress) error {
 		return errOurAddress
 	} else if _, exists := g.nodes[addr]; exists {
 		return errNodeExists
+	} else if g.isBanned(addr) {
+		return errHostBanned
 	} else if addr.IsStdValid() != nil {
 		return errors.New("address is not valid: " + string(addr))
 	} else if net.ParseIP(addr.Host()) == nil {
@@ -37,6 +37,10 @@ func (s insufficientVersionError) Error() string {
 type peer struct {
 	modules.Peer
 	sess streamSession
+	// conn counts the bytes exchanged with the peer,
+	// connectedSince is the time at which the connection was established
+	conn           *countingConn
+	connectedSince time.Time
 	// rate limiting channel
 	token chan struct{}
 }
@@ -138,6 +142,15 @@ func (g *Gateway) threadedAcceptConn(conn net.Conn) {
 	addr := modules.NetAddress(conn.RemoteAddr().String())
 	g.log.Debugf("INFO: %v wants to connect", addr)
 
+	g.mu.Lock()
+	banned := g.isBanned(addr)
+	g.mu.Unlock()
+	if banned {
+		g.log.Debugf("INFO: %v wanted to connect, but is banned", addr)
+		conn.Close()
+		return
+	}
+
 	remoteInfo, err := g.acceptConnHandshake(conn, g.bcInfo.ProtocolVersion, g.id)
 	if err != nil {
 		g.log.Debugf("INFO: %v wanted to connect but handshake failed: %v", addr, err)
@@ -170,6 +183,7 @@ func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteInfo remoteInfo) er
 	remoteAddr := modules.NetAddress(net.JoinHostPort(remoteIP, remotePort))
 
 	// Accept the peer.
+	counted := &countingConn{Conn: conn}
 	peer := &peer{
 		Peer: modules.Peer{
 			Inbound: true,
@@ -181,8 +195,10 @@ func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteInfo remoteInfo) er
 			NetAddress: remoteAddr,
 			Version:    remoteInfo.Version,
 		},
-		sess:  newSmuxServer(conn),
-		token: make(chan struct{}, g.concurrentRPCPerPeer),
+		sess:           newSmuxServer(counted),
+		conn:           counted,
+		connectedSince: time.Now(),
+		token:          make(chan struct{}, g.concurrentRPCPerPeer),
 	}
 	for i := 0; uint64(i) < g.concurrentRPCPerPeer; i++ {
 		// Fill the channel wit htokens
@@ -640,12 +656,16 @@ func (g *Gateway) managedConnect(addr modules.NetAddress) error {
 	if net.ParseIP(addr.Host()) == nil {
 		return errors.New("address must be an IP address")
 	}
-	g.mu.RLock()
+	g.mu.Lock()
 	_, exists := g.peers[addr]
-	g.mu.RUnlock()
+	banned := g.isBanned(addr)
+	g.mu.Unlock()
 	if exists {
 		return errPeerExists
 	}
+	if banned {
+		return errHostBanned
+	}
 
 	// Dial the peer and perform peer initialization.
 	conn, err := g.dial(addr)
@@ -667,7 +687,13 @@ func (g *Gateway) managedConnect(addr modules.NetAddress) error {
 	// Add the peer.
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	if g.isBanned(addr) {
+		// banned while the handshake was performed
+		conn.Close()
+		return errHostBanned
+	}
 
+	counted := &countingConn{Conn: conn}
 	peer := &peer{
 		Peer: modules.Peer{
 			Inbound:    false,
@@ -675,8 +701,10 @@ func (g *Gateway) managedConnect(addr modules.NetAddress) error {
 			NetAddress: addr,
 			Version:    remoteInfo.Version,
 		},
-		sess:  newSmuxClient(conn),
-		token: make(chan struct{}, g.concurrentRPCPerPeer),
+		sess:           newSmuxClient(counted),
+		conn:           counted,
+		connectedSince: time.Now(),
+		token:          make(chan struct{}, g.concurrentRPCPerPeer),
 	}
 	for i := 0; uint64(i) < g.concurrentRPCPerPeer; i++ {
 		// Fill the channel with tokens
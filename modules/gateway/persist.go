@@ -48,7 +48,11 @@ func (g *Gateway) load() error {
 // saveSync stores the Gateway's persistent data on disk, and then syncs to
 // disk to minimize the possibility of data loss.
 func (g *Gateway) saveSync() error {
-	return persist.SaveJSON(persistMetadata, g.persistData(), filepath.Join(g.persistDir, nodesFile))
+	err := persist.SaveJSON(persistMetadata, g.persistData(), filepath.Join(g.persistDir, nodesFile))
+	if err != nil {
+		return err
+	}
+	return g.saveBans()
 }
 
 // threadedSaveLoop periodically saves the gateway.
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"sort"
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// shareTime is the receiving end of the ShareTime RPC.
+// It writes the current (local) time of this node to the caller.
+func (g *Gateway) shareTime(conn modules.PeerConn) error {
+	conn.SetDeadline(time.Now().Add(connStdDeadline))
+	return siabin.WriteObject(conn, types.CurrentTimestamp())
+}
+
+// requestTime is the calling end of the ShareTime RPC.
+// It stores the offset between the clock of the peer and our local clock.
+func (g *Gateway) requestTime(conn modules.PeerConn) error {
+	start := time.Now()
+	conn.SetDeadline(start.Add(connStdDeadline))
+
+	var remoteTime types.Timestamp
+	if err := siabin.ReadObject(conn, &remoteTime, 8); err != nil {
+		return err
+	}
+	// assume the remote time was taken halfway the round trip
+	localTime := start.Add(time.Since(start) / 2).Unix()
+
+	g.mu.Lock()
+	g.peerClockOffsets[conn.RPCAddr()] = int64(remoteTime) - localTime
+	g.mu.Unlock()
+	return nil
+}
+
+// ClockOffset implements modules.Gateway.ClockOffset
+func (g *Gateway) ClockOffset() (int64, int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	offsets := make([]int64, 0, len(g.peerClockOffsets))
+	for addr, offset := range g.peerClockOffsets {
+		// only take the offsets of the currently connected peers into account
+		if _, ok := g.peers[addr]; ok {
+			offsets = append(offsets, offset)
+		}
+	}
+	if len(offsets) == 0 {
+		return 0, 0
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+	return offsets[len(offsets)/2], len(offsets)
+}
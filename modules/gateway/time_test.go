@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+)
+
+// TestShareTime tests that the clock offset of a peer is stored using the ShareTime RPC.
+func TestShareTime(t *testing.T) {
+	g := &Gateway{peerClockOffsets: make(map[modules.NetAddress]int64)}
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- g.shareTime(peerConn{Conn: remote})
+	}()
+	if err := g.requestTime(peerConn{Conn: local, dialbackAddr: "1.2.3.4:23112"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	// both ends share the same clock
+	if offset, ok := g.peerClockOffsets["1.2.3.4:23112"]; !ok || offset < -1 || offset > 1 {
+		t.Errorf("unexpected clock offset: %d (%t)", offset, ok)
+	}
+}
+
+// TestClockOffset tests that the median clock offset of the connected peers is returned.
+func TestClockOffset(t *testing.T) {
+	g := &Gateway{
+		peers:            make(map[modules.NetAddress]*peer),
+		peerClockOffsets: make(map[modules.NetAddress]int64),
+	}
+	if offset, samples := g.ClockOffset(); offset != 0 || samples != 0 {
+		t.Errorf("unexpected clock offset without peers: %d (%d samples)", offset, samples)
+	}
+
+	for addr, offset := range map[modules.NetAddress]int64{
+		"1.1.1.1:23112": -5,
+		"2.2.2.2:23112": 120,
+		"3.3.3.3:23112": 3,
+	} {
+		g.peers[addr] = &peer{Peer: modules.Peer{NetAddress: addr}}
+		g.peerClockOffsets[addr] = offset
+	}
+	// offsets of disconnected peers are ignored
+	g.peerClockOffsets["4.4.4.4:23112"] = 300
+	g.peerClockOffsets["5.5.5.5:23112"] = 400
+
+	if offset, samples := g.ClockOffset(); offset != 3 || samples != 3 {
+		t.Errorf("unexpected clock offset: %d (%d samples)", offset, samples)
+	}
+}
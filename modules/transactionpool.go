@@ -2,7 +2,9 @@ package modules
 
 import (
 	"errors"
+	"sort"
 
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -30,6 +32,10 @@ var (
 	// ErrTransactionNotFound is returned in case no transaction could be found
 	// in the transaction pool for a specific ID.
 	ErrTransactionNotFound = errors.New("transaction not found")
+
+	// ErrDoubleSpendProofNotFound is returned in case no double spend proof could be found
+	// in the transaction pool for a specific output ID.
+	ErrDoubleSpendProofNotFound = errors.New("double spend proof not found")
 )
 
 const (
@@ -64,6 +70,10 @@ type TransactionPool interface {
 	// transactions.
 	AcceptTransactionSet([]types.Transaction) error
 
+	// ValidateTransactionSet validates a set of potentially interdependent transactions,
+	// as if it were accepted, without adding it to the pool or relaying it.
+	ValidateTransactionSet([]types.Transaction) error
+
 	// Close is necessary for clean shutdown (e.g. during testing).
 	Close() error
 
@@ -91,4 +101,111 @@ type TransactionPool interface {
 	// Unsubscribe removes a subscriber from the transaction pool.
 	// This is necessary for clean shutdown of the miner.
 	Unsubscribe(TransactionPoolSubscriber)
+
+	// DoubleSpendProofs returns the proofs of all double spends detected by the transaction pool,
+	// which are created for transactions rejected as they spend an output already spent by a pool transaction.
+	DoubleSpendProofs() []types.DoubleSpendProof
+
+	// DoubleSpendProof returns the proof of the detected double spend of the output with the given ID.
+	// If no double spend was detected for that output ErrDoubleSpendProofNotFound is returned.
+	DoubleSpendProof(id types.OutputID) (types.DoubleSpendProof, error)
+}
+
+// TransactionPackage groups unconfirmed transactions which depend on one another,
+// and thus have to be included atomically into a block.
+// The transactions are ordered such that parents precede their children.
+type TransactionPackage struct {
+	Transactions []types.Transaction
+	// Fees is the sum of all miner fees paid by the transactions of this package.
+	Fees types.Currency
+	// Size is the total (siabin) encoded size of all transactions in this package.
+	Size uint64
+}
+
+// HasHigherFeeRate returns true if this package pays more fees per byte
+// than the other package.
+func (p TransactionPackage) HasHigherFeeRate(other TransactionPackage) bool {
+	return p.Fees.Mul64(other.Size).Cmp(other.Fees.Mul64(p.Size)) > 0
+}
+
+// NewTransactionPackages groups the given unconfirmed transactions into packages,
+// such that all transactions which depend on one another (directly or indirectly)
+// end up in the same package. The given transactions are expected to be in an order
+// that can acceptably be put into a block, which is an order that is kept within a package.
+//
+// The returned packages are sorted from the highest to the lowest fee rate,
+// packages with an equal fee rate keep the order in which they were given.
+func NewTransactionPackages(txns []types.Transaction) ([]TransactionPackage, error) {
+	// link each transaction to the first transaction of its package,
+	// using a disjoint-set forest
+	roots := make([]int, len(txns))
+	for i := range roots {
+		roots[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if roots[i] != i {
+			roots[i] = find(roots[i])
+		}
+		return roots[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if ra < rb {
+			roots[rb] = ra
+		} else {
+			roots[ra] = rb
+		}
+	}
+
+	outputs := make(map[types.OutputID]int)
+	for i, txn := range txns {
+		for _, ci := range txn.CoinInputs {
+			if parent, ok := outputs[types.OutputID(ci.ParentID)]; ok {
+				union(i, parent)
+			}
+		}
+		for _, bsi := range txn.BlockStakeInputs {
+			if parent, ok := outputs[types.OutputID(bsi.ParentID)]; ok {
+				union(i, parent)
+			}
+		}
+		for j := range txn.CoinOutputs {
+			outputs[types.OutputID(txn.CoinOutputID(uint64(j)))] = i
+		}
+		for j := range txn.BlockStakeOutputs {
+			outputs[types.OutputID(txn.BlockStakeOutputID(uint64(j)))] = i
+		}
+	}
+
+	// collect the packages, in order of appearance
+	var packages []TransactionPackage
+	packageIndices := make(map[int]int)
+	for i, txn := range txns {
+		b, err := siabin.Marshal(txn)
+		if err != nil {
+			return nil, err
+		}
+		root := find(i)
+		idx, ok := packageIndices[root]
+		if !ok {
+			idx = len(packages)
+			packageIndices[root] = idx
+			packages = append(packages, TransactionPackage{})
+		}
+		pkg := &packages[idx]
+		pkg.Transactions = append(pkg.Transactions, txn)
+		pkg.Size += uint64(len(b))
+		for _, fee := range txn.MinerFees {
+			pkg.Fees = pkg.Fees.Add(fee)
+		}
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].HasHigherFeeRate(packages[j])
+	})
+	return packages, nil
 }
@@ -66,9 +66,35 @@ func (tp *TransactionPool) validateTransactionSetComposition(ts []types.Transact
 // transaction pool, and then adds it to the transaction pool.
 func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 	tp.log.Debug("Trying to accept transaction set")
+	ts, setID, cc, err := tp.validateTransactionSet(ts)
+	if err != nil {
+		return err
+	}
+
+	// Add the transaction set to the pool.
+	tp.transactionSetMapping[setID] = len(tp.transactionSets)
+	tp.transactionSets = append(tp.transactionSets, poolTransactionSet{
+		ID:           setID,
+		Transactions: ts,
+	})
+	tp.log.Println(fmt.Sprintf("Accepted transaction set %v in pool", crypto.Hash(setID).String()))
+	// remember when the transaction was added
+	tp.broadcastCache.add(setID, tp.consensusSet.Height())
+	tp.transactionSetDiffs[setID] = cc
+	tsBytes, err := siabin.Marshal(ts)
+	if err != nil {
+		return fmt.Errorf("failed to (siabin) marshal transaction set: %v", err)
+	}
+	tp.transactionListSize += len(tsBytes)
+	return nil
+}
+
+// validateTransactionSet verifies that a transaction set is allowed to be in the transaction pool,
+// returning the unconfirmed transactions of the set, its ID and the consensus change it would cause.
+func (tp *TransactionPool) validateTransactionSet(ts []types.Transaction) ([]types.Transaction, TransactionSetID, modules.ConsensusChange, error) {
 	if len(ts) == 0 {
 		tp.log.Debug("Attempted to accept empty transaction set")
-		return errEmptySet
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, errEmptySet
 	}
 
 	// Remove all transactions that have been confirmed in the transaction set.
@@ -83,17 +109,17 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, err
 	}
 	// If no transactions remain, return a duplicate error.
 	if len(ts) == 0 {
 		tp.log.Debug("Transaction set could not be accepted: all transactions in set are duplicates")
-		return modules.ErrDuplicateTransactionSet
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, modules.ErrDuplicateTransactionSet
 	}
 
 	tsh, err := crypto.HashObject(ts)
 	if err != nil {
-		return err
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, err
 	}
 	setID := TransactionSetID(tsh)
 
@@ -102,7 +128,7 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 	err = tp.validateTransactionSetComposition(ts)
 	if err != nil {
 		tp.log.Debug(fmt.Sprintf("Transaction set %v composition invalid: %v", crypto.Hash(setID).String(), err))
-		return err
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, err
 	}
 
 	// Validate the new set in context of all other sets
@@ -113,25 +139,19 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 	cc, err := tp.consensusSet.TryTransactionSet(txns)
 	if err != nil {
 		tp.log.Debug(fmt.Sprintf("Transaction set %v has conflict with current consensus", crypto.Hash(setID).String()))
-		return err
+		tp.detectDoubleSpends(ts)
+		return nil, TransactionSetID{}, modules.ConsensusChange{}, err
 	}
+	return ts, setID, cc, nil
+}
 
-	// Add the transaction set to the pool.
-	tp.transactionSetMapping[setID] = len(tp.transactionSets)
-	tp.transactionSets = append(tp.transactionSets, poolTransactionSet{
-		ID:           setID,
-		Transactions: ts,
-	})
-	tp.log.Println(fmt.Sprintf("Accepted transaction set %v in pool", crypto.Hash(setID).String()))
-	// remember when the transaction was added
-	tp.broadcastCache.add(setID, tp.consensusSet.Height())
-	tp.transactionSetDiffs[setID] = cc
-	tsBytes, err := siabin.Marshal(ts)
-	if err != nil {
-		return fmt.Errorf("failed to (siabin) marshal transaction set: %v", err)
-	}
-	tp.transactionListSize += len(tsBytes)
-	return nil
+// ValidateTransactionSet validates a transaction set as if it were accepted by
+// AcceptTransactionSet, without adding it to the pool or relaying it to peers.
+func (tp *TransactionPool) ValidateTransactionSet(ts []types.Transaction) error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	_, _, _, err := tp.validateTransactionSet(ts)
+	return err
 }
 
 // AcceptTransactionSet adds a transaction to the unconfirmed set of
@@ -0,0 +1,87 @@
+package transactionpool
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// maxDoubleSpendProofs is the maximum amount of double spend proofs
+	// kept in memory by the transaction pool, proofs detected
+	// once this limit is reached are no longer kept.
+	maxDoubleSpendProofs = 1000
+)
+
+// detectDoubleSpends looks for transactions in the given (rejected) transaction set,
+// which spend the same parent output as a transaction already in the pool,
+// storing a (verified) double spend proof for each one that is found.
+func (tp *TransactionPool) detectDoubleSpends(ts []types.Transaction) {
+	for _, poolTxn := range tp.transactionList() {
+		for _, txn := range ts {
+			parentID, ok := types.FindDoubleSpend(poolTxn, txn)
+			if !ok {
+				continue
+			}
+			if _, exists := tp.doubleSpendProofs[parentID]; exists {
+				continue
+			}
+			if len(tp.doubleSpendProofs) >= maxDoubleSpendProofs {
+				return
+			}
+			condition, err := tp.parentCondition(parentID)
+			if err != nil {
+				tp.log.Debug(fmt.Sprintf("Unable to look up parent output %v of double spend: %v", parentID, err))
+				continue
+			}
+			proof, err := types.NewDoubleSpendProof(poolTxn, txn, condition, tp.chainCts.ChainID())
+			if err != nil {
+				tp.log.Debug(fmt.Sprintf("Rejected double spend of parent output %v cannot be proven: %v", parentID, err))
+				continue
+			}
+			tp.log.Println(fmt.Sprintf("Detected double spend of parent output %v by transaction %v", parentID, txn.ID()))
+			tp.doubleSpendProofs[parentID] = proof
+		}
+	}
+}
+
+// parentCondition returns the condition of the (coin or block stake) output with the given ID.
+func (tp *TransactionPool) parentCondition(id types.OutputID) (types.UnlockConditionProxy, error) {
+	co, err := tp.consensusSet.GetCoinOutput(types.CoinOutputID(id))
+	if err == nil {
+		return co.Condition, nil
+	}
+	bso, err := tp.consensusSet.GetBlockStakeOutput(types.BlockStakeOutputID(id))
+	if err != nil {
+		return types.UnlockConditionProxy{}, err
+	}
+	return bso.Condition, nil
+}
+
+// DoubleSpendProofs implements TransactionPool.DoubleSpendProofs
+func (tp *TransactionPool) DoubleSpendProofs() []types.DoubleSpendProof {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	proofs := make([]types.DoubleSpendProof, 0, len(tp.doubleSpendProofs))
+	for _, proof := range tp.doubleSpendProofs {
+		proofs = append(proofs, proof)
+	}
+	sort.Slice(proofs, func(i, j int) bool {
+		return bytes.Compare(proofs[i].ParentID[:], proofs[j].ParentID[:]) < 0
+	})
+	return proofs
+}
+
+// DoubleSpendProof implements TransactionPool.DoubleSpendProof
+func (tp *TransactionPool) DoubleSpendProof(id types.OutputID) (types.DoubleSpendProof, error) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	proof, ok := tp.doubleSpendProofs[id]
+	if !ok {
+		return types.DoubleSpendProof{}, modules.ErrDoubleSpendProofNotFound
+	}
+	return proof, nil
+}
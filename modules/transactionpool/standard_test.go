@@ -22,8 +22,7 @@ func TestIntegrationLargeTransactions(t *testing.T) {
 	defer tpt.Close()
 
 	// Create a large transaction and try to get it accepted.
-	arbData := make([]byte, modules.TransactionSizeLimit)
-	copy(arbData, modules.PrefixNonSia[:])
+	arbData := make([]byte, tpt.tpool.chainCts.TransactionPool.TransactionSizeLimit)
 	_, err = rand.Read(arbData[100:116]) // prevents collisions with other transacitons in the loop.
 	if err != nil {
 		t.Fatal(err)
@@ -39,9 +38,8 @@ func TestIntegrationLargeTransactions(t *testing.T) {
 
 	// Create a large transaction set and try to get it accepted.
 	var tset []types.Transaction
-	for i := 0; i <= modules.TransactionSetSizeLimit/10e3; i++ {
+	for i := 0; i <= tpt.tpool.chainCts.TransactionPool.TransactionSetSizeLimit/10e3; i++ {
 		arbData := make([]byte, 10e3)
-		copy(arbData, modules.PrefixNonSia[:])
 		_, err = rand.Read(arbData[100:116]) // prevents collisions with other transacitons in the loop.
 		if err != nil {
 			t.Fatal(err)
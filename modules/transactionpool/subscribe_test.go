@@ -50,7 +50,7 @@ func TestSubscription(t *testing.T) {
 
 	// Create a valid transaction set and check that the mock subscriber's
 	// transaction list is updated.
-	_, err = tpt.wallet.SendCoins(types.NewCurrency64(100), types.UnlockHash{}, nil)
+	_, err = tpt.wallet.SendCoins(types.NewCurrency64(100), types.NewCondition(nil), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -59,7 +59,7 @@ func TestSubscription(t *testing.T) {
 	}
 	numTxns := 0
 	for _, txnSet := range tpt.tpool.transactionSets {
-		numTxns += len(txnSet)
+		numTxns += len(txnSet.Transactions)
 	}
 	if len(ms.txns) != numTxns {
 		t.Errorf("mock subscriber should've received %v transactions; received %v instead", numTxns, len(ms.txns))
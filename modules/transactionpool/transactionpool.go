@@ -65,6 +65,10 @@ type (
 		// broadcastCache keeps track of all transaction sets currently in the pool.
 		broadcastCache transactionCache
 
+		// doubleSpendProofs are the proofs of all double spends detected
+		// by the transaction pool, indexed by the ID of the double spent output.
+		doubleSpendProofs map[types.OutputID]types.DoubleSpendProof
+
 		// Utilities.
 		db         *persist.BoltDatabase
 		mu         demotemutex.DemoteMutex
@@ -98,6 +102,8 @@ func New(cs modules.ConsensusSet, g modules.Gateway, persistDir string, bcInfo t
 
 		broadcastCache: newTransactionCache(),
 
+		doubleSpendProofs: make(map[types.OutputID]types.DoubleSpendProof),
+
 		persistDir: persistDir,
 
 		bcInfo:   bcInfo,
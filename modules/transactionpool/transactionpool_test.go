@@ -11,6 +11,7 @@ import (
 	"github.com/threefoldtech/rivine/modules/consensus"
 	"github.com/threefoldtech/rivine/modules/gateway"
 	"github.com/threefoldtech/rivine/modules/wallet"
+	"github.com/threefoldtech/rivine/types"
 )
 
 // A tpoolTester is used during testing to initialize a transaction pool and
@@ -23,27 +24,58 @@ type tpoolTester struct {
 	wallet    modules.Wallet
 	walletKey crypto.TwofishKey
 
+	// the first key of the wallet seed,
+	// owning all coin outputs of the genesis block
+	secretKey  crypto.SecretKey
+	publicKey  crypto.PublicKey
+	unlockHash types.UnlockHash
+
 	persistDir string
 }
 
 // createTpoolTester returns a ready-to-use tpool tester, with all modules
-// initialized.
+// initialized, and all genesis coin outputs owned by the wallet.
 func createTpoolTester(name string) (*tpoolTester, error) {
+	bcInfo := types.DefaultBlockchainInfo()
+	chainCts := types.TestnetChainConstants()
+
+	// Distribute the genesis coins to the first key of the wallet seed.
+	var seed modules.Seed
+	_, err := rand.Read(seed[:])
+	if err != nil {
+		return nil, err
+	}
+	sk, pk, err := seed.SpendableKey(0)
+	if err != nil {
+		return nil, err
+	}
+	uh, err := types.NewPubKeyUnlockHash(types.Ed25519PublicKey(pk))
+	if err != nil {
+		return nil, err
+	}
+	chainCts.GenesisCoinDistribution = nil
+	for i := 0; i < 10; i++ {
+		chainCts.GenesisCoinDistribution = append(chainCts.GenesisCoinDistribution, types.CoinOutput{
+			Value:     chainCts.CurrencyUnits.OneCoin.Mul64(100),
+			Condition: types.NewCondition(types.NewUnlockHashCondition(uh)),
+		})
+	}
+
 	// Initialize the modules.
 	testdir := build.TempDir(modules.TransactionPoolDir, name)
-	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	g, err := gateway.New("localhost:0", false, 1, filepath.Join(testdir, modules.GatewayDir), bcInfo, chainCts, nil, false)
 	if err != nil {
 		return nil, err
 	}
-	cs, err := consensus.New(g, false, filepath.Join(testdir, modules.ConsensusDir))
+	cs, err := consensus.New(g, false, filepath.Join(testdir, modules.ConsensusDir), bcInfo, chainCts, false, "")
 	if err != nil {
 		return nil, err
 	}
-	tp, err := New(cs, g, filepath.Join(testdir, modules.TransactionPoolDir))
+	tp, err := New(cs, g, filepath.Join(testdir, modules.TransactionPoolDir), bcInfo, chainCts, false)
 	if err != nil {
 		return nil, err
 	}
-	w, err := wallet.New(cs, tp, filepath.Join(testdir, modules.WalletDir))
+	w, err := wallet.New(cs, tp, filepath.Join(testdir, modules.WalletDir), bcInfo, chainCts, false)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +84,7 @@ func createTpoolTester(name string) (*tpoolTester, error) {
 	if err != nil {
 		return nil, err
 	}
-	_, err = w.Encrypt(key)
+	_, err = w.Encrypt(key, seed)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +106,10 @@ func createTpoolTester(name string) (*tpoolTester, error) {
 		wallet:    w,
 		walletKey: key,
 
+		secretKey:  sk,
+		publicKey:  pk,
+		unlockHash: uh,
+
 		persistDir: testdir,
 	}
 
@@ -106,34 +142,67 @@ func (tpt *tpoolTester) Close() error {
 	return nil
 }
 
+// genesisCoinOutputID returns the ID of the genesis coin output at the given index.
+func (tpt *tpoolTester) genesisCoinOutputID(index uint64) types.CoinOutputID {
+	return tpt.tpool.chainCts.GenesisBlock().Transactions[0].CoinOutputID(index)
+}
+
+// spendCoinOutput returns a signed transaction spending the given coin output,
+// of the given value and owned by the tester, to the given unlock hash,
+// paying the minimum transaction fee.
+func (tpt *tpoolTester) spendCoinOutput(parentID types.CoinOutputID, value types.Currency, uh types.UnlockHash) (types.Transaction, error) {
+	fee := tpt.tpool.chainCts.MinimumTransactionFee
+	txn := types.Transaction{
+		Version: tpt.tpool.chainCts.DefaultTransactionVersion,
+		CoinInputs: []types.CoinInput{{
+			ParentID:    parentID,
+			Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(tpt.publicKey))),
+		}},
+		CoinOutputs: []types.CoinOutput{{
+			Value:     value.Sub(fee),
+			Condition: types.NewCondition(types.NewUnlockHashCondition(uh)),
+		}},
+		MinerFees: []types.Currency{fee},
+	}
+	err := txn.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+		ChainID:      tpt.tpool.chainCts.ChainID(),
+		Key:          tpt.secretKey,
+	})
+	return txn, err
+}
+
 // TestIntegrationNewNilInputs tries to trigger a panic with nil inputs.
 func TestIntegrationNewNilInputs(t *testing.T) {
+	bcInfo := types.DefaultBlockchainInfo()
+	chainCts := types.TestnetChainConstants()
 	// Create a gateway and consensus set.
 	testdir := build.TempDir(modules.TransactionPoolDir, t.Name())
-	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	g, err := gateway.New("localhost:0", false, 1, filepath.Join(testdir, modules.GatewayDir), bcInfo, chainCts, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
-	cs, err := consensus.New(g, false, filepath.Join(testdir, modules.ConsensusDir))
+	cs, err := consensus.New(g, false, filepath.Join(testdir, modules.ConsensusDir), bcInfo, chainCts, false, "")
 	if err != nil {
 		t.Fatal(err)
 	}
 	tpDir := filepath.Join(testdir, modules.TransactionPoolDir)
 
 	// Try all combinations of nil inputs.
-	_, err = New(nil, nil, tpDir)
+	_, err = New(nil, nil, tpDir, bcInfo, chainCts, false)
 	if err == nil {
 		t.Error(err)
 	}
-	_, err = New(nil, g, tpDir)
+	_, err = New(nil, g, tpDir, bcInfo, chainCts, false)
 	if err != errNilCS {
 		t.Error(err)
 	}
-	_, err = New(cs, nil, tpDir)
+	_, err = New(cs, nil, tpDir, bcInfo, chainCts, false)
 	if err != errNilGateway {
 		t.Error(err)
 	}
-	_, err = New(cs, g, tpDir)
+	_, err = New(cs, g, tpDir, bcInfo, chainCts, false)
 	if err != nil {
 		t.Error(err)
 	}
@@ -152,7 +152,8 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 			tp.log.Println(fmt.Sprintf("Rebroadcasting transaction %v to peers", crypto.Hash(id).String()))
 			tSet, ok := tp.transactionSetByID(id)
 			if !ok {
-				tp.log.Println(fmt.Sprintf("failed to find transaction set for", crypto.Hash(id).String()))
+				tp.log.Println(fmt.Sprintf("failed to find transaction set for %v", crypto.Hash(id).String()))
+				continue
 			}
 			go tp.gateway.Broadcast("RelayTransactionSet", tSet.Transactions, tp.gateway.Peers())
 		}
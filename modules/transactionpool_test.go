@@ -0,0 +1,53 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// TestNewTransactionPackages tests that dependent transactions
+// end up in the same package, and that packages are sorted by fee rate.
+func TestNewTransactionPackages(t *testing.T) {
+	parent := types.Transaction{
+		Version:     types.TransactionVersionOne,
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(100)}},
+		MinerFees:   []types.Currency{types.NewCurrency64(1)},
+	}
+	child := types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinInputs: []types.CoinInput{
+			{ParentID: parent.CoinOutputID(0)},
+		},
+		MinerFees: []types.Currency{types.NewCurrency64(1000)},
+	}
+	independent := types.Transaction{
+		Version:   types.TransactionVersionOne,
+		MinerFees: []types.Currency{types.NewCurrency64(10)},
+	}
+
+	packages, err := NewTransactionPackages([]types.Transaction{parent, independent, child})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 {
+		t.Fatal("expected 2 packages, received:", len(packages))
+	}
+	// the parent-child package pays the highest fee rate
+	pkg := packages[0]
+	if len(pkg.Transactions) != 2 {
+		t.Fatal("expected the first package to contain 2 transactions, received:", len(pkg.Transactions))
+	}
+	if pkg.Transactions[0].ID() != parent.ID() || pkg.Transactions[1].ID() != child.ID() {
+		t.Fatal("expected the parent to precede the child within the package")
+	}
+	if !pkg.Fees.Equals64(1001) {
+		t.Fatal("unexpected package fees:", pkg.Fees)
+	}
+	if pkg.Size == 0 {
+		t.Fatal("package size should be computed")
+	}
+	if len(packages[1].Transactions) != 1 || packages[1].Transactions[0].ID() != independent.ID() {
+		t.Fatal("expected the second package to contain the independent transaction")
+	}
+}
@@ -2,7 +2,10 @@ package modules
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/crypto"
@@ -27,6 +30,19 @@ const (
 	WalletSeedPreloadDepth = 25
 )
 
+// The available intervals to aggregate an earnings report with.
+const (
+	EarningsIntervalDay EarningsInterval = iota
+	EarningsIntervalWeek
+	EarningsIntervalMonth
+)
+
+// The different roles a wallet can have within a payment channel.
+const (
+	PaymentChannelRoleSender PaymentChannelRole = iota
+	PaymentChannelRoleReceiver
+)
+
 var (
 	// ErrBadEncryptionKey is returned if the incorrect encryption key to a
 	// file is provided.
@@ -44,11 +60,15 @@ var (
 
 	// ErrLockedWallet is returned when an action cannot be performed due to
 	// the wallet being locked.
-	ErrLockedWallet = errors.New("wallet must be unlocked before it can be used")
+	ErrLockedWallet = types.NewError(types.ErrorCodeWalletLocked, "wallet must be unlocked before it can be used")
 
 	// ErrEncryptedWallet is returned in case the wallet is encrypted, preventing it from being
 	// used for plain purposes.
 	ErrEncryptedWallet = errors.New("wallet is encrypted and cannot use plain functionality")
+
+	// ErrUnknownPaymentChannel is returned in case a payment channel
+	// is not known by the wallet.
+	ErrUnknownPaymentChannel = errors.New("unknown payment channel")
 )
 
 type (
@@ -88,6 +108,8 @@ type (
 		WalletAddress  bool             `json:"walletaddress"`
 		RelatedAddress types.UnlockHash `json:"relatedaddress"`
 		Value          types.Currency   `json:"value"`
+		// Metadata is the (optional) metadata of the output
+		Metadata []byte `json:"metadata,omitempty"`
 	}
 
 	// A ProcessedTransaction is a transaction that has been processed into
@@ -107,6 +129,53 @@ type (
 		Outputs []ProcessedOutput `json:"outputs"`
 	}
 
+	// EarningsInterval defines the interval used to aggregate
+	// the earnings of a block creator in an earnings report.
+	EarningsInterval uint8
+
+	// PaymentChannelRole defines the role a wallet has within a payment channel.
+	PaymentChannelRole uint8
+
+	// PaymentChannel is a unidirectional payment channel, known by the wallet,
+	// either as the sender or as the receiver of that channel.
+	//
+	// The channel is funded by a single coin output, locked by a PaymentChannelCondition.
+	// Each update of the channel is a commitment: a settlement transaction spending that output,
+	// paying the (increased) total amount to the receiver and the remainder back to the sender,
+	// which is signed by the sender only. The receiver closes the channel by signing
+	// and submitting the latest commitment, while the sender can only close the channel
+	// once its lock time is reached, by refunding the funding output.
+	// No revocation is required, as each commitment pays the receiver more than the previous one,
+	// and only the receiver can complete a commitment.
+	PaymentChannel struct {
+		// ID of the channel, equal to the ID of the funding coin output.
+		ID types.CoinOutputID `json:"id"`
+		// Role of this wallet within the channel.
+		Role PaymentChannelRole `json:"role"`
+		// Condition of the funding coin output.
+		Condition types.PaymentChannelCondition `json:"condition"`
+		// Capacity is the value of the funding coin output.
+		Capacity types.Currency `json:"capacity"`
+		// Paid is the total amount paid to the receiver, as defined by the latest commitment.
+		Paid types.Currency `json:"paid"`
+		// Commitment is the latest commitment, signed by the sender only, nil if no payment was made yet.
+		Commitment *types.Transaction `json:"commitment,omitempty"`
+	}
+
+	// EarningsReportEntry contains the earnings of a block creator for a single period.
+	EarningsReportEntry struct {
+		// PeriodStart is the (UTC) start of the period.
+		PeriodStart types.Timestamp `json:"periodstart"`
+		// BlocksCreated is the amount of blocks created for which this wallet received a payout.
+		BlocksCreated uint64 `json:"blockscreated"`
+		// BlockCreatorFees is the sum of all block creator fees paid to this wallet.
+		BlockCreatorFees types.Currency `json:"blockcreatorfees"`
+		// TransactionFees is the sum of all collected transaction fees paid to this wallet.
+		TransactionFees types.Currency `json:"transactionfees"`
+		// Total is the sum of the block creator and transaction fees.
+		Total types.Currency `json:"total"`
+	}
+
 	// MultiSigWallet is a collection of coin and blockstake outputs, which have the same
 	// unlockhash.
 	MultiSigWallet struct {
@@ -221,6 +290,11 @@ type (
 		// SignAllPossible tries to sign as much of the inputs —and extension if required—
 		// in the tranaction using the keys loaded in the wallet
 		SignAllPossible() error
+
+		// Canonicalize sorts the inputs and outputs of the transaction into their
+		// canonical order (see types.Transaction.Canonicalize), such that multiple parties
+		// agree on the exact bytes being signed. It has to be called prior to signing.
+		Canonicalize() error
 	}
 
 	// EncryptionManager can encrypt, lock, unlock, and indicate the current
@@ -405,11 +479,24 @@ type (
 		// The transaction is automatically given to the transaction pool, and is also returned to the caller.
 		SendOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, refundAddress *types.UnlockHash, reuseRefundAddress bool) (types.Transaction, error)
 
+		// DryRunOutputs creates and validates the transaction SendOutputs would create,
+		// without submitting it to the transaction pool.
+		DryRunOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, refundAddress *types.UnlockHash, reuseRefundAddress bool) (types.Transaction, error)
+
+		// EstimateTransactionFee estimates the (encoded) size the given transaction will have once signed,
+		// as well as the fee to be paid for a transaction of that size.
+		EstimateTransactionFee(txn types.Transaction) (size int, fee types.Currency, err error)
+
 		// BlockStakeStats returns the blockstake statistical information of
 		// this wallet of the last 1000 blocks. If the blockcount is less than
 		// 1000 blocks, BlockCount will be the number available.
 		BlockStakeStats() (BCcountLast1000 uint64, BCfeeLast1000 types.Currency, BlockCount uint64, err error)
 
+		// EarningsReport aggregates the block creator fees and collected transaction fees
+		// paid to this wallet, per period of the given interval, ordered from oldest to newest period.
+		// Periods in which nothing was earned are not included.
+		EarningsReport(interval EarningsInterval) ([]EarningsReportEntry, error)
+
 		// UnlockedUnspendOutputs returns all unlocked and unspend coin and blockstake outputs
 		// owned by this wallet
 		UnlockedUnspendOutputs() (map[types.CoinOutputID]types.CoinOutput, map[types.BlockStakeOutputID]types.BlockStakeOutput, error)
@@ -426,9 +513,115 @@ type (
 		// GreedySign attempts to sign every input which can be signed by the keys loaded
 		// in this wallet.
 		GreedySign(types.Transaction) (types.Transaction, error)
+
+		// PaymentChannels returns all payment channels known by this wallet,
+		// either as sender or as receiver.
+		PaymentChannels() ([]PaymentChannel, error)
+
+		// OpenPaymentChannel opens a payment channel as the sender,
+		// funding it with the given capacity, and locking it until the given lock time.
+		// The funding transaction is automatically given to the transaction pool.
+		OpenPaymentChannel(receiver types.UnlockHash, capacity types.Currency, lockTime uint64) (PaymentChannel, error)
+
+		// UpdatePaymentChannel creates a new commitment as the sender of the payment channel,
+		// paying the given total amount to the receiver. The returned commitment
+		// is to be given to the receiver, which accepts it using AcceptPaymentChannelCommitment.
+		UpdatePaymentChannel(id types.CoinOutputID, amount types.Currency) (types.Transaction, error)
+
+		// AcceptPaymentChannelCommitment validates and stores a commitment as the receiver of a payment channel,
+		// registering the payment channel if it wasn't known yet by this wallet.
+		AcceptPaymentChannelCommitment(commitment types.Transaction) (PaymentChannel, error)
+
+		// ClosePaymentChannel closes the payment channel, settling the latest commitment as the receiver,
+		// or refunding the funding output as the sender, once its lock time is reached.
+		// The transaction is automatically given to the transaction pool.
+		ClosePaymentChannel(id types.CoinOutputID) (types.Transaction, error)
 	}
 )
 
+// String returns the EarningsInterval as a string.
+func (ei EarningsInterval) String() string {
+	switch ei {
+	case EarningsIntervalDay:
+		return "day"
+	case EarningsIntervalWeek:
+		return "week"
+	case EarningsIntervalMonth:
+		return "month"
+	default:
+		return ""
+	}
+}
+
+// LoadString loads the EarningsInterval from a string.
+func (ei *EarningsInterval) LoadString(str string) error {
+	switch str {
+	case "day":
+		*ei = EarningsIntervalDay
+	case "week":
+		*ei = EarningsIntervalWeek
+	case "month":
+		*ei = EarningsIntervalMonth
+	default:
+		return fmt.Errorf("invalid earnings interval %q, expected one of: day, week, month", str)
+	}
+	return nil
+}
+
+// PeriodStart returns the (UTC) start of the period the given timestamp belongs to.
+// Weeks start on Monday.
+func (ei EarningsInterval) PeriodStart(ts types.Timestamp) types.Timestamp {
+	t := time.Unix(int64(ts), 0).UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch ei {
+	case EarningsIntervalWeek:
+		// time.Sunday == 0, shift it such that Monday becomes the first day of the week
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case EarningsIntervalMonth:
+		day = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return types.Timestamp(day.Unix())
+}
+
+// String returns the PaymentChannelRole as a string.
+func (role PaymentChannelRole) String() string {
+	switch role {
+	case PaymentChannelRoleSender:
+		return "sender"
+	case PaymentChannelRoleReceiver:
+		return "receiver"
+	default:
+		return ""
+	}
+}
+
+// LoadString loads the PaymentChannelRole from a string.
+func (role *PaymentChannelRole) LoadString(str string) error {
+	switch str {
+	case "sender":
+		*role = PaymentChannelRoleSender
+	case "receiver":
+		*role = PaymentChannelRoleReceiver
+	default:
+		return fmt.Errorf("invalid payment channel role %q, expected one of: sender, receiver", str)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (role PaymentChannelRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(role.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (role *PaymentChannelRole) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	return role.LoadString(str)
+}
+
 // CalculateWalletTransactionID is a helper function for determining the id of
 // a wallet transaction.
 func CalculateWalletTransactionID(tid types.TransactionID, oid types.OutputID) WalletTransactionID {
@@ -455,6 +648,18 @@ func InitialSeedFromMnemonic(mnemonic string) (out Seed, err error) {
 	return
 }
 
+// SpendableKey deterministically derives the key pair at the given index of the seed,
+// the same way the wallet derives its spendable keys,
+// such that those keys can also be derived without a wallet (e.g. on an offline machine).
+func (s Seed) SpendableKey(index uint64) (crypto.SecretKey, crypto.PublicKey, error) {
+	h, err := crypto.HashAll(s, index)
+	if err != nil {
+		return crypto.SecretKey{}, crypto.PublicKey{}, err
+	}
+	sk, pk := crypto.GenerateKeyPairDeterministic(h)
+	return sk, pk, nil
+}
+
 // String returns this seed as a hex-encoded string.
 func (s Seed) String() string {
 	return hex.EncodeToString(s[:])
@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// EarningsReport implements modules.Wallet.EarningsReport
+func (w *Wallet) EarningsReport(interval modules.EarningsInterval) ([]modules.EarningsReportEntry, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+
+	var entries []modules.EarningsReportEntry
+	for _, pt := range w.processedTransactions {
+		// only the (pseudo) miner payout transactions are of interest,
+		// for which the transaction ID equals the block ID
+		if len(pt.Outputs) == 0 || pt.Outputs[0].FundType != types.SpecifierMinerPayout {
+			continue
+		}
+		block, ok := w.blockOfMinerPayoutTransaction(pt)
+		if !ok {
+			w.log.Debugf("failed to find block %s of miner payout transaction", pt.TransactionID.String())
+			continue
+		}
+
+		var creatorFee, txFee types.Currency
+		for idx, output := range w.classifyMinerPayouts(block) {
+			if !pt.Outputs[idx].WalletAddress {
+				continue
+			}
+			switch output {
+			case minerPayoutBlockCreatorFee:
+				creatorFee = creatorFee.Add(pt.Outputs[idx].Value)
+			case minerPayoutTransactionFee:
+				txFee = txFee.Add(pt.Outputs[idx].Value)
+			}
+		}
+		if creatorFee.IsZero() && txFee.IsZero() {
+			continue // not created by this wallet
+		}
+
+		periodStart := interval.PeriodStart(pt.ConfirmationTimestamp)
+		if len(entries) == 0 || entries[len(entries)-1].PeriodStart != periodStart {
+			entries = append(entries, modules.EarningsReportEntry{PeriodStart: periodStart})
+		}
+		entry := &entries[len(entries)-1]
+		entry.BlocksCreated++
+		entry.BlockCreatorFees = entry.BlockCreatorFees.Add(creatorFee)
+		entry.TransactionFees = entry.TransactionFees.Add(txFee)
+		entry.Total = entry.BlockCreatorFees.Add(entry.TransactionFees)
+	}
+	return entries, nil
+}
+
+type minerPayoutType uint8
+
+const (
+	minerPayoutOther minerPayoutType = iota
+	minerPayoutBlockCreatorFee
+	minerPayoutTransactionFee
+)
+
+// classifyMinerPayouts returns for each miner payout of the block what it pays for,
+// following the order in which the block creator adds them to a block.
+func (w *Wallet) classifyMinerPayouts(block types.Block) []minerPayoutType {
+	payouts := make([]minerPayoutType, len(block.MinerPayouts))
+	idx := 0
+	if !w.chainCts.BlockCreatorFee.IsZero() && idx < len(payouts) {
+		payouts[idx] = minerPayoutBlockCreatorFee
+		idx++
+	}
+	if !block.CalculateTotalMinerFees().IsZero() && idx < len(payouts) {
+		payouts[idx] = minerPayoutTransactionFee
+	}
+	return payouts
+}
+
+// blockOfMinerPayoutTransaction returns the block the given miner payout transaction was created for.
+func (w *Wallet) blockOfMinerPayoutTransaction(pt modules.ProcessedTransaction) (types.Block, bool) {
+	// the confirmation height of processed transactions is one higher than the block height
+	for _, height := range []types.BlockHeight{pt.ConfirmationHeight - 1, pt.ConfirmationHeight} {
+		block, ok := w.cs.BlockAtHeight(height)
+		if ok && types.TransactionID(block.ID()) == pt.TransactionID {
+			return block, true
+		}
+	}
+	return types.Block{}, false
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestEarningsReport(t *testing.T) {
+	cs := newConsensusSetStub()
+	w := &Wallet{
+		cs:                           cs,
+		chainCts:                     types.TestnetChainConstants(),
+		unlocked:                     true,
+		keys:                         make(map[types.UnlockHash]spendableKey),
+		historicOutputs:              make(map[types.OutputID]historicOutput),
+		processedTransactionMap:      make(map[types.TransactionID]*modules.ProcessedTransaction),
+		multiSigCoinOutputs:          make(map[types.CoinOutputID]types.CoinOutput),
+		multiSigBlockStakeOutputs:    make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+		blockstakeOutputs:            make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+		coldStakingBlockStakeOutputs: make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+		unspentblockstakeoutputs:     make(map[types.BlockStakeOutputID]types.UnspentBlockStakeOutput),
+	}
+	w.chainCts.BlockCreatorFee = types.NewCurrency64(10)
+	ours := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{1})
+	other := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{2})
+	w.keys[ours] = spendableKey{}
+
+	// Sunday 5 and Monday 6 January 2020
+	sunday := time.Date(2020, time.January, 5, 0, 0, 0, 0, time.UTC)
+	monday := sunday.AddDate(0, 0, 1)
+	payout := func(value uint64, uh types.UnlockHash) types.MinerPayout {
+		return types.MinerPayout{Value: types.NewCurrency64(value), UnlockHash: uh}
+	}
+	feeTransaction := func(fee uint64) []types.Transaction {
+		return []types.Transaction{{
+			Version:   w.chainCts.DefaultTransactionVersion,
+			MinerFees: []types.Currency{types.NewCurrency64(fee)},
+		}}
+	}
+	for _, block := range []types.Block{
+		// created by this wallet, without fee payout
+		{Timestamp: types.Timestamp(sunday.Add(10 * time.Hour).Unix()), MinerPayouts: []types.MinerPayout{payout(10, ours)}},
+		// created by this wallet, with fee payout, in the last second of Sunday
+		{Timestamp: types.Timestamp(monday.Unix() - 1), MinerPayouts: []types.MinerPayout{payout(10, ours), payout(3, ours)},
+			Transactions: feeTransaction(3)},
+		// created by another wallet, with fee payout, at the first second of Monday
+		{Timestamp: types.Timestamp(monday.Unix()), MinerPayouts: []types.MinerPayout{payout(10, other), payout(2, other)},
+			Transactions: feeTransaction(2)},
+		// created by another wallet, paying an unrelated miner payout to this wallet
+		{Timestamp: types.Timestamp(monday.Add(time.Hour).Unix()), MinerPayouts: []types.MinerPayout{payout(10, other), payout(5, ours)}},
+		// created by this wallet, with fee payout, on Monday
+		{Timestamp: types.Timestamp(monday.Add(12 * time.Hour).Unix()), MinerPayouts: []types.MinerPayout{payout(10, ours), payout(4, ours)},
+			Transactions: feeTransaction(4)},
+	} {
+		block.ParentID = cs.blocks[len(cs.blocks)-1].ID()
+		cs.blocks = append(cs.blocks, block)
+		w.applyHistory(modules.ConsensusChange{AppliedBlocks: []types.Block{block}})
+	}
+
+	entry := func(periodStart time.Time, blocks, creatorFees, txFees uint64) modules.EarningsReportEntry {
+		return modules.EarningsReportEntry{
+			PeriodStart:      types.Timestamp(periodStart.Unix()),
+			BlocksCreated:    blocks,
+			BlockCreatorFees: types.NewCurrency64(creatorFees),
+			TransactionFees:  types.NewCurrency64(txFees),
+			Total:            types.NewCurrency64(creatorFees + txFees),
+		}
+	}
+	testCases := []struct {
+		interval modules.EarningsInterval
+		entries  []modules.EarningsReportEntry
+	}{
+		{modules.EarningsIntervalDay, []modules.EarningsReportEntry{
+			entry(sunday, 2, 20, 3),
+			entry(monday, 1, 10, 4),
+		}},
+		{modules.EarningsIntervalWeek, []modules.EarningsReportEntry{
+			entry(sunday.AddDate(0, 0, -6), 2, 20, 3),
+			entry(monday, 1, 10, 4),
+		}},
+		{modules.EarningsIntervalMonth, []modules.EarningsReportEntry{
+			entry(sunday.AddDate(0, 0, -4), 3, 30, 7),
+		}},
+	}
+	for _, testCase := range testCases {
+		entries, err := w.EarningsReport(testCase.interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(testCase.entries) {
+			t.Errorf("%s: expected %d entries, not %d: %v", testCase.interval, len(testCase.entries), len(entries), entries)
+			continue
+		}
+		for i, entry := range entries {
+			expected := testCase.entries[i]
+			if entry.PeriodStart != expected.PeriodStart || entry.BlocksCreated != expected.BlocksCreated ||
+				!entry.BlockCreatorFees.Equals(expected.BlockCreatorFees) || !entry.TransactionFees.Equals(expected.TransactionFees) ||
+				!entry.Total.Equals(expected.Total) {
+				t.Errorf("%s: unexpected entry #%d: %v != %v", testCase.interval, i, entry, expected)
+			}
+		}
+	}
+
+	w.unlocked = false
+	if _, err := w.EarningsReport(modules.EarningsIntervalDay); err != modules.ErrLockedWallet {
+		t.Errorf("expected %v, not %v", modules.ErrLockedWallet, err)
+	}
+}
@@ -15,6 +15,10 @@ var (
 	ErrNilOutputs = errors.New("nil outputs cannot be send")
 )
 
+// maxFeeEstimationAttempts is the amount of times a transaction is funded again,
+// in case the fee estimated for it exceeds the fee it was funded for.
+const maxFeeEstimationAttempts = 3
+
 // sortedOutputs is a struct containing a slice of siacoin outputs and their
 // corresponding ids. sortedOutputs can be sorted using the sort package.
 type sortedOutputs struct {
@@ -258,17 +262,94 @@ func (w *Wallet) SendBlockStakes(amount types.Currency, cond types.UnlockConditi
 // SendOutputs is a tool for sending coins and block stakes from the wallet, to one or multiple addreses.
 // The transaction is automatically given to the transaction pool, and is also returned to the caller.
 func (w *Wallet) SendOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, refundAddress *types.UnlockHash, reuseRefundAddress bool) (types.Transaction, error) {
-	if len(coinOutputs) == 0 && len(blockstakeOutputs) == 0 {
-		// at least one coin output OR one block stake output has to be send
-		return types.Transaction{}, ErrNilOutputs
+	if err := w.tg.Add(); err != nil {
+		return types.Transaction{}, err
 	}
+	defer w.tg.Done()
+
+	_, txnSet, err := w.signOutputs(coinOutputs, blockstakeOutputs, data, refundAddress, reuseRefundAddress)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	err = w.tpool.AcceptTransactionSet(txnSet)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	return txnSet[0], nil
+}
 
+// DryRunOutputs creates and validates the transaction SendOutputs would create,
+// without submitting it to the transaction pool. The outputs funding the transaction
+// remain available for other transactions.
+func (w *Wallet) DryRunOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, refundAddress *types.UnlockHash, reuseRefundAddress bool) (types.Transaction, error) {
 	if err := w.tg.Add(); err != nil {
 		return types.Transaction{}, err
 	}
 	defer w.tg.Done()
 
-	tpoolFee := w.chainCts.MinimumTransactionFee.Mul64(1) // TODO better fee algo
+	txnBuilder, txnSet, err := w.signOutputs(coinOutputs, blockstakeOutputs, data, refundAddress, reuseRefundAddress)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	defer txnBuilder.Drop()
+	err = w.tpool.ValidateTransactionSet(txnSet)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	return txnSet[0], nil
+}
+
+// signOutputs creates and signs a transaction which sends the given outputs and data, funded by the wallet,
+// paying the (estimated) minimum fee. The builder is returned such that it can be dropped if needed.
+func (w *Wallet) signOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, refundAddress *types.UnlockHash, reuseRefundAddress bool) (modules.TransactionBuilder, []types.Transaction, error) {
+	if len(coinOutputs) == 0 && len(blockstakeOutputs) == 0 {
+		// at least one coin output OR one block stake output has to be send
+		return nil, nil, ErrNilOutputs
+	}
+	// validate the arbitrary data prior to funding the transaction
+	err := types.ValidateArbitraryData(data, w.chainCts.ArbitraryDataSizeLimit, w.chainCts.AllowedArbitraryDataTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the fee depends on the size of the transaction, which in turn depends on the inputs
+	// required to fund it (including the fee), as such the transaction is funded again
+	// for as long as the fee, estimated for the funded transaction, exceeds the fee paid
+	tpoolFee := w.chainCts.TransactionFee(0)
+	var txnBuilder modules.TransactionBuilder
+	for attempt := 0; ; attempt++ {
+		txnBuilder, err = w.fundOutputs(coinOutputs, blockstakeOutputs, data, tpoolFee, refundAddress, reuseRefundAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		txn, _ := txnBuilder.View()
+		_, estimatedFee, err := w.EstimateTransactionFee(txn)
+		if err != nil {
+			txnBuilder.Drop()
+			return nil, nil, err
+		}
+		if estimatedFee.Cmp(tpoolFee) <= 0 {
+			break
+		}
+		txnBuilder.Drop()
+		if attempt >= maxFeeEstimationAttempts {
+			return nil, nil, errors.New("failed to fund transaction: fee estimation did not converge")
+		}
+		tpoolFee = estimatedFee
+	}
+	txnSet, err := txnBuilder.Sign()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(txnSet) == 0 {
+		build.Severe(fmt.Errorf("unexpected txnSet length: " + strconv.Itoa(len(txnSet))))
+	}
+	return txnBuilder, txnSet, nil
+}
+
+// fundOutputs creates a transaction builder for a transaction which sends the given outputs and data,
+// funded by the wallet, paying the given fee. The builder is dropped in case it couldn't be funded.
+func (w *Wallet) fundOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs []types.BlockStakeOutput, data []byte, tpoolFee types.Currency, refundAddress *types.UnlockHash, reuseRefundAddress bool) (modules.TransactionBuilder, error) {
 	totalAmount := types.NewCurrency64(0).Add(tpoolFee)
 	txnBuilder := w.StartTransaction()
 	for _, co := range coinOutputs {
@@ -277,7 +358,8 @@ func (w *Wallet) SendOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs [
 	}
 	err := txnBuilder.FundCoins(totalAmount, refundAddress, reuseRefundAddress)
 	if err != nil {
-		return types.Transaction{}, err
+		txnBuilder.Drop()
+		return nil, err
 	}
 	txnBuilder.AddMinerFee(tpoolFee)
 	totalAmount = types.NewCurrency64(0)
@@ -288,24 +370,24 @@ func (w *Wallet) SendOutputs(coinOutputs []types.CoinOutput, blockstakeOutputs [
 	if !totalAmount.Equals64(0) {
 		err = txnBuilder.FundBlockStakes(totalAmount, refundAddress, reuseRefundAddress)
 		if err != nil {
-			return types.Transaction{}, err
+			txnBuilder.Drop()
+			return nil, err
 		}
 	}
 	if len(data) != 0 {
 		txnBuilder.SetArbitraryData(data)
 	}
-	txnSet, err := txnBuilder.Sign()
-	if err != nil {
-		return types.Transaction{}, err
-	}
-	if len(txnSet) == 0 {
-		build.Severe(fmt.Errorf("unexpected txnSet length: " + strconv.Itoa(len(txnSet))))
-	}
-	err = w.tpool.AcceptTransactionSet(txnSet)
+	return txnBuilder, nil
+}
+
+// EstimateTransactionFee estimates the (encoded) size the given transaction will have once signed,
+// as well as the fee to be paid for a transaction of that size.
+func (w *Wallet) EstimateTransactionFee(txn types.Transaction) (int, types.Currency, error) {
+	size, err := txn.EstimateSize()
 	if err != nil {
-		return types.Transaction{}, err
+		return 0, types.Currency{}, err
 	}
-	return txnSet[0], nil
+	return size, w.chainCts.TransactionFee(size), nil
 }
 
 // Len returns the number of elements in the sortedOutputs struct.
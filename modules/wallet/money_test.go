@@ -74,6 +74,108 @@ func TestSendCoins(t *testing.T) {
 	}
 }
 
+// TestDryRunOutputs probes the DryRunOutputs method of the wallet,
+// ensuring the transaction is created without being submitted or spending any outputs.
+func TestDryRunOutputs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	cs := newConsensusSetStub()
+	wt, err := createWalletTesterWithStubCS(t.Name(), cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	addr, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.addTransactionAsBlock(addr,
+		wt.wallet.chainCts.MinimumTransactionFee.Mul64(1).Add(types.NewCurrency64(5000)))
+
+	outputs := []types.CoinOutput{{Value: types.NewCurrency64(5000), Condition: types.NewCondition(nil)}}
+	txn, err := wt.wallet.DryRunOutputs(outputs, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txn.CoinInputs) != 1 || len(txn.MinerFees) != 1 {
+		t.Errorf("unexpected dry run transaction: %v", txn)
+	}
+	if n := len(wt.tpool.TransactionList()); n != 0 {
+		t.Errorf("expected the dry run transaction not to be submitted, pool has %d transaction(s)", n)
+	}
+	unconfirmedOut, _, err := wt.wallet.UnconfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unconfirmedOut.Equals64(0) {
+		t.Error("dry run should not spend any outputs")
+	}
+
+	// the outputs funding the dry run are still available
+	sent, err := wt.wallet.SendOutputs(outputs, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.ID() != txn.ID() {
+		t.Error("expected the sent transaction to equal the dry run transaction")
+	}
+}
+
+// TestSendCoinsVested ensures that the wallet can spend a fully vested output,
+// fulfilling it using the signature of the owner.
+func TestSendCoinsVested(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	cs := newConsensusSetStub()
+	wt, err := createWalletTesterWithStubCS(t.Name(), cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	addr, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := wt.wallet.chainCts.MinimumTransactionFee.Add(types.NewCurrency64(5000))
+	// vested in 10 periods of 10 seconds, starting 1000 seconds ago
+	condition := types.NewCondition(types.NewVestingCondition(addr, value, types.CurrentTimestamp()-1000, 0, 10, 10))
+	err = cs.AcceptBlock(types.Block{
+		ParentID:  cs.CurrentBlock().ID(),
+		Timestamp: types.CurrentTimestamp(),
+		Transactions: []types.Transaction{{
+			Version:     wt.wallet.chainCts.DefaultTransactionVersion,
+			CoinOutputs: []types.CoinOutput{{Value: value, Condition: condition}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := wt.wallet.SendCoins(types.NewCurrency64(5000), types.NewCondition(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txn.CoinInputs) != 1 {
+		t.Fatalf("unexpected transaction: %v", txn)
+	}
+	err = condition.Fulfill(txn.CoinInputs[0].Fulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		BlockHeight:  cs.Height(),
+		BlockTime:    cs.CurrentBlock().Timestamp,
+		Transaction:  txn,
+		ChainID:      wt.wallet.chainID,
+	})
+	if err != nil {
+		t.Error("invalid fulfillment of the vested output:", err)
+	}
+}
+
 // TestIntegrationSendOverUnder sends too many coins, resulting in an error,
 // followed by sending few enough coins that the send should complete.
 //
@@ -0,0 +1,322 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	errPaymentChannelRole        = errors.New("operation not allowed for the role of the wallet within the payment channel")
+	errPaymentChannelNoPayment   = errors.New("amount does not increase the total amount paid in the payment channel")
+	errPaymentChannelCapacity    = errors.New("amount (and transaction fee) exceeds the capacity of the payment channel")
+	errPaymentChannelCommitment  = errors.New("invalid payment channel commitment")
+	errPaymentChannelNoSettlment = errors.New("payment channel has no commitment to settle")
+)
+
+// PaymentChannels returns all payment channels known by this wallet,
+// either as sender or as receiver.
+func (w *Wallet) PaymentChannels() ([]modules.PaymentChannel, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	channels := make([]modules.PaymentChannel, len(w.persist.PaymentChannels))
+	copy(channels, w.persist.PaymentChannels)
+	return channels, nil
+}
+
+// OpenPaymentChannel opens a payment channel as the sender,
+// funding it with the given capacity, and locking it until the given lock time.
+// The funding transaction is automatically given to the transaction pool.
+func (w *Wallet) OpenPaymentChannel(receiver types.UnlockHash, capacity types.Currency, lockTime uint64) (modules.PaymentChannel, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.PaymentChannel{}, err
+	}
+	defer w.tg.Done()
+
+	if capacity.Cmp(w.chainCts.MinimumTransactionFee) <= 0 {
+		return modules.PaymentChannel{}, errors.New("payment channel capacity has to be greater than the minimum transaction fee")
+	}
+	sender, err := w.NextAddress()
+	if err != nil {
+		return modules.PaymentChannel{}, err
+	}
+	condition := types.NewPaymentChannelCondition(sender, receiver, lockTime)
+	if err = condition.IsStandardCondition(types.ValidationContext{}); err != nil {
+		return modules.PaymentChannel{}, fmt.Errorf("invalid payment channel: %v", err)
+	}
+	txn, err := w.SendCoins(capacity, types.NewCondition(condition), nil)
+	if err != nil {
+		return modules.PaymentChannel{}, err
+	}
+
+	channel := modules.PaymentChannel{
+		Role:      modules.PaymentChannelRoleSender,
+		Condition: *condition,
+		Capacity:  capacity,
+	}
+	for idx, co := range txn.CoinOutputs {
+		if co.Condition.Equal(types.NewCondition(condition)) {
+			channel.ID = txn.CoinOutputID(uint64(idx))
+			break
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.persist.PaymentChannels = append(w.persist.PaymentChannels, channel)
+	return channel, w.saveSettingsSync()
+}
+
+// UpdatePaymentChannel creates a new commitment as the sender of the payment channel,
+// paying the given total amount to the receiver.
+func (w *Wallet) UpdatePaymentChannel(id types.CoinOutputID, amount types.Currency) (types.Transaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return types.Transaction{}, modules.ErrLockedWallet
+	}
+	channel := w.paymentChannel(id)
+	if channel == nil {
+		return types.Transaction{}, modules.ErrUnknownPaymentChannel
+	}
+	if channel.Role != modules.PaymentChannelRoleSender {
+		return types.Transaction{}, errPaymentChannelRole
+	}
+	if amount.Cmp(channel.Paid) <= 0 {
+		return types.Transaction{}, errPaymentChannelNoPayment
+	}
+	if amount.Add(w.chainCts.MinimumTransactionFee).Cmp(channel.Capacity) > 0 {
+		return types.Transaction{}, errPaymentChannelCapacity
+	}
+
+	txn := w.paymentChannelTransaction(channel, amount)
+	err := w.signPaymentChannelTransaction(&txn, channel.Condition.Sender)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	channel.Paid = amount
+	channel.Commitment = &txn
+	if err = w.saveSettingsSync(); err != nil {
+		return types.Transaction{}, err
+	}
+	return copyTransaction(txn)
+}
+
+// AcceptPaymentChannelCommitment validates and stores a commitment as the receiver of a payment channel,
+// registering the payment channel if it wasn't known yet by this wallet.
+func (w *Wallet) AcceptPaymentChannelCommitment(commitment types.Transaction) (modules.PaymentChannel, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return modules.PaymentChannel{}, modules.ErrLockedWallet
+	}
+	if len(commitment.CoinInputs) != 1 || len(commitment.BlockStakeInputs) != 0 || len(commitment.BlockStakeOutputs) != 0 {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: a commitment can only spend the funding output of a payment channel", errPaymentChannelCommitment)
+	}
+	ff, ok := commitment.CoinInputs[0].Fulfillment.Fulfillment.(*types.PaymentChannelFulfillment)
+	if !ok || ff.Receiver != nil {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: a commitment has to be signed by the sender only", errPaymentChannelCommitment)
+	}
+	id := commitment.CoinInputs[0].ParentID
+	fundingOutput, err := w.cs.GetCoinOutput(id)
+	if err != nil {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: funding output %s not found: %v", errPaymentChannelCommitment, id.String(), err)
+	}
+	condition, ok := fundingOutput.Condition.Condition.(*types.PaymentChannelCondition)
+	if !ok {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: funding output %s is not a payment channel", errPaymentChannelCommitment, id.String())
+	}
+	if _, ok = w.keys[condition.Receiver]; !ok {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: wallet is not the receiver of payment channel %s", errPaymentChannelCommitment, id.String())
+	}
+
+	// ensure the commitment is balanced, and compute the amount paid to the receiver
+	var paid, outputSum types.Currency
+	for _, co := range commitment.CoinOutputs {
+		outputSum = outputSum.Add(co.Value)
+		if co.Condition.UnlockHash().Cmp(condition.Receiver) == 0 {
+			paid = paid.Add(co.Value)
+		}
+	}
+	var fees types.Currency
+	for _, fee := range commitment.MinerFees {
+		fees = fees.Add(fee)
+	}
+	if fees.Cmp(w.chainCts.MinimumTransactionFee) < 0 || outputSum.Add(fees).Cmp(fundingOutput.Value) != 0 {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: unbalanced commitment or insufficient transaction fee", errPaymentChannelCommitment)
+	}
+
+	channel := w.paymentChannel(id)
+	if channel == nil {
+		w.persist.PaymentChannels = append(w.persist.PaymentChannels, modules.PaymentChannel{
+			ID:        id,
+			Role:      modules.PaymentChannelRoleReceiver,
+			Condition: *condition,
+			Capacity:  fundingOutput.Value,
+		})
+		channel = &w.persist.PaymentChannels[len(w.persist.PaymentChannels)-1]
+	} else if channel.Role != modules.PaymentChannelRoleReceiver {
+		return modules.PaymentChannel{}, errPaymentChannelRole
+	}
+	if paid.Cmp(channel.Paid) <= 0 {
+		return modules.PaymentChannel{}, errPaymentChannelNoPayment
+	}
+
+	// ensure the sender's signature is valid, by completing the commitment
+	settlement, err := copyTransaction(commitment)
+	if err != nil {
+		return modules.PaymentChannel{}, err
+	}
+	if err = w.signPaymentChannelTransaction(&settlement, condition.Receiver); err != nil {
+		return modules.PaymentChannel{}, err
+	}
+	err = fundingOutput.Condition.Fulfill(settlement.CoinInputs[0].Fulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		BlockHeight:  w.consensusSetHeight,
+		Transaction:  settlement,
+		ChainID:      w.chainID,
+	})
+	if err != nil {
+		return modules.PaymentChannel{}, fmt.Errorf("%v: %v", errPaymentChannelCommitment, err)
+	}
+
+	channel.Paid = paid
+	channel.Commitment = &commitment
+	return *channel, w.saveSettingsSync()
+}
+
+// ClosePaymentChannel closes the payment channel, settling the latest commitment as the receiver,
+// or refunding the funding output as the sender, once its lock time is reached.
+// The transaction is automatically given to the transaction pool.
+func (w *Wallet) ClosePaymentChannel(id types.CoinOutputID) (types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.Transaction{}, err
+	}
+	defer w.tg.Done()
+
+	txn, err := w.closePaymentChannelTransaction(id)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	err = w.tpool.AcceptTransactionSet([]types.Transaction{txn})
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	return txn, nil
+}
+
+// closePaymentChannelTransaction creates the signed transaction used to close a payment channel.
+func (w *Wallet) closePaymentChannelTransaction(id types.CoinOutputID) (types.Transaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return types.Transaction{}, modules.ErrLockedWallet
+	}
+	channel := w.paymentChannel(id)
+	if channel == nil {
+		return types.Transaction{}, modules.ErrUnknownPaymentChannel
+	}
+
+	if channel.Role == modules.PaymentChannelRoleReceiver {
+		if channel.Commitment == nil {
+			return types.Transaction{}, errPaymentChannelNoSettlment
+		}
+		txn, err := copyTransaction(*channel.Commitment)
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		return txn, w.signPaymentChannelTransaction(&txn, channel.Condition.Receiver)
+	}
+
+	// refund all coins (minus the transaction fee) to the sender
+	txn := w.paymentChannelTransaction(channel, types.ZeroCurrency)
+	err := w.signPaymentChannelTransaction(&txn, channel.Condition.Sender)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	ctx := w.getFulfillableContextForLatestBlock()
+	err = channel.Condition.Fulfill(txn.CoinInputs[0].Fulfillment.Fulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  txn,
+		ChainID:      w.chainID,
+	})
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("cannot refund payment channel %s: %v", id.String(), err)
+	}
+	return txn, nil
+}
+
+// paymentChannel returns the payment channel, known by this wallet, or nil if it isn't known.
+func (w *Wallet) paymentChannel(id types.CoinOutputID) *modules.PaymentChannel {
+	for idx := range w.persist.PaymentChannels {
+		if w.persist.PaymentChannels[idx].ID == id {
+			return &w.persist.PaymentChannels[idx]
+		}
+	}
+	return nil
+}
+
+// paymentChannelTransaction creates an unsigned transaction spending the funding output of a payment channel,
+// paying the given amount to the receiver, and the remainder (minus the transaction fee) to the sender.
+func (w *Wallet) paymentChannelTransaction(channel *modules.PaymentChannel, amount types.Currency) types.Transaction {
+	fee := w.chainCts.MinimumTransactionFee
+	txn := types.Transaction{
+		Version: w.chainCts.DefaultTransactionVersion,
+		CoinInputs: []types.CoinInput{{
+			ParentID:    channel.ID,
+			Fulfillment: types.NewFulfillment(&types.PaymentChannelFulfillment{}),
+		}},
+		MinerFees: []types.Currency{fee},
+	}
+	if !amount.IsZero() {
+		txn.CoinOutputs = append(txn.CoinOutputs, types.CoinOutput{
+			Value:     amount,
+			Condition: types.NewCondition(types.NewUnlockHashCondition(channel.Condition.Receiver)),
+		})
+	}
+	if change := channel.Capacity.Sub(amount).Sub(fee); !change.IsZero() {
+		txn.CoinOutputs = append(txn.CoinOutputs, types.CoinOutput{
+			Value:     change,
+			Condition: types.NewCondition(types.NewUnlockHashCondition(channel.Condition.Sender)),
+		})
+	}
+	return txn
+}
+
+// signPaymentChannelTransaction signs the (single) payment channel input of the transaction,
+// using the key linked to the given address.
+func (w *Wallet) signPaymentChannelTransaction(txn *types.Transaction, address types.UnlockHash) error {
+	key, ok := w.keys[address]
+	if !ok {
+		return errUnknownAddress
+	}
+	return txn.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  *txn,
+		ChainID:      w.chainID,
+		Key: types.KeyPair{
+			PublicKey:  types.Ed25519PublicKey(key.PublicKey),
+			PrivateKey: types.ByteSlice(key.SecretKey[:]),
+		},
+	})
+}
+
+// copyTransaction creates a deep copy of a transaction,
+// such that it can be signed without modifying the original transaction.
+func copyTransaction(txn types.Transaction) (types.Transaction, error) {
+	b, err := siabin.Marshal(txn)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	var cpy types.Transaction
+	err = siabin.Unmarshal(b, &cpy)
+	return cpy, err
+}
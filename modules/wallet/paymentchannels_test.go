@@ -0,0 +1,159 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// TestPaymentChannel opens a payment channel between two wallets,
+// updating it a couple of times, prior to settling it as the receiver and refunding it as the sender.
+func TestPaymentChannel(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	cs := newConsensusSetStub()
+	sender, err := createWalletTesterWithStubCS(t.Name()+"Sender", cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.closeWt()
+	receiver, err := createWalletTesterWithStubCS(t.Name()+"Receiver", cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.closeWt()
+
+	fee := sender.wallet.chainCts.MinimumTransactionFee
+	capacity := fee.Mul64(10)
+	addr, err := sender.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.addTransactionAsBlock(addr, capacity.Add(fee)); err != nil {
+		t.Fatal(err)
+	}
+	receiverAddr, err := receiver.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// open the channel, confirming its funding transaction
+	const lockTime = 5
+	channel, err := sender.wallet.OpenPaymentChannel(receiverAddr, capacity, lockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Role != modules.PaymentChannelRoleSender || !channel.Capacity.Equals(capacity) ||
+		channel.Condition.Receiver != receiverAddr || channel.Condition.LockTime != lockTime {
+		t.Fatalf("unexpected payment channel: %v", channel)
+	}
+	err = cs.AcceptBlock(types.Block{
+		ParentID:     cs.CurrentBlock().ID(),
+		Timestamp:    types.CurrentTimestamp(),
+		Transactions: sender.tpool.TransactionList(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if co, err := cs.GetCoinOutput(channel.ID); err != nil || !co.Value.Equals(capacity) {
+		t.Fatalf("funding output %s not found: %v", channel.ID.String(), err)
+	}
+
+	// update the channel a couple of times, each commitment being accepted by the receiver
+	for _, amount := range []types.Currency{fee.Mul64(2), fee.Mul64(5)} {
+		commitment, err := sender.wallet.UpdatePaymentChannel(channel.ID, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		accepted, err := receiver.wallet.AcceptPaymentChannelCommitment(commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted.ID != channel.ID || accepted.Role != modules.PaymentChannelRoleReceiver || !accepted.Paid.Equals(amount) {
+			t.Errorf("unexpected accepted payment channel: %v", accepted)
+		}
+	}
+	channels, err := sender.wallet.PaymentChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 || !channels[0].Paid.Equals(fee.Mul64(5)) || channels[0].Commitment == nil {
+		t.Errorf("unexpected payment channels of sender: %v", channels)
+	}
+
+	// invalid updates are refused
+	if _, err = sender.wallet.UpdatePaymentChannel(channel.ID, fee.Mul64(4)); err != errPaymentChannelNoPayment {
+		t.Errorf("expected %v, not %v", errPaymentChannelNoPayment, err)
+	}
+	if _, err = sender.wallet.UpdatePaymentChannel(channel.ID, capacity); err != errPaymentChannelCapacity {
+		t.Errorf("expected %v, not %v", errPaymentChannelCapacity, err)
+	}
+	if _, err = receiver.wallet.UpdatePaymentChannel(channel.ID, fee.Mul64(6)); err != errPaymentChannelRole {
+		t.Errorf("expected %v, not %v", errPaymentChannelRole, err)
+	}
+	if _, err = sender.wallet.UpdatePaymentChannel(types.CoinOutputID{1}, fee.Mul64(6)); err != modules.ErrUnknownPaymentChannel {
+		t.Errorf("expected %v, not %v", modules.ErrUnknownPaymentChannel, err)
+	}
+	// a commitment paying less than the latest commitment is refused by the receiver
+	outdated, err := sender.wallet.UpdatePaymentChannel(channel.ID, fee.Mul64(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdated.CoinOutputs[0].Value = fee.Mul64(4)
+	outdated.CoinOutputs[1].Value = capacity.Sub(fee.Mul64(5))
+	if _, err = receiver.wallet.AcceptPaymentChannelCommitment(outdated); err != errPaymentChannelNoPayment {
+		t.Errorf("expected %v, not %v", errPaymentChannelNoPayment, err)
+	}
+	// the sender is not the receiver of its own channel
+	if _, err = sender.wallet.AcceptPaymentChannelCommitment(*channels[0].Commitment); err == nil {
+		t.Error("expected the sender to refuse its own commitment")
+	}
+
+	fulfill := func(w *Wallet, txn types.Transaction) error {
+		ctx := w.getFulfillableContextForLatestBlock()
+		return channel.Condition.Fulfill(txn.CoinInputs[0].Fulfillment.Fulfillment, types.FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			BlockHeight:  ctx.BlockHeight,
+			BlockTime:    ctx.BlockTime,
+			Transaction:  txn,
+			ChainID:      w.chainID,
+		})
+	}
+
+	// the sender can only refund the channel once the lock time is reached
+	if _, err = sender.wallet.closePaymentChannelTransaction(channel.ID); err == nil {
+		t.Error("expected a premature refund to be refused")
+	}
+	for cs.Height() < lockTime {
+		if err = cs.addTransactionAsBlock(addr, types.NewCurrency64(uint64(cs.Height()))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refund, err := sender.wallet.closePaymentChannelTransaction(channel.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refund.CoinOutputs) != 1 || !refund.CoinOutputs[0].Value.Equals(capacity.Sub(fee)) ||
+		refund.CoinOutputs[0].Condition.UnlockHash() != channel.Condition.Sender {
+		t.Errorf("unexpected refund outputs: %v", refund.CoinOutputs)
+	}
+	if err = fulfill(sender.wallet, refund); err != nil {
+		t.Error("invalid refund:", err)
+	}
+
+	// the receiver settles the latest commitment it accepted
+	settlement, err := receiver.wallet.ClosePaymentChannel(channel.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settlement.CoinOutputs) != 2 || !settlement.CoinOutputs[0].Value.Equals(fee.Mul64(5)) ||
+		settlement.CoinOutputs[0].Condition.UnlockHash() != receiverAddr {
+		t.Errorf("unexpected settlement outputs: %v", settlement.CoinOutputs)
+	}
+	if err = fulfill(receiver.wallet, settlement); err != nil {
+		t.Error("invalid settlement:", err)
+	}
+}
@@ -61,6 +61,10 @@ type WalletPersist struct {
 	// UnseededKeys are list of spendable keys that were not generated by a
 	// random seed.
 	UnseededKeys []SpendableKeyFile
+
+	// PaymentChannels are the payment channels this wallet takes part in,
+	// either as sender or as receiver, including their latest commitment.
+	PaymentChannels []modules.PaymentChannel `json:",omitempty"`
 }
 
 // loadSettings reads the wallet's settings from the wallet's settings file,
@@ -40,11 +40,10 @@ type (
 // generateSpendableKey creates the keys and unlock conditions for seed at a
 // given index.
 func generateSpendableKey(seed modules.Seed, index uint64) (spendableKey, error) {
-	h, err := crypto.HashAll(seed, index)
+	sk, pk, err := seed.SpendableKey(index)
 	if err != nil {
 		return spendableKey{}, err
 	}
-	sk, pk := crypto.GenerateKeyPairDeterministic(h)
 	return spendableKey{
 		PublicKey: pk,
 		SecretKey: sk,
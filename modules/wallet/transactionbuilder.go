@@ -9,6 +9,7 @@ import (
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -21,12 +22,13 @@ var (
 
 // transactionBuilder allows transactions to be manually constructed, including
 // the ability to fund transactions with siacoins and blockstakes from the wallet.
+// The transaction itself is assembled using a txbuilder.Builder.
 type transactionBuilder struct {
 	// 'signed' indicates that at least one transaction signature has been
 	// added to the wallet, meaning that future calls to 'Sign' will fail.
-	parents     []types.Transaction
-	signed      bool
-	transaction types.Transaction
+	parents []types.Transaction
+	signed  bool
+	builder *txbuilder.Builder
 
 	newParents       []int
 	coinInputs       []inputSignContext
@@ -81,18 +83,17 @@ func (tb *transactionBuilder) FundCoins(amount types.Currency, refundAddress *ty
 	}
 	sort.Sort(sort.Reverse(so))
 
-	// Create a transaction that will add the correct amount of siacoins to the
-	// transaction.
-	var fund types.Currency
+	// Collect the outputs which haven't been spent recently by the wallet.
+	var available []txbuilder.UnspentCoinOutput
 	// potentialFund tracks the balance of the wallet including outputs that
 	// have been spent in other unconfirmed transactions recently. This is to
 	// provide the user with a more useful error message in the event that they
 	// are overspending.
 	var potentialFund types.Currency
-	var spentScoids []types.CoinOutputID
 	for i := range so.ids {
 		scoid := so.ids[i]
 		sco := so.outputs[i]
+		potentialFund = potentialFund.Add(sco.Value)
 		// Check that this output has not recently been spent by the wallet.
 		spendHeight := tb.wallet.spentOutputs[types.OutputID(scoid)]
 		// Prevent an underflow error.
@@ -101,51 +102,48 @@ func (tb *transactionBuilder) FundCoins(amount types.Currency, refundAddress *ty
 			allowedHeight = 0
 		}
 		if spendHeight > allowedHeight {
-			potentialFund = potentialFund.Add(sco.Value)
 			continue
 		}
+		available = append(available, txbuilder.UnspentCoinOutput{ID: scoid, Output: sco})
+	}
 
+	// Select the outputs that will add the correct amount of coins to the transaction.
+	selected, fund, err := txbuilder.SelectCoinOutputs(available, amount)
+	if err == txbuilder.ErrInsufficientFunds {
+		if potentialFund.Cmp(amount) >= 0 {
+			return modules.ErrIncompleteTransactions
+		}
+		return modules.ErrLowBalance
+	}
+	if err != nil {
+		return err
+	}
+	for _, uco := range selected {
 		// prepare fulfillment, matching the output
-		uh := sco.Condition.UnlockHash()
+		uh := uco.Output.Condition.UnlockHash()
 		var ff types.MarshalableUnlockFulfillment
-		switch sco.Condition.ConditionType() {
-		case types.ConditionTypeUnlockHash, types.ConditionTypeTimeLock:
+		switch uco.Output.Condition.ConditionType() {
+		case types.ConditionTypeUnlockHash, types.ConditionTypeTimeLock, types.ConditionTypeColdStaking, types.ConditionTypeVesting:
 			// ConditionTypeTimeLock is fine, as we know it's fulfillable,
-			// and that can only mean for now that it is using an internal unlockHashCondition or nilCondition
+			// and that can only mean for now that it is using an internal unlockHashCondition or nilCondition.
+			// ConditionTypeColdStaking is fine as well, as the unlock hash is the one of the owner.
+			// ConditionTypeVesting is fine too, as it is only fulfillable once all its coins are vested,
+			// requiring nothing but the signature of the owner, whose unlock hash is the one of the condition.
 			pk, _, err := tb.wallet.getKey(uh)
 			if err != nil {
 				return err
 			}
 			ff = types.NewSingleSignatureFulfillment(pk)
 		default:
-			build.Severe(fmt.Errorf("unexpected condition type: %[1]v (%[1]T)", sco.Condition))
+			build.Severe(fmt.Errorf("unexpected condition type: %[1]v (%[1]T)", uco.Output.Condition))
 			return types.ErrUnexpectedUnlockCondition
 		}
 		// Add a coin input for this output.
-		sci := types.CoinInput{
-			ParentID:    scoid,
-			Fulfillment: types.NewFulfillment(ff),
-		}
 		tb.coinInputs = append(tb.coinInputs, inputSignContext{
-			InputIndex: len(tb.transaction.CoinInputs),
+			InputIndex: len(tb.builder.Transaction().CoinInputs),
 			UnlockHash: uh,
 		})
-		tb.transaction.CoinInputs = append(tb.transaction.CoinInputs, sci)
-
-		spentScoids = append(spentScoids, scoid)
-
-		// Add the output to the total fund
-		fund = fund.Add(sco.Value)
-		potentialFund = potentialFund.Add(sco.Value)
-		if fund.Cmp(amount) >= 0 {
-			break
-		}
-	}
-	if potentialFund.Cmp(amount) >= 0 && fund.Cmp(amount) < 0 {
-		return modules.ErrIncompleteTransactions
-	}
-	if fund.Cmp(amount) < 0 {
-		return modules.ErrLowBalance
+		tb.builder.SpendCoinOutput(uco.ID, uco.Output, ff)
 	}
 
 	// Create a refund output if needed.
@@ -157,7 +155,7 @@ func (tb *transactionBuilder) FundCoins(amount types.Currency, refundAddress *ty
 		} else if reuseRefundAddress {
 			// use the fist coin input of this tx as refund address
 			var maxCoinAmount types.Currency
-			for _, ci := range tb.transaction.CoinInputs {
+			for _, ci := range tb.builder.Transaction().CoinInputs {
 				co, exists := tb.wallet.coinOutputs[ci.ParentID]
 				if !exists {
 					co = tb.getCoFromUnconfirmedProcessedTransactions(ci.ParentID)
@@ -175,16 +173,12 @@ func (tb *transactionBuilder) FundCoins(amount types.Currency, refundAddress *ty
 				return err
 			}
 		}
-		refundOutput := types.CoinOutput{
-			Value:     fund.Sub(amount),
-			Condition: types.NewCondition(types.NewUnlockHashCondition(refundUnlockHash)),
-		}
-		tb.transaction.CoinOutputs = append(tb.transaction.CoinOutputs, refundOutput)
+		tb.builder.AddCoinOutput(fund.Sub(amount), types.NewCondition(types.NewUnlockHashCondition(refundUnlockHash)))
 	}
 
 	// Mark all outputs that were spent as spent.
-	for _, scoid := range spentScoids {
-		tb.wallet.spentOutputs[types.OutputID(scoid)] = tb.wallet.consensusSetHeight
+	for _, uco := range selected {
+		tb.wallet.spentOutputs[types.OutputID(uco.ID)] = tb.wallet.consensusSetHeight
 	}
 	return nil
 }
@@ -217,15 +211,14 @@ func (tb *transactionBuilder) FundBlockStakes(amount types.Currency, refundAddre
 	// prepare fulfillable context
 	ctx := tb.wallet.getFulfillableContextForLatestBlock()
 
-	// Create a transaction that will add the correct amount of siafunds to the
-	// transaction.
-	var fund types.Currency
+	// Collect the outputs which haven't been spent recently by the wallet.
+	var available []txbuilder.UnspentBlockStakeOutput
 	var potentialFund types.Currency
-	var spentSfoids []types.BlockStakeOutputID
 	for sfoid, sfo := range tb.wallet.blockstakeOutputs {
 		if !sfo.Condition.Fulfillable(ctx) {
 			continue
 		}
+		potentialFund = potentialFund.Add(sfo.Value)
 		// Check that this output has not recently been spent by the wallet.
 		spendHeight := tb.wallet.spentOutputs[types.OutputID(sfoid)]
 		// Prevent an underflow error.
@@ -234,51 +227,48 @@ func (tb *transactionBuilder) FundBlockStakes(amount types.Currency, refundAddre
 			allowedHeight = 0
 		}
 		if spendHeight > allowedHeight {
-			potentialFund = potentialFund.Add(sfo.Value)
 			continue
 		}
+		available = append(available, txbuilder.UnspentBlockStakeOutput{ID: sfoid, Output: sfo})
+	}
 
+	// Select the outputs that will add the correct amount of block stakes to the transaction.
+	selected, fund, err := txbuilder.SelectBlockStakeOutputs(available, amount)
+	if err == txbuilder.ErrInsufficientFunds {
+		if potentialFund.Cmp(amount) >= 0 {
+			return modules.ErrIncompleteTransactions
+		}
+		return modules.ErrLowBalance
+	}
+	if err != nil {
+		return err
+	}
+	for _, ubso := range selected {
 		// prepare fulfillment, matching the output
-		uh := sfo.Condition.UnlockHash()
+		uh := ubso.Output.Condition.UnlockHash()
 		var ff types.MarshalableUnlockFulfillment
-		switch sfo.Condition.ConditionType() {
-		case types.ConditionTypeUnlockHash, types.ConditionTypeTimeLock:
+		switch ubso.Output.Condition.ConditionType() {
+		case types.ConditionTypeUnlockHash, types.ConditionTypeTimeLock, types.ConditionTypeColdStaking, types.ConditionTypeVesting:
 			// ConditionTypeTimeLock is fine, as we know it's fulfillable,
-			// and that can only mean for now that it is using an internal unlockHashCondition or nilCondition
+			// and that can only mean for now that it is using an internal unlockHashCondition or nilCondition.
+			// ConditionTypeColdStaking is fine as well, as the unlock hash is the one of the owner.
+			// ConditionTypeVesting is fine too, as it is only fulfillable once all its coins are vested,
+			// requiring nothing but the signature of the owner, whose unlock hash is the one of the condition.
 			pk, _, err := tb.wallet.getKey(uh)
 			if err != nil {
 				return err
 			}
 			ff = types.NewSingleSignatureFulfillment(pk)
 		default:
-			build.Severe(fmt.Sprintf("unexpected condition type: %[1]v (%[1]T)", sfo.Condition))
+			build.Severe(fmt.Sprintf("unexpected condition type: %[1]v (%[1]T)", ubso.Output.Condition))
 			return types.ErrUnexpectedUnlockCondition
 		}
 		// Add a block stake input for this output.
-		sfi := types.BlockStakeInput{
-			ParentID:    sfoid,
-			Fulfillment: types.NewFulfillment(ff),
-		}
 		tb.blockstakeInputs = append(tb.blockstakeInputs, inputSignContext{
-			InputIndex: len(tb.transaction.BlockStakeInputs),
+			InputIndex: len(tb.builder.Transaction().BlockStakeInputs),
 			UnlockHash: uh,
 		})
-		tb.transaction.BlockStakeInputs = append(tb.transaction.BlockStakeInputs, sfi)
-
-		spentSfoids = append(spentSfoids, sfoid)
-
-		// Add the output to the total fund
-		fund = fund.Add(sfo.Value)
-		potentialFund = potentialFund.Add(sfo.Value)
-		if fund.Cmp(amount) >= 0 {
-			break
-		}
-	}
-	if potentialFund.Cmp(amount) >= 0 && fund.Cmp(amount) < 0 {
-		return modules.ErrIncompleteTransactions
-	}
-	if fund.Cmp(amount) < 0 {
-		return modules.ErrLowBalance
+		tb.builder.SpendBlockStakeOutput(ubso.ID, ubso.Output, ff)
 	}
 
 	// Create a refund output if needed.
@@ -290,7 +280,7 @@ func (tb *transactionBuilder) FundBlockStakes(amount types.Currency, refundAddre
 		} else if reuseRefundAddress {
 			// use the fist coin input of this tx as refund address
 			var maxCoinAmount types.Currency
-			for _, bsi := range tb.transaction.BlockStakeInputs {
+			for _, bsi := range tb.builder.Transaction().BlockStakeInputs {
 				bso := tb.wallet.blockstakeOutputs[bsi.ParentID]
 				if maxCoinAmount.Cmp(bso.Value) < 0 {
 					maxCoinAmount = bso.Value
@@ -305,16 +295,12 @@ func (tb *transactionBuilder) FundBlockStakes(amount types.Currency, refundAddre
 				return err
 			}
 		}
-		refundOutput := types.BlockStakeOutput{
-			Value:     fund.Sub(amount),
-			Condition: types.NewCondition(types.NewUnlockHashCondition(refundUnlockHash)),
-		}
-		tb.transaction.BlockStakeOutputs = append(tb.transaction.BlockStakeOutputs, refundOutput)
+		tb.builder.AddBlockStakeOutput(fund.Sub(amount), types.NewCondition(types.NewUnlockHashCondition(refundUnlockHash)))
 	}
 
 	// Mark all outputs that were spent as spent.
-	for _, sfoid := range spentSfoids {
-		tb.wallet.spentOutputs[types.OutputID(sfoid)] = tb.wallet.consensusSetHeight
+	for _, ubso := range selected {
+		tb.wallet.spentOutputs[types.OutputID(ubso.ID)] = tb.wallet.consensusSetHeight
 	}
 	return nil
 }
@@ -327,31 +313,31 @@ func (tb *transactionBuilder) AddParents(newParents []types.Transaction) {
 // AddMinerFee adds a miner fee to the transaction, returning the index of the
 // miner fee within the transaction.
 func (tb *transactionBuilder) AddMinerFee(fee types.Currency) uint64 {
-	tb.transaction.MinerFees = append(tb.transaction.MinerFees, fee)
-	return uint64(len(tb.transaction.MinerFees) - 1)
+	tb.builder.AddMinerFee(fee)
+	return uint64(len(tb.builder.Transaction().MinerFees) - 1)
 }
 
 // AddCoinInput adds a siacoin input to the transaction, returning the index
 // of the coin input within the transaction. When 'Sign' gets called, this
 // input will be left unsigned.
 func (tb *transactionBuilder) AddCoinInput(input types.CoinInput) uint64 {
-	tb.transaction.CoinInputs = append(tb.transaction.CoinInputs, input)
-	return uint64(len(tb.transaction.CoinInputs) - 1)
+	tb.builder.AddCoinInput(input)
+	return uint64(len(tb.builder.Transaction().CoinInputs) - 1)
 }
 
 // AddCoinOutput adds a siacoin output to the transaction, returning the
 // index of the siacoin output within the transaction.
 func (tb *transactionBuilder) AddCoinOutput(output types.CoinOutput) uint64 {
-	tb.transaction.CoinOutputs = append(tb.transaction.CoinOutputs, output)
-	return uint64(len(tb.transaction.CoinOutputs) - 1)
+	tb.builder.AddCoinOutput(output.Value, output.Condition)
+	return uint64(len(tb.builder.Transaction().CoinOutputs) - 1)
 }
 
 // AddBlockStakeInput adds a blockstake input to the transaction, returning the index
 // of the blockstake input within the transaction. When 'Sign' is called, this
 // input will be left unsigned.
 func (tb *transactionBuilder) AddBlockStakeInput(input types.BlockStakeInput) uint64 {
-	tb.transaction.BlockStakeInputs = append(tb.transaction.BlockStakeInputs, input)
-	return uint64(len(tb.transaction.BlockStakeInputs) - 1)
+	tb.builder.AddBlockStakeInput(input)
+	return uint64(len(tb.builder.Transaction().BlockStakeInputs) - 1)
 }
 
 // SpendBlockStake will link the unspent block stake to the transaction as an input.
@@ -372,19 +358,24 @@ func (tb *transactionBuilder) SpendBlockStake(ubsoid types.BlockStakeOutputID) e
 	}
 
 	uh := ubso.Condition.UnlockHash()
+	if _, exists := tb.wallet.keys[uh]; !exists {
+		// a cold staking output can be respent by its staker
+		if staker, ok := getColdStakingStaker(ubso.Condition.Condition); ok {
+			uh = staker
+		}
+	}
 	pk, _, err := tb.wallet.getKey(uh)
 	if err != nil {
 		return err
 	}
-	bsi := types.BlockStakeInput{
-		ParentID:    ubsoid,
-		Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(pk)),
-	}
 	tb.blockstakeInputs = append(tb.blockstakeInputs, inputSignContext{
-		InputIndex: len(tb.transaction.BlockStakeInputs),
+		InputIndex: len(tb.builder.Transaction().BlockStakeInputs),
 		UnlockHash: uh,
 	})
-	tb.transaction.BlockStakeInputs = append(tb.transaction.BlockStakeInputs, bsi)
+	tb.builder.SpendBlockStakeOutput(ubsoid, types.BlockStakeOutput{
+		Value:     ubso.Value,
+		Condition: ubso.Condition,
+	}, types.NewSingleSignatureFulfillment(pk))
 
 	// Mark output as spent.
 	tb.wallet.spentOutputs[types.OutputID(ubsoid)] = tb.wallet.consensusSetHeight
@@ -394,13 +385,13 @@ func (tb *transactionBuilder) SpendBlockStake(ubsoid types.BlockStakeOutputID) e
 // AddBlockStakeOutput adds a blockstake output to the transaction, returning the
 // index of the blockstake output within the transaction.
 func (tb *transactionBuilder) AddBlockStakeOutput(output types.BlockStakeOutput) uint64 {
-	tb.transaction.BlockStakeOutputs = append(tb.transaction.BlockStakeOutputs, output)
-	return uint64(len(tb.transaction.BlockStakeOutputs) - 1)
+	tb.builder.AddBlockStakeOutput(output.Value, output.Condition)
+	return uint64(len(tb.builder.Transaction().BlockStakeOutputs) - 1)
 }
 
 // AddArbitraryData sets the arbitrary data of the transaction.
 func (tb *transactionBuilder) SetArbitraryData(arb []byte) {
-	tb.transaction.ArbitraryData = arb
+	tb.builder.SetArbitraryData(arb)
 }
 
 // Drop discards all of the outputs in a transaction, returning them to the
@@ -412,18 +403,19 @@ func (tb *transactionBuilder) Drop() {
 
 	// Iterate through all parents and the transaction itself and restore all
 	// outputs to the list of available outputs.
-	txns := append(tb.parents, tb.transaction)
+	txns := append(tb.parents, tb.builder.Transaction())
 	for _, txn := range txns {
 		for _, sci := range txn.CoinInputs {
 			delete(tb.wallet.spentOutputs, types.OutputID(sci.ParentID))
 		}
+		for _, bsi := range txn.BlockStakeInputs {
+			delete(tb.wallet.spentOutputs, types.OutputID(bsi.ParentID))
+		}
 	}
 
 	tb.parents = nil
 	tb.signed = false
-	tb.transaction = types.Transaction{
-		Version: tb.wallet.chainCts.DefaultTransactionVersion,
-	}
+	tb.builder = txbuilder.New(tb.wallet.chainCts.DefaultTransactionVersion)
 
 	tb.newParents = nil
 	tb.coinInputs = nil
@@ -458,32 +450,22 @@ func (tb *transactionBuilder) Sign() ([]types.Transaction, error) {
 	}
 
 	for _, ctx := range tb.coinInputs {
-		input := tb.transaction.CoinInputs[ctx.InputIndex]
 		_, sk, err := tb.wallet.getKey(ctx.UnlockHash)
 		if err != nil {
 			return nil, err
 		}
-		err = input.Fulfillment.Sign(types.FulfillmentSignContext{
-			ExtraObjects: []interface{}{uint64(ctx.InputIndex)},
-			Transaction:  tb.transaction,
-			Key:          sk,
-		})
+		err = tb.builder.SignCoinInput(ctx.InputIndex, sk)
 		if err != nil {
 			return nil, err
 		}
 		tb.signed = true // Signed is set to true after one successful signature to indicate that future signings can cause issues.
 	}
 	for _, ctx := range tb.blockstakeInputs {
-		input := tb.transaction.BlockStakeInputs[ctx.InputIndex]
 		_, sk, err := tb.wallet.getKey(ctx.UnlockHash)
 		if err != nil {
 			return nil, err
 		}
-		err = input.Fulfillment.Sign(types.FulfillmentSignContext{
-			ExtraObjects: []interface{}{uint64(ctx.InputIndex)},
-			Transaction:  tb.transaction,
-			Key:          sk,
-		})
+		err = tb.builder.SignBlockStakeInput(ctx.InputIndex, sk)
 		if err != nil {
 			return nil, err
 		}
@@ -491,7 +473,7 @@ func (tb *transactionBuilder) Sign() ([]types.Transaction, error) {
 	}
 
 	// Get the transaction set and delete the transaction from the registry.
-	txnSet := append(tb.parents, tb.transaction)
+	txnSet := append(tb.parents, tb.builder.Transaction())
 	return txnSet, nil
 }
 
@@ -508,60 +490,61 @@ func (tb *transactionBuilder) SignAllPossible() error {
 		return modules.ErrLockedWallet
 	}
 
+	// sign a working copy of the transaction, as fulfillments might be replaced
+	// while signing, storing the signed fulfillments in the builder once done
+	txn := tb.builder.Transaction()
+	txn.CoinInputs = append([]types.CoinInput(nil), txn.CoinInputs...)
+	txn.BlockStakeInputs = append([]types.BlockStakeInput(nil), txn.BlockStakeInputs...)
+
 	// sign all coin inputs
-	for i := range tb.transaction.CoinInputs {
-		ci := &tb.transaction.CoinInputs[i]
+	for i := range txn.CoinInputs {
+		ci := &txn.CoinInputs[i]
 		uco, err := tb.wallet.cs.GetCoinOutput(ci.ParentID)
 		if err != nil {
 			return err
 		}
-
-		if err = tb.signCoinInput(i, ci, uco.Condition.Condition); err != nil {
+		if err = tb.signFulfillment(&txn, &ci.Fulfillment, uco.Condition.Condition, uint64(i)); err != nil {
+			return err
+		}
+		if err = tb.builder.SetCoinInputFulfillment(i, ci.Fulfillment); err != nil {
 			return err
 		}
-
 	}
 
 	// sign all blockstake inputs
-	for i := range tb.transaction.BlockStakeInputs {
-		bsi := &tb.transaction.BlockStakeInputs[i]
+	for i := range txn.BlockStakeInputs {
+		bsi := &txn.BlockStakeInputs[i]
 		ubso, err := tb.wallet.cs.GetBlockStakeOutput(bsi.ParentID)
 		if err != nil {
 			return err
 		}
-		if err = tb.signBlockStakeInput(i, bsi, ubso.Condition.Condition); err != nil {
+		if err = tb.signFulfillment(&txn, &bsi.Fulfillment, ubso.Condition.Condition, uint64(i)); err != nil {
+			return err
+		}
+		if err = tb.builder.SetBlockStakeInputFulfillment(i, bsi.Fulfillment); err != nil {
 			return err
 		}
 	}
 
 	// sign the extension if required
-	err := tb.transaction.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+	err := txn.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
 		if fulfillment == nil {
 			return errors.New("failed to sign extension: nil fulfillment proxy cannot be signed")
 		}
 		if condition.ConditionType() == types.ConditionTypeNil {
-			return tb.signFulfillment(fulfillment, &types.NilCondition{}, extraObjects...)
+			return tb.signFulfillment(&txn, fulfillment, &types.NilCondition{}, extraObjects...)
 		}
-		return tb.signFulfillment(fulfillment, condition.Condition, extraObjects...)
+		return tb.signFulfillment(&txn, fulfillment, condition.Condition, extraObjects...)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to sign extension, using tx-defined logic: %v", err)
 	}
+	tb.builder.SetExtension(txn.Extension)
 
 	return nil
 }
 
-// signCoinInput attempts to sign a coin input with a key from the wallet
-func (tb *transactionBuilder) signCoinInput(idx int, ci *types.CoinInput, cond types.MarshalableUnlockCondition) error {
-	return tb.signFulfillment(&ci.Fulfillment, cond, uint64(idx))
-}
-
-// signBlockStakeInput attempts to sign a blockstake input with a key from the wallet
-func (tb *transactionBuilder) signBlockStakeInput(idx int, bsi *types.BlockStakeInput, cond types.MarshalableUnlockCondition) error {
-	return tb.signFulfillment(&bsi.Fulfillment, cond, uint64(idx))
-}
-
-func (tb *transactionBuilder) signFulfillment(fulfillment *types.UnlockFulfillmentProxy, cond types.MarshalableUnlockCondition, extraObjects ...interface{}) error {
+func (tb *transactionBuilder) signFulfillment(txn *types.Transaction, fulfillment *types.UnlockFulfillmentProxy, cond types.MarshalableUnlockCondition, extraObjects ...interface{}) error {
 	var err error
 	switch uh := cond.UnlockHash(); uh.Type {
 	case types.UnlockTypeNil:
@@ -579,7 +562,8 @@ func (tb *transactionBuilder) signFulfillment(fulfillment *types.UnlockFulfillme
 			fulfillment.Fulfillment = types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(key.PublicKey))
 			err := fulfillment.Fulfillment.Sign(types.FulfillmentSignContext{
 				ExtraObjects: extraObjects,
-				Transaction:  tb.transaction,
+				Transaction:  *txn,
+				ChainID:      tb.wallet.chainID,
 				Key:          key.SecretKey,
 			})
 			if err != nil {
@@ -600,7 +584,8 @@ func (tb *transactionBuilder) signFulfillment(fulfillment *types.UnlockFulfillme
 			if key, exists := tb.wallet.keys[uh]; exists {
 				err := fulfillment.Sign(types.FulfillmentSignContext{
 					ExtraObjects: extraObjects,
-					Transaction:  tb.transaction,
+					Transaction:  *txn,
+					ChainID:      tb.wallet.chainID,
 					Key: types.KeyPair{
 						PublicKey:  types.Ed25519PublicKey(key.PublicKey),
 						PrivateKey: types.ByteSlice(key.SecretKey[:]),
@@ -620,12 +605,38 @@ func (tb *transactionBuilder) signFulfillment(fulfillment *types.UnlockFulfillme
 	return nil
 }
 
+// Canonicalize sorts the inputs and outputs of the transaction into their canonical order,
+// keeping track of the inputs added by the builder. It fails if the transaction is already signed.
+func (tb *transactionBuilder) Canonicalize() error {
+	if tb.signed {
+		return errBuilderAlreadySigned
+	}
+	txn := tb.builder.Transaction()
+	tb.coinInputs = reindexInputSignContexts(tb.coinInputs, types.CanonicalCoinInputOrder(txn.CoinInputs))
+	tb.blockstakeInputs = reindexInputSignContexts(tb.blockstakeInputs, types.CanonicalBlockStakeInputOrder(txn.BlockStakeInputs))
+	tb.builder.Canonicalize()
+	return nil
+}
+
+// reindexInputSignContexts updates the input indices of the given contexts,
+// using the given order, where order[newIndex] == oldIndex.
+func reindexInputSignContexts(ctxs []inputSignContext, order []int) []inputSignContext {
+	newIndices := make(map[int]int, len(order))
+	for newIndex, oldIndex := range order {
+		newIndices[oldIndex] = newIndex
+	}
+	for i := range ctxs {
+		ctxs[i].InputIndex = newIndices[ctxs[i].InputIndex]
+	}
+	return ctxs
+}
+
 // ViewTransaction returns a transaction-in-progress along with all of its
 // parents, specified by id. An error is returned if the id is invalid.  Note
 // that ids become invalid for a transaction after 'SignTransaction' has been
 // called because the transaction gets deleted.
 func (tb *transactionBuilder) View() (types.Transaction, []types.Transaction) {
-	return tb.transaction, tb.parents
+	return tb.builder.Transaction(), tb.parents
 }
 
 // ViewAdded returns all of the siacoin inputs, siafund inputs, and parent
@@ -672,9 +683,9 @@ func (w *Wallet) RegisterTransaction(t types.Transaction, parents []types.Transa
 		build.Severe("Failed to decode transaction: " + err.Error())
 	}
 	return &transactionBuilder{
-		parents:     pCopy,
-		transaction: tCopy,
-		wallet:      w,
+		parents: pCopy,
+		builder: txbuilder.FromTransaction(tCopy),
+		wallet:  w,
 	}
 }
 
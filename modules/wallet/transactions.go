@@ -232,6 +232,9 @@ func (w *Wallet) CreateRawTransaction(coids []types.CoinOutputID, bsoids []types
 	if uint64(len(arb)) > w.chainCts.ArbitraryDataSizeLimit {
 		return types.Transaction{}, errors.New("Arbitrary data too large")
 	}
+	if err := types.ValidateArbitraryData(arb, w.chainCts.ArbitraryDataSizeLimit, w.chainCts.AllowedArbitraryDataTypes); err != nil {
+		return types.Transaction{}, err
+	}
 
 	txnBuilder := w.StartTransaction()
 	// No need to drop the builder since we manually added inputs,
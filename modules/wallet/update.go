@@ -83,6 +83,26 @@ func (w *Wallet) updateConfirmedSet(cc modules.ConsensusChange) {
 	}
 
 	for _, diff := range cc.BlockStakeOutputDiffs {
+		// check if we are the staker of a cold staking output,
+		// in which case we can stake with it, whether or not we own it as well
+		staker, isColdStaking := getColdStakingStaker(diff.BlockStakeOutput.Condition.Condition)
+		if isColdStaking {
+			if _, exists := w.keys[staker]; exists {
+				_, exists = w.coldStakingBlockStakeOutputs[diff.ID]
+				if diff.Direction == modules.DiffApply {
+					if exists {
+						build.Severe("adding an existing cold staking output to wallet")
+					}
+					w.coldStakingBlockStakeOutputs[diff.ID] = diff.BlockStakeOutput
+				} else {
+					if !exists {
+						build.Severe("deleting nonexisting cold staking output from wallet")
+					}
+					delete(w.coldStakingBlockStakeOutputs, diff.ID)
+				}
+			}
+		}
+
 		// Verify that the diff is relevant to the wallet.
 		if _, exists := w.keys[diff.BlockStakeOutput.Condition.UnlockHash()]; exists {
 
@@ -100,6 +120,9 @@ func (w *Wallet) updateConfirmedSet(cc modules.ConsensusChange) {
 			}
 			continue
 		}
+		if isColdStaking {
+			continue
+		}
 
 		// try to get the unlock hash slice of a multisig
 		unlockhashes, _ := getMultisigConditionProperties(diff.BlockStakeOutput.Condition.Condition)
@@ -136,7 +159,7 @@ func getMultisigConditionProperties(condition types.MarshalableUnlockCondition)
 		}
 		return getMultisigConditionProperties(cg.GetMarshalableUnlockCondition())
 	}
-	if ct != types.ConditionTypeMultiSignature {
+	if ct != types.ConditionTypeMultiSignature && ct != types.ConditionTypeWeightedMultiSignature {
 		return nil, 0
 	}
 	type multisigCondition interface {
@@ -151,6 +174,23 @@ func getMultisigConditionProperties(condition types.MarshalableUnlockCondition)
 	}
 }
 
+// getColdStakingStaker returns the unlock hash of the staker,
+// in case the given condition is a cold staking condition.
+func getColdStakingStaker(condition types.MarshalableUnlockCondition) (types.UnlockHash, bool) {
+	if condition == nil || condition.ConditionType() != types.ConditionTypeColdStaking {
+		return types.UnlockHash{}, false
+	}
+	type coldStakingCondition interface {
+		StakerUnlockHash() types.UnlockHash
+	}
+	c, ok := condition.(coldStakingCondition)
+	if !ok {
+		build.Severe(fmt.Sprintf("unexpected Go-type for ColdStakingCondition: %T", condition))
+		return types.UnlockHash{}, false
+	}
+	return c.StakerUnlockHash(), true
+}
+
 // revertHistory reverts any transaction history that was destroyed by reverted
 // blocks in the consensus change.
 func (w *Wallet) revertHistory(cc modules.ConsensusChange) {
@@ -268,6 +308,7 @@ func (w *Wallet) applyHistory(cc modules.ConsensusChange) {
 					WalletAddress:  exists,
 					RelatedAddress: uh,
 					Value:          sco.Value,
+					Metadata:       sco.Metadata,
 				})
 				w.historicOutputs[types.OutputID(txn.CoinOutputID(uint64(i)))] = historicOutput{
 					UnlockHash: uh,
@@ -312,9 +353,13 @@ func (w *Wallet) applyHistory(cc modules.ConsensusChange) {
 					WalletAddress:  exists,
 					RelatedAddress: uh,
 					Value:          sfo.Value,
+					Metadata:       sfo.Metadata,
 				})
 				bsoid := txn.BlockStakeOutputID(uint64(i))
 				_, exists = w.blockstakeOutputs[bsoid]
+				if !exists {
+					_, exists = w.coldStakingBlockStakeOutputs[bsoid]
+				}
 				if exists {
 					w.unspentblockstakeoutputs[bsoid] = types.UnspentBlockStakeOutput{
 						BlockStakeOutputID: bsoid,
@@ -418,6 +463,7 @@ func (w *Wallet) ReceiveUpdatedUnconfirmedTransactions(txns []types.Transaction,
 				WalletAddress:  exists,
 				RelatedAddress: uh,
 				Value:          sco.Value,
+				Metadata:       sco.Metadata,
 			})
 			w.historicOutputs[types.OutputID(txn.CoinOutputID(uint64(i)))] = historicOutput{
 				UnlockHash: uh,
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// TestUpdateConfirmedSetColdStaking checks that a cold staking output is tracked
+// as owned by the owner, and as stakeable by the staker, once only if both are part of the wallet.
+func TestUpdateConfirmedSetColdStaking(t *testing.T) {
+	owner := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{1})
+	staker := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{2})
+	bso := types.BlockStakeOutput{
+		Value:     types.NewCurrency64(10),
+		Condition: types.NewCondition(types.NewColdStakingCondition(owner, staker)),
+	}
+	id := types.BlockStakeOutputID{1}
+
+	testCases := []struct {
+		name              string
+		keys              []types.UnlockHash
+		owned, stakeable  bool
+		expectedBalance   uint64
+		expectedStakeable int
+	}{
+		{"owner", []types.UnlockHash{owner}, true, false, 10, 1},
+		{"staker", []types.UnlockHash{staker}, false, true, 0, 1},
+		{"owner and staker", []types.UnlockHash{owner, staker}, true, true, 10, 1},
+	}
+	for _, testCase := range testCases {
+		cs := newConsensusSetStub()
+		w := &Wallet{
+			cs:                           cs,
+			unlocked:                     true,
+			keys:                         make(map[types.UnlockHash]spendableKey),
+			coinOutputs:                  make(map[types.CoinOutputID]types.CoinOutput),
+			blockstakeOutputs:            make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+			multiSigCoinOutputs:          make(map[types.CoinOutputID]types.CoinOutput),
+			multiSigBlockStakeOutputs:    make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+			coldStakingBlockStakeOutputs: make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+			unspentblockstakeoutputs: map[types.BlockStakeOutputID]types.UnspentBlockStakeOutput{
+				id: {BlockStakeOutputID: id, Value: bso.Value, Condition: bso.Condition},
+			},
+		}
+		for _, uh := range testCase.keys {
+			w.keys[uh] = spendableKey{}
+		}
+
+		w.updateConfirmedSet(modules.ConsensusChange{BlockStakeOutputDiffs: []modules.BlockStakeOutputDiff{
+			{Direction: modules.DiffApply, ID: id, BlockStakeOutput: bso},
+		}})
+		if _, ok := w.blockstakeOutputs[id]; ok != testCase.owned {
+			t.Errorf("%s: expected output to be owned: %v", testCase.name, testCase.owned)
+		}
+		if _, ok := w.coldStakingBlockStakeOutputs[id]; ok != testCase.stakeable {
+			t.Errorf("%s: expected output to be stakeable as staker: %v", testCase.name, testCase.stakeable)
+		}
+		_, balance, err := w.ConfirmedBalance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !balance.Equals64(testCase.expectedBalance) {
+			t.Errorf("%s: expected block stake balance %d, not %v", testCase.name, testCase.expectedBalance, balance)
+		}
+		unspent, err := w.GetUnspentBlockStakeOutputs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(unspent) != testCase.expectedStakeable {
+			t.Errorf("%s: expected %d stakeable outputs, not %d", testCase.name, testCase.expectedStakeable, len(unspent))
+		}
+
+		w.updateConfirmedSet(modules.ConsensusChange{BlockStakeOutputDiffs: []modules.BlockStakeOutputDiff{
+			{Direction: modules.DiffRevert, ID: id, BlockStakeOutput: bso},
+		}})
+		if len(w.blockstakeOutputs) != 0 || len(w.coldStakingBlockStakeOutputs) != 0 {
+			t.Errorf("%s: reverted output is still tracked", testCase.name)
+		}
+	}
+}
@@ -91,6 +91,11 @@ type Wallet struct {
 	multiSigCoinOutputs       map[types.CoinOutputID]types.CoinOutput
 	multiSigBlockStakeOutputs map[types.BlockStakeOutputID]types.BlockStakeOutput
 
+	// coldStakingBlockStakeOutputs holds all cold staking block stake outputs
+	// which can be used by this wallet for block creation, as their staker.
+	// Those which are owned by this wallet as well are also part of blockstakeOutputs.
+	coldStakingBlockStakeOutputs map[types.BlockStakeOutputID]types.BlockStakeOutput
+
 	// The following fields are kept to track transaction history.
 	// processedTransactions are stored in chronological order, and have a map for
 	// constant time random access. The set of full transactions is kept as
@@ -120,6 +125,7 @@ type Wallet struct {
 
 	bcInfo   types.BlockchainInfo
 	chainCts types.ChainConstants
+	chainID  types.ChainID
 }
 
 type historicOutput struct {
@@ -153,6 +159,8 @@ func New(cs modules.ConsensusSet, tpool modules.TransactionPool, persistDir stri
 		multiSigCoinOutputs:       make(map[types.CoinOutputID]types.CoinOutput),
 		multiSigBlockStakeOutputs: make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
 
+		coldStakingBlockStakeOutputs: make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+
 		processedTransactionMap: make(map[types.TransactionID]*modules.ProcessedTransaction),
 
 		historicOutputs: make(map[types.OutputID]historicOutput),
@@ -161,6 +169,7 @@ func New(cs modules.ConsensusSet, tpool modules.TransactionPool, persistDir stri
 
 		bcInfo:   bcInfo,
 		chainCts: chainCts,
+		chainID:  chainCts.ChainID(),
 	}
 	err := w.initPersist(verboseLogging)
 	if err != nil {
@@ -269,6 +278,15 @@ func (w *Wallet) GetUnspentBlockStakeOutputs() (unspent []types.UnspentBlockStak
 			unspent = append(unspent, w.unspentblockstakeoutputs[usbsoid])
 		}
 	}
+	// collect all cold staking block stake outputs we can stake with, but don't own
+	for usbsoid, output := range w.coldStakingBlockStakeOutputs {
+		if _, owned := w.blockstakeOutputs[usbsoid]; owned {
+			continue
+		}
+		if output.Condition.Fulfillable(ctx) {
+			unspent = append(unspent, w.unspentblockstakeoutputs[usbsoid])
+		}
+	}
 	return
 }
 
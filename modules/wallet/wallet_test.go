@@ -660,6 +660,14 @@ func (css *consensusSetStub) GetBlockStakeOutput(id types.BlockStakeOutputID) (b
 	return types.BlockStakeOutput{}, errors.New("BlockStake output not found in database")
 }
 
+func (css *consensusSetStub) BurnedTotals() (coins, blockStakes types.Currency) {
+	return
+}
+
+func (css *consensusSetStub) SupplyStatistics() (stats types.SupplyStatistics) {
+	return
+}
+
 func (css *consensusSetStub) RegisterPlugin(ctx context.Context, name string, plugin modules.ConsensusSetPlugin) (err error) {
 	return nil
 }
@@ -2,7 +2,10 @@ package modules
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
+
+	"github.com/threefoldtech/rivine/types"
 )
 
 // TestSeedMnemonicFunctions tests that
@@ -46,3 +49,45 @@ func TestSeedMnemonicFunctions(t *testing.T) {
 		}
 	}
 }
+
+func TestEarningsIntervalPeriodStart(t *testing.T) {
+	// Wednesday 2019-01-16 13:14:15 UTC
+	const ts = types.Timestamp(1547644455)
+	testCases := []struct {
+		Interval EarningsInterval
+		Expected types.Timestamp
+	}{
+		{EarningsIntervalDay, 1547596800},   // 2019-01-16
+		{EarningsIntervalWeek, 1547424000},  // Monday 2019-01-14
+		{EarningsIntervalMonth, 1546300800}, // 2019-01-01
+	}
+	for _, testCase := range testCases {
+		if start := testCase.Interval.PeriodStart(ts); start != testCase.Expected {
+			t.Errorf("unexpected period start for interval %s: %d != %d", testCase.Interval.String(), start, testCase.Expected)
+		}
+		var interval EarningsInterval
+		if err := interval.LoadString(testCase.Interval.String()); err != nil || interval != testCase.Interval {
+			t.Errorf("failed to load interval %s: %v", testCase.Interval.String(), err)
+		}
+	}
+}
+
+func TestPaymentChannelRoleJSON(t *testing.T) {
+	for _, role := range []PaymentChannelRole{PaymentChannelRoleSender, PaymentChannelRoleReceiver} {
+		b, err := json.Marshal(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := `"` + role.String() + `"`; string(b) != expected {
+			t.Errorf("unexpected JSON encoding for role %s: %s != %s", role.String(), string(b), expected)
+		}
+		var decoded PaymentChannelRole
+		if err = json.Unmarshal(b, &decoded); err != nil || decoded != role {
+			t.Errorf("failed to decode role %s: %v", role.String(), err)
+		}
+	}
+	var role PaymentChannelRole
+	if err := role.LoadString("unknown"); err == nil {
+		t.Error("loaded an unknown payment channel role")
+	}
+}
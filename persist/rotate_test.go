@@ -0,0 +1,70 @@
+package persist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/threefoldtech/rivine/build"
+)
+
+// TestRotatingFile checks that a rotating file is rotated once it exceeds its maximum size,
+// only keeping the configured amount of rotated files.
+func TestRotatingFile(t *testing.T) {
+	testdir := build.TempDir(persistDir, t.Name())
+	err := os.MkdirAll(testdir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(testdir, "access.log")
+
+	rf, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gggg\n"} {
+		if _, err = rf.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for suffix, expected := range map[string]string{
+		"":   "gggg\n",
+		".1": "eeee\nffff\n",
+		".2": "cccc\ndddd\n",
+	} {
+		b, err := ioutil.ReadFile(path + suffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Errorf("unexpected content of %q: %q != %q", path+suffix, b, expected)
+		}
+	}
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected the oldest rotated file to be removed: %v", err)
+	}
+
+	// reopening the file appends to it, taking its existing size into account
+	rf, err = NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rf.Write([]byte("hhhh\nii\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err = rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "gggg\n" {
+		t.Errorf("expected the reopened file to be rotated, got %q", b)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AccessLogEntry is a single entry of the API access log, logged as a JSON object per line.
+type AccessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestid"`
+	Method    string    `json:"method"`
+	// Path is the requested path, excluding the query string
+	Path string `json:"path"`
+	// Route is the route which handled the request (e.g. /explorer/hashes/:hash),
+	// omitted in case the request did not match any route
+	Route string `json:"route,omitempty"`
+	// RemoteAddr is the IP address of the caller
+	RemoteAddr string `json:"remoteaddr"`
+	// Token is the name of the API token the caller authenticated with, if any
+	Token     string `json:"token,omitempty"`
+	UserAgent string `json:"useragent,omitempty"`
+	Status    int    `json:"status"`
+	// Bytes is the size of the response body
+	Bytes int64 `json:"bytes"`
+	// Latency is the time it took to handle the request, in milliseconds
+	Latency float64 `json:"latencyms"`
+}
+
+// AccessLogger logs all requests to the API as structured (JSON) access log entries.
+type AccessLogger struct {
+	auth *APIAuthenticator
+
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewAccessLogger creates an AccessLogger writing to the given writer,
+// using the (optional) authenticator to log the API token used by each caller.
+func NewAccessLogger(w io.Writer, auth *APIAuthenticator) *AccessLogger {
+	return &AccessLogger{
+		auth:    auth,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Log writes a single entry to the access log.
+func (al *AccessLogger) Log(entry AccessLogEntry) {
+	al.mu.Lock()
+	al.encoder.Encode(entry) // ignore error, logging should never fail a request
+	al.mu.Unlock()
+}
+
+// AccessLogHandler is middleware which logs every request to the given AccessLogger,
+// identifying each request by its request ID (see RequestIDHandler). No requests are logged if the logger is nil.
+func AccessLogHandler(h http.Handler, al *AccessLogger) http.Handler {
+	if al == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		req, id := withRequestID(w, req)
+
+		record := &accessLogRecord{}
+		rec := &statusResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rec, req.WithContext(context.WithValue(req.Context(), accessLogContextKey{}, record)))
+
+		entry := AccessLogEntry{
+			Time:       start.UTC(),
+			RequestID:  id,
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Route:      record.route,
+			RemoteAddr: clientIP(req),
+			UserAgent:  req.UserAgent(),
+			Status:     rec.status,
+			Bytes:      rec.bytes,
+			Latency:    float64(time.Since(start)) / float64(time.Millisecond),
+		}
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		if al.auth != nil {
+			entry.Token, _ = al.auth.TokenName(req)
+		}
+		al.Log(entry)
+	})
+}
+
+// accessLogRecord collects the information of a request only known by the handler of the request
+type accessLogRecord struct {
+	route string
+}
+
+type accessLogContextKey struct{}
+
+// setAccessLogRoute records the route which handles the request, only the first (outer) route being recorded,
+// such that for example the calls executed as part of a batch are logged as part of the batch route.
+func setAccessLogRoute(req *http.Request, route string) {
+	if record, ok := req.Context().Value(accessLogContextKey{}).(*accessLogRecord); ok && record.route == "" {
+		record.route = route
+	}
+}
+
+// statusResponseWriter records the status and size of a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (rec *statusResponseWriter) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.Write
+func (rec *statusResponseWriter) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher.Flush
+func (rec *statusResponseWriter) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.Hijack,
+// logging hijacked (e.g. websocket) connections as switching protocols.
+func (rec *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	if rec.status == 0 {
+		rec.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
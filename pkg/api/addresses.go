@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// AddressTransactionsGET is the object returned by a GET request to
+// /addresses/:unlockhash/transactions, containing a page of the confirmed
+// transactions touching the unlock hash, in chronological order.
+type AddressTransactionsGET struct {
+	UnlockHash   types.UnlockHash      `json:"unlockhash"`
+	Transactions []ExplorerTransaction `json:"transactions"`
+	PageInfo
+}
+
+// RegisterAddressHTTPHandlers registers the default Rivine handlers for the address HTTP endpoints,
+// which are backed by the unlock hash index maintained by the explorer module.
+func RegisterAddressHTTPHandlers(router Router, explorer modules.Explorer) {
+	if explorer == nil {
+		build.Critical("no explorer module given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/addresses/:unlockhash/transactions", NewAddressTransactionsHandler(explorer))
+}
+
+// addressTransaction locates a transaction touching an unlock hash within the chain.
+type addressTransaction struct {
+	block  types.Block
+	height types.BlockHeight
+	index  int
+}
+
+// locateAddressTransactions locates all confirmed transactions touching the unlock hash
+// within the given height range (a zero maxHeight meaning no maximum), in chronological order.
+// The blocks which are indexed for the miner payouts they pay to the unlock hash are skipped.
+func locateAddressTransactions(explorer modules.Explorer, uh types.UnlockHash, minHeight, maxHeight types.BlockHeight) []addressTransaction {
+	var located []addressTransaction
+	for _, txid := range explorer.UnlockHash(uh) {
+		block, height, exists := explorer.Transaction(txid)
+		if !exists {
+			build.Severe("explorer pointing to nonexistent txn")
+			continue
+		}
+		if height < minHeight || (maxHeight != 0 && height > maxHeight) {
+			continue
+		}
+		if types.TransactionID(block.ID()) == txid {
+			continue
+		}
+		for index, txn := range block.Transactions {
+			if txn.ID() == txid {
+				located = append(located, addressTransaction{block: block, height: height, index: index})
+				break
+			}
+		}
+	}
+	sort.Slice(located, func(i, j int) bool {
+		if located[i].height != located[j].height {
+			return located[i].height < located[j].height
+		}
+		return located[i].index < located[j].index
+	})
+	return located
+}
+
+// NewAddressTransactionsHandler creates a handler to handle API calls to GET /addresses/:unlockhash/transactions,
+// returning a page of all confirmed transactions touching the unlock hash, optionally within a height range.
+func NewAddressTransactionsHandler(explorer modules.Explorer) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		uh, err := ScanRequestAddress(req, ps.ByName("unlockhash"))
+		if err != nil {
+			WriteError(w, Error{Message: "invalid unlock hash: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		pagination, err := ParsePagination(req, DefaultPaginationLimit, MaxPaginationLimit)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		minHeight, maxHeight := types.BlockHeight(0), types.BlockHeight(0)
+		if str := req.FormValue("minheight"); str != "" {
+			n, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				WriteError(w, Error{Message: "invalid minheight filter: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			minHeight = types.BlockHeight(n)
+		}
+		if str := req.FormValue("maxheight"); str != "" {
+			n, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				WriteError(w, Error{Message: "invalid maxheight filter: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			maxHeight = types.BlockHeight(n)
+		}
+
+		located := locateAddressTransactions(explorer, uh, minHeight, maxHeight)
+		if pagination.Order == PaginationOrderDescending {
+			for i, j := 0, len(located)-1; i < j; i, j = i+1, j-1 {
+				located[i], located[j] = located[j], located[i]
+			}
+		}
+
+		// only build the explorer transactions of the requested page
+		total := uint64(len(located))
+		first, last := pagination.Bounds(total)
+		txns := make([]ExplorerTransaction, 0, last-first)
+		for _, lt := range located[first:last] {
+			txns = append(txns, BuildExplorerTransaction(explorer, lt.height, lt.block.ID(), lt.block.Transactions[lt.index]))
+		}
+		WriteJSON(w, AddressTransactionsGET{
+			UnlockHash:   uh,
+			Transactions: txns,
+			PageInfo:     pagination.PageInfo(total),
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// addressesTestExplorer is an explorer only implementing the methods
+// used to look up the transactions of an unlock hash.
+type addressesTestExplorer struct {
+	modules.Explorer
+	blocks       map[types.TransactionID]types.Block
+	heights      map[types.TransactionID]types.BlockHeight
+	transactions []types.TransactionID
+}
+
+func (explorer *addressesTestExplorer) UnlockHash(types.UnlockHash) []types.TransactionID {
+	return explorer.transactions
+}
+
+func (explorer *addressesTestExplorer) Transaction(id types.TransactionID) (types.Block, types.BlockHeight, bool) {
+	block, ok := explorer.blocks[id]
+	return block, explorer.heights[id], ok
+}
+
+func TestAddressTransactionsHandler(t *testing.T) {
+	explorer := &addressesTestExplorer{
+		blocks:  make(map[types.TransactionID]types.Block),
+		heights: make(map[types.TransactionID]types.BlockHeight),
+	}
+	// 3 blocks with 2 transactions each, indexing the transactions
+	// in reverse order, as well as the first block for its miner payout
+	var expected []types.TransactionID
+	for height := types.BlockHeight(1); height <= 3; height++ {
+		block := types.Block{Timestamp: types.Timestamp(height)}
+		for i := byte(0); i < 2; i++ {
+			block.Transactions = append(block.Transactions, types.Transaction{
+				Version:       types.TransactionVersionOne,
+				ArbitraryData: []byte{byte(height), i},
+			})
+		}
+		for _, txn := range block.Transactions {
+			id := txn.ID()
+			explorer.blocks[id], explorer.heights[id] = block, height
+			explorer.transactions = append([]types.TransactionID{id}, explorer.transactions...)
+			expected = append(expected, id)
+		}
+		if height == 1 {
+			id := types.TransactionID(block.ID())
+			explorer.blocks[id], explorer.heights[id] = block, height
+			explorer.transactions = append(explorer.transactions, id)
+		}
+	}
+	router := httprouter.New()
+	RegisterAddressHTTPHandlers(router, explorer)
+	uh := types.UnlockHash{Type: types.UnlockTypePubKey, Hash: crypto.Hash{1}}
+
+	testCases := []struct {
+		query    string
+		indices  []int
+		pageInfo PageInfo
+	}{
+		{"", []int{0, 1, 2, 3, 4, 5}, PageInfo{Limit: 50, Order: PaginationOrderAscending, Total: 6}},
+		{"limit=2&offset=1", []int{1, 2}, PageInfo{Offset: 1, Limit: 2, Order: PaginationOrderAscending, Total: 6}},
+		{"limit=2&order=desc", []int{5, 4}, PageInfo{Limit: 2, Order: PaginationOrderDescending, Total: 6}},
+		{"minheight=2&maxheight=2", []int{2, 3}, PageInfo{Limit: 50, Order: PaginationOrderAscending, Total: 2}},
+		{"minheight=3", []int{4, 5}, PageInfo{Limit: 50, Order: PaginationOrderAscending, Total: 2}},
+	}
+	for _, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/addresses/"+uh.String()+"/transactions?"+testCase.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected status %d, not %d: %s", testCase.query, http.StatusOK, rec.Code, rec.Body.String())
+			continue
+		}
+		var resp AddressTransactionsGET
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.UnlockHash != uh || resp.PageInfo != testCase.pageInfo {
+			t.Errorf("%s: unexpected response: %v: %+v", testCase.query, resp.UnlockHash, resp.PageInfo)
+		}
+		if len(resp.Transactions) != len(testCase.indices) {
+			t.Errorf("%s: expected %d transactions, not %d", testCase.query, len(testCase.indices), len(resp.Transactions))
+			continue
+		}
+		for i, txn := range resp.Transactions {
+			if index := testCase.indices[i]; txn.ID != expected[index] || txn.Height != types.BlockHeight(index/2+1) {
+				t.Errorf("%s: unexpected transaction #%d: %v at height %d", testCase.query, i, txn.ID, txn.Height)
+			}
+		}
+	}
+
+	for _, path := range []string{
+		"/addresses/invalid/transactions",
+		"/addresses/" + uh.String() + "/transactions?minheight=x",
+		"/addresses/" + uh.String() + "/transactions?maxheight=-1",
+		"/addresses/" + uh.String() + "/transactions?limit=0",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", path, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestBech32Router(t *testing.T) {
+	explorer := &addressesTestExplorer{}
+	mux := httprouter.New()
+	RegisterAddressHTTPHandlers(NewBech32Router(mux, "riv"), explorer)
+	RegisterAddressHTTPHandlers(NewBech32Router(NewPrefixedRouter(mux, "/networks/test"), "rivt"), explorer)
+	uh := types.UnlockHash{Type: types.UnlockTypePubKey, Hash: crypto.Hash{1}}
+	primary, err := uh.Bech32String("riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	staking, err := uh.Bech32String("rivt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each network only accepts its own bech32 addresses, next to hex addresses
+	testCases := []struct {
+		path string
+		code int
+	}{
+		{"/addresses/" + uh.String() + "/transactions", http.StatusOK},
+		{"/addresses/" + primary + "/transactions", http.StatusOK},
+		{"/addresses/" + staking + "/transactions", http.StatusBadRequest},
+		{"/networks/test/addresses/" + uh.String() + "/transactions", http.StatusOK},
+		{"/networks/test/addresses/" + staking + "/transactions", http.StatusOK},
+		{"/networks/test/addresses/" + primary + "/transactions", http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", testCase.path, nil))
+		if rec.Code != testCase.code {
+			t.Errorf("%s: expected status %d, not %d: %s", testCase.path, testCase.code, rec.Code, rec.Body.String())
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var resp AddressTransactionsGET
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.UnlockHash != uh {
+			t.Errorf("%s: unexpected unlock hash: %v", testCase.path, resp.UnlockHash)
+		}
+	}
+
+	// no bech32 addresses are accepted without a prefix
+	mux = httprouter.New()
+	RegisterAddressHTTPHandlers(NewBech32Router(mux, ""), explorer)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/addresses/"+primary+"/transactions", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, not %d", http.StatusBadRequest, rec.Code)
+	}
+}
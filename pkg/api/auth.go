@@ -0,0 +1,409 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/NebulousLabs/fastrand"
+	"github.com/julienschmidt/httprouter"
+)
+
+// APIScope defines the group of routes an API token grants access to.
+type APIScope string
+
+// All API scopes, each scope includes the scopes listed before it.
+const (
+	// APIScopeReadOnly grants access to routes which expose sensitive information,
+	// but do not modify any state.
+	APIScopeReadOnly APIScope = "read-only"
+	// APIScopeWalletSpend grants access to routes which create, sign and send transactions.
+	APIScopeWalletSpend APIScope = "wallet-spend"
+	// APIScopeAdmin grants access to all routes, including the ones
+	// exposing wallet secrets and the ones managing the daemon (and its API tokens).
+	APIScopeAdmin APIScope = "admin"
+)
+
+var apiScopeLevels = map[APIScope]int{
+	APIScopeReadOnly:    1,
+	APIScopeWalletSpend: 2,
+	APIScopeAdmin:       3,
+}
+
+// Includes returns true if this scope grants access to the routes of the given scope.
+func (scope APIScope) Includes(other APIScope) bool {
+	level, ok := apiScopeLevels[scope]
+	return ok && level >= apiScopeLevels[other]
+}
+
+// Validate returns an error if the scope is unknown.
+func (scope APIScope) Validate() error {
+	if _, ok := apiScopeLevels[scope]; !ok {
+		return fmt.Errorf("unknown API scope %q", scope)
+	}
+	return nil
+}
+
+// APIToken is a named credential, granting access to the API routes of its scopes.
+type APIToken struct {
+	Name   string     `json:"name"`
+	Scopes []APIScope `json:"scopes"`
+	// Secret is only returned when the token is created,
+	// only its hash is stored by the daemon
+	Secret       string          `json:"secret,omitempty"`
+	CreationTime types.Timestamp `json:"creationtime"`
+}
+
+// Includes returns true if any scope of the token grants access to the routes of the given scope.
+func (token APIToken) Includes(scope APIScope) bool {
+	for _, s := range token.Scopes {
+		if s.Includes(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+type apiTokenPersist struct {
+	APIToken
+	SecretHash crypto.Hash `json:"secrethash"`
+}
+
+var apiTokensPersistMetadata = persist.Metadata{
+	Header:  "Rivine API Tokens",
+	Version: "1.0.0",
+}
+
+// errors returned by the APIAuthenticator
+var (
+	ErrAPITokenNotFound = errors.New("API token not found")
+	ErrAPITokenExists   = errors.New("API token already exists")
+)
+
+// APIAuthenticator authenticates API requests, using either the API password,
+// which grants admin access, or one of the named API tokens, each granting access to the routes of its scopes.
+// The credentials can be given using HTTP basic authentication (using the password or token secret as password),
+// or as a bearer token. API tokens can be created and revoked at runtime and are persisted on disk.
+//
+// Authentication is disabled in case neither an API password nor an API token is defined.
+type APIAuthenticator struct {
+	password    string
+	persistFile string
+
+	mu     sync.RWMutex
+	tokens map[string]apiTokenPersist
+	hashes map[crypto.Hash]string
+}
+
+// NewAPIAuthenticator creates a new APIAuthenticator,
+// loading the API tokens from the given file, if it exists.
+// Authentication is disabled if the given password is empty, as long as no API token is defined.
+func NewAPIAuthenticator(password, persistFile string) (*APIAuthenticator, error) {
+	auth := NewPasswordAPIAuthenticator(password)
+	auth.persistFile = persistFile
+	if persistFile == "" {
+		return auth, nil
+	}
+	var tokens []apiTokenPersist
+	err := persist.LoadJSON(apiTokensPersistMetadata, &tokens, persistFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return auth, nil
+		}
+		return nil, fmt.Errorf("failed to load API tokens: %v", err)
+	}
+	for _, token := range tokens {
+		auth.tokens[token.Name] = token
+		auth.hashes[token.SecretHash] = token.Name
+	}
+	return auth, nil
+}
+
+// NewPasswordAPIAuthenticator creates a new APIAuthenticator which authenticates
+// using the given API password only, without (persisted) API tokens.
+// It can be used to register the HTTP handlers which used to take the API password,
+// prior to the introduction of API tokens, e.g.:
+//
+//	api.RegisterWalletHTTPHandlers(router, wallet, api.NewPasswordAPIAuthenticator(password))
+//
+// Authentication is disabled if the given password is empty, as long as no API token is created.
+func NewPasswordAPIAuthenticator(password string) *APIAuthenticator {
+	return &APIAuthenticator{
+		password: password,
+		tokens:   make(map[string]apiTokenPersist),
+		hashes:   make(map[crypto.Hash]string),
+	}
+}
+
+// Enabled returns true if API authentication is enabled,
+// meaning an API password or at least one API token is defined.
+func (auth *APIAuthenticator) Enabled() bool {
+	if auth == nil {
+		return false
+	}
+	if auth.password != "" {
+		return true
+	}
+	auth.mu.RLock()
+	defer auth.mu.RUnlock()
+	return len(auth.tokens) > 0
+}
+
+// isPassword returns true if the given secret is the (defined) API password,
+// comparing it in constant time.
+func (auth *APIAuthenticator) isPassword(secret string) bool {
+	return auth.password != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(auth.password)) == 1
+}
+
+// CreateToken creates a new API token with the given (unique) name and scopes.
+// The returned token is the only one containing the secret.
+func (auth *APIAuthenticator) CreateToken(name string, scopes []APIScope) (APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return APIToken{}, errors.New("API token name cannot be empty")
+	}
+	if len(scopes) == 0 {
+		return APIToken{}, errors.New("API token requires at least one scope")
+	}
+	for _, scope := range scopes {
+		if err := scope.Validate(); err != nil {
+			return APIToken{}, err
+		}
+	}
+
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+	if _, ok := auth.tokens[name]; ok {
+		return APIToken{}, ErrAPITokenExists
+	}
+	token := APIToken{
+		Name:         name,
+		Scopes:       scopes,
+		Secret:       hex.EncodeToString(fastrand.Bytes(32)),
+		CreationTime: types.CurrentTimestamp(),
+	}
+	pt := apiTokenPersist{
+		APIToken: APIToken{
+			Name:         token.Name,
+			Scopes:       token.Scopes,
+			CreationTime: token.CreationTime,
+		},
+		SecretHash: crypto.HashBytes([]byte(token.Secret)),
+	}
+	auth.tokens[name] = pt
+	auth.hashes[pt.SecretHash] = name
+	if err := auth.save(); err != nil {
+		delete(auth.tokens, name)
+		delete(auth.hashes, pt.SecretHash)
+		return APIToken{}, err
+	}
+	return token, nil
+}
+
+// RevokeToken revokes the API token with the given name,
+// such that it can no longer be used to authenticate.
+func (auth *APIAuthenticator) RevokeToken(name string) error {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+	token, ok := auth.tokens[name]
+	if !ok {
+		return ErrAPITokenNotFound
+	}
+	delete(auth.tokens, name)
+	delete(auth.hashes, token.SecretHash)
+	if err := auth.save(); err != nil {
+		auth.tokens[name] = token
+		auth.hashes[token.SecretHash] = name
+		return err
+	}
+	return nil
+}
+
+// Tokens returns all API tokens, sorted by name and without their secrets.
+func (auth *APIAuthenticator) Tokens() []APIToken {
+	auth.mu.RLock()
+	defer auth.mu.RUnlock()
+	tokens := make([]APIToken, 0, len(auth.tokens))
+	for _, token := range auth.tokens {
+		tokens = append(tokens, token.APIToken)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Name < tokens[j].Name
+	})
+	return tokens
+}
+
+// save persists the API tokens, the lock has to be held by the caller.
+func (auth *APIAuthenticator) save() error {
+	if auth.persistFile == "" {
+		return nil
+	}
+	tokens := make([]apiTokenPersist, 0, len(auth.tokens))
+	for _, token := range auth.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Name < tokens[j].Name
+	})
+	if err := os.MkdirAll(filepath.Dir(auth.persistFile), 0700); err != nil {
+		return fmt.Errorf("failed to create API tokens directory: %v", err)
+	}
+	if err := persist.SaveJSON(apiTokensPersistMetadata, tokens, auth.persistFile); err != nil {
+		return fmt.Errorf("failed to save API tokens: %v", err)
+	}
+	return nil
+}
+
+// Authorize returns true if the request is authenticated
+// with credentials granting access to the routes of the given scope.
+func (auth *APIAuthenticator) Authorize(req *http.Request, scope APIScope) bool {
+	if !auth.Enabled() {
+		return true
+	}
+	secret, ok := requestSecret(req)
+	if !ok {
+		return false
+	}
+	if auth.isPassword(secret) {
+		return true
+	}
+	auth.mu.RLock()
+	defer auth.mu.RUnlock()
+	name, ok := auth.hashes[crypto.HashBytes([]byte(secret))]
+	if !ok {
+		return false
+	}
+	return auth.tokens[name].Includes(scope)
+}
+
+// TokenName returns the name of the API token the request is authenticated with,
+// and false in case the request isn't authenticated using a (valid) API token.
+func (auth *APIAuthenticator) TokenName(req *http.Request) (string, bool) {
+	if !auth.Enabled() {
+		return "", false
+	}
+	secret, ok := requestSecret(req)
+	if !ok || auth.isPassword(secret) {
+		return "", false
+	}
+	auth.mu.RLock()
+	defer auth.mu.RUnlock()
+	name, ok := auth.hashes[crypto.HashBytes([]byte(secret))]
+	return name, ok
+}
+
+// requestSecret returns the password or token secret,
+// given as HTTP basic auth password or as bearer token.
+func requestSecret(req *http.Request) (string, bool) {
+	if _, pass, ok := req.BasicAuth(); ok {
+		return pass, true
+	}
+	const prefix = "Bearer "
+	authorization := req.Header.Get("Authorization")
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return authorization[len(prefix):], true
+	}
+	return "", false
+}
+
+// RequireScopeHandler is middleware that requires a request to authenticate
+// with credentials granting access to the routes of the given scope.
+// No authentication is required in case the authenticator is nil or disabled,
+// the latter being checked for each request, as API tokens can be created at runtime.
+func RequireScopeHandler(h httprouter.Handle, auth *APIAuthenticator, scope APIScope) httprouter.Handle {
+	if auth == nil {
+		return h
+	}
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if !auth.Authorize(req, scope) {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"SiaAPI\"")
+			WriteError(w, Error{Message: "API Basic authentication failed."}, http.StatusUnauthorized)
+			return
+		}
+		h(w, req, ps)
+	}
+}
+
+type (
+	// DaemonTokensGET contains the fields returned by a GET call to "/daemon/tokens".
+	DaemonTokensGET struct {
+		Tokens []APIToken `json:"tokens"`
+	}
+
+	// DaemonTokensPOST is the body of a POST call to "/daemon/tokens".
+	DaemonTokensPOST struct {
+		Name   string     `json:"name"`
+		Scopes []APIScope `json:"scopes"`
+	}
+)
+
+// RegisterAuthHTTPHandlers registers the default Rivine handlers for the API token management HTTP endpoints,
+// all of which require the admin scope.
+func RegisterAuthHTTPHandlers(router Router, auth *APIAuthenticator) {
+	if auth == nil {
+		build.Critical("no API authenticator given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/daemon/tokens", RequireScopeHandler(NewDaemonTokensHandler(auth), auth, APIScopeAdmin))
+	router.POST("/daemon/tokens", RequireScopeHandler(NewDaemonTokenCreateHandler(auth), auth, APIScopeAdmin))
+	router.POST("/daemon/tokens/:name/revoke", RequireScopeHandler(NewDaemonTokenRevokeHandler(auth), auth, APIScopeAdmin))
+}
+
+// NewDaemonTokensHandler creates a handler to handle API calls to GET /daemon/tokens.
+func NewDaemonTokensHandler(auth *APIAuthenticator) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, DaemonTokensGET{Tokens: auth.Tokens()})
+	}
+}
+
+// NewDaemonTokenCreateHandler creates a handler to handle API calls to POST /daemon/tokens.
+func NewDaemonTokenCreateHandler(auth *APIAuthenticator) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body DaemonTokensPOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied API token: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		token, err := auth.CreateToken(body.Name, body.Scopes)
+		if err != nil {
+			status := http.StatusBadRequest
+			if err == ErrAPITokenExists {
+				status = http.StatusConflict
+			}
+			WriteError(w, Error{Message: "error after call to /daemon/tokens: " + err.Error()}, status)
+			return
+		}
+		WriteJSON(w, token)
+	}
+}
+
+// NewDaemonTokenRevokeHandler creates a handler to handle API calls to POST /daemon/tokens/:name/revoke.
+func NewDaemonTokenRevokeHandler(auth *APIAuthenticator) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		err := auth.RevokeToken(ps.ByName("name"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == ErrAPITokenNotFound {
+				status = http.StatusNotFound
+			}
+			WriteError(w, Error{Message: "error after call to /daemon/tokens/:name/revoke: " + err.Error()}, status)
+			return
+		}
+		WriteSuccess(w)
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/threefoldtech/rivine/build"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// newAuthTestRouter creates a router with a route for each scope,
+// as well as the API token management routes.
+func newAuthTestRouter(auth *APIAuthenticator) *httprouter.Router {
+	router := httprouter.New()
+	ok := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}
+	router.GET("/read", RequireScopeHandler(ok, auth, APIScopeReadOnly))
+	router.POST("/spend", RequireScopeHandler(ok, auth, APIScopeWalletSpend))
+	router.POST("/admin", RequireScopeHandler(ok, auth, APIScopeAdmin))
+	RegisterAuthHTTPHandlers(router, auth)
+	return router
+}
+
+// authTestRequest serves a request using the given router,
+// authenticating with the given secret as bearer token, if defined.
+func authTestRequest(router http.Handler, method, path, secret string, body interface{}) *httptest.ResponseRecorder {
+	var b bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&b).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &b)
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIAuthenticatorScopes(t *testing.T) {
+	auth := NewPasswordAPIAuthenticator("password")
+	router := newAuthTestRouter(auth)
+
+	tokens := make(map[APIScope]string)
+	for _, scope := range []APIScope{APIScopeReadOnly, APIScopeWalletSpend, APIScopeAdmin} {
+		token, err := auth.CreateToken(string(scope), []APIScope{scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Secret == "" {
+			t.Fatalf("created %s token doesn't define its secret", scope)
+		}
+		tokens[scope] = token.Secret
+	}
+
+	testCases := []struct {
+		method, path string
+		scope        APIScope
+	}{
+		{"GET", "/read", APIScopeReadOnly},
+		{"POST", "/spend", APIScopeWalletSpend},
+		{"POST", "/admin", APIScopeAdmin},
+		{"GET", "/daemon/tokens", APIScopeAdmin},
+	}
+	for _, testCase := range testCases {
+		if rec := authTestRequest(router, testCase.method, testCase.path, "", nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s: expected status %d without credentials, not %d", testCase.method, testCase.path, http.StatusUnauthorized, rec.Code)
+		} else if rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s %s: unauthorized response doesn't define the WWW-Authenticate header", testCase.method, testCase.path)
+		}
+		if rec := authTestRequest(router, testCase.method, testCase.path, "invalid", nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s: expected status %d using an invalid secret, not %d", testCase.method, testCase.path, http.StatusUnauthorized, rec.Code)
+		}
+		for scope, secret := range tokens {
+			expected := http.StatusUnauthorized
+			if scope.Includes(testCase.scope) {
+				expected = http.StatusOK
+			}
+			if rec := authTestRequest(router, testCase.method, testCase.path, secret, nil); rec.Code != expected {
+				t.Errorf("%s %s: expected status %d using a %s token, not %d", testCase.method, testCase.path, expected, scope, rec.Code)
+			}
+		}
+	}
+
+	// a token can be given as HTTP basic auth password as well
+	req := httptest.NewRequest("GET", "/read", nil)
+	req.SetBasicAuth("", tokens[APIScopeReadOnly])
+	if !auth.Authorize(req, APIScopeReadOnly) {
+		t.Error("token given as basic auth password is not authorized")
+	}
+	if name, ok := auth.TokenName(req); !ok || name != string(APIScopeReadOnly) {
+		t.Errorf("unexpected token name: %q (%v)", name, ok)
+	}
+
+	// unknown scopes and duplicate names are rejected
+	if _, err := auth.CreateToken("unknown", []APIScope{"root"}); err == nil {
+		t.Error("created a token with an unknown scope")
+	}
+	if _, err := auth.CreateToken(string(APIScopeAdmin), []APIScope{APIScopeAdmin}); err != ErrAPITokenExists {
+		t.Errorf("expected %v, not: %v", ErrAPITokenExists, err)
+	}
+}
+
+func TestAPIAuthenticatorPasswordFallback(t *testing.T) {
+	auth := NewPasswordAPIAuthenticator("password")
+	router := newAuthTestRouter(auth)
+
+	// the API password grants access to all scopes, using basic auth or as bearer token
+	for _, path := range []string{"/spend", "/admin"} {
+		req := httptest.NewRequest("POST", path, nil)
+		req.SetBasicAuth("", "password")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("POST %s: expected status %d using the API password, not %d", path, http.StatusOK, rec.Code)
+		}
+		if rec := authTestRequest(router, "POST", path, "password", nil); rec.Code != http.StatusOK {
+			t.Errorf("POST %s: expected status %d using the API password as bearer token, not %d", path, http.StatusOK, rec.Code)
+		}
+	}
+	req := httptest.NewRequest("GET", "/read", nil)
+	req.SetBasicAuth("", "password")
+	if _, ok := auth.TokenName(req); ok {
+		t.Error("API password should not be identified as a token")
+	}
+
+	// no authentication is required without an API password
+	disabled := NewPasswordAPIAuthenticator("")
+	if disabled.Enabled() {
+		t.Error("authentication is enabled without API password")
+	}
+	if rec := authTestRequest(newAuthTestRouter(disabled), "POST", "/admin", "", nil); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d with authentication disabled, not %d", http.StatusOK, rec.Code)
+	}
+	var nilAuth *APIAuthenticator
+	if nilAuth.Enabled() {
+		t.Error("nil authenticator should be disabled")
+	}
+}
+
+func TestAPIAuthenticatorTokensWithoutPassword(t *testing.T) {
+	auth := NewPasswordAPIAuthenticator("")
+	router := newAuthTestRouter(auth)
+
+	// authentication is enabled as soon as the first token is created
+	rec := authTestRequest(router, "POST", "/daemon/tokens", "", DaemonTokensPOST{Name: "spend", Scopes: []APIScope{APIScopeWalletSpend}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d creating a token, not %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var token APIToken
+	if err := json.NewDecoder(rec.Body).Decode(&token); err != nil {
+		t.Fatal(err)
+	}
+	if !auth.Enabled() {
+		t.Fatal("authentication is disabled with an API token defined")
+	}
+
+	testCases := []struct {
+		method, path, secret string
+		status               int
+	}{
+		{"POST", "/spend", token.Secret, http.StatusOK},
+		{"POST", "/spend", "", http.StatusUnauthorized},
+		{"POST", "/admin", token.Secret, http.StatusUnauthorized},
+		{"POST", "/daemon/tokens", "", http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		rec := authTestRequest(router, testCase.method, testCase.path, testCase.secret, nil)
+		if rec.Code != testCase.status {
+			t.Errorf("%s %s: expected status %d, not %d", testCase.method, testCase.path, testCase.status, rec.Code)
+		}
+	}
+
+	// the empty API password doesn't grant access
+	req := httptest.NewRequest("POST", "/admin", nil)
+	req.SetBasicAuth("", "")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d using an empty password, not %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestAPIAuthenticatorRevokeAndPersist(t *testing.T) {
+	dir := build.TempDir("api", t.Name())
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	persistFile := filepath.Join(dir, "apitokens.json")
+
+	auth, err := NewAPIAuthenticator("password", persistFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newAuthTestRouter(auth)
+
+	// create two tokens using the API
+	secrets := make(map[string]string)
+	for _, name := range []string{"explorer", "wallet"} {
+		rec := authTestRequest(router, "POST", "/daemon/tokens", "password", DaemonTokensPOST{
+			Name:   name,
+			Scopes: []APIScope{APIScopeWalletSpend},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("failed to create token %s: %d: %s", name, rec.Code, rec.Body.String())
+		}
+		var token APIToken
+		if err = json.NewDecoder(rec.Body).Decode(&token); err != nil {
+			t.Fatal(err)
+		}
+		secrets[name] = token.Secret
+	}
+	if rec := authTestRequest(router, "POST", "/daemon/tokens", "password", DaemonTokensPOST{
+		Name:   "wallet",
+		Scopes: []APIScope{APIScopeReadOnly},
+	}); rec.Code != http.StatusConflict {
+		t.Errorf("expected status %d creating an existing token, not %d", http.StatusConflict, rec.Code)
+	}
+
+	// secrets are not listed, nor persisted
+	rec := authTestRequest(router, "GET", "/daemon/tokens", "password", nil)
+	var list DaemonTokensGET
+	if err = json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Tokens) != 2 || list.Tokens[0].Name != "explorer" || list.Tokens[1].Name != "wallet" {
+		t.Fatalf("unexpected tokens listed: %+v", list.Tokens)
+	}
+	for _, token := range list.Tokens {
+		if token.Secret != "" {
+			t.Errorf("secret of token %s is listed", token.Name)
+		}
+	}
+	b, err := ioutil.ReadFile(persistFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, secret := range secrets {
+		if bytes.Contains(b, []byte(secret)) {
+			t.Errorf("secret of token %s is persisted", name)
+		}
+	}
+
+	// a non-admin token cannot revoke tokens
+	if rec := authTestRequest(router, "POST", "/daemon/tokens/explorer/revoke", secrets["wallet"], nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d revoking a token as non-admin, not %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec := authTestRequest(router, "POST", "/daemon/tokens/explorer/revoke", "password", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("failed to revoke token: %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := authTestRequest(router, "POST", "/daemon/tokens/explorer/revoke", "password", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d revoking an unknown token, not %d", http.StatusNotFound, rec.Code)
+	}
+	if rec := authTestRequest(router, "POST", "/spend", secrets["explorer"], nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d using a revoked token, not %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	// tokens (and their revocation) survive a restart
+	auth, err = NewAPIAuthenticator("password", persistFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router = newAuthTestRouter(auth)
+	if tokens := auth.Tokens(); len(tokens) != 1 || tokens[0].Name != "wallet" {
+		t.Fatalf("unexpected tokens loaded: %+v", tokens)
+	}
+	if rec := authTestRequest(router, "POST", "/spend", secrets["wallet"], nil); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d using a loaded token, not %d", http.StatusOK, rec.Code)
+	}
+	if rec := authTestRequest(router, "POST", "/spend", secrets["explorer"], nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d using a revoked token after a restart, not %d", http.StatusUnauthorized, rec.Code)
+	}
+}
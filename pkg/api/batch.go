@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/threefoldtech/rivine/build"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// MaxBatchRequests is the maximum amount of requests that can be executed as part of a single batch.
+const MaxBatchRequests = 100
+
+type (
+	// BatchRequest is a single API call executed as part of a POST request to /batch.
+	BatchRequest struct {
+		// Method is the HTTP method of the call, GET or POST
+		Method string `json:"method"`
+		// Path is the path of the call, including its query string if any
+		Path string `json:"path"`
+		// Body is the optional body of the call, a JSON string is sent
+		// as a form-encoded body, while any other JSON value is sent as-is as a JSON body
+		Body json.RawMessage `json:"body,omitempty"`
+	}
+
+	// BatchResponse is the response of a single API call executed as part of a POST request to /batch.
+	BatchResponse struct {
+		// Status is the HTTP status code of the response
+		Status int `json:"status"`
+		// Body is the body of the response, omitted for responses without content
+		Body json.RawMessage `json:"body,omitempty"`
+	}
+
+	// BatchPOST is the body of a POST request to /batch.
+	BatchPOST struct {
+		Requests []BatchRequest `json:"requests"`
+	}
+
+	// BatchPOSTResponse is the object returned by a POST request to /batch,
+	// containing a response for each request, in the same order as the requests.
+	BatchPOSTResponse struct {
+		Responses []BatchResponse `json:"responses"`
+	}
+)
+
+// RegisterBatchHTTPHandlers registers the default Rivine handler for the batch HTTP endpoint,
+// executing all batched calls using the given handler. Each batched call is authenticated on its own,
+// using the credentials of the batch request.
+func RegisterBatchHTTPHandlers(router Router, handler http.Handler) {
+	if handler == nil {
+		build.Critical("no http Handler given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.POST("/batch", NewBatchHandler(handler))
+}
+
+// NewBatchHandler creates a handler to handle API calls to POST /batch,
+// executing the batched calls sequentially using the given handler.
+func NewBatchHandler(handler http.Handler) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body BatchPOST
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied batch: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if len(body.Requests) == 0 {
+			WriteError(w, Error{Message: "no requests given in batch"}, http.StatusBadRequest)
+			return
+		}
+		if len(body.Requests) > MaxBatchRequests {
+			WriteError(w, Error{Message: fmt.Sprintf("too many requests given in batch: at most %d requests are allowed", MaxBatchRequests)}, http.StatusBadRequest)
+			return
+		}
+		// validate all requests first, such that none are executed if one is invalid
+		subRequests := make([]*http.Request, 0, len(body.Requests))
+		for index, batchReq := range body.Requests {
+			subReq, err := newBatchSubRequest(req, batchReq)
+			if err != nil {
+				WriteError(w, Error{Message: fmt.Sprintf("invalid batch request #%d: %v", index, err)}, http.StatusBadRequest)
+				return
+			}
+			subRequests = append(subRequests, subReq)
+		}
+		responses := make([]BatchResponse, 0, len(subRequests))
+		for _, subReq := range subRequests {
+			recorder := newBatchResponseRecorder()
+			// batched calls are identified by the ID of the batch request
+			if id := RequestID(req); id != "" {
+				recorder.Header().Set(RequestIDHeader, id)
+			}
+			handler.ServeHTTP(recorder, subReq)
+			responses = append(responses, recorder.response())
+		}
+		WriteJSON(w, BatchPOSTResponse{Responses: responses})
+	}
+}
+
+// newBatchSubRequest creates the HTTP request for a batched call,
+// inheriting the headers (e.g. authentication) and remote address of the batch request.
+func newBatchSubRequest(req *http.Request, batchReq BatchRequest) (*http.Request, error) {
+	method := strings.ToUpper(batchReq.Method)
+	if method != http.MethodGet && method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method %q", batchReq.Method)
+	}
+	if !strings.HasPrefix(batchReq.Path, "/") {
+		return nil, fmt.Errorf("invalid path %q: has to be absolute", batchReq.Path)
+	}
+	var (
+		body        []byte
+		contentType string
+	)
+	if len(batchReq.Body) != 0 && string(batchReq.Body) != "null" {
+		var form string
+		if json.Unmarshal(batchReq.Body, &form) == nil {
+			body, contentType = []byte(form), "application/x-www-form-urlencoded"
+		} else {
+			body, contentType = batchReq.Body, "application/json"
+		}
+	}
+	subReq, err := http.NewRequest(method, batchReq.Path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	// compare the decoded (and cleaned) path, such that an encoded path (e.g. /%62atch) is recognized as well
+	if strings.HasPrefix(strings.TrimPrefix(path.Clean(subReq.URL.Path), APIVersionPrefix), "/batch") {
+		return nil, fmt.Errorf("batches cannot be nested")
+	}
+	subReq = subReq.WithContext(req.Context())
+	subReq.Header = req.Header.Clone()
+	subReq.Header.Del("Content-Length")
+	subReq.Header.Del("Content-Type")
+	if contentType != "" {
+		subReq.Header.Set("Content-Type", contentType)
+	}
+	subReq.RemoteAddr = req.RemoteAddr
+	subReq.Host = req.Host
+	return subReq, nil
+}
+
+// batchResponseRecorder records the response of a batched call.
+type batchResponseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchResponseRecorder() *batchResponseRecorder {
+	return &batchResponseRecorder{header: make(http.Header)}
+}
+
+// Header implements http.ResponseWriter.Header
+func (rec *batchResponseRecorder) Header() http.Header {
+	return rec.header
+}
+
+// Write implements http.ResponseWriter.Write
+func (rec *batchResponseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (rec *batchResponseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+// response returns the recorded response, encoding non-JSON bodies as a JSON string.
+func (rec *batchResponseRecorder) response() BatchResponse {
+	resp := BatchResponse{Status: rec.status}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	body := bytes.TrimSpace(rec.body.Bytes())
+	if len(body) == 0 {
+		return resp
+	}
+	if json.Valid(body) {
+		resp.Body = body
+		return resp
+	}
+	resp.Body, _ = json.Marshal(string(body))
+	return resp
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// newBatchTestRouter creates a router with a batch endpoint,
+// executing its calls using the same router.
+func newBatchTestRouter(auth *APIAuthenticator) *httprouter.Router {
+	router := httprouter.New()
+	router.GET("/private", RequireScopeHandler(func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		WriteJSON(w, req.FormValue("q"))
+	}, auth, APIScopeReadOnly))
+	router.POST("/form", func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		w.Write([]byte("amount=" + req.FormValue("amount")))
+	})
+	router.POST("/json", func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		if req.Header.Get("Content-Type") != "application/json" {
+			WriteError(w, Error{Message: "expected a JSON body"}, http.StatusBadRequest)
+			return
+		}
+		b, _ := ioutil.ReadAll(req.Body)
+		w.Write(b)
+	})
+	router.POST("/empty", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteSuccess(w)
+	})
+	RegisterBatchHTTPHandlers(router, router)
+	return router
+}
+
+// batchTestRequest executes the given batch using the given router, authenticating with the given secret if defined.
+func batchTestRequest(router http.Handler, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/batch", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBatchHandler(t *testing.T) {
+	router := newBatchTestRouter(NewPasswordAPIAuthenticator("password"))
+
+	rec := batchTestRequest(router, "password", `{"requests": [
+		{"method": "get", "path": "/private?q=hello"},
+		{"method": "POST", "path": "/form", "body": "amount=42"},
+		{"method": "POST", "path": "/json", "body": {"amount": 42}},
+		{"method": "POST", "path": "/empty"},
+		{"method": "GET", "path": "/unknown"}
+	]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, not %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp BatchPOSTResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	expected := []BatchResponse{
+		{Status: http.StatusOK, Body: json.RawMessage(`"hello"`)},
+		// non-JSON bodies are returned as a JSON string
+		{Status: http.StatusOK, Body: json.RawMessage(`"amount=42"`)},
+		{Status: http.StatusOK, Body: json.RawMessage(`{"amount":42}`)},
+		{Status: http.StatusNoContent},
+		{Status: http.StatusNotFound, Body: json.RawMessage(`"404 page not found"`)},
+	}
+	if len(resp.Responses) != len(expected) {
+		t.Fatalf("expected %d responses, not %d", len(expected), len(resp.Responses))
+	}
+	for i, r := range resp.Responses {
+		if r.Status != expected[i].Status || string(r.Body) != string(expected[i].Body) {
+			t.Errorf("unexpected response #%d: %d: %s", i, r.Status, r.Body)
+		}
+	}
+
+	// each call is authenticated using the credentials of the batch
+	rec = batchTestRequest(router, "", `{"requests": [{"method": "GET", "path": "/private"}, {"method": "POST", "path": "/empty"}]}`)
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Responses) != 2 || resp.Responses[0].Status != http.StatusUnauthorized || resp.Responses[1].Status != http.StatusNoContent {
+		t.Errorf("unexpected responses without credentials: %+v", resp.Responses)
+	}
+}
+
+func TestBatchHandlerInvalid(t *testing.T) {
+	var executed int
+	router := httprouter.New()
+	router.POST("/empty", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		executed++
+		WriteSuccess(w)
+	})
+	RegisterBatchHTTPHandlers(router, router)
+
+	tooMany := make([]BatchRequest, MaxBatchRequests+1)
+	for i := range tooMany {
+		tooMany[i] = BatchRequest{Method: "POST", Path: "/empty"}
+	}
+	b, err := json.Marshal(BatchPOST{Requests: tooMany})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name, body string
+	}{
+		{"invalid JSON", `{"requests": `},
+		{"empty batch", `{"requests": []}`},
+		{"too many requests", string(b)},
+		// a single invalid request prevents all requests from being executed
+		{"unsupported method", `{"requests": [{"method": "POST", "path": "/empty"}, {"method": "DELETE", "path": "/empty"}]}`},
+		{"relative path", `{"requests": [{"method": "POST", "path": "/empty"}, {"method": "POST", "path": "empty"}]}`},
+		{"nested batch", `{"requests": [{"method": "POST", "path": "/empty"}, {"method": "POST", "path": "/batch"}]}`},
+		{"nested versioned batch", `{"requests": [{"method": "POST", "path": "/v2/batch"}]}`},
+		{"nested encoded batch", `{"requests": [{"method": "POST", "path": "/%62atch"}]}`},
+		{"nested encoded versioned batch", `{"requests": [{"method": "POST", "path": "/v2/%62atch?x=1"}]}`},
+		{"nested unclean batch", `{"requests": [{"method": "POST", "path": "/empty/../batch"}]}`},
+	}
+	for _, testCase := range testCases {
+		if rec := batchTestRequest(router, "", testCase.body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, http.StatusBadRequest, rec.Code)
+		}
+	}
+	if executed != 0 {
+		t.Errorf("%d calls of invalid batches are executed", executed)
+	}
+}
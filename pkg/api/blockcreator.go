@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// BlockCreatorGET contains the fields returned by a GET call to "/blockcreator".
+type BlockCreatorGET struct {
+	modules.BlockCreatorStatus
+}
+
+// RegisterBlockCreatorHTTPHandlers registers the default Rivine handlers for all default Rivine BlockCreator HTTP endpoints.
+func RegisterBlockCreatorHTTPHandlers(router Router, blockCreator modules.BlockCreator) {
+	if blockCreator == nil {
+		build.Critical("no block creator module given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/blockcreator", NewBlockCreatorRootHandler(blockCreator))
+}
+
+// NewBlockCreatorRootHandler creates a handler to handle the API call asking for the block creator status.
+func NewBlockCreatorRootHandler(blockCreator modules.BlockCreator) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		WriteJSON(w, BlockCreatorGET{blockCreator.Status()})
+	}
+}
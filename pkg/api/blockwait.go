@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// DefaultBlockWaitTimeout is the time a request to /consensus/blocks/wait
+	// waits for a new block, in case no timeout is given.
+	DefaultBlockWaitTimeout = 30 * time.Second
+	// MaxBlockWaitTimeout is the maximum time a request to /consensus/blocks/wait waits for a new block.
+	MaxBlockWaitTimeout = 5 * time.Minute
+)
+
+// blockNotifier notifies all waiting requests of every change to the consensus set.
+// It only subscribes to the consensus set once a request waits for a new block.
+type blockNotifier struct {
+	cs modules.ConsensusSet
+
+	subscribeOnce sync.Once
+	subscribeErr  error
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+func newBlockNotifier(cs modules.ConsensusSet) *blockNotifier {
+	return &blockNotifier{
+		cs:      cs,
+		changed: make(chan struct{}),
+	}
+}
+
+// ProcessConsensusChange implements modules.ConsensusSetSubscriber.ProcessConsensusChange
+func (bn *blockNotifier) ProcessConsensusChange(modules.ConsensusChange) {
+	bn.mu.Lock()
+	close(bn.changed)
+	bn.changed = make(chan struct{})
+	bn.mu.Unlock()
+}
+
+// changes returns a channel which is closed on the next change to the consensus set.
+func (bn *blockNotifier) changes() (<-chan struct{}, error) {
+	bn.subscribeOnce.Do(func() {
+		bn.subscribeErr = bn.cs.ConsensusSetSubscribe(bn, modules.ConsensusChangeRecent, nil)
+	})
+	if bn.subscribeErr != nil {
+		return nil, bn.subscribeErr
+	}
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	return bn.changed, nil
+}
+
+// NewConsensusWaitBlockHandler creates a handler to handle the API calls to /consensus/blocks/wait,
+// returning the block following the given height as soon as it is accepted,
+// or responding with 204 (No Content) in case no such block is accepted before the timeout.
+func NewConsensusWaitBlockHandler(cs modules.ConsensusSet) httprouter.Handle {
+	bn := newBlockNotifier(cs)
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		after := cs.Height()
+		if str := req.FormValue("after"); str != "" {
+			n, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				WriteError(w, Error{Message: "invalid after height: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			after = types.BlockHeight(n)
+		}
+		timeout, err := parseBlockWaitTimeout(req.FormValue("timeout"))
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		for {
+			// get the change channel prior to checking the height,
+			// such that a block accepted in between is not missed
+			changed, err := bn.changes()
+			if err != nil {
+				WriteError(w, Error{Message: "failed to subscribe to the consensus set: " + err.Error()}, http.StatusInternalServerError)
+				return
+			}
+			if cs.Height() > after {
+				if block, exists := cs.BlockAtHeight(after + 1); exists {
+					WriteJSON(w, ConsensusBlock{
+						ID:     block.ID(),
+						Height: after + 1,
+						Block:  block,
+					})
+					return
+				}
+			}
+			select {
+			case <-changed:
+			case <-timer.C:
+				WriteSuccess(w)
+				return
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// parseBlockWaitTimeout parses the timeout of a request to /consensus/blocks/wait,
+// given as a duration (e.g. 30s) or as an amount of seconds.
+func parseBlockWaitTimeout(str string) (time.Duration, error) {
+	if str == "" {
+		return DefaultBlockWaitTimeout, nil
+	}
+	timeout, err := time.ParseDuration(str)
+	if err != nil {
+		seconds, convErr := strconv.ParseUint(str, 10, 32)
+		if convErr != nil {
+			return 0, errors.New("invalid timeout: " + err.Error())
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout < 0 {
+		return 0, errors.New("invalid timeout: cannot be negative")
+	}
+	if timeout > MaxBlockWaitTimeout {
+		timeout = MaxBlockWaitTimeout
+	}
+	return timeout, nil
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// blockWaitTestConsensusSet extends the events test consensus set
+// with the methods used to look up the blocks of its path.
+type blockWaitTestConsensusSet struct {
+	*eventsTestConsensusSet
+}
+
+func (cs blockWaitTestConsensusSet) Height() types.BlockHeight {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return types.BlockHeight(len(cs.changes) - 1)
+}
+
+func (cs blockWaitTestConsensusSet) BlockAtHeight(height types.BlockHeight) (types.Block, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if height >= types.BlockHeight(len(cs.changes)) {
+		return types.Block{}, false
+	}
+	return cs.changes[height].AppliedBlocks[0], true
+}
+
+func TestConsensusWaitBlockHandler(t *testing.T) {
+	cs := blockWaitTestConsensusSet{newEventsTestConsensusSet()}
+	cs.applyBlock()
+	cs.applyBlock()
+	handler := NewConsensusWaitBlockHandler(cs)
+	wait := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/consensus/blocks/wait?"+query, nil), nil)
+		return rec
+	}
+	decodeBlock := func(rec *httptest.ResponseRecorder) ConsensusBlock {
+		var block ConsensusBlock
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, not %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&block); err != nil {
+			t.Fatal(err)
+		}
+		return block
+	}
+
+	// a block which is already accepted is returned immediately
+	if block := decodeBlock(wait("after=0")); block.Height != 1 || block.ID != cs.changes[1].AppliedBlocks[0].ID() {
+		t.Errorf("unexpected block: %v at height %d", block.ID, block.Height)
+	}
+
+	// the next block is returned as soon as it is accepted
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- wait("timeout=10s")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case rec := <-done:
+		t.Fatalf("request returned before a block is accepted: %d", rec.Code)
+	default:
+	}
+	_, next := cs.applyBlock()
+	select {
+	case rec := <-done:
+		if block := decodeBlock(rec); block.Height != 2 || block.ID != next.ID() {
+			t.Errorf("unexpected next block: %v at height %d", block.ID, block.Height)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request didn't return once a block is accepted")
+	}
+
+	// no content is returned if no block is accepted before the timeout
+	if rec := wait("timeout=50ms"); rec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d after the timeout, not %d", http.StatusNoContent, rec.Code)
+	}
+
+	for _, query := range []string{"after=x", "timeout=x", "timeout=-1s"} {
+		if rec := wait(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", query, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestParseBlockWaitTimeout(t *testing.T) {
+	for str, expected := range map[string]time.Duration{
+		"":      DefaultBlockWaitTimeout,
+		"10":    10 * time.Second,
+		"500ms": 500 * time.Millisecond,
+		"1h":    MaxBlockWaitTimeout,
+	} {
+		if timeout, err := parseBlockWaitTimeout(str); err != nil || timeout != expected {
+			t.Errorf("%q: unexpected timeout: %v (%v)", str, timeout, err)
+		}
+	}
+}
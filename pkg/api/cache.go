@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// DefaultResponseCacheSize is the default maximum size (in bytes) of all responses cached by the API.
+	DefaultResponseCacheSize = 64 << 20
+
+	// ImmutableConfirmations is the amount of confirmations after which a block
+	// (and thus the transactions it contains) is considered immutable,
+	// such that responses containing it can be cached.
+	ImmutableConfirmations = 10
+
+	// immutableCacheControl is the Cache-Control header of immutable responses,
+	// which is also used by the API to identify the responses it can cache
+	immutableCacheControl = "public, max-age=31536000, immutable"
+)
+
+// SetImmutable marks the response as immutable, such that it can be cached,
+// by ResponseCacheHandler as well as by clients and proxies.
+// It should only be used for public responses which never change (e.g. a confirmed block).
+func SetImmutable(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", immutableCacheControl)
+}
+
+// IsImmutableHeight returns true if a block at the given height
+// has (at least) ImmutableConfirmations confirmations at the given current height.
+func IsImmutableHeight(height, currentHeight types.BlockHeight) bool {
+	return height <= currentHeight && currentHeight-height+1 >= ImmutableConfirmations
+}
+
+// ResponseCache is an in-memory cache of immutable responses, limited in size,
+// evicting the least recently used responses once full.
+type ResponseCache struct {
+	maxSize int
+
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cachedResponse struct {
+	key         string
+	etag        string
+	contentType string
+	body        []byte
+}
+
+// NewResponseCache creates a cache which caches responses up to the given total size (in bytes).
+// Nil is returned if the size isn't positive, disabling the cache.
+func NewResponseCache(maxSize int) *ResponseCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &ResponseCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the response cached for the given key, if any.
+func (cache *ResponseCache) get(key string) (*cachedResponse, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	elem, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cache.lru.MoveToFront(elem)
+	return elem.Value.(*cachedResponse), true
+}
+
+// add caches the given response, evicting the least recently used responses if required.
+// Responses larger than an eighth of the cache are not cached.
+func (cache *ResponseCache) add(resp *cachedResponse) {
+	size := len(resp.key) + len(resp.body)
+	if size > cache.maxSize/8 {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if _, ok := cache.entries[resp.key]; ok {
+		return
+	}
+	cache.entries[resp.key] = cache.lru.PushFront(resp)
+	cache.size += size
+	for cache.size > cache.maxSize {
+		elem := cache.lru.Back()
+		evicted := cache.lru.Remove(elem).(*cachedResponse)
+		delete(cache.entries, evicted.key)
+		cache.size -= len(evicted.key) + len(evicted.body)
+	}
+}
+
+// ResponseCacheHandler is middleware that caches the responses to GET requests which are marked immutable
+// (see SetImmutable) in the given cache, serving subsequent requests for the same URL from the cache.
+// Immutable responses are identified by an ETag, such that a client can revalidate its copy
+// using the If-None-Match header, which is responded to with 304 Not Modified if the copy is still valid.
+// All requests are served as-is in case the cache is nil.
+//
+// As cached responses are served without calling the given handler,
+// it should only be used for handlers which do not require authentication for immutable responses.
+func ResponseCacheHandler(h http.Handler, cache *ResponseCache) http.Handler {
+	if cache == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			h.ServeHTTP(w, req)
+			return
+		}
+		key := req.URL.Path + "?" + req.URL.RawQuery
+		if resp, ok := cache.get(key); ok {
+			writeCachedResponse(w, req, resp)
+			return
+		}
+		cw := &cacheResponseWriter{w: w}
+		h.ServeHTTP(cw, req)
+		if !cw.buffered {
+			return
+		}
+		sum := sha256.Sum256(cw.body.Bytes())
+		resp := &cachedResponse{
+			key: key,
+			// the ETag is weak, as the response might be wrapped in an envelope or compressed
+			etag:        `W/"` + hex.EncodeToString(sum[:16]) + `"`,
+			contentType: cw.Header().Get("Content-Type"),
+			body:        cw.body.Bytes(),
+		}
+		if req.Method == http.MethodGet {
+			cache.add(resp)
+		}
+		writeCachedResponse(w, req, resp)
+	})
+}
+
+// writeCachedResponse writes an immutable response,
+// or 304 Not Modified in case the client has a valid copy of it.
+func writeCachedResponse(w http.ResponseWriter, req *http.Request, resp *cachedResponse) {
+	header := w.Header()
+	header.Set("ETag", resp.etag)
+	header.Set("Cache-Control", immutableCacheControl)
+	if etagMatches(req.Header.Get("If-None-Match"), resp.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if resp.contentType != "" {
+		header.Set("Content-Type", resp.contentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp.body)
+}
+
+// etagMatches returns true if the given If-None-Match header matches the given ETag,
+// using the weak comparison required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheResponseWriter buffers a successful response marked immutable, such that it can be cached,
+// while writing any other response as-is.
+type cacheResponseWriter struct {
+	w        http.ResponseWriter
+	status   int
+	buffered bool
+	body     bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.Header
+func (cw *cacheResponseWriter) Header() http.Header {
+	return cw.w.Header()
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (cw *cacheResponseWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+	if status == http.StatusOK && cw.Header().Get("Cache-Control") == immutableCacheControl {
+		cw.buffered = true
+		return
+	}
+	cw.w.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.Write
+func (cw *cacheResponseWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.buffered {
+		return cw.body.Write(b)
+	}
+	return cw.w.Write(b)
+}
+
+// Flush implements http.Flusher.Flush,
+// only flushing responses which are not buffered.
+func (cw *cacheResponseWriter) Flush() {
+	if cw.buffered {
+		return
+	}
+	if f, ok := cw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.Hijack
+func (cw *cacheResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
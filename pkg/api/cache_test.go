@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// cacheTestRequest serves a request using the given handler, revalidating the given ETag, if defined.
+func cacheTestRequest(h http.Handler, method, path, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResponseCacheHandlerRevalidation(t *testing.T) {
+	var served int
+	h := ResponseCacheHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served++
+		SetImmutable(w)
+		WriteJSON(w, req.URL.Path)
+	}), NewResponseCache(DefaultResponseCacheSize))
+
+	rec := cacheTestRequest(h, "GET", "/explorer/blocks/1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, not %d", http.StatusOK, rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("unexpected ETag: %q", etag)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != immutableCacheControl {
+		t.Errorf("unexpected Cache-Control header: %q", cc)
+	}
+
+	// subsequent requests are served from the cache
+	cached := cacheTestRequest(h, "GET", "/explorer/blocks/1", "")
+	if cached.Code != http.StatusOK || cached.Body.String() != rec.Body.String() || cached.Header().Get("ETag") != etag {
+		t.Errorf("unexpected cached response: %d: %s", cached.Code, cached.Body.String())
+	}
+	if ct := cached.Header().Get("Content-Type"); ct != rec.Header().Get("Content-Type") {
+		t.Errorf("unexpected cached Content-Type: %q", ct)
+	}
+
+	// a valid copy is revalidated using 304 Not Modified, without body
+	for _, ifNoneMatch := range []string{etag, strings.TrimPrefix(etag, "W/"), `"other", ` + etag, "*"} {
+		rec := cacheTestRequest(h, "GET", "/explorer/blocks/1", ifNoneMatch)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: expected status %d without body, not %d: %s", ifNoneMatch, http.StatusNotModified, rec.Code, rec.Body.String())
+		}
+	}
+	// an invalid copy receives the full response
+	if rec := cacheTestRequest(h, "GET", "/explorer/blocks/1", `W/"other"`); rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Errorf("expected status %d with body for an outdated copy, not %d", http.StatusOK, rec.Code)
+	}
+	if served != 1 {
+		t.Errorf("expected the handler to be called once, not %d times", served)
+	}
+
+	// a different URL is a different response
+	if rec := cacheTestRequest(h, "GET", "/explorer/blocks/2", etag); rec.Code != http.StatusOK || served != 2 {
+		t.Errorf("expected status %d for another URL, not %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestResponseCacheHandlerPassthrough(t *testing.T) {
+	var served int
+	cache := NewResponseCache(DefaultResponseCacheSize)
+	h := ResponseCacheHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served++
+		switch req.URL.Path {
+		case "/consensus":
+			// responses which are not immutable are written as they are written by the handler
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("height"))
+			if rec := w.(*cacheResponseWriter).w.(*httptest.ResponseRecorder); rec.Body.String() != "height" {
+				t.Errorf("response which isn't immutable is buffered: %q", rec.Body.String())
+			}
+			w.(http.Flusher).Flush()
+		case "/explorer/blocks/1":
+			SetImmutable(w)
+			WriteJSON(w, "block")
+		case "/explorer/blocks/unknown":
+			// errors are never cached, even if marked immutable
+			SetImmutable(w)
+			WriteError(w, Error{Message: "unknown block"}, http.StatusBadRequest)
+		default:
+			WriteSuccess(w)
+		}
+	}), cache)
+
+	for i := 0; i < 2; i++ {
+		rec := cacheTestRequest(h, "GET", "/consensus", "")
+		if rec.Code != http.StatusOK || rec.Body.String() != "height" || !rec.Flushed {
+			t.Errorf("unexpected response: %d: %q", rec.Code, rec.Body.String())
+		}
+		if etag := rec.Header().Get("ETag"); etag != "" {
+			t.Errorf("response which isn't immutable has an ETag: %q", etag)
+		}
+		if rec := cacheTestRequest(h, "GET", "/explorer/blocks/unknown", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, not %d", http.StatusBadRequest, rec.Code)
+		}
+	}
+	if served != 4 || len(cache.entries) != 0 {
+		t.Errorf("responses which are not immutable are cached: %d served, %d cached", served, len(cache.entries))
+	}
+
+	// HEAD requests are answered with an ETag, but do not fill the cache
+	rec := cacheTestRequest(h, "HEAD", "/explorer/blocks/1", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == "" {
+		t.Errorf("unexpected HEAD response: %d: %v", rec.Code, rec.Header())
+	}
+	if served != 5 || len(cache.entries) != 0 {
+		t.Errorf("HEAD request fills the cache: %d cached", len(cache.entries))
+	}
+	cacheTestRequest(h, "GET", "/explorer/blocks/1", "")
+	if rec := cacheTestRequest(h, "HEAD", "/explorer/blocks/1", ""); rec.Code != http.StatusOK || served != 6 {
+		t.Errorf("HEAD request isn't served from the cache: %d", rec.Code)
+	}
+
+	// other methods are never cached
+	for i := 0; i < 2; i++ {
+		if rec := cacheTestRequest(h, "POST", "/explorer/blocks/1", ""); rec.Code != http.StatusOK {
+			t.Errorf("expected status %d for a POST request, not %d", http.StatusOK, rec.Code)
+		}
+	}
+	if served != 8 {
+		t.Errorf("POST request is served from the cache")
+	}
+
+	// requests are served as-is without cache
+	h = ResponseCacheHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		served++
+		SetImmutable(w)
+		WriteJSON(w, "block")
+	}), NewResponseCache(0))
+	for i := 0; i < 2; i++ {
+		if rec := cacheTestRequest(h, "GET", "/explorer/blocks/1", ""); rec.Code != http.StatusOK || rec.Header().Get("ETag") != "" {
+			t.Errorf("unexpected response without cache: %d: %v", rec.Code, rec.Header())
+		}
+	}
+	if served != 10 {
+		t.Errorf("request is served from a disabled cache")
+	}
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	// room for 8 responses of 8 bytes (including their 4-byte key),
+	// while a response larger than 8 bytes isn't cached
+	cache := NewResponseCache(64)
+	newResponse := func(key string, size int) *cachedResponse {
+		return &cachedResponse{key: key, body: make([]byte, size-len(key))}
+	}
+	for _, key := range []string{"/k/1", "/k/2", "/k/3"} {
+		cache.add(newResponse(key, 8))
+	}
+	if cache.size != 24 || cache.lru.Len() != 3 {
+		t.Fatalf("unexpected cache size: %d (%d responses)", cache.size, cache.lru.Len())
+	}
+
+	// responses larger than an eighth of the cache are not cached
+	cache.add(newResponse("/big", 9))
+	if _, ok := cache.get("/big"); ok {
+		t.Error("response larger than an eighth of the cache is cached")
+	}
+
+	// the least recently used responses are evicted once the cache is full
+	if _, ok := cache.get("/k/1"); !ok {
+		t.Fatal("cached response isn't found")
+	}
+	for _, key := range []string{"/k/4", "/k/5", "/k/6", "/k/7", "/k/8", "/k/9"} {
+		cache.add(newResponse(key, 8))
+	}
+	if cache.size != 64 || cache.lru.Len() != 8 {
+		t.Errorf("unexpected cache size after eviction: %d (%d responses)", cache.size, cache.lru.Len())
+	}
+	for key, cached := range map[string]bool{
+		"/k/1": true,
+		"/k/2": false,
+		"/k/3": true,
+		"/k/9": true,
+	} {
+		if _, ok := cache.get(key); ok != cached {
+			t.Errorf("%s: expected cached to be %v", key, cached)
+		}
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/bgentry/speakeasy"
 )
@@ -26,12 +29,39 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP %d error: %v", e.statusCode, e.internalError)
 }
 
+// Unwrap returns the internal error,
+// which is an Error in case it was returned by the API.
+func (e *HTTPError) Unwrap() error {
+	return e.internalError
+}
+
 // HTTPStatusCode returns the internal status code,
 // returned by the HTTP client in case of an error.
 func (e *HTTPError) HTTPStatusCode() int {
 	return e.statusCode
 }
 
+// NoResponseError is returned by the HTTPClient in case no response was received from the daemon,
+// e.g. because it isn't running or cannot be reached.
+type NoResponseError struct {
+	Err error
+	// Context optionally describes the call which failed
+	Context string
+}
+
+// Error implements error.Error
+func (e *NoResponseError) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("no response from daemon - %s: %v", e.Context, e.Err)
+	}
+	return fmt.Sprintf("no response from daemon: %v", e.Err)
+}
+
+// Unwrap returns the (network) error which caused no response to be received.
+func (e *NoResponseError) Unwrap() error {
+	return e.Err
+}
+
 // Non2xx returns true for non-success HTTP status codes.
 func Non2xx(code int) bool {
 	return code < 200 || code > 299
@@ -47,15 +77,91 @@ func DecodeError(resp *http.Response) error {
 	if err != nil {
 		return err
 	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get(RequestIDHeader)
+	}
 	return apiErr
 }
 
 // HTTPClient is used to communicate with the Rivine-based daemon,
 // using the exposed (local) REST API over HTTP.
 type HTTPClient struct {
-	RootURL   string
-	Password  string
-	UserAgent string
+	RootURL string
+	// FailoverURLs are the root URLs of alternative daemons, used in order
+	// in case the daemon at RootURL cannot be reached. Reads are retried transparently
+	// using the next daemon, while writes are only retried in case they could not be sent.
+	// The daemon which responds becomes the RootURL for all following calls.
+	FailoverURLs []string
+	Password     string
+	UserAgent    string
+}
+
+// HealthCheckTimeout is the time a daemon gets to respond to a health check
+// of (*HTTPClient).SelectHealthyDaemon.
+const HealthCheckTimeout = 5 * time.Second
+
+// SelectHealthyDaemon checks the health of the daemon at RootURL and those of the FailoverURLs in order,
+// using the first daemon which responds as the RootURL. False is returned if none of them respond,
+// in which case the daemons are used in their original order.
+func (c *HTTPClient) SelectHealthyDaemon() bool {
+	client := &http.Client{Transport: http.DefaultTransport, Timeout: HealthCheckTimeout}
+	for attempt := 0; attempt <= len(c.FailoverURLs); attempt++ {
+		if attempt > 0 {
+			c.failover()
+		}
+		req, err := http.NewRequest("GET", c.RootURL+"/health/live", nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("User-Agent", c.UserAgent)
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		// daemons predating the health endpoints respond with 404, which is fine as well
+		if resp.StatusCode < http.StatusInternalServerError {
+			return true
+		}
+	}
+	// we're back at the original RootURL, as every failover rotates the URLs
+	c.failover()
+	return false
+}
+
+// failover makes the first failover URL the root URL,
+// appending the previous root URL to the end of the failover URLs.
+func (c *HTTPClient) failover() {
+	if len(c.FailoverURLs) == 0 {
+		return
+	}
+	urls := append(append([]string{}, c.FailoverURLs[1:]...), c.RootURL)
+	c.RootURL, c.FailoverURLs = c.FailoverURLs[0], urls
+}
+
+// shouldFailover returns true if a call which failed with the given error
+// can be retried using the next daemon. Calls which aren't idempotent
+// are only retried if the request could not be sent at all.
+func shouldFailover(err error, idempotent bool) bool {
+	if err == nil {
+		return false
+	}
+	var nrErr *NoResponseError
+	if errors.As(err, &nrErr) {
+		if idempotent {
+			return true
+		}
+		var opErr *net.OpError
+		return errors.As(nrErr.Err, &opErr) && opErr.Op == "dial"
+	}
+	var httpErr *HTTPError
+	if idempotent && errors.As(err, &httpErr) {
+		switch httpErr.statusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
 }
 
 // PostResp makes a POST API call and decodes the response. An error is
@@ -151,13 +257,34 @@ func (c *HTTPClient) Get(call string) error {
 	return nil
 }
 
+// GetStream makes a GET API call, returning the body of the response to be streamed,
+// e.g. the server-sent events of "/events/stream". The caller has to close it.
+// An error is returned if the response status is not 2xx.
+func (c *HTTPClient) GetStream(call string) (io.ReadCloser, error) {
+	resp, err := c.apiGet(call, "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 // ApiGet wraps a GET request with a status code check, such that if the GET does
 // not return 2xx, the error will be read and returned. When no error is returned,
 // the response's body isn't closed, otherwise it is.
+// The request is retried using the failover daemons, if any, in case the daemon cannot be reached.
 func (c *HTTPClient) apiGet(call, data string) (*http.Response, error) {
-	resp, err := HTTPGet(c.RootURL+call, data, c.UserAgent)
+	resp, err := c.apiGetFrom(c.RootURL, call, data)
+	for attempt := 0; attempt < len(c.FailoverURLs) && shouldFailover(err, true); attempt++ {
+		c.failover()
+		resp, err = c.apiGetFrom(c.RootURL, call, data)
+	}
+	return resp, err
+}
+
+func (c *HTTPClient) apiGetFrom(rootURL, call, data string) (*http.Response, error) {
+	resp, err := HTTPGet(rootURL+call, data, c.UserAgent)
 	if err != nil {
-		return nil, errors.New("no response from daemon")
+		return nil, &NoResponseError{Err: err}
 	}
 	// check error code
 	if resp.StatusCode == http.StatusUnauthorized {
@@ -167,9 +294,9 @@ func (c *HTTPClient) apiGet(call, data string) (*http.Response, error) {
 		if err != nil {
 			return nil, err
 		}
-		resp, err = HTTPGETAuthenticated(c.RootURL+call, data, c.UserAgent, password)
+		resp, err = HTTPGETAuthenticated(rootURL+call, data, c.UserAgent, password)
 		if err != nil {
-			return nil, errors.New("no response from daemon - authentication failed")
+			return nil, &NoResponseError{Err: err, Context: "authentication failed"}
 		}
 	}
 	if Non2xx(resp.StatusCode) {
@@ -193,10 +320,20 @@ func (c *HTTPClient) apiGet(call, data string) (*http.Response, error) {
 // ApiPost wraps a POST request with a status code check, such that if the POST
 // does not return 2xx, the error will be read and returned. When no error is returned,
 // the response's body isn't closed, otherwise it is.
+// The request is only retried using the failover daemons, if any, in case it could not be sent.
 func (c *HTTPClient) apiPost(call, data string) (*http.Response, error) {
-	resp, err := HTTPPost(c.RootURL+call, data, c.UserAgent)
+	resp, err := c.apiPostTo(c.RootURL, call, data)
+	for attempt := 0; attempt < len(c.FailoverURLs) && shouldFailover(err, false); attempt++ {
+		c.failover()
+		resp, err = c.apiPostTo(c.RootURL, call, data)
+	}
+	return resp, err
+}
+
+func (c *HTTPClient) apiPostTo(rootURL, call, data string) (*http.Response, error) {
+	resp, err := HTTPPost(rootURL+call, data, c.UserAgent)
 	if err != nil {
-		return nil, errors.New("no response from daemon")
+		return nil, &NoResponseError{Err: err}
 	}
 	// check error code
 	if resp.StatusCode == http.StatusUnauthorized {
@@ -214,9 +351,9 @@ func (c *HTTPClient) apiPost(call, data string) (*http.Response, error) {
 		if err != nil {
 			return nil, err
 		}
-		resp, err = HTTPPostAuthenticated(c.RootURL+call, data, c.UserAgent, password)
+		resp, err = HTTPPostAuthenticated(rootURL+call, data, c.UserAgent, password)
 		if err != nil {
-			return nil, errors.New("no response from daemon - authentication failed")
+			return nil, &NoResponseError{Err: err, Context: "authentication failed"}
 		}
 	}
 	if Non2xx(resp.StatusCode) {
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionMinSize is the minimum size (in bytes) of a response body
+// for it to be compressed, smaller responses not being worth the overhead of compressing them.
+const DefaultCompressionMinSize = 1024
+
+var (
+	gzipWriterPool = sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(nil)
+		},
+	}
+	flateWriterPool = sync.Pool{
+		New: func() interface{} {
+			w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+			return w
+		},
+	}
+)
+
+// CompressionHandler is middleware that compresses responses of at least the given size,
+// using gzip or deflate as accepted by the client (as indicated by its Accept-Encoding header).
+// Only textual responses (e.g. JSON) are compressed. Compression is disabled if the minimum size is negative.
+func CompressionHandler(h http.Handler, minSize int) http.Handler {
+	if minSize < 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(req.Header.Get("Accept-Encoding"))
+		if encoding == "" || req.Method == http.MethodHead {
+			h.ServeHTTP(w, req)
+			return
+		}
+		cw := &compressResponseWriter{
+			w:        w,
+			encoding: encoding,
+			minSize:  minSize,
+		}
+		defer cw.close()
+		h.ServeHTTP(cw, req)
+	})
+}
+
+// negotiateEncoding returns the content encoding to use for a response,
+// based on the given Accept-Encoding header, gzip being preferred over deflate.
+// An empty string is returned if neither encoding is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	var gzipAccepted, deflateAccepted bool
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		accepted := true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				accepted = err == nil && q > 0
+			}
+		}
+		switch encoding {
+		case "gzip", "x-gzip":
+			gzipAccepted = accepted
+		case "deflate":
+			deflateAccepted = accepted
+		}
+	}
+	switch {
+	case gzipAccepted:
+		return "gzip"
+	case deflateAccepted:
+		return "deflate"
+	default:
+		return ""
+	}
+}
+
+// isCompressibleContentType returns true if responses of the given content type are worth compressing.
+// Server-sent events are streamed, and thus never compressed.
+func isCompressibleContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "application/javascript") ||
+		strings.HasPrefix(contentType, "application/xml")
+}
+
+// compressResponseWriter buffers the start of a response, until it knows whether or not
+// the response is to be compressed, after which it writes the response (compressed) to the underlying writer.
+type compressResponseWriter struct {
+	w        http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status     int
+	buf        []byte
+	decided    bool
+	compressor io.WriteCloser
+}
+
+// Header implements http.ResponseWriter.Header
+func (cw *compressResponseWriter) Header() http.Header {
+	return cw.w.Header()
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		cw.Header().Get("Content-Encoding") != "" {
+		// responses without body or which are already encoded are never compressed
+		cw.decide(false)
+	}
+}
+
+// Write implements http.ResponseWriter.Write
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.compressor != nil {
+			return cw.compressor.Write(b)
+		}
+		return cw.w.Write(b)
+	}
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.decide(cw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// compressible returns true if the (buffered) response is to be compressed.
+func (cw *compressResponseWriter) compressible() bool {
+	contentType := cw.Header().Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(cw.buf)
+	}
+	return isCompressibleContentType(contentType)
+}
+
+// decide writes the header and the buffered start of the response,
+// compressing it and the remainder of the response if requested.
+func (cw *compressResponseWriter) decide(compress bool) error {
+	cw.decided = true
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if compress {
+		header := cw.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", cw.encoding)
+		switch cw.encoding {
+		case "gzip":
+			gw := gzipWriterPool.Get().(*gzip.Writer)
+			gw.Reset(cw.w)
+			cw.compressor = gw
+		default:
+			fw := flateWriterPool.Get().(*flate.Writer)
+			fw.Reset(cw.w)
+			cw.compressor = fw
+		}
+	}
+	cw.w.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.compressor != nil {
+		_, err = cw.compressor.Write(buf)
+	} else {
+		_, err = cw.w.Write(buf)
+	}
+	return err
+}
+
+// Flush implements http.Flusher.Flush,
+// writing a (small) buffered response uncompressed, such that streamed responses are not delayed.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		cw.decide(len(cw.buf) >= cw.minSize && cw.compressible())
+	}
+	switch compressor := cw.compressor.(type) {
+	case *gzip.Writer:
+		compressor.Flush()
+	case *flate.Writer:
+		compressor.Flush()
+	}
+	if f, ok := cw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.Hijack
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	// the connection is no longer handled by this writer
+	cw.decided = true
+	return hijacker.Hijack()
+}
+
+// close writes the remainder of the response, returning the compressor to its pool.
+func (cw *compressResponseWriter) close() {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			// nothing was written, leave the default response to the http server
+			return
+		}
+		cw.decide(false)
+	}
+	switch compressor := cw.compressor.(type) {
+	case *gzip.Writer:
+		compressor.Close()
+		gzipWriterPool.Put(compressor)
+	case *flate.Writer:
+		compressor.Close()
+		flateWriterPool.Put(compressor)
+	}
+	cw.compressor = nil
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	for acceptEncoding, expected := range map[string]string{
+		"":                          "",
+		"gzip":                      "gzip",
+		"deflate, gzip":             "gzip",
+		"x-gzip":                    "gzip",
+		"deflate":                   "deflate",
+		"gzip;q=0, deflate;q=0.5":   "deflate",
+		"gzip;q=0, deflate;q=0":     "",
+		"br, identity":              "",
+		"GZIP ; q=1.0, deflate":     "gzip",
+		"gzip;q=invalid, deflate":   "deflate",
+		"deflate;q=0.1, gzip;q=0.9": "gzip",
+	} {
+		if encoding := negotiateEncoding(acceptEncoding); encoding != expected {
+			t.Errorf("%q: expected encoding %q, not %q", acceptEncoding, expected, encoding)
+		}
+	}
+}
+
+// compressionTestRequest serves a request accepting the given encoding,
+// returning the response and its decompressed body.
+func compressionTestRequest(t *testing.T, h http.Handler, method, path, acceptEncoding string) (*httptest.ResponseRecorder, string) {
+	req := httptest.NewRequest(method, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var r io.Reader = rec.Body
+	switch rec.Header().Get("Content-Encoding") {
+	case "gzip":
+		gr, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case "deflate":
+		r = flate.NewReader(rec.Body)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%s %s: failed to read body: %v", method, path, err)
+	}
+	return rec, string(b)
+}
+
+func TestCompressionHandler(t *testing.T) {
+	large := strings.Repeat("rivine ", 512)
+	h := CompressionHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/large":
+			WriteJSON(w, large)
+		case "/small":
+			WriteJSON(w, "small")
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte(large))
+		case "/chunked":
+			// a large response written in small chunks
+			w.Header().Set("Content-Type", "text/plain")
+			for i := 0; i < 512; i++ {
+				w.Write([]byte("rivine "))
+			}
+		case "/error":
+			WriteError(w, Error{Message: large}, http.StatusBadRequest)
+		case "/empty":
+			WriteSuccess(w)
+		}
+	}), DefaultCompressionMinSize)
+
+	testCases := []struct {
+		path, acceptEncoding, encoding string
+		status                         int
+	}{
+		{"/large", "gzip, deflate", "gzip", http.StatusOK},
+		{"/large", "deflate", "deflate", http.StatusOK},
+		{"/large", "", "", http.StatusOK},
+		{"/chunked", "gzip", "gzip", http.StatusOK},
+		{"/error", "gzip", "gzip", http.StatusBadRequest},
+		// small, binary and empty responses are never compressed
+		{"/small", "gzip", "", http.StatusOK},
+		{"/binary", "gzip", "", http.StatusOK},
+		{"/empty", "gzip", "", http.StatusNoContent},
+	}
+	for _, testCase := range testCases {
+		rec, body := compressionTestRequest(t, h, "GET", testCase.path, testCase.acceptEncoding)
+		if rec.Code != testCase.status {
+			t.Errorf("%s (%s): expected status %d, not %d", testCase.path, testCase.acceptEncoding, testCase.status, rec.Code)
+		}
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != testCase.encoding {
+			t.Errorf("%s (%s): expected encoding %q, not %q", testCase.path, testCase.acceptEncoding, testCase.encoding, encoding)
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s (%s): response doesn't vary on the accepted encoding", testCase.path, testCase.acceptEncoding)
+		}
+		if testCase.status != http.StatusNoContent && testCase.path != "/small" && !strings.Contains(body, large) {
+			t.Errorf("%s (%s): unexpected body of %d bytes", testCase.path, testCase.acceptEncoding, len(body))
+		}
+	}
+
+	// the compressed response is smaller than the original response
+	rec, _ := compressionTestRequest(t, h, "GET", "/large", "gzip")
+	if rec.Body.Len() >= len(large) {
+		t.Errorf("compressed response of %d bytes isn't smaller than the original %d bytes", rec.Body.Len(), len(large))
+	}
+
+	// HEAD requests are never compressed, such that their headers match those of an uncompressed GET request
+	if rec, _ := compressionTestRequest(t, h, "HEAD", "/large", "gzip"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("response to a HEAD request is compressed")
+	}
+}
+
+func TestCompressionHandlerStreaming(t *testing.T) {
+	h := CompressionHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: ping\n\n"))
+		w.(http.Flusher).Flush()
+		if rec := w.(*compressResponseWriter).w.(*httptest.ResponseRecorder); rec.Body.String() != "event: ping\n\n" || !rec.Flushed {
+			t.Errorf("flushed event isn't written: %q", rec.Body.String())
+		}
+		w.Write([]byte(strings.Repeat("data: rivine\n", 256)))
+	}), DefaultCompressionMinSize)
+
+	// server-sent events are never compressed
+	rec, _ := compressionTestRequest(t, h, "GET", "/events/stream", "gzip")
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("event stream is compressed using %s", encoding)
+	}
+
+	// compression is disabled using a negative minimum size
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		WriteJSON(w, strings.Repeat("rivine ", 512))
+	})
+	if rec, _ := compressionTestRequest(t, CompressionHandler(next, -1), "GET", "/large", "gzip"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("response is compressed with compression disabled")
+	}
+}
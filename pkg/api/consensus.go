@@ -83,6 +83,35 @@ type (
 	ConsensusGetUnspentBlockstakeOutput struct {
 		Output types.BlockStakeOutput `json:"output"`
 	}
+
+	// ConsensusGetBurned is the object returned by a GET request to
+	// /consensus/burned, containing the cumulative amount of burned coins and block stakes.
+	ConsensusGetBurned struct {
+		Height      types.BlockHeight `json:"height"`
+		Coins       types.Currency    `json:"coins"`
+		BlockStakes types.Currency    `json:"blockstakes"`
+	}
+
+	// ConsensusGetSupply is the object returned by a GET request to
+	// /consensus/supply, containing the supply statistics of the current path.
+	ConsensusGetSupply struct {
+		Height types.BlockHeight `json:"height"`
+		types.SupplyStatistics
+	}
+
+	// ConsensusBlock is a block of the current path, together with its ID and height.
+	ConsensusBlock struct {
+		ID     types.BlockID     `json:"id"`
+		Height types.BlockHeight `json:"height"`
+		Block  types.Block       `json:"block"`
+	}
+
+	// ConsensusGetBlocks is the object returned by a GET request to
+	// /consensus/blocks, containing a page of the blocks within the requested height range.
+	ConsensusGetBlocks struct {
+		Blocks []ConsensusBlock `json:"blocks"`
+		PageInfo
+	}
 )
 
 // RegisterConsensusHTTPHandlers registers the default Rivine handlers for all default Rivine Consensus HTTP endpoints.
@@ -98,6 +127,10 @@ func RegisterConsensusHTTPHandlers(router Router, cs modules.ConsensusSet) {
 	router.GET("/consensus/transactions/:id", NewConsensusGetTransactionHandler(cs))
 	router.GET("/consensus/unspent/coinoutputs/:id", NewConsensusGetUnspentCoinOutputHandler(cs))
 	router.GET("/consensus/unspent/blockstakeoutputs/:id", NewConsensusGetUnspentBlockstakeOutputHandler(cs))
+	router.GET("/consensus/burned", NewConsensusGetBurnedHandler(cs))
+	router.GET("/consensus/supply", NewConsensusGetSupplyHandler(cs))
+	router.GET("/consensus/blocks", NewConsensusGetBlocksHandler(cs))
+	router.GET("/consensus/blocks/wait", NewConsensusWaitBlockHandler(cs))
 }
 
 // NewConsensusRootHandler creates a handler to handle the API calls to /consensus.
@@ -114,6 +147,63 @@ func NewConsensusRootHandler(cs modules.ConsensusSet) httprouter.Handle {
 	}
 }
 
+// NewConsensusGetBurnedHandler creates a handler to handle the API calls to /consensus/burned.
+func NewConsensusGetBurnedHandler(cs modules.ConsensusSet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		coins, blockStakes := cs.BurnedTotals()
+		WriteJSON(w, ConsensusGetBurned{
+			Height:      cs.Height(),
+			Coins:       coins,
+			BlockStakes: blockStakes,
+		})
+	}
+}
+
+// NewConsensusGetSupplyHandler creates a handler to handle the API calls to /consensus/supply.
+func NewConsensusGetSupplyHandler(cs modules.ConsensusSet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		WriteJSON(w, ConsensusGetSupply{
+			Height:           cs.Height(),
+			SupplyStatistics: cs.SupplyStatistics(),
+		})
+	}
+}
+
+// NewConsensusGetBlocksHandler creates a handler to handle the API calls to /consensus/blocks,
+// returning a page of the blocks within the requested height range.
+func NewConsensusGetBlocksHandler(cs modules.ConsensusSet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		pagination, err := ParsePagination(req, DefaultPaginationLimit, MaxPaginationLimit)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		start, end, err := ParseHeightRange(req, cs.Height())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		heights, pageInfo := paginatedHeights(start, end, pagination)
+		blocks := make([]ConsensusBlock, 0, len(heights))
+		for _, height := range heights {
+			block, exists := cs.BlockAtHeight(height)
+			if !exists {
+				// the current path was shortened by a reorg since the height range was defined
+				continue
+			}
+			blocks = append(blocks, ConsensusBlock{
+				ID:     block.ID(),
+				Height: height,
+				Block:  block,
+			})
+		}
+		WriteJSON(w, ConsensusGetBlocks{
+			Blocks:   blocks,
+			PageInfo: pageInfo,
+		})
+	}
+}
+
 // NewConsensusGetTransactionHandler creates a handler to handle lookups of a transaction based on a short or long ID.
 func NewConsensusGetTransactionHandler(cs modules.ConsensusSet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
@@ -136,13 +226,16 @@ func NewConsensusGetTransactionHandler(cs modules.ConsensusSet) httprouter.Handl
 
 		if err != nil {
 			if err == ErrNotFound {
-				WriteError(w, Error{err.Error()}, http.StatusNoContent)
+				WriteError(w, Error{Message: err.Error()}, http.StatusNoContent)
 				return
 			}
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 
+		if idLen == 64 && IsImmutableHeight(cgt.TxShortID.BlockHeight(), cs.Height()) {
+			SetImmutable(w)
+		}
 		WriteJSON(w, cgt)
 	}
 }
@@ -156,19 +249,19 @@ func NewConsensusGetUnspentCoinOutputHandler(cs modules.ConsensusSet) httprouter
 		)
 
 		if len(id) != len(outputID)*2 {
-			WriteError(w, Error{ErrInvalidIDLength.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: ErrInvalidIDLength.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		err := outputID.LoadString(id)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		output, err := cs.GetCoinOutput(outputID)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusNoContent)
+			WriteError(w, Error{Message: err.Error()}, http.StatusNoContent)
 			return
 		}
 		WriteJSON(w, ConsensusGetUnspentCoinOutput{Output: output})
@@ -184,19 +277,19 @@ func NewConsensusGetUnspentBlockstakeOutputHandler(cs modules.ConsensusSet) http
 		)
 
 		if len(id) != len(outputID)*2 {
-			WriteError(w, Error{ErrInvalidIDLength.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: ErrInvalidIDLength.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		err := outputID.LoadString(id)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		output, err := cs.GetBlockStakeOutput(outputID)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusNoContent)
+			WriteError(w, Error{Message: err.Error()}, http.StatusNoContent)
 			return
 		}
 		WriteJSON(w, ConsensusGetUnspentBlockstakeOutput{Output: output})
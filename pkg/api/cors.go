@@ -0,0 +1,147 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSPolicy defines which cross-origin requests are allowed by the API,
+// such that browser-based applications can communicate with it directly.
+// No cross-origin requests are allowed if no origins are defined.
+type CORSPolicy struct {
+	// AllowedOrigins lists the origins (e.g. "https://wallet.example.com") allowed
+	// to make cross-origin requests, "*" allows any origin
+	AllowedOrigins []string
+	// AllowedMethods lists the HTTP methods allowed for cross-origin requests,
+	// defaults to DefaultCORSAllowedMethods if none are defined
+	AllowedMethods []string
+	// AllowedHeaders lists the (non-simple) headers allowed for cross-origin requests,
+	// defaults to DefaultCORSAllowedHeaders if none are defined
+	AllowedHeaders []string
+}
+
+// The default methods and headers allowed for cross-origin requests, in case the CORS policy does not define any.
+var (
+	DefaultCORSAllowedMethods = []string{http.MethodGet, http.MethodPost}
+	DefaultCORSAllowedHeaders = []string{"Authorization", "Content-Type"}
+)
+
+// corsPreflightMaxAge is the amount of seconds a browser can cache the result of a preflight request
+const corsPreflightMaxAge = 600
+
+// Enabled returns true if the policy allows any cross-origin requests.
+func (policy CORSPolicy) Enabled() bool {
+	return len(policy.AllowedOrigins) > 0
+}
+
+// AllowsOrigin returns true if cross-origin requests from the given origin are allowed.
+func (policy CORSPolicy) AllowsOrigin(origin string) bool {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsCredentials returns true if cross-origin requests from the given origin
+// are allowed to include credentials (e.g. HTTP basic authentication managed by the browser),
+// which is only the case for origins which are explicitly allowed, rather than allowed using "*".
+func (policy CORSPolicy) AllowsCredentials(origin string) bool {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed != "*" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (policy CORSPolicy) methods() []string {
+	if len(policy.AllowedMethods) == 0 {
+		return DefaultCORSAllowedMethods
+	}
+	return policy.AllowedMethods
+}
+
+func (policy CORSPolicy) headers() []string {
+	if len(policy.AllowedHeaders) == 0 {
+		return DefaultCORSAllowedHeaders
+	}
+	return policy.AllowedHeaders
+}
+
+func (policy CORSPolicy) allowsMethod(method string) bool {
+	for _, allowed := range policy.methods() {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (policy CORSPolicy) allowsHeaders(headers string) bool {
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		allowed := false
+		for _, h := range policy.headers() {
+			if h == "*" || strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// CORSHandler is middleware that handles cross-origin requests according to the given policy,
+// answering preflight requests itself. Requests without an Origin header are passed on as-is,
+// while cross-origin requests not allowed by the policy do not receive any CORS headers,
+// causing browsers to block them.
+func CORSHandler(h http.Handler, policy CORSPolicy) http.Handler {
+	if !policy.Enabled() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+		if !policy.AllowsOrigin(origin) {
+			if preflight {
+				WriteError(w, Error{Message: "cross-origin requests are not allowed for origin " + origin}, http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, req)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if policy.AllowsCredentials(origin) {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !preflight {
+			h.ServeHTTP(w, req)
+			return
+		}
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		if !policy.allowsMethod(req.Header.Get("Access-Control-Request-Method")) ||
+			!policy.allowsHeaders(req.Header.Get("Access-Control-Request-Headers")) {
+			WriteError(w, Error{Message: "cross-origin request method or headers are not allowed"}, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(policy.methods(), ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(policy.headers(), ", "))
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsPreflightMaxAge))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
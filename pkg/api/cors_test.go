@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// corsTestRequest serves a (preflight) request from the given origin using the given handler.
+func corsTestRequest(h http.Handler, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/wallet", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSHandlerPreflight(t *testing.T) {
+	h := CORSHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("preflight request is passed on")
+	}), CORSPolicy{AllowedOrigins: []string{"https://wallet.example.com"}})
+
+	rec := corsTestRequest(h, "OPTIONS", "https://wallet.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "authorization, content-type",
+	})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d for an allowed preflight request, not %d", http.StatusNoContent, rec.Code)
+	}
+	for key, expected := range map[string]string{
+		"Access-Control-Allow-Origin":      "https://wallet.example.com",
+		"Access-Control-Allow-Methods":     "GET, POST",
+		"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	} {
+		if value := rec.Header().Get(key); value != expected {
+			t.Errorf("unexpected %s header: %q != %q", key, value, expected)
+		}
+	}
+	if vary := rec.Header()["Vary"]; len(vary) != 3 {
+		t.Errorf("unexpected Vary headers: %v", vary)
+	}
+
+	// methods and headers not allowed by the policy are rejected
+	for _, headers := range []map[string]string{
+		{"Access-Control-Request-Method": "DELETE"},
+		{"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-Custom"},
+	} {
+		if rec := corsTestRequest(h, "OPTIONS", "https://wallet.example.com", headers); rec.Code != http.StatusForbidden {
+			t.Errorf("expected status %d for a disallowed preflight request %v, not %d", http.StatusForbidden, headers, rec.Code)
+		}
+	}
+}
+
+func TestCORSHandlerOrigins(t *testing.T) {
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	})
+	h := CORSHandler(next, CORSPolicy{AllowedOrigins: []string{"https://wallet.example.com"}})
+
+	// requests from an allowed origin receive the CORS headers
+	rec := corsTestRequest(h, "GET", "https://WALLET.example.com", nil)
+	if rec.Code != http.StatusOK || served != 1 {
+		t.Fatalf("request from an allowed origin isn't served: %d", rec.Code)
+	}
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "https://WALLET.example.com" {
+		t.Errorf("unexpected allowed origin: %q", origin)
+	}
+	if credentials := rec.Header().Get("Access-Control-Allow-Credentials"); credentials != "true" {
+		t.Errorf("unexpected allow credentials header: %q", credentials)
+	}
+
+	// requests from other origins are served without CORS headers, such that browsers block them,
+	// while their preflight requests are rejected
+	rec = corsTestRequest(h, "GET", "https://evil.example.com", nil)
+	if rec.Code != http.StatusOK || served != 2 {
+		t.Fatalf("request from another origin isn't passed on: %d", rec.Code)
+	}
+	for _, key := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"} {
+		if value := rec.Header().Get(key); value != "" {
+			t.Errorf("request from another origin received the %s header: %q", key, value)
+		}
+	}
+	rec = corsTestRequest(h, "OPTIONS", "https://evil.example.com", map[string]string{"Access-Control-Request-Method": "GET"})
+	if rec.Code != http.StatusForbidden || served != 2 {
+		t.Errorf("expected status %d for a preflight request from another origin, not %d", http.StatusForbidden, rec.Code)
+	}
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("preflight request from another origin received an allowed origin: %q", origin)
+	}
+
+	// requests without an origin are passed on as-is
+	rec = corsTestRequest(h, "GET", "", nil)
+	if rec.Code != http.StatusOK || served != 3 || len(rec.Header()) != 0 {
+		t.Errorf("same-origin request isn't passed on as-is: %d: %v", rec.Code, rec.Header())
+	}
+
+	// any origin is allowed using *, but without credentials
+	h = CORSHandler(next, CORSPolicy{AllowedOrigins: []string{"*"}})
+	rec = corsTestRequest(h, "GET", "https://explorer.example.com", nil)
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "https://explorer.example.com" {
+		t.Errorf("unexpected allowed origin: %q", origin)
+	}
+	if credentials := rec.Header().Get("Access-Control-Allow-Credentials"); credentials != "" {
+		t.Errorf("origin allowed using * is allowed to include credentials")
+	}
+
+	// no CORS headers are sent without allowed origins
+	h = CORSHandler(next, CORSPolicy{})
+	rec = corsTestRequest(h, "GET", "https://wallet.example.com", nil)
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("unexpected allowed origin with CORS disabled: %q", origin)
+	}
+}
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"github.com/threefoldtech/rivine/types"
+)
+
 // Error is a type that is encoded as JSON and returned in an API response in
 // the event of an error. Only the Message field is required. More fields may
 // be added to this struct in the future for better error reporting.
@@ -8,6 +12,17 @@ type Error struct {
 	// `err.Error()`. This field is required.
 	Message string `json:"message"`
 
+	// Code is the stable code of the (validation) error which caused this API error,
+	// allowing clients to program against failures. It is omitted if not known.
+	Code types.ErrorCode `json:"code,omitempty"`
+
+	// Input identifies the transaction input which caused this API error, if any.
+	Input *types.InputErrorContext `json:"input,omitempty"`
+
+	// RequestID identifies the request which caused this API error,
+	// allowing it to be correlated with the logs of the daemon. It is omitted if not known.
+	RequestID string `json:"requestid,omitempty"`
+
 	// TODO: add a Param field with the (omitempty option in the json tag)
 	// to indicate that the error was caused by an invalid, missing, or
 	// incorrect parameter. This is not trivial as the API does not
@@ -20,8 +35,32 @@ type Error struct {
 	// be valid or invalid depending on the current state of a module.
 }
 
+// NewError creates an API Error for the given error,
+// using the given prefix for its message and copying the
+// error code and input context from the error chain, if defined.
+func NewError(prefix string, err error) Error {
+	apiErr := Error{
+		Message: prefix + err.Error(),
+		Code:    types.ErrorCodeOf(err),
+	}
+	if ctx, ok := types.InputErrorContextOf(err); ok {
+		apiErr.Input = &ctx
+	}
+	return apiErr
+}
+
 // Error implements the error interface for the Error type. It returns only the
 // Message field.
 func (err Error) Error() string {
 	return err.Message
 }
+
+// Unwrap returns the error identified by the code of this API error,
+// such that the code can be retrieved using types.ErrorCodeOf,
+// or nil in case the code of this API error is not known.
+func (err Error) Unwrap() error {
+	if err.Code == types.ErrorCodeUnknown {
+		return nil
+	}
+	return types.NewError(err.Code, err.Message)
+}
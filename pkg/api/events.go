@@ -0,0 +1,586 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// EventTopic identifies a group of events which can be subscribed to using the "/events" websocket endpoint.
+	EventTopic string
+
+	// EventType identifies the type of an event streamed by the "/events" websocket endpoint.
+	EventType string
+
+	// Event is a JSON message streamed by the "/events" websocket endpoint.
+	Event struct {
+		Type EventType   `json:"type"`
+		Data interface{} `json:"data"`
+	}
+
+	// EventBlock is the data of an event of type EventTypeBlockApplied or EventTypeBlockReverted.
+	EventBlock struct {
+		ID     types.BlockID     `json:"id"`
+		Height types.BlockHeight `json:"height"`
+		Block  types.Block       `json:"block"`
+	}
+
+	// EventBlockSummary is the data of an event of type EventTypeBlockApplied or EventTypeBlockReverted
+	// streamed by the "/events/stream" endpoint, summarizing the block rather than containing it.
+	EventBlockSummary struct {
+		ID             types.BlockID         `json:"id"`
+		Height         types.BlockHeight     `json:"height"`
+		ParentID       types.BlockID         `json:"parentid"`
+		Timestamp      types.Timestamp       `json:"timestamp"`
+		TransactionIDs []types.TransactionID `json:"transactionids"`
+	}
+
+	// EventTransaction is the data of a transaction pool event,
+	// as well as of an event of type EventTypeWalletTransactionReverted.
+	EventTransaction struct {
+		ID          types.TransactionID `json:"id"`
+		Transaction types.Transaction   `json:"transaction"`
+	}
+
+	// EventsSubscription defines the filters of an "/events" websocket subscription.
+	// It can be defined using the query string of the websocket request,
+	// and can be replaced at any time by sending it as a JSON message over the websocket.
+	EventsSubscription struct {
+		// Topics to receive events for, all available topics are used if none are defined.
+		Topics []EventTopic `json:"topics"`
+		// UnlockHashes limits the transaction pool and wallet events
+		// to transactions referencing at least one of these unlock hashes.
+		UnlockHashes []types.UnlockHash `json:"unlockhashes,omitempty"`
+	}
+)
+
+// All topics which can be subscribed to using the "/events" websocket endpoint.
+const (
+	EventTopicConsensus       EventTopic = "consensus"
+	EventTopicTransactionPool EventTopic = "transactionpool"
+	EventTopicWallet          EventTopic = "wallet"
+)
+
+// All types of events streamed by the "/events" websocket endpoint.
+const (
+	// EventTypeSubscription is sent as acknowledgement of a (new) subscription,
+	// the data is the EventsSubscription in use.
+	EventTypeSubscription EventType = "subscription"
+	// EventTypeError is sent in case a message received from the client was invalid,
+	// the data is an Error.
+	EventTypeError EventType = "error"
+
+	EventTypeBlockApplied  EventType = "consensus.block.applied"
+	EventTypeBlockReverted EventType = "consensus.block.reverted"
+
+	EventTypeTransactionPoolTransactionAdded   EventType = "transactionpool.transaction.added"
+	EventTypeTransactionPoolTransactionRemoved EventType = "transactionpool.transaction.removed"
+
+	// the data of confirmed and unconfirmed wallet transaction events is a modules.ProcessedTransaction
+	EventTypeWalletTransactionUnconfirmed EventType = "wallet.transaction.unconfirmed"
+	EventTypeWalletTransactionConfirmed   EventType = "wallet.transaction.confirmed"
+	EventTypeWalletTransactionReverted    EventType = "wallet.transaction.reverted"
+)
+
+const (
+	// eventsUpdateBufferSize is the amount of module updates which can be buffered per connection,
+	// connections of clients which are too slow to consume their events are closed
+	eventsUpdateBufferSize = 256
+	// eventsPingInterval defines how often a ping is sent to keep the connection alive
+	eventsPingInterval = 30 * time.Second
+)
+
+// RegisterEventsHTTPHandlers registers the default Rivine handlers for the "/events" websocket endpoint
+// and the "/events/stream" server-sent events endpoint.
+// The consensus set is required, the transaction pool and wallet are optional.
+func RegisterEventsHTTPHandlers(router Router, cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet, auth *APIAuthenticator) {
+	if cs == nil {
+		build.Critical("no consensus set module given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/events", RequireScopeHandler(NewEventsHandler(cs, tpool, wallet), auth, APIScopeReadOnly))
+	router.GET("/events/stream", RequireScopeHandler(NewEventsStreamHandler(cs, tpool, wallet), auth, APIScopeReadOnly))
+}
+
+// NewEventsHandler creates a handler to handle websocket connections to "/events",
+// streaming the events of the given modules as JSON messages.
+func NewEventsHandler(cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		stream := newEventStream(cs, tpool, wallet)
+		subscription, err := parseEventsSubscriptionQuery(req)
+		if err == nil {
+			err = stream.setSubscription(subscription)
+		}
+		if err != nil {
+			WriteError(w, Error{Message: "invalid events subscription: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		conn, err := upgradeWebsocket(w, req)
+		if err != nil {
+			return // error is already written to the client, if possible
+		}
+		defer conn.Close()
+		stream.sink = websocketEventSink{conn: conn}
+		stream.serve()
+	}
+}
+
+func parseEventsSubscriptionQuery(req *http.Request) (EventsSubscription, error) {
+	var subscription EventsSubscription
+	if str := req.FormValue("topics"); str != "" {
+		for _, topic := range strings.Split(str, ",") {
+			subscription.Topics = append(subscription.Topics, EventTopic(strings.TrimSpace(topic)))
+		}
+	}
+	if str := req.FormValue("unlockhashes"); str != "" {
+		for _, s := range strings.Split(str, ",") {
+			uh, err := ScanRequestAddress(req, strings.TrimSpace(s))
+			if err != nil {
+				return EventsSubscription{}, fmt.Errorf("invalid unlock hash %q: %v", s, err)
+			}
+			subscription.UnlockHashes = append(subscription.UnlockHashes, uh)
+		}
+	}
+	return subscription, nil
+}
+
+// eventStream streams the events of a single connection.
+// Module updates are buffered, such that module subscriptions are never blocked,
+// and processed by the goroutine serving the connection.
+type eventStream struct {
+	sink   eventSink
+	cs     modules.ConsensusSet
+	tpool  modules.TransactionPool
+	wallet modules.Wallet
+
+	// since is the consensus change after which the consensus changes are streamed
+	since modules.ConsensusChangeID
+	// summarizeBlocks defines whether block events contain an EventBlockSummary rather than an EventBlock
+	summarizeBlocks bool
+
+	updates      chan eventStreamUpdate
+	overflow     chan struct{}
+	overflowOnce sync.Once
+	done         chan struct{}
+
+	// only to be accessed by the goroutine serving the connection
+	subscription          EventsSubscription
+	topics                map[EventTopic]struct{}
+	tpoolInitialized      bool
+	unconfirmedTxns       map[types.TransactionID]types.Transaction
+	unconfirmedWalletTxns map[types.TransactionID]struct{}
+}
+
+func newEventStream(cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet) *eventStream {
+	return &eventStream{
+		cs:                    cs,
+		tpool:                 tpool,
+		wallet:                wallet,
+		since:                 modules.ConsensusChangeRecent,
+		updates:               make(chan eventStreamUpdate, eventsUpdateBufferSize),
+		overflow:              make(chan struct{}),
+		done:                  make(chan struct{}),
+		unconfirmedTxns:       make(map[types.TransactionID]types.Transaction),
+		unconfirmedWalletTxns: make(map[types.TransactionID]struct{}),
+	}
+}
+
+// eventSink is the transport over which the events of an event stream are streamed.
+type eventSink interface {
+	// writeEvent writes a single event to the client.
+	writeEvent(Event) error
+	// endConsensusChange is called once all events of a consensus change are written.
+	endConsensusChange(modules.ConsensusChangeID) error
+	// ping keeps the connection alive.
+	ping() error
+	// fail ends the stream because of the given reason,
+	// using the given HTTP status if the stream isn't started yet.
+	fail(status int, reason string)
+	// messages returns the messages received from the client,
+	// the channel being closed once the client disconnected or the stream is done.
+	messages(done <-chan struct{}) <-chan []byte
+}
+
+type eventStreamUpdate struct {
+	consensusChange *modules.ConsensusChange
+	// only defined for transaction pool updates
+	unconfirmedTxns []types.Transaction
+}
+
+// ProcessConsensusChange implements modules.ConsensusSetSubscriber.ProcessConsensusChange
+func (s *eventStream) ProcessConsensusChange(cc modules.ConsensusChange) {
+	s.push(eventStreamUpdate{consensusChange: &cc})
+}
+
+// ReceiveUpdatedUnconfirmedTransactions implements modules.TransactionPoolSubscriber.ReceiveUpdatedUnconfirmedTransactions
+func (s *eventStream) ReceiveUpdatedUnconfirmedTransactions(txns []types.Transaction, _ modules.ConsensusChange) error {
+	if txns == nil {
+		txns = []types.Transaction{}
+	}
+	s.push(eventStreamUpdate{unconfirmedTxns: txns})
+	return nil
+}
+
+func (s *eventStream) push(update eventStreamUpdate) {
+	select {
+	case s.updates <- update:
+	default:
+		s.overflowOnce.Do(func() {
+			close(s.overflow)
+		})
+	}
+}
+
+func (s *eventStream) serve() {
+	defer close(s.done)
+
+	err := s.cs.ConsensusSetSubscribe(s, s.since, s.done)
+	if err == modules.ErrInvalidConsensusChangeID {
+		s.sink.fail(http.StatusBadRequest, "unknown consensus change to resume from")
+		return
+	}
+	if err != nil {
+		s.sink.fail(http.StatusInternalServerError, "failed to subscribe to consensus set")
+		return
+	}
+	defer s.cs.Unsubscribe(s)
+	select {
+	case <-s.overflow:
+		// all consensus changes to resume from are buffered while subscribing
+		s.sink.fail(http.StatusGone, "too many consensus changes to resume from")
+		return
+	default:
+	}
+	if s.tpool != nil {
+		s.tpool.TransactionPoolSubscribe(s)
+		defer s.tpool.Unsubscribe(s)
+	}
+
+	if err = s.sink.writeEvent(Event{Type: EventTypeSubscription, Data: s.subscription}); err != nil {
+		return
+	}
+
+	messages := s.sink.messages(s.done)
+	ticker := time.NewTicker(eventsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case update := <-s.updates:
+			if update.consensusChange != nil {
+				err = s.processConsensusChange(*update.consensusChange)
+				if err == nil {
+					err = s.sink.endConsensusChange(update.consensusChange.ID)
+				}
+			} else {
+				err = s.processUnconfirmedTransactions(update.unconfirmedTxns)
+			}
+		case msg, ok := <-messages:
+			if !ok {
+				return // connection closed by client
+			}
+			err = s.processMessage(msg)
+		case <-ticker.C:
+			err = s.sink.ping()
+		case <-s.overflow:
+			s.sink.fail(http.StatusServiceUnavailable, "client is too slow to consume events")
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// processMessage replaces the subscription with the one defined by the received JSON message.
+func (s *eventStream) processMessage(msg []byte) error {
+	var subscription EventsSubscription
+	err := json.Unmarshal(msg, &subscription)
+	if err == nil {
+		err = s.setSubscription(subscription)
+	}
+	if err != nil {
+		return s.sink.writeEvent(Event{
+			Type: EventTypeError,
+			Data: Error{Message: "invalid events subscription: " + err.Error()},
+		})
+	}
+	return s.sink.writeEvent(Event{Type: EventTypeSubscription, Data: s.subscription})
+}
+
+func (s *eventStream) setSubscription(subscription EventsSubscription) error {
+	if len(subscription.Topics) == 0 {
+		subscription.Topics = append(subscription.Topics, EventTopicConsensus)
+		if s.tpool != nil {
+			subscription.Topics = append(subscription.Topics, EventTopicTransactionPool)
+		}
+		if s.wallet != nil {
+			subscription.Topics = append(subscription.Topics, EventTopicWallet)
+		}
+	}
+	topics := make(map[EventTopic]struct{}, len(subscription.Topics))
+	for _, topic := range subscription.Topics {
+		switch topic {
+		case EventTopicConsensus:
+		case EventTopicTransactionPool:
+			if s.tpool == nil {
+				return errors.New("transaction pool module is not loaded")
+			}
+		case EventTopicWallet:
+			if s.wallet == nil {
+				return errors.New("wallet module is not loaded")
+			}
+		default:
+			return fmt.Errorf("unknown topic %q", topic)
+		}
+		topics[topic] = struct{}{}
+	}
+	s.subscription = subscription
+	s.topics = topics
+	return nil
+}
+
+func (s *eventStream) subscribed(topic EventTopic) bool {
+	_, ok := s.topics[topic]
+	return ok
+}
+
+func (s *eventStream) processConsensusChange(cc modules.ConsensusChange) error {
+	for _, block := range cc.RevertedBlocks {
+		if s.subscribed(EventTopicConsensus) {
+			if err := s.writeBlockEvent(EventTypeBlockReverted, block); err != nil {
+				return err
+			}
+		}
+		if !s.subscribed(EventTopicWallet) {
+			continue
+		}
+		for _, txn := range block.Transactions {
+			if !s.isWalletTransaction(txn) {
+				continue
+			}
+			err := s.sink.writeEvent(Event{
+				Type: EventTypeWalletTransactionReverted,
+				Data: EventTransaction{ID: txn.ID(), Transaction: txn},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, block := range cc.AppliedBlocks {
+		if s.subscribed(EventTopicConsensus) {
+			if err := s.writeBlockEvent(EventTypeBlockApplied, block); err != nil {
+				return err
+			}
+		}
+		if !s.subscribed(EventTopicWallet) {
+			continue
+		}
+		for _, txn := range block.Transactions {
+			// the wallet processed the consensus change prior to this stream,
+			// as it subscribed to the consensus set earlier
+			pt, found, err := s.wallet.Transaction(txn.ID())
+			if err != nil || !found || !s.matchesProcessedTransaction(pt) {
+				continue
+			}
+			err = s.sink.writeEvent(Event{Type: EventTypeWalletTransactionConfirmed, Data: pt})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *eventStream) writeBlockEvent(eventType EventType, block types.Block) error {
+	height, _ := s.cs.BlockHeightOfBlock(block)
+	if s.summarizeBlocks {
+		summary := EventBlockSummary{
+			ID:             block.ID(),
+			Height:         height,
+			ParentID:       block.ParentID,
+			Timestamp:      block.Timestamp,
+			TransactionIDs: make([]types.TransactionID, 0, len(block.Transactions)),
+		}
+		for _, txn := range block.Transactions {
+			summary.TransactionIDs = append(summary.TransactionIDs, txn.ID())
+		}
+		return s.sink.writeEvent(Event{Type: eventType, Data: summary})
+	}
+	return s.sink.writeEvent(Event{
+		Type: eventType,
+		Data: EventBlock{
+			ID:     block.ID(),
+			Height: height,
+			Block:  block,
+		},
+	})
+}
+
+// processUnconfirmedTransactions streams the transactions added to and removed from the transaction pool,
+// as well as the new unconfirmed wallet transactions. The first update only defines the initial state.
+func (s *eventStream) processUnconfirmedTransactions(txns []types.Transaction) error {
+	initialized := s.tpoolInitialized
+	s.tpoolInitialized = true
+
+	unconfirmedTxns := make(map[types.TransactionID]types.Transaction, len(txns))
+	for _, txn := range txns {
+		id := txn.ID()
+		unconfirmedTxns[id] = txn
+		if _, ok := s.unconfirmedTxns[id]; ok || !initialized || !s.subscribed(EventTopicTransactionPool) || !s.matchesTransaction(txn) {
+			continue
+		}
+		err := s.sink.writeEvent(Event{
+			Type: EventTypeTransactionPoolTransactionAdded,
+			Data: EventTransaction{ID: id, Transaction: txn},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	previousTxns := s.unconfirmedTxns
+	s.unconfirmedTxns = unconfirmedTxns
+	if s.subscribed(EventTopicTransactionPool) {
+		for id, txn := range previousTxns {
+			if _, ok := unconfirmedTxns[id]; ok || !s.matchesTransaction(txn) {
+				continue
+			}
+			err := s.sink.writeEvent(Event{
+				Type: EventTypeTransactionPoolTransactionRemoved,
+				Data: EventTransaction{ID: id, Transaction: txn},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.wallet == nil {
+		return nil
+	}
+	pts, err := s.wallet.UnconfirmedTransactions()
+	if err != nil {
+		return nil // wallet is locked, try again on the next update
+	}
+	unconfirmedWalletTxns := make(map[types.TransactionID]struct{}, len(pts))
+	for _, pt := range pts {
+		unconfirmedWalletTxns[pt.TransactionID] = struct{}{}
+		if _, ok := s.unconfirmedWalletTxns[pt.TransactionID]; ok || !initialized || !s.subscribed(EventTopicWallet) || !s.matchesProcessedTransaction(pt) {
+			continue
+		}
+		err = s.sink.writeEvent(Event{Type: EventTypeWalletTransactionUnconfirmed, Data: pt})
+		if err != nil {
+			return err
+		}
+	}
+	s.unconfirmedWalletTxns = unconfirmedWalletTxns
+	return nil
+}
+
+// matchesTransaction returns true if the transaction references
+// any of the unlock hashes of the subscription, or if it doesn't define any.
+func (s *eventStream) matchesTransaction(txn types.Transaction) bool {
+	if len(s.subscription.UnlockHashes) == 0 {
+		return true
+	}
+	for _, uh := range s.subscription.UnlockHashes {
+		if isUnlockHashInTransaction(s.cs, uh, txn) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesProcessedTransaction returns true if the wallet transaction references
+// any of the unlock hashes of the subscription, or if it doesn't define any.
+func (s *eventStream) matchesProcessedTransaction(pt modules.ProcessedTransaction) bool {
+	if len(s.subscription.UnlockHashes) == 0 {
+		return true
+	}
+	for _, uh := range s.subscription.UnlockHashes {
+		for _, input := range pt.Inputs {
+			if input.RelatedAddress == uh {
+				return true
+			}
+		}
+		for _, output := range pt.Outputs {
+			if output.RelatedAddress == uh {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isWalletTransaction returns true if a (reverted) transaction references an address of the wallet,
+// and matches the subscription. As the wallet no longer tracks reverted transactions,
+// the consensus set is used to look up the (restored) parent outputs.
+func (s *eventStream) isWalletTransaction(txn types.Transaction) bool {
+	if !s.matchesTransaction(txn) {
+		return false
+	}
+	addresses, err := s.wallet.AllAddresses()
+	if err != nil {
+		return false
+	}
+	for _, uh := range addresses {
+		if isUnlockHashInTransaction(s.cs, uh, txn) {
+			return true
+		}
+	}
+	return false
+}
+
+// websocketEventSink streams events as JSON messages over a websocket connection,
+// over which the client can replace its subscription.
+type websocketEventSink struct {
+	conn *websocketConn
+}
+
+func (ws websocketEventSink) writeEvent(event Event) error {
+	return ws.conn.WriteJSON(event)
+}
+
+func (ws websocketEventSink) endConsensusChange(modules.ConsensusChangeID) error {
+	return nil
+}
+
+func (ws websocketEventSink) ping() error {
+	return ws.conn.Ping()
+}
+
+func (ws websocketEventSink) fail(_ int, reason string) {
+	ws.conn.CloseWithReason(websocketClosePolicyViolation, reason)
+}
+
+func (ws websocketEventSink) messages(done <-chan struct{}) <-chan []byte {
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		for {
+			msg, err := ws.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return messages
+}
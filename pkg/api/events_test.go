@@ -0,0 +1,318 @@
+package api
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// eventsTestConsensusSet is a minimal in-memory consensus set,
+// only implementing the methods used by the event streams.
+type eventsTestConsensusSet struct {
+	modules.ConsensusSet
+
+	mu          sync.Mutex
+	changes     []modules.ConsensusChange
+	heights     map[types.BlockID]types.BlockHeight
+	subscribers []modules.ConsensusSetSubscriber
+}
+
+func newEventsTestConsensusSet() *eventsTestConsensusSet {
+	return &eventsTestConsensusSet{heights: make(map[types.BlockID]types.BlockHeight)}
+}
+
+// applyBlock applies a new (empty) block, notifying all subscribers.
+func (cs *eventsTestConsensusSet) applyBlock() (modules.ConsensusChange, types.Block) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	height := types.BlockHeight(len(cs.changes))
+	block := types.Block{Timestamp: types.Timestamp(height + 1)}
+	if height > 0 {
+		block.ParentID = cs.changes[height-1].AppliedBlocks[0].ID()
+	}
+	cs.heights[block.ID()] = height
+	cc := modules.ConsensusChange{
+		ID:            modules.ConsensusChangeID(block.ID()),
+		AppliedBlocks: []types.Block{block},
+	}
+	cs.changes = append(cs.changes, cc)
+	for _, subscriber := range cs.subscribers {
+		subscriber.ProcessConsensusChange(cc)
+	}
+	return cc, block
+}
+
+func (cs *eventsTestConsensusSet) ConsensusSetSubscribe(subscriber modules.ConsensusSetSubscriber, start modules.ConsensusChangeID, _ <-chan struct{}) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	var index int
+	switch start {
+	case modules.ConsensusChangeBeginning:
+	case modules.ConsensusChangeRecent:
+		index = len(cs.changes)
+	default:
+		index = -1
+		for i, cc := range cs.changes {
+			if cc.ID == start {
+				index = i + 1
+			}
+		}
+		if index < 0 {
+			return modules.ErrInvalidConsensusChangeID
+		}
+	}
+	for _, cc := range cs.changes[index:] {
+		subscriber.ProcessConsensusChange(cc)
+	}
+	cs.subscribers = append(cs.subscribers, subscriber)
+	return nil
+}
+
+func (cs *eventsTestConsensusSet) Unsubscribe(subscriber modules.ConsensusSetSubscriber) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for i := range cs.subscribers {
+		if cs.subscribers[i] == subscriber {
+			cs.subscribers = append(cs.subscribers[:i], cs.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (cs *eventsTestConsensusSet) subscriberCount() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return len(cs.subscribers)
+}
+
+func (cs *eventsTestConsensusSet) BlockHeightOfBlock(block types.Block) (types.BlockHeight, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	height, ok := cs.heights[block.ID()]
+	return height, ok
+}
+
+// websocketTestClient is a minimal websocket client, sufficient to test the events endpoint.
+type websocketTestClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWebsocketTest opens a websocket connection to the given path of the test server.
+func dialWebsocketTest(t *testing.T, server *httptest.Server, path string) *websocketTestClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: %s\r\n\r\n", path, key)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status %d upgrading to a websocket, not %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected websocket accept key: %q", accept)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return &websocketTestClient{conn: conn, reader: reader}
+}
+
+// writeFrame writes a single final frame, masked unless specified otherwise.
+func (c *websocketTestClient) writeFrame(opcode byte, payload []byte, masked bool) error {
+	frame := []byte{0x80 | opcode, byte(len(payload))}
+	if !masked {
+		frame = append(frame, payload...)
+	} else {
+		mask := []byte{1, 2, 3, 4}
+		frame[1] |= 0x80
+		frame = append(frame, mask...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readFrame reads a single (unmasked) frame written by the server.
+func (c *websocketTestClient) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, length)
+	_, err := io.ReadFull(c.reader, payload)
+	return header[0] & 0x0F, payload, err
+}
+
+// readEvent reads the next event, decoding its data into the given value.
+func (c *websocketTestClient) readEvent(t *testing.T, data interface{}) EventType {
+	opcode, payload, err := c.readFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opcode != websocketOpText {
+		t.Fatalf("expected a text frame, not opcode %d: %s", opcode, payload)
+	}
+	var event struct {
+		Type EventType       `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err = json.Unmarshal(payload, &event); err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		if err = json.Unmarshal(event.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return event.Type
+}
+
+func newEventsTestServer(cs modules.ConsensusSet) *httptest.Server {
+	router := httprouter.New()
+	RegisterEventsHTTPHandlers(router, cs, nil, nil, nil)
+	return httptest.NewServer(router)
+}
+
+func TestEventsHandlerWebsocket(t *testing.T) {
+	cs := newEventsTestConsensusSet()
+	server := newEventsTestServer(cs)
+	defer server.Close()
+
+	client := dialWebsocketTest(t, server, "/events")
+	defer client.conn.Close()
+	var subscription EventsSubscription
+	if eventType := client.readEvent(t, &subscription); eventType != EventTypeSubscription {
+		t.Fatalf("expected a %s event, not %s", EventTypeSubscription, eventType)
+	}
+	// only the consensus topic is available without transaction pool or wallet
+	if len(subscription.Topics) != 1 || subscription.Topics[0] != EventTopicConsensus {
+		t.Errorf("unexpected default subscription: %+v", subscription)
+	}
+
+	// applied blocks are streamed
+	for i := 0; i < 2; i++ {
+		_, block := cs.applyBlock()
+		var eb EventBlock
+		if eventType := client.readEvent(t, &eb); eventType != EventTypeBlockApplied {
+			t.Fatalf("expected a %s event, not %s", EventTypeBlockApplied, eventType)
+		}
+		if eb.ID != block.ID() || eb.Height != types.BlockHeight(i) {
+			t.Errorf("unexpected block event: %v at height %d", eb.ID, eb.Height)
+		}
+	}
+
+	// an invalid subscription is answered with an error, while the stream continues
+	client.writeFrame(websocketOpText, []byte(`{"topics":["wallet"]}`), true)
+	var apiErr Error
+	if eventType := client.readEvent(t, &apiErr); eventType != EventTypeError || !strings.Contains(apiErr.Message, "wallet") {
+		t.Errorf("unexpected event for an invalid subscription: %s: %v", eventType, apiErr.Message)
+	}
+	client.writeFrame(websocketOpText, []byte(`{"topics":["consensus"]}`), true)
+	if eventType := client.readEvent(t, nil); eventType != EventTypeSubscription {
+		t.Errorf("expected a %s event for a new subscription, not %s", EventTypeSubscription, eventType)
+	}
+
+	// control frames are handled by the server
+	client.writeFrame(websocketOpPing, []byte("ping"), true)
+	if opcode, payload, err := client.readFrame(); err != nil || opcode != websocketOpPong || string(payload) != "ping" {
+		t.Errorf("unexpected answer to a ping: %d: %q (%v)", opcode, payload, err)
+	}
+	client.writeFrame(websocketOpClose, nil, true)
+	if opcode, _, err := client.readFrame(); err != nil || opcode != websocketOpClose {
+		t.Errorf("unexpected answer to a close frame: %d (%v)", opcode, err)
+	}
+	// the stream unsubscribes once the connection is closed
+	for i := 0; cs.subscriberCount() != 0; i++ {
+		if i == 100 {
+			t.Fatal("events stream didn't unsubscribe from the consensus set")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEventsHandlerErrors(t *testing.T) {
+	cs := newEventsTestConsensusSet()
+	server := newEventsTestServer(cs)
+	defer server.Close()
+
+	// invalid subscriptions are rejected before upgrading the connection
+	testCases := []struct {
+		query  string
+		status int
+	}{
+		{"topics=unknown", http.StatusBadRequest},
+		{"topics=transactionpool", http.StatusBadRequest},
+		{"unlockhashes=invalid", http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", "/events?"+testCase.query, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		rec := httptest.NewRecorder()
+		server.Config.Handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.status {
+			t.Errorf("%s: expected status %d, not %d", testCase.query, testCase.status, rec.Code)
+		}
+	}
+
+	// requests which are not a valid websocket upgrade are rejected
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d for a plain GET request, not %d", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	// unmasked client frames are a protocol error, closing the connection
+	client := dialWebsocketTest(t, server, "/events")
+	defer client.conn.Close()
+	client.readEvent(t, nil)
+	client.writeFrame(websocketOpText, []byte(`{}`), false)
+	opcode, payload, err := client.readFrame()
+	if err != nil || opcode != websocketOpClose || len(payload) < 2 {
+		t.Fatalf("expected a close frame, not opcode %d (%v)", opcode, err)
+	}
+	if code := binary.BigEndian.Uint16(payload); code != websocketCloseProtocolError {
+		t.Errorf("expected close code %d, not %d", websocketCloseProtocolError, code)
+	}
+}
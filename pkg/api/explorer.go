@@ -15,11 +15,11 @@ import (
 
 // hash type string constants
 const (
-	HashTypeTransactionIDStr      = "transactionid"
-	HashTypeCoinOutputIDStr       = "coinoutputid"
-	HashTypeBlockStakeOutputIDStr = "blockstakeoutputid"
+	HashTypeTransactionIDStr      = modules.ExplorerHashTypeTransactionID
+	HashTypeCoinOutputIDStr       = modules.ExplorerHashTypeCoinOutputID
+	HashTypeBlockStakeOutputIDStr = modules.ExplorerHashTypeBlockStakeOutputID
 	HashTypeUnlockHashStr         = "unlockhash"
-	HashTypeBlockIDStr            = "blockid"
+	HashTypeBlockIDStr            = modules.ExplorerHashTypeBlockID
 )
 
 type (
@@ -203,6 +203,27 @@ type (
 		Block ExplorerBlock `json:"block"`
 	}
 
+	// ExplorerRichListGET is the object returned by a GET request to
+	// /explorer/richlist/:currency, containing the unlock hashes with the highest balance.
+	ExplorerRichListGET struct {
+		Height    types.BlockHeight           `json:"height"`
+		Addresses []modules.UnlockHashBalance `json:"addresses"`
+	}
+
+	// ExplorerDistributionGET is the object returned by a GET request to
+	// /explorer/distribution/:currency, describing how the currency is distributed over all unlock hashes.
+	ExplorerDistributionGET struct {
+		Height types.BlockHeight `json:"height"`
+		modules.BalanceDistribution
+	}
+
+	// ExplorerBlocksGET is the object returned by a GET request to
+	// /explorer/blocks, containing a page of the blocks within the requested height range.
+	ExplorerBlocksGET struct {
+		Blocks []ExplorerBlock `json:"blocks"`
+		PageInfo
+	}
+
 	// ExplorerHashGET is the object returned as a response to a GET request to
 	// /explorer/hash. The HashType will indicate whether the hash corresponds
 	// to a block id, a transaction id, a siacoin output id, a file contract
@@ -211,8 +232,10 @@ type (
 	// a transaction id, 'Transaction' will be filled out and all the rest of
 	// the fields will be blank. For everything else, 'Transactions' and
 	// 'Blocks' will/may be filled out and everything else will be blank.
+	// Hash is the full hash or address that was looked up, which is useful in case a hash prefix was looked up.
 	ExplorerHashGET struct {
 		HashType          string                `json:"hashtype"`
+		Hash              string                `json:"hash"`
 		Block             ExplorerBlock         `json:"block"`
 		Blocks            []ExplorerBlock       `json:"blocks"`
 		Transaction       ExplorerTransaction   `json:"transaction"`
@@ -235,14 +258,87 @@ func RegisterExplorerHTTPHandlers(router Router, cs modules.ConsensusSet, explor
 	}
 
 	router.GET("/explorer", NewExplorerRootHandler(explorer))
+	router.GET("/explorer/blocks", NewExplorerBlockRangeHandler(cs, explorer))
 	router.GET("/explorer/blocks/:height", NewExplorerBlocksHandler(cs, explorer))
 	router.GET("/explorer/hashes/:hash", NewExplorerHashHandler(explorer, tpool))
+	router.GET("/explorer/search", NewExplorerSearchHandler(cs, explorer, tpool))
+	router.GET("/explorer/unspent", NewExplorerUnspentOutputsHandler(cs, explorer))
+	router.POST("/explorer/unspent", NewExplorerUnspentOutputsHandler(cs, explorer))
 	router.GET("/explorer/stats/history", NewExplorerHistoryStatsHandler(explorer))
 	router.GET("/explorer/stats/range", NewExplorerRangeStatsHandler(explorer))
 	router.GET("/explorer/constants", NewExplorerConstantsHandler(explorer))
+	router.GET("/explorer/richlist/:currency", NewExplorerRichListHandler(explorer))
+	router.GET("/explorer/distribution/:currency", NewExplorerDistributionHandler(explorer))
 	router.GET("/explorer/downloader/status", NewConsensusRootHandler(cs))
 }
 
+// the currencies for which a rich list and distribution can be requested
+const (
+	explorerCurrencyCoins       = "coins"
+	explorerCurrencyBlockStakes = "blockstakes"
+)
+
+const (
+	// DefaultRichListLimit is the amount of unlock hashes returned by the rich list in case no limit is given.
+	DefaultRichListLimit = 100
+	// MaxRichListLimit is the maximum amount of unlock hashes returned by the rich list.
+	MaxRichListLimit = 1000
+)
+
+// NewExplorerRichListHandler creates a handler to handle API calls to /explorer/richlist/:currency,
+// returning the unlock hashes with the highest coin or block stake balance.
+func NewExplorerRichListHandler(explorer modules.Explorer) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		limit := DefaultRichListLimit
+		if str := req.FormValue("limit"); str != "" {
+			n, err := strconv.Atoi(str)
+			if err != nil || n <= 0 {
+				WriteError(w, Error{Message: "invalid limit: has to be a positive integer"}, http.StatusBadRequest)
+				return
+			}
+			if n > MaxRichListLimit {
+				n = MaxRichListLimit
+			}
+			limit = n
+		}
+		var addresses []modules.UnlockHashBalance
+		switch currency := ps.ByName("currency"); currency {
+		case explorerCurrencyCoins:
+			addresses = explorer.CoinRichList(limit)
+		case explorerCurrencyBlockStakes:
+			addresses = explorer.BlockStakeRichList(limit)
+		default:
+			WriteError(w, Error{Message: fmt.Sprintf("invalid currency %q: expected %q or %q", currency, explorerCurrencyCoins, explorerCurrencyBlockStakes)}, http.StatusBadRequest)
+			return
+		}
+		WriteJSON(w, ExplorerRichListGET{
+			Height:    explorer.LatestBlockFacts().Height,
+			Addresses: addresses,
+		})
+	}
+}
+
+// NewExplorerDistributionHandler creates a handler to handle API calls to /explorer/distribution/:currency,
+// returning how the coins or block stakes are distributed over all unlock hashes.
+func NewExplorerDistributionHandler(explorer modules.Explorer) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var distribution modules.BalanceDistribution
+		switch currency := ps.ByName("currency"); currency {
+		case explorerCurrencyCoins:
+			distribution = explorer.CoinDistribution()
+		case explorerCurrencyBlockStakes:
+			distribution = explorer.BlockStakeDistribution()
+		default:
+			WriteError(w, Error{Message: fmt.Sprintf("invalid currency %q: expected %q or %q", currency, explorerCurrencyCoins, explorerCurrencyBlockStakes)}, http.StatusBadRequest)
+			return
+		}
+		WriteJSON(w, ExplorerDistributionGET{
+			Height:              explorer.LatestBlockFacts().Height,
+			BalanceDistribution: distribution,
+		})
+	}
+}
+
 // NewExplorerBlocksHandler creates a handler to handle API calls to /explorer/blocks/:height.
 func NewExplorerBlocksHandler(cs modules.ConsensusSet, explorer modules.Explorer) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
@@ -250,32 +346,80 @@ func NewExplorerBlocksHandler(cs modules.ConsensusSet, explorer modules.Explorer
 		var height types.BlockHeight
 		_, err := fmt.Sscan(ps.ByName("height"), &height)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		// Fetch and return the explorer block.
 		block, exists := cs.BlockAtHeight(height)
 		if !exists {
-			WriteError(w, Error{"no block found at input height in call to /explorer/block"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "no block found at input height in call to /explorer/block"}, http.StatusBadRequest)
 			return
 		}
+		if IsImmutableHeight(height, cs.Height()) {
+			SetImmutable(w)
+		}
 		WriteJSON(w, ExplorerBlockGET{
 			Block: BuildExplorerBlock(explorer, height, block),
 		})
 	}
 }
 
+// NewExplorerBlockRangeHandler creates a handler to handle GET requests to /explorer/blocks,
+// returning a page of the explorer blocks within the requested height range.
+func NewExplorerBlockRangeHandler(cs modules.ConsensusSet, explorer modules.Explorer) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		pagination, err := ParsePagination(req, DefaultPaginationLimit, MaxPaginationLimit)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		start, end, err := ParseHeightRange(req, cs.Height())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		heights, pageInfo := paginatedHeights(start, end, pagination)
+		blocks := make([]ExplorerBlock, 0, len(heights))
+		for _, height := range heights {
+			block, exists := cs.BlockAtHeight(height)
+			if !exists {
+				// the current path was shortened by a reorg since the height range was defined
+				continue
+			}
+			blocks = append(blocks, BuildExplorerBlock(explorer, height, block))
+		}
+		WriteJSON(w, ExplorerBlocksGET{
+			Blocks:   blocks,
+			PageInfo: pageInfo,
+		})
+	}
+}
+
 // NewExplorerHashHandler creates a handler to handle GET requests to /explorer/hash/:hash.
+// Besides a full hash or address, an unambiguous prefix of a block, transaction or output ID can be looked up.
+// Lookups of a confirmed block or transaction by its full ID are immutable.
 func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.TransactionPool) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hashStr := ps.ByName("hash")
+		// a prefix can become ambiguous, and its lookup is thus never immutable
+		isPrefix := len(hashStr) < crypto.HashSize*2
+		if isPrefix {
+			match, err := resolveHashPrefix(explorer, tpool, hashStr)
+			if err != nil {
+				WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+			hashStr = match.Hash.String()
+		}
+
 		// Scan the hash as a hash. If that fails, try scanning the hash as an
 		// address.
-		hash, err := ScanHash(ps.ByName("hash"))
+		hash, err := ScanHash(hashStr)
 		if err != nil {
-			addr, err := ScanAddress(ps.ByName("hash"))
+			addr, err := ScanRequestAddress(req, hashStr)
 			if err != nil {
-				WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 				return
 			}
 
@@ -297,7 +441,7 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 				if str := req.FormValue("minheight"); str != "" {
 					n, err := strconv.ParseUint(str, 10, 64)
 					if err != nil {
-						WriteError(w, Error{"invalid minheight filter: " + err.Error()}, http.StatusBadRequest)
+						WriteError(w, Error{Message: "invalid minheight filter: " + err.Error()}, http.StatusBadRequest)
 						return
 					}
 					filters.MinBlockHeight = types.BlockHeight(n)
@@ -311,6 +455,7 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 			if len(txns) != 0 || len(blocks) != 0 || len(multiSigAddresses) != 0 {
 				WriteJSON(w, ExplorerHashGET{
 					HashType:          HashTypeUnlockHashStr,
+					Hash:              hashStr,
 					Blocks:            blocks,
 					Transactions:      txns,
 					MultiSigAddresses: multiSigAddresses,
@@ -319,22 +464,26 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 			}
 
 			// Hash not found, return an error.
-			WriteError(w, Error{"no transactions or blocks found for given address"}, http.StatusNoContent)
+			WriteError(w, Error{Message: "no transactions or blocks found for given address"}, http.StatusNoContent)
 			return
 		}
 
 		// TODO: lookups on the zero hash are too expensive to allow. Need a
 		// better way to handle this case.
 		if hash == (crypto.Hash{}) {
-			WriteError(w, Error{"can't lookup the empty unlock hash"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "can't lookup the empty unlock hash"}, http.StatusBadRequest)
 			return
 		}
 
 		// Try the hash as a block id.
 		block, height, exists := explorer.Block(types.BlockID(hash))
 		if exists {
+			if !isPrefix && IsImmutableHeight(height, explorer.LatestBlockFacts().Height) {
+				SetImmutable(w)
+			}
 			WriteJSON(w, ExplorerHashGET{
 				HashType: HashTypeBlockIDStr,
+				Hash:     hashStr,
 				Block:    BuildExplorerBlock(explorer, height, block),
 			})
 			return
@@ -349,8 +498,12 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 					txn = t
 				}
 			}
+			if !isPrefix && IsImmutableHeight(height, explorer.LatestBlockFacts().Height) {
+				SetImmutable(w)
+			}
 			WriteJSON(w, ExplorerHashGET{
 				HashType:    HashTypeTransactionIDStr,
+				Hash:        hashStr,
 				Transaction: BuildExplorerTransaction(explorer, height, block.ID(), txn),
 			})
 			return
@@ -362,6 +515,7 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 			txns, blocks := BuildTransactionSet(explorer, txids, TransactionSetFilters{})
 			WriteJSON(w, ExplorerHashGET{
 				HashType:     HashTypeCoinOutputIDStr,
+				Hash:         hashStr,
 				Blocks:       blocks,
 				Transactions: txns,
 			})
@@ -374,6 +528,7 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 			txns, blocks := BuildTransactionSet(explorer, txids, TransactionSetFilters{})
 			WriteJSON(w, ExplorerHashGET{
 				HashType:     HashTypeBlockStakeOutputIDStr,
+				Hash:         hashStr,
 				Blocks:       blocks,
 				Transactions: txns,
 			})
@@ -387,21 +542,21 @@ func NewExplorerHashHandler(explorer modules.Explorer, tpool modules.Transaction
 			if err == nil {
 				WriteJSON(w, ExplorerHashGET{
 					HashType:    HashTypeTransactionIDStr,
+					Hash:        hashStr,
 					Transaction: BuildExplorerTransaction(explorer, 0, types.BlockID{}, txn),
 					Unconfirmed: true,
 				})
 				return
 			}
 			if err != modules.ErrTransactionNotFound {
-				WriteError(w, Error{
-					"error during call to /explorer/hash: failed to get txn from transaction pool: " + err.Error()},
+				WriteError(w, Error{Message: "error during call to /explorer/hash: failed to get txn from transaction pool: " + err.Error()},
 					http.StatusInternalServerError)
 				return
 			}
 		}
 
 		// Hash not found, return an error.
-		WriteError(w, Error{"unrecognized hash used as input to /explorer/hash"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unrecognized hash used as input to /explorer/hash"}, http.StatusBadRequest)
 	}
 }
 
@@ -430,12 +585,12 @@ func NewExplorerHistoryStatsHandler(explorer modules.Explorer) httprouter.Handle
 		q := req.URL.Query()
 		_, err := fmt.Sscan(q.Get("history"), &history)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		stats, err := explorer.HistoryStats(history)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteJSON(w, stats)
@@ -450,17 +605,17 @@ func NewExplorerRangeStatsHandler(explorer modules.Explorer) httprouter.Handle {
 		q := req.URL.Query()
 		_, err := fmt.Sscan(q.Get("start"), &start)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		_, err = fmt.Sscan(q.Get("end"), &end)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		stats, err := explorer.RangeStats(start, end)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteJSON(w, stats)
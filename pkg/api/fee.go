@@ -0,0 +1,157 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// DefaultFeeTarget is the amount of blocks a transaction is recommended a fee for
+	// to be confirmed within, in case no target is given.
+	DefaultFeeTarget = 3
+	// MaxFeeTarget is the maximum amount of blocks a fee can be recommended for.
+	MaxFeeTarget = 100
+	// DefaultFeeTransactionSize is the (siabin) encoded size in bytes of the transaction a fee is recommended for,
+	// in case no size is given, which is roughly the size of a signed transaction with two inputs and two outputs.
+	DefaultFeeTransactionSize = 400
+
+	// feeRecentBlocks is the amount of recent blocks taken into account to recommend a fee
+	feeRecentBlocks = 10
+	// blockReservedSize is the size reserved by the block creator for the block itself,
+	// the remainder of the block size limit being available for transactions
+	blockReservedSize = 5e3
+)
+
+// TransactionPoolFeeGET contains the fields returned by a GET call to "/transactionpool/fee".
+type TransactionPoolFeeGET struct {
+	// Target is the amount of blocks the transaction is to be confirmed within
+	Target uint64 `json:"target"`
+	// Size is the (siabin) encoded size in bytes of the transaction the fee is recommended for
+	Size uint64 `json:"size"`
+	// Fee is the recommended fee
+	Fee types.Currency `json:"fee"`
+	// MinimumFee is the minimum fee required for a transaction of the given size
+	MinimumFee types.Currency `json:"minimumfee"`
+	// PoolTransactions and PoolSize are the amount and total size of the transactions
+	// currently in the transaction pool
+	PoolTransactions int    `json:"pooltransactions"`
+	PoolSize         uint64 `json:"poolsize"`
+}
+
+// NewTransactionPoolFeeHandler creates a handler to handle the API call to GET /transactionpool/fee,
+// recommending a fee for a transaction to be confirmed within the target amount of blocks.
+func NewTransactionPoolFeeHandler(cs modules.ConsensusSet, tpool modules.TransactionPool, chainCts types.ChainConstants) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		target, err := parseFeeParameter(req, "target", DefaultFeeTarget, MaxFeeTarget)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		size, err := parseFeeParameter(req, "size", DefaultFeeTransactionSize, uint64(chainCts.TransactionPool.TransactionSizeLimit))
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		packages, err := modules.NewTransactionPackages(tpool.TransactionList())
+		if err != nil {
+			WriteError(w, Error{Message: "failed to compute the transaction pool packages: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		WriteJSON(w, recommendFee(cs, packages, chainCts, target, size))
+	}
+}
+
+// parseFeeParameter parses a positive integer query string parameter, capped to the given maximum.
+func parseFeeParameter(req *http.Request, name string, def, max uint64) (uint64, error) {
+	str := req.FormValue(name)
+	if str == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(str, 10, 64)
+	if err != nil || n == 0 {
+		return 0, errors.New("invalid " + name + ": expected a positive integer")
+	}
+	if n > max {
+		n = max
+	}
+	return n, nil
+}
+
+// recommendFee recommends a fee for a transaction of the given size to be confirmed within the target amount of blocks.
+// The block creator includes transaction packages ordered by their fee rate, such that the transaction
+// has to pay a higher fee rate than all packages which would otherwise not fit in the target amount of blocks.
+// Should recent blocks have been (nearly) full, the transaction has to pay a higher fee rate
+// than the lowest one included in those blocks as well, as the pool might not reflect the congestion yet.
+func recommendFee(cs modules.ConsensusSet, packages []modules.TransactionPackage, chainCts types.ChainConstants, target, size uint64) TransactionPoolFeeGET {
+	minimumFee := chainCts.TransactionFee(int(size))
+	resp := TransactionPoolFeeGET{
+		Target:     target,
+		Size:       size,
+		Fee:        minimumFee,
+		MinimumFee: minimumFee,
+	}
+	raise := func(fees types.Currency, feesSize uint64) {
+		if feesSize == 0 {
+			return
+		}
+		// pay one hasting more than the given fee rate
+		fee := fees.Mul64(size).Div64(feesSize).Add(types.NewCurrency64(1))
+		if fee.Cmp(resp.Fee) > 0 {
+			resp.Fee = fee
+		}
+	}
+
+	capacity := uint64(chainCts.BlockSizeLimit - blockReservedSize)
+	var competing *modules.TransactionPackage
+	for i := range packages {
+		resp.PoolTransactions += len(packages[i].Transactions)
+		resp.PoolSize += packages[i].Size
+		if competing == nil && resp.PoolSize > capacity*target {
+			competing = &packages[i]
+		}
+	}
+	if competing != nil {
+		raise(competing.Fees, competing.Size)
+	}
+
+	height := cs.Height()
+	for i := uint64(0); i < feeRecentBlocks && types.BlockHeight(i) <= height; i++ {
+		block, ok := cs.BlockAtHeight(height - types.BlockHeight(i))
+		if !ok {
+			break
+		}
+		var (
+			blockSize          uint64
+			lowestFees         types.Currency
+			lowestSize         uint64
+			lowestRateDetected bool
+		)
+		for _, txn := range block.Transactions {
+			b, err := siabin.Marshal(txn)
+			if err != nil || len(b) == 0 {
+				continue
+			}
+			txnSize := uint64(len(b))
+			blockSize += txnSize
+			var fees types.Currency
+			for _, fee := range txn.MinerFees {
+				fees = fees.Add(fee)
+			}
+			if !lowestRateDetected || fees.Mul64(lowestSize).Cmp(lowestFees.Mul64(txnSize)) < 0 {
+				lowestFees, lowestSize, lowestRateDetected = fees, txnSize, true
+			}
+		}
+		// a block is considered full once 90% of its capacity is used
+		if lowestRateDetected && blockSize*10 >= capacity*9 {
+			raise(lowestFees, lowestSize)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// feeTestPool is a transaction pool only returning its transactions.
+type feeTestPool struct {
+	modules.TransactionPool
+	transactions []types.Transaction
+}
+
+func (tpool *feeTestPool) TransactionList() []types.Transaction {
+	return tpool.transactions
+}
+
+// feeTestTransaction creates a transaction of roughly the given size, paying the given fee.
+func feeTestTransaction(size int, fee uint64) types.Transaction {
+	return types.Transaction{
+		Version:       types.TransactionVersionOne,
+		ArbitraryData: make([]byte, size),
+		MinerFees:     []types.Currency{types.NewCurrency64(fee)},
+	}
+}
+
+// feeTestSize returns the (siabin) encoded size of the given transaction.
+func feeTestSize(t *testing.T, txn types.Transaction) uint64 {
+	b, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uint64(len(b))
+}
+
+// feeTestChainConstants returns chain constants with room for 10e3 bytes of transactions per block.
+func feeTestChainConstants() types.ChainConstants {
+	cts := types.StandardnetChainConstants()
+	cts.BlockSizeLimit = 15e3
+	cts.MinimumTransactionFee = types.NewCurrency64(100)
+	cts.TransactionPool.FeePerByte = types.ZeroCurrency
+	return cts
+}
+
+// feeTestRequest requests a fee recommendation, returning the status and decoded response.
+func feeTestRequest(t *testing.T, cs modules.ConsensusSet, tpool modules.TransactionPool, query string) (int, TransactionPoolFeeGET) {
+	rec := httptest.NewRecorder()
+	NewTransactionPoolFeeHandler(cs, tpool, feeTestChainConstants())(rec, httptest.NewRequest("GET", "/transactionpool/fee?"+query, nil), nil)
+	var resp TransactionPoolFeeGET
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, resp
+}
+
+// feeTestEqual returns true if both fee recommendations are equal.
+func feeTestEqual(a, b TransactionPoolFeeGET) bool {
+	return a.Target == b.Target && a.Size == b.Size && a.Fee.Equals(b.Fee) && a.MinimumFee.Equals(b.MinimumFee) &&
+		a.PoolTransactions == b.PoolTransactions && a.PoolSize == b.PoolSize
+}
+
+func TestTransactionPoolFeeHandler(t *testing.T) {
+	cs := newPaginationTestConsensusSet(3)
+	minimumFee := types.NewCurrency64(100)
+
+	// the minimum fee is recommended for an empty pool
+	status, resp := feeTestRequest(t, cs, new(feeTestPool), "")
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, not %d", http.StatusOK, status)
+	}
+	expected := TransactionPoolFeeGET{
+		Target:     DefaultFeeTarget,
+		Size:       DefaultFeeTransactionSize,
+		Fee:        minimumFee,
+		MinimumFee: minimumFee,
+	}
+	if !feeTestEqual(resp, expected) {
+		t.Errorf("unexpected recommendation for an empty pool: %+v", resp)
+	}
+
+	// packages competing for the target amount of blocks raise the fee
+	tpool := &feeTestPool{transactions: []types.Transaction{
+		feeTestTransaction(6000, 60000),
+		feeTestTransaction(6000, 30000),
+	}}
+	sizeA, sizeB := feeTestSize(t, tpool.transactions[0]), feeTestSize(t, tpool.transactions[1])
+	testCases := []struct {
+		query    string
+		expected TransactionPoolFeeGET
+	}{
+		// the second package doesn't fit in a single block, and has to be outbid
+		{"target=1", TransactionPoolFeeGET{Target: 1, Size: 400, Fee: types.NewCurrency64(30000*400/sizeB + 1), MinimumFee: minimumFee}},
+		{"target=1&size=600", TransactionPoolFeeGET{Target: 1, Size: 600, Fee: types.NewCurrency64(30000*600/sizeB + 1), MinimumFee: minimumFee}},
+		// all packages fit in two blocks
+		{"target=2", TransactionPoolFeeGET{Target: 2, Size: 400, Fee: minimumFee, MinimumFee: minimumFee}},
+		// the target and size are capped
+		{"target=1000&size=1000000", TransactionPoolFeeGET{Target: MaxFeeTarget, Size: 16e3, Fee: minimumFee, MinimumFee: minimumFee}},
+	}
+	for _, testCase := range testCases {
+		status, resp := feeTestRequest(t, cs, tpool, testCase.query)
+		testCase.expected.PoolTransactions, testCase.expected.PoolSize = 2, sizeA+sizeB
+		if status != http.StatusOK || !feeTestEqual(resp, testCase.expected) {
+			t.Errorf("%s: unexpected recommendation: %d: %+v != %+v", testCase.query, status, resp, testCase.expected)
+		}
+	}
+
+	for _, query := range []string{"target=0", "target=x", "size=-1"} {
+		if status, _ := feeTestRequest(t, cs, tpool, query); status != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", query, http.StatusBadRequest, status)
+		}
+	}
+}
+
+func TestTransactionPoolFeeHandlerFullBlocks(t *testing.T) {
+	// a recent block using more than 90% of its capacity, paying different fee rates
+	block := types.Block{Transactions: []types.Transaction{
+		feeTestTransaction(4600, 3*4600),
+		feeTestTransaction(4601, 2*4600),
+	}}
+	cs := newPaginationTestConsensusSet(1)
+	cs.blocks = append(cs.blocks, block)
+
+	// the fee rate has to exceed the lowest fee rate of the full block
+	status, resp := feeTestRequest(t, cs, new(feeTestPool), "")
+	expected := types.NewCurrency64(2 * 4600 * DefaultFeeTransactionSize / feeTestSize(t, block.Transactions[1])).Add(types.NewCurrency64(1))
+	if status != http.StatusOK || !resp.Fee.Equals(expected) {
+		t.Errorf("unexpected recommendation after a full block: %d: %v != %v", status, resp.Fee, expected)
+	}
+
+	// blocks which are not full do not raise the fee
+	cs.blocks[1].Transactions = cs.blocks[1].Transactions[:1]
+	if status, resp = feeTestRequest(t, cs, new(feeTestPool), ""); status != http.StatusOK || !resp.Fee.Equals64(100) {
+		t.Errorf("unexpected recommendation after a block which isn't full: %d: %v", status, resp.Fee)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/modules"
@@ -15,8 +16,18 @@ type GatewayGET struct {
 	Peers      []modules.Peer     `json:"peers"`
 }
 
+// GatewayPeersGET contains the fields returned by a GET call to "/gateway/peers".
+type GatewayPeersGET struct {
+	Peers []modules.PeerStats `json:"peers"`
+}
+
+// GatewayBansGET contains the fields returned by a GET call to "/gateway/bans".
+type GatewayBansGET struct {
+	Bans []modules.PeerBan `json:"bans"`
+}
+
 // RegisterGatewayHTTPHandlers registers the default Rivine handlers for all default Rivine Gateway HTTP endpoints.
-func RegisterGatewayHTTPHandlers(router Router, gateway modules.Gateway, requiredPassword string) {
+func RegisterGatewayHTTPHandlers(router Router, gateway modules.Gateway, auth *APIAuthenticator) {
 	if gateway == nil {
 		build.Critical("no gateway module given")
 	}
@@ -24,8 +35,12 @@ func RegisterGatewayHTTPHandlers(router Router, gateway modules.Gateway, require
 		build.Critical("no httprouter Router given")
 	}
 	router.GET("/gateway", NewGatewayRootHandler(gateway))
-	router.POST("/gateway/connect/:netaddress", RequirePasswordHandler(NewGatewayConnectHandler(gateway), requiredPassword))
-	router.POST("/gateway/disconnect/:netaddress", RequirePasswordHandler(NewGatewayDisconnectHandler(gateway), requiredPassword))
+	router.POST("/gateway/connect/:netaddress", RequireScopeHandler(NewGatewayConnectHandler(gateway), auth, APIScopeAdmin))
+	router.POST("/gateway/disconnect/:netaddress", RequireScopeHandler(NewGatewayDisconnectHandler(gateway), auth, APIScopeAdmin))
+	router.GET("/gateway/peers", NewGatewayPeersHandler(gateway))
+	router.GET("/gateway/bans", NewGatewayBansHandler(gateway))
+	router.POST("/gateway/ban/:netaddress", RequireScopeHandler(NewGatewayBanHandler(gateway), auth, APIScopeAdmin))
+	router.POST("/gateway/unban/:netaddress", RequireScopeHandler(NewGatewayUnbanHandler(gateway), auth, APIScopeAdmin))
 }
 
 // NewGatewayRootHandler creates a handler to handle the API call asking for the gatway status.
@@ -51,7 +66,7 @@ func NewGatewayConnectHandler(gateway modules.Gateway) httprouter.Handle {
 		addr.TryNameResolution()
 		err := gateway.Connect(addr)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteSuccess(w)
@@ -67,7 +82,65 @@ func NewGatewayDisconnectHandler(gateway modules.Gateway) httprouter.Handle {
 		addr.TryNameResolution()
 		err := gateway.Disconnect(addr)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		WriteSuccess(w)
+	}
+}
+
+// NewGatewayPeersHandler creates a handler to handle the API call asking for the statistics of the connected peers.
+func NewGatewayPeersHandler(gateway modules.Gateway) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		peers := gateway.PeerStats()
+		if peers == nil {
+			peers = make([]modules.PeerStats, 0)
+		}
+		WriteJSON(w, GatewayPeersGET{Peers: peers})
+	}
+}
+
+// NewGatewayBansHandler creates a handler to handle the API call asking for the banned hosts.
+func NewGatewayBansHandler(gateway modules.Gateway) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		bans := gateway.PeerBans()
+		if bans == nil {
+			bans = make([]modules.PeerBan, 0)
+		}
+		WriteJSON(w, GatewayBansGET{Bans: bans})
+	}
+}
+
+// NewGatewayBanHandler creates a handler to handle the API call to ban the host of a peer,
+// for the (optional) duration and with the (optional) reason given as form values.
+func NewGatewayBanHandler(gateway modules.Gateway) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		addr := modules.NetAddress(ps.ByName("netaddress"))
+		var duration time.Duration
+		if str := req.FormValue("duration"); str != "" {
+			var err error
+			duration, err = time.ParseDuration(str)
+			if err != nil || duration < 0 {
+				WriteError(w, Error{Message: "invalid duration: " + str}, http.StatusBadRequest)
+				return
+			}
+		}
+		err := gateway.BanPeer(addr, duration, req.FormValue("reason"))
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		WriteSuccess(w)
+	}
+}
+
+// NewGatewayUnbanHandler creates a handler to handle the API call to lift the ban of the host of a peer.
+func NewGatewayUnbanHandler(gateway modules.Gateway) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		addr := modules.NetAddress(ps.ByName("netaddress"))
+		err := gateway.UnbanPeer(addr)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteSuccess(w)
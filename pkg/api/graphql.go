@@ -0,0 +1,547 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/graphql"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Limits of a GraphQL query, limiting the amount of work a single query can cause by navigating the chain.
+const (
+	// GraphQLMaxDepth is the maximum depth of the selection sets of a GraphQL query.
+	GraphQLMaxDepth = 10
+	// GraphQLMaxCost is the maximum cost of a GraphQL query, each selected field costing 1,
+	// with the cost of the fields selected for a page of blocks or transactions
+	// multiplied by the (maximum) amount of items of that page.
+	GraphQLMaxCost = 10000
+	// GraphQLMaxAliases is the maximum amount of aliased fields of a GraphQL query.
+	GraphQLMaxAliases = 20
+)
+
+// RegisterGraphQLHTTPHandlers registers the handlers for the GraphQL HTTP endpoints,
+// exposing the chain data indexed by the explorer module.
+func RegisterGraphQLHTTPHandlers(router Router, cs modules.ConsensusSet, explorer modules.Explorer) {
+	if cs == nil {
+		build.Critical("no consensus module given")
+	}
+	if explorer == nil {
+		build.Critical("no explorer module given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	schema := NewChainGraphQLSchema(cs, explorer)
+	router.GET("/graphql", NewGraphQLHandler(schema))
+	router.POST("/graphql", NewGraphQLHandler(schema))
+	router.GET("/graphql/schema", NewGraphQLSchemaHandler(schema))
+}
+
+// NewGraphQLHandler creates a handler to handle API calls to /graphql,
+// executing the query given as JSON body (POST) or as query string parameters (GET).
+func NewGraphQLHandler(schema *graphql.Schema) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var gqlReq graphql.Request
+		if req.Method == http.MethodPost {
+			decoder := json.NewDecoder(req.Body)
+			decoder.UseNumber()
+			err := decoder.Decode(&gqlReq)
+			if err != nil {
+				WriteError(w, Error{Message: "error decoding the supplied GraphQL request: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+		} else {
+			gqlReq.Query = req.FormValue("query")
+			gqlReq.OperationName = req.FormValue("operationName")
+			if str := req.FormValue("variables"); str != "" {
+				decoder := json.NewDecoder(strings.NewReader(str))
+				decoder.UseNumber()
+				err := decoder.Decode(&gqlReq.Variables)
+				if err != nil {
+					WriteError(w, Error{Message: "error decoding the supplied GraphQL variables: " + err.Error()}, http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		if gqlReq.Query == "" {
+			WriteError(w, Error{Message: "no GraphQL query given"}, http.StatusBadRequest)
+			return
+		}
+		gqlReq.Context = req.Context()
+		WriteJSON(w, schema.Execute(gqlReq))
+	}
+}
+
+// NewGraphQLSchemaHandler creates a handler to handle API calls to GET /graphql/schema,
+// returning the schema in the GraphQL schema definition language.
+func NewGraphQLSchemaHandler(schema *graphql.Schema) httprouter.Handle {
+	sdl := schema.String()
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, sdl)
+	}
+}
+
+// the sources of the GraphQL object types
+type (
+	graphqlBlock struct {
+		block  types.Block
+		height types.BlockHeight
+	}
+	graphqlTransaction struct {
+		txn    types.Transaction
+		height types.BlockHeight
+	}
+	graphqlCoinOutput struct {
+		id     types.CoinOutputID
+		output types.CoinOutput
+	}
+	graphqlBlockStakeOutput struct {
+		id     types.BlockStakeOutputID
+		output types.BlockStakeOutput
+	}
+)
+
+// NewChainGraphQLSchema creates the GraphQL schema exposing the blocks, transactions,
+// outputs and addresses of the chain, as indexed by the explorer module.
+func NewChainGraphQLSchema(cs modules.ConsensusSet, explorer modules.Explorer) *graphql.Schema {
+	c := &graphqlChain{cs: cs, explorer: explorer}
+	var (
+		block            = &graphql.Object{Name: "Block", Description: "A block of the chain."}
+		transaction      = &graphql.Object{Name: "Transaction", Description: "A confirmed transaction."}
+		coinInput        = &graphql.Object{Name: "CoinInput", Description: "A coin input, spending a coin output."}
+		coinOutput       = &graphql.Object{Name: "CoinOutput", Description: "A coin output, including the miner payouts of blocks."}
+		blockStakeInput  = &graphql.Object{Name: "BlockStakeInput", Description: "A block stake input, spending a block stake output."}
+		blockStakeOutput = &graphql.Object{Name: "BlockStakeOutput", Description: "A block stake output."}
+		address          = &graphql.Object{Name: "Address", Description: "An unlock hash, the address of the unlock conditions of outputs."}
+	)
+	nonNull := func(t graphql.Type) graphql.Type { return &graphql.NonNull{Of: t} }
+	list := func(t graphql.Type) graphql.Type { return &graphql.NonNull{Of: &graphql.List{Of: nonNull(t)}} }
+
+	block.Fields = graphql.Fields{
+		"id": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlock).block.ID(), nil
+		}},
+		"height": &graphql.Field{Type: nonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlock).height, nil
+		}},
+		"timestamp": &graphql.Field{Type: nonNull(graphql.Int), Description: "Unix timestamp of the block.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlock).block.Timestamp, nil
+		}},
+		"parentId": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlock).block.ParentID, nil
+		}},
+		"parent": &graphql.Field{Type: block, Description: "The parent block, null for the genesis block.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			b := p.Source.(graphqlBlock)
+			if b.height == 0 {
+				return nil, nil
+			}
+			return c.blockAtHeight(b.height - 1), nil
+		}},
+		"minerPayouts": &graphql.Field{Type: list(coinOutput), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			b := p.Source.(graphqlBlock).block
+			outputs := make([]graphqlCoinOutput, 0, len(b.MinerPayouts))
+			for i, mp := range b.MinerPayouts {
+				outputs = append(outputs, graphqlCoinOutput{
+					id: b.MinerPayoutID(uint64(i)),
+					output: types.CoinOutput{
+						Value:     mp.Value,
+						Condition: types.NewCondition(types.NewUnlockHashCondition(mp.UnlockHash)),
+					},
+				})
+			}
+			return outputs, nil
+		}},
+		"transactions": &graphql.Field{Type: list(transaction), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			b := p.Source.(graphqlBlock)
+			txns := make([]graphqlTransaction, 0, len(b.block.Transactions))
+			for _, txn := range b.block.Transactions {
+				txns = append(txns, graphqlTransaction{txn: txn, height: b.height})
+			}
+			return txns, nil
+		}},
+	}
+
+	transaction.Fields = graphql.Fields{
+		"id": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).txn.ID(), nil
+		}},
+		"version": &graphql.Field{Type: nonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).txn.Version, nil
+		}},
+		"height": &graphql.Field{Type: nonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).height, nil
+		}},
+		"block": &graphql.Field{Type: nonNull(block), Description: "The block the transaction is part of.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.blockAtHeight(p.Source.(graphqlTransaction).height), nil
+		}},
+		"coinInputs": &graphql.Field{Type: list(coinInput), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).txn.CoinInputs, nil
+		}},
+		"coinOutputs": &graphql.Field{Type: list(coinOutput), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			txn := p.Source.(graphqlTransaction).txn
+			outputs := make([]graphqlCoinOutput, 0, len(txn.CoinOutputs))
+			for i, co := range txn.CoinOutputs {
+				outputs = append(outputs, graphqlCoinOutput{id: txn.CoinOutputID(uint64(i)), output: co})
+			}
+			return outputs, nil
+		}},
+		"blockStakeInputs": &graphql.Field{Type: list(blockStakeInput), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).txn.BlockStakeInputs, nil
+		}},
+		"blockStakeOutputs": &graphql.Field{Type: list(blockStakeOutput), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			txn := p.Source.(graphqlTransaction).txn
+			outputs := make([]graphqlBlockStakeOutput, 0, len(txn.BlockStakeOutputs))
+			for i, bso := range txn.BlockStakeOutputs {
+				outputs = append(outputs, graphqlBlockStakeOutput{id: txn.BlockStakeOutputID(uint64(i)), output: bso})
+			}
+			return outputs, nil
+		}},
+		"minerFees": &graphql.Field{Type: list(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlTransaction).txn.MinerFees, nil
+		}},
+		"arbitraryData": &graphql.Field{Type: graphql.String, Description: "Base64-encoded arbitrary data, null if not defined.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			data := p.Source.(graphqlTransaction).txn.ArbitraryData
+			if len(data) == 0 {
+				return nil, nil
+			}
+			return data, nil
+		}},
+	}
+
+	coinInput.Fields = graphql.Fields{
+		"parentId": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(types.CoinInput).ParentID, nil
+		}},
+		"parent": &graphql.Field{Type: nonNull(coinOutput), Description: "The coin output spent by the input.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.coinOutput(p.Source.(types.CoinInput).ParentID), nil
+		}},
+	}
+	coinOutput.Fields = graphql.Fields{
+		"id": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlCoinOutput).id, nil
+		}},
+		"value": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlCoinOutput).output.Value, nil
+		}},
+		"conditionType": &graphql.Field{Type: nonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlCoinOutput).output.Condition.ConditionType(), nil
+		}},
+		"unlockhash": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlCoinOutput).output.Condition.UnlockHash(), nil
+		}},
+		"address": &graphql.Field{Type: nonNull(address), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlCoinOutput).output.Condition.UnlockHash(), nil
+		}},
+		"spentBy": &graphql.Field{Type: transaction, Description: "The transaction spending the output, null if the output is unspent.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id := p.Source.(graphqlCoinOutput).id
+			for _, txid := range c.explorer.CoinOutputID(id) {
+				txn, ok := c.transaction(txid)
+				if !ok {
+					continue
+				}
+				for _, ci := range txn.txn.CoinInputs {
+					if ci.ParentID == id {
+						return txn, nil
+					}
+				}
+			}
+			return nil, nil
+		}},
+	}
+
+	blockStakeInput.Fields = graphql.Fields{
+		"parentId": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(types.BlockStakeInput).ParentID, nil
+		}},
+		"parent": &graphql.Field{Type: nonNull(blockStakeOutput), Description: "The block stake output spent by the input.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.blockStakeOutput(p.Source.(types.BlockStakeInput).ParentID), nil
+		}},
+	}
+	blockStakeOutput.Fields = graphql.Fields{
+		"id": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlockStakeOutput).id, nil
+		}},
+		"value": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlockStakeOutput).output.Value, nil
+		}},
+		"conditionType": &graphql.Field{Type: nonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlockStakeOutput).output.Condition.ConditionType(), nil
+		}},
+		"unlockhash": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlockStakeOutput).output.Condition.UnlockHash(), nil
+		}},
+		"address": &graphql.Field{Type: nonNull(address), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(graphqlBlockStakeOutput).output.Condition.UnlockHash(), nil
+		}},
+		"spentBy": &graphql.Field{Type: transaction, Description: "The transaction spending the output, null if the output is unspent.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id := p.Source.(graphqlBlockStakeOutput).id
+			for _, txid := range c.explorer.BlockStakeOutputID(id) {
+				txn, ok := c.transaction(txid)
+				if !ok {
+					continue
+				}
+				for _, bsi := range txn.txn.BlockStakeInputs {
+					if bsi.ParentID == id {
+						return txn, nil
+					}
+				}
+			}
+			return nil, nil
+		}},
+	}
+
+	address.Fields = graphql.Fields{
+		"unlockhash": &graphql.Field{Type: nonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(types.UnlockHash), nil
+		}},
+		"coinBalance": &graphql.Field{Type: nonNull(graphql.String), Description: "The confirmed coin balance.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.explorer.Balance(p.Source.(types.UnlockHash)).Coins, nil
+		}},
+		"blockStakeBalance": &graphql.Field{Type: nonNull(graphql.String), Description: "The confirmed block stake balance.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.explorer.Balance(p.Source.(types.UnlockHash)).BlockStakes, nil
+		}},
+		"transactionCount": &graphql.Field{Type: nonNull(graphql.Int), Description: "The amount of confirmed transactions touching the address.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return len(locateAddressTransactions(c.explorer, p.Source.(types.UnlockHash), 0, 0)), nil
+		}},
+		"transactions": &graphql.Field{
+			Type:        list(transaction),
+			Description: fmt.Sprintf("A page of the confirmed transactions touching the address, in chronological order, returning at most %d transactions.", MaxPaginationLimit),
+			Args: graphql.Args{
+				"offset": &graphql.Argument{Type: graphql.Int},
+				"limit":  &graphql.Argument{Type: graphql.Int},
+			},
+			Multiplier: func(args map[string]interface{}) int {
+				pagination, err := graphqlPagination(args)
+				if err != nil {
+					return 0
+				}
+				return int(pagination.Limit)
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				pagination, err := graphqlPagination(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				located := locateAddressTransactions(c.explorer, p.Source.(types.UnlockHash), 0, 0)
+				first, last := pagination.Bounds(uint64(len(located)))
+				txns := make([]graphqlTransaction, 0, last-first)
+				for _, lt := range located[first:last] {
+					txns = append(txns, graphqlTransaction{txn: lt.block.Transactions[lt.index], height: lt.height})
+				}
+				return txns, nil
+			},
+		},
+		"multisigAddresses": &graphql.Field{Type: list(address), Description: "The multisig addresses the address is involved in.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return c.explorer.MultiSigAddresses(p.Source.(types.UnlockHash)), nil
+		}},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"height": &graphql.Field{Type: nonNull(graphql.Int), Description: "The current height of the chain.", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return c.cs.Height(), nil
+			}},
+			"block": &graphql.Field{
+				Type:        block,
+				Description: "A block, identified by either its ID or its height.",
+				Args: graphql.Args{
+					"id":     &graphql.Argument{Type: graphql.String},
+					"height": &graphql.Argument{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if str, ok := p.Args["id"].(string); ok {
+						hash, err := ScanHash(str)
+						if err != nil {
+							return nil, err
+						}
+						b, height, exists := c.explorer.Block(types.BlockID(hash))
+						if !exists {
+							return nil, nil
+						}
+						return graphqlBlock{block: b, height: height}, nil
+					}
+					if height, ok := p.Args["height"].(int64); ok {
+						if height < 0 || types.BlockHeight(height) > c.cs.Height() {
+							return nil, nil
+						}
+						return c.blockAtHeight(types.BlockHeight(height)), nil
+					}
+					return nil, errors.New("either the id or the height of the block is required")
+				},
+			},
+			"blocks": &graphql.Field{
+				Type:        list(block),
+				Description: fmt.Sprintf("The blocks within the given (inclusive) height range, returning at most %d blocks.", MaxPaginationLimit),
+				Args: graphql.Args{
+					"start": &graphql.Argument{Type: nonNull(graphql.Int)},
+					"end":   &graphql.Argument{Type: graphql.Int},
+				},
+				Multiplier: func(args map[string]interface{}) int {
+					start, _ := args["start"].(int64)
+					end, ok := args["end"].(int64)
+					if !ok || end-start >= MaxPaginationLimit {
+						return MaxPaginationLimit
+					}
+					if start > end {
+						return 0
+					}
+					return int(end - start + 1)
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					height := c.cs.Height()
+					start := p.Args["start"].(int64)
+					end, ok := p.Args["end"].(int64)
+					if !ok || types.BlockHeight(end) > height {
+						end = int64(height)
+					}
+					if start < 0 || start > end {
+						return nil, fmt.Errorf("invalid block range [%d, %d]", start, end)
+					}
+					if end-start >= MaxPaginationLimit {
+						end = start + MaxPaginationLimit - 1
+					}
+					blocks := make([]graphqlBlock, 0, end-start+1)
+					for h := start; h <= end; h++ {
+						blocks = append(blocks, c.blockAtHeight(types.BlockHeight(h)))
+					}
+					return blocks, nil
+				},
+			},
+			"transaction": &graphql.Field{
+				Type: transaction,
+				Args: graphql.Args{"id": &graphql.Argument{Type: nonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					hash, err := ScanHash(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					if txn, ok := c.transaction(types.TransactionID(hash)); ok {
+						return txn, nil
+					}
+					return nil, nil
+				},
+			},
+			"coinOutput": &graphql.Field{
+				Type: coinOutput,
+				Args: graphql.Args{"id": &graphql.Argument{Type: nonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					hash, err := ScanHash(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					id := types.CoinOutputID(hash)
+					co, exists := c.explorer.CoinOutput(id)
+					if !exists {
+						return nil, nil
+					}
+					return graphqlCoinOutput{id: id, output: co}, nil
+				},
+			},
+			"blockStakeOutput": &graphql.Field{
+				Type: blockStakeOutput,
+				Args: graphql.Args{"id": &graphql.Argument{Type: nonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					hash, err := ScanHash(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					id := types.BlockStakeOutputID(hash)
+					bso, exists := c.explorer.BlockStakeOutput(id)
+					if !exists {
+						return nil, nil
+					}
+					return graphqlBlockStakeOutput{id: id, output: bso}, nil
+				},
+			},
+			"address": &graphql.Field{
+				Type: address,
+				Args: graphql.Args{"unlockhash": &graphql.Argument{Type: nonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return scanContextAddress(p.Context, p.Args["unlockhash"].(string))
+				},
+			},
+		},
+	}
+	return &graphql.Schema{
+		Query:      query,
+		MaxDepth:   GraphQLMaxDepth,
+		MaxCost:    GraphQLMaxCost,
+		MaxAliases: GraphQLMaxAliases,
+	}
+}
+
+// graphqlChain resolves the chain data exposed by the GraphQL schema
+type graphqlChain struct {
+	cs       modules.ConsensusSet
+	explorer modules.Explorer
+}
+
+func (c *graphqlChain) blockAtHeight(height types.BlockHeight) graphqlBlock {
+	b, exists := c.cs.BlockAtHeight(height)
+	if !exists {
+		build.Severe("block at height", height, "not found, while it should exist")
+	}
+	return graphqlBlock{block: b, height: height}
+}
+
+// transaction returns the confirmed transaction with the given ID,
+// returning false for unknown transactions and block IDs (indexed for their miner payouts).
+func (c *graphqlChain) transaction(txid types.TransactionID) (graphqlTransaction, bool) {
+	b, height, exists := c.explorer.Transaction(txid)
+	if !exists || types.TransactionID(b.ID()) == txid {
+		return graphqlTransaction{}, false
+	}
+	for _, txn := range b.Transactions {
+		if txn.ID() == txid {
+			return graphqlTransaction{txn: txn, height: height}, true
+		}
+	}
+	return graphqlTransaction{}, false
+}
+
+func (c *graphqlChain) coinOutput(id types.CoinOutputID) graphqlCoinOutput {
+	co, exists := c.explorer.CoinOutput(id)
+	if !exists {
+		build.Severe("could not find corresponding coin output")
+	}
+	return graphqlCoinOutput{id: id, output: co}
+}
+
+func (c *graphqlChain) blockStakeOutput(id types.BlockStakeOutputID) graphqlBlockStakeOutput {
+	bso, exists := c.explorer.BlockStakeOutput(id)
+	if !exists {
+		build.Severe("could not find corresponding blockstake output")
+	}
+	return graphqlBlockStakeOutput{id: id, output: bso}
+}
+
+// graphqlPagination returns the pagination defined by the offset and limit arguments of a GraphQL field.
+func graphqlPagination(args map[string]interface{}) (Pagination, error) {
+	pagination := Pagination{Limit: DefaultPaginationLimit, Order: PaginationOrderAscending}
+	if offset, ok := args["offset"].(int64); ok {
+		if offset < 0 {
+			return Pagination{}, errors.New("invalid offset: cannot be negative")
+		}
+		pagination.Offset = uint64(offset)
+	}
+	if limit, ok := args["limit"].(int64); ok {
+		if limit <= 0 {
+			return Pagination{}, errors.New("invalid limit: has to be positive")
+		}
+		if limit > MaxPaginationLimit {
+			limit = MaxPaginationLimit
+		}
+		pagination.Limit = uint64(limit)
+	}
+	return pagination, nil
+}
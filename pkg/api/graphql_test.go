@@ -0,0 +1,36 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/graphql"
+)
+
+func TestChainGraphQLSchemaLimits(t *testing.T) {
+	// queries exceeding the limits are rejected prior to being executed,
+	// such that no modules are required
+	schema := NewChainGraphQLSchema(nil, nil)
+
+	spreads := strings.Repeat("...f ", 10)
+	aliases := make([]string, 0, GraphQLMaxAliases+1)
+	for i := 0; i <= GraphQLMaxAliases; i++ {
+		aliases = append(aliases, "h"+string(rune('a'+i))+": height")
+	}
+	testCases := []struct {
+		name, query, err string
+	}{
+		// 1 + 500 blocks * 10 spreads * 2 fields
+		{"blocks", `{ blocks(start: 0) { ` + spreads + `} } fragment f on Block { id height }`, "query exceeds the maximum cost of 10000"},
+		// 1 + 1 + 50 transactions (the default limit) * 10 spreads * 20 fields
+		{"address transactions", `{ address(unlockhash: "") { transactions { ` + spreads + `} } } fragment f on Transaction { ` +
+			strings.Repeat("id ", 20) + `}`, "query exceeds the maximum cost of 10000"},
+		{"aliases", `{ ` + strings.Join(aliases, " ") + ` }`, "query exceeds the maximum of 20 aliases"},
+	}
+	for _, testCase := range testCases {
+		result := schema.Execute(graphql.Request{Query: testCase.query})
+		if len(result.Errors) != 1 || result.Errors[0].Message != testCase.err {
+			t.Errorf("%s: expected error %q, not: %v", testCase.name, testCase.err, result.Errors)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// The names of the checks performed by a HealthChecker.
+const (
+	HealthCheckModules   = "modules"
+	HealthCheckConsensus = "consensus"
+	HealthCheckWallet    = "wallet"
+	HealthCheckDisk      = "disk"
+)
+
+type (
+	// HealthConfig configures a HealthChecker.
+	HealthConfig struct {
+		// Modules is the amount of modules the daemon loads
+		Modules int
+		// PersistDir is the root persistent directory of the daemon,
+		// which is required to be writable
+		PersistDir string
+		// BlockFrequency is the average amount of seconds between blocks,
+		// used to estimate how many blocks the consensus set is behind
+		BlockFrequency types.BlockHeight
+		// MaxBlocksBehind is the maximum amount of blocks the consensus set
+		// can be estimated to be behind, for the daemon to be ready
+		MaxBlocksBehind types.BlockHeight
+		// RequireUnlockedWallet requires the wallet to be unlocked, for the daemon to be ready
+		RequireUnlockedWallet bool
+	}
+
+	// HealthCheck is the result of a single health check.
+	HealthCheck struct {
+		Name string `json:"name"`
+		// Healthy indicates if the check passed
+		Healthy bool `json:"healthy"`
+		// Required indicates if the check is required to pass for the daemon to be ready
+		Required bool   `json:"required"`
+		Message  string `json:"message,omitempty"`
+	}
+
+	// HealthLiveGET is the object returned by a GET request to /health/live.
+	HealthLiveGET struct {
+		Alive bool `json:"alive"`
+	}
+
+	// HealthReadyGET is the object returned by a GET request to /health/ready.
+	HealthReadyGET struct {
+		Ready  bool          `json:"ready"`
+		Checks []HealthCheck `json:"checks"`
+	}
+)
+
+// HealthChecker checks whether the daemon is ready to serve requests.
+// Modules are registered with the checker as they are loaded,
+// such that the readiness of the daemon can be checked while it is starting.
+type HealthChecker struct {
+	config HealthConfig
+
+	mu      sync.RWMutex
+	loaded  []string
+	started bool
+	cs      modules.ConsensusSet
+	wallet  modules.Wallet
+}
+
+// NewHealthChecker creates a new HealthChecker.
+func NewHealthChecker(config HealthConfig) *HealthChecker {
+	return &HealthChecker{config: config}
+}
+
+// ModuleLoaded registers that the module with the given name has been loaded.
+func (hc *HealthChecker) ModuleLoaded(name string) {
+	hc.mu.Lock()
+	hc.loaded = append(hc.loaded, name)
+	hc.mu.Unlock()
+}
+
+// SetConsensusSet registers the loaded consensus set, whose sync status is checked.
+func (hc *HealthChecker) SetConsensusSet(cs modules.ConsensusSet) {
+	hc.mu.Lock()
+	hc.cs = cs
+	hc.loaded = append(hc.loaded, "consensus")
+	hc.mu.Unlock()
+}
+
+// SetWallet registers the loaded wallet, whose lock status is checked.
+func (hc *HealthChecker) SetWallet(wallet modules.Wallet) {
+	hc.mu.Lock()
+	hc.wallet = wallet
+	hc.loaded = append(hc.loaded, "wallet")
+	hc.mu.Unlock()
+}
+
+// Modules returns the sorted names of the loaded modules.
+func (hc *HealthChecker) Modules() []string {
+	hc.mu.RLock()
+	loaded := append([]string{}, hc.loaded...)
+	hc.mu.RUnlock()
+	sort.Strings(loaded)
+	return loaded
+}
+
+// ConsensusSet returns the loaded consensus set, or nil if it isn't loaded (yet).
+func (hc *HealthChecker) ConsensusSet() modules.ConsensusSet {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+	return hc.cs
+}
+
+// SetStarted registers that the daemon has started, all modules being loaded.
+func (hc *HealthChecker) SetStarted() {
+	hc.mu.Lock()
+	hc.started = true
+	hc.mu.Unlock()
+}
+
+// Check performs all health checks, returning the results
+// as well as whether or not the daemon is ready.
+func (hc *HealthChecker) Check() HealthReadyGET {
+	hc.mu.RLock()
+	loaded := append([]string(nil), hc.loaded...)
+	started, cs, wallet := hc.started, hc.cs, hc.wallet
+	hc.mu.RUnlock()
+
+	checks := []HealthCheck{hc.checkModules(loaded, started)}
+	if cs != nil {
+		checks = append(checks, hc.checkConsensus(cs))
+	}
+	if wallet != nil {
+		checks = append(checks, hc.checkWallet(wallet))
+	}
+	if hc.config.PersistDir != "" {
+		checks = append(checks, hc.checkDisk())
+	}
+	ready := true
+	for _, check := range checks {
+		if check.Required && !check.Healthy {
+			ready = false
+		}
+	}
+	return HealthReadyGET{
+		Ready:  ready,
+		Checks: checks,
+	}
+}
+
+func (hc *HealthChecker) checkModules(loaded []string, started bool) HealthCheck {
+	sort.Strings(loaded)
+	check := HealthCheck{
+		Name:     HealthCheckModules,
+		Healthy:  started,
+		Required: true,
+		Message:  fmt.Sprintf("%d/%d modules loaded", len(loaded), hc.config.Modules),
+	}
+	if len(loaded) > 0 {
+		check.Message += ": " + strings.Join(loaded, ", ")
+	}
+	if !started {
+		check.Message = "daemon is starting, " + check.Message
+	}
+	return check
+}
+
+func (hc *HealthChecker) checkConsensus(cs modules.ConsensusSet) HealthCheck {
+	check := HealthCheck{
+		Name:     HealthCheckConsensus,
+		Required: true,
+	}
+	height := cs.Height()
+	if !cs.Synced() {
+		check.Message = fmt.Sprintf("consensus set is not synced, at height %d", height)
+		return check
+	}
+	var behind types.BlockHeight
+	if now, timestamp := types.CurrentTimestamp(), cs.CurrentBlock().Timestamp; now > timestamp && hc.config.BlockFrequency > 0 {
+		behind = types.BlockHeight(now-timestamp) / hc.config.BlockFrequency
+	}
+	if behind > hc.config.MaxBlocksBehind {
+		check.Message = fmt.Sprintf("consensus set is estimated to be %d blocks behind, at height %d", behind, height)
+		return check
+	}
+	check.Healthy = true
+	check.Message = fmt.Sprintf("consensus set is synced, at height %d", height)
+	return check
+}
+
+func (hc *HealthChecker) checkWallet(wallet modules.Wallet) HealthCheck {
+	check := HealthCheck{
+		Name:     HealthCheckWallet,
+		Healthy:  wallet.Unlocked(),
+		Required: hc.config.RequireUnlockedWallet,
+	}
+	if check.Healthy {
+		check.Message = "wallet is unlocked"
+	} else {
+		check.Message = "wallet is locked"
+	}
+	return check
+}
+
+// checkDisk checks that the persistent directory is writable, by writing and removing a temporary file.
+func (hc *HealthChecker) checkDisk() HealthCheck {
+	check := HealthCheck{
+		Name:     HealthCheckDisk,
+		Required: true,
+	}
+	file, err := ioutil.TempFile(hc.config.PersistDir, ".health")
+	if err != nil {
+		check.Message = "persistent directory is not writable: " + err.Error()
+		return check
+	}
+	name := file.Name()
+	_, err = file.Write([]byte("ok"))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	os.Remove(name)
+	if err != nil {
+		check.Message = "persistent directory is not writable: " + err.Error()
+		return check
+	}
+	check.Healthy = true
+	check.Message = "persistent directory is writable"
+	return check
+}
+
+// RegisterHealthHTTPHandlers registers the default Rivine handlers for the health HTTP endpoints,
+// which do not require authentication, such that they can be used as liveness and readiness probes.
+func RegisterHealthHTTPHandlers(router Router, hc *HealthChecker) {
+	if hc == nil {
+		build.Critical("no HealthChecker given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/health/live", NewHealthLiveHandler())
+	router.GET("/health/ready", NewHealthReadyHandler(hc))
+}
+
+// NewHealthLiveHandler creates a handler to handle API calls to GET /health/live,
+// which succeeds as long as the daemon is able to serve requests.
+func NewHealthLiveHandler() httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, HealthLiveGET{Alive: true})
+	}
+}
+
+// NewHealthReadyHandler creates a handler to handle API calls to GET /health/ready,
+// responding with 503 (Service Unavailable) in case the daemon is not ready.
+func NewHealthReadyHandler(hc *HealthChecker) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		status := hc.Check()
+		if !status.Ready {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(status)
+			return
+		}
+		WriteJSON(w, status)
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// healthTestConsensusSet is a consensus set only implementing the methods used by the health checks.
+type healthTestConsensusSet struct {
+	modules.ConsensusSet
+	synced    bool
+	timestamp types.Timestamp
+}
+
+func (cs *healthTestConsensusSet) Height() types.BlockHeight { return 42 }
+func (cs *healthTestConsensusSet) Synced() bool              { return cs.synced }
+func (cs *healthTestConsensusSet) CurrentBlock() types.Block {
+	return types.Block{Timestamp: cs.timestamp}
+}
+
+// healthTestWallet is a wallet only implementing the methods used by the health checks.
+type healthTestWallet struct {
+	modules.Wallet
+	unlocked bool
+}
+
+func (w *healthTestWallet) Unlocked() bool { return w.unlocked }
+
+// healthReadyTestRequest requests the readiness of the daemon, returning the status and decoded response.
+func healthReadyTestRequest(t *testing.T, hc *HealthChecker) (int, HealthReadyGET) {
+	rec := httptest.NewRecorder()
+	NewHealthReadyHandler(hc)(rec, httptest.NewRequest("GET", "/health/ready", nil), nil)
+	var resp HealthReadyGET
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, resp
+}
+
+func TestHealthReadyHandler(t *testing.T) {
+	dir := build.TempDir("api", t.Name())
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	hc := NewHealthChecker(HealthConfig{
+		Modules:         2,
+		PersistDir:      dir,
+		BlockFrequency:  10,
+		MaxBlocksBehind: 5,
+	})
+	cs := &healthTestConsensusSet{synced: true, timestamp: types.CurrentTimestamp()}
+	wallet := &healthTestWallet{}
+
+	// the daemon isn't ready while it is starting
+	hc.SetConsensusSet(cs)
+	if status, resp := healthReadyTestRequest(t, hc); status != http.StatusServiceUnavailable || resp.Ready {
+		t.Errorf("expected status %d while starting, not %d", http.StatusServiceUnavailable, status)
+	}
+	hc.SetWallet(wallet)
+	hc.SetStarted()
+
+	// a locked wallet is reported, but not required to be unlocked by default
+	status, resp := healthReadyTestRequest(t, hc)
+	if status != http.StatusOK || !resp.Ready {
+		t.Fatalf("expected status %d once started, not %d: %+v", http.StatusOK, status, resp.Checks)
+	}
+	expected := map[string]bool{
+		HealthCheckModules:   true,
+		HealthCheckConsensus: true,
+		HealthCheckWallet:    false,
+		HealthCheckDisk:      true,
+	}
+	if len(resp.Checks) != len(expected) {
+		t.Fatalf("unexpected checks: %+v", resp.Checks)
+	}
+	for _, check := range resp.Checks {
+		if healthy, ok := expected[check.Name]; !ok || check.Healthy != healthy {
+			t.Errorf("unexpected %s check: %+v", check.Name, check)
+		}
+	}
+	if loaded := hc.Modules(); len(loaded) != 2 || loaded[0] != "consensus" || loaded[1] != "wallet" {
+		t.Errorf("unexpected loaded modules: %v", loaded)
+	}
+
+	// the daemon isn't ready if a required check fails
+	testCases := []struct {
+		name  string
+		setup func()
+	}{
+		{HealthCheckConsensus + " not synced", func() { cs.synced = false }},
+		{HealthCheckConsensus + " behind", func() { cs.timestamp = types.CurrentTimestamp() - 60 }},
+		{HealthCheckWallet, func() { hc.config.RequireUnlockedWallet = true }},
+		{HealthCheckDisk, func() { hc.config.PersistDir = filepath.Join(dir, "unknown") }},
+	}
+	for _, testCase := range testCases {
+		cs.synced, cs.timestamp = true, types.CurrentTimestamp()
+		hc.config.RequireUnlockedWallet, hc.config.PersistDir = false, dir
+		testCase.setup()
+		if status, resp := healthReadyTestRequest(t, hc); status != http.StatusServiceUnavailable || resp.Ready {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, http.StatusServiceUnavailable, status)
+		}
+	}
+
+	// an unlocked wallet is healthy
+	hc.config.PersistDir = dir
+	cs.synced, cs.timestamp = true, types.CurrentTimestamp()
+	wallet.unlocked = true
+	if status, _ := healthReadyTestRequest(t, hc); status != http.StatusOK {
+		t.Errorf("expected status %d with an unlocked wallet, not %d", http.StatusOK, status)
+	}
+}
+
+func TestHealthLiveHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHealthLiveHandler()(rec, httptest.NewRequest("GET", "/health/live", nil), nil)
+	var resp HealthLiveGET
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || !resp.Alive {
+		t.Errorf("expected status %d, not %d: %+v", http.StatusOK, rec.Code, resp)
+	}
+}
@@ -82,7 +82,7 @@ func HTTPPostAuthenticated(url, data, userAgent, password string) (resp *http.Re
 func RequireUserAgentHandler(h http.Handler, userAgent string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if !strings.Contains(req.UserAgent(), userAgent) {
-			WriteError(w, Error{"Browser access disabled due to security vulnerability. Use an official client."}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "Browser access disabled due to security vulnerability. Use an official client."}, http.StatusBadRequest)
 			return
 		}
 		h.ServeHTTP(w, req)
@@ -101,7 +101,7 @@ func RequirePasswordHandler(h httprouter.Handle, password string) httprouter.Han
 		_, pass, ok := req.BasicAuth()
 		if !ok || pass != password {
 			w.Header().Set("WWW-Authenticate", "Basic realm=\"SiaAPI\"")
-			WriteError(w, Error{"API Basic authentication failed."}, http.StatusUnauthorized)
+			WriteError(w, Error{Message: "API Basic authentication failed."}, http.StatusUnauthorized)
 			return
 		}
 		h(w, req, ps)
@@ -112,11 +112,14 @@ func RequirePasswordHandler(h httprouter.Handle, password string) httprouter.Han
 
 // UnrecognizedCallHandler handles calls to unknown pages (404).
 func UnrecognizedCallHandler(w http.ResponseWriter, req *http.Request) {
-	WriteError(w, Error{"404 - Refer to API.md"}, http.StatusNotFound)
+	WriteError(w, Error{Message: "404 - Refer to API.md"}, http.StatusNotFound)
 }
 
 // WriteError an error to the API caller.
 func WriteError(w http.ResponseWriter, err Error, code int) {
+	if err.RequestID == "" {
+		err.RequestID = w.Header().Get(RequestIDHeader)
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(err) // ignore error, as it probably means that the status code does not allow a body
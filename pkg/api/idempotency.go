@@ -0,0 +1,253 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader is the header used by clients to identify a request which can be retried,
+	// such that a retry is responded to with the response of the original request rather than being executed again.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses which are replayed for a retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyWindow is the default duration for which the responses of requests
+	// made with an idempotency key are stored.
+	DefaultIdempotencyWindow = 24 * time.Hour
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key
+	maxIdempotencyKeyLength = 255
+	// maxIdempotencyEntries is the maximum amount of stored responses,
+	// the oldest responses being dropped (even if still within the window) once exceeded
+	maxIdempotencyEntries = 10000
+	// maxIdempotentRequestSize is the maximum size of the body of a request made with an idempotency key
+	maxIdempotentRequestSize = 4 << 20
+)
+
+// IdempotentRoutes are the routes which create and send transactions,
+// for which POST requests can be made with an idempotency key.
+var IdempotentRoutes = []string{
+	"/transactionpool/transactions",
+	"/wallet/transaction",
+	"/wallet/coins",
+	"/wallet/blockstakes",
+	"/wallet/data",
+}
+
+// IdempotencyStore stores the responses of requests made with an idempotency key, for a limited window.
+type IdempotencyStore struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	// keys of the entries, in the order they were created
+	keys []string
+}
+
+type idempotencyEntry struct {
+	created time.Time
+	// fingerprint identifies the request, such that a key can't be reused for a different request
+	fingerprint [sha256.Size]byte
+	// done is closed once the response is stored
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+}
+
+// NewIdempotencyStore creates a store which stores responses for the given window.
+// Nil is returned if the window isn't positive, disabling idempotency keys.
+func NewIdempotencyStore(window time.Duration) *IdempotencyStore {
+	if window <= 0 {
+		return nil
+	}
+	return &IdempotencyStore{
+		window:  window,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// begin returns the entry stored for the given key, or creates a new (pending) entry if none is stored.
+// The returned bool is true if the entry is created.
+func (store *IdempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotencyEntry, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.prune(time.Now())
+	if entry, ok := store.entries[key]; ok {
+		return entry, false
+	}
+	entry := &idempotencyEntry{
+		created:     time.Now(),
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+	store.entries[key] = entry
+	store.keys = append(store.keys, key)
+	return entry, true
+}
+
+// finish stores the response of a pending entry, or removes the entry in case the response isn't to be stored.
+func (store *IdempotencyStore) finish(key string, entry *idempotencyEntry, rec *idempotencyRecorder) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if rec.status >= http.StatusInternalServerError {
+		// server errors are not stored, such that the request can be retried
+		if store.entries[key] == entry {
+			delete(store.entries, key)
+		}
+	} else {
+		entry.status = rec.status
+		entry.contentType = rec.Header().Get("Content-Type")
+		entry.body = rec.body.Bytes()
+	}
+	close(entry.done)
+}
+
+// prune removes the entries created before the window, as well as the oldest entries
+// in case too many entries are stored.
+func (store *IdempotencyStore) prune(now time.Time) {
+	n := 0
+	for ; n < len(store.keys); n++ {
+		entry, ok := store.entries[store.keys[n]]
+		if ok && now.Sub(entry.created) < store.window && len(store.keys)-n < maxIdempotencyEntries {
+			break
+		}
+		if ok {
+			delete(store.entries, store.keys[n])
+		}
+	}
+	store.keys = store.keys[n:]
+}
+
+// IdempotencyHandler is middleware that allows POST requests to the given routes to be made with an idempotency key
+// (using the Idempotency-Key header), storing their response in the given store. A retry of such a request
+// (with the same key, credentials and body) is responded to with the stored response, rather than being executed again,
+// preventing (for example) coins from being sent twice in case a client retries a request which timed out.
+// A request with a key which is already used for a different request is rejected.
+// All requests are served as-is in case the store is nil.
+func IdempotencyHandler(h http.Handler, store *IdempotencyStore, routes []string) http.Handler {
+	if store == nil {
+		return h
+	}
+	idempotentRoutes := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		idempotentRoutes[route] = struct{}{}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := req.Header.Get(IdempotencyKeyHeader)
+		if key == "" || req.Method != http.MethodPost {
+			h.ServeHTTP(w, req)
+			return
+		}
+		if _, ok := idempotentRoutes[req.URL.Path]; !ok {
+			WriteError(w, Error{Message: "idempotency keys are not supported by this route"}, http.StatusBadRequest)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength || !isPrintableASCII(key) {
+			WriteError(w, Error{Message: "invalid idempotency key: expected at most 255 printable ASCII characters"}, http.StatusBadRequest)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxIdempotentRequestSize+1))
+		if err != nil {
+			WriteError(w, Error{Message: "failed to read request body: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxIdempotentRequestSize {
+			WriteError(w, Error{Message: "request body is too large"}, http.StatusRequestEntityTooLarge)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// keys are scoped to the credentials of the request, such that stored responses
+		// are never returned to callers using other credentials
+		secret, _ := requestSecret(req)
+		keyHash := hashIdempotencyParts(secret, req.URL.Path, key)
+		storeKey := string(keyHash[:])
+		fingerprint := hashIdempotencyParts(req.URL.RawQuery, req.Header.Get("Content-Type"), string(body))
+
+		entry, created := store.begin(storeKey, fingerprint)
+		if !created {
+			if entry.fingerprint != fingerprint {
+				WriteError(w, Error{Message: "idempotency key is already used for a different request"}, http.StatusUnprocessableEntity)
+				return
+			}
+			select {
+			case <-entry.done:
+			default:
+				WriteError(w, Error{Message: "a request with the same idempotency key is still in progress"}, http.StatusConflict)
+				return
+			}
+			if entry.status == 0 {
+				// the original request failed with a server error, and can thus be retried
+				entry, created = store.begin(storeKey, fingerprint)
+				if !created {
+					WriteError(w, Error{Message: "a request with the same idempotency key is still in progress"}, http.StatusConflict)
+					return
+				}
+			}
+		}
+		if !created {
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			if entry.contentType != "" {
+				w.Header().Set("Content-Type", entry.contentType)
+			}
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		defer func() {
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			store.finish(storeKey, entry, rec)
+		}()
+		h.ServeHTTP(rec, req)
+	})
+}
+
+func hashIdempotencyParts(parts ...string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, part := range parts {
+		// parts are length-prefixed, such that their boundaries are unambiguous
+		var length [8]byte
+		binary.LittleEndian.PutUint64(length[:], uint64(len(part)))
+		h.Write(length[:])
+		io.WriteString(h, part)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// idempotencyRecorder writes a response, while recording it such that it can be stored.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.Write
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
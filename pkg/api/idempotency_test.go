@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// idempotencyTestServer counts the requests it executes,
+// responding with the amount of executed requests, or with a server error if requested.
+type idempotencyTestServer struct {
+	executed int
+}
+
+func (server *idempotencyTestServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server.executed++
+	body, _ := ioutil.ReadAll(req.Body)
+	if string(body) == "fail" {
+		WriteError(w, Error{Message: "internal error"}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, fmt.Sprintf("%s #%d", body, server.executed))
+}
+
+// idempotencyTestRequest serves a POST request with the given idempotency key, secret and body.
+func idempotencyTestRequest(h http.Handler, path, key, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyHandlerReplay(t *testing.T) {
+	server := new(idempotencyTestServer)
+	h := IdempotencyHandler(server, NewIdempotencyStore(time.Hour), IdempotentRoutes)
+
+	original := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send")
+	if original.Code != http.StatusOK || original.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("unexpected original response: %d: %v", original.Code, original.Header())
+	}
+	// a retry returns the stored response, without executing the request again
+	for i := 0; i < 2; i++ {
+		replay := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send")
+		if replay.Code != original.Code || replay.Body.String() != original.Body.String() ||
+			replay.Header().Get("Content-Type") != original.Header().Get("Content-Type") {
+			t.Errorf("replayed response differs: %d: %s != %d: %s", replay.Code, replay.Body.String(), original.Code, original.Body.String())
+		}
+		if replay.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Error("replayed response doesn't define the replayed header")
+		}
+	}
+	if server.executed != 1 {
+		t.Errorf("request is executed %d times", server.executed)
+	}
+
+	// keys are scoped per route and credentials, and requests without a key are always executed
+	idempotencyTestRequest(h, "/wallet/blockstakes", "key", "", "send")
+	idempotencyTestRequest(h, "/wallet/coins", "key", "secret", "send")
+	idempotencyTestRequest(h, "/wallet/coins", "", "", "send")
+	idempotencyTestRequest(h, "/wallet/coins", "", "", "send")
+	if server.executed != 5 {
+		t.Errorf("expected 5 executed requests, not %d", server.executed)
+	}
+
+	// server errors are not stored, such that the request can be retried
+	for i := 0; i < 2; i++ {
+		if rec := idempotencyTestRequest(h, "/wallet/coins", "failing", "", "fail"); rec.Code != http.StatusInternalServerError {
+			t.Errorf("unexpected status for a failing request: %d", rec.Code)
+		}
+	}
+	if server.executed != 7 {
+		t.Errorf("expected the failing request to be executed twice, total executed: %d", server.executed)
+	}
+}
+
+func TestIdempotencyHandlerRejections(t *testing.T) {
+	server := new(idempotencyTestServer)
+	h := IdempotencyHandler(server, NewIdempotencyStore(time.Hour), IdempotentRoutes)
+
+	if rec := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send 1"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rec.Code)
+	}
+	// a key can't be reused for a different request
+	rec := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send 2")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d reusing a key for a different body, not %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+
+	testCases := []struct {
+		name, path, key string
+		status          int
+	}{
+		{"unsupported route", "/wallet/unlock", "key", http.StatusBadRequest},
+		{"too long key", "/wallet/coins", strings.Repeat("k", maxIdempotencyKeyLength+1), http.StatusBadRequest},
+		{"non-printable key", "/wallet/coins", "key\x01", http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		if rec := idempotencyTestRequest(h, testCase.path, testCase.key, "", "send"); rec.Code != testCase.status {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, testCase.status, rec.Code)
+		}
+	}
+	if server.executed != 1 {
+		t.Errorf("rejected requests are executed, total executed: %d", server.executed)
+	}
+
+	// requests are served as-is without store
+	if h := IdempotencyHandler(server, NewIdempotencyStore(0), IdempotentRoutes); h != http.Handler(server) {
+		t.Error("idempotency handler is used without store")
+	}
+}
+
+func TestIdempotencyStoreExpiry(t *testing.T) {
+	server := new(idempotencyTestServer)
+	store := NewIdempotencyStore(time.Hour)
+	h := IdempotencyHandler(server, store, IdempotentRoutes)
+
+	first := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send")
+	idempotencyTestRequest(h, "/wallet/coins", "other", "", "send")
+	if len(store.entries) != 2 || len(store.keys) != 2 {
+		t.Fatalf("unexpected amount of stored entries: %d", len(store.entries))
+	}
+
+	// age the first entry beyond the window
+	store.mu.Lock()
+	store.entries[store.keys[0]].created = time.Now().Add(-time.Hour)
+	store.mu.Unlock()
+
+	second := idempotencyTestRequest(h, "/wallet/coins", "key", "", "send")
+	if second.Header().Get(IdempotentReplayedHeader) != "" || second.Body.String() == first.Body.String() {
+		t.Error("expired response is replayed")
+	}
+	if server.executed != 3 {
+		t.Errorf("expected the expired request to be executed again, total executed: %d", server.executed)
+	}
+	// the expired entry is replaced, while the other entry is still stored
+	if len(store.entries) != 2 || len(store.keys) != 2 {
+		t.Errorf("unexpected amount of stored entries after expiry: %d (%d keys)", len(store.entries), len(store.keys))
+	}
+	if rec := idempotencyTestRequest(h, "/wallet/coins", "other", "", "send"); rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("entry within the window isn't replayed")
+	}
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// JSONRPCVersion is the version of the JSON-RPC protocol served by the JSON-RPC endpoint.
+const JSONRPCVersion = "2.0"
+
+// JSON-RPC error codes. Next to the codes defined by the JSON-RPC 2.0 specification,
+// the codes used by Bitcoin-like daemons are used for application errors,
+// such that adapters built for those daemons can interpret them.
+const (
+	JSONRPCErrorParse          = -32700
+	JSONRPCErrorInvalidRequest = -32600
+	JSONRPCErrorMethodNotFound = -32601
+	JSONRPCErrorInvalidParams  = -32602
+	JSONRPCErrorInternal       = -32603
+	// JSONRPCErrorUnauthorized is returned for calls made with credentials lacking the scope required by the method
+	JSONRPCErrorUnauthorized = -32001
+
+	JSONRPCErrorWallet           = -4
+	JSONRPCErrorNotFound         = -5
+	JSONRPCErrorInvalidParameter = -8
+	JSONRPCErrorWalletLocked     = -13
+	JSONRPCErrorDeserialization  = -22
+	JSONRPCErrorVerifyRejected   = -26
+)
+
+type (
+	// JSONRPCRequest is a JSON-RPC 2.0 call, a call without ID being a notification,
+	// which is executed without being responded to.
+	JSONRPCRequest struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params,omitempty"`
+		ID      json.RawMessage `json:"id,omitempty"`
+	}
+
+	// JSONRPCResponse is the response to a JSON-RPC 2.0 call,
+	// containing either the result or the error of the call.
+	JSONRPCResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  interface{}     `json:"result,omitempty"`
+		Error   *JSONRPCError   `json:"error,omitempty"`
+		ID      json.RawMessage `json:"id"`
+	}
+
+	// JSONRPCError is the error returned by a failed JSON-RPC 2.0 call.
+	JSONRPCError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// JSONRPCBlock is the result of the getblock method, for the default verbosity of 1.
+	JSONRPCBlock struct {
+		Hash              types.BlockID         `json:"hash"`
+		Confirmations     uint64                `json:"confirmations"`
+		Size              int                   `json:"size"`
+		Height            types.BlockHeight     `json:"height"`
+		Time              types.Timestamp       `json:"time"`
+		Tx                []types.TransactionID `json:"tx"`
+		PreviousBlockHash string                `json:"previousblockhash,omitempty"`
+		NextBlockHash     string                `json:"nextblockhash,omitempty"`
+	}
+)
+
+// Error implements error.Error
+func (err *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", err.Code, err.Message)
+}
+
+// jsonRPCMethod is a method which can be called using the JSON-RPC endpoint.
+type jsonRPCMethod struct {
+	// scope is the scope required to call the method, if any
+	scope APIScope
+	call  func(params jsonRPCParams) (interface{}, *JSONRPCError)
+}
+
+// RegisterJSONRPCHTTPHandlers registers the handler for the JSON-RPC 2.0 endpoint,
+// mapping the standard methods of Bitcoin-like daemons onto the given modules, of which only the consensus set is required.
+// Methods requiring a module which isn't given are not available.
+func RegisterJSONRPCHTTPHandlers(router Router, cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet, explorer modules.Explorer, chainCts types.ChainConstants, auth *APIAuthenticator) {
+	if cs == nil {
+		build.Critical("no consensus module given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.POST("/jsonrpc", NewJSONRPCHandler(cs, tpool, wallet, explorer, chainCts, auth))
+}
+
+// newJSONRPCMethods creates the JSON-RPC methods available for the given modules.
+func newJSONRPCMethods(cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet, explorer modules.Explorer, chainCts types.ChainConstants) map[string]jsonRPCMethod {
+	methods := map[string]jsonRPCMethod{
+		"getblockcount": {call: func(jsonRPCParams) (interface{}, *JSONRPCError) {
+			return cs.Height(), nil
+		}},
+		"getbestblockhash": {call: func(jsonRPCParams) (interface{}, *JSONRPCError) {
+			return cs.CurrentBlock().ID(), nil
+		}},
+		"getblockhash": {call: func(params jsonRPCParams) (interface{}, *JSONRPCError) {
+			var height types.BlockHeight
+			if err := params.required(0, "height", &height); err != nil {
+				return nil, err
+			}
+			block, exists := cs.BlockAtHeight(height)
+			if !exists {
+				return nil, &JSONRPCError{Code: JSONRPCErrorInvalidParameter, Message: "Block height out of range"}
+			}
+			return block.ID(), nil
+		}},
+	}
+	if explorer != nil {
+		methods["getblock"] = jsonRPCMethod{call: func(params jsonRPCParams) (interface{}, *JSONRPCError) {
+			var id types.BlockID
+			if err := params.required(0, "blockhash", &id); err != nil {
+				return nil, err
+			}
+			verbosity := 1
+			if err := params.optional(1, "verbosity", &verbosity); err != nil {
+				return nil, err
+			}
+			if verbosity != 0 && verbosity != 1 {
+				return nil, &JSONRPCError{Code: JSONRPCErrorInvalidParameter, Message: "verbosity has to be 0 or 1"}
+			}
+			block, height, exists := explorer.Block(id)
+			if !exists {
+				return nil, &JSONRPCError{Code: JSONRPCErrorNotFound, Message: "Block not found"}
+			}
+			b, err := rivbin.Marshal(block)
+			if err != nil {
+				return nil, &JSONRPCError{Code: JSONRPCErrorInternal, Message: "failed to encode block: " + err.Error()}
+			}
+			if verbosity == 0 {
+				return hex.EncodeToString(b), nil
+			}
+			result := JSONRPCBlock{
+				Hash:          id,
+				Confirmations: uint64(cs.Height()-height) + 1,
+				Size:          len(b),
+				Height:        height,
+				Time:          block.Timestamp,
+				Tx:            make([]types.TransactionID, 0, len(block.Transactions)),
+			}
+			for _, txn := range block.Transactions {
+				result.Tx = append(result.Tx, txn.ID())
+			}
+			if height > 0 {
+				result.PreviousBlockHash = block.ParentID.String()
+			}
+			if next, exists := cs.BlockAtHeight(height + 1); exists {
+				result.NextBlockHash = next.ID().String()
+			}
+			return result, nil
+		}}
+	}
+	if tpool != nil {
+		methods["sendrawtransaction"] = jsonRPCMethod{scope: APIScopeWalletSpend, call: func(params jsonRPCParams) (interface{}, *JSONRPCError) {
+			var str string
+			if err := params.required(0, "hexstring", &str); err != nil {
+				return nil, err
+			}
+			b, err := hex.DecodeString(str)
+			if err != nil || len(b) > MaxRawTransactionSize {
+				return nil, &JSONRPCError{Code: JSONRPCErrorDeserialization, Message: "TX decode failed"}
+			}
+			var txn types.Transaction
+			r := bytes.NewReader(b)
+			if err := rivbin.NewDecoder(r).Decode(&txn); err != nil || r.Len() != 0 {
+				return nil, &JSONRPCError{Code: JSONRPCErrorDeserialization, Message: "TX decode failed"}
+			}
+			err = tpool.AcceptTransactionSet([]types.Transaction{txn})
+			if err != nil && err != modules.ErrDuplicateTransactionSet {
+				return nil, &JSONRPCError{Code: JSONRPCErrorVerifyRejected, Message: err.Error()}
+			}
+			return txn.ID(), nil
+		}}
+	}
+	if wallet != nil {
+		format := types.NewCurrencyFormat(chainCts.CurrencyUnits, "")
+		methods["getbalance"] = jsonRPCMethod{scope: APIScopeReadOnly, call: func(jsonRPCParams) (interface{}, *JSONRPCError) {
+			coins, _, err := wallet.ConfirmedBalance()
+			if err == modules.ErrLockedWallet {
+				return nil, &JSONRPCError{Code: JSONRPCErrorWalletLocked, Message: err.Error()}
+			}
+			if err != nil {
+				return nil, &JSONRPCError{Code: JSONRPCErrorWallet, Message: err.Error()}
+			}
+			// balances are returned as a number expressed in the coin unit, as done by Bitcoin-like daemons
+			return json.Number(format.FormatCoins(coins)), nil
+		}}
+	}
+	return methods
+}
+
+// NewJSONRPCHandler creates a handler to handle API calls to POST /jsonrpc,
+// executing a single JSON-RPC call or a batch of (at most MaxBatchRequests) calls.
+// Calls to methods requiring a scope are authorized using the credentials of the request.
+func NewJSONRPCHandler(cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet, explorer modules.Explorer, chainCts types.ChainConstants, auth *APIAuthenticator) httprouter.Handle {
+	methods := newJSONRPCMethods(cs, tpool, wallet, explorer, chainCts)
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body json.RawMessage
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil && err != io.EOF {
+			WriteJSON(w, newJSONRPCErrorResponse(nil, JSONRPCErrorParse, "Parse error"))
+			return
+		}
+		execute := func(raw json.RawMessage) *JSONRPCResponse {
+			var call JSONRPCRequest
+			if err := json.Unmarshal(raw, &call); err != nil || call.JSONRPC != JSONRPCVersion || call.Method == "" {
+				return newJSONRPCErrorResponse(call.ID, JSONRPCErrorInvalidRequest, "Invalid Request")
+			}
+			resp := executeJSONRPCCall(req, methods, auth, call)
+			if len(call.ID) == 0 {
+				// notifications are not responded to
+				return nil
+			}
+			return resp
+		}
+
+		body = bytes.TrimSpace(body)
+		if len(body) == 0 || body[0] != '[' {
+			if resp := execute(body); resp != nil {
+				WriteJSON(w, resp)
+				return
+			}
+			WriteSuccess(w)
+			return
+		}
+		var calls []json.RawMessage
+		if err := json.Unmarshal(body, &calls); err != nil || len(calls) == 0 {
+			WriteJSON(w, newJSONRPCErrorResponse(nil, JSONRPCErrorInvalidRequest, "Invalid Request"))
+			return
+		}
+		if len(calls) > MaxBatchRequests {
+			WriteJSON(w, newJSONRPCErrorResponse(nil, JSONRPCErrorInvalidRequest,
+				fmt.Sprintf("too many calls given in batch: at most %d calls are allowed", MaxBatchRequests)))
+			return
+		}
+		responses := make([]*JSONRPCResponse, 0, len(calls))
+		for _, raw := range calls {
+			if resp := execute(raw); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			WriteSuccess(w)
+			return
+		}
+		WriteJSON(w, responses)
+	}
+}
+
+// executeJSONRPCCall executes a single JSON-RPC call, authorizing it if required by its method.
+func executeJSONRPCCall(req *http.Request, methods map[string]jsonRPCMethod, auth *APIAuthenticator, call JSONRPCRequest) *JSONRPCResponse {
+	method, ok := methods[call.Method]
+	if !ok {
+		return newJSONRPCErrorResponse(call.ID, JSONRPCErrorMethodNotFound, "Method not found")
+	}
+	if method.scope != "" && !auth.Authorize(req, method.scope) {
+		return newJSONRPCErrorResponse(call.ID, JSONRPCErrorUnauthorized,
+			fmt.Sprintf("method %s requires credentials with the %s scope", call.Method, method.scope))
+	}
+	params, err := parseJSONRPCParams(call.Params)
+	if err != nil {
+		return newJSONRPCErrorResponse(call.ID, err.Code, err.Message)
+	}
+	result, err := method.call(params)
+	if err != nil {
+		return newJSONRPCErrorResponse(call.ID, err.Code, err.Message)
+	}
+	return &JSONRPCResponse{
+		JSONRPC: JSONRPCVersion,
+		Result:  result,
+		ID:      call.ID,
+	}
+}
+
+func newJSONRPCErrorResponse(id json.RawMessage, code int, message string) *JSONRPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &JSONRPCResponse{
+		JSONRPC: JSONRPCVersion,
+		Error:   &JSONRPCError{Code: code, Message: message},
+		ID:      id,
+	}
+}
+
+// jsonRPCParams are the parameters of a JSON-RPC call, given either by position or by name.
+type jsonRPCParams struct {
+	positional []json.RawMessage
+	named      map[string]json.RawMessage
+}
+
+func parseJSONRPCParams(raw json.RawMessage) (jsonRPCParams, *JSONRPCError) {
+	var params jsonRPCParams
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return params, nil
+	}
+	var err error
+	if raw[0] == '[' {
+		err = json.Unmarshal(raw, &params.positional)
+	} else {
+		err = json.Unmarshal(raw, &params.named)
+	}
+	if err != nil {
+		return params, &JSONRPCError{Code: JSONRPCErrorInvalidParams, Message: "params have to be an array or object"}
+	}
+	return params, nil
+}
+
+// optional decodes the parameter at the given position or with the given name into v,
+// leaving v untouched if the parameter isn't given (or null).
+func (params jsonRPCParams) optional(position int, name string, v interface{}) *JSONRPCError {
+	var raw json.RawMessage
+	if params.named != nil {
+		raw = params.named[name]
+	} else if position < len(params.positional) {
+		raw = params.positional[position]
+	}
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &JSONRPCError{Code: JSONRPCErrorInvalidParams, Message: fmt.Sprintf("invalid %s parameter: %v", name, err)}
+	}
+	return nil
+}
+
+// required decodes the parameter at the given position or with the given name into v,
+// returning an error if the parameter isn't given.
+func (params jsonRPCParams) required(position int, name string, v interface{}) *JSONRPCError {
+	var raw json.RawMessage
+	if params.named != nil {
+		raw = params.named[name]
+	} else if position < len(params.positional) {
+		raw = params.positional[position]
+	}
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return &JSONRPCError{Code: JSONRPCErrorInvalidParams, Message: fmt.Sprintf("missing %s parameter", name)}
+	}
+	return params.optional(position, name, v)
+}
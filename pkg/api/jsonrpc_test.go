@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// jsonRPCTestConsensusSet extends the pagination test consensus set with its current block.
+type jsonRPCTestConsensusSet struct {
+	*paginationTestConsensusSet
+}
+
+func (cs jsonRPCTestConsensusSet) CurrentBlock() types.Block {
+	return cs.blocks[len(cs.blocks)-1]
+}
+
+// jsonRPCTestExplorer is an explorer only looking up the blocks of its consensus set.
+type jsonRPCTestExplorer struct {
+	modules.Explorer
+	cs *paginationTestConsensusSet
+}
+
+func (explorer jsonRPCTestExplorer) Block(id types.BlockID) (types.Block, types.BlockHeight, bool) {
+	for height, block := range explorer.cs.blocks {
+		if block.ID() == id {
+			return block, types.BlockHeight(height), true
+		}
+	}
+	return types.Block{}, 0, false
+}
+
+// jsonRPCTestWallet is a wallet only returning its confirmed balance.
+type jsonRPCTestWallet struct {
+	modules.Wallet
+	balance types.Currency
+	err     error
+}
+
+func (w jsonRPCTestWallet) ConfirmedBalance() (types.Currency, types.Currency, error) {
+	return w.balance, types.ZeroCurrency, w.err
+}
+
+// jsonRPCTestCall posts the given body to the JSON-RPC handler using the given password,
+// returning the status and raw response.
+func jsonRPCTestCall(t *testing.T, h http.Handler, password, body string) (int, []byte) {
+	req := httptest.NewRequest("POST", "/jsonrpc", strings.NewReader(body))
+	if password != "" {
+		req.SetBasicAuth("", password)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.Bytes()
+}
+
+// jsonRPCTestResponse is a JSON-RPC response, keeping its result undecoded.
+type jsonRPCTestResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *JSONRPCError   `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newJSONRPCTestHandler(tpool modules.TransactionPool, wallet modules.Wallet) (http.Handler, *paginationTestConsensusSet) {
+	cs := newPaginationTestConsensusSet(3)
+	cs.blocks[1].Transactions = []types.Transaction{{Version: types.TransactionVersionOne, ArbitraryData: []byte("rivine")}}
+	cts := types.StandardnetChainConstants()
+	cts.CurrencyUnits = types.NewCurrencyUnits(9)
+	handler := NewJSONRPCHandler(jsonRPCTestConsensusSet{cs}, tpool, wallet, jsonRPCTestExplorer{cs: cs}, cts, NewPasswordAPIAuthenticator("password"))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handler(w, req, nil)
+	}), cs
+}
+
+func TestJSONRPCHandler(t *testing.T) {
+	tpool := new(transactionPoolTestPool)
+	wallet := jsonRPCTestWallet{balance: types.NewCurrency64(1500000000)}
+	h, cs := newJSONRPCTestHandler(tpool, wallet)
+
+	b, err := rivbin.Marshal(cs.blocks[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := types.Transaction{Version: types.TransactionVersionOne, ArbitraryData: []byte("jsonrpc")}
+	rawTxn, err := rivbin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockID, _ := json.Marshal(cs.blocks[1].ID())
+	block, _ := json.Marshal(JSONRPCBlock{
+		Hash:              cs.blocks[1].ID(),
+		Confirmations:     2,
+		Size:              len(b),
+		Height:            1,
+		Time:              cs.blocks[1].Timestamp,
+		Tx:                []types.TransactionID{cs.blocks[1].Transactions[0].ID()},
+		PreviousBlockHash: cs.blocks[0].ID().String(),
+		NextBlockHash:     cs.blocks[2].ID().String(),
+	})
+	bestBlockID, _ := json.Marshal(cs.blocks[2].ID())
+	txnID, _ := json.Marshal(txn.ID())
+
+	testCases := []struct {
+		method, params, expected string
+	}{
+		{"getblockcount", "", "2"},
+		{"getbestblockhash", "[]", string(bestBlockID)},
+		{"getblockhash", "[1]", string(blockID)},
+		{"getblockhash", `{"height": 1}`, string(blockID)},
+		{"getblock", "[" + string(blockID) + "]", string(block)},
+		{"getblock", "[" + string(blockID) + ", 0]", `"` + hex.EncodeToString(b) + `"`},
+		{"sendrawtransaction", `["` + hex.EncodeToString(rawTxn) + `"]`, string(txnID)},
+		{"getbalance", "", "1.5"},
+	}
+	for _, testCase := range testCases {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "` + testCase.method + `"`
+		if testCase.params != "" {
+			body += `, "params": ` + testCase.params
+		}
+		status, b := jsonRPCTestCall(t, h, "password", body+"}")
+		var resp jsonRPCTestResponse
+		if err := json.Unmarshal(b, &resp); err != nil || status != http.StatusOK {
+			t.Errorf("%s: unexpected response: %d: %s", testCase.method, status, b)
+			continue
+		}
+		if resp.Error != nil || resp.JSONRPC != JSONRPCVersion || string(resp.ID) != "1" {
+			t.Errorf("%s: unexpected response: %s", testCase.method, b)
+		}
+		if string(resp.Result) != testCase.expected {
+			t.Errorf("%s: expected result %s, not %s", testCase.method, testCase.expected, resp.Result)
+		}
+	}
+	if len(tpool.accepted) != 1 || tpool.accepted[0].ID() != txn.ID() {
+		t.Errorf("unexpected accepted transactions: %v", tpool.accepted)
+	}
+
+	// batched calls are responded to in order, notifications excluded
+	status, b := jsonRPCTestCall(t, h, "", `[
+		{"jsonrpc": "2.0", "id": "a", "method": "getblockcount"},
+		{"jsonrpc": "2.0", "method": "getblockcount"},
+		{"jsonrpc": "2.0", "id": "b", "method": "getblockhash", "params": [1]}
+	]`)
+	var responses []jsonRPCTestResponse
+	if err := json.Unmarshal(b, &responses); err != nil || status != http.StatusOK {
+		t.Fatalf("unexpected batch response: %d: %s", status, b)
+	}
+	if len(responses) != 2 || string(responses[0].ID) != `"a"` || string(responses[0].Result) != "2" ||
+		string(responses[1].ID) != `"b"` || string(responses[1].Result) != string(blockID) {
+		t.Errorf("unexpected batch response: %s", b)
+	}
+
+	// notifications only are not responded to
+	if status, b := jsonRPCTestCall(t, h, "", `{"jsonrpc": "2.0", "method": "getblockcount"}`); status != http.StatusNoContent {
+		t.Errorf("expected status %d for a notification, not %d: %s", http.StatusNoContent, status, b)
+	}
+}
+
+func TestJSONRPCHandlerErrors(t *testing.T) {
+	tpool := &transactionPoolTestPool{err: errors.New("invalid transaction")}
+	h, _ := newJSONRPCTestHandler(tpool, jsonRPCTestWallet{err: modules.ErrLockedWallet})
+
+	rawTxn, err := rivbin.Marshal(types.Transaction{Version: types.TransactionVersionOne})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func(method, params string) string {
+		return `{"jsonrpc": "2.0", "id": 1, "method": "` + method + `", "params": ` + params + `}`
+	}
+	testCases := []struct {
+		name, password, body string
+		code                 int
+	}{
+		{"invalid JSON", "", "{", JSONRPCErrorParse},
+		{"invalid version", "", `{"jsonrpc": "1.0", "id": 1, "method": "getblockcount"}`, JSONRPCErrorInvalidRequest},
+		{"empty batch", "", "[]", JSONRPCErrorInvalidRequest},
+		{"too large batch", "", "[" + strings.Repeat("1,", MaxBatchRequests) + "1]", JSONRPCErrorInvalidRequest},
+		{"unknown method", "", call("getmininginfo", "[]"), JSONRPCErrorMethodNotFound},
+		{"invalid params", "", call("getblockhash", `"1"`), JSONRPCErrorInvalidParams},
+		{"missing param", "", call("getblockhash", "[]"), JSONRPCErrorInvalidParams},
+		{"invalid param", "", call("getblockhash", `["x"]`), JSONRPCErrorInvalidParams},
+		{"unknown height", "", call("getblockhash", "[3]"), JSONRPCErrorInvalidParameter},
+		{"unknown block", "", call("getblock", `["`+strings.Repeat("0", 64)+`"]`), JSONRPCErrorNotFound},
+		{"invalid verbosity", "", call("getblock", `["`+strings.Repeat("0", 64)+`", 2]`), JSONRPCErrorInvalidParameter},
+		{"unauthorized", "", call("sendrawtransaction", `["`+hex.EncodeToString(rawTxn)+`"]`), JSONRPCErrorUnauthorized},
+		{"invalid transaction", "password", call("sendrawtransaction", `["xyz"]`), JSONRPCErrorDeserialization},
+		{"trailing bytes", "password", call("sendrawtransaction", `["`+hex.EncodeToString(rawTxn)+`00"]`), JSONRPCErrorDeserialization},
+		{"rejected transaction", "password", call("sendrawtransaction", `["`+hex.EncodeToString(rawTxn)+`"]`), JSONRPCErrorVerifyRejected},
+		{"locked wallet", "password", call("getbalance", "[]"), JSONRPCErrorWalletLocked},
+	}
+	for _, testCase := range testCases {
+		status, b := jsonRPCTestCall(t, h, testCase.password, testCase.body)
+		var resp jsonRPCTestResponse
+		if err := json.Unmarshal(b, &resp); err != nil || status != http.StatusOK {
+			t.Errorf("%s: unexpected response: %d: %s", testCase.name, status, b)
+			continue
+		}
+		if resp.Error == nil || resp.Error.Code != testCase.code || resp.Result != nil {
+			t.Errorf("%s: expected error code %d: %s", testCase.name, testCase.code, b)
+		}
+	}
+
+	// errors of batched calls are returned per call
+	status, b := jsonRPCTestCall(t, h, "", "["+call("getblockhash", "[3]")+`, 1]`)
+	var responses []jsonRPCTestResponse
+	if err := json.Unmarshal(b, &responses); err != nil || status != http.StatusOK || len(responses) != 2 {
+		t.Fatalf("unexpected batch response: %d: %s", status, b)
+	}
+	if responses[0].Error == nil || responses[0].Error.Code != JSONRPCErrorInvalidParameter || string(responses[0].ID) != "1" ||
+		responses[1].Error == nil || responses[1].Error.Code != JSONRPCErrorInvalidRequest || string(responses[1].ID) != "null" {
+		t.Errorf("unexpected batch response: %s", b)
+	}
+}
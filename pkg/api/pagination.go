@@ -0,0 +1,148 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// PaginationOrder defines the order in which paginated results are returned.
+type PaginationOrder string
+
+// The orders in which paginated results can be returned.
+const (
+	PaginationOrderAscending  PaginationOrder = "asc"
+	PaginationOrderDescending PaginationOrder = "desc"
+)
+
+const (
+	// DefaultPaginationLimit is the amount of results returned per page
+	// by paginated endpoints, in case no limit is given.
+	DefaultPaginationLimit = 50
+	// MaxPaginationLimit is the maximum amount of results returned per page
+	// by paginated endpoints that can return expensive results (e.g. blocks).
+	MaxPaginationLimit = 500
+)
+
+// Pagination defines the page of results requested,
+// using the `offset`, `limit` and `order` query parameters.
+type Pagination struct {
+	Offset uint64
+	// Limit is the maximum amount of results to return, 0 meaning no limit
+	Limit uint64
+	Order PaginationOrder
+}
+
+// PageInfo is returned as part of paginated results,
+// describing the page returned and the total amount of results available.
+type PageInfo struct {
+	Offset uint64          `json:"offset"`
+	Limit  uint64          `json:"limit"`
+	Order  PaginationOrder `json:"order"`
+	Total  uint64          `json:"total"`
+}
+
+// ParsePagination parses the optional `offset`, `limit` and `order` query parameters of a request.
+// The given default limit is used in case no limit is given, 0 meaning no limit,
+// while a non-zero max limit caps the given limit.
+func ParsePagination(req *http.Request, defaultLimit, maxLimit uint64) (Pagination, error) {
+	pagination := Pagination{
+		Limit: defaultLimit,
+		Order: PaginationOrderAscending,
+	}
+	var err error
+	if str := req.FormValue("offset"); str != "" {
+		pagination.Offset, err = strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return Pagination{}, fmt.Errorf("invalid offset: %v", err)
+		}
+	}
+	if str := req.FormValue("limit"); str != "" {
+		pagination.Limit, err = strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return Pagination{}, fmt.Errorf("invalid limit: %v", err)
+		}
+		if pagination.Limit == 0 {
+			return Pagination{}, fmt.Errorf("invalid limit: has to be positive")
+		}
+	}
+	if maxLimit != 0 && (pagination.Limit == 0 || pagination.Limit > maxLimit) {
+		pagination.Limit = maxLimit
+	}
+	switch order := PaginationOrder(req.FormValue("order")); order {
+	case "":
+	case PaginationOrderAscending, PaginationOrderDescending:
+		pagination.Order = order
+	default:
+		return Pagination{}, fmt.Errorf("invalid order %q: expected %q or %q", order, PaginationOrderAscending, PaginationOrderDescending)
+	}
+	return pagination, nil
+}
+
+// Bounds returns the (ascending) start and end index of the page
+// within a result set of the given size. Callers are expected to
+// reverse the result set themselves in case a descending order is requested.
+func (pagination Pagination) Bounds(total uint64) (start, end uint64) {
+	if pagination.Offset >= total {
+		return total, total
+	}
+	start, end = pagination.Offset, total
+	if pagination.Limit != 0 && end-start > pagination.Limit {
+		end = start + pagination.Limit
+	}
+	return start, end
+}
+
+// PageInfo returns the info of the page, for a result set of the given size.
+func (pagination Pagination) PageInfo(total uint64) PageInfo {
+	return PageInfo{
+		Offset: pagination.Offset,
+		Limit:  pagination.Limit,
+		Order:  pagination.Order,
+		Total:  total,
+	}
+}
+
+// ParseHeightRange parses the optional `start` and `end` (inclusive) block height query parameters of a request,
+// defaulting to the genesis block and given (current) height, the latter also used to cap the end height.
+func ParseHeightRange(req *http.Request, height types.BlockHeight) (start, end types.BlockHeight, err error) {
+	end = height
+	if str := req.FormValue("start"); str != "" {
+		n, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start height: %v", err)
+		}
+		start = types.BlockHeight(n)
+	}
+	if str := req.FormValue("end"); str != "" {
+		n, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end height: %v", err)
+		}
+		if types.BlockHeight(n) < end {
+			end = types.BlockHeight(n)
+		}
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("invalid height range: start height %d is greater than end height %d", start, end)
+	}
+	return start, end, nil
+}
+
+// paginatedHeights returns the heights of the requested page within the given (inclusive) height range,
+// in the requested order, as well as the info of that page.
+func paginatedHeights(start, end types.BlockHeight, pagination Pagination) ([]types.BlockHeight, PageInfo) {
+	total := uint64(end-start) + 1
+	first, last := pagination.Bounds(total)
+	heights := make([]types.BlockHeight, 0, last-first)
+	for i := first; i < last; i++ {
+		if pagination.Order == PaginationOrderDescending {
+			heights = append(heights, end-types.BlockHeight(i))
+		} else {
+			heights = append(heights, start+types.BlockHeight(i))
+		}
+	}
+	return heights, pagination.PageInfo(total)
+}
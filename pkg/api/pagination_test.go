@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// paginationTestConsensusSet is a consensus set with a path of blocks,
+// only implementing the methods used by the paginated block endpoints.
+type paginationTestConsensusSet struct {
+	modules.ConsensusSet
+	blocks []types.Block
+}
+
+func newPaginationTestConsensusSet(n int) *paginationTestConsensusSet {
+	cs := new(paginationTestConsensusSet)
+	for i := 0; i < n; i++ {
+		block := types.Block{Timestamp: types.Timestamp(i)}
+		if i > 0 {
+			block.ParentID = cs.blocks[i-1].ID()
+		}
+		cs.blocks = append(cs.blocks, block)
+	}
+	return cs
+}
+
+func (cs *paginationTestConsensusSet) Height() types.BlockHeight {
+	return types.BlockHeight(len(cs.blocks) - 1)
+}
+
+func (cs *paginationTestConsensusSet) BlockAtHeight(height types.BlockHeight) (types.Block, bool) {
+	if height >= types.BlockHeight(len(cs.blocks)) {
+		return types.Block{}, false
+	}
+	return cs.blocks[height], true
+}
+
+func TestParsePagination(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected Pagination
+	}{
+		{"", Pagination{Limit: 50, Order: PaginationOrderAscending}},
+		{"offset=10&limit=5&order=desc", Pagination{Offset: 10, Limit: 5, Order: PaginationOrderDescending}},
+		// the limit is capped
+		{"limit=1000", Pagination{Limit: 500, Order: PaginationOrderAscending}},
+	}
+	for _, testCase := range testCases {
+		pagination, err := ParsePagination(httptest.NewRequest("GET", "/?"+testCase.query, nil), 50, 500)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.query, err)
+		} else if pagination != testCase.expected {
+			t.Errorf("%s: unexpected pagination: %+v != %+v", testCase.query, pagination, testCase.expected)
+		}
+	}
+	for _, query := range []string{"offset=-1", "limit=0", "limit=x", "order=random"} {
+		if _, err := ParsePagination(httptest.NewRequest("GET", "/?"+query, nil), 50, 500); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+
+	// no limit is applied without default and max limit
+	pagination, err := ParsePagination(httptest.NewRequest("GET", "/", nil), 0, 0)
+	if err != nil || pagination.Limit != 0 {
+		t.Errorf("unexpected pagination without limit: %+v (%v)", pagination, err)
+	}
+	if start, end := pagination.Bounds(3); start != 0 || end != 3 {
+		t.Errorf("unexpected bounds without limit: [%d, %d)", start, end)
+	}
+	if start, end := (Pagination{Offset: 5, Limit: 2}).Bounds(3); start != 3 || end != 3 {
+		t.Errorf("unexpected bounds beyond the result set: [%d, %d)", start, end)
+	}
+}
+
+// consensusBlocksTestRequest requests a page of blocks, returning the status and decoded response.
+func consensusBlocksTestRequest(t *testing.T, cs modules.ConsensusSet, query string) (int, ConsensusGetBlocks) {
+	rec := httptest.NewRecorder()
+	NewConsensusGetBlocksHandler(cs)(rec, httptest.NewRequest("GET", "/consensus/blocks?"+query, nil), nil)
+	var resp ConsensusGetBlocks
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestConsensusGetBlocksHandler(t *testing.T) {
+	cs := newPaginationTestConsensusSet(10)
+
+	testCases := []struct {
+		query    string
+		heights  []types.BlockHeight
+		pageInfo PageInfo
+	}{
+		{"limit=3", []types.BlockHeight{0, 1, 2}, PageInfo{Limit: 3, Order: PaginationOrderAscending, Total: 10}},
+		{"limit=3&offset=8", []types.BlockHeight{8, 9}, PageInfo{Offset: 8, Limit: 3, Order: PaginationOrderAscending, Total: 10}},
+		{"limit=3&order=desc", []types.BlockHeight{9, 8, 7}, PageInfo{Limit: 3, Order: PaginationOrderDescending, Total: 10}},
+		{"start=2&end=4&order=desc", []types.BlockHeight{4, 3, 2}, PageInfo{Limit: 50, Order: PaginationOrderDescending, Total: 3}},
+		// the end height is capped to the current height
+		{"start=8&end=100", []types.BlockHeight{8, 9}, PageInfo{Limit: 50, Order: PaginationOrderAscending, Total: 2}},
+	}
+	for _, testCase := range testCases {
+		status, resp := consensusBlocksTestRequest(t, cs, testCase.query)
+		if status != http.StatusOK {
+			t.Errorf("%s: expected status %d, not %d", testCase.query, http.StatusOK, status)
+			continue
+		}
+		if resp.PageInfo != testCase.pageInfo {
+			t.Errorf("%s: unexpected page info: %+v != %+v", testCase.query, resp.PageInfo, testCase.pageInfo)
+		}
+		if len(resp.Blocks) != len(testCase.heights) {
+			t.Errorf("%s: expected %d blocks, not %d", testCase.query, len(testCase.heights), len(resp.Blocks))
+			continue
+		}
+		for i, block := range resp.Blocks {
+			height := testCase.heights[i]
+			if block.Height != height || block.ID != cs.blocks[height].ID() {
+				t.Errorf("%s: unexpected block #%d: %v at height %d", testCase.query, i, block.ID, block.Height)
+			}
+		}
+	}
+
+	for _, query := range []string{"start=5&end=4", "start=x", "limit=0", "order=random"} {
+		if status, _ := consensusBlocksTestRequest(t, cs, query); status != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", query, http.StatusBadRequest, status)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIPublicRoutes are the read-only routes exposing public chain and network information
+// (blocks, transactions, outputs, addresses and gateway info), which can be served without authentication
+// by a daemon that otherwise requires authentication for all routes.
+var DefaultAPIPublicRoutes = []string{
+	"/consensus",
+	"/consensus/*",
+	"/explorer",
+	"/explorer/*",
+	"/addresses/*",
+	"/transactionpool/transactions",
+	"/gateway",
+	"/daemon/constants",
+	"/daemon/version",
+}
+
+// publicRoute is a parsed route pattern, matching the path of a request.
+type publicRoute struct {
+	segments []string
+	// wildcard indicates the pattern ends with a *, matching any amount of remaining segments
+	wildcard bool
+}
+
+// parsePublicRoute parses a route pattern, which has to be an absolute path,
+// in which :name segments match any single path segment and a final * segment matches any remaining segments.
+func parsePublicRoute(pattern string) (publicRoute, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return publicRoute{}, fmt.Errorf("invalid public API route %q: has to start with a /", pattern)
+	}
+	var route publicRoute
+	segments := splitPath(pattern)
+	for i, segment := range segments {
+		if segment == "*" {
+			if i != len(segments)-1 {
+				return publicRoute{}, fmt.Errorf("invalid public API route %q: * is only allowed as the last segment", pattern)
+			}
+			route.wildcard = true
+			break
+		}
+		if segment == "" || segment == ":" {
+			return publicRoute{}, fmt.Errorf("invalid public API route %q: contains an empty segment", pattern)
+		}
+		route.segments = append(route.segments, segment)
+	}
+	return route, nil
+}
+
+// matches returns true if this route matches the given (split) path.
+// Paths containing dot segments never match, as they might resolve to another route.
+func (route publicRoute) matches(segments []string) bool {
+	if len(segments) < len(route.segments) || (!route.wildcard && len(segments) != len(route.segments)) {
+		return false
+	}
+	for _, segment := range segments {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+	for i, segment := range route.segments {
+		if segment[0] != ':' && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// PublicRoutesHandler is middleware that requires all requests to authenticate with credentials
+// granting (at least) read-only access, except for GET requests to one of the given public route patterns,
+// such that a daemon can expose its public information while keeping all other routes protected.
+// Routes which require a specific scope still require it, even when matching a public route pattern.
+//
+// Patterns are absolute paths, in which :name segments match any single path segment
+// and a final * segment matches any remaining segments (e.g. /explorer/* or /explorer/blocks/:height).
+// All requests are served as-is in case no public routes are given or the authenticator is nil or disabled,
+// the latter being checked for each request, as API tokens can be created at runtime.
+func PublicRoutesHandler(h http.Handler, auth *APIAuthenticator, patterns []string) (http.Handler, error) {
+	if len(patterns) == 0 || auth == nil {
+		return h, nil
+	}
+	routes := make([]publicRoute, 0, len(patterns))
+	for _, pattern := range patterns {
+		route, err := parsePublicRoute(pattern)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			segments := splitPath(req.URL.Path)
+			for _, route := range routes {
+				if route.matches(segments) {
+					h.ServeHTTP(w, req)
+					return
+				}
+			}
+		}
+		if !auth.Authorize(req, APIScopeReadOnly) {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"SiaAPI\"")
+			WriteError(w, Error{Message: "API Basic authentication failed."}, http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, req)
+	}), nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublicRoutesHandler(t *testing.T) {
+	auth := NewPasswordAPIAuthenticator("password")
+	h, err := PublicRoutesHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), auth, []string{"/consensus", "/explorer/*", "/explorer/blocks/:height/transactions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		method, path string
+		public       bool
+	}{
+		// public routes
+		{"GET", "/consensus", true},
+		{"GET", "/consensus/", true},
+		{"HEAD", "/consensus", true},
+		{"GET", "/explorer", true},
+		{"GET", "/explorer/hashes/abcd", true},
+		{"GET", "/explorer/blocks/42/transactions", true},
+		// private routes
+		{"GET", "/wallet", false},
+		{"GET", "/daemon/stop", false},
+		{"POST", "/consensus", false},
+		{"POST", "/explorer/hashes/abcd", false},
+		{"GET", "/consensus/blocks/42", false},
+		// paths merely sharing a prefix with a public route
+		{"GET", "/consensusx", false},
+		{"GET", "/explorerx/secret", false},
+		{"GET", "/consensus/../wallet", false},
+		{"GET", "/explorer/../wallet", false},
+		{"GET", "/explorer/./hashes", false},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, "http://localhost"+testCase.path, nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		expected := http.StatusUnauthorized
+		if testCase.public {
+			expected = http.StatusOK
+		}
+		if rec.Code != expected {
+			t.Errorf("%s %s: expected status %d without credentials, not %d", testCase.method, testCase.path, expected, rec.Code)
+		}
+
+		// all routes are served using (at least) read-only credentials
+		token, err := auth.CreateToken(testCase.method+" "+testCase.path, []APIScope{APIScopeReadOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = httptest.NewRequest(testCase.method, "http://localhost"+testCase.path, nil)
+		req.Header.Set("Authorization", "Bearer "+token.Secret)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s %s: expected status %d using read-only credentials, not %d", testCase.method, testCase.path, http.StatusOK, rec.Code)
+		}
+	}
+}
+
+func TestPublicRoutesHandlerDisabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	// requests are served as-is without public routes or without authentication
+	for _, auth := range []*APIAuthenticator{nil, NewPasswordAPIAuthenticator("")} {
+		h, err := PublicRoutesHandler(next, auth, []string{"/consensus"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/wallet", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d with authentication disabled, not %d", http.StatusOK, rec.Code)
+		}
+	}
+
+	for _, pattern := range []string{"consensus", "/explorer/*/blocks", "/explorer//blocks", "/explorer/:"} {
+		if _, err := PublicRoutesHandler(next, NewPasswordAPIAuthenticator("password"), []string{pattern}); err == nil {
+			t.Errorf("expected an error for invalid pattern %q", pattern)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// RateLimit defines a token bucket rate limit,
+// allowing a sustained amount of requests per second, with bursts up to a given amount of requests.
+// A rate limit is disabled if its rate is not positive.
+type RateLimit struct {
+	// Rate is the amount of requests per second that are allowed on average
+	Rate float64
+	// Burst is the maximum amount of requests that are allowed at once,
+	// defaults to the (ceiled) rate if it is not positive
+	Burst int
+}
+
+// Enabled returns true if the rate limit is enabled.
+func (limit RateLimit) Enabled() bool {
+	return limit.Rate > 0
+}
+
+func (limit RateLimit) burst() float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+	return math.Max(1, math.Ceil(limit.Rate))
+}
+
+// RateLimiterConfig configures a RateLimiter.
+type RateLimiterConfig struct {
+	// IP is the rate limit applied per client IP address,
+	// to all requests not authenticated using an API token
+	IP RateLimit
+	// Token is the rate limit applied per API token,
+	// to all requests authenticated using that API token
+	Token RateLimit
+	// ExemptRoutes are the route patterns (see PublicRoutesHandler) of which requests
+	// are never rate limited, e.g. routes polled by monitoring tools
+	ExemptRoutes []string
+}
+
+// RateLimiterStats contains the statistics of a RateLimiter.
+type RateLimiterStats struct {
+	// Allowed is the amount of requests that were allowed
+	Allowed uint64 `json:"allowed"`
+	// Exempt is the amount of requests that were allowed as they match an exempt route
+	Exempt uint64 `json:"exempt"`
+	// ThrottledIP is the amount of requests that were throttled by the per-IP rate limit
+	ThrottledIP uint64 `json:"throttledip"`
+	// ThrottledToken is the amount of requests that were throttled by the per-token rate limit
+	ThrottledToken uint64 `json:"throttledtoken"`
+	// TrackedIPs is the amount of client IP addresses currently tracked
+	TrackedIPs int `json:"trackedips"`
+	// TrackedTokens is the amount of API tokens currently tracked
+	TrackedTokens int `json:"trackedtokens"`
+}
+
+// rateLimiterPruneInterval defines how often idle buckets are removed from a RateLimiter
+const rateLimiterPruneInterval = time.Minute
+
+// RateLimiter limits the rate of API requests using token buckets,
+// tracked per API token for requests authenticated using an API token,
+// and per client IP address for all other requests.
+type RateLimiter struct {
+	config RateLimiterConfig
+	auth   *APIAuthenticator
+	exempt []publicRoute
+
+	mu        sync.Mutex
+	ipBuckets map[string]*tokenBucket
+	tkBuckets map[string]*tokenBucket
+	lastPrune time.Time
+	stats     RateLimiterStats
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastTime time.Time
+}
+
+// take refills the bucket according to the time passed and takes a single token,
+// returning false and the time until a token is available if the bucket is empty.
+func (bucket *tokenBucket) take(limit RateLimit, now time.Time) (bool, time.Duration) {
+	burst := limit.burst()
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.lastTime).Seconds()*limit.Rate)
+	bucket.lastTime = now
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// full returns true if the bucket would be full at the given time,
+// meaning it is no longer required to track it.
+func (bucket *tokenBucket) full(limit RateLimit, now time.Time) bool {
+	return bucket.tokens+now.Sub(bucket.lastTime).Seconds()*limit.Rate >= limit.burst()
+}
+
+// NewRateLimiter creates a new RateLimiter, using the given authenticator
+// to identify requests authenticated using an API token. It returns nil
+// in case neither the per-IP nor the per-token rate limit is enabled,
+// and an error in case one of the exempt route patterns is invalid.
+func NewRateLimiter(config RateLimiterConfig, auth *APIAuthenticator) (*RateLimiter, error) {
+	if !config.IP.Enabled() && !config.Token.Enabled() {
+		return nil, nil
+	}
+	exempt := make([]publicRoute, 0, len(config.ExemptRoutes))
+	for _, pattern := range config.ExemptRoutes {
+		route, err := parsePublicRoute(pattern)
+		if err != nil {
+			return nil, err
+		}
+		exempt = append(exempt, route)
+	}
+	return &RateLimiter{
+		config:    config,
+		auth:      auth,
+		exempt:    exempt,
+		ipBuckets: make(map[string]*tokenBucket),
+		tkBuckets: make(map[string]*tokenBucket),
+		lastPrune: time.Now(),
+	}, nil
+}
+
+// isExempt returns true if the given request matches one of the exempt routes.
+func (rl *RateLimiter) isExempt(req *http.Request) bool {
+	if len(rl.exempt) == 0 {
+		return false
+	}
+	segments := splitPath(req.URL.Path)
+	for _, route := range rl.exempt {
+		if route.matches(segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow returns true if the given request is allowed by the rate limits,
+// and otherwise false as well as the time after which the request should be retried.
+func (rl *RateLimiter) Allow(req *http.Request) (bool, time.Duration) {
+	if rl.isExempt(req) {
+		rl.mu.Lock()
+		rl.stats.Exempt++
+		rl.mu.Unlock()
+		return true, 0
+	}
+	limit, buckets, throttled := rl.config.IP, rl.ipBuckets, &rl.stats.ThrottledIP
+	key := clientIP(req)
+	if name, ok := rl.auth.TokenName(req); ok {
+		limit, buckets, throttled = rl.config.Token, rl.tkBuckets, &rl.stats.ThrottledToken
+		key = name
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	rl.prune(now)
+	if !limit.Enabled() {
+		rl.stats.Allowed++
+		return true, 0
+	}
+	bucket, ok := buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit.burst(), lastTime: now}
+		buckets[key] = bucket
+	}
+	allowed, retryAfter := bucket.take(limit, now)
+	if !allowed {
+		*throttled++
+		return false, retryAfter
+	}
+	rl.stats.Allowed++
+	return true, 0
+}
+
+// prune removes all buckets that have been refilled completely,
+// as these behave the same as new buckets. Assumes the lock is held.
+func (rl *RateLimiter) prune(now time.Time) {
+	if now.Sub(rl.lastPrune) < rateLimiterPruneInterval {
+		return
+	}
+	rl.lastPrune = now
+	for key, bucket := range rl.ipBuckets {
+		if bucket.full(rl.config.IP, now) {
+			delete(rl.ipBuckets, key)
+		}
+	}
+	for key, bucket := range rl.tkBuckets {
+		if bucket.full(rl.config.Token, now) {
+			delete(rl.tkBuckets, key)
+		}
+	}
+}
+
+// Stats returns the current statistics of the rate limiter.
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	stats := rl.stats
+	stats.TrackedIPs = len(rl.ipBuckets)
+	stats.TrackedTokens = len(rl.tkBuckets)
+	return stats
+}
+
+// clientIP returns the IP address of the client that made the given request.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// RateLimitHandler is middleware that rejects requests exceeding the rate limits
+// of the given rate limiter with a 429 (Too Many Requests) response.
+// All requests are allowed in case the rate limiter is nil.
+func RateLimitHandler(h http.Handler, rl *RateLimiter) http.Handler {
+	if rl == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		allowed, retryAfter := rl.Allow(req)
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			WriteError(w, Error{Message: "API rate limit exceeded"}, http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// RegisterRateLimitHTTPHandlers registers the default Rivine handlers for the rate limit HTTP endpoints.
+func RegisterRateLimitHTTPHandlers(router Router, rl *RateLimiter, auth *APIAuthenticator) {
+	if rl == nil {
+		build.Critical("no RateLimiter given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/daemon/ratelimit", RequireScopeHandler(NewDaemonRateLimitHandler(rl), auth, APIScopeAdmin))
+}
+
+// NewDaemonRateLimitHandler creates a handler to handle API calls to GET /daemon/ratelimit.
+func NewDaemonRateLimitHandler(rl *RateLimiter) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, rl.Stats())
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstAndRefill(t *testing.T) {
+	limit := RateLimit{Rate: 2, Burst: 3}
+	now := time.Now()
+	bucket := &tokenBucket{tokens: limit.burst(), lastTime: now}
+
+	// the burst is allowed at once
+	for i := 0; i < 3; i++ {
+		if ok, _ := bucket.take(limit, now); !ok {
+			t.Fatalf("request #%d of the burst is throttled", i+1)
+		}
+	}
+	ok, retryAfter := bucket.take(limit, now)
+	if ok {
+		t.Fatal("request exceeding the burst is allowed")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Errorf("unexpected retry after: %v", retryAfter)
+	}
+
+	// tokens are refilled at the configured rate
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ = bucket.take(limit, now); !ok {
+		t.Fatal("request is throttled after a token is refilled")
+	}
+	if ok, _ = bucket.take(limit, now); ok {
+		t.Fatal("request is allowed while no token is refilled")
+	}
+
+	// the bucket is never refilled beyond its burst
+	now = now.Add(time.Hour)
+	if !bucket.full(limit, now) {
+		t.Error("bucket is not full after an hour")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _ := bucket.take(limit, now); !ok {
+			t.Fatalf("request #%d of the refilled burst is throttled", i+1)
+		}
+	}
+	if ok, _ = bucket.take(limit, now); ok {
+		t.Fatal("request exceeding the refilled burst is allowed")
+	}
+
+	// the burst defaults to the (ceiled) rate
+	if burst := (RateLimit{Rate: 2.5}).burst(); burst != 3 {
+		t.Errorf("unexpected default burst: %v", burst)
+	}
+	if burst := (RateLimit{Rate: 0.1}).burst(); burst != 1 {
+		t.Errorf("unexpected default burst: %v", burst)
+	}
+}
+
+// rateLimitTestRequest serves a request from the given client IP using the given handler,
+// authenticating with the given secret as bearer token, if defined.
+func rateLimitTestRequest(h http.Handler, path, ip, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	auth := NewPasswordAPIAuthenticator("password")
+	token, err := auth.CreateToken("explorer", []APIScope{APIScopeReadOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl, err := NewRateLimiter(RateLimiterConfig{
+		IP:           RateLimit{Rate: 0.001, Burst: 2},
+		Token:        RateLimit{Rate: 0.001, Burst: 3},
+		ExemptRoutes: []string{"/daemon/status", "/health/*"},
+	}, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := RateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), rl)
+
+	// each client IP has its own limit
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		for i := 0; i < 2; i++ {
+			if rec := rateLimitTestRequest(h, "/consensus", ip, ""); rec.Code != http.StatusOK {
+				t.Fatalf("%s: request #%d is throttled: %d", ip, i+1, rec.Code)
+			}
+		}
+	}
+	rec := rateLimitTestRequest(h, "/consensus", "10.0.0.1", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d exceeding the rate limit, not %d", http.StatusTooManyRequests, rec.Code)
+	}
+	// 1 request per 1000 seconds
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "1000" {
+		t.Errorf("unexpected Retry-After header: %q", retryAfter)
+	}
+
+	// requests using an API token are limited per token, rather than per IP
+	for i := 0; i < 3; i++ {
+		if rec := rateLimitTestRequest(h, "/consensus", "10.0.0.1", token.Secret); rec.Code != http.StatusOK {
+			t.Fatalf("token request #%d is throttled: %d", i+1, rec.Code)
+		}
+	}
+	if rec := rateLimitTestRequest(h, "/consensus", "10.0.0.3", token.Secret); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d exceeding the token rate limit, not %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	// exempt routes are never limited
+	for _, path := range []string{"/daemon/status", "/health/ready"} {
+		if rec := rateLimitTestRequest(h, path, "10.0.0.1", ""); rec.Code != http.StatusOK {
+			t.Errorf("request to exempt route %s is throttled: %d", path, rec.Code)
+		}
+	}
+	// a path merely sharing a prefix with an exempt route isn't exempt
+	if rec := rateLimitTestRequest(h, "/daemon/statusx", "10.0.0.1", ""); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d for a non-exempt route, not %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	stats := rl.Stats()
+	expected := RateLimiterStats{
+		Allowed:        7,
+		Exempt:         2,
+		ThrottledIP:    2,
+		ThrottledToken: 1,
+		TrackedIPs:     2,
+		TrackedTokens:  1,
+	}
+	if stats != expected {
+		t.Errorf("unexpected stats: %+v != %+v", stats, expected)
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	rl, err := NewRateLimiter(RateLimiterConfig{}, nil)
+	if err != nil || rl != nil {
+		t.Errorf("expected no rate limiter when disabled, not: %v (%v)", rl, err)
+	}
+	// a nil rate limiter allows all requests
+	h := RateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), nil)
+	for i := 0; i < 10; i++ {
+		if rec := rateLimitTestRequest(h, "/consensus", "10.0.0.1", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request #%d is throttled without rate limiter: %d", i+1, rec.Code)
+		}
+	}
+	if _, err = NewRateLimiter(RateLimiterConfig{
+		IP:           RateLimit{Rate: 1},
+		ExemptRoutes: []string{"daemon/status"},
+	}, nil); err == nil {
+		t.Error("expected an error for an invalid exempt route")
+	}
+}
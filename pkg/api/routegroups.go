@@ -0,0 +1,180 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/threefoldtech/rivine/build"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DaemonRouteGroup is the route group of the daemon routes (e.g. /daemon/routegroups),
+// which can't be disabled, as the route groups couldn't be enabled again otherwise.
+const DaemonRouteGroup = "daemon"
+
+// RouteGroupStatus is the status of a route group.
+type RouteGroupStatus struct {
+	// Name of the group, which is the first segment of the paths of its routes (e.g. wallet for /wallet/*)
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RouteGroups allows groups of routes to be disabled (and enabled again) at runtime,
+// e.g. disabling the wallet routes during maintenance, without restarting the daemon.
+// Routes are grouped by the first segment of their path, the groups being those of the routes
+// registered in the catalog. All groups, except for the daemon group, can be disabled.
+type RouteGroups struct {
+	catalog *RouteCatalog
+
+	mu       sync.RWMutex
+	disabled map[string]struct{}
+}
+
+// NewRouteGroups creates route groups for the routes registered in the given catalog, all of them enabled.
+func NewRouteGroups(catalog *RouteCatalog) *RouteGroups {
+	return &RouteGroups{
+		catalog:  catalog,
+		disabled: make(map[string]struct{}),
+	}
+}
+
+// Status returns the status of all route groups, sorted by name.
+func (groups *RouteGroups) Status() []RouteGroupStatus {
+	names := groups.names()
+	groups.mu.RLock()
+	defer groups.mu.RUnlock()
+	status := make([]RouteGroupStatus, 0, len(names))
+	for _, name := range names {
+		_, disabled := groups.disabled[name]
+		status = append(status, RouteGroupStatus{
+			Name:    name,
+			Enabled: !disabled,
+		})
+	}
+	return status
+}
+
+// Enabled returns true if the given route group is enabled.
+func (groups *RouteGroups) Enabled(name string) bool {
+	groups.mu.RLock()
+	_, disabled := groups.disabled[name]
+	groups.mu.RUnlock()
+	return !disabled
+}
+
+// Enable enables the given route group.
+func (groups *RouteGroups) Enable(name string) error {
+	if err := groups.validate(name); err != nil {
+		return err
+	}
+	groups.mu.Lock()
+	delete(groups.disabled, name)
+	groups.mu.Unlock()
+	return nil
+}
+
+// Disable disables the given route group, such that all its routes respond with 503 Service Unavailable.
+func (groups *RouteGroups) Disable(name string) error {
+	if err := groups.validate(name); err != nil {
+		return err
+	}
+	if name == DaemonRouteGroup {
+		return fmt.Errorf("route group %q can't be disabled", name)
+	}
+	groups.mu.Lock()
+	groups.disabled[name] = struct{}{}
+	groups.mu.Unlock()
+	return nil
+}
+
+// validate returns an error if the given route group doesn't exist.
+func (groups *RouteGroups) validate(name string) error {
+	for _, group := range groups.names() {
+		if group == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown route group %q", name)
+}
+
+// names returns the sorted names of all route groups.
+func (groups *RouteGroups) names() []string {
+	set := make(map[string]struct{})
+	for _, route := range groups.catalog.Routes() {
+		if segments := splitPath(route.Path); len(segments) != 0 {
+			set[segments[0]] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RouteGroupsHandler is middleware that responds to all requests to the routes of a disabled route group
+// with 503 Service Unavailable. All requests are served as-is in case the route groups are nil.
+func RouteGroupsHandler(h http.Handler, groups *RouteGroups) http.Handler {
+	if groups == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if segments := splitPath(req.URL.Path); len(segments) != 0 && !groups.Enabled(segments[0]) {
+			WriteError(w, Error{Message: fmt.Sprintf("the %s routes are disabled", segments[0])}, http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// DaemonRouteGroupsGET contains the fields returned by a GET call to "/daemon/routegroups".
+type DaemonRouteGroupsGET struct {
+	RouteGroups []RouteGroupStatus `json:"routegroups"`
+}
+
+// RegisterRouteGroupsHTTPHandlers registers the default Rivine handlers for the route group HTTP endpoints,
+// of which enabling and disabling route groups requires the admin scope.
+func RegisterRouteGroupsHTTPHandlers(router Router, groups *RouteGroups, auth *APIAuthenticator) {
+	if groups == nil {
+		build.Critical("no RouteGroups given")
+	}
+	if router == nil {
+		build.Critical("no httprouter Router given")
+	}
+	router.GET("/daemon/routegroups", RequireScopeHandler(NewDaemonRouteGroupsHandler(groups), auth, APIScopeReadOnly))
+	router.POST("/daemon/routegroups/:name/enable", RequireScopeHandler(NewDaemonRouteGroupEnableHandler(groups), auth, APIScopeAdmin))
+	router.POST("/daemon/routegroups/:name/disable", RequireScopeHandler(NewDaemonRouteGroupDisableHandler(groups), auth, APIScopeAdmin))
+}
+
+// NewDaemonRouteGroupsHandler creates a handler to handle API calls to GET /daemon/routegroups.
+func NewDaemonRouteGroupsHandler(groups *RouteGroups) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, DaemonRouteGroupsGET{RouteGroups: groups.Status()})
+	}
+}
+
+// NewDaemonRouteGroupEnableHandler creates a handler to handle API calls to POST /daemon/routegroups/:name/enable.
+func NewDaemonRouteGroupEnableHandler(groups *RouteGroups) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		if err := groups.Enable(ps.ByName("name")); err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		WriteSuccess(w)
+	}
+}
+
+// NewDaemonRouteGroupDisableHandler creates a handler to handle API calls to POST /daemon/routegroups/:name/disable.
+func NewDaemonRouteGroupDisableHandler(groups *RouteGroups) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		if err := groups.Disable(ps.ByName("name")); err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		WriteSuccess(w)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// newRouteGroupsTestHandler creates a handler serving wallet, consensus and route group routes,
+// responding to disabled route groups.
+func newRouteGroupsTestHandler(auth *APIAuthenticator) (http.Handler, *RouteGroups) {
+	router := httprouter.New()
+	catalog := NewRouteCatalog(router)
+	groups := NewRouteGroups(catalog)
+	ok := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteSuccess(w)
+	}
+	catalog.GET("/wallet", ok)
+	catalog.POST("/wallet/lock", ok)
+	catalog.GET("/consensus", ok)
+	RegisterRouteGroupsHTTPHandlers(catalog, groups, auth)
+	return RouteGroupsHandler(router, groups), groups
+}
+
+// routeGroupsTestRequest serves a request authenticated with the given password, if defined.
+func routeGroupsTestRequest(h http.Handler, method, path, password string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if password != "" {
+		req.SetBasicAuth("", password)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouteGroupsHandler(t *testing.T) {
+	h, groups := newRouteGroupsTestHandler(NewPasswordAPIAuthenticator("password"))
+
+	expectStatus := func(method, path string, expected int) {
+		t.Helper()
+		if rec := routeGroupsTestRequest(h, method, path, "password"); rec.Code != expected {
+			t.Errorf("%s %s: expected status %d, not %d", method, path, expected, rec.Code)
+		}
+	}
+	expectGroups := func(expected []RouteGroupStatus) {
+		t.Helper()
+		rec := routeGroupsTestRequest(h, "GET", "/daemon/routegroups", "password")
+		var resp DaemonRouteGroupsGET
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(resp.RouteGroups, expected) {
+			t.Errorf("unexpected route groups: %v != %v", resp.RouteGroups, expected)
+		}
+	}
+
+	expectGroups([]RouteGroupStatus{{"consensus", true}, {"daemon", true}, {"wallet", true}})
+	expectStatus("GET", "/wallet", http.StatusNoContent)
+
+	// all routes of a disabled group are unavailable
+	expectStatus("POST", "/daemon/routegroups/wallet/disable", http.StatusNoContent)
+	expectStatus("GET", "/wallet", http.StatusServiceUnavailable)
+	expectStatus("POST", "/wallet/lock", http.StatusServiceUnavailable)
+	expectStatus("GET", "/consensus", http.StatusNoContent)
+	expectGroups([]RouteGroupStatus{{"consensus", true}, {"daemon", true}, {"wallet", false}})
+	if groups.Enabled("wallet") {
+		t.Error("wallet route group is enabled once disabled")
+	}
+
+	// disabling a group twice is a no-op
+	expectStatus("POST", "/daemon/routegroups/wallet/disable", http.StatusNoContent)
+
+	expectStatus("POST", "/daemon/routegroups/wallet/enable", http.StatusNoContent)
+	expectStatus("GET", "/wallet", http.StatusNoContent)
+	expectGroups([]RouteGroupStatus{{"consensus", true}, {"daemon", true}, {"wallet", true}})
+
+	// all requests are served without route groups
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		WriteSuccess(w)
+	})
+	if rec := routeGroupsTestRequest(RouteGroupsHandler(next, nil), "GET", "/wallet", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d without route groups, not %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestRouteGroupsHandlerErrors(t *testing.T) {
+	h, groups := newRouteGroupsTestHandler(NewPasswordAPIAuthenticator("password"))
+
+	testCases := []struct {
+		name, path, password string
+		status               int
+	}{
+		{"unknown group", "/daemon/routegroups/explorer/disable", "password", http.StatusBadRequest},
+		{"unknown group enabled", "/daemon/routegroups/explorer/enable", "password", http.StatusBadRequest},
+		{"daemon group", "/daemon/routegroups/daemon/disable", "password", http.StatusBadRequest},
+		{"unauthenticated", "/daemon/routegroups/wallet/disable", "", http.StatusUnauthorized},
+		{"invalid password", "/daemon/routegroups/wallet/disable", "invalid", http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		if rec := routeGroupsTestRequest(h, "POST", testCase.path, testCase.password); rec.Code != testCase.status {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, testCase.status, rec.Code)
+		}
+	}
+	for _, status := range groups.Status() {
+		if !status.Enabled {
+			t.Errorf("route group %s is disabled by a failed request", status.Name)
+		}
+	}
+}
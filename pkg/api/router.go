@@ -1,6 +1,12 @@
 package api
 
-import "github.com/julienschmidt/httprouter"
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
 
 // Router represents an http.Handler which can be used to dispatch requests to different
 // handler functions via configurable routes.
@@ -9,3 +15,82 @@ type Router interface {
 	POST(path string, handle httprouter.Handle)
 	OPTIONS(path string, handle httprouter.Handle)
 }
+
+// NewPrefixedRouter returns a Router which registers all routes
+// on the given router, prefixing each path with the given prefix.
+func NewPrefixedRouter(router Router, prefix string) Router {
+	return &prefixedRouter{
+		router: router,
+		prefix: "/" + strings.Trim(prefix, "/"),
+	}
+}
+
+type prefixedRouter struct {
+	router Router
+	prefix string
+}
+
+// GET implements Router.GET
+func (pr *prefixedRouter) GET(path string, handle httprouter.Handle) {
+	pr.router.GET(pr.prefix+path, handle)
+}
+
+// POST implements Router.POST
+func (pr *prefixedRouter) POST(path string, handle httprouter.Handle) {
+	pr.router.POST(pr.prefix+path, handle)
+}
+
+// OPTIONS implements Router.OPTIONS
+func (pr *prefixedRouter) OPTIONS(path string, handle httprouter.Handle) {
+	pr.router.OPTIONS(pr.prefix+path, handle)
+}
+
+// NewBech32Router returns a Router which registers all routes on the given router,
+// such that the addresses given to their handlers can be in the bech32 format of the given prefix
+// (see types.ChainConstants.Bech32AddressPrefix), next to the hex format, see ScanRequestAddress.
+// The given router is returned as-is if the prefix is empty.
+func NewBech32Router(router Router, prefix string) Router {
+	if prefix == "" {
+		return router
+	}
+	return &bech32Router{
+		router: router,
+		prefix: prefix,
+	}
+}
+
+type bech32Router struct {
+	router Router
+	prefix string
+}
+
+// bech32PrefixContextKey is the context key of the bech32 address prefix of a request
+type bech32PrefixContextKey struct{}
+
+// GET implements Router.GET
+func (br *bech32Router) GET(path string, handle httprouter.Handle) {
+	br.router.GET(path, br.wrap(handle))
+}
+
+// POST implements Router.POST
+func (br *bech32Router) POST(path string, handle httprouter.Handle) {
+	br.router.POST(path, br.wrap(handle))
+}
+
+// OPTIONS implements Router.OPTIONS
+func (br *bech32Router) OPTIONS(path string, handle httprouter.Handle) {
+	br.router.OPTIONS(path, br.wrap(handle))
+}
+
+func (br *bech32Router) wrap(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		handle(w, req.WithContext(context.WithValue(req.Context(), bech32PrefixContextKey{}, br.prefix)), ps)
+	}
+}
+
+// requestBech32Prefix returns the bech32 address prefix of the network a request is made for,
+// an empty string if the request was not routed using a router created by NewBech32Router.
+func requestBech32Prefix(ctx context.Context) string {
+	prefix, _ := ctx.Value(bech32PrefixContextKey{}).(string)
+	return prefix
+}
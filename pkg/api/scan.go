@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"math/big"
+	"net/http"
 
 	"github.com/threefoldtech/rivine/crypto"
 	"github.com/threefoldtech/rivine/types"
@@ -27,6 +29,20 @@ func ScanAddress(addrStr string) (addr types.UnlockHash, err error) {
 	return addr, nil
 }
 
+// ScanRequestAddress scans a types.UnlockHash from a string given as part of the request,
+// accepting the bech32 format of the network the request is made for as well, see NewBech32Router.
+func ScanRequestAddress(req *http.Request, addrStr string) (types.UnlockHash, error) {
+	return scanContextAddress(req.Context(), addrStr)
+}
+
+func scanContextAddress(ctx context.Context, addrStr string) (addr types.UnlockHash, err error) {
+	err = addr.LoadAddressString(addrStr, requestBech32Prefix(ctx))
+	if err != nil {
+		return types.UnlockHash{}, err
+	}
+	return addr, nil
+}
+
 // ScanHash scans a crypto.Hash from a string.
 func ScanHash(s string) (h crypto.Hash, err error) {
 	err = h.LoadString(s)
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// MinHashPrefixLength is the minimum length of a hex-encoded hash prefix which can be looked up.
+	MinHashPrefixLength = 4
+
+	// DefaultSearchLimit is the amount of results returned by a search in case no limit is given.
+	DefaultSearchLimit = 10
+	// MaxSearchLimit is the maximum amount of results returned by a search.
+	MaxSearchLimit = 100
+)
+
+type (
+	// ExplorerSearchGET is the object returned by a GET request to /explorer/search,
+	// containing the objects matching the search query.
+	ExplorerSearchGET struct {
+		Query   string                 `json:"query"`
+		Results []ExplorerSearchResult `json:"results"`
+	}
+
+	// ExplorerSearchResult is an object matching a search query,
+	// which can be looked up using its ID at /explorer/hashes/:hash.
+	ExplorerSearchResult struct {
+		// Type is the type of the object, using the same types as the hash lookup
+		Type string `json:"type"`
+		// ID is the (full) block, transaction or output ID, or the address of the object
+		ID string `json:"id"`
+		// Height is the height of the block (containing the transaction), if known
+		Height *types.BlockHeight `json:"height,omitempty"`
+		// Unconfirmed is true for a transaction which is only in the transaction pool
+		Unconfirmed bool `json:"unconfirmed,omitempty"`
+	}
+)
+
+// NewExplorerSearchHandler creates a handler to handle GET requests to /explorer/search,
+// searching for the blocks, transactions, outputs and addresses matching the query given as q parameter.
+// The query can be a block height, an address or (a prefix of) a block, transaction or output ID.
+func NewExplorerSearchHandler(cs modules.ConsensusSet, explorer modules.Explorer, tpool modules.TransactionPool) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		query := strings.TrimSpace(req.FormValue("q"))
+		if query == "" {
+			WriteError(w, Error{Message: "no search query given"}, http.StatusBadRequest)
+			return
+		}
+		limit := DefaultSearchLimit
+		if str := req.FormValue("limit"); str != "" {
+			n, err := strconv.Atoi(str)
+			if err != nil || n <= 0 {
+				WriteError(w, Error{Message: "invalid limit: has to be a positive integer"}, http.StatusBadRequest)
+				return
+			}
+			if n > MaxSearchLimit {
+				n = MaxSearchLimit
+			}
+			limit = n
+		}
+
+		results := []ExplorerSearchResult{}
+		// a decimal query can be both a block height and a hash prefix
+		if n, err := strconv.ParseUint(query, 10, 64); err == nil {
+			height := types.BlockHeight(n)
+			if block, exists := cs.BlockAtHeight(height); exists {
+				results = append(results, ExplorerSearchResult{
+					Type:   HashTypeBlockIDStr,
+					ID:     block.ID().String(),
+					Height: &height,
+				})
+			}
+		}
+		if addr, err := ScanRequestAddress(req, query); err == nil {
+			if len(explorer.UnlockHash(addr)) != 0 || len(explorer.MultiSigAddresses(addr)) != 0 ||
+				len(getUnconfirmedTransactions(explorer, tpool, addr)) != 0 {
+				results = append(results, ExplorerSearchResult{
+					Type: HashTypeUnlockHashStr,
+					ID:   addr.String(),
+				})
+			}
+		} else if len(query) >= MinHashPrefixLength && len(query) <= crypto.HashSize*2 && isHex(query) {
+			matches, err := hashPrefixMatches(explorer, tpool, query, limit-len(results))
+			if err != nil {
+				WriteError(w, Error{Message: "failed to search hash prefix: " + err.Error()}, http.StatusInternalServerError)
+				return
+			}
+			for _, match := range matches {
+				results = append(results, newExplorerSearchResult(explorer, match))
+			}
+		}
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		WriteJSON(w, ExplorerSearchGET{
+			Query:   query,
+			Results: results,
+		})
+	}
+}
+
+// newExplorerSearchResult creates a search result for an object matching a hash prefix,
+// adding the height of the block (containing the transaction) if known.
+func newExplorerSearchResult(explorer modules.Explorer, match hashPrefixMatch) ExplorerSearchResult {
+	result := ExplorerSearchResult{
+		Type:        match.Type,
+		ID:          match.Hash.String(),
+		Unconfirmed: match.unconfirmed,
+	}
+	var (
+		height types.BlockHeight
+		exists bool
+	)
+	switch match.Type {
+	case HashTypeBlockIDStr:
+		_, height, exists = explorer.Block(types.BlockID(match.Hash))
+	case HashTypeTransactionIDStr:
+		if !match.unconfirmed {
+			_, height, exists = explorer.Transaction(types.TransactionID(match.Hash))
+		}
+	}
+	if exists {
+		result.Height = &height
+	}
+	return result
+}
+
+// hashPrefixMatch is an object identified by a hash starting with a searched prefix,
+// which is either indexed by the explorer or an unconfirmed transaction in the transaction pool.
+type hashPrefixMatch struct {
+	modules.HashPrefixMatch
+	unconfirmed bool
+}
+
+// hashPrefixMatches returns the (at most) n objects identified by a hash starting with the given hex-encoded prefix,
+// searching the IDs indexed by the explorer and the unconfirmed transactions in the transaction pool (if available).
+func hashPrefixMatches(explorer modules.Explorer, tpool modules.TransactionPool, prefix string, n int) ([]hashPrefixMatch, error) {
+	explorerMatches, err := explorer.HashesWithPrefix(prefix, n)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]hashPrefixMatch, 0, len(explorerMatches))
+	for _, match := range explorerMatches {
+		matches = append(matches, hashPrefixMatch{HashPrefixMatch: match})
+	}
+	if tpool == nil {
+		return matches, nil
+	}
+	prefix = strings.ToLower(prefix)
+	for _, txn := range tpool.TransactionList() {
+		if len(matches) >= n {
+			break
+		}
+		id := txn.ID()
+		if strings.HasPrefix(id.String(), prefix) {
+			matches = append(matches, hashPrefixMatch{
+				HashPrefixMatch: modules.HashPrefixMatch{
+					Type: HashTypeTransactionIDStr,
+					Hash: crypto.Hash(id),
+				},
+				unconfirmed: true,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// resolveHashPrefix resolves a hex-encoded hash prefix to the object identified by the hash starting with it,
+// returning an error in case the prefix is too short, or matches no or multiple objects.
+func resolveHashPrefix(explorer modules.Explorer, tpool modules.TransactionPool, prefix string) (hashPrefixMatch, error) {
+	if len(prefix) < MinHashPrefixLength {
+		return hashPrefixMatch{}, fmt.Errorf("hash prefix %q is too short: at least %d characters are required", prefix, MinHashPrefixLength)
+	}
+	if !isHex(prefix) {
+		return hashPrefixMatch{}, fmt.Errorf("invalid hash prefix %q: not hex-encoded", prefix)
+	}
+	matches, err := hashPrefixMatches(explorer, tpool, prefix, 2)
+	if err != nil {
+		return hashPrefixMatch{}, err
+	}
+	switch len(matches) {
+	case 0:
+		return hashPrefixMatch{}, fmt.Errorf("no block, transaction or output ID found starting with %q", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return hashPrefixMatch{}, fmt.Errorf("ambiguous hash prefix %q: matches %s %s and %s %s (at least)",
+			prefix, matches[0].Type, matches[0].Hash.String(), matches[1].Type, matches[1].Hash.String())
+	}
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
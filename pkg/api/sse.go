@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// LastEventIDHeader is the header used by server-sent events clients
+// to resume a stream after the last event they received.
+const LastEventIDHeader = "Last-Event-ID"
+
+// NewEventsStreamHandler creates a handler to handle GET requests to "/events/stream",
+// streaming the events of the given modules as server-sent events, for clients which can't use websockets.
+// The stream can be resumed after the last consensus change the client received,
+// using the Last-Event-ID header (or lastEventId query string parameter).
+func NewEventsStreamHandler(cs modules.ConsensusSet, tpool modules.TransactionPool, wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			WriteError(w, Error{Message: "response writer does not support streaming"}, http.StatusInternalServerError)
+			return
+		}
+		stream := newEventStream(cs, tpool, wallet)
+		stream.summarizeBlocks = true
+		subscription, err := parseEventsSubscriptionQuery(req)
+		if err == nil {
+			err = stream.setSubscription(subscription)
+		}
+		if err != nil {
+			WriteError(w, Error{Message: "invalid events subscription: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		lastEventID := req.Header.Get(LastEventIDHeader)
+		if lastEventID == "" {
+			lastEventID = req.FormValue("lastEventId")
+		}
+		if lastEventID != "" {
+			var id crypto.Hash
+			if err := id.LoadString(lastEventID); err != nil {
+				WriteError(w, Error{Message: "invalid last event ID: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			stream.since = modules.ConsensusChangeID(id)
+			// transaction pool changes can't be resumed, instead all transactions
+			// currently in the pool are streamed, as the client might have missed some of them
+			stream.tpoolInitialized = true
+		}
+		stream.sink = &sseEventSink{
+			w:       w,
+			flusher: flusher,
+			closed:  req.Context().Done(),
+		}
+		stream.serve()
+	}
+}
+
+// sseEventSink streams events as server-sent events, the type of the event being the event name
+// and its JSON-encoded data the event data. Every streamed consensus change is identified by its ID,
+// such that the client can resume the stream after the last consensus change it received.
+type sseEventSink struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  <-chan struct{}
+	started bool
+}
+
+func (sse *sseEventSink) write(msg string) error {
+	if !sse.started {
+		header := sse.w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		// prevent reverse proxies (e.g. nginx) from buffering the stream
+		header.Set("X-Accel-Buffering", "no")
+		sse.w.WriteHeader(http.StatusOK)
+		sse.started = true
+	}
+	_, err := io.WriteString(sse.w, msg)
+	if err != nil {
+		return err
+	}
+	sse.flusher.Flush()
+	return nil
+}
+
+func (sse *sseEventSink) writeEvent(event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	return sse.write("event: " + string(event.Type) + "\ndata: " + string(data) + "\n\n")
+}
+
+// endConsensusChange writes a message only containing the ID of the consensus change,
+// which updates the last event ID of the client without dispatching an event.
+func (sse *sseEventSink) endConsensusChange(id modules.ConsensusChangeID) error {
+	return sse.write("id: " + crypto.Hash(id).String() + "\n\n")
+}
+
+func (sse *sseEventSink) ping() error {
+	return sse.write(": ping\n\n")
+}
+
+func (sse *sseEventSink) fail(status int, reason string) {
+	if !sse.started {
+		WriteError(sse.w, Error{Message: reason}, status)
+		return
+	}
+	sse.writeEvent(Event{Type: EventTypeError, Data: Error{Message: reason}})
+}
+
+// messages returns a channel which never receives a message,
+// as server-sent events are unidirectional, and which is closed once the client disconnected.
+func (sse *sseEventSink) messages(done <-chan struct{}) <-chan []byte {
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		select {
+		case <-sse.closed:
+		case <-done:
+		}
+	}()
+	return messages
+}
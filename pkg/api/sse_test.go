@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// sseTestMessage is a single message of a server-sent events stream.
+type sseTestMessage struct {
+	event, data, id string
+}
+
+// readSSEMessage reads the next message of the stream, skipping comments.
+func readSSEMessage(t *testing.T, r *bufio.Reader) sseTestMessage {
+	var msg sseTestMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if msg != (sseTestMessage{}) {
+				return msg
+			}
+		case strings.HasPrefix(line, "event: "):
+			msg.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			msg.data = strings.TrimPrefix(line, "data: ")
+		case strings.HasPrefix(line, "id: "):
+			msg.id = strings.TrimPrefix(line, "id: ")
+		}
+	}
+}
+
+// readSSEBlock reads a block applied event, followed by the ID of its consensus change.
+func readSSEBlock(t *testing.T, r *bufio.Reader, cc modules.ConsensusChange, height types.BlockHeight) {
+	msg := readSSEMessage(t, r)
+	if msg.event != string(EventTypeBlockApplied) {
+		t.Fatalf("expected a %s event, not %q", EventTypeBlockApplied, msg.event)
+	}
+	var summary EventBlockSummary
+	if err := json.Unmarshal([]byte(msg.data), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.ID != cc.AppliedBlocks[0].ID() || summary.Height != height {
+		t.Errorf("unexpected block summary: %v at height %d", summary.ID, summary.Height)
+	}
+	if msg = readSSEMessage(t, r); msg.id != crypto.Hash(cc.ID).String() || msg.event != "" {
+		t.Errorf("expected the consensus change ID, not: %+v", msg)
+	}
+}
+
+func TestEventsStreamHandler(t *testing.T) {
+	cs := newEventsTestConsensusSet()
+	server := newEventsTestServer(cs)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events/stream?topics=consensus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response: %d: %v", resp.StatusCode, resp.Header)
+	}
+	r := bufio.NewReader(resp.Body)
+	if msg := readSSEMessage(t, r); msg.event != string(EventTypeSubscription) {
+		t.Fatalf("expected a %s event, not %q", EventTypeSubscription, msg.event)
+	}
+	var changes []modules.ConsensusChange
+	for i := 0; i < 3; i++ {
+		cc, _ := cs.applyBlock()
+		changes = append(changes, cc)
+		readSSEBlock(t, r, cc, types.BlockHeight(i))
+	}
+
+	// the stream can be resumed after the last event received
+	req, err := http.NewRequest("GET", server.URL+"/events/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(LastEventIDHeader, crypto.Hash(changes[0].ID).String())
+	resumed, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Body.Close()
+	r = bufio.NewReader(resumed.Body)
+	readSSEMessage(t, r)
+	for i, cc := range changes[1:] {
+		readSSEBlock(t, r, cc, types.BlockHeight(i+1))
+	}
+}
+
+func TestEventsStreamHandlerErrors(t *testing.T) {
+	cs := newEventsTestConsensusSet()
+	cs.applyBlock()
+	server := newEventsTestServer(cs)
+	defer server.Close()
+
+	testCases := []struct {
+		query  string
+		status int
+	}{
+		{"topics=wallet", http.StatusBadRequest},
+		{"lastEventId=invalid", http.StatusBadRequest},
+		// a consensus change unknown to the consensus set can't be resumed from
+		{"lastEventId=" + crypto.Hash{1, 2, 3}.String(), http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		resp, err := http.Get(server.URL + "/events/stream?" + testCase.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var apiErr Error
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		resp.Body.Close()
+		if resp.StatusCode != testCase.status || apiErr.Message == "" {
+			t.Errorf("%s: expected status %d with an error, not %d: %q", testCase.query, testCase.status, resp.StatusCode, apiErr.Message)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// syncSampleInterval is the minimum interval between the samples of the consensus height,
+	// used to estimate the rate at which the consensus set syncs
+	syncSampleInterval = 10 * time.Second
+	// syncRateWindow is the window over which the sync rate is estimated
+	syncRateWindow = 10 * time.Minute
+)
+
+type (
+	// DaemonStatusGET contains the fields returned by a GET call to "/daemon/status".
+	DaemonStatusGET struct {
+		// Version is the version of the Rivine daemon software
+		Version build.ProtocolVersion `json:"version"`
+		// Name, NetworkName, ChainVersion and ProtocolVersion identify the blockchain and network of the daemon
+		Name            string                `json:"name"`
+		NetworkName     string                `json:"network"`
+		ChainVersion    build.ProtocolVersion `json:"chainversion"`
+		ProtocolVersion build.ProtocolVersion `json:"protocolversion"`
+
+		// Modules lists the loaded modules
+		Modules []string `json:"modules"`
+		// StartTime is the time the daemon was started at, Uptime the amount of seconds since
+		StartTime types.Timestamp `json:"starttime"`
+		Uptime    uint64          `json:"uptime"`
+
+		// Consensus is the sync status of the consensus set, only defined if the consensus module is loaded
+		Consensus *DaemonConsensusStatus `json:"consensus,omitempty"`
+
+		// RouteGroups lists the route groups of the API and whether or not they are enabled
+		RouteGroups []RouteGroupStatus `json:"routegroups"`
+	}
+
+	// DaemonConsensusStatus is the sync status of the consensus set.
+	DaemonConsensusStatus struct {
+		Synced bool              `json:"synced"`
+		Height types.BlockHeight `json:"height"`
+		// EstimatedHeight is the estimated height of the network, based on the timestamp of the current block
+		EstimatedHeight types.BlockHeight `json:"estimatedheight"`
+		// SyncProgress is the percentage of the estimated network height which is synced
+		SyncProgress float64 `json:"syncprogress"`
+		// SyncRate is the amount of blocks synced per second, over the last minutes
+		SyncRate float64 `json:"syncrate"`
+		// SyncETA is the estimated amount of seconds until synced,
+		// undefined in case the consensus set isn't syncing
+		SyncETA *uint64 `json:"synceta,omitempty"`
+	}
+)
+
+// DaemonStatus reports the status of the daemon, including the sync progress of its consensus set,
+// using the modules registered with its health checker.
+type DaemonStatus struct {
+	info           types.BlockchainInfo
+	blockFrequency types.BlockHeight
+	health         *HealthChecker
+	groups         *RouteGroups
+	started        time.Time
+
+	mu      sync.Mutex
+	samples []syncSample
+}
+
+// syncSample is the height of the consensus set at a point in time.
+type syncSample struct {
+	time   time.Time
+	height types.BlockHeight
+}
+
+// NewDaemonStatus creates the status of a daemon started now, using the given health checker
+// for the loaded modules and the given block frequency to estimate the height of the network.
+func NewDaemonStatus(info types.BlockchainInfo, blockFrequency types.BlockHeight, health *HealthChecker, groups *RouteGroups) *DaemonStatus {
+	return &DaemonStatus{
+		info:           info,
+		blockFrequency: blockFrequency,
+		health:         health,
+		groups:         groups,
+		started:        time.Now(),
+	}
+}
+
+// Status returns the current status of the daemon.
+func (ds *DaemonStatus) Status() DaemonStatusGET {
+	now := time.Now()
+	status := DaemonStatusGET{
+		Version:         build.Version,
+		Name:            ds.info.Name,
+		NetworkName:     ds.info.NetworkName,
+		ChainVersion:    ds.info.ChainVersion,
+		ProtocolVersion: ds.info.ProtocolVersion,
+		Modules:         ds.health.Modules(),
+		StartTime:       types.Timestamp(ds.started.Unix()),
+		Uptime:          uint64(now.Sub(ds.started).Seconds()),
+		RouteGroups:     []RouteGroupStatus{},
+	}
+	if ds.groups != nil {
+		status.RouteGroups = ds.groups.Status()
+	}
+	if cs := ds.health.ConsensusSet(); cs != nil {
+		cstatus := &DaemonConsensusStatus{
+			Synced: cs.Synced(),
+			Height: cs.Height(),
+		}
+		cstatus.EstimatedHeight = cstatus.Height
+		if timestamp := cs.CurrentBlock().Timestamp; types.Timestamp(now.Unix()) > timestamp && ds.blockFrequency > 0 {
+			cstatus.EstimatedHeight += types.BlockHeight(types.Timestamp(now.Unix())-timestamp) / ds.blockFrequency
+		}
+		cstatus.SyncProgress = 100
+		if cstatus.EstimatedHeight > 0 {
+			cstatus.SyncProgress = math.Floor(float64(cstatus.Height)/float64(cstatus.EstimatedHeight)*10000) / 100
+		}
+		cstatus.SyncRate = ds.syncRate(now, cstatus.Height)
+		if behind := cstatus.EstimatedHeight - cstatus.Height; behind == 0 {
+			eta := uint64(0)
+			cstatus.SyncETA = &eta
+		} else if cstatus.SyncRate > 0 {
+			eta := uint64(math.Ceil(float64(behind) / cstatus.SyncRate))
+			cstatus.SyncETA = &eta
+		}
+		status.Consensus = cstatus
+	}
+	return status
+}
+
+// syncRate samples the given height of the consensus set,
+// returning the amount of blocks synced per second within the sync rate window.
+func (ds *DaemonStatus) syncRate(now time.Time, height types.BlockHeight) float64 {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if n := len(ds.samples); n == 0 || now.Sub(ds.samples[n-1].time) >= syncSampleInterval {
+		ds.samples = append(ds.samples, syncSample{time: now, height: height})
+	}
+	// keep the latest sample outside of the window, such that the rate is estimated over the full window
+	for len(ds.samples) > 2 && now.Sub(ds.samples[1].time) >= syncRateWindow {
+		ds.samples = ds.samples[1:]
+	}
+	oldest := ds.samples[0]
+	elapsed := now.Sub(oldest.time).Seconds()
+	if elapsed < syncSampleInterval.Seconds() || height <= oldest.height {
+		return 0
+	}
+	return float64(height-oldest.height) / elapsed
+}
+
+// NewDaemonStatusHandler creates a handler to handle API calls to GET /daemon/status,
+// reporting the status of the daemon.
+func NewDaemonStatusHandler(status *DaemonStatus) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, status.Status())
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// statusTestConsensusSet is a consensus set only implementing the methods used by the daemon status.
+type statusTestConsensusSet struct {
+	modules.ConsensusSet
+	height    types.BlockHeight
+	timestamp types.Timestamp
+}
+
+func (cs *statusTestConsensusSet) Height() types.BlockHeight { return cs.height }
+func (cs *statusTestConsensusSet) Synced() bool              { return false }
+func (cs *statusTestConsensusSet) CurrentBlock() types.Block {
+	return types.Block{Timestamp: cs.timestamp}
+}
+
+// daemonStatusTestRequest requests the status of the daemon, returning the status and decoded response.
+func daemonStatusTestRequest(t *testing.T, ds *DaemonStatus) (int, DaemonStatusGET) {
+	rec := httptest.NewRecorder()
+	NewDaemonStatusHandler(ds)(rec, httptest.NewRequest("GET", "/daemon/status", nil), nil)
+	var resp DaemonStatusGET
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, resp
+}
+
+func TestDaemonStatusHandler(t *testing.T) {
+	info := types.BlockchainInfo{
+		Name:            "rivine",
+		NetworkName:     "testnet",
+		ChainVersion:    build.NewVersion(1, 0, 0, 0),
+		ProtocolVersion: build.NewVersion(1, 0, 7, 0),
+	}
+	hc := NewHealthChecker(HealthConfig{})
+	catalog := NewRouteCatalog(httprouter.New())
+	catalog.GET("/wallet", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {})
+	groups := NewRouteGroups(catalog)
+	ds := NewDaemonStatus(info, 10, hc, groups)
+
+	// the consensus status isn't reported while the consensus set isn't loaded
+	status, resp := daemonStatusTestRequest(t, ds)
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, not %d", http.StatusOK, status)
+	}
+	if resp.Version != build.Version || resp.Name != info.Name || resp.NetworkName != info.NetworkName ||
+		resp.ChainVersion != info.ChainVersion || resp.ProtocolVersion != info.ProtocolVersion {
+		t.Errorf("unexpected versions: %+v", resp)
+	}
+	if resp.Consensus != nil || len(resp.Modules) != 0 {
+		t.Errorf("unexpected status without modules: %+v", resp)
+	}
+	if now := time.Now().Unix(); int64(resp.StartTime) > now || int64(resp.StartTime) < now-5 || resp.Uptime > 5 {
+		t.Errorf("unexpected start time %d and uptime %d", resp.StartTime, resp.Uptime)
+	}
+	if err := groups.Disable("wallet"); err != nil {
+		t.Fatal(err)
+	}
+	if _, resp := daemonStatusTestRequest(t, ds); !reflect.DeepEqual(resp.RouteGroups, []RouteGroupStatus{{"wallet", false}}) {
+		t.Errorf("unexpected route groups: %v", resp.RouteGroups)
+	}
+
+	// the consensus set is 100 blocks behind the estimated network height
+	cs := &statusTestConsensusSet{height: 300, timestamp: types.Timestamp(time.Now().Unix() - 1000)}
+	hc.SetConsensusSet(cs)
+	hc.ModuleLoaded("gateway")
+	_, resp = daemonStatusTestRequest(t, ds)
+	if !reflect.DeepEqual(resp.Modules, []string{"consensus", "gateway"}) {
+		t.Errorf("unexpected modules: %v", resp.Modules)
+	}
+	if resp.Consensus == nil {
+		t.Fatal("consensus status isn't reported")
+	}
+	if c := resp.Consensus; c.Synced || c.Height != 300 || c.EstimatedHeight != 400 || c.SyncProgress != 75 || c.SyncRate != 0 || c.SyncETA != nil {
+		t.Errorf("unexpected consensus status: %+v", c)
+	}
+
+	// no time is left once the estimated network height is reached
+	cs.timestamp = types.Timestamp(time.Now().Unix())
+	_, resp = daemonStatusTestRequest(t, ds)
+	if c := resp.Consensus; c.EstimatedHeight != 300 || c.SyncProgress != 100 || c.SyncETA == nil || *c.SyncETA != 0 {
+		t.Errorf("unexpected synced consensus status: %+v", c)
+	}
+
+	// the status requires read-only credentials, as registered by the daemon
+	rec := httptest.NewRecorder()
+	RequireScopeHandler(NewDaemonStatusHandler(ds), NewPasswordAPIAuthenticator("password"), APIScopeReadOnly)(
+		rec, httptest.NewRequest("GET", "/daemon/status", nil), nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d without credentials, not %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	// route groups are optional
+	if _, resp := daemonStatusTestRequest(t, NewDaemonStatus(info, 10, NewHealthChecker(HealthConfig{}), nil)); resp.RouteGroups == nil || len(resp.RouteGroups) != 0 {
+		t.Errorf("unexpected route groups without route groups: %v", resp.RouteGroups)
+	}
+}
+
+func TestDaemonStatusSyncRate(t *testing.T) {
+	ds := NewDaemonStatus(types.BlockchainInfo{}, 10, NewHealthChecker(HealthConfig{}), nil)
+	start := time.Now()
+
+	testCases := []struct {
+		elapsed  time.Duration
+		height   types.BlockHeight
+		expected float64
+	}{
+		// the rate is unknown until a sample interval has elapsed
+		{0, 100, 0},
+		{time.Second, 110, 0},
+		{syncSampleInterval, 200, 10},
+		{2 * syncSampleInterval, 300, 10},
+		// the rate is estimated over the last window only
+		{syncRateWindow + 2*syncSampleInterval, 300 + 600*15, 15},
+		{syncRateWindow + 3*syncSampleInterval, 300 + 610*15, 15},
+		// the rate is unknown once syncing stopped for a full window
+		{2*syncRateWindow + 4*syncSampleInterval, 300 + 610*15, 0},
+	}
+	for _, testCase := range testCases {
+		if rate := ds.syncRate(start.Add(testCase.elapsed), testCase.height); rate != testCase.expected {
+			t.Errorf("after %v at height %d: expected sync rate %v, not %v", testCase.elapsed, testCase.height, testCase.expected, rate)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header used to identify a request,
+// taken from the request if defined and returned as part of the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of a request ID given by a client
+const maxRequestIDLength = 128
+
+type requestIDContextKey struct{}
+
+// RequestIDHandler is middleware which identifies every request by a request ID,
+// propagating the ID given by the client (as X-Request-ID header) or generating a random one otherwise.
+// The request ID is returned as X-Request-ID header, as part of all errors and can be retrieved using RequestID.
+func RequestIDHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req, _ = withRequestID(w, req)
+		h.ServeHTTP(w, req)
+	})
+}
+
+// RequestID returns the ID of the given request,
+// or an empty string in case the request isn't identified by RequestIDHandler.
+func RequestID(req *http.Request) string {
+	id, _ := req.Context().Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// withRequestID identifies the request by a request ID, unless it is already identified.
+func withRequestID(w http.ResponseWriter, req *http.Request) (*http.Request, string) {
+	if id := RequestID(req); id != "" {
+		return req, id
+	}
+	id := req.Header.Get(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength || !isPrintableASCII(id) {
+		var b [16]byte
+		rand.Read(b[:])
+		id = hex.EncodeToString(b[:])
+	}
+	w.Header().Set(RequestIDHeader, id)
+	return req.WithContext(context.WithValue(req.Context(), requestIDContextKey{}, id)), id
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Tracer starts the spans traced around the handling of API requests by their routes (and thus around the module calls
+// made by the routes), allowing requests to be traced end to end by a distributed tracing system,
+// e.g. by implementing it using an OpenTelemetry tracer.
+type Tracer interface {
+	// StartSpan starts a span with the given name for the given request,
+	// returning the request context containing the span, such that spans started while handling
+	// the request (e.g. for batched calls) are its children. A tracer can continue the trace of the caller
+	// by extracting it from the request headers (e.g. the W3C traceparent header).
+	StartSpan(req *http.Request, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation, started by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key, value string)
+	// End ends the span, given the HTTP status the request was responded to with.
+	End(status int)
+}
+
+// span attributes set by the API on every span
+const (
+	SpanAttributeRequestID = "http.request_id"
+	SpanAttributeMethod    = "http.method"
+	SpanAttributeRoute     = "http.route"
+)
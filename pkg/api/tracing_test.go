@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	var id string
+	h := RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id = RequestID(req)
+		WriteError(w, Error{Message: "failed"}, http.StatusBadRequest)
+	}))
+
+	testCases := []struct {
+		name, given string
+		propagated  bool
+	}{
+		{"given ID", "my-request-1", true},
+		{"no ID", "", false},
+		{"too long ID", strings.Repeat("x", maxRequestIDLength+1), false},
+		{"non-printable ID", "my\x00request", false},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", "/consensus", nil)
+		if testCase.given != "" {
+			req.Header.Set(RequestIDHeader, testCase.given)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if testCase.propagated && id != testCase.given {
+			t.Errorf("%s: expected request ID %q, not %q", testCase.name, testCase.given, id)
+		} else if !testCase.propagated && (len(id) != 32 || id == testCase.given) {
+			t.Errorf("%s: unexpected generated request ID %q", testCase.name, id)
+		}
+		if header := rec.Header().Get(RequestIDHeader); header != id {
+			t.Errorf("%s: unexpected %s header: %q != %q", testCase.name, RequestIDHeader, header, id)
+		}
+		// errors are identified by the request ID, on the server and client side
+		var apiErr Error
+		if err := json.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil || apiErr.RequestID != id {
+			t.Errorf("%s: unexpected error request ID: %q (%v)", testCase.name, apiErr.RequestID, err)
+		}
+		if err, ok := DecodeError(rec.Result()).(Error); !ok || err.RequestID != id {
+			t.Errorf("%s: unexpected decoded error: %#v", testCase.name, err)
+		}
+	}
+
+	// requests are only identified once
+	h = RequestIDHandler(RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id = RequestID(req)
+	})))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/consensus", nil))
+	if rec.Header().Get(RequestIDHeader) != id {
+		t.Errorf("request is identified twice: %q != %q", rec.Header().Get(RequestIDHeader), id)
+	}
+	if id := RequestID(httptest.NewRequest("GET", "/consensus", nil)); id != "" {
+		t.Errorf("unidentified request has request ID %q", id)
+	}
+}
+
+type (
+	// tracingTestTracer records all spans it starts.
+	tracingTestTracer struct {
+		mu    sync.Mutex
+		spans []*tracingTestSpan
+	}
+
+	tracingTestSpan struct {
+		name       string
+		attributes map[string]string
+		status     int
+		ended      bool
+	}
+
+	tracingTestSpanContextKey struct{}
+)
+
+func (tracer *tracingTestTracer) StartSpan(req *http.Request, name string) (context.Context, Span) {
+	span := &tracingTestSpan{name: name, attributes: make(map[string]string)}
+	if parent, ok := req.Context().Value(tracingTestSpanContextKey{}).(*tracingTestSpan); ok {
+		span.attributes["parent"] = parent.name
+	}
+	tracer.mu.Lock()
+	tracer.spans = append(tracer.spans, span)
+	tracer.mu.Unlock()
+	return context.WithValue(req.Context(), tracingTestSpanContextKey{}, span), span
+}
+
+func (span *tracingTestSpan) SetAttribute(key, value string) {
+	span.attributes[key] = value
+}
+
+func (span *tracingTestSpan) End(status int) {
+	span.status = status
+	span.ended = true
+}
+
+func TestRouteCatalogTracing(t *testing.T) {
+	router := httprouter.New()
+	catalog := NewRouteCatalog(router)
+	tracer := new(tracingTestTracer)
+	catalog.SetTracer(tracer)
+	catalog.GET("/explorer/hashes/:hash", func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		if _, ok := req.Context().Value(tracingTestSpanContextKey{}).(*tracingTestSpan); !ok {
+			t.Error("route is not handled using the context of its span")
+		}
+		WriteError(w, Error{Message: "unknown hash"}, http.StatusBadRequest)
+	})
+	catalog.POST("/wallet/lock", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteSuccess(w)
+	})
+	RegisterBatchHTTPHandlers(catalog, router)
+	h := RequestIDHandler(router)
+
+	req := httptest.NewRequest("POST", "/batch", strings.NewReader(`{"requests": [
+		{"method": "GET", "path": "/explorer/hashes/abcd"},
+		{"method": "POST", "path": "/wallet/lock"}
+	]}`))
+	req.Header.Set(RequestIDHeader, "batch-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, not %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp BatchPOSTResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	// errors of batched calls are identified by the ID of the batch
+	var apiErr Error
+	if err := json.Unmarshal(resp.Responses[0].Body, &apiErr); err != nil || apiErr.RequestID != "batch-1" {
+		t.Errorf("unexpected request ID of a batched call error: %q (%v)", apiErr.RequestID, err)
+	}
+
+	// a span is traced around every route, batched calls being children of the batch span
+	expected := []tracingTestSpan{
+		{name: "POST /batch", status: http.StatusOK},
+		{name: "GET /explorer/hashes/:hash", status: http.StatusBadRequest},
+		{name: "POST /wallet/lock", status: http.StatusNoContent},
+	}
+	if len(tracer.spans) != len(expected) {
+		t.Fatalf("expected %d spans, not %d", len(expected), len(tracer.spans))
+	}
+	for i, span := range tracer.spans {
+		if span.name != expected[i].name || span.status != expected[i].status || !span.ended {
+			t.Errorf("unexpected span #%d: %+v", i, span)
+		}
+		parts := strings.SplitN(span.name, " ", 2)
+		if span.attributes[SpanAttributeMethod] != parts[0] || span.attributes[SpanAttributeRoute] != parts[1] ||
+			span.attributes[SpanAttributeRequestID] != "batch-1" {
+			t.Errorf("unexpected attributes of span #%d: %v", i, span.attributes)
+		}
+		if parent := span.attributes["parent"]; (i == 0 && parent != "") || (i > 0 && parent != "POST /batch") {
+			t.Errorf("unexpected parent of span #%d: %q", i, parent)
+		}
+	}
+}
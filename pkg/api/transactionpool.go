@@ -1,11 +1,20 @@
 package api
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	"github.com/threefoldtech/rivine/types"
 
 	"github.com/julienschmidt/httprouter"
@@ -21,11 +30,18 @@ type (
 	// It contains the the ID of the newly posted transaction.
 	TransactionPoolPOST struct {
 		TransactionID types.TransactionID `json:"transactionid"`
+		// DryRun is true in case the transaction was only validated, using the dryrun query parameter
+		DryRun bool `json:"dryrun,omitempty"`
+	}
+
+	// TransactionPoolGetDoubleSpends contains the fields returned by a GET call to "/transactionpool/doublespends".
+	TransactionPoolGetDoubleSpends struct {
+		Proofs []types.DoubleSpendProof `json:"proofs"`
 	}
 )
 
 // RegisterTransactionPoolHTTPHandlers registers the default Rivine handlers for all default Rivine TransactionPool HTTP endpoints.
-func RegisterTransactionPoolHTTPHandlers(router Router, cs modules.ConsensusSet, tpool modules.TransactionPool, requiredPassword string) {
+func RegisterTransactionPoolHTTPHandlers(router Router, cs modules.ConsensusSet, tpool modules.TransactionPool, chainCts types.ChainConstants, auth *APIAuthenticator) {
 	if cs == nil {
 		build.Critical("no consensus set module given")
 	}
@@ -36,8 +52,11 @@ func RegisterTransactionPoolHTTPHandlers(router Router, cs modules.ConsensusSet,
 		build.Critical("no httprouter Router given")
 	}
 	router.GET("/transactionpool/transactions", NewTransactionPoolGetTransactionsHandler(cs, tpool))
-	router.POST("/transactionpool/transactions", RequirePasswordHandler(NewTransactionPoolPostTransactionHandler(tpool), requiredPassword))
-	router.OPTIONS("/transactionpool/transactions", RequirePasswordHandler(NewTransactionPoolOptionsTransactionHandler(), requiredPassword))
+	router.POST("/transactionpool/transactions", RequireScopeHandler(NewTransactionPoolPostTransactionHandler(tpool), auth, APIScopeWalletSpend))
+	router.OPTIONS("/transactionpool/transactions", RequireScopeHandler(NewTransactionPoolOptionsTransactionHandler(), auth, APIScopeReadOnly))
+	router.GET("/transactionpool/fee", NewTransactionPoolFeeHandler(cs, tpool, chainCts))
+	router.GET("/transactionpool/doublespends", NewTransactionPoolGetDoubleSpendsHandler(tpool))
+	router.GET("/transactionpool/doublespends/:id", NewTransactionPoolGetDoubleSpendHandler(tpool))
 }
 
 // NewTransactionPoolGetTransactionsHandler creates a handler
@@ -57,54 +76,17 @@ func NewTransactionPoolGetTransactionsHandler(cs modules.ConsensusSet, tpool mod
 		}
 
 		// parse unlockhash param as an actual UnlockHash
-		var uh types.UnlockHash
-		err := uh.LoadString(str)
+		uh, err := ScanRequestAddress(req, str)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		// filter based on unlock hash
-	txnLoop:
 		for i := 0; i < len(txns); {
-			txn := txns[i]
-			// try to find it either as the condition's unlockhash,
-			// or as an unlockhash-property of a condition,
-			// in other words where the unlockhash is the target
-			for _, co := range txn.CoinOutputs {
-				if isUnlockHashInCondition(uh, co.Condition) {
-					i++
-					continue txnLoop
-				}
-			}
-			for _, bso := range txn.BlockStakeOutputs {
-				if isUnlockHashInCondition(uh, bso.Condition) {
-					i++
-					continue txnLoop
-				}
-			}
-			// try to find it the parent-condition's unlockhash,
-			// or as an unlockhash-property of that parent-condition,
-			// in other words where the unlockhash is the source
-			for _, ci := range txn.CoinInputs {
-				co, err := cs.GetCoinOutput(ci.ParentID)
-				if err != nil {
-					continue
-				}
-				if isUnlockHashInCondition(uh, co.Condition) {
-					i++
-					continue txnLoop
-				}
-			}
-			for _, bsi := range txn.BlockStakeInputs {
-				bso, err := cs.GetBlockStakeOutput(bsi.ParentID)
-				if err != nil {
-					continue
-				}
-				if isUnlockHashInCondition(uh, bso.Condition) {
-					i++
-					continue txnLoop
-				}
+			if isUnlockHashInTransaction(cs, uh, txns[i]) {
+				i++
+				continue
 			}
 			// txn doesn't reference unlock hash
 			txns = append(txns[:i], txns[i+1:]...)
@@ -116,6 +98,46 @@ func NewTransactionPoolGetTransactionsHandler(cs modules.ConsensusSet, tpool mod
 	}
 }
 
+// isUnlockHashInTransaction returns true if the unlock hash is referenced
+// by any output of the transaction, or by the parent output of any of its inputs.
+func isUnlockHashInTransaction(cs modules.ConsensusSet, uh types.UnlockHash, txn types.Transaction) bool {
+	// try to find it either as the condition's unlockhash,
+	// or as an unlockhash-property of a condition,
+	// in other words where the unlockhash is the target
+	for _, co := range txn.CoinOutputs {
+		if isUnlockHashInCondition(uh, co.Condition) {
+			return true
+		}
+	}
+	for _, bso := range txn.BlockStakeOutputs {
+		if isUnlockHashInCondition(uh, bso.Condition) {
+			return true
+		}
+	}
+	// try to find it the parent-condition's unlockhash,
+	// or as an unlockhash-property of that parent-condition,
+	// in other words where the unlockhash is the source
+	for _, ci := range txn.CoinInputs {
+		co, err := cs.GetCoinOutput(ci.ParentID)
+		if err != nil {
+			continue
+		}
+		if isUnlockHashInCondition(uh, co.Condition) {
+			return true
+		}
+	}
+	for _, bsi := range txn.BlockStakeInputs {
+		bso, err := cs.GetBlockStakeOutput(bsi.ParentID)
+		if err != nil {
+			continue
+		}
+		if isUnlockHashInCondition(uh, bso.Condition) {
+			return true
+		}
+	}
+	return false
+}
+
 func isUnlockHashInCondition(uh types.UnlockHash, co types.UnlockConditionProxy) bool {
 	if uh == co.UnlockHash() {
 		return true
@@ -127,30 +149,143 @@ func isUnlockHashInCondition(uh types.UnlockHash, co types.UnlockConditionProxy)
 				return true
 			}
 		}
+	case *types.WeightedMultiSignatureCondition:
+		return tco.Weight(uh) != 0
+	case *types.AnyOfCondition:
+		return isUnlockHashInConditions(uh, tco.Conditions)
+	case *types.AllOfCondition:
+		return isUnlockHashInConditions(uh, tco.Conditions)
 	case *types.NilCondition, nil:
 		return true
 	}
 	return false
 }
 
+func isUnlockHashInConditions(uh types.UnlockHash, conditions []types.UnlockConditionProxy) bool {
+	for _, condition := range conditions {
+		if isUnlockHashInCondition(uh, condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxRawTransactionSize is the maximum size (in bytes) of a binary transaction
+// posted to /transactionpool/transactions, prior to it being validated by the transaction pool.
+const MaxRawTransactionSize = 1 << 20
+
 // NewTransactionPoolPostTransactionHandler creates a handler to handle
-// the API call to post a complete/valid transaction on /transactionpool/transactions
+// the API call to post a complete/valid transaction on /transactionpool/transactions.
+// The transaction is JSON-encoded by default, or binary-encoded (using the encoding given by the encoding
+// query parameter, rivbin by default) in case the Content-Type is application/octet-stream (raw bytes)
+// or text/plain (hex-encoded bytes).
 func NewTransactionPoolPostTransactionHandler(tpool modules.TransactionPool) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-		tx := types.Transaction{}
-
-		if err := json.NewDecoder(req.Body).Decode(&tx); err != nil {
-			WriteError(w, Error{"error decoding the supplied transaction: " + err.Error()}, http.StatusBadRequest)
+		tx, err := decodePostedTransaction(req)
+		if err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied transaction: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if dryRun := req.FormValue("dryrun"); dryRun == "true" || dryRun == "1" {
+			// only validate the transaction, as if it were accepted
+			if err := tpool.ValidateTransactionSet([]types.Transaction{tx}); err != nil {
+				WriteError(w, NewError("error after call to /wallet/transactions: ", err), transactionPoolErrorToHTTPStatus(err))
+				return
+			}
+			WriteJSON(w, TransactionPoolPOST{TransactionID: tx.ID(), DryRun: true})
 			return
 		}
 		if err := tpool.AcceptTransactionSet([]types.Transaction{tx}); err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, transactionPoolErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transactions: ", err), transactionPoolErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, TransactionPoolPOST{TransactionID: tx.ID()})
 	}
 }
 
+// decodePostedTransaction decodes the transaction posted to /transactionpool/transactions,
+// according to the Content-Type of the request.
+func decodePostedTransaction(req *http.Request) (types.Transaction, error) {
+	var tx types.Transaction
+	var mediaType string
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return tx, fmt.Errorf("invalid Content-Type: %v", err)
+		}
+	}
+	var b []byte
+	switch mediaType {
+	case "application/octet-stream", "text/plain":
+		var err error
+		b, err = ioutil.ReadAll(io.LimitReader(req.Body, 2*MaxRawTransactionSize+1))
+		if err != nil {
+			return tx, err
+		}
+		if mediaType == "text/plain" {
+			b, err = hex.DecodeString(string(bytes.TrimSpace(b)))
+			if err != nil {
+				return tx, fmt.Errorf("invalid hex-encoded transaction: %v", err)
+			}
+		}
+		if len(b) > MaxRawTransactionSize {
+			return tx, fmt.Errorf("transaction exceeds the maximum size of %d bytes", MaxRawTransactionSize)
+		}
+	default:
+		err := json.NewDecoder(req.Body).Decode(&tx)
+		return tx, err
+	}
+
+	r := bytes.NewReader(b)
+	var err error
+	switch encoding := req.URL.Query().Get("encoding"); encoding {
+	case "", "rivbin":
+		err = rivbin.NewDecoder(r).Decode(&tx)
+	case "siabin":
+		err = siabin.NewDecoder(r).Decode(&tx)
+	default:
+		return tx, fmt.Errorf("unsupported encoding %q: expected rivbin or siabin", encoding)
+	}
+	if err != nil {
+		return tx, err
+	}
+	if r.Len() != 0 {
+		return tx, errors.New("unexpected trailing bytes after the binary-encoded transaction")
+	}
+	return tx, nil
+}
+
+// NewTransactionPoolGetDoubleSpendsHandler creates a handler
+// to handle the API call to get the proofs of all double spends detected by the transaction pool.
+func NewTransactionPoolGetDoubleSpendsHandler(tpool modules.TransactionPool) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		WriteJSON(w, TransactionPoolGetDoubleSpends{Proofs: tpool.DoubleSpendProofs()})
+	}
+}
+
+// NewTransactionPoolGetDoubleSpendHandler creates a handler
+// to handle the API call to get the proof of the detected double spend of a single output.
+func NewTransactionPoolGetDoubleSpendHandler(tpool modules.TransactionPool) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var id types.OutputID
+		if err := id.LoadString(ps.ByName("id")); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied output ID: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		proof, err := tpool.DoubleSpendProof(id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == modules.ErrDoubleSpendProofNotFound {
+				status = http.StatusNoContent
+			}
+			WriteError(w, Error{Message: err.Error()}, status)
+			return
+		}
+		WriteJSON(w, proof)
+	}
+}
+
 // NewTransactionPoolOptionsTransactionHandler creates a handler to handle OPTIONS calls
 func NewTransactionPoolOptionsTransactionHandler() httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// transactionPoolTestPool is a transaction pool recording the transactions it accepts,
+// rejecting all transactions with its error, if defined.
+type transactionPoolTestPool struct {
+	modules.TransactionPool
+	accepted  []types.Transaction
+	validated []types.Transaction
+	err       error
+}
+
+func (tpool *transactionPoolTestPool) AcceptTransactionSet(txns []types.Transaction) error {
+	if tpool.err != nil {
+		return tpool.err
+	}
+	tpool.accepted = append(tpool.accepted, txns...)
+	return nil
+}
+
+func (tpool *transactionPoolTestPool) ValidateTransactionSet(txns []types.Transaction) error {
+	if tpool.err != nil {
+		return tpool.err
+	}
+	tpool.validated = append(tpool.validated, txns...)
+	return nil
+}
+
+// postTransactionTestRequest posts the given body with the given Content-Type, returning the status and response.
+func postTransactionTestRequest(t *testing.T, tpool modules.TransactionPool, query, contentType string, body []byte) (int, TransactionPoolPOST) {
+	req := httptest.NewRequest("POST", "/transactionpool/transactions"+query, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	NewTransactionPoolPostTransactionHandler(tpool)(rec, req, nil)
+	var resp TransactionPoolPOST
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestTransactionPoolPostTransactionHandler(t *testing.T) {
+	tx := types.Transaction{
+		Version:       types.TransactionVersionOne,
+		ArbitraryData: []byte("rivine"),
+	}
+	jsonTx, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rivbinTx, err := rivbin.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	siabinTx, err := siabin.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name, query, contentType string
+		body                     []byte
+	}{
+		{"JSON", "", "", jsonTx},
+		{"JSON with Content-Type", "", "application/json; charset=utf-8", jsonTx},
+		{"raw rivbin", "", "application/octet-stream", rivbinTx},
+		{"raw siabin", "?encoding=siabin", "application/octet-stream", siabinTx},
+		{"hex rivbin", "?encoding=rivbin", "text/plain", []byte(hex.EncodeToString(rivbinTx) + "\n")},
+		{"hex siabin", "?encoding=siabin", "text/plain; charset=utf-8", []byte(hex.EncodeToString(siabinTx))},
+	}
+	for _, testCase := range testCases {
+		tpool := new(transactionPoolTestPool)
+		status, resp := postTransactionTestRequest(t, tpool, testCase.query, testCase.contentType, testCase.body)
+		if status != http.StatusOK {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, http.StatusOK, status)
+			continue
+		}
+		if resp.TransactionID != tx.ID() || resp.DryRun {
+			t.Errorf("%s: unexpected response: %+v", testCase.name, resp)
+		}
+		if len(tpool.accepted) != 1 || tpool.accepted[0].ID() != tx.ID() {
+			t.Errorf("%s: unexpected accepted transactions: %v", testCase.name, tpool.accepted)
+		}
+	}
+
+	// a transaction is only validated in a dry run
+	tpool := new(transactionPoolTestPool)
+	status, resp := postTransactionTestRequest(t, tpool, "?dryrun=true", "application/octet-stream", rivbinTx)
+	if status != http.StatusOK || !resp.DryRun || len(tpool.accepted) != 0 || len(tpool.validated) != 1 {
+		t.Errorf("unexpected dry run: %d: %+v", status, resp)
+	}
+
+	// the error of the transaction pool is returned
+	tpool.err = types.NewClientError(errors.New("invalid transaction"), types.ClientErrorForbidden)
+	if status, _ := postTransactionTestRequest(t, tpool, "", "application/octet-stream", rivbinTx); status != http.StatusForbidden {
+		t.Errorf("expected status %d for a rejected transaction, not %d", http.StatusForbidden, status)
+	}
+}
+
+func TestTransactionPoolPostTransactionHandlerInvalid(t *testing.T) {
+	rivbinTx, err := rivbin.Marshal(types.Transaction{Version: types.TransactionVersionOne})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name, query, contentType string
+		body                     []byte
+	}{
+		{"invalid JSON", "", "application/json", []byte("{")},
+		{"invalid Content-Type", "", "text/plain; charset", []byte("00")},
+		{"invalid hex", "", "text/plain", []byte("xyz")},
+		{"unsupported encoding", "?encoding=json", "application/octet-stream", rivbinTx},
+		{"truncated transaction", "", "application/octet-stream", rivbinTx[:len(rivbinTx)-1]},
+		{"trailing bytes", "", "application/octet-stream", append(append([]byte{}, rivbinTx...), 0)},
+		{"too large transaction", "", "application/octet-stream", make([]byte, MaxRawTransactionSize+1)},
+	}
+	for _, testCase := range testCases {
+		tpool := new(transactionPoolTestPool)
+		if status, _ := postTransactionTestRequest(t, tpool, testCase.query, testCase.contentType, testCase.body); status != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, http.StatusBadRequest, status)
+		}
+		if len(tpool.accepted) != 0 {
+			t.Errorf("%s: invalid transaction is accepted", testCase.name)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/threefoldtech/rivine/build"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// MaxUnspentOutputsUnlockHashes is the maximum amount of unlock hashes
+// for which the unspent outputs can be requested at once.
+const MaxUnspentOutputsUnlockHashes = 100
+
+type (
+	// ExplorerUnspentOutputsPOST is the body of a POST request to /explorer/unspent.
+	ExplorerUnspentOutputsPOST struct {
+		UnlockHashes []types.UnlockHash `json:"unlockhashes"`
+	}
+
+	// ExplorerUnspentOutputsGET is the object returned by a request to /explorer/unspent,
+	// containing the unspent outputs of all requested unlock hashes.
+	ExplorerUnspentOutputsGET struct {
+		// Height and Timestamp of the current block, at which the lock status of the outputs is evaluated
+		Height    types.BlockHeight `json:"height"`
+		Timestamp types.Timestamp   `json:"timestamp"`
+		// Addresses contains the unspent outputs per unlock hash, in the requested order
+		Addresses []ExplorerAddressUnspentOutputs `json:"addresses"`
+	}
+
+	// ExplorerAddressUnspentOutputs contains the unspent outputs of an unlock hash,
+	// ordered by the height of the block which created them.
+	ExplorerAddressUnspentOutputs struct {
+		UnlockHash        types.UnlockHash                  `json:"unlockhash"`
+		CoinOutputs       []ExplorerUnspentCoinOutput       `json:"coinoutputs"`
+		BlockStakeOutputs []ExplorerUnspentBlockStakeOutput `json:"blockstakeoutputs"`
+	}
+
+	// ExplorerUnspentCoinOutput is an unspent coin output, with the height of the block which created it
+	// and whether or not it is (time or maturity) locked.
+	ExplorerUnspentCoinOutput struct {
+		ID types.CoinOutputID `json:"id"`
+		types.CoinOutput
+		Height types.BlockHeight `json:"height"`
+		Locked bool              `json:"locked"`
+		// MaturityHeight is the height at which a miner payout matures and can be spent
+		MaturityHeight types.BlockHeight `json:"maturityheight,omitempty"`
+	}
+
+	// ExplorerUnspentBlockStakeOutput is an unspent block stake output, with the height of the block which created it
+	// and whether or not it is (time) locked.
+	ExplorerUnspentBlockStakeOutput struct {
+		ID types.BlockStakeOutputID `json:"id"`
+		types.BlockStakeOutput
+		Height types.BlockHeight `json:"height"`
+		Locked bool              `json:"locked"`
+	}
+)
+
+// NewExplorerUnspentOutputsHandler creates a handler to handle GET and POST requests to /explorer/unspent,
+// returning all unspent coin and block stake outputs of the requested unlock hashes in a single response.
+// The unlock hashes are given as a comma-separated unlockhashes parameter (GET),
+// or as an ExplorerUnspentOutputsPOST body (POST).
+func NewExplorerUnspentOutputsHandler(cs modules.ConsensusSet, explorer modules.Explorer) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var uhs []types.UnlockHash
+		if req.Method == http.MethodPost {
+			var body ExplorerUnspentOutputsPOST
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				WriteError(w, Error{Message: "error decoding the supplied unlock hashes: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			uhs = body.UnlockHashes
+		} else {
+			for _, str := range strings.Split(req.FormValue("unlockhashes"), ",") {
+				if str = strings.TrimSpace(str); str == "" {
+					continue
+				}
+				uh, err := ScanRequestAddress(req, str)
+				if err != nil {
+					WriteError(w, Error{Message: fmt.Sprintf("invalid unlock hash %q: %v", str, err)}, http.StatusBadRequest)
+					return
+				}
+				uhs = append(uhs, uh)
+			}
+		}
+		uhs = uniqueUnlockHashes(uhs)
+		if len(uhs) == 0 {
+			WriteError(w, Error{Message: "no unlock hashes given"}, http.StatusBadRequest)
+			return
+		}
+		if len(uhs) > MaxUnspentOutputsUnlockHashes {
+			WriteError(w, Error{Message: fmt.Sprintf("too many unlock hashes: at most %d are allowed", MaxUnspentOutputsUnlockHashes)}, http.StatusBadRequest)
+			return
+		}
+
+		height := cs.Height()
+		block, _ := cs.BlockAtHeight(height)
+		resp := ExplorerUnspentOutputsGET{
+			Height:    height,
+			Timestamp: block.Timestamp,
+			Addresses: make([]ExplorerAddressUnspentOutputs, 0, len(uhs)),
+		}
+		maturityDelay := explorer.Constants().MaturityDelay
+		for _, uh := range uhs {
+			resp.Addresses = append(resp.Addresses, locateUnspentOutputs(cs, explorer, uh, height, block.Timestamp, maturityDelay))
+		}
+		WriteJSON(w, resp)
+	}
+}
+
+// uniqueUnlockHashes returns the given unlock hashes without duplicates, preserving their order.
+func uniqueUnlockHashes(uhs []types.UnlockHash) []types.UnlockHash {
+	seen := make(map[types.UnlockHash]struct{}, len(uhs))
+	unique := uhs[:0]
+	for _, uh := range uhs {
+		if _, ok := seen[uh]; ok {
+			continue
+		}
+		seen[uh] = struct{}{}
+		unique = append(unique, uh)
+	}
+	return unique
+}
+
+// locateUnspentOutputs locates all unspent outputs of the given unlock hash,
+// using the explorer to find the blocks and transactions which created outputs for it,
+// and the consensus set to check which of those outputs are still unspent.
+func locateUnspentOutputs(cs modules.ConsensusSet, explorer modules.Explorer, uh types.UnlockHash, height types.BlockHeight, timestamp types.Timestamp, maturityDelay types.BlockHeight) ExplorerAddressUnspentOutputs {
+	outputs := ExplorerAddressUnspentOutputs{
+		UnlockHash:        uh,
+		CoinOutputs:       []ExplorerUnspentCoinOutput{},
+		BlockStakeOutputs: []ExplorerUnspentBlockStakeOutput{},
+	}
+	seen := make(map[types.TransactionID]struct{})
+	for _, txid := range explorer.UnlockHash(uh) {
+		if _, ok := seen[txid]; ok {
+			continue
+		}
+		seen[txid] = struct{}{}
+		block, blockHeight, exists := explorer.Transaction(txid)
+		if !exists {
+			build.Severe("explorer pointing to nonexistent txn")
+			continue
+		}
+		ctx := types.FulfillableContext{
+			BlockHeight: height,
+			BlockTime:   timestamp,
+			OutputOrigin: &types.OutputOrigin{
+				BlockHeight: blockHeight,
+				BlockTime:   block.Timestamp,
+			},
+		}
+
+		if types.TransactionID(block.ID()) == txid {
+			// the block is indexed for the miner payouts it pays to the unlock hash,
+			// which are only added to the consensus set once matured
+			maturityHeight := blockHeight + maturityDelay
+			for i, mp := range block.MinerPayouts {
+				if mp.UnlockHash != uh {
+					continue
+				}
+				id := block.MinerPayoutID(uint64(i))
+				matured := height >= maturityHeight
+				if matured {
+					if _, err := cs.GetCoinOutput(id); err != nil {
+						continue
+					}
+				}
+				outputs.CoinOutputs = append(outputs.CoinOutputs, ExplorerUnspentCoinOutput{
+					ID: id,
+					CoinOutput: types.CoinOutput{
+						Value:     mp.Value,
+						Condition: types.NewCondition(types.NewUnlockHashCondition(mp.UnlockHash)),
+					},
+					Height:         blockHeight,
+					Locked:         !matured,
+					MaturityHeight: maturityHeight,
+				})
+			}
+			continue
+		}
+
+		for _, txn := range block.Transactions {
+			if txn.ID() != txid {
+				continue
+			}
+			for i, co := range txn.CoinOutputs {
+				if co.Condition.UnlockHash() != uh {
+					continue
+				}
+				id := txn.CoinOutputID(uint64(i))
+				if _, err := cs.GetCoinOutput(id); err != nil {
+					continue
+				}
+				outputs.CoinOutputs = append(outputs.CoinOutputs, ExplorerUnspentCoinOutput{
+					ID:         id,
+					CoinOutput: co,
+					Height:     blockHeight,
+					Locked:     !co.Condition.Fulfillable(ctx),
+				})
+			}
+			for i, bso := range txn.BlockStakeOutputs {
+				if bso.Condition.UnlockHash() != uh {
+					continue
+				}
+				id := txn.BlockStakeOutputID(uint64(i))
+				if _, err := cs.GetBlockStakeOutput(id); err != nil {
+					continue
+				}
+				outputs.BlockStakeOutputs = append(outputs.BlockStakeOutputs, ExplorerUnspentBlockStakeOutput{
+					ID:               id,
+					BlockStakeOutput: bso,
+					Height:           blockHeight,
+					Locked:           !bso.Condition.Fulfillable(ctx),
+				})
+			}
+			break
+		}
+	}
+	sort.SliceStable(outputs.CoinOutputs, func(i, j int) bool {
+		return outputs.CoinOutputs[i].Height < outputs.CoinOutputs[j].Height
+	})
+	sort.SliceStable(outputs.BlockStakeOutputs, func(i, j int) bool {
+		return outputs.BlockStakeOutputs[i].Height < outputs.BlockStakeOutputs[j].Height
+	})
+	return outputs
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// unspentTestConsensusSet extends the pagination test consensus set with its unspent outputs.
+type unspentTestConsensusSet struct {
+	*paginationTestConsensusSet
+	coinOutputs       map[types.CoinOutputID]types.CoinOutput
+	blockStakeOutputs map[types.BlockStakeOutputID]types.BlockStakeOutput
+}
+
+func (cs *unspentTestConsensusSet) GetCoinOutput(id types.CoinOutputID) (types.CoinOutput, error) {
+	co, ok := cs.coinOutputs[id]
+	if !ok {
+		return types.CoinOutput{}, errors.New("coin output not found")
+	}
+	return co, nil
+}
+
+func (cs *unspentTestConsensusSet) GetBlockStakeOutput(id types.BlockStakeOutputID) (types.BlockStakeOutput, error) {
+	bso, ok := cs.blockStakeOutputs[id]
+	if !ok {
+		return types.BlockStakeOutput{}, errors.New("block stake output not found")
+	}
+	return bso, nil
+}
+
+// unspentTestExplorer is an explorer indexing the blocks of its consensus set.
+type unspentTestExplorer struct {
+	modules.Explorer
+	cs           *unspentTestConsensusSet
+	transactions map[types.UnlockHash][]types.TransactionID
+}
+
+func (explorer *unspentTestExplorer) UnlockHash(uh types.UnlockHash) []types.TransactionID {
+	return explorer.transactions[uh]
+}
+
+func (explorer *unspentTestExplorer) Transaction(id types.TransactionID) (types.Block, types.BlockHeight, bool) {
+	for height, block := range explorer.cs.blocks {
+		if types.TransactionID(block.ID()) == id {
+			return block, types.BlockHeight(height), true
+		}
+		for _, txn := range block.Transactions {
+			if txn.ID() == id {
+				return block, types.BlockHeight(height), true
+			}
+		}
+	}
+	return types.Block{}, 0, false
+}
+
+func (explorer *unspentTestExplorer) Constants() modules.DaemonConstants {
+	return modules.DaemonConstants{MaturityDelay: 2}
+}
+
+// unspentTestRequest requests the unspent outputs, returning the status and decoded response.
+func unspentTestRequest(t *testing.T, cs *unspentTestConsensusSet, explorer *unspentTestExplorer, method, query, body string) (int, ExplorerUnspentOutputsGET) {
+	rec := httptest.NewRecorder()
+	NewExplorerUnspentOutputsHandler(cs, explorer)(rec, httptest.NewRequest(method, "/explorer/unspent"+query, strings.NewReader(body)), nil)
+	var resp ExplorerUnspentOutputsGET
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, resp
+}
+
+// newUnspentTestModules creates a consensus set of 4 blocks, with outputs for both given unlock hashes,
+// some of which are spent, locked or not yet matured, indexed by the returned explorer.
+func newUnspentTestModules(uhA, uhB types.UnlockHash) (*unspentTestConsensusSet, *unspentTestExplorer) {
+	cs := &unspentTestConsensusSet{
+		paginationTestConsensusSet: newPaginationTestConsensusSet(4),
+		coinOutputs:                make(map[types.CoinOutputID]types.CoinOutput),
+		blockStakeOutputs:          make(map[types.BlockStakeOutputID]types.BlockStakeOutput),
+	}
+	explorer := &unspentTestExplorer{cs: cs, transactions: make(map[types.UnlockHash][]types.TransactionID)}
+	condition := func(uh types.UnlockHash) types.UnlockConditionProxy {
+		return types.NewCondition(types.NewUnlockHashCondition(uh))
+	}
+
+	// a matured miner payout at height 0 and an immature one at height 3
+	for _, height := range []int{3, 0} {
+		cs.blocks[height].MinerPayouts = []types.MinerPayout{{Value: types.NewCurrency64(uint64(100 + height)), UnlockHash: uhA}}
+		explorer.transactions[uhA] = append(explorer.transactions[uhA], types.TransactionID(cs.blocks[height].ID()))
+	}
+	cs.coinOutputs[cs.blocks[0].MinerPayoutID(0)] = types.CoinOutput{Value: types.NewCurrency64(100), Condition: condition(uhA)}
+
+	// coin outputs at height 2 and 1, of which one is spent and one is time locked
+	txn2 := types.Transaction{
+		Version:     types.TransactionVersionOne,
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(20), Condition: condition(uhA)}},
+	}
+	txn1 := types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinOutputs: []types.CoinOutput{
+			{Value: types.NewCurrency64(10), Condition: condition(uhA)},
+			{Value: types.NewCurrency64(11), Condition: condition(uhA)},
+			{Value: types.NewCurrency64(12), Condition: types.NewCondition(types.NewTimeLockCondition(10, types.NewUnlockHashCondition(uhA)))},
+			{Value: types.NewCurrency64(13), Condition: condition(uhB)},
+		},
+		BlockStakeOutputs: []types.BlockStakeOutput{{Value: types.NewCurrency64(1), Condition: condition(uhA)}},
+	}
+	cs.blocks[2].Transactions = []types.Transaction{txn2}
+	cs.blocks[1].Transactions = []types.Transaction{txn1}
+	for _, txn := range []types.Transaction{txn2, txn1} {
+		for i, co := range txn.CoinOutputs {
+			if co.Value.Equals64(11) {
+				continue // spent
+			}
+			cs.coinOutputs[txn.CoinOutputID(uint64(i))] = co
+		}
+		for i, bso := range txn.BlockStakeOutputs {
+			cs.blockStakeOutputs[txn.BlockStakeOutputID(uint64(i))] = bso
+		}
+		// transactions are indexed once for every output
+		explorer.transactions[uhA] = append(explorer.transactions[uhA], txn.ID(), txn.ID())
+	}
+	explorer.transactions[uhB] = []types.TransactionID{txn1.ID()}
+	return cs, explorer
+}
+
+func TestExplorerUnspentOutputsHandler(t *testing.T) {
+	uhA := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{1})
+	uhB := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{2})
+	cs, explorer := newUnspentTestModules(uhA, uhB)
+	txn1, txn2 := cs.blocks[1].Transactions[0], cs.blocks[2].Transactions[0]
+
+	status, resp := unspentTestRequest(t, cs, explorer, "GET", "?unlockhashes="+uhA.String()+",%20"+uhB.String()+","+uhA.String(), "")
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, not %d", http.StatusOK, status)
+	}
+	if resp.Height != 3 || resp.Timestamp != cs.blocks[3].Timestamp || len(resp.Addresses) != 2 ||
+		resp.Addresses[0].UnlockHash != uhA || resp.Addresses[1].UnlockHash != uhB {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	// the unspent outputs of an unlock hash are ordered by height, spent outputs excluded
+	expected := []struct {
+		id             types.CoinOutputID
+		height         types.BlockHeight
+		locked         bool
+		maturityHeight types.BlockHeight
+	}{
+		{cs.blocks[0].MinerPayoutID(0), 0, false, 2},
+		{txn1.CoinOutputID(0), 1, false, 0},
+		{txn1.CoinOutputID(2), 1, true, 0},
+		{txn2.CoinOutputID(0), 2, false, 0},
+		{cs.blocks[3].MinerPayoutID(0), 3, true, 5},
+	}
+	outputs := resp.Addresses[0]
+	if len(outputs.CoinOutputs) != len(expected) {
+		t.Fatalf("expected %d coin outputs, not %d: %+v", len(expected), len(outputs.CoinOutputs), outputs.CoinOutputs)
+	}
+	for i, co := range outputs.CoinOutputs {
+		if co.ID != expected[i].id || co.Height != expected[i].height || co.Locked != expected[i].locked || co.MaturityHeight != expected[i].maturityHeight {
+			t.Errorf("unexpected coin output #%d: %+v", i, co)
+		}
+	}
+	if len(outputs.BlockStakeOutputs) != 1 || outputs.BlockStakeOutputs[0].ID != txn1.BlockStakeOutputID(0) || outputs.BlockStakeOutputs[0].Locked {
+		t.Errorf("unexpected block stake outputs: %+v", outputs.BlockStakeOutputs)
+	}
+	if outputs := resp.Addresses[1]; len(outputs.CoinOutputs) != 1 || outputs.CoinOutputs[0].ID != txn1.CoinOutputID(3) || len(outputs.BlockStakeOutputs) != 0 {
+		t.Errorf("unexpected outputs of the second unlock hash: %+v", outputs)
+	}
+
+	// the unlock hashes can be posted as well
+	status, resp = unspentTestRequest(t, cs, explorer, "POST", "", `{"unlockhashes": ["`+uhB.String()+`"]}`)
+	if status != http.StatusOK || len(resp.Addresses) != 1 || resp.Addresses[0].UnlockHash != uhB || len(resp.Addresses[0].CoinOutputs) != 1 {
+		t.Errorf("unexpected response to posted unlock hashes: %d: %+v", status, resp)
+	}
+}
+
+func TestExplorerUnspentOutputsHandlerInvalid(t *testing.T) {
+	cs, explorer := newUnspentTestModules(types.UnlockHash{}, types.UnlockHash{})
+
+	uhs := make([]string, 0, MaxUnspentOutputsUnlockHashes+1)
+	for i := 0; i <= MaxUnspentOutputsUnlockHashes; i++ {
+		uhs = append(uhs, types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{byte(i), byte(i >> 8)}).String())
+	}
+	testCases := []struct {
+		name, method, query, body string
+	}{
+		{"no unlock hashes", "GET", "", ""},
+		{"empty unlock hashes", "GET", "?unlockhashes=,", ""},
+		{"invalid unlock hash", "GET", "?unlockhashes=xyz", ""},
+		{"too many unlock hashes", "GET", "?unlockhashes=" + strings.Join(uhs, ","), ""},
+		{"invalid body", "POST", "", "{"},
+		{"no posted unlock hashes", "POST", "", `{"unlockhashes": []}`},
+		{"too many posted unlock hashes", "POST", "", fmt.Sprintf(`{"unlockhashes": ["%s"]}`, strings.Join(uhs, `","`))},
+	}
+	for _, testCase := range testCases {
+		if status, _ := unspentTestRequest(t, cs, explorer, testCase.method, testCase.query, testCase.body); status != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, not %d", testCase.name, http.StatusBadRequest, status)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// APIVersion is the latest version of the versioned API.
+	APIVersion = 2
+	// APIVersionPrefix is the path prefix of all routes of the latest version of the versioned API.
+	APIVersionPrefix = "/v2"
+)
+
+type (
+	// Route is a single route of the API.
+	Route struct {
+		Method string `json:"method"`
+		// Path of the route, path parameters are prefixed with a colon (e.g. `/explorer/hashes/:hash`)
+		Path string `json:"path"`
+	}
+
+	// RoutesGET is the object returned by a GET request to /v2/routes,
+	// listing all routes of the versioned API.
+	RoutesGET struct {
+		Version int     `json:"version"`
+		Routes  []Route `json:"routes"`
+	}
+
+	// Envelope is the object returned by all routes of the versioned API,
+	// containing either the data of a successful response or the error of a failed response.
+	Envelope struct {
+		// Data is the body of a successful response, null for failed responses
+		// or successful responses without content
+		Data json.RawMessage `json:"data"`
+		// Error is the error of a failed response, null for successful responses
+		Error *Error       `json:"error"`
+		Meta  EnvelopeMeta `json:"meta"`
+	}
+
+	// EnvelopeMeta contains the metadata of a versioned API response.
+	EnvelopeMeta struct {
+		Version int `json:"version"`
+		Status  int `json:"status"`
+	}
+)
+
+// RouteCatalog is a Router which keeps track of all routes registered on it,
+// such that they can be listed by the versioned API, logged in the access log and traced.
+type RouteCatalog struct {
+	router Router
+	tracer Tracer
+
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRouteCatalog returns a RouteCatalog which registers all routes on the given router.
+func NewRouteCatalog(router Router) *RouteCatalog {
+	return &RouteCatalog{router: router}
+}
+
+// SetTracer sets the tracer used to trace a span around the handling of every request by its route.
+// It has to be set prior to serving any requests.
+func (rc *RouteCatalog) SetTracer(tracer Tracer) {
+	rc.tracer = tracer
+}
+
+// GET implements Router.GET
+func (rc *RouteCatalog) GET(path string, handle httprouter.Handle) {
+	rc.add(http.MethodGet, path)
+	rc.router.GET(path, rc.handle(path, handle))
+}
+
+// POST implements Router.POST
+func (rc *RouteCatalog) POST(path string, handle httprouter.Handle) {
+	rc.add(http.MethodPost, path)
+	rc.router.POST(path, rc.handle(path, handle))
+}
+
+// OPTIONS implements Router.OPTIONS
+func (rc *RouteCatalog) OPTIONS(path string, handle httprouter.Handle) {
+	rc.add(http.MethodOptions, path)
+	rc.router.OPTIONS(path, rc.handle(path, handle))
+}
+
+// handle records the route handling a request, for the access log,
+// and traces the handling of the request should a tracer be set.
+func (rc *RouteCatalog) handle(path string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		setAccessLogRoute(req, path)
+		if rc.tracer == nil {
+			handle(w, req, ps)
+			return
+		}
+		ctx, span := rc.tracer.StartSpan(req, req.Method+" "+path)
+		span.SetAttribute(SpanAttributeMethod, req.Method)
+		span.SetAttribute(SpanAttributeRoute, path)
+		if id := RequestID(req); id != "" {
+			span.SetAttribute(SpanAttributeRequestID, id)
+		}
+		rec := &statusResponseWriter{ResponseWriter: w}
+		handle(rec, req.WithContext(ctx), ps)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		span.End(rec.status)
+	}
+}
+
+func (rc *RouteCatalog) add(method, path string) {
+	rc.mu.Lock()
+	rc.routes = append(rc.routes, Route{Method: method, Path: path})
+	rc.mu.Unlock()
+}
+
+// Routes returns all registered routes, sorted by path and method.
+func (rc *RouteCatalog) Routes() []Route {
+	rc.mu.RLock()
+	routes := append([]Route(nil), rc.routes...)
+	rc.mu.RUnlock()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// NewVersionedHandler returns a handler which serves all routes registered in the given catalog
+// using the given handler, both unversioned (frozen to their current responses) and
+// prefixed by APIVersionPrefix (returning all responses wrapped in an Envelope).
+// GET /v2/routes lists all routes of the versioned API.
+func NewVersionedHandler(h http.Handler, catalog *RouteCatalog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path := req.URL.Path
+		if path != APIVersionPrefix && !strings.HasPrefix(path, APIVersionPrefix+"/") {
+			h.ServeHTTP(w, req)
+			return
+		}
+		ew := &envelopeResponseWriter{w: w}
+		path = strings.TrimPrefix(path, APIVersionPrefix)
+		if path == "/routes" && req.Method == http.MethodGet {
+			setAccessLogRoute(req, APIVersionPrefix+"/routes")
+			WriteJSON(ew, NewRoutesGET(catalog))
+			ew.finish()
+			return
+		}
+		subReq := req.Clone(req.Context())
+		subReq.URL.Path = path
+		subReq.URL.RawPath = ""
+		h.ServeHTTP(ew, subReq)
+		ew.finish()
+	})
+}
+
+// NewRoutesGET lists all routes of the versioned API, registered in the given catalog.
+func NewRoutesGET(catalog *RouteCatalog) RoutesGET {
+	routes := []Route{{Method: http.MethodGet, Path: APIVersionPrefix + "/routes"}}
+	for _, route := range catalog.Routes() {
+		route.Path = APIVersionPrefix + route.Path
+		routes = append(routes, route)
+	}
+	return RoutesGET{
+		Version: APIVersion,
+		Routes:  routes,
+	}
+}
+
+// envelopeResponseWriter buffers a JSON (or error) response, such that it can be written wrapped in an Envelope.
+// Any other response (e.g. a stream of events) is written as-is.
+type envelopeResponseWriter struct {
+	w           http.ResponseWriter
+	status      int
+	passthrough bool
+	body        bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.Header
+func (ew *envelopeResponseWriter) Header() http.Header {
+	return ew.w.Header()
+}
+
+// WriteHeader implements http.ResponseWriter.WriteHeader
+func (ew *envelopeResponseWriter) WriteHeader(status int) {
+	if ew.status != 0 {
+		return
+	}
+	ew.status = status
+	contentType := ew.Header().Get("Content-Type")
+	// a 304 Not Modified response has no body to wrap
+	if status == http.StatusNotModified ||
+		(status < http.StatusBadRequest && contentType != "" && !strings.HasPrefix(contentType, "application/json")) {
+		ew.passthrough = true
+		ew.w.WriteHeader(status)
+	}
+}
+
+// Write implements http.ResponseWriter.Write
+func (ew *envelopeResponseWriter) Write(b []byte) (int, error) {
+	if ew.status == 0 {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if ew.passthrough {
+		return ew.w.Write(b)
+	}
+	return ew.body.Write(b)
+}
+
+// Flush implements http.Flusher.Flush,
+// only flushing responses which are written as-is.
+func (ew *envelopeResponseWriter) Flush() {
+	if !ew.passthrough {
+		return
+	}
+	if f, ok := ew.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.Hijack
+func (ew *envelopeResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := ew.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	ew.passthrough = true
+	return hijacker.Hijack()
+}
+
+// finish writes the buffered response wrapped in an Envelope.
+func (ew *envelopeResponseWriter) finish() {
+	if ew.passthrough {
+		return
+	}
+	status := ew.status
+	if status == 0 || status == http.StatusNoContent {
+		// an envelope is always returned, even if the response has no content
+		status = http.StatusOK
+	}
+	envelope := Envelope{
+		Meta: EnvelopeMeta{
+			Version: APIVersion,
+			Status:  status,
+		},
+	}
+	header := ew.w.Header()
+	body := bytes.TrimSpace(ew.body.Bytes())
+	if status >= http.StatusBadRequest {
+		var apiErr Error
+		if json.Unmarshal(body, &apiErr) != nil || apiErr.Message == "" {
+			apiErr = Error{Message: string(body)}
+			if apiErr.Message == "" {
+				apiErr.Message = http.StatusText(status)
+			}
+		}
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = header.Get(RequestIDHeader)
+		}
+		envelope.Error = &apiErr
+	} else if len(body) != 0 {
+		if json.Valid(body) {
+			envelope.Data = body
+		} else {
+			envelope.Data, _ = json.Marshal(string(body))
+		}
+	}
+	header.Del("Content-Length")
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	ew.w.WriteHeader(status)
+	json.NewEncoder(ew.w).Encode(envelope)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// newVersioningTestHandler creates a versioned handler serving a couple of routes.
+func newVersioningTestHandler() (http.Handler, *RouteCatalog) {
+	router := httprouter.New()
+	catalog := NewRouteCatalog(router)
+	catalog.GET("/consensus", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteJSON(w, ConsensusGET{Height: 42})
+	})
+	catalog.POST("/wallet/lock", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		WriteSuccess(w)
+	})
+	catalog.GET("/explorer/hashes/:hash", func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		WriteError(w, Error{Message: "unknown hash " + ps.ByName("hash")}, http.StatusBadRequest)
+	})
+	catalog.GET("/daemon/version", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("v1.0.0"))
+	})
+	return NewVersionedHandler(router, catalog), catalog
+}
+
+// versioningTestRequest serves a request, decoding the envelope of the response.
+func versioningTestRequest(t *testing.T, h http.Handler, method, path string) (*httptest.ResponseRecorder, Envelope) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	var envelope Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("%s %s: invalid envelope: %v: %s", method, path, err, rec.Body.String())
+	}
+	return rec, envelope
+}
+
+func TestVersionedHandler(t *testing.T) {
+	h, _ := newVersioningTestHandler()
+
+	// unversioned routes respond as before
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/consensus", nil))
+	var cg ConsensusGET
+	if err := json.NewDecoder(rec.Body).Decode(&cg); err != nil || rec.Code != http.StatusOK || cg.Height != 42 {
+		t.Errorf("unexpected unversioned response: %d: %+v (%v)", rec.Code, cg, err)
+	}
+
+	// versioned routes wrap their responses in an envelope
+	rec, envelope := versioningTestRequest(t, h, "GET", "/v2/consensus")
+	if rec.Code != http.StatusOK || envelope.Error != nil || envelope.Meta != (EnvelopeMeta{Version: APIVersion, Status: http.StatusOK}) {
+		t.Errorf("unexpected envelope: %d: %+v", rec.Code, envelope)
+	}
+	cg = ConsensusGET{}
+	if err := json.Unmarshal(envelope.Data, &cg); err != nil || cg.Height != 42 {
+		t.Errorf("unexpected envelope data: %s (%v)", envelope.Data, err)
+	}
+
+	// responses without content still return an envelope
+	rec, envelope = versioningTestRequest(t, h, "POST", "/v2/wallet/lock")
+	if rec.Code != http.StatusOK || string(envelope.Data) != "null" || envelope.Error != nil || envelope.Meta.Status != http.StatusOK {
+		t.Errorf("unexpected envelope without content: %d: %+v", rec.Code, envelope)
+	}
+
+	// errors, including those not written by the API, are returned as envelope error
+	for _, testCase := range []struct {
+		path    string
+		status  int
+		message string
+	}{
+		{"/v2/explorer/hashes/abcd", http.StatusBadRequest, "unknown hash abcd"},
+		{"/v2/unknown", http.StatusNotFound, "404 page not found"},
+	} {
+		rec, envelope = versioningTestRequest(t, h, "GET", testCase.path)
+		if rec.Code != testCase.status || envelope.Meta.Status != testCase.status || string(envelope.Data) != "null" {
+			t.Errorf("%s: expected status %d, not %d: %+v", testCase.path, testCase.status, rec.Code, envelope)
+		}
+		if envelope.Error == nil || envelope.Error.Message != testCase.message {
+			t.Errorf("%s: unexpected envelope error: %+v", testCase.path, envelope.Error)
+		}
+	}
+
+	// responses which are not JSON are written as-is
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/v2/daemon/version", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "v1.0.0" {
+		t.Errorf("unexpected plain text response: %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestVersionedHandlerRoutes(t *testing.T) {
+	h, catalog := newVersioningTestHandler()
+
+	rec, envelope := versioningTestRequest(t, h, "GET", "/v2/routes")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, not %d", http.StatusOK, rec.Code)
+	}
+	var resp RoutesGET
+	if err := json.Unmarshal(envelope.Data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	expected := RoutesGET{
+		Version: APIVersion,
+		Routes: []Route{
+			{Method: "GET", Path: "/v2/routes"},
+			{Method: "GET", Path: "/v2/consensus"},
+			{Method: "GET", Path: "/v2/daemon/version"},
+			{Method: "GET", Path: "/v2/explorer/hashes/:hash"},
+			{Method: "POST", Path: "/v2/wallet/lock"},
+		},
+	}
+	if !reflect.DeepEqual(resp, expected) {
+		t.Errorf("unexpected routes: %+v", resp)
+	}
+	if len(catalog.Routes()) != 4 {
+		t.Errorf("unexpected catalog routes: %+v", catalog.Routes())
+	}
+
+	// the route catalog is only served by the versioned API
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/routes", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for the unversioned route catalog, not %d", http.StatusNotFound, rec.Code)
+	}
+}
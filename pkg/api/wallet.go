@@ -2,11 +2,13 @@ package api
 
 import (
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/crypto"
@@ -46,6 +48,13 @@ type (
 		BlockStakeUTXOAddress []types.BlockStakeOutputID `json:"blockstakeutxoaddress"`
 	}
 
+	// WalletEarningsGET contains the earnings report of the wallet,
+	// returned by a GET call to /wallet/earnings.
+	WalletEarningsGET struct {
+		Interval string                        `json:"interval"`
+		Entries  []modules.EarningsReportEntry `json:"entries"`
+	}
+
 	// WalletAddressGET contains an address returned by a GET call to
 	// /wallet/address.
 	WalletAddressGET struct {
@@ -89,11 +98,15 @@ type (
 		Data                  []byte             `json:"data,omitempty"`
 		RefundAddress         *types.UnlockHash  `json:"refundaddress,omitempty"`
 		GenerateRefundAddress bool               `json:"genrefundaddress,omitempty"`
+		// DryRun creates and validates the transaction, without submitting it
+		DryRun bool `json:"dryrun,omitempty"`
 	}
 	// WalletCoinsPOSTResp Resp contains the ID of the transaction
 	// that was created as a result of a POST call to /wallet/coins.
+	// The transaction itself is only returned for a dry run.
 	WalletCoinsPOSTResp struct {
 		TransactionID types.TransactionID `json:"transactionid"`
+		Transaction   *types.Transaction  `json:"transaction,omitempty"`
 	}
 
 	// WalletBlockStakesPOST is given by the user
@@ -103,11 +116,15 @@ type (
 		Data                  []byte                   `json:"data,omitempty"`
 		RefundAddress         *types.UnlockHash        `json:"refundaddress,omitempty"`
 		GenerateRefundAddress bool                     `json:"genrefundaddress,omitempty"`
+		// DryRun creates and validates the transaction, without submitting it
+		DryRun bool `json:"dryrun,omitempty"`
 	}
 	// WalletBlockStakesPOSTResp Resp contains the ID of the transaction
 	// that was created as a result of a POST call to /wallet/blockstakes.
+	// The transaction itself is only returned for a dry run.
 	WalletBlockStakesPOSTResp struct {
 		TransactionID types.TransactionID `json:"transactionids"`
+		Transaction   *types.Transaction  `json:"transaction,omitempty"`
 	}
 
 	// WalletSeedsGET contains the seeds used by the wallet.
@@ -132,9 +149,11 @@ type (
 
 	// WalletTransactionsGET contains the specified set of confirmed and
 	// unconfirmed transactions.
+	// The confirmed transactions can be paginated, described by the page info.
 	WalletTransactionsGET struct {
 		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+		PageInfo
 	}
 
 	// WalletTransactionsGETaddr contains the set of wallet transactions
@@ -194,15 +213,67 @@ type (
 		RefundCoinOutput *types.CoinOutput `json:"refund"`
 	}
 
+	// WalletEstimatePOSTResp contains the estimated size of a transaction,
+	// as well as the fee to be paid for it, returned by a POST call to /wallet/estimate.
+	WalletEstimatePOSTResp struct {
+		Size int            `json:"size"`
+		Fee  types.Currency `json:"fee"`
+	}
+
 	// WalletPublicKeyGET contains a public key returned by a GET call to
 	// /wallet/publickey.
 	WalletPublicKeyGET struct {
 		PublicKey types.PublicKey `json:"publickey"`
 	}
+
+	// WalletPaymentChannelsGET contains the payment channels of the wallet,
+	// returned by a GET call to /wallet/paymentchannels.
+	WalletPaymentChannelsGET struct {
+		Channels []modules.PaymentChannel `json:"channels"`
+	}
+
+	// WalletPaymentChannelsPOST is given by the user
+	// to open a payment channel, as the sender, using a POST call to /wallet/paymentchannels.
+	WalletPaymentChannelsPOST struct {
+		Receiver types.UnlockHash `json:"receiver"`
+		Capacity types.Currency   `json:"capacity"`
+		LockTime uint64           `json:"locktime"`
+	}
+
+	// WalletPaymentChannelPOSTResp contains the (opened or updated) payment channel,
+	// returned by a POST call to /wallet/paymentchannels or /wallet/paymentchannels/commitment.
+	WalletPaymentChannelPOSTResp struct {
+		Channel modules.PaymentChannel `json:"channel"`
+	}
+
+	// WalletPaymentChannelCommitmentPOST contains a commitment, received from the sender,
+	// given to the receiver of a payment channel using a POST call to /wallet/paymentchannels/commitment.
+	WalletPaymentChannelCommitmentPOST struct {
+		Commitment types.Transaction `json:"commitment"`
+	}
+
+	// WalletPaymentChannelUpdatePOST is given by the sender of a payment channel,
+	// to pay a new total amount to the receiver using a POST call to /wallet/paymentchannel/:id/update.
+	WalletPaymentChannelUpdatePOST struct {
+		Amount types.Currency `json:"amount"`
+	}
+
+	// WalletPaymentChannelUpdatePOSTResp contains the commitment
+	// returned by a POST call to /wallet/paymentchannel/:id/update,
+	// which is to be given to the receiver of the payment channel.
+	WalletPaymentChannelUpdatePOSTResp struct {
+		Commitment types.Transaction `json:"commitment"`
+	}
+
+	// WalletPaymentChannelClosePOSTResp contains the ID of the transaction
+	// that was created as a result of a POST call to /wallet/paymentchannel/:id/close.
+	WalletPaymentChannelClosePOSTResp struct {
+		TransactionID types.TransactionID `json:"transactionid"`
+	}
 )
 
 // RegisterWalletHTTPHandlers registers the default Rivine handlers for all default Rivine Wallet HTTP endpoints.
-func RegisterWalletHTTPHandlers(router Router, wallet modules.Wallet, requiredPassword string) {
+func RegisterWalletHTTPHandlers(router Router, wallet modules.Wallet, auth *APIAuthenticator) {
 	if wallet == nil {
 		build.Critical("no wallet module given")
 	}
@@ -210,30 +281,37 @@ func RegisterWalletHTTPHandlers(router Router, wallet modules.Wallet, requiredPa
 		build.Critical("no httprouter Router given")
 	}
 
-	router.GET("/wallet", RequirePasswordHandler(NewWalletRootHandler(wallet), requiredPassword))
-	router.GET("/wallet/blockstakestats", RequirePasswordHandler(NewWalletBlockStakeStatsHandler(wallet), requiredPassword))
-	router.GET("/wallet/address", RequirePasswordHandler(NewWalletAddressHandler(wallet), requiredPassword))
-	router.GET("/wallet/addresses", RequirePasswordHandler(NewWalletAddressesHandler(wallet), requiredPassword))
-	router.GET("/wallet/backup", RequirePasswordHandler(NewWalletBackupHandler(wallet), requiredPassword))
-	router.POST("/wallet/init", RequirePasswordHandler(NewWalletInitHandler(wallet), requiredPassword))
-	router.POST("/wallet/lock", RequirePasswordHandler(NewWalletLockHandler(wallet), requiredPassword))
-	router.POST("/wallet/seed", RequirePasswordHandler(NewWalletSeedHandler(wallet), requiredPassword))
-	router.GET("/wallet/seeds", RequirePasswordHandler(NewWalletSeedsHandler(wallet), requiredPassword))
-	router.GET("/wallet/key/:unlockhash", RequirePasswordHandler(NewWalletKeyHandler(wallet), requiredPassword))
-	router.POST("/wallet/transaction", RequirePasswordHandler(NewWalletTransactionCreateHandler(wallet), requiredPassword))
-	router.POST("/wallet/coins", RequirePasswordHandler(NewWalletCoinsHandler(wallet), requiredPassword))
-	router.POST("/wallet/blockstakes", RequirePasswordHandler(NewWalletBlockStakesHandler(wallet), requiredPassword))
-	router.POST("/wallet/data", RequirePasswordHandler(NewWalletDataHandler(wallet), requiredPassword))
+	router.GET("/wallet", RequireScopeHandler(NewWalletRootHandler(wallet), auth, APIScopeReadOnly))
+	router.GET("/wallet/blockstakestats", RequireScopeHandler(NewWalletBlockStakeStatsHandler(wallet), auth, APIScopeReadOnly))
+	router.GET("/wallet/earnings", RequireScopeHandler(NewWalletEarningsHandler(wallet), auth, APIScopeReadOnly))
+	router.GET("/wallet/address", RequireScopeHandler(NewWalletAddressHandler(wallet), auth, APIScopeWalletSpend))
+	router.GET("/wallet/addresses", RequireScopeHandler(NewWalletAddressesHandler(wallet), auth, APIScopeReadOnly))
+	router.GET("/wallet/backup", RequireScopeHandler(NewWalletBackupHandler(wallet), auth, APIScopeAdmin))
+	router.POST("/wallet/init", RequireScopeHandler(NewWalletInitHandler(wallet), auth, APIScopeAdmin))
+	router.POST("/wallet/lock", RequireScopeHandler(NewWalletLockHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/seed", RequireScopeHandler(NewWalletSeedHandler(wallet), auth, APIScopeAdmin))
+	router.GET("/wallet/seeds", RequireScopeHandler(NewWalletSeedsHandler(wallet), auth, APIScopeAdmin))
+	router.GET("/wallet/key/:unlockhash", RequireScopeHandler(NewWalletKeyHandler(wallet), auth, APIScopeAdmin))
+	router.POST("/wallet/transaction", RequireScopeHandler(NewWalletTransactionCreateHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/coins", RequireScopeHandler(NewWalletCoinsHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/blockstakes", RequireScopeHandler(NewWalletBlockStakesHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/data", RequireScopeHandler(NewWalletDataHandler(wallet), auth, APIScopeWalletSpend))
 	router.GET("/wallet/transaction/:id", NewWalletTransactionHandler(wallet))
 	router.GET("/wallet/transactions", NewWalletTransactionsHandler(wallet))
 	router.GET("/wallet/transactions/:addr", NewWalletTransactionsAddrHandler(wallet))
-	router.POST("/wallet/unlock", RequirePasswordHandler(NewWalletUnlockHandler(wallet), requiredPassword))
-	router.GET("/wallet/unlocked", RequirePasswordHandler(NewWalletListUnlockedHandler(wallet), requiredPassword))
-	router.GET("/wallet/locked", RequirePasswordHandler(NewWalletListLockedHandler(wallet), requiredPassword))
-	router.POST("/wallet/create/transaction", RequirePasswordHandler(NewWalletCreateTransactionHandler(wallet), requiredPassword))
-	router.POST("/wallet/sign", RequirePasswordHandler(NewWalletSignHandler(wallet), requiredPassword))
-	router.GET("/wallet/publickey", RequirePasswordHandler(NewWalletGetPublicKeyHandler(wallet), requiredPassword))
-	router.GET("/wallet/fund/coins", RequirePasswordHandler(NewWalletFundCoinsHandler(wallet), requiredPassword))
+	router.POST("/wallet/unlock", RequireScopeHandler(NewWalletUnlockHandler(wallet), auth, APIScopeWalletSpend))
+	router.GET("/wallet/unlocked", RequireScopeHandler(NewWalletListUnlockedHandler(wallet), auth, APIScopeReadOnly))
+	router.GET("/wallet/locked", RequireScopeHandler(NewWalletListLockedHandler(wallet), auth, APIScopeReadOnly))
+	router.POST("/wallet/create/transaction", RequireScopeHandler(NewWalletCreateTransactionHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/sign", RequireScopeHandler(NewWalletSignHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/estimate", NewWalletEstimateHandler(wallet))
+	router.GET("/wallet/publickey", RequireScopeHandler(NewWalletGetPublicKeyHandler(wallet), auth, APIScopeWalletSpend))
+	router.GET("/wallet/fund/coins", RequireScopeHandler(NewWalletFundCoinsHandler(wallet), auth, APIScopeWalletSpend))
+	router.GET("/wallet/paymentchannels", RequireScopeHandler(NewWalletPaymentChannelsHandler(wallet), auth, APIScopeReadOnly))
+	router.POST("/wallet/paymentchannels", RequireScopeHandler(NewWalletPaymentChannelOpenHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/paymentchannels/commitment", RequireScopeHandler(NewWalletPaymentChannelCommitmentHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/paymentchannel/:id/update", RequireScopeHandler(NewWalletPaymentChannelUpdateHandler(wallet), auth, APIScopeWalletSpend))
+	router.POST("/wallet/paymentchannel/:id/close", RequireScopeHandler(NewWalletPaymentChannelCloseHandler(wallet), auth, APIScopeWalletSpend))
 }
 
 // NewWalletRootHandler creates a handler to handle API calls to /wallet.
@@ -241,22 +319,22 @@ func NewWalletRootHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		coinBal, blockstakeBal, err := wallet.ConfirmedBalance()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		coinLockBal, blockstakeLockBal, err := wallet.ConfirmedLockedBalance()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		coinsOut, coinsIn, err := wallet.UnconfirmedBalance()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		multiSigWallets, err := wallet.MultiSigWallets()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 
@@ -277,12 +355,60 @@ func NewWalletRootHandler(wallet modules.Wallet) httprouter.Handle {
 	}
 }
 
+// NewWalletEarningsHandler creates a new handler to handle API calls to /wallet/earnings.
+// The report is aggregated per day by default, and returned as JSON unless the CSV format is requested.
+func NewWalletEarningsHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		interval := modules.EarningsIntervalDay
+		if str := req.FormValue("interval"); str != "" {
+			err := interval.LoadString(str)
+			if err != nil {
+				WriteError(w, Error{Message: "invalid interval given: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+		}
+		entries, err := wallet.EarningsReport(interval)
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/earnings: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		if entries == nil {
+			entries = make([]modules.EarningsReportEntry, 0)
+		}
+
+		switch format := req.FormValue("format"); format {
+		case "", "json":
+			WriteJSON(w, WalletEarningsGET{
+				Interval: interval.String(),
+				Entries:  entries,
+			})
+		case "csv":
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"earnings_%s.csv\"", interval.String()))
+			cw := csv.NewWriter(w)
+			cw.Write([]string{"periodstart", "blockscreated", "blockcreatorfees", "transactionfees", "total"})
+			for _, entry := range entries {
+				cw.Write([]string{
+					time.Unix(int64(entry.PeriodStart), 0).UTC().Format("2006-01-02"),
+					strconv.FormatUint(entry.BlocksCreated, 10),
+					entry.BlockCreatorFees.String(),
+					entry.TransactionFees.String(),
+					entry.Total.String(),
+				})
+			}
+			cw.Flush()
+		default:
+			WriteError(w, Error{Message: fmt.Sprintf("invalid format %q given, expected json or csv", format)}, http.StatusBadRequest)
+		}
+	}
+}
+
 // NewWalletBlockStakeStatsHandler creates a new handler to handle API calls to /wallet/blockstakestat.
 func NewWalletBlockStakeStatsHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		unspentBSOs, err := wallet.GetUnspentBlockStakeOutputs()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/blockstakestat: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/blockstakestat: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		count := len(unspentBSOs)
@@ -295,7 +421,7 @@ func NewWalletBlockStakeStatsHandler(wallet modules.Wallet) httprouter.Handle {
 		num := 0
 		tbclt, bsf, bc, err := wallet.BlockStakeStats()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/blockstakestat: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/blockstakestat: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 
@@ -331,7 +457,7 @@ func NewWalletAddressHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		unlockHash, err := wallet.NextAddress()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/addresses: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/addresses: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletAddressGET{
@@ -345,7 +471,7 @@ func NewWalletAddressesHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		addresses, err := wallet.AllAddresses()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/addresses: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/addresses: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletAddressesGET{Addresses: addresses})
@@ -358,12 +484,12 @@ func NewWalletBackupHandler(wallet modules.Wallet) httprouter.Handle {
 		destination := req.FormValue("destination")
 		// Check that the destination is absolute.
 		if !filepath.IsAbs(destination) {
-			WriteError(w, Error{"error when calling /wallet/backup: destination must be an absolute path"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/backup: destination must be an absolute path"}, http.StatusBadRequest)
 			return
 		}
 		err := wallet.CreateBackup(destination)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/backup: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/backup: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteSuccess(w)
@@ -380,8 +506,7 @@ func NewWalletInitHandler(wallet modules.Wallet) httprouter.Handle {
 		if seedStr != "" {
 			err := seed.LoadString(seedStr)
 			if err != nil {
-				WriteError(w, Error{
-					"error when calling /wallet/init: invalid seed given: " + err.Error()},
+				WriteError(w, Error{Message: "error when calling /wallet/init: invalid seed given: " + err.Error()},
 					http.StatusBadRequest)
 				return
 			}
@@ -391,26 +516,26 @@ func NewWalletInitHandler(wallet modules.Wallet) httprouter.Handle {
 		if passphrase == "" {
 			seed, err = wallet.Init(seed)
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 				return
 			}
 		} else {
 			ph, err := crypto.HashObject(passphrase)
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 				return
 			}
 			encryptionKey := crypto.TwofishKey(ph)
 			seed, err = wallet.Encrypt(encryptionKey, seed)
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 				return
 			}
 		}
 
 		mnemonic, err := modules.NewMnemonic(seed)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteJSON(w, WalletInitPOST{
@@ -427,32 +552,32 @@ func NewWalletSeedHandler(wallet modules.Wallet) httprouter.Handle {
 
 		seed, err := modules.InitialSeedFromMnemonic(menmonic)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		if passphrase == "" {
 			err = wallet.LoadPlainSeed(seed)
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/seed: " +
+				WriteError(w, Error{Message: "error when calling /wallet/seed: " +
 					modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 				return
 			}
 		} else {
 			ph, err := crypto.HashObject(passphrase)
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: "error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
 				return
 			}
 			encryptionKey := crypto.TwofishKey(ph)
 			err = wallet.LoadSeed(encryptionKey, seed)
 			if err == modules.ErrBadEncryptionKey {
-				WriteError(w, Error{"error when calling /wallet/seed: " +
+				WriteError(w, Error{Message: "error when calling /wallet/seed: " +
 					modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 				return
 			}
 			if err != nil {
-				WriteError(w, Error{"error when calling /wallet/seed: " +
+				WriteError(w, Error{Message: "error when calling /wallet/seed: " +
 					err.Error()}, http.StatusBadRequest)
 				return
 			}
@@ -467,7 +592,7 @@ func NewWalletLockHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		err := wallet.Lock()
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		WriteSuccess(w)
@@ -480,26 +605,26 @@ func NewWalletSeedsHandler(wallet modules.Wallet) httprouter.Handle {
 		// Get the primary seed information.
 		primarySeed, progress, err := wallet.PrimarySeed()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/seeds: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/seeds: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		primarySeedStr, err := modules.NewMnemonic(primarySeed)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/seeds: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/seeds: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 
 		// Get the list of seeds known to the wallet.
 		allSeeds, err := wallet.AllSeeds()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/seeds: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/seeds: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		var allSeedsStrs []string
 		for _, seed := range allSeeds {
 			str, err := modules.NewMnemonic(seed)
 			if err != nil {
-				WriteError(w, Error{"error after call to /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+				WriteError(w, Error{Message: "error after call to /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
 				return
 			}
 			allSeedsStrs = append(allSeedsStrs, str)
@@ -516,16 +641,16 @@ func NewWalletSeedsHandler(wallet modules.Wallet) httprouter.Handle {
 func NewWalletKeyHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		strUH := ps.ByName("unlockhash")
-		uh, err := ScanAddress(strUH)
+		uh, err := ScanRequestAddress(req, strUH)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/key/" + strUH + " : " + err.Error()},
+			WriteError(w, NewError("error after call to /wallet/key/"+strUH+" : ", err),
 				http.StatusBadRequest)
 			return
 		}
 
 		pk, sk, err := wallet.GetKey(uh)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/key/" + strUH + " : " + err.Error()},
+			WriteError(w, Error{Message: "error after call to /wallet/key/" + strUH + " : " + err.Error()},
 				walletErrorToHTTPStatus(err))
 			return
 		}
@@ -542,13 +667,13 @@ func NewWalletTransactionCreateHandler(wallet modules.Wallet) httprouter.Handle
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		var body WalletTransactionPOST
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
-			WriteError(w, Error{"error decoding the supplied transaction output: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding the supplied transaction output: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		tx, err := wallet.SendCoins(body.Amount, body.Condition, []byte(body.Data))
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transaction: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transaction: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletTransactionPOSTResponse{
@@ -562,17 +687,25 @@ func NewWalletCoinsHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		var body WalletCoinsPOST
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
-			WriteError(w, Error{"error decoding the supplied coin outputs: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding the supplied coin outputs: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
-		tx, err := wallet.SendOutputs(body.CoinOutputs, nil, body.Data, body.RefundAddress, !body.GenerateRefundAddress)
+		send := wallet.SendOutputs
+		if body.DryRun {
+			send = wallet.DryRunOutputs
+		}
+		tx, err := send(body.CoinOutputs, nil, body.Data, body.RefundAddress, !body.GenerateRefundAddress)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/coins: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/coins: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
-		WriteJSON(w, WalletCoinsPOSTResp{
+		resp := WalletCoinsPOSTResp{
 			TransactionID: tx.ID(),
-		})
+		}
+		if body.DryRun {
+			resp.Transaction = &tx
+		}
+		WriteJSON(w, resp)
 	}
 }
 
@@ -581,32 +714,40 @@ func NewWalletBlockStakesHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		var body WalletBlockStakesPOST
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
-			WriteError(w, Error{"error decoding the supplied blockstake outputs: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding the supplied blockstake outputs: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
-		tx, err := wallet.SendOutputs(nil, body.BlockStakeOutputs, body.Data, body.RefundAddress, !body.GenerateRefundAddress)
+		send := wallet.SendOutputs
+		if body.DryRun {
+			send = wallet.DryRunOutputs
+		}
+		tx, err := send(nil, body.BlockStakeOutputs, body.Data, body.RefundAddress, !body.GenerateRefundAddress)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/blockstakes: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/blockstakes: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
-		WriteJSON(w, WalletBlockStakesPOSTResp{
+		resp := WalletBlockStakesPOSTResp{
 			TransactionID: tx.ID(),
-		})
+		}
+		if body.DryRun {
+			resp.Transaction = &tx
+		}
+		WriteJSON(w, resp)
 	}
 }
 
 // NewWalletDataHandler creates a handler to handle the API calls to /wallet/data
 func NewWalletDataHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-		dest, err := ScanAddress(req.FormValue("destination"))
+		dest, err := ScanRequestAddress(req, req.FormValue("destination"))
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/coins: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, NewError("error after call to /wallet/coins: ", err), http.StatusBadRequest)
 			return
 		}
 		dataString := req.FormValue("data")
 		data, err := base64.StdEncoding.DecodeString(dataString)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/coins: Failed to decode arbitrary data"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error after call to /wallet/coins: Failed to decode arbitrary data"}, http.StatusBadRequest)
 			return
 		}
 		// Since zero outputs are not allowed, just send one of the smallest unit, the minimal amount.
@@ -614,7 +755,7 @@ func NewWalletDataHandler(wallet modules.Wallet) httprouter.Handle {
 		tx, err := wallet.SendCoins(types.NewCurrency64(1),
 			types.NewCondition(types.NewUnlockHashCondition(dest)), data)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/coins: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/coins: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletCoinsPOSTResp{
@@ -630,17 +771,17 @@ func NewWalletTransactionHandler(wallet modules.Wallet) httprouter.Handle {
 		var id types.TransactionID
 		err := id.LoadString(ps.ByName("id"))
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transaction/$(id): " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error after call to /wallet/transaction/$(id): " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		txn, ok, err := wallet.Transaction(id)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/transaction/$(id): " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error when calling /wallet/transaction/$(id): ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		if !ok {
-			WriteError(w, Error{"error when calling /wallet/transaction/$(id): transaction not found"}, http.StatusNotFound)
+			WriteError(w, Error{Message: "error when calling /wallet/transaction/$(id): transaction not found"}, http.StatusNotFound)
 			return
 		}
 		WriteJSON(w, WalletTransactionGETid{
@@ -654,34 +795,49 @@ func NewWalletTransactionsHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		startheightStr, endheightStr := req.FormValue("startheight"), req.FormValue("endheight")
 		if startheightStr == "" || endheightStr == "" {
-			WriteError(w, Error{"startheight and endheight must be provided to a /wallet/transactions call."}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "startheight and endheight must be provided to a /wallet/transactions call."}, http.StatusBadRequest)
 			return
 		}
 		// Get the start and end blocks.
 		start, err := strconv.Atoi(startheightStr)
 		if err != nil {
-			WriteError(w, Error{"parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		end, err := strconv.Atoi(endheightStr)
 		if err != nil {
-			WriteError(w, Error{"parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		// the confirmed transactions are only paginated if requested
+		pagination, err := ParsePagination(req, 0, 0)
+		if err != nil {
+			WriteError(w, Error{Message: "error after call to /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		confirmedTxns, err := wallet.Transactions(types.BlockHeight(start), types.BlockHeight(end))
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transactions: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
+		if pagination.Order == PaginationOrderDescending {
+			for i, j := 0, len(confirmedTxns)-1; i < j; i, j = i+1, j-1 {
+				confirmedTxns[i], confirmedTxns[j] = confirmedTxns[j], confirmedTxns[i]
+			}
+		}
+		total := uint64(len(confirmedTxns))
+		first, last := pagination.Bounds(total)
+		confirmedTxns = confirmedTxns[first:last]
 		unconfirmedTxns, err := wallet.UnconfirmedTransactions()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transactions: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 
 		WriteJSON(w, WalletTransactionsGET{
 			ConfirmedTransactions:   confirmedTxns,
 			UnconfirmedTransactions: unconfirmedTxns,
+			PageInfo:                pagination.PageInfo(total),
 		})
 	}
 }
@@ -694,18 +850,18 @@ func NewWalletTransactionsAddrHandler(wallet modules.Wallet) httprouter.Handle {
 		var addr types.UnlockHash
 		err := addr.UnmarshalJSON([]byte(jsonAddr))
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error after call to /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		confirmedATs, err := wallet.AddressTransactions(addr)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transactions: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		unconfirmedATs, err := wallet.AddressUnconfirmedTransactions(addr)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/transactions: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/transactions: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletTransactionsGETaddr{
@@ -720,13 +876,13 @@ func NewWalletUnlockHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		passphrase := req.FormValue("passphrase")
 		if passphrase == "" {
-			WriteError(w, Error{"error when calling /wallet/unlock: passphrase is required"},
+			WriteError(w, Error{Message: "error when calling /wallet/unlock: passphrase is required"},
 				http.StatusUnauthorized)
 			return
 		}
 		ph, err := crypto.HashObject(passphrase)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/unlock:" + err.Error()},
+			WriteError(w, Error{Message: "error when calling /wallet/unlock:" + err.Error()},
 				http.StatusUnauthorized)
 			return
 		}
@@ -737,11 +893,11 @@ func NewWalletUnlockHandler(wallet modules.Wallet) httprouter.Handle {
 			return
 		}
 		if err != modules.ErrBadEncryptionKey {
-			WriteError(w, Error{"error when calling /wallet/unlock: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/unlock: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
-		WriteError(w, Error{"error when calling /wallet/unlock: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/unlock: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 	}
 }
 
@@ -750,7 +906,7 @@ func NewWalletListUnlockedHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		ucos, ubsos, err := wallet.UnlockedUnspendOutputs()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/unlocked: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/unlocked: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		ucor := []UnspentCoinOutput{}
@@ -776,7 +932,7 @@ func NewWalletListLockedHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		ucos, ubsos, err := wallet.LockedUnspendOutputs()
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/locked: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/locked: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		ucor := []UnspentCoinOutput{}
@@ -802,12 +958,12 @@ func NewWalletCreateTransactionHandler(wallet modules.Wallet) httprouter.Handle
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		var body WalletCreateTransactionPOST
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
-			WriteError(w, Error{"error decoding the supplied inputs and outputs: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding the supplied inputs and outputs: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		tx, err := wallet.CreateRawTransaction(body.CoinInputs, body.BlockStakeInputs, body.CoinOutputs, body.BlockStakeOutputs, nil)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/create/transaction: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/create/transaction: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, WalletCreateTransactionRESP{
@@ -821,18 +977,39 @@ func NewWalletSignHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		var body types.Transaction
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
-			WriteError(w, Error{"error decoding the supplied transaction: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding the supplied transaction: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		txn, err := wallet.GreedySign(body)
 		if err != nil {
-			WriteError(w, Error{"error after call to /wallet/sign: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/sign: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		WriteJSON(w, txn)
 	}
 }
 
+// NewWalletEstimateHandler creates a handler to handle API calls to /wallet/estimate,
+// estimating the size of the given (unsigned) transaction once signed, and the fee to be paid for it.
+func NewWalletEstimateHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body types.Transaction
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied transaction: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		size, fee, err := wallet.EstimateTransactionFee(body)
+		if err != nil {
+			WriteError(w, Error{Message: "error after call to /wallet/estimate: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		WriteJSON(w, WalletEstimatePOSTResp{
+			Size: size,
+			Fee:  fee,
+		})
+	}
+}
+
 // NewWalletFundCoinsHandler creates a handler to handle the API calls to /wallet/fund/coins?amount=.
 // While it might be handy for other use cases, it is needed for 3bot registration
 func NewWalletFundCoinsHandler(wallet modules.Wallet) httprouter.Handle {
@@ -866,7 +1043,7 @@ func NewWalletFundCoinsHandler(wallet modules.Wallet) httprouter.Handle {
 			} else {
 				// try as an address
 				var uh types.UnlockHash
-				err = uh.LoadString(refundStr)
+				uh, err = ScanRequestAddress(req, refundStr)
 				if err != nil {
 					WriteError(w, Error{Message: fmt.Sprintf("refund query param has to be a boolean or unlockhash, %s is invalid", refundStr)}, http.StatusBadRequest)
 					return
@@ -915,7 +1092,7 @@ func NewWalletGetPublicKeyHandler(wallet modules.Wallet) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 		unlockHash, err := wallet.NextAddress()
 		if err != nil {
-			WriteError(w, Error{Message: "error after call to /wallet/publickey: " + err.Error()}, walletErrorToHTTPStatus(err))
+			WriteError(w, NewError("error after call to /wallet/publickey: ", err), walletErrorToHTTPStatus(err))
 			return
 		}
 		pk, _, err := wallet.GetKey(unlockHash)
@@ -927,10 +1104,108 @@ func NewWalletGetPublicKeyHandler(wallet modules.Wallet) httprouter.Handle {
 	}
 }
 
+// NewWalletPaymentChannelsHandler creates a handler to handle API calls to GET /wallet/paymentchannels.
+func NewWalletPaymentChannelsHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		channels, err := wallet.PaymentChannels()
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/paymentchannels: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		WriteJSON(w, WalletPaymentChannelsGET{
+			Channels: channels,
+		})
+	}
+}
+
+// NewWalletPaymentChannelOpenHandler creates a handler to handle API calls to POST /wallet/paymentchannels.
+func NewWalletPaymentChannelOpenHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body WalletPaymentChannelsPOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied payment channel: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		channel, err := wallet.OpenPaymentChannel(body.Receiver, body.Capacity, body.LockTime)
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/paymentchannels: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		WriteJSON(w, WalletPaymentChannelPOSTResp{
+			Channel: channel,
+		})
+	}
+}
+
+// NewWalletPaymentChannelCommitmentHandler creates a handler to handle API calls to POST /wallet/paymentchannels/commitment.
+func NewWalletPaymentChannelCommitmentHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body WalletPaymentChannelCommitmentPOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied commitment: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		channel, err := wallet.AcceptPaymentChannelCommitment(body.Commitment)
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/paymentchannels/commitment: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		WriteJSON(w, WalletPaymentChannelPOSTResp{
+			Channel: channel,
+		})
+	}
+}
+
+// NewWalletPaymentChannelUpdateHandler creates a handler to handle API calls to POST /wallet/paymentchannel/:id/update.
+func NewWalletPaymentChannelUpdateHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var id types.CoinOutputID
+		if err := id.LoadString(ps.ByName("id")); err != nil {
+			WriteError(w, Error{Message: "error after call to /wallet/paymentchannel/$(id)/update: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		var body WalletPaymentChannelUpdatePOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			WriteError(w, Error{Message: "error decoding the supplied payment channel update: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		commitment, err := wallet.UpdatePaymentChannel(id, body.Amount)
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/paymentchannel/$(id)/update: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		WriteJSON(w, WalletPaymentChannelUpdatePOSTResp{
+			Commitment: commitment,
+		})
+	}
+}
+
+// NewWalletPaymentChannelCloseHandler creates a handler to handle API calls to POST /wallet/paymentchannel/:id/close.
+func NewWalletPaymentChannelCloseHandler(wallet modules.Wallet) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var id types.CoinOutputID
+		if err := id.LoadString(ps.ByName("id")); err != nil {
+			WriteError(w, Error{Message: "error after call to /wallet/paymentchannel/$(id)/close: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		txn, err := wallet.ClosePaymentChannel(id)
+		if err != nil {
+			WriteError(w, NewError("error after call to /wallet/paymentchannel/$(id)/close: ", err), walletErrorToHTTPStatus(err))
+			return
+		}
+		WriteJSON(w, WalletPaymentChannelClosePOSTResp{
+			TransactionID: txn.ID(),
+		})
+	}
+}
+
 func walletErrorToHTTPStatus(err error) int {
 	if err == modules.ErrLockedWallet {
 		return http.StatusForbidden
 	}
+	if err == modules.ErrUnknownPaymentChannel {
+		return http.StatusNotFound
+	}
 	if cErr, ok := err.(types.ClientError); ok {
 		return cErr.Kind.AsHTTPStatusCode()
 	}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minimal server-side implementation of the WebSocket protocol (RFC 6455),
+// sufficient to stream JSON messages to clients and receive (small) JSON messages from them.
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	websocketOpContinuation = 0x0
+	websocketOpText         = 0x1
+	websocketOpBinary       = 0x2
+	websocketOpClose        = 0x8
+	websocketOpPing         = 0x9
+	websocketOpPong         = 0xA
+
+	websocketCloseNormal          = 1000
+	websocketCloseProtocolError   = 1002
+	websocketClosePolicyViolation = 1008
+	websocketCloseMessageTooBig   = 1009
+
+	// websocketMaxMessageSize is the max size of a message received from a client
+	websocketMaxMessageSize = 64 * 1024
+	// websocketWriteTimeout is the max duration a single frame write can take
+	websocketWriteTimeout = 10 * time.Second
+)
+
+var (
+	errWebsocketClosed          = errors.New("websocket connection closed")
+	errWebsocketMessageTooLarge = errors.New("websocket message exceeds max size")
+	errWebsocketUnmaskedFrame   = errors.New("websocket client frame is not masked")
+)
+
+// websocketConn is a server-side WebSocket connection.
+// Reads have to happen from a single goroutine,
+// writes can happen from any goroutine.
+type websocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu     sync.Mutex // guards writes
+	closed bool
+}
+
+// upgradeWebsocket upgrades the HTTP request to a WebSocket connection.
+// An error is written to the client and returned in case the request isn't a valid upgrade request.
+func upgradeWebsocket(w http.ResponseWriter, req *http.Request) (*websocketConn, error) {
+	if req.Method != http.MethodGet {
+		err := errors.New("websocket upgrade requires a GET request")
+		WriteError(w, Error{Message: err.Error()}, http.StatusMethodNotAllowed)
+		return nil, err
+	}
+	if !headerContainsToken(req.Header, "Connection", "upgrade") || !headerContainsToken(req.Header, "Upgrade", "websocket") {
+		err := errors.New("websocket upgrade headers are missing")
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return nil, err
+	}
+	if req.Header.Get("Sec-Websocket-Version") != "13" {
+		err := errors.New("unsupported websocket version, only version 13 is supported")
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		WriteError(w, Error{Message: err.Error()}, http.StatusUpgradeRequired)
+		return nil, err
+	}
+	key := req.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		err := errors.New("websocket key is missing")
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return nil, err
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		err := errors.New("websocket upgrade is not supported by the HTTP server")
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return nil, err
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack HTTP connection: %v", err)
+	}
+	// clear any deadlines set by the HTTP server, as this connection is long-lived
+	conn.SetDeadline(time.Time{})
+	_, err = fmt.Fprintf(rw,
+		"HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		websocketAcceptKey(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write websocket handshake: %v", err)
+	}
+	return &websocketConn{
+		conn:   conn,
+		reader: rw.Reader,
+	}, nil
+}
+
+func websocketAcceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+websocketGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage reads the next (text or binary) data message from the client.
+// Control frames are handled transparently. errWebsocketClosed is returned
+// once the client closed the connection.
+func (wc *websocketConn) ReadMessage() ([]byte, error) {
+	var (
+		message    []byte
+		fragmented bool
+	)
+	for {
+		fin, opcode, payload, err := wc.readFrame()
+		if err != nil {
+			if err == errWebsocketMessageTooLarge {
+				wc.CloseWithReason(websocketCloseMessageTooBig, err.Error())
+			} else if err == errWebsocketUnmaskedFrame {
+				wc.CloseWithReason(websocketCloseProtocolError, err.Error())
+			}
+			return nil, err
+		}
+		switch opcode {
+		case websocketOpPing:
+			if err = wc.writeFrame(websocketOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case websocketOpPong:
+			continue
+		case websocketOpClose:
+			wc.CloseWithReason(websocketCloseNormal, "")
+			return nil, errWebsocketClosed
+		case websocketOpText, websocketOpBinary:
+			if fragmented {
+				wc.CloseWithReason(websocketCloseProtocolError, "expected continuation frame")
+				return nil, errors.New("websocket client sent a new message before finishing the previous one")
+			}
+			message = payload
+		case websocketOpContinuation:
+			if !fragmented {
+				wc.CloseWithReason(websocketCloseProtocolError, "unexpected continuation frame")
+				return nil, errors.New("websocket client sent an unexpected continuation frame")
+			}
+			if len(message)+len(payload) > websocketMaxMessageSize {
+				wc.CloseWithReason(websocketCloseMessageTooBig, errWebsocketMessageTooLarge.Error())
+				return nil, errWebsocketMessageTooLarge
+			}
+			message = append(message, payload...)
+		default:
+			wc.CloseWithReason(websocketCloseProtocolError, "unknown opcode")
+			return nil, fmt.Errorf("websocket client sent a frame with unknown opcode %d", opcode)
+		}
+		if fin {
+			return message, nil
+		}
+		fragmented = true
+	}
+}
+
+// readFrame reads a single (masked) frame from the client, returning its unmasked payload.
+func (wc *websocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(wc.reader, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[1]&0x80 == 0 {
+		err = errWebsocketUnmaskedFrame
+		return
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(wc.reader, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(wc.reader, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > websocketMaxMessageSize {
+		err = errWebsocketMessageTooLarge
+		return
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(wc.reader, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(wc.reader, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// WriteJSON writes the given object as a JSON-encoded text message.
+func (wc *websocketConn) WriteJSON(obj interface{}) error {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return wc.writeFrame(websocketOpText, b)
+}
+
+// Ping sends a ping control frame to the client.
+func (wc *websocketConn) Ping() error {
+	return wc.writeFrame(websocketOpPing, nil)
+}
+
+// writeFrame writes a single (final) frame to the client.
+func (wc *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if wc.closed {
+		return errWebsocketClosed
+	}
+	return wc.writeFrameLocked(opcode, payload)
+}
+
+func (wc *websocketConn) writeFrameLocked(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch length := len(payload); {
+	case length < 126:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+	frame = append(frame, payload...)
+	wc.conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+	_, err := wc.conn.Write(frame)
+	return err
+}
+
+// CloseWithReason sends a close frame with the given status code and reason
+// to the client, after which the underlying connection is closed.
+func (wc *websocketConn) CloseWithReason(code uint16, reason string) error {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if wc.closed {
+		return nil
+	}
+	wc.closed = true
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	wc.writeFrameLocked(websocketOpClose, payload) // ignore error, as we close the connection anyhow
+	return wc.conn.Close()
+}
+
+// Close closes the connection normally.
+func (wc *websocketConn) Close() error {
+	return wc.CloseWithReason(websocketCloseNormal, "")
+}
@@ -1,47 +1,188 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+
+	"github.com/threefoldtech/rivine/types"
 )
 
 // exit codes
 // inspired by sysexits.h
+//
+// The exit codes are stable, such that scripts can branch on the cause of a failure,
+// new exit codes are only ever appended.
+const (
+	ExitCodeGeneral             = 1 // Not in sysexits.h, but is standard practice.
+	ExitCodeNotFound            = 2
+	ExitCodeCancelled           = 3
+	ExitCodeForbidden           = 4
+	ExitCodeTemporaryError      = 5
+	ExitCodeNetworkError        = 6  // the daemon (or explorer) could not be reached
+	ExitCodeRejectedTransaction = 7  // a transaction was rejected as invalid
+	ExitCodeWalletLocked        = 8  // the wallet has to be unlocked first
+	ExitCodeUsage               = 64 // EX_USAGE in sysexits.h
+)
+
+// ErrorFormat defines the format in which the Die functions print errors.
+type ErrorFormat uint8
+
 const (
-	ExitCodeGeneral        = 1 // Not in sysexits.h, but is standard practice.
-	ExitCodeNotFound       = 2
-	ExitCodeCancelled      = 3
-	ExitCodeForbidden      = 4
-	ExitCodeTemporaryError = 5
-	ExitCodeUsage          = 64 // EX_USAGE in sysexits.h
+	// ErrorFormatText prints errors as human-readable text, the default.
+	ErrorFormatText ErrorFormat = iota
+	// ErrorFormatJSON prints errors as a single-line JSON object (see ErrorOutput),
+	// which format is promised to be backwards-compatible, to be used for automation purposes.
+	ErrorFormatJSON
 )
 
-// Die prints its arguments to stderr, then exits the program with the default
-// error code.
+// errorFormat is the format in which the Die functions print errors
+var errorFormat = ErrorFormatText
+
+// SetErrorFormat sets the format in which the Die functions print errors.
+func SetErrorFormat(format ErrorFormat) {
+	errorFormat = format
+}
+
+// ErrorFormatFlag is a flag which can be used to expose the error format,
+// used by the Die functions, as a (global) flag.
+type ErrorFormatFlag struct{}
+
+// String implements pflag.Value.String
+func (ErrorFormatFlag) String() string {
+	if errorFormat == ErrorFormatJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// Set implements pflag.Value.Set
+func (ErrorFormatFlag) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "text":
+		errorFormat = ErrorFormatText
+	case "json":
+		errorFormat = ErrorFormatJSON
+	default:
+		return fmt.Errorf("%q is not a valid ErrorFormat", s)
+	}
+	return nil
+}
+
+// Type implements pflag.Value.Type
+func (ErrorFormatFlag) Type() string {
+	return "ErrorFormat"
+}
+
+// ErrorFormatFlagDescription returns the description of an error format flag.
+func ErrorFormatFlagDescription() string {
+	return "enum flag to define how to print errors, options: text|json"
+}
+
+// ErrorOutput is the structured error printed by the Die functions,
+// in case the JSON error format is used.
+type ErrorOutput struct {
+	// ExitCode is the exit code the program exits with
+	ExitCode int `json:"exitcode"`
+	// Message describes the error in English
+	Message string `json:"message"`
+	// Code is the stable code of the (validation) error which caused the failure,
+	// omitted if not known
+	Code types.ErrorCode `json:"code,omitempty"`
+	// HTTPStatus is the status code of the failed API call which caused the failure,
+	// omitted if not caused by a failed API call
+	HTTPStatus int `json:"httpstatus,omitempty"`
+}
+
+// Die prints its arguments to stderr, then exits the program with the
+// exit code of the first error given as argument, see ExitCodeOf,
+// or the default error code if no error is given.
 func Die(args ...interface{}) {
-	DieWithExitCode(ExitCodeGeneral, args...)
+	code := ExitCodeGeneral
+	if err := firstError(args); err != nil {
+		code = ExitCodeOf(err)
+	}
+	DieWithExitCode(code, args...)
 }
 
-// DieWithError exits with an error.
-// if the error is of the type api.ErrorWithStatusCode, its status will be used,
-// otherwise the General exit code will be used
+// DieWithError exits with an error,
+// using the exit code of the error, see ExitCodeOf.
 func DieWithError(description string, err error) {
-	if httpStatusCodeErr, ok := err.(interface{ HTTPStatusCode() int }); ok {
-		switch httpStatusCodeErr.HTTPStatusCode() {
-		case http.StatusUnauthorized:
-			DieWithExitCode(ExitCodeForbidden, description, err)
-			return
-		default:
-			DieWithExitCode(ExitCodeGeneral, description, err)
-		}
-	}
-	DieWithExitCode(ExitCodeGeneral, description, err)
+	DieWithExitCode(ExitCodeOf(err), description, err)
 }
 
 // DieWithExitCode prints its arguments to stderr,
 // then exits the program with the given exit code.
 func DieWithExitCode(code int, args ...interface{}) {
-	fmt.Fprintln(os.Stderr, args...)
+	if errorFormat == ErrorFormatJSON {
+		json.NewEncoder(os.Stderr).Encode(newErrorOutput(code, args))
+	} else {
+		fmt.Fprintln(os.Stderr, args...)
+	}
 	os.Exit(code)
 }
+
+// ExitCodeOf returns the exit code for the given error:
+//   - ExitCodeNetworkError in case of a network error;
+//   - ExitCodeWalletLocked in case the wallet has to be unlocked first;
+//   - ExitCodeRejectedTransaction in case of a transaction validation error;
+//   - the exit code matching the HTTP status of a failed API call;
+//   - ExitCodeGeneral otherwise.
+func ExitCodeOf(err error) int {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitCodeNetworkError
+	}
+	switch code := types.ErrorCodeOf(err); {
+	case code == types.ErrorCodeWalletLocked:
+		return ExitCodeWalletLocked
+	case code >= 100 && code < 400:
+		// signature, transaction and unlock condition/fulfillment errors
+		return ExitCodeRejectedTransaction
+	}
+	switch httpStatusCodeOf(err) {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitCodeForbidden
+	case http.StatusNotFound:
+		return ExitCodeNotFound
+	case http.StatusRequestTimeout, http.StatusServiceUnavailable:
+		return ExitCodeTemporaryError
+	}
+	return ExitCodeGeneral
+}
+
+// httpStatusCodeOf returns the HTTP status code of a failed API call,
+// or 0 if the error was not caused by a failed API call.
+func httpStatusCodeOf(err error) int {
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return httpErr.HTTPStatusCode()
+	}
+	return 0
+}
+
+// firstError returns the first error found in the given arguments, if any.
+func firstError(args []interface{}) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newErrorOutput(code int, args []interface{}) ErrorOutput {
+	output := ErrorOutput{
+		ExitCode: code,
+		Message:  strings.TrimSpace(fmt.Sprintln(args...)),
+	}
+	if err := firstError(args); err != nil {
+		output.Code = types.ErrorCodeOf(err)
+		output.HTTPStatus = httpStatusCodeOf(err)
+	}
+	return output
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+type testHTTPError struct {
+	err    error
+	status int
+}
+
+func (e testHTTPError) Error() string       { return e.err.Error() }
+func (e testHTTPError) Unwrap() error       { return e.err }
+func (e testHTTPError) HTTPStatusCode() int { return e.status }
+
+func TestExitCodeOf(t *testing.T) {
+	testCases := []struct {
+		Err      error
+		ExitCode int
+	}{
+		{errors.New("foo"), ExitCodeGeneral},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ExitCodeNetworkError},
+		{fmt.Errorf("failed: %w", &net.DNSError{Err: "no such host"}), ExitCodeNetworkError},
+		{modules.ErrLockedWallet, ExitCodeWalletLocked},
+		{testHTTPError{modules.ErrLockedWallet, http.StatusForbidden}, ExitCodeWalletLocked},
+		{types.ErrDoubleSpend, ExitCodeRejectedTransaction},
+		{types.NewClientError(types.ErrInvalidSignature, types.ClientErrorBadRequest), ExitCodeRejectedTransaction},
+		{testHTTPError{errors.New("foo"), http.StatusBadRequest}, ExitCodeGeneral},
+		{testHTTPError{errors.New("foo"), http.StatusUnauthorized}, ExitCodeForbidden},
+		{testHTTPError{errors.New("foo"), http.StatusNotFound}, ExitCodeNotFound},
+		{testHTTPError{errors.New("foo"), http.StatusServiceUnavailable}, ExitCodeTemporaryError},
+	}
+	for idx, testCase := range testCases {
+		if code := ExitCodeOf(testCase.Err); code != testCase.ExitCode {
+			t.Errorf("#%d: unexpected exit code for %q: %d != %d", idx, testCase.Err, code, testCase.ExitCode)
+		}
+	}
+}
+
+func TestNewErrorOutput(t *testing.T) {
+	output := newErrorOutput(ExitCodeRejectedTransaction, []interface{}{
+		"failed to submit transaction:", testHTTPError{types.ErrDoubleSpend, http.StatusBadRequest}})
+	expected := ErrorOutput{
+		ExitCode:   ExitCodeRejectedTransaction,
+		Message:    "failed to submit transaction: " + types.ErrDoubleSpend.Error(),
+		Code:       types.ErrorCodeDoubleSpend,
+		HTTPStatus: http.StatusBadRequest,
+	}
+	if output != expected {
+		t.Errorf("unexpected error output: %+v != %+v", output, expected)
+	}
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes tabular output as comma-separated values (see EncodingTypeCSV),
+// using the given header as the first record.
+func WriteCSV(w io.Writer, header []string, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
 )
 
 const (
@@ -18,12 +20,60 @@ const (
 	DateOnlyLayout = "02/01/2006 MST"
 )
 
+// LockTimeInterpretation defines how the value of a LockTimeFlag was interpreted.
+type LockTimeInterpretation uint8
+
+// The different interpretations of a LockTimeFlag value.
+const (
+	// LockTimeBlockHeight is an absolute block height,
+	// defined as an integer smaller than types.LockTimeMinTimestampValue.
+	LockTimeBlockHeight LockTimeInterpretation = iota + 1
+	// LockTimeRelativeBlockHeight is a block height relative to the current block height,
+	// defined as "+<n> blocks", and resolved using (*LockTimeFlag).ResolveHeight.
+	LockTimeRelativeBlockHeight
+	// LockTimeTimestamp is an absolute (epoch unix) timestamp,
+	// defined as a date, a time or an integer of at least types.LockTimeMinTimestampValue.
+	LockTimeTimestamp
+	// LockTimeDuration is a timestamp relative to the current time,
+	// defined as a (calendar) duration.
+	LockTimeDuration
+)
+
+// String returns a human-readable name of the lock time interpretation.
+func (lti LockTimeInterpretation) String() string {
+	switch lti {
+	case LockTimeBlockHeight:
+		return "block height"
+	case LockTimeRelativeBlockHeight:
+		return "relative block height"
+	case LockTimeTimestamp:
+		return "timestamp"
+	case LockTimeDuration:
+		return "duration"
+	default:
+		return "undefined"
+	}
+}
+
 // LockTimeFlag defines LockTime as a flag,
 // as to give the user several ways to define the lock time,
 // such that for example the user isn't required to define it in unix epoch time.
+//
+// A lock time can be defined as:
+//
+//   - a timestamp in DateOnlyLayout, RFC822 or ISO 8601 layout, e.g. "2018-08-03" or "2018-08-03T12:00:00Z";
+//   - a duration, relative to the current time, e.g. "48h", "+24h", "-12h" or "1y6m",
+//     where a duration using calendar units (y, mo, w, d) interprets m as months (use min for minutes);
+//   - a block height offset, relative to the current block height, e.g. "+100 blocks";
+//   - an epoch unix timestamp or block height, e.g. "1533254400" or "42".
+//
+// Description can be used to surface to the user how the lock time was interpreted.
 type LockTimeFlag struct {
-	lockTime uint64
-	rawFlag  string
+	lockTime       uint64
+	rawFlag        string
+	interpretation LockTimeInterpretation
+	blockOffset    uint64
+	resolved       bool
 }
 
 // String implements pflag.Value.String,
@@ -34,23 +84,39 @@ func (f *LockTimeFlag) String() string {
 }
 
 // Set implements pflag.Value.Set,
-// which parses the given string either as a timestamp in DateOnlyLayout or RFC822 layout,
-// a duration or as an uint64.
+// which parses the given string either as a timestamp in DateOnlyLayout, RFC822 or ISO 8601 layout,
+// a (calendar) duration, a relative block height or as an uint64.
 func (f *LockTimeFlag) Set(s string) error {
 	f.rawFlag = s
-	if t, err := time.Parse(DateOnlyLayout, s); err == nil {
+	f.blockOffset, f.resolved = 0, false
+	for _, layout := range lockTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			// epoch unix (block) time
+			f.lockTime = uint64(t.Unix())
+			f.interpretation = LockTimeTimestamp
+			return nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
 		// epoch unix (block) time
-		f.lockTime = uint64(t.Unix())
+		f.lockTime = uint64(computeTimeNow().Add(d).Unix())
+		f.interpretation = LockTimeDuration
 		return nil
 	}
-	if t, err := time.Parse(time.RFC822, s); err == nil {
-		// epoch unix (block) time
-		f.lockTime = uint64(t.Unix())
+	if m := relativeBlockHeightRegexp.FindStringSubmatch(s); m != nil {
+		// block height, relative to the current block height
+		offset, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		f.blockOffset, f.lockTime = offset, offset
+		f.interpretation = LockTimeRelativeBlockHeight
 		return nil
 	}
-	if d, err := time.ParseDuration(s); err == nil {
+	if t, ok := addCalendarDuration(computeTimeNow(), s); ok {
 		// epoch unix (block) time
-		f.lockTime = uint64(computeTimeNow().Add(d).Unix())
+		f.lockTime = uint64(t.Unix())
+		f.interpretation = LockTimeDuration
 		return nil
 	}
 	// epoch unix (block) time or block height
@@ -59,6 +125,11 @@ func (f *LockTimeFlag) Set(s string) error {
 		return err
 	}
 	f.lockTime = x
+	if x < types.LockTimeMinTimestampValue {
+		f.interpretation = LockTimeBlockHeight
+	} else {
+		f.interpretation = LockTimeTimestamp
+	}
 	return nil
 }
 
@@ -67,11 +138,128 @@ func (f *LockTimeFlag) Type() string {
 	return "LockTime"
 }
 
-// LockTime returns the internal lock time of this LockTime flag
+// LockTime returns the internal lock time of this LockTime flag.
+// A relative block height has to be resolved using ResolveHeight first,
+// as it is otherwise returned relative to the genesis block.
 func (f *LockTimeFlag) LockTime() uint64 {
 	return f.lockTime
 }
 
+// Interpretation returns how the value of this LockTime flag was interpreted,
+// 0 is returned if no value was set.
+func (f *LockTimeFlag) Interpretation() LockTimeInterpretation {
+	return f.interpretation
+}
+
+// RequiresHeight returns true if the lock time is defined relative to the current block height,
+// and still has to be resolved using ResolveHeight.
+func (f *LockTimeFlag) RequiresHeight() bool {
+	return f.interpretation == LockTimeRelativeBlockHeight && !f.resolved
+}
+
+// ResolveHeight resolves a lock time defined relative to the current block height,
+// using the given current block height. It is a no-op for any other lock time.
+func (f *LockTimeFlag) ResolveHeight(height types.BlockHeight) error {
+	if f.interpretation != LockTimeRelativeBlockHeight {
+		return nil
+	}
+	lockTime := uint64(height) + f.blockOffset
+	if lockTime < uint64(height) || lockTime >= types.LockTimeMinTimestampValue {
+		return fmt.Errorf("block height %d + %d blocks exceeds the maximum block height lock time", height, f.blockOffset)
+	}
+	f.lockTime, f.resolved = lockTime, true
+	return nil
+}
+
+// Description returns a human-readable description of the lock time,
+// surfacing how the value of this flag was interpreted.
+func (f *LockTimeFlag) Description() string {
+	switch f.interpretation {
+	case LockTimeBlockHeight:
+		return fmt.Sprintf("block height %d", f.lockTime)
+	case LockTimeRelativeBlockHeight:
+		if !f.resolved {
+			return fmt.Sprintf("%d blocks after the current block height", f.blockOffset)
+		}
+		return fmt.Sprintf("block height %d (%d blocks after the current block height %d)",
+			f.lockTime, f.blockOffset, f.lockTime-f.blockOffset)
+	case LockTimeTimestamp:
+		return fmt.Sprintf("timestamp %d (%s)", f.lockTime, lockTimeUTCString(f.lockTime))
+	case LockTimeDuration:
+		relative := strings.TrimPrefix(f.rawFlag, "+") + " from now"
+		if strings.HasPrefix(f.rawFlag, "-") {
+			relative = f.rawFlag[1:] + " ago"
+		}
+		return fmt.Sprintf("timestamp %d (%s, %s)", f.lockTime, lockTimeUTCString(f.lockTime), relative)
+	default:
+		return "no lock time"
+	}
+}
+
+func lockTimeUTCString(lockTime uint64) string {
+	return time.Unix(int64(lockTime), 0).UTC().Format(time.RFC3339)
+}
+
+// lockTimeLayouts are the timestamp layouts supported by the LockTimeFlag,
+// ISO 8601 timestamps without timezone are interpreted as UTC.
+var lockTimeLayouts = []string{
+	DateOnlyLayout,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+var (
+	relativeBlockHeightRegexp       = regexp.MustCompile(`^\+(\d+)\s*blocks?$`)
+	calendarDurationComponentRegexp = regexp.MustCompile(`^(\d+)(min|mo|y|m|w|d|h|s)`)
+)
+
+// addCalendarDuration adds a duration defined using calendar units to the given time,
+// using the units y (years), mo or m (months), w (weeks), d (days), h (hours), min (minutes) and s (seconds),
+// such that for example 1y6m adds one year and six months. The duration can be prefixed with a sign.
+func addCalendarDuration(t time.Time, s string) (time.Time, bool) {
+	sign := 1
+	if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	} else if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	if s == "" {
+		return t, false
+	}
+	for s != "" {
+		m := calendarDurationComponentRegexp.FindStringSubmatch(s)
+		if m == nil {
+			return t, false
+		}
+		s = s[len(m[0]):]
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return t, false
+		}
+		n *= sign
+		switch m[2] {
+		case "y":
+			t = t.AddDate(n, 0, 0)
+		case "mo", "m":
+			t = t.AddDate(0, n, 0)
+		case "w":
+			t = t.AddDate(0, 0, 7*n)
+		case "d":
+			t = t.AddDate(0, 0, n)
+		case "h":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "min":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "s":
+			t = t.Add(time.Duration(n) * time.Second)
+		}
+	}
+	return t, true
+}
+
 type (
 	// StringLoaderFlag defines a utility type,
 	// allowing any StringLoader to be turned into a pflag-interface compatible type.
@@ -128,9 +316,15 @@ const (
 	// and encoding that binary output using the std hex encoder,
 	// resulting in a hex-encoded string.
 	EncodingTypeHex
+	// EncodingTypeCSV encodes tabular output as comma-separated values,
+	// with a header as the first record, to be used by spreadsheets and scripts.
+	// As only tabular output can be encoded as CSV, it has to be allowed explicitly
+	// by the mask of a flag, and isn't allowed by the default mask.
+	EncodingTypeCSV
 )
 
-// defaultEncodingTypeMask returns a mask which allows all possible encoding types.
+// defaultEncodingTypeMask returns a mask which allows all possible encoding types,
+// except for the CSV encoding type, which is only supported for tabular output.
 func defaultEncodingTypeMask() EncodingType {
 	return EncodingTypeHuman | EncodingTypeJSON | EncodingTypeHex
 }
@@ -153,7 +347,7 @@ func NewEncodingTypeFlag(def EncodingType, ref *EncodingType, mask EncodingType)
 	// sanity checks
 	if mask&def == 0 {
 		build.Critical(fmt.Sprintf("given default encoding type %d is not covered by given encoding type mask %b", def, mask))
-	} else if def != EncodingTypeHuman && def != EncodingTypeJSON && def != EncodingTypeHex {
+	} else if def != EncodingTypeHuman && def != EncodingTypeJSON && def != EncodingTypeHex && def != EncodingTypeCSV {
 		build.Critical(fmt.Sprintf("given default encoding type %d is not a valid encoding type", def))
 	}
 	return EncodingTypeFlag{
@@ -170,6 +364,8 @@ func (e EncodingTypeFlag) String() string {
 		return "json"
 	case EncodingTypeHex:
 		return "hex"
+	case EncodingTypeCSV:
+		return "csv"
 	default:
 		return "human"
 	}
@@ -190,6 +386,11 @@ func (e EncodingTypeFlag) Set(s string) error {
 			return errors.New("this command does not suppport Binary-Hex encoding")
 		}
 		*e.et = EncodingTypeHex
+	case "csv":
+		if e.mask&EncodingTypeCSV == 0 {
+			return errors.New("this command does not suppport CSV encoding")
+		}
+		*e.et = EncodingTypeCSV
 	case "human":
 		if e.mask&EncodingTypeHuman == 0 {
 			return errors.New("this command does not suppport Human-Format encoding")
@@ -220,6 +421,9 @@ func EncodingTypeFlagDescription(mask EncodingType) string {
 	if mask&EncodingTypeHex != 0 {
 		options = append(options, "hex")
 	}
+	if mask&EncodingTypeCSV != 0 {
+		options = append(options, "csv")
+	}
 	if mask&EncodingTypeHuman != 0 {
 		options = append(options, "human")
 	}
@@ -317,11 +521,86 @@ func (flag *netAddressArray) String() string {
 	return str[:len(str)-1]
 }
 
+// ErrAmbiguousCurrency is returned by ParseCurrency in case a value without unit is so large,
+// that it is most likely expressed in the smallest unit rather than the coin unit.
+var ErrAmbiguousCurrency = errors.New("ambiguous currency value")
+
+// ParseCurrency parses a currency value given by a user, expressed in the coin unit by default,
+// and optionally suffixed with the (SI-prefixed) coin unit or a sub-unit SI prefix only,
+// e.g. "10", "10.5", "10 TFT", "500 mTFT" or "250m".
+//
+// A natural number without unit of at least 10^precision coins is refused with ErrAmbiguousCurrency,
+// as it is most likely a value expressed in the smallest unit, off by 10^precision.
+// Such values have to be suffixed with the coin unit explicitly.
+func ParseCurrency(format types.CurrencyFormat, str string) (types.Currency, error) {
+	str = strings.TrimSpace(str)
+	c, err := format.ParseCoins(str)
+	if err != nil {
+		return types.Currency{}, err
+	}
+	precision := format.Precision()
+	if precision == 0 || strings.TrimLeft(str, "0123456789") != "" {
+		return c, nil // has decimals or a unit
+	}
+	if uint(len(strings.TrimLeft(str, "0"))) <= precision {
+		return c, nil
+	}
+	alternative, _ := format.Parse(str)
+	return types.Currency{}, fmt.Errorf(
+		"%v: %s would be %s, suffix it with the unit (%s %s) if intended, or use %s if it is expressed in the smallest unit",
+		ErrAmbiguousCurrency, str, format.Format(c), str, format.UnitName, format.Format(alternative))
+}
+
+// CurrencyFlag defines a currency value as a flag, expressed in the coin unit by default,
+// and optionally suffixed with the (SI-prefixed) coin unit, e.g. "1.5 TFT" or "500 mTFT".
+// See ParseCurrency for all accepted formats.
+// As the currency format of a chain is usually only known once all flags are parsed,
+// the value is only parsed when it is requested, using the currency format of the chain.
+type CurrencyFlag struct {
+	rawFlag string
+}
+
+// String implements pflag.Value.String,
+// returning the value as it was given.
+func (f *CurrencyFlag) String() string {
+	return f.rawFlag
+}
+
+// Set implements pflag.Value.Set,
+// storing the value, such that it can be parsed later.
+func (f *CurrencyFlag) Set(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("currency flag cannot be empty")
+	}
+	f.rawFlag = s
+	return nil
+}
+
+// Type implements pflag.Value.Type
+func (f *CurrencyFlag) Type() string {
+	return "Currency"
+}
+
+// IsSet returns true if a value was given for this flag.
+func (f *CurrencyFlag) IsSet() bool {
+	return f.rawFlag != ""
+}
+
+// Currency parses the value of this flag using ParseCurrency and the given currency format,
+// returning the zero value in case no value was given.
+func (f *CurrencyFlag) Currency(format types.CurrencyFormat) (types.Currency, error) {
+	if f.rawFlag == "" {
+		return types.Currency{}, nil
+	}
+	return ParseCurrency(format, f.rawFlag)
+}
+
 var computeTimeNow = func() time.Time {
 	return time.Now()
 }
 
 var (
 	_ pflag.Value = (*LockTimeFlag)(nil)
+	_ pflag.Value = (*CurrencyFlag)(nil)
 	_ pflag.Value = StringLoaderFlag{}
 )
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"time"
 
@@ -71,6 +72,14 @@ var lockTimeFlagTestCases = []struct {
 	{"25000", 25000},
 	{"1525599730", 1525599730},
 	{"1533254400", 1533254400},
+	{"2018-08-03", 1533254400},
+	{"2018-08-03T12:00", 1533297600},
+	{"2018-08-03T14:00:00+02:00", 1533297600},
+	{"+100 blocks", 100},
+	{"+1block", 1},
+	{"1y6m", uint64(time.Unix(testTimeNow, 0).AddDate(1, 6, 0).Unix())},
+	{"2w1d12h30min", testTimeNow + (15 * 24 * 3600) + (12 * 3600) + (30 * 60)},
+	{"-1mo", uint64(time.Unix(testTimeNow, 0).AddDate(0, -1, 0).Unix())},
 }
 
 func TestLockTimeFlagSet(t *testing.T) {
@@ -87,6 +96,79 @@ func TestLockTimeFlagSet(t *testing.T) {
 	}
 }
 
+func TestLockTimeFlagInterpretation(t *testing.T) {
+	testCases := []struct {
+		Raw            string
+		Interpretation LockTimeInterpretation
+		Description    string
+	}{
+		{"42", LockTimeBlockHeight, "block height 42"},
+		{"1533254400", LockTimeTimestamp, "timestamp 1533254400 (2018-08-03T00:00:00Z)"},
+		{"2018-08-03", LockTimeTimestamp, "timestamp 1533254400 (2018-08-03T00:00:00Z)"},
+		{"+24h", LockTimeDuration, "timestamp 1525686788 (2018-05-07T09:53:08Z, 24h from now)"},
+		{"-12h", LockTimeDuration, "timestamp 1525557188 (2018-05-05T21:53:08Z, 12h ago)"},
+		{"1y6m", LockTimeDuration, "timestamp 1573033988 (2019-11-06T09:53:08Z, 1y6m from now)"},
+		{"+100 blocks", LockTimeRelativeBlockHeight, "100 blocks after the current block height"},
+	}
+	for idx, testCase := range testCases {
+		var ltf LockTimeFlag
+		if err := ltf.Set(testCase.Raw); err != nil {
+			t.Error(idx, err)
+			continue
+		}
+		if lti := ltf.Interpretation(); lti != testCase.Interpretation {
+			t.Error(idx, lti, "!=", testCase.Interpretation)
+		}
+		if desc := ltf.Description(); desc != testCase.Description {
+			t.Errorf("#%d: unexpected description: %q != %q", idx, desc, testCase.Description)
+		}
+	}
+}
+
+func TestLockTimeFlagResolveHeight(t *testing.T) {
+	var ltf LockTimeFlag
+	if err := ltf.Set("+100 blocks"); err != nil {
+		t.Fatal(err)
+	}
+	if !ltf.RequiresHeight() {
+		t.Fatal("relative block height should require the current height")
+	}
+	if err := ltf.ResolveHeight(50); err != nil {
+		t.Fatal(err)
+	}
+	if ltf.RequiresHeight() {
+		t.Error("resolved block height should no longer require the current height")
+	}
+	if lt := ltf.LockTime(); lt != 150 {
+		t.Error(lt, "!= 150")
+	}
+	const expectedDescription = "block height 150 (100 blocks after the current block height 50)"
+	if desc := ltf.Description(); desc != expectedDescription {
+		t.Errorf("unexpected description: %q", desc)
+	}
+	if err := ltf.ResolveHeight(types.LockTimeMinTimestampValue - 50); err == nil {
+		t.Error("expected a lock time exceeding the maximum block height to fail")
+	}
+
+	// other lock times are unaffected by the current height
+	if err := ltf.Set("42"); err != nil {
+		t.Fatal(err)
+	}
+	if ltf.RequiresHeight() {
+		t.Error("absolute block height should not require the current height")
+	}
+	if err := ltf.ResolveHeight(50); err != nil || ltf.LockTime() != 42 {
+		t.Error("unexpected resolved lock time:", ltf.LockTime(), err)
+	}
+
+	// invalid lock times
+	for idx, raw := range []string{"", "+", "+blocks", "100 blocks", "1y6q", "abc"} {
+		if err := ltf.Set(raw); err == nil {
+			t.Errorf("#%d: expected %q to be invalid", idx, raw)
+		}
+	}
+}
+
 func TestLockTimeSetStringLoop(t *testing.T) {
 	for idx, testCase := range lockTimeFlagTestCases {
 		var ltf LockTimeFlag
@@ -148,6 +230,7 @@ func TestEncodingTypeFlagString(t *testing.T) {
 		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingTypeHuman; return &et }()}, "human"},
 		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingTypeJSON; return &et }()}, "json"},
 		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingTypeHex; return &et }()}, "hex"},
+		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingTypeCSV; return &et }()}, "csv"},
 		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingTypeJSON | EncodingTypeHex; return &et }()}, "human"}, // def = human
 		{EncodingTypeFlag{et: func() *EncodingType { et := EncodingType(128); return &et }()}, "human"},                  // def = human
 	}
@@ -258,6 +341,25 @@ func TestEncodingTypeFlagSet(t *testing.T) {
 	}
 }
 
+func TestEncodingTypeFlagSetCSV(t *testing.T) {
+	var et EncodingType
+	// CSV is not allowed by the default mask
+	f := NewEncodingTypeFlag(0, &et, 0)
+	if err := f.Set("csv"); err == nil {
+		t.Fatal("setting to csv should fail for the default mask, but now et is: ", et)
+	}
+	f = NewEncodingTypeFlag(0, &et, EncodingTypeHuman|EncodingTypeCSV)
+	if err := f.Set("CSV"); err != nil {
+		t.Fatal(err)
+	}
+	if et != EncodingTypeCSV {
+		t.Fatal("et was supposed to be EncodingTypeCSV, but was instead:", et)
+	}
+	if desc := EncodingTypeFlagDescription(EncodingTypeHuman | EncodingTypeCSV); !strings.HasSuffix(desc, "csv|human") {
+		t.Fatal("unexpected description:", desc)
+	}
+}
+
 func TestInvalidEncodingTypeSetAsFlag(t *testing.T) {
 	// create new encoding type and flag
 	var et EncodingType
@@ -408,3 +510,76 @@ func init() {
 		return time.Unix(testTimeNow, 0)
 	}
 }
+
+func TestCurrencyFlag(t *testing.T) {
+	format := types.NewCurrencyFormat(types.DefaultCurrencyUnits(), "TFT")
+
+	var flag CurrencyFlag
+	if flag.IsSet() {
+		t.Error("flag shouldn't be set yet")
+	}
+	c, err := flag.Currency(format)
+	if err != nil || !c.IsZero() {
+		t.Errorf("unexpected value of unset flag: %v (%v)", c, err)
+	}
+	if err = flag.Set(""); err == nil {
+		t.Error("empty currency flag shouldn't be accepted")
+	}
+
+	for _, testCase := range []string{"1.5", "1.5 TFT", "1500 mTFT"} {
+		if err = flag.Set(testCase); err != nil {
+			t.Errorf("failed to set %q: %v", testCase, err)
+			continue
+		}
+		if flag.String() != testCase {
+			t.Errorf("%q != %q", flag.String(), testCase)
+		}
+		c, err = flag.Currency(format)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", testCase, err)
+		} else if !c.Equals64(1500000000) {
+			t.Errorf("%q parsed as %s", testCase, c.String())
+		}
+	}
+
+	if err = flag.Set("1.5 BTC"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = flag.Currency(format); err == nil {
+		t.Error("currency of unknown unit shouldn't parse")
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	format := types.NewCurrencyFormat(types.DefaultCurrencyUnits(), "TFT")
+
+	for str, expected := range map[string]uint64{
+		"10":             10000000000,
+		"10.5":           10500000000,
+		"10 TFT":         10000000000,
+		"250m":           250000000,
+		"250 mTFT":       250000000,
+		"999999999":      999999999000000000,
+		"000000001":      1000000000,
+		"1000000000 TFT": 1000000000000000000,
+		"1000000000.0":   1000000000000000000,
+	} {
+		c, err := ParseCurrency(format, str)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", str, err)
+		} else if !c.Equals64(expected) {
+			t.Errorf("%q parsed as %s, expected %d", str, c.String(), expected)
+		}
+	}
+
+	// raw values expressed in the smallest unit are refused
+	_, err := ParseCurrency(format, "1000000000")
+	if err == nil || !strings.HasPrefix(err.Error(), ErrAmbiguousCurrency.Error()) {
+		t.Errorf("unexpected error for ambiguous value: %v", err)
+	}
+	// unless the precision is zero, in which case no value is ambiguous
+	format = types.NewCurrencyFormat(types.CurrencyUnits{OneCoin: types.NewCurrency64(1)}, "TFT")
+	if c, err := ParseCurrency(format, "1000000000"); err != nil || !c.Equals64(1000000000) {
+		t.Errorf("unexpected result for zero precision: %v, %v", c, err)
+	}
+}
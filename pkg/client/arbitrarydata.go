@@ -0,0 +1,82 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// arbitraryDataFlags are the flags used to attach (structured) arbitrary data to a transaction,
+// given as a flag value (--data) or read from a file (--data-file),
+// and wrapped in the typed envelope of the type defined by --data-type.
+type arbitraryDataFlags struct {
+	flags    *pflag.FlagSet
+	data     []byte
+	dataFile string
+	dataType types.ArbitraryDataType
+}
+
+// register registers the arbitrary data flags on the given flag set.
+func (f *arbitraryDataFlags) register(flags *pflag.FlagSet) {
+	f.flags = flags
+	cli.ArbitraryDataFlagVar(flags, &f.data,
+		"data", "optional arbitrary data (or description) to attach to transaction, stored as UTF-8 text by default")
+	flags.StringVar(&f.dataFile, "data-file", "",
+		"optional file of which to attach the content as arbitrary data to the transaction, stored as binary data by default, - reads STDIN")
+	flags.Var(cli.StringLoaderFlag{StringLoader: &f.dataType}, "data-type",
+		"type of the arbitrary data, wrapping it in a typed envelope, options: raw|binary|utf8|json|<number>, raw storing it without envelope")
+	// the default type depends on the flag used to define the data, see ArbitraryData
+	flags.Lookup("data-type").DefValue = ""
+}
+
+// ArbitraryData returns the arbitrary data defined by the flags, wrapped in the typed envelope of its type,
+// validating it against the arbitrary data size limit and allowed types of the chain, if known.
+// Nil is returned in case no arbitrary data is defined.
+func (f *arbitraryDataFlags) ArbitraryData(cfg *Config) ([]byte, error) {
+	var payload []byte
+	dataType := f.dataType
+	switch {
+	case len(f.data) > 0 && f.dataFile != "":
+		return nil, errors.New("arbitrary data can only be defined using one of --data and --data-file")
+	case f.dataFile != "":
+		var err error
+		if f.dataFile == "-" {
+			payload, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			payload, err = ioutil.ReadFile(f.dataFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read arbitrary data file: %v", err)
+		}
+		if f.flags == nil || !f.flags.Changed("data-type") {
+			dataType = types.ArbitraryDataTypeBinary
+		}
+	case len(f.data) > 0:
+		payload = f.data
+		if f.flags == nil || !f.flags.Changed("data-type") {
+			dataType = types.ArbitraryDataTypeUTF8
+		}
+	default:
+		return nil, nil
+	}
+	data, err := types.EncodeArbitraryData(dataType, 0, payload)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ArbitraryDataSizeLimit == 0 {
+		// the limits of the chain are unknown, leaving it up to the daemon to validate the data
+		return data, nil
+	}
+	err = types.ValidateArbitraryData(data, cfg.ArbitraryDataSizeLimit, cfg.AllowedArbitraryDataTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arbitrary data of %d bytes (limit: %d bytes): %v",
+			len(data), cfg.ArbitraryDataSizeLimit, err)
+	}
+	return data, nil
+}
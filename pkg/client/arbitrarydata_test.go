@@ -0,0 +1,92 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+func parseArbitraryDataFlags(t *testing.T, args ...string) *arbitraryDataFlags {
+	var f arbitraryDataFlags
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.register(flags)
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return &f
+}
+
+func TestArbitraryDataFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "arbitrarydata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data")
+	if err = ioutil.WriteFile(path, []byte{0, 1, 2}, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{ArbitraryDataSizeLimit: 83}
+	testCases := []struct {
+		Args         []string
+		ExpectedType types.ArbitraryDataType
+		Payload      []byte
+	}{
+		{nil, 0, nil},
+		{[]string{"--data", "hello"}, types.ArbitraryDataTypeUTF8, []byte("hello")},
+		{[]string{"--data", `{"a":1}`, "--data-type", "json"}, types.ArbitraryDataTypeJSON, []byte(`{"a":1}`)},
+		{[]string{"--data", "hello", "--data-type", "raw"}, types.ArbitraryDataTypeRaw, []byte("hello")},
+		{[]string{"--data-file", path}, types.ArbitraryDataTypeBinary, []byte{0, 1, 2}},
+		{[]string{"--data-file", path, "--data-type", "200"}, 200, []byte{0, 1, 2}},
+	}
+	for idx, testCase := range testCases {
+		data, err := parseArbitraryDataFlags(t, testCase.Args...).ArbitraryData(cfg)
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", idx, err)
+			continue
+		}
+		if testCase.Payload == nil {
+			if data != nil {
+				t.Errorf("#%d: expected no data, got %x", idx, data)
+			}
+			continue
+		}
+		sad, err := types.DecodeArbitraryData(data)
+		if err != nil {
+			t.Errorf("#%d: failed to decode data %x: %v", idx, data, err)
+			continue
+		}
+		if sad.Type != testCase.ExpectedType {
+			t.Errorf("#%d: unexpected type: %v != %v", idx, sad.Type, testCase.ExpectedType)
+		}
+		if !bytes.Equal(sad.Payload, testCase.Payload) {
+			t.Errorf("#%d: unexpected payload: %x != %x", idx, sad.Payload, testCase.Payload)
+		}
+	}
+}
+
+func TestArbitraryDataFlagsInvalid(t *testing.T) {
+	cfg := &Config{
+		ArbitraryDataSizeLimit:    16,
+		AllowedArbitraryDataTypes: []types.ArbitraryDataType{types.ArbitraryDataTypeUTF8},
+	}
+	for idx, args := range [][]string{
+		{"--data", "hello", "--data-file", "-"},
+		{"--data", "this text does not fit within the limit"},
+		{"--data", "hello", "--data-type", "json"},
+		{"--data", "hello", "--data-type", "binary"},
+		{"--data-file", filepath.Join("non", "existing", "file")},
+	} {
+		_, err := parseArbitraryDataFlags(t, args...).ArbitraryData(cfg)
+		if err == nil {
+			t.Errorf("#%d: expected %v to fail", idx, args)
+		}
+	}
+}
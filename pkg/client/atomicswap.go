@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/threefoldtech/rivine/pkg/api"
 	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -39,6 +40,9 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  0: contract created successfully as participant
 		  1: generic error, automatically recovering is not possible or recommended
 		  3: command cancelled by user
+		  6: network error: the daemon could not be reached
+		  7: transaction rejected by the daemon
+		  8: wallet locked, unlock the wallet and try again
 		  64: misusage of the command, see --help on how to use the command
 		
 		Example STDOUT output when using the '--encoding json' flag:
@@ -84,6 +88,9 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  0: contract created successfully as initiator
 		  1: generic error, automatically recovering is not possible or recommended
 		  3: command cancelled by user
+		  6: network error: the daemon could not be reached
+		  7: transaction rejected by the daemon
+		  8: wallet locked, unlock the wallet and try again
 		  64: misusage of the command, see --help on how to use the command
 		
 		Example output when using the '--encoding json' flag:
@@ -140,7 +147,8 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  1: generic error, automatically recovering is not possible or recommended
 		  2: contract not found
 		  3: command cancelled by user
-		  4: temporary error: contract was found and valid but not yet confirmed
+		  5: temporary error: contract was found and valid but not yet confirmed
+		  6: network error: the daemon could not be reached
 		  64: misusage of the command, see --help on how to use the command
 		  128: contract invalid compared to the given criteria
 		
@@ -190,6 +198,7 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  1: generic error, automatically recovering is not possible or recommended
 		  2: contract not found
 		  3: command cancelled by user
+		  6: network error: the daemon could not be reached
 		  64: misusage of the command, see --help on how to use the command
 		  128: contract invalid compared to the given criteria
 		
@@ -218,6 +227,9 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  1: generic error, automatically recovering is not possible or recommended
 		  2: contract not found
 		  3: command cancelled by user
+		  6: network error: the daemon could not be reached
+		  7: transaction rejected by the daemon
+		  8: wallet locked, unlock the wallet and try again
 		  64: misusage of the command, see --help on how to use the command
 		
 		Example output when using the '--encoding json' flag:
@@ -244,6 +256,9 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 		  1: generic error, automatically recovering is not possible or recommended
 		  2: contract not found
 		  3: command cancelled by user
+		  6: network error: the daemon could not be reached
+		  7: transaction rejected by the daemon
+		  8: wallet locked, unlock the wallet and try again
 		  64: misusage of the command, see --help on how to use the command
 		
 		Example output when using the '--encoding json' flag:
@@ -293,8 +308,8 @@ func createAtomicSwapCmd(client *CommandLineClient) *cobra.Command {
 	auditCmd.Flags().Var(
 		cli.StringLoaderFlag{StringLoader: &atomicSwapCmd.auditCfg.ReceiverAddress}, "receiver",
 		"optionally validate the given receiver's address (unlockhash) to the one found in the atomic swap contract condition")
-	auditCmd.Flags().StringVar(
-		&atomicSwapCmd.auditCfg.CoinAmountString, "amount", "",
+	auditCmd.Flags().Var(
+		&atomicSwapCmd.auditCfg.CoinAmount, "amount",
 		"optionally validate the given coin amount to the one found in the unspent coin output")
 	auditCmd.Flags().DurationVar(
 		&atomicSwapCmd.auditCfg.MinDurationLeft, "min-duration", 0,
@@ -324,10 +339,10 @@ type atomicSwapCmd struct {
 		SourceUnlockHash types.UnlockHash
 	}
 	auditCfg struct {
-		ReceiverAddress  types.UnlockHash
-		CoinAmountString string
-		HashedSecret     types.AtomicSwapHashedSecret
-		MinDurationLeft  time.Duration
+		ReceiverAddress types.UnlockHash
+		CoinAmount      cli.CurrencyFlag
+		HashedSecret    types.AtomicSwapHashedSecret
+		MinDurationLeft time.Duration
 	}
 	extractSecretCfg struct {
 		HashedSecret types.AtomicSwapHashedSecret
@@ -371,7 +386,7 @@ func (atomicSwapCmd *atomicSwapCmd) participateCmd(participantAddress, amount, h
 	var (
 		receiver, sender types.UnlockHash
 	)
-	err := receiver.LoadString(participantAddress)
+	err := receiver.LoadAddressString(participantAddress, atomicSwapCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cli.DieWithExitCode(cli.ExitCodeUsage, "failed to parse participant address (unlock hash):", err)
 	}
@@ -410,7 +425,7 @@ func (atomicSwapCmd *atomicSwapCmd) initiateCmd(participantAddress, amount strin
 	var (
 		receiver, sender types.UnlockHash
 	)
-	err := receiver.LoadString(participantAddress)
+	err := receiver.LoadAddressString(participantAddress, atomicSwapCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cli.DieWithExitCode(cli.ExitCodeUsage, "failed to parse participant address (unlock hash):", err)
 	}
@@ -667,13 +682,13 @@ TimeLock reached in: %s
 	}
 
 	var invalidContract bool
-	if atomicSwapCmd.auditCfg.CoinAmountString != "" && atomicSwapCmd.auditCfg.CoinAmountString != "0" {
-		amount, err := currencyConverter.ParseCoinString(atomicSwapCmd.auditCfg.CoinAmountString)
+	if atomicSwapCmd.auditCfg.CoinAmount.IsSet() {
+		amount, err := atomicSwapCmd.auditCfg.CoinAmount.Currency(currencyConverter.CurrencyFormat())
 		if err != nil {
 			cli.DieWithError("failed to parse amount string: ", err)
 		}
 		// optionally validate coin amount
-		if !amount.Equals(co.Value) {
+		if !amount.IsZero() && !amount.Equals(co.Value) {
 			invalidContract = true
 			fmt.Fprintln(os.Stderr, "unspent out's value "+
 				currencyConverter.ToCoinStringWithUnit(co.Value)+
@@ -961,39 +976,26 @@ func (atomicSwapCmd *atomicSwapCmd) spendAtomicSwapContract(outputID types.CoinO
 			cli.DieWithExitCode(cli.ExitCodeCancelled, "atomic swap "+keyWord+" transaction cancelled")
 		}
 	}
-	// step 4: create a transaction
-	txn := types.Transaction{
-		Version: atomicSwapCmd.cli.Config.DefaultTransactionVersion,
-		CoinInputs: []types.CoinInput{
-			{
-				ParentID: outputID,
-				Fulfillment: types.NewFulfillment(&types.AtomicSwapFulfillment{
-					PublicKey: pk,
-					Secret:    secret,
-				}),
-			},
-		},
-		CoinOutputs: []types.CoinOutput{
-			{
-				Condition: types.NewCondition(types.NewUnlockHashCondition(uh)),
-				Value:     unspentCoinOutputResp.Output.Value.Sub(atomicSwapCmd.cli.Config.MinimumTransactionFee),
-			},
-		},
-		MinerFees: []types.Currency{atomicSwapCmd.cli.Config.MinimumTransactionFee},
+	// step 4: create a transaction, sending all locked coins (minus the fee) to ourselves
+	builder := txbuilder.New(atomicSwapCmd.cli.Config.DefaultTransactionVersion).
+		SpendCoinOutput(outputID, unspentCoinOutputResp.Output, &types.AtomicSwapFulfillment{
+			PublicKey: pk,
+			Secret:    secret,
+		}).
+		AddMinerFee(atomicSwapCmd.cli.Config.MinimumTransactionFee)
+	err = builder.AddCoinChange(types.NewCondition(types.NewUnlockHashCondition(uh)))
+	if err != nil {
+		cli.Die("failed to "+keyWord+" atomic swap's locked coins, couldn't create transaction:", err)
 	}
 
 	// step 5: sign transaction's only input
-	err = txn.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
-		ExtraObjects: []interface{}{uint64(0)},
-		Transaction:  txn,
-		Key:          sk,
-	})
+	err = builder.SignCoinInput(0, sk)
 	if err != nil {
 		cli.Die("failed to "+keyWord+" atomic swap's locked coins, couldn't sign transaction:", err)
 	}
 
 	// step 6: submit transaction to transaction pool and celebrate if possible
-	txnid, err := atomicSwapCmd.commitTxn(txn)
+	txnid, err := atomicSwapCmd.commitTxn(builder.Transaction())
 	if err != nil {
 		cli.Die("failed to "+keyWord+" atomic swaps locked tokens, as transaction couldn't commit:", err)
 	}
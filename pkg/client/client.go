@@ -29,8 +29,12 @@ func ConfigFromDaemonConstants(constants modules.DaemonConstants) Config {
 		CurrencyCoinUnit:          constants.ChainInfo.CoinUnit,
 		MinimumTransactionFee:     constants.MinimumTransactionFee,
 		DefaultTransactionVersion: constants.DefaultTransactionVersion,
+		ArbitraryDataSizeLimit:    constants.ArbitraryDataSizeLimit,
+		AllowedArbitraryDataTypes: constants.AllowedArbitraryDataTypes,
 		BlockFrequencyInSeconds:   int64(constants.BlockFrequency),
 		GenesisBlockTimestamp:     constants.GenesisTimestamp,
+		Bech32AddressPrefix:       constants.Bech32AddressPrefix,
+		ChainID:                   constants.ChainID,
 	}
 }
 
@@ -45,10 +49,24 @@ type Config struct {
 	MinimumTransactionFee     types.Currency
 	DefaultTransactionVersion types.TransactionVersion
 
+	// ArbitraryDataSizeLimit and AllowedArbitraryDataTypes are used to validate
+	// the arbitrary data of transactions prior to submitting them,
+	// arbitrary data isn't validated by the client in case the size limit is undefined.
+	ArbitraryDataSizeLimit    uint64
+	AllowedArbitraryDataTypes []types.ArbitraryDataType
+
 	// These values aren't used for validation,
 	// but only in order to estimate progress with the syncing of your consensus.
 	BlockFrequencyInSeconds int64
 	GenesisBlockTimestamp   types.Timestamp
+
+	// Bech32AddressPrefix is the human-readable part of the bech32 addresses of the chain,
+	// bech32 addresses are only accepted if defined.
+	Bech32AddressPrefix string
+
+	// ChainID identifies the chain (network), it is required in order
+	// to sign replay-protected transactions and is only known for daemons which expose it.
+	ChainID types.ChainID
 }
 
 // Wrap wraps a generic command with a check that the command has been
@@ -185,11 +203,39 @@ func NewCommandLineClient(address, name, userAgent string) (*CommandLineClient,
 	client.MergeCmd = createMergeCmd(client)
 	client.RootCmd.AddCommand(client.MergeCmd)
 
+	client.DecodeCmd = createDecodeCmd(client)
+	client.RootCmd.AddCommand(client.DecodeCmd)
+
+	client.ValidateCmd = createValidateCmd(client)
+	client.RootCmd.AddCommand(client.ValidateCmd)
+
+	client.MultisigCmd = createMultisigCmd(client)
+	client.RootCmd.AddCommand(client.MultisigCmd)
+
+	client.WatchCmd = createWatchCmd(client)
+	client.RootCmd.AddCommand(client.WatchCmd)
+
+	client.GenerateCmd = createGenerateCmd(client)
+	client.RootCmd.AddCommand(client.GenerateCmd)
+
+	client.RecoverCmd = createRecoverCmd(client)
+	client.RootCmd.AddCommand(client.RecoverCmd)
+
 	// parse flags
 	client.RootCmd.PersistentFlags().StringVarP(&client.HTTPClient.RootURL, "addr", "a",
 		client.HTTPClient.RootURL, fmt.Sprintf(
-			"which host/port to communicate with (i.e. the host/port %sd is listening on)",
+			"which host/port to communicate with (i.e. the host/port %sd is listening on), or unix://<path> to communicate over its unix socket, "+
+				"multiple comma-separated addresses are tried in order, failing over to the next one if a daemon cannot be reached",
 			name))
+	client.RootCmd.PersistentFlags().StringVar(&client.tlsCertFile, "tls-cert", "",
+		fmt.Sprintf("PEM-encoded certificate to trust when communicating with %sd over https, e.g. its self-signed certificate", name))
+	client.RootCmd.PersistentFlags().BoolVar(&client.tlsSkipVerify, "tls-skip-verify", false,
+		fmt.Sprintf("do not verify the certificate of %sd when communicating over https (INSECURE)", name))
+	client.RootCmd.PersistentFlags().StringVar(&client.profilesConfigPath, "config", DefaultProfilesConfigPath(name),
+		"config file defining named profiles (YAML, or JSON if it has the .json extension), ignored if it does not exist")
+	client.RootCmd.PersistentFlags().StringVar(&client.profileName, "profile", "",
+		"name of the config file profile to use, the default profile of the config file is used if not defined")
+	client.RootCmd.PersistentFlags().Var(cli.ErrorFormatFlag{}, "error-format", cli.ErrorFormatFlagDescription())
 
 	// return client
 	return client, nil
@@ -204,6 +250,8 @@ type CommandLineClient struct {
 	*api.HTTPClient
 
 	Config *Config
+	// Profile is the config file profile in use, nil if no profile is used
+	Profile *Profile
 
 	PreRunE func(*Config) (*Config, error)
 
@@ -214,15 +262,57 @@ type CommandLineClient struct {
 	GatewayCmd    *cobra.Command
 	ExploreCmd    *cobra.Command
 	MergeCmd      *cobra.Command
+	DecodeCmd     *cobra.Command
+	ValidateCmd   *cobra.Command
+	MultisigCmd   *cobra.Command
+	WatchCmd      *cobra.Command
+	GenerateCmd   *cobra.Command
+	RecoverCmd    *cobra.Command
+
+	tlsCertFile   string
+	tlsSkipVerify bool
+
+	profilesConfigPath string
+	profileName        string
 }
 
 // preRunE checks that all preConditions match
-func (cli *CommandLineClient) preRunE(*cobra.Command, []string) error {
-	address, err := sanitizeURL(cli.HTTPClient.RootURL)
-	if err != nil {
-		return fmt.Errorf("invalid daemon RPC address %q: %v", cli.HTTPClient.RootURL, err)
+func (cli *CommandLineClient) preRunE(cmd *cobra.Command, _ []string) error {
+	if err := cli.applyProfile(cmd); err != nil {
+		return err
+	}
+	addresses := strings.Split(cli.HTTPClient.RootURL, ",")
+	if strings.HasPrefix(cli.HTTPClient.RootURL, unixSocketScheme) {
+		if len(addresses) > 1 {
+			return errors.New("a unix socket address cannot be combined with other daemon addresses")
+		}
+		err := configureUnixSocket(strings.TrimPrefix(cli.HTTPClient.RootURL, unixSocketScheme))
+		if err != nil {
+			return err
+		}
+		cli.HTTPClient.RootURL = unixSocketRootURL
+	} else {
+		urls := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			address = strings.TrimSpace(address)
+			if strings.HasPrefix(address, unixSocketScheme) {
+				return errors.New("a unix socket address cannot be combined with other daemon addresses")
+			}
+			url, err := sanitizeURL(address)
+			if err != nil {
+				return fmt.Errorf("invalid daemon RPC address %q: %v", address, err)
+			}
+			urls = append(urls, url)
+		}
+		cli.HTTPClient.RootURL, cli.HTTPClient.FailoverURLs = urls[0], urls[1:]
+		err := configureTLS(cli.tlsCertFile, cli.tlsSkipVerify)
+		if err != nil {
+			return err
+		}
+		if len(cli.HTTPClient.FailoverURLs) > 0 && !cli.HTTPClient.SelectHealthyDaemon() {
+			fmt.Fprintln(os.Stderr, "none of the daemons passed the health check, trying them in order")
+		}
 	}
-	cli.HTTPClient.RootURL = address
 
 	if cli.Config == nil {
 		var err error
@@ -232,6 +322,7 @@ func (cli *CommandLineClient) preRunE(*cobra.Command, []string) error {
 		}
 	}
 	if cli.PreRunE != nil {
+		var err error
 		cli.Config, err = cli.PreRunE(cli.Config)
 		if err != nil {
 			return fmt.Errorf("user-defined pre-run callback failed: %v", err)
@@ -240,6 +331,10 @@ func (cli *CommandLineClient) preRunE(*cobra.Command, []string) error {
 	if cli.Config == nil {
 		return errors.New("cannot run command line client: no config is defined")
 	}
+	if cli.Profile != nil && cli.Profile.Network != "" && cli.Profile.Network != cli.Config.NetworkName {
+		return fmt.Errorf("profile expects network %q, while the config is defined for network %q",
+			cli.Profile.Network, cli.Config.NetworkName)
+	}
 	return nil
 }
 
@@ -268,7 +363,7 @@ func FetchConfigFromDaemon(httpClient *api.HTTPClient) (*Config, error) {
 	}
 	err = httpClient.GetAPI("/explorer/constants", &constants)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load constants from daemon's server and explorer modules: %v", err)
+		return nil, fmt.Errorf("failed to load constants from daemon's server and explorer modules: %w", err)
 	}
 	if constants.ChainInfo == (types.BlockchainInfo{}) {
 		// only since 1.0.7 do we support the full set of public daemon constants for both
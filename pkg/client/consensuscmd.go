@@ -1,10 +1,15 @@
 package client
 
 import (
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -32,13 +37,46 @@ func createConsensusCmd(client *CommandLineClient) (*consensusCmd, *cobra.Comman
 			Long:  "Get an existing transaction from the blockchain, using its given shortID or longID.",
 			Run:   Wrap(consensusCmd.transactionCmd),
 		}
+		blocksCmd = &cobra.Command{
+			Use:   "blocks",
+			Short: "Get the blocks within a height range",
+			Long: `Get the blocks of the current chain within the given (inclusive) height range,
+printing a summary of each block, or the full blocks as JSON (one block per line).
+The blocks are fetched page per page and printed as they are received,
+such that large ranges can be processed without waiting for the entire range.
+The range ends at the current height if --to is not defined.`,
+			Args: cobra.NoArgs,
+			Run:  consensusCmd.blocksCmd,
+		}
+		syncStatusCmd = &cobra.Command{
+			Use:   "sync-status",
+			Short: "Follow the synchronisation progress of the consensus",
+			Long: `Follow the synchronisation progress of the consensus until it is synced,
+printing the current height, the estimated network height, the progress,
+the sync speed in blocks per second over the last minute and the estimated time remaining.
+Peers do not advertise their height, such that the network height is estimated
+using the genesis timestamp and block frequency of the chain.
+When attached to a terminal the status is updated in place, using a progress bar,
+otherwise a status line is printed every interval.`,
+			Args: cobra.NoArgs,
+			Run:  consensusCmd.syncStatusCmd,
+		}
 	)
-	rootCmd.AddCommand(transactionCmd)
+	rootCmd.AddCommand(transactionCmd, blocksCmd, syncStatusCmd)
 
 	// create flags
 	transactionCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &consensusCmd.transactionCfg.EncodingType, 0), "encoding",
 		cli.EncodingTypeFlagDescription(0))
+	blocksCmd.Flags().Uint64Var(
+		&consensusCmd.blocksCfg.From, "from", 0, "height of the first block of the range")
+	blocksCmd.Flags().Uint64Var(
+		&consensusCmd.blocksCfg.To, "to", 0, "height of the last block of the range, the current height if not defined")
+	blocksCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &consensusCmd.blocksCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON|cli.EncodingTypeCSV), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON|cli.EncodingTypeCSV))
+	syncStatusCmd.Flags().DurationVar(
+		&consensusCmd.syncStatusCfg.Interval, "interval", 2*time.Second, "interval in which the consensus state is polled")
 
 	// return root command
 	return consensusCmd, rootCmd
@@ -49,6 +87,13 @@ type consensusCmd struct {
 	transactionCfg struct {
 		EncodingType cli.EncodingType
 	}
+	blocksCfg struct {
+		From, To     uint64
+		EncodingType cli.EncodingType
+	}
+	syncStatusCfg struct {
+		Interval time.Duration
+	}
 }
 
 // rootCmd is the handler for the command `rivinec consensus`.
@@ -74,10 +119,159 @@ Target: %v
 		fmt.Printf(`Synced: %v
 Height: %v
 Progress (estimated): %.2f%%
+Use 'consensus sync-status' to follow the progress.
 `, YesNo(cg.Synced), cg.Height, estimatedProgress)
 	}
 }
 
+// syncStatusCmd is the handler for the command `rivinec consensus sync-status`.
+// Prints the sync progress of the consensus every interval, until it is synced.
+func (consensusCmd *consensusCmd) syncStatusCmd(cmd *cobra.Command, _ []string) {
+	interval := consensusCmd.syncStatusCfg.Interval
+	if interval <= 0 {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithExitCode(cli.ExitCodeUsage, "the interval has to be positive")
+	}
+	tty := isTerminal(os.Stdout)
+	progress := syncProgress{Window: time.Minute}
+	for {
+		var cg api.ConsensusGET
+		err := consensusCmd.cli.GetAPI("/consensus", &cg)
+		if err != nil {
+			if tty {
+				fmt.Println()
+			}
+			cli.DieWithError("Could not get current consensus state:", err)
+		}
+		now := time.Now()
+		progress.Add(now, cg.Height)
+		target := consensusCmd.estimatedHeightAt(now)
+		if cg.Synced || target < cg.Height {
+			target = cg.Height
+		}
+		status := progress.Status(target, cg.Synced)
+		if tty {
+			// overwrite the previous status line, clearing any leftover characters
+			fmt.Printf("\r%s %s\x1b[K", progressBar(30, progress.Fraction(target, cg.Synced)), status)
+		} else {
+			fmt.Println(status)
+		}
+		if cg.Synced {
+			if tty {
+				fmt.Println()
+			}
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+// syncProgress keeps track of the consensus heights observed within a time window,
+// such that the sync speed and remaining sync time can be estimated.
+type syncProgress struct {
+	Window  time.Duration
+	samples []syncSample
+}
+
+type syncSample struct {
+	Time   time.Time
+	Height types.BlockHeight
+}
+
+// Add adds an observed height, dropping the observations which fell out of the window.
+func (p *syncProgress) Add(t time.Time, height types.BlockHeight) {
+	p.samples = append(p.samples, syncSample{Time: t, Height: height})
+	cutoff := t.Add(-p.Window)
+	var n int
+	for n < len(p.samples)-1 && p.samples[n].Time.Before(cutoff) {
+		n++
+	}
+	p.samples = p.samples[n:]
+}
+
+// Height returns the last observed height.
+func (p *syncProgress) Height() types.BlockHeight {
+	if len(p.samples) == 0 {
+		return 0
+	}
+	return p.samples[len(p.samples)-1].Height
+}
+
+// Rate returns the amount of blocks synced per second within the window,
+// 0 is returned if no rate can be computed (yet).
+func (p *syncProgress) Rate() float64 {
+	if len(p.samples) < 2 {
+		return 0
+	}
+	first, last := p.samples[0], p.samples[len(p.samples)-1]
+	seconds := last.Time.Sub(first.Time).Seconds()
+	if seconds <= 0 || last.Height <= first.Height {
+		return 0
+	}
+	return float64(last.Height-first.Height) / seconds
+}
+
+// ETA returns the estimated time remaining until the given height is reached,
+// false is returned if it cannot be estimated as no progress was made within the window.
+func (p *syncProgress) ETA(target types.BlockHeight) (time.Duration, bool) {
+	height := p.Height()
+	if height >= target {
+		return 0, true
+	}
+	rate := p.Rate()
+	if rate == 0 {
+		return 0, false
+	}
+	return time.Duration(float64(target-height) / rate * float64(time.Second)), true
+}
+
+// Fraction returns the (estimated) fraction of the chain synced,
+// which is only 1 once the consensus reports that it is synced.
+func (p *syncProgress) Fraction(target types.BlockHeight, synced bool) float64 {
+	if synced {
+		return 1
+	}
+	if target == 0 {
+		return 0
+	}
+	fraction := float64(p.Height()) / float64(target)
+	if fraction > 0.99 {
+		fraction = 0.99
+	}
+	return fraction
+}
+
+// Status returns a single-line summary of the sync progress.
+func (p *syncProgress) Status(target types.BlockHeight, synced bool) string {
+	if synced {
+		return fmt.Sprintf("Height: %d (synced)", p.Height())
+	}
+	eta := "unknown"
+	if d, ok := p.ETA(target); ok {
+		eta = d.Round(time.Second).String()
+	}
+	return fmt.Sprintf("Height: %d/%d (%.2f%%) | %.2f blocks/s | ETA: %s",
+		p.Height(), target, p.Fraction(target, synced)*100, p.Rate(), eta)
+}
+
+// progressBar returns a progress bar of the given width (excluding its brackets),
+// filled for the given fraction.
+func progressBar(width int, fraction float64) string {
+	filled := int(fraction * float64(width))
+	if filled < 0 {
+		filled = 0
+	} else if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", width-filled) + "]"
+}
+
+// isTerminal returns true if the given file is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
 // EstimatedHeightAt returns the estimated block height for the given time.
 // Block height is estimated by calculating the minutes since a known block in
 // the past and dividing by 10 minutes (the block time).
@@ -135,3 +329,103 @@ func (consensusCmd *consensusCmd) transactionCmd(id string) {
 		cli.Die("failed to encode transaction:", err, "; ID:", id)
 	}
 }
+
+// blocksCmd is the handler for the command `rivinec consensus blocks`.
+// Prints the blocks within the given height range, page per page.
+func (consensusCmd *consensusCmd) blocksCmd(cmd *cobra.Command, _ []string) {
+	cfg := consensusCmd.blocksCfg
+	to := types.BlockHeight(math.MaxUint64)
+	if cmd.Flags().Changed("to") {
+		to = types.BlockHeight(cfg.To)
+	}
+	if types.BlockHeight(cfg.From) > to {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "invalid height range: --from height is greater than --to height")
+	}
+
+	var printPage func([]api.ConsensusBlock) error
+	switch cfg.EncodingType {
+	case cli.EncodingTypeJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		printPage = func(blocks []api.ConsensusBlock) error {
+			for _, block := range blocks {
+				if err := encoder.Encode(block); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case cli.EncodingTypeCSV:
+		cw := csv.NewWriter(os.Stdout)
+		if err := cw.Write(blockSummaryHeader); err != nil {
+			cli.Die("failed to print blocks:", err)
+		}
+		printPage = func(blocks []api.ConsensusBlock) error {
+			for _, block := range blocks {
+				if err := cw.Write(blockSummaryRecord(block)); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		header := true
+		printPage = func(blocks []api.ConsensusBlock) error {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			if header {
+				fmt.Fprintln(w, strings.Join(blockSummaryHeader, "\t"))
+				header = false
+			}
+			for _, block := range blocks {
+				fmt.Fprintln(w, strings.Join(blockSummaryRecord(block), "\t"))
+			}
+			return w.Flush()
+		}
+	}
+
+	err := streamBlockRange(consensusCmd.cli.HTTPClient, types.BlockHeight(cfg.From), to, printPage)
+	if err != nil {
+		cli.DieWithError("failed to get blocks:", err)
+	}
+}
+
+// streamBlockRange fetches the blocks within the given (inclusive) height range page per page,
+// passing each page to the given callback as soon as it is received.
+// Pages are requested by moving the start of the range, rather than using an offset,
+// such that blocks accepted while streaming are included until the end of the range is reached.
+func streamBlockRange(client *api.HTTPClient, start, end types.BlockHeight, fn func([]api.ConsensusBlock) error) error {
+	for {
+		call := fmt.Sprintf("/consensus/blocks?start=%d&limit=%d", start, api.MaxPaginationLimit)
+		if end != math.MaxUint64 {
+			call += fmt.Sprintf("&end=%d", end)
+		}
+		var resp api.ConsensusGetBlocks
+		if err := client.GetAPI(call, &resp); err != nil {
+			return err
+		}
+		if len(resp.Blocks) == 0 {
+			return nil
+		}
+		if err := fn(resp.Blocks); err != nil {
+			return err
+		}
+		last := resp.Blocks[len(resp.Blocks)-1].Height
+		if last >= end || uint64(len(resp.Blocks)) < resp.Limit {
+			return nil
+		}
+		start = last + 1
+	}
+}
+
+// blockSummaryHeader is the header of the block summaries printed by `rivinec consensus blocks`
+var blockSummaryHeader = []string{"height", "id", "timestamp", "transactions"}
+
+// blockSummaryRecord returns the summary of a block, as printed by `rivinec consensus blocks`.
+func blockSummaryRecord(block api.ConsensusBlock) []string {
+	return []string{
+		strconv.FormatUint(uint64(block.Height), 10),
+		block.ID.String(),
+		time.Unix(int64(block.Block.Timestamp), 0).UTC().Format(time.RFC3339),
+		strconv.Itoa(len(block.Block.Transactions)),
+	}
+}
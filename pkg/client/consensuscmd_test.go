@@ -1,9 +1,16 @@
 package client
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/threefoldtech/rivine/pkg/api"
 	"github.com/threefoldtech/rivine/types"
 )
 
@@ -59,3 +66,115 @@ func TestEstimatedHeightBetween(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamBlockRange(t *testing.T) {
+	const height = 1200
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start, _ := strconv.ParseUint(req.FormValue("start"), 10, 64)
+		end := uint64(height)
+		if str := req.FormValue("end"); str != "" {
+			if n, _ := strconv.ParseUint(str, 10, 64); n < end {
+				end = n
+			}
+		}
+		limit, _ := strconv.ParseUint(req.FormValue("limit"), 10, 64)
+		resp := api.ConsensusGetBlocks{PageInfo: api.PageInfo{Limit: limit}}
+		for h := start; h <= end && uint64(len(resp.Blocks)) < limit; h++ {
+			resp.Blocks = append(resp.Blocks, api.ConsensusBlock{Height: types.BlockHeight(h)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := &api.HTTPClient{RootURL: server.URL}
+
+	testCases := []struct {
+		Start, End    types.BlockHeight
+		Pages, Blocks int
+	}{
+		{3, 1100, 3, 1098},
+		{0, math.MaxUint64, 3, height + 1},
+		{1000, 1499, 1, 201},
+		{5, 5, 1, 1},
+	}
+	for idx, testCase := range testCases {
+		var pages, blocks int
+		next := testCase.Start
+		err := streamBlockRange(client, testCase.Start, testCase.End, func(page []api.ConsensusBlock) error {
+			pages++
+			for _, block := range page {
+				if block.Height != next {
+					return fmt.Errorf("unexpected block height %d, expected %d", block.Height, next)
+				}
+				next++
+				blocks++
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("#%d: %v", idx, err)
+			continue
+		}
+		if pages != testCase.Pages || blocks != testCase.Blocks {
+			t.Errorf("#%d: unexpected amount of pages (%d != %d) or blocks (%d != %d)",
+				idx, pages, testCase.Pages, blocks, testCase.Blocks)
+		}
+	}
+}
+
+func TestSyncProgress(t *testing.T) {
+	progress := syncProgress{Window: time.Minute}
+	start := time.Unix(1000, 0)
+	if _, ok := progress.ETA(100); ok {
+		t.Error("expected the ETA to be unknown without observations")
+	}
+	progress.Add(start, 10)
+	if rate := progress.Rate(); rate != 0 {
+		t.Errorf("expected no rate for a single observation, got %f", rate)
+	}
+	progress.Add(start.Add(10*time.Second), 30)
+	if rate := progress.Rate(); rate != 2 {
+		t.Errorf("unexpected rate: %f", rate)
+	}
+	if eta, ok := progress.ETA(130); !ok || eta != 50*time.Second {
+		t.Errorf("unexpected ETA: %v (%v)", eta, ok)
+	}
+	// the first observation falls out of the window
+	progress.Add(start.Add(65*time.Second), 140)
+	if rate := progress.Rate(); rate != 2 {
+		t.Errorf("unexpected rate: %f", rate)
+	}
+	if eta, ok := progress.ETA(130); !ok || eta != 0 {
+		t.Errorf("unexpected ETA: %v (%v)", eta, ok)
+	}
+	if fraction := progress.Fraction(280, false); fraction != 0.5 {
+		t.Errorf("unexpected fraction: %f", fraction)
+	}
+	if fraction := progress.Fraction(140, false); fraction != 0.99 {
+		t.Errorf("unexpected fraction: %f", fraction)
+	}
+	if fraction := progress.Fraction(140, true); fraction != 1 {
+		t.Errorf("unexpected fraction: %f", fraction)
+	}
+	const expectedStatus = "Height: 140/280 (50.00%) | 2.00 blocks/s | ETA: 1m10s"
+	if status := progress.Status(280, false); status != expectedStatus {
+		t.Errorf("unexpected status: %q", status)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	for idx, testCase := range []struct {
+		Width    int
+		Fraction float64
+		Expected string
+	}{
+		{4, 0, "[    ]"},
+		{4, 0.5, "[==  ]"},
+		{4, 1, "[====]"},
+		{4, 2, "[====]"},
+		{4, -1, "[    ]"},
+	} {
+		if bar := progressBar(testCase.Width, testCase.Fraction); bar != testCase.Expected {
+			t.Errorf("#%d: unexpected progress bar: %q != %q", idx, bar, testCase.Expected)
+		}
+	}
+}
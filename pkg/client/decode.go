@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// transaction encodings supported by the decode command
+const (
+	TransactionEncodingJSON = "json"
+	// TransactionEncodingBinary is the binary encoding of a transaction,
+	// which is defined by the controller of its version and is the same for siabin and rivbin
+	TransactionEncodingBinary = "binary"
+)
+
+func createDecodeCmd(client *CommandLineClient) *cobra.Command {
+	decodeCmd := &decodeCmd{cli: client}
+
+	// create root decode command and all subs
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "decode",
+			Short: "Decode raw objects",
+			Long:  "Decode raw objects locally, printing them in a human-readable format.",
+			// Run field is not set, as the decode command itself is not a valid command.
+			// A subcommand must be provided.
+		}
+		txCmd = &cobra.Command{
+			Use:   "tx <hex|json>",
+			Short: "Decode a raw transaction",
+			Long: `Decode a raw transaction, given as JSON or hex-encoded binary (rivbin or siabin),
+and print a breakdown of its version, inputs, outputs, fees and data.
+The encoding of the transaction is detected automatically,
+and the transaction is read from STDIN if "-" is given as argument.
+
+The transaction is decoded locally, without requiring a daemon.
+`,
+			Run: Wrap(decodeCmd.txCmd),
+		}
+	)
+	rootCmd.AddCommand(txCmd)
+
+	// create flags
+	txCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &decodeCmd.txCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	// return root command
+	return rootCmd
+}
+
+type decodeCmd struct {
+	cli   *CommandLineClient
+	txCfg struct {
+		EncodingType cli.EncodingType
+	}
+}
+
+// DecodedTransaction is a transaction decoded by the decode command,
+// printed as JSON when the JSON encoding type is used.
+type DecodedTransaction struct {
+	// Encoding is the detected encoding of the raw transaction (json or binary)
+	Encoding    string              `json:"encoding"`
+	ID          types.TransactionID `json:"id"`
+	Transaction types.Transaction   `json:"transaction"`
+}
+
+// txCmd is the handler for the command `rivinec decode tx`,
+// decoding a raw transaction and printing its breakdown.
+func (cmd *decodeCmd) txCmd(raw string) {
+	if raw == "-" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			cli.Die("Failed to read the transaction from STDIN:", err)
+		}
+		raw = string(b)
+	}
+	txn, encoding, err := DecodeTransaction(raw)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "Failed to decode transaction:", err)
+	}
+	decoded := DecodedTransaction{
+		Encoding:    encoding,
+		ID:          txn.ID(),
+		Transaction: txn,
+	}
+
+	switch cmd.txCfg.EncodingType {
+	case cli.EncodingTypeJSON:
+		json.NewEncoder(os.Stdout).Encode(decoded)
+	default:
+		printDecodedTransaction(os.Stdout, decoded, cmd.cli.CreateCurrencyConvertor())
+	}
+}
+
+// DecodeTransaction decodes a raw transaction, given as JSON or hex-encoded binary (rivbin or siabin),
+// returning the decoded transaction and the encoding it was decoded from.
+func DecodeTransaction(raw string) (types.Transaction, string, error) {
+	var txn types.Transaction
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "{") {
+		err := json.Unmarshal([]byte(raw), &txn)
+		return txn, TransactionEncodingJSON, err
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+	if err != nil || len(b) == 0 {
+		return txn, "", errors.New("transaction is neither JSON nor hex-encoded")
+	}
+	r := bytes.NewReader(b)
+	if err = rivbin.NewDecoder(r).Decode(&txn); err != nil {
+		return types.Transaction{}, "", err
+	}
+	if r.Len() != 0 {
+		return types.Transaction{}, "", fmt.Errorf("binary transaction has %d trailing bytes", r.Len())
+	}
+	return txn, TransactionEncodingBinary, nil
+}
+
+// printDecodedTransaction prints a human-readable breakdown of the given decoded transaction.
+func printDecodedTransaction(w io.Writer, decoded DecodedTransaction, cc CurrencyConvertor) {
+	txn := decoded.Transaction
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "Transaction ID:\t%s\n", decoded.ID.String())
+	fmt.Fprintf(tw, "Encoding:\t%s\n", decoded.Encoding)
+	fmt.Fprintf(tw, "Version:\t%d\n", txn.Version)
+
+	fmt.Fprintf(tw, "\nCoin Inputs:\t%d\n", len(txn.CoinInputs))
+	for idx, ci := range txn.CoinInputs {
+		fmt.Fprintf(tw, "  #%d parent ID:\t%s\n", idx, ci.ParentID.String())
+		printFulfillment(tw, ci.Fulfillment)
+	}
+	fmt.Fprintf(tw, "\nCoin Outputs:\t%d\n", len(txn.CoinOutputs))
+	for idx, co := range txn.CoinOutputs {
+		fmt.Fprintf(tw, "  #%d ID:\t%s\n", idx, txn.CoinOutputID(uint64(idx)).String())
+		fmt.Fprintf(tw, "     value:\t%s\n", cc.ToCoinStringWithUnit(co.Value))
+		printCondition(tw, co.Condition)
+	}
+	fmt.Fprintf(tw, "\nBlock Stake Inputs:\t%d\n", len(txn.BlockStakeInputs))
+	for idx, bsi := range txn.BlockStakeInputs {
+		fmt.Fprintf(tw, "  #%d parent ID:\t%s\n", idx, bsi.ParentID.String())
+		printFulfillment(tw, bsi.Fulfillment)
+	}
+	fmt.Fprintf(tw, "\nBlock Stake Outputs:\t%d\n", len(txn.BlockStakeOutputs))
+	for idx, bso := range txn.BlockStakeOutputs {
+		fmt.Fprintf(tw, "  #%d ID:\t%s\n", idx, txn.BlockStakeOutputID(uint64(idx)).String())
+		fmt.Fprintf(tw, "     value:\t%s BS\n", bso.Value.String())
+		printCondition(tw, bso.Condition)
+	}
+
+	fmt.Fprintf(tw, "\nMiner Fees:\t%d\n", len(txn.MinerFees))
+	for idx, fee := range txn.MinerFees {
+		fmt.Fprintf(tw, "  #%d\t%s\n", idx, cc.ToCoinStringWithUnit(fee))
+	}
+
+	if len(txn.ArbitraryData) != 0 {
+		fmt.Fprintf(tw, "\nArbitrary Data:\t%d bytes\n", len(txn.ArbitraryData))
+		if data, err := types.DecodeArbitraryData(txn.ArbitraryData); err != nil {
+			fmt.Fprintf(tw, "  invalid:\t%v\n", err)
+		} else {
+			fmt.Fprintf(tw, "  type:\t%s\n", data.Type.String())
+			if utf8.Valid(data.Payload) {
+				fmt.Fprintf(tw, "  payload:\t%q\n", data.Payload)
+			} else {
+				fmt.Fprintf(tw, "  payload:\t0x%x\n", data.Payload)
+			}
+		}
+	}
+	if len(txn.ExtensionData) != 0 {
+		fmt.Fprintf(tw, "\nExtension Data:\t%d bytes\n", len(txn.ExtensionData))
+		fmt.Fprintf(tw, "  raw:\t0x%x\n", txn.ExtensionData)
+	}
+	if txn.Extension != nil {
+		b, _ := json.Marshal(txn.Extension)
+		fmt.Fprintf(tw, "\nExtension:\t%s\n", goTypeName(txn.Extension))
+		fmt.Fprintf(tw, "  data:\t%s\n", b)
+	}
+}
+
+// printCondition prints the type, unlock hash and data of an output condition.
+func printCondition(w io.Writer, condition types.UnlockConditionProxy) {
+	fmt.Fprintf(w, "     condition:\t%s (type %d)\n", goTypeName(condition.Condition), condition.ConditionType())
+	if uh := condition.UnlockHash(); uh.Type != types.UnlockTypeNil {
+		fmt.Fprintf(w, "     unlock hash:\t%s\n", uh.String())
+	}
+	if condition.Condition != nil {
+		b, _ := json.Marshal(condition.Condition)
+		fmt.Fprintf(w, "     data:\t%s\n", b)
+	}
+}
+
+// printFulfillment prints the type and data of an input fulfillment.
+func printFulfillment(w io.Writer, fulfillment types.UnlockFulfillmentProxy) {
+	fmt.Fprintf(w, "     fulfillment:\t%s (type %d)\n", goTypeName(fulfillment.Fulfillment), fulfillment.FulfillmentType())
+	if fulfillment.Fulfillment != nil {
+		b, _ := json.Marshal(fulfillment.Fulfillment)
+		fmt.Fprintf(w, "     data:\t%s\n", b)
+	}
+}
+
+// goTypeName returns the name of the Go type of the given value, without package and pointer prefix.
+func goTypeName(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	name := fmt.Sprintf("%T", v)
+	return name[strings.LastIndex(name, ".")+1:]
+}
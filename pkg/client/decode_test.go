@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	txn := types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinInputs: []types.CoinInput{{
+			ParentID:    types.CoinOutputID{1},
+			Fulfillment: types.NewFulfillment(&types.NilFulfillment{}),
+		}},
+		CoinOutputs: []types.CoinOutput{{
+			Value:     types.NewCurrency64(42),
+			Condition: types.NewCondition(types.NewUnlockHashCondition(types.UnlockHash{Type: types.UnlockTypePubKey, Hash: [32]byte{2}})),
+		}},
+		MinerFees:     []types.Currency{types.NewCurrency64(1)},
+		ArbitraryData: []byte("decode"),
+	}
+	jsonTxn, err := json.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rivbinTxn, err := rivbin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	siabinTxn, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Raw      string
+		Encoding string
+	}{
+		{string(jsonTxn), TransactionEncodingJSON},
+		{hex.EncodeToString(rivbinTxn), TransactionEncodingBinary},
+		{"0x" + hex.EncodeToString(rivbinTxn) + "\n", TransactionEncodingBinary},
+		{hex.EncodeToString(siabinTxn), TransactionEncodingBinary},
+	}
+	for idx, testCase := range testCases {
+		decoded, encoding, err := DecodeTransaction(testCase.Raw)
+		if err != nil {
+			t.Errorf("#%d: failed to decode transaction: %v", idx, err)
+			continue
+		}
+		if encoding != testCase.Encoding {
+			t.Errorf("#%d: unexpected encoding: %s != %s", idx, encoding, testCase.Encoding)
+		}
+		if decoded.ID() != txn.ID() {
+			t.Errorf("#%d: unexpected transaction: %v", idx, decoded)
+		}
+	}
+
+	for _, raw := range []string{"", "not a transaction", hex.EncodeToString(append(rivbinTxn, 0))} {
+		if _, _, err = DecodeTransaction(raw); err == nil {
+			t.Errorf("decoding %q should fail", raw)
+		}
+	}
+}
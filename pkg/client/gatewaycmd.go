@@ -1,12 +1,17 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"text/tabwriter"
+	"time"
 
 	"github.com/threefoldtech/rivine/pkg/api"
 	"github.com/threefoldtech/rivine/pkg/cli"
+	clipkg "github.com/threefoldtech/rivine/pkg/cli"
 	"github.com/spf13/cobra"
 )
 
@@ -45,20 +50,78 @@ func createGatewayCmd(cli *CommandLineClient) *cobra.Command {
 			Long:  "View the current peer list.",
 			Run:   Wrap(gatewayCmd.listPeersCmd),
 		}
+		peerStatsCmd = &cobra.Command{
+			Use:   "peer-stats",
+			Short: "View the statistics of the connected peers",
+			Long:  "View for each connected peer how long it is connected and how many bytes were exchanged with it.",
+			Run:   Wrap(gatewayCmd.peerStatsCmd),
+		}
+		banCmd = &cobra.Command{
+			Use:   "ban [address]",
+			Short: "Ban a peer",
+			Long: `Disconnect from all peers of the host (IP address) of the given address,
+and refuse any connection with that host, permanently or for the given --duration.`,
+			Run: Wrap(gatewayCmd.banCmd),
+		}
+		unbanCmd = &cobra.Command{
+			Use:   "unban [address]",
+			Short: "Lift the ban of a peer",
+			Long:  "Lift the ban of the host (IP address) of the given address.",
+			Run:   Wrap(gatewayCmd.unbanCmd),
+		}
+		listBansCmd = &cobra.Command{
+			Use:   "list-bans",
+			Short: "View a list of banned peers",
+			Long:  "View all banned hosts, with the time at which their ban expires.",
+			Run:   Wrap(gatewayCmd.listBansCmd),
+		}
 	)
 	rootCmd.AddCommand(
 		connectCmd,
 		disconnectCmd,
 		addressCmd,
 		listPeersCmd,
+		peerStatsCmd,
+		banCmd,
+		unbanCmd,
+		listBansCmd,
 	)
 
+	// create flags
+	listPeersCmd.Flags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &gatewayCmd.listPeersCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV))
+	peerStatsCmd.Flags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &gatewayCmd.peerStatsCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeJSON), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeJSON))
+	banCmd.Flags().DurationVar(
+		&gatewayCmd.banCfg.Duration, "duration", 0,
+		"duration of the ban (e.g. 24h), the ban is permanent if not defined")
+	banCmd.Flags().StringVar(
+		&gatewayCmd.banCfg.Reason, "reason", "", "optional reason of the ban, stored with the ban")
+	listBansCmd.Flags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &gatewayCmd.listBansCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeJSON), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeJSON))
+
 	// return root command
 	return rootCmd
 }
 
 type gatewayCmd struct {
-	cli *CommandLineClient
+	cli          *CommandLineClient
+	listPeersCfg struct {
+		EncodingType cli.EncodingType
+	}
+	peerStatsCfg struct {
+		EncodingType cli.EncodingType
+	}
+	banCfg struct {
+		Duration time.Duration
+		Reason   string
+	}
+	listBansCfg struct {
+		EncodingType cli.EncodingType
+	}
 }
 
 // connectCmd is the handler for the command `gateway add [address]`.
@@ -112,6 +175,17 @@ func (gatewayCmd *gatewayCmd) listPeersCmd() {
 	if err != nil {
 		cli.Die("Could not get peer list:", err)
 	}
+	if gatewayCmd.listPeersCfg.EncodingType == cli.EncodingTypeCSV {
+		records := make([][]string, 0, len(info.Peers))
+		for _, peer := range info.Peers {
+			records = append(records, []string{
+				peer.Version.String(), strconv.FormatBool(!peer.Inbound), string(peer.NetAddress)})
+		}
+		if err = cli.WriteCSV(os.Stdout, []string{"version", "outbound", "address"}, records); err != nil {
+			cli.Die("Failed to write peer list as CSV:", err)
+		}
+		return
+	}
 	if len(info.Peers) == 0 {
 		fmt.Println("No peers to show.")
 		return
@@ -124,3 +198,105 @@ func (gatewayCmd *gatewayCmd) listPeersCmd() {
 	}
 	w.Flush()
 }
+
+// peerStatsCmd is the handler for the command `gateway peer-stats`.
+// Prints the statistics of all connected peers.
+func (gatewayCmd *gatewayCmd) peerStatsCmd() {
+	var info api.GatewayPeersGET
+	err := gatewayCmd.cli.GetAPI("/gateway/peers", &info)
+	if err != nil {
+		cli.Die("Could not get peer statistics:", err)
+	}
+	if gatewayCmd.peerStatsCfg.EncodingType == cli.EncodingTypeJSON {
+		json.NewEncoder(os.Stdout).Encode(info.Peers)
+		return
+	}
+	if len(info.Peers) == 0 {
+		fmt.Println("No peers to show.")
+		return
+	}
+	now := time.Now()
+	fmt.Println(len(info.Peers), "active peers:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Address\tVersion\tOutbound\tConnected\tSent\tReceived")
+	for _, peer := range info.Peers {
+		connected := now.Sub(time.Unix(peer.ConnectedSince, 0)).Round(time.Second)
+		fmt.Fprintf(w, "%v\t%s\t%v\t%v\t%s\t%s\n", peer.NetAddress, peer.Version, YesNo(!peer.Inbound),
+			connected, formatByteSize(peer.BytesSent), formatByteSize(peer.BytesReceived))
+	}
+	w.Flush()
+}
+
+// banCmd is the handler for the command `gateway ban [address]`.
+// Bans the host of a peer.
+func (gatewayCmd *gatewayCmd) banCmd(addr string) {
+	values := url.Values{}
+	if gatewayCmd.banCfg.Duration != 0 {
+		values.Set("duration", gatewayCmd.banCfg.Duration.String())
+	}
+	if gatewayCmd.banCfg.Reason != "" {
+		values.Set("reason", gatewayCmd.banCfg.Reason)
+	}
+	err := gatewayCmd.cli.Post("/gateway/ban/"+addr, values.Encode())
+	if err != nil {
+		cli.Die("Could not ban peer:", err)
+	}
+	if gatewayCmd.banCfg.Duration == 0 {
+		fmt.Println("Banned", addr, "permanently.")
+		return
+	}
+	fmt.Println("Banned", addr, "for", gatewayCmd.banCfg.Duration.String()+".")
+}
+
+// unbanCmd is the handler for the command `gateway unban [address]`.
+// Lifts the ban of the host of a peer.
+func (gatewayCmd *gatewayCmd) unbanCmd(addr string) {
+	err := gatewayCmd.cli.Post("/gateway/unban/"+addr, "")
+	if err != nil {
+		cli.Die("Could not unban peer:", err)
+	}
+	fmt.Println("Unbanned", addr+".")
+}
+
+// listBansCmd is the handler for the command `gateway list-bans`.
+// Prints a list of all banned hosts.
+func (gatewayCmd *gatewayCmd) listBansCmd() {
+	var info api.GatewayBansGET
+	err := gatewayCmd.cli.GetAPI("/gateway/bans", &info)
+	if err != nil {
+		cli.Die("Could not get ban list:", err)
+	}
+	if gatewayCmd.listBansCfg.EncodingType == cli.EncodingTypeJSON {
+		json.NewEncoder(os.Stdout).Encode(info.Bans)
+		return
+	}
+	if len(info.Bans) == 0 {
+		fmt.Println("No banned peers.")
+		return
+	}
+	fmt.Println(len(info.Bans), "banned hosts:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Host\tExpires\tReason")
+	for _, ban := range info.Bans {
+		expires := "never"
+		if ban.Expires != 0 {
+			expires = time.Unix(ban.Expires, 0).Format(time.RFC822)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", ban.Host, expires, ban.Reason)
+	}
+	w.Flush()
+}
+
+// formatByteSize formats the given amount of bytes in a human-readable form, using binary prefixes.
+func formatByteSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,22 @@
+package client
+
+import "testing"
+
+func TestFormatByteSize(t *testing.T) {
+	testCases := []struct {
+		n        uint64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 << 40, "3.0 TiB"},
+	}
+	for _, testCase := range testCases {
+		if str := formatByteSize(testCase.n); str != testCase.expected {
+			t.Errorf("%d: expected %q, not %q", testCase.n, testCase.expected, str)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func createGenerateCmd(client *CommandLineClient) *cobra.Command {
+	generateCmd := &generateCmd{cli: client}
+
+	// create root generate command and all subs
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "generate",
+			Short: "Generate seeds and key pairs",
+			Long: `Generate seeds and key pairs locally, without requiring a daemon,
+e.g. to provision genesis allocations or cold wallets on an offline machine.`,
+			// Run field is not set, as the generate command itself is not a valid command.
+			// A subcommand must be provided.
+		}
+		seedCmd = &cobra.Command{
+			Use:   "seed",
+			Short: "Generate a new seed",
+			Long: `Generate a new random seed, printing its mnemonic,
+together with the public keys and addresses of its first key pairs,
+derived the same way the wallet derives them.
+The mnemonic can be loaded into a wallet at any time, using the wallet recover or wallet load seed command.
+`,
+			Run: Wrap(generateCmd.seedCmd),
+		}
+		keyPairCmd = &cobra.Command{
+			Use:   "keypair",
+			Short: "Generate a new key pair",
+			Long: `Generate a new key pair, printing its secret key, public key and address.
+The key pair is derived from a new random seed, of which the mnemonic is printed as well,
+such that the key pair can be recovered (or loaded into a wallet) at any time.
+When a seed is given using the --seed flag, the key pair at the given --index is derived from that seed instead.
+`,
+			Run: Wrap(generateCmd.keyPairCmd),
+		}
+	)
+	rootCmd.AddCommand(seedCmd, keyPairCmd)
+
+	// create flags
+	seedCmd.Flags().Uint64Var(
+		&generateCmd.seedCfg.KeyCount, "keys", 1,
+		"amount of key pairs (starting from the first) of which to print the public key and address")
+	seedCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &generateCmd.seedCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	keyPairCmd.Flags().StringVar(
+		&generateCmd.keyPairCfg.Seed, "seed", "",
+		"mnemonic of the seed to derive the key pair from, instead of generating a new seed")
+	keyPairCmd.Flags().Uint64Var(
+		&generateCmd.keyPairCfg.Index, "index", 0,
+		"index of the key pair to derive from the seed")
+	keyPairCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &generateCmd.keyPairCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	// return root command
+	return rootCmd
+}
+
+type generateCmd struct {
+	cli     *CommandLineClient
+	seedCfg struct {
+		KeyCount     uint64
+		EncodingType cli.EncodingType
+	}
+	keyPairCfg struct {
+		Seed         string
+		Index        uint64
+		EncodingType cli.EncodingType
+	}
+}
+
+// GeneratedSeed is a seed generated by the CLI client,
+// printed as JSON when the JSON encoding type is used.
+type GeneratedSeed struct {
+	Mnemonic string `json:"mnemonic"`
+	// Seed is the hex-encoded seed
+	Seed string         `json:"seed"`
+	Keys []GeneratedKey `json:"keys"`
+}
+
+// GeneratedKey is a key pair derived from a seed by the CLI client,
+// printed as JSON when the JSON encoding type is used.
+type GeneratedKey struct {
+	// Mnemonic is only defined in case the seed was generated together with the key pair
+	Mnemonic string `json:"mnemonic,omitempty"`
+	Index    uint64 `json:"index"`
+	// SecretKey is only defined for a generated key pair, not for the keys of a generated seed
+	SecretKey  types.ByteSlice  `json:"secretkey,omitempty"`
+	PublicKey  types.PublicKey  `json:"publickey"`
+	UnlockHash types.UnlockHash `json:"unlockhash"`
+	// Bech32 is the bech32 format of the address,
+	// only defined in case a bech32 address prefix is configured for the chain
+	Bech32 string `json:"bech32,omitempty"`
+}
+
+// deriveGeneratedKey derives the key pair at the given index of the given seed,
+// including the secret key only if requested.
+func deriveGeneratedKey(seed modules.Seed, index uint64, withSecretKey bool, bech32Prefix string) (GeneratedKey, error) {
+	sk, pk, err := seed.SpendableKey(index)
+	if err != nil {
+		return GeneratedKey{}, err
+	}
+	key := GeneratedKey{
+		Index:     index,
+		PublicKey: types.Ed25519PublicKey(pk),
+	}
+	if withSecretKey {
+		key.SecretKey = types.ByteSlice(sk[:])
+	}
+	key.UnlockHash, err = types.NewPubKeyUnlockHash(key.PublicKey)
+	if err != nil {
+		return GeneratedKey{}, err
+	}
+	if bech32Prefix != "" {
+		key.Bech32, err = key.UnlockHash.Bech32String(bech32Prefix)
+		if err != nil {
+			return GeneratedKey{}, err
+		}
+	}
+	return key, nil
+}
+
+// newRandomSeed generates a new random seed, returning it together with its mnemonic.
+func newRandomSeed() (modules.Seed, string, error) {
+	var seed modules.Seed
+	if _, err := rand.Read(seed[:]); err != nil {
+		return modules.Seed{}, "", err
+	}
+	mnemonic, err := modules.NewMnemonic(seed)
+	return seed, mnemonic, err
+}
+
+// seedCmd is the handler for the command `rivinec generate seed`,
+// generating a new random seed.
+func (cmd *generateCmd) seedCmd() {
+	if cmd.seedCfg.KeyCount == 0 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "at least one key pair has to be printed (--keys)")
+	}
+	seed, mnemonic, err := newRandomSeed()
+	if err != nil {
+		cli.Die("Failed to generate a seed:", err)
+	}
+	generated := GeneratedSeed{
+		Mnemonic: mnemonic,
+		Seed:     hex.EncodeToString(seed[:]),
+		Keys:     make([]GeneratedKey, 0, cmd.seedCfg.KeyCount),
+	}
+	for index := uint64(0); index < cmd.seedCfg.KeyCount; index++ {
+		key, err := deriveGeneratedKey(seed, index, false, cmd.cli.Config.Bech32AddressPrefix)
+		if err != nil {
+			cli.Die("Failed to derive the keys of the seed:", err)
+		}
+		generated.Keys = append(generated.Keys, key)
+	}
+
+	switch cmd.seedCfg.EncodingType {
+	case cli.EncodingTypeJSON:
+		json.NewEncoder(os.Stdout).Encode(generated)
+	default:
+		printGeneratedSeed(os.Stdout, generated)
+	}
+}
+
+// keyPairCmd is the handler for the command `rivinec generate keypair`,
+// generating a new key pair, or deriving it from an existing seed.
+func (cmd *generateCmd) keyPairCmd() {
+	var (
+		seed     modules.Seed
+		mnemonic string
+		err      error
+	)
+	if cmd.keyPairCfg.Seed != "" {
+		seed, err = modules.InitialSeedFromMnemonic(cmd.keyPairCfg.Seed)
+		if err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid mnemonic given:", err)
+		}
+	} else {
+		seed, mnemonic, err = newRandomSeed()
+		if err != nil {
+			cli.Die("Failed to generate a seed:", err)
+		}
+	}
+	key, err := deriveGeneratedKey(seed, cmd.keyPairCfg.Index, true, cmd.cli.Config.Bech32AddressPrefix)
+	if err != nil {
+		cli.Die("Failed to derive the key pair:", err)
+	}
+	key.Mnemonic = mnemonic
+
+	switch cmd.keyPairCfg.EncodingType {
+	case cli.EncodingTypeJSON:
+		json.NewEncoder(os.Stdout).Encode(key)
+	default:
+		printGeneratedKey(os.Stdout, key)
+	}
+}
+
+// printGeneratedSeed prints the given generated seed in a human-readable format.
+func printGeneratedSeed(w io.Writer, seed GeneratedSeed) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Mnemonic:\t%s\n", seed.Mnemonic)
+	fmt.Fprintf(tw, "Seed:\t%s\n", seed.Seed)
+	for _, key := range seed.Keys {
+		fmt.Fprintf(tw, "Key #%d:\t%s\n", key.Index, key.PublicKey.String())
+		fmt.Fprintf(tw, "  address:\t%s\n", key.UnlockHash.String())
+		if key.Bech32 != "" {
+			fmt.Fprintf(tw, "  bech32:\t%s\n", key.Bech32)
+		}
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "Store the mnemonic safely, as anyone knowing it can spend the funds of all its addresses.")
+}
+
+// printGeneratedKey prints the given generated key pair in a human-readable format.
+func printGeneratedKey(w io.Writer, key GeneratedKey) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if key.Mnemonic != "" {
+		fmt.Fprintf(tw, "Mnemonic:\t%s\n", key.Mnemonic)
+	}
+	fmt.Fprintf(tw, "Index:\t%d\n", key.Index)
+	fmt.Fprintf(tw, "Secret key:\t%s\n", key.SecretKey.String())
+	fmt.Fprintf(tw, "Public key:\t%s\n", key.PublicKey.String())
+	fmt.Fprintf(tw, "Address:\t%s\n", key.UnlockHash.String())
+	if key.Bech32 != "" {
+		fmt.Fprintf(tw, "Bech32:\t%s\n", key.Bech32)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "Store the secret key (and mnemonic) safely, as anyone knowing it can spend the funds of its address.")
+}
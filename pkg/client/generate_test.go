@@ -0,0 +1,41 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+)
+
+func TestDeriveGeneratedKey(t *testing.T) {
+	const mnemonic = "carbon boss inject cover mountain fetch fiber fit tornado cloth wing dinosaur proof joy intact fabric thumb rebel borrow poet chair network expire else"
+	seed, err := modules.InitialSeedFromMnemonic(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := deriveGeneratedKey(seed, 0, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedAddress = "015df22a2e82a3323bc6ffbd1730450ed844feca711c8fe0c15e218c171962fd17b206263220ee"
+	if address := key.UnlockHash.String(); address != expectedAddress {
+		t.Errorf("unexpected address: %s != %s", address, expectedAddress)
+	}
+	if len(key.SecretKey) != 0 || key.Bech32 != "" {
+		t.Errorf("unexpected secret key or bech32 address: %v, %s", key.SecretKey, key.Bech32)
+	}
+
+	key, err = deriveGeneratedKey(seed, 1, true, "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Index != 1 || key.UnlockHash.String() == expectedAddress {
+		t.Errorf("unexpected key at index 1: %d, %s", key.Index, key.UnlockHash.String())
+	}
+	if len(key.SecretKey) != 64 || key.Bech32 == "" {
+		t.Errorf("missing secret key or bech32 address: %v, %s", key.SecretKey, key.Bech32)
+	}
+	if pk := []byte(key.SecretKey[32:]); string(pk) != string(key.PublicKey.Key) {
+		t.Error("secret key does not match public key")
+	}
+}
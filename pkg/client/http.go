@@ -1,11 +1,26 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"regexp"
 	"strings"
 )
 
+// unixSocketScheme is the scheme used to communicate with a daemon
+// over a unix domain socket, e.g. unix:///var/lib/rivine/api.sock
+const unixSocketScheme = "unix://"
+
+// unixSocketRootURL is the root URL used for all requests made over a unix domain socket,
+// the host being ignored as all connections are made to the socket
+const unixSocketRootURL = "http://unix"
+
 var (
 	urlSchemeSplitter   = regexp.MustCompile(`^(https?://)?(.+)$`)
 	urlLocalHostMatcher = regexp.MustCompile(`^(localhost|127\.0\.0\.1)?(\:[0-9]{1,5})?$`)
@@ -33,3 +48,51 @@ func sanitizeURL(url string) (string, error) {
 	}
 	return strings.Join(parts[1:], ""), nil
 }
+
+// configureUnixSocket configures all HTTP requests to be made over the unix domain socket at the given path.
+func configureUnixSocket(path string) error {
+	if path == "" {
+		return errors.New("invalid unix socket address: no path given")
+	}
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("cannot configure unix socket: unexpected default HTTP transport")
+	}
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+	return nil
+}
+
+// configureTLS configures the TLS settings used to communicate with the daemon over https,
+// trusting the given certificate next to the system ones, or not verifying the certificate at all.
+func configureTLS(certFile string, skipVerify bool) error {
+	if certFile == "" && !skipVerify {
+		return nil // use the default settings
+	}
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("cannot configure TLS: unexpected default HTTP transport")
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: skipVerify,
+	}
+	if certFile != "" {
+		pemBytes, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS certificate %q: %v", certFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemBytes) {
+			return fmt.Errorf("failed to parse TLS certificate %q: no PEM-encoded certificate found", certFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
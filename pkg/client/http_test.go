@@ -1,6 +1,12 @@
 package client
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/api"
+)
 
 func TestSanitizeValidURL(t *testing.T) {
 	urls := []struct {
@@ -46,3 +52,75 @@ func TestSanitizeInvalidURL(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPClientFailover(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+		w.Write([]byte(`{"height":42}`))
+	}))
+	defer server.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message":"the consensus routes are disabled"}`))
+	}))
+	defer unavailable.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	client := &api.HTTPClient{
+		RootURL:      down.URL,
+		FailoverURLs: []string{unavailable.URL, server.URL},
+	}
+	var resp struct {
+		Height int `json:"height"`
+	}
+	if err := client.GetAPI("/consensus", &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Height != 42 {
+		t.Errorf("unexpected response: %v", resp)
+	}
+	if client.RootURL != server.URL {
+		t.Errorf("expected the responding daemon to become the root URL, got %s", client.RootURL)
+	}
+	if len(client.FailoverURLs) != 2 || client.FailoverURLs[0] != down.URL || client.FailoverURLs[1] != unavailable.URL {
+		t.Errorf("unexpected failover URLs: %v", client.FailoverURLs)
+	}
+
+	// writes are only retried if the request could not be sent
+	client = &api.HTTPClient{RootURL: unavailable.URL, FailoverURLs: []string{server.URL}}
+	if err := client.Post("/wallet/coins", "{}"); err == nil {
+		t.Error("expected the write to fail without failing over")
+	}
+	client = &api.HTTPClient{RootURL: down.URL, FailoverURLs: []string{server.URL}}
+	if err := client.Post("/wallet/coins", "{}"); err != nil {
+		t.Error(err)
+	}
+	if posts != 1 {
+		t.Errorf("expected 1 write to be received, got %d", posts)
+	}
+}
+
+func TestHTTPClientSelectHealthyDaemon(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health/live" {
+			t.Errorf("unexpected health check path: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"alive":true}`))
+	}))
+	defer server.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	client := &api.HTTPClient{RootURL: down.URL, FailoverURLs: []string{server.URL}}
+	if !client.SelectHealthyDaemon() || client.RootURL != server.URL {
+		t.Errorf("expected the healthy daemon to be selected, got %s", client.RootURL)
+	}
+	client = &api.HTTPClient{RootURL: down.URL, FailoverURLs: []string{down.URL + "/other"}}
+	if client.SelectHealthyDaemon() || client.RootURL != down.URL {
+		t.Errorf("expected the original order to be kept, got %s", client.RootURL)
+	}
+}
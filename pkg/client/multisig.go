@@ -0,0 +1,305 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func createMultisigCmd(client *CommandLineClient) *cobra.Command {
+	multisigCmd := &multisigCmd{cli: client}
+
+	// create root multisig command and all subs
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "multisig",
+			Short: "Create, sign and broadcast multisig transactions",
+			Long: `Operate multisig wallets, by passing a transaction file from one signer to the next.
+
+A transaction is created using the create command, after which the file is signed
+by the signers, one after the other using the sign command, or in parallel,
+combining their signed copies afterwards using the combine command.
+Once sufficient signatures are collected, it can be broadcast using the broadcast command.
+Each command reports which signatures are still missing.
+`,
+			// Run field is not set, as the multisig command itself is not a valid command.
+			// A subcommand must be provided.
+		}
+		createCmd = &cobra.Command{
+			Use:   "create <file> <multisigAddress> <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...",
+			Short: "Create an unsigned transaction file spending from a multisig address",
+			Long: `Create an unsigned coin transaction, funded by the unspent coin outputs of the given multisig address,
+and write it to the given file, together with all information required to sign it.
+The unspent outputs are looked up using the explorer module of the daemon.
+
+Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency or a sub-unit SI prefix only (e.g. 250m).
+Amounts which look like a value expressed in the smallest unit have to be suffixed with the unit of currency explicitly.
+Decimals are possible and have to be defined using the decimal point.
+
+The Minimum Miner Fee will be added on top of the total given amount automatically,
+and the change is sent back to the multisig address, unless another refund address is defined.
+`,
+			Run: multisigCmd.createCmd,
+		}
+		signCmd = &cobra.Command{
+			Use:   "sign <file>",
+			Short: "Sign a multisig transaction file using a seed",
+			Long: `Sign all inputs of the transaction in the given file which are owned by keys of the given seed,
+writing the signed transaction back to the file (or to the file defined by --out),
+and report which signatures are still missing.
+As the keys are derived locally, this command does not communicate with the daemon,
+and can be used on an offline machine. The mnemonic of the seed is read from STDIN,
+unless it is defined using the --seed flag.
+`,
+			Run: Wrap(multisigCmd.signCmd),
+		}
+		combineCmd = &cobra.Command{
+			Use:   "combine <outFile> <file> <file>...",
+			Short: "Combine the signatures of multiple signed copies of a transaction file",
+			Long: `Combine the signatures of multiple copies of the same transaction file,
+each signed by one or more signers in parallel, and write the result to the given output file.
+It reports which signatures are still missing, and does not require a daemon.
+`,
+			Args: cobra.MinimumNArgs(3),
+			Run:  multisigCmd.combineCmd,
+		}
+		statusCmd = &cobra.Command{
+			Use:   "status <file>",
+			Short: "Report the signatures present and missing in a transaction file",
+			Long:  "Report for each input of the transaction in the given file which signatures are present and which are still missing.",
+			Run:   Wrap(multisigCmd.statusCmd),
+		}
+		broadcastCmd = &cobra.Command{
+			Use:   "broadcast <file>",
+			Short: "Broadcast a fully signed multisig transaction file",
+			Long: `Submit the transaction of the given file to the transaction pool of the daemon,
+once sufficient signatures are collected for all its inputs.`,
+			Run: Wrap(multisigCmd.broadcastCmd),
+		}
+	)
+	rootCmd.AddCommand(
+		createCmd,
+		signCmd,
+		combineCmd,
+		statusCmd,
+		broadcastCmd,
+	)
+
+	// create flags
+	createCmd.Flags().StringVar(
+		&multisigCmd.createCfg.RefundAddress,
+		"refund-address", "", "address (or raw condition) to send the change to, defaults to the multisig address")
+	multisigCmd.createCfg.Data.register(createCmd.Flags())
+
+	signCmd.Flags().StringVar(
+		&multisigCmd.signCfg.Seed,
+		"seed", "", "define the mnemonic of the seed as a flag instead of the STDIN")
+	signCmd.Flags().Uint64Var(
+		&multisigCmd.signCfg.KeyDepth,
+		"key-depth", modules.PublicKeysPerSeed, "amount of keys of the seed to derive and sign with")
+	signCmd.Flags().StringVar(
+		&multisigCmd.signCfg.Out,
+		"out", "", "file to write the signed transaction to, instead of overwriting the given file")
+
+	// return root command
+	return rootCmd
+}
+
+type multisigCmd struct {
+	cli       *CommandLineClient
+	createCfg struct {
+		RefundAddress string
+		Data          arbitraryDataFlags
+	}
+	signCfg struct {
+		Seed     string
+		KeyDepth uint64
+		Out      string
+	}
+}
+
+// createCmd is the handler for the command `multisig create`,
+// creating an unsigned transaction file spending the unspent outputs of a multisig address.
+func (cmd *multisigCmd) createCmd(c *cobra.Command, args []string) {
+	if len(args) < 4 || len(args)%2 != 0 {
+		c.UsageFunc()(c)
+		cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid arguments. Arguments must be of the form <file> <multisigAddress> <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...")
+	}
+	path := args[0]
+	var address types.UnlockHash
+	if err := address.LoadAddressString(args[1], cmd.cli.Config.Bech32AddressPrefix); err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "invalid multisig address:", err)
+	}
+	if address.Type != types.UnlockTypeMultiSig {
+		cli.DieWithExitCode(cli.ExitCodeUsage, fmt.Sprintf(
+			"address %s is a %s address, not a multisig address", address.String(), UnlockTypeName(address.Type)))
+	}
+	pairs, err := parsePairedOutputs(args[2:], cmd.cli.CreateCurrencyConvertor().ParseCoinString, cmd.cli.Config.Bech32AddressPrefix)
+	if err != nil {
+		c.UsageFunc()(c)
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
+	// the change is sent back to the multisig condition of the spent outputs by default
+	var refundCondition types.UnlockConditionProxy
+	if cmd.createCfg.RefundAddress != "" {
+		refundCondition, err = parseConditionString(cmd.createCfg.RefundAddress, cmd.cli.Config.Bech32AddressPrefix)
+		if err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "invalid refund address specified:", err)
+		}
+	}
+
+	data, err := cmd.createCfg.Data.ArbitraryData(cmd.cli.Config)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
+
+	file, spent := createUnsignedTransactionFile(cmd.cli, []types.UnlockHash{address}, refundCondition, data, pairs)
+	if err = WriteUnsignedTransactionFile(path, file); err != nil {
+		cli.Die("Failed to write the transaction file:", err)
+	}
+	fmt.Printf("Unsigned transaction spending %d coin output(s) written to %s\n", spent, path)
+	builder, err := file.Builder()
+	if err != nil {
+		cli.Die("Failed to read the created transaction file:", err)
+	}
+	printSignatureStatus(os.Stdout, builder)
+}
+
+// signCmd is the handler for the command `multisig sign`,
+// signing a transaction file using the keys derived from a seed.
+func (cmd *multisigCmd) signCmd(path string) {
+	_, builder := signUnsignedTransactionFile(path, cmd.signCfg.Seed, cmd.signCfg.KeyDepth, cmd.signCfg.Out)
+	printSignatureStatus(os.Stdout, builder)
+}
+
+// combineCmd is the handler for the command `multisig combine`,
+// combining the signatures of multiple signed copies of the same transaction file.
+func (cmd *multisigCmd) combineCmd(_ *cobra.Command, args []string) {
+	out := args[0]
+	file, err := ReadUnsignedTransactionFile(args[1])
+	if err != nil {
+		cli.Die(fmt.Sprintf("Failed to read the transaction file %s:", args[1]), err)
+	}
+	builder, err := file.Builder()
+	if err != nil {
+		cli.Die(fmt.Sprintf("Failed to read the transaction file %s:", args[1]), err)
+	}
+	var merged int
+	for _, path := range args[2:] {
+		other, err := ReadUnsignedTransactionFile(path)
+		if err != nil {
+			cli.Die(fmt.Sprintf("Failed to read the transaction file %s:", path), err)
+		}
+		if other.ChainID != file.ChainID {
+			cli.Die(fmt.Sprintf("Transaction file %s is created for the %s network, while %s is created for the %s network",
+				path, other.NetworkName, args[1], file.NetworkName))
+		}
+		n, err := builder.MergeSignatures(other.Transaction)
+		if err == txbuilder.ErrTransactionMismatch {
+			fmt.Fprintf(os.Stderr, "Transaction of %s differs from the transaction of %s:\n", path, args[1])
+			for _, diff := range txbuilder.DiffUnfulfilled(builder.Transaction(), other.Transaction) {
+				fmt.Fprintf(os.Stderr, "  - %s\n", diff)
+			}
+			cli.Die(fmt.Sprintf("Failed to combine the signatures of %s:", path), err)
+		}
+		if err != nil {
+			cli.Die(fmt.Sprintf("Failed to combine the signatures of %s:", path), err)
+		}
+		merged += n
+	}
+	file.Transaction = builder.Transaction()
+	if err = WriteUnsignedTransactionFile(out, file); err != nil {
+		cli.Die("Failed to write the transaction file:", err)
+	}
+	fmt.Printf("Combined %d signature(s) of %d file(s), written to %s\n", merged, len(args)-2, out)
+	printSignatureStatus(os.Stdout, builder)
+}
+
+// statusCmd is the handler for the command `multisig status`,
+// reporting the signatures present and missing in a transaction file.
+func (cmd *multisigCmd) statusCmd(path string) {
+	file, err := ReadUnsignedTransactionFile(path)
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	builder, err := file.Builder()
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	printSignatureStatus(os.Stdout, builder)
+}
+
+// broadcastCmd is the handler for the command `multisig broadcast`,
+// submitting the transaction of a fully signed transaction file to the transaction pool.
+func (cmd *multisigCmd) broadcastCmd(path string) {
+	file, err := ReadUnsignedTransactionFile(path)
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	builder, err := file.Builder()
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	if !printSignatureStatus(os.Stdout, builder) {
+		cli.Die("Transaction cannot be broadcast, as it is missing signatures")
+	}
+	if err = validateOfflineTx(builder, file); err != nil {
+		cli.Die("Transaction is invalid:", err)
+	}
+	broadcastUnsignedTransactionFile(cmd.cli, file)
+}
+
+// printSignatureStatus prints for each signature-based input of the transaction
+// which signatures are present and which are still missing,
+// returning true if sufficient signatures are present for all inputs.
+func printSignatureStatus(w io.Writer, builder *txbuilder.Builder) bool {
+	complete := true
+	for _, status := range builder.SignatureStatus() {
+		kind := "coin"
+		if status.BlockStake {
+			kind = "block stake"
+		}
+		fmt.Fprintf(w, "%s input #%d (%s %s): %d of %d required signature(s)\n",
+			kind, status.Index, UnlockTypeName(status.UnlockHash.Type), status.UnlockHash.String(),
+			len(status.Signers), status.RequiredSignatures)
+		for _, signer := range status.Signers {
+			fmt.Fprintf(w, "  signed:  %s\n", signer.String())
+		}
+		if status.Complete {
+			continue
+		}
+		complete = false
+		for _, signer := range status.MissingSigners {
+			fmt.Fprintf(w, "  missing: %s\n", signer.String())
+		}
+	}
+	if complete {
+		fmt.Fprintln(w, "All required signatures are present")
+	} else {
+		fmt.Fprintln(w, "Transaction is missing signatures, and has to be signed by (some of) the missing signers")
+	}
+	return complete
+}
+
+// parseConditionString parses an address (hex, or bech32 using the given prefix), or a raw (JSON-encoded) condition.
+func parseConditionString(str, bech32Prefix string) (types.UnlockConditionProxy, error) {
+	var condition types.UnlockConditionProxy
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "{") {
+		err := condition.UnmarshalJSON([]byte(str))
+		return condition, err
+	}
+	var uh types.UnlockHash
+	if err := uh.LoadAddressString(str, bech32Prefix); err != nil {
+		return condition, err
+	}
+	return types.NewCondition(types.NewUnlockHashCondition(uh)), nil
+}
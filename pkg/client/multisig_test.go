@@ -0,0 +1,45 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestPrintSignatureStatus(t *testing.T) {
+	const (
+		address1 = "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+		address2 = "01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e"
+	)
+	condition, err := parseConditionString(`{"type":4,"data":{"unlockhashes":["`+address1+`","`+address2+`"],"minimumsignaturecount":1}}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := txbuilder.New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{1}, types.CoinOutput{Value: types.NewCurrency64(10), Condition: condition}, &types.NilFulfillment{})
+
+	var buf bytes.Buffer
+	if printSignatureStatus(&buf, builder) {
+		t.Error("unsigned multisig input should be reported as incomplete")
+	}
+	output := buf.String()
+	for _, expected := range []string{
+		"coin input #0 (multisig " + condition.UnlockHash().String() + "): 0 of 1 required signature(s)",
+		"missing: " + address1,
+		"missing: " + address2,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+
+	if _, err = parseConditionString(address1, ""); err != nil {
+		t.Error(err)
+	}
+	if _, err = parseConditionString("invalid", ""); err == nil {
+		t.Error("parsing an invalid condition should fail")
+	}
+}
@@ -0,0 +1,458 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// UnsignedTransactionFileVersion is the version of the transaction files written by this client.
+// Files without a version are read as files of version 1, which predate the embedded raw transaction.
+// See doc/transactions/transaction_file.md for the specification of the format.
+const UnsignedTransactionFileVersion = 2
+
+// UnsignedTransactionFile is the content of a transaction file, as created by `wallet create-tx`.
+// Next to the (unsigned or partially signed) transaction, it contains the parent outputs
+// of all its inputs and the chain it was created for, such that it can be signed
+// on an offline machine, without access to a daemon.
+type UnsignedTransactionFile struct {
+	// Version of the file format, UnsignedTransactionFileVersion for files written by this client
+	Version uint8 `json:"version,omitempty"`
+	// NetworkName and ChainID identify the chain (network) the transaction is created for,
+	// the ChainID is required to sign replay-protected transactions
+	NetworkName string        `json:"network"`
+	ChainID     types.ChainID `json:"chainid"`
+	// Height and Timestamp of the current block at the time the transaction was created,
+	// used to validate the (time locked) conditions of the spent outputs when signing
+	Height    types.BlockHeight `json:"height"`
+	Timestamp types.Timestamp   `json:"timestamp"`
+
+	Transaction types.Transaction `json:"transaction"`
+	// RawTransaction is the hex-encoded rivbin encoding of the transaction,
+	// which has to match the (human-readable) JSON-encoded transaction
+	RawTransaction string `json:"rawtransaction,omitempty"`
+	// CoinInputParents and BlockStakeInputParents are the outputs spent by the inputs of the transaction,
+	// indexed the same as those inputs
+	CoinInputParents       []types.CoinOutput       `json:"coininputparents"`
+	BlockStakeInputParents []types.BlockStakeOutput `json:"blockstakeinputparents,omitempty"`
+}
+
+// Validate validates the structure of the transaction file,
+// ensuring its version is supported and its content is consistent.
+func (file *UnsignedTransactionFile) Validate() error {
+	switch file.Version {
+	case 0, 1:
+		if file.RawTransaction != "" {
+			return fmt.Errorf("version %d files do not define a raw transaction", file.Version)
+		}
+	case UnsignedTransactionFileVersion:
+		if file.NetworkName == "" || file.ChainID == (types.ChainID{}) {
+			return errors.New("the network of the transaction is not defined")
+		}
+		raw, err := hex.DecodeString(file.RawTransaction)
+		if err != nil || len(raw) == 0 {
+			return errors.New("invalid or missing raw transaction")
+		}
+		b, err := rivbin.Marshal(file.Transaction)
+		if err != nil {
+			return fmt.Errorf("failed to encode the transaction: %v", err)
+		}
+		if !bytes.Equal(raw, b) {
+			return errors.New("the transaction does not match the raw transaction, the file was modified or corrupted")
+		}
+	default:
+		return fmt.Errorf("unsupported version %d, the file is created by a newer client", file.Version)
+	}
+	if len(file.CoinInputParents) != len(file.Transaction.CoinInputs) {
+		return fmt.Errorf("file defines %d coin input parents for %d coin inputs",
+			len(file.CoinInputParents), len(file.Transaction.CoinInputs))
+	}
+	if len(file.BlockStakeInputParents) != len(file.Transaction.BlockStakeInputs) {
+		return fmt.Errorf("file defines %d block stake input parents for %d block stake inputs",
+			len(file.BlockStakeInputParents), len(file.Transaction.BlockStakeInputs))
+	}
+	return nil
+}
+
+// Builder returns a transaction builder continuing from the transaction of the file,
+// with the parent outputs of its inputs restored.
+func (file *UnsignedTransactionFile) Builder() (*txbuilder.Builder, error) {
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	builder := txbuilder.FromTransaction(file.Transaction).WithChainID(file.ChainID)
+	for idx, co := range file.CoinInputParents {
+		if err := builder.SetCoinInputParent(idx, co); err != nil {
+			return nil, err
+		}
+	}
+	for idx, bso := range file.BlockStakeInputParents {
+		if err := builder.SetBlockStakeInputParent(idx, bso); err != nil {
+			return nil, err
+		}
+	}
+	return builder, nil
+}
+
+// ReadUnsignedTransactionFile reads a transaction file from the given path.
+// The ChainID it defines is used by the Builder of the file,
+// such that its transaction can be signed for the correct chain.
+func ReadUnsignedTransactionFile(path string) (*UnsignedTransactionFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file UnsignedTransactionFile
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid transaction file: %v", err)
+	}
+	if err = file.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transaction file: %v", err)
+	}
+	return &file, nil
+}
+
+// WriteUnsignedTransactionFile writes the given transaction file to the given path,
+// overwriting any existing file. The file is written using the current version of the format,
+// embedding the raw transaction.
+func WriteUnsignedTransactionFile(path string, file *UnsignedTransactionFile) error {
+	raw, err := rivbin.Marshal(file.Transaction)
+	if err != nil {
+		return err
+	}
+	file.Version, file.RawTransaction = UnsignedTransactionFileVersion, hex.EncodeToString(raw)
+	b, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0600)
+}
+
+// createOfflineTxCmds creates the wallet commands that allow a transaction to be created,
+// signed and broadcast in separate steps, such that it can be signed on an (air-gapped) offline machine.
+func (walletCmd *walletCmd) createOfflineTxCmds() []*cobra.Command {
+	createTxCmd := &cobra.Command{
+		Use:   "create-tx <file> <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...",
+		Short: "Create an unsigned coin transaction file, to be signed offline",
+		Long: `Create an unsigned coin transaction, funded by the unspent coin outputs of the given addresses,
+	and write it to the given file, together with all information required to sign it using sign-tx.
+	The unspent outputs are looked up using the explorer module of the daemon,
+	meaning that the wallet used to sign the transaction doesn't have to be loaded or even be online.
+
+	The outputs can be given as a pair of value and a raw output condition (or
+	address, which resolved to a singlesignature condition).
+
+	Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency or a sub-unit SI prefix only (e.g. 250m).
+	Amounts which look like a value expressed in the smallest unit have to be suffixed with the unit of currency explicitly.
+	Decimals are possible and have to be defined using the decimal point.
+
+	The Minimum Miner Fee will be added on top of the total given amount automatically.
+	`,
+		Run: walletCmd.createOfflineTxCmd,
+	}
+	signTxCmd := &cobra.Command{
+		Use:   "sign-tx <file>",
+		Short: "Sign a transaction file using a seed, without requiring a daemon",
+		Long: `Sign all inputs of the transaction in the given file which are owned by keys of the given seed,
+	writing the signed transaction back to the file (or to the file defined by --out).
+	As the keys are derived locally, this command does not communicate with the daemon,
+	and can be used on an offline machine. The mnemonic of the seed is read from STDIN,
+	unless it is defined using the --seed flag.
+
+	Multisig inputs can be signed by multiple seeds,
+	by signing the same file on each machine, one after the other.
+	`,
+		Run: Wrap(walletCmd.signOfflineTxCmd),
+	}
+	broadcastTxCmd := &cobra.Command{
+		Use:   "broadcast-tx <file>",
+		Short: "Broadcast a signed transaction file",
+		Long:  "Submit the (fully signed) transaction of the given file to the transaction pool of the daemon.",
+		Run:   Wrap(walletCmd.broadcastOfflineTxCmd),
+	}
+
+	createTxCmd.Flags().StringSliceVar(
+		&walletCmd.createOfflineTxCfg.From,
+		"from", nil, "address of which the unspent coin outputs fund the transaction (required, can be repeated)")
+	createTxCmd.Flags().StringVar(
+		&walletCmd.createOfflineTxCfg.RefundAddress,
+		"refund-address", "", "address to send the change to, defaults to the first --from address")
+	walletCmd.createOfflineTxCfg.Data.register(createTxCmd.Flags())
+
+	signTxCmd.Flags().StringVar(
+		&walletCmd.signOfflineTxCfg.Seed,
+		"seed", "", "define the mnemonic of the seed as a flag instead of the STDIN")
+	signTxCmd.Flags().Uint64Var(
+		&walletCmd.signOfflineTxCfg.KeyDepth,
+		"key-depth", modules.PublicKeysPerSeed, "amount of keys of the seed to derive and sign with")
+	signTxCmd.Flags().StringVar(
+		&walletCmd.signOfflineTxCfg.Out,
+		"out", "", "file to write the signed transaction to, instead of overwriting the given file")
+
+	return []*cobra.Command{createTxCmd, signTxCmd, broadcastTxCmd}
+}
+
+// createOfflineTxCmd creates an unsigned coin transaction file, funded by the unspent outputs of the given addresses.
+func (walletCmd *walletCmd) createOfflineTxCmd(cmd *cobra.Command, args []string) {
+	if len(args) < 3 || len(args)%2 != 1 {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid arguments. Arguments must be of the form <file> <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...")
+	}
+	path := args[0]
+	currencyConvertor := walletCmd.cli.CreateCurrencyConvertor()
+	pairs, err := parsePairedOutputs(args[1:], currencyConvertor.ParseCoinString, walletCmd.cli.Config.Bech32AddressPrefix)
+	if err != nil {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
+	cfg := walletCmd.createOfflineTxCfg
+	if len(cfg.From) == 0 {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithExitCode(cli.ExitCodeUsage, "at least one --from address is required")
+	}
+	var body api.ExplorerUnspentOutputsPOST
+	for _, str := range cfg.From {
+		var uh types.UnlockHash
+		if err = uh.LoadAddressString(strings.TrimSpace(str), walletCmd.cli.Config.Bech32AddressPrefix); err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, fmt.Sprintf("invalid --from address %q: %v", str, err))
+		}
+		body.UnlockHashes = append(body.UnlockHashes, uh)
+	}
+	refundAddress := body.UnlockHashes[0]
+	if cfg.RefundAddress != "" {
+		if err = refundAddress.LoadAddressString(cfg.RefundAddress, walletCmd.cli.Config.Bech32AddressPrefix); err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "invalid refund address specified:", err)
+		}
+	}
+
+	data, err := cfg.Data.ArbitraryData(walletCmd.cli.Config)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
+
+	refundCondition := types.NewCondition(types.NewUnlockHashCondition(refundAddress))
+	file, spent := createUnsignedTransactionFile(walletCmd.cli, body.UnlockHashes, refundCondition, data, pairs)
+	if err = WriteUnsignedTransactionFile(path, file); err != nil {
+		cli.Die("Failed to write the transaction file:", err)
+	}
+	fmt.Printf("Unsigned transaction spending %d coin output(s) written to %s\n", spent, path)
+}
+
+// createUnsignedTransactionFile creates an unsigned coin transaction file, funded by the unspent outputs
+// of the given addresses, returning it together with the amount of coin outputs it spends.
+// The change is sent to the given refund condition, or to the condition of the first spent output if it is nil.
+func createUnsignedTransactionFile(client *CommandLineClient, from []types.UnlockHash, refundCondition types.UnlockConditionProxy, data []byte, pairs []outputPair) (*UnsignedTransactionFile, int) {
+	// collect the unlocked coin outputs of the given addresses
+	b, err := json.Marshal(api.ExplorerUnspentOutputsPOST{UnlockHashes: from})
+	if err != nil {
+		cli.Die("Failed to JSON Marshal the unlock hashes:", err)
+	}
+	var resp api.ExplorerUnspentOutputsGET
+	err = client.PostResp("/explorer/unspent", string(b), &resp)
+	if err != nil {
+		cli.DieWithError("Failed to get the unspent outputs of the given addresses:", err)
+	}
+	var unspent []txbuilder.UnspentCoinOutput
+	for _, address := range resp.Addresses {
+		for _, uco := range address.CoinOutputs {
+			if uco.Locked {
+				continue
+			}
+			unspent = append(unspent, txbuilder.UnspentCoinOutput{ID: uco.ID, Output: uco.CoinOutput})
+		}
+	}
+
+	// fund the outputs and miner fee, sending the change back to the refund address
+	currencyConvertor := client.CreateCurrencyConvertor()
+	builder := txbuilder.New(client.Config.DefaultTransactionVersion)
+	amount := client.Config.MinimumTransactionFee
+	for _, pair := range pairs {
+		builder.AddCoinOutput(pair.Value, pair.Condition)
+		amount = amount.Add(pair.Value)
+	}
+	selected, _, err := txbuilder.SelectCoinOutputs(unspent, amount)
+	if err != nil {
+		cli.Die(fmt.Sprintf("Failed to fund %s:", currencyConvertor.ToCoinStringWithUnit(amount)), err)
+	}
+	for _, uco := range selected {
+		builder.SpendCoinOutput(uco.ID, uco.Output, &types.NilFulfillment{})
+	}
+	builder.AddMinerFee(client.Config.MinimumTransactionFee)
+	if refundCondition.Condition == nil {
+		refundCondition = selected[0].Output.Condition
+	}
+	err = builder.AddCoinChange(refundCondition)
+	if err != nil {
+		cli.Die("Failed to add the coin change:", err)
+	}
+	if len(data) != 0 {
+		builder.SetArbitraryData(data)
+	}
+	builder.Canonicalize()
+
+	file := &UnsignedTransactionFile{
+		NetworkName: client.Config.NetworkName,
+		ChainID:     client.Config.ChainID,
+		Height:      resp.Height,
+		Timestamp:   resp.Timestamp,
+		Transaction: builder.Transaction(),
+	}
+	for idx := range file.Transaction.CoinInputs {
+		co, _ := builder.CoinInputParent(idx)
+		file.CoinInputParents = append(file.CoinInputParents, co)
+	}
+	return file, len(selected)
+}
+
+// signOfflineTxCmd signs a transaction file, using the keys derived from a seed.
+func (walletCmd *walletCmd) signOfflineTxCmd(path string) {
+	file, builder := signUnsignedTransactionFile(path, walletCmd.signOfflineTxCfg.Seed,
+		walletCmd.signOfflineTxCfg.KeyDepth, walletCmd.signOfflineTxCfg.Out)
+	if err := validateOfflineTx(builder, file); err != nil {
+		fmt.Println("Transaction is not yet fully signed:", err)
+		return
+	}
+	fmt.Println("Transaction is fully signed and can be broadcast using broadcast-tx")
+}
+
+// signUnsignedTransactionFile signs the transaction file at the given path, using the keys derived from a seed,
+// and writes it to the given output path (or back to the same path if not defined).
+// The mnemonic of the seed is read from STDIN if not given.
+func signUnsignedTransactionFile(path, mnemonic string, keyDepth uint64, out string) (*UnsignedTransactionFile, *txbuilder.Builder) {
+	file, err := ReadUnsignedTransactionFile(path)
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	builder, err := file.Builder()
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+
+	if mnemonic == "" {
+		mnemonic, err = speakeasy.Ask("Mnemonic of the seed to sign with: ")
+		if err != nil {
+			cli.Die("Reading mnemonic failed:", err)
+		}
+	}
+	seed, err := modules.InitialSeedFromMnemonic(mnemonic)
+	if err != nil {
+		cli.Die("Invalid mnemonic given:", err)
+	}
+	ring := make(txbuilder.KeyRing, keyDepth)
+	for index := uint64(0); index < keyDepth; index++ {
+		sk, pk, err := seed.SpendableKey(index)
+		if err != nil {
+			cli.Die("Failed to derive the keys of the seed:", err)
+		}
+		if _, err = ring.AddKey(sk, pk); err != nil {
+			cli.Die("Failed to derive the keys of the seed:", err)
+		}
+	}
+
+	signed, err := builder.SignInputs(ring)
+	if err != nil {
+		cli.Die("Failed to sign the transaction:", err)
+	}
+	if signed == 0 {
+		cli.Die("None of the inputs of the transaction could be signed by the given seed")
+	}
+	file.Transaction = builder.Transaction()
+	if out == "" {
+		out = path
+	}
+	if err = WriteUnsignedTransactionFile(out, file); err != nil {
+		cli.Die("Failed to write the transaction file:", err)
+	}
+	fmt.Printf("Added %d signature(s) for the %s network, written to %s\n", signed, file.NetworkName, out)
+	return file, builder
+}
+
+// validateOfflineTx validates the signatures of the transaction file locally.
+// The size limits and minimum miner fee of the network are unknown offline,
+// and are left to be validated by the daemon when the transaction is broadcast.
+func validateOfflineTx(builder *txbuilder.Builder, file *UnsignedTransactionFile) error {
+	txn := builder.Transaction()
+	blockTime := types.Timestamp(time.Now().Unix())
+	if file.Timestamp > blockTime {
+		blockTime = file.Timestamp
+	}
+	return builder.Validate(types.TransactionValidationContext{
+		ValidationContext: types.ValidationContext{
+			BlockHeight: file.Height,
+			BlockTime:   blockTime,
+		},
+		ArbitraryDataSizeLimit: uint64(len(txn.ArbitraryData)),
+		ExtensionDataSizeLimit: uint64(len(txn.ExtensionData)),
+		ChainID:                file.ChainID,
+	})
+}
+
+// broadcastOfflineTxCmd submits the transaction of a signed transaction file to the transaction pool.
+func (walletCmd *walletCmd) broadcastOfflineTxCmd(path string) {
+	file, err := ReadUnsignedTransactionFile(path)
+	if err != nil {
+		cli.Die("Failed to read the transaction file:", err)
+	}
+	broadcastUnsignedTransactionFile(walletCmd.cli, file)
+}
+
+// broadcastUnsignedTransactionFile submits the transaction of the given (signed) transaction file to the transaction pool.
+func broadcastUnsignedTransactionFile(client *CommandLineClient, file *UnsignedTransactionFile) {
+	if err := checkOfflineTxChain(file, client.Config); err != nil {
+		cli.Die(err)
+	}
+	b, err := json.Marshal(file.Transaction)
+	if err != nil {
+		cli.Die("Failed to JSON Marshal the transaction:", err)
+	}
+	var resp api.TransactionPoolPOST
+	err = client.PostResp("/transactionpool/transactions", string(b), &resp)
+	if err != nil {
+		cli.DieWithError("Could not publish transaction:", err)
+	}
+	fmt.Println("Transaction published, transaction id:", resp.TransactionID)
+}
+
+// dryRunUnsignedTransactionFile validates the (signed) transaction of the file against the transaction pool,
+// printing it and the fees it pays, without publishing it.
+func dryRunUnsignedTransactionFile(client *CommandLineClient, file *UnsignedTransactionFile) {
+	if err := checkOfflineTxChain(file, client.Config); err != nil {
+		cli.Die(err)
+	}
+	b, err := json.Marshal(file.Transaction)
+	if err != nil {
+		cli.Die("Failed to JSON Marshal the transaction:", err)
+	}
+	var resp api.TransactionPoolPOST
+	err = client.PostResp("/transactionpool/transactions?dryrun=true", string(b), &resp)
+	if err != nil {
+		cli.DieWithError("Invalid transaction:", err)
+	}
+	printDryRunTransaction(file.Transaction, client.CreateCurrencyConvertor())
+}
+
+// checkOfflineTxChain ensures the transaction file is created for the chain of the daemon.
+func checkOfflineTxChain(file *UnsignedTransactionFile, cfg *Config) error {
+	if file.ChainID == (types.ChainID{}) || cfg.ChainID == (types.ChainID{}) || file.ChainID == cfg.ChainID {
+		return nil
+	}
+	return errors.New("transaction file is created for the " + file.NetworkName +
+		" network, while the daemon runs on the " + cfg.NetworkName + " network")
+}
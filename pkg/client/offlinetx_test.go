@@ -0,0 +1,83 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestUnsignedTransactionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rivinec-txfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	condition := types.NewCondition(types.NewUnlockHashCondition(types.UnlockHash{Type: types.UnlockTypePubKey, Hash: crypto.Hash{1}}))
+	parent := types.CoinOutput{Value: types.NewCurrency64(100), Condition: condition}
+	file := &UnsignedTransactionFile{
+		NetworkName: "devnet",
+		ChainID:     types.ChainID{1},
+		Height:      42,
+		Transaction: txbuilder.New(types.TransactionVersionOne).
+			SpendCoinOutput(types.CoinOutputID{1}, parent, &types.NilFulfillment{}).
+			AddCoinOutput(types.NewCurrency64(90), condition).
+			AddMinerFee(types.NewCurrency64(10)).
+			Transaction(),
+		CoinInputParents: []types.CoinOutput{parent},
+	}
+	path := filepath.Join(dir, "tx.json")
+	if err = WriteUnsignedTransactionFile(path, file); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadUnsignedTransactionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Version != UnsignedTransactionFileVersion || read.RawTransaction == "" ||
+		read.Transaction.ID() != file.Transaction.ID() || read.Height != 42 {
+		t.Fatalf("unexpected transaction file read: %+v", read)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name, old, new, err string
+	}{
+		{"modified transaction", `"value": "90"`, `"value": "95"`, "does not match the raw transaction"},
+		{"unknown field", `"height"`, `"heigth"`, "unknown field"},
+		{"newer version", `"version": 2`, `"version": 3`, "unsupported version 3"},
+		{"older version", `"version": 2`, `"version": 1`, "version 1 files do not define a raw transaction"},
+	}
+	for _, testCase := range testCases {
+		if !strings.Contains(string(content), testCase.old) {
+			t.Fatalf("%s: %q not found in file:\n%s", testCase.name, testCase.old, content)
+		}
+		if err = ioutil.WriteFile(path, []byte(strings.Replace(string(content), testCase.old, testCase.new, 1)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ReadUnsignedTransactionFile(path); err == nil || !strings.Contains(err.Error(), testCase.err) {
+			t.Errorf("%s: expected error containing %q, not: %v", testCase.name, testCase.err, err)
+		}
+	}
+
+	// files created prior to the versioned format can still be read
+	legacy := strings.Replace(string(content), `"version": 2,`, "", 1)
+	start := strings.Index(legacy, `"rawtransaction"`)
+	end := start + strings.Index(legacy[start:], "\n")
+	legacy = legacy[:start] + `"rawtransaction": "",` + legacy[end:]
+	if err = ioutil.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if read, err = ReadUnsignedTransactionFile(path); err != nil || read.Version != 0 {
+		t.Errorf("failed to read legacy transaction file: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ProfilesConfig is the (optional) config file of a CLI client,
+// defining named profiles, such that the daemon address, API password and other options
+// do not have to be repeated for every command, when working with multiple nodes.
+//
+// The file is encoded as YAML, or as JSON if its path has the .json extension.
+type ProfilesConfig struct {
+	// Default is the name of the profile used when no profile is selected explicitly,
+	// no profile is used by default if not defined.
+	Default  string             `json:"default,omitempty" yaml:"default,omitempty"`
+	Profiles map[string]Profile `json:"profiles" yaml:"profiles"`
+}
+
+// Profile defines the options of a CLI client for a single node.
+// Options given explicitly as flags take precedence over the options of a profile.
+type Profile struct {
+	// Address is the host/port (or unix://<path>) of the daemon,
+	// or multiple comma-separated host/ports of daemons to fail over between
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// Password is the API password of the daemon
+	Password      string `json:"password,omitempty" yaml:"password,omitempty"`
+	TLSCert       string `json:"tlsCert,omitempty" yaml:"tlsCert,omitempty"`
+	TLSSkipVerify bool   `json:"tlsSkipVerify,omitempty" yaml:"tlsSkipVerify,omitempty"`
+	// Encoding is the default encoding type (e.g. json) of commands supporting it
+	Encoding string `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	// Network is the name of the network the daemon is expected to be part of
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+}
+
+// DefaultProfilesConfigPath returns the default path of the profiles config file
+// of the CLI client with the given name, e.g. ~/.rivinec/config.yaml.
+func DefaultProfilesConfigPath(name string) string {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "."+strings.ToLower(name)+"c", "config.yaml")
+}
+
+// LoadProfilesConfig loads the profiles config file from the given path.
+// An empty config is returned if the file does not exist.
+func LoadProfilesConfig(path string) (ProfilesConfig, error) {
+	var cfg ProfilesConfig
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(b, &cfg)
+	} else {
+		err = yaml.UnmarshalStrict(b, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	if cfg.Default != "" {
+		if _, ok := cfg.Profiles[cfg.Default]; !ok {
+			return cfg, fmt.Errorf("invalid config file %s: default profile %q is not defined", path, cfg.Default)
+		}
+	}
+	return cfg, nil
+}
+
+// Profile returns the profile with the given name, or the default profile if no name is given.
+// False is returned if no name is given and no default profile is defined,
+// an error is returned if the profile with the given name does not exist.
+func (cfg ProfilesConfig) Profile(name string) (Profile, bool, error) {
+	if name == "" {
+		name = cfg.Default
+		if name == "" {
+			return Profile{}, false, nil
+		}
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return Profile{}, false, fmt.Errorf("profile %q is not defined (available profiles: %s)",
+			name, strings.Join(cfg.ProfileNames(), ", "))
+	}
+	return profile, true, nil
+}
+
+// ProfileNames returns the sorted names of all defined profiles.
+func (cfg ProfilesConfig) ProfileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile loads the selected profile (if any) and applies its options,
+// for all options which aren't given explicitly as a flag of the given command.
+func (cli *CommandLineClient) applyProfile(cmd *cobra.Command) error {
+	if cli.profilesConfigPath == "" {
+		if cli.profileName != "" {
+			return fmt.Errorf("cannot use profile %q: no config file is defined", cli.profileName)
+		}
+		return nil
+	}
+	cfg, err := LoadProfilesConfig(cli.profilesConfigPath)
+	if err != nil {
+		return err
+	}
+	profile, ok, err := cfg.Profile(cli.profileName)
+	if err != nil || !ok {
+		return err
+	}
+	cli.Profile = &profile
+
+	flags := cmd.Flags()
+	if profile.Address != "" && !flags.Changed("addr") {
+		cli.HTTPClient.RootURL = profile.Address
+	}
+	if profile.Password != "" {
+		cli.HTTPClient.Password = profile.Password
+	}
+	if profile.TLSCert != "" && !flags.Changed("tls-cert") {
+		cli.tlsCertFile = profile.TLSCert
+	}
+	if profile.TLSSkipVerify && !flags.Changed("tls-skip-verify") {
+		cli.tlsSkipVerify = true
+	}
+	if profile.Encoding != "" {
+		// only applied to commands which support the encoding type
+		if f := flags.Lookup("encoding"); f != nil && !f.Changed {
+			f.Value.Set(profile.Encoding)
+		}
+	}
+	return nil
+}
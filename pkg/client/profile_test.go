@@ -0,0 +1,139 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+)
+
+func TestLoadProfilesConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a missing config file results in an empty config
+	cfg, err := LoadProfilesConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := cfg.Profile(""); ok || err != nil {
+		t.Errorf("unexpected default profile: %v, %v", ok, err)
+	}
+
+	files := map[string]string{
+		"config.yaml": `default: local
+profiles:
+  local:
+    address: localhost:23110
+  remote:
+    address: https://node.example.com
+    password: secret
+    encoding: json
+    network: testnet
+`,
+		"config.json": `{"default":"local","profiles":{
+	"local":{"address":"localhost:23110"},
+	"remote":{"address":"https://node.example.com","password":"secret","encoding":"json","network":"testnet"}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err = LoadProfilesConfig(path)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		profile, ok, err := cfg.Profile("")
+		if err != nil || !ok || profile.Address != "localhost:23110" {
+			t.Errorf("%s: unexpected default profile: %v, %v, %v", name, profile, ok, err)
+		}
+		profile, ok, err = cfg.Profile("remote")
+		if err != nil || !ok || profile != (Profile{
+			Address:  "https://node.example.com",
+			Password: "secret",
+			Encoding: "json",
+			Network:  "testnet",
+		}) {
+			t.Errorf("%s: unexpected remote profile: %v, %v, %v", name, profile, ok, err)
+		}
+		if _, _, err = cfg.Profile("unknown"); err == nil {
+			t.Errorf("%s: selecting an unknown profile should fail", name)
+		}
+	}
+
+	for name, content := range map[string]string{
+		"undefined.yaml": "default: foo\nprofiles:\n  bar: {}\n",
+		"unknown.yaml":   "profiles:\n  bar:\n    adress: localhost\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = LoadProfilesConfig(path); err == nil {
+			t.Errorf("loading %s should fail", name)
+		}
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	err = ioutil.WriteFile(path, []byte(`profiles:
+  remote:
+    address: node.example.com:23110
+    password: secret
+    encoding: json
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(args ...string) (*CommandLineClient, *cli.EncodingType) {
+		client := &CommandLineClient{
+			HTTPClient:         &api.HTTPClient{RootURL: "localhost:23110"},
+			profilesConfigPath: path,
+		}
+		var et cli.EncodingType
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().StringVar(&client.HTTPClient.RootURL, "addr", client.HTTPClient.RootURL, "")
+		cmd.Flags().StringVar(&client.profileName, "profile", "", "")
+		cmd.Flags().Var(cli.NewEncodingTypeFlag(0, &et, 0), "encoding", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.applyProfile(cmd); err != nil {
+			t.Fatal(err)
+		}
+		return client, &et
+	}
+
+	// no default profile
+	client, et := newCmd()
+	if client.Profile != nil || client.HTTPClient.RootURL != "localhost:23110" || *et != cli.EncodingTypeHuman {
+		t.Errorf("unexpected client state without profile: %v, %s, %v", client.Profile, client.HTTPClient.RootURL, *et)
+	}
+	// profile options are applied
+	client, et = newCmd("--profile", "remote")
+	if client.Profile == nil || client.HTTPClient.RootURL != "node.example.com:23110" ||
+		client.HTTPClient.Password != "secret" || *et != cli.EncodingTypeJSON {
+		t.Errorf("unexpected client state with profile: %v, %s, %v", client.Profile, client.HTTPClient.RootURL, *et)
+	}
+	// explicit flags take precedence
+	client, et = newCmd("--profile", "remote", "--addr", "localhost:2000", "--encoding", "hex")
+	if client.HTTPClient.RootURL != "localhost:2000" || *et != cli.EncodingTypeHex {
+		t.Errorf("unexpected client state with profile and flags: %s, %v", client.HTTPClient.RootURL, *et)
+	}
+}
@@ -0,0 +1,327 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// recoverMaxInputsPerTransaction is the default maximum amount of inputs of a single sweep transaction,
+// keeping it well within the standard transaction size limit of the transaction pool.
+const recoverMaxInputsPerTransaction = 75
+
+func createRecoverCmd(client *CommandLineClient) *cobra.Command {
+	recoverCmd := &recoverCmd{cli: client}
+
+	// create recover command
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Recover the funds of a seed, without a wallet",
+		Long: `Scan the addresses derived from a seed for unspent coin and block stake outputs,
+without requiring the seed to be loaded in a wallet, and report the funds found per address.
+The unspent outputs are looked up using the explorer module of the daemon,
+or using the explorer API at the URL defined by --explorer.
+
+When --sweep-to is defined, all unlocked outputs found are sent to the given address (or raw condition),
+in one or more transactions signed locally and published using the daemon.
+The Minimum Miner Fee is paid by each transaction from the coins it spends.
+
+The mnemonic of the seed is read from STDIN, unless it is defined using the --seed flag.
+`,
+		Run: Wrap(recoverCmd.recoverCmd),
+	}
+
+	// create flags
+	cmd.Flags().StringVar(
+		&recoverCmd.recoverCfg.Seed,
+		"seed", "", "define the mnemonic of the seed as a flag instead of the STDIN")
+	cmd.Flags().Uint64Var(
+		&recoverCmd.recoverCfg.KeyDepth,
+		"key-depth", modules.PublicKeysPerSeed, "amount of addresses of the seed to derive and scan")
+	cmd.Flags().StringVar(
+		&recoverCmd.recoverCfg.Explorer,
+		"explorer", "", "URL of the explorer API to look up the unspent outputs with, instead of the daemon")
+	cmd.Flags().StringVar(
+		&recoverCmd.recoverCfg.SweepTo,
+		"sweep-to", "", "address (or raw condition) to send all unlocked outputs to")
+	cmd.Flags().IntVar(
+		&recoverCmd.recoverCfg.MaxInputs,
+		"max-inputs", recoverMaxInputsPerTransaction, "maximum amount of inputs of a single sweep transaction")
+
+	// return recover command
+	return cmd
+}
+
+type recoverCmd struct {
+	cli        *CommandLineClient
+	recoverCfg struct {
+		Seed      string
+		KeyDepth  uint64
+		Explorer  string
+		SweepTo   string
+		MaxInputs int
+	}
+}
+
+// recoveredAddress is an address derived from a seed, together with its unspent outputs.
+type recoveredAddress struct {
+	Index uint64
+	api.ExplorerAddressUnspentOutputs
+}
+
+// recoveredOutputs are the unspent outputs found for the addresses of a seed.
+type recoveredOutputs struct {
+	// Height and Timestamp of the current block at the time of the (last) lookup
+	Height    types.BlockHeight
+	Timestamp types.Timestamp
+	// Addresses contains only the addresses which have unspent outputs, ordered by index
+	Addresses []recoveredAddress
+}
+
+// recoverCmd is the handler for the command `rivinec recover`,
+// scanning the addresses of a seed for unspent outputs, and optionally sweeping them.
+func (cmd *recoverCmd) recoverCmd() {
+	cfg := cmd.recoverCfg
+	if cfg.KeyDepth == 0 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "at least one address has to be scanned (--key-depth)")
+	}
+	if cfg.MaxInputs < 2 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "a sweep transaction requires at least 2 inputs (--max-inputs)")
+	}
+	var (
+		sweepCondition types.UnlockConditionProxy
+		err            error
+	)
+	if cfg.SweepTo != "" {
+		sweepCondition, err = parseConditionString(cfg.SweepTo, cmd.cli.Config.Bech32AddressPrefix)
+		if err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "invalid --sweep-to address specified:", err)
+		}
+	}
+
+	mnemonic := cfg.Seed
+	if mnemonic == "" {
+		mnemonic, err = speakeasy.Ask("Mnemonic of the seed to recover: ")
+		if err != nil {
+			cli.Die("Reading mnemonic failed:", err)
+		}
+	}
+	seed, err := modules.InitialSeedFromMnemonic(mnemonic)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid mnemonic given:", err)
+	}
+
+	lookup := cmd.cli.HTTPClient
+	if cfg.Explorer != "" {
+		lookup = &api.HTTPClient{
+			RootURL:   cfg.Explorer,
+			UserAgent: cmd.cli.HTTPClient.UserAgent,
+		}
+	}
+	uhs := make([]types.UnlockHash, 0, cfg.KeyDepth)
+	ring := make(txbuilder.KeyRing, cfg.KeyDepth)
+	for index := uint64(0); index < cfg.KeyDepth; index++ {
+		sk, pk, err := seed.SpendableKey(index)
+		if err != nil {
+			cli.Die("Failed to derive the keys of the seed:", err)
+		}
+		uh, err := ring.AddKey(sk, pk)
+		if err != nil {
+			cli.Die("Failed to derive the keys of the seed:", err)
+		}
+		uhs = append(uhs, uh)
+	}
+	recovered, err := scanUnspentOutputs(lookup, uhs)
+	if err != nil {
+		cli.DieWithError("Failed to look up the unspent outputs of the seed:", err)
+	}
+	printRecoveredOutputs(os.Stdout, recovered, len(uhs), cmd.cli.CreateCurrencyConvertor())
+
+	if cfg.SweepTo == "" {
+		return
+	}
+	sweepRecoveredOutputs(cmd.cli, recovered, sweepCondition, ring, cfg.MaxInputs, false)
+}
+
+// sweepRecoveredOutputs sends all unlocked recovered outputs to the given condition,
+// signing the sweep transactions using the given key ring and publishing them using the daemon,
+// or only validating them using the transaction pool of the daemon in case of a dry run.
+func sweepRecoveredOutputs(client *CommandLineClient, recovered recoveredOutputs, condition types.UnlockConditionProxy, ring txbuilder.KeyRing, maxInputs int, dryRun bool) {
+	files, err := createSweepTransactions(client.Config, recovered, condition, ring, maxInputs)
+	if err != nil {
+		cli.Die("Failed to create the sweep transactions:", err)
+	}
+	if len(files) == 0 {
+		fmt.Println("No unlocked outputs to sweep")
+		return
+	}
+	if dryRun {
+		for _, file := range files {
+			dryRunUnsignedTransactionFile(client, file)
+		}
+		fmt.Printf("Dry run: would sweep all unlocked outputs to %s in %d transaction(s)\n", condition.UnlockHash().String(), len(files))
+		return
+	}
+	for _, file := range files {
+		broadcastUnsignedTransactionFile(client, file)
+	}
+	fmt.Printf("Swept all unlocked outputs to %s in %d transaction(s)\n", condition.UnlockHash().String(), len(files))
+}
+
+// scanUnspentOutputs looks up the unspent outputs of the given unlock hashes using the explorer API,
+// in batches of the maximum amount of unlock hashes allowed per request.
+func scanUnspentOutputs(client *api.HTTPClient, uhs []types.UnlockHash) (recoveredOutputs, error) {
+	var recovered recoveredOutputs
+	for offset := 0; offset < len(uhs); offset += api.MaxUnspentOutputsUnlockHashes {
+		end := offset + api.MaxUnspentOutputsUnlockHashes
+		if end > len(uhs) {
+			end = len(uhs)
+		}
+		b, err := json.Marshal(api.ExplorerUnspentOutputsPOST{UnlockHashes: uhs[offset:end]})
+		if err != nil {
+			return recoveredOutputs{}, err
+		}
+		var resp api.ExplorerUnspentOutputsGET
+		if err = client.PostResp("/explorer/unspent", string(b), &resp); err != nil {
+			return recoveredOutputs{}, err
+		}
+		recovered.Height, recovered.Timestamp = resp.Height, resp.Timestamp
+		for idx, address := range resp.Addresses {
+			if len(address.CoinOutputs) == 0 && len(address.BlockStakeOutputs) == 0 {
+				continue
+			}
+			recovered.Addresses = append(recovered.Addresses, recoveredAddress{
+				Index:                         uint64(offset + idx),
+				ExplorerAddressUnspentOutputs: address,
+			})
+		}
+	}
+	return recovered, nil
+}
+
+// printRecoveredOutputs prints the unspent outputs found per address, and their totals.
+func printRecoveredOutputs(w io.Writer, recovered recoveredOutputs, scanned int, cc CurrencyConvertor) {
+	fmt.Fprintf(w, "Scanned %d address(es) at height %d, %d address(es) have unspent outputs\n",
+		scanned, recovered.Height, len(recovered.Addresses))
+	if len(recovered.Addresses) == 0 {
+		return
+	}
+	var coins, lockedCoins, blockStakes, lockedBlockStakes types.Currency
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "index\taddress\tcoins\tlocked coins\tblock stakes\tlocked block stakes")
+	for _, address := range recovered.Addresses {
+		var c, lc, bs, lbs types.Currency
+		for _, co := range address.CoinOutputs {
+			if co.Locked {
+				lc = lc.Add(co.Value)
+			} else {
+				c = c.Add(co.Value)
+			}
+		}
+		for _, bso := range address.BlockStakeOutputs {
+			if bso.Locked {
+				lbs = lbs.Add(bso.Value)
+			} else {
+				bs = bs.Add(bso.Value)
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", address.Index, address.UnlockHash.String(),
+			cc.ToCoinString(c), cc.ToCoinString(lc), bs.String(), lbs.String())
+		coins, lockedCoins = coins.Add(c), lockedCoins.Add(lc)
+		blockStakes, lockedBlockStakes = blockStakes.Add(bs), lockedBlockStakes.Add(lbs)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "Total: %s (and %s locked), %s BS (and %s BS locked)\n",
+		cc.ToCoinStringWithUnit(coins), cc.ToCoinStringWithUnit(lockedCoins),
+		blockStakes.String(), lockedBlockStakes.String())
+}
+
+// createSweepTransactions creates and signs the transactions sending all unlocked outputs
+// to the given condition, each transaction spending at most the given amount of inputs
+// and paying the minimum miner fee from the coins it spends.
+// All block stake outputs are spent by the first transaction.
+func createSweepTransactions(cfg *Config, recovered recoveredOutputs, condition types.UnlockConditionProxy, ring txbuilder.KeyRing, maxInputs int) ([]*UnsignedTransactionFile, error) {
+	var (
+		coinOutputs       []api.ExplorerUnspentCoinOutput
+		blockStakeOutputs []api.ExplorerUnspentBlockStakeOutput
+	)
+	for _, address := range recovered.Addresses {
+		for _, co := range address.CoinOutputs {
+			if !co.Locked {
+				coinOutputs = append(coinOutputs, co)
+			}
+		}
+		for _, bso := range address.BlockStakeOutputs {
+			if !bso.Locked {
+				blockStakeOutputs = append(blockStakeOutputs, bso)
+			}
+		}
+	}
+	if len(blockStakeOutputs) >= maxInputs {
+		return nil, fmt.Errorf("cannot spend %d block stake outputs and the coins to pay the fee with at most %d inputs",
+			len(blockStakeOutputs), maxInputs)
+	}
+	if len(blockStakeOutputs) > 0 && len(coinOutputs) == 0 {
+		return nil, fmt.Errorf("cannot sweep %d block stake output(s), as there are no unlocked coins to pay the fee with",
+			len(blockStakeOutputs))
+	}
+
+	fee := cfg.MinimumTransactionFee
+	currencyConvertor := NewCurrencyConvertor(cfg.CurrencyUnits, cfg.CurrencyCoinUnit)
+	var files []*UnsignedTransactionFile
+	for len(coinOutputs) > 0 {
+		builder := txbuilder.New(cfg.DefaultTransactionVersion)
+		var blockStakes types.Currency
+		for _, bso := range blockStakeOutputs {
+			builder.SpendBlockStakeOutput(bso.ID, bso.BlockStakeOutput, &types.NilFulfillment{})
+			blockStakes = blockStakes.Add(bso.Value)
+		}
+		n := maxInputs - len(blockStakeOutputs)
+		if n > len(coinOutputs) {
+			n = len(coinOutputs)
+		}
+		var coins types.Currency
+		for _, co := range coinOutputs[:n] {
+			builder.SpendCoinOutput(co.ID, co.CoinOutput, &types.NilFulfillment{})
+			coins = coins.Add(co.Value)
+		}
+		if coins.Cmp(fee) <= 0 {
+			return nil, fmt.Errorf("%d coin output(s) of %s cannot pay the minimum miner fee of %s",
+				n, currencyConvertor.ToCoinStringWithUnit(coins), currencyConvertor.ToCoinStringWithUnit(fee))
+		}
+		builder.AddCoinOutput(coins.Sub(fee), condition)
+		if len(blockStakeOutputs) > 0 {
+			builder.AddBlockStakeOutput(blockStakes, condition)
+		}
+		builder.AddMinerFee(fee)
+		builder.Canonicalize()
+
+		if _, err := builder.SignInputs(ring); err != nil {
+			return nil, err
+		}
+		file := &UnsignedTransactionFile{
+			NetworkName: cfg.NetworkName,
+			ChainID:     cfg.ChainID,
+			Height:      recovered.Height,
+			Timestamp:   recovered.Timestamp,
+			Transaction: builder.Transaction(),
+		}
+		if err := validateOfflineTx(builder, file); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		coinOutputs, blockStakeOutputs = coinOutputs[n:], nil
+	}
+	return files, nil
+}
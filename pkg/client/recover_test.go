@@ -0,0 +1,89 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCreateSweepTransactions(t *testing.T) {
+	const mnemonic = "carbon boss inject cover mountain fetch fiber fit tornado cloth wing dinosaur proof joy intact fabric thumb rebel borrow poet chair network expire else"
+	seed, err := modules.InitialSeedFromMnemonic(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := make(txbuilder.KeyRing)
+	sk, pk, err := seed.SpendableKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uh, err := ring.AddKey(sk, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := types.NewCondition(types.NewUnlockHashCondition(uh))
+
+	// 5 unlocked coin outputs, a locked coin output and an unlocked block stake output
+	address := recoveredAddress{ExplorerAddressUnspentOutputs: api.ExplorerAddressUnspentOutputs{UnlockHash: uh}}
+	for i := byte(1); i <= 6; i++ {
+		address.CoinOutputs = append(address.CoinOutputs, api.ExplorerUnspentCoinOutput{
+			ID:         types.CoinOutputID{i},
+			CoinOutput: types.CoinOutput{Value: types.NewCurrency64(10), Condition: condition},
+			Locked:     i == 6,
+		})
+	}
+	address.BlockStakeOutputs = append(address.BlockStakeOutputs, api.ExplorerUnspentBlockStakeOutput{
+		ID:               types.BlockStakeOutputID{1},
+		BlockStakeOutput: types.BlockStakeOutput{Value: types.NewCurrency64(42), Condition: condition},
+	})
+	recovered := recoveredOutputs{Height: 10, Timestamp: 1000, Addresses: []recoveredAddress{address}}
+
+	cfg := &Config{
+		MinimumTransactionFee:     types.NewCurrency64(1),
+		DefaultTransactionVersion: types.TransactionVersionOne,
+	}
+	destination := types.NewCondition(types.NewUnlockHashCondition(unlockHashFromString(t,
+		"01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e")))
+	files, err := createSweepTransactions(cfg, recovered, destination, ring, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first transaction spends the block stakes and 2 coin outputs, the second the remaining 3 coin outputs
+	if len(files) != 2 {
+		t.Fatalf("expected 2 sweep transactions, got %d", len(files))
+	}
+	for idx, expected := range []struct {
+		CoinInputs, BlockStakeInputs int
+		Coins                        uint64
+	}{{2, 1, 19}, {3, 0, 29}} {
+		txn := files[idx].Transaction
+		if len(txn.CoinInputs) != expected.CoinInputs || len(txn.BlockStakeInputs) != expected.BlockStakeInputs {
+			t.Errorf("#%d: unexpected inputs: %d coin, %d block stake", idx, len(txn.CoinInputs), len(txn.BlockStakeInputs))
+		}
+		if len(txn.CoinOutputs) != 1 || !txn.CoinOutputs[0].Value.Equals64(expected.Coins) ||
+			txn.CoinOutputs[0].Condition.UnlockHash().Cmp(destination.UnlockHash()) != 0 {
+			t.Errorf("#%d: unexpected coin outputs: %v", idx, txn.CoinOutputs)
+		}
+		if expected.BlockStakeInputs > 0 && (len(txn.BlockStakeOutputs) != 1 || !txn.BlockStakeOutputs[0].Value.Equals64(42)) {
+			t.Errorf("#%d: unexpected block stake outputs: %v", idx, txn.BlockStakeOutputs)
+		}
+	}
+
+	// block stakes cannot be swept without coins to pay the fee
+	address.CoinOutputs = address.CoinOutputs[5:]
+	recovered.Addresses = []recoveredAddress{address}
+	if _, err = createSweepTransactions(cfg, recovered, destination, ring, 3); err == nil {
+		t.Error("sweeping block stakes without unlocked coins should fail")
+	}
+}
+
+func unlockHashFromString(t *testing.T, str string) types.UnlockHash {
+	var uh types.UnlockHash
+	if err := uh.LoadString(str); err != nil {
+		t.Fatal(err)
+	}
+	return uh
+}
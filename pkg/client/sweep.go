@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// createSweepCmd creates the `wallet sweep` command,
+// sweeping the funds of a (paper wallet) secret key or foreign seed into the wallet.
+func (walletCmd *walletCmd) createSweepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sweep",
+		Short: "Sweep the funds of a secret key or seed into the wallet",
+		Long: `Sweep the funds of one or more secret keys (e.g. of a paper wallet) or of a foreign seed,
+which isn't loaded in the wallet, into the wallet.
+The unspent outputs of the addresses of the keys (or seed) are looked up using the explorer module of the daemon,
+or using the explorer API at the URL defined by --explorer,
+and all unlocked outputs are sent in full to a new address of the wallet,
+in one or more transactions signed locally and published using the daemon.
+The Minimum Miner Fee is paid by each transaction from the coins it spends.
+
+Secret keys are given hex-encoded, either as the full (64 byte) ed25519 secret key,
+as printed by the generate keypair command, or as its (32 byte) seed.
+When neither --key nor --seed is defined, a secret key or mnemonic is read from STDIN.
+`,
+		Run: Wrap(walletCmd.sweepCmd),
+	}
+
+	cmd.Flags().StringArrayVar(
+		&walletCmd.sweepCfg.Keys,
+		"key", nil, "hex-encoded secret key of which to sweep the funds, can be defined multiple times")
+	cmd.Flags().StringVar(
+		&walletCmd.sweepCfg.Seed,
+		"seed", "", "mnemonic of the seed of which to sweep the funds")
+	cmd.Flags().Uint64Var(
+		&walletCmd.sweepCfg.KeyDepth,
+		"key-depth", modules.PublicKeysPerSeed, "amount of addresses of the seed to derive and scan")
+	cmd.Flags().StringVar(
+		&walletCmd.sweepCfg.Explorer,
+		"explorer", "", "URL of the explorer API to look up the unspent outputs with, instead of the daemon")
+	cmd.Flags().IntVar(
+		&walletCmd.sweepCfg.MaxInputs,
+		"max-inputs", recoverMaxInputsPerTransaction, "maximum amount of inputs of a single sweep transaction")
+	cmd.Flags().BoolVar(
+		&walletCmd.sweepCfg.DryRun,
+		"dry-run", false, "create and validate the sweep transactions, printing them and their fees, without publishing them")
+
+	return cmd
+}
+
+// sweepCmd is the handler for the command `rivinec wallet sweep`,
+// sweeping the funds of secret keys or a foreign seed into the wallet.
+func (walletCmd *walletCmd) sweepCmd() {
+	cfg := walletCmd.sweepCfg
+	if cfg.KeyDepth == 0 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "at least one address has to be scanned (--key-depth)")
+	}
+	if cfg.MaxInputs < 2 {
+		cli.DieWithExitCode(cli.ExitCodeUsage, "a sweep transaction requires at least 2 inputs (--max-inputs)")
+	}
+
+	keys, mnemonic := cfg.Keys, cfg.Seed
+	if len(keys) == 0 && mnemonic == "" {
+		secret, err := speakeasy.Ask("Secret key or mnemonic of the seed to sweep: ")
+		if err != nil {
+			cli.Die("Reading secret key or mnemonic failed:", err)
+		}
+		if isHexSecretKey(secret) {
+			keys = []string{secret}
+		} else {
+			mnemonic = secret
+		}
+	}
+
+	var (
+		ring = make(txbuilder.KeyRing)
+		uhs  []types.UnlockHash
+	)
+	for _, str := range keys {
+		sk, pk, err := parseSecretKey(str)
+		if err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid secret key given:", err)
+		}
+		uh, err := ring.AddKey(sk, pk)
+		if err != nil {
+			cli.Die("Failed to add the secret key:", err)
+		}
+		uhs = append(uhs, uh)
+	}
+	if mnemonic != "" {
+		seed, err := modules.InitialSeedFromMnemonic(mnemonic)
+		if err != nil {
+			cli.DieWithExitCode(cli.ExitCodeUsage, "Invalid mnemonic given:", err)
+		}
+		for index := uint64(0); index < cfg.KeyDepth; index++ {
+			sk, pk, err := seed.SpendableKey(index)
+			if err != nil {
+				cli.Die("Failed to derive the keys of the seed:", err)
+			}
+			uh, err := ring.AddKey(sk, pk)
+			if err != nil {
+				cli.Die("Failed to derive the keys of the seed:", err)
+			}
+			uhs = append(uhs, uh)
+		}
+	}
+
+	lookup := walletCmd.cli.HTTPClient
+	if cfg.Explorer != "" {
+		lookup = &api.HTTPClient{
+			RootURL:   cfg.Explorer,
+			UserAgent: walletCmd.cli.HTTPClient.UserAgent,
+		}
+	}
+	recovered, err := scanUnspentOutputs(lookup, uhs)
+	if err != nil {
+		cli.DieWithError("Failed to look up the unspent outputs:", err)
+	}
+	printRecoveredOutputs(os.Stdout, recovered, len(uhs), walletCmd.cli.CreateCurrencyConvertor())
+	if len(recovered.Addresses) == 0 {
+		return
+	}
+
+	// only request a new wallet address once there is something to sweep
+	addr := new(api.WalletAddressGET)
+	if err = walletCmd.cli.GetAPI("/wallet/address", addr); err != nil {
+		cli.DieWithError("Could not generate a new wallet address to sweep to:", err)
+	}
+	condition := types.NewCondition(types.NewUnlockHashCondition(addr.Address))
+	sweepRecoveredOutputs(walletCmd.cli, recovered, condition, ring, cfg.MaxInputs, cfg.DryRun)
+}
+
+// isHexSecretKey returns true if the given string is a hex-encoded (seed of a) secret key,
+// rather than a mnemonic.
+func isHexSecretKey(str string) bool {
+	b, err := hex.DecodeString(strings.TrimSpace(str))
+	return err == nil && (len(b) == crypto.SecretKeySize || len(b) == crypto.EntropySize)
+}
+
+// parseSecretKey parses a hex-encoded ed25519 secret key, or the (32 byte) seed it is derived from,
+// returning the secret key together with its public key.
+func parseSecretKey(str string) (crypto.SecretKey, crypto.PublicKey, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(str))
+	if err != nil {
+		return crypto.SecretKey{}, crypto.PublicKey{}, err
+	}
+	if len(b) != crypto.SecretKeySize && len(b) != crypto.EntropySize {
+		return crypto.SecretKey{}, crypto.PublicKey{}, fmt.Errorf(
+			"secret key has an invalid length of %d bytes, expected %d (or %d) bytes",
+			len(b), crypto.SecretKeySize, crypto.EntropySize)
+	}
+	var entropy [crypto.EntropySize]byte
+	copy(entropy[:], b)
+	sk, pk := crypto.GenerateKeyPairDeterministic(entropy)
+	if len(b) == crypto.SecretKeySize && !bytes.Equal(b, sk[:]) {
+		return crypto.SecretKey{}, crypto.PublicKey{}, errors.New("public key part of the secret key does not match its seed")
+	}
+	return sk, pk, nil
+}
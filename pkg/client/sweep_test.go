@@ -0,0 +1,45 @@
+package client
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+func TestParseSecretKey(t *testing.T) {
+	esk, epk := crypto.GenerateKeyPair()
+	for idx, str := range []string{
+		hex.EncodeToString(esk[:]),
+		hex.EncodeToString(esk[:crypto.EntropySize]),
+		" " + hex.EncodeToString(esk[:]) + "\n",
+	} {
+		if !isHexSecretKey(str) {
+			t.Errorf("#%d: %q not recognized as a secret key", idx, str)
+		}
+		sk, pk, err := parseSecretKey(str)
+		if err != nil {
+			t.Errorf("#%d: failed to parse %q: %v", idx, str, err)
+			continue
+		}
+		if sk != esk || pk != epk {
+			t.Errorf("#%d: unexpected key pair parsed from %q", idx, str)
+		}
+	}
+
+	corrupt := esk
+	corrupt[crypto.SecretKeySize-1]++
+	for idx, str := range []string{
+		"",
+		"abc",
+		hex.EncodeToString(esk[:crypto.EntropySize-1]),
+		hex.EncodeToString(corrupt[:]),
+	} {
+		if _, _, err := parseSecretKey(str); err == nil {
+			t.Errorf("#%d: expected %q to fail to parse", idx, str)
+		}
+	}
+	if isHexSecretKey("carbon boss inject cover") {
+		t.Error("mnemonic recognized as a secret key")
+	}
+}
@@ -37,3 +37,13 @@ func (tpool *TransactionPoolClient) AddTransactiom(t types.Transaction) (types.T
 	}
 	return resp.TransactionID, nil
 }
+
+// GetDoubleSpendProofs returns the proofs of all double spends detected by the transaction pool.
+func (tpool *TransactionPoolClient) GetDoubleSpendProofs() ([]types.DoubleSpendProof, error) {
+	var resp rivineapi.TransactionPoolGetDoubleSpends
+	err := tpool.client.GetAPI("/transactionpool/doublespends", &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Proofs, nil
+}
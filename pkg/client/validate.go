@@ -0,0 +1,168 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func createValidateCmd(client *CommandLineClient) *cobra.Command {
+	validateCmd := &validateCmd{cli: client}
+
+	// create root validate command and all subs
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "validate",
+			Short: "Validate objects",
+			Long:  "Validate objects locally, without requiring a daemon.",
+			// Run field is not set, as the validate command itself is not a valid command.
+			// A subcommand must be provided.
+		}
+		addressCmd = &cobra.Command{
+			Use:   "address <address>|<rawCondition>",
+			Short: "Validate and inspect an address",
+			Long: `Validate an address (unlock hash), given in hex or bech32 format,
+verifying its checksum and reporting its type (e.g. singlesig, multisig or atomicswap).
+
+A raw (JSON-encoded) condition can be given as well,
+in which case the condition is validated and printed together with its address.
+
+The address is validated locally, without requiring a daemon.
+`,
+			Run: Wrap(validateCmd.addressCmd),
+		}
+	)
+	rootCmd.AddCommand(addressCmd)
+
+	// create flags
+	addressCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &validateCmd.addressCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	// return root command
+	return rootCmd
+}
+
+type validateCmd struct {
+	cli        *CommandLineClient
+	addressCfg struct {
+		EncodingType cli.EncodingType
+	}
+}
+
+// AddressInfo is the result of inspecting an address (or condition),
+// printed as JSON when the JSON encoding type is used.
+type AddressInfo struct {
+	UnlockHash types.UnlockHash `json:"unlockhash"`
+	// Bech32 is the bech32 format of the address,
+	// only defined in case a bech32 address prefix is registered for the chain
+	Bech32   string           `json:"bech32,omitempty"`
+	Type     types.UnlockType `json:"type"`
+	TypeName string           `json:"typename"`
+	// Condition is only defined in case a raw condition was inspected
+	Condition *types.UnlockConditionProxy `json:"condition,omitempty"`
+}
+
+// InspectAddress validates and inspects an address or raw (JSON-encoded) condition,
+// the address can be in the bech32 format as well if the (chain's) bech32 prefix is given.
+// An error is returned if the address is invalid (e.g. its checksum doesn't match)
+// or the condition can't be decoded.
+func InspectAddress(str, bech32Prefix string) (AddressInfo, error) {
+	var info AddressInfo
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "{") {
+		var condition types.UnlockConditionProxy
+		if err := json.Unmarshal([]byte(str), &condition); err != nil {
+			return AddressInfo{}, fmt.Errorf("invalid condition: %v", err)
+		}
+		info.UnlockHash = condition.UnlockHash()
+		info.Condition = &condition
+	} else if err := info.UnlockHash.LoadAddressString(str, bech32Prefix); err != nil {
+		return AddressInfo{}, fmt.Errorf("invalid address: %v", err)
+	}
+	info.Type = info.UnlockHash.Type
+	info.TypeName = UnlockTypeName(info.Type)
+	if bech32Prefix != "" {
+		info.Bech32, _ = info.UnlockHash.Bech32String(bech32Prefix)
+	}
+	return info, nil
+}
+
+// UnlockTypeName returns the human-readable name of the given unlock type.
+func UnlockTypeName(t types.UnlockType) string {
+	switch t {
+	case types.UnlockTypeNil:
+		return "nil"
+	case types.UnlockTypePubKey:
+		return "singlesig"
+	case types.UnlockTypeAtomicSwap:
+		return "atomicswap"
+	case types.UnlockTypeMultiSig:
+		return "multisig"
+	case types.UnlockTypeBurn:
+		return "burn"
+	default:
+		return "unknown"
+	}
+}
+
+// addressCmd is the handler for the command `rivinec validate address`,
+// validating an address (or condition) and printing its type.
+func (cmd *validateCmd) addressCmd(str string) {
+	info, err := InspectAddress(str, cmd.cli.Config.Bech32AddressPrefix)
+	if err != nil {
+		cli.Die(err)
+	}
+
+	switch cmd.addressCfg.EncodingType {
+	case cli.EncodingTypeJSON:
+		json.NewEncoder(os.Stdout).Encode(info)
+	default:
+		printAddressInfo(os.Stdout, info)
+	}
+}
+
+// printAddressInfo prints the given address info in a human-readable format.
+func printAddressInfo(w io.Writer, info AddressInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "Address:\t%s\n", info.UnlockHash.String())
+	if info.Bech32 != "" {
+		fmt.Fprintf(tw, "Bech32:\t%s\n", info.Bech32)
+	}
+	fmt.Fprintf(tw, "Type:\t%s (%d)\n", info.TypeName, info.Type)
+	if info.TypeName == "unknown" {
+		fmt.Fprintf(tw, "Note:\tthe checksum is valid, but the unlock type isn't known to this client\n")
+	}
+	if info.Condition == nil {
+		return
+	}
+	fmt.Fprintf(tw, "Condition:\t%s (type %d)\n", goTypeName(info.Condition.Condition), info.Condition.ConditionType())
+	if info.Condition.Condition != nil {
+		b, _ := json.Marshal(info.Condition.Condition)
+		fmt.Fprintf(tw, "  data:\t%s\n", b)
+	}
+	condition := info.Condition.Condition
+	for condition != nil {
+		// print the signatories of (time locked) multisig conditions
+		if getter, ok := condition.(types.UnlockHashSliceGetter); ok {
+			for idx, uh := range getter.UnlockHashSlice() {
+				fmt.Fprintf(tw, "  #%d:\t%s (%s)\n", idx, uh.String(), UnlockTypeName(uh.Type))
+			}
+		}
+		getter, ok := condition.(types.MarshalableUnlockConditionGetter)
+		if !ok {
+			break
+		}
+		condition = getter.GetMarshalableUnlockCondition()
+	}
+}
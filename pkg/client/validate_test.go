@@ -0,0 +1,56 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestInspectAddress(t *testing.T) {
+	const address = "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+	info, err := InspectAddress(address, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.UnlockHash.String() != address || info.Type != types.UnlockTypePubKey || info.TypeName != "singlesig" || info.Condition != nil {
+		t.Errorf("unexpected address info: %v", info)
+	}
+
+	condition := `{"type":4,"data":{"unlockhashes":["` + address + `","01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e"],"minimumsignaturecount":1}}`
+	info, err = InspectAddress(condition, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != types.UnlockTypeMultiSig || info.TypeName != "multisig" || info.Condition == nil {
+		t.Errorf("unexpected condition info: %v", info)
+	}
+	if info.UnlockHash != info.Condition.UnlockHash() {
+		t.Errorf("unexpected unlock hash of condition: %s", info.UnlockHash.String())
+	}
+
+	// bech32 addresses are accepted only if the prefix of the chain is given
+	info, err = InspectAddress(address, "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = InspectAddress(info.Bech32, "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.UnlockHash.String() != address {
+		t.Errorf("unexpected unlock hash of bech32 address: %s", info.UnlockHash.String())
+	}
+	if _, err = InspectAddress(info.Bech32, ""); err == nil {
+		t.Error("inspecting a bech32 address without prefix should fail")
+	}
+
+	for _, invalid := range []string{
+		"015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6e", // invalid checksum
+		"015a080a",
+		`{"type":4,"data":{"unlockhashes":"invalid"}}`,
+	} {
+		if _, err = InspectAddress(invalid, ""); err == nil {
+			t.Errorf("inspecting %q should fail", invalid)
+		}
+	}
+}
@@ -51,6 +51,21 @@ func (wallet *WalletClient) FundCoins(amount types.Currency, refundAddress *type
 	return result.CoinInputs, result.RefundCoinOutput, nil
 }
 
+// EstimateTransactionFee estimates the size the given transaction will have once signed,
+// as well as the fee to be paid for it.
+func (wallet *WalletClient) EstimateTransactionFee(t types.Transaction) (int, types.Currency, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return 0, types.Currency{}, err
+	}
+	var result api.WalletEstimatePOSTResp
+	err = wallet.client.PostResp("/wallet/estimate", string(b), &result)
+	if err != nil {
+		return 0, types.Currency{}, fmt.Errorf("failed to estimate transaction fee: %v", err)
+	}
+	return result.Size, result.Fee, nil
+}
+
 // GreedySignTx signs the given transactions greedy,
 // meaning that all fulfillments that can be signed, will be signed.
 func (wallet *WalletClient) GreedySignTx(t *types.Transaction) error {
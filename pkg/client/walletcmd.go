@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"os"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/bgentry/speakeasy"
@@ -136,10 +137,8 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	instead of an unlockHash, you can also give a JSON-encoded UnlockCondition directly,
 	giving you more control and options over how exactly the block stake is to be unlocked.
 	
-	Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
-	Decimals are possible and are to be expressed using English conventions.
-	
-	Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+	Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency or a sub-unit SI prefix only (e.g. 250m).
+	Amounts which look like a value expressed in the smallest unit have to be suffixed with the unit of currency explicitly.
 	Decimals are possible and have to be defined using the decimal point.
 	
 	The Minimum Miner Fee will be added on top of the total given amount automatically.
@@ -154,7 +153,7 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	instead of an unlockHash, you can also give a JSON-encoded UnlockCondition directly,
 	giving you more control and options over how exactly the block stake is to be unlocked.
 	
-	Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+	Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency.
 	Decimals are possible and have to be defined using the decimal point.
 	
 	The Minimum Miner Fee will be added on top of the total given amount automatically.
@@ -209,6 +208,24 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 			Args: cobra.MinimumNArgs(3),
 			Run:  walletCmd.createMultisigAddressesCmd,
 		}
+		createColdStakingConditionCmd = &cobra.Command{
+			Use:   "coldstakingcondition <owner> <staker>",
+			Short: "Create a cold staking condition",
+			Long: `Create a cold staking condition, which can be used as the raw condition of block stake outputs.
+	The owner is the only one who can spend these block stakes, while the staker
+	can only use them to create blocks. This allows the owner's wallet to be kept in cold storage.`,
+			Args: cobra.ExactArgs(2),
+			Run:  walletCmd.createColdStakingConditionCmd,
+		}
+		createWeightedMultisigConditionCmd = &cobra.Command{
+			Use:   "weightedmultisigcondition <minweight> <address1>:<weight1> <address2>:<weight2> [<address>:<weight>]...",
+			Short: "Create a weighted multisig condition",
+			Long: `Create a weighted multisig condition, which can be used as the raw condition of coin and block stake outputs.
+	Each address is given a weight, and the output can only be spent once the total weight
+	of the addresses who signed reaches <minweight>.`,
+			Args: cobra.MinimumNArgs(3),
+			Run:  walletCmd.createWeightedMultisigConditionCmd,
+		}
 		createCoinTxCmd = &cobra.Command{
 			Use:   "cointransaction <parentID>... <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...",
 			Short: "Create a new coin transaction",
@@ -216,7 +233,8 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	The outputs can be given as a pair of value and a raw output condition (or
 	address, which resolved to a singlesignature condition).
 	
-	Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+	Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency or a sub-unit SI prefix only (e.g. 250m).
+	Amounts which look like a value expressed in the smallest unit have to be suffixed with the unit of currency explicitly.
 	Decimals are possible and have to be defined using the decimal point.
 	
 	The Minimum Miner Fee will be added on top of the total given amount automatically.
@@ -230,7 +248,7 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	The outputs can be given as a pair of value and a raw output condition (or
 	address, which resolved to a singlesignature condition).
 	
-	Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+	Amounts have to be given expressed in the OneCoin unit, unless suffixed with the (optionally SI-prefixed) unit of currency.
 	Decimals are possible and have to be defined using the decimal point.
 	
 	The Minimum Miner Fee will be added on top of the total given amount automatically.
@@ -257,6 +275,8 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 		listCmd,
 		createCmd,
 		signTxCmd)
+	rootCmd.AddCommand(walletCmd.createOfflineTxCmds()...)
+	rootCmd.AddCommand(walletCmd.createSweepCmd())
 
 	sendCmd.AddCommand(
 		sendCoinsCmd,
@@ -268,13 +288,24 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	listCmd.AddCommand(
 		listUnlockedCmd,
 		listLockedCmd)
+	listCmd.PersistentFlags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &walletCmd.listOutputsCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV))
 
 	createCmd.AddCommand(
 		createMultisigAddressesCmd,
+		createColdStakingConditionCmd,
+		createWeightedMultisigConditionCmd,
 		createCoinTxCmd,
 		createBlockStakeTxCmd)
 
 	// define config of commands that have a config
+	addressesCmd.Flags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &walletCmd.addressesCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV))
+	listTransactionsCmd.Flags().Var(
+		clipkg.NewEncodingTypeFlag(clipkg.EncodingTypeHuman, &walletCmd.listTransactionsCfg.EncodingType, clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV), "encoding",
+		clipkg.EncodingTypeFlagDescription(clipkg.EncodingTypeHuman|clipkg.EncodingTypeCSV))
 	initCmd.Flags().BoolVar(
 		&walletCmd.walletInitCfg.Plain,
 		"plain", false, "create a plain wallet, requiring no passphrase")
@@ -291,11 +322,9 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 		&walletCmd.walletLoadSeedCfg.Seed,
 		"seed", "", "define the seed to be loaded as a flag instead of the STDIN")
 
-	// custom arbitrarydata flag
-	clipkg.ArbitraryDataFlagVar(sendCoinsCmd.Flags(), &walletCmd.sendCoinsCfg.Data,
-		"data", "optional arbitrary data (or description) to attach to transaction")
-	clipkg.ArbitraryDataFlagVar(sendBlockStakesCmd.Flags(), &walletCmd.sendBlockStakesCfg.Data,
-		"data", "optional arbitrary data (or description) to attach to transaction")
+	// custom arbitrarydata flags
+	walletCmd.sendCoinsCfg.Data.register(sendCoinsCmd.Flags())
+	walletCmd.sendBlockStakesCfg.Data.register(sendBlockStakesCmd.Flags())
 
 	// other custom send coins flags
 	sendCoinsCmd.Flags().StringVar(
@@ -304,6 +333,9 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	sendCoinsCmd.Flags().BoolVar(
 		&walletCmd.sendCoinsCfg.RefundAddressNew,
 		"refund-address-new", false, "generate a new refund address if a refund needs to happen")
+	sendCoinsCmd.Flags().BoolVar(
+		&walletCmd.sendCoinsCfg.DryRun,
+		"dry-run", false, "create and validate the transaction, printing it and its fees, without publishing it")
 
 	// other custom send blockstkars flags
 	sendBlockStakesCmd.Flags().StringVar(
@@ -312,6 +344,9 @@ func createWalletCmd(cli *CommandLineClient) *WalletCommand {
 	sendBlockStakesCmd.Flags().BoolVar(
 		&walletCmd.sendBlockStakesCfg.RefundAddressNew,
 		"refund-address-new", false, "generate a new refund address if a refund needs to happen")
+	sendBlockStakesCmd.Flags().BoolVar(
+		&walletCmd.sendBlockStakesCfg.DryRun,
+		"dry-run", false, "create and validate the transaction, printing it and its fees, without publishing it")
 
 	// return root command
 	return &WalletCommand{
@@ -337,14 +372,16 @@ type WalletCommand struct {
 type walletCmd struct {
 	cli          *CommandLineClient
 	sendCoinsCfg struct {
-		Data             []byte
+		Data             arbitraryDataFlags
 		RefundAddress    string
 		RefundAddressNew bool
+		DryRun           bool
 	}
 	sendBlockStakesCfg struct {
-		Data             []byte
+		Data             arbitraryDataFlags
 		RefundAddress    string
 		RefundAddressNew bool
+		DryRun           bool
 	}
 	walletInitCfg struct {
 		Plain bool
@@ -357,6 +394,33 @@ type walletCmd struct {
 		Plain bool
 		Seed  string
 	}
+	createOfflineTxCfg struct {
+		From          []string
+		RefundAddress string
+		Data          arbitraryDataFlags
+	}
+	signOfflineTxCfg struct {
+		Seed     string
+		KeyDepth uint64
+		Out      string
+	}
+	sweepCfg struct {
+		Keys      []string
+		Seed      string
+		KeyDepth  uint64
+		Explorer  string
+		MaxInputs int
+		DryRun    bool
+	}
+	addressesCfg struct {
+		EncodingType cli.EncodingType
+	}
+	listTransactionsCfg struct {
+		EncodingType cli.EncodingType
+	}
+	listOutputsCfg struct {
+		EncodingType cli.EncodingType
+	}
 }
 
 // addressCmd fetches a new address from the wallet that will be able to
@@ -368,6 +432,13 @@ func (walletCmd *walletCmd) addressCmd() {
 		cli.DieWithError("Could not generate new address:", err)
 	}
 	fmt.Printf("Created new address: %s\n", addr.Address)
+	if prefix := walletCmd.cli.Config.Bech32AddressPrefix; prefix != "" {
+		str, err := addr.Address.Bech32String(prefix)
+		if err != nil {
+			cli.DieWithError("Could not encode new address as a bech32 address:", err)
+		}
+		fmt.Printf("Bech32 address: %s\n", str)
+	}
 }
 
 // addressesCmd fetches the list of addresses that the wallet knows.
@@ -377,6 +448,29 @@ func (walletCmd *walletCmd) addressesCmd() {
 	if err != nil {
 		cli.DieWithError("Failed to fetch addresses:", err)
 	}
+	if walletCmd.addressesCfg.EncodingType == cli.EncodingTypeCSV {
+		header := []string{"address"}
+		prefix := walletCmd.cli.Config.Bech32AddressPrefix
+		if prefix != "" {
+			header = append(header, "bech32")
+		}
+		records := make([][]string, 0, len(addrs.Addresses))
+		for _, addr := range addrs.Addresses {
+			record := []string{addr.String()}
+			if prefix != "" {
+				str, err := addr.Bech32String(prefix)
+				if err != nil {
+					cli.DieWithError("Could not encode address as a bech32 address:", err)
+				}
+				record = append(record, str)
+			}
+			records = append(records, record)
+		}
+		if err = cli.WriteCSV(os.Stdout, header, records); err != nil {
+			cli.Die("Failed to write addresses as CSV:", err)
+		}
+		return
+	}
 	for _, addr := range addrs.Addresses {
 		fmt.Println(addr)
 	}
@@ -541,15 +635,20 @@ func (walletCmd *walletCmd) seedsCmd() {
 // sendCoinsCmd sends siacoins to one or multiple destination addresses.
 func (walletCmd *walletCmd) sendCoinsCmd(cmd *cobra.Command, args []string) {
 	currencyConvertor := walletCmd.cli.CreateCurrencyConvertor()
-	pairs, err := parsePairedOutputs(args, currencyConvertor.ParseCoinString)
+	pairs, err := parsePairedOutputs(args, currencyConvertor.ParseCoinString, walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
 	}
+	data, err := walletCmd.sendCoinsCfg.Data.ArbitraryData(walletCmd.cli.Config)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
 
 	body := api.WalletCoinsPOST{
 		CoinOutputs: make([]types.CoinOutput, len(pairs)),
-		Data:        []byte(walletCmd.sendCoinsCfg.Data),
+		Data:        data,
+		DryRun:      walletCmd.sendCoinsCfg.DryRun,
 	}
 	for i, pair := range pairs {
 		body.CoinOutputs[i] = types.CoinOutput{
@@ -560,7 +659,7 @@ func (walletCmd *walletCmd) sendCoinsCmd(cmd *cobra.Command, args []string) {
 	if walletCmd.sendCoinsCfg.RefundAddress != "" {
 		// use the specified address as the refund address if a refund has to happen
 		var uh types.UnlockHash
-		err = uh.LoadString(walletCmd.sendCoinsCfg.RefundAddress)
+		err = uh.LoadAddressString(walletCmd.sendCoinsCfg.RefundAddress, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.DieWithError("invalid refund address specified", err)
 		}
@@ -579,6 +678,10 @@ func (walletCmd *walletCmd) sendCoinsCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.DieWithError("Could not send coins:", err)
 	}
+	if resp.Transaction != nil {
+		printDryRunTransaction(*resp.Transaction, currencyConvertor)
+		return
+	}
 	fmt.Println("Succesfully sent coins as transaction " + resp.TransactionID.String())
 	for _, co := range body.CoinOutputs {
 		fmt.Printf("Sent %s to %s (using ConditionType %d)\n",
@@ -589,15 +692,20 @@ func (walletCmd *walletCmd) sendCoinsCmd(cmd *cobra.Command, args []string) {
 
 // sendBlockStakesCmd sends block stakes to one or multiple destination addresses.
 func (walletCmd *walletCmd) sendBlockStakesCmd(cmd *cobra.Command, args []string) {
-	pairs, err := parsePairedOutputs(args, stringToBlockStakes)
+	pairs, err := parsePairedOutputs(args, stringToBlockStakes, walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
 	}
+	data, err := walletCmd.sendBlockStakesCfg.Data.ArbitraryData(walletCmd.cli.Config)
+	if err != nil {
+		cli.DieWithExitCode(cli.ExitCodeUsage, err)
+	}
 
 	body := api.WalletBlockStakesPOST{
 		BlockStakeOutputs: make([]types.BlockStakeOutput, len(pairs)),
-		Data:              []byte(walletCmd.sendBlockStakesCfg.Data),
+		Data:              data,
+		DryRun:            walletCmd.sendBlockStakesCfg.DryRun,
 	}
 	for i, pair := range pairs {
 		body.BlockStakeOutputs[i] = types.BlockStakeOutput{
@@ -608,7 +716,7 @@ func (walletCmd *walletCmd) sendBlockStakesCmd(cmd *cobra.Command, args []string
 	if walletCmd.sendBlockStakesCfg.RefundAddress != "" {
 		// use the specified address as the refund address if a refund has to happen
 		var uh types.UnlockHash
-		err = uh.LoadString(walletCmd.sendBlockStakesCfg.RefundAddress)
+		err = uh.LoadAddressString(walletCmd.sendBlockStakesCfg.RefundAddress, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.DieWithError("invalid refund address specified", err)
 		}
@@ -627,6 +735,10 @@ func (walletCmd *walletCmd) sendBlockStakesCmd(cmd *cobra.Command, args []string
 	if err != nil {
 		cli.DieWithError("Could not send block stakes:", err)
 	}
+	if resp.Transaction != nil {
+		printDryRunTransaction(*resp.Transaction, walletCmd.cli.CreateCurrencyConvertor())
+		return
+	}
 	fmt.Println("Succesfully sent blockstakes as transaction " + resp.TransactionID.String())
 	for _, bo := range body.BlockStakeOutputs {
 		fmt.Printf("Sent %s BS to %s (using ConditionType %d)\n",
@@ -634,6 +746,22 @@ func (walletCmd *walletCmd) sendBlockStakesCmd(cmd *cobra.Command, args []string
 	}
 }
 
+// printDryRunTransaction prints a transaction which was created and validated,
+// but not published, together with the fees it pays.
+func printDryRunTransaction(txn types.Transaction, currencyConvertor CurrencyConvertor) {
+	var fees types.Currency
+	for _, fee := range txn.MinerFees {
+		fees = fees.Add(fee)
+	}
+	b, err := json.MarshalIndent(txn, "", "  ")
+	if err != nil {
+		cli.Die("Failed to JSON Marshal the transaction:", err)
+	}
+	fmt.Printf("Dry run: transaction %s is valid, but was not published\n", txn.ID().String())
+	fmt.Printf("Miner fees: %s\n", currencyConvertor.ToCoinStringWithUnit(fees))
+	fmt.Println(string(b))
+}
+
 type outputPair struct {
 	Condition types.UnlockConditionProxy
 	Value     types.Currency
@@ -647,7 +775,7 @@ func stringToBlockStakes(input string) (types.Currency, error) {
 	return types.NewCurrency64(bsv), err
 }
 
-func parsePairedOutputs(args []string, parseCurrency parseCurrencyString) (pairs []outputPair, err error) {
+func parsePairedOutputs(args []string, parseCurrency parseCurrencyString, bech32Prefix string) (pairs []outputPair, err error) {
 	argn := len(args)
 	if argn < 2 {
 		err = errors.New("not enough arguments, at least 2 required")
@@ -669,7 +797,7 @@ func parsePairedOutputs(args []string, parseCurrency parseCurrencyString) (pairs
 
 		// try to parse it as an unlock hash
 		var uh types.UnlockHash
-		err = uh.LoadString(args[i])
+		err = uh.LoadAddressString(args[i], bech32Prefix)
 		if err == nil {
 			// parsing as an unlock hash was succesfull, store the pair and continue to the next pair
 			pair.Condition = types.NewCondition(types.NewUnlockHashCondition(uh))
@@ -838,12 +966,18 @@ func (walletCmd *walletCmd) listTransactionsCmd() {
 	multiSigWalletTxns := make(map[types.UnlockHash][]modules.ProcessedTransaction)
 	txns := append(wtg.ConfirmedTransactions, wtg.UnconfirmedTransactions...)
 
-	if len(txns) == 0 {
+	csvOutput := walletCmd.listTransactionsCfg.EncodingType == cli.EncodingTypeCSV
+	var records [][]string
+	currencyConvertor := walletCmd.cli.CreateCurrencyConvertor()
+
+	if len(txns) == 0 && !csvOutput {
 		fmt.Println("This wallet has no transaction related to it.")
 		return
 	}
 
-	fmt.Println("    [height]                                                   [transaction id]       [net coins]   [net blockstakes]")
+	if !csvOutput {
+		fmt.Println("    [height]                                                   [transaction id]       [net coins]   [net blockstakes]")
+	}
 	for _, txn := range txns {
 		var relatedMultiSigUnlockHashes []types.UnlockHash
 		// Determine the number of outgoing siacoins and siafunds.
@@ -893,6 +1027,11 @@ func (walletCmd *walletCmd) listTransactionsCmd() {
 		if !rootWalletOwned {
 			continue
 		}
+		if csvOutput {
+			records = append(records, transactionCSVRecord("wallet", txn, currencyConvertor,
+				incomingSiacoins, outgoingSiacoins, incomingBlockStakes, outgoingBlockStakes))
+			continue
+		}
 
 		// Convert the siacoins to a float.
 		incomingSiacoinsFloat, _ := new(big.Rat).SetFrac(incomingSiacoins.Big(), walletCmd.cli.Config.CurrencyUnits.OneCoin.Big()).Float64()
@@ -913,13 +1052,15 @@ func (walletCmd *walletCmd) listTransactionsCmd() {
 	if len(multiSigWalletTxns) > 0 {
 		for uh, txns := range multiSigWalletTxns {
 			for _, txn := range txns {
-				fmt.Println()
-				fmt.Println("=====================================================================================================================")
-				fmt.Println()
-
-				fmt.Println("Wallet Address:", uh)
-				fmt.Println()
-				fmt.Println("    [height]                                             [transaction/block id]       [net coins]   [net blockstakes]")
+				if !csvOutput {
+					fmt.Println()
+					fmt.Println("=====================================================================================================================")
+					fmt.Println()
+
+					fmt.Println("Wallet Address:", uh)
+					fmt.Println()
+					fmt.Println("    [height]                                             [transaction/block id]       [net coins]   [net blockstakes]")
+				}
 
 				// Determine the number of outgoing siacoins and siafunds.
 				var outgoingSiacoins types.Currency
@@ -948,6 +1089,12 @@ func (walletCmd *walletCmd) listTransactionsCmd() {
 					}
 				}
 
+				if csvOutput {
+					records = append(records, transactionCSVRecord(uh.String(), txn, currencyConvertor,
+						incomingSiacoins, outgoingSiacoins, incomingBlockStakes, outgoingBlockStakes))
+					continue
+				}
+
 				// Convert the siacoins to a float.
 				incomingSiacoinsFloat, _ := new(big.Rat).SetFrac(incomingSiacoins.Big(),
 					walletCmd.cli.Config.CurrencyUnits.OneCoin.Big()).Float64()
@@ -968,6 +1115,33 @@ func (walletCmd *walletCmd) listTransactionsCmd() {
 			}
 		}
 	}
+
+	if csvOutput {
+		err = cli.WriteCSV(os.Stdout,
+			[]string{"address", "height", "transactionid", "netcoins", "netblockstakes"}, records)
+		if err != nil {
+			cli.Die("Failed to write transactions as CSV:", err)
+		}
+	}
+}
+
+// transactionCSVRecord returns the CSV record of a transaction listed by `wallet transactions`,
+// containing the exact net flow of coins and blockstakes for the given address.
+func transactionCSVRecord(address string, txn modules.ProcessedTransaction, cc CurrencyConvertor, incomingCoins, outgoingCoins, incomingBlockStakes, outgoingBlockStakes types.Currency) []string {
+	height := "unconfirmed"
+	if txn.ConfirmationHeight < 1e9 {
+		height = strconv.FormatUint(uint64(txn.ConfirmationHeight-1), 10)
+	}
+	netBlockStakes := new(big.Int).Sub(incomingBlockStakes.Big(), outgoingBlockStakes.Big()).String()
+	return []string{address, height, txn.TransactionID.String(), netCoinString(cc, incomingCoins, outgoingCoins), netBlockStakes}
+}
+
+// netCoinString returns the exact net flow of coins as a signed coin string (e.g. -1.5).
+func netCoinString(cc CurrencyConvertor, incoming, outgoing types.Currency) string {
+	if incoming.Cmp(outgoing) < 0 {
+		return "-" + cc.ToCoinString(outgoing.Sub(incoming))
+	}
+	return cc.ToCoinString(incoming.Sub(outgoing))
 }
 
 // unlockCmd unlocks a saved wallet
@@ -1005,7 +1179,7 @@ func (walletCmd *walletCmd) listUnlockedCmd(_ *cobra.Command, args []string) {
 		addressGiven = len(args) == 1
 	)
 	if addressGiven {
-		err = address.LoadString(args[0])
+		err = address.LoadAddressString(args[0], walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.Die("failed to parse given wallet address: ", err)
 		}
@@ -1039,6 +1213,14 @@ func (walletCmd *walletCmd) listUnlockedCmd(_ *cobra.Command, args []string) {
 		}
 	}
 
+	if walletCmd.listOutputsCfg.EncodingType == cli.EncodingTypeCSV {
+		err = writeOutputsCSV(currencyConvertor, resp.UnlockedCoinOutputs, resp.UnlockedBlockstakeOutputs)
+		if err != nil {
+			cli.Die("Failed to write unlocked outputs as CSV:", err)
+		}
+		return
+	}
+
 	if len(resp.UnlockedBlockstakeOutputs) == 0 && len(resp.UnlockedCoinOutputs) == 0 {
 		if addressGiven {
 			fmt.Println("No unlocked outputs matched to address: " + address.String())
@@ -1082,7 +1264,7 @@ func (walletCmd *walletCmd) listLockedCmd(_ *cobra.Command, args []string) {
 		addressGiven = len(args) == 1
 	)
 	if addressGiven {
-		err = address.LoadString(args[0])
+		err = address.LoadAddressString(args[0], walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.Die("failed to parse given wallet address: ", err)
 		}
@@ -1116,6 +1298,14 @@ func (walletCmd *walletCmd) listLockedCmd(_ *cobra.Command, args []string) {
 		}
 	}
 
+	if walletCmd.listOutputsCfg.EncodingType == cli.EncodingTypeCSV {
+		err = writeOutputsCSV(currencyConvertor, resp.LockedCoinOutputs, resp.LockedBlockstakeOutputs)
+		if err != nil {
+			cli.Die("Failed to write locked outputs as CSV:", err)
+		}
+		return
+	}
+
 	if len(resp.LockedBlockstakeOutputs) == 0 && len(resp.LockedCoinOutputs) == 0 {
 		if addressGiven {
 			fmt.Println("No locked outputs matched to address: " + address.String())
@@ -1150,6 +1340,31 @@ func (walletCmd *walletCmd) listLockedCmd(_ *cobra.Command, args []string) {
 	}
 }
 
+// writeOutputsCSV writes the given unspent coin and blockstake outputs as CSV to the STDOUT,
+// one record per output, with the condition of the output encoded as JSON.
+func writeOutputsCSV(cc CurrencyConvertor, cos []api.UnspentCoinOutput, bsos []api.UnspentBlockstakeOutput) error {
+	records := make([][]string, 0, len(cos)+len(bsos))
+	for _, co := range cos {
+		condition, err := json.Marshal(co.Output.Condition)
+		if err != nil {
+			return err
+		}
+		records = append(records, []string{
+			"coin", co.ID.String(), cc.ToCoinString(co.Output.Value),
+			co.Output.Condition.UnlockHash().String(), string(condition)})
+	}
+	for _, bso := range bsos {
+		condition, err := json.Marshal(bso.Output.Condition)
+		if err != nil {
+			return err
+		}
+		records = append(records, []string{
+			"blockstake", bso.ID.String(), bso.Output.Value.String(),
+			bso.Output.Condition.UnlockHash().String(), string(condition)})
+	}
+	return cli.WriteCSV(os.Stdout, []string{"type", "id", "value", "unlockhash", "condition"}, records)
+}
+
 func (walletCmd *walletCmd) createMultisigAddressesCmd(cmd *cobra.Command, args []string) {
 	msr, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
@@ -1163,7 +1378,7 @@ func (walletCmd *walletCmd) createMultisigAddressesCmd(cmd *cobra.Command, args
 	uhs := types.UnlockHashSlice{}
 	var uh types.UnlockHash
 	for _, addr := range args[1:] {
-		err = uh.LoadString(addr)
+		err = uh.LoadAddressString(addr, walletCmd.cli.Config.Bech32AddressPrefix)
 		if err != nil {
 			cli.Die("Failed to load unlock hash:", err)
 		}
@@ -1174,6 +1389,63 @@ func (walletCmd *walletCmd) createMultisigAddressesCmd(cmd *cobra.Command, args
 	fmt.Println("Multisig address:", multiSigCond.UnlockHash())
 }
 
+func (walletCmd *walletCmd) createColdStakingConditionCmd(cmd *cobra.Command, args []string) {
+	var owner, staker types.UnlockHash
+	if err := owner.LoadAddressString(args[0], walletCmd.cli.Config.Bech32AddressPrefix); err != nil {
+		cli.Die("Failed to load owner unlock hash:", err)
+	}
+	if err := staker.LoadAddressString(args[1], walletCmd.cli.Config.Bech32AddressPrefix); err != nil {
+		cli.Die("Failed to load staker unlock hash:", err)
+	}
+
+	condition := types.NewCondition(types.NewColdStakingCondition(owner, staker))
+	if err := condition.IsStandardCondition(types.ValidationContext{}); err != nil {
+		cli.Die("Invalid cold staking condition:", err)
+	}
+	b, err := json.Marshal(condition)
+	if err != nil {
+		cli.Die("Failed to JSON-marshal cold staking condition:", err)
+	}
+	fmt.Println(string(b))
+}
+
+func (walletCmd *walletCmd) createWeightedMultisigConditionCmd(cmd *cobra.Command, args []string) {
+	minWeight, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		cli.Die("Invalid minimum weight:", err)
+	}
+
+	signatories := make([]types.WeightedUnlockHash, 0, len(args[1:]))
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 {
+			cli.Die("Invalid weighted address, expected <address>:<weight>:", arg)
+		}
+		var signatory types.WeightedUnlockHash
+		if err = signatory.UnlockHash.LoadAddressString(parts[0], walletCmd.cli.Config.Bech32AddressPrefix); err != nil {
+			cli.Die("Failed to load unlock hash:", err)
+		}
+		if signatory.Weight, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			cli.Die("Invalid weight of address", parts[0], err)
+		}
+		signatories = append(signatories, signatory)
+	}
+
+	condition := types.NewCondition(&types.WeightedMultiSignatureCondition{
+		Signatories:   signatories,
+		MinimumWeight: minWeight,
+	})
+	if err := condition.IsStandardCondition(types.ValidationContext{}); err != nil {
+		cli.Die("Invalid weighted multisig condition:", err)
+	}
+	b, err := json.Marshal(condition)
+	if err != nil {
+		cli.Die("Failed to JSON-marshal weighted multisig condition:", err)
+	}
+	fmt.Println("Weighted multisig address:", condition.UnlockHash())
+	fmt.Println(string(b))
+}
+
 func (walletCmd *walletCmd) createCoinTxCmd(cmd *cobra.Command, args []string) {
 	currencyConvertor := walletCmd.cli.CreateCurrencyConvertor()
 
@@ -1194,7 +1466,7 @@ func (walletCmd *walletCmd) createCoinTxCmd(cmd *cobra.Command, args []string) {
 	}
 
 	// parse the remainder as output coditions and values
-	pairs, err := parsePairedOutputs(args[len(inputs):], currencyConvertor.ParseCoinString)
+	pairs, err := parsePairedOutputs(args[len(inputs):], currencyConvertor.ParseCoinString, walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
@@ -1238,7 +1510,7 @@ func (walletCmd *walletCmd) createBlockStakeTxCmd(cmd *cobra.Command, args []str
 	}
 
 	// parse the remainder as output coditions and values
-	pairs, err := parsePairedOutputs(args[len(inputs):], stringToBlockStakes)
+	pairs, err := parsePairedOutputs(args[len(inputs):], stringToBlockStakes, walletCmd.cli.Config.Bech32AddressPrefix)
 	if err != nil {
 		cmd.UsageFunc()(cmd)
 		cli.Die(err)
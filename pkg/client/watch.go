@@ -0,0 +1,285 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// watchReconnectDelay is the time waited before reconnecting to a lost event stream
+	watchReconnectDelay = 3 * time.Second
+	// watchMaxEventSize is the maximum size of a single line of the event stream
+	watchMaxEventSize = 16 * 1024 * 1024
+)
+
+func createWatchCmd(client *CommandLineClient) *cobra.Command {
+	watchCmd := &watchCmd{cli: client}
+
+	// create watch command
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch new blocks, transactions and wallet events in real time",
+		Long: `Watch the events of the daemon in real time, as they happen:
+blocks applied to and reverted from the consensus set, transactions added to and removed from
+the transaction pool and transactions of the (unlocked) wallet being created, confirmed or reverted.
+
+The transaction pool and wallet events can be limited to transactions referencing
+at least one of the given addresses. The stream is resumed automatically
+(without missing any blocks) if the connection to the daemon is lost.
+`,
+		Run: Wrap(watchCmd.watchCmd),
+	}
+
+	// create flags
+	cmd.Flags().StringSliceVar(
+		&watchCmd.watchCfg.Topics, "topics", nil,
+		"topics to watch (consensus, transactionpool and/or wallet), all topics of the loaded modules are watched if not defined")
+	cmd.Flags().StringSliceVar(
+		&watchCmd.watchCfg.Addresses, "address", nil,
+		"only watch transaction pool and wallet events of transactions referencing this address (can be repeated)")
+	cmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &watchCmd.watchCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	// return watch command
+	return cmd
+}
+
+type watchCmd struct {
+	cli      *CommandLineClient
+	watchCfg struct {
+		Topics       []string
+		Addresses    []string
+		EncodingType cli.EncodingType
+	}
+}
+
+// serverSentEvent is a single event of a server-sent events stream,
+// the name being empty for a message only updating the last event ID.
+type serverSentEvent struct {
+	Name string
+	Data string
+	ID   string
+}
+
+// watchCmd is the handler for the command `rivinec watch`,
+// printing the events streamed by the daemon until interrupted.
+func (cmd *watchCmd) watchCmd() {
+	query := url.Values{}
+	if len(cmd.watchCfg.Topics) > 0 {
+		query.Set("topics", strings.Join(cmd.watchCfg.Topics, ","))
+	}
+	if len(cmd.watchCfg.Addresses) > 0 {
+		addresses := make([]string, 0, len(cmd.watchCfg.Addresses))
+		for _, str := range cmd.watchCfg.Addresses {
+			var uh types.UnlockHash
+			if err := uh.LoadAddressString(strings.TrimSpace(str), cmd.cli.Config.Bech32AddressPrefix); err != nil {
+				cli.DieWithExitCode(cli.ExitCodeUsage, fmt.Sprintf("invalid address %q: %v", str, err))
+			}
+			addresses = append(addresses, uh.String())
+		}
+		query.Set("unlockhashes", strings.Join(addresses, ","))
+	}
+
+	var lastEventID string
+	for {
+		err := cmd.watch(query, &lastEventID)
+		if httpErr, ok := err.(*api.HTTPError); ok {
+			switch httpErr.HTTPStatusCode() {
+			case http.StatusGone:
+				// the stream is too far behind to be resumed, restart it from the current block
+				fmt.Fprintln(os.Stderr, "Event stream cannot be resumed, as it is too far behind, some blocks might have been missed")
+				lastEventID = ""
+				continue
+			case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+				cli.DieWithError("Failed to watch the events of the daemon:", err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Lost connection to the event stream (%v), reconnecting in %v...\n", err, watchReconnectDelay)
+		time.Sleep(watchReconnectDelay)
+	}
+}
+
+// watch streams the events of the daemon, resuming after the given last event ID (if any),
+// until the stream is lost. The last event ID is updated while streaming.
+func (cmd *watchCmd) watch(query url.Values, lastEventID *string) error {
+	if *lastEventID != "" {
+		query.Set("lastEventId", *lastEventID)
+	} else {
+		query.Del("lastEventId")
+	}
+	call := "/events/stream"
+	if len(query) > 0 {
+		call += "?" + query.Encode()
+	}
+	stream, err := cmd.cli.GetStream(call)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	currencyConvertor := cmd.cli.CreateCurrencyConvertor()
+	err = readServerSentEvents(stream, func(event serverSentEvent) error {
+		if event.ID != "" {
+			*lastEventID = event.ID
+		}
+		if event.Name == "" {
+			return nil
+		}
+		if api.EventType(event.Name) == api.EventTypeError {
+			return fmt.Errorf("daemon error: %s", event.Data)
+		}
+		if cmd.watchCfg.EncodingType == cli.EncodingTypeJSON {
+			return json.NewEncoder(os.Stdout).Encode(api.Event{
+				Type: api.EventType(event.Name),
+				Data: json.RawMessage(event.Data),
+			})
+		}
+		return printWatchEvent(os.Stdout, event, currencyConvertor)
+	})
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readServerSentEvents reads the server-sent events of the given stream,
+// calling the given callback for each event, until the stream ends or the callback returns an error.
+func readServerSentEvents(r io.Reader, fn func(serverSentEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), watchMaxEventSize)
+	var (
+		event   serverSentEvent
+		data    []string
+		pending bool
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// a blank line dispatches the event
+			if pending {
+				event.Data = strings.Join(data, "\n")
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			event, data, pending = serverSentEvent{}, nil, false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment, e.g. a ping
+		}
+		field, value := line, ""
+		if idx := strings.IndexByte(line, ':'); idx >= 0 {
+			field, value = line[:idx], strings.TrimPrefix(line[idx+1:], " ")
+		}
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		default:
+			continue // unknown fields are ignored
+		}
+		pending = true
+	}
+	return scanner.Err()
+}
+
+// printWatchEvent prints a single event in a human-readable format.
+func printWatchEvent(w io.Writer, event serverSentEvent, cc CurrencyConvertor) error {
+	timestamp := time.Now().Format("15:04:05")
+	switch eventType := api.EventType(event.Name); eventType {
+	case api.EventTypeSubscription:
+		var subscription api.EventsSubscription
+		if err := json.Unmarshal([]byte(event.Data), &subscription); err != nil {
+			return err
+		}
+		topics := make([]string, 0, len(subscription.Topics))
+		for _, topic := range subscription.Topics {
+			topics = append(topics, string(topic))
+		}
+		fmt.Fprintf(w, "%s  watching %s", timestamp, strings.Join(topics, ", "))
+		if len(subscription.UnlockHashes) > 0 {
+			fmt.Fprintf(w, " (filtered by %d address(es))", len(subscription.UnlockHashes))
+		}
+		fmt.Fprintln(w)
+
+	case api.EventTypeBlockApplied, api.EventTypeBlockReverted:
+		var block api.EventBlockSummary
+		if err := json.Unmarshal([]byte(event.Data), &block); err != nil {
+			return err
+		}
+		action := "block applied"
+		if eventType == api.EventTypeBlockReverted {
+			action = "block reverted"
+		}
+		fmt.Fprintf(w, "%s  %-21s  %s  height %d, %d transaction(s)\n",
+			timestamp, action, block.ID.String(), block.Height, len(block.TransactionIDs))
+
+	case api.EventTypeTransactionPoolTransactionAdded, api.EventTypeTransactionPoolTransactionRemoved, api.EventTypeWalletTransactionReverted:
+		var txn api.EventTransaction
+		if err := json.Unmarshal([]byte(event.Data), &txn); err != nil {
+			return err
+		}
+		var action string
+		switch eventType {
+		case api.EventTypeTransactionPoolTransactionAdded:
+			action = "pool tx added"
+		case api.EventTypeTransactionPoolTransactionRemoved:
+			action = "pool tx removed"
+		default:
+			action = "wallet tx reverted"
+		}
+		var value types.Currency
+		for _, co := range txn.Transaction.CoinOutputs {
+			value = value.Add(co.Value)
+		}
+		fmt.Fprintf(w, "%s  %-21s  %s  %d coin output(s) of %s\n",
+			timestamp, action, txn.ID.String(), len(txn.Transaction.CoinOutputs), cc.ToCoinStringWithUnit(value))
+
+	case api.EventTypeWalletTransactionUnconfirmed, api.EventTypeWalletTransactionConfirmed:
+		var txn modules.ProcessedTransaction
+		if err := json.Unmarshal([]byte(event.Data), &txn); err != nil {
+			return err
+		}
+		var incoming, outgoing types.Currency
+		for _, input := range txn.Inputs {
+			if input.FundType == types.SpecifierCoinInput && input.WalletAddress {
+				outgoing = outgoing.Add(input.Value)
+			}
+		}
+		for _, output := range txn.Outputs {
+			if output.FundType == types.SpecifierMinerPayout ||
+				(output.FundType == types.SpecifierCoinOutput && output.WalletAddress) {
+				incoming = incoming.Add(output.Value)
+			}
+		}
+		action, height := "wallet tx unconfirmed", "unconfirmed"
+		if eventType == api.EventTypeWalletTransactionConfirmed {
+			action, height = "wallet tx confirmed", fmt.Sprintf("height %d", txn.ConfirmationHeight-1)
+		}
+		fmt.Fprintf(w, "%s  %-21s  %s  %s, net %s %s\n",
+			timestamp, action, txn.TransactionID.String(), height, netCoinString(cc, incoming, outgoing), cc.CurrencyFormat().UnitName)
+
+	default:
+		fmt.Fprintf(w, "%s  %s  %s\n", timestamp, event.Name, event.Data)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadServerSentEvents(t *testing.T) {
+	const stream = `event: subscription
+data: {"topics":["consensus"]}
+
+: ping
+
+event: consensus.block.applied
+data: {"height":1}
+
+id: 6b5d1c
+
+event: multiline
+data: foo
+data: bar
+retry: 1000
+
+`
+	var events []serverSentEvent
+	err := readServerSentEvents(strings.NewReader(stream), func(event serverSentEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []serverSentEvent{
+		{Name: "subscription", Data: `{"topics":["consensus"]}`},
+		{Name: "consensus.block.applied", Data: `{"height":1}`},
+		{ID: "6b5d1c"},
+		{Name: "multiline", Data: "foo\nbar"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
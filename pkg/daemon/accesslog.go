@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/threefoldtech/rivine/persist"
+)
+
+// APIAccessLogStdout is the APIAccessLog value used to write the API access log to the standard output.
+const APIAccessLogStdout = "-"
+
+// APIAccessLogWriter returns the writer the API access log is written to,
+// or nil in case no API access log is configured.
+// A relative access log path is resolved relative to the root persistent directory.
+func APIAccessLogWriter(cfg Config) (io.WriteCloser, error) {
+	switch cfg.APIAccessLog {
+	case "":
+		return nil, nil
+	case APIAccessLogStdout:
+		return nopCloser{os.Stdout}, nil
+	}
+	path := cfg.APIAccessLog
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.RootPersistentDir, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return persist.NewRotatingFile(path, int64(cfg.APIAccessLogMaxSize)*1e6, cfg.APIAccessLogMaxBackups)
+}
+
+// nopCloser is used to prevent the standard output from being closed
+type nopCloser struct {
+	io.Writer
+}
+
+// Close implements io.Closer.Close
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestAPIAccessLogWriter(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RootPersistentDir = t.TempDir()
+
+	// no access log by default
+	w, err := APIAccessLogWriter(cfg)
+	if err != nil || w != nil {
+		t.Fatalf("expected no access log writer and no error, got: %v, %v", w, err)
+	}
+
+	// a relative path is resolved relative to the root persistent directory
+	cfg.APIAccessLog = filepath.Join("logs", "access.log")
+	w, err = APIAccessLogWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(cfg.RootPersistentDir, "logs", "access.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "{}\n" {
+		t.Fatalf("unexpected access log content: %q", b)
+	}
+
+	// the standard output is never closed
+	cfg.APIAccessLog = APIAccessLogStdout
+	w, err = APIAccessLogWriter(cfg)
+	if err != nil || w == nil {
+		t.Fatalf("expected an access log writer and no error, got: %v, %v", w, err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,323 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	bolt "github.com/rivine/bbolt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/modules/consensus"
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/pkg/txbuilder"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// benchmarkDBBatchSize is the amount of key-value pairs written or read per database transaction
+	benchmarkDBBatchSize = 1000
+	// benchmarkDBValueSize is the size of the values written to the database, in bytes
+	benchmarkDBValueSize = 256
+)
+
+var benchmarkDBBucket = []byte("benchmark")
+
+// BenchmarkConfig configures the benchmarks run by RunBenchmarks.
+type BenchmarkConfig struct {
+	// Duration is the (minimum) duration of each benchmark
+	Duration time.Duration
+	// Dir is the directory in which the temporary database of the database benchmarks is created,
+	// which should be on the disk the daemon stores its data on, for the results to be relevant.
+	Dir string
+	// Constants of the network, used to validate the transactions of the validation benchmark
+	Constants types.ChainConstants
+}
+
+// BenchmarkResult is the result of a single benchmark.
+type BenchmarkResult struct {
+	Name       string        `json:"name"`
+	Operations uint64        `json:"operations"`
+	Duration   time.Duration `json:"duration"`
+	// Rate is the amount of operations per second
+	Rate float64 `json:"rate"`
+	// Unit is the name of a single operation (e.g. "signatures")
+	Unit string `json:"unit"`
+}
+
+// BenchmarkReport is the report of all benchmarks run by RunBenchmarks,
+// allowing the performance of different machines to be compared.
+type BenchmarkReport struct {
+	GOOS    string            `json:"goos"`
+	GOARCH  string            `json:"goarch"`
+	CPUs    int               `json:"cpus"`
+	Results []BenchmarkResult `json:"results"`
+	// BlockTransactions is the amount of (small) transactions fitting in a full block,
+	// and BlockValidationTime the estimated time to validate the transactions of such a block
+	BlockTransactions   uint64        `json:"blocktransactions"`
+	BlockValidationTime time.Duration `json:"blockvalidationtime"`
+}
+
+// RunBenchmarks measures the signature verification throughput, database read and write rates
+// and transaction (block) validation speed of the local machine.
+func RunBenchmarks(cfg BenchmarkConfig) (BenchmarkReport, error) {
+	report := BenchmarkReport{
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+		CPUs:   runtime.NumCPU(),
+	}
+	results, err := benchmarkSignatures(cfg.Duration)
+	if err != nil {
+		return report, err
+	}
+	report.Results = append(report.Results, results...)
+	results, err = benchmarkDatabase(cfg.Dir, cfg.Duration)
+	if err != nil {
+		return report, err
+	}
+	report.Results = append(report.Results, results...)
+	result, size, err := benchmarkTransactionValidation(cfg.Constants, cfg.Duration)
+	if err != nil {
+		return report, err
+	}
+	report.Results = append(report.Results, result)
+	if size > 0 && result.Rate > 0 {
+		report.BlockTransactions = cfg.Constants.BlockSizeLimit / size
+		report.BlockValidationTime = time.Duration(float64(report.BlockTransactions) / result.Rate * float64(time.Second))
+	}
+	return report, nil
+}
+
+// benchmark calls the given function repeatedly for (at least) the given duration,
+// using the given amount of goroutines, each call counting as the given amount of operations.
+func benchmark(name, unit string, duration time.Duration, goroutines int, opsPerCall uint64, fn func() error) (BenchmarkResult, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		ops   uint64
+		ferr  error
+		start = time.Now()
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n uint64
+			for time.Since(start) < duration {
+				if err := fn(); err != nil {
+					mu.Lock()
+					ferr = err
+					mu.Unlock()
+					return
+				}
+				n += opsPerCall
+			}
+			mu.Lock()
+			ops += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	if ferr != nil {
+		return BenchmarkResult{}, fmt.Errorf("%s benchmark failed: %v", name, ferr)
+	}
+	return BenchmarkResult{
+		Name:       name,
+		Operations: ops,
+		Duration:   elapsed,
+		Rate:       float64(ops) / elapsed.Seconds(),
+		Unit:       unit,
+	}, nil
+}
+
+// benchmarkSignatures measures the ed25519 signature verification throughput,
+// using a single core and using all cores.
+func benchmarkSignatures(duration time.Duration) ([]BenchmarkResult, error) {
+	sk, pk := crypto.GenerateKeyPair()
+	hash := crypto.HashBytes([]byte("benchmark"))
+	sig := crypto.SignHash(hash, sk)
+	verify := func() error {
+		return crypto.VerifyHash(hash, pk, sig)
+	}
+	single, err := benchmark("signature verification (1 core)", "signatures", duration, 1, 1, verify)
+	if err != nil {
+		return nil, err
+	}
+	cpus := runtime.NumCPU()
+	all, err := benchmark(fmt.Sprintf("signature verification (all %d CPUs)", cpus), "signatures", duration, cpus, 1, verify)
+	if err != nil {
+		return nil, err
+	}
+	return []BenchmarkResult{single, all}, nil
+}
+
+// benchmarkDatabase measures the write, commit and read rates of a temporary (bolt) database,
+// created in the given directory, the way the modules of the daemon store their data.
+func benchmarkDatabase(dir string, duration time.Duration) ([]BenchmarkResult, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	tmpDir, err := ioutil.TempDir(dir, "benchmark")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	db, err := persist.OpenDatabase(persist.Metadata{Header: "Benchmark Database", Version: "1.0.0"},
+		filepath.Join(tmpDir, "benchmark.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(benchmarkDBBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys uint64
+	value := make([]byte, benchmarkDBValueSize)
+	rand.Read(value)
+	put := func(n int) func() error {
+		return func() error {
+			return db.Update(func(tx *bolt.Tx) error {
+				bucket := tx.Bucket(benchmarkDBBucket)
+				for i := 0; i < n; i++ {
+					keys++
+					if err := bucket.Put(benchmarkDBKey(keys), value); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	}
+	writes, err := benchmark("database writes", "writes", duration, 1, benchmarkDBBatchSize, put(benchmarkDBBatchSize))
+	if err != nil {
+		return nil, err
+	}
+	commits, err := benchmark("database synced commits", "commits", duration, 1, 1, put(1))
+	if err != nil {
+		return nil, err
+	}
+	reads, err := benchmark("database random reads", "reads", duration, 1, benchmarkDBBatchSize, func() error {
+		return db.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket(benchmarkDBBucket)
+			for i := 0; i < benchmarkDBBatchSize; i++ {
+				if bucket.Get(benchmarkDBKey(uint64(rand.Int63n(int64(keys)))+1)) == nil {
+					return fmt.Errorf("missing database key")
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []BenchmarkResult{writes, commits, reads}, nil
+}
+
+// benchmarkDBKey returns the database key of the given (sequence) number.
+func benchmarkDBKey(n uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, n)
+	return key
+}
+
+// benchmarkTransactionValidation measures the validation speed of a small transaction,
+// spending a single (public key) coin output to two coin outputs,
+// using the standard transaction validators the consensus set validates the transactions of a block with.
+// Database lookups of the spent outputs are excluded, see the database benchmarks for those.
+// The encoded size of the transaction is returned as well.
+func benchmarkTransactionValidation(constants types.ChainConstants, duration time.Duration) (BenchmarkResult, uint64, error) {
+	sk, pk := crypto.GenerateKeyPair()
+	spk := types.Ed25519PublicKey(pk)
+	uh, err := types.NewPubKeyUnlockHash(spk)
+	if err != nil {
+		return BenchmarkResult{}, 0, err
+	}
+	condition := types.NewCondition(types.NewUnlockHashCondition(uh))
+	parent := types.CoinOutput{Value: constants.CurrencyUnits.OneCoin.Mul64(10), Condition: condition}
+	value := parent.Value.Sub(constants.MinimumTransactionFee).Div64(2)
+	chainID := constants.ChainID()
+	builder := txbuilder.New(constants.DefaultTransactionVersion).
+		WithChainID(chainID).
+		SpendCoinOutput(types.CoinOutputID(crypto.HashBytes([]byte("benchmark"))), parent, types.NewSingleSignatureFulfillment(spk)).
+		AddCoinOutput(value, condition).
+		AddCoinOutput(value, condition).
+		AddMinerFee(parent.Value.Sub(value.Mul64(2)))
+	if err = builder.SignCoinInput(0, sk); err != nil {
+		return BenchmarkResult{}, 0, err
+	}
+	txn := builder.Transaction()
+	encoded, err := siabin.Marshal(txn)
+	if err != nil {
+		return BenchmarkResult{}, 0, err
+	}
+	ctx := types.TransactionValidationContext{
+		ValidationContext: types.ValidationContext{
+			Confirmed:   true,
+			BlockHeight: 1,
+			BlockTime:   types.CurrentTimestamp(),
+		},
+		BlockSizeLimit:            constants.BlockSizeLimit,
+		ArbitraryDataSizeLimit:    constants.ArbitraryDataSizeLimit,
+		AllowedArbitraryDataTypes: constants.AllowedArbitraryDataTypes,
+		MinimumMinerFee:           constants.MinimumTransactionFee,
+		ExtensionDataSizeLimit:    constants.ExtensionDataSizeLimit,
+		OutputMetadataSizeLimit:   constants.OutputMetadataSizeLimit,
+		ChainID:                   chainID,
+	}
+	validators := append(consensus.StandardTransactionValidators(),
+		consensus.StandardTransactionVersionMappedValidators()[txn.Version]...)
+
+	result, err := benchmark("transaction validation", "transactions", duration, 1, 1, func() error {
+		// transactions are decoded and identified as part of the validation of a block
+		var decoded types.Transaction
+		if err := siabin.Unmarshal(encoded, &decoded); err != nil {
+			return err
+		}
+		ctxn := modules.ConsensusTransaction{
+			Transaction:      decoded,
+			BlockHeight:      ctx.BlockHeight,
+			BlockTime:        ctx.BlockTime,
+			SpentCoinOutputs: map[types.CoinOutputID]types.CoinOutput{decoded.CoinInputs[0].ParentID: parent},
+		}
+		_ = decoded.ID()
+		for _, validator := range validators {
+			if err := validator(ctxn, ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, uint64(len(encoded)), err
+}
+
+// WriteBenchmarkReport writes the given benchmark report in a human-readable format.
+func WriteBenchmarkReport(w io.Writer, report BenchmarkReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "benchmark\trate\tunit\toperations\tduration\t\n")
+	for _, result := range report.Results {
+		fmt.Fprintf(tw, "%s\t%.0f\t%s/s\t%d\t%v\t\n", result.Name, result.Rate, result.Unit,
+			result.Operations, result.Duration.Round(time.Millisecond))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%s/%s, %d CPU(s): the transactions of a full block (%d transactions) validate in about %v\n",
+		report.GOOS, report.GOARCH, report.CPUs, report.BlockTransactions, report.BlockValidationTime.Round(time.Microsecond))
+	return err
+}
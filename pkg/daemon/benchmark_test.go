@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunBenchmarks(t *testing.T) {
+	networkCfg, err := DefaultNetworkConfig("devnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := RunBenchmarks(BenchmarkConfig{
+		Duration:  10 * time.Millisecond,
+		Dir:       t.TempDir(),
+		Constants: networkCfg.Constants,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 6 {
+		t.Fatalf("unexpected amount of results: %d", len(report.Results))
+	}
+	for _, result := range report.Results {
+		if result.Operations == 0 || result.Rate <= 0 {
+			t.Errorf("benchmark %q did not run: %+v", result.Name, result)
+		}
+	}
+	if report.BlockTransactions == 0 || report.BlockValidationTime <= 0 {
+		t.Errorf("block validation time was not estimated: %+v", report)
+	}
+
+	var buf bytes.Buffer
+	if err = WriteBenchmarkReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "transaction validation") {
+		t.Errorf("unexpected report: %s", buf.String())
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -15,6 +17,10 @@ import (
 const (
 	// RivineUserAgent is the user agent used by Rivine by default.
 	RivineUserAgent = "Rivine-Agent"
+
+	// APIPublicRoutesDefault is the APIPublicRoutes value used to serve
+	// the default public API routes (blocks, transactions, outputs, addresses and gateway info).
+	APIPublicRoutesDefault = "default"
 )
 
 type (
@@ -28,8 +34,14 @@ type (
 		APIPassword string
 
 		// the host:port for the HTTP API to listen on.
-		// If `AllowAPIBind` is false, only localhost hosts are allowed
+		// If `AllowAPIBind` is false, only localhost hosts are allowed.
+		// The HTTP API is not served over TCP if empty, requiring `APISocket` to be defined.
 		APIaddr string
+		// the path of the unix domain socket the HTTP API listens on (as well),
+		// relative to the root persistent directory unless absolute,
+		// and the (octal) file permissions restricting access to it
+		APISocket     string
+		APISocketMode string
 		// the host:port to listen for RPC calls
 		RPCaddr string
 		// indicates that the http API can listen on a non localhost address.
@@ -45,6 +57,77 @@ type (
 		// indicates if the http api is password protected
 		AuthenticateAPI bool
 
+		// PEM-encoded certificate and key files used to serve the http api over TLS,
+		// the http api is served over plain HTTP if neither is defined
+		APITLSCertFile string
+		APITLSKeyFile  string
+		// generate a self-signed certificate to serve the http api over TLS,
+		// in case the (default) certificate file does not exist yet
+		APITLSSelfSigned bool
+
+		// origins allowed to make cross-origin requests to the http api,
+		// no cross-origin requests are allowed if none are defined
+		APICORSAllowedOrigins []string
+		// methods and headers allowed for cross-origin requests to the http api,
+		// sane defaults are used if none are defined
+		APICORSAllowedMethods []string
+		APICORSAllowedHeaders []string
+
+		// the amount of requests per second (and burst) allowed per client IP address
+		// for requests to the http api not authenticated using an API token,
+		// rate limiting is disabled if the rate is not positive
+		APIRateLimit      float64
+		APIRateLimitBurst int
+		// the amount of requests per second (and burst) allowed per API token
+		// for requests to the http api authenticated using that API token,
+		// rate limiting is disabled if the rate is not positive
+		APITokenRateLimit      float64
+		APITokenRateLimitBurst int
+		// routes of the http api which are never rate limited
+		APIRateLimitExemptRoutes []string
+
+		// serve the GraphQL endpoint over the chain data,
+		// only available if the explorer module is loaded
+		APIGraphQL bool
+
+		// serve the JSON-RPC 2.0 endpoint, mapping the standard methods
+		// of Bitcoin-like daemons onto the loaded modules
+		APIJSONRPC bool
+
+		// read-only routes of the http api served without authentication,
+		// requiring all other routes to authenticate using (at least) read-only credentials,
+		// routes only require authentication according to their own scope if none are defined
+		APIPublicRoutes []string
+
+		// the minimum size (in bytes) of http api responses to be compressed,
+		// for clients accepting gzip or deflate encoded responses,
+		// responses are never compressed if negative
+		APICompressionMinSize int
+
+		// the duration for which the responses of transaction submissions made with an idempotency key are stored,
+		// such that retries are responded to with the original response, disabled if not positive
+		APIIdempotencyWindow time.Duration
+
+		// the maximum size (in MB) of the immutable http api responses (e.g. confirmed blocks)
+		// cached in memory, responses are not cached if 0
+		APICacheSize int
+
+		// path of the structured (JSON) access log of the http api,
+		// relative to the root persistent directory unless absolute,
+		// "-" logs to the standard output and no access log is written if empty
+		APIAccessLog string
+		// the size (in MB) at which the access log is rotated,
+		// and the amount of rotated access logs to keep
+		APIAccessLogMaxSize    uint64
+		APIAccessLogMaxBackups int
+
+		// the maximum amount of blocks the consensus set can be estimated
+		// to be behind for the daemon to be reported as ready by its health endpoint
+		HealthMaxBlocksBehind uint64
+		// require the wallet to be unlocked for the daemon
+		// to be reported as ready by its health endpoint
+		HealthRequireUnlockedWallet bool
+
 		// indicates if profile info should be collected while
 		// the daemon is running
 		Profile bool
@@ -64,6 +147,24 @@ type (
 		// DebugConsensusDB is an optional filepath in which json encoded
 		// consensus database stats will be saved
 		DebugConsensusDB string
+
+		// BlockCreatorDryRun makes the block creator log the blocks it would have created,
+		// instead of submitting them
+		BlockCreatorDryRun bool
+
+		// StakingNetworks are the additional networks on which this daemon creates blocks,
+		// next to the network it is connected to. Each staking network runs its own
+		// gateway, consensus set, transaction pool, wallet and block creator.
+		StakingNetworks []StakingNetworkConfig
+	}
+
+	// StakingNetworkConfig defines an additional network on which to create blocks.
+	StakingNetworkConfig struct {
+		// NetworkName is the name of the network to stake on
+		NetworkName string
+		// RPCaddr is the host:port the gateway of this network listens on,
+		// it has to differ from the RPC address of any other network
+		RPCaddr string
 	}
 
 	// NetworkConfig are variables for a particular chain. Currently, these are genesis constants and bootstrap peers
@@ -82,14 +183,46 @@ func DefaultConfig() Config {
 
 		APIPassword: "",
 
-		APIaddr:      "localhost:23110",
-		RPCaddr:      ":23112",
-		AllowAPIBind: false,
+		APIaddr:       "localhost:23110",
+		APISocket:     "",
+		APISocketMode: "0660",
+		RPCaddr:       ":23112",
+		AllowAPIBind:  false,
 
 		NoBootstrap:       false,
 		RequiredUserAgent: RivineUserAgent,
 		AuthenticateAPI:   false,
 
+		APITLSCertFile:   "",
+		APITLSKeyFile:    "",
+		APITLSSelfSigned: false,
+
+		APICORSAllowedOrigins: nil,
+		APICORSAllowedMethods: nil,
+		APICORSAllowedHeaders: nil,
+
+		APIRateLimit:           0,
+		APIRateLimitBurst:      0,
+		APITokenRateLimit:      0,
+		APITokenRateLimitBurst: 0,
+
+		APIRateLimitExemptRoutes: nil,
+
+		APIGraphQL:      false,
+		APIJSONRPC:      false,
+		APIPublicRoutes: nil,
+
+		APICompressionMinSize: 1024,
+		APIIdempotencyWindow:  24 * time.Hour,
+		APICacheSize:          64,
+
+		APIAccessLog:           "",
+		APIAccessLogMaxSize:    100,
+		APIAccessLogMaxBackups: 5,
+
+		HealthMaxBlocksBehind:       10,
+		HealthRequireUnlockedWallet: false,
+
 		Profile:           false,
 		ProfileDir:        "profiles",
 		RootPersistentDir: "",
@@ -98,6 +231,10 @@ func DefaultConfig() Config {
 		BootstrapPeers: nil,
 
 		DebugConsensusDB: "",
+
+		BlockCreatorDryRun: false,
+
+		StakingNetworks: nil,
 	}
 }
 
@@ -105,7 +242,11 @@ func DefaultConfig() Config {
 func (cfg *Config) RegisterAsFlags(flagSet *pflag.FlagSet) {
 	flagSet.StringVarP(&cfg.RequiredUserAgent, "agent", "", cfg.RequiredUserAgent, "required substring for the user agent")
 	flagSet.StringVarP(&cfg.ProfileDir, "profile-directory", "", cfg.ProfileDir, "location of the profiling directory")
-	flagSet.StringVarP(&cfg.APIaddr, "api-addr", "", cfg.APIaddr, "which host:port the API server listens on")
+	flagSet.StringVarP(&cfg.APIaddr, "api-addr", "", cfg.APIaddr, "which host:port the API server listens on (empty to only listen on the unix socket)")
+	flagSet.StringVarP(&cfg.APISocket, "api-socket", "", cfg.APISocket,
+		"unix domain socket the API server listens on, relative to the persistent directory unless absolute")
+	flagSet.StringVarP(&cfg.APISocketMode, "api-socket-mode", "", cfg.APISocketMode,
+		"file permissions (in octal) of the unix domain socket the API server listens on")
 	flagSet.StringVarP(&cfg.RootPersistentDir, "persistent-directory", "d", cfg.RootPersistentDir,
 		"location of the root directory used to store persistent data of the daemon of "+
 			cfg.BlockchainInfo.Name)
@@ -115,12 +256,105 @@ func (cfg *Config) RegisterAsFlags(flagSet *pflag.FlagSet) {
 	flagSet.BoolVarP(&cfg.Profile, "profile", "", cfg.Profile, "enable profiling")
 	flagSet.StringVarP(&cfg.RPCaddr, "rpc-addr", "", cfg.RPCaddr, "which port the gateway listens on")
 	flagSet.BoolVarP(&cfg.AuthenticateAPI, "authenticate-api", "", cfg.AuthenticateAPI, "enable API password protection")
+	flagSet.StringVarP(&cfg.APITLSCertFile, "api-tls-cert", "", cfg.APITLSCertFile, "PEM-encoded certificate file used to serve the API over TLS")
+	flagSet.StringVarP(&cfg.APITLSKeyFile, "api-tls-key", "", cfg.APITLSKeyFile, "PEM-encoded key file used to serve the API over TLS")
+	flagSet.BoolVarP(&cfg.APITLSSelfSigned, "api-tls-self-signed", "", cfg.APITLSSelfSigned,
+		fmt.Sprintf("serve the API over TLS using a self-signed certificate, generated if it doesn't exist yet (default location: <persistent-directory>/%s)", DefaultAPITLSCertFile))
+	flagSet.StringSliceVarP(&cfg.APICORSAllowedOrigins, "api-cors-origins", "", cfg.APICORSAllowedOrigins,
+		"origins allowed to make cross-origin requests to the API (e.g. https://wallet.example.com), use * to allow any origin")
+	flagSet.StringSliceVarP(&cfg.APICORSAllowedMethods, "api-cors-methods", "", cfg.APICORSAllowedMethods,
+		"HTTP methods allowed for cross-origin requests to the API (default GET,POST)")
+	flagSet.StringSliceVarP(&cfg.APICORSAllowedHeaders, "api-cors-headers", "", cfg.APICORSAllowedHeaders,
+		"headers allowed for cross-origin requests to the API (default Authorization,Content-Type)")
+	flagSet.Float64VarP(&cfg.APIRateLimit, "api-rate-limit", "", cfg.APIRateLimit,
+		"requests per second allowed per client IP for API requests not using an API token (0 disables rate limiting)")
+	flagSet.IntVarP(&cfg.APIRateLimitBurst, "api-rate-burst", "", cfg.APIRateLimitBurst,
+		"maximum burst of API requests allowed per client IP (defaults to the rate limit)")
+	flagSet.Float64VarP(&cfg.APITokenRateLimit, "api-token-rate-limit", "", cfg.APITokenRateLimit,
+		"requests per second allowed per API token (0 disables rate limiting)")
+	flagSet.IntVarP(&cfg.APITokenRateLimitBurst, "api-token-rate-burst", "", cfg.APITokenRateLimitBurst,
+		"maximum burst of API requests allowed per API token (defaults to the token rate limit)")
+	flagSet.StringSliceVarP(&cfg.APIRateLimitExemptRoutes, "api-rate-limit-exempt", "", cfg.APIRateLimitExemptRoutes,
+		"API routes (e.g. /daemon/status) which are never rate limited, using the same patterns as --api-public-routes")
+	flagSet.BoolVarP(&cfg.APIGraphQL, "api-graphql", "", cfg.APIGraphQL,
+		"serve the GraphQL endpoint over the chain data (requires the explorer module)")
+	flagSet.BoolVarP(&cfg.APIJSONRPC, "api-jsonrpc", "", cfg.APIJSONRPC,
+		"serve the JSON-RPC 2.0 endpoint, mapping the standard methods of Bitcoin-like daemons onto the loaded modules (requires the consensus module)")
+	flagSet.StringSliceVarP(&cfg.APIPublicRoutes, "api-public-routes", "", cfg.APIPublicRoutes,
+		fmt.Sprintf("read-only API routes (e.g. /explorer/*) served without authentication while all other routes require authentication, %q serves the public chain and network info routes", APIPublicRoutesDefault))
+	flagSet.IntVarP(&cfg.APICompressionMinSize, "api-compression-min-size", "", cfg.APICompressionMinSize,
+		"minimum size in bytes of API responses to be compressed for clients accepting gzip or deflate (-1 disables compression)")
+	flagSet.DurationVarP(&cfg.APIIdempotencyWindow, "api-idempotency-window", "", cfg.APIIdempotencyWindow,
+		"duration for which responses to transaction submissions made with an Idempotency-Key header are stored for retries (0 disables idempotency keys)")
+	flagSet.IntVarP(&cfg.APICacheSize, "api-cache-size", "", cfg.APICacheSize,
+		"maximum size in MB of the immutable API responses (confirmed blocks and transactions) cached in memory (0 disables the cache)")
+	flagSet.StringVarP(&cfg.APIAccessLog, "api-access-log", "", cfg.APIAccessLog,
+		"file to write the structured (JSON) API access log to, relative to the persistent directory unless absolute (- logs to stdout)")
+	flagSet.Uint64VarP(&cfg.APIAccessLogMaxSize, "api-access-log-max-size", "", cfg.APIAccessLogMaxSize,
+		"size in MB at which the API access log is rotated (0 disables rotation)")
+	flagSet.IntVarP(&cfg.APIAccessLogMaxBackups, "api-access-log-max-backups", "", cfg.APIAccessLogMaxBackups,
+		"amount of rotated API access logs to keep")
+	flagSet.Uint64VarP(&cfg.HealthMaxBlocksBehind, "health-max-blocks-behind", "", cfg.HealthMaxBlocksBehind,
+		"maximum amount of blocks the consensus set can be behind for the daemon to be reported as ready")
+	flagSet.BoolVarP(&cfg.HealthRequireUnlockedWallet, "health-require-unlocked-wallet", "", cfg.HealthRequireUnlockedWallet,
+		"require the wallet to be unlocked for the daemon to be reported as ready")
 	flagSet.BoolVarP(&cfg.AllowAPIBind, "disable-api-security", "", cfg.AllowAPIBind, fmt.Sprintf("allow the daemon of %s to listen on a non-localhost address (DANGEROUS)", cfg.BlockchainInfo.Name))
 	flagSet.StringVarP(&cfg.BlockchainInfo.NetworkName, "network", "n", cfg.BlockchainInfo.NetworkName, "the name of the network to which the daemon connects")
 	flagSet.StringVarP(&cfg.DebugConsensusDB, "consensus-db-stats", "", cfg.DebugConsensusDB, "file path in which json encoded database stats will be saved")
+	flagSet.BoolVarP(&cfg.BlockCreatorDryRun, "blockcreator-dry-run", "", cfg.BlockCreatorDryRun, "only log the blocks the block creator would have created, without submitting them")
 
 	cli.NetAddressArrayFlagVar(flagSet, &cfg.BootstrapPeers, "bootstrap-peers",
 		"overwrite the bootstrap peers to use, instead of using the default bootstrap peers")
+	flagSet.Var(&stakingNetworkArray{array: &cfg.StakingNetworks}, "stake-network",
+		"additionally stake on the given network, defined as <network>@<rpc-addr>, can be defined multiple times")
+}
+
+// ParseStakingNetworkConfig parses a staking network config, defined as <network>@<rpc-addr>.
+func ParseStakingNetworkConfig(str string) (StakingNetworkConfig, error) {
+	parts := strings.SplitN(str, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return StakingNetworkConfig{}, fmt.Errorf("invalid staking network %q: expected format <network>@<rpc-addr>", str)
+	}
+	return StakingNetworkConfig{
+		NetworkName: parts[0],
+		RPCaddr:     processNetAddr(parts[1]),
+	}, nil
+}
+
+type stakingNetworkArray struct {
+	array   *[]StakingNetworkConfig
+	changed bool
+}
+
+// Set implements pflag.Value.Set
+func (flag *stakingNetworkArray) Set(val string) error {
+	if !flag.changed {
+		*flag.array = make([]StakingNetworkConfig, 0)
+		flag.changed = true
+	}
+	cfg, err := ParseStakingNetworkConfig(val)
+	if err != nil {
+		return err
+	}
+	*flag.array = append(*flag.array, cfg)
+	return nil
+}
+
+// Type implements pflag.Value.Type
+func (flag *stakingNetworkArray) Type() string {
+	return "StakingNetworkArray"
+}
+
+// String implements pflag.Value.String
+func (flag *stakingNetworkArray) String() string {
+	if flag.array == nil || len(*flag.array) == 0 {
+		return ""
+	}
+	strs := make([]string, 0, len(*flag.array))
+	for _, cfg := range *flag.array {
+		strs = append(strs, cfg.NetworkName+"@"+cfg.RPCaddr)
+	}
+	return strings.Join(strs, ",")
 }
 
 // ProcessConfig checks the configuration values and performs cleanup on
@@ -128,12 +362,28 @@ func (cfg *Config) RegisterAsFlags(flagSet *pflag.FlagSet) {
 func ProcessConfig(config Config) Config {
 	config.APIaddr = processNetAddr(config.APIaddr)
 	config.RPCaddr = processNetAddr(config.RPCaddr)
+	for i := range config.StakingNetworks {
+		config.StakingNetworks[i].RPCaddr = processNetAddr(config.StakingNetworks[i].RPCaddr)
+	}
 	return config
 }
 
 // VerifyAPISecurity checks that the security values are consistent with a
 // sane, secure system.
 func VerifyAPISecurity(cfg Config) error {
+	// Public routes only make sense if all other routes require authentication.
+	if len(cfg.APIPublicRoutes) != 0 && !cfg.AuthenticateAPI {
+		return errors.New("cannot use --api-public-routes without setting an api password")
+	}
+
+	if cfg.APIaddr == "" {
+		// access to the unix socket is controlled using its file permissions
+		if cfg.APISocket == "" {
+			return errors.New("the API has to be served on an address and/or a unix socket")
+		}
+		return nil
+	}
+
 	// Make sure that only the loopback address is allowed unless the
 	// --disable-api-security flag has been used.
 	if !cfg.AllowAPIBind {
@@ -184,3 +434,24 @@ func DefaultNetworkConfig(networkName string) (NetworkConfig, error) {
 	}
 	return networkCfg, nil
 }
+
+// VerifyStakingNetworks checks that the configured staking networks are known,
+// unique and do not conflict with the network the daemon is connected to.
+func VerifyStakingNetworks(cfg Config) error {
+	networks := map[string]struct{}{cfg.BlockchainInfo.NetworkName: {}}
+	rpcAddrs := map[string]struct{}{cfg.RPCaddr: {}}
+	for _, stakingCfg := range cfg.StakingNetworks {
+		if _, err := DefaultNetworkConfig(stakingCfg.NetworkName); err != nil {
+			return err
+		}
+		if _, ok := networks[stakingCfg.NetworkName]; ok {
+			return fmt.Errorf("network %s is configured more than once", stakingCfg.NetworkName)
+		}
+		networks[stakingCfg.NetworkName] = struct{}{}
+		if _, ok := rpcAddrs[stakingCfg.RPCaddr]; ok {
+			return fmt.Errorf("RPC address %s of staking network %s is already in use by another network", stakingCfg.RPCaddr, stakingCfg.NetworkName)
+		}
+		rpcAddrs[stakingCfg.RPCaddr] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"testing"
+)
+
+func TestParseStakingNetworkConfig(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected StakingNetworkConfig
+	}{
+		{"testnet@:23113", StakingNetworkConfig{NetworkName: "testnet", RPCaddr: ":23113"}},
+		{"testnet@23113", StakingNetworkConfig{NetworkName: "testnet", RPCaddr: ":23113"}},
+		{"devnet@localhost:23114", StakingNetworkConfig{NetworkName: "devnet", RPCaddr: "localhost:23114"}},
+	}
+	for idx, testCase := range testCases {
+		cfg, err := ParseStakingNetworkConfig(testCase.Input)
+		if err != nil {
+			t.Errorf("#%d: failed to parse %q: %v", idx, testCase.Input, err)
+			continue
+		}
+		if cfg != testCase.Expected {
+			t.Errorf("#%d: unexpected config for %q: %v != %v", idx, testCase.Input, cfg, testCase.Expected)
+		}
+	}
+
+	for idx, input := range []string{"", "testnet", "testnet@", "@:23113"} {
+		_, err := ParseStakingNetworkConfig(input)
+		if err == nil {
+			t.Errorf("#%d: expected %q to fail to parse", idx, input)
+		}
+	}
+}
+
+func TestVerifyStakingNetworks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BlockchainInfo.NetworkName = "standard"
+
+	cfg.StakingNetworks = []StakingNetworkConfig{{NetworkName: "testnet", RPCaddr: ":23113"}}
+	if err := VerifyStakingNetworks(cfg); err != nil {
+		t.Errorf("expected valid staking networks: %v", err)
+	}
+
+	for idx, stakingNetworks := range [][]StakingNetworkConfig{
+		{{NetworkName: "unknown", RPCaddr: ":23113"}},
+		{{NetworkName: "standard", RPCaddr: ":23113"}},
+		{{NetworkName: "testnet", RPCaddr: cfg.RPCaddr}},
+		{{NetworkName: "testnet", RPCaddr: ":23113"}, {NetworkName: "testnet", RPCaddr: ":23114"}},
+	} {
+		cfg.StakingNetworks = stakingNetworks
+		if err := VerifyStakingNetworks(cfg); err == nil {
+			t.Errorf("#%d: expected staking networks to be invalid", idx)
+		}
+	}
+}
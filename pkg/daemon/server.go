@@ -1,8 +1,14 @@
 package daemon
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -10,23 +16,63 @@ import (
 type HTTPServer struct {
 	httpServer *http.Server
 	mux        *http.ServeMux
-	listener   net.Listener
+	listeners  []net.Listener
 }
 
 // NewHTTPServer creates a new net.http server listening on bindAddr.
-func NewHTTPServer(bindAddr string) (*HTTPServer, error) {
-	l, err := net.Listen("tcp", bindAddr)
-	if err != nil {
-		return nil, err
-	}
+// The server is served over TLS in case a TLS config is given.
+// No TCP listener is created in case bindAddr is empty,
+// in which case the server has to listen on a unix socket instead.
+func NewHTTPServer(bindAddr string, tlsConfig *tls.Config) (*HTTPServer, error) {
 	mux := http.NewServeMux()
-	return &HTTPServer{
-		mux:      mux,
-		listener: l,
+	srv := &HTTPServer{
+		mux: mux,
 		httpServer: &http.Server{
 			Handler: mux,
 		},
-	}, nil
+	}
+	if bindAddr == "" {
+		return srv, nil
+	}
+	l, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		l = tls.NewListener(l, tlsConfig)
+	}
+	srv.listeners = append(srv.listeners, l)
+	return srv, nil
+}
+
+// ListenUnix makes the server listen on a unix domain socket at the given path as well,
+// restricting access to the socket using the given file permissions.
+// A stale socket left behind at the given path is removed.
+// Must be called prior to serving the server.
+func (srv *HTTPServer) ListenUnix(path string, perm os.FileMode) error {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("cannot listen on unix socket %q: file exists and is not a socket", path)
+		}
+		// refuse to remove a socket which is still in use
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return fmt.Errorf("cannot listen on unix socket %q: socket is already in use", path)
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(path, perm); err != nil {
+		l.Close()
+		return err
+	}
+	srv.listeners = append(srv.listeners, l)
+	return nil
 }
 
 // Handle the given pattern using the given handler.
@@ -34,19 +80,58 @@ func (srv *HTTPServer) Handle(pattern string, handler http.Handler) {
 	srv.mux.Handle(pattern, handler)
 }
 
-// Serve all registered endpoins as a REST API over HTTP endpoints.
+// Serve all registered endpoins as a REST API over HTTP endpoints,
+// on all listeners of the server.
 func (srv *HTTPServer) Serve() error {
-	// The server will run until an error is encountered or the listener is
-	// closed, via the Close method. Closing the listener will result in the benign error handled below.
-	err := srv.httpServer.Serve(srv.listener)
-	if err != nil && !strings.HasSuffix(err.Error(), "use of closed network connection") {
-		return err
+	if len(srv.listeners) == 0 {
+		return errors.New("HTTP server has no listeners")
+	}
+	errs := make(chan error, len(srv.listeners))
+	for _, l := range srv.listeners {
+		go func(l net.Listener) {
+			// The server will run until an error is encountered or the listener is
+			// closed, via the Close method. Closing the listener will result in the benign error handled below.
+			err := srv.httpServer.Serve(l)
+			if err != nil && strings.HasSuffix(err.Error(), "use of closed network connection") {
+				err = nil
+			}
+			errs <- err
+		}(l)
+	}
+	for range srv.listeners {
+		if err := <-errs; err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// Close closes the Server's listener, causing the HTTP server to shut down.
+// Close closes the Server's listeners, causing the HTTP server to shut down.
 func (srv *HTTPServer) Close() error {
-	// Close the listener, which will cause Server.Serve() to return.
-	return srv.listener.Close()
+	var err error
+	// Close the listeners, which will cause Server.Serve() to return.
+	for _, l := range srv.listeners {
+		if closeErr := l.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// APISocket returns the path and file permissions of the unix domain socket the HTTP API listens on,
+// or an empty path in case the HTTP API does not listen on a unix socket.
+// A relative socket path is resolved relative to the root persistent directory.
+func APISocket(cfg Config) (string, os.FileMode, error) {
+	if cfg.APISocket == "" {
+		return "", 0, nil
+	}
+	perm, err := strconv.ParseUint(cfg.APISocketMode, 8, 32)
+	if err != nil || os.FileMode(perm)&^os.ModePerm != 0 {
+		return "", 0, fmt.Errorf("invalid API socket mode %q: expected octal file permissions (e.g. 0660)", cfg.APISocketMode)
+	}
+	path := cfg.APISocket
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.RootPersistentDir, path)
+	}
+	return path, os.FileMode(perm), nil
 }
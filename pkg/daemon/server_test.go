@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPServerListenUnix(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RootPersistentDir = t.TempDir()
+	cfg.APISocket = "api.sock"
+	path, perm, err := APISocket(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(cfg.RootPersistentDir, "api.sock") || perm != 0660 {
+		t.Fatalf("unexpected API socket: %q (%v)", path, perm)
+	}
+	cfg.APISocketMode = "rw"
+	if _, _, err = APISocket(cfg); err == nil {
+		t.Fatal("expected an error for an invalid socket mode")
+	}
+
+	// a stale socket is removed
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	srv, err := NewHTTPServer("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = srv.ListenUnix(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	srv.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	go srv.Serve()
+	defer srv.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("unexpected socket permissions: %v", info.Mode().Perm())
+	}
+
+	// a socket in use is not removed
+	srv2, _ := NewHTTPServer("", nil)
+	if err = srv2.ListenUnix(path, 0600); err == nil {
+		t.Fatal("expected an error for a socket in use")
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://unix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "hello" {
+		t.Fatalf("unexpected response: %q", string(body))
+	}
+}
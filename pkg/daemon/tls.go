@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultAPITLSCertFile is the name of the self-signed certificate file,
+	// stored in the root persistent directory, used if no certificate file is configured.
+	DefaultAPITLSCertFile = "api.crt"
+	// DefaultAPITLSKeyFile is the name of the self-signed key file,
+	// stored in the root persistent directory, used if no key file is configured.
+	DefaultAPITLSKeyFile = "api.key"
+
+	// selfSignedCertificateValidity defines how long a generated self-signed certificate is valid
+	selfSignedCertificateValidity = 365 * 24 * time.Hour
+)
+
+// APITLSConfig returns the TLS config used to serve the HTTP API,
+// or nil in case the HTTP API is to be served over plain HTTP.
+// A self-signed certificate is generated if requested and the certificate file does not exist yet.
+func APITLSConfig(cfg Config) (*tls.Config, error) {
+	certFile, keyFile := cfg.APITLSCertFile, cfg.APITLSKeyFile
+	if certFile == "" && keyFile == "" {
+		if !cfg.APITLSSelfSigned {
+			return nil, nil
+		}
+		certFile = filepath.Join(cfg.RootPersistentDir, DefaultAPITLSCertFile)
+		keyFile = filepath.Join(cfg.RootPersistentDir, DefaultAPITLSKeyFile)
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both a TLS certificate and key file are required to serve the API over TLS")
+	}
+	if cfg.APITLSSelfSigned {
+		if _, err := os.Stat(certFile); os.IsNotExist(err) {
+			err = GenerateSelfSignedCertificate(certFile, keyFile, apiHosts(cfg.APIaddr))
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate self-signed API certificate: %v", err)
+			}
+		}
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API certificate: %v", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// apiHosts returns the hosts a self-signed certificate is generated for,
+// the local hosts as well as the host the API listens on, if defined.
+func apiHosts(apiAddr string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	host, _, err := net.SplitHostPort(apiAddr)
+	if err != nil || host == "" {
+		return hosts
+	}
+	for _, h := range hosts {
+		if h == host {
+			return hosts
+		}
+	}
+	return append(hosts, host)
+}
+
+// GenerateSelfSignedCertificate generates a self-signed (ECDSA P-256) certificate for the given hosts,
+// storing it and its private key PEM-encoded in the given files. The certificate can be used as its own
+// certificate authority, such that clients can trust it explicitly.
+func GenerateSelfSignedCertificate(certFile, keyFile string, hosts []string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"Rivine daemon"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedCertificateValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	err = writePEMFile(keyFile, "PRIVATE KEY", keyBytes, 0600)
+	if err != nil {
+		return err
+	}
+	return writePEMFile(certFile, "CERTIFICATE", certBytes, 0644)
+}
+
+func writePEMFile(filename, blockType string, data []byte, perm os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(filename), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	err = pem.Encode(file, &pem.Block{Type: blockType, Bytes: data})
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
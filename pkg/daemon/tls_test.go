@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestAPITLSConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RootPersistentDir = t.TempDir()
+	cfg.APIaddr = "127.0.0.1:0"
+
+	// plain HTTP by default
+	tlsConfig, err := APITLSConfig(cfg)
+	if err != nil || tlsConfig != nil {
+		t.Fatalf("expected no TLS config and no error, got: %v, %v", tlsConfig, err)
+	}
+
+	// a certificate without key is invalid
+	cfg.APITLSCertFile = filepath.Join(cfg.RootPersistentDir, "cert.pem")
+	if _, err = APITLSConfig(cfg); err == nil {
+		t.Fatal("expected an error for a certificate file without key file")
+	}
+	cfg.APITLSCertFile = ""
+
+	// generate a self-signed certificate at the default location
+	cfg.APITLSSelfSigned = true
+	tlsConfig, err = APITLSConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig == nil || len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("unexpected TLS config: %v", tlsConfig)
+	}
+	certFile := filepath.Join(cfg.RootPersistentDir, DefaultAPITLSCertFile)
+	pemBytes, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the existing certificate is reused
+	tlsConfig2, err := APITLSConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tlsConfig2.Certificates[0].Certificate[0]) != string(tlsConfig.Certificates[0].Certificate[0]) {
+		t.Fatal("expected the existing self-signed certificate to be reused")
+	}
+
+	// serve over TLS, trusting the self-signed certificate as client
+	srv, err := NewHTTPServer(cfg.APIaddr, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	go srv.Serve()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		t.Fatal("failed to parse generated certificate")
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + srv.listeners[0].Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "hello" {
+		t.Fatalf("unexpected response: %q", string(body))
+	}
+}
@@ -0,0 +1,225 @@
+// Package bech32 implements the bech32 encoding, as defined in BIP 173.
+//
+// Bech32 strings consist of a human-readable part (HRP), the separator '1',
+// and the (base32-encoded) data, followed by a checksum of 6 characters.
+// The checksum guarantees the detection of any error affecting at most 4 characters,
+// which makes bech32 strings well suited to be copied and typed by humans.
+//
+// See https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki for more information.
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// separator separates the human-readable part from the data part.
+	separator = '1'
+	// checksumSize is the amount of characters used for the checksum.
+	checksumSize = 6
+	// MaxLength is the maximum length of a bech32 string.
+	MaxLength = 90
+)
+
+// bech32 errors
+var (
+	// ErrInvalidLength is returned in case a bech32 string is too short or too long.
+	ErrInvalidLength = errors.New("invalid bech32 string length")
+	// ErrMixedCase is returned in case a bech32 string contains both lower- and uppercase characters.
+	ErrMixedCase = errors.New("bech32 string cannot be of mixed case")
+	// ErrInvalidHRP is returned in case the human-readable part of a bech32 string is invalid.
+	ErrInvalidHRP = errors.New("invalid bech32 human-readable part")
+	// ErrMissingSeparator is returned in case a bech32 string contains no separator.
+	ErrMissingSeparator = errors.New("bech32 string has no separator")
+	// ErrInvalidCharacter is returned in case the data part of a bech32 string contains a character outside of its charset.
+	ErrInvalidCharacter = errors.New("invalid bech32 character")
+	// ErrInvalidChecksum is returned in case the checksum of a bech32 string is invalid.
+	ErrInvalidChecksum = errors.New("invalid bech32 checksum")
+	// ErrInvalidPadding is returned in case the data of a bech32 string is not correctly padded.
+	ErrInvalidPadding = errors.New("invalid bech32 data padding")
+)
+
+var charsetRev = func() (rev [128]int8) {
+	for i := range rev {
+		rev[i] = -1
+	}
+	for i, c := range charset {
+		rev[c] = int8(i)
+	}
+	return
+}()
+
+// Encode encodes the given (8-bit) data as a bech32 string, using the given human-readable part.
+// The human-readable part is lowercased, as is the resulting string.
+func Encode(hrp string, data []byte) (string, error) {
+	values, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return EncodeValues(hrp, values)
+}
+
+// Decode decodes a bech32 string, returning its (lowercased) human-readable part and (8-bit) data.
+func Decode(str string) (string, []byte, error) {
+	hrp, values, err := DecodeValues(str)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := ConvertBits(values, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+// EncodeValues encodes the given 5-bit values as a bech32 string, using the given human-readable part.
+func EncodeValues(hrp string, values []byte) (string, error) {
+	hrp = strings.ToLower(hrp)
+	if err := ValidateHRP(hrp); err != nil {
+		return "", err
+	}
+	if len(hrp)+1+len(values)+checksumSize > MaxLength {
+		return "", ErrInvalidLength
+	}
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(values) + checksumSize)
+	sb.WriteString(hrp)
+	sb.WriteByte(separator)
+	for _, v := range values {
+		if v >= 32 {
+			return "", fmt.Errorf("invalid 5-bit value %d", v)
+		}
+		sb.WriteByte(charset[v])
+	}
+	for _, v := range createChecksum(hrp, values) {
+		sb.WriteByte(charset[v])
+	}
+	return sb.String(), nil
+}
+
+// DecodeValues decodes a bech32 string, returning its (lowercased) human-readable part and 5-bit values.
+func DecodeValues(str string) (string, []byte, error) {
+	if len(str) < 1+1+checksumSize || len(str) > MaxLength {
+		return "", nil, ErrInvalidLength
+	}
+	lower := strings.ToLower(str)
+	if lower != str && strings.ToUpper(str) != str {
+		return "", nil, ErrMixedCase
+	}
+	str = lower
+
+	sepIndex := strings.LastIndexByte(str, separator)
+	if sepIndex < 0 {
+		return "", nil, ErrMissingSeparator
+	}
+	if sepIndex+1+checksumSize > len(str) {
+		return "", nil, ErrInvalidLength
+	}
+	hrp := str[:sepIndex]
+	if err := ValidateHRP(hrp); err != nil {
+		return "", nil, err
+	}
+
+	values := make([]byte, 0, len(str)-sepIndex-1)
+	for i := sepIndex + 1; i < len(str); i++ {
+		c := str[i]
+		if c >= 128 || charsetRev[c] < 0 {
+			return "", nil, fmt.Errorf("%v: %q at position %d", ErrInvalidCharacter, c, i)
+		}
+		values = append(values, byte(charsetRev[c]))
+	}
+	if polymod(append(expandHRP(hrp), values...)) != 1 {
+		return "", nil, ErrInvalidChecksum
+	}
+	return hrp, values[:len(values)-checksumSize], nil
+}
+
+// ValidateHRP validates a (lowercase) human-readable part,
+// which has to consist of 1 to 83 printable US-ASCII characters.
+func ValidateHRP(hrp string) error {
+	if len(hrp) == 0 || len(hrp) > 83 {
+		return fmt.Errorf("%v: length has to be within the [1, 83] range", ErrInvalidHRP)
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return fmt.Errorf("%v: invalid character %q", ErrInvalidHRP, hrp[i])
+		}
+	}
+	if strings.ToLower(hrp) != hrp {
+		return fmt.Errorf("%v: has to be lowercase", ErrInvalidHRP)
+	}
+	return nil
+}
+
+// ConvertBits regroups the given values of fromBits bits as values of toBits bits.
+// If pad is true, the last value is padded with zero bits if required,
+// otherwise an error is returned in case non-zero padding bits would be left over.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var (
+		acc    uint32
+		bits   uint
+		maxv   = uint32(1)<<toBits - 1
+		result = make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid %d-bit value %d", fromBits, v)
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+	return result, nil
+}
+
+func polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := uint(0); i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func expandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func createChecksum(hrp string, values []byte) []byte {
+	input := append(expandHRP(hrp), values...)
+	input = append(input, make([]byte, checksumSize)...)
+	mod := polymod(input) ^ 1
+	checksum := make([]byte, checksumSize)
+	for i := range checksum {
+		checksum[i] = byte(mod >> uint(5*(5-i)) & 31)
+	}
+	return checksum
+}
@@ -0,0 +1,97 @@
+package bech32
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// test vectors as defined in BIP 173
+func TestDecodeValidStrings(t *testing.T) {
+	for idx, str := range []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	} {
+		hrp, values, err := DecodeValues(str)
+		if err != nil {
+			t.Errorf("valid test case #%d: failed to decode %s: %v", idx, str, err)
+			continue
+		}
+		encoded, err := EncodeValues(hrp, values)
+		if err != nil {
+			t.Errorf("valid test case #%d: failed to re-encode %s: %v", idx, str, err)
+			continue
+		}
+		if encoded != strings.ToLower(str) {
+			t.Errorf("valid test case #%d: %s != %s", idx, encoded, strings.ToLower(str))
+		}
+	}
+}
+
+func TestDecodeInvalidStrings(t *testing.T) {
+	for idx, str := range []string{
+		"\x201nwldj5", // HRP character out of range
+		"\x7f1axkwrx", // HRP character out of range
+		"\x801eym55h", // HRP character out of range
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", // overall max length exceeded
+		"pzry9x0s0muk",  // no separator character
+		"1pzry9x0s0muk", // empty HRP
+		"x1b4n0q5v",     // invalid data character
+		"li1dgmt3",      // too short checksum
+		"de1lg7wt\xff",  // invalid character in checksum
+		"A1G7SGD8",      // checksum calculated with uppercase form of HRP
+		"10a06t8",       // empty HRP
+		"1qzzfhee",      // empty HRP
+		"a12UEL5L",      // mixed case
+	} {
+		if _, _, err := DecodeValues(str); err == nil {
+			t.Errorf("invalid test case #%d: expected %q to be rejected", idx, str)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for idx, data := range [][]byte{
+		{},
+		{0},
+		{1, 2, 3, 4, 5},
+		bytes.Repeat([]byte{0xff}, 33),
+	} {
+		str, err := Encode("riv", data)
+		if err != nil {
+			t.Errorf("test case #%d: failed to encode %x: %v", idx, data, err)
+			continue
+		}
+		hrp, decoded, err := Decode(str)
+		if err != nil {
+			t.Errorf("test case #%d: failed to decode %s: %v", idx, str, err)
+			continue
+		}
+		if hrp != "riv" {
+			t.Errorf("test case #%d: unexpected HRP %s", idx, hrp)
+		}
+		if !bytes.Equal(data, decoded) {
+			t.Errorf("test case #%d: %x != %x", idx, decoded, data)
+		}
+		// any single character error is detected
+		for i := len("riv") + 1; i < len(str); i++ {
+			b := []byte(str)
+			if b[i] == 'q' {
+				b[i] = 'p'
+			} else {
+				b[i] = 'q'
+			}
+			if _, _, err := Decode(string(b)); err == nil {
+				t.Errorf("test case #%d: expected %s to be rejected", idx, string(b))
+			}
+		}
+	}
+	if _, err := Encode("Invalid HRP", nil); err == nil {
+		t.Error("expected HRP with a space to be rejected")
+	}
+}
@@ -0,0 +1,213 @@
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Errors returned by this package.
+var (
+	// ErrNonIntegerNumber is returned in case a number with a fraction or exponent is encountered,
+	// as only integer numbers are supported by the canonical representation.
+	ErrNonIntegerNumber = errors.New("canonical JSON only supports integer numbers, without fraction or exponent")
+	// ErrDuplicateKey is returned in case an object defines the same key more than once.
+	ErrDuplicateKey = errors.New("duplicate object key")
+	// ErrTrailingData is returned in case data is found after the (first) JSON value.
+	ErrTrailingData = errors.New("trailing data after JSON value")
+	// ErrNotRoundTripStable is returned by Unmarshal in case the given data
+	// doesn't survive a round trip through the value it is decoded into,
+	// which is for example the case when the data defines unknown fields.
+	ErrNotRoundTripStable = errors.New("JSON data does not round-trip through the decoded value")
+)
+
+// Marshal returns the canonical JSON encoding of v,
+// using the (regular) JSON encoding of v as its source.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(buf.Bytes())
+}
+
+// Unmarshal decodes the (canonical or not) JSON data into v, in a strict manner.
+// On top of the validation done by the JSON decoding of v itself,
+// an error is returned in case the data defines duplicate keys,
+// or in case the data does not survive a round trip through v,
+// which is for example the case when the data defines unknown fields.
+//
+// The given data doesn't have to be canonical, but it has to be
+// equal to the encoding of v once canonicalized.
+func Unmarshal(data []byte, v interface{}) error {
+	canonical, err := Canonicalize(data)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(canonical))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(v)
+	if err != nil {
+		return err
+	}
+	encoded, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(canonical, encoded) {
+		return ErrNotRoundTripStable
+	}
+	return nil
+}
+
+// Canonicalize returns the canonical form of the given JSON data.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	err := canonicalizeValue(dec, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		if err == nil {
+			err = ErrTrailingData
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalizeValue reads the next JSON value from the decoder,
+// and writes it in canonical form to the buffer.
+func canonicalizeValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			return canonicalizeObject(dec, buf)
+		}
+		return canonicalizeArray(dec, buf)
+	case string:
+		return writeString(buf, t)
+	case json.Number:
+		return writeNumber(buf, t)
+	case bool:
+		if t {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+		return nil
+	case nil:
+		buf.WriteString("null")
+		return nil
+	default:
+		return fmt.Errorf("unexpected JSON token %v (%T)", token, token)
+	}
+}
+
+// canonicalizeObject reads the members of a JSON object,
+// and writes them sorted by key to the buffer.
+func canonicalizeObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	members := make(map[string][]byte)
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected JSON object key %v (%T)", token, token)
+		}
+		if _, ok = members[key]; ok {
+			return fmt.Errorf("%v: %q", ErrDuplicateKey, key)
+		}
+		var value bytes.Buffer
+		err = canonicalizeValue(dec, &value)
+		if err != nil {
+			return err
+		}
+		members[key] = value.Bytes()
+	}
+	if _, err := dec.Token(); err != nil { // closing '}'
+		return err
+	}
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	buf.WriteByte('{')
+	for idx, key := range keys {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		err := writeString(buf, key)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		buf.Write(members[key])
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// canonicalizeArray reads the elements of a JSON array,
+// and writes them in their original order to the buffer.
+func canonicalizeArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for idx := 0; dec.More(); idx++ {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		err := canonicalizeValue(dec, buf)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // closing ']'
+		return err
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// writeString writes a string as a JSON string, without escaping HTML characters.
+func writeString(buf *bytes.Buffer, str string) error {
+	var sb bytes.Buffer
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(str)
+	if err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(sb.Bytes(), []byte{'\n'}))
+	return nil
+}
+
+// writeNumber writes a number as a decimal integer.
+func writeNumber(buf *bytes.Buffer, number json.Number) error {
+	str := number.String()
+	if strings.ContainsAny(str, ".eE") {
+		return fmt.Errorf("%v: %s", ErrNonIntegerNumber, str)
+	}
+	x, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return fmt.Errorf("invalid JSON number %q", str)
+	}
+	buf.WriteString(x.String())
+	return nil
+}
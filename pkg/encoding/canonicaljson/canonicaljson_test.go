@@ -0,0 +1,127 @@
+package canonicaljson
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected string
+	}{
+		{`null`, `null`},
+		{` true `, `true`},
+		{`"<a&b>"`, `"<a&b>"`},
+		{`"A"`, `"A"`},
+		{`-0`, `0`},
+		{`18446744073709551616`, `18446744073709551616`},
+		{`[ 1, "2", [ ] ]`, `[1,"2",[]]`},
+		{`{ "b": 1, "a": { "d": null, "c": [ {} ] } }`, `{"a":{"c":[{}],"d":null},"b":1}`},
+		{"{\n\t\"z\": \"\\/\",\n\t\"y\": false\n}", `{"y":false,"z":"/"}`},
+	}
+	for idx, testCase := range testCases {
+		output, err := Canonicalize([]byte(testCase.Input))
+		if err != nil {
+			t.Errorf("test case #%d: failed to canonicalize %s: %v", idx, testCase.Input, err)
+			continue
+		}
+		if string(output) != testCase.Expected {
+			t.Errorf("test case #%d: %s != %s", idx, string(output), testCase.Expected)
+		}
+		// canonicalization is idempotent
+		again, err := Canonicalize(output)
+		if err != nil {
+			t.Errorf("test case #%d: failed to canonicalize canonical %s: %v", idx, string(output), err)
+		} else if string(again) != string(output) {
+			t.Errorf("test case #%d: canonicalization is not idempotent: %s != %s", idx, string(again), string(output))
+		}
+	}
+
+	for idx, input := range []string{
+		``,
+		`1.5`,
+		`1e3`,
+		`1.0`,
+		`{"a":1,"a":2}`,
+		`{"a":1} {}`,
+		`[1,]`,
+		`{"a"}`,
+	} {
+		if output, err := Canonicalize([]byte(input)); err == nil {
+			t.Errorf("invalid test case #%d: expected %s to be rejected, but got %s", idx, input, string(output))
+		}
+	}
+}
+
+func TestMarshalUnmarshalTransaction(t *testing.T) {
+	uh := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{1, 2, 3})
+	txn := types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinInputs: []types.CoinInput{{
+			ParentID:    types.CoinOutputID{4, 5, 6},
+			Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(crypto.PublicKey{7}))),
+		}},
+		CoinOutputs: []types.CoinOutput{
+			{Value: types.NewCurrency64(42), Condition: types.NewCondition(types.NewUnlockHashCondition(uh))},
+			{Value: types.NewCurrency64(1), Condition: types.NewCondition(types.NewTimeLockCondition(1600000000, types.NewUnlockHashCondition(uh)))},
+		},
+		MinerFees:     []types.Currency{types.NewCurrency64(1)},
+		ArbitraryData: []byte("<canonical & deterministic>"),
+	}
+	b, err := Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(string(b), " \n\t") {
+		t.Fatalf("canonical JSON contains insignificant whitespace: %s", string(b))
+	}
+	var decoded types.Transaction
+	if err = Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != txn.ID() {
+		t.Fatal("transaction changed after round trip:", string(b))
+	}
+	b2, err := Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(b2) {
+		t.Fatalf("canonical JSON is not round-trip stable: %s != %s", string(b), string(b2))
+	}
+
+	// unknown fields are rejected, even within nested (custom decoded) values
+	unknownField := strings.Replace(string(b), `"minerfees":`, `"unknown":true,"minerfees":`, 1)
+	if err = Unmarshal([]byte(unknownField), &decoded); err == nil {
+		t.Fatal("expected unknown field to be rejected:", unknownField)
+	}
+	duplicateKey := strings.Replace(string(b), `"version":1`, `"version":1,"version":1`, 1)
+	if err = Unmarshal([]byte(duplicateKey), &decoded); err == nil {
+		t.Fatal("expected duplicate key to be rejected:", duplicateKey)
+	}
+}
+
+func TestMarshalUnmarshalBlock(t *testing.T) {
+	block := types.Block{
+		ParentID:  types.BlockID{1},
+		Timestamp: 1600000000,
+		MinerPayouts: []types.MinerPayout{
+			{Value: types.NewCurrency64(10), UnlockHash: types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{2})},
+		},
+	}
+	b, err := Marshal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.Block
+	if err = Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != block.ID() {
+		t.Fatal("block changed after round trip:", string(b))
+	}
+}
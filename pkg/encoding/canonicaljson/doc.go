@@ -0,0 +1,10 @@
+// Package canonicaljson converts arbitrary objects into their canonical JSON representation,
+// and vice versa. The canonical representation is round-trip stable: object keys are sorted,
+// no insignificant whitespace is used, strings are escaped in a single way and numbers are
+// formatted as integers without exponent or fraction. This allows external systems to
+// hash and sign the JSON representation of consensus types (such as blocks, transactions,
+// conditions and fulfillments) in a deterministic way.
+//
+// Decoding is strict: duplicate object keys are rejected, as is any data which
+// doesn't survive a round trip through the decoded value, such as unknown fields.
+package canonicaljson
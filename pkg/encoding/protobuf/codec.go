@@ -0,0 +1,652 @@
+package protobuf
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// MarshalBlock encodes a block as a protobuf Block message.
+func MarshalBlock(block types.Block) ([]byte, error) {
+	var e encoder
+	err := encodeBlock(&e, block)
+	return e.buf, err
+}
+
+// UnmarshalBlock decodes a protobuf Block message into a block.
+func UnmarshalBlock(b []byte, block *types.Block) error {
+	*block = types.Block{}
+	return decodeBlock(b, block)
+}
+
+// MarshalTransaction encodes a transaction as a protobuf Transaction message.
+func MarshalTransaction(txn types.Transaction) ([]byte, error) {
+	var e encoder
+	err := encodeTransaction(&e, txn)
+	return e.buf, err
+}
+
+// UnmarshalTransaction decodes a protobuf Transaction message into a transaction.
+func UnmarshalTransaction(b []byte, txn *types.Transaction) error {
+	*txn = types.Transaction{}
+	return decodeTransaction(b, txn)
+}
+
+// MarshalCoinOutput encodes a coin output as a protobuf CoinOutput message.
+func MarshalCoinOutput(co types.CoinOutput) ([]byte, error) {
+	var e encoder
+	err := encodeOutput(&e, co.Value, co.Condition)
+	return e.buf, err
+}
+
+// UnmarshalCoinOutput decodes a protobuf CoinOutput message into a coin output.
+func UnmarshalCoinOutput(b []byte, co *types.CoinOutput) error {
+	*co = types.CoinOutput{}
+	return decodeOutput(b, &co.Value, &co.Condition)
+}
+
+// MarshalBlockStakeOutput encodes a block stake output as a protobuf BlockStakeOutput message.
+func MarshalBlockStakeOutput(bso types.BlockStakeOutput) ([]byte, error) {
+	var e encoder
+	err := encodeOutput(&e, bso.Value, bso.Condition)
+	return e.buf, err
+}
+
+// UnmarshalBlockStakeOutput decodes a protobuf BlockStakeOutput message into a block stake output.
+func UnmarshalBlockStakeOutput(b []byte, bso *types.BlockStakeOutput) error {
+	*bso = types.BlockStakeOutput{}
+	return decodeOutput(b, &bso.Value, &bso.Condition)
+}
+
+// MarshalCondition encodes an unlock condition as a protobuf Condition message.
+func MarshalCondition(condition types.UnlockConditionProxy) ([]byte, error) {
+	var e encoder
+	err := encodeCondition(&e, condition)
+	return e.buf, err
+}
+
+// UnmarshalCondition decodes a protobuf Condition message into an unlock condition.
+func UnmarshalCondition(b []byte, condition *types.UnlockConditionProxy) error {
+	return decodeCondition(b, condition)
+}
+
+// MarshalFulfillment encodes an unlock fulfillment as a protobuf Fulfillment message.
+func MarshalFulfillment(fulfillment types.UnlockFulfillmentProxy) ([]byte, error) {
+	var e encoder
+	err := encodeFulfillment(&e, fulfillment)
+	return e.buf, err
+}
+
+// UnmarshalFulfillment decodes a protobuf Fulfillment message into an unlock fulfillment.
+func UnmarshalFulfillment(b []byte, fulfillment *types.UnlockFulfillmentProxy) error {
+	return decodeFulfillment(b, fulfillment)
+}
+
+func encodeBlock(e *encoder, block types.Block) error {
+	e.bytes(1, block.ParentID[:])
+	e.uvarint(2, uint64(block.Timestamp))
+	e.message(3, func(e *encoder) error {
+		e.uvarint(1, uint64(block.POBSOutput.BlockHeight))
+		e.uvarint(2, block.POBSOutput.TransactionIndex)
+		e.uvarint(3, block.POBSOutput.OutputIndex)
+		return nil
+	})
+	for _, mp := range block.MinerPayouts {
+		e.message(4, func(e *encoder) error {
+			e.bytes(1, mp.Value.Big().Bytes())
+			return e.message(2, func(e *encoder) error {
+				encodeUnlockHash(e, mp.UnlockHash)
+				return nil
+			})
+		})
+	}
+	for _, txn := range block.Transactions {
+		err := e.message(5, func(e *encoder) error {
+			return encodeTransaction(e, txn)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeBlock(b []byte, block *types.Block) error {
+	return decodeFields(b, func(f field) (err error) {
+		switch f.number {
+		case 1:
+			err = decodeHash(f, (*crypto.Hash)(&block.ParentID))
+		case 2:
+			var x uint64
+			x, err = f.uvarint()
+			block.Timestamp = types.Timestamp(x)
+		case 3:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			err = decodeFields(data, func(f field) (err error) {
+				var x uint64
+				switch f.number {
+				case 1:
+					x, err = f.uvarint()
+					block.POBSOutput.BlockHeight = types.BlockHeight(x)
+				case 2:
+					block.POBSOutput.TransactionIndex, err = f.uvarint()
+				case 3:
+					block.POBSOutput.OutputIndex, err = f.uvarint()
+				}
+				return
+			})
+		case 4:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var mp types.MinerPayout
+			err = decodeFields(data, func(f field) (err error) {
+				switch f.number {
+				case 1:
+					err = decodeCurrency(f, &mp.Value)
+				case 2:
+					err = decodeUnlockHashField(f, &mp.UnlockHash)
+				}
+				return
+			})
+			block.MinerPayouts = append(block.MinerPayouts, mp)
+		case 5:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var txn types.Transaction
+			err = decodeTransaction(data, &txn)
+			block.Transactions = append(block.Transactions, txn)
+		}
+		return
+	})
+}
+
+func encodeTransaction(e *encoder, txn types.Transaction) error {
+	e.uvarint(1, uint64(txn.Version))
+	for _, ci := range txn.CoinInputs {
+		err := e.message(2, func(e *encoder) error {
+			return encodeInput(e, crypto.Hash(ci.ParentID), ci.Fulfillment)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, co := range txn.CoinOutputs {
+		err := e.message(3, func(e *encoder) error {
+			return encodeOutput(e, co.Value, co.Condition)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, bsi := range txn.BlockStakeInputs {
+		err := e.message(4, func(e *encoder) error {
+			return encodeInput(e, crypto.Hash(bsi.ParentID), bsi.Fulfillment)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, bso := range txn.BlockStakeOutputs {
+		err := e.message(5, func(e *encoder) error {
+			return encodeOutput(e, bso.Value, bso.Condition)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, fee := range txn.MinerFees {
+		// repeated bytes are always encoded, even if empty, as to preserve the amount of fees
+		e.lengthDelimited(6, fee.Big().Bytes())
+	}
+	e.bytes(7, txn.ArbitraryData)
+	if txn.Extension != nil {
+		b, err := rivbin.Marshal(txn)
+		if err != nil {
+			return fmt.Errorf("failed to rivbin-encode transaction with extension: %v", err)
+		}
+		e.bytes(8, b)
+	}
+	return nil
+}
+
+func decodeTransaction(b []byte, txn *types.Transaction) error {
+	var encoded []byte
+	err := decodeFields(b, func(f field) (err error) {
+		var data []byte
+		switch f.number {
+		case 1:
+			var x uint64
+			x, err = f.uvarint()
+			txn.Version = types.TransactionVersion(x)
+		case 2:
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var ci types.CoinInput
+			err = decodeInput(data, (*crypto.Hash)(&ci.ParentID), &ci.Fulfillment)
+			txn.CoinInputs = append(txn.CoinInputs, ci)
+		case 3:
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var co types.CoinOutput
+			err = decodeOutput(data, &co.Value, &co.Condition)
+			txn.CoinOutputs = append(txn.CoinOutputs, co)
+		case 4:
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var bsi types.BlockStakeInput
+			err = decodeInput(data, (*crypto.Hash)(&bsi.ParentID), &bsi.Fulfillment)
+			txn.BlockStakeInputs = append(txn.BlockStakeInputs, bsi)
+		case 5:
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			var bso types.BlockStakeOutput
+			err = decodeOutput(data, &bso.Value, &bso.Condition)
+			txn.BlockStakeOutputs = append(txn.BlockStakeOutputs, bso)
+		case 6:
+			var fee types.Currency
+			err = decodeCurrency(f, &fee)
+			txn.MinerFees = append(txn.MinerFees, fee)
+		case 7:
+			txn.ArbitraryData, err = f.bytes()
+		case 8:
+			encoded, err = f.bytes()
+		}
+		return
+	})
+	if err != nil || len(encoded) == 0 {
+		return err
+	}
+	*txn = types.Transaction{}
+	return rivbin.Unmarshal(encoded, txn)
+}
+
+func encodeInput(e *encoder, parentID crypto.Hash, fulfillment types.UnlockFulfillmentProxy) error {
+	e.bytes(1, parentID[:])
+	return e.message(2, func(e *encoder) error {
+		return encodeFulfillment(e, fulfillment)
+	})
+}
+
+func decodeInput(b []byte, parentID *crypto.Hash, fulfillment *types.UnlockFulfillmentProxy) error {
+	return decodeFields(b, func(f field) (err error) {
+		switch f.number {
+		case 1:
+			err = decodeHash(f, parentID)
+		case 2:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			err = decodeFulfillment(data, fulfillment)
+		}
+		return
+	})
+}
+
+func encodeOutput(e *encoder, value types.Currency, condition types.UnlockConditionProxy) error {
+	e.bytes(1, value.Big().Bytes())
+	return e.message(2, func(e *encoder) error {
+		return encodeCondition(e, condition)
+	})
+}
+
+func decodeOutput(b []byte, value *types.Currency, condition *types.UnlockConditionProxy) error {
+	return decodeFields(b, func(f field) (err error) {
+		switch f.number {
+		case 1:
+			err = decodeCurrency(f, value)
+		case 2:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			err = decodeCondition(data, condition)
+		}
+		return
+	})
+}
+
+func encodeCondition(e *encoder, condition types.UnlockConditionProxy) error {
+	switch c := condition.Condition.(type) {
+	case nil, *types.NilCondition:
+		return e.message(1, func(*encoder) error { return nil })
+	case *types.UnlockHashCondition:
+		return e.message(2, func(e *encoder) error {
+			return e.message(1, func(e *encoder) error {
+				encodeUnlockHash(e, c.TargetUnlockHash)
+				return nil
+			})
+		})
+	case *types.AtomicSwapCondition:
+		return e.message(3, func(e *encoder) error {
+			e.message(1, func(e *encoder) error {
+				encodeUnlockHash(e, c.Sender)
+				return nil
+			})
+			e.message(2, func(e *encoder) error {
+				encodeUnlockHash(e, c.Receiver)
+				return nil
+			})
+			e.bytes(3, c.HashedSecret[:])
+			e.uvarint(4, uint64(c.TimeLock))
+			return nil
+		})
+	case *types.TimeLockCondition:
+		return e.message(4, func(e *encoder) error {
+			e.uvarint(1, c.LockTime)
+			return e.message(2, func(e *encoder) error {
+				return encodeCondition(e, types.NewCondition(c.Condition))
+			})
+		})
+	case *types.MultiSignatureCondition:
+		return e.message(5, func(e *encoder) error {
+			for _, uh := range c.UnlockHashes {
+				e.message(1, func(e *encoder) error {
+					encodeUnlockHash(e, uh)
+					return nil
+				})
+			}
+			e.uvarint(2, c.MinimumSignatureCount)
+			return nil
+		})
+	default:
+		b, err := rivbin.Marshal(condition)
+		if err != nil {
+			return fmt.Errorf("failed to rivbin-encode condition: %v", err)
+		}
+		return e.message(15, func(e *encoder) error {
+			e.uvarint(1, uint64(condition.ConditionType()))
+			e.bytes(2, b)
+			return nil
+		})
+	}
+}
+
+func decodeCondition(b []byte, condition *types.UnlockConditionProxy) error {
+	*condition = types.NewCondition(nil)
+	return decodeFields(b, func(f field) (err error) {
+		var data []byte
+		if f.number == 1 || f.number == 2 || f.number == 3 || f.number == 4 || f.number == 5 || f.number == 15 {
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+		}
+		switch f.number {
+		case 1:
+			*condition = types.NewCondition(nil)
+		case 2:
+			c := new(types.UnlockHashCondition)
+			err = decodeFields(data, func(f field) error {
+				if f.number == 1 {
+					return decodeUnlockHashField(f, &c.TargetUnlockHash)
+				}
+				return nil
+			})
+			*condition = types.NewCondition(c)
+		case 3:
+			c := new(types.AtomicSwapCondition)
+			err = decodeFields(data, func(f field) (err error) {
+				switch f.number {
+				case 1:
+					err = decodeUnlockHashField(f, &c.Sender)
+				case 2:
+					err = decodeUnlockHashField(f, &c.Receiver)
+				case 3:
+					err = decodeHash(f, (*crypto.Hash)(&c.HashedSecret))
+				case 4:
+					var x uint64
+					x, err = f.uvarint()
+					c.TimeLock = types.Timestamp(x)
+				}
+				return
+			})
+			*condition = types.NewCondition(c)
+		case 4:
+			c := &types.TimeLockCondition{Condition: &types.NilCondition{}}
+			err = decodeFields(data, func(f field) (err error) {
+				switch f.number {
+				case 1:
+					c.LockTime, err = f.uvarint()
+				case 2:
+					var data []byte
+					if data, err = f.bytes(); err != nil {
+						return err
+					}
+					var internal types.UnlockConditionProxy
+					if err = decodeCondition(data, &internal); err != nil {
+						return err
+					}
+					if internal.Condition != nil {
+						c.Condition = internal.Condition
+					}
+				}
+				return
+			})
+			*condition = types.NewCondition(c)
+		case 5:
+			c := new(types.MultiSignatureCondition)
+			err = decodeFields(data, func(f field) (err error) {
+				switch f.number {
+				case 1:
+					var uh types.UnlockHash
+					err = decodeUnlockHashField(f, &uh)
+					c.UnlockHashes = append(c.UnlockHashes, uh)
+				case 2:
+					c.MinimumSignatureCount, err = f.uvarint()
+				}
+				return
+			})
+			*condition = types.NewCondition(c)
+		case 15:
+			err = decodeFields(data, func(f field) error {
+				if f.number != 2 {
+					return nil
+				}
+				b, err := f.bytes()
+				if err != nil {
+					return err
+				}
+				return rivbin.Unmarshal(b, condition)
+			})
+		}
+		return
+	})
+}
+
+func encodeFulfillment(e *encoder, fulfillment types.UnlockFulfillmentProxy) error {
+	switch ff := fulfillment.Fulfillment.(type) {
+	case nil, *types.NilFulfillment:
+		return nil
+	case *types.SingleSignatureFulfillment:
+		return e.message(1, func(e *encoder) error {
+			encodePublicKeySignaturePair(e, ff.PublicKey, ff.Signature)
+			return nil
+		})
+	case *types.MultiSignatureFulfillment:
+		return e.message(2, func(e *encoder) error {
+			for _, pair := range ff.Pairs {
+				e.message(1, func(e *encoder) error {
+					encodePublicKeySignaturePair(e, pair.PublicKey, pair.Signature)
+					return nil
+				})
+			}
+			return nil
+		})
+	case *types.AtomicSwapFulfillment:
+		return e.message(3, func(e *encoder) error {
+			encodePublicKeySignaturePair(e, ff.PublicKey, ff.Signature)
+			if ff.Secret != (types.AtomicSwapSecret{}) {
+				e.bytes(3, ff.Secret[:])
+			}
+			return nil
+		})
+	default:
+		b, err := rivbin.Marshal(fulfillment)
+		if err != nil {
+			return fmt.Errorf("failed to rivbin-encode fulfillment: %v", err)
+		}
+		return e.message(15, func(e *encoder) error {
+			e.uvarint(1, uint64(fulfillment.FulfillmentType()))
+			e.bytes(2, b)
+			return nil
+		})
+	}
+}
+
+func decodeFulfillment(b []byte, fulfillment *types.UnlockFulfillmentProxy) error {
+	*fulfillment = types.NewFulfillment(nil)
+	return decodeFields(b, func(f field) (err error) {
+		var data []byte
+		if f.number == 1 || f.number == 2 || f.number == 3 || f.number == 15 {
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+		}
+		switch f.number {
+		case 1:
+			ff := new(types.SingleSignatureFulfillment)
+			err = decodePublicKeySignaturePair(data, &ff.PublicKey, &ff.Signature, nil)
+			*fulfillment = types.NewFulfillment(ff)
+		case 2:
+			ff := new(types.MultiSignatureFulfillment)
+			err = decodeFields(data, func(f field) error {
+				if f.number != 1 {
+					return nil
+				}
+				data, err := f.bytes()
+				if err != nil {
+					return err
+				}
+				var pair types.PublicKeySignaturePair
+				err = decodePublicKeySignaturePair(data, &pair.PublicKey, &pair.Signature, nil)
+				ff.Pairs = append(ff.Pairs, pair)
+				return err
+			})
+			*fulfillment = types.NewFulfillment(ff)
+		case 3:
+			ff := new(types.AtomicSwapFulfillment)
+			err = decodePublicKeySignaturePair(data, &ff.PublicKey, &ff.Signature, func(f field) error {
+				if f.number == 3 {
+					return decodeHash(f, (*crypto.Hash)(&ff.Secret))
+				}
+				return nil
+			})
+			*fulfillment = types.NewFulfillment(ff)
+		case 15:
+			err = decodeFields(data, func(f field) error {
+				if f.number != 2 {
+					return nil
+				}
+				b, err := f.bytes()
+				if err != nil {
+					return err
+				}
+				return rivbin.Unmarshal(b, fulfillment)
+			})
+		}
+		return
+	})
+}
+
+func encodePublicKeySignaturePair(e *encoder, pk types.PublicKey, signature types.ByteSlice) {
+	e.message(1, func(e *encoder) error {
+		e.uvarint(1, uint64(pk.Algorithm))
+		e.bytes(2, pk.Key)
+		return nil
+	})
+	e.bytes(2, signature)
+}
+
+// decodePublicKeySignaturePair decodes a message starting with a public key (1) and signature (2),
+// passing all other fields to the optional callback.
+func decodePublicKeySignaturePair(b []byte, pk *types.PublicKey, signature *types.ByteSlice, callback func(f field) error) error {
+	return decodeFields(b, func(f field) (err error) {
+		switch f.number {
+		case 1:
+			var data []byte
+			if data, err = f.bytes(); err != nil {
+				return err
+			}
+			err = decodeFields(data, func(f field) (err error) {
+				switch f.number {
+				case 1:
+					var x uint64
+					x, err = f.uvarint()
+					pk.Algorithm = types.SignatureAlgoType(x)
+				case 2:
+					pk.Key, err = f.bytes()
+				}
+				return
+			})
+		case 2:
+			*signature, err = f.bytes()
+		default:
+			if callback != nil {
+				err = callback(f)
+			}
+		}
+		return
+	})
+}
+
+func encodeUnlockHash(e *encoder, uh types.UnlockHash) {
+	e.uvarint(1, uint64(uh.Type))
+	e.bytes(2, uh.Hash[:])
+}
+
+// decodeUnlockHashField decodes an UnlockHash message field.
+func decodeUnlockHashField(f field, uh *types.UnlockHash) error {
+	data, err := f.bytes()
+	if err != nil {
+		return err
+	}
+	*uh = types.UnlockHash{}
+	return decodeFields(data, func(f field) (err error) {
+		switch f.number {
+		case 1:
+			var x uint64
+			x, err = f.uvarint()
+			uh.Type = types.UnlockType(x)
+		case 2:
+			err = decodeHash(f, &uh.Hash)
+		}
+		return
+	})
+}
+
+// decodeHash decodes a 32 byte hash (or hash-like) field.
+func decodeHash(f field, h *crypto.Hash) error {
+	b, err := f.bytes()
+	if err != nil {
+		return err
+	}
+	if len(b) != crypto.HashSize {
+		return fmt.Errorf("%v: field %d has %d bytes, while %d bytes were expected", ErrInvalidWireData, f.number, len(b), crypto.HashSize)
+	}
+	copy(h[:], b)
+	return nil
+}
+
+// decodeCurrency decodes an unsigned big-endian integer field as a currency.
+func decodeCurrency(f field, c *types.Currency) error {
+	b, err := f.bytes()
+	if err != nil {
+		return err
+	}
+	*c = types.NewCurrency(new(big.Int).SetBytes(b))
+	return nil
+}
@@ -0,0 +1,161 @@
+package protobuf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func testTransaction() types.Transaction {
+	uh := types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{1, 2, 3})
+	pk := types.Ed25519PublicKey(crypto.PublicKey{7})
+	ssf := types.NewSingleSignatureFulfillment(pk)
+	ssf.Signature = types.ByteSlice{8, 9}
+	return types.Transaction{
+		Version: types.TransactionVersionOne,
+		CoinInputs: []types.CoinInput{
+			{ParentID: types.CoinOutputID{4, 5, 6}, Fulfillment: types.NewFulfillment(ssf)},
+			{ParentID: types.CoinOutputID{5}, Fulfillment: types.NewFulfillment(types.NewMultiSignatureFulfillment(
+				[]types.PublicKeySignaturePair{{PublicKey: pk, Signature: types.ByteSlice{1}}, {PublicKey: pk}}))},
+			{ParentID: types.CoinOutputID{6}, Fulfillment: types.NewFulfillment(types.NewAtomicSwapClaimFulfillment(pk, types.AtomicSwapSecret{3}))},
+			{ParentID: types.CoinOutputID{7}, Fulfillment: types.NewFulfillment(types.NewAtomicSwapRefundFulfillment(pk))},
+			{ParentID: types.CoinOutputID{8}, Fulfillment: types.NewFulfillment(types.NewHashedTimeLockRefundFulfillment(pk))},
+		},
+		CoinOutputs: []types.CoinOutput{
+			{Value: types.NewCurrency64(42), Condition: types.NewCondition(types.NewUnlockHashCondition(uh))},
+			{Value: types.NewCurrency64(1), Condition: types.NewCondition(types.NewTimeLockCondition(1600000000, types.NewUnlockHashCondition(uh)))},
+			{Value: types.NewCurrency64(2), Condition: types.NewCondition(types.NewTimeLockCondition(1600000000, &types.NilCondition{}))},
+			{Value: types.NewCurrency64(3), Condition: types.NewCondition(&types.AtomicSwapCondition{
+				Sender: uh, Receiver: uh, HashedSecret: types.AtomicSwapHashedSecret{1}, TimeLock: 1600000000})},
+			{Value: types.NewCurrency64(4), Condition: types.NewCondition(types.NewMultiSignatureCondition(types.UnlockHashSlice{uh, uh}, 1))},
+			{Value: types.NewCurrency64(5), Condition: types.NewCondition(types.NewBurnCondition())},
+			{Value: types.NewCurrency64(6), Condition: types.NewCondition(nil)},
+		},
+		BlockStakeInputs: []types.BlockStakeInput{
+			{ParentID: types.BlockStakeOutputID{9}, Fulfillment: types.NewFulfillment(ssf)},
+		},
+		BlockStakeOutputs: []types.BlockStakeOutput{
+			{Value: types.NewCurrency64(1), Condition: types.NewCondition(types.NewUnlockHashCondition(uh))},
+		},
+		MinerFees:     []types.Currency{types.NewCurrency64(1), types.ZeroCurrency},
+		ArbitraryData: []byte("protobuf"),
+	}
+}
+
+func TestMarshalUnmarshalTransaction(t *testing.T) {
+	txn := testTransaction()
+	b, err := MarshalTransaction(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.Transaction
+	err = UnmarshalTransaction(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != txn.ID() {
+		t.Fatalf("decoded transaction has a different ID: %v != %v", decoded.ID(), txn.ID())
+	}
+	expected, err := rivbin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := rivbin.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("decoded transaction differs:\n%x\n%x", actual, expected)
+	}
+	// re-encoding is deterministic
+	again, err := MarshalTransaction(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, again) {
+		t.Fatalf("re-encoded transaction differs:\n%x\n%x", again, b)
+	}
+}
+
+func TestMarshalUnmarshalBlock(t *testing.T) {
+	block := types.Block{
+		ParentID:  types.BlockID{1},
+		Timestamp: 1600000000,
+		POBSOutput: types.BlockStakeOutputIndexes{
+			BlockHeight:      1,
+			TransactionIndex: 2,
+			OutputIndex:      3,
+		},
+		MinerPayouts: []types.MinerPayout{
+			{Value: types.NewCurrency64(10), UnlockHash: types.NewUnlockHash(types.UnlockTypePubKey, crypto.Hash{2})},
+		},
+		Transactions: []types.Transaction{testTransaction(), {Version: types.TransactionVersionZero}},
+	}
+	b, err := MarshalBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.Block
+	err = UnmarshalBlock(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != block.ID() {
+		t.Fatalf("decoded block has a different ID: %v != %v", decoded.ID(), block.ID())
+	}
+	for idx := range block.Transactions {
+		if decoded.Transactions[idx].ID() != block.Transactions[idx].ID() {
+			t.Errorf("decoded transaction #%d has a different ID", idx)
+		}
+	}
+}
+
+func TestUnmarshalSkipsUnknownFields(t *testing.T) {
+	txn := testTransaction()
+	b, err := MarshalTransaction(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// append fields unknown to this schema, one of each wire type
+	var e encoder
+	e.uvarint(100, 42)
+	e.bytes(101, []byte("unknown"))
+	e.tag(102, wireFixed64)
+	e.buf = append(e.buf, make([]byte, 8)...)
+	e.tag(103, wireFixed32)
+	e.buf = append(e.buf, make([]byte, 4)...)
+	b = append(b, e.buf...)
+
+	var decoded types.Transaction
+	err = UnmarshalTransaction(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != txn.ID() {
+		t.Fatalf("decoded transaction has a different ID: %v != %v", decoded.ID(), txn.ID())
+	}
+}
+
+func TestUnmarshalInvalidWireData(t *testing.T) {
+	b, err := MarshalTransaction(testTransaction())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, input := range [][]byte{
+		b[:len(b)-1],
+		{0x0a, 0x05, 0x01},             // truncated length-delimited field
+		{0x08},                         // truncated varint
+		{0x0b},                         // unsupported (group) wire type
+		{0x12, 0x02, 0x0a, 0x01},       // coin input with truncated parent ID
+		{0x12, 0x03, 0x0a, 0x01, 0x01}, // coin input with a too short parent ID
+		{0x1a, 0x03, 0x12, 0x01, 0x08}, // coin output with truncated condition
+	} {
+		var txn types.Transaction
+		if err := UnmarshalTransaction(input, &txn); err == nil {
+			t.Errorf("invalid test case #%d: expected %x to be rejected", idx, input)
+		}
+	}
+}
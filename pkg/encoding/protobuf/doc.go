@@ -0,0 +1,11 @@
+// Package protobuf encodes the core Rivine types (blocks, transactions, outputs, conditions and fulfillments)
+// using the Protocol Buffers wire format, following the schema defined in rivine.proto.
+// It is meant for non-Go integrators (such as exchanges and explorers), which can generate
+// their own codec from that schema, rather than having to reimplement the custom binary encodings
+// (siabin and rivbin) used by Rivine itself. It does not replace those encodings,
+// and the protobuf encoding is never used for hashing or signing.
+//
+// The codec is written by hand, as to not depend on the protobuf runtime,
+// and only supports the wire types used by the schema.
+// Unknown fields are skipped when decoding, as is required by the protobuf specification.
+package protobuf
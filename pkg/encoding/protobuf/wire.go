@@ -0,0 +1,141 @@
+package protobuf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// protobuf wire types
+const (
+	wireVarint          = 0
+	wireFixed64         = 1
+	wireLengthDelimited = 2
+	wireFixed32         = 5
+)
+
+var (
+	// ErrInvalidWireData is returned in case the given bytes aren't valid protobuf wire data.
+	ErrInvalidWireData = errors.New("invalid protobuf wire data")
+)
+
+// encoder appends protobuf fields to a byte slice.
+// Scalar fields with a zero value are omitted, as is done by proto3.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field int, wireType int) {
+	e.buf = appendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+// uvarint encodes a (non-zero) unsigned integer field.
+func (e *encoder) uvarint(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = appendUvarint(e.buf, x)
+}
+
+// bytes encodes a (non-empty) bytes field.
+func (e *encoder) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	e.lengthDelimited(field, b)
+}
+
+// message encodes an (embedded) message field, even if the message is empty.
+func (e *encoder) message(field int, encode func(*encoder) error) error {
+	var sub encoder
+	if err := encode(&sub); err != nil {
+		return err
+	}
+	e.lengthDelimited(field, sub.buf)
+	return nil
+}
+
+func (e *encoder) lengthDelimited(field int, b []byte) {
+	e.tag(field, wireLengthDelimited)
+	e.buf = appendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// field is a single decoded protobuf field.
+type field struct {
+	number   int
+	wireType int
+	varint   uint64
+	data     []byte
+}
+
+// uvarint returns the value of a varint field.
+func (f field) uvarint() (uint64, error) {
+	if f.wireType != wireVarint {
+		return 0, fmt.Errorf("%v: field %d is not a varint", ErrInvalidWireData, f.number)
+	}
+	return f.varint, nil
+}
+
+// bytes returns the data of a length-delimited field.
+func (f field) bytes() ([]byte, error) {
+	if f.wireType != wireLengthDelimited {
+		return nil, fmt.Errorf("%v: field %d is not length-delimited", ErrInvalidWireData, f.number)
+	}
+	return f.data, nil
+}
+
+// decodeFields decodes all fields of a message, calling the given callback for each field.
+// Fields of the fixed wire types are decoded as well, such that unknown fields can be skipped.
+func decodeFields(b []byte, callback func(f field) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrInvalidWireData
+		}
+		b = b[n:]
+		f := field{number: int(key >> 3), wireType: int(key & 7)}
+		if f.number <= 0 {
+			return fmt.Errorf("%v: invalid field number %d", ErrInvalidWireData, f.number)
+		}
+		switch f.wireType {
+		case wireVarint:
+			f.varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrInvalidWireData
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return ErrInvalidWireData
+			}
+			f.data, b = b[:8], b[8:]
+		case wireLengthDelimited:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return ErrInvalidWireData
+			}
+			b = b[n:]
+			f.data, b = b[:length], b[length:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return ErrInvalidWireData
+			}
+			f.data, b = b[:4], b[4:]
+		default:
+			return fmt.Errorf("%v: unsupported wire type %d", ErrInvalidWireData, f.wireType)
+		}
+		if err := callback(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendUvarint appends the varint encoding of x to b.
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
@@ -7,7 +7,7 @@ import (
 
 func isFieldHidden(val reflect.Value, index int) bool {
 	field := val.Type().Field(index)
-	if field.Anonymous {
+	if field.Anonymous || field.Tag.Get("rivbin") == "-" {
 		return true
 	}
 	for _, r := range field.Name {
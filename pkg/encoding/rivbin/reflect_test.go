@@ -15,9 +15,10 @@ func TestIsFieldHidden(t *testing.T) {
 		Nop    struct {
 			a bool
 		}
+		Skipped int `rivbin:"-"`
 	}
 	val := reflect.ValueOf(f)
-	expected := []bool{false, true, true, true, false, false}
+	expected := []bool{false, true, true, true, false, false, true}
 	for i := 0; i < val.NumField(); i++ {
 		if result := isFieldHidden(val, i); expected[i] != result {
 			t.Error(i, "unexpected result: isFieldHidden() ==", result)
@@ -135,6 +135,9 @@ func (e *Encoder) encode(val reflect.Value) error {
 		return nil
 	case reflect.Struct:
 		for i := 0; i < val.NumField(); i++ {
+			if isFieldSkipped(val, i) {
+				continue // ignore
+			}
 			if err := e.encode(val.Field(i)); err != nil {
 				return err
 			}
@@ -336,6 +339,9 @@ func (d *Decoder) decode(val reflect.Value) {
 		return
 	case reflect.Struct:
 		for i := 0; i < val.NumField(); i++ {
+			if isFieldSkipped(val, i) {
+				continue // ignore
+			}
 			d.decode(val.Field(i))
 		}
 		return
@@ -377,3 +383,9 @@ func ReadFile(filename string, v interface{}) error {
 	}
 	return nil
 }
+
+// isFieldSkipped returns true if the struct field at the given index
+// is tagged as `siabin:"-"`, and should therefore not be encoded nor decoded.
+func isFieldSkipped(val reflect.Value, index int) bool {
+	return val.Type().Field(index).Tag.Get("siabin") == "-"
+}
@@ -204,6 +204,30 @@ func TestMarshalUnmarshal(t *testing.T) {
 	}
 }
 
+// TestMarshalSkippedField tests that struct fields tagged as `siabin:"-"`
+// are neither encoded nor decoded.
+func TestMarshalSkippedField(t *testing.T) {
+	type skipped struct {
+		A uint8
+		B []byte `siabin:"-"`
+		C uint8
+	}
+	b, err := Marshal(skipped{A: 1, B: []byte("foo"), C: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, append(EncUint64(1), EncUint64(2)...)) {
+		t.Fatalf("unexpected encoding: %v", b)
+	}
+	var s skipped
+	if err = Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.A != 1 || s.B != nil || s.C != 2 {
+		t.Errorf("unexpected decoded struct: %v", s)
+	}
+}
+
 // TestEncodeDecode tests the Encode and Decode functions, which are inverses
 // of each other.
 func TestEncodeDecode(t *testing.T) {
@@ -0,0 +1,576 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Execute parses, validates and executes the given request.
+// Fields which fail to resolve are null in the result, with their error listed in the result.
+func (s *Schema) Execute(req Request) *Result {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{err}}
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{err}}
+	}
+	v := &validator{
+		schema:    s,
+		src:       req.Query,
+		doc:       doc,
+		variables: make(map[string]*variableDefinition, len(op.variables)),
+	}
+	vars := v.validateOperation(op, req.Variables)
+	if len(v.errors) > 0 {
+		return &Result{Errors: v.errors}
+	}
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := &executor{
+		ctx:  ctx,
+		src:  req.Query,
+		doc:  doc,
+		vars: vars,
+	}
+	data := e.executeSelections(s.Query, nil, op.selections, nil)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+// operation returns the operation to execute.
+func (doc *document) operation(name string) (*operation, *Error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, &Error{Message: "an operation name is required for documents with multiple operations"}
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("unknown operation %q", name)}
+}
+
+// the arguments of the @include and @skip directives
+var directiveArgs = Args{
+	"if": &Argument{Type: &NonNull{Of: Boolean}},
+}
+
+// validator validates a query document against a schema, prior to its execution
+type validator struct {
+	schema    *Schema
+	src       string
+	doc       *document
+	variables map[string]*variableDefinition
+	vars      map[string]interface{}
+	errors    []*Error
+	tooDeep   bool
+	aliases   int
+}
+
+func (v *validator) errorf(pos int, format string, args ...interface{}) {
+	v.errors = append(v.errors, newError(v.src, pos, fmt.Sprintf(format, args...)))
+}
+
+// validateOperation validates the operation and returns its coerced variables.
+func (v *validator) validateOperation(op *operation, given map[string]interface{}) map[string]interface{} {
+	vars := make(map[string]interface{}, len(op.variables))
+	for _, def := range op.variables {
+		if _, exists := v.variables[def.name]; exists {
+			v.errorf(def.pos, "variable $%s is defined more than once", def.name)
+			continue
+		}
+		v.variables[def.name] = def
+		t, ok := inputType(def.typ)
+		if !ok {
+			v.errorf(def.pos, "variable $%s has unknown type %s", def.name, def.typ)
+			continue
+		}
+		value, present := given[def.name]
+		if !present && def.hasDefault {
+			value, present = def.defaultValue, true
+		}
+		if !present || value == nil {
+			if def.typ.nonNull {
+				v.errorf(def.pos, "variable $%s of required type %s was not provided", def.name, def.typ)
+			} else if present {
+				vars[def.name] = nil
+			}
+			continue
+		}
+		coerced, ok := coerceInput(t, value, nil)
+		if !ok {
+			v.errorf(def.pos, "variable $%s has an invalid value for type %s", def.name, def.typ)
+			continue
+		}
+		vars[def.name] = coerced
+	}
+	v.vars = vars
+	cost := v.validateSelections(v.schema.Query, op.selections, 1, nil)
+	if v.schema.MaxCost > 0 && cost > v.schema.MaxCost {
+		v.errors = append(v.errors, &Error{Message: fmt.Sprintf("query exceeds the maximum cost of %d", v.schema.MaxCost)})
+	}
+	if v.schema.MaxAliases > 0 && v.aliases > v.schema.MaxAliases {
+		v.errors = append(v.errors, &Error{Message: fmt.Sprintf("query exceeds the maximum of %d aliases", v.schema.MaxAliases)})
+	}
+	return vars
+}
+
+// validateSelections validates the selections of an object, returning their cost.
+func (v *validator) validateSelections(obj *Object, selections []selection, depth int, fragments []string) (cost int) {
+	if v.schema.MaxDepth > 0 && depth > v.schema.MaxDepth {
+		if !v.tooDeep {
+			v.tooDeep = true
+			v.errors = append(v.errors, &Error{Message: fmt.Sprintf("query exceeds the maximum depth of %d", v.schema.MaxDepth)})
+		}
+		return 0
+	}
+	// the cost is capped right above the maximum cost, preventing it from overflowing
+	addCost := func(c int) {
+		cost += c
+		if v.schema.MaxCost > 0 && cost > v.schema.MaxCost {
+			cost = v.schema.MaxCost + 1
+		}
+	}
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			v.validateDirectives(sel.directives)
+			if sel.alias != "" {
+				v.aliases++
+			}
+			addCost(1)
+			if sel.name == "__typename" {
+				if len(sel.selections) > 0 {
+					v.errorf(sel.pos, "field \"__typename\" must not have a selection since type \"String\" has no subfields")
+				}
+				continue
+			}
+			def, ok := obj.Fields[sel.name]
+			if !ok {
+				v.errorf(sel.pos, "cannot query field %q on type %q", sel.name, obj.Name)
+				continue
+			}
+			v.validateArguments(def.Args, sel.arguments, sel.pos, fmt.Sprintf("field %q", sel.name))
+			if fieldObj, ok := namedType(def.Type).(*Object); ok {
+				if len(sel.selections) == 0 {
+					v.errorf(sel.pos, "field %q of type %q must have a selection of subfields", sel.name, def.Type)
+					continue
+				}
+				subCost := v.validateSelections(fieldObj, sel.selections, depth+1, fragments)
+				if def.Multiplier != nil {
+					if n := def.Multiplier(fieldArguments(def, sel, v.vars)); n >= 0 {
+						subCost *= n
+					}
+				}
+				addCost(subCost)
+			} else if len(sel.selections) > 0 {
+				v.errorf(sel.pos, "field %q must not have a selection since type %q has no subfields", sel.name, def.Type)
+			}
+
+		case *fragmentSpread:
+			v.validateDirectives(sel.directives)
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.errorf(sel.pos, "unknown fragment %q", sel.name)
+				continue
+			}
+			if containsString(fragments, sel.name) {
+				v.errorf(sel.pos, "fragment %q cannot spread itself", sel.name)
+				continue
+			}
+			if frag.typeCondition != obj.Name {
+				v.errorf(sel.pos, "fragment %q cannot be spread here as objects of type %q can never be of type %q", sel.name, obj.Name, frag.typeCondition)
+				continue
+			}
+			addCost(v.validateSelections(obj, frag.selections, depth, append(fragments, sel.name)))
+
+		case *inlineFragment:
+			v.validateDirectives(sel.directives)
+			if sel.typeCondition != "" && sel.typeCondition != obj.Name {
+				v.errorf(sel.pos, "fragment cannot be spread here as objects of type %q can never be of type %q", obj.Name, sel.typeCondition)
+				continue
+			}
+			addCost(v.validateSelections(obj, sel.selections, depth, fragments))
+		}
+	}
+	return cost
+}
+
+func (v *validator) validateDirectives(directives []*directive) {
+	for _, d := range directives {
+		if d.name != "include" && d.name != "skip" {
+			v.errorf(d.pos, "unknown directive @%s", d.name)
+			continue
+		}
+		v.validateArguments(directiveArgs, d.arguments, d.pos, "directive @"+d.name)
+	}
+}
+
+func (v *validator) validateArguments(defs Args, args []*argument, pos int, owner string) {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		def, ok := defs[arg.name]
+		if !ok {
+			v.errorf(arg.pos, "unknown argument %q on %s", arg.name, owner)
+			continue
+		}
+		if given[arg.name] {
+			v.errorf(arg.pos, "argument %q is given more than once", arg.name)
+			continue
+		}
+		given[arg.name] = true
+		v.validateValue(def.Type, arg.value, arg.pos, arg.name)
+	}
+	for name, def := range defs {
+		if _, required := def.Type.(*NonNull); required && !given[name] {
+			v.errorf(pos, "argument %q of type %q is required on %s", name, def.Type, owner)
+		}
+	}
+}
+
+func (v *validator) validateValue(t Type, value interface{}, pos int, name string) {
+	if ref, ok := value.(variableRef); ok {
+		def, ok := v.variables[string(ref)]
+		if !ok {
+			v.errorf(pos, "variable $%s is not defined", ref)
+		} else if !compatible(def.typ, t) {
+			v.errorf(pos, "variable $%s of type %s cannot be used for argument %q of type %s", ref, def.typ, name, t)
+		}
+		return
+	}
+	if nn, ok := t.(*NonNull); ok {
+		if value == nil {
+			v.errorf(pos, "argument %q of type %s cannot be null", name, t)
+			return
+		}
+		t = nn.Of
+	}
+	switch t := t.(type) {
+	case *List:
+		if list, ok := value.([]interface{}); ok {
+			for _, elem := range list {
+				v.validateValue(t.Of, elem, pos, name)
+			}
+		} else {
+			v.validateValue(t.Of, value, pos, name)
+		}
+	case *Scalar:
+		if value == nil {
+			return
+		}
+		if _, ok := t.coerce(value); !ok {
+			v.errorf(pos, "argument %q has an invalid value for type %s", name, t)
+		}
+	default:
+		v.errorf(pos, "argument %q has unsupported input type %s", name, t)
+	}
+}
+
+// inputType returns the input type referenced by a variable definition,
+// only the built-in scalars being supported as input types.
+func inputType(ref *typeRef) (Type, bool) {
+	var t Type
+	if ref.elem != nil {
+		elem, ok := inputType(ref.elem)
+		if !ok {
+			return nil, false
+		}
+		t = &List{Of: elem}
+	} else {
+		switch ref.name {
+		case String.Name:
+			t = String
+		case Int.Name:
+			t = Int
+		case Boolean.Name:
+			t = Boolean
+		default:
+			return nil, false
+		}
+	}
+	if ref.nonNull {
+		t = &NonNull{Of: t}
+	}
+	return t, true
+}
+
+// compatible returns true if a variable of the referenced type can be used for the given type.
+func compatible(ref *typeRef, t Type) bool {
+	if nn, ok := t.(*NonNull); ok {
+		if !ref.nonNull {
+			return false
+		}
+		t = nn.Of
+	}
+	switch t := t.(type) {
+	case *List:
+		return ref.elem != nil && compatible(ref.elem, t.Of)
+	case *Scalar:
+		return ref.elem == nil && ref.name == t.Name
+	}
+	return false
+}
+
+// coerceInput coerces an input value for the given type,
+// resolving the variables it references using the given (coerced) variables.
+func coerceInput(t Type, value interface{}, vars map[string]interface{}) (interface{}, bool) {
+	if ref, ok := value.(variableRef); ok {
+		value = vars[string(ref)]
+		_, isNonNull := t.(*NonNull)
+		return value, value != nil || !isNonNull
+	}
+	if nn, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, false
+		}
+		t = nn.Of
+	}
+	if value == nil {
+		return nil, true
+	}
+	switch t := t.(type) {
+	case *List:
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value}
+		}
+		coerced := make([]interface{}, 0, len(list))
+		for _, elem := range list {
+			c, ok := coerceInput(t.Of, elem, vars)
+			if !ok {
+				return nil, false
+			}
+			coerced = append(coerced, c)
+		}
+		return coerced, true
+	case *Scalar:
+		return t.coerce(value)
+	}
+	return nil, false
+}
+
+// namedType returns the named type wrapped by list and non-null types.
+func namedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *List:
+			t = wrapped.Of
+		case *NonNull:
+			t = wrapped.Of
+		default:
+			return t
+		}
+	}
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// executor executes a validated operation
+type executor struct {
+	ctx    context.Context
+	src    string
+	doc    *document
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+func (e *executor) fieldError(pos int, path []interface{}, message string) {
+	err := newError(e.src, pos, message)
+	err.Path = path
+	e.errors = append(e.errors, err)
+}
+
+// executeSelections resolves the selected fields of an object.
+func (e *executor) executeSelections(obj *Object, source interface{}, selections []selection, path []interface{}) *object {
+	var keys []string
+	fields := make(map[string][]*field)
+	e.collectFields(selections, &keys, fields, make(map[string]bool))
+
+	result := &object{}
+	for _, key := range keys {
+		fs := fields[key]
+		f := fs[0]
+		fieldPath := append(append([]interface{}(nil), path...), key)
+		if f.name == "__typename" {
+			result.set(key, obj.Name)
+			continue
+		}
+		def := obj.Fields[f.name]
+		args := fieldArguments(def, f, e.vars)
+		if def.Resolve == nil {
+			e.fieldError(f.pos, fieldPath, fmt.Sprintf("field %q of type %q has no resolver", f.name, obj.Name))
+			result.set(key, nil)
+			continue
+		}
+		value, err := def.Resolve(ResolveParams{Source: source, Args: args, Context: e.ctx})
+		if err != nil {
+			e.fieldError(f.pos, fieldPath, err.Error())
+			result.set(key, nil)
+			continue
+		}
+		result.set(key, e.completeValue(def.Type, fs, value, fieldPath))
+	}
+	return result
+}
+
+// fieldArguments returns the coerced arguments given for a field.
+func fieldArguments(def *Field, f *field, vars map[string]interface{}) map[string]interface{} {
+	args := make(map[string]interface{}, len(f.arguments))
+	for _, arg := range f.arguments {
+		argDef, ok := def.Args[arg.name]
+		if !ok {
+			continue
+		}
+		if ref, ok := arg.value.(variableRef); ok {
+			if _, defined := vars[string(ref)]; !defined {
+				// an argument referencing an omitted variable is not given
+				continue
+			}
+		}
+		value, _ := coerceInput(argDef.Type, arg.value, vars)
+		args[arg.name] = value
+	}
+	return args
+}
+
+// collectFields collects the fields to resolve, grouped by response key,
+// flattening all fragments and skipping the fields excluded by directives.
+func (e *executor) collectFields(selections []selection, keys *[]string, fields map[string][]*field, visited map[string]bool) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if !e.included(sel.directives) {
+				continue
+			}
+			key := sel.responseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], sel)
+		case *fragmentSpread:
+			if visited[sel.name] || !e.included(sel.directives) {
+				continue
+			}
+			visited[sel.name] = true
+			e.collectFields(e.doc.fragments[sel.name].selections, keys, fields, visited)
+		case *inlineFragment:
+			if !e.included(sel.directives) {
+				continue
+			}
+			e.collectFields(sel.selections, keys, fields, visited)
+		}
+	}
+}
+
+// included returns false if the selection is excluded using the @include or @skip directive.
+func (e *executor) included(directives []*directive) bool {
+	for _, d := range directives {
+		value, _ := coerceInput(directiveArgs["if"].Type, d.arguments[0].value, e.vars)
+		cond, _ := value.(bool)
+		if (d.name == "skip" && cond) || (d.name == "include" && !cond) {
+			return false
+		}
+	}
+	return true
+}
+
+// completeValue completes a resolved value according to its type.
+func (e *executor) completeValue(t Type, fs []*field, value interface{}, path []interface{}) interface{} {
+	if nn, ok := t.(*NonNull); ok {
+		completed := e.completeValue(nn.Of, fs, value, path)
+		if completed == nil {
+			e.fieldError(fs[0].pos, path, fmt.Sprintf("non-null field %q resolved to null", fs[0].name))
+		}
+		return completed
+	}
+	if isNil(value) {
+		return nil
+	}
+	switch t := t.(type) {
+	case *Scalar:
+		return value
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fieldError(fs[0].pos, path, fmt.Sprintf("field %q resolved to a non-list value", fs[0].name))
+			return nil
+		}
+		items := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			itemPath := append(append([]interface{}(nil), path...), i)
+			items = append(items, e.completeValue(t.Of, fs, rv.Index(i).Interface(), itemPath))
+		}
+		return items
+	case *Object:
+		var selections []selection
+		for _, f := range fs {
+			selections = append(selections, f.selections...)
+		}
+		return e.executeSelections(t, value, selections, path)
+	}
+	return nil
+}
+
+// isNil returns true if the value is nil or a nil pointer, map or interface.
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// object is a resolved object, encoded as a JSON object with its fields in the order they were selected
+type object struct {
+	keys   []string
+	values []interface{}
+}
+
+func (o *object) set(key string, value interface{}) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+		b, err = json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,291 @@
+// Package graphql implements a minimal GraphQL query engine,
+// executing read-only queries against a schema of object types with Go resolvers.
+//
+// Only the subset of GraphQL needed to expose (read-only) data is supported:
+// queries with variables, aliases, arguments, fragments and the @include and @skip directives.
+// Mutations, subscriptions, interfaces, unions and introspection (apart from __typename)
+// are not supported, the schema can however be printed in the schema definition language.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type (
+	// Type is the type of a field or argument,
+	// either a *Scalar, an *Object, a *List or a *NonNull type.
+	Type interface {
+		// String returns the type as referenced in the schema definition language.
+		String() string
+	}
+
+	// Scalar is a leaf type, whose values are returned as JSON-encoded by the resolver.
+	Scalar struct {
+		Name        string
+		Description string
+
+		// coerce coerces an input value into the Go value passed to resolvers
+		coerce func(interface{}) (interface{}, bool)
+	}
+
+	// Object is a type with a set of named fields.
+	Object struct {
+		Name        string
+		Description string
+		Fields      Fields
+	}
+
+	// List is a list of values of the given type.
+	List struct {
+		Of Type
+	}
+
+	// NonNull is a non-null value of the given type.
+	NonNull struct {
+		Of Type
+	}
+
+	// Fields maps the names of fields to their definition.
+	Fields map[string]*Field
+
+	// Field defines a field of an object type.
+	Field struct {
+		Type        Type
+		Description string
+		Args        Args
+		Resolve     ResolveFunc
+		// Multiplier optionally returns the maximum amount of values a list field resolves to,
+		// given its coerced arguments, multiplying the cost of its selected subfields.
+		Multiplier func(args map[string]interface{}) int
+	}
+
+	// Args maps the names of arguments to their definition.
+	Args map[string]*Argument
+
+	// Argument defines an argument of a field.
+	Argument struct {
+		Type        Type
+		Description string
+	}
+
+	// ResolveFunc resolves the value of a field. Object values are used as the source
+	// of the fields of the object type, list values are expected to be slices.
+	// A nil value resolves to null.
+	ResolveFunc func(p ResolveParams) (interface{}, error)
+
+	// ResolveParams are the parameters passed to a ResolveFunc.
+	ResolveParams struct {
+		// Source is the value the parent field resolved to, nil for the fields of the query type
+		Source interface{}
+		// Args contains the coerced arguments given for the field,
+		// with Int arguments as int64, String arguments as string and Boolean arguments as bool,
+		// arguments which are not given are not defined
+		Args map[string]interface{}
+		// Context is the context of the request, never nil
+		Context context.Context
+	}
+)
+
+// The built-in scalar types.
+var (
+	String = &Scalar{
+		Name:        "String",
+		Description: "The `String` scalar type represents textual data as UTF-8 character sequences.",
+		coerce: func(v interface{}) (interface{}, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+	}
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "The `Int` scalar type represents signed 64-bit numeric non-fractional values.",
+		coerce: func(v interface{}) (interface{}, bool) {
+			switch v := v.(type) {
+			case int64:
+				return v, true
+			case json.Number:
+				n, err := v.Int64()
+				return n, err == nil
+			case float64:
+				// integral values given as JSON variables decoded without json.Number
+				if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+					return int64(v), true
+				}
+			}
+			return nil, false
+		},
+	}
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "The `Boolean` scalar type represents `true` or `false`.",
+		coerce: func(v interface{}) (interface{}, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+	}
+)
+
+// String implements Type.String
+func (s *Scalar) String() string { return s.Name }
+
+// String implements Type.String
+func (o *Object) String() string { return o.Name }
+
+// String implements Type.String
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+// String implements Type.String
+func (nn *NonNull) String() string { return nn.Of.String() + "!" }
+
+// Error is an error encountered while parsing, validating or executing a query.
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	// Path of the field which failed to resolve, if any
+	Path []interface{} `json:"path,omitempty"`
+}
+
+// Location is the location in the query document an Error applies to.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// newError creates an error located at the given byte offset of the query document.
+func newError(src string, pos int, message string) *Error {
+	if pos > len(src) {
+		pos = len(src)
+	}
+	line := strings.Count(src[:pos], "\n") + 1
+	column := pos - strings.LastIndexByte(src[:pos], '\n')
+	return &Error{
+		Message:   message,
+		Locations: []Location{{Line: line, Column: column}},
+	}
+}
+
+// Error implements error.Error
+func (err *Error) Error() string {
+	if len(err.Locations) == 0 {
+		return err.Message
+	}
+	return fmt.Sprintf("%s (line %d, column %d)", err.Message, err.Locations[0].Line, err.Locations[0].Column)
+}
+
+type (
+	// Request is a GraphQL request, as sent to a GraphQL HTTP endpoint.
+	Request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName,omitempty"`
+		Variables     map[string]interface{} `json:"variables,omitempty"`
+		// Context is passed to the resolvers, context.Background() is used if nil
+		Context context.Context `json:"-"`
+	}
+
+	// Result is the result of executing a GraphQL request. Data is not defined
+	// in case the request could not be executed, as it failed to parse or validate.
+	Result struct {
+		Data   json.Marshaler `json:"data,omitempty"`
+		Errors []*Error       `json:"errors,omitempty"`
+	}
+)
+
+// Schema is a GraphQL schema, defining the types which can be queried.
+type Schema struct {
+	// Query is the root type of all queries
+	Query *Object
+	// MaxDepth is the maximum depth of the selection sets of a query, 0 meaning no limit
+	MaxDepth int
+	// MaxCost is the maximum cost of a query, 0 meaning no limit.
+	// Each selected field costs 1, with the cost of its subfields
+	// multiplied by the Multiplier of the field, if defined.
+	MaxCost int
+	// MaxAliases is the maximum amount of aliased fields in a query, 0 meaning no limit
+	MaxAliases int
+}
+
+// String returns the schema in the schema definition language,
+// listing the query type first and all other object types sorted by name.
+func (s *Schema) String() string {
+	objects := map[string]*Object{}
+	var collect func(Type)
+	collect = func(t Type) {
+		switch t := t.(type) {
+		case *List:
+			collect(t.Of)
+		case *NonNull:
+			collect(t.Of)
+		case *Object:
+			if _, ok := objects[t.Name]; ok {
+				return
+			}
+			objects[t.Name] = t
+			for _, f := range t.Fields {
+				collect(f.Type)
+			}
+		}
+	}
+	collect(s.Query)
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		if name != s.Query.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{s.Query.Name}, names...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema {\n  query: %s\n}\n", s.Query.Name)
+	for _, name := range names {
+		obj := objects[name]
+		b.WriteString("\n")
+		writeDescription(&b, "", obj.Description)
+		fmt.Fprintf(&b, "type %s {\n", obj.Name)
+		for _, fieldName := range sortedKeys(obj.Fields) {
+			f := obj.Fields[fieldName]
+			writeDescription(&b, "  ", f.Description)
+			b.WriteString("  " + fieldName)
+			if len(f.Args) > 0 {
+				var args []string
+				for _, argName := range sortedKeys(f.Args) {
+					args = append(args, argName+": "+f.Args[argName].Type.String())
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + f.Type.String() + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	quoted, _ := json.Marshal(description)
+	b.WriteString(indent + string(quoted) + "\n")
+}
+
+// sortedKeys returns the sorted keys of Fields or Args.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case Fields:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case Args:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testNode struct {
+	ID       int64
+	Name     string
+	Children []*testNode
+}
+
+func newTestSchema() *Schema {
+	nodes := map[int64]*testNode{
+		1: {ID: 1, Name: "root"},
+		2: {ID: 2, Name: "left"},
+		3: {ID: 3, Name: "right"},
+	}
+	nodes[1].Children = []*testNode{nodes[2], nodes[3]}
+
+	node := &Object{Name: "Node"}
+	node.Fields = Fields{
+		"id": &Field{
+			Type: &NonNull{Of: Int},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return p.Source.(*testNode).ID, nil
+			},
+		},
+		"name": &Field{
+			Type: String,
+			Args: Args{"upper": &Argument{Type: Boolean}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				name := p.Source.(*testNode).Name
+				if upper, _ := p.Args["upper"].(bool); upper {
+					name = strings.ToUpper(name)
+				}
+				return name, nil
+			},
+		},
+		"children": &Field{
+			Type: &List{Of: node},
+			Args: Args{"limit": &Argument{Type: Int}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				children := p.Source.(*testNode).Children
+				if limit, ok := p.Args["limit"].(int64); ok && int(limit) < len(children) {
+					children = children[:limit]
+				}
+				return children, nil
+			},
+			Multiplier: func(args map[string]interface{}) int {
+				if limit, ok := args["limit"].(int64); ok {
+					return int(limit)
+				}
+				return 2
+			},
+		},
+		"broken": &Field{
+			Type: String,
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return nil, errors.New("broken field")
+			},
+		},
+	}
+	return &Schema{
+		Query: &Object{
+			Name: "Query",
+			Fields: Fields{
+				"node": &Field{
+					Type: node,
+					Args: Args{"id": &Argument{Type: &NonNull{Of: Int}}},
+					Resolve: func(p ResolveParams) (interface{}, error) {
+						if n, ok := nodes[p.Args["id"].(int64)]; ok {
+							return n, nil
+						}
+						return nil, nil
+					},
+				},
+			},
+		},
+		MaxDepth:   3,
+		MaxCost:    10,
+		MaxAliases: 3,
+	}
+}
+
+func TestExecute(t *testing.T) {
+	schema := newTestSchema()
+	testCases := []struct {
+		Request        Request
+		ExpectedData   string
+		ExpectedErrors []string
+	}{
+		{
+			Request:      Request{Query: `{ node(id: 1) { id name } }`},
+			ExpectedData: `{"node":{"id":1,"name":"root"}}`,
+		},
+		{
+			Request: Request{Query: `
+				query Named($id: Int!, $upper: Boolean = true) {
+					first: node(id: $id) { __typename ...names children { id } }
+					missing: node(id: 42) { id }
+				}
+				fragment names on Node { upper: name(upper: $upper), name }`,
+				Variables: map[string]interface{}{"id": json.Number("1")},
+			},
+			ExpectedData: `{"first":{"__typename":"Node","upper":"ROOT","name":"root","children":[{"id":2},{"id":3}]},"missing":null}`,
+		},
+		{
+			Request:      Request{Query: `query($skip: Boolean!) { node(id: 2) { id @skip(if: $skip) ... @include(if: true) { name } } }`, Variables: map[string]interface{}{"skip": true}},
+			ExpectedData: `{"node":{"name":"left"}}`,
+		},
+		{
+			Request:        Request{Query: `{ node(id: 1) { id broken } }`},
+			ExpectedData:   `{"node":{"id":1,"broken":null}}`,
+			ExpectedErrors: []string{"broken field"},
+		},
+		{
+			Request:        Request{Query: `{ node(id: 1) { id `},
+			ExpectedErrors: []string{"syntax error: unexpected end of document"},
+		},
+		{
+			Request:        Request{Query: `{ node { unknown } }`},
+			ExpectedErrors: []string{`argument "id" of type "Int!" is required on field "node"`, `cannot query field "unknown" on type "Node"`},
+		},
+		{
+			Request:        Request{Query: `query($id: String) { node(id: $id) { id } }`},
+			ExpectedErrors: []string{`variable $id of type String cannot be used for argument "id" of type Int!`},
+		},
+		{
+			Request:        Request{Query: `{ node(id: 1) { children { children { id } } } }`},
+			ExpectedErrors: []string{"query exceeds the maximum depth of 3"},
+		},
+		{
+			Request:      Request{Query: `query($n: Int) { node(id: 1) { id name children(limit: $n) { id name upper: name(upper: true) __typename } } }`, Variables: map[string]interface{}{"n": json.Number("1")}},
+			ExpectedData: `{"node":{"id":1,"name":"root","children":[{"id":2,"name":"left","upper":"LEFT","__typename":"Node"}]}}`,
+		},
+		{
+			Request:        Request{Query: `query($n: Int) { node(id: 1) { id name children(limit: $n) { id name upper: name(upper: true) __typename } } }`},
+			ExpectedErrors: []string{"query exceeds the maximum cost of 10"},
+		},
+		{
+			Request:        Request{Query: `{ a: node(id: 1) { id } b: node(id: 2) { id } c: node(id: 3) { id } d: node(id: 1) { id } }`},
+			ExpectedErrors: []string{"query exceeds the maximum of 3 aliases"},
+		},
+		{
+			Request:        Request{Query: `{ node(id: 1) { ...a } } fragment a on Node { ...a }`},
+			ExpectedErrors: []string{`fragment "a" cannot spread itself`},
+		},
+		{
+			Request:        Request{Query: `mutation { node(id: 1) { id } }`},
+			ExpectedErrors: []string{"syntax error: mutation operations are not supported"},
+		},
+	}
+	for idx, testCase := range testCases {
+		result := schema.Execute(testCase.Request)
+		if testCase.ExpectedData == "" {
+			if result.Data != nil {
+				t.Errorf("#%d: expected no data", idx)
+			}
+		} else {
+			data, err := json.Marshal(result.Data)
+			if err != nil {
+				t.Errorf("#%d: failed to encode data: %v", idx, err)
+			} else if string(data) != testCase.ExpectedData {
+				t.Errorf("#%d: unexpected data: %s != %s", idx, data, testCase.ExpectedData)
+			}
+		}
+		if len(result.Errors) != len(testCase.ExpectedErrors) {
+			t.Errorf("#%d: unexpected errors: %v", idx, result.Errors)
+			continue
+		}
+		for i, err := range result.Errors {
+			if err.Message != testCase.ExpectedErrors[i] {
+				t.Errorf("#%d: unexpected error: %q != %q", idx, err.Message, testCase.ExpectedErrors[i])
+			}
+		}
+	}
+}
+
+func TestSchemaString(t *testing.T) {
+	const expected = `schema {
+  query: Query
+}
+
+type Query {
+  node(id: Int!): Node
+}
+
+type Node {
+  broken: String
+  children(limit: Int): [Node]
+  id: Int!
+  name(upper: Boolean): String
+}
+`
+	if str := newTestSchema().String(); str != expected {
+		t.Errorf("unexpected schema:\n%s", str)
+	}
+}
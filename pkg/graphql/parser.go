@@ -0,0 +1,555 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// the AST of a query document, only containing the definitions supported by this package
+
+type (
+	document struct {
+		operations []*operation
+		fragments  map[string]*fragment
+	}
+
+	operation struct {
+		name       string
+		variables  []*variableDefinition
+		selections []selection
+		pos        int
+	}
+
+	variableDefinition struct {
+		name         string
+		typ          *typeRef
+		defaultValue interface{}
+		hasDefault   bool
+		pos          int
+	}
+
+	// typeRef references a (named or list) input type,
+	// elem being defined for list types only
+	typeRef struct {
+		name    string
+		elem    *typeRef
+		nonNull bool
+	}
+
+	// selection is either a *field, a *fragmentSpread or an *inlineFragment
+	selection interface{}
+
+	field struct {
+		alias      string
+		name       string
+		arguments  []*argument
+		directives []*directive
+		selections []selection
+		pos        int
+	}
+
+	argument struct {
+		name  string
+		value interface{}
+		pos   int
+	}
+
+	directive struct {
+		name      string
+		arguments []*argument
+		pos       int
+	}
+
+	fragmentSpread struct {
+		name       string
+		directives []*directive
+		pos        int
+	}
+
+	inlineFragment struct {
+		typeCondition string
+		directives    []*directive
+		selections    []selection
+		pos           int
+	}
+
+	fragment struct {
+		name          string
+		typeCondition string
+		selections    []selection
+		pos           int
+	}
+
+	// variableRef is a reference to a variable used as value
+	variableRef string
+	// enumValue is an enum value, which is coerced as a string
+	enumValue string
+)
+
+// responseKey returns the key of the field in the response.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+func (t *typeRef) String() string {
+	var s string
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	} else {
+		s = t.name
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// parser is a recursive descent parser of query documents,
+// looking ahead a single token
+type parser struct {
+	src   string
+	pos   int
+	token token
+}
+
+// parse parses the given query document.
+func parse(src string) (*document, *Error) {
+	p := &parser{src: src}
+	var doc *document
+	err := p.try(func() {
+		p.advance()
+		doc = p.parseDocument()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseError is used to abort parsing at the first syntax error
+type parseError struct {
+	err *Error
+}
+
+func (p *parser) try(f func()) (err *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pe, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			err = pe.err
+		}
+	}()
+	f()
+	return nil
+}
+
+func (p *parser) fail(pos int, format string, args ...interface{}) {
+	panic(parseError{err: newError(p.src, pos, "syntax error: "+fmt.Sprintf(format, args...))})
+}
+
+func (p *parser) parseDocument() *document {
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunctuator, "{"):
+			doc.operations = append(doc.operations, &operation{pos: p.token.pos, selections: p.parseSelectionSet()})
+		case p.peek(tokenName, "query"):
+			doc.operations = append(doc.operations, p.parseOperation())
+		case p.peek(tokenName, "fragment"):
+			frag := p.parseFragment()
+			if _, exists := doc.fragments[frag.name]; exists {
+				p.fail(frag.pos, "fragment %q is defined more than once", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		case p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			p.fail(p.token.pos, "%s operations are not supported", p.token.value)
+		default:
+			p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		p.fail(p.token.pos, "no operation defined")
+	}
+	return doc
+}
+
+func (p *parser) parseOperation() *operation {
+	op := &operation{pos: p.token.pos}
+	p.expect(tokenName, "query")
+	if p.token.kind == tokenName {
+		op.name = p.parseName()
+	}
+	if p.skip(tokenPunctuator, "(") {
+		for !p.skip(tokenPunctuator, ")") {
+			op.variables = append(op.variables, p.parseVariableDefinition())
+		}
+	}
+	if p.peek(tokenPunctuator, "@") {
+		p.fail(p.token.pos, "directives are not supported on operations")
+	}
+	op.selections = p.parseSelectionSet()
+	return op
+}
+
+func (p *parser) parseVariableDefinition() *variableDefinition {
+	def := &variableDefinition{pos: p.token.pos}
+	p.expect(tokenPunctuator, "$")
+	def.name = p.parseName()
+	p.expect(tokenPunctuator, ":")
+	def.typ = p.parseTypeRef()
+	if p.skip(tokenPunctuator, "=") {
+		def.defaultValue = p.parseValue(true)
+		def.hasDefault = true
+	}
+	return def
+}
+
+func (p *parser) parseTypeRef() *typeRef {
+	var t *typeRef
+	if p.skip(tokenPunctuator, "[") {
+		t = &typeRef{elem: p.parseTypeRef()}
+		p.expect(tokenPunctuator, "]")
+	} else {
+		t = &typeRef{name: p.parseName()}
+	}
+	t.nonNull = p.skip(tokenPunctuator, "!")
+	return t
+}
+
+func (p *parser) parseFragment() *fragment {
+	frag := &fragment{pos: p.token.pos}
+	p.expect(tokenName, "fragment")
+	frag.name = p.parseName()
+	if frag.name == "on" {
+		p.fail(frag.pos, "a fragment cannot be named \"on\"")
+	}
+	p.expect(tokenName, "on")
+	frag.typeCondition = p.parseName()
+	frag.selections = p.parseSelectionSet()
+	return frag
+}
+
+func (p *parser) parseSelectionSet() []selection {
+	p.expect(tokenPunctuator, "{")
+	var selections []selection
+	for !p.skip(tokenPunctuator, "}") {
+		selections = append(selections, p.parseSelection())
+	}
+	if len(selections) == 0 {
+		p.fail(p.token.pos, "a selection set cannot be empty")
+	}
+	return selections
+}
+
+func (p *parser) parseSelection() selection {
+	pos := p.token.pos
+	if !p.skip(tokenPunctuator, "...") {
+		return p.parseField()
+	}
+	if p.token.kind == tokenName && p.token.value != "on" {
+		return &fragmentSpread{
+			name:       p.parseName(),
+			directives: p.parseDirectives(),
+			pos:        pos,
+		}
+	}
+	frag := &inlineFragment{pos: pos}
+	if p.skip(tokenName, "on") {
+		frag.typeCondition = p.parseName()
+	}
+	frag.directives = p.parseDirectives()
+	frag.selections = p.parseSelectionSet()
+	return frag
+}
+
+func (p *parser) parseField() *field {
+	f := &field{pos: p.token.pos}
+	f.name = p.parseName()
+	if p.skip(tokenPunctuator, ":") {
+		f.alias, f.name = f.name, p.parseName()
+	}
+	f.arguments = p.parseArguments()
+	f.directives = p.parseDirectives()
+	if p.peek(tokenPunctuator, "{") {
+		f.selections = p.parseSelectionSet()
+	}
+	return f
+}
+
+func (p *parser) parseArguments() []*argument {
+	if !p.skip(tokenPunctuator, "(") {
+		return nil
+	}
+	var args []*argument
+	for !p.skip(tokenPunctuator, ")") {
+		arg := &argument{pos: p.token.pos}
+		arg.name = p.parseName()
+		p.expect(tokenPunctuator, ":")
+		arg.value = p.parseValue(false)
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (p *parser) parseDirectives() []*directive {
+	var directives []*directive
+	for p.peek(tokenPunctuator, "@") {
+		d := &directive{pos: p.token.pos}
+		p.advance()
+		d.name = p.parseName()
+		d.arguments = p.parseArguments()
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+// parseValue parses an input value, constant values not being allowed to reference variables.
+func (p *parser) parseValue(constant bool) interface{} {
+	tok := p.token
+	switch tok.kind {
+	case tokenPunctuator:
+		switch tok.value {
+		case "$":
+			if constant {
+				p.fail(tok.pos, "variables are not allowed in constant values")
+			}
+			p.advance()
+			return variableRef(p.parseName())
+		case "[":
+			p.advance()
+			list := []interface{}{}
+			for !p.skip(tokenPunctuator, "]") {
+				list = append(list, p.parseValue(constant))
+			}
+			return list
+		case "{":
+			p.advance()
+			obj := map[string]interface{}{}
+			for !p.skip(tokenPunctuator, "}") {
+				name := p.parseName()
+				p.expect(tokenPunctuator, ":")
+				obj[name] = p.parseValue(constant)
+			}
+			return obj
+		}
+	case tokenInt:
+		p.advance()
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			p.fail(tok.pos, "invalid integer %s: %v", tok.value, err)
+		}
+		return n
+	case tokenFloat:
+		p.advance()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			p.fail(tok.pos, "invalid float %s: %v", tok.value, err)
+		}
+		return f
+	case tokenString:
+		p.advance()
+		return tok.value
+	case tokenName:
+		p.advance()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		default:
+			return enumValue(tok.value)
+		}
+	}
+	p.unexpected()
+	return nil
+}
+
+func (p *parser) parseName() string {
+	if p.token.kind != tokenName {
+		p.unexpected()
+	}
+	name := p.token.value
+	p.advance()
+	return name
+}
+
+// peek returns true if the current token is of the given kind and value.
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.token.kind == kind && p.token.value == value
+}
+
+// skip consumes the current token if it is of the given kind and value.
+func (p *parser) skip(kind tokenKind, value string) bool {
+	if !p.peek(kind, value) {
+		return false
+	}
+	p.advance()
+	return true
+}
+
+// expect consumes the current token, failing if it is not of the given kind and value.
+func (p *parser) expect(kind tokenKind, value string) {
+	if !p.skip(kind, value) {
+		p.fail(p.token.pos, "expected %q, found %s", value, p.describeToken())
+	}
+}
+
+func (p *parser) unexpected() {
+	p.fail(p.token.pos, "unexpected %s", p.describeToken())
+}
+
+func (p *parser) describeToken() string {
+	if p.token.kind == tokenEOF {
+		return "end of document"
+	}
+	return strconv.Quote(p.token.value)
+}
+
+// advance lexes the next token.
+func (p *parser) advance() {
+	p.skipIgnored()
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.token = token{kind: tokenEOF, pos: start}
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.token = token{kind: tokenPunctuator, value: "...", pos: start}
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		p.pos++
+		p.token = token{kind: tokenPunctuator, value: string(c), pos: start}
+	case isNameStart(c):
+		for p.pos < len(p.src) && (isNameStart(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.token = token{kind: tokenName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		p.lexNumber()
+	case c == '"':
+		p.lexString()
+	default:
+		p.fail(start, "unexpected character %q", c)
+	}
+}
+
+// skipIgnored skips all white space, line terminators, commas and comments.
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+		default:
+			if strings.HasPrefix(p.src[p.pos:], "\uFEFF") {
+				p.pos += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (p *parser) lexNumber() {
+	start := p.pos
+	kind := tokenInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	if !p.skipDigits() {
+		p.fail(start, "invalid number")
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		if !p.skipDigits() {
+			p.fail(start, "invalid number")
+		}
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if !p.skipDigits() {
+			p.fail(start, "invalid number")
+		}
+	}
+	if p.pos < len(p.src) && (isNameStart(p.src[p.pos]) || p.src[p.pos] == '.') {
+		p.fail(start, "invalid number")
+	}
+	p.token = token{kind: kind, value: p.src[start:p.pos], pos: start}
+}
+
+func (p *parser) skipDigits() bool {
+	start := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// lexString lexes a (single line) string, which uses the same escape sequences as JSON.
+func (p *parser) lexString() {
+	start := p.pos
+	p.pos++
+	for {
+		if p.pos >= len(p.src) || p.src[p.pos] == '\n' || p.src[p.pos] == '\r' {
+			p.fail(start, "unterminated string")
+		}
+		c := p.src[p.pos]
+		p.pos++
+		if c == '\\' {
+			p.pos++
+			continue
+		}
+		if c == '"' {
+			break
+		}
+	}
+	var value string
+	if err := json.Unmarshal([]byte(p.src[start:p.pos]), &value); err != nil {
+		p.fail(start, "invalid string: %v", err)
+	}
+	p.token = token{kind: tokenString, value: value, pos: start}
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,487 @@
+// Package txbuilder provides a high-level API to assemble transactions,
+// such that they can be funded, signed and validated locally,
+// prior to being submitted to the network.
+//
+// It is used by the wallet module and the client,
+// but can be used by any Go integrator that wishes to create transactions.
+package txbuilder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+// builder errors
+var (
+	// ErrInsufficientFunds is returned in case the outputs available
+	// are insufficient to fund the requested amount.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrUnknownInputValue is returned in case the change of a transaction is computed,
+	// while the value of one of its inputs is unknown, as its parent output wasn't given.
+	ErrUnknownInputValue = errors.New("value of input is unknown")
+	// ErrNegativeChange is returned in case the inputs of a transaction
+	// are insufficient to fund its outputs and miner fees.
+	ErrNegativeChange = errors.New("inputs are insufficient to fund outputs and miner fees")
+	// ErrInvalidInputIndex is returned in case an input is referenced,
+	// using an index which is out of range.
+	ErrInvalidInputIndex = errors.New("input index out of range")
+)
+
+type (
+	// Builder is used to assemble a transaction.
+	//
+	// All methods which cannot fail return the builder itself,
+	// such that multiple calls can be chained.
+	Builder struct {
+		transaction types.Transaction
+		// ChainID of the chain the transaction is built for,
+		// only required to sign replay-protected transactions
+		chainID types.ChainID
+
+		// parent outputs of the inputs, if known,
+		// indexed the same as the inputs of the transaction
+		coinInputParents       []*parentOutput
+		blockStakeInputParents []*parentOutput
+
+		// signature hash cache, shared by all signatures,
+		// reset every time the transaction is modified
+		sigHashCache *types.SignatureHashCache
+	}
+
+	// parentOutput is the (coin or block stake) output spent by an input.
+	parentOutput struct {
+		Value     types.Currency
+		Condition types.UnlockConditionProxy
+	}
+
+	// UnspentCoinOutput is an unspent coin output, which can be used to fund a transaction.
+	UnspentCoinOutput struct {
+		ID     types.CoinOutputID
+		Output types.CoinOutput
+	}
+
+	// UnspentBlockStakeOutput is an unspent block stake output, which can be used to fund a transaction.
+	UnspentBlockStakeOutput struct {
+		ID     types.BlockStakeOutputID
+		Output types.BlockStakeOutput
+	}
+)
+
+// New creates a new builder for an (empty) transaction of the given version.
+func New(version types.TransactionVersion) *Builder {
+	return &Builder{
+		transaction: types.Transaction{Version: version},
+	}
+}
+
+// FromTransaction creates a new builder, continuing from an existing transaction.
+// The parent outputs of its inputs are unknown, meaning that the change of the transaction
+// cannot be computed and the inputs cannot be fulfilled as part of local validation.
+// The builder takes ownership of the given transaction.
+func FromTransaction(txn types.Transaction) *Builder {
+	return &Builder{
+		transaction:            txn,
+		coinInputParents:       make([]*parentOutput, len(txn.CoinInputs)),
+		blockStakeInputParents: make([]*parentOutput, len(txn.BlockStakeInputs)),
+	}
+}
+
+// WithChainID defines the ChainID of the chain the transaction is built for,
+// which is required in order to sign replay-protected transactions.
+func (b *Builder) WithChainID(id types.ChainID) *Builder {
+	b.chainID = id
+	b.sigHashCache = nil
+	return b
+}
+
+// Transaction returns the transaction assembled so far.
+func (b *Builder) Transaction() types.Transaction {
+	return b.transaction
+}
+
+// AddCoinInput adds a coin input to the transaction, for which the parent output is unknown.
+func (b *Builder) AddCoinInput(input types.CoinInput) *Builder {
+	b.transaction.CoinInputs = append(b.transaction.CoinInputs, input)
+	b.coinInputParents = append(b.coinInputParents, nil)
+	b.sigHashCache = nil
+	return b
+}
+
+// SpendCoinOutput adds a coin input to the transaction, spending the given coin output,
+// using the given (unsigned) fulfillment.
+func (b *Builder) SpendCoinOutput(id types.CoinOutputID, output types.CoinOutput, fulfillment types.MarshalableUnlockFulfillment) *Builder {
+	b.AddCoinInput(types.CoinInput{
+		ParentID:    id,
+		Fulfillment: types.NewFulfillment(fulfillment),
+	})
+	b.coinInputParents[len(b.coinInputParents)-1] = &parentOutput{
+		Value:     output.Value,
+		Condition: output.Condition,
+	}
+	return b
+}
+
+// AddCoinOutput adds a coin output to the transaction.
+func (b *Builder) AddCoinOutput(value types.Currency, condition types.UnlockConditionProxy) *Builder {
+	b.transaction.CoinOutputs = append(b.transaction.CoinOutputs, types.CoinOutput{
+		Value:     value,
+		Condition: condition,
+	})
+	b.sigHashCache = nil
+	return b
+}
+
+// AddBlockStakeInput adds a block stake input to the transaction, for which the parent output is unknown.
+func (b *Builder) AddBlockStakeInput(input types.BlockStakeInput) *Builder {
+	b.transaction.BlockStakeInputs = append(b.transaction.BlockStakeInputs, input)
+	b.blockStakeInputParents = append(b.blockStakeInputParents, nil)
+	b.sigHashCache = nil
+	return b
+}
+
+// SpendBlockStakeOutput adds a block stake input to the transaction, spending the given block stake output,
+// using the given (unsigned) fulfillment.
+func (b *Builder) SpendBlockStakeOutput(id types.BlockStakeOutputID, output types.BlockStakeOutput, fulfillment types.MarshalableUnlockFulfillment) *Builder {
+	b.AddBlockStakeInput(types.BlockStakeInput{
+		ParentID:    id,
+		Fulfillment: types.NewFulfillment(fulfillment),
+	})
+	b.blockStakeInputParents[len(b.blockStakeInputParents)-1] = &parentOutput{
+		Value:     output.Value,
+		Condition: output.Condition,
+	}
+	return b
+}
+
+// AddBlockStakeOutput adds a block stake output to the transaction.
+func (b *Builder) AddBlockStakeOutput(value types.Currency, condition types.UnlockConditionProxy) *Builder {
+	b.transaction.BlockStakeOutputs = append(b.transaction.BlockStakeOutputs, types.BlockStakeOutput{
+		Value:     value,
+		Condition: condition,
+	})
+	b.sigHashCache = nil
+	return b
+}
+
+// AddMinerFee adds a miner fee to the transaction.
+func (b *Builder) AddMinerFee(fee types.Currency) *Builder {
+	b.transaction.MinerFees = append(b.transaction.MinerFees, fee)
+	b.sigHashCache = nil
+	return b
+}
+
+// SetCoinInputFulfillment sets the fulfillment of the coin input at the given index.
+func (b *Builder) SetCoinInputFulfillment(index int, fulfillment types.UnlockFulfillmentProxy) error {
+	if index < 0 || index >= len(b.transaction.CoinInputs) {
+		return ErrInvalidInputIndex
+	}
+	b.transaction.CoinInputs[index].Fulfillment = fulfillment
+	b.sigHashCache = nil // legacy transactions cover the unlock hash of fulfillments
+	return nil
+}
+
+// SetBlockStakeInputFulfillment sets the fulfillment of the block stake input at the given index.
+func (b *Builder) SetBlockStakeInputFulfillment(index int, fulfillment types.UnlockFulfillmentProxy) error {
+	if index < 0 || index >= len(b.transaction.BlockStakeInputs) {
+		return ErrInvalidInputIndex
+	}
+	b.transaction.BlockStakeInputs[index].Fulfillment = fulfillment
+	b.sigHashCache = nil // legacy transactions cover the unlock hash of fulfillments
+	return nil
+}
+
+// SetArbitraryData sets the arbitrary data of the transaction.
+func (b *Builder) SetArbitraryData(data []byte) *Builder {
+	b.transaction.ArbitraryData = data
+	b.sigHashCache = nil
+	return b
+}
+
+// SetExtensionData sets the extension data of the transaction,
+// encoding the given fields (see types.EncodeTransactionExtensionData).
+func (b *Builder) SetExtensionData(fields ...types.TransactionExtensionField) error {
+	data, err := types.EncodeTransactionExtensionData(fields...)
+	if err != nil {
+		return err
+	}
+	b.transaction.ExtensionData = data
+	b.sigHashCache = nil
+	return nil
+}
+
+// SetExtension sets the extension of the transaction.
+func (b *Builder) SetExtension(extension interface{}) *Builder {
+	b.transaction.Extension = extension
+	b.sigHashCache = nil
+	return b
+}
+
+// Canonicalize sorts the inputs and outputs of the transaction into their canonical order
+// (see types.Transaction.Canonicalize), keeping track of the parent outputs of the inputs.
+// As the signature hash covers the position of the inputs, it has to be called prior to signing.
+func (b *Builder) Canonicalize() *Builder {
+	coinInputParents := make([]*parentOutput, 0, len(b.transaction.CoinInputs))
+	for _, idx := range types.CanonicalCoinInputOrder(b.transaction.CoinInputs) {
+		coinInputParents = append(coinInputParents, b.coinInputParent(idx))
+	}
+	blockStakeInputParents := make([]*parentOutput, 0, len(b.transaction.BlockStakeInputs))
+	for _, idx := range types.CanonicalBlockStakeInputOrder(b.transaction.BlockStakeInputs) {
+		blockStakeInputParents = append(blockStakeInputParents, b.blockStakeInputParent(idx))
+	}
+	b.transaction.Canonicalize()
+	b.coinInputParents, b.blockStakeInputParents = coinInputParents, blockStakeInputParents
+	b.sigHashCache = nil
+	return b
+}
+
+// CoinChange returns the coin value of the inputs of the transaction,
+// which isn't spent yet by its coin outputs and miner fees.
+// The parent outputs of all coin inputs have to be known.
+func (b *Builder) CoinChange() (types.Currency, error) {
+	var inputSum types.Currency
+	for _, parent := range b.coinInputParents {
+		if parent == nil {
+			return types.Currency{}, ErrUnknownInputValue
+		}
+		inputSum = inputSum.Add(parent.Value)
+	}
+	outputSum := b.transaction.CoinOutputSum()
+	if inputSum.Cmp(outputSum) < 0 {
+		return types.Currency{}, ErrNegativeChange
+	}
+	return inputSum.Sub(outputSum), nil
+}
+
+// BlockStakeChange returns the block stake value of the inputs of the transaction,
+// which isn't spent yet by its block stake outputs.
+// The parent outputs of all block stake inputs have to be known.
+func (b *Builder) BlockStakeChange() (types.Currency, error) {
+	var inputSum, outputSum types.Currency
+	for _, parent := range b.blockStakeInputParents {
+		if parent == nil {
+			return types.Currency{}, ErrUnknownInputValue
+		}
+		inputSum = inputSum.Add(parent.Value)
+	}
+	for _, bso := range b.transaction.BlockStakeOutputs {
+		outputSum = outputSum.Add(bso.Value)
+	}
+	if inputSum.Cmp(outputSum) < 0 {
+		return types.Currency{}, ErrNegativeChange
+	}
+	return inputSum.Sub(outputSum), nil
+}
+
+// AddCoinChange adds a coin output, sending the coin change of the transaction
+// to the given condition. No output is added if there is no change.
+func (b *Builder) AddCoinChange(condition types.UnlockConditionProxy) error {
+	change, err := b.CoinChange()
+	if err != nil {
+		return err
+	}
+	if !change.IsZero() {
+		b.AddCoinOutput(change, condition)
+	}
+	return nil
+}
+
+// AddBlockStakeChange adds a block stake output, sending the block stake change of the transaction
+// to the given condition. No output is added if there is no change.
+func (b *Builder) AddBlockStakeChange(condition types.UnlockConditionProxy) error {
+	change, err := b.BlockStakeChange()
+	if err != nil {
+		return err
+	}
+	if !change.IsZero() {
+		b.AddBlockStakeOutput(change, condition)
+	}
+	return nil
+}
+
+// SelectCoinOutputs selects the coin outputs required to fund the given amount,
+// preferring the outputs with the highest value. The value of the selected outputs is returned as well,
+// and is equal to or greater than the requested amount.
+func SelectCoinOutputs(outputs []UnspentCoinOutput, amount types.Currency) ([]UnspentCoinOutput, types.Currency, error) {
+	sorted := make([]UnspentCoinOutput, len(outputs))
+	copy(sorted, outputs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Output.Value.Cmp(sorted[j].Output.Value) > 0
+	})
+	var fund types.Currency
+	for idx, uco := range sorted {
+		fund = fund.Add(uco.Output.Value)
+		if fund.Cmp(amount) >= 0 {
+			return sorted[:idx+1], fund, nil
+		}
+	}
+	return nil, types.Currency{}, ErrInsufficientFunds
+}
+
+// SelectBlockStakeOutputs selects the block stake outputs required to fund the given amount,
+// preferring the outputs with the highest value. The value of the selected outputs is returned as well,
+// and is equal to or greater than the requested amount.
+func SelectBlockStakeOutputs(outputs []UnspentBlockStakeOutput, amount types.Currency) ([]UnspentBlockStakeOutput, types.Currency, error) {
+	sorted := make([]UnspentBlockStakeOutput, len(outputs))
+	copy(sorted, outputs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Output.Value.Cmp(sorted[j].Output.Value) > 0
+	})
+	var fund types.Currency
+	for idx, ubso := range sorted {
+		fund = fund.Add(ubso.Output.Value)
+		if fund.Cmp(amount) >= 0 {
+			return sorted[:idx+1], fund, nil
+		}
+	}
+	return nil, types.Currency{}, ErrInsufficientFunds
+}
+
+// SignCoinInput signs the fulfillment of the coin input at the given index, using the given key.
+// Inputs can be signed one by one, but only once the transaction is complete,
+// as any other modification of the transaction invalidates the signatures already registered.
+func (b *Builder) SignCoinInput(index int, key interface{}) error {
+	if index < 0 || index >= len(b.transaction.CoinInputs) {
+		return ErrInvalidInputIndex
+	}
+	return b.sign(&b.transaction.CoinInputs[index].Fulfillment, uint64(index), key)
+}
+
+// SignBlockStakeInput signs the fulfillment of the block stake input at the given index, using the given key.
+// Inputs can be signed one by one, but only once the transaction is complete,
+// as any other modification of the transaction invalidates the signatures already registered.
+func (b *Builder) SignBlockStakeInput(index int, key interface{}) error {
+	if index < 0 || index >= len(b.transaction.BlockStakeInputs) {
+		return ErrInvalidInputIndex
+	}
+	return b.sign(&b.transaction.BlockStakeInputs[index].Fulfillment, uint64(index), key)
+}
+
+func (b *Builder) sign(fulfillment *types.UnlockFulfillmentProxy, index uint64, key interface{}) error {
+	if b.sigHashCache == nil {
+		// signing does not modify the signature-covered properties of the transaction,
+		// allowing the cache to be shared by all signatures
+		b.sigHashCache = types.NewSignatureHashCache(b.transaction, b.chainID)
+	}
+	return fulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects:       []interface{}{index},
+		Transaction:        b.transaction,
+		SignatureHashCache: b.sigHashCache,
+		ChainID:            b.chainID,
+		Key:                key,
+	})
+}
+
+// Validate validates the transaction locally, within the given context.
+// It validates that the transaction is standard, that it spends no output twice,
+// that it respects the given size limits and minimum miner fee,
+// and that all inputs for which the parent output is known fulfill the condition of that output.
+// If the parent outputs of all inputs are known, it also validates
+// that the inputs exactly fund the outputs and miner fees.
+//
+// Validation is local only: it does not validate whether the spent outputs exist and are unspent.
+func (b *Builder) Validate(ctx types.TransactionValidationContext) error {
+	txn := b.transaction
+	if ctx.BlockSizeLimit > 0 {
+		err := types.TransactionFitsInABlock(txn, ctx.BlockSizeLimit)
+		if err != nil {
+			return err
+		}
+	}
+	err := types.ValidateArbitraryData(txn.ArbitraryData, ctx.ArbitraryDataSizeLimit, ctx.AllowedArbitraryDataTypes)
+	if err != nil {
+		return err
+	}
+	err = types.ValidateTransactionExtensionData(txn.ExtensionData, ctx.ExtensionDataSizeLimit)
+	if err != nil {
+		return err
+	}
+	err = types.TransactionFollowsMinimumValues(txn, ctx.MinimumMinerFee, ctx.IsBlockCreatingTx)
+	if err != nil {
+		return err
+	}
+	err = types.ValidateNoDoubleSpendsWithinTransaction(txn)
+	if err != nil {
+		return err
+	}
+	for idx, co := range txn.CoinOutputs {
+		if err = co.Condition.IsStandardCondition(ctx.ValidationContext); err != nil {
+			return fmt.Errorf("coin output #%d: %v", idx, err)
+		}
+	}
+	for idx, bso := range txn.BlockStakeOutputs {
+		if err = bso.Condition.IsStandardCondition(ctx.ValidationContext); err != nil {
+			return fmt.Errorf("block stake output #%d: %v", idx, err)
+		}
+	}
+
+	sigHashCache := types.NewSignatureHashCache(txn, ctx.ChainID)
+	spentCoinOutputs := make(map[types.CoinOutputID]types.CoinOutput, len(txn.CoinInputs))
+	for idx, ci := range txn.CoinInputs {
+		if parent := b.coinInputParent(idx); parent != nil {
+			spentCoinOutputs[ci.ParentID] = types.CoinOutput{Value: parent.Value, Condition: parent.Condition}
+		}
+	}
+	validateInput := func(index int, fulfillment types.UnlockFulfillmentProxy, parent *parentOutput) error {
+		err := fulfillment.IsStandardFulfillment(ctx.ValidationContext)
+		if err != nil || parent == nil {
+			return err
+		}
+		return parent.Condition.Fulfill(fulfillment, types.FulfillContext{
+			ExtraObjects:       []interface{}{uint64(index)},
+			BlockHeight:        ctx.BlockHeight,
+			BlockTime:          ctx.BlockTime,
+			Transaction:        txn,
+			SignatureHashCache: sigHashCache,
+			ChainID:            ctx.ChainID,
+			SpentCoinOutputs:   spentCoinOutputs,
+		})
+	}
+	for idx, ci := range txn.CoinInputs {
+		if err = validateInput(idx, ci.Fulfillment, b.coinInputParent(idx)); err != nil {
+			return fmt.Errorf("coin input #%d: %v", idx, err)
+		}
+	}
+	for idx, bsi := range txn.BlockStakeInputs {
+		if err = validateInput(idx, bsi.Fulfillment, b.blockStakeInputParent(idx)); err != nil {
+			return fmt.Errorf("block stake input #%d: %v", idx, err)
+		}
+	}
+
+	// ensure the inputs exactly fund the outputs, if all input values are known
+	if change, err := b.CoinChange(); err != ErrUnknownInputValue {
+		if err == nil && !change.IsZero() {
+			err = fmt.Errorf("coin inputs exceed coin outputs and miner fees by %s", change.String())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if change, err := b.BlockStakeChange(); err != ErrUnknownInputValue {
+		if err == nil && !change.IsZero() {
+			err = fmt.Errorf("block stake inputs exceed block stake outputs by %s", change.String())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coinInputParent returns the parent output of the coin input at the given index, nil if unknown.
+func (b *Builder) coinInputParent(index int) *parentOutput {
+	if index >= len(b.coinInputParents) {
+		return nil
+	}
+	return b.coinInputParents[index]
+}
+
+// blockStakeInputParent returns the parent output of the block stake input at the given index, nil if unknown.
+func (b *Builder) blockStakeInputParent(index int) *parentOutput {
+	if index >= len(b.blockStakeInputParents) {
+		return nil
+	}
+	return b.blockStakeInputParents[index]
+}
@@ -0,0 +1,284 @@
+package txbuilder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestSelectCoinOutputs(t *testing.T) {
+	outputs := []UnspentCoinOutput{
+		{ID: types.CoinOutputID{1}, Output: types.CoinOutput{Value: types.NewCurrency64(10)}},
+		{ID: types.CoinOutputID{2}, Output: types.CoinOutput{Value: types.NewCurrency64(50)}},
+		{ID: types.CoinOutputID{3}, Output: types.CoinOutput{Value: types.NewCurrency64(20)}},
+	}
+	selected, fund, err := SelectCoinOutputs(outputs, types.NewCurrency64(60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].ID != (types.CoinOutputID{2}) || selected[1].ID != (types.CoinOutputID{3}) {
+		t.Errorf("unexpected selection: %v", selected)
+	}
+	if !fund.Equals64(70) {
+		t.Errorf("unexpected fund: %s", fund.String())
+	}
+	_, _, err = SelectCoinOutputs(outputs, types.NewCurrency64(81))
+	if err != ErrInsufficientFunds {
+		t.Errorf("expected insufficient funds, not: %v", err)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair()
+	spk := types.Ed25519PublicKey(pk)
+	uh, err := types.NewPubKeyUnlockHash(spk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := types.NewCondition(types.NewUnlockHashCondition(uh))
+	ctx := types.TransactionValidationContext{
+		BlockSizeLimit:         2e6,
+		ArbitraryDataSizeLimit: 83,
+		MinimumMinerFee:        types.NewCurrency64(1),
+	}
+
+	b := New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{1}, types.CoinOutput{Value: types.NewCurrency64(100), Condition: condition}, types.NewSingleSignatureFulfillment(spk)).
+		SpendBlockStakeOutput(types.BlockStakeOutputID{1}, types.BlockStakeOutput{Value: types.NewCurrency64(5), Condition: condition}, types.NewSingleSignatureFulfillment(spk)).
+		AddCoinOutput(types.NewCurrency64(40), types.NewCondition(nil)).
+		AddBlockStakeOutput(types.NewCurrency64(2), types.NewCondition(nil)).
+		AddMinerFee(types.NewCurrency64(10)).
+		SetArbitraryData([]byte("txbuilder"))
+
+	change, err := b.CoinChange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !change.Equals64(50) {
+		t.Errorf("unexpected coin change: %s", change.String())
+	}
+	if err = b.Validate(ctx); err == nil {
+		t.Error("unsigned transaction without change shouldn't be valid")
+	}
+	if err = b.AddCoinChange(condition); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.AddBlockStakeChange(condition); err != nil {
+		t.Fatal(err)
+	}
+	if change, err = b.BlockStakeChange(); err != nil || !change.IsZero() {
+		t.Errorf("unexpected block stake change: %v (%v)", change, err)
+	}
+
+	if err = b.SignCoinInput(1, sk); err != ErrInvalidInputIndex {
+		t.Errorf("expected invalid input index error, not: %v", err)
+	}
+	if err = b.SignCoinInput(0, sk); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Validate(ctx); err == nil {
+		t.Error("transaction with unsigned block stake input shouldn't be valid")
+	}
+	if err = b.SignBlockStakeInput(0, sk); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Validate(ctx); err != nil {
+		t.Errorf("signed transaction should be valid: %v", err)
+	}
+
+	// spending more than the inputs is not allowed
+	b.AddCoinOutput(types.NewCurrency64(1), condition)
+	if _, err = b.CoinChange(); err != ErrNegativeChange {
+		t.Errorf("expected negative change error, not: %v", err)
+	}
+
+	// parent outputs are unknown when continuing from a transaction
+	b = FromTransaction(b.Transaction())
+	if _, err = b.CoinChange(); err != ErrUnknownInputValue {
+		t.Errorf("expected unknown input value error, not: %v", err)
+	}
+}
+
+func TestBuilderCanonicalize(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair()
+	spk := types.Ed25519PublicKey(pk)
+	uh, err := types.NewPubKeyUnlockHash(spk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := types.NewCondition(types.NewUnlockHashCondition(uh))
+
+	b := New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{2}, types.CoinOutput{Value: types.NewCurrency64(30), Condition: condition}, types.NewSingleSignatureFulfillment(spk)).
+		SpendCoinOutput(types.CoinOutputID{1}, types.CoinOutput{Value: types.NewCurrency64(70), Condition: condition}, types.NewSingleSignatureFulfillment(spk)).
+		AddCoinOutput(types.NewCurrency64(60), condition).
+		AddCoinOutput(types.NewCurrency64(30), condition).
+		AddMinerFee(types.NewCurrency64(10)).
+		Canonicalize()
+
+	txn := b.Transaction()
+	if !txn.IsCanonical() {
+		t.Fatal("transaction should be canonical")
+	}
+	if txn.CoinInputs[0].ParentID != (types.CoinOutputID{1}) || !txn.CoinOutputs[0].Value.Equals64(30) {
+		t.Errorf("unexpected canonical transaction: %v", txn)
+	}
+	// parent outputs are reordered together with their inputs
+	if parent := b.coinInputParent(0); parent == nil || !parent.Value.Equals64(70) {
+		t.Errorf("unexpected parent of first coin input: %v", parent)
+	}
+	if change, err := b.CoinChange(); err != nil || !change.IsZero() {
+		t.Errorf("unexpected coin change: %v (%v)", change, err)
+	}
+	if err = b.SignCoinInput(0, sk); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilderSignInputs(t *testing.T) {
+	ring1, ring2 := make(KeyRing), make(KeyRing)
+	uh1, err := ring1.AddKey(crypto.GenerateKeyPair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uh2, err := ring2.AddKey(crypto.GenerateKeyPair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	singleSig := types.NewCondition(types.NewUnlockHashCondition(uh1))
+	multiSig := types.NewCondition(types.NewMultiSignatureCondition(types.UnlockHashSlice{uh1, uh2}, 2))
+	ctx := types.TransactionValidationContext{
+		BlockSizeLimit:  2e6,
+		MinimumMinerFee: types.NewCurrency64(1),
+	}
+
+	b := New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{1}, types.CoinOutput{Value: types.NewCurrency64(30), Condition: singleSig}, &types.NilFulfillment{}).
+		SpendCoinOutput(types.CoinOutputID{2}, types.CoinOutput{Value: types.NewCurrency64(70), Condition: multiSig}, &types.NilFulfillment{}).
+		AddCoinOutput(types.NewCurrency64(90), singleSig).
+		AddMinerFee(types.NewCurrency64(10))
+
+	// parent outputs can be restored when continuing from a transaction
+	parent, _ := b.CoinInputParent(1)
+	b = FromTransaction(b.Transaction())
+	if err = b.SetCoinInputParent(0, types.CoinOutput{Value: types.NewCurrency64(30), Condition: singleSig}); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.SetCoinInputParent(1, parent); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.SetCoinInputParent(2, parent); err != ErrInvalidInputIndex {
+		t.Errorf("expected invalid input index error, not: %v", err)
+	}
+
+	// the first signer signs both inputs, the second signer only the multisig input
+	if n, err := b.SignInputs(ring1); err != nil || n != 2 {
+		t.Fatalf("unexpected signatures by first signer: %d (%v)", n, err)
+	}
+	if err = b.Validate(ctx); err == nil {
+		t.Error("transaction with a partially signed multisig input shouldn't be valid")
+	}
+	if n, err := b.SignInputs(ring1); err != nil || n != 0 {
+		t.Errorf("inputs shouldn't be signed twice by the same key: %d (%v)", n, err)
+	}
+	if n, err := b.SignInputs(ring2); err != nil || n != 1 {
+		t.Fatalf("unexpected signatures by second signer: %d (%v)", n, err)
+	}
+	if err = b.Validate(ctx); err != nil {
+		t.Errorf("fully signed transaction should be valid: %v", err)
+	}
+}
+
+func TestBuilderMergeSignatures(t *testing.T) {
+	ring1, ring2 := make(KeyRing), make(KeyRing)
+	uh1, err := ring1.AddKey(crypto.GenerateKeyPair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uh2, err := ring2.AddKey(crypto.GenerateKeyPair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiSig := types.NewCondition(types.NewMultiSignatureCondition(types.UnlockHashSlice{uh1, uh2}, 2))
+	ctx := types.TransactionValidationContext{
+		BlockSizeLimit:  2e6,
+		MinimumMinerFee: types.NewCurrency64(1),
+	}
+	parent := types.CoinOutput{Value: types.NewCurrency64(100), Condition: multiSig}
+	newTransaction := func(id types.CoinOutputID) types.Transaction {
+		return New(types.TransactionVersionOne).
+			SpendCoinOutput(id, parent, &types.NilFulfillment{}).
+			AddCoinOutput(types.NewCurrency64(90), multiSig).
+			AddMinerFee(types.NewCurrency64(10)).
+			Transaction()
+	}
+
+	// both signers sign their own copy of the transaction in parallel
+	copies := make([]*Builder, 2)
+	for idx, ring := range []KeyRing{ring1, ring2} {
+		copies[idx] = FromTransaction(newTransaction(types.CoinOutputID{1}))
+		if err = copies[idx].SetCoinInputParent(0, parent); err != nil {
+			t.Fatal(err)
+		}
+		if n, err := copies[idx].SignInputs(ring); err != nil || n != 1 {
+			t.Fatalf("unexpected signatures by signer #%d: %d (%v)", idx+1, n, err)
+		}
+	}
+	statuses := copies[0].SignatureStatus()
+	if len(statuses) != 1 || statuses[0].Complete || statuses[0].RequiredSignatures != 2 ||
+		len(statuses[0].Signers) != 1 || statuses[0].Signers[0] != uh1 ||
+		len(statuses[0].MissingSigners) != 1 || statuses[0].MissingSigners[0] != uh2 {
+		t.Fatalf("unexpected signature status of partially signed transaction: %+v", statuses)
+	}
+
+	if n, err := copies[0].MergeSignatures(copies[1].Transaction()); err != nil || n != 1 {
+		t.Fatalf("unexpected merged signatures: %d (%v)", n, err)
+	}
+	if n, err := copies[0].MergeSignatures(copies[1].Transaction()); err != nil || n != 0 {
+		t.Errorf("signatures shouldn't be merged twice: %d (%v)", n, err)
+	}
+	statuses = copies[0].SignatureStatus()
+	if len(statuses) != 1 || !statuses[0].Complete || len(statuses[0].Signers) != 2 || len(statuses[0].MissingSigners) != 0 {
+		t.Errorf("unexpected signature status of fully signed transaction: %+v", statuses)
+	}
+	if err = copies[0].Validate(ctx); err != nil {
+		t.Errorf("merged transaction should be valid: %v", err)
+	}
+
+	// only signatures of the same transaction can be merged
+	if _, err = copies[0].MergeSignatures(newTransaction(types.CoinOutputID{2})); err != ErrTransactionMismatch {
+		t.Errorf("expected transaction mismatch error, not: %v", err)
+	}
+}
+
+func TestDiffUnfulfilled(t *testing.T) {
+	uh := types.NewCondition(types.NewUnlockHashCondition(types.UnlockHash{Type: types.UnlockTypePubKey, Hash: crypto.Hash{1}}))
+	parent := types.CoinOutput{Value: types.NewCurrency64(100), Condition: uh}
+	a := New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{1}, parent, &types.NilFulfillment{}).
+		AddCoinOutput(types.NewCurrency64(90), uh).
+		AddMinerFee(types.NewCurrency64(10)).
+		Transaction()
+	if diffs := DiffUnfulfilled(a, a); len(diffs) != 0 {
+		t.Errorf("equal transactions shouldn't differ: %v", diffs)
+	}
+
+	b := New(types.TransactionVersionOne).
+		SpendCoinOutput(types.CoinOutputID{2}, parent, &types.NilFulfillment{}).
+		AddCoinOutput(types.NewCurrency64(80), uh).
+		AddMinerFee(types.NewCurrency64(20)).
+		Transaction()
+	b.ArbitraryData = []byte("data")
+	diffs := DiffUnfulfilled(a, b)
+	expected := []string{"coin input #0: spends", "coin output #0: value 90 != 80", "miner fee #0: 10 != 20", "arbitrary data: \"\" != \"data\""}
+	if len(diffs) != len(expected) {
+		t.Fatalf("unexpected differences: %v", diffs)
+	}
+	for idx, diff := range diffs {
+		if !strings.HasPrefix(diff, expected[idx]) {
+			t.Errorf("difference #%d: expected %q, not %q", idx, expected[idx], diff)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package txbuilder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// KeyRing contains the key pairs which can be used to sign the inputs of a transaction,
+// indexed by the (public key) unlock hash they own.
+type KeyRing map[types.UnlockHash]types.KeyPair
+
+// AddKey adds an Ed25519 key pair to the key ring, returning the unlock hash it owns.
+func (ring KeyRing) AddKey(sk crypto.SecretKey, pk crypto.PublicKey) (types.UnlockHash, error) {
+	spk := types.Ed25519PublicKey(pk)
+	uh, err := types.NewPubKeyUnlockHash(spk)
+	if err != nil {
+		return types.UnlockHash{}, err
+	}
+	ring[uh] = types.KeyPair{
+		PublicKey:  spk,
+		PrivateKey: types.ByteSlice(sk[:]),
+	}
+	return uh, nil
+}
+
+// SetCoinInputParent sets the parent output of the coin input at the given index,
+// e.g. to restore it for a builder continuing from an existing transaction.
+func (b *Builder) SetCoinInputParent(index int, output types.CoinOutput) error {
+	if index < 0 || index >= len(b.transaction.CoinInputs) {
+		return ErrInvalidInputIndex
+	}
+	for len(b.coinInputParents) < len(b.transaction.CoinInputs) {
+		b.coinInputParents = append(b.coinInputParents, nil)
+	}
+	b.coinInputParents[index] = &parentOutput{
+		Value:     output.Value,
+		Condition: output.Condition,
+	}
+	return nil
+}
+
+// SetBlockStakeInputParent sets the parent output of the block stake input at the given index,
+// e.g. to restore it for a builder continuing from an existing transaction.
+func (b *Builder) SetBlockStakeInputParent(index int, output types.BlockStakeOutput) error {
+	if index < 0 || index >= len(b.transaction.BlockStakeInputs) {
+		return ErrInvalidInputIndex
+	}
+	for len(b.blockStakeInputParents) < len(b.transaction.BlockStakeInputs) {
+		b.blockStakeInputParents = append(b.blockStakeInputParents, nil)
+	}
+	b.blockStakeInputParents[index] = &parentOutput{
+		Value:     output.Value,
+		Condition: output.Condition,
+	}
+	return nil
+}
+
+// CoinInputParent returns the parent output of the coin input at the given index,
+// false is returned if it is unknown.
+func (b *Builder) CoinInputParent(index int) (types.CoinOutput, bool) {
+	parent := b.coinInputParent(index)
+	if parent == nil {
+		return types.CoinOutput{}, false
+	}
+	return types.CoinOutput{Value: parent.Value, Condition: parent.Condition}, true
+}
+
+// BlockStakeInputParent returns the parent output of the block stake input at the given index,
+// false is returned if it is unknown.
+func (b *Builder) BlockStakeInputParent(index int) (types.BlockStakeOutput, bool) {
+	parent := b.blockStakeInputParent(index)
+	if parent == nil {
+		return types.BlockStakeOutput{}, false
+	}
+	return types.BlockStakeOutput{Value: parent.Value, Condition: parent.Condition}, true
+}
+
+// SignInputs signs all inputs of which the parent output is known and owned by keys of the given ring,
+// returning the amount of signatures added. Outputs locked by a (time locked) public key
+// or (weighted) multisig condition are supported, other inputs are left untouched.
+// Multisig inputs are signed using all keys of the ring which haven't signed yet,
+// such that signatures can be collected one signer at a time.
+//
+// Similar to SignCoinInput, it should only be called once the transaction is complete.
+func (b *Builder) SignInputs(ring KeyRing) (int, error) {
+	var signed int
+	for idx := range b.transaction.CoinInputs {
+		n, err := b.signInput(&b.transaction.CoinInputs[idx].Fulfillment, uint64(idx), b.coinInputParent(idx), ring)
+		if err != nil {
+			return signed, fmt.Errorf("coin input #%d: %v", idx, err)
+		}
+		signed += n
+	}
+	for idx := range b.transaction.BlockStakeInputs {
+		n, err := b.signInput(&b.transaction.BlockStakeInputs[idx].Fulfillment, uint64(idx), b.blockStakeInputParent(idx), ring)
+		if err != nil {
+			return signed, fmt.Errorf("block stake input #%d: %v", idx, err)
+		}
+		signed += n
+	}
+	return signed, nil
+}
+
+// signInput signs the fulfillment of a single input, using the keys of the ring owning its parent output.
+func (b *Builder) signInput(fulfillment *types.UnlockFulfillmentProxy, index uint64, parent *parentOutput, ring KeyRing) (int, error) {
+	if parent == nil || parent.Condition.Condition == nil {
+		return 0, nil
+	}
+	condition := parent.Condition.Condition
+	switch uh := condition.UnlockHash(); uh.Type {
+	case types.UnlockTypePubKey:
+		key, ok := ring[uh]
+		if !ok {
+			return 0, nil
+		}
+		if ss, ok := fulfillment.Fulfillment.(*types.SingleSignatureFulfillment); ok && len(ss.Signature) != 0 {
+			return 0, nil // already signed
+		}
+		fulfillment.Fulfillment = types.NewSingleSignatureFulfillment(key.PublicKey)
+		return 1, b.sign(fulfillment, index, key.PrivateKey)
+
+	case types.UnlockTypeMultiSig:
+		for {
+			// unwrap time locks
+			getter, ok := condition.(types.MarshalableUnlockConditionGetter)
+			if !ok {
+				break
+			}
+			condition = getter.GetMarshalableUnlockCondition()
+		}
+		getter, ok := condition.(types.UnlockHashSliceGetter)
+		if !ok {
+			return 0, fmt.Errorf("unexpected condition type %T for multisig unlock hash", condition)
+		}
+		if fulfillment.FulfillmentType() == types.FulfillmentTypeNil {
+			fulfillment.Fulfillment = &types.MultiSignatureFulfillment{}
+		}
+		ms, ok := fulfillment.Fulfillment.(*types.MultiSignatureFulfillment)
+		if !ok {
+			return 0, fmt.Errorf("unexpected fulfillment type %T for multisig condition", fulfillment.Fulfillment)
+		}
+		var signed int
+		for _, signatory := range getter.UnlockHashSlice() {
+			key, ok := ring[signatory]
+			if !ok || hasSigned(ms, key.PublicKey) {
+				continue
+			}
+			if err := b.sign(fulfillment, index, key); err != nil {
+				return signed, err
+			}
+			signed++
+		}
+		return signed, nil
+
+	default:
+		return 0, nil
+	}
+}
+
+// hasSigned returns true if the given public key already signed the given multisig fulfillment.
+func hasSigned(ms *types.MultiSignatureFulfillment, pk types.PublicKey) bool {
+	for _, pair := range ms.Pairs {
+		if pair.PublicKey.Algorithm == pk.Algorithm && bytes.Equal(pair.PublicKey.Key, pk.Key) {
+			return true
+		}
+	}
+	return false
+}
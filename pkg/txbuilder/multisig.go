@@ -0,0 +1,306 @@
+package txbuilder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// ErrTransactionMismatch is returned in case the signatures of a transaction are merged,
+// while it isn't the same transaction as the one being built.
+var ErrTransactionMismatch = errors.New("transactions differ in more than their fulfillments")
+
+// InputSignatureStatus reports the signatures present for a single input,
+// and the signatures which are still missing to fulfill its parent output.
+type InputSignatureStatus struct {
+	// BlockStake is true for block stake inputs, false for coin inputs
+	BlockStake bool
+	Index      int
+	// UnlockHash is the unlock hash of the condition of the parent output
+	UnlockHash types.UnlockHash
+	// RequiredSignatures is the minimum amount of signatures required to fulfill the condition
+	RequiredSignatures uint64
+	// Signers are the signatories which signed the input,
+	// MissingSigners the signatories which can still sign it
+	Signers        []types.UnlockHash
+	MissingSigners []types.UnlockHash
+	// Complete is true in case sufficient signatures are present,
+	// the signatures themselves aren't validated (see Validate)
+	Complete bool
+}
+
+// SignatureStatus returns the signature status of all inputs of which the parent output is known
+// and locked by a (time locked) public key or (weighted) multisig condition,
+// allowing to report which signatures are still missing for a transaction signed one signer at a time.
+func (b *Builder) SignatureStatus() []InputSignatureStatus {
+	var statuses []InputSignatureStatus
+	for idx, ci := range b.transaction.CoinInputs {
+		if status, ok := inputSignatureStatus(ci.Fulfillment, b.coinInputParent(idx)); ok {
+			status.Index = idx
+			statuses = append(statuses, status)
+		}
+	}
+	for idx, bsi := range b.transaction.BlockStakeInputs {
+		if status, ok := inputSignatureStatus(bsi.Fulfillment, b.blockStakeInputParent(idx)); ok {
+			status.BlockStake, status.Index = true, idx
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// inputSignatureStatus returns the signature status of a single input,
+// false is returned if its parent output is unknown or not signature-based.
+func inputSignatureStatus(fulfillment types.UnlockFulfillmentProxy, parent *parentOutput) (InputSignatureStatus, bool) {
+	if parent == nil || parent.Condition.Condition == nil {
+		return InputSignatureStatus{}, false
+	}
+	condition := parent.Condition.Condition
+	status := InputSignatureStatus{UnlockHash: condition.UnlockHash()}
+	switch status.UnlockHash.Type {
+	case types.UnlockTypePubKey:
+		status.RequiredSignatures = 1
+		if ss, ok := fulfillment.Fulfillment.(*types.SingleSignatureFulfillment); ok && len(ss.Signature) != 0 {
+			status.Signers = []types.UnlockHash{status.UnlockHash}
+			status.Complete = true
+		} else {
+			status.MissingSigners = []types.UnlockHash{status.UnlockHash}
+		}
+		return status, true
+
+	case types.UnlockTypeMultiSig:
+		for {
+			// unwrap time locks
+			getter, ok := condition.(types.MarshalableUnlockConditionGetter)
+			if !ok {
+				break
+			}
+			condition = getter.GetMarshalableUnlockCondition()
+		}
+		getter, ok := condition.(interface {
+			types.UnlockHashSliceGetter
+			GetMinimumSignatureCount() uint64
+		})
+		if !ok {
+			return InputSignatureStatus{}, false
+		}
+		status.RequiredSignatures = getter.GetMinimumSignatureCount()
+		ms, _ := fulfillment.Fulfillment.(*types.MultiSignatureFulfillment)
+		for _, signatory := range getter.UnlockHashSlice() {
+			if ms != nil && hasSignatory(ms, signatory) {
+				status.Signers = append(status.Signers, signatory)
+			} else {
+				status.MissingSigners = append(status.MissingSigners, signatory)
+			}
+		}
+		if wms, ok := condition.(*types.WeightedMultiSignatureCondition); ok {
+			var weight uint64
+			for _, signer := range status.Signers {
+				weight += wms.Weight(signer)
+			}
+			status.Complete = weight >= wms.MinimumWeight
+		} else {
+			status.Complete = uint64(len(status.Signers)) >= status.RequiredSignatures
+		}
+		return status, true
+
+	default:
+		return InputSignatureStatus{}, false
+	}
+}
+
+// hasSignatory returns true if the given (public key) unlock hash signed the given multisig fulfillment.
+func hasSignatory(ms *types.MultiSignatureFulfillment, uh types.UnlockHash) bool {
+	for _, pair := range ms.Pairs {
+		puh, err := types.NewPubKeyUnlockHash(pair.PublicKey)
+		if err == nil && puh.Cmp(uh) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeSignatures adds the signatures of the given transaction to the transaction being built,
+// returning the amount of signatures added. It allows a multisig transaction
+// to be signed by multiple signers in parallel, each signing their own copy of the transaction.
+// ErrTransactionMismatch is returned if the given transaction differs in more than its fulfillments.
+func (b *Builder) MergeSignatures(txn types.Transaction) (int, error) {
+	equal, err := equalUnfulfilled(b.transaction, txn)
+	if err != nil {
+		return 0, err
+	}
+	if !equal {
+		return 0, ErrTransactionMismatch
+	}
+	var merged int
+	for idx := range b.transaction.CoinInputs {
+		n, err := mergeFulfillment(&b.transaction.CoinInputs[idx].Fulfillment, txn.CoinInputs[idx].Fulfillment)
+		if err != nil {
+			return merged, fmt.Errorf("coin input #%d: %v", idx, err)
+		}
+		merged += n
+	}
+	for idx := range b.transaction.BlockStakeInputs {
+		n, err := mergeFulfillment(&b.transaction.BlockStakeInputs[idx].Fulfillment, txn.BlockStakeInputs[idx].Fulfillment)
+		if err != nil {
+			return merged, fmt.Errorf("block stake input #%d: %v", idx, err)
+		}
+		merged += n
+	}
+	return merged, nil
+}
+
+// mergeFulfillment adds the signatures of the other fulfillment to the given fulfillment.
+func mergeFulfillment(fulfillment *types.UnlockFulfillmentProxy, other types.UnlockFulfillmentProxy) (int, error) {
+	switch otherFulfillment := other.Fulfillment.(type) {
+	case nil, *types.NilFulfillment:
+		return 0, nil
+
+	case *types.SingleSignatureFulfillment:
+		if len(otherFulfillment.Signature) == 0 {
+			return 0, nil
+		}
+		switch ss := fulfillment.Fulfillment.(type) {
+		case nil, *types.NilFulfillment:
+		case *types.SingleSignatureFulfillment:
+			if len(ss.Signature) != 0 {
+				return 0, nil // already signed
+			}
+		default:
+			return 0, fmt.Errorf("cannot merge %T into %T", other.Fulfillment, fulfillment.Fulfillment)
+		}
+		fulfillment.Fulfillment = otherFulfillment
+		return 1, nil
+
+	case *types.MultiSignatureFulfillment:
+		switch fulfillment.Fulfillment.(type) {
+		case nil, *types.NilFulfillment:
+			fulfillment.Fulfillment = &types.MultiSignatureFulfillment{}
+		case *types.MultiSignatureFulfillment:
+		default:
+			return 0, fmt.Errorf("cannot merge %T into %T", other.Fulfillment, fulfillment.Fulfillment)
+		}
+		ms := fulfillment.Fulfillment.(*types.MultiSignatureFulfillment)
+		var merged int
+		for _, pair := range otherFulfillment.Pairs {
+			if hasSigned(ms, pair.PublicKey) {
+				continue
+			}
+			ms.Pairs = append(ms.Pairs, pair)
+			merged++
+		}
+		return merged, nil
+
+	default:
+		return 0, fmt.Errorf("cannot merge fulfillments of type %T", other.Fulfillment)
+	}
+}
+
+// equalUnfulfilled returns true if both transactions are equal, ignoring the fulfillments of their inputs.
+func equalUnfulfilled(a, b types.Transaction) (bool, error) {
+	ab, err := rivbin.Marshal(withoutFulfillments(a))
+	if err != nil {
+		return false, err
+	}
+	bb, err := rivbin.Marshal(withoutFulfillments(b))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ab, bb), nil
+}
+
+// withoutFulfillments returns a copy of the given transaction, with the fulfillments of its inputs removed.
+func withoutFulfillments(txn types.Transaction) types.Transaction {
+	txn.CoinInputs = append([]types.CoinInput(nil), txn.CoinInputs...)
+	for idx := range txn.CoinInputs {
+		txn.CoinInputs[idx].Fulfillment = types.NewFulfillment(&types.NilFulfillment{})
+	}
+	txn.BlockStakeInputs = append([]types.BlockStakeInput(nil), txn.BlockStakeInputs...)
+	for idx := range txn.BlockStakeInputs {
+		txn.BlockStakeInputs[idx].Fulfillment = types.NewFulfillment(&types.NilFulfillment{})
+	}
+	return txn
+}
+
+// DiffUnfulfilled describes the differences between two transactions, ignoring their fulfillments,
+// such that it can be reported why the signatures of both transactions cannot be merged.
+// An empty slice is returned in case both transactions only differ in their fulfillments.
+func DiffUnfulfilled(a, b types.Transaction) []string {
+	var diffs []string
+	if a.Version != b.Version {
+		diffs = append(diffs, fmt.Sprintf("version: %d != %d", a.Version, b.Version))
+	}
+	if len(a.CoinInputs) != len(b.CoinInputs) {
+		diffs = append(diffs, fmt.Sprintf("coin inputs: %d != %d", len(a.CoinInputs), len(b.CoinInputs)))
+	} else {
+		for idx := range a.CoinInputs {
+			if a.CoinInputs[idx].ParentID != b.CoinInputs[idx].ParentID {
+				diffs = append(diffs, fmt.Sprintf("coin input #%d: spends %s != %s",
+					idx, a.CoinInputs[idx].ParentID.String(), b.CoinInputs[idx].ParentID.String()))
+			}
+		}
+	}
+	if len(a.CoinOutputs) != len(b.CoinOutputs) {
+		diffs = append(diffs, fmt.Sprintf("coin outputs: %d != %d", len(a.CoinOutputs), len(b.CoinOutputs)))
+	} else {
+		for idx := range a.CoinOutputs {
+			diffs = append(diffs, diffOutput(fmt.Sprintf("coin output #%d", idx),
+				a.CoinOutputs[idx].Value, b.CoinOutputs[idx].Value,
+				a.CoinOutputs[idx].Condition, b.CoinOutputs[idx].Condition)...)
+		}
+	}
+	if len(a.BlockStakeInputs) != len(b.BlockStakeInputs) {
+		diffs = append(diffs, fmt.Sprintf("block stake inputs: %d != %d", len(a.BlockStakeInputs), len(b.BlockStakeInputs)))
+	} else {
+		for idx := range a.BlockStakeInputs {
+			if a.BlockStakeInputs[idx].ParentID != b.BlockStakeInputs[idx].ParentID {
+				diffs = append(diffs, fmt.Sprintf("block stake input #%d: spends %s != %s",
+					idx, a.BlockStakeInputs[idx].ParentID.String(), b.BlockStakeInputs[idx].ParentID.String()))
+			}
+		}
+	}
+	if len(a.BlockStakeOutputs) != len(b.BlockStakeOutputs) {
+		diffs = append(diffs, fmt.Sprintf("block stake outputs: %d != %d", len(a.BlockStakeOutputs), len(b.BlockStakeOutputs)))
+	} else {
+		for idx := range a.BlockStakeOutputs {
+			diffs = append(diffs, diffOutput(fmt.Sprintf("block stake output #%d", idx),
+				a.BlockStakeOutputs[idx].Value, b.BlockStakeOutputs[idx].Value,
+				a.BlockStakeOutputs[idx].Condition, b.BlockStakeOutputs[idx].Condition)...)
+		}
+	}
+	if len(a.MinerFees) != len(b.MinerFees) {
+		diffs = append(diffs, fmt.Sprintf("miner fees: %d != %d", len(a.MinerFees), len(b.MinerFees)))
+	} else {
+		for idx := range a.MinerFees {
+			if !a.MinerFees[idx].Equals(b.MinerFees[idx]) {
+				diffs = append(diffs, fmt.Sprintf("miner fee #%d: %s != %s",
+					idx, a.MinerFees[idx].String(), b.MinerFees[idx].String()))
+			}
+		}
+	}
+	if !bytes.Equal(a.ArbitraryData, b.ArbitraryData) {
+		diffs = append(diffs, fmt.Sprintf("arbitrary data: %q != %q", a.ArbitraryData, b.ArbitraryData))
+	}
+	if len(diffs) == 0 {
+		// the remaining properties are either the fulfillments or extension data
+		if equal, err := equalUnfulfilled(a, b); err != nil || !equal {
+			diffs = append(diffs, "extension data differs")
+		}
+	}
+	return diffs
+}
+
+// diffOutput describes the differences between two outputs, identified by the given name.
+func diffOutput(name string, av, bv types.Currency, ac, bc types.UnlockConditionProxy) []string {
+	var diffs []string
+	if !av.Equals(bv) {
+		diffs = append(diffs, fmt.Sprintf("%s: value %s != %s", name, av.String(), bv.String()))
+	}
+	if !ac.Equal(bc) {
+		diffs = append(diffs, fmt.Sprintf("%s: condition %s != %s", name, ac.UnlockHash().String(), bc.UnlockHash().String()))
+	}
+	return diffs
+}
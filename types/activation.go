@@ -0,0 +1,145 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// ActivationHeightDisabled can be used as the activation height of a condition type,
+// signature algorithm or other consensus rule, as to not activate it at all,
+// until a (future) activation height is agreed upon by the block creators of the chain.
+const ActivationHeightDisabled BlockHeight = math.MaxUint64
+
+var (
+	// ErrConditionTypeNotActive is returned when a transaction creates an output
+	// using a condition type which isn't yet accepted at the height of its block,
+	// see ChainConstants.ConditionTypeActivationHeights.
+	ErrConditionTypeNotActive = NewError(ErrorCodeConditionTypeNotActive, "condition type is not yet accepted at this block height")
+	// ErrSignatureAlgorithmNotActive is returned when a transaction fulfills an input
+	// using a public key of a signature algorithm which isn't yet accepted at the height of its block,
+	// see ChainConstants.SignatureAlgoActivationHeights.
+	ErrSignatureAlgorithmNotActive = NewError(ErrorCodeSignatureAlgorithmNotActive, "signature algorithm is not yet accepted at this block height")
+)
+
+// NewUnlockTypeActivationHeights returns the activation heights of all condition types
+// and signature algorithms which were added after the launch of the standard network and testnet,
+// all set to the given height. Chains which launched with them can leave the activation heights undefined instead.
+func NewUnlockTypeActivationHeights(height BlockHeight) (map[ConditionType]BlockHeight, map[SignatureAlgoType]BlockHeight) {
+	conditionTypes := make(map[ConditionType]BlockHeight)
+	for _, ct := range []ConditionType{
+		ConditionTypeColdStaking,
+		ConditionTypeHashedTimeLock,
+		ConditionTypePaymentChannel,
+		ConditionTypeBurn,
+		ConditionTypeRelativeTimeLock,
+		ConditionTypeVesting,
+		ConditionTypeWeightedMultiSignature,
+		ConditionTypeAnyOf,
+		ConditionTypeAllOf,
+	} {
+		conditionTypes[ct] = height
+	}
+	signatureAlgorithms := map[SignatureAlgoType]BlockHeight{
+		SignatureAlgoSecp256k1: height,
+		SignatureAlgoBLS12381:  height,
+	}
+	return conditionTypes, signatureAlgorithms
+}
+
+// ValidateUnlockTypesActivated returns an error in case the transaction creates an output
+// using a condition type, or fulfills an input using a public key of a signature algorithm,
+// which isn't yet accepted at the given block height, see ConditionTypeActivationHeights
+// and SignatureAlgoActivationHeights. Nested conditions and fulfillments are validated as well.
+func (c *ChainConstants) ValidateUnlockTypesActivated(t Transaction, height BlockHeight) error {
+	if len(c.ConditionTypeActivationHeights) != 0 {
+		for idx, co := range t.CoinOutputs {
+			if err := c.validateConditionActivated(co.Condition.Condition, height); err != nil {
+				return fmt.Errorf("coin output #%d: %w", idx, err)
+			}
+		}
+		for idx, bso := range t.BlockStakeOutputs {
+			if err := c.validateConditionActivated(bso.Condition.Condition, height); err != nil {
+				return fmt.Errorf("block stake output #%d: %w", idx, err)
+			}
+		}
+	}
+	if len(c.SignatureAlgoActivationHeights) != 0 {
+		for idx, ci := range t.CoinInputs {
+			if err := c.validateFulfillmentActivated(ci.Fulfillment.Fulfillment, height); err != nil {
+				return NewCoinInputError(err, idx, ci, nil)
+			}
+		}
+		for idx, bsi := range t.BlockStakeInputs {
+			if err := c.validateFulfillmentActivated(bsi.Fulfillment.Fulfillment, height); err != nil {
+				return NewBlockStakeInputError(err, idx, bsi, nil)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ChainConstants) validateConditionActivated(condition MarshalableUnlockCondition, height BlockHeight) error {
+	if condition == nil {
+		return nil
+	}
+	ct := condition.ConditionType()
+	if activationHeight, ok := c.ConditionTypeActivationHeights[ct]; ok && height < activationHeight {
+		return fmt.Errorf("%w: %d", ErrConditionTypeNotActive, ct)
+	}
+	switch tc := condition.(type) {
+	case *AnyOfCondition:
+		return c.validateConditionsActivated(tc.Conditions, height)
+	case *AllOfCondition:
+		return c.validateConditionsActivated(tc.Conditions, height)
+	case MarshalableUnlockConditionGetter:
+		return c.validateConditionActivated(tc.GetMarshalableUnlockCondition(), height)
+	default:
+		return nil
+	}
+}
+
+func (c *ChainConstants) validateConditionsActivated(conditions []UnlockConditionProxy, height BlockHeight) error {
+	for _, condition := range conditions {
+		if err := c.validateConditionActivated(condition.Condition, height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ChainConstants) validateFulfillmentActivated(fulfillment MarshalableUnlockFulfillment, height BlockHeight) error {
+	var publicKeys []PublicKey
+	switch tf := fulfillment.(type) {
+	case *SingleSignatureFulfillment:
+		publicKeys = append(publicKeys, tf.PublicKey)
+	case *AtomicSwapFulfillment:
+		publicKeys = append(publicKeys, tf.PublicKey)
+	case *LegacyAtomicSwapFulfillment:
+		publicKeys = append(publicKeys, tf.PublicKey)
+	case *anyAtomicSwapFulfillment:
+		return c.validateFulfillmentActivated(tf.atomicSwapFulfillment, height)
+	case *MultiSignatureFulfillment:
+		for _, pair := range tf.Pairs {
+			publicKeys = append(publicKeys, pair.PublicKey)
+		}
+	case *HashedTimeLockFulfillment:
+		publicKeys = append(publicKeys, tf.PublicKey)
+	case *PaymentChannelFulfillment:
+		publicKeys = append(publicKeys, tf.Sender.PublicKey)
+		if tf.Receiver != nil {
+			publicKeys = append(publicKeys, tf.Receiver.PublicKey)
+		}
+	case *CompositeFulfillment:
+		for _, branch := range tf.Branches {
+			if err := c.validateFulfillmentActivated(branch.Fulfillment.Fulfillment, height); err != nil {
+				return err
+			}
+		}
+	}
+	for _, pk := range publicKeys {
+		if activationHeight, ok := c.SignatureAlgoActivationHeights[pk.Algorithm]; ok && height < activationHeight {
+			return fmt.Errorf("%w: %s", ErrSignatureAlgorithmNotActive, pk.Algorithm)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+func TestValidateUnlockTypesActivated(t *testing.T) {
+	uh := NewUnlockHash(UnlockTypePubKey, crypto.Hash{1})
+	otherUH := NewUnlockHash(UnlockTypePubKey, crypto.Hash{2})
+	var cts ChainConstants
+	cts.ConditionTypeActivationHeights, cts.SignatureAlgoActivationHeights = NewUnlockTypeActivationHeights(100)
+
+	testCases := []struct {
+		name        string
+		transaction Transaction
+		err         error
+	}{
+		{"legacy condition", Transaction{
+			CoinOutputs: []CoinOutput{{Condition: NewCondition(NewTimeLockCondition(42, NewUnlockHashCondition(uh)))}},
+		}, nil},
+		{"cold staking condition", Transaction{
+			BlockStakeOutputs: []BlockStakeOutput{{Condition: NewCondition(NewColdStakingCondition(uh, otherUH))}},
+		}, ErrConditionTypeNotActive},
+		{"nested vesting condition", Transaction{
+			CoinOutputs: []CoinOutput{{Condition: NewCondition(NewTimeLockCondition(42,
+				NewVestingCondition(uh, NewCurrency64(10), 0, 0, 10, 1)))}},
+		}, ErrConditionTypeNotActive},
+		{"ed25519 public key", Transaction{
+			CoinInputs: []CoinInput{{Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(Ed25519PublicKey(crypto.PublicKey{})))}},
+		}, nil},
+		{"secp256k1 public key", Transaction{
+			CoinInputs: []CoinInput{{Fulfillment: NewFulfillment(&MultiSignatureFulfillment{Pairs: []PublicKeySignaturePair{
+				{PublicKey: Ed25519PublicKey(crypto.PublicKey{})},
+				{PublicKey: PublicKey{Algorithm: SignatureAlgoSecp256k1}},
+			}})}},
+		}, ErrSignatureAlgorithmNotActive},
+		{"nested bls12-381 public key", Transaction{
+			BlockStakeInputs: []BlockStakeInput{{Fulfillment: NewFulfillment(NewAnyOfFulfillment(1,
+				NewSingleSignatureFulfillment(PublicKey{Algorithm: SignatureAlgoBLS12381})))}},
+		}, ErrSignatureAlgorithmNotActive},
+	}
+	for _, testCase := range testCases {
+		for _, height := range []BlockHeight{99, 100} {
+			err := cts.ValidateUnlockTypesActivated(testCase.transaction, height)
+			if height < 100 && testCase.err != nil {
+				if ErrorCodeOf(err) != ErrorCodeOf(testCase.err) {
+					t.Errorf("%s at height %d: expected %v, not %v", testCase.name, height, testCase.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("%s at height %d: unexpected error: %v", testCase.name, height, err)
+			}
+		}
+	}
+
+	// all condition types and signature algorithms are accepted if no activation heights are defined
+	if err := (&ChainConstants{}).ValidateUnlockTypesActivated(testCases[len(testCases)-1].transaction, 0); err != nil {
+		t.Error("unexpected error without activation heights:", err)
+	}
+	// no new condition type or signature algorithm is accepted while disabled
+	cts.ConditionTypeActivationHeights, cts.SignatureAlgoActivationHeights = NewUnlockTypeActivationHeights(ActivationHeightDisabled)
+	if err := cts.ValidateUnlockTypesActivated(testCases[1].transaction, ActivationHeightDisabled-1); ErrorCodeOf(err) != ErrorCodeConditionTypeNotActive {
+		t.Errorf("expected %v, not %v", ErrConditionTypeNotActive, err)
+	}
+}
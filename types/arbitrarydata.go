@@ -0,0 +1,246 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ArbitraryDataType defines the type of the payload of structured arbitrary data.
+type ArbitraryDataType uint8
+
+// The standard arbitrary data types. Types in the range [ArbitraryDataTypeCustom, 255]
+// are free to be used (and registered) by chains, in order to build their own protocols
+// on top of arbitrary data.
+const (
+	// ArbitraryDataTypeRaw is the type of arbitrary data which isn't structured,
+	// meaning it isn't wrapped in an envelope, as is the case for all legacy arbitrary data.
+	ArbitraryDataTypeRaw ArbitraryDataType = iota
+	// ArbitraryDataTypeBinary defines a structured payload of opaque binary data.
+	ArbitraryDataTypeBinary
+	// ArbitraryDataTypeUTF8 defines a structured payload of UTF-8 encoded text.
+	ArbitraryDataTypeUTF8
+	// ArbitraryDataTypeJSON defines a structured payload of a JSON-encoded value.
+	ArbitraryDataTypeJSON
+
+	// ArbitraryDataTypeCustom is the first arbitrary data type reserved for chain-specific types.
+	ArbitraryDataTypeCustom ArbitraryDataType = 128
+)
+
+// arbitraryDataEnvelopeMagic is the prefix which identifies structured arbitrary data,
+// it is followed by the type (1 byte), the version (1 byte) and the payload.
+var arbitraryDataEnvelopeMagic = [...]byte{0xAD, 0xE7}
+
+// ArbitraryDataEnvelopeHeaderSize is the size (in bytes) of the envelope
+// of structured arbitrary data, prefixed to its payload.
+const ArbitraryDataEnvelopeHeaderSize = len(arbitraryDataEnvelopeMagic) + 2
+
+// Structured arbitrary data errors
+var (
+	// ErrInvalidArbitraryDataEnvelope is returned in case arbitrary data
+	// is prefixed with the envelope magic, but doesn't define a valid envelope.
+	ErrInvalidArbitraryDataEnvelope = errors.New("invalid structured arbitrary data envelope")
+	// ErrArbitraryDataTypeNotAllowed is returned in case arbitrary data
+	// is of a type which isn't allowed by the chain.
+	ErrArbitraryDataTypeNotAllowed = errors.New("arbitrary data type is not allowed")
+)
+
+// ArbitraryDataPayloadValidator is a function used to validate
+// the (versioned) payload of structured arbitrary data of a specific type.
+type ArbitraryDataPayloadValidator func(version uint8, payload []byte) error
+
+// _RegisteredArbitraryDataTypes contains the payload validators of all known arbitrary data types,
+// arbitrary data of an unknown type is considered valid, as long as its envelope is valid.
+var _RegisteredArbitraryDataTypes = map[ArbitraryDataType]ArbitraryDataPayloadValidator{
+	ArbitraryDataTypeBinary: func(uint8, []byte) error { return nil },
+	ArbitraryDataTypeUTF8: func(_ uint8, payload []byte) error {
+		if !utf8.Valid(payload) {
+			return errors.New("payload is not valid UTF-8")
+		}
+		return nil
+	},
+	ArbitraryDataTypeJSON: func(_ uint8, payload []byte) error {
+		if !json.Valid(payload) {
+			return errors.New("payload is not valid JSON")
+		}
+		return nil
+	},
+}
+
+// RegisterArbitraryDataType is used to register a (custom) arbitrary data type,
+// by linking it to the validator used to validate its payload.
+//
+// RegisterArbitraryDataType can also used to unregister an arbitrary data type,
+// by calling this function with nil as the ArbitraryDataPayloadValidator.
+func RegisterArbitraryDataType(dt ArbitraryDataType, validator ArbitraryDataPayloadValidator) {
+	if validator == nil {
+		delete(_RegisteredArbitraryDataTypes, dt)
+		return
+	}
+	_RegisteredArbitraryDataTypes[dt] = validator
+}
+
+// String returns the name of a standard arbitrary data type,
+// or the decimal representation of any other type.
+func (dt ArbitraryDataType) String() string {
+	switch dt {
+	case ArbitraryDataTypeRaw:
+		return "raw"
+	case ArbitraryDataTypeBinary:
+		return "binary"
+	case ArbitraryDataTypeUTF8:
+		return "utf8"
+	case ArbitraryDataTypeJSON:
+		return "json"
+	default:
+		return strconv.FormatUint(uint64(dt), 10)
+	}
+}
+
+// LoadString loads an arbitrary data type from its name or decimal representation.
+func (dt *ArbitraryDataType) LoadString(str string) error {
+	switch str {
+	case "raw":
+		*dt = ArbitraryDataTypeRaw
+	case "binary":
+		*dt = ArbitraryDataTypeBinary
+	case "utf8":
+		*dt = ArbitraryDataTypeUTF8
+	case "json":
+		*dt = ArbitraryDataTypeJSON
+	default:
+		x, err := strconv.ParseUint(str, 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid arbitrary data type %q", str)
+		}
+		*dt = ArbitraryDataType(x)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (dt ArbitraryDataType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dt.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (dt *ArbitraryDataType) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	return dt.LoadString(str)
+}
+
+// StructuredArbitraryData is arbitrary data wrapped in a typed and versioned envelope,
+// such that protocols can be built on top of the arbitrary data of transactions.
+// Arbitrary data which isn't wrapped in an envelope is represented
+// as structured arbitrary data of the raw type.
+type StructuredArbitraryData struct {
+	Type    ArbitraryDataType `json:"type"`
+	Version uint8             `json:"version"`
+	Payload []byte            `json:"payload"`
+}
+
+// NewStructuredArbitraryData creates new structured arbitrary data,
+// validating the payload using the validator registered for the given type.
+func NewStructuredArbitraryData(dt ArbitraryDataType, version uint8, payload []byte) (StructuredArbitraryData, error) {
+	data := StructuredArbitraryData{
+		Type:    dt,
+		Version: version,
+		Payload: payload,
+	}
+	return data, data.Validate()
+}
+
+// EncodeArbitraryData encodes the payload as structured arbitrary data,
+// such that it can be used as the arbitrary data of a transaction.
+func EncodeArbitraryData(dt ArbitraryDataType, version uint8, payload []byte) ([]byte, error) {
+	data, err := NewStructuredArbitraryData(dt, version, payload)
+	if err != nil {
+		return nil, err
+	}
+	return data.MarshalArbitraryData(), nil
+}
+
+// DecodeArbitraryData decodes the arbitrary data of a transaction as structured arbitrary data.
+// Arbitrary data which isn't wrapped in an envelope is returned as structured data of the raw type.
+func DecodeArbitraryData(b []byte) (StructuredArbitraryData, error) {
+	if !bytes.HasPrefix(b, arbitraryDataEnvelopeMagic[:]) {
+		return StructuredArbitraryData{
+			Type:    ArbitraryDataTypeRaw,
+			Payload: b,
+		}, nil
+	}
+	if len(b) < ArbitraryDataEnvelopeHeaderSize {
+		return StructuredArbitraryData{}, ErrInvalidArbitraryDataEnvelope
+	}
+	data := StructuredArbitraryData{
+		Type:    ArbitraryDataType(b[len(arbitraryDataEnvelopeMagic)]),
+		Version: b[len(arbitraryDataEnvelopeMagic)+1],
+		Payload: b[ArbitraryDataEnvelopeHeaderSize:],
+	}
+	return data, data.Validate()
+}
+
+// MarshalArbitraryData encodes the structured arbitrary data as raw arbitrary data,
+// wrapping the payload in an envelope for all types but the raw type.
+func (data StructuredArbitraryData) MarshalArbitraryData() []byte {
+	if data.Type == ArbitraryDataTypeRaw {
+		return data.Payload
+	}
+	b := make([]byte, 0, ArbitraryDataEnvelopeHeaderSize+len(data.Payload))
+	b = append(b, arbitraryDataEnvelopeMagic[:]...)
+	b = append(b, byte(data.Type), data.Version)
+	return append(b, data.Payload...)
+}
+
+// Validate validates the structured arbitrary data,
+// using the payload validator registered for its type, if any.
+func (data StructuredArbitraryData) Validate() error {
+	if data.Type == ArbitraryDataTypeRaw {
+		if data.Version != 0 {
+			return fmt.Errorf("%v: raw arbitrary data cannot be versioned", ErrInvalidArbitraryDataEnvelope)
+		}
+		if bytes.HasPrefix(data.Payload, arbitraryDataEnvelopeMagic[:]) {
+			return fmt.Errorf("%v: raw arbitrary data cannot start with the envelope prefix", ErrInvalidArbitraryDataEnvelope)
+		}
+		return nil
+	}
+	validator, ok := _RegisteredArbitraryDataTypes[data.Type]
+	if !ok {
+		return nil // unknown types are opaque
+	}
+	if err := validator(data.Version, data.Payload); err != nil {
+		return fmt.Errorf("invalid arbitrary data of type %s (v%d): %v", data.Type.String(), data.Version, err)
+	}
+	return nil
+}
+
+// ValidateArbitraryData validates the arbitrary data of a transaction,
+// ensuring it fits within the given size limit, and in case it is structured,
+// that its envelope and payload are valid. If allowedTypes is non-empty,
+// the (structured) arbitrary data has to be of one of the allowed types,
+// where unstructured arbitrary data is of the raw type. Empty arbitrary data is always valid.
+func ValidateArbitraryData(arbitraryData []byte, sizeLimit uint64, allowedTypes []ArbitraryDataType) error {
+	err := ArbitraryDataFits(arbitraryData, sizeLimit)
+	if err != nil || len(arbitraryData) == 0 {
+		return err
+	}
+	data, err := DecodeArbitraryData(arbitraryData)
+	if err != nil {
+		return err
+	}
+	if len(allowedTypes) == 0 {
+		return nil
+	}
+	for _, dt := range allowedTypes {
+		if dt == data.Type {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v: %s", ErrArbitraryDataTypeNotAllowed, data.Type.String())
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStructuredArbitraryDataEncoding(t *testing.T) {
+	testCases := []struct {
+		Type    ArbitraryDataType
+		Version uint8
+		Payload []byte
+	}{
+		{ArbitraryDataTypeRaw, 0, []byte("legacy data")},
+		{ArbitraryDataTypeBinary, 1, []byte{0, 1, 2, 3}},
+		{ArbitraryDataTypeUTF8, 0, []byte("héllo")},
+		{ArbitraryDataTypeJSON, 2, []byte(`{"foo":42}`)},
+		{ArbitraryDataTypeCustom + 1, 7, []byte("opaque")},
+	}
+	for idx, testCase := range testCases {
+		b, err := EncodeArbitraryData(testCase.Type, testCase.Version, testCase.Payload)
+		if err != nil {
+			t.Errorf("#%d: failed to encode: %v", idx, err)
+			continue
+		}
+		data, err := DecodeArbitraryData(b)
+		if err != nil {
+			t.Errorf("#%d: failed to decode: %v", idx, err)
+			continue
+		}
+		if data.Type != testCase.Type || data.Version != testCase.Version || !bytes.Equal(data.Payload, testCase.Payload) {
+			t.Errorf("#%d: unexpected decoded data: %v != %v", idx, data, testCase)
+		}
+		var dt ArbitraryDataType
+		if err = dt.LoadString(testCase.Type.String()); err != nil || dt != testCase.Type {
+			t.Errorf("#%d: failed to load type %s: %v", idx, testCase.Type.String(), err)
+		}
+	}
+}
+
+func TestStructuredArbitraryDataInvalid(t *testing.T) {
+	if _, err := EncodeArbitraryData(ArbitraryDataTypeJSON, 0, []byte("{")); err == nil {
+		t.Error("encoded invalid JSON payload")
+	}
+	if _, err := EncodeArbitraryData(ArbitraryDataTypeUTF8, 0, []byte{0xff, 0xfe}); err == nil {
+		t.Error("encoded invalid UTF-8 payload")
+	}
+	if _, err := EncodeArbitraryData(ArbitraryDataTypeRaw, 1, []byte("data")); err == nil {
+		t.Error("encoded versioned raw data")
+	}
+	if _, err := DecodeArbitraryData(arbitraryDataEnvelopeMagic[:]); err != ErrInvalidArbitraryDataEnvelope {
+		t.Error("expected invalid envelope error, got:", err)
+	}
+
+	// custom types can be validated once registered
+	customType := ArbitraryDataTypeCustom
+	b, err := EncodeArbitraryData(customType, 1, []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterArbitraryDataType(customType, func(version uint8, _ []byte) error {
+		if version != 2 {
+			return ErrInvalidArbitraryDataEnvelope
+		}
+		return nil
+	})
+	defer RegisterArbitraryDataType(customType, nil)
+	if _, err = DecodeArbitraryData(b); err == nil {
+		t.Error("decoded custom data with an unsupported version")
+	}
+}
+
+func TestValidateArbitraryData(t *testing.T) {
+	jsonData, err := EncodeArbitraryData(ArbitraryDataTypeJSON, 0, []byte(`[1,2,3]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawData := []byte("data")
+
+	// all types are allowed by default
+	for _, data := range [][]byte{nil, jsonData, rawData} {
+		if err = ValidateArbitraryData(data, 83, nil); err != nil {
+			t.Errorf("unexpected error for data %v: %v", data, err)
+		}
+	}
+	// size limit applies to all data
+	if err = ValidateArbitraryData(jsonData, uint64(len(jsonData)-1), nil); err != ErrArbitraryDataTooLarge {
+		t.Error("expected data too large error, got:", err)
+	}
+	// only allowed types are accepted, empty data is always valid
+	allowed := []ArbitraryDataType{ArbitraryDataTypeJSON}
+	if err = ValidateArbitraryData(jsonData, 83, allowed); err != nil {
+		t.Error("unexpected error for allowed JSON data:", err)
+	}
+	if err = ValidateArbitraryData(nil, 83, allowed); err != nil {
+		t.Error("unexpected error for empty data:", err)
+	}
+	if err = ValidateArbitraryData(rawData, 83, allowed); err == nil {
+		t.Error("accepted raw data, while only JSON data is allowed")
+	}
+	allowed = append(allowed, ArbitraryDataTypeRaw)
+	if err = ValidateArbitraryData(rawData, 83, allowed); err != nil {
+		t.Error("unexpected error for allowed raw data:", err)
+	}
+}
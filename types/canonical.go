@@ -0,0 +1,126 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+// Canonicalize sorts the inputs and outputs of the transaction into their canonical order,
+// such that multiple parties assembling the same transaction independently,
+// end up with the exact same transaction (and thus the same bytes to sign).
+//
+// Coin and block stake inputs are sorted by their parent ID,
+// coin and block stake outputs are sorted by value, and then by (binary-encoded) condition and metadata.
+// The order of all other fields (such as the miner fees) is left untouched.
+//
+// As the signature hash of a transaction covers the position of its inputs,
+// a transaction has to be canonicalized prior to being signed.
+func (t *Transaction) Canonicalize() {
+	t.CoinInputs = reorderCoinInputs(t.CoinInputs, CanonicalCoinInputOrder(t.CoinInputs))
+	t.BlockStakeInputs = reorderBlockStakeInputs(t.BlockStakeInputs, CanonicalBlockStakeInputOrder(t.BlockStakeInputs))
+	sort.SliceStable(t.CoinOutputs, func(i, j int) bool {
+		return compareOutputs(t.CoinOutputs[i].Value, t.CoinOutputs[i].Condition, t.CoinOutputs[i].Metadata,
+			t.CoinOutputs[j].Value, t.CoinOutputs[j].Condition, t.CoinOutputs[j].Metadata) < 0
+	})
+	sort.SliceStable(t.BlockStakeOutputs, func(i, j int) bool {
+		return compareOutputs(t.BlockStakeOutputs[i].Value, t.BlockStakeOutputs[i].Condition, t.BlockStakeOutputs[i].Metadata,
+			t.BlockStakeOutputs[j].Value, t.BlockStakeOutputs[j].Condition, t.BlockStakeOutputs[j].Metadata) < 0
+	})
+}
+
+// IsCanonical returns true if the inputs and outputs
+// of the transaction are in their canonical order, see Canonicalize.
+func (t Transaction) IsCanonical() bool {
+	for i := 1; i < len(t.CoinInputs); i++ {
+		if bytes.Compare(t.CoinInputs[i-1].ParentID[:], t.CoinInputs[i].ParentID[:]) > 0 {
+			return false
+		}
+	}
+	for i := 1; i < len(t.BlockStakeInputs); i++ {
+		if bytes.Compare(t.BlockStakeInputs[i-1].ParentID[:], t.BlockStakeInputs[i].ParentID[:]) > 0 {
+			return false
+		}
+	}
+	for i := 1; i < len(t.CoinOutputs); i++ {
+		a, b := t.CoinOutputs[i-1], t.CoinOutputs[i]
+		if compareOutputs(a.Value, a.Condition, a.Metadata, b.Value, b.Condition, b.Metadata) > 0 {
+			return false
+		}
+	}
+	for i := 1; i < len(t.BlockStakeOutputs); i++ {
+		a, b := t.BlockStakeOutputs[i-1], t.BlockStakeOutputs[i]
+		if compareOutputs(a.Value, a.Condition, a.Metadata, b.Value, b.Condition, b.Metadata) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalCoinInputOrder returns the indices of the given coin inputs,
+// in the canonical order of those inputs. It can be used by callers
+// which have to reorder data they keep indexed the same as the inputs.
+func CanonicalCoinInputOrder(inputs []CoinInput) []int {
+	order := identityOrder(len(inputs))
+	sort.SliceStable(order, func(i, j int) bool {
+		return bytes.Compare(inputs[order[i]].ParentID[:], inputs[order[j]].ParentID[:]) < 0
+	})
+	return order
+}
+
+// CanonicalBlockStakeInputOrder returns the indices of the given block stake inputs,
+// in the canonical order of those inputs. It can be used by callers
+// which have to reorder data they keep indexed the same as the inputs.
+func CanonicalBlockStakeInputOrder(inputs []BlockStakeInput) []int {
+	order := identityOrder(len(inputs))
+	sort.SliceStable(order, func(i, j int) bool {
+		return bytes.Compare(inputs[order[i]].ParentID[:], inputs[order[j]].ParentID[:]) < 0
+	})
+	return order
+}
+
+func identityOrder(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func reorderCoinInputs(inputs []CoinInput, order []int) []CoinInput {
+	if len(inputs) == 0 {
+		return inputs
+	}
+	reordered := make([]CoinInput, 0, len(inputs))
+	for _, idx := range order {
+		reordered = append(reordered, inputs[idx])
+	}
+	return reordered
+}
+
+func reorderBlockStakeInputs(inputs []BlockStakeInput, order []int) []BlockStakeInput {
+	if len(inputs) == 0 {
+		return inputs
+	}
+	reordered := make([]BlockStakeInput, 0, len(inputs))
+	for _, idx := range order {
+		reordered = append(reordered, inputs[idx])
+	}
+	return reordered
+}
+
+// compareOutputs compares two (coin or block stake) outputs,
+// first by value, then by binary-encoded condition and finally by metadata.
+func compareOutputs(valueA Currency, conditionA UnlockConditionProxy, metadataA []byte, valueB Currency, conditionB UnlockConditionProxy, metadataB []byte) int {
+	if c := valueA.Cmp(valueB); c != 0 {
+		return c
+	}
+	// conditions are always encodable, as they are validated when decoded
+	ba, _ := siabin.Marshal(conditionA)
+	bb, _ := siabin.Marshal(conditionB)
+	if c := bytes.Compare(ba, bb); c != 0 {
+		return c
+	}
+	return bytes.Compare(metadataA, metadataB)
+}
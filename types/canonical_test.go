@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+func TestTransactionCanonicalize(t *testing.T) {
+	txn := manyInputsTransaction(3)
+	// reverse the inputs and outputs, such that the transaction is no longer canonical
+	txn.CoinInputs[0], txn.CoinInputs[2] = txn.CoinInputs[2], txn.CoinInputs[0]
+	txn.CoinOutputs[0], txn.CoinOutputs[2] = txn.CoinOutputs[2], txn.CoinOutputs[0]
+	txn.BlockStakeInputs = []BlockStakeInput{{ParentID: BlockStakeOutputID{2}}, {ParentID: BlockStakeOutputID{1}}}
+	txn.BlockStakeOutputs = []BlockStakeOutput{
+		{Value: NewCurrency64(1), Condition: NewCondition(NewUnlockHashCondition(NewUnlockHash(UnlockTypePubKey, crypto.Hash{2})))},
+		{Value: NewCurrency64(1), Condition: NewCondition(NewUnlockHashCondition(NewUnlockHash(UnlockTypePubKey, crypto.Hash{1})))},
+	}
+	if txn.IsCanonical() {
+		t.Fatal("transaction shouldn't be canonical")
+	}
+	id := txn.ID()
+
+	canonical := txn
+	canonical.CoinInputs = append([]CoinInput(nil), txn.CoinInputs...)
+	canonical.CoinOutputs = append([]CoinOutput(nil), txn.CoinOutputs...)
+	canonical.BlockStakeInputs = append([]BlockStakeInput(nil), txn.BlockStakeInputs...)
+	canonical.BlockStakeOutputs = append([]BlockStakeOutput(nil), txn.BlockStakeOutputs...)
+	canonical.Canonicalize()
+	if !canonical.IsCanonical() {
+		t.Fatal("canonicalized transaction should be canonical")
+	}
+	if canonical.ID() == id {
+		t.Error("canonicalized transaction should have a different ID")
+	}
+	for i := 1; i < len(canonical.CoinOutputs); i++ {
+		if canonical.CoinOutputs[i-1].Value.Cmp(canonical.CoinOutputs[i].Value) > 0 {
+			t.Errorf("coin outputs aren't sorted by value: %v", canonical.CoinOutputs)
+		}
+	}
+	if canonical.BlockStakeInputs[0].ParentID != (BlockStakeOutputID{1}) {
+		t.Errorf("block stake inputs aren't sorted by parent ID: %v", canonical.BlockStakeInputs)
+	}
+	if canonical.BlockStakeOutputs[0].Condition.UnlockHash().Hash != (crypto.Hash{1}) {
+		t.Errorf("block stake outputs of equal value aren't sorted by condition: %v", canonical.BlockStakeOutputs)
+	}
+
+	// canonicalizing is deterministic, no matter the original order
+	txn.Canonicalize()
+	if txn.ID() != canonical.ID() {
+		t.Error("canonicalizing the same transaction should result in the same transaction")
+	}
+}
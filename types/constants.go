@@ -8,10 +8,13 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/bech32"
 )
 
 // ChainConstants is a utility struct which groups together the chain configuration
@@ -21,6 +24,52 @@ type ChainConstants struct {
 	// ArbitraryDataSizeLimit is the maximum size an arbitrary data block
 	// within a single transaction can have, in bytes
 	ArbitraryDataSizeLimit uint64
+	// AllowedArbitraryDataTypes limits the (structured) arbitrary data of transactions
+	// to the given types, see StructuredArbitraryData. Unstructured arbitrary data
+	// is of the raw type. No types defined means that all types are allowed.
+	AllowedArbitraryDataTypes []ArbitraryDataType
+	// ExtensionDataSizeLimit is the maximum size the extension data of
+	// a single (v1 or v2) transaction can have, in bytes, see TransactionExtensionField.
+	// Zero means that extension data isn't accepted. Note that enabling it for an existing chain
+	// is a hard fork, future features built on top of the extension data can be soft forks however.
+	ExtensionDataSizeLimit uint64
+	// OutputMetadataSizeLimit is the maximum size the metadata of a single coin or block stake output
+	// of an output metadata (v3) transaction can have, in bytes, see TransactionVersionThree.
+	// Zero means that output metadata transactions aren't accepted.
+	// Note that enabling it for an existing chain is a hard fork.
+	OutputMetadataSizeLimit uint64
+
+	// Bech32AddressPrefix is the human-readable part used for the bech32 representation
+	// of unlock hashes (addresses) of this chain, see (UnlockHash).Bech32String.
+	// Bech32 addresses are only accepted (next to the hex representation) if a prefix is defined.
+	Bech32AddressPrefix string
+
+	// ReplayProtectionActivationHeight is the block height from which on replay-protected
+	// transactions (TransactionVersionTwo) are accepted, activating replay protection as a hard fork.
+	// Existing chains should set it to a (future) height agreed upon by the block creators,
+	// zero accepts them from the genesis block on, which is only safe for new chains.
+	ReplayProtectionActivationHeight BlockHeight
+	// ConditionTypeActivationHeights defines the block height from which on outputs
+	// of the given condition types are accepted, including conditions nested by other conditions.
+	// Accepting a condition type which isn't known by deployed nodes is a hard fork,
+	// and thus requires an activation height agreed upon by the block creators for existing chains,
+	// or ActivationHeightDisabled as long as there is none. Condition types which aren't defined
+	// are accepted from the genesis block on, which is only safe for new chains.
+	ConditionTypeActivationHeights map[ConditionType]BlockHeight
+	// SignatureAlgoActivationHeights defines, in the same way as ConditionTypeActivationHeights,
+	// the block height from which on public keys of the given signature algorithms are accepted
+	// to fulfill inputs.
+	SignatureAlgoActivationHeights map[SignatureAlgoType]BlockHeight
+	// ArbitraryDataEnvelopeActivationHeight is the block height from which on the arbitrary data
+	// of transactions is validated as (structured) arbitrary data, see ValidateArbitraryData.
+	// Prior to it, only the size of the arbitrary data is validated, as existing chains
+	// can contain raw arbitrary data which looks like an invalid envelope.
+	// Zero validates it from the genesis block on, which is only safe for new chains.
+	ArbitraryDataEnvelopeActivationHeight BlockHeight
+	// ForkIdentifier can optionally be defined by a chain which forks from another chain,
+	// such that its ChainID differs from the ChainID of the chain it forked from,
+	// preventing replay-protected transactions from being replayed on the other chain.
+	ForkIdentifier string
 
 	RootDepth Target
 	// BlockFrequency is the average timespan between blocks, in seconds.
@@ -94,12 +143,39 @@ type ChainConstants struct {
 	TransactionPool TransactionPoolConstants
 }
 
+// DefaultCurrencyPrecision is the default amount of decimals a coin can be split up in.
+const DefaultCurrencyPrecision = 9
+
 // CurrencyUnits defines the units used for the different kind of currencies.
 type CurrencyUnits struct {
-	// OneCoin is the size of a "coin", making it possible to split a coin up if wanted
+	// OneCoin is the size of a "coin", making it possible to split a coin up if wanted,
+	// it is required to be a power of ten (10^precision).
 	OneCoin Currency
 }
 
+// NewCurrencyUnits creates the currency units for a coin
+// which can be split up in the given amount of decimals.
+func NewCurrencyUnits(precision uint) CurrencyUnits {
+	return CurrencyUnits{
+		OneCoin: NewCurrency(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)),
+	}
+}
+
+// Precision returns the amount of decimals a coin can be split up in,
+// meaning the amount of zeros of OneCoin.
+func (cu CurrencyUnits) Precision() uint {
+	return uint(len(cu.OneCoin.String()) - 1)
+}
+
+// Validate validates that OneCoin is defined as a power of ten.
+func (cu CurrencyUnits) Validate() error {
+	str := cu.OneCoin.String()
+	if str[0] != '1' || strings.Trim(str[1:], "0") != "" {
+		return fmt.Errorf("Invalid currency units: one coin (%s) is not a power of ten", str)
+	}
+	return nil
+}
+
 // TransactionPoolConstants defines the constants used by the TransactionPool.
 type TransactionPoolConstants struct {
 	// TransactionSizeLimit defines the size of the largest transaction that
@@ -116,13 +192,16 @@ type TransactionPoolConstants struct {
 	// ordering, so the size limit is such that the transaction pool will never
 	// exceed the size of a block.
 	PoolSizeLimit int
+
+	// FeePerByte defines the fee, on top of the MinimumTransactionFee,
+	// that wallets pay for each byte of the (siabin) encoded transaction.
+	// By default it is zero, meaning only the MinimumTransactionFee is paid.
+	FeePerByte Currency
 }
 
 // DefaultCurrencyUnits provides sane defaults for currency units
 func DefaultCurrencyUnits() CurrencyUnits {
-	return CurrencyUnits{
-		OneCoin: NewCurrency(new(big.Int).Exp(big.NewInt(10), big.NewInt(9), nil)),
-	}
+	return NewCurrencyUnits(DefaultCurrencyPrecision)
 }
 
 // DefaultTransactionPoolConstants provides sane defaults for TransactionPool constants.
@@ -167,9 +246,18 @@ func StandardnetChainConstants() ChainConstants {
 		GenesisTimestamp:          Timestamp(1496322000),
 		GenesisTransactionVersion: genesisTxnVersion,
 		DefaultTransactionVersion: defaultTxnVersion,
+		Bech32AddressPrefix:       "riv",
 		CurrencyUnits:             currencyUnits,
 		TransactionPool:           DefaultTransactionPoolConstants(),
+		// replay protection is a hard fork for the existing standard network,
+		// activated once all block creators are expected to have upgraded
+		ReplayProtectionActivationHeight: 600000,
 	}
+	// condition types and signature algorithms added since the launch of the standard network
+	// are a hard fork as well, and stay disabled until an activation height is agreed upon
+	cts.ConditionTypeActivationHeights, cts.SignatureAlgoActivationHeights = NewUnlockTypeActivationHeights(ActivationHeightDisabled)
+	// the same goes for the arbitrary data envelope rules
+	cts.ArbitraryDataEnvelopeActivationHeight = ActivationHeightDisabled
 
 	cts.GenesisBlockStakeAllocation = append(cts.GenesisBlockStakeAllocation, BlockStakeOutput{
 		Value:     NewCurrency64(1000000),
@@ -198,7 +286,7 @@ func TestnetChainConstants() ChainConstants {
 
 	// 'testing' settings are for automatic testing, and create much faster
 	// environments than a human can interact with.
-	return ChainConstants{
+	cts := ChainConstants{
 		BlockSizeLimit:            2e6,
 		ArbitraryDataSizeLimit:    83,
 		RootDepth:                 Target{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
@@ -217,6 +305,7 @@ func TestnetChainConstants() ChainConstants {
 		BlockStakeAging:           uint64(1 << 10),
 		GenesisTransactionVersion: genesisTxnVersion,
 		DefaultTransactionVersion: defaultTxnVersion,
+		Bech32AddressPrefix:       "rivt",
 		GenesisBlockStakeAllocation: []BlockStakeOutput{
 			{
 				Value: NewCurrency64(2000),
@@ -248,7 +337,16 @@ func TestnetChainConstants() ChainConstants {
 		},
 		CurrencyUnits:   currencyUnits,
 		TransactionPool: DefaultTransactionPoolConstants(),
+		// replay protection is a hard fork for the existing testnet,
+		// activated once all block creators are expected to have upgraded
+		ReplayProtectionActivationHeight: 3000000,
 	}
+	// condition types and signature algorithms added since the launch of the testnet
+	// are a hard fork as well, and stay disabled until an activation height is agreed upon
+	cts.ConditionTypeActivationHeights, cts.SignatureAlgoActivationHeights = NewUnlockTypeActivationHeights(ActivationHeightDisabled)
+	// the same goes for the arbitrary data envelope rules
+	cts.ArbitraryDataEnvelopeActivationHeight = ActivationHeightDisabled
+	return cts
 }
 
 // DevnetChainConstants provide sane defaults for a new devnet chain. Not all constants
@@ -271,11 +369,13 @@ func DevnetChainConstants() ChainConstants {
 	// enough that there isn't much time wasted on waiting for things to
 	// happen.
 	cts := ChainConstants{
-		BlockSizeLimit:         2e6,
-		ArbitraryDataSizeLimit: 83,
-		RootDepth:              Target{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
-		BlockCreatorFee:        currencyUnits.OneCoin.Mul64(10),
-		MinimumTransactionFee:  currencyUnits.OneCoin.Mul64(1),
+		BlockSizeLimit:          2e6,
+		ArbitraryDataSizeLimit:  83,
+		ExtensionDataSizeLimit:  1024,
+		OutputMetadataSizeLimit: 64,
+		RootDepth:               Target{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		BlockCreatorFee:         currencyUnits.OneCoin.Mul64(10),
+		MinimumTransactionFee:   currencyUnits.OneCoin.Mul64(1),
 		// 12 seconds, slow enough for developers to see
 		// ~each block, fast enough that blocks don't waste time
 		BlockFrequency: 12,
@@ -298,6 +398,7 @@ func DevnetChainConstants() ChainConstants {
 		GenesisTransactionVersion: genesisTxnVersion,
 		DefaultTransactionVersion: defaultTxnVersion,
 		GenesisTimestamp:          Timestamp(1424139000),
+		Bech32AddressPrefix:       "rivd",
 		CurrencyUnits:             currencyUnits,
 		TransactionPool:           DefaultTransactionPoolConstants(),
 	}
@@ -328,6 +429,14 @@ func (c *ChainConstants) Validate() error {
 	if c.GenesisTimestamp < Timestamp(1231006505) {
 		return errors.New("Invalid genesis timestamp")
 	}
+	if c.Bech32AddressPrefix != "" {
+		if err := bech32.ValidateHRP(c.Bech32AddressPrefix); err != nil {
+			return fmt.Errorf("Invalid bech32 address prefix: %v", err)
+		}
+	}
+	if err := c.CurrencyUnits.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
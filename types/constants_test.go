@@ -23,3 +23,48 @@ func TestCheckBuildConstants(t *testing.T) {
 		t.Error(build.DEBUG)
 	}
 }
+
+func TestCurrencyUnits(t *testing.T) {
+	for _, precision := range []uint{0, 6, 9, 18} {
+		units := NewCurrencyUnits(precision)
+		if p := units.Precision(); p != precision {
+			t.Errorf("expected precision %d, not %d", precision, p)
+		}
+		if err := units.Validate(); err != nil {
+			t.Errorf("units of precision %d should be valid: %v", precision, err)
+		}
+	}
+	if DefaultCurrencyUnits().Precision() != DefaultCurrencyPrecision {
+		t.Error("unexpected default currency precision")
+	}
+	for _, oneCoin := range []uint64{0, 2, 20, 1001, 1100} {
+		units := CurrencyUnits{OneCoin: NewCurrency64(oneCoin)}
+		if err := units.Validate(); err == nil {
+			t.Errorf("one coin of %d shouldn't be valid", oneCoin)
+		}
+	}
+}
+
+func TestSupplyStatistics(t *testing.T) {
+	cts := ChainConstants{
+		GenesisCoinDistribution:     []CoinOutput{{Value: NewCurrency64(100)}},
+		GenesisBlockStakeAllocation: []BlockStakeOutput{{Value: NewCurrency64(10)}},
+	}
+	stats := cts.SupplyStatistics(SupplyTotals{
+		Coins:             NewCurrency64(130),
+		BlockStakes:       NewCurrency64(10),
+		BlockCreatorFees:  NewCurrency64(20),
+		BurnedCoins:       NewCurrency64(5),
+		BurnedBlockStakes: NewCurrency64(1),
+	})
+	if !stats.GenesisCoins.Equals64(100) || !stats.MintedCoins.Equals64(10) || !stats.DestroyedCoins.IsZero() {
+		t.Errorf("unexpected coin supply statistics: %v", stats)
+	}
+	if !stats.CirculatingCoins.Equals64(125) || !stats.CirculatingBlockStakes.Equals64(9) {
+		t.Errorf("unexpected circulating supply: %v", stats)
+	}
+	stats = cts.SupplyStatistics(SupplyTotals{Coins: NewCurrency64(110), BlockCreatorFees: NewCurrency64(20)})
+	if !stats.MintedCoins.IsZero() || !stats.DestroyedCoins.Equals64(10) {
+		t.Errorf("unexpected net destroyed coins: %v", stats)
+	}
+}
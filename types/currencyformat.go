@@ -0,0 +1,168 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// currency format errors
+var (
+	// ErrInvalidCurrencyString is returned in case a string
+	// cannot be parsed as a currency value.
+	ErrInvalidCurrencyString = errors.New("invalid currency string")
+	// ErrCurrencyTooPrecise is returned in case a currency string
+	// defines more decimals than the smallest unit allows.
+	ErrCurrencyTooPrecise = errors.New("currency value is too precise")
+	// ErrUnknownCurrencyUnit is returned in case a currency string
+	// is suffixed with an unknown unit.
+	ErrUnknownCurrencyUnit = errors.New("unknown currency unit")
+)
+
+// currencyUnitPrefixes are the SI prefixes which can be used in front of the coin unit,
+// mapped to their power of ten, relative to the coin unit.
+var currencyUnitPrefixes = map[string]int{
+	"G": 9,
+	"M": 6,
+	"k": 3,
+	"":  0,
+	"m": -3,
+	"u": -6,
+	"µ": -6,
+	"n": -9,
+}
+
+// CurrencyFormat is used to parse and format currency values,
+// as human-readable strings expressed in the coin unit of a chain (e.g. "1.5 TFT").
+type CurrencyFormat struct {
+	// Units defines the size of one coin.
+	Units CurrencyUnits
+	// UnitName is the name of the coin unit (e.g. "TFT").
+	UnitName string
+}
+
+// NewCurrencyFormat creates a new currency format, using the given units and coin unit name.
+func NewCurrencyFormat(units CurrencyUnits, unitName string) CurrencyFormat {
+	return CurrencyFormat{
+		Units:    units,
+		UnitName: unitName,
+	}
+}
+
+// Precision returns the amount of decimals (digits after the decimal point)
+// a value expressed in the coin unit can have.
+func (f CurrencyFormat) Precision() uint {
+	return f.Units.Precision()
+}
+
+// Parse parses a currency value, optionally suffixed with the (SI-prefixed) coin unit,
+// such as "1.5 TFT" or "500 mTFT". A value without unit is expressed in the smallest unit,
+// in which case it has to be a natural number (e.g. "1000000000").
+func (f CurrencyFormat) Parse(str string) (Currency, error) {
+	return f.parse(str, -int(f.Precision()))
+}
+
+// ParseCoins parses a currency value, optionally suffixed with the (SI-prefixed) coin unit,
+// such as "1.5 TFT" or "500 mTFT", or with a sub-unit SI prefix only, such as "500m".
+// A value without unit is expressed in the coin unit (e.g. "1.5").
+func (f CurrencyFormat) ParseCoins(str string) (Currency, error) {
+	return f.parse(str, 0)
+}
+
+func (f CurrencyFormat) parse(str string, defaultExponent int) (Currency, error) {
+	str = strings.TrimSpace(str)
+	idx := strings.IndexFunc(str, func(r rune) bool {
+		return r != '.' && !unicode.IsDigit(r)
+	})
+	number, unit := str, ""
+	if idx >= 0 {
+		number, unit = str[:idx], strings.TrimSpace(str[idx:])
+	}
+	exponent := defaultExponent
+	if unit != "" {
+		prefix, ok := f.unitPrefix(unit)
+		if !ok {
+			return Currency{}, fmt.Errorf("%v: %q", ErrUnknownCurrencyUnit, unit)
+		}
+		exponent = prefix
+	}
+	exponent += int(f.Precision())
+	if exponent < 0 {
+		return Currency{}, ErrCurrencyTooPrecise
+	}
+	i, err := parseDecimal(number, uint(exponent))
+	if err != nil {
+		return Currency{}, err
+	}
+	return NewCurrency(i), nil
+}
+
+// unitPrefix returns the power of ten of the given unit, relative to the coin unit,
+// the unit being the (SI-prefixed) coin unit (e.g. "mTFT") or a sub-unit SI prefix only (e.g. "m").
+// The multiplying prefixes (e.g. "M") require the coin unit to follow,
+// as a value such as "10M" is too easily confused with "10m".
+func (f CurrencyFormat) unitPrefix(unit string) (int, bool) {
+	if f.UnitName != "" && len(unit) >= len(f.UnitName) && strings.EqualFold(unit[len(unit)-len(f.UnitName):], f.UnitName) {
+		if prefix, ok := currencyUnitPrefixes[unit[:len(unit)-len(f.UnitName)]]; ok {
+			return prefix, true
+		}
+	}
+	prefix, ok := currencyUnitPrefixes[unit]
+	return prefix, ok && prefix < 0
+}
+
+// parseDecimal parses a positive decimal number, multiplied by 10^exponent,
+// returning an error in case the result isn't a natural number.
+func parseDecimal(str string, exponent uint) (*big.Int, error) {
+	if str == "" || str == "." {
+		return nil, ErrInvalidCurrencyString
+	}
+	for _, r := range strings.Replace(str, ".", "", 1) {
+		if r < '0' || r > '9' {
+			return nil, ErrInvalidCurrencyString
+		}
+	}
+	parts := strings.SplitN(str, ".", 2)
+	whole, decimals := parts[0], ""
+	if len(parts) == 2 {
+		decimals = strings.TrimRight(parts[1], "0")
+	}
+	if uint(len(decimals)) > exponent {
+		return nil, ErrCurrencyTooPrecise
+	}
+	digits := whole + decimals + strings.Repeat("0", int(exponent)-len(decimals))
+	i, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, ErrInvalidCurrencyString
+	}
+	return i, nil
+}
+
+// FormatCoins formats the currency value expressed in the coin unit, without unit (e.g. "1.5").
+// Trailing zero decimals are omitted.
+func (f CurrencyFormat) FormatCoins(c Currency) string {
+	str := c.String()
+	precision := int(f.Precision())
+	if precision == 0 || c.IsZero() {
+		return str
+	}
+	if len(str) <= precision {
+		str = strings.Repeat("0", precision-len(str)+1) + str
+	}
+	idx := len(str) - precision
+	decimals := strings.TrimRight(str[idx:], "0")
+	if decimals == "" {
+		return str[:idx]
+	}
+	return str[:idx] + "." + decimals
+}
+
+// Format formats the currency value expressed in the coin unit, suffixed with the unit (e.g. "1.5 TFT").
+func (f CurrencyFormat) Format(c Currency) string {
+	if f.UnitName == "" {
+		return f.FormatCoins(c)
+	}
+	return f.FormatCoins(c) + " " + f.UnitName
+}
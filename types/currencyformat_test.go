@@ -0,0 +1,117 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCurrencyFormatParse(t *testing.T) {
+	format := NewCurrencyFormat(DefaultCurrencyUnits(), "TFT")
+	testCases := []struct {
+		Input    string
+		Coins    bool
+		Expected uint64
+	}{
+		{"1.5 TFT", false, 1500000000},
+		{"1.5TFT", false, 1500000000},
+		{"1.5 tft", false, 1500000000},
+		{"500 mTFT", false, 500000000},
+		{"2 kTFT", false, 2000000000000},
+		{"3 nTFT", false, 3},
+		{"1.5 uTFT", false, 1500},
+		{"1000000000", false, 1000000000},
+		{"1000000000", true, 1000000000000000000},
+		{"1.5", true, 1500000000},
+		{".5", true, 500000000},
+		{"1.", true, 1000000000},
+		{"1.123456789000", true, 1123456789},
+		{" 42 TFT ", true, 42000000000},
+		{"250m", true, 250000000},
+		{"10m", true, 10000000},
+		{"1.5u", true, 1500},
+		{"1.5µ", true, 1500},
+		{"3n", false, 3},
+	}
+	for idx, testCase := range testCases {
+		parse := format.Parse
+		if testCase.Coins {
+			parse = format.ParseCoins
+		}
+		c, err := parse(testCase.Input)
+		if err != nil {
+			t.Errorf("#%d: failed to parse %q: %v", idx, testCase.Input, err)
+			continue
+		}
+		if !c.Equals64(testCase.Expected) {
+			t.Errorf("#%d: %q parsed as %s, expected %d", idx, testCase.Input, c.String(), testCase.Expected)
+		}
+	}
+}
+
+func TestCurrencyFormatParseInvalid(t *testing.T) {
+	format := NewCurrencyFormat(DefaultCurrencyUnits(), "TFT")
+	testCases := []struct {
+		Input string
+		Err   error
+	}{
+		{"", ErrInvalidCurrencyString},
+		{".", ErrInvalidCurrencyString},
+		{"1..0", ErrInvalidCurrencyString},
+		{"-1", ErrUnknownCurrencyUnit},
+		{"1 BTC", ErrUnknownCurrencyUnit},
+		{"1 xTFT", ErrUnknownCurrencyUnit},
+		{"1 x", ErrUnknownCurrencyUnit},
+		{"1 mm", ErrUnknownCurrencyUnit},
+		// the multiplying SI prefixes require the coin unit to follow
+		{"1.5k", ErrUnknownCurrencyUnit},
+		{"10M", ErrUnknownCurrencyUnit},
+		{"10 G", ErrUnknownCurrencyUnit},
+		{"1.5", ErrCurrencyTooPrecise},
+		{"0.1 nTFT", ErrCurrencyTooPrecise},
+	}
+	for idx, testCase := range testCases {
+		_, err := format.Parse(testCase.Input)
+		if err == nil {
+			t.Errorf("#%d: expected %q to fail", idx, testCase.Input)
+			continue
+		}
+		if err != testCase.Err && !strings.HasPrefix(err.Error(), testCase.Err.Error()) {
+			t.Errorf("#%d: unexpected error for %q: %v", idx, testCase.Input, err)
+		}
+	}
+}
+
+func TestCurrencyFormatFormat(t *testing.T) {
+	format := NewCurrencyFormat(DefaultCurrencyUnits(), "TFT")
+	testCases := []struct {
+		Value    uint64
+		Expected string
+	}{
+		{0, "0 TFT"},
+		{1, "0.000000001 TFT"},
+		{1500000000, "1.5 TFT"},
+		{1000000000, "1 TFT"},
+		{123456789987654321, "123456789.987654321 TFT"},
+	}
+	for idx, testCase := range testCases {
+		c := NewCurrency64(testCase.Value)
+		if str := format.Format(c); str != testCase.Expected {
+			t.Errorf("#%d: %q != %q", idx, str, testCase.Expected)
+		}
+		parsed, err := format.Parse(format.Format(c))
+		if err != nil {
+			t.Errorf("#%d: failed to parse formatted value: %v", idx, err)
+		} else if !parsed.Equals(c) {
+			t.Errorf("#%d: %s != %s", idx, parsed.String(), c.String())
+		}
+	}
+
+	// a precision of zero doesn't allow decimals
+	format = NewCurrencyFormat(CurrencyUnits{OneCoin: NewCurrency64(1)}, "")
+	if str := format.Format(NewCurrency64(42)); str != "42" {
+		t.Errorf("unexpected format: %q", str)
+	}
+	if _, err := format.ParseCoins("4.2"); err != ErrCurrencyTooPrecise {
+		t.Errorf("expected too precise error, not: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// double spend proof errors
+var (
+	// ErrNoDoubleSpend is returned in case a double spend proof is created or verified
+	// for two transactions which do not spend the same parent output.
+	ErrNoDoubleSpend = errors.New("transactions do not spend the same parent output")
+	// ErrIdenticalTransactions is returned in case a double spend proof is created or verified
+	// for two transactions which are identical.
+	ErrIdenticalTransactions = errors.New("a transaction cannot double spend with itself")
+)
+
+// DoubleSpendProof proves that the owner of an output signed two different transactions,
+// both spending that same (parent) output. It can be verified offline,
+// as it contains the condition of the parent output, against which
+// the fulfillments of both transactions are verified.
+//
+// Note that a proof can not prove that the given condition is the actual condition
+// of the parent output, which can be looked up on chain (e.g. using an explorer) if desired.
+type DoubleSpendProof struct {
+	// ParentID is the ID of the (coin or block stake) output spent by both transactions.
+	ParentID OutputID `json:"parentid"`
+	// ParentCondition is the condition of the output spent by both transactions.
+	ParentCondition UnlockConditionProxy `json:"parentcondition"`
+	// Transactions are the two (different) transactions spending the parent output.
+	Transactions [2]Transaction `json:"transactions"`
+	// ChainID of the chain both transactions belong to,
+	// only required to verify replay-protected transactions.
+	ChainID ChainID `json:"chainid"`
+}
+
+// NewDoubleSpendProof creates a proof that the two given signed transactions
+// of the chain identified by the given ChainID spend the same parent output,
+// which has to be owned by the given condition.
+// An error is returned in case the proof couldn't be verified.
+func NewDoubleSpendProof(a, b Transaction, parentCondition UnlockConditionProxy, chainID ChainID) (DoubleSpendProof, error) {
+	parentID, ok := FindDoubleSpend(a, b)
+	if !ok {
+		return DoubleSpendProof{}, ErrNoDoubleSpend
+	}
+	proof := DoubleSpendProof{
+		ParentID:        parentID,
+		ParentCondition: parentCondition,
+		Transactions:    [2]Transaction{a, b},
+		ChainID:         chainID,
+	}
+	err := proof.Verify()
+	if err != nil {
+		return DoubleSpendProof{}, err
+	}
+	return proof, nil
+}
+
+// FindDoubleSpend returns the ID of the first (coin or block stake) parent output
+// spent by both transactions, false if there is no such output.
+func FindDoubleSpend(a, b Transaction) (OutputID, bool) {
+	coinParents := make(map[CoinOutputID]struct{}, len(a.CoinInputs))
+	for _, ci := range a.CoinInputs {
+		coinParents[ci.ParentID] = struct{}{}
+	}
+	for _, ci := range b.CoinInputs {
+		if _, ok := coinParents[ci.ParentID]; ok {
+			return OutputID(ci.ParentID), true
+		}
+	}
+	blockStakeParents := make(map[BlockStakeOutputID]struct{}, len(a.BlockStakeInputs))
+	for _, bsi := range a.BlockStakeInputs {
+		blockStakeParents[bsi.ParentID] = struct{}{}
+	}
+	for _, bsi := range b.BlockStakeInputs {
+		if _, ok := blockStakeParents[bsi.ParentID]; ok {
+			return OutputID(bsi.ParentID), true
+		}
+	}
+	return OutputID{}, false
+}
+
+// Verify the double spend proof, returning an error if the proof is invalid.
+// A proof is valid if both transactions are different, and each of them
+// contains an input spending the parent output, fulfilling the parent condition.
+//
+// As the proof is verified offline, lock times are considered to be reached.
+func (p DoubleSpendProof) Verify() error {
+	if p.Transactions[0].ID() == p.Transactions[1].ID() {
+		return ErrIdenticalTransactions
+	}
+	for idx, txn := range p.Transactions {
+		fulfillment, index, ok := findParentFulfillment(txn, p.ParentID)
+		if !ok {
+			return ErrNoDoubleSpend
+		}
+		err := p.ParentCondition.Fulfill(fulfillment, FulfillContext{
+			ExtraObjects: []interface{}{uint64(index)},
+			BlockHeight:  BlockHeight(math.MaxUint64),
+			BlockTime:    Timestamp(math.MaxUint64),
+			Transaction:  txn,
+			OutputOrigin: &OutputOrigin{},
+			ChainID:      p.ChainID,
+		})
+		if err != nil {
+			return fmt.Errorf("transaction #%d does not fulfill the parent condition: %v", idx+1, err)
+		}
+	}
+	return nil
+}
+
+// findParentFulfillment returns the fulfillment and index of the (coin or block stake)
+// input of the transaction which spends the given parent output.
+func findParentFulfillment(txn Transaction, parentID OutputID) (UnlockFulfillmentProxy, int, bool) {
+	for index, ci := range txn.CoinInputs {
+		if OutputID(ci.ParentID) == parentID {
+			return ci.Fulfillment, index, true
+		}
+	}
+	for index, bsi := range txn.BlockStakeInputs {
+		if OutputID(bsi.ParentID) == parentID {
+			return bsi.Fulfillment, index, true
+		}
+	}
+	return UnlockFulfillmentProxy{}, 0, false
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+func TestDoubleSpendProof(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair()
+	uh, err := NewPubKeyUnlockHash(Ed25519PublicKey(pk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := NewCondition(NewUnlockHashCondition(uh))
+	parentID := CoinOutputID{42}
+
+	signedSpend := func(receiver byte) Transaction {
+		txn := Transaction{
+			Version: TransactionVersionOne,
+			CoinInputs: []CoinInput{
+				// an unrelated input, unique to each transaction
+				{ParentID: CoinOutputID{100 + receiver}},
+				{ParentID: parentID, Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(Ed25519PublicKey(pk)))},
+			},
+			CoinOutputs: []CoinOutput{{
+				Value:     NewCurrency64(10),
+				Condition: NewCondition(NewUnlockHashCondition(NewUnlockHash(UnlockTypePubKey, crypto.Hash{receiver}))),
+			}},
+		}
+		err := txn.CoinInputs[1].Fulfillment.Sign(FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(1)},
+			Transaction:  txn,
+			Key:          sk,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return txn
+	}
+	a, b := signedSpend(1), signedSpend(2)
+
+	if id, ok := FindDoubleSpend(a, b); !ok || id != OutputID(parentID) {
+		t.Errorf("unexpected double spend: %v (%v)", id, ok)
+	}
+	proof, err := NewDoubleSpendProof(a, b, condition, ChainID{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = proof.Verify(); err != nil {
+		t.Errorf("proof should be valid: %v", err)
+	}
+
+	if _, err = NewDoubleSpendProof(a, a, condition, ChainID{}); err != ErrIdenticalTransactions {
+		t.Errorf("expected identical transactions error, not: %v", err)
+	}
+	if _, err = NewDoubleSpendProof(a, Transaction{Version: TransactionVersionOne}, condition, ChainID{}); err != ErrNoDoubleSpend {
+		t.Errorf("expected no double spend error, not: %v", err)
+	}
+	// the fulfillments have to fulfill the parent condition
+	otherCondition := NewCondition(NewUnlockHashCondition(NewUnlockHash(UnlockTypePubKey, crypto.Hash{3})))
+	if _, err = NewDoubleSpendProof(a, b, otherCondition, ChainID{}); err == nil {
+		t.Error("proof with a foreign parent condition shouldn't be valid")
+	}
+	// a tampered transaction invalidates the proof
+	proof.Transactions[1].CoinOutputs[0].Value = NewCurrency64(11)
+	if err = proof.Verify(); err == nil {
+		t.Error("proof with a tampered transaction shouldn't be valid")
+	}
+}
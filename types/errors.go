@@ -1,6 +1,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -77,3 +78,194 @@ func NewClientError(err error, kind ClientErrorKind) ClientError {
 func (ce ClientError) Error() string {
 	return fmt.Sprintf("User Error %s: %v", ce.Kind, ce.Err)
 }
+
+// Unwrap returns the error wrapped by the client error.
+func (ce ClientError) Unwrap() error {
+	return ce.Err
+}
+
+// ErrorCode is a stable code identifying a (validation) error,
+// allowing clients to program against failures, rather than against error messages.
+// Codes are grouped per category, and are never renumbered nor reused.
+type ErrorCode uint32
+
+// The error codes of the errors defined by this package.
+const (
+	// ErrorCodeUnknown is the code of any error which does not define a code.
+	ErrorCodeUnknown ErrorCode = 0
+
+	// signature and unlock hash errors (1xx)
+
+	ErrorCodeInvalidUnlockHashChecksum           ErrorCode = 100
+	ErrorCodeUnlockHashWrongLen                  ErrorCode = 101
+	ErrorCodeUnknownSignAlgorithmType            ErrorCode = 102
+	ErrorCodeInvalidSignature                    ErrorCode = 103
+	ErrorCodeNilPublicKey                        ErrorCode = 104
+	ErrorCodeMissingAggregatedBLSSignature       ErrorCode = 105
+	ErrorCodeAggregatedBLSSignatureRequiresBatch ErrorCode = 106
+
+	// transaction errors (2xx)
+
+	ErrorCodeInvalidTransactionVersion            ErrorCode = 200
+	ErrorCodeTransactionIDWrongLen                ErrorCode = 201
+	ErrorCodeUnknownTransactionType               ErrorCode = 202
+	ErrorCodeDoubleSpend                          ErrorCode = 203
+	ErrorCodeNonZeroRevision                      ErrorCode = 204
+	ErrorCodeTransactionTooLarge                  ErrorCode = 205
+	ErrorCodeTooSmallMinerFee                     ErrorCode = 206
+	ErrorCodeZeroOutput                           ErrorCode = 207
+	ErrorCodeArbitraryDataTooLarge                ErrorCode = 208
+	ErrorCodeCoinInputOutputMismatch              ErrorCode = 209
+	ErrorCodeBlockStakeInputOutputMismatch        ErrorCode = 210
+	ErrorCodeMissingMinerFee                      ErrorCode = 211
+	ErrorCodeInvalidTransactionExtensionData      ErrorCode = 212
+	ErrorCodeTransactionExtensionDataTooLarge     ErrorCode = 213
+	ErrorCodeTransactionExtensionDataNotSupported ErrorCode = 214
+	ErrorCodeOutputMetadataNotSupported           ErrorCode = 215
+	ErrorCodeOutputMetadataNotEnabled             ErrorCode = 216
+	ErrorCodeOutputMetadataTooLarge               ErrorCode = 217
+	ErrorCodeUnknownParentOutput                  ErrorCode = 218
+	ErrorCodeMissingParentID                      ErrorCode = 219
+	ErrorCodeUnknownChainID                       ErrorCode = 220
+
+	// unlock condition and fulfillment errors (3xx)
+
+	ErrorCodeUnexpectedUnlockCondition   ErrorCode = 300
+	ErrorCodeUnexpectedUnlockFulfillment ErrorCode = 301
+	ErrorCodeUnexpectedUnlockType        ErrorCode = 302
+	ErrorCodeFulfillmentDoubleSign       ErrorCode = 303
+	ErrorCodeUnknownConditionType        ErrorCode = 304
+	ErrorCodeUnknownFulfillmentType      ErrorCode = 305
+	ErrorCodeNilFulfillmentType          ErrorCode = 306
+	ErrorCodeInsufficientSignatures      ErrorCode = 307
+	ErrorCodeUnauthorizedPubKey          ErrorCode = 308
+	ErrorCodePrematureRefund             ErrorCode = 309
+	ErrorCodeInvalidColdStakingRespend   ErrorCode = 310
+	ErrorCodeExpiredClaim                ErrorCode = 311
+	ErrorCodeBurnedOutput                ErrorCode = 312
+	ErrorCodeCompositeConditionTooDeep   ErrorCode = 313
+	ErrorCodeInvalidPreImageSha256       ErrorCode = 314
+	ErrorCodeInvalidRedeemer             ErrorCode = 315
+	ErrorCodeWrongPublicKey              ErrorCode = 316
+	ErrorCodeUnlockHashMismatch          ErrorCode = 317
+	ErrorCodeLockTimeNotReached          ErrorCode = 318
+	ErrorCodeUnknownOutputOrigin         ErrorCode = 319
+	ErrorCodeUnknownSpentCoinOutputs     ErrorCode = 320
+	ErrorCodeConditionTypeNotActive      ErrorCode = 321
+	ErrorCodeSignatureAlgorithmNotActive ErrorCode = 322
+
+	// wallet errors (4xx)
+
+	ErrorCodeWalletLocked ErrorCode = 400
+)
+
+// Error is an error identified by a stable ErrorCode.
+// Error values are comparable, such that the errors predefined
+// by this package can be compared using the equality operator.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// NewError creates a new error, identified by the given code.
+func NewError(code ErrorCode, message string) error {
+	return Error{Code: code, Message: message}
+}
+
+// Error implements error.Error
+func (e Error) Error() string {
+	return e.Message
+}
+
+// ErrorCodeOf returns the code of the given error,
+// looking through any error wrapping it (such as an InputError or ClientError).
+// ErrorCodeUnknown is returned in case the error does not define a code.
+func ErrorCodeOf(err error) ErrorCode {
+	var e Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrorCodeUnknown
+}
+
+// InputType identifies the type of a transaction input.
+type InputType string
+
+// The types of transaction inputs.
+const (
+	InputTypeCoin       InputType = "coin"
+	InputTypeBlockStake InputType = "blockstake"
+)
+
+// InputErrorContext identifies the transaction input
+// which caused an InputError, as well as its fulfillment,
+// and the condition of its parent output if known.
+type InputErrorContext struct {
+	Type            InputType       `json:"type"`
+	Index           uint64          `json:"index"`
+	ParentID        OutputID        `json:"parentid"`
+	FulfillmentType FulfillmentType `json:"fulfillmenttype"`
+	// ConditionType is nil in case the parent output is unknown.
+	ConditionType *ConditionType `json:"conditiontype,omitempty"`
+}
+
+// InputError is an error caused by a (coin or block stake) input of a transaction,
+// adding the context of that input to the error which caused it.
+type InputError struct {
+	Err error
+	InputErrorContext
+}
+
+// Error implements error.Error
+func (ie InputError) Error() string {
+	if ie.ConditionType == nil {
+		return fmt.Sprintf("%s input #%d (parent %s, fulfillment type %d): %v",
+			ie.Type, ie.Index, ie.ParentID.String(), ie.FulfillmentType, ie.Err)
+	}
+	return fmt.Sprintf("%s input #%d (parent %s, fulfillment type %d, condition type %d): %v",
+		ie.Type, ie.Index, ie.ParentID.String(), ie.FulfillmentType, *ie.ConditionType, ie.Err)
+}
+
+// Unwrap returns the error which caused the input error.
+func (ie InputError) Unwrap() error {
+	return ie.Err
+}
+
+// NewCoinInputError creates an InputError for the coin input at the given index,
+// optionally defining the condition of its parent output.
+func NewCoinInputError(err error, index int, ci CoinInput, condition *UnlockConditionProxy) error {
+	return newInputError(err, InputTypeCoin, index, OutputID(ci.ParentID), ci.Fulfillment, condition)
+}
+
+// NewBlockStakeInputError creates an InputError for the block stake input at the given index,
+// optionally defining the condition of its parent output.
+func NewBlockStakeInputError(err error, index int, bsi BlockStakeInput, condition *UnlockConditionProxy) error {
+	return newInputError(err, InputTypeBlockStake, index, OutputID(bsi.ParentID), bsi.Fulfillment, condition)
+}
+
+func newInputError(err error, inputType InputType, index int, parentID OutputID, fulfillment UnlockFulfillmentProxy, condition *UnlockConditionProxy) error {
+	ie := InputError{
+		Err: err,
+		InputErrorContext: InputErrorContext{
+			Type:            inputType,
+			Index:           uint64(index),
+			ParentID:        parentID,
+			FulfillmentType: fulfillment.FulfillmentType(),
+		},
+	}
+	if condition != nil {
+		ct := condition.ConditionType()
+		ie.ConditionType = &ct
+	}
+	return ie
+}
+
+// InputErrorContextOf returns the context of the input
+// which caused the given error, if it was caused by an input.
+func InputErrorContextOf(err error) (InputErrorContext, bool) {
+	var ie InputError
+	if errors.As(err, &ie) {
+		return ie.InputErrorContext, true
+	}
+	return InputErrorContext{}, false
+}
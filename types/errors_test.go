@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	testCases := []struct {
+		Error        error
+		ExpectedCode ErrorCode
+	}{
+		{nil, ErrorCodeUnknown},
+		{fmt.Errorf("some error"), ErrorCodeUnknown},
+		{ErrDoubleSpend, ErrorCodeDoubleSpend},
+		{ErrInvalidSignature, ErrorCodeInvalidSignature},
+		{fmt.Errorf("wrapped: %w", ErrInsufficientSignatures), ErrorCodeInsufficientSignatures},
+		{NewClientError(ErrTooSmallMinerFee, ClientErrorBadRequest), ErrorCodeTooSmallMinerFee},
+		{NewCoinInputError(ErrMissingParentID, 0, CoinInput{}, nil), ErrorCodeMissingParentID},
+	}
+	for idx, testCase := range testCases {
+		code := ErrorCodeOf(testCase.Error)
+		if code != testCase.ExpectedCode {
+			t.Errorf("test case #%d: unexpected error code %d, expected %d", idx, code, testCase.ExpectedCode)
+		}
+	}
+	// sentinel errors remain comparable
+	if err := NewError(ErrorCodeDoubleSpend, ErrDoubleSpend.Error()); err != ErrDoubleSpend {
+		t.Errorf("expected %v to equal %v", err, ErrDoubleSpend)
+	}
+}
+
+func TestInputErrorContextOf(t *testing.T) {
+	if _, ok := InputErrorContextOf(ErrUnlockHashMismatch); ok {
+		t.Fatal("expected no input context for a plain error")
+	}
+
+	condition := NewCondition(NewUnlockHashCondition(UnlockHash{Type: UnlockTypePubKey}))
+	fulfillment := NewFulfillment(NewSingleSignatureFulfillment(PublicKey{Algorithm: SignatureAlgoEd25519}))
+	ci := CoinInput{
+		ParentID:    CoinOutputID{1, 2, 3},
+		Fulfillment: fulfillment,
+	}
+	err := fmt.Errorf("tx is invalid: %w", NewCoinInputError(ErrUnlockHashMismatch, 2, ci, &condition))
+	ctx, ok := InputErrorContextOf(err)
+	if !ok {
+		t.Fatal("expected input context for an input error")
+	}
+	if ctx.Type != InputTypeCoin || ctx.Index != 2 || ctx.ParentID != OutputID(ci.ParentID) {
+		t.Errorf("unexpected input context: %v", ctx)
+	}
+	if ctx.FulfillmentType != FulfillmentTypeSingleSignature {
+		t.Errorf("unexpected fulfillment type: %d", ctx.FulfillmentType)
+	}
+	if ctx.ConditionType == nil || *ctx.ConditionType != ConditionTypeUnlockHash {
+		t.Errorf("unexpected condition type: %v", ctx.ConditionType)
+	}
+	if code := ErrorCodeOf(err); code != ErrorCodeUnlockHashMismatch {
+		t.Errorf("unexpected error code %d", code)
+	}
+
+	b, jerr := json.Marshal(ctx)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var decoded InputErrorContext
+	if jerr = json.Unmarshal(b, &decoded); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if decoded.ConditionType == nil || *decoded.ConditionType != ConditionTypeUnlockHash || decoded.Index != 2 {
+		t.Errorf("unexpected JSON-decoded input context: %s", string(b))
+	}
+
+	// the condition type is omitted if the parent output is unknown
+	ctx, _ = InputErrorContextOf(NewBlockStakeInputError(ErrUnknownParentOutput, 0, BlockStakeInput{}, nil))
+	if ctx.Type != InputTypeBlockStake || ctx.ConditionType != nil {
+		t.Errorf("unexpected input context: %v", ctx)
+	}
+}
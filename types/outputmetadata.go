@@ -0,0 +1,159 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+const (
+	// TransactionVersionThree defines the output metadata transaction version.
+	// It is identical to TransactionVersionOne, except that each coin and block stake output
+	// can carry a small metadata field (e.g. a payment reference), which is committed on-chain.
+	//
+	// Transactions of this version are only accepted by chains which define
+	// an output metadata size limit, see ChainConstants.OutputMetadataSizeLimit.
+	TransactionVersionThree TransactionVersion = 3
+)
+
+// Output metadata errors
+var (
+	// ErrOutputMetadataNotSupported is returned in case a transaction
+	// defines output metadata, while its version doesn't support it.
+	ErrOutputMetadataNotSupported = NewError(ErrorCodeOutputMetadataNotSupported, "transaction version does not support output metadata")
+	// ErrOutputMetadataNotEnabled is returned in case an output metadata transaction
+	// is used on a chain which doesn't accept output metadata.
+	ErrOutputMetadataNotEnabled = NewError(ErrorCodeOutputMetadataNotEnabled, "output metadata transactions are not enabled on this chain")
+	// ErrOutputMetadataTooLarge is returned in case
+	// the metadata of an output exceeds the size limit.
+	ErrOutputMetadataTooLarge = NewError(ErrorCodeOutputMetadataTooLarge, "output metadata is too large")
+)
+
+// HasOutputMetadata returns true if any coin or block stake output of the transaction defines metadata.
+func (t Transaction) HasOutputMetadata() bool {
+	return transactionDataHasOutputMetadata(t.CoinOutputs, t.BlockStakeOutputs)
+}
+
+// ValidateOutputMetadata validates the metadata of all coin and block stake outputs
+// of a transaction, ensuring that the transaction version supports output metadata
+// and that the metadata of each output fits within the given size limit.
+func ValidateOutputMetadata(t Transaction, sizeLimit uint64) error {
+	if t.Version != TransactionVersionThree {
+		if t.HasOutputMetadata() {
+			return ErrOutputMetadataNotSupported
+		}
+		return nil
+	}
+	if sizeLimit == 0 {
+		return ErrOutputMetadataNotEnabled
+	}
+	for idx, co := range t.CoinOutputs {
+		if uint64(len(co.Metadata)) > sizeLimit {
+			return fmt.Errorf("coin output #%d: %v", idx, ErrOutputMetadataTooLarge)
+		}
+	}
+	for idx, bso := range t.BlockStakeOutputs {
+		if uint64(len(bso.Metadata)) > sizeLimit {
+			return fmt.Errorf("block stake output #%d: %v", idx, ErrOutputMetadataTooLarge)
+		}
+	}
+	return nil
+}
+
+// OutputMetadataTransactionController is the transaction controller
+// used for the output metadata TransactionVersionThree.
+// It encodes transactions the same way as the DefaultTransactionController does,
+// appending the metadata of all coin and block stake outputs.
+// Extension data is not supported by this version.
+type OutputMetadataTransactionController struct {
+	DefaultTransactionController
+}
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (omtc OutputMetadataTransactionController) EncodeTransactionData(w io.Writer, td TransactionData) error {
+	if len(td.ExtensionData) > 0 {
+		return ErrTransactionExtensionDataNotSupported
+	}
+	coinOutputMetadata, blockStakeOutputMetadata := outputMetadataOf(td.CoinOutputs, td.BlockStakeOutputs)
+	b, err := siabin.MarshalAll(td, coinOutputMetadata, blockStakeOutputMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to (siabin) marshal output metadata transaction data: %v", err)
+	}
+	// copy those bytes together with its prefixed length, as the final encoding
+	return siabin.NewEncoder(w).Encode(b)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (omtc OutputMetadataTransactionController) DecodeTransactionData(r io.Reader) (td TransactionData, err error) {
+	// decode it as a byte slice first
+	var b []byte
+	err = siabin.NewDecoder(r).Decode(&b)
+	if err != nil {
+		return
+	}
+	br := bytes.NewReader(b)
+	var coinOutputMetadata, blockStakeOutputMetadata [][]byte
+	err = siabin.NewDecoder(br).DecodeAll(&td, &coinOutputMetadata, &blockStakeOutputMetadata)
+	if err != nil {
+		return
+	}
+	if br.Len() > 0 {
+		err = fmt.Errorf("output metadata transaction data has %d trailing bytes", br.Len())
+		return
+	}
+	if len(coinOutputMetadata) != len(td.CoinOutputs) || len(blockStakeOutputMetadata) != len(td.BlockStakeOutputs) {
+		err = errors.New("output metadata does not match the outputs of the transaction")
+		return
+	}
+	for idx := range td.CoinOutputs {
+		td.CoinOutputs[idx].Metadata = coinOutputMetadata[idx]
+	}
+	for idx := range td.BlockStakeOutputs {
+		td.BlockStakeOutputs[idx].Metadata = blockStakeOutputMetadata[idx]
+	}
+	return
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (omtc OutputMetadataTransactionController) JSONEncodeTransactionData(td TransactionData) ([]byte, error) {
+	if len(td.ExtensionData) > 0 {
+		return nil, ErrTransactionExtensionDataNotSupported
+	}
+	return omtc.DefaultTransactionController.JSONEncodeTransactionData(td)
+}
+
+// ensures at compile time that the Transaction Controller implement all desired interfaces
+var (
+	_ TransactionController = OutputMetadataTransactionController{}
+)
+
+func transactionDataHasOutputMetadata(coinOutputs []CoinOutput, blockStakeOutputs []BlockStakeOutput) bool {
+	for _, co := range coinOutputs {
+		if len(co.Metadata) > 0 {
+			return true
+		}
+	}
+	for _, bso := range blockStakeOutputs {
+		if len(bso.Metadata) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// outputMetadataOf returns the metadata of the given outputs,
+// as slices of the same length as the given outputs.
+func outputMetadataOf(coinOutputs []CoinOutput, blockStakeOutputs []BlockStakeOutput) (coinOutputMetadata, blockStakeOutputMetadata [][]byte) {
+	coinOutputMetadata = make([][]byte, 0, len(coinOutputs))
+	for _, co := range coinOutputs {
+		coinOutputMetadata = append(coinOutputMetadata, co.Metadata)
+	}
+	blockStakeOutputMetadata = make([][]byte, 0, len(blockStakeOutputs))
+	for _, bso := range blockStakeOutputs {
+		blockStakeOutputMetadata = append(blockStakeOutputMetadata, bso.Metadata)
+	}
+	return
+}
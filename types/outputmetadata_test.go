@@ -0,0 +1,94 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+func TestOutputMetadataEncoding(t *testing.T) {
+	txn := manyInputsTransaction(2)
+	v1, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// metadata-less outputs are encoded exactly as before
+	b, err := siabin.Marshal(txn.CoinOutputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn.CoinOutputs[0].Metadata = []byte("invoice 42")
+	bWithMetadata, err := siabin.Marshal(txn.CoinOutputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, bWithMetadata) {
+		t.Error("output metadata shouldn't be part of the output encoding")
+	}
+	if _, err = siabin.Marshal(txn); err != ErrOutputMetadataNotSupported {
+		t.Errorf("expected output metadata not supported error, not: %v", err)
+	}
+
+	txn.Version = TransactionVersionThree
+	v3, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v3) <= len(v1) {
+		t.Error("output metadata should be encoded")
+	}
+	var decoded Transaction
+	if err = siabin.Unmarshal(v3, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.CoinOutputs[0].Metadata, []byte("invoice 42")) || len(decoded.CoinOutputs[1].Metadata) != 0 {
+		t.Errorf("unexpected decoded coin outputs: %v", decoded.CoinOutputs)
+	}
+	if decoded.ID() != txn.ID() {
+		t.Error("decoded transaction should have the same ID")
+	}
+
+	// metadata is covered by the transaction ID and signature hash
+	idWith, hashWith := txn.ID(), mustSignatureHash(t, txn)
+	txn.CoinOutputs[0].Metadata = []byte("invoice 43")
+	if txn.ID() == idWith || mustSignatureHash(t, txn) == hashWith {
+		t.Error("output metadata should be covered by the transaction ID and signature hash")
+	}
+
+	// metadata is part of the JSON encoding
+	jb, err := json.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = Transaction{}
+	if err = json.Unmarshal(jb, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.CoinOutputs[0].Metadata, []byte("invoice 43")) {
+		t.Errorf("unexpected JSON-decoded coin outputs: %v", decoded.CoinOutputs)
+	}
+}
+
+func TestValidateOutputMetadata(t *testing.T) {
+	txn := manyInputsTransaction(1)
+	if err := ValidateOutputMetadata(txn, 0); err != nil {
+		t.Errorf("transaction without metadata should be valid: %v", err)
+	}
+	txn.CoinOutputs[0].Metadata = []byte("ref")
+	if err := ValidateOutputMetadata(txn, 64); err != ErrOutputMetadataNotSupported {
+		t.Errorf("expected output metadata not supported error, not: %v", err)
+	}
+	txn.Version = TransactionVersionThree
+	if err := ValidateOutputMetadata(txn, 0); err != ErrOutputMetadataNotEnabled {
+		t.Errorf("expected output metadata not enabled error, not: %v", err)
+	}
+	if err := ValidateOutputMetadata(txn, 3); err != nil {
+		t.Errorf("metadata within the size limit should be valid: %v", err)
+	}
+	if err := ValidateOutputMetadata(txn, 2); err == nil {
+		t.Error("metadata exceeding the size limit should be invalid")
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+const (
+	// TransactionVersionTwo defines the replay-protected transaction version.
+	// It is identical to TransactionVersionOne, except that the ChainID of the chain
+	// is included in the signature hash of all its inputs, such that a transaction
+	// signed for one chain (network) can never be valid on another chain (network).
+	//
+	// The ChainID isn't registered globally, as a single process can operate on multiple chains,
+	// instead it is given as part of the context used to sign and validate a transaction,
+	// see ChainSignatureHash for more information.
+	TransactionVersionTwo TransactionVersion = 2
+)
+
+// ErrUnknownChainID is returned when the signature hash of a replay-protected
+// transaction is computed, without the ChainID of its chain being known.
+var ErrUnknownChainID = NewError(ErrorCodeUnknownChainID, "replay-protected transaction requires the chain ID to be known")
+
+// SpecifierChainID is the specifier used to compute a ChainID.
+var SpecifierChainID = Specifier{'c', 'h', 'a', 'i', 'n', ' ', 'i', 'd'}
+
+// ChainID uniquely identifies a chain (network), and is used
+// to protect transactions against being replayed on other chains (networks).
+type ChainID crypto.Hash
+
+// ChainID returns the identifier of the chain, derived from
+// its genesis block ID and the (optional) fork identifier.
+func (c *ChainConstants) ChainID() ChainID {
+	h, err := crypto.HashAll(SpecifierChainID, c.GenesisBlockID(), c.ForkIdentifier)
+	if err != nil {
+		panic(err) // never fails, as only fixed-size and string objects are encoded
+	}
+	return ChainID(h)
+}
+
+// String prints the ChainID in hex.
+func (id ChainID) String() string {
+	return crypto.Hash(id).String()
+}
+
+// LoadString loads a ChainID from a hex string.
+func (id *ChainID) LoadString(str string) error {
+	return (*crypto.Hash)(id).LoadString(str)
+}
+
+// MarshalJSON marshals a ChainID as a hex string.
+func (id ChainID) MarshalJSON() ([]byte, error) {
+	return crypto.Hash(id).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the json string of the ChainID.
+func (id *ChainID) UnmarshalJSON(b []byte) error {
+	return (*crypto.Hash)(id).UnmarshalJSON(b)
+}
+
+// ReplayProtectedTransactionController is the transaction controller
+// used for the replay-protected TransactionVersionTwo.
+// It encodes transactions the same way as the DefaultTransactionController does,
+// but includes the ChainID in the signature hash.
+type ReplayProtectedTransactionController struct {
+	DefaultTransactionController
+}
+
+// ChainSignatureHash implements TransactionChainSignatureHasher.ChainSignatureHash
+func (rtc ReplayProtectedTransactionController) ChainSignatureHash(t Transaction, chainID ChainID, extraObjects ...interface{}) (crypto.Hash, error) {
+	if chainID == (ChainID{}) {
+		return crypto.Hash{}, ErrUnknownChainID
+	}
+	h := crypto.NewHash()
+	enc := siabin.NewEncoder(h)
+
+	enc.Encode(t.Version)
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+	// the chain ID is encoded as part of the body shared by all inputs,
+	// such that the SignatureHashCache can still be used for this version
+	enc.Encode(chainID)
+	encodeSignatureHashBody(enc, t)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// ensures at compile time that the Transaction Controller implement all desired interfaces
+var (
+	_ TransactionController           = ReplayProtectedTransactionController{}
+	_ TransactionChainSignatureHasher = ReplayProtectedTransactionController{}
+)
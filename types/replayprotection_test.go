@@ -0,0 +1,162 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+func TestChainID(t *testing.T) {
+	standard, testnet := StandardnetChainConstants(), TestnetChainConstants()
+	if standard.ChainID() == testnet.ChainID() {
+		t.Error("standard and testnet chain IDs should differ")
+	}
+	fork := StandardnetChainConstants()
+	fork.ForkIdentifier = "fork"
+	if standard.ChainID() == fork.ChainID() {
+		t.Error("chain IDs of a chain and its fork should differ")
+	}
+	if other := StandardnetChainConstants(); standard.ChainID() != other.ChainID() {
+		t.Error("chain ID should be deterministic")
+	}
+
+	b, err := standard.ChainID().MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id ChainID
+	if err = id.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if id != standard.ChainID() {
+		t.Errorf("unexpected JSON-decoded chain ID: %s", id.String())
+	}
+}
+
+func TestReplayProtectionActivationHeight(t *testing.T) {
+	// existing networks activate replay protection as a hard fork
+	for name, cts := range map[string]ChainConstants{
+		"standard": StandardnetChainConstants(),
+		"testnet":  TestnetChainConstants(),
+	} {
+		if cts.ReplayProtectionActivationHeight == 0 {
+			t.Errorf("%s: replay protection should only be activated at a future block height", name)
+		}
+	}
+	if cts := DevnetChainConstants(); cts.ReplayProtectionActivationHeight != 0 {
+		t.Errorf("devnet: replay protection should be active from the genesis block on, not from height %d",
+			cts.ReplayProtectionActivationHeight)
+	}
+}
+
+func TestReplayProtectedSignatureHash(t *testing.T) {
+	standard, testnet := StandardnetChainConstants(), TestnetChainConstants()
+	txn := manyInputsTransaction(4)
+	txn.Version = TransactionVersionTwo
+
+	if _, err := txn.SignatureHash(uint64(0)); err != ErrUnknownChainID {
+		t.Errorf("expected unknown chain ID error, not: %v", err)
+	}
+	if _, err := NewSignatureHashCache(txn, ChainID{}).SignatureHash(uint64(0)); err != ErrUnknownChainID {
+		t.Errorf("expected unknown chain ID error from cache, not: %v", err)
+	}
+
+	standardHash, err := txn.ChainSignatureHash(standard.ChainID(), uint64(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewSignatureHashCache(txn, standard.ChainID())
+	for index := range txn.CoinInputs {
+		expected, err := txn.ChainSignatureHash(standard.ChainID(), uint64(index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := cache.SignatureHash(uint64(index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != expected {
+			t.Errorf("input #%d: cached signature hash %s != %s", index, hash.String(), expected.String())
+		}
+	}
+
+	testnetHash, err := txn.ChainSignatureHash(testnet.ChainID(), uint64(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standardHash == testnetHash {
+		t.Error("signature hash of a replay-protected transaction should differ per chain")
+	}
+
+	txn.Version = TransactionVersionOne
+	v1Hash, err := txn.SignatureHash(uint64(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1Hash == standardHash || v1Hash == testnetHash {
+		t.Error("signature hash of a replay-protected transaction should differ from a v1 transaction")
+	}
+	// the chain ID is ignored by transactions which do not commit to it
+	if hash, err := txn.ChainSignatureHash(standard.ChainID(), uint64(0)); err != nil || hash != v1Hash {
+		t.Errorf("unexpected chain signature hash of a v1 transaction: %v (%v)", hash.String(), err)
+	}
+}
+
+func TestReplayProtectedFulfillment(t *testing.T) {
+	standard, testnet := StandardnetChainConstants(), TestnetChainConstants()
+	sk, pk := crypto.GenerateKeyPair()
+	spk := Ed25519PublicKey(pk)
+	uh, err := NewPubKeyUnlockHash(spk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := NewCondition(NewUnlockHashCondition(uh))
+	txn := Transaction{
+		Version: TransactionVersionTwo,
+		CoinInputs: []CoinInput{{
+			ParentID:    CoinOutputID{1},
+			Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(spk)),
+		}},
+		MinerFees: []Currency{NewCurrency64(1)},
+	}
+
+	// signing requires the chain ID to be known
+	err = txn.CoinInputs[0].Fulfillment.Sign(FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+		Key:          sk,
+	})
+	if err == nil {
+		t.Fatal("expected signing a replay-protected transaction without chain ID to fail")
+	}
+	err = txn.CoinInputs[0].Fulfillment.Sign(FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+		ChainID:      standard.ChainID(),
+		Key:          sk,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fulfill := func(chainID ChainID, cache *SignatureHashCache) error {
+		return condition.Fulfill(txn.CoinInputs[0].Fulfillment, FulfillContext{
+			ExtraObjects:       []interface{}{uint64(0)},
+			Transaction:        txn,
+			SignatureHashCache: cache,
+			ChainID:            chainID,
+		})
+	}
+	if err = fulfill(standard.ChainID(), nil); err != nil {
+		t.Errorf("fulfillment should be valid on the chain it is signed for: %v", err)
+	}
+	if err = fulfill(standard.ChainID(), NewSignatureHashCache(txn, standard.ChainID())); err != nil {
+		t.Errorf("fulfillment should be valid on the chain it is signed for, using a cache: %v", err)
+	}
+	if err = fulfill(testnet.ChainID(), nil); err == nil {
+		t.Error("fulfillment should be invalid on another chain")
+	}
+	if err = fulfill(ChainID{}, nil); err == nil {
+		t.Error("fulfillment should be invalid without chain ID")
+	}
+}
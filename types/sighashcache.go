@@ -0,0 +1,159 @@
+package types
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+// signatureHasher is implemented by both the chainTransaction and the SignatureHashCache type,
+// and is used to compute the signature hash of an input.
+type signatureHasher interface {
+	SignatureHash(extraObjects ...interface{}) (crypto.Hash, error)
+}
+
+// chainTransaction binds a transaction to the ChainID of the chain it belongs to,
+// such that its signature hash can be computed without a SignatureHashCache.
+type chainTransaction struct {
+	transaction Transaction
+	chainID     ChainID
+}
+
+// SignatureHash implements signatureHasher.SignatureHash
+func (ct chainTransaction) SignatureHash(extraObjects ...interface{}) (crypto.Hash, error) {
+	return ct.transaction.ChainSignatureHash(ct.chainID, extraObjects...)
+}
+
+// SignatureHashCache can be used to compute the signature hashes of all inputs of a transaction,
+// encoding the properties shared by all signature hashes of that transaction only once,
+// rather than encoding the entire transaction once for each input.
+// This makes the encoding work of signing and validating a transaction with N inputs O(N) rather than O(N²).
+// As the version and extra objects are hashed prior to the shared properties,
+// the encoded properties are still hashed once for each input, which is cheap in comparison.
+//
+// The cache is only valid as long as the signature-covered properties of the transaction
+// (version, input parent IDs, outputs, miner fees and arbitrary data) are not modified.
+// Signing the inputs of the transaction does not modify any of these properties.
+// A SignatureHashCache is safe for concurrent use.
+type SignatureHashCache struct {
+	transaction Transaction
+	chainID     ChainID
+
+	once   sync.Once
+	hasher signatureHasher
+	body   []byte
+	err    error
+}
+
+// NewSignatureHashCache creates a new signature hash cache for the given transaction,
+// belonging to the chain identified by the given ChainID.
+// The ChainID is only required for transactions which commit to it, such as replay-protected transactions.
+// The shared properties are only encoded the first time a signature hash is computed.
+func NewSignatureHashCache(t Transaction, chainID ChainID) *SignatureHashCache {
+	return &SignatureHashCache{transaction: t, chainID: chainID}
+}
+
+// SignatureHash returns the signature hash of the cached transaction,
+// for the given extra objects. The returned hash is equal to the hash
+// returned by the ChainSignatureHash method of the cached Transaction.
+//
+// Replay-protected transactions are cached as well, as their ChainID is shared by all inputs.
+// Transactions of which the controller implements any other custom signature hash logic
+// (TransactionSignatureHasher or TransactionChainSignatureHasher)
+// are hashed by that controller, without any caching.
+func (cache *SignatureHashCache) SignatureHash(extraObjects ...interface{}) (crypto.Hash, error) {
+	cache.once.Do(cache.encodeBody)
+	if cache.err != nil {
+		return crypto.Hash{}, cache.err
+	}
+	if cache.hasher != nil {
+		return cache.hasher.SignatureHash(extraObjects...)
+	}
+
+	h := crypto.NewHash()
+	enc := siabin.NewEncoder(h)
+	enc.Encode(cache.transaction.Version)
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+	h.Write(cache.body)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// encodeBody encodes the part of the signature hash which is shared by all inputs.
+func (cache *SignatureHashCache) encodeBody() {
+	controller, exists := _RegisteredTransactionVersions[cache.transaction.Version]
+	if !exists {
+		cache.err = ErrUnknownTransactionType
+		return
+	}
+	var buf bytes.Buffer
+	enc := siabin.NewEncoder(&buf)
+	switch controller.(type) {
+	case ReplayProtectedTransactionController:
+		if cache.chainID == (ChainID{}) {
+			cache.err = ErrUnknownChainID
+			return
+		}
+		// the chain ID is shared by all inputs
+		enc.Encode(cache.chainID)
+	case TransactionChainSignatureHasher, TransactionSignatureHasher:
+		cache.hasher = chainTransaction{transaction: cache.transaction, chainID: cache.chainID}
+		return
+	}
+	encodeSignatureHashBody(enc, cache.transaction)
+	cache.body = buf.Bytes()
+}
+
+// encodeSignatureHashBody encodes the transaction properties covered by the (default) signature hash,
+// which are encoded after the version and extra objects, and thus shared by all inputs.
+func encodeSignatureHashBody(enc *siabin.Encoder, t Transaction) {
+	enc.Encode(len(t.CoinInputs))
+	for _, ci := range t.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+	enc.Encode(t.CoinOutputs)
+	enc.Encode(len(t.BlockStakeInputs))
+	for _, bsi := range t.BlockStakeInputs {
+		enc.Encode(bsi.ParentID)
+	}
+	enc.EncodeAll(
+		t.BlockStakeOutputs,
+		t.MinerFees,
+		t.ArbitraryData,
+	)
+	// the optional extension data is only covered if defined,
+	// such that the signature hash of transactions without it remains unchanged
+	if len(t.ExtensionData) > 0 {
+		enc.Encode(t.ExtensionData)
+	}
+	// the optional output metadata is only covered if defined,
+	// for the same reason as the extension data
+	if t.HasOutputMetadata() {
+		coinOutputMetadata, blockStakeOutputMetadata := outputMetadataOf(t.CoinOutputs, t.BlockStakeOutputs)
+		enc.EncodeAll(coinOutputMetadata, blockStakeOutputMetadata)
+	}
+}
+
+// signatureHasher returns the signature hash cache of the context if defined,
+// or the transaction of the context bound to the ChainID of the context otherwise.
+func (ctx FulfillContext) signatureHasher() signatureHasher {
+	if ctx.SignatureHashCache != nil {
+		return ctx.SignatureHashCache
+	}
+	return chainTransaction{transaction: ctx.Transaction, chainID: ctx.ChainID}
+}
+
+// signatureHasher returns the signature hash cache of the context if defined,
+// or the transaction of the context bound to the ChainID of the context otherwise.
+func (ctx FulfillmentSignContext) signatureHasher() signatureHasher {
+	if ctx.SignatureHashCache != nil {
+		return ctx.SignatureHashCache
+	}
+	return chainTransaction{transaction: ctx.Transaction, chainID: ctx.ChainID}
+}
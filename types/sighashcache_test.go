@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+// manyInputsTransaction creates a transaction with n coin inputs and n coin outputs.
+func manyInputsTransaction(n int) Transaction {
+	txn := Transaction{
+		Version:       TransactionVersionOne,
+		MinerFees:     []Currency{NewCurrency64(1)},
+		ArbitraryData: []byte("signature hash cache"),
+	}
+	for i := 0; i < n; i++ {
+		var id CoinOutputID
+		copy(id[:], fmt.Sprintf("input%d", i))
+		txn.CoinInputs = append(txn.CoinInputs, CoinInput{ParentID: id})
+		txn.CoinOutputs = append(txn.CoinOutputs, CoinOutput{
+			Value:     NewCurrency64(uint64(i + 1)),
+			Condition: NewCondition(NewUnlockHashCondition(NewUnlockHash(UnlockTypePubKey, crypto.Hash{byte(i)}))),
+		})
+	}
+	return txn
+}
+
+func TestSignatureHashCache(t *testing.T) {
+	for _, txn := range []Transaction{
+		{Version: TransactionVersionOne},
+		manyInputsTransaction(1),
+		manyInputsTransaction(16),
+		{Version: TransactionVersionZero, CoinInputs: []CoinInput{{
+			Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(Ed25519PublicKey(crypto.PublicKey{1}))),
+		}}},
+	} {
+		cache := NewSignatureHashCache(txn, ChainID{})
+		for index := range txn.CoinInputs {
+			for _, extraObjects := range [][]interface{}{
+				{uint64(index)},
+				{uint64(index), Ed25519PublicKey(crypto.PublicKey{2})},
+			} {
+				expected, err := txn.SignatureHash(extraObjects...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hash, err := cache.SignatureHash(extraObjects...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hash != expected {
+					t.Errorf("v%d tx input #%d: cached signature hash %s != %s", txn.Version, index, hash.String(), expected.String())
+				}
+			}
+		}
+	}
+
+	_, err := NewSignatureHashCache(Transaction{Version: 255}, ChainID{}).SignatureHash(uint64(0))
+	if err != ErrUnknownTransactionType {
+		t.Errorf("expected unknown transaction type error, not: %v", err)
+	}
+}
+
+func benchmarkInputSignatureHashes(b *testing.B, n int, cached bool) {
+	txn := manyInputsTransaction(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var hasher signatureHasher = txn
+		if cached {
+			hasher = NewSignatureHashCache(txn, ChainID{})
+		}
+		for index := 0; index < n; index++ {
+			_, err := hasher.SignatureHash(uint64(index))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkInputSignatureHashes benchmarks computing the signature hashes
+// of all inputs of a transaction, with and without a signature hash cache.
+func BenchmarkInputSignatureHashes(b *testing.B) {
+	for _, n := range []int{1, 10, 100, 500} {
+		b.Run(fmt.Sprintf("uncached/%d", n), func(b *testing.B) {
+			benchmarkInputSignatureHashes(b, n, false)
+		})
+		b.Run(fmt.Sprintf("cached/%d", n), func(b *testing.B) {
+			benchmarkInputSignatureHashes(b, n, true)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+// BLS signature batch errors
+var (
+	// ErrMissingAggregatedBLSSignature is returned in case BLS signature checks
+	// are to be verified, without any (aggregated) BLS signature being given.
+	ErrMissingAggregatedBLSSignature = NewError(ErrorCodeMissingAggregatedBLSSignature, "missing (aggregated) BLS signature")
+	// ErrAggregatedBLSSignatureRequiresBatch is returned in case an empty BLS signature
+	// is to be verified outside the context of a BLS signature batch.
+	ErrAggregatedBLSSignatureRequiresBatch = NewError(ErrorCodeAggregatedBLSSignatureRequiresBatch, "empty BLS signature can only be verified as part of a BLS signature batch")
+)
+
+// BLSSignatureBatch collects the BLS12-381 signature checks of a set of fulfillments,
+// such that they can be verified in a single batch, against the aggregation of all signatures
+// given as part of those fulfillments. This allows all BLS fulfillments but one
+// to leave their signature empty, with the remaining fulfillment
+// carrying the aggregated signature of all of them.
+//
+// The zero value is ready to use.
+type BLSSignatureBatch struct {
+	hashes []crypto.Hash
+	pks    []crypto.BLSPublicKey
+	sigs   []crypto.BLSSignature
+}
+
+// Len returns the amount of signature checks collected by this batch.
+func (batch *BLSSignatureBatch) Len() int {
+	return len(batch.hashes)
+}
+
+// Verify verifies all collected signature checks at once,
+// using the aggregation of all collected (non-empty) signatures.
+// Verifying an empty batch always succeeds.
+func (batch *BLSSignatureBatch) Verify() error {
+	if len(batch.hashes) == 0 {
+		return nil
+	}
+	if len(batch.sigs) == 0 {
+		return ErrMissingAggregatedBLSSignature
+	}
+	sig, err := crypto.AggregateBLSSignatures(batch.sigs...)
+	if err != nil {
+		return err
+	}
+	err = crypto.VerifyAggregatedHashesBLS(batch.hashes, batch.pks, sig)
+	if err == crypto.ErrInvalidSignature {
+		return ErrInvalidSignature
+	}
+	return err
+}
+
+// add a signature check to the batch, the signature is optional
+func (batch *BLSSignatureBatch) add(hash crypto.Hash, pk crypto.BLSPublicKey, sig []byte) {
+	batch.hashes = append(batch.hashes, hash)
+	batch.pks = append(batch.pks, pk)
+	if len(sig) > 0 {
+		var blsSig crypto.BLSSignature
+		copy(blsSig[:], sig)
+		batch.sigs = append(batch.sigs, blsSig)
+	}
+}
+
+// AggregateBLSSignatures aggregates the BLS signatures of all coin input fulfillments
+// into the first BLS signature found, leaving the other BLS signatures empty.
+// The same is done, separately, for the BLS signatures of all block stake input fulfillments.
+// Only the signatures of single- and multi-signature fulfillments are aggregated.
+//
+// The transaction has to be (fully) signed prior to calling this function,
+// and should not be signed any further afterwards.
+func AggregateBLSSignatures(tx *Transaction) error {
+	var sigs []*ByteSlice
+	for _, ci := range tx.CoinInputs {
+		sigs = append(sigs, blsSignaturesOfFulfillment(ci.Fulfillment.Fulfillment)...)
+	}
+	err := aggregateBLSSignatures(sigs)
+	if err != nil {
+		return err
+	}
+	sigs = nil
+	for _, bsi := range tx.BlockStakeInputs {
+		sigs = append(sigs, blsSignaturesOfFulfillment(bsi.Fulfillment.Fulfillment)...)
+	}
+	return aggregateBLSSignatures(sigs)
+}
+
+func aggregateBLSSignatures(sigs []*ByteSlice) error {
+	if len(sigs) < 2 {
+		return nil // nothing to aggregate
+	}
+	var blsSigs []crypto.BLSSignature
+	for _, sig := range sigs {
+		if len(*sig) == 0 {
+			continue // already aggregated
+		}
+		if len(*sig) != crypto.BLSSignatureSize {
+			return errors.New("invalid BLS signature size")
+		}
+		var blsSig crypto.BLSSignature
+		copy(blsSig[:], *sig)
+		blsSigs = append(blsSigs, blsSig)
+	}
+	if len(blsSigs) == 0 {
+		return ErrMissingAggregatedBLSSignature
+	}
+	agg, err := crypto.AggregateBLSSignatures(blsSigs...)
+	if err != nil {
+		return err
+	}
+	*sigs[0] = ByteSlice(agg[:])
+	for _, sig := range sigs[1:] {
+		*sig = nil
+	}
+	return nil
+}
+
+func blsSignaturesOfFulfillment(fulfillment UnlockFulfillment) (sigs []*ByteSlice) {
+	switch tf := fulfillment.(type) {
+	case *SingleSignatureFulfillment:
+		if tf.PublicKey.Algorithm == SignatureAlgoBLS12381 {
+			sigs = append(sigs, &tf.Signature)
+		}
+	case *MultiSignatureFulfillment:
+		for idx := range tf.Pairs {
+			if tf.Pairs[idx].PublicKey.Algorithm == SignatureAlgoBLS12381 {
+				sigs = append(sigs, &tf.Pairs[idx].Signature)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+)
+
+// TestBLSAggregatedSignatureFulfillments signs multiple coin inputs using BLS keys,
+// aggregates their signatures and verifies them as a single batch.
+func TestBLSAggregatedSignatureFulfillments(t *testing.T) {
+	const inputCount = 3
+	var (
+		sks        = make([]crypto.BLSSecretKey, inputCount)
+		conditions = make([]UnlockConditionProxy, inputCount)
+		txn        = Transaction{Version: TransactionVersionOne}
+	)
+	for i := range sks {
+		var pk crypto.BLSPublicKey
+		// reuse the same key for the last input, as to ensure the same key can sign multiple inputs
+		if i < inputCount-1 {
+			sks[i], pk = crypto.GenerateBLSKeyPair()
+		} else {
+			sks[i], pk = sks[0], sks[0].PublicKey()
+		}
+		uh, err := NewPubKeyUnlockHash(BLS12381PublicKey(pk))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conditions[i] = NewCondition(NewUnlockHashCondition(uh))
+		txn.CoinInputs = append(txn.CoinInputs, CoinInput{
+			ParentID:    CoinOutputID{byte(i)},
+			Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(BLS12381PublicKey(pk))),
+		})
+	}
+	for i, ci := range txn.CoinInputs {
+		err := ci.Fulfillment.Sign(FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(i)},
+			Transaction:  txn,
+			Key:          sks[i],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fulfillAll := func(txn Transaction, batch *BLSSignatureBatch) error {
+		for i, ci := range txn.CoinInputs {
+			err := conditions[i].Fulfill(ci.Fulfillment, FulfillContext{
+				ExtraObjects:      []interface{}{uint64(i)},
+				Transaction:       txn,
+				BLSSignatureBatch: batch,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if batch != nil {
+			return batch.Verify()
+		}
+		return nil
+	}
+
+	// individual signatures verify with and without a batch
+	if err := fulfillAll(txn, nil); err != nil {
+		t.Fatal("failed to verify individual BLS signatures:", err)
+	}
+	if err := fulfillAll(txn, new(BLSSignatureBatch)); err != nil {
+		t.Fatal("failed to batch verify individual BLS signatures:", err)
+	}
+
+	// aggregate all signatures into the first fulfillment
+	if err := AggregateBLSSignatures(&txn); err != nil {
+		t.Fatal(err)
+	}
+	for i, ci := range txn.CoinInputs {
+		sig := ci.Fulfillment.Fulfillment.(*SingleSignatureFulfillment).Signature
+		if i == 0 && len(sig) != crypto.BLSSignatureSize {
+			t.Fatal("expected aggregated signature in first fulfillment, got signature of size", len(sig))
+		}
+		if i > 0 && len(sig) != 0 {
+			t.Fatal("expected empty signature for aggregated fulfillment", i)
+		}
+		if err := ci.Fulfillment.IsStandardFulfillment(ValidationContext{}); err != nil {
+			t.Fatal("aggregated BLS fulfillment is not standard:", err)
+		}
+	}
+	if err := fulfillAll(txn, new(BLSSignatureBatch)); err != nil {
+		t.Fatal("failed to batch verify aggregated BLS signature:", err)
+	}
+	if err := fulfillAll(txn, nil); err == nil {
+		t.Fatal("verified aggregated BLS signature without a batch")
+	}
+
+	// the aggregated signature should not verify for a subset of the inputs
+	partialTxn := txn
+	partialTxn.CoinInputs = txn.CoinInputs[:inputCount-1]
+	if err := fulfillAll(partialTxn, new(BLSSignatureBatch)); err == nil {
+		t.Fatal("verified aggregated BLS signature for a subset of its inputs")
+	}
+
+	// nor should it verify once the transaction is modified
+	txn.ArbitraryData = []byte("modified")
+	if err := fulfillAll(txn, new(BLSSignatureBatch)); err == nil {
+		t.Fatal("verified aggregated BLS signature for a modified transaction")
+	}
+}
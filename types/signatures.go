@@ -27,15 +27,23 @@ const (
 	// SignatureAlgoNil identifies a nil SignatureAlgoType value.
 	SignatureAlgoNil SignatureAlgoType = iota
 	// SignatureAlgoEd25519 identifies the Ed25519 signature Algorithm,
-	// the default (and only) algorithm supported by this chain.
+	// the default algorithm supported by this chain.
 	SignatureAlgoEd25519
+	// SignatureAlgoSecp256k1 identifies the secp256k1 (ECDSA) signature Algorithm,
+	// allowing keys from Bitcoin/Ethereum tooling and common HSMs to be used.
+	SignatureAlgoSecp256k1
+	// SignatureAlgoBLS12381 identifies the BLS12-381 signature Algorithm,
+	// which allows the signatures of many inputs to be aggregated into a single signature.
+	SignatureAlgoBLS12381
 )
 
 // These Specifiers enumerate the string versions of the types of signatures that are recognized
 // by this implementation. see Consensus.md for more details.
 var (
-	SignatureAlgoNilSpecifier     = Specifier{}
-	SignatureAlgoEd25519Specifier = Specifier{'e', 'd', '2', '5', '5', '1', '9'}
+	SignatureAlgoNilSpecifier       = Specifier{}
+	SignatureAlgoEd25519Specifier   = Specifier{'e', 'd', '2', '5', '5', '1', '9'}
+	SignatureAlgoSecp256k1Specifier = Specifier{'s', 'e', 'c', 'p', '2', '5', '6', 'k', '1'}
+	SignatureAlgoBLS12381Specifier  = Specifier{'b', 'l', 's', '1', '2', '3', '8', '1'}
 )
 
 func (sat SignatureAlgoType) String() string {
@@ -48,6 +56,10 @@ func (sat SignatureAlgoType) Specifier() Specifier {
 	switch sat {
 	case SignatureAlgoEd25519:
 		return SignatureAlgoEd25519Specifier
+	case SignatureAlgoSecp256k1:
+		return SignatureAlgoSecp256k1Specifier
+	case SignatureAlgoBLS12381:
+		return SignatureAlgoBLS12381Specifier
 	default:
 		return SignatureAlgoNilSpecifier
 	}
@@ -58,6 +70,10 @@ func (sat *SignatureAlgoType) LoadString(str string) error {
 	switch str {
 	case SignatureAlgoEd25519Specifier.String():
 		*sat = SignatureAlgoEd25519
+	case SignatureAlgoSecp256k1Specifier.String():
+		*sat = SignatureAlgoSecp256k1
+	case SignatureAlgoBLS12381Specifier.String():
+		*sat = SignatureAlgoBLS12381
 	case SignatureAlgoNilSpecifier.String():
 		*sat = SignatureAlgoNil
 	default:
@@ -71,6 +87,10 @@ func (sat *SignatureAlgoType) LoadSpecifier(specifier Specifier) error {
 	switch specifier {
 	case SignatureAlgoEd25519Specifier:
 		*sat = SignatureAlgoEd25519
+	case SignatureAlgoSecp256k1Specifier:
+		*sat = SignatureAlgoSecp256k1
+	case SignatureAlgoBLS12381Specifier:
+		*sat = SignatureAlgoBLS12381
 	case SignatureAlgoNilSpecifier:
 		*sat = SignatureAlgoNil
 	default:
@@ -83,12 +103,12 @@ func (sat *SignatureAlgoType) LoadSpecifier(specifier Specifier) error {
 var (
 	//ErrFrivolousSignature        = errors.New("transaction contains a frivolous signature")
 	//ErrInvalidPubKeyIndex        = errors.New("transaction contains a signature that points to a nonexistent public key")
-	ErrInvalidUnlockHashChecksum = errors.New("provided unlock hash has an invalid checksum")
+	ErrInvalidUnlockHashChecksum = NewError(ErrorCodeInvalidUnlockHashChecksum, "provided unlock hash has an invalid checksum")
 	//ErrMissingSignatures         = errors.New("transaction has inputs with missing signatures")
 	//ErrPrematureSignature        = errors.New("timelock on signature has not expired")
 	//ErrPublicKeyOveruse          = errors.New("public key was used multiple times while signing transaction")
 	//ErrSortedUniqueViolation     = errors.New("sorted unique violation")
-	ErrUnlockHashWrongLen = errors.New("marshalled unlock hash is the wrong length")
+	ErrUnlockHashWrongLen = NewError(ErrorCodeUnlockHashWrongLen, "marshalled unlock hash is the wrong length")
 )
 
 type (
@@ -116,13 +136,57 @@ func Ed25519PublicKey(pk crypto.PublicKey) PublicKey {
 	}
 }
 
+// Secp256k1PublicKey returns pk as a PublicKey, denoting its algorithm as
+// secp256k1.
+func Secp256k1PublicKey(pk crypto.Secp256k1PublicKey) PublicKey {
+	return PublicKey{
+		Algorithm: SignatureAlgoSecp256k1,
+		Key:       pk[:],
+	}
+}
+
+// BLS12381PublicKey returns pk as a PublicKey, denoting its algorithm as
+// BLS12-381.
+func BLS12381PublicKey(pk crypto.BLSPublicKey) PublicKey {
+	return PublicKey{
+		Algorithm: SignatureAlgoBLS12381,
+		Key:       pk[:],
+	}
+}
+
+// AggregatedBLS12381PublicKey aggregates the given (n-of-n) group of BLS12-381 public keys
+// into a single PublicKey. On-chain this key (and its unlock hash) cannot be distinguished
+// from a regular BLS12-381 public key, while all members of the group have to sign
+// (see crypto.SignHashBLSMultiSig) in order to produce a valid signature for it.
+func AggregatedBLS12381PublicKey(pks ...crypto.BLSPublicKey) (PublicKey, error) {
+	pk, err := crypto.AggregateBLSPublicKeys(pks...)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	return BLS12381PublicKey(pk), nil
+}
+
 // SignatureHash returns the hash of all fields in a transaction,
 // relevant to a Tx sig.
+//
+// The signature hash of transactions which commit to the ChainID of their chain,
+// such as replay-protected transactions, can only be computed using ChainSignatureHash.
 func (t Transaction) SignatureHash(extraObjects ...interface{}) (crypto.Hash, error) {
+	return t.ChainSignatureHash(ChainID{}, extraObjects...)
+}
+
+// ChainSignatureHash returns the hash of all fields in a transaction,
+// relevant to a Tx sig, for a transaction belonging to the chain identified by the given ChainID.
+// The ChainID is only used by transactions which commit to it, such as replay-protected transactions,
+// for which ErrUnknownChainID is returned in case no ChainID is given.
+func (t Transaction) ChainSignatureHash(chainID ChainID, extraObjects ...interface{}) (crypto.Hash, error) {
 	controller, exists := _RegisteredTransactionVersions[t.Version]
 	if !exists {
 		return crypto.Hash{}, ErrUnknownTransactionType
 	}
+	if hasher, ok := controller.(TransactionChainSignatureHasher); ok {
+		return hasher.ChainSignatureHash(t, chainID, extraObjects...)
+	}
 	if hasher, ok := controller.(TransactionSignatureHasher); ok {
 		// if extension implements TransactionSignatureHasher,
 		// use it here to sign the input with it
@@ -136,20 +200,7 @@ func (t Transaction) SignatureHash(extraObjects ...interface{}) (crypto.Hash, er
 	if len(extraObjects) > 0 {
 		enc.EncodeAll(extraObjects...)
 	}
-	enc.Encode(len(t.CoinInputs))
-	for _, ci := range t.CoinInputs {
-		enc.Encode(ci.ParentID)
-	}
-	enc.Encode(t.CoinOutputs)
-	enc.Encode(len(t.BlockStakeInputs))
-	for _, bsi := range t.BlockStakeInputs {
-		enc.Encode(bsi.ParentID)
-	}
-	enc.EncodeAll(
-		t.BlockStakeOutputs,
-		t.MinerFees,
-		t.ArbitraryData,
-	)
+	encodeSignatureHashBody(enc, t)
 
 	var hash crypto.Hash
 	h.Sum(hash[:0])
@@ -289,6 +340,10 @@ func (pk *PublicKey) UnmarshalRivine(r io.Reader) error {
 	switch pk.Algorithm {
 	case SignatureAlgoEd25519:
 		pk.Key = make(ByteSlice, crypto.PublicKeySize)
+	case SignatureAlgoSecp256k1:
+		pk.Key = make(ByteSlice, crypto.Secp256k1PublicKeySize)
+	case SignatureAlgoBLS12381:
+		pk.Key = make(ByteSlice, crypto.BLSPublicKeySize)
 	case SignatureAlgoNil:
 		pk.Key = nil
 	default:
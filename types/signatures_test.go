@@ -2,12 +2,15 @@ package types
 
 import (
 	"bytes"
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/NebulousLabs/fastrand"
 	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 )
 
 // TestEd25519PublicKey tests the Ed25519PublicKey function.
@@ -139,3 +142,107 @@ func TestPublicKeyString(t *testing.T) {
 		t.Error("got wrong value for spk.String():", spk.String())
 	}
 }
+
+// TestSecp256k1PublicKeyEncoding checks that secp256k1 public keys
+// survive a string, JSON, siabin and rivbin round trip.
+func TestSecp256k1PublicKeyEncoding(t *testing.T) {
+	_, secpPK := crypto.GenerateSecp256k1KeyPair()
+	pk := Secp256k1PublicKey(secpPK)
+	if pk.Algorithm != SignatureAlgoSecp256k1 {
+		t.Fatal("created key with wrong algorithm specifier:", pk.Algorithm)
+	}
+	if !strings.HasPrefix(pk.String(), "secp256k1:") {
+		t.Fatal("unexpected string format:", pk.String())
+	}
+
+	var loadedPK PublicKey
+	if err := loadedPK.LoadString(pk.String()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pk, loadedPK) {
+		t.Error("string round trip failed:", pk, "!=", loadedPK)
+	}
+
+	b, err := json.Marshal(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonPK PublicKey
+	if err = json.Unmarshal(b, &jsonPK); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pk, jsonPK) {
+		t.Error("JSON round trip failed:", pk, "!=", jsonPK)
+	}
+
+	b, err = siabin.Marshal(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var siaPK PublicKey
+	if err = siabin.Unmarshal(b, &siaPK); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pk, siaPK) {
+		t.Error("siabin round trip failed:", pk, "!=", siaPK)
+	}
+
+	b, err = rivbin.Marshal(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rivPK PublicKey
+	if err = rivbin.Unmarshal(b, &rivPK); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pk, rivPK) {
+		t.Error("rivbin round trip failed:", pk, "!=", rivPK)
+	}
+}
+
+// TestSecp256k1SingleSignatureFulfillment checks that a secp256k1 key
+// can be used to control (and spend) an output.
+func TestSecp256k1SingleSignatureFulfillment(t *testing.T) {
+	sk, pk := crypto.GenerateSecp256k1KeyPair()
+	uh, err := NewPubKeyUnlockHash(Secp256k1PublicKey(pk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := NewCondition(NewUnlockHashCondition(uh))
+	txn := Transaction{
+		Version:    TransactionVersionOne,
+		CoinInputs: []CoinInput{{ParentID: CoinOutputID{1}}},
+		CoinOutputs: []CoinOutput{
+			{Value: NewCurrency64(10), Condition: condition},
+		},
+	}
+
+	ff := NewSingleSignatureFulfillment(Secp256k1PublicKey(pk))
+	err = ff.Sign(FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+		Key:          sk,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ff.IsStandardFulfillment(ValidationContext{}); err != nil {
+		t.Fatal("secp256k1 fulfillment is not standard:", err)
+	}
+	err = condition.Fulfill(NewFulfillment(ff), FulfillContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  txn,
+	})
+	if err != nil {
+		t.Fatal("failed to fulfill condition using secp256k1 key:", err)
+	}
+
+	// fulfilling it for another input should fail
+	err = condition.Fulfill(NewFulfillment(ff), FulfillContext{
+		ExtraObjects: []interface{}{uint64(1)},
+		Transaction:  txn,
+	})
+	if err == nil {
+		t.Fatal("fulfilled condition using a signature of another input")
+	}
+}
@@ -0,0 +1,81 @@
+package types
+
+type (
+	// SupplyTotals are the cumulative supply totals maintained
+	// by the consensus set for the current path.
+	SupplyTotals struct {
+		// Coins is the total amount of coins in existence,
+		// including the (not yet matured) block creator payouts and the burned coins.
+		Coins Currency `json:"coins"`
+		// BlockStakes is the total amount of block stakes in existence,
+		// including the burned block stakes.
+		BlockStakes Currency `json:"blockstakes"`
+		// BlockCreatorFees is the total amount of coins created as block creator fees,
+		// meaning the miner payouts minus the transaction fees they include.
+		BlockCreatorFees Currency `json:"blockcreatorfees"`
+		// BurnedCoins is the total amount of coins sent to a BurnCondition.
+		BurnedCoins Currency `json:"burnedcoins"`
+		// BurnedBlockStakes is the total amount of block stakes sent to a BurnCondition.
+		BurnedBlockStakes Currency `json:"burnedblockstakes"`
+	}
+
+	// SupplyStatistics describe the supply of coins and block stakes of a chain,
+	// see ChainConstants.SupplyStatistics.
+	SupplyStatistics struct {
+		// TotalCoins is the total amount of coins in existence, including the burned coins,
+		// which equals GenesisCoins + BlockCreatorFees + MintedCoins - DestroyedCoins.
+		TotalCoins Currency `json:"totalcoins"`
+		// CirculatingCoins is TotalCoins - BurnedCoins.
+		CirculatingCoins Currency `json:"circulatingcoins"`
+		// GenesisCoins is the amount of coins distributed by the genesis block.
+		GenesisCoins Currency `json:"genesiscoins"`
+		// BlockCreatorFees is the amount of coins created as block creator fees.
+		BlockCreatorFees Currency `json:"blockcreatorfees"`
+		// MintedCoins is the net amount of coins created by other means
+		// than the genesis block and block creator fees (e.g. minting),
+		// zero if more such coins were destroyed than created.
+		MintedCoins Currency `json:"mintedcoins"`
+		// DestroyedCoins is the net amount of coins destroyed (e.g. coin destruction transactions),
+		// zero if more coins were created than destroyed by other means
+		// than the genesis block and block creator fees.
+		DestroyedCoins Currency `json:"destroyedcoins"`
+		// BurnedCoins is the amount of coins sent to a BurnCondition.
+		BurnedCoins Currency `json:"burnedcoins"`
+
+		// TotalBlockStakes is the total amount of block stakes in existence, including the burned block stakes.
+		TotalBlockStakes Currency `json:"totalblockstakes"`
+		// CirculatingBlockStakes is TotalBlockStakes - BurnedBlockStakes.
+		CirculatingBlockStakes Currency `json:"circulatingblockstakes"`
+		// GenesisBlockStakes is the amount of block stakes allocated by the genesis block.
+		GenesisBlockStakes Currency `json:"genesisblockstakes"`
+		// BurnedBlockStakes is the amount of block stakes sent to a BurnCondition.
+		BurnedBlockStakes Currency `json:"burnedblockstakes"`
+	}
+)
+
+// SupplyStatistics computes the supply statistics of the chain,
+// using the supply totals maintained by the consensus set.
+func (c *ChainConstants) SupplyStatistics(totals SupplyTotals) SupplyStatistics {
+	stats := SupplyStatistics{
+		TotalCoins:         totals.Coins,
+		GenesisCoins:       c.GenesisCoinCount(),
+		BlockCreatorFees:   totals.BlockCreatorFees,
+		BurnedCoins:        totals.BurnedCoins,
+		TotalBlockStakes:   totals.BlockStakes,
+		GenesisBlockStakes: c.GenesisBlockStakeCount(),
+		BurnedBlockStakes:  totals.BurnedBlockStakes,
+	}
+	if totals.Coins.Cmp(totals.BurnedCoins) > 0 {
+		stats.CirculatingCoins = totals.Coins.Sub(totals.BurnedCoins)
+	}
+	if totals.BlockStakes.Cmp(totals.BurnedBlockStakes) > 0 {
+		stats.CirculatingBlockStakes = totals.BlockStakes.Sub(totals.BurnedBlockStakes)
+	}
+	expected := stats.GenesisCoins.Add(stats.BlockCreatorFees)
+	if c := totals.Coins.Cmp(expected); c > 0 {
+		stats.MintedCoins = totals.Coins.Sub(expected)
+	} else if c < 0 {
+		stats.DestroyedCoins = expected.Sub(totals.Coins)
+	}
+	return stats
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -48,6 +49,9 @@ type (
 		BlockStakeOutputs []BlockStakeOutput `json:"blockstakeoutputs,omitempty"`
 		MinerFees         []Currency         `json:"minerfees"` // required
 		ArbitraryData     []byte             `json:"arbitrarydata,omitempty"`
+		// ExtensionData is optional, and only supported by the DefaultTransactionController,
+		// see TransactionExtensionField for more information.
+		ExtensionData []byte `json:"extensiondata,omitempty"`
 
 		// Extension is an optional field that can be used,
 		// in order to attach non-standard state to a transaction.
@@ -59,9 +63,12 @@ type (
 	// TransactionValidationConstants defines the contants that a TransactionValidator
 	// can use in order to validate the transaction, within its local scope.
 	TransactionValidationConstants struct {
-		BlockSizeLimit         uint64
-		ArbitraryDataSizeLimit uint64
-		MinimumMinerFee        Currency
+		BlockSizeLimit            uint64
+		ArbitraryDataSizeLimit    uint64
+		AllowedArbitraryDataTypes []ArbitraryDataType
+		MinimumMinerFee           Currency
+		ExtensionDataSizeLimit    uint64
+		OutputMetadataSizeLimit   uint64
 	}
 )
 
@@ -75,6 +82,14 @@ type (
 		SignatureHash(t Transaction, extraObjects ...interface{}) (crypto.Hash, error)
 	}
 
+	// TransactionChainSignatureHasher defines the interface a transaction controller
+	// can optionally implement, in order to define custom Tx signatures
+	// which commit to the ChainID of the chain (network) the transaction belongs to,
+	// overwriting the default Tx sig hash logic.
+	TransactionChainSignatureHasher interface {
+		ChainSignatureHash(t Transaction, chainID ChainID, extraObjects ...interface{}) (crypto.Hash, error)
+	}
+
 	// TransactionIDEncoder is an optional interface a transaction controller
 	// can implement, in order to use a different binary encoding for ID-generation purposes,
 	// instead of using the default binary encoding logic for that transaction (version).
@@ -182,11 +197,24 @@ type (
 
 // EncodeTransactionData implements TransactionController.EncodeTransactionData
 func (dtc DefaultTransactionController) EncodeTransactionData(w io.Writer, td TransactionData) error {
+	// output metadata is only encoded by the OutputMetadataTransactionController
+	if transactionDataHasOutputMetadata(td.CoinOutputs, td.BlockStakeOutputs) {
+		return ErrOutputMetadataNotSupported
+	}
 	// encode to a byte slice first
 	b, err := siabin.Marshal(td)
 	if err != nil {
 		return fmt.Errorf("failed to (siabin) marshal transaction data: %v", err)
 	}
+	// the optional extension data is appended only if defined,
+	// such that the encoding of transactions without it remains unchanged
+	if len(td.ExtensionData) > 0 {
+		eb, err := siabin.Marshal(td.ExtensionData)
+		if err != nil {
+			return fmt.Errorf("failed to (siabin) marshal transaction extension data: %v", err)
+		}
+		b = append(b, eb...)
+	}
 	// copy those bytes together with its prefixed length, as the final encoding
 	return siabin.NewEncoder(w).Encode(b)
 }
@@ -200,7 +228,20 @@ func (dtc DefaultTransactionController) DecodeTransactionData(r io.Reader) (td T
 		return
 	}
 	// decode
-	err = siabin.Unmarshal(b, &td)
+	br := bytes.NewReader(b)
+	dec := siabin.NewDecoder(br)
+	err = dec.Decode(&td)
+	if err != nil || br.Len() == 0 {
+		return
+	}
+	// decode the optional extension data
+	err = dec.Decode(&td.ExtensionData)
+	if err != nil {
+		return
+	}
+	if len(td.ExtensionData) == 0 || br.Len() > 0 {
+		err = ErrInvalidTransactionExtensionData
+	}
 	return
 }
 
@@ -365,6 +406,7 @@ func (dtc DisabledTransactionController) validateTransaction(t Transaction) erro
 		BlockStakeOutputs: t.BlockStakeOutputs,
 		MinerFees:         t.MinerFees,
 		ArbitraryData:     t.ArbitraryData,
+		ExtensionData:     t.ExtensionData,
 		Extension:         t.Extension,
 	})
 }
@@ -388,7 +430,7 @@ func (dtc DisabledTransactionController) validateTransactionData(t TransactionDa
 	if len(t.ArbitraryData) != 0 {
 		return errors.New("DisabledTransactionController allows only empty (nil) transactions: arbitrary data not allowed")
 	}
-	if t.Extension != nil {
+	if t.Extension != nil || len(t.ExtensionData) != 0 {
 		return errors.New("DisabledTransactionController allows only empty (nil) transactions: extension data not allowed")
 	}
 	return nil
@@ -397,4 +439,6 @@ func (dtc DisabledTransactionController) validateTransactionData(t TransactionDa
 func init() {
 	RegisterTransactionVersion(TransactionVersionZero, LegacyTransactionController{})
 	RegisterTransactionVersion(TransactionVersionOne, DefaultTransactionController{})
+	RegisterTransactionVersion(TransactionVersionTwo, ReplayProtectedTransactionController{})
+	RegisterTransactionVersion(TransactionVersionThree, OutputMetadataTransactionController{})
 }
@@ -53,12 +53,21 @@ func newLegacyTransactionDataFromTransaction(t Transaction) (legacyTransactionDa
 		BlockStakeOutputs: t.BlockStakeOutputs,
 		MinerFees:         t.MinerFees,
 		ArbitraryData:     t.ArbitraryData,
+		ExtensionData:     t.ExtensionData,
 	})
 }
 
 // newLegacyTransactionData creates legacy transaction data (as part of v0 transactions),
 // using the given transaction data in the newer (in-memory) format, passed as input.
 func newLegacyTransactionData(data TransactionData) (ltd legacyTransactionData, err error) {
+	if len(data.ExtensionData) > 0 {
+		err = ErrTransactionExtensionDataNotSupported
+		return
+	}
+	if transactionDataHasOutputMetadata(data.CoinOutputs, data.BlockStakeOutputs) {
+		err = ErrOutputMetadataNotSupported
+		return
+	}
 	ltd.CoinInputs = make([]legacyTransactionCoinInput, len(data.CoinInputs))
 	for i, ci := range data.CoinInputs {
 		ltd.CoinInputs[i] = legacyTransactionCoinInput{
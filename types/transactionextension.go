@@ -0,0 +1,150 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+// TransactionExtensionType defines the type of a field within the extension data of a transaction.
+type TransactionExtensionType uint8
+
+// TransactionExtensionField is a single (typed) field of the extension data of a transaction.
+//
+// The extension data of a transaction is an opaque, length-limited blob,
+// which allows future features to attach data to (v1 and v2) transactions,
+// without requiring a new (incompatible) transaction version each time.
+// It is encoded as a list of fields, sorted by type, with each type defined at most once.
+//
+// Validators only validate the fields of the types they know about,
+// fields of unknown types are considered valid, no matter their data.
+// This way a new feature can be introduced as a soft fork,
+// by registering a new field type (see RegisterTransactionExtensionType),
+// as its rules only restrict what was valid before.
+type TransactionExtensionField struct {
+	Type TransactionExtensionType `json:"type"`
+	Data []byte                   `json:"data"`
+}
+
+// TransactionExtensionFieldValidator is a function used to validate
+// the data of a transaction extension field of a specific type.
+type TransactionExtensionFieldValidator func(data []byte) error
+
+// Transaction extension data errors
+var (
+	// ErrInvalidTransactionExtensionData is returned in case
+	// the extension data of a transaction isn't encoded as expected.
+	ErrInvalidTransactionExtensionData = NewError(ErrorCodeInvalidTransactionExtensionData, "invalid transaction extension data")
+	// ErrTransactionExtensionDataTooLarge is returned in case
+	// the extension data of a transaction exceeds the size limit.
+	ErrTransactionExtensionDataTooLarge = NewError(ErrorCodeTransactionExtensionDataTooLarge, "transaction extension data is too large")
+	// ErrTransactionExtensionDataNotSupported is returned in case a transaction
+	// defines extension data, while its version doesn't support it.
+	ErrTransactionExtensionDataNotSupported = NewError(ErrorCodeTransactionExtensionDataNotSupported, "transaction version does not support extension data")
+)
+
+// _RegisteredTransactionExtensionTypes contains the validators of all known extension field types,
+// fields of an unknown type are considered valid.
+var _RegisteredTransactionExtensionTypes = map[TransactionExtensionType]TransactionExtensionFieldValidator{}
+
+// RegisterTransactionExtensionType is used to register a transaction extension field type,
+// by linking it to the validator used to validate its data.
+//
+// RegisterTransactionExtensionType can also used to unregister a transaction extension field type,
+// by calling this function with nil as the TransactionExtensionFieldValidator.
+func RegisterTransactionExtensionType(typ TransactionExtensionType, validator TransactionExtensionFieldValidator) {
+	if validator == nil {
+		delete(_RegisteredTransactionExtensionTypes, typ)
+		return
+	}
+	_RegisteredTransactionExtensionTypes[typ] = validator
+}
+
+// EncodeTransactionExtensionData encodes the given fields as the extension data of a transaction.
+// The fields are required to be sorted by type, with each type defined at most once.
+// No fields results in nil extension data.
+func EncodeTransactionExtensionData(fields ...TransactionExtensionField) ([]byte, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	if err := validateTransactionExtensionFieldOrder(fields); err != nil {
+		return nil, err
+	}
+	return siabin.Marshal(fields)
+}
+
+// DecodeTransactionExtensionData decodes the extension data of a transaction into its fields.
+// Nil extension data results in no fields.
+func DecodeTransactionExtensionData(b []byte) ([]TransactionExtensionField, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	r := bytes.NewReader(b)
+	var fields []TransactionExtensionField
+	err := siabin.NewDecoder(r).Decode(&fields)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidTransactionExtensionData, err)
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("%v: %d trailing bytes", ErrInvalidTransactionExtensionData, r.Len())
+	}
+	if len(fields) == 0 {
+		// encoding no fields is only possible as nil extension data
+		return nil, fmt.Errorf("%v: no fields", ErrInvalidTransactionExtensionData)
+	}
+	if err = validateTransactionExtensionFieldOrder(fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func validateTransactionExtensionFieldOrder(fields []TransactionExtensionField) error {
+	for i := 1; i < len(fields); i++ {
+		if fields[i].Type <= fields[i-1].Type {
+			return fmt.Errorf("%v: fields are not sorted by (unique) type", ErrInvalidTransactionExtensionData)
+		}
+	}
+	return nil
+}
+
+// ValidateTransactionExtensionData validates the extension data of a transaction,
+// ensuring it fits within the given size limit, that it is encoded as expected,
+// and that the fields of known types are valid. Empty extension data is always valid.
+func ValidateTransactionExtensionData(extensionData []byte, sizeLimit uint64) error {
+	if len(extensionData) == 0 {
+		return nil
+	}
+	if uint64(len(extensionData)) > sizeLimit {
+		return ErrTransactionExtensionDataTooLarge
+	}
+	fields, err := DecodeTransactionExtensionData(extensionData)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		validator, ok := _RegisteredTransactionExtensionTypes[field.Type]
+		if !ok {
+			continue // unknown types are opaque
+		}
+		if err = validator(field.Data); err != nil {
+			return fmt.Errorf("invalid transaction extension field of type %d: %v", field.Type, err)
+		}
+	}
+	return nil
+}
+
+// ExtensionField returns the data of the extension field of the given type,
+// and false in case the transaction doesn't define (valid extension data with) such a field.
+func (t Transaction) ExtensionField(typ TransactionExtensionType) ([]byte, bool) {
+	fields, err := DecodeTransactionExtensionData(t.ExtensionData)
+	if err != nil {
+		return nil, false
+	}
+	for _, field := range fields {
+		if field.Type == typ {
+			return field.Data, true
+		}
+	}
+	return nil, false
+}
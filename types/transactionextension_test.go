@@ -0,0 +1,99 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+func TestTransactionExtensionData(t *testing.T) {
+	fields := []TransactionExtensionField{
+		{Type: 1, Data: []byte("foo")},
+		{Type: 42, Data: []byte("bar")},
+	}
+	b, err := EncodeTransactionExtensionData(fields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransactionExtensionData(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[1].Type != 42 || !bytes.Equal(decoded[1].Data, []byte("bar")) {
+		t.Errorf("unexpected decoded fields: %v", decoded)
+	}
+
+	if _, err = EncodeTransactionExtensionData(fields[1], fields[0]); err == nil {
+		t.Error("unsorted fields shouldn't be encodable")
+	}
+	if _, err = DecodeTransactionExtensionData(append(b, 0)); err == nil {
+		t.Error("extension data with trailing bytes shouldn't be decodable")
+	}
+
+	if err = ValidateTransactionExtensionData(b, uint64(len(b))); err != nil {
+		t.Errorf("unknown fields should be valid: %v", err)
+	}
+	if err = ValidateTransactionExtensionData(b, uint64(len(b)-1)); err != ErrTransactionExtensionDataTooLarge {
+		t.Errorf("expected too large error, not: %v", err)
+	}
+	RegisterTransactionExtensionType(42, func(data []byte) error {
+		if !bytes.Equal(data, []byte("baz")) {
+			return errors.New("unexpected data")
+		}
+		return nil
+	})
+	defer RegisterTransactionExtensionType(42, nil)
+	if err = ValidateTransactionExtensionData(b, uint64(len(b))); err == nil {
+		t.Error("field of a registered type should be validated")
+	}
+}
+
+func TestTransactionExtensionDataEncoding(t *testing.T) {
+	txn := manyInputsTransaction(2)
+	withoutExtension, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idWithout, hashWithout := txn.ID(), mustSignatureHash(t, txn)
+
+	txn.ExtensionData, err = EncodeTransactionExtensionData(TransactionExtensionField{Type: 1, Data: []byte("foo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := siabin.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) <= len(withoutExtension) {
+		t.Error("extension data should be encoded")
+	}
+	if txn.ID() == idWithout || mustSignatureHash(t, txn) == hashWithout {
+		t.Error("extension data should be covered by the transaction ID and signature hash")
+	}
+	var decoded Transaction
+	if err = siabin.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.ExtensionData, txn.ExtensionData) {
+		t.Errorf("unexpected decoded extension data: %x", decoded.ExtensionData)
+	}
+	if data, ok := decoded.ExtensionField(1); !ok || string(data) != "foo" {
+		t.Errorf("unexpected extension field: %q (%v)", data, ok)
+	}
+
+	// legacy transactions do not support extension data
+	txn.Version = TransactionVersionZero
+	if _, err = siabin.Marshal(txn); err != ErrTransactionExtensionDataNotSupported {
+		t.Errorf("expected extension data not supported error, not: %v", err)
+	}
+}
+
+func mustSignatureHash(t *testing.T, txn Transaction) [32]byte {
+	hash, err := txn.SignatureHash(uint64(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
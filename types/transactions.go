@@ -31,8 +31,8 @@ var (
 	SpecifierBlockStakeOutput = Specifier{'b', 'l', 's', 't', 'a', 'k', 'e', ' ', 'o', 'u', 't', 'p', 'u', 't'}
 	SpecifierMinerFee         = Specifier{'m', 'i', 'n', 'e', 'r', ' ', 'f', 'e', 'e'}
 
-	ErrInvalidTransactionVersion = errors.New("invalid transaction version")
-	ErrTransactionIDWrongLen     = errors.New("input has wrong length to be an encoded transaction id")
+	ErrInvalidTransactionVersion = NewError(ErrorCodeInvalidTransactionVersion, "invalid transaction version")
+	ErrTransactionIDWrongLen     = NewError(ErrorCodeTransactionIDWrongLen, "input has wrong length to be an encoded transaction id")
 )
 
 const (
@@ -93,6 +93,11 @@ type (
 		MinerFees         []Currency
 		ArbitraryData     []byte
 
+		// ExtensionData is an optional, opaque and length-limited blob,
+		// see TransactionExtensionField for more information.
+		// It is only supported by (and encoded for) v1 and v2 transactions.
+		ExtensionData []byte
+
 		// can adhere any (at once) of {TransactionDataEncoder, TransactionValidator, InputSigHasher},
 		// or simply be nil.
 		//
@@ -118,6 +123,9 @@ type (
 	CoinOutput struct {
 		Value     Currency             `json:"value"`
 		Condition UnlockConditionProxy `json:"condition"`
+		// Metadata is optional, and only supported by the OutputMetadataTransactionController,
+		// which encodes it separately, such that the binary encoding of the output itself remains unchanged.
+		Metadata []byte `json:"metadata,omitempty" siabin:"-" rivbin:"-"`
 	}
 
 	// A BlockStakeInput consumes a BlockStakeOutput and adds the blockstakes to the set of
@@ -137,6 +145,9 @@ type (
 	BlockStakeOutput struct {
 		Value     Currency             `json:"value"`
 		Condition UnlockConditionProxy `json:"condition"`
+		// Metadata is optional, and only supported by the OutputMetadataTransactionController,
+		// which encodes it separately, such that the binary encoding of the output itself remains unchanged.
+		Metadata []byte `json:"metadata,omitempty" siabin:"-" rivbin:"-"`
 	}
 
 	// UnspentBlockStakeOutput groups the BlockStakeOutputID, the block height, the transaction index, the output index and the value
@@ -158,7 +169,7 @@ type (
 
 var (
 	// ErrUnknownTransactionType is returned when an unknown transaction version/type was encountered.
-	ErrUnknownTransactionType = errors.New("unknown transaction type")
+	ErrUnknownTransactionType = NewError(ErrorCodeUnknownTransactionType, "unknown transaction type")
 )
 
 // ID returns the id of a transaction, which is taken by marshalling all of the
@@ -301,6 +312,7 @@ func (t Transaction) MarshalSia(w io.Writer) error {
 		BlockStakeOutputs: t.BlockStakeOutputs,
 		MinerFees:         t.MinerFees,
 		ArbitraryData:     t.ArbitraryData,
+		ExtensionData:     t.ExtensionData,
 		Extension:         t.Extension,
 	})
 }
@@ -328,7 +340,7 @@ func (t *Transaction) UnmarshalSia(r io.Reader) error {
 	t.BlockStakeInputs = td.BlockStakeInputs
 	t.BlockStakeOutputs = td.BlockStakeOutputs
 	t.MinerFees, t.ArbitraryData = td.MinerFees, td.ArbitraryData
-	t.Extension = td.Extension
+	t.ExtensionData, t.Extension = td.ExtensionData, td.Extension
 	return nil
 }
 
@@ -362,6 +374,7 @@ func (t Transaction) MarshalRivine(w io.Writer) error {
 		BlockStakeOutputs: t.BlockStakeOutputs,
 		MinerFees:         t.MinerFees,
 		ArbitraryData:     t.ArbitraryData,
+		ExtensionData:     t.ExtensionData,
 		Extension:         t.Extension,
 	})
 }
@@ -389,7 +402,7 @@ func (t *Transaction) UnmarshalRivine(r io.Reader) error {
 	t.BlockStakeInputs = td.BlockStakeInputs
 	t.BlockStakeOutputs = td.BlockStakeOutputs
 	t.MinerFees, t.ArbitraryData = td.MinerFees, td.ArbitraryData
-	t.Extension = td.Extension
+	t.ExtensionData, t.Extension = td.ExtensionData, td.Extension
 	return nil
 }
 
@@ -420,6 +433,7 @@ func (t Transaction) MarshalJSON() ([]byte, error) {
 		BlockStakeOutputs: t.BlockStakeOutputs,
 		MinerFees:         t.MinerFees,
 		ArbitraryData:     t.ArbitraryData,
+		ExtensionData:     t.ExtensionData,
 		Extension:         t.Extension,
 	})
 	if err != nil {
@@ -453,7 +467,7 @@ func (t *Transaction) UnmarshalJSON(b []byte) error {
 	t.BlockStakeInputs = td.BlockStakeInputs
 	t.BlockStakeOutputs = td.BlockStakeOutputs
 	t.MinerFees, t.ArbitraryData = td.MinerFees, td.ArbitraryData
-	t.Extension = td.Extension
+	t.ExtensionData, t.Extension = td.ExtensionData, td.Extension
 	return nil
 }
 
@@ -2146,7 +2146,7 @@ func (t Transaction) LegacyBlockStakeOutputID(i uint64) BlockStakeOutputID {
 }
 
 func TestIsValidTransactionVersion(t *testing.T) {
-	minVersion, maxVersion := TransactionVersion(0), TransactionVersion(1)
+	minVersion, maxVersion := TransactionVersion(0), TransactionVersionThree
 	for v := minVersion; v <= maxVersion; v++ {
 		err := v.IsValidTransactionVersion()
 		if err != nil {
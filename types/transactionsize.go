@@ -0,0 +1,116 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+// SignatureSize returns the size (in bytes) of a signature
+// created using this Signature Algorithm Type, 0 is returned for unknown algorithms.
+func (sat SignatureAlgoType) SignatureSize() int {
+	switch sat {
+	case SignatureAlgoEd25519:
+		return crypto.SignatureSize
+	case SignatureAlgoSecp256k1:
+		return crypto.Secp256k1SignatureSize
+	case SignatureAlgoBLS12381:
+		return crypto.BLSSignatureSize
+	default:
+		return 0
+	}
+}
+
+// TransactionFee returns the fee to be paid for a transaction of the given (encoded) size,
+// which is the MinimumTransactionFee increased with the FeePerByte for each byte of the transaction.
+func (cts ChainConstants) TransactionFee(size int) Currency {
+	return cts.MinimumTransactionFee.Add(cts.TransactionPool.FeePerByte.Mul64(uint64(size)))
+}
+
+// EstimateSize estimates the encoded size (in bytes) the transaction will have once signed,
+// which is the size used to validate a transaction against the size limits of the transaction pool and blocks.
+// It can be computed prior to signing, as the signatures which are still missing
+// are accounted for, using the signature size of the algorithm of the public key they are paired with.
+// Fulfillments which aren't defined yet (using the NilFulfillment),
+// are assumed to become a SingleSignatureFulfillment using an Ed25519 public key.
+//
+// Note that fulfillments defined as part of the extension of a transaction are not accounted for.
+func (t Transaction) EstimateSize() (int, error) {
+	b, err := siabin.Marshal(t)
+	if err != nil {
+		return 0, fmt.Errorf("failed to (siabin) marshal transaction: %v", err)
+	}
+	size := len(b)
+	for _, ci := range t.CoinInputs {
+		missing, err := estimateMissingFulfillmentSize(ci.Fulfillment)
+		if err != nil {
+			return 0, err
+		}
+		size += missing
+	}
+	for _, bsi := range t.BlockStakeInputs {
+		missing, err := estimateMissingFulfillmentSize(bsi.Fulfillment)
+		if err != nil {
+			return 0, err
+		}
+		size += missing
+	}
+	return size, nil
+}
+
+// estimateMissingFulfillmentSize returns the amount of bytes
+// the (siabin) encoding of the given fulfillment will grow with, once signed.
+func estimateMissingFulfillmentSize(fulfillment UnlockFulfillmentProxy) (int, error) {
+	if fulfillment.FulfillmentType() == FulfillmentTypeNil {
+		nb, err := siabin.Marshal(fulfillment)
+		if err != nil {
+			return 0, fmt.Errorf("failed to (siabin) marshal fulfillment: %v", err)
+		}
+		sb, err := siabin.Marshal(NewFulfillment(&SingleSignatureFulfillment{
+			PublicKey: Ed25519PublicKey(crypto.PublicKey{}),
+			Signature: make(ByteSlice, crypto.SignatureSize),
+		}))
+		if err != nil {
+			return 0, fmt.Errorf("failed to (siabin) marshal fulfillment: %v", err)
+		}
+		return len(sb) - len(nb), nil
+	}
+	return missingSignatureSize(fulfillment.Fulfillment), nil
+}
+
+// missingSignatureSize returns the size of the signatures missing in the given fulfillment.
+func missingSignatureSize(fulfillment UnlockFulfillment) int {
+	missing := func(pk PublicKey, signature ByteSlice) int {
+		if len(signature) != 0 {
+			return 0
+		}
+		return pk.Algorithm.SignatureSize()
+	}
+	switch tf := fulfillment.(type) {
+	case *SingleSignatureFulfillment:
+		return missing(tf.PublicKey, tf.Signature)
+	case *MultiSignatureFulfillment:
+		var size int
+		for _, pair := range tf.Pairs {
+			size += missing(pair.PublicKey, pair.Signature)
+		}
+		return size
+	case *AtomicSwapFulfillment:
+		return missing(tf.PublicKey, tf.Signature)
+	case *LegacyAtomicSwapFulfillment:
+		return missing(tf.PublicKey, tf.Signature)
+	case *anyAtomicSwapFulfillment:
+		return missingSignatureSize(tf.atomicSwapFulfillment)
+	case *HashedTimeLockFulfillment:
+		return missing(tf.PublicKey, tf.Signature)
+	case *PaymentChannelFulfillment:
+		size := missing(tf.Sender.PublicKey, tf.Sender.Signature)
+		if tf.Receiver != nil {
+			size += missing(tf.Receiver.PublicKey, tf.Receiver.Signature)
+		}
+		return size
+	default:
+		return 0
+	}
+}
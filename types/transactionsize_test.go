@@ -0,0 +1,120 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+func TestTransactionEstimateSize(t *testing.T) {
+	ed25519PK := Ed25519PublicKey(crypto.PublicKey{1})
+	secp256k1PK := PublicKey{Algorithm: SignatureAlgoSecp256k1, Key: make(ByteSlice, crypto.Secp256k1PublicKeySize)}
+
+	testCases := []struct {
+		Unsigned Transaction
+		Signed   Transaction
+	}{
+		{
+			Unsigned: Transaction{Version: TransactionVersionOne},
+			Signed:   Transaction{Version: TransactionVersionOne},
+		},
+		{
+			Unsigned: Transaction{
+				Version: TransactionVersionZero,
+				CoinInputs: []CoinInput{{
+					Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(ed25519PK)),
+				}},
+				MinerFees: []Currency{NewCurrency64(1)},
+			},
+			Signed: Transaction{
+				Version: TransactionVersionZero,
+				CoinInputs: []CoinInput{{
+					Fulfillment: NewFulfillment(&SingleSignatureFulfillment{
+						PublicKey: ed25519PK,
+						Signature: make(ByteSlice, crypto.SignatureSize),
+					}),
+				}},
+				MinerFees: []Currency{NewCurrency64(1)},
+			},
+		},
+		{
+			Unsigned: Transaction{
+				Version: TransactionVersionOne,
+				CoinInputs: []CoinInput{
+					{Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(secp256k1PK))},
+					{Fulfillment: NewFulfillment(NewMultiSignatureFulfillment([]PublicKeySignaturePair{
+						{PublicKey: ed25519PK},
+						{PublicKey: ed25519PK, Signature: make(ByteSlice, crypto.SignatureSize)},
+						{PublicKey: secp256k1PK},
+					}))},
+					{}, // nil fulfillment
+				},
+				BlockStakeInputs: []BlockStakeInput{
+					{Fulfillment: NewFulfillment(NewSingleSignatureFulfillment(ed25519PK))},
+				},
+				MinerFees:     []Currency{NewCurrency64(1)},
+				ArbitraryData: []byte("estimate"),
+			},
+			Signed: Transaction{
+				Version: TransactionVersionOne,
+				CoinInputs: []CoinInput{
+					{Fulfillment: NewFulfillment(&SingleSignatureFulfillment{
+						PublicKey: secp256k1PK,
+						Signature: make(ByteSlice, crypto.Secp256k1SignatureSize),
+					})},
+					{Fulfillment: NewFulfillment(NewMultiSignatureFulfillment([]PublicKeySignaturePair{
+						{PublicKey: ed25519PK, Signature: make(ByteSlice, crypto.SignatureSize)},
+						{PublicKey: ed25519PK, Signature: make(ByteSlice, crypto.SignatureSize)},
+						{PublicKey: secp256k1PK, Signature: make(ByteSlice, crypto.Secp256k1SignatureSize)},
+					}))},
+					{Fulfillment: NewFulfillment(&SingleSignatureFulfillment{
+						PublicKey: ed25519PK,
+						Signature: make(ByteSlice, crypto.SignatureSize),
+					})},
+				},
+				BlockStakeInputs: []BlockStakeInput{
+					{Fulfillment: NewFulfillment(&SingleSignatureFulfillment{
+						PublicKey: ed25519PK,
+						Signature: make(ByteSlice, crypto.SignatureSize),
+					})},
+				},
+				MinerFees:     []Currency{NewCurrency64(1)},
+				ArbitraryData: []byte("estimate"),
+			},
+		},
+	}
+	for idx, testCase := range testCases {
+		size, err := testCase.Unsigned.EstimateSize()
+		if err != nil {
+			t.Errorf("test case #%d: failed to estimate size: %v", idx, err)
+			continue
+		}
+		b, err := siabin.Marshal(testCase.Signed)
+		if err != nil {
+			t.Errorf("test case #%d: failed to marshal signed transaction: %v", idx, err)
+			continue
+		}
+		if size != len(b) {
+			t.Errorf("test case #%d: estimated size %d != actual size %d", idx, size, len(b))
+		}
+		// signed transactions are estimated to be exactly their own size
+		size, err = testCase.Signed.EstimateSize()
+		if err != nil {
+			t.Errorf("test case #%d: failed to estimate size of signed transaction: %v", idx, err)
+		} else if size != len(b) {
+			t.Errorf("test case #%d: estimated size %d of signed transaction != actual size %d", idx, size, len(b))
+		}
+	}
+}
+
+func TestChainConstantsTransactionFee(t *testing.T) {
+	cts := StandardnetChainConstants()
+	if fee := cts.TransactionFee(1000); !fee.Equals(cts.MinimumTransactionFee) {
+		t.Errorf("expected only the minimum transaction fee by default, not %s", fee.String())
+	}
+	cts.TransactionPool.FeePerByte = NewCurrency64(10)
+	if fee := cts.TransactionFee(1000); !fee.Equals(cts.MinimumTransactionFee.Add(NewCurrency64(10000))) {
+		t.Errorf("unexpected fee: %s", fee.String())
+	}
+}
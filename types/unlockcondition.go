@@ -186,9 +186,19 @@ type (
 	TransactionValidationContext struct {
 		ValidationContext
 
-		BlockSizeLimit         uint64
-		ArbitraryDataSizeLimit uint64
-		MinimumMinerFee        Currency
+		BlockSizeLimit            uint64
+		ArbitraryDataSizeLimit    uint64
+		AllowedArbitraryDataTypes []ArbitraryDataType
+		MinimumMinerFee           Currency
+		ExtensionDataSizeLimit    uint64
+		OutputMetadataSizeLimit   uint64
+		// LegacyArbitraryData is true if the transaction is validated at a block height
+		// prior to the activation of the arbitrary data envelope rules,
+		// see ChainConstants.ArbitraryDataEnvelopeActivationHeight.
+		LegacyArbitraryData bool
+		// ChainID of the chain the transaction is validated for,
+		// required to validate the fulfillments of replay-protected transactions.
+		ChainID ChainID
 	}
 
 	// TransactionCreationValidationContext is given to any transaction creation validator function,
@@ -197,9 +207,10 @@ type (
 	TransactionCreationValidationContext struct {
 		FundValidationContext
 
-		BlockSizeLimit         uint64
-		ArbitraryDataSizeLimit uint64
-		MinimumMinerFee        Currency
+		BlockSizeLimit            uint64
+		ArbitraryDataSizeLimit    uint64
+		AllowedArbitraryDataTypes []ArbitraryDataType
+		MinimumMinerFee           Currency
 	}
 
 	// FulfillmentSignContext is given as part of the sign call of an UnlockFullment,
@@ -214,6 +225,13 @@ type (
 		// are defined at this point, even though it is allowed that one or multiple
 		// inputs haven't been signed yet, and this should have no influence on the signature.
 		Transaction Transaction
+		// SignatureHashCache is optional, and can be given in order to cache
+		// the signature hash properties shared by all inputs of the (parent) transaction,
+		// when signing multiple fulfillments of the same transaction.
+		SignatureHashCache *SignatureHashCache
+		// ChainID of the chain the transaction belongs to,
+		// only required to sign replay-protected transactions.
+		ChainID ChainID
 		// (Private) key to be used for signing, what type it is or whether it is defined at all
 		// is of no importance, as long as the fulfillment supports its (none) definition.
 		Key interface{}
@@ -232,6 +250,26 @@ type (
 		BlockTime Timestamp
 		// (Parent) transaction the fulfillment belongs to.
 		Transaction Transaction
+		// SignatureHashCache is optional, and can be given in order to cache
+		// the signature hash properties shared by all inputs of the (parent) transaction,
+		// when validating multiple fulfillments of the same transaction.
+		SignatureHashCache *SignatureHashCache
+		// ChainID of the chain the transaction belongs to,
+		// only required to validate replay-protected transactions.
+		ChainID ChainID
+		// OutputOrigin defines the height and time of the block which created
+		// the output being fulfilled, required only by relative lock conditions,
+		// nil if unknown.
+		OutputOrigin *OutputOrigin
+		// SpentCoinOutputs defines the coin outputs spent by the coin inputs
+		// of the (parent) transaction, required only by vesting conditions, nil if unknown.
+		SpentCoinOutputs map[CoinOutputID]CoinOutput
+		// BLSSignatureBatch is optional, and can be given in order to collect
+		// the BLS signature checks, rather than verifying them one by one.
+		// When given, fulfillments can use empty BLS signatures, as long as another
+		// fulfillment in the batch carries their aggregated signature.
+		// It is up to the caller to verify the batch once all fulfillments are checked.
+		BLSSignatureBatch *BLSSignatureBatch
 	}
 
 	// FulfillableContext is given as part of the fulfillable call of an UnlockCondition,
@@ -242,6 +280,15 @@ type (
 		// BlockTime defines the time of the currently last registered block,
 		// the transaction belonged to.
 		BlockTime Timestamp
+		// OutputOrigin defines the height and time of the block which created
+		// the output, required only by relative lock conditions, nil if unknown.
+		OutputOrigin *OutputOrigin
+	}
+
+	// OutputOrigin defines the height and time of the block which created an output.
+	OutputOrigin struct {
+		BlockHeight BlockHeight `json:"blockheight"`
+		BlockTime   Timestamp   `json:"blocktime"`
 	}
 
 	// FundValidationContext is used for coin- and block stake- validators,
@@ -305,6 +352,7 @@ const (
 	// NilCondition,
 	// UnlockHashCondition (0x01 unlock hash type is the only standard one at the moment, others aren't allowed),
 	// MultiSignatureCondition,
+	// WeightedMultiSignatureCondition,
 	// ]
 	ConditionTypeTimeLock
 
@@ -320,6 +368,99 @@ const (
 	//
 	// Implemented by the MultiSignatureCondition type
 	ConditionTypeMultiSignature
+
+	// ConditionTypeColdStaking defines an unlock condition which separates
+	// the right to use an output for block creation from the right to spend it.
+	// Both the owner and the staker are identified by an unlock hash of type UnlockTypePubKey.
+	// The owner can spend the output without restrictions, using a SingleSignatureFulfillment,
+	// while the staker can only use a SingleSignatureFulfillment to respend the output
+	// to the exact same condition, as is done as part of the Proof of Block Stake protocol.
+	// This allows block stakes to be kept in cold storage, while a hot node creates blocks.
+	//
+	// Implemented by the ColdStakingCondition type.
+	ConditionTypeColdStaking
+
+	// ConditionTypeHashedTimeLock defines a hashed time lock condition (HTLC).
+	// Before the lock time is reached, the output can only be claimed by the receiver,
+	// revealing the secret matching the hashed secret. Once the lock time is reached,
+	// the output can no longer be claimed, and can only be refunded to the refunder.
+	// The lock time is either a block height or a timestamp, as defined for TimeLockConditions.
+	// It can be fulfilled only by a HashedTimeLockFulfillment.
+	//
+	// Implemented by the HashedTimeLockCondition type.
+	ConditionTypeHashedTimeLock
+
+	// ConditionTypePaymentChannel defines the funding condition of a unidirectional payment channel,
+	// jointly controlled by the sender and the receiver of the channel.
+	// At any time the output can be spent using the signatures of both the sender and the receiver,
+	// as is done to settle the channel. Once the lock time is reached,
+	// the sender can also spend the output on its own, as to refund an unsettled channel.
+	// The lock time is either a block height or a timestamp, as defined for TimeLockConditions.
+	// It can be fulfilled only by a PaymentChannelFulfillment.
+	//
+	// Implemented by the PaymentChannelCondition type.
+	ConditionTypePaymentChannel
+
+	// ConditionTypeBurn defines a condition which can never be fulfilled,
+	// making the paired output provably unspendable, as to burn the coins or block stakes it holds.
+	// Contrary to the NilCondition, which can be fulfilled by anyone,
+	// there is no fulfillment for this condition. Burned coins and block stakes
+	// are accounted for by the consensus set, as they are removed from the circulating supply.
+	//
+	// Implemented by the BurnCondition type.
+	ConditionTypeBurn
+
+	// ConditionTypeRelativeTimeLock defines an unlock condition
+	// which locks another condition for a relative amount of blocks or seconds,
+	// counted from the height or time of the block which created the output,
+	// rather than using an absolute lock time as is done by the TimeLockCondition.
+	// The internal condition has to be one of: [
+	// NilCondition,
+	// UnlockHashCondition (0x01 unlock hash type is the only standard one at the moment, others aren't allowed),
+	// MultiSignatureCondition,
+	// WeightedMultiSignatureCondition,
+	// ]
+	//
+	// Implemented by the RelativeTimeLockCondition type.
+	ConditionTypeRelativeTimeLock
+
+	// ConditionTypeVesting defines an unlock condition which releases
+	// the coins of its paired output gradually, following a linear release schedule.
+	// Once the cliff is passed, the total amount vests in equal parts at the end of each period,
+	// counted from the start of the schedule. The output can be spent at any time by the owner,
+	// using a SingleSignatureFulfillment, as long as the transaction respends the amount
+	// which isn't yet vested as a single coin output to the exact same condition.
+	//
+	// Implemented by the VestingCondition type.
+	ConditionTypeVesting
+
+	// ConditionTypeWeightedMultiSignature defines an unlock condition which
+	// can only be unlocked by multiple signatures, where each signatory has a weight.
+	// The accepted signatories are declared up front, by specifying for each of them
+	// the unlockhash created from its public key together with its weight.
+	// The output can only be spent once the total weight of the signatories
+	// who provided a signature, reaches the minimum weight defined by the condition.
+	// It is fulfilled by a MultiSignatureFulfillment, same as the MultiSignatureCondition.
+	//
+	// Implemented by the WeightedMultiSignatureCondition type.
+	ConditionTypeWeightedMultiSignature
+
+	// ConditionTypeAnyOf defines a composite unlock condition, which nests
+	// two or more other conditions, of which any single one has to be fulfilled (boolean OR).
+	// It allows policies such as "(2-of-3 multisig) OR (single key after 1 year)".
+	// Composite conditions can be nested, up to MaxCompositeConditionDepth levels deep.
+	// It can be fulfilled only by a CompositeFulfillment, fulfilling exactly one of its conditions.
+	//
+	// Implemented by the AnyOfCondition type.
+	ConditionTypeAnyOf
+
+	// ConditionTypeAllOf defines a composite unlock condition, which nests
+	// two or more other conditions, all of which have to be fulfilled (boolean AND).
+	// Composite conditions can be nested, up to MaxCompositeConditionDepth levels deep.
+	// It can be fulfilled only by a CompositeFulfillment, fulfilling each of its conditions.
+	//
+	// Implemented by the AllOfCondition type.
+	ConditionTypeAllOf
 )
 
 // The following enumeration defines the different possible and standard
@@ -362,6 +503,26 @@ const (
 	//
 	// Implemented by the MultiSignatureFulfillment type
 	FulfillmentTypeMultiSignature
+	// FulfillmentTypeHashedTimeLock defines the fulfillment of a HashedTimeLockCondition,
+	// defined by a public key and signature, and optionally a secret.
+	// When a secret is given it is used to claim the output,
+	// otherwise it is used to refund the output.
+	//
+	// Implemented by the HashedTimeLockFulfillment type.
+	FulfillmentTypeHashedTimeLock
+	// FulfillmentTypePaymentChannel defines the fulfillment of a PaymentChannelCondition,
+	// defined by the public key and signature of the sender, and optionally those of the receiver.
+	// When the receiver is defined it is used to settle the channel,
+	// otherwise it is used by the sender to refund the channel.
+	//
+	// Implemented by the PaymentChannelFulfillment type.
+	FulfillmentTypePaymentChannel
+	// FulfillmentTypeComposite defines the fulfillment of an AnyOfCondition or AllOfCondition,
+	// defined by one or multiple branches, each pairing the index of a nested condition
+	// with the fulfillment of that condition.
+	//
+	// Implemented by the CompositeFulfillment type.
+	FulfillmentTypeComposite
 )
 
 // Constants that are used as part of AtomicSwap Conditions/Fulfillments.
@@ -378,49 +539,95 @@ const (
 var (
 	// ErrUnexpectedUnlockCondition is returned when a fulfillment is given
 	// an UnlockCondition of an unexpected type.
-	ErrUnexpectedUnlockCondition = errors.New("unexpected unlock condition")
+	ErrUnexpectedUnlockCondition = NewError(ErrorCodeUnexpectedUnlockCondition, "unexpected unlock condition")
 
 	// ErrUnexpectedUnlockFulfillment is returned when an UnlockCondition is given
 	// an UnlockFulfillment of an unexpected type.
-	ErrUnexpectedUnlockFulfillment = errors.New("unexpected unlock fulfillment")
+	ErrUnexpectedUnlockFulfillment = NewError(ErrorCodeUnexpectedUnlockFulfillment, "unexpected unlock fulfillment")
 
 	// ErrUnexpectedUnlockType is returned when an unlock hash has the wrong type.
-	ErrUnexpectedUnlockType = errors.New("unexpected unlock (hash) type")
+	ErrUnexpectedUnlockType = NewError(ErrorCodeUnexpectedUnlockType, "unexpected unlock (hash) type")
 
 	// ErrFulfillmentDoubleSign is returned when a fulfillment that is already signed,
 	// is attempted to be signed once again.
-	ErrFulfillmentDoubleSign = errors.New("cannot sign a fulfillment which is already signed")
+	ErrFulfillmentDoubleSign = NewError(ErrorCodeFulfillmentDoubleSign, "cannot sign a fulfillment which is already signed")
 
 	// ErrUnknownConditionType is returned to define the non-standardness
 	// of an UnknownUnlockCondition.
-	ErrUnknownConditionType = errors.New("unknown condition type")
+	ErrUnknownConditionType = NewError(ErrorCodeUnknownConditionType, "unknown condition type")
 	// ErrUnknownFulfillmentType is returned to define the non-standardness
 	// of an UnknownUnlockFulfillment.
-	ErrUnknownFulfillmentType = errors.New("unknown fulfillment type")
+	ErrUnknownFulfillmentType = NewError(ErrorCodeUnknownFulfillmentType, "unknown fulfillment type")
 
 	// ErrNilFulfillmentType is returned by pretty much any method of the
 	// NilFullfilment type, as it is not to be used for anything.
-	ErrNilFulfillmentType = errors.New("nil fulfillment type")
+	ErrNilFulfillmentType = NewError(ErrorCodeNilFulfillmentType, "nil fulfillment type")
 
 	// ErrUnknownSignAlgorithmType is an error returned in case
 	// one tries to sign using an unknown signing algorithm type.
 	//
 	// NOTE That verification of unknown signing algorithm types does always succeed!
-	ErrUnknownSignAlgorithmType = errors.New("unknown signature algorithm type")
+	ErrUnknownSignAlgorithmType = NewError(ErrorCodeUnknownSignAlgorithmType, "unknown signature algorithm type")
 
 	// ErrInsufficientSignatures is an error returned when a multisig
 	// condition is attempted to be fulfilled, but the fulfillment does not
 	// (yet) have the required amount of signatures
-	ErrInsufficientSignatures = errors.New("not enough signatures")
+	ErrInsufficientSignatures = NewError(ErrorCodeInsufficientSignatures, "not enough signatures")
 
 	// ErrUnauthorizedPubKey is an error returned when a public key used in a multisig
 	// fulfillment is not allowed to unlock the input (as the associated pubkey hash is not
 	// listed in the conditions unlockhashes)
-	ErrUnauthorizedPubKey = errors.New("public key used which is not allowed to sign this input")
+	ErrUnauthorizedPubKey = NewError(ErrorCodeUnauthorizedPubKey, "public key used which is not allowed to sign this input")
 
 	// ErrPrematureRefund is an error returned when a refund is requested for a contract,
 	// while the contract is still active, and thus not yet expired.
-	ErrPrematureRefund = errors.New("contract cannot yet be refunded")
+	ErrPrematureRefund = NewError(ErrorCodePrematureRefund, "contract cannot yet be refunded")
+
+	// ErrInvalidColdStakingRespend is an error returned when the staker of a cold staking condition
+	// attempts to fulfill the condition as part of a transaction which does more than
+	// respending the block stakes to the exact same cold staking condition.
+	ErrInvalidColdStakingRespend = NewError(ErrorCodeInvalidColdStakingRespend, "cold staking output can only be respent to itself by its staker")
+
+	// ErrExpiredClaim is an error returned when a claim is attempted for a contract,
+	// while the contract has already expired, and thus can only be refunded.
+	ErrExpiredClaim = NewError(ErrorCodeExpiredClaim, "contract has expired and can no longer be claimed")
+
+	// ErrBurnedOutput is an error returned when an output locked by a BurnCondition is spent,
+	// something which is never possible.
+	ErrBurnedOutput = NewError(ErrorCodeBurnedOutput, "burned output can never be spent")
+
+	// ErrCompositeConditionTooDeep is an error returned when a composite condition or fulfillment
+	// nests composite conditions or fulfillments deeper than MaxCompositeConditionDepth.
+	ErrCompositeConditionTooDeep = NewError(ErrorCodeCompositeConditionTooDeep, "composite condition exceeds the maximum nesting depth")
+
+	// ErrWrongPublicKey is an error returned when a fulfillment provides
+	// a public key which does not match the unlock hash of the condition it fulfills.
+	ErrWrongPublicKey = NewError(ErrorCodeWrongPublicKey, "single signature fulfillment provides wrong public key")
+
+	// ErrUnlockHashMismatch is an error returned when the unlock hash produced by a fulfillment
+	// does not equal the unlock hash of the condition it fulfills.
+	ErrUnlockHashMismatch = NewError(ErrorCodeUnlockHashMismatch, "produced unlock hash doesn't equal the expected unlock hash")
+
+	// ErrLockTimeNotReached is an error returned when a time lock condition
+	// is fulfilled prior to its lock time being reached.
+	ErrLockTimeNotReached = NewError(ErrorCodeLockTimeNotReached, "time lock has not yet been reached")
+	// ErrRelativeLockTimeNotReached is an error returned when a relative time lock condition
+	// is fulfilled prior to its relative lock time being passed.
+	ErrRelativeLockTimeNotReached = NewError(ErrorCodeLockTimeNotReached, "relative time lock has not yet been reached")
+
+	// ErrUnknownOutputOrigin is an error returned when a relative time lock condition
+	// is fulfilled without the origin of its output being known.
+	ErrUnknownOutputOrigin = NewError(ErrorCodeUnknownOutputOrigin, "relative time lock requires the origin of the output to be known")
+
+	// ErrUnknownSpentCoinOutputs is an error returned when a vesting condition, which isn't fully vested,
+	// is fulfilled without the coin outputs spent by the parent transaction being known.
+	ErrUnknownSpentCoinOutputs = NewError(ErrorCodeUnknownSpentCoinOutputs, "vesting condition requires the spent coin outputs of the transaction to be known")
+
+	// ErrInvalidSignature is an error returned when a fulfillment provides an invalid signature.
+	ErrInvalidSignature = NewError(ErrorCodeInvalidSignature, "invalid signature")
+
+	// ErrNilPublicKey is an error returned when a fulfillment provides a nil public key.
+	ErrNilPublicKey = NewError(ErrorCodeNilPublicKey, "public key is nil")
 )
 
 // RegisterUnlockConditionType is used to register a condition type, by linking it to
@@ -471,6 +678,18 @@ var (
 		ConditionTypeAtomicSwap:     func() MarshalableUnlockCondition { return &AtomicSwapCondition{} },
 		ConditionTypeTimeLock:       func() MarshalableUnlockCondition { return &TimeLockCondition{} },
 		ConditionTypeMultiSignature: func() MarshalableUnlockCondition { return &MultiSignatureCondition{} },
+		ConditionTypeColdStaking:    func() MarshalableUnlockCondition { return &ColdStakingCondition{} },
+		ConditionTypeHashedTimeLock: func() MarshalableUnlockCondition { return &HashedTimeLockCondition{} },
+		ConditionTypePaymentChannel: func() MarshalableUnlockCondition { return &PaymentChannelCondition{} },
+		ConditionTypeBurn:           func() MarshalableUnlockCondition { return &BurnCondition{} },
+
+		ConditionTypeRelativeTimeLock: func() MarshalableUnlockCondition { return &RelativeTimeLockCondition{} },
+		ConditionTypeVesting:          func() MarshalableUnlockCondition { return &VestingCondition{} },
+
+		ConditionTypeWeightedMultiSignature: func() MarshalableUnlockCondition { return &WeightedMultiSignatureCondition{} },
+
+		ConditionTypeAnyOf: func() MarshalableUnlockCondition { return &AnyOfCondition{} },
+		ConditionTypeAllOf: func() MarshalableUnlockCondition { return &AllOfCondition{} },
 	}
 	// Manipulated by the RegisterUnlockFulfillmentType function,
 	// and used by the UnlockFulfillmentProxy.
@@ -479,6 +698,9 @@ var (
 		FulfillmentTypeSingleSignature: func() MarshalableUnlockFulfillment { return &SingleSignatureFulfillment{} },
 		FulfillmentTypeAtomicSwap:      func() MarshalableUnlockFulfillment { return &anyAtomicSwapFulfillment{} },
 		FulfillmentTypeMultiSignature:  func() MarshalableUnlockFulfillment { return &MultiSignatureFulfillment{} },
+		FulfillmentTypeHashedTimeLock:  func() MarshalableUnlockFulfillment { return &HashedTimeLockFulfillment{} },
+		FulfillmentTypePaymentChannel:  func() MarshalableUnlockFulfillment { return &PaymentChannelFulfillment{} },
+		FulfillmentTypeComposite:       func() MarshalableUnlockFulfillment { return &CompositeFulfillment{} },
 	}
 )
 
@@ -529,6 +751,24 @@ type (
 		Signature ByteSlice        `json:"signature"`
 		Secret    AtomicSwapSecret `json:"secret,omitempty"`
 	}
+
+	// BurnCondition implements the ConditionTypeBurn ConditionType.
+	// See ConditionTypeBurn for more information.
+	BurnCondition struct{} // can never be fulfilled
+
+	// PaymentChannelCondition implements the ConditionTypePaymentChannel ConditionType.
+	// See ConditionTypePaymentChannel for more information.
+	PaymentChannelCondition struct {
+		Sender   UnlockHash `json:"sender"`
+		Receiver UnlockHash `json:"receiver"`
+		LockTime uint64     `json:"locktime"`
+	}
+	// PaymentChannelFulfillment implements the FulfillmentTypePaymentChannel FulfillmentType.
+	// See FulfillmentTypePaymentChannel for more information.
+	PaymentChannelFulfillment struct {
+		Sender   PublicKeySignaturePair  `json:"sender"`
+		Receiver *PublicKeySignaturePair `json:"receiver,omitempty"`
+	}
 	// LegacyAtomicSwapFulfillment implements the (legacy) FulfillmentTypeAtomicSwap (unlock) FulfillmentType.
 	// See FulfillmentTypeAtomicSwap for more information.
 	LegacyAtomicSwapFulfillment struct { // legacy fulfillment as used in transactions of version 0
@@ -561,6 +801,40 @@ type (
 		Condition MarshalableUnlockCondition
 	}
 
+	// RelativeTimeLockCondition defines an unlock condition which requires
+	// a relative LockTime to be passed since the creation of the output,
+	// on top of some other defined condition, which both have to be fulfilled
+	// in order to unlock/spend/use the unspent output as an input.
+	RelativeTimeLockCondition struct {
+		// LockTime defines the amount of blocks or seconds,
+		// depending on the Unit, the output is locked since its creation.
+		LockTime uint64
+		// Unit defines whether the LockTime is expressed in blocks or seconds.
+		Unit RelativeLockTimeUnit
+		// Condition defines the condition which has to be fulfilled
+		// on top of the relative LockTime defined by this condition.
+		// See ConditionTypeRelativeTimeLock in order to know which conditions are supported.
+		Condition MarshalableUnlockCondition
+	}
+
+	// VestingCondition implements the ConditionTypeVesting ConditionType.
+	// See ConditionTypeVesting for more information.
+	VestingCondition struct {
+		// Owner is the unlock hash (of the public key type) which can spend the vested coins.
+		Owner UnlockHash `json:"owner"`
+		// TotalAmount is the total amount of coins released by the schedule.
+		TotalAmount Currency `json:"totalamount"`
+		// StartTime is the (unix epoch seconds) timestamp at which the schedule starts.
+		StartTime Timestamp `json:"starttime"`
+		// CliffDuration is the amount of seconds, counted from the start time,
+		// prior to which none of the coins are vested.
+		CliffDuration uint64 `json:"cliffduration"`
+		// PeriodDuration is the amount of seconds of a single vesting period.
+		PeriodDuration uint64 `json:"periodduration"`
+		// PeriodCount is the amount of periods over which the total amount vests.
+		PeriodCount uint64 `json:"periodcount"`
+	}
+
 	// MultiSignatureCondition implements the ConditionTypeMultiSignature ConditionType.
 	// See ConditionTypeMultiSignature for more information.
 	MultiSignatureCondition struct {
@@ -568,6 +842,42 @@ type (
 		MinimumSignatureCount uint64          `json:"minimumsignaturecount"`
 	}
 
+	// WeightedMultiSignatureCondition implements the ConditionTypeWeightedMultiSignature ConditionType.
+	// See ConditionTypeWeightedMultiSignature for more information.
+	WeightedMultiSignatureCondition struct {
+		Signatories   []WeightedUnlockHash `json:"signatories"`
+		MinimumWeight uint64               `json:"minimumweight"`
+	}
+
+	// WeightedUnlockHash is an unlock hash (of the public key type)
+	// paired with the weight of its signature, as used by the WeightedMultiSignatureCondition.
+	WeightedUnlockHash struct {
+		UnlockHash UnlockHash `json:"unlockhash"`
+		Weight     uint64     `json:"weight"`
+	}
+
+	// AnyOfCondition implements the ConditionTypeAnyOf ConditionType.
+	// See ConditionTypeAnyOf for more information.
+	AnyOfCondition struct {
+		Conditions []UnlockConditionProxy `json:"conditions"`
+	}
+	// AllOfCondition implements the ConditionTypeAllOf ConditionType.
+	// See ConditionTypeAllOf for more information.
+	AllOfCondition struct {
+		Conditions []UnlockConditionProxy `json:"conditions"`
+	}
+	// CompositeFulfillment implements the FulfillmentTypeComposite FulfillmentType.
+	// See FulfillmentTypeComposite for more information.
+	CompositeFulfillment struct {
+		Branches []CompositeFulfillmentBranch `json:"branches"`
+	}
+	// CompositeFulfillmentBranch pairs the index of a condition nested
+	// by a composite condition, with the fulfillment which fulfills that condition.
+	CompositeFulfillmentBranch struct {
+		Index       uint64                 `json:"index"`
+		Fulfillment UnlockFulfillmentProxy `json:"fulfillment"`
+	}
+
 	// MultiSignatureFulfillment implements the FulfillmentTypeMultiSignature FulfillmentType.
 	// See FulfillmentTypeMultiSignature for more information.
 	MultiSignatureFulfillment struct {
@@ -581,6 +891,29 @@ type (
 		Signature ByteSlice `json:"signature"`
 	}
 
+	// ColdStakingCondition implements the ConditionTypeColdStaking ConditionType.
+	// See ConditionTypeColdStaking for more information.
+	ColdStakingCondition struct {
+		Owner  UnlockHash `json:"owner"`
+		Staker UnlockHash `json:"staker"`
+	}
+
+	// HashedTimeLockCondition implements the ConditionTypeHashedTimeLock ConditionType.
+	// See ConditionTypeHashedTimeLock for more information.
+	HashedTimeLockCondition struct {
+		Receiver     UnlockHash             `json:"receiver"`
+		Refunder     UnlockHash             `json:"refunder"`
+		HashedSecret AtomicSwapHashedSecret `json:"hashedsecret"`
+		LockTime     uint64                 `json:"locktime"`
+	}
+	// HashedTimeLockFulfillment implements the FulfillmentTypeHashedTimeLock FulfillmentType.
+	// See FulfillmentTypeHashedTimeLock for more information.
+	HashedTimeLockFulfillment struct {
+		PublicKey PublicKey        `json:"publickey"`
+		Signature ByteSlice        `json:"signature"`
+		Secret    AtomicSwapSecret `json:"secret,omitempty"`
+	}
+
 	// KeyPair is a matching public and private key
 	KeyPair struct {
 		PublicKey  PublicKey
@@ -612,12 +945,12 @@ var (
 	// ErrInvalidPreImageSha256 is returned as the result of a failed fulfillment,
 	// in case the condition-defined hashed secret (pre image) does not match
 	// the fulfillment-defined secret (image).
-	ErrInvalidPreImageSha256 = errors.New("invalid pre-image sha256")
+	ErrInvalidPreImageSha256 = NewError(ErrorCodeInvalidPreImageSha256, "invalid pre-image sha256")
 	// ErrInvalidRedeemer is returned in case the redeemer, one of two parties,
 	// is the wrong redeemer due to the timelock rule.
 	// Prior to the timelock only the receiver can redeem,
 	// while after that timelock only the sender can redeem.
-	ErrInvalidRedeemer = errors.New("invalid input redeemer")
+	ErrInvalidRedeemer = NewError(ErrorCodeInvalidRedeemer, "invalid input redeemer")
 )
 
 // Fulfill implements UnlockCondition.Fulfill
@@ -625,7 +958,7 @@ func (n *NilCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext
 	switch tf := fulfillment.(type) {
 	case *SingleSignatureFulfillment:
 		return verifyHashUsingPublicKey(tf.PublicKey,
-			ctx.Transaction, tf.Signature, ctx.ExtraObjects)
+			ctx, tf.Signature, ctx.ExtraObjects)
 	default:
 		return ErrUnexpectedUnlockFulfillment
 	}
@@ -710,9 +1043,9 @@ func (uh *UnlockHashCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 			return err
 		}
 		if euh != uh.TargetUnlockHash {
-			return errors.New("single signature fulfillment provides wrong public key")
+			return ErrWrongPublicKey
 		}
-		return verifyHashUsingPublicKey(tf.PublicKey, ctx.Transaction, tf.Signature, ctx.ExtraObjects)
+		return verifyHashUsingPublicKey(tf.PublicKey, ctx, tf.Signature, ctx.ExtraObjects)
 
 	case *LegacyAtomicSwapFulfillment:
 		// only UnlockTypeAtomicSwap is supported when fulfilling using a LegacyAtomicSwapFulfillment
@@ -731,7 +1064,7 @@ func (uh *UnlockHashCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 		}
 		ourHS := NewUnlockHash(UnlockTypeAtomicSwap, ourConditionHash)
 		if ourHS.Cmp(uh.TargetUnlockHash) != 0 {
-			return errors.New("produced unlock hash doesn't equal the expected unlock hash")
+			return ErrUnlockHashMismatch
 		}
 
 		// create the unlockHash for the given public Key
@@ -749,7 +1082,7 @@ func (uh *UnlockHashCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 
 			// verify signature
 			err := verifyHashUsingPublicKey(
-				tf.PublicKey, ctx.Transaction, tf.Signature,
+				tf.PublicKey, ctx, tf.Signature,
 				mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey, tf.Secret))
 			if err != nil {
 				return err
@@ -773,7 +1106,7 @@ func (uh *UnlockHashCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 		// after the deadline (timelock),
 		// only the original sender can reclaim the unspend output
 		return verifyHashUsingPublicKey(
-			tf.PublicKey, ctx.Transaction, tf.Signature,
+			tf.PublicKey, ctx, tf.Signature,
 			mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey))
 
 	case *anyAtomicSwapFulfillment:
@@ -838,7 +1171,7 @@ func (ss *SingleSignatureFulfillment) Sign(ctx FulfillmentSignContext) (err erro
 		return ErrFulfillmentDoubleSign
 	}
 
-	ss.Signature, err = signHashUsingPublicKey(ss.PublicKey, ctx.Transaction, ctx.Key, ctx.ExtraObjects)
+	ss.Signature, err = signHashUsingPublicKey(ss.PublicKey, ctx.signatureHasher(), ctx.Key, ctx.ExtraObjects)
 	return
 }
 
@@ -913,7 +1246,7 @@ func (as *AtomicSwapCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 
 			// verify signature
 			return verifyHashUsingPublicKey(
-				tf.PublicKey, ctx.Transaction, tf.Signature,
+				tf.PublicKey, ctx, tf.Signature,
 				mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey, tf.Secret))
 		}
 
@@ -930,7 +1263,7 @@ func (as *AtomicSwapCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fulfil
 		}
 		// verify the signature is indeed done by
 		return verifyHashUsingPublicKey(
-			tf.PublicKey, ctx.Transaction, tf.Signature,
+			tf.PublicKey, ctx, tf.Signature,
 			mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey))
 
 	case *LegacyAtomicSwapFulfillment:
@@ -1060,7 +1393,7 @@ func (as *AtomicSwapFulfillment) Sign(ctx FulfillmentSignContext) error {
 		// sign as claimer
 		var err error
 		as.Signature, err = signHashUsingPublicKey(
-			as.PublicKey, ctx.Transaction, ctx.Key,
+			as.PublicKey, ctx.signatureHasher(), ctx.Key,
 			mergeExtraObjects(ctx.ExtraObjects, as.PublicKey, as.Secret))
 		return err
 	}
@@ -1068,7 +1401,7 @@ func (as *AtomicSwapFulfillment) Sign(ctx FulfillmentSignContext) error {
 	// sign as refunder
 	var err error
 	as.Signature, err = signHashUsingPublicKey(
-		as.PublicKey, ctx.Transaction, ctx.Key,
+		as.PublicKey, ctx.signatureHasher(), ctx.Key,
 		mergeExtraObjects(ctx.ExtraObjects, as.PublicKey))
 	return err
 }
@@ -1123,7 +1456,7 @@ func (as *LegacyAtomicSwapFulfillment) Sign(ctx FulfillmentSignContext) error {
 		// sign as claimer
 		var err error
 		as.Signature, err = signHashUsingPublicKey(
-			as.PublicKey, ctx.Transaction, ctx.Key,
+			as.PublicKey, ctx.signatureHasher(), ctx.Key,
 			mergeExtraObjects(ctx.ExtraObjects, as.PublicKey, as.Secret))
 		return err
 	}
@@ -1131,7 +1464,7 @@ func (as *LegacyAtomicSwapFulfillment) Sign(ctx FulfillmentSignContext) error {
 	// sign as refunder
 	var err error
 	as.Signature, err = signHashUsingPublicKey(
-		as.PublicKey, ctx.Transaction, ctx.Key,
+		as.PublicKey, ctx.signatureHasher(), ctx.Key,
 		mergeExtraObjects(ctx.ExtraObjects, as.PublicKey))
 	return err
 }
@@ -1209,12 +1542,23 @@ var (
 	_ MarshalableUnlockCondition = (*UnlockHashCondition)(nil)
 	_ MarshalableUnlockCondition = (*AtomicSwapCondition)(nil)
 	_ MarshalableUnlockCondition = (*MultiSignatureCondition)(nil)
+	_ MarshalableUnlockCondition = (*HashedTimeLockCondition)(nil)
+	_ MarshalableUnlockCondition = (*PaymentChannelCondition)(nil)
+	_ MarshalableUnlockCondition = (*BurnCondition)(nil)
+	_ MarshalableUnlockCondition = (*RelativeTimeLockCondition)(nil)
+	_ MarshalableUnlockCondition = (*VestingCondition)(nil)
+	_ MarshalableUnlockCondition = (*WeightedMultiSignatureCondition)(nil)
+	_ MarshalableUnlockCondition = (*AnyOfCondition)(nil)
+	_ MarshalableUnlockCondition = (*AllOfCondition)(nil)
 
 	_ MarshalableUnlockFulfillment = (*NilFulfillment)(nil)
 	_ MarshalableUnlockFulfillment = (*SingleSignatureFulfillment)(nil)
 	_ MarshalableUnlockFulfillment = (*AtomicSwapFulfillment)(nil)
 	_ MarshalableUnlockFulfillment = (*LegacyAtomicSwapFulfillment)(nil)
 	_ MarshalableUnlockFulfillment = (*MultiSignatureFulfillment)(nil)
+	_ MarshalableUnlockFulfillment = (*HashedTimeLockFulfillment)(nil)
+	_ MarshalableUnlockFulfillment = (*PaymentChannelFulfillment)(nil)
+	_ MarshalableUnlockFulfillment = (*CompositeFulfillment)(nil)
 )
 
 // NewAtomicSwapHashedSecret creates a new atomic swap hashed secret,
@@ -1400,7 +1744,7 @@ func NewTimeLockCondition(lockTime uint64, condition MarshalableUnlockCondition)
 // The TimeLockFulfillment can only be used to fulfill a TimeLockCondition.
 func (tl *TimeLockCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
 	if !tl.Fulfillable(FulfillableContext{BlockHeight: ctx.BlockHeight, BlockTime: ctx.BlockTime}) {
-		return errors.New("time lock has not yet been reached")
+		return ErrLockTimeNotReached
 	}
 
 	// time lock hash been reached,
@@ -1433,7 +1777,7 @@ func (tl *TimeLockCondition) IsStandardCondition(ctx ValidationContext) error {
 			return errors.New("non-standard unlock hash type")
 		}
 		return nil
-	case ConditionTypeMultiSignature:
+	case ConditionTypeMultiSignature, ConditionTypeWeightedMultiSignature:
 		return tl.Condition.IsStandardCondition(ctx)
 	case ConditionTypeNil:
 		return nil
@@ -1608,7 +1952,7 @@ func (ms *MultiSignatureCondition) Fulfill(fulfillment UnlockFulfillment, ctx Fu
 	// Finally verify all the signatures
 	for _, pks := range tf.Pairs {
 		if err := verifyHashUsingPublicKey(
-			pks.PublicKey, ctx.Transaction, pks.Signature,
+			pks.PublicKey, ctx, pks.Signature,
 			mergeExtraObjects(ctx.ExtraObjects, pks.PublicKey),
 		); err != nil {
 			return err
@@ -1802,7 +2146,7 @@ func (ms *MultiSignatureFulfillment) Sign(ctx FulfillmentSignContext) (err error
 	}
 
 	signature, err := signHashUsingPublicKey(
-		keypair.PublicKey, ctx.Transaction, keypair.PrivateKey,
+		keypair.PublicKey, ctx.signatureHasher(), keypair.PrivateKey,
 		mergeExtraObjects(ctx.ExtraObjects, keypair.PublicKey))
 	if err != nil {
 		return
@@ -1823,162 +2167,1870 @@ func (ms *MultiSignatureFulfillment) Unmarshal(b []byte, f UnmarshalFunc) error
 	return f(b, &ms.Pairs)
 }
 
-// MarshalSia implements siabin.SiaMarshaler.MarshalSia
-//
-// Marshals this ConditionType as a single byte.
-func (ct ConditionType) MarshalSia(w io.Writer) error {
-	_, err := w.Write([]byte{byte(ct)})
-	return err
+// NewColdStakingCondition creates a new cold staking condition,
+// allowing the staker to use the output for block creation,
+// while only the owner can spend it.
+func NewColdStakingCondition(owner, staker UnlockHash) *ColdStakingCondition {
+	return &ColdStakingCondition{
+		Owner:  owner,
+		Staker: staker,
+	}
 }
 
-// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
-//
-// Unmarshals this ConditionType from a single byte.
-func (ct *ConditionType) UnmarshalSia(r io.Reader) error {
-	var b [1]byte
-	_, err := r.Read(b[:])
-	*ct = ConditionType(b[0])
-	return err
+// Fulfill implements UnlockCondition.Fulfill
+func (cs *ColdStakingCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*SingleSignatureFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+	euh, err := NewPubKeyUnlockHash(tf.PublicKey)
+	if err != nil {
+		return err
+	}
+	switch {
+	case euh.Cmp(cs.Owner) == 0:
+		// the owner can spend the output as it sees fit
+	case euh.Cmp(cs.Staker) == 0:
+		// the staker can only respend the output to itself,
+		// as is done when creating a block
+		if err = cs.validateStakerRespend(ctx.Transaction); err != nil {
+			return err
+		}
+	default:
+		return ErrWrongPublicKey
+	}
+	return verifyHashUsingPublicKey(tf.PublicKey, ctx, tf.Signature, ctx.ExtraObjects)
 }
 
-// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
-//
-// Marshals this ConditionType as a single byte.
-func (ct ConditionType) MarshalRivine(w io.Writer) error {
-	return rivbin.MarshalUint8(w, uint8(ct))
+// validateStakerRespend ensures the given transaction does nothing more
+// than respending block stakes to this cold staking condition.
+func (cs *ColdStakingCondition) validateStakerRespend(txn Transaction) error {
+	if txn.Version != TransactionVersionZero && txn.Version != TransactionVersionOne {
+		return ErrInvalidColdStakingRespend
+	}
+	if txn.Extension != nil || len(txn.CoinInputs) != 0 || len(txn.CoinOutputs) != 0 || len(txn.MinerFees) != 0 {
+		return ErrInvalidColdStakingRespend
+	}
+	if len(txn.BlockStakeOutputs) == 0 {
+		return ErrInvalidColdStakingRespend
+	}
+	for _, bso := range txn.BlockStakeOutputs {
+		if !cs.Equal(bso.Condition.Condition) {
+			return ErrInvalidColdStakingRespend
+		}
+	}
+	return nil
 }
 
-// UnmarshalRivine implements rivbin.RivineUnmarshaler.UnmarshalRivine
-//
-// Unmarshals this ConditionType from a single byte.
-func (ct *ConditionType) UnmarshalRivine(r io.Reader) error {
-	x, err := rivbin.UnmarshalUint8(r)
-	if err != nil {
-		return err
+// ConditionType implements UnlockCondition.ConditionType
+func (cs *ColdStakingCondition) ConditionType() ConditionType { return ConditionTypeColdStaking }
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (cs *ColdStakingCondition) IsStandardCondition(ValidationContext) error {
+	if cs.Owner.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash owner type: %d", cs.Owner.Type)
 	}
-	*ct = ConditionType(x)
-	return err
+	if cs.Staker.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash staker type: %d", cs.Staker.Type)
+	}
+	if cs.Owner.Hash == (crypto.Hash{}) || cs.Staker.Hash == (crypto.Hash{}) {
+		return errors.New("nil crypto hash cannot be used as unlock hash")
+	}
+	if cs.Owner.Cmp(cs.Staker) == 0 {
+		return errors.New("owner and staker of a cold staking condition have to be different")
+	}
+	return nil
 }
 
-// MarshalSia implements siabin.SiaMarshaler.MarshalSia
+// UnlockHash implements UnlockCondition.UnlockHash
 //
-// Marshals this FulfillmentType as a single byte.
-func (ft FulfillmentType) MarshalSia(w io.Writer) error {
-	_, err := w.Write([]byte{byte(ft)})
-	return err
+// The unlock hash of the owner is returned,
+// as it is the owner who can spend the output.
+func (cs *ColdStakingCondition) UnlockHash() UnlockHash {
+	return cs.Owner
 }
 
-// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
-//
-// Unmarshals this FulfillmentType from a single byte.
-func (ft *FulfillmentType) UnmarshalSia(r io.Reader) error {
-	var b [1]byte
-	_, err := r.Read(b[:])
-	*ft = FulfillmentType(b[0])
-	return err
+// StakerUnlockHash returns the unlock hash of the staker,
+// the one allowed to use the output for block creation.
+func (cs *ColdStakingCondition) StakerUnlockHash() UnlockHash {
+	return cs.Staker
 }
 
-// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
-//
-// Marshals this FulfillmentType as a single byte.
-func (ft FulfillmentType) MarshalRivine(w io.Writer) error {
-	return rivbin.MarshalUint8(w, uint8(ft))
+// Equal implements UnlockCondition.Equal
+func (cs *ColdStakingCondition) Equal(c UnlockCondition) bool {
+	ocs, ok := c.(*ColdStakingCondition)
+	if !ok {
+		return false
+	}
+	return cs.Owner.Cmp(ocs.Owner) == 0 && cs.Staker.Cmp(ocs.Staker) == 0
 }
 
-// UnmarshalRivin implements rivbin.RivineUnmarshaler.UnmarshalRivin
-//
-// Unmarshals this FulfillmentType from a single byte.
-func (ft *FulfillmentType) UnmarshalRivin(r io.Reader) error {
-	x, err := rivbin.UnmarshalUint8(r)
-	if err != nil {
-		return err
+// Fulfillable implements UnlockCondition.Fulfillable
+func (cs *ColdStakingCondition) Fulfillable(FulfillableContext) bool { return true }
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (cs *ColdStakingCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(cs.Owner, cs.Staker)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (cs *ColdStakingCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &cs.Owner, &cs.Staker)
+}
+
+// NewHashedTimeLockCondition creates a new hashed time lock condition,
+// which can be claimed by the receiver revealing the secret before the lock time is reached,
+// or refunded to the refunder once the lock time is reached.
+func NewHashedTimeLockCondition(receiver, refunder UnlockHash, hashedSecret AtomicSwapHashedSecret, lockTime uint64) *HashedTimeLockCondition {
+	return &HashedTimeLockCondition{
+		Receiver:     receiver,
+		Refunder:     refunder,
+		HashedSecret: hashedSecret,
+		LockTime:     lockTime,
 	}
-	*ft = FulfillmentType(x)
-	return err
 }
 
-// Fulfill implements UnlockFulfillment.Fulfill
-//
-// If no child is defined, an error will be returned,
-// otherwise the child condition will be attempted to be fulfilled
-// using the given fulfillment within the given (fulfill) context.
-func (up UnlockConditionProxy) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
+// Fulfill implements UnlockCondition.Fulfill
+func (htl *HashedTimeLockCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*HashedTimeLockFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
 	}
-	if p, ok := fulfillment.(UnlockFulfillmentProxy); ok {
-		fulfillment = p.Fulfillment
-		if fulfillment == nil {
-			fulfillment = &NilFulfillment{}
+	unlockHash, err := NewPubKeyUnlockHash(tf.PublicKey)
+	if err != nil {
+		return err
+	}
+	lockTimeReached := htl.lockTimeReached(ctx.BlockHeight, ctx.BlockTime)
+
+	// if secret is given, we'll assume that the receiver wants to claim
+	if tf.Secret != (AtomicSwapSecret{}) {
+		// a claim is only possible as long as the lock time isn't reached
+		if lockTimeReached {
+			return ErrExpiredClaim
 		}
+		if unlockHash.Cmp(htl.Receiver) != 0 {
+			return ErrInvalidRedeemer
+		}
+		hashedSecret := NewAtomicSwapHashedSecret(tf.Secret)
+		if bytes.Compare(htl.HashedSecret[:], hashedSecret[:]) != 0 {
+			return ErrInvalidPreImageSha256
+		}
+		return verifyHashUsingPublicKey(
+			tf.PublicKey, ctx, tf.Signature,
+			mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey, tf.Secret))
 	}
-	return condition.Fulfill(fulfillment, ctx)
+
+	// if no secret is given, we'll assume that the refunder wants to refund,
+	// which is only possible once the lock time is reached
+	if !lockTimeReached {
+		return ErrPrematureRefund
+	}
+	if unlockHash.Cmp(htl.Refunder) != 0 {
+		return ErrInvalidRedeemer
+	}
+	return verifyHashUsingPublicKey(
+		tf.PublicKey, ctx, tf.Signature,
+		mergeExtraObjects(ctx.ExtraObjects, tf.PublicKey))
 }
 
-// ConditionType implements UnlockCondition.ConditionType
-//
-// ConditionType returns the ConditionType of the child UnlockCondition.
-// If no child is defined, a NilCondition is assumed.
-func (up UnlockConditionProxy) ConditionType() ConditionType {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
+// lockTimeReached returns true if the lock time, either a block height or timestamp, has been reached.
+func (htl *HashedTimeLockCondition) lockTimeReached(height BlockHeight, time Timestamp) bool {
+	if htl.LockTime < LockTimeMinTimestampValue {
+		return BlockHeight(htl.LockTime) <= height
 	}
-	return condition.ConditionType()
+	return Timestamp(htl.LockTime) <= time
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (htl *HashedTimeLockCondition) ConditionType() ConditionType {
+	return ConditionTypeHashedTimeLock
 }
 
 // IsStandardCondition implements UnlockCondition.IsStandardCondition
-//
-// If no child is defined, nil will be returned,
-// otherwise the question will be delegated to the child condition.
-func (up UnlockConditionProxy) IsStandardCondition(ctx ValidationContext) error {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
+func (htl *HashedTimeLockCondition) IsStandardCondition(ValidationContext) error {
+	if htl.Receiver.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash receiver type: %d", htl.Receiver.Type)
 	}
-	return condition.IsStandardCondition(ctx)
+	if htl.Refunder.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash refunder type: %d", htl.Refunder.Type)
+	}
+	if htl.Receiver.Hash == (crypto.Hash{}) || htl.Refunder.Hash == (crypto.Hash{}) {
+		return errors.New("nil crypto hash cannot be used as unlock hash")
+	}
+	if htl.HashedSecret == (AtomicSwapHashedSecret{}) {
+		return errors.New("nil hashed secret not allowed")
+	}
+	if htl.LockTime == 0 {
+		return errors.New("lock time has to be defined")
+	}
+	return nil
 }
 
 // UnlockHash implements UnlockCondition.UnlockHash
 //
-// If no child is defined, a nil hash will be returned,
-// otherwise the child condition's unlock hash will be returned.
-func (up UnlockConditionProxy) UnlockHash() UnlockHash {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
-	}
-	return condition.UnlockHash()
+// The hash is prefixed with a specifier, such that it never equals the unlock hash
+// of an AtomicSwapCondition with the same binary layout, but inverted roles.
+func (htl *HashedTimeLockCondition) UnlockHash() UnlockHash {
+	cb, _ := htl.Marshal(siabin.MarshalAll)
+	h, _ := crypto.HashAll(hashedTimeLockSpecifier, cb)
+	return NewUnlockHash(UnlockTypeAtomicSwap, h)
 }
 
+// hashedTimeLockSpecifier is used to distinguish the unlock hash
+// of a HashedTimeLockCondition from the unlock hash of an AtomicSwapCondition.
+var hashedTimeLockSpecifier = Specifier{'h', 'a', 's', 'h', 'e', 'd', 't', 'i', 'm', 'e', 'l', 'o', 'c', 'k'}
+
 // Equal implements UnlockCondition.Equal
-//
-// If no child is defined, the given UnlockCondition will be compared to the NilCondition,
-// otherwise the child condition will be returned with the given UnlockCondition.
-func (up UnlockConditionProxy) Equal(o UnlockCondition) bool {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
+func (htl *HashedTimeLockCondition) Equal(c UnlockCondition) bool {
+	ohtl, ok := c.(*HashedTimeLockCondition)
+	if !ok {
+		return false
 	}
-	if p, ok := o.(UnlockConditionProxy); ok {
-		o = p.Condition
+	if htl.LockTime != ohtl.LockTime {
+		return false
 	}
-	return condition.Equal(o)
+	if bytes.Compare(htl.HashedSecret[:], ohtl.HashedSecret[:]) != 0 {
+		return false
+	}
+	if htl.Receiver.Cmp(ohtl.Receiver) != 0 {
+		return false
+	}
+	return htl.Refunder.Cmp(ohtl.Refunder) == 0
 }
 
 // Fulfillable implements UnlockCondition.Fulfillable
-func (up UnlockConditionProxy) Fulfillable(ctx FulfillableContext) bool {
-	condition := up.Condition
-	if condition == nil {
-		condition = &NilCondition{}
-	}
-	return condition.Fulfillable(ctx)
+func (htl *HashedTimeLockCondition) Fulfillable(FulfillableContext) bool { return true }
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (htl *HashedTimeLockCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(htl.Receiver, htl.Refunder, htl.HashedSecret, htl.LockTime)
 }
 
-// Sign implements UnlockFulfillment.Sign
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (htl *HashedTimeLockCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &htl.Receiver, &htl.Refunder, &htl.HashedSecret, &htl.LockTime)
+}
+
+// NewHashedTimeLockClaimFulfillment creates an unsigned hashed time lock fulfillment,
+// as to claim an output as the receiver, revealing the secret.
 //
-// If no child is defined, an error will be returned,
+// Returned fulfillment still has to be signed, as to add the signature,
+// with the parent transaction as the input as well as the matching private key.
+func NewHashedTimeLockClaimFulfillment(pk PublicKey, secret AtomicSwapSecret) *HashedTimeLockFulfillment {
+	return &HashedTimeLockFulfillment{
+		PublicKey: pk,
+		Secret:    secret,
+	}
+}
+
+// NewHashedTimeLockRefundFulfillment creates an unsigned hashed time lock fulfillment,
+// as to refund an output as the refunder, once the lock time is reached.
+//
+// Returned fulfillment still has to be signed, as to add the signature,
+// with the parent transaction as the input as well as the matching private key.
+func NewHashedTimeLockRefundFulfillment(pk PublicKey) *HashedTimeLockFulfillment {
+	return &HashedTimeLockFulfillment{PublicKey: pk}
+}
+
+// Sign implements UnlockFulfillment.Sign
+func (htl *HashedTimeLockFulfillment) Sign(ctx FulfillmentSignContext) error {
+	if len(htl.Signature) != 0 {
+		return ErrFulfillmentDoubleSign
+	}
+	extraObjects := mergeExtraObjects(ctx.ExtraObjects, htl.PublicKey)
+	if htl.Secret != (AtomicSwapSecret{}) {
+		// sign as claimer
+		extraObjects = mergeExtraObjects(ctx.ExtraObjects, htl.PublicKey, htl.Secret)
+	}
+	var err error
+	htl.Signature, err = signHashUsingPublicKey(htl.PublicKey, ctx.signatureHasher(), ctx.Key, extraObjects)
+	return err
+}
+
+// FulfillmentType implements UnlockFulfillment.FulfillmentType
+func (htl *HashedTimeLockFulfillment) FulfillmentType() FulfillmentType {
+	return FulfillmentTypeHashedTimeLock
+}
+
+// IsStandardFulfillment implements UnlockFulfillment.IsStandardFulfillment
+func (htl *HashedTimeLockFulfillment) IsStandardFulfillment(ValidationContext) error {
+	return strictSignatureCheck(htl.PublicKey, htl.Signature)
+}
+
+// Equal implements UnlockFulfillment.Equal
+func (htl *HashedTimeLockFulfillment) Equal(f UnlockFulfillment) bool {
+	ohtl, ok := f.(*HashedTimeLockFulfillment)
+	if !ok {
+		return false
+	}
+	if htl.PublicKey.Algorithm != ohtl.PublicKey.Algorithm {
+		return false
+	}
+	if bytes.Compare(htl.PublicKey.Key[:], ohtl.PublicKey.Key[:]) != 0 {
+		return false
+	}
+	if bytes.Compare(htl.Signature[:], ohtl.Signature[:]) != 0 {
+		return false
+	}
+	return bytes.Compare(htl.Secret[:], ohtl.Secret[:]) == 0
+}
+
+// Marshal implements MarshalableUnlockFulfillment.Marshal
+func (htl *HashedTimeLockFulfillment) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(htl.PublicKey, htl.Signature, htl.Secret)
+}
+
+// Unmarshal implements MarshalableUnlockFulfillment.Unmarshal
+func (htl *HashedTimeLockFulfillment) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &htl.PublicKey, &htl.Signature, &htl.Secret)
+}
+
+// NewPaymentChannelCondition creates a new payment channel (funding) condition,
+// which can be spent by the sender and receiver together at any time,
+// or by the sender alone once the lock time is reached.
+func NewPaymentChannelCondition(sender, receiver UnlockHash, lockTime uint64) *PaymentChannelCondition {
+	return &PaymentChannelCondition{
+		Sender:   sender,
+		Receiver: receiver,
+		LockTime: lockTime,
+	}
+}
+
+// Fulfill implements UnlockCondition.Fulfill
+func (pc *PaymentChannelCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*PaymentChannelFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+	senderUnlockHash, err := NewPubKeyUnlockHash(tf.Sender.PublicKey)
+	if err != nil {
+		return err
+	}
+	if senderUnlockHash.Cmp(pc.Sender) != 0 {
+		return ErrInvalidRedeemer
+	}
+
+	// if no receiver is given, we'll assume that the sender wants to refund,
+	// which is only possible once the lock time is reached
+	if tf.Receiver == nil {
+		if !pc.lockTimeReached(ctx.BlockHeight, ctx.BlockTime) {
+			return ErrPrematureRefund
+		}
+		return verifyHashUsingPublicKey(
+			tf.Sender.PublicKey, ctx, tf.Sender.Signature,
+			mergeExtraObjects(ctx.ExtraObjects, tf.Sender.PublicKey))
+	}
+
+	// settle the channel, using the signatures of both sender and receiver
+	receiverUnlockHash, err := NewPubKeyUnlockHash(tf.Receiver.PublicKey)
+	if err != nil {
+		return err
+	}
+	if receiverUnlockHash.Cmp(pc.Receiver) != 0 {
+		return ErrInvalidRedeemer
+	}
+	err = verifyHashUsingPublicKey(
+		tf.Sender.PublicKey, ctx, tf.Sender.Signature,
+		mergeExtraObjects(ctx.ExtraObjects, tf.Sender.PublicKey))
+	if err != nil {
+		return err
+	}
+	return verifyHashUsingPublicKey(
+		tf.Receiver.PublicKey, ctx, tf.Receiver.Signature,
+		mergeExtraObjects(ctx.ExtraObjects, tf.Receiver.PublicKey))
+}
+
+// lockTimeReached returns true if the lock time, either a block height or timestamp, has been reached.
+func (pc *PaymentChannelCondition) lockTimeReached(height BlockHeight, time Timestamp) bool {
+	if pc.LockTime < LockTimeMinTimestampValue {
+		return BlockHeight(pc.LockTime) <= height
+	}
+	return Timestamp(pc.LockTime) <= time
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (pc *PaymentChannelCondition) ConditionType() ConditionType {
+	return ConditionTypePaymentChannel
+}
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (pc *PaymentChannelCondition) IsStandardCondition(ValidationContext) error {
+	if pc.Sender.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash sender type: %d", pc.Sender.Type)
+	}
+	if pc.Receiver.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash receiver type: %d", pc.Receiver.Type)
+	}
+	if pc.Sender.Hash == (crypto.Hash{}) || pc.Receiver.Hash == (crypto.Hash{}) {
+		return errors.New("nil crypto hash cannot be used as unlock hash")
+	}
+	if pc.Sender.Cmp(pc.Receiver) == 0 {
+		return errors.New("sender and receiver of a payment channel cannot be the same")
+	}
+	if pc.LockTime == 0 {
+		return errors.New("lock time has to be defined")
+	}
+	return nil
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+func (pc *PaymentChannelCondition) UnlockHash() UnlockHash {
+	cb, _ := pc.Marshal(siabin.MarshalAll)
+	h, _ := crypto.HashObject(cb)
+	return NewUnlockHash(UnlockTypeMultiSig, h)
+}
+
+// Equal implements UnlockCondition.Equal
+func (pc *PaymentChannelCondition) Equal(c UnlockCondition) bool {
+	opc, ok := c.(*PaymentChannelCondition)
+	if !ok {
+		return false
+	}
+	if pc.LockTime != opc.LockTime {
+		return false
+	}
+	if pc.Sender.Cmp(opc.Sender) != 0 {
+		return false
+	}
+	return pc.Receiver.Cmp(opc.Receiver) == 0
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+func (pc *PaymentChannelCondition) Fulfillable(FulfillableContext) bool { return true }
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (pc *PaymentChannelCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(pc.Sender, pc.Receiver, pc.LockTime)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (pc *PaymentChannelCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &pc.Sender, &pc.Receiver, &pc.LockTime)
+}
+
+// Sign implements UnlockFulfillment.Sign
+//
+// The key has to be given as a KeyPair. The first key pair to sign
+// is assumed to be the sender, any other key pair is assumed to be the receiver.
+func (pcf *PaymentChannelFulfillment) Sign(ctx FulfillmentSignContext) error {
+	keyPair, ok := ctx.Key.(KeyPair)
+	if !ok {
+		return fmt.Errorf("%T is an unknown key pair type", ctx.Key)
+	}
+	pair := &pcf.Sender
+	if pcf.Sender.PublicKey.Algorithm != SignatureAlgoNil && !publicKeysEqual(pcf.Sender.PublicKey, keyPair.PublicKey) {
+		if pcf.Receiver == nil {
+			pcf.Receiver = &PublicKeySignaturePair{}
+		}
+		pair = pcf.Receiver
+	}
+	if len(pair.Signature) != 0 {
+		return ErrFulfillmentDoubleSign
+	}
+	sig, err := signHashUsingPublicKey(
+		keyPair.PublicKey, ctx.signatureHasher(), keyPair.PrivateKey,
+		mergeExtraObjects(ctx.ExtraObjects, keyPair.PublicKey))
+	if err != nil {
+		return err
+	}
+	pair.PublicKey = keyPair.PublicKey
+	pair.Signature = sig
+	return nil
+}
+
+// FulfillmentType implements UnlockFulfillment.FulfillmentType
+func (pcf *PaymentChannelFulfillment) FulfillmentType() FulfillmentType {
+	return FulfillmentTypePaymentChannel
+}
+
+// IsStandardFulfillment implements UnlockFulfillment.IsStandardFulfillment
+func (pcf *PaymentChannelFulfillment) IsStandardFulfillment(ValidationContext) error {
+	err := strictSignatureCheck(pcf.Sender.PublicKey, pcf.Sender.Signature)
+	if err != nil || pcf.Receiver == nil {
+		return err
+	}
+	return strictSignatureCheck(pcf.Receiver.PublicKey, pcf.Receiver.Signature)
+}
+
+// Equal implements UnlockFulfillment.Equal
+func (pcf *PaymentChannelFulfillment) Equal(f UnlockFulfillment) bool {
+	opcf, ok := f.(*PaymentChannelFulfillment)
+	if !ok {
+		return false
+	}
+	if !publicKeysEqual(pcf.Sender.PublicKey, opcf.Sender.PublicKey) {
+		return false
+	}
+	if bytes.Compare(pcf.Sender.Signature[:], opcf.Sender.Signature[:]) != 0 {
+		return false
+	}
+	if pcf.Receiver == nil || opcf.Receiver == nil {
+		return pcf.Receiver == nil && opcf.Receiver == nil
+	}
+	if !publicKeysEqual(pcf.Receiver.PublicKey, opcf.Receiver.PublicKey) {
+		return false
+	}
+	return bytes.Compare(pcf.Receiver.Signature[:], opcf.Receiver.Signature[:]) == 0
+}
+
+// publicKeysEqual returns true if both public keys define the same algorithm and key.
+func publicKeysEqual(a, b PublicKey) bool {
+	return a.Algorithm == b.Algorithm && bytes.Compare(a.Key[:], b.Key[:]) == 0
+}
+
+// Marshal implements MarshalableUnlockFulfillment.Marshal
+func (pcf *PaymentChannelFulfillment) Marshal(f MarshalFunc) ([]byte, error) {
+	var receiver PublicKeySignaturePair
+	if pcf.Receiver != nil {
+		receiver = *pcf.Receiver
+	}
+	return f(pcf.Sender.PublicKey, pcf.Sender.Signature, receiver.PublicKey, receiver.Signature)
+}
+
+// Unmarshal implements MarshalableUnlockFulfillment.Unmarshal
+func (pcf *PaymentChannelFulfillment) Unmarshal(b []byte, f UnmarshalFunc) error {
+	var receiver PublicKeySignaturePair
+	err := f(b, &pcf.Sender.PublicKey, &pcf.Sender.Signature, &receiver.PublicKey, &receiver.Signature)
+	if err != nil {
+		return err
+	}
+	if receiver.PublicKey.Algorithm == SignatureAlgoNil {
+		pcf.Receiver = nil
+	} else {
+		pcf.Receiver = &receiver
+	}
+	return nil
+}
+
+// NewBurnCondition creates a new condition which burns the output it is paired with.
+func NewBurnCondition() *BurnCondition {
+	return &BurnCondition{}
+}
+
+// Fulfill implements UnlockCondition.Fulfill
+func (bc *BurnCondition) Fulfill(UnlockFulfillment, FulfillContext) error {
+	return ErrBurnedOutput
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (bc *BurnCondition) ConditionType() ConditionType { return ConditionTypeBurn }
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (bc *BurnCondition) IsStandardCondition(ValidationContext) error { return nil } // always valid
+
+// UnlockHash implements UnlockCondition.UnlockHash
+func (bc *BurnCondition) UnlockHash() UnlockHash { return BurnUnlockHash }
+
+// Equal implements UnlockCondition.Equal
+func (bc *BurnCondition) Equal(c UnlockCondition) bool {
+	_, equal := c.(*BurnCondition)
+	return equal
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+func (bc *BurnCondition) Fulfillable(FulfillableContext) bool { return false }
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (bc *BurnCondition) Marshal(MarshalFunc) ([]byte, error) { return nil, nil } // nothing to marshal
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (bc *BurnCondition) Unmarshal(b []byte, _ UnmarshalFunc) error {
+	if len(b) != 0 {
+		return errors.New("unexpected byte content for BurnCondition")
+	}
+	return nil
+} // nothing to unmarshal
+
+// IsBurnCondition returns true if the given condition burns the output it is paired with.
+func IsBurnCondition(condition UnlockConditionProxy) bool {
+	_, ok := condition.Condition.(*BurnCondition)
+	return ok
+}
+
+// NewVestingCondition creates a new VestingCondition, releasing the total amount
+// to the owner in equal parts over the given amount of periods, counted from the start time,
+// with none of the coins being released prior to the end of the cliff.
+func NewVestingCondition(owner UnlockHash, totalAmount Currency, startTime Timestamp, cliffDuration, periodDuration, periodCount uint64) *VestingCondition {
+	return &VestingCondition{
+		Owner:          owner,
+		TotalAmount:    totalAmount,
+		StartTime:      startTime,
+		CliffDuration:  cliffDuration,
+		PeriodDuration: periodDuration,
+		PeriodCount:    periodCount,
+	}
+}
+
+// Fulfill implements UnlockCondition.Fulfill
+//
+// The owner can spend the output at any time using a SingleSignatureFulfillment,
+// but the amount which isn't yet vested at the time of the block has to be respent,
+// as a single coin output of the parent transaction, to the exact same condition.
+// The locked amount of an output is the amount which isn't yet vested, capped at the value of that output,
+// and is summed over all coin inputs of the parent transaction spending an output with the same condition,
+// such that those inputs cannot share a single respend of the locked amount.
+func (vc *VestingCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*SingleSignatureFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+	err := NewUnlockHashCondition(vc.Owner).Fulfill(tf, ctx)
+	if err != nil {
+		return err
+	}
+	lockedPerOutput := vc.LockedAmount(ctx.BlockTime)
+	if lockedPerOutput.IsZero() {
+		return nil // all coins are vested
+	}
+	if ctx.SpentCoinOutputs == nil {
+		return ErrUnknownSpentCoinOutputs
+	}
+	var locked Currency
+	for _, ci := range ctx.Transaction.CoinInputs {
+		co, ok := ctx.SpentCoinOutputs[ci.ParentID]
+		if !ok || !vc.Equal(co.Condition.Condition) {
+			continue
+		}
+		if co.Value.Cmp(lockedPerOutput) < 0 {
+			locked = locked.Add(co.Value)
+		} else {
+			locked = locked.Add(lockedPerOutput)
+		}
+	}
+	if locked.IsZero() {
+		// the output being fulfilled is not part of the given spent coin outputs
+		return ErrUnknownSpentCoinOutputs
+	}
+	var (
+		respent      Currency
+		respentCount int
+	)
+	for _, co := range ctx.Transaction.CoinOutputs {
+		if vc.Equal(co.Condition.Condition) {
+			respent = co.Value
+			respentCount++
+		}
+	}
+	if respentCount != 1 {
+		return fmt.Errorf(
+			"%s coins are not yet vested, and have to be respent as a single coin output to the same vesting condition, found %d outputs",
+			locked.String(), respentCount)
+	}
+	if respent.Cmp(locked) < 0 {
+		return fmt.Errorf(
+			"only %s coins are respent to the vesting condition, while %s coins are not yet vested",
+			respent.String(), locked.String())
+	}
+	return nil
+}
+
+// VestedAmount returns the amount of coins which are vested at the given time.
+func (vc *VestingCondition) VestedAmount(time Timestamp) Currency {
+	if vc.PeriodCount == 0 || vc.PeriodDuration == 0 {
+		return vc.TotalAmount
+	}
+	if time < vc.StartTime || uint64(time-vc.StartTime) < vc.CliffDuration {
+		return Currency{}
+	}
+	periods := uint64(time-vc.StartTime) / vc.PeriodDuration
+	if periods >= vc.PeriodCount {
+		return vc.TotalAmount
+	}
+	return vc.TotalAmount.Mul64(periods).Div64(vc.PeriodCount)
+}
+
+// LockedAmount returns the amount of coins which are not yet vested at the given time.
+func (vc *VestingCondition) LockedAmount(time Timestamp) Currency {
+	return vc.TotalAmount.Sub(vc.VestedAmount(time))
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (vc *VestingCondition) ConditionType() ConditionType {
+	return ConditionTypeVesting
+}
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (vc *VestingCondition) IsStandardCondition(ValidationContext) error {
+	if vc.Owner.Type != UnlockTypePubKey {
+		return fmt.Errorf("unsupported unlock hash owner type: %d", vc.Owner.Type)
+	}
+	if vc.Owner.Hash == (crypto.Hash{}) {
+		return errors.New("nil crypto hash cannot be used as unlock hash")
+	}
+	if vc.TotalAmount.IsZero() {
+		return errors.New("total amount of a vesting schedule has to be defined")
+	}
+	if vc.PeriodDuration == 0 || vc.PeriodCount == 0 {
+		return errors.New("period duration and count of a vesting schedule have to be defined")
+	}
+	return nil
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+//
+// The unlock hash of the owner is returned,
+// such that wallets can track the output as a (locked) output of the owner.
+func (vc *VestingCondition) UnlockHash() UnlockHash {
+	return vc.Owner
+}
+
+// Equal implements UnlockCondition.Equal
+func (vc *VestingCondition) Equal(c UnlockCondition) bool {
+	ovc, ok := c.(*VestingCondition)
+	if !ok {
+		return false
+	}
+	return vc.Owner.Cmp(ovc.Owner) == 0 &&
+		vc.TotalAmount.Equals(ovc.TotalAmount) &&
+		vc.StartTime == ovc.StartTime &&
+		vc.CliffDuration == ovc.CliffDuration &&
+		vc.PeriodDuration == ovc.PeriodDuration &&
+		vc.PeriodCount == ovc.PeriodCount
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+//
+// A vesting output is only considered fulfillable (without having to respend any of its coins),
+// once all of its coins are vested.
+func (vc *VestingCondition) Fulfillable(ctx FulfillableContext) bool {
+	return vc.LockedAmount(ctx.BlockTime).IsZero()
+}
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (vc *VestingCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(vc.Owner, vc.TotalAmount, vc.StartTime, vc.CliffDuration, vc.PeriodDuration, vc.PeriodCount)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (vc *VestingCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &vc.Owner, &vc.TotalAmount, &vc.StartTime, &vc.CliffDuration, &vc.PeriodDuration, &vc.PeriodCount)
+}
+
+// RelativeLockTimeUnit defines the unit of the LockTime of a RelativeTimeLockCondition.
+type RelativeLockTimeUnit uint8
+
+const (
+	// RelativeLockTimeUnitBlocks defines a relative lock time expressed in blocks.
+	RelativeLockTimeUnitBlocks RelativeLockTimeUnit = iota
+	// RelativeLockTimeUnitSeconds defines a relative lock time expressed in seconds.
+	RelativeLockTimeUnitSeconds
+)
+
+// String returns the name of the relative lock time unit.
+func (u RelativeLockTimeUnit) String() string {
+	switch u {
+	case RelativeLockTimeUnitBlocks:
+		return "blocks"
+	case RelativeLockTimeUnitSeconds:
+		return "seconds"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(u))
+	}
+}
+
+// MarshalSia implements siabin.SiaMarshaler.MarshalSia
+//
+// Marshals this RelativeLockTimeUnit as a single byte.
+func (u RelativeLockTimeUnit) MarshalSia(w io.Writer) error {
+	_, err := w.Write([]byte{byte(u)})
+	return err
+}
+
+// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
+//
+// Unmarshals this RelativeLockTimeUnit from a single byte.
+func (u *RelativeLockTimeUnit) UnmarshalSia(r io.Reader) error {
+	var b [1]byte
+	_, err := r.Read(b[:])
+	*u = RelativeLockTimeUnit(b[0])
+	return err
+}
+
+// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
+//
+// Marshals this RelativeLockTimeUnit as a single byte.
+func (u RelativeLockTimeUnit) MarshalRivine(w io.Writer) error {
+	return rivbin.MarshalUint8(w, uint8(u))
+}
+
+// UnmarshalRivine implements rivbin.RivineUnmarshaler.UnmarshalRivine
+//
+// Unmarshals this RelativeLockTimeUnit from a single byte.
+func (u *RelativeLockTimeUnit) UnmarshalRivine(r io.Reader) error {
+	x, err := rivbin.UnmarshalUint8(r)
+	if err != nil {
+		return err
+	}
+	*u = RelativeLockTimeUnit(x)
+	return nil
+}
+
+// NewRelativeTimeLockCondition creates a new RelativeTimeLockCondition.
+// If no MarshalableUnlockCondition is given, the NilCondition is assumed.
+func NewRelativeTimeLockCondition(lockTime uint64, unit RelativeLockTimeUnit, condition MarshalableUnlockCondition) *RelativeTimeLockCondition {
+	if lockTime == 0 {
+		build.Severe("lock time is required")
+	}
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	return &RelativeTimeLockCondition{
+		LockTime:  lockTime,
+		Unit:      unit,
+		Condition: condition,
+	}
+}
+
+// Fulfill implements UnlockFulfillment.Fulfill
+//
+// The relative time lock has to be passed since the creation of the output,
+// requiring the output origin to be defined as part of the given context.
+func (rtl *RelativeTimeLockCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	if ctx.OutputOrigin == nil {
+		return ErrUnknownOutputOrigin
+	}
+	if !rtl.Fulfillable(FulfillableContext{BlockHeight: ctx.BlockHeight, BlockTime: ctx.BlockTime, OutputOrigin: ctx.OutputOrigin}) {
+		return ErrRelativeLockTimeNotReached
+	}
+
+	// relative time lock hash been reached,
+	// delegate the actual fulfillment to the given fulfillment, if supported
+	switch tf := fulfillment.(type) {
+	case *SingleSignatureFulfillment:
+		return rtl.Condition.Fulfill(tf, ctx)
+	case *MultiSignatureFulfillment:
+		return rtl.Condition.Fulfill(tf, ctx)
+	default:
+		return ErrUnexpectedUnlockFulfillment
+	}
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (rtl *RelativeTimeLockCondition) ConditionType() ConditionType {
+	return ConditionTypeRelativeTimeLock
+}
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (rtl *RelativeTimeLockCondition) IsStandardCondition(ctx ValidationContext) error {
+	if rtl.LockTime == 0 {
+		return errors.New("lock time has to be defined")
+	}
+	if rtl.Unit != RelativeLockTimeUnitBlocks && rtl.Unit != RelativeLockTimeUnitSeconds {
+		return fmt.Errorf("unknown relative lock time unit %d", uint8(rtl.Unit))
+	}
+	switch ct := rtl.Condition.ConditionType(); ct {
+	case ConditionTypeUnlockHash:
+		uh := rtl.Condition.UnlockHash()
+		if uh.Hash == (crypto.Hash{}) {
+			return errors.New("nil crypto hash cannot be used as unlock hash")
+		}
+		if uh.Type != UnlockTypePubKey {
+			return errors.New("non-standard unlock hash type")
+		}
+		return nil
+	case ConditionTypeMultiSignature, ConditionTypeWeightedMultiSignature:
+		return rtl.Condition.IsStandardCondition(ctx)
+	case ConditionTypeNil:
+		return nil
+	default:
+		return errors.New("unexpected internal unlock condition used as part of relative time lock condition")
+	}
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+func (rtl *RelativeTimeLockCondition) UnlockHash() UnlockHash {
+	return rtl.Condition.UnlockHash()
+}
+
+// GetMarshalableUnlockCondition implements MarshalableUnlockConditionGetter.GetMarshalableUnlockCondition
+func (rtl *RelativeTimeLockCondition) GetMarshalableUnlockCondition() MarshalableUnlockCondition {
+	return rtl.Condition
+}
+
+// Equal implements UnlockCondition.Equal
+func (rtl *RelativeTimeLockCondition) Equal(c UnlockCondition) bool {
+	ortl, ok := c.(*RelativeTimeLockCondition)
+	if !ok {
+		return false
+	}
+	return rtl.LockTime == ortl.LockTime && rtl.Unit == ortl.Unit && rtl.Condition.Equal(ortl.Condition)
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+//
+// An output of which the origin is unknown is never considered fulfillable.
+func (rtl *RelativeTimeLockCondition) Fulfillable(ctx FulfillableContext) bool {
+	if ctx.OutputOrigin == nil {
+		return false
+	}
+	if rtl.Unit == RelativeLockTimeUnitSeconds {
+		return uint64(ctx.OutputOrigin.BlockTime)+rtl.LockTime <= uint64(ctx.BlockTime)
+	}
+	return uint64(ctx.OutputOrigin.BlockHeight)+rtl.LockTime <= uint64(ctx.BlockHeight)
+}
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (rtl *RelativeTimeLockCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	cb, err := rtl.Condition.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	b, err := f(rtl.LockTime, rtl.Unit, rtl.Condition.ConditionType())
+	if err != nil {
+		return nil, err
+	}
+	return append(b, cb...), nil
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (rtl *RelativeTimeLockCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	if len(b) < 10 {
+		// at least 10 bytes are required (lock time (8) + unit (1) + condition type (1)),
+		// as to enforce we can decode the relative time lock condition's properties,
+		// whether or not the internal condition requires bytes is of no concern of us.
+		return io.ErrUnexpectedEOF
+	}
+	// unmarshal the lock time and its unit
+	err := f(b[:9], &rtl.LockTime, &rtl.Unit)
+	if err != nil {
+		return err
+	}
+	// interpret the condition type, and continue decoding based on that,
+	// by getting the correct constructor from the registration mapping
+	var ct ConditionType
+	err = f(b[9:10], &ct)
+	if err != nil {
+		return err
+	}
+	cc, ok := _RegisteredUnlockConditionTypes[ct]
+	if !ok {
+		return ErrUnknownConditionType
+	}
+	// known condition type, create and decode it
+	rtl.Condition = cc()
+	return rtl.Condition.Unmarshal(b[10:], f)
+}
+
+type jsonRelativeTimeLockCondition struct {
+	LockTime  uint64               `json:"locktime"`
+	Unit      RelativeLockTimeUnit `json:"unit"`
+	Condition UnlockConditionProxy `json:"condition"`
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+//
+// This function is required, as to ensure
+// the underlying properties are properly serialized,
+// including the type of the internal condition.
+func (rtl *RelativeTimeLockCondition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonRelativeTimeLockCondition{
+		LockTime:  rtl.LockTime,
+		Unit:      rtl.Unit,
+		Condition: UnlockConditionProxy{Condition: rtl.Condition},
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+//
+// This function is required, as to be able to unmarshal
+// the internal condition based on the encoded condition type.
+func (rtl *RelativeTimeLockCondition) UnmarshalJSON(b []byte) error {
+	var jrtl jsonRelativeTimeLockCondition
+	err := json.Unmarshal(b, &jrtl)
+	if err != nil {
+		return err
+	}
+	rtl.LockTime = jrtl.LockTime
+	rtl.Unit = jrtl.Unit
+	if jrtl.Condition.Condition == nil {
+		rtl.Condition = &NilCondition{}
+	} else {
+		rtl.Condition = jrtl.Condition.Condition
+	}
+	return nil
+}
+
+// NewWeightedMultiSignatureCondition creates a new weighted multisig unlock condition,
+// using the given weighted unlock hashes as a representation of the identities
+// who can unlock the output, and the minimum total weight their signatures have to reach.
+func NewWeightedMultiSignatureCondition(signatories []WeightedUnlockHash, minimumWeight uint64) *WeightedMultiSignatureCondition {
+	if minimumWeight == 0 {
+		build.Severe("weighted multisig outputs must require a minimum weight to unlock")
+	}
+	if len(signatories) == 0 {
+		build.Severe("weighted multisig outputs must specify at least a single address which can sign it as an input")
+	}
+	return &WeightedMultiSignatureCondition{Signatories: signatories, MinimumWeight: minimumWeight}
+}
+
+// Fulfill implements UnlockFulfillment.Fulfill
+//
+// The condition is fulfilled by a MultiSignatureFulfillment,
+// of which the (unique) signatories together reach the minimum weight.
+func (wms *WeightedMultiSignatureCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*MultiSignatureFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+
+	// Check if all the unlock keypairs have an associated (unique) signatory,
+	// summing the weights of the signatories
+	signatories := make([]WeightedUnlockHash, len(wms.Signatories))
+	copy(signatories, wms.Signatories)
+	var weight uint64
+	for _, kp := range tf.Pairs {
+		uh, err := NewPubKeyUnlockHash(kp.PublicKey)
+		if err != nil {
+			return err
+		}
+		found := false
+		for i, signatory := range signatories {
+			if signatory.UnlockHash.Cmp(uh) == 0 {
+				if weight+signatory.Weight < weight {
+					return errors.New("overflow of total signature weight")
+				}
+				weight += signatory.Weight
+				signatories = append(signatories[:i], signatories[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrUnauthorizedPubKey
+		}
+	}
+	// Check if enough weight has been provided
+	if weight < wms.MinimumWeight {
+		return ErrInsufficientSignatures
+	}
+
+	// Finally verify all the signatures
+	for _, pks := range tf.Pairs {
+		if err := verifyHashUsingPublicKey(
+			pks.PublicKey, ctx, pks.Signature,
+			mergeExtraObjects(ctx.ExtraObjects, pks.PublicKey),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (wms *WeightedMultiSignatureCondition) ConditionType() ConditionType {
+	return ConditionTypeWeightedMultiSignature
+}
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (wms *WeightedMultiSignatureCondition) IsStandardCondition(ValidationContext) error {
+	if wms.MinimumWeight == 0 {
+		return errors.New("A minimum weight must be specified")
+	}
+	if len(wms.Signatories) < 2 {
+		return errors.New("At least two weighted unlockhashes must be provided which identifies to possible signatories")
+	}
+	var total uint64
+	for idx, signatory := range wms.Signatories {
+		if signatory.UnlockHash.Type != UnlockTypePubKey {
+			return fmt.Errorf("unsupported unlock hash #%d type: %d", idx, signatory.UnlockHash.Type)
+		}
+		if signatory.Weight == 0 {
+			return fmt.Errorf("weight of unlock hash #%d has to be defined", idx)
+		}
+		for _, other := range wms.Signatories[:idx] {
+			if other.UnlockHash.Cmp(signatory.UnlockHash) == 0 {
+				return fmt.Errorf("unlock hash #%d is defined more than once", idx)
+			}
+		}
+		if total+signatory.Weight < total {
+			return errors.New("overflow of total signature weight")
+		}
+		total += signatory.Weight
+	}
+	if wms.MinimumWeight > total {
+		return errors.New("The minimum weight can't be higher than the total weight of all unlockhashes")
+	}
+	return nil
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+//
+// UnlockHash calculates the root hash of a Merkle tree of the
+// WeightedMultiSignatureCondition object, in a similar fashion as is done for the MultiSignatureCondition.
+// The first leaf is a specifier, distinguishing it from the tree of a MultiSignatureCondition.
+// The leaves which follow are formed by taking the hash of the length of signatories,
+// the hash of the sorted weighted unlock hashes (one leaf each, the unlock hash followed by its weight),
+// and the hash of the minimum weight required.
+func (wms *WeightedMultiSignatureCondition) UnlockHash() UnlockHash {
+	// Copy the signatories to a new slice and sort it,
+	// so the same unlockhash is produced for the same set
+	// of signatories, regardless of their ordering
+	signatories := make([]WeightedUnlockHash, len(wms.Signatories))
+	copy(signatories, wms.Signatories)
+	sort.Slice(signatories, func(i, j int) bool {
+		return signatories[i].UnlockHash.Cmp(signatories[j].UnlockHash) < 0
+	})
+
+	// compute the hash
+	var buf bytes.Buffer
+	e := encoder(&buf)
+	tree := crypto.NewTree()
+	tree.Push(weightedMultiSignatureSpecifier[:])
+	e.WriteUint64(uint64(len(signatories)))
+	tree.Push(buf.Bytes())
+	buf.Reset()
+	for _, signatory := range signatories {
+		signatory.UnlockHash.MarshalSia(e)
+		e.WriteUint64(signatory.Weight)
+		tree.Push(buf.Bytes())
+		buf.Reset()
+	}
+	e.WriteUint64(wms.MinimumWeight)
+	tree.Push(buf.Bytes())
+	return NewUnlockHash(UnlockTypeMultiSig, tree.Root())
+}
+
+// weightedMultiSignatureSpecifier is used as the first leaf of
+// the Merkle tree used to compute the unlock hash of a WeightedMultiSignatureCondition.
+var weightedMultiSignatureSpecifier = Specifier{'w', 'e', 'i', 'g', 'h', 't', 'e', 'd', 'm', 'u', 'l', 't', 'i', 's', 'i', 'g'}
+
+// UnlockHashSlice implements UnlockHashSliceGetter.UnlockHashSlice
+func (wms *WeightedMultiSignatureCondition) UnlockHashSlice() []UnlockHash {
+	uhs := make([]UnlockHash, 0, len(wms.Signatories))
+	for _, signatory := range wms.Signatories {
+		uhs = append(uhs, signatory.UnlockHash)
+	}
+	return uhs
+}
+
+// GetMinimumSignatureCount returns the minimum amount of signatures required
+// in order to fulfill this WeightedMultiSignatureCondition using a MultiSignatureFulfillment,
+// which is the amount of signatures required when only the heaviest signatories sign.
+func (wms *WeightedMultiSignatureCondition) GetMinimumSignatureCount() uint64 {
+	weights := make([]uint64, 0, len(wms.Signatories))
+	for _, signatory := range wms.Signatories {
+		weights = append(weights, signatory.Weight)
+	}
+	sort.Slice(weights, func(i, j int) bool { return weights[i] > weights[j] })
+	var total uint64
+	for idx, weight := range weights {
+		total += weight
+		if total >= wms.MinimumWeight {
+			return uint64(idx + 1)
+		}
+	}
+	return uint64(len(weights)) // unfulfillable, the minimum weight cannot be reached
+}
+
+// Weight returns the weight of the given unlock hash,
+// zero is returned if the unlock hash is not a signatory of this condition.
+func (wms *WeightedMultiSignatureCondition) Weight(uh UnlockHash) uint64 {
+	for _, signatory := range wms.Signatories {
+		if signatory.UnlockHash.Cmp(uh) == 0 {
+			return signatory.Weight
+		}
+	}
+	return 0
+}
+
+// Equal implements UnlockCondition.Equal
+func (wms *WeightedMultiSignatureCondition) Equal(c UnlockCondition) bool {
+	owms, ok := c.(*WeightedMultiSignatureCondition)
+	if !ok {
+		return false
+	}
+	if wms.MinimumWeight != owms.MinimumWeight || len(wms.Signatories) != len(owms.Signatories) {
+		return false
+	}
+	// Check and make sure that all signatories match,
+	// regardless of the ordering
+	others := make([]WeightedUnlockHash, len(owms.Signatories))
+	copy(others, owms.Signatories)
+	for _, signatory := range wms.Signatories {
+		for i, other := range others {
+			if signatory.Weight == other.Weight && signatory.UnlockHash.Cmp(other.UnlockHash) == 0 {
+				others = append(others[:i], others[i+1:]...)
+				break
+			}
+		}
+	}
+	return len(others) == 0
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+func (wms *WeightedMultiSignatureCondition) Fulfillable(FulfillableContext) bool {
+	return true
+}
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (wms *WeightedMultiSignatureCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(wms.MinimumWeight, wms.Signatories)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (wms *WeightedMultiSignatureCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	return f(b, &wms.MinimumWeight, &wms.Signatories)
+}
+
+// Limits of composite conditions (and their fulfillments).
+const (
+	// MaxCompositeConditionDepth is the maximum nesting depth of composite conditions,
+	// as well as of the composite fulfillments which fulfill them.
+	// A composite condition which only nests non-composite conditions has a depth of 1.
+	MaxCompositeConditionDepth = 4
+	// MaxCompositeConditionCount is the maximum amount of conditions
+	// a standard composite condition can nest, and thus the maximum
+	// amount of branches of a standard composite fulfillment.
+	MaxCompositeConditionCount = 16
+)
+
+// NewAnyOfCondition creates a new composite condition,
+// which is fulfilled by fulfilling any one of the given (two or more) conditions.
+func NewAnyOfCondition(conditions ...MarshalableUnlockCondition) *AnyOfCondition {
+	return &AnyOfCondition{Conditions: newCompositeConditions(conditions)}
+}
+
+// Fulfill implements UnlockFulfillment.Fulfill
+//
+// The condition is fulfilled by a CompositeFulfillment which defines a single branch,
+// of which the fulfillment fulfills the nested condition at the index of that branch.
+func (ao *AnyOfCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*CompositeFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+	if len(tf.Branches) != 1 {
+		return fmt.Errorf("any-of condition has to be fulfilled by exactly one branch, not %d", len(tf.Branches))
+	}
+	branch := tf.Branches[0]
+	if branch.Index >= uint64(len(ao.Conditions)) {
+		return fmt.Errorf("branch index %d is out of range", branch.Index)
+	}
+	return ao.Conditions[branch.Index].Fulfill(branch.Fulfillment, ctx)
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (ao *AnyOfCondition) ConditionType() ConditionType { return ConditionTypeAnyOf }
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (ao *AnyOfCondition) IsStandardCondition(ctx ValidationContext) error {
+	return isStandardCompositeCondition(ao, ao.Conditions, ctx)
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+//
+// See compositeUnlockHash for more information.
+func (ao *AnyOfCondition) UnlockHash() UnlockHash {
+	return compositeUnlockHash(anyOfConditionSpecifier, ao.Conditions)
+}
+
+// UnlockHashSlice implements UnlockHashSliceGetter.UnlockHashSlice
+func (ao *AnyOfCondition) UnlockHashSlice() []UnlockHash {
+	return compositeUnlockHashSlice(ao.Conditions)
+}
+
+// Equal implements UnlockCondition.Equal
+func (ao *AnyOfCondition) Equal(c UnlockCondition) bool {
+	oao, ok := c.(*AnyOfCondition)
+	if !ok {
+		return false
+	}
+	return compositeConditionsEqual(ao.Conditions, oao.Conditions)
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+//
+// The condition is fulfillable if any of its nested conditions is fulfillable.
+func (ao *AnyOfCondition) Fulfillable(ctx FulfillableContext) bool {
+	for _, condition := range ao.Conditions {
+		if condition.Fulfillable(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (ao *AnyOfCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(ao.Conditions)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (ao *AnyOfCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	err := f(b, &ao.Conditions)
+	if err != nil {
+		return err
+	}
+	if compositeConditionDepth(ao) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+//
+// This function is required, as to enforce the maximum nesting depth.
+func (ao *AnyOfCondition) UnmarshalJSON(b []byte) error {
+	type anyOfCondition AnyOfCondition
+	err := json.Unmarshal(b, (*anyOfCondition)(ao))
+	if err != nil {
+		return err
+	}
+	if compositeConditionDepth(ao) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// NewAllOfCondition creates a new composite condition,
+// which is fulfilled by fulfilling all of the given (two or more) conditions.
+func NewAllOfCondition(conditions ...MarshalableUnlockCondition) *AllOfCondition {
+	return &AllOfCondition{Conditions: newCompositeConditions(conditions)}
+}
+
+// Fulfill implements UnlockFulfillment.Fulfill
+//
+// The condition is fulfilled by a CompositeFulfillment which defines a branch
+// for each nested condition, in the order of those conditions,
+// each branch fulfilling the nested condition at its index.
+func (ao *AllOfCondition) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	tf, ok := fulfillment.(*CompositeFulfillment)
+	if !ok {
+		return ErrUnexpectedUnlockFulfillment
+	}
+	if len(tf.Branches) != len(ao.Conditions) {
+		return fmt.Errorf("all-of condition has to be fulfilled by %d branches, not %d", len(ao.Conditions), len(tf.Branches))
+	}
+	for idx, branch := range tf.Branches {
+		if branch.Index != uint64(idx) {
+			return fmt.Errorf("branch #%d has to fulfill condition #%d, not #%d", idx, idx, branch.Index)
+		}
+		err := ao.Conditions[idx].Fulfill(branch.Fulfillment, ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+func (ao *AllOfCondition) ConditionType() ConditionType { return ConditionTypeAllOf }
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+func (ao *AllOfCondition) IsStandardCondition(ctx ValidationContext) error {
+	return isStandardCompositeCondition(ao, ao.Conditions, ctx)
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+//
+// See compositeUnlockHash for more information.
+func (ao *AllOfCondition) UnlockHash() UnlockHash {
+	return compositeUnlockHash(allOfConditionSpecifier, ao.Conditions)
+}
+
+// UnlockHashSlice implements UnlockHashSliceGetter.UnlockHashSlice
+func (ao *AllOfCondition) UnlockHashSlice() []UnlockHash {
+	return compositeUnlockHashSlice(ao.Conditions)
+}
+
+// Equal implements UnlockCondition.Equal
+func (ao *AllOfCondition) Equal(c UnlockCondition) bool {
+	oao, ok := c.(*AllOfCondition)
+	if !ok {
+		return false
+	}
+	return compositeConditionsEqual(ao.Conditions, oao.Conditions)
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+//
+// The condition is fulfillable if all of its nested conditions are fulfillable.
+func (ao *AllOfCondition) Fulfillable(ctx FulfillableContext) bool {
+	for _, condition := range ao.Conditions {
+		if !condition.Fulfillable(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// Marshal implements MarshalableUnlockCondition.Marshal
+func (ao *AllOfCondition) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(ao.Conditions)
+}
+
+// Unmarshal implements MarshalableUnlockCondition.Unmarshal
+func (ao *AllOfCondition) Unmarshal(b []byte, f UnmarshalFunc) error {
+	err := f(b, &ao.Conditions)
+	if err != nil {
+		return err
+	}
+	if compositeConditionDepth(ao) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+//
+// This function is required, as to enforce the maximum nesting depth.
+func (ao *AllOfCondition) UnmarshalJSON(b []byte) error {
+	type allOfCondition AllOfCondition
+	err := json.Unmarshal(b, (*allOfCondition)(ao))
+	if err != nil {
+		return err
+	}
+	if compositeConditionDepth(ao) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// specifiers used as the first leaf of the Merkle tree used to compute the unlock hash
+// of a composite condition, distinguishing it from the tree of a (weighted) MultiSignatureCondition.
+var (
+	anyOfConditionSpecifier = Specifier{'a', 'n', 'y', 'o', 'f'}
+	allOfConditionSpecifier = Specifier{'a', 'l', 'l', 'o', 'f'}
+)
+
+func newCompositeConditions(conditions []MarshalableUnlockCondition) []UnlockConditionProxy {
+	if len(conditions) < 2 {
+		build.Severe("composite conditions must nest at least two conditions")
+	}
+	proxies := make([]UnlockConditionProxy, 0, len(conditions))
+	for _, condition := range conditions {
+		proxies = append(proxies, NewCondition(condition))
+	}
+	return proxies
+}
+
+// isStandardCompositeCondition checks if a composite condition is standard,
+// which is the case if it nests a limited amount of standard conditions,
+// without exceeding the maximum nesting depth.
+// Conditions which cannot be fulfilled, can be fulfilled by anyone,
+// or which have to be respent to themselves, cannot be nested.
+func isStandardCompositeCondition(condition UnlockCondition, conditions []UnlockConditionProxy, ctx ValidationContext) error {
+	if len(conditions) < 2 {
+		return errors.New("a composite condition has to nest at least two conditions")
+	}
+	if len(conditions) > MaxCompositeConditionCount {
+		return fmt.Errorf("a composite condition can nest at most %d conditions", MaxCompositeConditionCount)
+	}
+	if compositeConditionDepth(condition) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	for idx, nested := range conditions {
+		switch ct := nested.ConditionType(); ct {
+		case ConditionTypeNil, ConditionTypeBurn, ConditionTypeColdStaking, ConditionTypeVesting:
+			return fmt.Errorf("condition #%d of type %d cannot be nested by a composite condition", idx, ct)
+		}
+		err := nested.IsStandardCondition(ctx)
+		if err != nil {
+			return fmt.Errorf("nested condition #%d is not standard: %v", idx, err)
+		}
+	}
+	return nil
+}
+
+// compositeUnlockHash calculates the root hash of a Merkle tree of a composite condition,
+// in a similar fashion as is done for the WeightedMultiSignatureCondition.
+// The first leaf is a specifier, identifying the type of composite condition.
+// The leaves which follow are formed by taking the hash of the amount of nested conditions,
+// and the hash of each nested condition (binary encoded including its type), in order.
+// As the full nested conditions are hashed, rather than their unlock hashes,
+// the unlock hash commits to the lock times of nested time lock conditions as well.
+func compositeUnlockHash(specifier Specifier, conditions []UnlockConditionProxy) UnlockHash {
+	var buf bytes.Buffer
+	e := encoder(&buf)
+	tree := crypto.NewTree()
+	tree.Push(specifier[:])
+	e.WriteUint64(uint64(len(conditions)))
+	tree.Push(buf.Bytes())
+	buf.Reset()
+	for _, condition := range conditions {
+		condition.MarshalSia(&buf)
+		tree.Push(buf.Bytes())
+		buf.Reset()
+	}
+	return NewUnlockHash(UnlockTypeMultiSig, tree.Root())
+}
+
+// compositeUnlockHashSlice returns the unique public key unlock hashes
+// of the given nested conditions, including the signatories of nested multisig conditions.
+func compositeUnlockHashSlice(conditions []UnlockConditionProxy) []UnlockHash {
+	var uhs []UnlockHash
+	add := func(uh UnlockHash) {
+		for _, other := range uhs {
+			if other.Cmp(uh) == 0 {
+				return
+			}
+		}
+		uhs = append(uhs, uh)
+	}
+	var collect func(condition MarshalableUnlockCondition)
+	collect = func(condition MarshalableUnlockCondition) {
+		switch c := condition.(type) {
+		case nil:
+		case UnlockHashSliceGetter:
+			for _, uh := range c.UnlockHashSlice() {
+				add(uh)
+			}
+		case MarshalableUnlockConditionGetter:
+			collect(c.GetMarshalableUnlockCondition())
+		default:
+			if uh := c.UnlockHash(); uh.Type == UnlockTypePubKey {
+				add(uh)
+			}
+		}
+	}
+	for _, condition := range conditions {
+		collect(condition.Condition)
+	}
+	return uhs
+}
+
+// compositeConditionsEqual returns true if both slices contain equal conditions,
+// in the same order, as the order of nested conditions matters to their fulfillment.
+func compositeConditionsEqual(a, b []UnlockConditionProxy) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if !a[idx].Equal(b[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// compositeConditionDepth returns the nesting depth of the composite conditions
+// within the given condition, 0 if it is not (and does not wrap) a composite condition.
+func compositeConditionDepth(condition UnlockCondition) int {
+	switch c := condition.(type) {
+	case UnlockConditionProxy:
+		if c.Condition == nil {
+			return 0
+		}
+		return compositeConditionDepth(c.Condition)
+	case *AnyOfCondition:
+		return 1 + maxCompositeConditionDepth(c.Conditions)
+	case *AllOfCondition:
+		return 1 + maxCompositeConditionDepth(c.Conditions)
+	case MarshalableUnlockConditionGetter:
+		inner := c.GetMarshalableUnlockCondition()
+		if inner == nil {
+			return 0
+		}
+		return compositeConditionDepth(inner)
+	default:
+		return 0
+	}
+}
+
+func maxCompositeConditionDepth(conditions []UnlockConditionProxy) (depth int) {
+	for _, condition := range conditions {
+		if d := compositeConditionDepth(condition); d > depth {
+			depth = d
+		}
+	}
+	return
+}
+
+// NewAnyOfFulfillment creates a composite fulfillment for an AnyOfCondition,
+// fulfilling the nested condition at the given index using the given fulfillment.
+func NewAnyOfFulfillment(index uint64, fulfillment MarshalableUnlockFulfillment) *CompositeFulfillment {
+	return &CompositeFulfillment{Branches: []CompositeFulfillmentBranch{
+		{Index: index, Fulfillment: NewFulfillment(fulfillment)},
+	}}
+}
+
+// NewAllOfFulfillment creates a composite fulfillment for an AllOfCondition,
+// fulfilling its nested conditions using the given fulfillments, in the order of those conditions.
+func NewAllOfFulfillment(fulfillments ...MarshalableUnlockFulfillment) *CompositeFulfillment {
+	branches := make([]CompositeFulfillmentBranch, 0, len(fulfillments))
+	for idx, fulfillment := range fulfillments {
+		branches = append(branches, CompositeFulfillmentBranch{
+			Index:       uint64(idx),
+			Fulfillment: NewFulfillment(fulfillment),
+		})
+	}
+	return &CompositeFulfillment{Branches: branches}
+}
+
+// Sign implements UnlockFulfillment.Sign
+//
+// The fulfillments of all branches are signed using the given key.
+// Branches which require different keys can be signed individually,
+// by signing the fulfillment of the branch instead.
+func (cf *CompositeFulfillment) Sign(ctx FulfillmentSignContext) error {
+	if len(cf.Branches) == 0 {
+		return errors.New("composite fulfillment has no branches to sign")
+	}
+	for idx, branch := range cf.Branches {
+		err := branch.Fulfillment.Sign(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sign branch #%d: %v", idx, err)
+		}
+	}
+	return nil
+}
+
+// FulfillmentType implements UnlockFulfillment.FulfillmentType
+func (cf *CompositeFulfillment) FulfillmentType() FulfillmentType {
+	return FulfillmentTypeComposite
+}
+
+// IsStandardFulfillment implements UnlockFulfillment.IsStandardFulfillment
+func (cf *CompositeFulfillment) IsStandardFulfillment(ctx ValidationContext) error {
+	if len(cf.Branches) == 0 {
+		return errors.New("a composite fulfillment has to define at least one branch")
+	}
+	if len(cf.Branches) > MaxCompositeConditionCount {
+		return fmt.Errorf("a composite fulfillment can define at most %d branches", MaxCompositeConditionCount)
+	}
+	if compositeFulfillmentDepth(cf) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	for idx, branch := range cf.Branches {
+		if idx > 0 && branch.Index <= cf.Branches[idx-1].Index {
+			return errors.New("composite fulfillment branches have to be unique and ordered by index")
+		}
+		err := branch.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return fmt.Errorf("fulfillment of branch #%d is not standard: %v", idx, err)
+		}
+	}
+	return nil
+}
+
+// Equal implements UnlockFulfillment.Equal
+func (cf *CompositeFulfillment) Equal(f UnlockFulfillment) bool {
+	ocf, ok := f.(*CompositeFulfillment)
+	if !ok {
+		return false
+	}
+	if len(cf.Branches) != len(ocf.Branches) {
+		return false
+	}
+	for idx, branch := range cf.Branches {
+		other := ocf.Branches[idx]
+		if branch.Index != other.Index || !branch.Fulfillment.Equal(other.Fulfillment) {
+			return false
+		}
+	}
+	return true
+}
+
+// Marshal implements MarshalableUnlockFulfillment.Marshal
+func (cf *CompositeFulfillment) Marshal(f MarshalFunc) ([]byte, error) {
+	return f(cf.Branches)
+}
+
+// Unmarshal implements MarshalableUnlockFulfillment.Unmarshal
+func (cf *CompositeFulfillment) Unmarshal(b []byte, f UnmarshalFunc) error {
+	err := f(b, &cf.Branches)
+	if err != nil {
+		return err
+	}
+	if compositeFulfillmentDepth(cf) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+//
+// This function is required, as to enforce the maximum nesting depth.
+func (cf *CompositeFulfillment) UnmarshalJSON(b []byte) error {
+	type compositeFulfillment CompositeFulfillment
+	err := json.Unmarshal(b, (*compositeFulfillment)(cf))
+	if err != nil {
+		return err
+	}
+	if compositeFulfillmentDepth(cf) > MaxCompositeConditionDepth {
+		return ErrCompositeConditionTooDeep
+	}
+	return nil
+}
+
+// compositeFulfillmentDepth returns the nesting depth of the composite fulfillments
+// within the given fulfillment, 0 if it is not a composite fulfillment.
+func compositeFulfillmentDepth(fulfillment UnlockFulfillment) int {
+	switch tf := fulfillment.(type) {
+	case UnlockFulfillmentProxy:
+		if tf.Fulfillment == nil {
+			return 0
+		}
+		return compositeFulfillmentDepth(tf.Fulfillment)
+	case *CompositeFulfillment:
+		depth := 0
+		for _, branch := range tf.Branches {
+			if d := compositeFulfillmentDepth(branch.Fulfillment); d > depth {
+				depth = d
+			}
+		}
+		return depth + 1
+	default:
+		return 0
+	}
+}
+
+// MarshalSia implements siabin.SiaMarshaler.MarshalSia
+//
+// Marshals this ConditionType as a single byte.
+func (ct ConditionType) MarshalSia(w io.Writer) error {
+	_, err := w.Write([]byte{byte(ct)})
+	return err
+}
+
+// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
+//
+// Unmarshals this ConditionType from a single byte.
+func (ct *ConditionType) UnmarshalSia(r io.Reader) error {
+	var b [1]byte
+	_, err := r.Read(b[:])
+	*ct = ConditionType(b[0])
+	return err
+}
+
+// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
+//
+// Marshals this ConditionType as a single byte.
+func (ct ConditionType) MarshalRivine(w io.Writer) error {
+	return rivbin.MarshalUint8(w, uint8(ct))
+}
+
+// UnmarshalRivine implements rivbin.RivineUnmarshaler.UnmarshalRivine
+//
+// Unmarshals this ConditionType from a single byte.
+func (ct *ConditionType) UnmarshalRivine(r io.Reader) error {
+	x, err := rivbin.UnmarshalUint8(r)
+	if err != nil {
+		return err
+	}
+	*ct = ConditionType(x)
+	return err
+}
+
+// MarshalSia implements siabin.SiaMarshaler.MarshalSia
+//
+// Marshals this FulfillmentType as a single byte.
+func (ft FulfillmentType) MarshalSia(w io.Writer) error {
+	_, err := w.Write([]byte{byte(ft)})
+	return err
+}
+
+// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
+//
+// Unmarshals this FulfillmentType from a single byte.
+func (ft *FulfillmentType) UnmarshalSia(r io.Reader) error {
+	var b [1]byte
+	_, err := r.Read(b[:])
+	*ft = FulfillmentType(b[0])
+	return err
+}
+
+// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
+//
+// Marshals this FulfillmentType as a single byte.
+func (ft FulfillmentType) MarshalRivine(w io.Writer) error {
+	return rivbin.MarshalUint8(w, uint8(ft))
+}
+
+// UnmarshalRivin implements rivbin.RivineUnmarshaler.UnmarshalRivin
+//
+// Unmarshals this FulfillmentType from a single byte.
+func (ft *FulfillmentType) UnmarshalRivin(r io.Reader) error {
+	x, err := rivbin.UnmarshalUint8(r)
+	if err != nil {
+		return err
+	}
+	*ft = FulfillmentType(x)
+	return err
+}
+
+// Fulfill implements UnlockFulfillment.Fulfill
+//
+// If no child is defined, an error will be returned,
+// otherwise the child condition will be attempted to be fulfilled
+// using the given fulfillment within the given (fulfill) context.
+func (up UnlockConditionProxy) Fulfill(fulfillment UnlockFulfillment, ctx FulfillContext) error {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	if p, ok := fulfillment.(UnlockFulfillmentProxy); ok {
+		fulfillment = p.Fulfillment
+		if fulfillment == nil {
+			fulfillment = &NilFulfillment{}
+		}
+	}
+	return condition.Fulfill(fulfillment, ctx)
+}
+
+// ConditionType implements UnlockCondition.ConditionType
+//
+// ConditionType returns the ConditionType of the child UnlockCondition.
+// If no child is defined, a NilCondition is assumed.
+func (up UnlockConditionProxy) ConditionType() ConditionType {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	return condition.ConditionType()
+}
+
+// IsStandardCondition implements UnlockCondition.IsStandardCondition
+//
+// If no child is defined, nil will be returned,
+// otherwise the question will be delegated to the child condition.
+func (up UnlockConditionProxy) IsStandardCondition(ctx ValidationContext) error {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	return condition.IsStandardCondition(ctx)
+}
+
+// UnlockHash implements UnlockCondition.UnlockHash
+//
+// If no child is defined, a nil hash will be returned,
+// otherwise the child condition's unlock hash will be returned.
+func (up UnlockConditionProxy) UnlockHash() UnlockHash {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	return condition.UnlockHash()
+}
+
+// Equal implements UnlockCondition.Equal
+//
+// If no child is defined, the given UnlockCondition will be compared to the NilCondition,
+// otherwise the child condition will be returned with the given UnlockCondition.
+func (up UnlockConditionProxy) Equal(o UnlockCondition) bool {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	if p, ok := o.(UnlockConditionProxy); ok {
+		o = p.Condition
+	}
+	return condition.Equal(o)
+}
+
+// Fulfillable implements UnlockCondition.Fulfillable
+func (up UnlockConditionProxy) Fulfillable(ctx FulfillableContext) bool {
+	condition := up.Condition
+	if condition == nil {
+		condition = &NilCondition{}
+	}
+	return condition.Fulfillable(ctx)
+}
+
+// Sign implements UnlockFulfillment.Sign
+//
+// If no child is defined, an error will be returned,
 // otherwise the child fulfillment will be signed within the given (fulfill sign) context.
 func (fp UnlockFulfillmentProxy) Sign(ctx FulfillmentSignContext) error {
 	fulfillment := fp.Fulfillment
@@ -2382,6 +4434,23 @@ func strictSignatureCheck(pk PublicKey, signature ByteSlice) error {
 			return errors.New("invalid signature size in transaction")
 		}
 		return nil
+	case SignatureAlgoSecp256k1:
+		if len(pk.Key) != crypto.Secp256k1PublicKeySize {
+			return errors.New("invalid public key size in transaction")
+		}
+		if len(signature) != crypto.Secp256k1SignatureSize {
+			return errors.New("invalid signature size in transaction")
+		}
+		return nil
+	case SignatureAlgoBLS12381:
+		if len(pk.Key) != crypto.BLSPublicKeySize {
+			return errors.New("invalid public key size in transaction")
+		}
+		// an empty signature is allowed, as its signature can be aggregated into another
+		if len(signature) != crypto.BLSSignatureSize && len(signature) != 0 {
+			return errors.New("invalid signature size in transaction")
+		}
+		return nil
 	default:
 		return errors.New("unrecognized public key type in transaction")
 	}
@@ -2403,7 +4472,7 @@ func mergeExtraObjects(extraObjects []interface{}, fulfillmentDefinedObjects ...
 // using the given (optional private) key, and using any extra objects (on top of the normal properties).
 // The public key is to be given, as based on that the function can figure out what algorithm to use,
 // and this also allows the function to know how to interpret the given (private) key.
-func signHashUsingPublicKey(pk PublicKey, tx Transaction, key interface{}, extraObjects []interface{}) ([]byte, error) {
+func signHashUsingPublicKey(pk PublicKey, tx signatureHasher, key interface{}, extraObjects []interface{}) ([]byte, error) {
 	switch pk.Algorithm {
 	case SignatureAlgoEd25519:
 		// decode the ed-secretKey
@@ -2434,6 +4503,70 @@ func signHashUsingPublicKey(pk PublicKey, tx Transaction, key interface{}, extra
 		sig := crypto.SignHash(sigHash, edSK)
 		return sig[:], nil
 
+	case SignatureAlgoSecp256k1:
+		// decode the secp256k1-secretKey
+		var secpSK crypto.Secp256k1SecretKey
+		switch k := key.(type) {
+		case crypto.Secp256k1SecretKey:
+			secpSK = k
+		case ByteSlice:
+			if len(k) != crypto.Secp256k1SecretKeySize {
+				return nil, errors.New("invalid secret key size")
+			}
+			copy(secpSK[:], k)
+		case []byte:
+			if len(k) != crypto.Secp256k1SecretKeySize {
+				return nil, errors.New("invalid secret key size")
+			}
+			copy(secpSK[:], k)
+		default:
+			return nil, fmt.Errorf("%T is an unknown secret key type", key)
+		}
+		if secpSK.IsNil() {
+			return nil, crypto.ErrSecretNilKey
+		}
+		sigHash, err := tx.SignatureHash(extraObjects...)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := crypto.SignHashSecp256k1(sigHash, secpSK)
+		if err != nil {
+			return nil, err
+		}
+		return sig[:], nil
+
+	case SignatureAlgoBLS12381:
+		// decode the BLS-secretKey
+		var blsSK crypto.BLSSecretKey
+		switch k := key.(type) {
+		case crypto.BLSSecretKey:
+			blsSK = k
+		case ByteSlice:
+			if len(k) != crypto.BLSSecretKeySize {
+				return nil, errors.New("invalid secret key size")
+			}
+			copy(blsSK[:], k)
+		case []byte:
+			if len(k) != crypto.BLSSecretKeySize {
+				return nil, errors.New("invalid secret key size")
+			}
+			copy(blsSK[:], k)
+		default:
+			return nil, fmt.Errorf("%T is an unknown secret key type", key)
+		}
+		if blsSK.IsNil() {
+			return nil, crypto.ErrSecretNilKey
+		}
+		sigHash, err := tx.SignatureHash(extraObjects...)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := crypto.SignHashBLS(sigHash, blsSK)
+		if err != nil {
+			return nil, err
+		}
+		return sig[:], nil
+
 	default:
 		return nil, ErrUnknownSignAlgorithmType
 	}
@@ -2448,7 +4581,11 @@ func signHashUsingPublicKey(pk PublicKey, tx Transaction, key interface{}, extra
 // 2. using the algorithm type of the given public key,
 //    as to figure out what signature algorithm is used,
 //    and thus being able to know how to verify the given signature;
-func verifyHashUsingPublicKey(pk PublicKey, tx Transaction, sig []byte, extraObjects []interface{}) (err error) {
+//
+// BLS signatures are added to the BLS signature batch of the given context instead,
+// in case such a batch is defined.
+func verifyHashUsingPublicKey(pk PublicKey, ctx FulfillContext, sig []byte, extraObjects []interface{}) (err error) {
+	tx := ctx.signatureHasher()
 	switch pk.Algorithm {
 	case SignatureAlgoEd25519:
 		// Decode the public key and signature.
@@ -2459,7 +4596,7 @@ func verifyHashUsingPublicKey(pk PublicKey, tx Transaction, sig []byte, extraObj
 		copy(edPK[:], pk.Key)
 		copy(edSig[:], sig)
 		if edPK.IsNil() {
-			return crypto.ErrPublicNilKey
+			return ErrNilPublicKey
 		}
 		cryptoSig := crypto.Signature(edSig)
 		var sigHash crypto.Hash
@@ -2468,9 +4605,52 @@ func verifyHashUsingPublicKey(pk PublicKey, tx Transaction, sig []byte, extraObj
 			err = crypto.VerifyHash(sigHash, edPK, cryptoSig)
 		}
 
+	case SignatureAlgoSecp256k1:
+		// Decode the public key and signature.
+		var (
+			secpPK  crypto.Secp256k1PublicKey
+			secpSig crypto.Secp256k1Signature
+		)
+		copy(secpPK[:], pk.Key)
+		copy(secpSig[:], sig)
+		if secpPK.IsNil() {
+			return ErrNilPublicKey
+		}
+		var sigHash crypto.Hash
+		sigHash, err = tx.SignatureHash(extraObjects...)
+		if err == nil {
+			err = crypto.VerifyHashSecp256k1(sigHash, secpPK, secpSig)
+		}
+
+	case SignatureAlgoBLS12381:
+		// Decode the public key, the signature is decoded only when verified on its own.
+		var blsPK crypto.BLSPublicKey
+		copy(blsPK[:], pk.Key)
+		if blsPK.IsNil() {
+			return ErrNilPublicKey
+		}
+		var sigHash crypto.Hash
+		sigHash, err = tx.SignatureHash(extraObjects...)
+		if err != nil {
+			return
+		}
+		if ctx.BLSSignatureBatch != nil {
+			ctx.BLSSignatureBatch.add(sigHash, blsPK, sig)
+			return nil
+		}
+		if len(sig) == 0 {
+			return ErrAggregatedBLSSignatureRequiresBatch
+		}
+		var blsSig crypto.BLSSignature
+		copy(blsSig[:], sig)
+		err = crypto.VerifyHashBLS(sigHash, blsPK, blsSig)
+
 	default:
 		err = ErrUnknownSignAlgorithmType
 	}
+	if err == crypto.ErrInvalidSignature {
+		err = ErrInvalidSignature
+	}
 	return
 }
 
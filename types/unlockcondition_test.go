@@ -3257,3 +3257,960 @@ func TestMultisigAddresses(t *testing.T) {
 		t.Fatalf("expected uh2 (%s) to be equal to uh3 (%s), but the they weren't", uh2.String(), uh3.String())
 	}
 }
+
+func TestColdStakingCondition(t *testing.T) {
+	ownerSK, ownerPK := crypto.GenerateKeyPair()
+	stakerSK, stakerPK := crypto.GenerateKeyPair()
+	ownerUH, err := NewEd25519PubKeyUnlockHash(ownerPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stakerUH, err := NewEd25519PubKeyUnlockHash(stakerPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := NewColdStakingCondition(ownerUH, stakerUH)
+	if err = condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("cold staking condition should be standard:", err)
+	}
+	if condition.UnlockHash() != ownerUH {
+		t.Fatal("cold staking condition should be owned by the owner:", condition.UnlockHash())
+	}
+
+	// binary and JSON encoding
+	up := NewCondition(condition)
+	b, err := siabin.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bup UnlockConditionProxy
+	if err = siabin.Unmarshal(b, &bup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(bup) {
+		t.Fatal("siabin round trip failed:", bup)
+	}
+	b, err = json.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jup UnlockConditionProxy
+	if err = json.Unmarshal(b, &jup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(jup) {
+		t.Fatal("JSON round trip failed:", string(b))
+	}
+
+	fulfill := func(txn Transaction, sk crypto.SecretKey, pk crypto.PublicKey) error {
+		ff := NewSingleSignatureFulfillment(Ed25519PublicKey(pk))
+		err := ff.Sign(FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  txn,
+			Key:          sk,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return condition.Fulfill(ff, FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  txn,
+		})
+	}
+
+	respendTxn := Transaction{
+		Version:          TransactionVersionOne,
+		BlockStakeInputs: []BlockStakeInput{{ParentID: BlockStakeOutputID{1}}},
+		BlockStakeOutputs: []BlockStakeOutput{
+			{Value: NewCurrency64(10), Condition: up},
+		},
+	}
+	transferTxn := Transaction{
+		Version:          TransactionVersionOne,
+		BlockStakeInputs: []BlockStakeInput{{ParentID: BlockStakeOutputID{1}}},
+		BlockStakeOutputs: []BlockStakeOutput{
+			{Value: NewCurrency64(10), Condition: NewCondition(NewUnlockHashCondition(stakerUH))},
+		},
+	}
+
+	// the owner can do whatever it wants
+	if err = fulfill(respendTxn, ownerSK, ownerPK); err != nil {
+		t.Error("owner failed to respend:", err)
+	}
+	if err = fulfill(transferTxn, ownerSK, ownerPK); err != nil {
+		t.Error("owner failed to transfer:", err)
+	}
+	// the staker can only respend
+	if err = fulfill(respendTxn, stakerSK, stakerPK); err != nil {
+		t.Error("staker failed to respend:", err)
+	}
+	if err = fulfill(transferTxn, stakerSK, stakerPK); err != ErrInvalidColdStakingRespend {
+		t.Error("staker should not be able to transfer, unexpected error:", err)
+	}
+	// no one else can fulfill
+	otherSK, otherPK := crypto.GenerateKeyPair()
+	if err = fulfill(respendTxn, otherSK, otherPK); err == nil {
+		t.Error("unknown key should not be able to fulfill a cold staking condition")
+	}
+}
+
+func TestHashedTimeLockCondition(t *testing.T) {
+	receiverSK, receiverPK := crypto.GenerateKeyPair()
+	refunderSK, refunderPK := crypto.GenerateKeyPair()
+	receiverUH, err := NewEd25519PubKeyUnlockHash(receiverPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refunderUH, err := NewEd25519PubKeyUnlockHash(refunderPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := AtomicSwapSecret{1, 2, 3, 4}
+	const lockTime = 1549012665
+	condition := NewHashedTimeLockCondition(receiverUH, refunderUH, NewAtomicSwapHashedSecret(secret), lockTime)
+	if err = condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("hashed time lock condition should be standard:", err)
+	}
+
+	// siabin, rivbin and JSON encoding
+	up := NewCondition(condition)
+	b, err := siabin.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sup UnlockConditionProxy
+	if err = siabin.Unmarshal(b, &sup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(sup) {
+		t.Fatal("siabin round trip failed:", sup)
+	}
+	b, err = rivbin.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rup UnlockConditionProxy
+	if err = rivbin.Unmarshal(b, &rup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(rup) {
+		t.Fatal("rivbin round trip failed:", rup)
+	}
+	b, err = json.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jup UnlockConditionProxy
+	if err = json.Unmarshal(b, &jup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(jup) {
+		t.Fatal("JSON round trip failed:", string(b))
+	}
+
+	// the unlock hash differs from the atomic swap with the same layout, but inverted roles
+	atomicSwap := &AtomicSwapCondition{
+		Sender:       receiverUH,
+		Receiver:     refunderUH,
+		HashedSecret: condition.HashedSecret,
+		TimeLock:     lockTime,
+	}
+	if uh := condition.UnlockHash(); uh.Type != UnlockTypeAtomicSwap || uh.Cmp(atomicSwap.UnlockHash()) == 0 {
+		t.Error("unexpected hashed time lock unlock hash:", uh)
+	}
+
+	txn := Transaction{
+		Version:    TransactionVersionOne,
+		CoinInputs: []CoinInput{{ParentID: CoinOutputID{1}}},
+		CoinOutputs: []CoinOutput{
+			{Value: NewCurrency64(10), Condition: NewCondition(NewUnlockHashCondition(receiverUH))},
+		},
+	}
+	fulfill := func(ff *HashedTimeLockFulfillment, sk crypto.SecretKey, blockTime Timestamp) error {
+		err := ff.Sign(FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  txn,
+			Key:          sk,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fp := NewFulfillment(ff)
+		b, err := rivbin.Marshal(fp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rfp UnlockFulfillmentProxy
+		if err = rivbin.Unmarshal(b, &rfp); err != nil {
+			t.Fatal(err)
+		}
+		if !fp.Equal(rfp) {
+			t.Fatal("rivbin round trip failed:", rfp)
+		}
+		return condition.Fulfill(ff, FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			BlockTime:    blockTime,
+			Transaction:  txn,
+		})
+	}
+
+	// the receiver can claim using the secret before the lock time
+	if err = fulfill(NewHashedTimeLockClaimFulfillment(Ed25519PublicKey(receiverPK), secret), receiverSK, lockTime-1); err != nil {
+		t.Error("receiver failed to claim:", err)
+	}
+	if err = fulfill(NewHashedTimeLockClaimFulfillment(Ed25519PublicKey(receiverPK), AtomicSwapSecret{4, 3, 2, 1}), receiverSK, lockTime-1); err != ErrInvalidPreImageSha256 {
+		t.Error("receiver should not be able to claim using a wrong secret, unexpected error:", err)
+	}
+	if err = fulfill(NewHashedTimeLockClaimFulfillment(Ed25519PublicKey(refunderPK), secret), refunderSK, lockTime-1); err != ErrInvalidRedeemer {
+		t.Error("refunder should not be able to claim, unexpected error:", err)
+	}
+	// ... but no longer once the lock time is reached
+	if err = fulfill(NewHashedTimeLockClaimFulfillment(Ed25519PublicKey(receiverPK), secret), receiverSK, lockTime); err != ErrExpiredClaim {
+		t.Error("receiver should not be able to claim an expired contract, unexpected error:", err)
+	}
+
+	// the refunder can only refund once the lock time is reached
+	if err = fulfill(NewHashedTimeLockRefundFulfillment(Ed25519PublicKey(refunderPK)), refunderSK, lockTime-1); err != ErrPrematureRefund {
+		t.Error("refunder should not be able to refund prematurely, unexpected error:", err)
+	}
+	if err = fulfill(NewHashedTimeLockRefundFulfillment(Ed25519PublicKey(refunderPK)), refunderSK, lockTime); err != nil {
+		t.Error("refunder failed to refund:", err)
+	}
+	if err = fulfill(NewHashedTimeLockRefundFulfillment(Ed25519PublicKey(receiverPK)), receiverSK, lockTime); err != ErrInvalidRedeemer {
+		t.Error("receiver should not be able to refund, unexpected error:", err)
+	}
+}
+
+func TestPaymentChannelCondition(t *testing.T) {
+	senderSK, senderPK := crypto.GenerateKeyPair()
+	receiverSK, receiverPK := crypto.GenerateKeyPair()
+	senderUH, err := NewEd25519PubKeyUnlockHash(senderPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiverUH, err := NewEd25519PubKeyUnlockHash(receiverPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const lockTime = 1000
+	condition := NewPaymentChannelCondition(senderUH, receiverUH, lockTime)
+	if err = condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("payment channel condition should be standard:", err)
+	}
+	if err = NewPaymentChannelCondition(senderUH, senderUH, lockTime).IsStandardCondition(ValidationContext{}); err == nil {
+		t.Fatal("payment channel condition to self should not be standard")
+	}
+
+	// siabin, rivbin and JSON encoding
+	up := NewCondition(condition)
+	b, err := siabin.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sup UnlockConditionProxy
+	if err = siabin.Unmarshal(b, &sup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(sup) {
+		t.Fatal("siabin round trip failed:", sup)
+	}
+	b, err = rivbin.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rup UnlockConditionProxy
+	if err = rivbin.Unmarshal(b, &rup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(rup) {
+		t.Fatal("rivbin round trip failed:", rup)
+	}
+	b, err = json.Marshal(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jup UnlockConditionProxy
+	if err = json.Unmarshal(b, &jup); err != nil {
+		t.Fatal(err)
+	}
+	if !up.Equal(jup) {
+		t.Fatal("JSON round trip failed:", string(b))
+	}
+
+	txn := Transaction{
+		Version:    TransactionVersionOne,
+		CoinInputs: []CoinInput{{ParentID: CoinOutputID{1}}},
+		CoinOutputs: []CoinOutput{
+			{Value: NewCurrency64(10), Condition: NewCondition(NewUnlockHashCondition(receiverUH))},
+		},
+	}
+	senderKeyPair := KeyPair{PublicKey: Ed25519PublicKey(senderPK), PrivateKey: ByteSlice(senderSK[:])}
+	receiverKeyPair := KeyPair{PublicKey: Ed25519PublicKey(receiverPK), PrivateKey: ByteSlice(receiverSK[:])}
+	fulfill := func(blockHeight BlockHeight, keyPairs ...KeyPair) error {
+		ff := &PaymentChannelFulfillment{}
+		for _, keyPair := range keyPairs {
+			err := ff.Sign(FulfillmentSignContext{
+				ExtraObjects: []interface{}{uint64(0)},
+				Transaction:  txn,
+				Key:          keyPair,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, marshal := range []struct {
+			Marshal   func(interface{}) ([]byte, error)
+			Unmarshal func([]byte, interface{}) error
+		}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+			fp := NewFulfillment(ff)
+			b, err := marshal.Marshal(fp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dfp UnlockFulfillmentProxy
+			if err = marshal.Unmarshal(b, &dfp); err != nil {
+				t.Fatal(err)
+			}
+			if !fp.Equal(dfp) {
+				t.Fatal("fulfillment round trip failed:", dfp)
+			}
+		}
+		return condition.Fulfill(ff, FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			BlockHeight:  blockHeight,
+			Transaction:  txn,
+		})
+	}
+
+	// sender and receiver can settle the channel at any time
+	if err = fulfill(lockTime-1, senderKeyPair, receiverKeyPair); err != nil {
+		t.Error("failed to settle channel:", err)
+	}
+	if err = fulfill(lockTime, senderKeyPair, receiverKeyPair); err != nil {
+		t.Error("failed to settle channel once the lock time is reached:", err)
+	}
+	// the receiver cannot spend the channel without the sender
+	if err = fulfill(lockTime, receiverKeyPair); err != ErrInvalidRedeemer {
+		t.Error("receiver should not be able to spend the channel on its own, unexpected error:", err)
+	}
+	if err = fulfill(lockTime, receiverKeyPair, receiverKeyPair); err != ErrFulfillmentDoubleSign {
+		t.Error("receiver should not be able to sign twice, unexpected error:", err)
+	}
+	// the sender can only refund once the lock time is reached
+	if err = fulfill(lockTime-1, senderKeyPair); err != ErrPrematureRefund {
+		t.Error("sender should not be able to refund prematurely, unexpected error:", err)
+	}
+	if err = fulfill(lockTime, senderKeyPair); err != nil {
+		t.Error("sender failed to refund:", err)
+	}
+}
+
+func TestBurnCondition(t *testing.T) {
+	up := NewCondition(NewBurnCondition())
+	if !IsBurnCondition(up) {
+		t.Fatal("expected burn condition")
+	}
+	if IsBurnCondition(NewCondition(nil)) {
+		t.Fatal("nil condition should not be a burn condition")
+	}
+	if up.Equal(NewCondition(nil)) {
+		t.Fatal("burn condition should not equal the nil condition")
+	}
+	if up.Fulfillable(FulfillableContext{}) {
+		t.Fatal("burn condition should never be fulfillable")
+	}
+	if err := up.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("burn condition should be standard:", err)
+	}
+	if err := up.Fulfill(NewFulfillment(&SingleSignatureFulfillment{}), FulfillContext{}); err != ErrBurnedOutput {
+		t.Fatal("unexpected fulfill result:", err)
+	}
+
+	uh := up.UnlockHash()
+	if uh != BurnUnlockHash {
+		t.Fatal("unexpected unlock hash:", uh.String())
+	}
+	var luh UnlockHash
+	if err := luh.LoadString(uh.String()); err != nil {
+		t.Fatal(err)
+	}
+	if luh != uh {
+		t.Fatal("unlock hash string round trip failed:", luh.String())
+	}
+
+	for _, marshal := range []struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+		b, err := marshal.Marshal(up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dup UnlockConditionProxy
+		if err = marshal.Unmarshal(b, &dup); err != nil {
+			t.Fatal(err)
+		}
+		if !up.Equal(dup) || !IsBurnCondition(dup) {
+			t.Fatal("round trip failed:", string(b))
+		}
+	}
+}
+
+func TestRelativeTimeLockCondition(t *testing.T) {
+	_, pk := crypto.GenerateKeyPair()
+	uh, err := NewEd25519PubKeyUnlockHash(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := NewRelativeTimeLockCondition(10, RelativeLockTimeUnitBlocks, NewUnlockHashCondition(uh))
+	if err = condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("relative time lock condition should be standard:", err)
+	}
+	if err = (&RelativeTimeLockCondition{LockTime: 10, Unit: 2, Condition: &NilCondition{}}).IsStandardCondition(ValidationContext{}); err == nil {
+		t.Fatal("relative time lock condition with unknown unit should not be standard")
+	}
+	if condition.UnlockHash() != uh {
+		t.Fatal("unexpected unlock hash:", condition.UnlockHash().String())
+	}
+
+	testCases := []struct {
+		Condition *RelativeTimeLockCondition
+		Context   FulfillableContext
+		Expected  bool
+	}{
+		{condition, FulfillableContext{BlockHeight: 100}, false},
+		{condition, FulfillableContext{BlockHeight: 100, OutputOrigin: &OutputOrigin{BlockHeight: 91}}, false},
+		{condition, FulfillableContext{BlockHeight: 100, OutputOrigin: &OutputOrigin{BlockHeight: 90}}, true},
+		{condition, FulfillableContext{BlockHeight: 100, OutputOrigin: &OutputOrigin{BlockHeight: 0}}, true},
+		{NewRelativeTimeLockCondition(3600, RelativeLockTimeUnitSeconds, nil), FulfillableContext{
+			BlockHeight: 100, BlockTime: 1600003599, OutputOrigin: &OutputOrigin{BlockHeight: 99, BlockTime: 1600000000}}, false},
+		{NewRelativeTimeLockCondition(3600, RelativeLockTimeUnitSeconds, nil), FulfillableContext{
+			BlockHeight: 100, BlockTime: 1600003600, OutputOrigin: &OutputOrigin{BlockHeight: 99, BlockTime: 1600000000}}, true},
+	}
+	for idx, testCase := range testCases {
+		if result := testCase.Condition.Fulfillable(testCase.Context); result != testCase.Expected {
+			t.Errorf("test case #%d: expected fulfillable to be %v, not %v", idx, testCase.Expected, result)
+		}
+	}
+
+	err = condition.Fulfill(&SingleSignatureFulfillment{}, FulfillContext{BlockHeight: 100})
+	if err == nil {
+		t.Fatal("relative time lock condition cannot be fulfilled without a known output origin")
+	}
+	err = condition.Fulfill(&SingleSignatureFulfillment{}, FulfillContext{BlockHeight: 100, OutputOrigin: &OutputOrigin{BlockHeight: 95}})
+	if err == nil {
+		t.Fatal("relative time lock condition cannot be fulfilled before the lock time is passed")
+	}
+
+	up := NewCondition(condition)
+	for _, marshal := range []struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+		b, err := marshal.Marshal(up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dup UnlockConditionProxy
+		if err = marshal.Unmarshal(b, &dup); err != nil {
+			t.Fatal(err)
+		}
+		if !up.Equal(dup) {
+			t.Fatal("round trip failed:", string(b))
+		}
+	}
+}
+
+func TestVestingCondition(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair()
+	owner, err := NewEd25519PubKeyUnlockHash(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		start  = 1600000000
+		month  = 30 * 24 * 3600
+		cliff  = 6 * month
+		months = 24
+	)
+	condition := NewVestingCondition(owner, NewCurrency64(2400), start, cliff, month, months)
+	if err = condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("vesting condition should be standard:", err)
+	}
+	if err = NewVestingCondition(owner, NewCurrency64(2400), start, cliff, month, 0).IsStandardCondition(ValidationContext{}); err == nil {
+		t.Fatal("vesting condition without periods should not be standard")
+	}
+	if condition.UnlockHash() != owner {
+		t.Fatal("unexpected unlock hash:", condition.UnlockHash().String())
+	}
+
+	vestingTestCases := []struct {
+		Time   Timestamp
+		Vested uint64
+	}{
+		{start - 1, 0},
+		{start, 0},
+		{start + cliff - 1, 0},
+		{start + cliff, 600},
+		{start + 7*month - 1, 600},
+		{start + 7*month, 700},
+		{start + months*month, 2400},
+		{start + 100*month, 2400},
+	}
+	for idx, testCase := range vestingTestCases {
+		if vested := condition.VestedAmount(testCase.Time); !vested.Equals64(testCase.Vested) {
+			t.Errorf("test case #%d: expected %d coins to be vested, not %s", idx, testCase.Vested, vested.String())
+		}
+	}
+	if condition.Fulfillable(FulfillableContext{BlockTime: start + months*month - 1}) {
+		t.Error("vesting condition should not be fulfillable before all coins are vested")
+	}
+	if !condition.Fulfillable(FulfillableContext{BlockTime: start + months*month}) {
+		t.Error("vesting condition should be fulfillable once all coins are vested")
+	}
+
+	// fulfillInputs fulfills all given spent outputs as the coin inputs of a single transaction
+	unknownSpentOutputs := false
+	fulfillInputs := func(blockTime Timestamp, spent []CoinOutput, outputs ...CoinOutput) error {
+		txn := Transaction{
+			Version:     TransactionVersionOne,
+			CoinOutputs: outputs,
+		}
+		spentCoinOutputs := make(map[CoinOutputID]CoinOutput, len(spent))
+		for idx, co := range spent {
+			id := CoinOutputID{byte(idx + 1)}
+			txn.CoinInputs = append(txn.CoinInputs, CoinInput{ParentID: id})
+			spentCoinOutputs[id] = co
+		}
+		for idx := range txn.CoinInputs {
+			ff := NewSingleSignatureFulfillment(Ed25519PublicKey(pk))
+			err := ff.Sign(FulfillmentSignContext{
+				ExtraObjects: []interface{}{uint64(idx)},
+				Transaction:  txn,
+				Key:          ByteSlice(sk[:]),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			txn.CoinInputs[idx].Fulfillment = NewFulfillment(ff)
+		}
+		if unknownSpentOutputs {
+			spentCoinOutputs = nil
+		}
+		for idx, ci := range txn.CoinInputs {
+			err := spent[idx].Condition.Fulfill(ci.Fulfillment, FulfillContext{
+				ExtraObjects:     []interface{}{uint64(idx)},
+				BlockTime:        blockTime,
+				Transaction:      txn,
+				SpentCoinOutputs: spentCoinOutputs,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	vesting := CoinOutput{Value: NewCurrency64(2400), Condition: NewCondition(condition)}
+	fulfill := func(blockTime Timestamp, outputs ...CoinOutput) error {
+		return fulfillInputs(blockTime, []CoinOutput{vesting}, outputs...)
+	}
+	free := CoinOutput{Value: NewCurrency64(700), Condition: NewCondition(NewUnlockHashCondition(owner))}
+	if err = fulfill(start+7*month, free, CoinOutput{Value: NewCurrency64(1700), Condition: NewCondition(condition)}); err != nil {
+		t.Error("owner should be able to spend the vested coins:", err)
+	}
+	if err = fulfill(start+7*month, free, CoinOutput{Value: NewCurrency64(1699), Condition: NewCondition(condition)}); err == nil {
+		t.Error("owner should not be able to spend coins which are not yet vested")
+	}
+	if err = fulfill(start+7*month, free,
+		CoinOutput{Value: NewCurrency64(1700), Condition: NewCondition(condition)},
+		CoinOutput{Value: NewCurrency64(1700), Condition: NewCondition(condition)}); err == nil {
+		t.Error("coins which are not yet vested have to be respent as a single output")
+	}
+	if err = fulfill(start+months*month, CoinOutput{Value: NewCurrency64(2400), Condition: NewCondition(NewUnlockHashCondition(owner))}); err != nil {
+		t.Error("owner should be able to spend all coins once vested:", err)
+	}
+
+	// inputs spending outputs with the same vesting condition cannot share a single respend
+	twoVesting := []CoinOutput{vesting, vesting}
+	if err = fulfillInputs(start+7*month, twoVesting,
+		CoinOutput{Value: NewCurrency64(3100), Condition: NewCondition(NewUnlockHashCondition(owner))},
+		CoinOutput{Value: NewCurrency64(1700), Condition: NewCondition(condition)}); err == nil {
+		t.Error("the coins which are not yet vested of both inputs have to be respent")
+	}
+	if err = fulfillInputs(start+7*month, twoVesting,
+		CoinOutput{Value: NewCurrency64(1400), Condition: NewCondition(NewUnlockHashCondition(owner))},
+		CoinOutput{Value: NewCurrency64(3400), Condition: NewCondition(condition)}); err != nil {
+		t.Error("owner should be able to respend the coins which are not yet vested of both inputs as a single output:", err)
+	}
+	// the locked amount of an output is capped at its value
+	partial := CoinOutput{Value: NewCurrency64(500), Condition: NewCondition(condition)}
+	if err = fulfillInputs(start+7*month, []CoinOutput{vesting, partial},
+		CoinOutput{Value: NewCurrency64(700), Condition: NewCondition(NewUnlockHashCondition(owner))},
+		CoinOutput{Value: NewCurrency64(2200), Condition: NewCondition(condition)}); err != nil {
+		t.Error("the locked amount of an output should be capped at its value:", err)
+	}
+	if err = fulfillInputs(start+7*month, []CoinOutput{vesting, partial},
+		CoinOutput{Value: NewCurrency64(701), Condition: NewCondition(NewUnlockHashCondition(owner))},
+		CoinOutput{Value: NewCurrency64(2199), Condition: NewCondition(condition)}); err == nil {
+		t.Error("owner should not be able to spend the locked coins of a partial output")
+	}
+	// the spent coin outputs have to be known while coins are not yet vested
+	unknownSpentOutputs = true
+	if err = fulfill(start+7*month, free, CoinOutput{Value: NewCurrency64(1700), Condition: NewCondition(condition)}); err != ErrUnknownSpentCoinOutputs {
+		t.Error("vesting condition should not be fulfilled without the spent coin outputs being known:", err)
+	}
+	if err = fulfill(start+months*month, free); err != nil {
+		t.Error("spent coin outputs should not be required once all coins are vested:", err)
+	}
+
+	up := NewCondition(condition)
+	for _, marshal := range []struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+		b, err := marshal.Marshal(up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dup UnlockConditionProxy
+		if err = marshal.Unmarshal(b, &dup); err != nil {
+			t.Fatal(err)
+		}
+		if !up.Equal(dup) {
+			t.Fatal("round trip failed:", string(b))
+		}
+	}
+}
+
+func TestWeightedMultiSignatureCondition(t *testing.T) {
+	type signatory struct {
+		sk crypto.SecretKey
+		pk crypto.PublicKey
+		uh UnlockHash
+	}
+	signatories := make([]signatory, 4)
+	for idx := range signatories {
+		sk, pk := crypto.GenerateKeyPair()
+		uh, err := NewEd25519PubKeyUnlockHash(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatories[idx] = signatory{sk: sk, pk: pk, uh: uh}
+	}
+	// CFO=2, clerks=1, threshold 3
+	condition := NewWeightedMultiSignatureCondition([]WeightedUnlockHash{
+		{UnlockHash: signatories[0].uh, Weight: 2},
+		{UnlockHash: signatories[1].uh, Weight: 1},
+		{UnlockHash: signatories[2].uh, Weight: 1},
+		{UnlockHash: signatories[3].uh, Weight: 1},
+	}, 3)
+	if err := condition.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("weighted multisig condition should be standard:", err)
+	}
+	for idx, invalid := range []*WeightedMultiSignatureCondition{
+		{Signatories: condition.Signatories, MinimumWeight: 6},
+		{Signatories: condition.Signatories[:1], MinimumWeight: 1},
+		{Signatories: []WeightedUnlockHash{condition.Signatories[0], condition.Signatories[0]}, MinimumWeight: 1},
+		{Signatories: []WeightedUnlockHash{condition.Signatories[0], {UnlockHash: signatories[1].uh}}, MinimumWeight: 1},
+		{Signatories: condition.Signatories},
+	} {
+		if err := invalid.IsStandardCondition(ValidationContext{}); err == nil {
+			t.Errorf("invalid condition #%d should not be standard", idx)
+		}
+	}
+	if n := condition.GetMinimumSignatureCount(); n != 2 {
+		t.Errorf("expected a minimum of 2 signatures, not %d", n)
+	}
+	uh := condition.UnlockHash()
+	if uh.Type != UnlockTypeMultiSig {
+		t.Fatal("unexpected unlock hash type:", uh.Type)
+	}
+	if uh == NewMultiSignatureCondition(condition.UnlockHashSlice(), 3).UnlockHash() {
+		t.Fatal("weighted multisig condition should not have the same unlock hash as a regular multisig condition")
+	}
+	reordered := NewWeightedMultiSignatureCondition([]WeightedUnlockHash{
+		condition.Signatories[3], condition.Signatories[2], condition.Signatories[1], condition.Signatories[0],
+	}, 3)
+	if reordered.UnlockHash() != uh || !reordered.Equal(condition) {
+		t.Fatal("ordering of signatories should not matter")
+	}
+	reweighted := NewWeightedMultiSignatureCondition([]WeightedUnlockHash{
+		condition.Signatories[0], condition.Signatories[1], condition.Signatories[2],
+		{UnlockHash: signatories[3].uh, Weight: 2},
+	}, 3)
+	if reweighted.UnlockHash() == uh || reweighted.Equal(condition) {
+		t.Fatal("weights should be part of the identity of the condition")
+	}
+
+	fulfill := func(signers ...int) error {
+		txn := Transaction{
+			Version:    TransactionVersionOne,
+			CoinInputs: []CoinInput{{ParentID: CoinOutputID{1}}},
+		}
+		ff := NewMultiSignatureFulfillment(nil)
+		for _, idx := range signers {
+			err := ff.Sign(FulfillmentSignContext{
+				ExtraObjects: []interface{}{uint64(0)},
+				Transaction:  txn,
+				Key: KeyPair{
+					PublicKey:  Ed25519PublicKey(signatories[idx].pk),
+					PrivateKey: ByteSlice(signatories[idx].sk[:]),
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewCondition(condition).Fulfill(NewFulfillment(ff), FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  txn,
+		})
+	}
+	for idx, testCase := range []struct {
+		Signers []int
+		Valid   bool
+	}{
+		{[]int{0, 1}, true},
+		{[]int{1, 2, 3}, true},
+		{[]int{0, 1, 2, 3}, true},
+		{[]int{0}, false},
+		{[]int{1, 2}, false},
+		{[]int{1, 1, 1}, false},
+	} {
+		err := fulfill(testCase.Signers...)
+		if testCase.Valid && err != nil {
+			t.Errorf("test case #%d: signers %v should fulfill the condition: %v", idx, testCase.Signers, err)
+		} else if !testCase.Valid && err == nil {
+			t.Errorf("test case #%d: signers %v should not fulfill the condition", idx, testCase.Signers)
+		}
+	}
+
+	up := NewCondition(condition)
+	for _, marshal := range []struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+		b, err := marshal.Marshal(up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dup UnlockConditionProxy
+		if err = marshal.Unmarshal(b, &dup); err != nil {
+			t.Fatal(err)
+		}
+		if !up.Equal(dup) {
+			t.Fatal("round trip failed:", string(b))
+		}
+	}
+}
+
+func TestCompositeCondition(t *testing.T) {
+	type signatory struct {
+		sk crypto.SecretKey
+		pk crypto.PublicKey
+		uh UnlockHash
+	}
+	signatories := make([]signatory, 4)
+	for idx := range signatories {
+		sk, pk := crypto.GenerateKeyPair()
+		uh, err := NewEd25519PubKeyUnlockHash(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatories[idx] = signatory{sk: sk, pk: pk, uh: uh}
+	}
+	txn := Transaction{
+		Version:    TransactionVersionOne,
+		CoinInputs: []CoinInput{{ParentID: CoinOutputID{1}}},
+	}
+	signCtx := func(idx int) FulfillmentSignContext {
+		return FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  txn,
+			Key: KeyPair{
+				PublicKey:  Ed25519PublicKey(signatories[idx].pk),
+				PrivateKey: ByteSlice(signatories[idx].sk[:]),
+			},
+		}
+	}
+	singleSignature := func(idx int) *SingleSignatureFulfillment {
+		ff := NewSingleSignatureFulfillment(Ed25519PublicKey(signatories[idx].pk))
+		ctx := signCtx(idx)
+		ctx.Key = ByteSlice(signatories[idx].sk[:])
+		if err := ff.Sign(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return ff
+	}
+	multiSignature := func(signers ...int) *MultiSignatureFulfillment {
+		ff := NewMultiSignatureFulfillment(nil)
+		for _, idx := range signers {
+			if err := ff.Sign(signCtx(idx)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return ff
+	}
+	fulfill := func(condition MarshalableUnlockCondition, ff MarshalableUnlockFulfillment, height BlockHeight) error {
+		return NewCondition(condition).Fulfill(NewFulfillment(ff), FulfillContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			BlockHeight:  height,
+			Transaction:  txn,
+		})
+	}
+
+	// (2-of-3 multisig) OR (single key after block 100)
+	multisig := NewMultiSignatureCondition(UnlockHashSlice{signatories[0].uh, signatories[1].uh, signatories[2].uh}, 2)
+	anyOf := NewAnyOfCondition(multisig, NewTimeLockCondition(100, NewUnlockHashCondition(signatories[3].uh)))
+	if err := anyOf.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("any-of condition should be standard:", err)
+	}
+	for idx, testCase := range []struct {
+		Fulfillment MarshalableUnlockFulfillment
+		Height      BlockHeight
+		Valid       bool
+	}{
+		{NewAnyOfFulfillment(0, multiSignature(0, 2)), 1, true},
+		{NewAnyOfFulfillment(0, multiSignature(1)), 1, false},
+		{NewAnyOfFulfillment(1, singleSignature(3)), 100, true},
+		{NewAnyOfFulfillment(1, singleSignature(3)), 99, false},
+		{NewAnyOfFulfillment(1, singleSignature(0)), 100, false},
+		{NewAnyOfFulfillment(2, singleSignature(3)), 100, false},
+		{NewAllOfFulfillment(multiSignature(0, 1), singleSignature(3)), 100, false},
+		{multiSignature(0, 1), 1, false},
+	} {
+		err := fulfill(anyOf, testCase.Fulfillment, testCase.Height)
+		if testCase.Valid && err != nil {
+			t.Errorf("test case #%d: should fulfill the any-of condition: %v", idx, err)
+		} else if !testCase.Valid && err == nil {
+			t.Errorf("test case #%d: should not fulfill the any-of condition", idx)
+		}
+	}
+	if anyOf.Fulfillable(FulfillableContext{BlockHeight: 1}) != true {
+		t.Error("any-of condition should be fulfillable as long as one of its conditions is")
+	}
+
+	allOf := NewAllOfCondition(NewUnlockHashCondition(signatories[0].uh), NewUnlockHashCondition(signatories[1].uh))
+	if err := allOf.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("all-of condition should be standard:", err)
+	}
+	for idx, testCase := range []struct {
+		Fulfillment MarshalableUnlockFulfillment
+		Valid       bool
+	}{
+		{NewAllOfFulfillment(singleSignature(0), singleSignature(1)), true},
+		{NewAllOfFulfillment(singleSignature(1), singleSignature(0)), false},
+		{NewAllOfFulfillment(singleSignature(0)), false},
+		{NewAnyOfFulfillment(0, singleSignature(0)), false},
+		{&CompositeFulfillment{Branches: []CompositeFulfillmentBranch{
+			{Index: 1, Fulfillment: NewFulfillment(singleSignature(1))},
+			{Index: 0, Fulfillment: NewFulfillment(singleSignature(0))},
+		}}, false},
+	} {
+		err := fulfill(allOf, testCase.Fulfillment, 1)
+		if testCase.Valid && err != nil {
+			t.Errorf("test case #%d: should fulfill the all-of condition: %v", idx, err)
+		} else if !testCase.Valid && err == nil {
+			t.Errorf("test case #%d: should not fulfill the all-of condition", idx)
+		}
+	}
+
+	// unlock hashes commit to the full policy, including nested lock times
+	uh := anyOf.UnlockHash()
+	if uh.Type != UnlockTypeMultiSig {
+		t.Fatal("unexpected unlock hash type:", uh.Type)
+	}
+	for idx, other := range []MarshalableUnlockCondition{
+		NewAnyOfCondition(multisig, NewTimeLockCondition(101, NewUnlockHashCondition(signatories[3].uh))),
+		NewAnyOfCondition(NewTimeLockCondition(100, NewUnlockHashCondition(signatories[3].uh)), multisig),
+		NewAllOfCondition(multisig, NewTimeLockCondition(100, NewUnlockHashCondition(signatories[3].uh))),
+	} {
+		if other.UnlockHash() == uh || other.Equal(anyOf) {
+			t.Errorf("condition #%d should not have the same identity as the original condition", idx)
+		}
+	}
+	if uhs := anyOf.UnlockHashSlice(); len(uhs) != 4 {
+		t.Errorf("expected the unlock hashes of all 4 signatories, not: %v", uhs)
+	}
+
+	// non-standard composite conditions
+	nested := MarshalableUnlockCondition(allOf)
+	for depth := 1; depth < MaxCompositeConditionDepth; depth++ {
+		nested = NewAnyOfCondition(nested, NewUnlockHashCondition(signatories[0].uh))
+	}
+	if err := nested.IsStandardCondition(ValidationContext{}); err != nil {
+		t.Fatal("composite condition of maximum depth should be standard:", err)
+	}
+	tooDeep := NewAnyOfCondition(nested, NewUnlockHashCondition(signatories[0].uh))
+	for idx, invalid := range []MarshalableUnlockCondition{
+		tooDeep,
+		&AnyOfCondition{Conditions: []UnlockConditionProxy{NewCondition(multisig)}},
+		NewAnyOfCondition(multisig, &NilCondition{}),
+		NewAllOfCondition(multisig, &BurnCondition{}),
+		NewAllOfCondition(multisig, NewColdStakingCondition(signatories[0].uh, signatories[1].uh)),
+	} {
+		if err := invalid.IsStandardCondition(ValidationContext{}); err == nil {
+			t.Errorf("invalid condition #%d should not be standard", idx)
+		}
+	}
+
+	if err := NewAllOfFulfillment(singleSignature(0), singleSignature(1)).IsStandardFulfillment(ValidationContext{}); err != nil {
+		t.Error("composite fulfillment should be standard:", err)
+	}
+	for idx, invalid := range []*CompositeFulfillment{
+		{},
+		NewAllOfFulfillment(singleSignature(0), &NilFulfillment{}),
+		{Branches: []CompositeFulfillmentBranch{
+			{Index: 1, Fulfillment: NewFulfillment(singleSignature(1))},
+			{Index: 1, Fulfillment: NewFulfillment(singleSignature(1))},
+		}},
+	} {
+		if err := invalid.IsStandardFulfillment(ValidationContext{}); err == nil {
+			t.Errorf("invalid fulfillment #%d should not be standard", idx)
+		}
+	}
+
+	for _, marshal := range []struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}{{siabin.Marshal, siabin.Unmarshal}, {rivbin.Marshal, rivbin.Unmarshal}, {json.Marshal, json.Unmarshal}} {
+		for _, condition := range []MarshalableUnlockCondition{anyOf, allOf, nested} {
+			up := NewCondition(condition)
+			b, err := marshal.Marshal(up)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dup UnlockConditionProxy
+			if err = marshal.Unmarshal(b, &dup); err != nil {
+				t.Fatal(err)
+			}
+			if !up.Equal(dup) || up.UnlockHash() != dup.UnlockHash() {
+				t.Fatal("round trip failed:", string(b))
+			}
+		}
+		b, err := marshal.Marshal(NewCondition(tooDeep))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dup UnlockConditionProxy
+		if err = marshal.Unmarshal(b, &dup); err == nil {
+			t.Error("composite condition exceeding the maximum depth should not decode")
+		}
+
+		fp := NewFulfillment(NewAllOfFulfillment(singleSignature(0), singleSignature(1)))
+		b, err = marshal.Marshal(fp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dfp UnlockFulfillmentProxy
+		if err = marshal.Unmarshal(b, &dfp); err != nil {
+			t.Fatal(err)
+		}
+		if !fp.Equal(dfp) {
+			t.Fatal("fulfillment round trip failed:", string(b))
+		}
+		if err = fulfill(allOf, dfp.Fulfillment, 1); err != nil {
+			t.Error("decoded fulfillment should fulfill the all-of condition:", err)
+		}
+	}
+}
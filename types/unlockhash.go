@@ -3,11 +3,14 @@ package types
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/threefoldtech/rivine/build"
 	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/bech32"
 	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 )
@@ -64,9 +67,17 @@ const (
 	// be spent after at least the specified amount of identities have agreed,
 	// by means of providing their signature.
 	UnlockTypeMultiSig
+
+	// UnlockTypeBurn defines the unlock type of the BurnCondition,
+	// of which the (single) unlock hash is BurnUnlockHash.
+	// Outputs sent to this unlock hash can never be spent.
+	UnlockTypeBurn
 )
 
 var (
+	// BurnUnlockHash is the unlock hash of the BurnCondition.
+	BurnUnlockHash = UnlockHash{Type: UnlockTypeBurn}
+
 	NilUnlockHash     UnlockHash
 	UnknownUnlockHash = UnlockHash{
 		Type: UnlockTypeNil,
@@ -229,13 +240,14 @@ func (uh UnlockHash) String() string {
 // of an unlock hash into an unlock hash object.
 // An error is returned if the string is invalid or
 // fails the checksum.
+//
+// See LoadAddressString in order to accept the bech32 representation as well.
 func (uh *UnlockHash) LoadString(strUH string) error {
 	if strUH == "" {
 		// an empty string is considered to be a(n) empty/nil unlock hash
 		*uh = NilUnlockHash
 		return nil
 	}
-
 	// Check the length of strUH.
 	// total length is 39, 1 byte for the (unlock) type,
 	// 32 for the hash itself and 6 for the (partial) checksum of the hash.
@@ -294,6 +306,57 @@ func (uh *UnlockHash) LoadString(strUH string) error {
 	return nil
 }
 
+// LoadAddressString loads either the hex or the bech32 representation of an unlock hash,
+// the latter only if a bech32 prefix is given, which is the human-readable part
+// used by the bech32 addresses of the chain (see ChainConstants.Bech32AddressPrefix).
+func (uh *UnlockHash) LoadAddressString(str, bech32Prefix string) error {
+	if bech32Prefix != "" && len(str) != 0 && len(str) != (1+crypto.HashSize+UnlockHashChecksumSize)*2 {
+		return uh.LoadBech32String(str, bech32Prefix)
+	}
+	return uh.LoadString(str)
+}
+
+// Bech32String returns the bech32 representation of the unlock hash,
+// using the given human-readable part as prefix. The bech32 data consists
+// of the unlock type (1 byte) followed by the hash (32 bytes), the checksum is the one of bech32.
+func (uh UnlockHash) Bech32String(prefix string) (string, error) {
+	if uh.Type == 0 {
+		return "", nil // nil unlock hash
+	}
+	data := make([]byte, 0, 1+crypto.HashSize)
+	data = append(data, byte(uh.Type))
+	data = append(data, uh.Hash[:]...)
+	return bech32.Encode(prefix, data)
+}
+
+// LoadBech32String loads the bech32 representation of an unlock hash,
+// which has to use the given human-readable part as prefix.
+// An error is returned if the string is invalid, fails the checksum or has a different prefix.
+func (uh *UnlockHash) LoadBech32String(str, prefix string) error {
+	if str == "" {
+		// an empty string is considered to be a(n) empty/nil unlock hash
+		*uh = NilUnlockHash
+		return nil
+	}
+	hrp, data, err := bech32.Decode(str)
+	if err != nil {
+		return fmt.Errorf("invalid bech32 unlock hash: %v", err)
+	}
+	if hrp != strings.ToLower(prefix) {
+		return fmt.Errorf("invalid bech32 unlock hash: unexpected prefix %q, expected %q", hrp, strings.ToLower(prefix))
+	}
+	if len(data) != 1+crypto.HashSize {
+		return ErrUnlockHashWrongLen
+	}
+	ut := UnlockType(data[0])
+	if ut == UnlockTypeNil {
+		return errors.New("invalid bech32 unlock hash: the nil unlock hash has no bech32 representation")
+	}
+	uh.Type = ut
+	copy(uh.Hash[:], data[1:])
+	return nil
+}
+
 // Len implements the Len method of sort.Interface.
 func (uhs UnlockHashSlice) Len() int {
 	return len(uhs)
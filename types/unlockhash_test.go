@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/threefoldtech/rivine/crypto"
@@ -381,3 +382,74 @@ func TestUnlockHashLoadNilUnlockHashJSONStringErrors(t *testing.T) {
 		t.Fatal("no error received, while unmarshalling nil unlock hash with invalid hash")
 	}
 }
+
+func TestUnlockHashBech32(t *testing.T) {
+	var uh UnlockHash
+	err := uh.LoadString("01b5e42056ef394f2ad9b511a61cec874d25bebe2095682dd37455cbafed4bec154e382a23f90e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	str, err := uh.Bech32String("riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(str, "riv1") {
+		t.Fatal("unexpected bech32 unlock hash:", str)
+	}
+
+	var other UnlockHash
+	err = other.LoadBech32String(str, "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uh.Cmp(other) != 0 {
+		t.Fatal(other, "!=", uh)
+	}
+	err = other.LoadBech32String(strings.ToUpper(str), "RIV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uh.Cmp(other) != 0 {
+		t.Fatal(other, "!=", uh)
+	}
+	if err = other.LoadBech32String(str, "rivt"); err == nil {
+		t.Fatal("expected bech32 unlock hash with a different prefix to be rejected")
+	}
+	if err = other.LoadBech32String(str[:len(str)-1]+"q", "riv"); err == nil {
+		t.Fatal("expected bech32 unlock hash with an invalid checksum to be rejected")
+	}
+
+	// bech32 addresses are only accepted by LoadAddressString if a prefix is given
+	if err = other.LoadString(str); err == nil {
+		t.Fatal("expected bech32 unlock hash to be rejected by LoadString")
+	}
+	if err = other.LoadAddressString(str, ""); err == nil {
+		t.Fatal("expected bech32 unlock hash to be rejected, while no prefix is given")
+	}
+	if err = other.LoadAddressString(str, "rivt"); err == nil {
+		t.Fatal("expected bech32 unlock hash to be rejected for a different prefix")
+	}
+	other = UnlockHash{}
+	err = other.LoadAddressString(str, "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uh.Cmp(other) != 0 {
+		t.Fatal(other, "!=", uh)
+	}
+	// hex addresses are still accepted
+	other = UnlockHash{}
+	err = other.LoadAddressString(uh.String(), "riv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uh.Cmp(other) != 0 {
+		t.Fatal(other, "!=", uh)
+	}
+
+	// the nil unlock hash has no bech32 representation
+	str, err = NilUnlockHash.Bech32String("riv")
+	if err != nil || str != "" {
+		t.Fatal("unexpected bech32 nil unlock hash:", str, err)
+	}
+}
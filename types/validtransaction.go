@@ -6,7 +6,6 @@ package types
 // other rules that are inherent to how a transaction should be constructed.
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
@@ -14,15 +13,17 @@ import (
 
 // various errors that can be returned as result of a specific transaction validation
 var (
-	ErrDoubleSpend                   = errors.New("transaction uses a parent object twice")
-	ErrNonZeroRevision               = errors.New("new file contract has a nonzero revision number")
-	ErrTransactionTooLarge           = errors.New("transaction is too large to fit in a block")
-	ErrTooSmallMinerFee              = errors.New("transaction has a too small miner fee")
-	ErrZeroOutput                    = errors.New("transaction cannot have an output or payout that has zero value")
-	ErrArbitraryDataTooLarge         = errors.New("arbitrary data is too large to fit in a transaction")
-	ErrCoinInputOutputMismatch       = errors.New("coin inputs do not equal coin outputs for transaction")
-	ErrBlockStakeInputOutputMismatch = errors.New("blockstake inputs do not equal blockstake outputs for transaction")
-	ErrMissingMinerFee               = errors.New("transaction does not specify any miner fees")
+	ErrDoubleSpend                   = NewError(ErrorCodeDoubleSpend, "transaction uses a parent object twice")
+	ErrNonZeroRevision               = NewError(ErrorCodeNonZeroRevision, "new file contract has a nonzero revision number")
+	ErrTransactionTooLarge           = NewError(ErrorCodeTransactionTooLarge, "transaction is too large to fit in a block")
+	ErrTooSmallMinerFee              = NewError(ErrorCodeTooSmallMinerFee, "transaction has a too small miner fee")
+	ErrZeroOutput                    = NewError(ErrorCodeZeroOutput, "transaction cannot have an output or payout that has zero value")
+	ErrArbitraryDataTooLarge         = NewError(ErrorCodeArbitraryDataTooLarge, "arbitrary data is too large to fit in a transaction")
+	ErrCoinInputOutputMismatch       = NewError(ErrorCodeCoinInputOutputMismatch, "coin inputs do not equal coin outputs for transaction")
+	ErrBlockStakeInputOutputMismatch = NewError(ErrorCodeBlockStakeInputOutputMismatch, "blockstake inputs do not equal blockstake outputs for transaction")
+	ErrMissingMinerFee               = NewError(ErrorCodeMissingMinerFee, "transaction does not specify any miner fees")
+	ErrUnknownParentOutput           = NewError(ErrorCodeUnknownParentOutput, "parent output is not an unspent output")
+	ErrMissingParentID               = NewError(ErrorCodeMissingParentID, "no parent ID defined for input")
 )
 
 // TransactionFitsInABlock checks if the transaction is likely to fit in a block.